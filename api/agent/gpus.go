@@ -0,0 +1,49 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/segmentio/encoding/json"
+)
+
+// ErrGPUInventoryNotSupported is returned by the agents older than the GPU inventory
+var ErrGPUInventoryNotSupported = errors.New("the agent does not support the GPU inventory")
+
+// GetGPUs returns the NVIDIA GPUs of the host of an agent, as reported by nvidia-smi.
+// The headers must hold the signature of the request, and the target node in an agent cluster
+func GetGPUs(ctx context.Context, httpCli *http.Client, agentURL string, headers map[string]string) ([]portainer.DockerSnapshotGPU, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, agentURL+"/host/gpus", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpCli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return nil, ErrGPUInventoryNotSupported
+	default:
+		return nil, fmt.Errorf("failed to retrieve the GPUs of the agent, status code: %d", resp.StatusCode)
+	}
+
+	var gpus []portainer.DockerSnapshotGPU
+	if err := json.NewDecoder(resp.Body).Decode(&gpus); err != nil {
+		return nil, err
+	}
+
+	return gpus, nil
+}
@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/segmentio/encoding/json"
+)
+
+// ErrHostInfoNotSupported is returned by the agents that do not report the information of their host
+var ErrHostInfoNotSupported = errors.New("the agent does not support the host information")
+
+// HostInfo represents the information of the host of an agent
+type HostInfo struct {
+	// Size in bytes of the filesystem holding the Docker data directory, 0 when the agent cannot read it
+	DataRootFilesystemSize int64 `json:"DataRootFilesystemSize"`
+}
+
+// GetHostInfo returns the information of the host of an agent.
+// The headers must hold the signature of the request, and the target node in an agent cluster
+func GetHostInfo(ctx context.Context, httpCli *http.Client, agentURL string, headers map[string]string) (*HostInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, agentURL+"/host/info", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := httpCli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return nil, ErrHostInfoNotSupported
+	default:
+		return nil, fmt.Errorf("failed to retrieve the host information of the agent, status code: %d", resp.StatusCode)
+	}
+
+	var info HostInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
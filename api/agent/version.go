@@ -13,10 +13,30 @@ import (
 	"github.com/portainer/portainer/api/url"
 )
 
+// PingResponse represents the information returned by the agent in the headers of a ping
+type PingResponse struct {
+	Platform portainer.AgentPlatform
+	Version  string
+	// Difference between the clock of the agent and the clock of the server, measured from the Date header
+	ClockSkew time.Duration
+	// Whether the agent returned a Date header the clock skew could be measured from
+	ClockSkewMeasured bool
+}
+
 // GetAgentVersionAndPlatform returns the agent version and platform
 //
 // it sends a ping to the agent and parses the version and platform from the headers
 func GetAgentVersionAndPlatform(endpointUrl string, tlsConfig *tls.Config) (portainer.AgentPlatform, string, error) {
+	ping, err := Ping(endpointUrl, tlsConfig)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return ping.Platform, ping.Version, nil
+}
+
+// Ping sends a ping to the agent and parses the version, the platform and the clock skew from the headers
+func Ping(endpointUrl string, tlsConfig *tls.Config) (*PingResponse, error) {
 	httpCli := &http.Client{
 		Timeout: 3 * time.Second,
 	}
@@ -29,46 +49,61 @@ func GetAgentVersionAndPlatform(endpointUrl string, tlsConfig *tls.Config) (port
 
 	parsedURL, err := url.ParseURL(endpointUrl + "/ping")
 	if err != nil {
-		return 0, "", err
+		return nil, err
 	}
 
 	parsedURL.Scheme = "https"
 
 	req, err := http.NewRequest(http.MethodGet, parsedURL.String(), nil)
 	if err != nil {
-		return 0, "", err
+		return nil, err
 	}
 
+	sentAt := time.Now()
+
 	resp, err := httpCli.Do(req)
 	if err != nil {
-		return 0, "", err
+		return nil, err
 	}
 
+	receivedAt := time.Now()
+
 	io.Copy(io.Discard, resp.Body)
 	resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		return 0, "", fmt.Errorf("Failed request with status %d", resp.StatusCode)
+		return nil, fmt.Errorf("Failed request with status %d", resp.StatusCode)
 	}
 
 	version := resp.Header.Get(portainer.PortainerAgentHeader)
 	if version == "" {
-		return 0, "", errors.New("Version Header is missing")
+		return nil, errors.New("Version Header is missing")
 	}
 
 	agentPlatformHeader := resp.Header.Get(portainer.HTTPResponseAgentPlatform)
 	if agentPlatformHeader == "" {
-		return 0, "", errors.New("Agent Platform Header is missing")
+		return nil, errors.New("Agent Platform Header is missing")
 	}
 
 	agentPlatformNumber, err := strconv.Atoi(agentPlatformHeader)
 	if err != nil {
-		return 0, "", err
+		return nil, err
 	}
 
 	if agentPlatformNumber == 0 {
-		return 0, "", errors.New("Agent platform is invalid")
+		return nil, errors.New("Agent platform is invalid")
+	}
+
+	ping := &PingResponse{
+		Platform: portainer.AgentPlatform(agentPlatformNumber),
+		Version:  version,
+	}
+
+	// The Date header has a precision of one second, it is compared to the middle of the round trip
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		ping.ClockSkew = date.Sub(sentAt.Add(receivedAt.Sub(sentAt) / 2))
+		ping.ClockSkewMeasured = true
 	}
 
-	return portainer.AgentPlatform(agentPlatformNumber), version, nil
+	return ping, nil
 }
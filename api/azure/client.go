@@ -0,0 +1,214 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/client"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	// BaseURL is the URL of the Azure Resource Manager API
+	BaseURL = "https://management.azure.com"
+	// containerInstanceAPIVersion is the version of the Azure Container Instances API
+	containerInstanceAPIVersion = "2023-05-01"
+	// subscriptionsAPIVersion is the version of the Azure subscriptions API
+	subscriptionsAPIVersion = "2022-12-01"
+	// requestTimeout bounds the duration of a request to the Azure API
+	requestTimeout = 60 * time.Second
+	// tokenRenewal is how long before its expiration a token is renewed
+	tokenRenewal = 5 * time.Minute
+)
+
+// APIError represents an error returned by the Azure API
+type APIError struct {
+	StatusCode int
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (err *APIError) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("unexpected status code %d from the Azure API", err.StatusCode)
+	}
+
+	return fmt.Sprintf("%s (%s)", err.Message, err.Code)
+}
+
+// IsNotFound returns true when the error reports a missing Azure resource
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Client is a typed client of the Azure Container Instances API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	// authenticate returns a new access token along with its expiration time
+	authenticate func() (string, time.Time, error)
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewClient returns a client of the Azure API authenticated with the credentials of an Azure environment(endpoint)
+func NewClient(credentials portainer.AzureCredentials) *Client {
+	httpClient := client.NewHTTPClient()
+
+	return &Client{
+		baseURL:    BaseURL,
+		httpClient: &http.Client{Timeout: requestTimeout, Transport: client.NewTransport()},
+		authenticate: func() (string, time.Time, error) {
+			token, err := httpClient.ExecuteAzureAuthenticationRequest(&credentials)
+			if err != nil {
+				return "", time.Time{}, err
+			}
+
+			expiresOn, err := strconv.ParseInt(token.ExpiresOn, 10, 64)
+			if err != nil {
+				return "", time.Time{}, err
+			}
+
+			return token.AccessToken, time.Unix(expiresOn, 0), nil
+		},
+	}
+}
+
+func (c *Client) accessToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Now().Add(tokenRenewal).Before(c.expiresAt) {
+		return c.token, nil
+	}
+
+	token, expiresAt, err := c.authenticate()
+	if err != nil {
+		return "", fmt.Errorf("unable to authenticate against Azure: %w", err)
+	}
+
+	c.token = token
+	c.expiresAt = expiresAt
+
+	return token, nil
+}
+
+// do sends a request to the Azure API and decodes the JSON response into out when it is not nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	token, err := c.accessToken()
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reader = bytes.NewReader(content)
+	}
+
+	requestURL := path
+	if !isAbsoluteURL(path) {
+		requestURL = c.baseURL + path + "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+
+		var errorResponse struct {
+			Error *APIError `json:"error"`
+		}
+		errorResponse.Error = apiErr
+
+		content, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		_ = json.Unmarshal(content, &errorResponse)
+
+		return apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func isAbsoluteURL(path string) bool {
+	u, err := url.Parse(path)
+
+	return err == nil && u.IsAbs()
+}
+
+// ClientFactory creates the clients of the Azure environments(endpoints) and keeps them so that
+// their access tokens are reused
+type ClientFactory struct {
+	mu      sync.Mutex
+	clients map[portainer.EndpointID]cachedClient
+}
+
+type cachedClient struct {
+	credentials portainer.AzureCredentials
+	client      *Client
+}
+
+// NewClientFactory returns a pointer to a new instance of ClientFactory
+func NewClientFactory() *ClientFactory {
+	return &ClientFactory{clients: make(map[portainer.EndpointID]cachedClient)}
+}
+
+// GetClient returns the client of an Azure environment(endpoint), a new client is created when
+// the credentials of the environment changed
+func (factory *ClientFactory) GetClient(endpoint *portainer.Endpoint) *Client {
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+
+	if cached, ok := factory.clients[endpoint.ID]; ok && cached.credentials == endpoint.AzureCredentials {
+		return cached.client
+	}
+
+	c := NewClient(endpoint.AzureCredentials)
+	factory.clients[endpoint.ID] = cachedClient{credentials: endpoint.AzureCredentials, client: c}
+
+	return c
+}
+
+// RemoveClient drops the client of an environment(endpoint)
+func (factory *ClientFactory) RemoveClient(endpointID portainer.EndpointID) {
+	factory.mu.Lock()
+	defer factory.mu.Unlock()
+
+	delete(factory.clients, endpointID)
+}
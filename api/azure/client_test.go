@@ -0,0 +1,78 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *int) {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	authentications := 0
+
+	return &Client{
+		baseURL:    srv.URL,
+		httpClient: srv.Client(),
+		authenticate: func() (string, time.Time, error) {
+			authentications++
+
+			return "token", time.Now().Add(time.Hour), nil
+		},
+	}, &authentications
+}
+
+func TestParseContainerGroupID(t *testing.T) {
+	id := ContainerGroupID{SubscriptionID: "sub", ResourceGroup: "rg", Name: "web"}
+
+	parsed, err := ParseContainerGroupID(id.String())
+	require.NoError(t, err)
+	assert.Equal(t, id, parsed)
+
+	_, err = ParseContainerGroupID("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Storage/storageAccounts/web")
+	require.Error(t, err)
+}
+
+func TestContainerGroupsFollowsTheNextLink(t *testing.T) {
+	var srvURL string
+	client, authentications := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, containerInstanceAPIVersion, r.URL.Query().Get("api-version"))
+
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`{"value":[{"id":"/subscriptions/sub/resourceGroups/rg/providers/Microsoft.ContainerInstance/containerGroups/db"}]}`))
+
+			return
+		}
+
+		w.Write([]byte(`{"value":[{"name":"web"}],"nextLink":"` + srvURL + r.URL.Path + `?api-version=` + containerInstanceAPIVersion + `&page=2"}`))
+	})
+	srvURL = client.baseURL
+
+	groups, err := client.ContainerGroups(context.Background(), "sub")
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "web", groups[0].Name)
+	assert.Equal(t, "rg", groups[1].ResourceGroup())
+
+	// The access token is reused until it expires
+	assert.Equal(t, 1, *authentications)
+}
+
+func TestAPIError(t *testing.T) {
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"ResourceNotFound","message":"The container group was not found"}}`))
+	})
+
+	_, err := client.ContainerGroup(context.Background(), ContainerGroupID{SubscriptionID: "sub", ResourceGroup: "rg", Name: "web"})
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+	assert.Equal(t, "The container group was not found (ResourceNotFound)", err.Error())
+}
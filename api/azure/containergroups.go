@@ -0,0 +1,278 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Container group states reported by Azure
+const (
+	StateRunning   = "Running"
+	StateStopped   = "Stopped"
+	StateSucceeded = "Succeeded"
+	StateFailed    = "Failed"
+	StatePending   = "Pending"
+)
+
+type (
+	// Subscription represents an Azure subscription
+	Subscription struct {
+		ID             string `json:"id"`
+		SubscriptionID string `json:"subscriptionId"`
+		DisplayName    string `json:"displayName"`
+		State          string `json:"state"`
+	}
+
+	// ContainerGroupID identifies an Azure container group
+	ContainerGroupID struct {
+		SubscriptionID string
+		ResourceGroup  string
+		Name           string
+	}
+
+	// ContainerGroup represents an Azure container group, only the properties managed by Portainer are typed
+	ContainerGroup struct {
+		ID         string                   `json:"id,omitempty"`
+		Name       string                   `json:"name,omitempty"`
+		Location   string                   `json:"location"`
+		Tags       map[string]string        `json:"tags,omitempty"`
+		Properties ContainerGroupProperties `json:"properties"`
+	}
+
+	ContainerGroupProperties struct {
+		ProvisioningState string                      `json:"provisioningState,omitempty"`
+		Containers        []Container                 `json:"containers"`
+		OSType            string                      `json:"osType"`
+		RestartPolicy     string                      `json:"restartPolicy,omitempty"`
+		IPAddress         *IPAddress                  `json:"ipAddress,omitempty"`
+		InstanceView      *ContainerGroupInstanceView `json:"instanceView,omitempty"`
+	}
+
+	ContainerGroupInstanceView struct {
+		State string `json:"state"`
+	}
+
+	IPAddress struct {
+		Type  string `json:"type"`
+		IP    string `json:"ip,omitempty"`
+		Ports []Port `json:"ports"`
+	}
+
+	Port struct {
+		Port     int    `json:"port"`
+		Protocol string `json:"protocol,omitempty"`
+	}
+
+	Container struct {
+		Name       string              `json:"name"`
+		Properties ContainerProperties `json:"properties"`
+	}
+
+	ContainerProperties struct {
+		Image                string                 `json:"image"`
+		Command              []string               `json:"command,omitempty"`
+		Ports                []Port                 `json:"ports,omitempty"`
+		EnvironmentVariables []EnvironmentVariable  `json:"environmentVariables,omitempty"`
+		Resources            ResourceRequirements   `json:"resources"`
+		InstanceView         *ContainerInstanceView `json:"instanceView,omitempty"`
+	}
+
+	EnvironmentVariable struct {
+		Name        string `json:"name"`
+		Value       string `json:"value,omitempty"`
+		SecureValue string `json:"secureValue,omitempty"`
+	}
+
+	ResourceRequirements struct {
+		Requests ResourceRequests `json:"requests"`
+	}
+
+	ResourceRequests struct {
+		CPU        float64 `json:"cpu"`
+		MemoryInGB float64 `json:"memoryInGB"`
+	}
+
+	ContainerInstanceView struct {
+		RestartCount int             `json:"restartCount"`
+		CurrentState *ContainerState `json:"currentState,omitempty"`
+	}
+
+	ContainerState struct {
+		State        string `json:"state"`
+		DetailStatus string `json:"detailStatus,omitempty"`
+	}
+
+	// ExecRequest represents a command to run in a container
+	ExecRequest struct {
+		Command      string       `json:"command"`
+		TerminalSize TerminalSize `json:"terminalSize"`
+	}
+
+	TerminalSize struct {
+		Rows int `json:"rows"`
+		Cols int `json:"cols"`
+	}
+
+	// ExecResponse holds the websocket to connect to in order to interact with a command run in a container.
+	// The password must be sent as the first message on the websocket
+	ExecResponse struct {
+		WebSocketURI string `json:"webSocketUri"`
+		Password     string `json:"password"`
+	}
+)
+
+// ParseContainerGroupID parses the Azure resource identifier of a container group
+func ParseContainerGroupID(resourceID string) (ContainerGroupID, error) {
+	parts := strings.Split(strings.Trim(resourceID, "/"), "/")
+	if len(parts) != 8 ||
+		!strings.EqualFold(parts[0], "subscriptions") ||
+		!strings.EqualFold(parts[2], "resourceGroups") ||
+		!strings.EqualFold(parts[4], "providers") ||
+		!strings.EqualFold(parts[5], "Microsoft.ContainerInstance") ||
+		!strings.EqualFold(parts[6], "containerGroups") {
+		return ContainerGroupID{}, fmt.Errorf("invalid container group identifier: %q", resourceID)
+	}
+
+	return ContainerGroupID{SubscriptionID: parts[1], ResourceGroup: parts[3], Name: parts[7]}, nil
+}
+
+// String returns the Azure resource identifier of the container group, it is also the identifier
+// of the resource control of the container group
+func (id ContainerGroupID) String() string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerInstance/containerGroups/%s",
+		url.PathEscape(id.SubscriptionID), url.PathEscape(id.ResourceGroup), url.PathEscape(id.Name))
+}
+
+// State returns the state of the container group
+func (group *ContainerGroup) State() string {
+	if group.Properties.InstanceView != nil && group.Properties.InstanceView.State != "" {
+		return group.Properties.InstanceView.State
+	}
+
+	return group.Properties.ProvisioningState
+}
+
+// ResourceGroup returns the resource group of the container group, taken from its identifier
+func (group *ContainerGroup) ResourceGroup() string {
+	id, err := ParseContainerGroupID(group.ID)
+	if err != nil {
+		return ""
+	}
+
+	return id.ResourceGroup
+}
+
+func apiVersion(version string) url.Values {
+	return url.Values{"api-version": {version}}
+}
+
+// Subscriptions returns the subscriptions the credentials of the environment have access to
+func (c *Client) Subscriptions(ctx context.Context) ([]Subscription, error) {
+	var subscriptions []Subscription
+
+	path := "/subscriptions"
+	for path != "" {
+		var page struct {
+			Value    []Subscription `json:"value"`
+			NextLink string         `json:"nextLink"`
+		}
+
+		if err := c.do(ctx, http.MethodGet, path, apiVersion(subscriptionsAPIVersion), nil, &page); err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, page.Value...)
+		path = page.NextLink
+	}
+
+	return subscriptions, nil
+}
+
+// ContainerGroups returns the container groups of a subscription
+func (c *Client) ContainerGroups(ctx context.Context, subscriptionID string) ([]ContainerGroup, error) {
+	groups := []ContainerGroup{}
+
+	path := fmt.Sprintf("/subscriptions/%s/providers/Microsoft.ContainerInstance/containerGroups", url.PathEscape(subscriptionID))
+	for path != "" {
+		var page struct {
+			Value    []ContainerGroup `json:"value"`
+			NextLink string           `json:"nextLink"`
+		}
+
+		if err := c.do(ctx, http.MethodGet, path, apiVersion(containerInstanceAPIVersion), nil, &page); err != nil {
+			return nil, err
+		}
+
+		groups = append(groups, page.Value...)
+		path = page.NextLink
+	}
+
+	return groups, nil
+}
+
+// ContainerGroup returns a container group along with the state of its containers
+func (c *Client) ContainerGroup(ctx context.Context, id ContainerGroupID) (*ContainerGroup, error) {
+	var group ContainerGroup
+
+	return &group, c.do(ctx, http.MethodGet, id.String(), apiVersion(containerInstanceAPIVersion), nil, &group)
+}
+
+// CreateContainerGroup creates or replaces a container group, its containers are started by Azure
+func (c *Client) CreateContainerGroup(ctx context.Context, id ContainerGroupID, group *ContainerGroup) (*ContainerGroup, error) {
+	var created ContainerGroup
+
+	return &created, c.do(ctx, http.MethodPut, id.String(), apiVersion(containerInstanceAPIVersion), group, &created)
+}
+
+// DeleteContainerGroup deletes a container group along with its containers
+func (c *Client) DeleteContainerGroup(ctx context.Context, id ContainerGroupID) error {
+	return c.do(ctx, http.MethodDelete, id.String(), apiVersion(containerInstanceAPIVersion), nil, nil)
+}
+
+// StartContainerGroup starts the containers of a stopped container group
+func (c *Client) StartContainerGroup(ctx context.Context, id ContainerGroupID) error {
+	return c.do(ctx, http.MethodPost, id.String()+"/start", apiVersion(containerInstanceAPIVersion), nil, nil)
+}
+
+// RestartContainerGroup restarts the containers of a container group in place
+func (c *Client) RestartContainerGroup(ctx context.Context, id ContainerGroupID) error {
+	return c.do(ctx, http.MethodPost, id.String()+"/restart", apiVersion(containerInstanceAPIVersion), nil, nil)
+}
+
+// StopContainerGroup stops the containers of a container group, the compute resources are released
+func (c *Client) StopContainerGroup(ctx context.Context, id ContainerGroupID) error {
+	return c.do(ctx, http.MethodPost, id.String()+"/stop", apiVersion(containerInstanceAPIVersion), nil, nil)
+}
+
+// ContainerLogs returns the logs of a container of a container group, only the last lines are returned when tail is positive
+func (c *Client) ContainerLogs(ctx context.Context, id ContainerGroupID, container string, tail int, timestamps bool) (string, error) {
+	query := apiVersion(containerInstanceAPIVersion)
+	if tail > 0 {
+		query.Set("tail", strconv.Itoa(tail))
+	}
+
+	if timestamps {
+		query.Set("timestamps", "true")
+	}
+
+	var logs struct {
+		Content string `json:"content"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, id.String()+"/containers/"+url.PathEscape(container)+"/logs", query, nil, &logs); err != nil {
+		return "", err
+	}
+
+	return logs.Content, nil
+}
+
+// ExecContainer runs a command in a container of a container group and returns the websocket to interact with it
+func (c *Client) ExecContainer(ctx context.Context, id ContainerGroupID, container string, exec ExecRequest) (*ExecResponse, error) {
+	var resp ExecResponse
+
+	return &resp, c.do(ctx, http.MethodPost, id.String()+"/containers/"+url.PathEscape(container)+"/exec", apiVersion(containerInstanceAPIVersion), exec, &resp)
+}
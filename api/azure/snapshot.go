@@ -0,0 +1,86 @@
+package azure
+
+import (
+	"context"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+// snapshotTimeout bounds the duration of the snapshot of an Azure environment(endpoint)
+const snapshotTimeout = 2 * time.Minute
+
+// Snapshotter is used to create snapshots of the container instances of the Azure environments(endpoints)
+type Snapshotter struct {
+	clientFactory *ClientFactory
+}
+
+// NewSnapshotter returns a new Snapshotter instance
+func NewSnapshotter(clientFactory *ClientFactory) *Snapshotter {
+	return &Snapshotter{clientFactory: clientFactory}
+}
+
+// CreateSnapshot lists the container groups of every subscription the environment has access to
+func (snapshotter *Snapshotter) CreateSnapshot(endpoint *portainer.Endpoint) (*portainer.AzureSnapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+	defer cancel()
+
+	c := snapshotter.clientFactory.GetClient(endpoint)
+
+	subscriptions, err := c.Subscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &portainer.AzureSnapshot{
+		Time:              time.Now().Unix(),
+		SubscriptionCount: len(subscriptions),
+		ContainerGroups:   []portainer.AzureContainerGroupSnapshot{},
+	}
+
+	for _, subscription := range subscriptions {
+		groups, err := c.ContainerGroups(ctx, subscription.SubscriptionID)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range groups {
+			addContainerGroup(snapshot, &groups[i])
+		}
+	}
+
+	return snapshot, nil
+}
+
+func addContainerGroup(snapshot *portainer.AzureSnapshot, group *ContainerGroup) {
+	state := group.State()
+
+	snapshot.ContainerGroupCount++
+	switch state {
+	case StateRunning:
+		snapshot.RunningContainerGroupCount++
+	case StateStopped, StateSucceeded, StateFailed:
+		snapshot.StoppedContainerGroupCount++
+	}
+
+	for _, container := range group.Properties.Containers {
+		snapshot.ContainerCount++
+		snapshot.TotalCPU += container.Properties.Resources.Requests.CPU
+		snapshot.TotalMemory += container.Properties.Resources.Requests.MemoryInGB
+	}
+
+	groupSnapshot := portainer.AzureContainerGroupSnapshot{
+		ID:             group.ID,
+		Name:           group.Name,
+		ResourceGroup:  group.ResourceGroup(),
+		Location:       group.Location,
+		State:          state,
+		ContainerCount: len(group.Properties.Containers),
+	}
+
+	if group.Properties.IPAddress != nil {
+		groupSnapshot.IPAddress = group.Properties.IPAddress.IP
+	}
+
+	snapshot.ContainerGroups = append(snapshot.ContainerGroups, groupSnapshot)
+}
@@ -0,0 +1,278 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/offlinegate"
+	"github.com/portainer/portainer/api/internal/events"
+	"github.com/portainer/portainer/api/internal/settingsbus"
+	"github.com/portainer/portainer/api/scheduler"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog/log"
+)
+
+// scheduledArchivePrefix is the prefix of the names of the archives written by the periodic backups
+const scheduledArchivePrefix = "portainer-backup_"
+
+// ScheduledBackups writes the periodic backups configured in the settings
+type ScheduledBackups struct {
+	dataStore   dataservices.DataStore
+	gate        *offlinegate.OfflineGate
+	fileService portainer.FileService
+	scheduler   *scheduler.Scheduler
+
+	mu      sync.Mutex
+	jobID   string
+	running bool
+}
+
+// NewScheduledBackups returns a new instance of ScheduledBackups
+func NewScheduledBackups(dataStore dataservices.DataStore, gate *offlinegate.OfflineGate, fileService portainer.FileService, scheduler *scheduler.Scheduler) *ScheduledBackups {
+	return &ScheduledBackups{
+		dataStore:   dataStore,
+		gate:        gate,
+		fileService: fileService,
+		scheduler:   scheduler,
+	}
+}
+
+// ValidateSchedule returns an error when an enabled schedule is incomplete
+func ValidateSchedule(schedule portainer.BackupSchedule) error {
+	if !schedule.Enabled {
+		return nil
+	}
+
+	if _, err := cron.ParseStandard(schedule.CronExpression); err != nil {
+		return fmt.Errorf("invalid backup cron expression: %w", err)
+	}
+
+	if schedule.Retention < 0 {
+		return errors.New("invalid backup retention, it must be positive")
+	}
+
+	switch schedule.Destination {
+	case portainer.BackupDestinationLocal:
+		if !filepath.IsAbs(schedule.LocalPath) {
+			return errors.New("invalid backup path, it must be an absolute path")
+		}
+	case portainer.BackupDestinationS3:
+		// The archives are uploaded to the object storage of the settings
+	default:
+		return fmt.Errorf("invalid backup destination %q, it must be one of: local, s3", schedule.Destination)
+	}
+
+	return nil
+}
+
+// Start schedules the backups configured in the settings
+func (service *ScheduledBackups) Start() error {
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve the settings")
+	}
+
+	return service.Configure(settings.BackupSchedule)
+}
+
+// SettingsChanged reschedules the backups when their schedule is updated
+func (service *ScheduledBackups) SettingsChanged(change settingsbus.Change) {
+	if change.Previous.BackupSchedule == change.Current.BackupSchedule {
+		return
+	}
+
+	if err := service.Configure(change.Current.BackupSchedule); err != nil {
+		log.Warn().Err(err).Msg("unable to apply the new backup schedule")
+	}
+}
+
+// Configure (re)schedules the backups, they are unscheduled when disabled
+func (service *ScheduledBackups) Configure(schedule portainer.BackupSchedule) error {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	if service.jobID != "" {
+		if err := service.scheduler.StopJob(service.jobID); err != nil {
+			log.Warn().Err(err).Msg("unable to stop the scheduled backups")
+		}
+
+		service.jobID = ""
+	}
+
+	if !schedule.Enabled {
+		return nil
+	}
+
+	jobID, err := service.scheduler.StartJobCron(schedule.CronExpression, service.runScheduled)
+	if err != nil {
+		return err
+	}
+
+	service.jobID = jobID
+
+	return nil
+}
+
+// runScheduled runs a backup of the schedule and raises an event when it fails
+func (service *ScheduledBackups) runScheduled() error {
+	err := service.Run()
+	if err != nil {
+		events.Publish(events.Event{
+			Type:    portainer.NotificationEventBackupFailed,
+			Title:   "Scheduled backup failed",
+			Message: err.Error(),
+		})
+	}
+
+	return err
+}
+
+// Run creates a backup archive and writes it to the destination configured in the settings
+func (service *ScheduledBackups) Run() error {
+	service.mu.Lock()
+	if service.running {
+		service.mu.Unlock()
+
+		return errors.New("a scheduled backup is already running")
+	}
+	service.running = true
+	service.mu.Unlock()
+
+	defer func() {
+		service.mu.Lock()
+		service.running = false
+		service.mu.Unlock()
+	}()
+
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve the settings")
+	}
+
+	schedule := settings.BackupSchedule
+
+	if schedule.Destination == portainer.BackupDestinationS3 && !settings.ObjectStorage.Enabled {
+		return errors.New("the backups cannot be uploaded, the object storage is not enabled")
+	}
+
+	archivePath, err := CreateBackupArchive(schedule.Password, service.gate, service.dataStore, service.fileService.GetDatastorePath())
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(filepath.Dir(archivePath))
+
+	name := scheduledArchivePrefix + filepath.Base(archivePath)
+
+	switch schedule.Destination {
+	case portainer.BackupDestinationS3:
+		err = uploadArchive(service.fileService, archivePath, name)
+	default:
+		err = writeArchive(schedule.LocalPath, schedule.Retention, archivePath, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Info().Str("archive", name).Str("destination", string(schedule.Destination)).Msg("scheduled backup completed")
+
+	return nil
+}
+
+// writeArchive copies an archive to a local directory and removes the oldest archives beyond the retention
+func writeArchive(dir string, retention int, archivePath, name string) error {
+	if err := os.MkdirAll(dir, rwxr__r__); err != nil {
+		return errors.Wrap(err, "unable to create the backup directory")
+	}
+
+	in, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	// The archive is renamed once complete so that a partial archive is never pruned in place of a complete one
+	tmpPath := filepath.Join(dir, "."+name+".tmp")
+	out, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return errors.Wrap(err, "unable to create the backup file")
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmpPath)
+
+		return errors.Wrap(err, "unable to write the backup file")
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(tmpPath)
+
+		return errors.Wrap(err, "unable to write the backup file")
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(dir, name)); err != nil {
+		os.Remove(tmpPath)
+
+		return errors.Wrap(err, "unable to write the backup file")
+	}
+
+	return pruneArchives(dir, retention)
+}
+
+// pruneArchives keeps the most recent archives of a directory, the names of the archives are timestamped
+// so that their lexical order is their chronological order
+func pruneArchives(dir string, retention int) error {
+	if retention <= 0 {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return errors.Wrap(err, "unable to list the backup directory")
+	}
+
+	var archives []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasPrefix(entry.Name(), scheduledArchivePrefix) {
+			archives = append(archives, entry.Name())
+		}
+	}
+
+	if len(archives) <= retention {
+		return nil
+	}
+
+	slices.Sort(archives)
+
+	for _, name := range archives[:len(archives)-retention] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			log.Warn().Err(err).Str("archive", name).Msg("unable to remove an expired backup")
+		}
+	}
+
+	return nil
+}
+
+// uploadArchive uploads an archive to the object storage through the file service
+func uploadArchive(fileService portainer.FileService, archivePath, name string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := fileService.StoreBackupArchive(name, file); err != nil {
+		return errors.Wrap(err, "unable to upload the backup")
+	}
+
+	return nil
+}
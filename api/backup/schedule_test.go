@@ -0,0 +1,47 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	assert.NoError(t, ValidateSchedule(portainer.BackupSchedule{}))
+	assert.NoError(t, ValidateSchedule(portainer.BackupSchedule{Enabled: true, CronExpression: "0 2 * * *", Destination: portainer.BackupDestinationLocal, LocalPath: "/backups"}))
+	assert.Error(t, ValidateSchedule(portainer.BackupSchedule{Enabled: true, CronExpression: "every day", Destination: portainer.BackupDestinationLocal, LocalPath: "/backups"}))
+	assert.Error(t, ValidateSchedule(portainer.BackupSchedule{Enabled: true, CronExpression: "0 2 * * *", Destination: portainer.BackupDestinationLocal, LocalPath: "backups"}))
+	assert.NoError(t, ValidateSchedule(portainer.BackupSchedule{Enabled: true, CronExpression: "0 2 * * *", Destination: portainer.BackupDestinationS3}))
+	assert.Error(t, ValidateSchedule(portainer.BackupSchedule{Enabled: true, CronExpression: "0 2 * * *", Destination: "ftp"}))
+}
+
+func TestWriteArchive(t *testing.T) {
+	src := t.TempDir()
+	dir := filepath.Join(t.TempDir(), "backups")
+
+	for _, timestamp := range []string{"2024-01-01_02-00-00", "2024-01-02_02-00-00", "2024-01-03_02-00-00"} {
+		archivePath := filepath.Join(src, timestamp+".tar.gz")
+		require.NoError(t, os.WriteFile(archivePath, []byte(timestamp), 0o600))
+
+		require.NoError(t, writeArchive(dir, 2, archivePath, scheduledArchivePrefix+filepath.Base(archivePath)))
+	}
+
+	// The files that are not archives are never pruned
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), nil, 0o600))
+	require.NoError(t, pruneArchives(dir, 1))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	assert.Equal(t, []string{"README", "portainer-backup_2024-01-03_02-00-00.tar.gz"}, names)
+}
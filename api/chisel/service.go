@@ -30,6 +30,7 @@ type Service struct {
 	serverFingerprint      string
 	serverPort             string
 	activeTunnels          map[portainer.EndpointID]*portainer.TunnelDetails
+	meters                 map[portainer.EndpointID]*tunnelMeter
 	edgeJobs               map[portainer.EndpointID][]portainer.EdgeJob
 	dataStore              dataservices.DataStore
 	snapshotService        portainer.SnapshotService
@@ -54,6 +55,7 @@ func NewService(dataStore dataservices.DataStore, shutdownCtx context.Context, f
 
 	return &Service{
 		activeTunnels:          make(map[portainer.EndpointID]*portainer.TunnelDetails),
+		meters:                 make(map[portainer.EndpointID]*tunnelMeter),
 		edgeJobs:               make(map[portainer.EndpointID][]portainer.EdgeJob),
 		dataStore:              dataStore,
 		shutdownCtx:            shutdownCtx,
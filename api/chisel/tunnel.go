@@ -1,11 +1,13 @@
 package chisel
 
 import (
+	"cmp"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"math/rand"
 	"net"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/portainer/portainer/api/internal/edge"
 	"github.com/portainer/portainer/api/internal/edge/cache"
 	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/settingsbus"
 	"github.com/portainer/portainer/pkg/libcrypto"
 
 	"github.com/dchest/uniuri"
@@ -25,9 +28,10 @@ const (
 )
 
 var (
-	ErrNonEdgeEnv = errors.New("cannot open a tunnel for non-edge environments")
-	ErrAsyncEnv   = errors.New("cannot open a tunnel for async edge environments")
-	ErrInvalidEnv = errors.New("cannot open a tunnel for an invalid environment")
+	ErrNonEdgeEnv     = errors.New("cannot open a tunnel for non-edge environments")
+	ErrAsyncEnv       = errors.New("cannot open a tunnel for async edge environments")
+	ErrInvalidEnv     = errors.New("cannot open a tunnel for an invalid environment")
+	ErrTunnelNotFound = errors.New("no tunnel is open for the environment")
 )
 
 // Open will mark the tunnel as REQUIRED so the agent opens it
@@ -53,10 +57,13 @@ func (s *Service) Open(endpoint *portainer.Endpoint) error {
 
 	defer cache.Del(endpoint.ID)
 
+	now := time.Now()
+
 	tun := &portainer.TunnelDetails{
 		Status:       portainer.EdgeAgentManagementRequired,
 		Port:         s.getUnusedPort(),
-		LastActivity: time.Now(),
+		LastActivity: now,
+		Opened:       now,
 	}
 
 	username, password := generateRandomCredentials()
@@ -100,6 +107,11 @@ func (s *Service) close(endpointID portainer.EndpointID) {
 		s.ProxyManager.DeleteEndpointProxy(endpointID)
 	}
 
+	if meter, ok := s.meters[endpointID]; ok {
+		meter.close()
+		delete(s.meters, endpointID)
+	}
+
 	delete(s.activeTunnels, endpointID)
 
 	cache.Del(endpointID)
@@ -117,6 +129,19 @@ func (s *Service) Config(endpointID portainer.EndpointID) portainer.TunnelDetail
 	return portainer.TunnelDetails{Status: portainer.EdgeAgentIdle}
 }
 
+// TunnelCountByStatus returns the number of open tunnels for each tunnel status
+func (s *Service) TunnelCountByStatus() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, tunnel := range s.activeTunnels {
+		counts[tunnel.Status]++
+	}
+
+	return counts
+}
+
 // TunnelAddr returns the address of the local tunnel, including the port, it
 // will block until the tunnel is ready
 func (s *Service) TunnelAddr(endpoint *portainer.Endpoint) (string, error) {
@@ -149,7 +174,84 @@ func (s *Service) TunnelAddr(endpoint *portainer.Endpoint) (string, error) {
 
 	s.UpdateLastActivity(endpoint.ID)
 
-	return fmt.Sprintf("127.0.0.1:%d", tun.Port), nil
+	return s.meterAddr(endpoint.ID, tun.Port)
+}
+
+// meterAddr returns the local address counting the traffic of the tunnel of an environment
+func (s *Service) meterAddr(endpointID portainer.EndpointID, port int) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.activeTunnels[endpointID]; !ok {
+		return "", errors.New("the tunnel was closed")
+	}
+
+	if meter, ok := s.meters[endpointID]; ok && meter.targetPort == port {
+		return meter.addr(), nil
+	} else if ok {
+		meter.close()
+	}
+
+	meter, err := newTunnelMeter(port)
+	if err != nil {
+		return "", err
+	}
+
+	s.meters[endpointID] = meter
+
+	return meter.addr(), nil
+}
+
+// TunnelMetrics returns the traffic and the age of the open tunnels
+func (s *Service) TunnelMetrics() []portainer.TunnelMetrics {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+
+	metrics := make([]portainer.TunnelMetrics, 0, len(s.activeTunnels))
+	for endpointID, tun := range s.activeTunnels {
+		m := portainer.TunnelMetrics{
+			EndpointID:   endpointID,
+			Status:       tun.Status,
+			Port:         tun.Port,
+			Opened:       tun.Opened.Unix(),
+			Age:          int64(now.Sub(tun.Opened).Seconds()),
+			LastActivity: tun.LastActivity.Unix(),
+		}
+
+		if meter, ok := s.meters[endpointID]; ok {
+			m.BytesSent = meter.bytesSent.Load()
+			m.BytesReceived = meter.bytesReceived.Load()
+			m.ActiveConnections = meter.activeConnections()
+		}
+
+		metrics = append(metrics, m)
+	}
+
+	slices.SortFunc(metrics, func(a, b portainer.TunnelMetrics) int {
+		return cmp.Compare(a.EndpointID, b.EndpointID)
+	})
+
+	return metrics
+}
+
+// ForceClose closes the tunnel of an environment and the connections going through it, the agent
+// opens a new tunnel the next time it is required
+func (s *Service) ForceClose(endpointID portainer.EndpointID) error {
+	s.mu.RLock()
+	_, ok := s.activeTunnels[endpointID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return ErrTunnelNotFound
+	}
+
+	log.Info().Int("endpoint_id", int(endpointID)).Msg("closing the environment tunnel")
+
+	s.close(endpointID)
+
+	return nil
 }
 
 // tryEffectiveCheckinInterval avoids a potential deadlock by returning a
@@ -176,6 +278,20 @@ func (s *Service) tryEffectiveCheckinInterval(endpoint *portainer.Endpoint) int
 	}
 }
 
+// SettingsChanged applies the new default check-in interval of the Edge agents, the cached
+// status responses are dropped so that the agents receive it on their next poll
+func (s *Service) SettingsChanged(change settingsbus.Change) {
+	if change.Current.EdgeAgentCheckinInterval == change.Previous.EdgeAgentCheckinInterval {
+		return
+	}
+
+	s.mu.Lock()
+	s.defaultCheckinInterval = cmp.Or(change.Current.EdgeAgentCheckinInterval, portainer.DefaultEdgeAgentCheckinIntervalInSeconds)
+	s.mu.Unlock()
+
+	cache.Reset()
+}
+
 // UpdateLastActivity sets the current timestamp to avoid the tunnel timeout
 func (s *Service) UpdateLastActivity(endpointID portainer.EndpointID) {
 	s.mu.Lock()
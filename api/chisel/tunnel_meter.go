@@ -0,0 +1,158 @@
+package chisel
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+)
+
+// tunnelMeter forwards the local connections to the port of a reverse tunnel and counts the bytes
+// exchanged with the agent, closing it cuts every connection going through the tunnel
+type tunnelMeter struct {
+	listener   net.Listener
+	targetPort int
+
+	bytesSent     atomic.Uint64
+	bytesReceived atomic.Uint64
+
+	mu sync.Mutex
+	// conns maps the local connections to their connection to the tunnel
+	conns  map[net.Conn]net.Conn
+	closed bool
+}
+
+func newTunnelMeter(targetPort int) (*tunnelMeter, error) {
+	listener, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 0})
+	if err != nil {
+		return nil, err
+	}
+
+	meter := &tunnelMeter{
+		listener:   listener,
+		targetPort: targetPort,
+		conns:      make(map[net.Conn]net.Conn),
+	}
+
+	go meter.serve()
+
+	return meter, nil
+}
+
+// addr returns the local address forwarding to the tunnel
+func (m *tunnelMeter) addr() string {
+	return m.listener.Addr().String()
+}
+
+// activeConnections returns the number of connections currently going through the tunnel
+func (m *tunnelMeter) activeConnections() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.conns)
+}
+
+func (m *tunnelMeter) serve() {
+	for {
+		conn, err := m.listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		} else if err != nil {
+			log.Debug().Err(err).Msg("unable to accept a tunnel connection")
+
+			continue
+		}
+
+		go m.forward(conn)
+	}
+}
+
+func (m *tunnelMeter) forward(conn net.Conn) {
+	target, err := net.DialTCP("tcp", nil, &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: m.targetPort})
+	if err != nil {
+		conn.Close()
+
+		return
+	}
+
+	if !m.track(conn, target) {
+		return
+	}
+	defer m.untrack(conn)
+
+	done := make(chan struct{})
+
+	go func() {
+		io.Copy(&countingWriter{w: target, n: &m.bytesSent}, conn)
+
+		// Let the agent finish its response
+		target.CloseWrite()
+
+		close(done)
+	}()
+
+	io.Copy(&countingWriter{w: conn, n: &m.bytesReceived}, target)
+
+	conn.Close()
+	<-done
+}
+
+func (m *tunnelMeter) track(conn, target net.Conn) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		conn.Close()
+		target.Close()
+
+		return false
+	}
+
+	m.conns[conn] = target
+
+	return true
+}
+
+func (m *tunnelMeter) untrack(conn net.Conn) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if target, ok := m.conns[conn]; ok {
+		target.Close()
+		delete(m.conns, conn)
+	}
+
+	conn.Close()
+}
+
+// close stops accepting connections and closes the ones going through the tunnel
+func (m *tunnelMeter) close() {
+	m.listener.Close()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+
+	for conn, target := range m.conns {
+		conn.Close()
+		target.Close()
+	}
+}
+
+// countingWriter counts the bytes written as they are forwarded so that long-lived connections
+// such as the websockets are accounted for while they are open
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Uint64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n.Add(uint64(n))
+
+	return n, err
+}
@@ -0,0 +1,85 @@
+package chisel
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTunnelMetricsAndForceClose(t *testing.T) {
+	endpoint := &portainer.Endpoint{
+		ID:          1,
+		EdgeID:      "test-edge-id",
+		Type:        portainer.EdgeAgentOnDockerEnvironment,
+		UserTrusted: true,
+	}
+
+	_, store := datastore.MustNewTestStore(t, true, true)
+	require.NoError(t, store.Endpoint().Create(endpoint))
+
+	s := NewService(store, nil, nil)
+
+	// The echo server stands for the agent behind the tunnel
+	ln, err := net.ListenTCP("tcp", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 0})
+	require.NoError(t, err)
+	defer ln.Close()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+
+	require.NoError(t, s.Open(endpoint))
+	s.activeTunnels[endpoint.ID].Port = ln.Addr().(*net.TCPAddr).Port
+
+	addr, err := s.TunnelAddr(endpoint)
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("ping"))
+	require.NoError(t, err)
+
+	buf := make([]byte, 4)
+	_, err = io.ReadFull(conn, buf)
+	require.NoError(t, err)
+	assert.Equal(t, "ping", string(buf))
+
+	// The counters are updated right after the bytes are forwarded
+	require.Eventually(t, func() bool {
+		metrics := s.TunnelMetrics()
+
+		return len(metrics) == 1 && metrics[0].BytesSent == 4 && metrics[0].BytesReceived == 4
+	}, time.Second, 10*time.Millisecond)
+
+	metrics := s.TunnelMetrics()
+	assert.Equal(t, endpoint.ID, metrics[0].EndpointID)
+	assert.Equal(t, 1, metrics[0].ActiveConnections)
+
+	require.NoError(t, s.ForceClose(endpoint.ID))
+	assert.Empty(t, s.TunnelMetrics())
+
+	// The connections going through the tunnel are closed
+	_, err = conn.Read(buf)
+	require.Error(t, err)
+
+	require.ErrorIs(t, s.ForceClose(endpoint.ID), ErrTunnelNotFound)
+}
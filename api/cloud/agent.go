@@ -0,0 +1,190 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	// AgentPort is the port the agent is exposed on
+	AgentPort = 9001
+	// agentNamespace is the namespace of the agent deployed on the Kubernetes clusters
+	agentNamespace = "portainer"
+	// agentServiceAccount is the service account of the agent, it is bound to the cluster-admin role
+	agentServiceAccount = "portainer-sa-clusteradmin"
+	// agentName is the name of the deployment and of the load balancer service of the agent
+	agentName = "portainer-agent"
+)
+
+// AgentImage returns the image of the agent matching the version of Portainer
+func AgentImage() string {
+	return "portainer/agent:" + portainer.APIVersion
+}
+
+// DockerUserData returns the cloud-init script that installs Docker on a virtual machine and runs the agent
+func DockerUserData() string {
+	return fmt.Sprintf(`#!/bin/sh
+set -e
+curl -fsSL https://get.docker.com | sh
+docker run -d \
+  -p %[1]d:%[1]d \
+  --name portainer_agent \
+  --restart=always \
+  -v /var/run/docker.sock:/var/run/docker.sock \
+  -v /var/lib/docker/volumes:/var/lib/docker/volumes \
+  -v /:/host \
+  %[2]s
+`, AgentPort, AgentImage())
+}
+
+// DeployKubernetesAgent deploys the agent on a Kubernetes cluster and exposes it through a load balancer service,
+// it returns the address of the load balancer once it is assigned
+func DeployKubernetesAgent(ctx context.Context, kubeconfig []byte, pollInterval time.Duration) (string, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse the kubeconfig of the cluster: %w", err)
+	}
+
+	cli, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return "", err
+	}
+
+	if err := createAgentResources(ctx, cli); err != nil {
+		return "", fmt.Errorf("unable to deploy the agent on the cluster: %w", err)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		service, err := cli.CoreV1().Services(agentNamespace).Get(ctx, agentName, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			if ingress.IP != "" {
+				return ingress.IP, nil
+			}
+
+			if ingress.Hostname != "" {
+				return ingress.Hostname, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("the load balancer of the agent was not assigned an address: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// createAgentResources creates the resources of the agent, the existing ones are kept so that a deployment
+// can be resumed
+func createAgentResources(ctx context.Context, cli kubernetes.Interface) error {
+	labels := map[string]string{"app": agentName}
+
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: agentNamespace}}
+	if _, err := cli.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{}); ignoreExists(err) != nil {
+		return err
+	}
+
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: agentServiceAccount, Namespace: agentNamespace}}
+	if _, err := cli.CoreV1().ServiceAccounts(agentNamespace).Create(ctx, serviceAccount, metav1.CreateOptions{}); ignoreExists(err) != nil {
+		return err
+	}
+
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "portainer"},
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "cluster-admin"},
+		Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: agentServiceAccount, Namespace: agentNamespace}},
+	}
+	if _, err := cli.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{}); ignoreExists(err) != nil {
+		return err
+	}
+
+	services := []*corev1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: agentName, Namespace: agentNamespace},
+			Spec: corev1.ServiceSpec{
+				Type:     corev1.ServiceTypeLoadBalancer,
+				Selector: labels,
+				Ports: []corev1.ServicePort{{
+					Name:       "http",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       AgentPort,
+					TargetPort: intstr.FromInt(AgentPort),
+				}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: agentName + "-headless", Namespace: agentNamespace},
+			Spec: corev1.ServiceSpec{
+				ClusterIP: corev1.ClusterIPNone,
+				Selector:  labels,
+			},
+		},
+	}
+
+	for _, service := range services {
+		if _, err := cli.CoreV1().Services(agentNamespace).Create(ctx, service, metav1.CreateOptions{}); ignoreExists(err) != nil {
+			return err
+		}
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: agentName, Namespace: agentNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: agentServiceAccount,
+					Containers: []corev1.Container{{
+						Name:            agentName,
+						Image:           AgentImage(),
+						ImagePullPolicy: corev1.PullAlways,
+						Env: []corev1.EnvVar{
+							{Name: "LOG_LEVEL", Value: "DEBUG"},
+							{Name: "AGENT_CLUSTER_ADDR", Value: agentName + "-headless"},
+							{
+								Name: "KUBERNETES_POD_IP",
+								ValueFrom: &corev1.EnvVarSource{
+									FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+								},
+							},
+						},
+						Ports: []corev1.ContainerPort{{ContainerPort: AgentPort, Protocol: corev1.ProtocolTCP}},
+					}},
+				},
+			},
+		},
+	}
+	if _, err := cli.AppsV1().Deployments(agentNamespace).Create(ctx, deployment, metav1.CreateOptions{}); ignoreExists(err) != nil {
+		return err
+	}
+
+	return nil
+}
+
+func ignoreExists(err error) error {
+	if k8serrors.IsAlreadyExists(err) {
+		return nil
+	}
+
+	return err
+}
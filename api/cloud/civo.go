@@ -0,0 +1,149 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	// CivoBaseURL is the URL of the Civo API
+	CivoBaseURL = "https://api.civo.com/v2"
+	// civoImage is the prefix of the name of the disk image of the Docker virtual machines
+	civoImage = "ubuntu-jammy"
+)
+
+// CivoProvisioner creates Civo Kubernetes clusters and instances
+type CivoProvisioner struct {
+	client *restClient
+}
+
+// NewCivoProvisioner returns a provisioner authenticated with a Civo API key
+func NewCivoProvisioner(token string) *CivoProvisioner {
+	return &CivoProvisioner{client: newRESTClient(CivoBaseURL, token, civoErrorMessage)}
+}
+
+func civoErrorMessage(content []byte) string {
+	var errorResponse struct {
+		Code   string `json:"code"`
+		Reason string `json:"reason"`
+	}
+	_ = json.Unmarshal(content, &errorResponse)
+
+	if errorResponse.Reason != "" {
+		return errorResponse.Reason
+	}
+
+	return errorResponse.Code
+}
+
+func regionQuery(region string) string {
+	return "?" + url.Values{"region": {region}}.Encode()
+}
+
+// CreateKubernetesCluster starts the creation of a Civo Kubernetes cluster
+func (p *CivoProvisioner) CreateKubernetesCluster(ctx context.Context, request Request) (string, error) {
+	body := map[string]any{
+		"name":              request.Name,
+		"region":            request.Region,
+		"num_target_nodes":  request.NodeCount,
+		"target_nodes_size": request.NodeSize,
+	}
+
+	if request.KubernetesVersion != "" {
+		body["kubernetes_version"] = request.KubernetesVersion
+	}
+
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := p.client.do(ctx, http.MethodPost, "/kubernetes/clusters", body, &response); err != nil {
+		return "", err
+	}
+
+	return response.ID, nil
+}
+
+// KubernetesCluster returns the kubeconfig of a Civo Kubernetes cluster once it is ready
+func (p *CivoProvisioner) KubernetesCluster(ctx context.Context, region, resourceID string) ([]byte, bool, error) {
+	var response struct {
+		Ready      bool   `json:"ready"`
+		KubeConfig string `json:"kubeconfig"`
+	}
+	if err := p.client.do(ctx, http.MethodGet, "/kubernetes/clusters/"+resourceID+regionQuery(region), nil, &response); err != nil {
+		return nil, false, err
+	}
+
+	if !response.Ready || response.KubeConfig == "" {
+		return nil, false, nil
+	}
+
+	return []byte(response.KubeConfig), true, nil
+}
+
+// CreateDockerVM starts the creation of a Civo instance
+func (p *CivoProvisioner) CreateDockerVM(ctx context.Context, request Request) (string, error) {
+	imageID, err := p.diskImage(ctx, request.Region)
+	if err != nil {
+		return "", err
+	}
+
+	body := map[string]any{
+		"hostname":    request.Name,
+		"region":      request.Region,
+		"size":        request.NodeSize,
+		"template_id": imageID,
+		"count":       1,
+		"public_ip":   "create",
+		"script":      request.UserData,
+	}
+
+	var response struct {
+		ID string `json:"id"`
+	}
+	if err := p.client.do(ctx, http.MethodPost, "/instances", body, &response); err != nil {
+		return "", err
+	}
+
+	return response.ID, nil
+}
+
+// DockerVM returns the public IP address of a Civo instance once it is active
+func (p *CivoProvisioner) DockerVM(ctx context.Context, region, resourceID string) (string, bool, error) {
+	var response struct {
+		Status   string `json:"status"`
+		PublicIP string `json:"public_ip"`
+	}
+	if err := p.client.do(ctx, http.MethodGet, "/instances/"+resourceID+regionQuery(region), nil, &response); err != nil {
+		return "", false, err
+	}
+
+	if response.Status != "ACTIVE" || response.PublicIP == "" {
+		return "", false, nil
+	}
+
+	return response.PublicIP, true, nil
+}
+
+// diskImage returns the identifier of the Ubuntu disk image of a region
+func (p *CivoProvisioner) diskImage(ctx context.Context, region string) (string, error) {
+	var images []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := p.client.do(ctx, http.MethodGet, "/disk_images"+regionQuery(region), nil, &images); err != nil {
+		return "", err
+	}
+
+	for _, image := range images {
+		if strings.HasPrefix(image.Name, civoImage) {
+			return image.ID, nil
+		}
+	}
+
+	return "", errors.New("unable to find an Ubuntu disk image in the region")
+}
@@ -0,0 +1,136 @@
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/portainer/portainer/api/http/client"
+
+	"github.com/segmentio/encoding/json"
+)
+
+// requestTimeout bounds the duration of a request to the API of a cloud provider
+const requestTimeout = 60 * time.Second
+
+// APIError represents an error returned by the API of a cloud provider
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (err *APIError) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("unexpected status code %d from the cloud provider API", err.StatusCode)
+	}
+
+	return err.Message
+}
+
+// IsNotFound returns true when the error reports a missing cloud resource
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// restClient is a JSON client of the API of a cloud provider authenticated with a bearer token
+type restClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+	// errorMessage extracts the message of an error response of the API
+	errorMessage func(content []byte) string
+}
+
+func newRESTClient(baseURL, token string, errorMessage func(content []byte) string) *restClient {
+	return &restClient{
+		baseURL:      baseURL,
+		token:        token,
+		httpClient:   &http.Client{Timeout: requestTimeout, Transport: client.NewTransport()},
+		errorMessage: errorMessage,
+	}
+}
+
+// do sends a request to the API and decodes the JSON response into out when it is not nil
+func (c *restClient) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reader = bytes.NewReader(content)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+
+		content, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if c.errorMessage != nil {
+			apiErr.Message = c.errorMessage(content)
+		}
+
+		return apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// getRaw sends a GET request to the API and returns the raw content of the response
+func (c *restClient) getRaw(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if c.errorMessage != nil {
+			apiErr.Message = c.errorMessage(content)
+		}
+
+		return nil, apiErr
+	}
+
+	return content, nil
+}
@@ -0,0 +1,144 @@
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	// DigitalOceanBaseURL is the URL of the DigitalOcean API
+	DigitalOceanBaseURL = "https://api.digitalocean.com/v2"
+	// digitalOceanImage is the image of the Docker virtual machines
+	digitalOceanImage = "ubuntu-22-04-x64"
+)
+
+// DigitalOceanProvisioner creates DigitalOcean Kubernetes clusters and droplets
+type DigitalOceanProvisioner struct {
+	client *restClient
+}
+
+type digitalOceanCluster struct {
+	ID     string `json:"id"`
+	Status struct {
+		State string `json:"state"`
+	} `json:"status"`
+}
+
+type digitalOceanDroplet struct {
+	ID       int    `json:"id"`
+	Status   string `json:"status"`
+	Networks struct {
+		V4 []struct {
+			IPAddress string `json:"ip_address"`
+			Type      string `json:"type"`
+		} `json:"v4"`
+	} `json:"networks"`
+}
+
+// NewDigitalOceanProvisioner returns a provisioner authenticated with a DigitalOcean personal access token
+func NewDigitalOceanProvisioner(token string) *DigitalOceanProvisioner {
+	return &DigitalOceanProvisioner{client: newRESTClient(DigitalOceanBaseURL, token, digitalOceanErrorMessage)}
+}
+
+func digitalOceanErrorMessage(content []byte) string {
+	var errorResponse struct {
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(content, &errorResponse)
+
+	return errorResponse.Message
+}
+
+// CreateKubernetesCluster starts the creation of a DigitalOcean Kubernetes cluster
+func (p *DigitalOceanProvisioner) CreateKubernetesCluster(ctx context.Context, request Request) (string, error) {
+	version := request.KubernetesVersion
+	if version == "" {
+		version = "latest"
+	}
+
+	body := map[string]any{
+		"name":    request.Name,
+		"region":  request.Region,
+		"version": version,
+		"node_pools": []map[string]any{{
+			"name":  request.Name + "-pool",
+			"size":  request.NodeSize,
+			"count": request.NodeCount,
+		}},
+	}
+
+	var response struct {
+		Cluster digitalOceanCluster `json:"kubernetes_cluster"`
+	}
+	if err := p.client.do(ctx, http.MethodPost, "/kubernetes/clusters", body, &response); err != nil {
+		return "", err
+	}
+
+	return response.Cluster.ID, nil
+}
+
+// KubernetesCluster returns the kubeconfig of a DigitalOcean Kubernetes cluster once it is running
+func (p *DigitalOceanProvisioner) KubernetesCluster(ctx context.Context, region, resourceID string) ([]byte, bool, error) {
+	var response struct {
+		Cluster digitalOceanCluster `json:"kubernetes_cluster"`
+	}
+	if err := p.client.do(ctx, http.MethodGet, "/kubernetes/clusters/"+resourceID, nil, &response); err != nil {
+		return nil, false, err
+	}
+
+	if response.Cluster.Status.State != "running" {
+		return nil, false, nil
+	}
+
+	kubeconfig, err := p.client.getRaw(ctx, "/kubernetes/clusters/"+resourceID+"/kubeconfig")
+	if err != nil {
+		return nil, false, err
+	}
+
+	return kubeconfig, true, nil
+}
+
+// CreateDockerVM starts the creation of a DigitalOcean droplet
+func (p *DigitalOceanProvisioner) CreateDockerVM(ctx context.Context, request Request) (string, error) {
+	body := map[string]any{
+		"name":      request.Name,
+		"region":    request.Region,
+		"size":      request.NodeSize,
+		"image":     digitalOceanImage,
+		"user_data": request.UserData,
+	}
+
+	var response struct {
+		Droplet digitalOceanDroplet `json:"droplet"`
+	}
+	if err := p.client.do(ctx, http.MethodPost, "/droplets", body, &response); err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(response.Droplet.ID), nil
+}
+
+// DockerVM returns the public IP address of a DigitalOcean droplet once it is active
+func (p *DigitalOceanProvisioner) DockerVM(ctx context.Context, region, resourceID string) (string, bool, error) {
+	var response struct {
+		Droplet digitalOceanDroplet `json:"droplet"`
+	}
+	if err := p.client.do(ctx, http.MethodGet, "/droplets/"+resourceID, nil, &response); err != nil {
+		return "", false, err
+	}
+
+	if response.Droplet.Status != "active" {
+		return "", false, nil
+	}
+
+	for _, network := range response.Droplet.Networks.V4 {
+		if network.Type == "public" {
+			return network.IPAddress, true, nil
+		}
+	}
+
+	return "", false, nil
+}
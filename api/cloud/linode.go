@@ -0,0 +1,188 @@
+package cloud
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	// LinodeBaseURL is the URL of the Linode API
+	LinodeBaseURL = "https://api.linode.com/v4"
+	// linodeImage is the image of the Docker virtual machines, it supports the cloud-init metadata
+	linodeImage = "linode/ubuntu22.04"
+)
+
+// LinodeProvisioner creates Linode Kubernetes Engine clusters and Linode instances
+type LinodeProvisioner struct {
+	client *restClient
+}
+
+// NewLinodeProvisioner returns a provisioner authenticated with a Linode personal access token
+func NewLinodeProvisioner(token string) *LinodeProvisioner {
+	return &LinodeProvisioner{client: newRESTClient(LinodeBaseURL, token, linodeErrorMessage)}
+}
+
+func linodeErrorMessage(content []byte) string {
+	var errorResponse struct {
+		Errors []struct {
+			Field  string `json:"field"`
+			Reason string `json:"reason"`
+		} `json:"errors"`
+	}
+	_ = json.Unmarshal(content, &errorResponse)
+
+	reasons := make([]string, 0, len(errorResponse.Errors))
+	for _, e := range errorResponse.Errors {
+		if e.Field != "" {
+			reasons = append(reasons, e.Field+": "+e.Reason)
+		} else {
+			reasons = append(reasons, e.Reason)
+		}
+	}
+
+	return strings.Join(reasons, ", ")
+}
+
+// CreateKubernetesCluster starts the creation of a Linode Kubernetes Engine cluster
+func (p *LinodeProvisioner) CreateKubernetesCluster(ctx context.Context, request Request) (string, error) {
+	version := request.KubernetesVersion
+	if version == "" {
+		var err error
+		if version, err = p.latestKubernetesVersion(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	body := map[string]any{
+		"label":       request.Name,
+		"region":      request.Region,
+		"k8s_version": version,
+		"node_pools": []map[string]any{{
+			"type":  request.NodeSize,
+			"count": request.NodeCount,
+		}},
+	}
+
+	var response struct {
+		ID int `json:"id"`
+	}
+	if err := p.client.do(ctx, http.MethodPost, "/lke/clusters", body, &response); err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(response.ID), nil
+}
+
+// KubernetesCluster returns the kubeconfig of a Linode Kubernetes Engine cluster once it is ready
+func (p *LinodeProvisioner) KubernetesCluster(ctx context.Context, region, resourceID string) ([]byte, bool, error) {
+	var cluster struct {
+		Status string `json:"status"`
+	}
+	if err := p.client.do(ctx, http.MethodGet, "/lke/clusters/"+resourceID, nil, &cluster); err != nil {
+		return nil, false, err
+	}
+
+	if cluster.Status != "ready" {
+		return nil, false, nil
+	}
+
+	var response struct {
+		KubeConfig string `json:"kubeconfig"`
+	}
+	if err := p.client.do(ctx, http.MethodGet, "/lke/clusters/"+resourceID+"/kubeconfig", nil, &response); err != nil {
+		// The kubeconfig is not available until the control plane of the cluster is up
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusServiceUnavailable {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	kubeconfig, err := base64.StdEncoding.DecodeString(response.KubeConfig)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return kubeconfig, true, nil
+}
+
+// CreateDockerVM starts the creation of a Linode instance
+func (p *LinodeProvisioner) CreateDockerVM(ctx context.Context, request Request) (string, error) {
+	rootPassword, err := randomPassword()
+	if err != nil {
+		return "", err
+	}
+
+	body := map[string]any{
+		"label":     request.Name,
+		"region":    request.Region,
+		"type":      request.NodeSize,
+		"image":     linodeImage,
+		"root_pass": rootPassword,
+		"metadata": map[string]any{
+			"user_data": base64.StdEncoding.EncodeToString([]byte(request.UserData)),
+		},
+	}
+
+	var response struct {
+		ID int `json:"id"`
+	}
+	if err := p.client.do(ctx, http.MethodPost, "/linode/instances", body, &response); err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(response.ID), nil
+}
+
+// DockerVM returns the public IP address of a Linode instance once it is running
+func (p *LinodeProvisioner) DockerVM(ctx context.Context, region, resourceID string) (string, bool, error) {
+	var response struct {
+		Status string   `json:"status"`
+		IPv4   []string `json:"ipv4"`
+	}
+	if err := p.client.do(ctx, http.MethodGet, "/linode/instances/"+resourceID, nil, &response); err != nil {
+		return "", false, err
+	}
+
+	if response.Status != "running" || len(response.IPv4) == 0 {
+		return "", false, nil
+	}
+
+	return response.IPv4[0], true, nil
+}
+
+func (p *LinodeProvisioner) latestKubernetesVersion(ctx context.Context) (string, error) {
+	var response struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := p.client.do(ctx, http.MethodGet, "/lke/versions", nil, &response); err != nil {
+		return "", err
+	}
+
+	if len(response.Data) == 0 {
+		return "", errors.New("no Kubernetes version is available on Linode")
+	}
+
+	return response.Data[0].ID, nil
+}
+
+// randomPassword returns the root password of a Linode instance, the instance is only reached through the agent
+// so the password is not kept
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b) + "-P0", nil
+}
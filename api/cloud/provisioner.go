@@ -0,0 +1,49 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+// Request describes the Kubernetes cluster or the Docker virtual machine to create on a cloud provider
+type Request struct {
+	Name   string
+	Region string
+	// NodeSize is the size of the virtual machine or of the nodes of the cluster, as named by the provider
+	NodeSize string
+	// NodeCount is the number of nodes of the cluster
+	NodeCount int
+	// KubernetesVersion is the version of the cluster, the default version of the provider is used when empty
+	KubernetesVersion string
+	// UserData is the cloud-init script run on the first boot of the virtual machine
+	UserData string
+}
+
+// Provisioner creates the Kubernetes clusters and the Docker virtual machines on a cloud provider.
+// The resources are created asynchronously, their creation returns the identifier used to follow it
+type Provisioner interface {
+	// CreateKubernetesCluster starts the creation of a managed Kubernetes cluster
+	CreateKubernetesCluster(ctx context.Context, request Request) (string, error)
+	// KubernetesCluster returns the kubeconfig of a cluster once it is ready
+	KubernetesCluster(ctx context.Context, region, resourceID string) ([]byte, bool, error)
+	// CreateDockerVM starts the creation of a virtual machine running Docker
+	CreateDockerVM(ctx context.Context, request Request) (string, error)
+	// DockerVM returns the public IP address of a virtual machine once it is running
+	DockerVM(ctx context.Context, region, resourceID string) (string, bool, error)
+}
+
+// NewProvisioner returns the provisioner of a cloud provider authenticated with an API token of the provider
+func NewProvisioner(provider portainer.CloudProvider, token string) (Provisioner, error) {
+	switch provider {
+	case portainer.CloudProviderDigitalOcean:
+		return NewDigitalOceanProvisioner(token), nil
+	case portainer.CloudProviderCivo:
+		return NewCivoProvisioner(token), nil
+	case portainer.CloudProviderLinode:
+		return NewLinodeProvisioner(token), nil
+	}
+
+	return nil, fmt.Errorf("unsupported cloud provider: %q", provider)
+}
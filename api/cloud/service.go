@@ -0,0 +1,272 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// PollInterval is how often the progress of the resources being created at a provider is checked
+	PollInterval = 15 * time.Second
+	// ProvisioningTimeout bounds the duration of a provisioning, from the creation of the resources
+	// to the registration of the environment(endpoint)
+	ProvisioningTimeout = 45 * time.Minute
+)
+
+// ErrInterrupted is recorded in the provisionings still running when Portainer stopped, the API token
+// of the provider is not persisted so they cannot be resumed
+var ErrInterrupted = errors.New("the provisioning was interrupted by a restart of Portainer")
+
+// Service creates the Kubernetes clusters and the Docker virtual machines on the cloud providers, deploys the
+// agent on them and registers them as environments(endpoints) once the agent answers
+type Service struct {
+	shutdownCtx     context.Context
+	dataStore       dataservices.DataStore
+	snapshotService portainer.SnapshotService
+	newProvisioner  func(provider portainer.CloudProvider, token string) (Provisioner, error)
+	deployAgent     func(ctx context.Context, kubeconfig []byte, pollInterval time.Duration) (string, error)
+	pollInterval    time.Duration
+	timeout         time.Duration
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService(shutdownCtx context.Context, dataStore dataservices.DataStore, snapshotService portainer.SnapshotService) *Service {
+	return &Service{
+		shutdownCtx:     shutdownCtx,
+		dataStore:       dataStore,
+		snapshotService: snapshotService,
+		newProvisioner:  NewProvisioner,
+		deployAgent:     DeployKubernetesAgent,
+		pollInterval:    PollInterval,
+		timeout:         ProvisioningTimeout,
+	}
+}
+
+// Start fails the provisionings that were still running when Portainer stopped
+func (service *Service) Start() error {
+	provisionings, err := service.dataStore.CloudProvisioning().ReadAll()
+	if err != nil {
+		return err
+	}
+
+	for _, provisioning := range provisionings {
+		if provisioning.Status == portainer.CloudProvisioningReady || provisioning.Status == portainer.CloudProvisioningFailed {
+			continue
+		}
+
+		service.fail(provisioning.ID, ErrInterrupted)
+	}
+
+	return nil
+}
+
+// Provision saves a new provisioning and starts the creation of its resources in the background,
+// the token is the API token of the provider and is only kept in memory during the provisioning
+func (service *Service) Provision(provisioning *portainer.CloudProvisioning, token string) error {
+	provisioner, err := service.newProvisioner(provisioning.Provider, token)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	provisioning.Status = portainer.CloudProvisioningCreating
+	provisioning.Created = now
+	provisioning.Updated = now
+
+	if err := service.dataStore.CloudProvisioning().Create(provisioning); err != nil {
+		return err
+	}
+
+	go service.run(*provisioning, provisioner)
+
+	return nil
+}
+
+func (service *Service) run(provisioning portainer.CloudProvisioning, provisioner Provisioner) {
+	ctx, cancel := context.WithTimeout(service.shutdownCtx, service.timeout)
+	defer cancel()
+
+	endpointID, err := service.provision(ctx, &provisioning, provisioner)
+	if err != nil {
+		log.Warn().Err(err).Int("provisioning_id", int(provisioning.ID)).Msg("unable to provision the environment")
+
+		service.fail(provisioning.ID, err)
+
+		return
+	}
+
+	service.update(provisioning.ID, func(p *portainer.CloudProvisioning) {
+		p.Status = portainer.CloudProvisioningReady
+		p.EndpointID = endpointID
+	})
+}
+
+// provision creates the resources at the provider, waits for them to be ready and registers the environment
+func (service *Service) provision(ctx context.Context, provisioning *portainer.CloudProvisioning, provisioner Provisioner) (portainer.EndpointID, error) {
+	request := Request{
+		Name:              provisioning.Name,
+		Region:            provisioning.Region,
+		NodeSize:          provisioning.NodeSize,
+		NodeCount:         provisioning.NodeCount,
+		KubernetesVersion: provisioning.KubernetesVersion,
+	}
+
+	if provisioning.Type == portainer.CloudProvisioningDocker {
+		request.UserData = DockerUserData()
+
+		resourceID, err := provisioner.CreateDockerVM(ctx, request)
+		if err != nil {
+			return 0, fmt.Errorf("unable to create the virtual machine: %w", err)
+		}
+		service.recordResource(provisioning.ID, resourceID)
+
+		ip, err := poll(ctx, service.pollInterval, func() (string, bool, error) {
+			return provisioner.DockerVM(ctx, provisioning.Region, resourceID)
+		})
+		if err != nil {
+			return 0, fmt.Errorf("the virtual machine is not running: %w", err)
+		}
+
+		return service.register(ctx, provisioning, portainer.AgentOnDockerEnvironment, "tcp://"+agentAddress(ip))
+	}
+
+	resourceID, err := provisioner.CreateKubernetesCluster(ctx, request)
+	if err != nil {
+		return 0, fmt.Errorf("unable to create the Kubernetes cluster: %w", err)
+	}
+	service.recordResource(provisioning.ID, resourceID)
+
+	kubeconfig, err := poll(ctx, service.pollInterval, func() ([]byte, bool, error) {
+		return provisioner.KubernetesCluster(ctx, provisioning.Region, resourceID)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("the Kubernetes cluster is not ready: %w", err)
+	}
+
+	address, err := service.deployAgent(ctx, kubeconfig, service.pollInterval)
+	if err != nil {
+		return 0, err
+	}
+
+	return service.register(ctx, provisioning, portainer.AgentOnKubernetesEnvironment, agentAddress(address))
+}
+
+// register waits for the agent to answer and saves the environment, the agent can take a while to start
+// once the resources are ready so its snapshot is retried until the provisioning times out
+func (service *Service) register(ctx context.Context, provisioning *portainer.CloudProvisioning, endpointType portainer.EndpointType, url string) (portainer.EndpointID, error) {
+	service.update(provisioning.ID, func(p *portainer.CloudProvisioning) {
+		p.Status = portainer.CloudProvisioningRegistering
+	})
+
+	endpoint := &portainer.Endpoint{
+		ID:      portainer.EndpointID(service.dataStore.Endpoint().GetNextIdentifier()),
+		Name:    provisioning.Name,
+		URL:     url,
+		Type:    endpointType,
+		GroupID: provisioning.GroupID,
+		TLSConfig: portainer.TLSConfiguration{
+			TLS:           true,
+			TLSSkipVerify: true,
+		},
+		UserAccessPolicies: portainer.UserAccessPolicies{},
+		TeamAccessPolicies: portainer.TeamAccessPolicies{},
+		TagIDs:             provisioning.TagIDs,
+		Status:             portainer.EndpointStatusUp,
+		Snapshots:          []portainer.DockerSnapshot{},
+		Kubernetes:         portainer.KubernetesDefault(),
+	}
+	endpoint.Agent.Version = portainer.APIVersion
+
+	if _, err := poll(ctx, service.pollInterval, func() (struct{}, bool, error) {
+		err := service.snapshotService.SnapshotEndpoint(endpoint)
+		if err != nil {
+			log.Debug().Err(err).Str("url", url).Msg("the agent of the provisioned environment is not answering yet")
+		}
+
+		return struct{}{}, err == nil, nil
+	}); err != nil {
+		return 0, fmt.Errorf("the agent is not answering: %w", err)
+	}
+
+	err := service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := endpointutils.CreateEndpoint(tx, endpoint); err != nil {
+			return err
+		}
+
+		return tx.EndpointRelation().Create(&portainer.EndpointRelation{
+			EndpointID: endpoint.ID,
+			EdgeStacks: map[portainer.EdgeStackID]bool{},
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to save the environment: %w", err)
+	}
+
+	return endpoint.ID, nil
+}
+
+func (service *Service) recordResource(provisioningID portainer.CloudProvisioningID, resourceID string) {
+	service.update(provisioningID, func(p *portainer.CloudProvisioning) {
+		p.ResourceID = resourceID
+	})
+}
+
+func (service *Service) fail(provisioningID portainer.CloudProvisioningID, provisioningErr error) {
+	service.update(provisioningID, func(p *portainer.CloudProvisioning) {
+		p.Status = portainer.CloudProvisioningFailed
+		p.Error = provisioningErr.Error()
+	})
+}
+
+// update changes a provisioning, unless it was deleted in the meantime
+func (service *Service) update(provisioningID portainer.CloudProvisioningID, updateFunc func(*portainer.CloudProvisioning)) {
+	err := service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		provisioning, err := tx.CloudProvisioning().Read(provisioningID)
+		if err != nil {
+			return err
+		}
+
+		updateFunc(provisioning)
+		provisioning.Updated = time.Now().Unix()
+
+		return tx.CloudProvisioning().Update(provisioningID, provisioning)
+	})
+	if err != nil && !dataservices.IsErrObjectNotFound(err) {
+		log.Warn().Err(err).Int("provisioning_id", int(provisioningID)).Msg("unable to update the cloud provisioning")
+	}
+}
+
+func agentAddress(host string) string {
+	return net.JoinHostPort(host, strconv.Itoa(AgentPort))
+}
+
+// poll calls check until it reports that the resource is ready, fails or the context is done
+func poll[T any](ctx context.Context, interval time.Duration, check func() (T, bool, error)) (T, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		value, ready, err := check()
+		if err != nil || ready {
+			return value, err
+		}
+
+		select {
+		case <-ctx.Done():
+			var zero T
+
+			return zero, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testProvisioner struct {
+	request  Request
+	dockerVM func() (string, bool, error)
+}
+
+func (p *testProvisioner) CreateKubernetesCluster(ctx context.Context, request Request) (string, error) {
+	return "", errors.New("not implemented")
+}
+
+func (p *testProvisioner) KubernetesCluster(ctx context.Context, region, resourceID string) ([]byte, bool, error) {
+	return nil, false, errors.New("not implemented")
+}
+
+func (p *testProvisioner) CreateDockerVM(ctx context.Context, request Request) (string, error) {
+	p.request = request
+
+	return "42", nil
+}
+
+func (p *testProvisioner) DockerVM(ctx context.Context, region, resourceID string) (string, bool, error) {
+	return p.dockerVM()
+}
+
+type testSnapshotService struct {
+	portainer.SnapshotService
+	attempts int
+}
+
+func (s *testSnapshotService) SnapshotEndpoint(endpoint *portainer.Endpoint) error {
+	s.attempts++
+	if s.attempts < 3 {
+		return errors.New("connection refused")
+	}
+
+	return nil
+}
+
+func newTestService(t *testing.T, provisioner Provisioner, snapshotService portainer.SnapshotService) (*Service, *datastore.Store) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	service := NewService(context.Background(), store, snapshotService)
+	service.newProvisioner = func(provider portainer.CloudProvider, token string) (Provisioner, error) {
+		return provisioner, nil
+	}
+	service.pollInterval = time.Millisecond
+	service.timeout = 5 * time.Second
+
+	return service, store
+}
+
+func waitForStatus(t *testing.T, store *datastore.Store, provisioningID portainer.CloudProvisioningID) *portainer.CloudProvisioning {
+	var provisioning *portainer.CloudProvisioning
+
+	require.Eventually(t, func() bool {
+		var err error
+		provisioning, err = store.CloudProvisioning().Read(provisioningID)
+		require.NoError(t, err)
+
+		return provisioning.Status == portainer.CloudProvisioningReady || provisioning.Status == portainer.CloudProvisioningFailed
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return provisioning
+}
+
+func TestProvisionDockerVM(t *testing.T) {
+	polls := 0
+	provisioner := &testProvisioner{dockerVM: func() (string, bool, error) {
+		polls++
+
+		return "203.0.113.10", polls > 1, nil
+	}}
+	snapshotService := &testSnapshotService{}
+
+	service, store := newTestService(t, provisioner, snapshotService)
+
+	provisioning := &portainer.CloudProvisioning{
+		Provider: portainer.CloudProviderDigitalOcean,
+		Type:     portainer.CloudProvisioningDocker,
+		Name:     "web",
+		Region:   "fra1",
+		NodeSize: "s-1vcpu-1gb",
+		GroupID:  1,
+		TagIDs:   []portainer.TagID{},
+	}
+	require.NoError(t, service.Provision(provisioning, "token"))
+
+	provisioning = waitForStatus(t, store, provisioning.ID)
+	require.Equal(t, portainer.CloudProvisioningReady, provisioning.Status, provisioning.Error)
+	assert.Equal(t, "42", provisioning.ResourceID)
+	assert.Contains(t, provisioner.request.UserData, AgentImage())
+
+	// The environment is registered once the agent answers
+	assert.Equal(t, 3, snapshotService.attempts)
+
+	endpoint, err := store.Endpoint().Endpoint(provisioning.EndpointID)
+	require.NoError(t, err)
+	assert.Equal(t, "tcp://203.0.113.10:9001", endpoint.URL)
+	assert.Equal(t, portainer.AgentOnDockerEnvironment, endpoint.Type)
+
+	_, err = store.EndpointRelation().EndpointRelation(endpoint.ID)
+	require.NoError(t, err)
+}
+
+func TestProvisionFailure(t *testing.T) {
+	provisioner := &testProvisioner{dockerVM: func() (string, bool, error) {
+		return "", false, &APIError{StatusCode: 422, Message: "size is not available in the region"}
+	}}
+
+	service, store := newTestService(t, provisioner, &testSnapshotService{})
+
+	provisioning := &portainer.CloudProvisioning{Provider: portainer.CloudProviderCivo, Type: portainer.CloudProvisioningDocker, Name: "web"}
+	require.NoError(t, service.Provision(provisioning, "token"))
+
+	provisioning = waitForStatus(t, store, provisioning.ID)
+	assert.Equal(t, portainer.CloudProvisioningFailed, provisioning.Status)
+	assert.Equal(t, "the virtual machine is not running: size is not available in the region", provisioning.Error)
+}
+
+func TestStartFailsTheInterruptedProvisionings(t *testing.T) {
+	service, store := newTestService(t, nil, nil)
+
+	require.NoError(t, store.CloudProvisioning().Create(&portainer.CloudProvisioning{Status: portainer.CloudProvisioningCreating}))
+	require.NoError(t, store.CloudProvisioning().Create(&portainer.CloudProvisioning{Status: portainer.CloudProvisioningReady}))
+
+	require.NoError(t, service.Start())
+
+	provisioning, err := store.CloudProvisioning().Read(1)
+	require.NoError(t, err)
+	assert.Equal(t, portainer.CloudProvisioningFailed, provisioning.Status)
+	assert.Equal(t, ErrInterrupted.Error(), provisioning.Error)
+
+	provisioning, err = store.CloudProvisioning().Read(2)
+	require.NoError(t, err)
+	assert.Equal(t, portainer.CloudProvisioningReady, provisioning.Status)
+}
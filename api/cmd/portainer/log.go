@@ -5,9 +5,12 @@ import (
 	stdlog "log"
 	"os"
 
+	"github.com/portainer/portainer/pkg/redact"
+
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/rs/zerolog/pkgerrors"
+	"github.com/segmentio/encoding/json"
 )
 
 func configureLogger() {
@@ -15,6 +18,11 @@ func configureLogger() {
 	zerolog.ErrorStackMarshaler = pkgerrors.MarshalStack
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 
+	// Strip the secret fields of the values logged with Interface()
+	zerolog.InterfaceMarshalFunc = func(v any) ([]byte, error) {
+		return json.Marshal(redact.Redact(v, redact.Log))
+	}
+
 	stdlog.SetFlags(0)
 	stdlog.SetOutput(log.Logger)
 
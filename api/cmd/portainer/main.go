@@ -3,16 +3,17 @@ package main
 import (
 	"cmp"
 	"context"
-	"crypto/sha256"
 	"os"
-	"path"
+	"path/filepath"
 	"strings"
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/apikey"
+	"github.com/portainer/portainer/api/azure"
 	"github.com/portainer/portainer/api/build"
 	"github.com/portainer/portainer/api/chisel"
 	"github.com/portainer/portainer/api/cli"
+	"github.com/portainer/portainer/api/cloud"
 	"github.com/portainer/portainer/api/crypto"
 	"github.com/portainer/portainer/api/database"
 	"github.com/portainer/portainer/api/database/boltdb"
@@ -28,19 +29,43 @@ import (
 	"github.com/portainer/portainer/api/git"
 	"github.com/portainer/portainer/api/hostmanagement/openamt"
 	"github.com/portainer/portainer/api/http"
+	"github.com/portainer/portainer/api/http/client"
 	"github.com/portainer/portainer/api/http/proxy"
 	kubeproxy "github.com/portainer/portainer/api/http/proxy/factory/kubernetes"
+	"github.com/portainer/portainer/api/internal/acme"
+	"github.com/portainer/portainer/api/internal/activity"
+	"github.com/portainer/portainer/api/internal/amtpower"
 	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/deploymenthistory"
+	"github.com/portainer/portainer/api/internal/deploymentvalidation"
+	"github.com/portainer/portainer/api/internal/dockerhub"
 	"github.com/portainer/portainer/api/internal/edge/edgestacks"
+	"github.com/portainer/portainer/api/internal/edge/updateschedules"
 	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/events"
+	"github.com/portainer/portainer/api/internal/failover"
+	"github.com/portainer/portainer/api/internal/gc"
+	"github.com/portainer/portainer/api/internal/imageupdate"
+	"github.com/portainer/portainer/api/internal/insights"
+	"github.com/portainer/portainer/api/internal/metrics"
+	"github.com/portainer/portainer/api/internal/notifications"
+	"github.com/portainer/portainer/api/internal/ociartifact"
+	"github.com/portainer/portainer/api/internal/quotas"
+	"github.com/portainer/portainer/api/internal/registryaccess"
+	"github.com/portainer/portainer/api/internal/registrytokens"
+	"github.com/portainer/portainer/api/internal/reports"
+	"github.com/portainer/portainer/api/internal/settingsbus"
 	"github.com/portainer/portainer/api/internal/snapshot"
+	"github.com/portainer/portainer/api/internal/snapshotwebhook"
 	"github.com/portainer/portainer/api/internal/ssl"
+	"github.com/portainer/portainer/api/internal/templatesources"
 	"github.com/portainer/portainer/api/internal/upgrade"
 	"github.com/portainer/portainer/api/jwt"
 	"github.com/portainer/portainer/api/kubernetes"
 	kubecli "github.com/portainer/portainer/api/kubernetes/cli"
 	"github.com/portainer/portainer/api/ldap"
 	"github.com/portainer/portainer/api/oauth"
+	"github.com/portainer/portainer/api/objectstorage"
 	"github.com/portainer/portainer/api/pendingactions"
 	"github.com/portainer/portainer/api/pendingactions/actions"
 	"github.com/portainer/portainer/api/pendingactions/handlers"
@@ -90,6 +115,7 @@ func initDataStore(flags *portainer.CLIFlags, secretKey []byte, fileService port
 		bconn.MaxBatchSize = *flags.MaxBatchSize
 		bconn.MaxBatchDelay = *flags.MaxBatchDelay
 		bconn.InitialMmapSize = *flags.InitialMmapSize
+		bconn.SlowQueryThreshold = *flags.SlowQueryThreshold
 	} else {
 		log.Fatal().Msg("failed creating database connection: expecting a boltdb database type but a different one was received")
 	}
@@ -110,6 +136,11 @@ func initDataStore(flags *portainer.CLIFlags, secretKey []byte, fileService port
 		os.Exit(0)
 	}
 
+	if *flags.NewSecretKeyName != "" {
+		reencryptDataStore(store, *flags.NewSecretKeyName)
+		os.Exit(0)
+	}
+
 	// Init sets some defaults - it's basically a migration
 	if err := store.Init(); err != nil {
 		log.Fatal().Err(err).Msg("failed initializing data store")
@@ -166,8 +197,8 @@ func checkDBSchemaServerVersionMatch(dbStore dataservices.DataStore, serverVersi
 	return v.SchemaVersion == serverVersion && v.Edition == serverEdition
 }
 
-func initComposeStackManager(composeDeployer libstack.Deployer, proxyManager *proxy.Manager) portainer.ComposeStackManager {
-	composeWrapper, err := exec.NewComposeStackManager(composeDeployer, proxyManager)
+func initComposeStackManager(composeDeployer libstack.Deployer, composeBinaries *exec.ComposeBinaries, proxyManager *proxy.Manager) portainer.ComposeStackManager {
+	composeWrapper, err := exec.NewComposeStackManager(composeDeployer, composeBinaries, proxyManager)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed creating compose manager")
 	}
@@ -203,7 +234,16 @@ func initJWTService(userSessionTimeout string, dataStore dataservices.DataStore)
 		userSessionTimeout = portainer.DefaultUserSessionTimeout
 	}
 
-	return jwt.NewService(userSessionTimeout, dataStore)
+	jwtService, err := jwt.NewService(userSessionTimeout, dataStore)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := jwtService.LoadSigningKeys(); err != nil {
+		return nil, err
+	}
+
+	return jwtService, nil
 }
 
 func initDigitalSignatureService() portainer.DigitalSignatureService {
@@ -234,7 +274,7 @@ func initSnapshotService(
 	kubernetesClientFactory *kubecli.ClientFactory,
 	shutdownCtx context.Context,
 	pendingActionsService *pendingactions.PendingActionsService,
-) (portainer.SnapshotService, error) {
+) (*snapshot.Service, error) {
 	dockerSnapshotter := docker.NewSnapshotter(dockerClientFactory)
 	kubernetesSnapshotter := kubernetes.NewSnapshotter(kubernetesClientFactory)
 
@@ -268,6 +308,16 @@ func updateSettingsFromFlags(dataStore dataservices.DataStore, flags *portainer.
 		settings.BlackListedLabels = *flags.Labels
 	}
 
+	if *flags.HTTPProxy != "" {
+		proxyConfig, err := client.ProxyConfigFromURL(*flags.HTTPProxy)
+		if err != nil {
+			return err
+		}
+
+		proxyConfig.NoProxy = *flags.NoProxy
+		settings.ProxyConfig = proxyConfig
+	}
+
 	settings.AgentSecret = ""
 	if agentKey, ok := os.LookupEnv("AGENT_SECRET"); ok {
 		settings.AgentSecret = agentKey
@@ -324,8 +374,27 @@ func initKeyPair(fileService portainer.FileService, signatureService portainer.D
 	return generateAndStoreKeyPair(fileService, signatureService)
 }
 
+// reencryptDataStore encrypts the database with the secret key /run/secrets/<newSecretKeyName>, an unencrypted
+// database is encrypted and the key named by --secret-key-name is replaced otherwise
+func reencryptDataStore(store dataservices.DataStore, newSecretKeyName string) {
+	newKey, err := database.LoadEncryptionKey(newSecretKeyName)
+	if err != nil {
+		log.Fatal().Err(err).Str("filename", newSecretKeyName).Msg("failed loading the new encryption key")
+	}
+
+	if err := store.Connection().Reencrypt(newKey); err != nil {
+		log.Fatal().Err(err).Msg("failed re-encrypting the database, it was left unchanged")
+	}
+
+	if err := store.Close(); err != nil {
+		log.Error().Err(err).Msg("failed closing the database")
+	}
+
+	log.Info().Str("secret_key_name", newSecretKeyName).Msg("database re-encrypted, start Portainer with --secret-key-name set to the new secret key name")
+}
+
 func loadEncryptionSecretKey(keyfilename string) []byte {
-	content, err := os.ReadFile(path.Join("/run/secrets", keyfilename))
+	key, err := database.LoadEncryptionKey(keyfilename)
 	if err != nil {
 		if os.IsNotExist(err) {
 			log.Info().Str("filename", keyfilename).Msg("encryption key file not present")
@@ -336,10 +405,7 @@ func loadEncryptionSecretKey(keyfilename string) []byte {
 		return nil
 	}
 
-	// return a 32 byte hash of the secret (required for AES)
-	hash := sha256.Sum256(content)
-
-	return hash[:]
+	return key
 }
 
 func buildServer(flags *portainer.CLIFlags) portainer.Server {
@@ -378,6 +444,12 @@ func buildServer(flags *portainer.CLIFlags) portainer.Server {
 		log.Fatal().Err(err).Msg("")
 	}
 
+	if err := client.SetProxyConfig(settings.ProxyConfig); err != nil {
+		log.Fatal().Err(err).Msg("failed initializing the HTTP proxy of outbound calls")
+	}
+
+	fileService.SetObjectStorage(objectstorage.FromSettings(settings.ObjectStorage))
+
 	jwtService, err := initJWTService(settings.UserSessionTimeout, dataStore)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed initializing JWT service")
@@ -388,8 +460,10 @@ func buildServer(flags *portainer.CLIFlags) portainer.Server {
 	oauthService := oauth.NewService()
 
 	gitService := git.NewService(shutdownCtx)
+	gitService.SetKnownHosts(git.NewKnownHosts(filepath.Join(*flags.Data, "ssh", "known_hosts")))
 
 	openAMTService := openamt.NewService()
+	amtPowerService := amtpower.NewService(dataStore, openAMTService)
 
 	cryptoService := &crypto.Service{}
 
@@ -413,6 +487,12 @@ func buildServer(flags *portainer.CLIFlags) portainer.Server {
 
 	reverseTunnelService := chisel.NewService(dataStore, shutdownCtx, fileService)
 
+	if *flags.Metrics {
+		if err := metrics.RegisterTunnelStatusCounter(reverseTunnelService.TunnelCountByStatus); err != nil {
+			log.Fatal().Err(err).Msg("failed registering the tunnel metrics")
+		}
+	}
+
 	dockerClientFactory := dockerclient.NewClientFactory(signatureService, reverseTunnelService)
 
 	kubernetesClientFactory, err := kubecli.NewClientFactory(signatureService, reverseTunnelService, dataStore, instanceID, *flags.AddrHTTPS, settings.UserSessionTimeout)
@@ -424,6 +504,7 @@ func buildServer(flags *portainer.CLIFlags) portainer.Server {
 	authorizationService.K8sClientFactory = kubernetesClientFactory
 
 	kubernetesTokenCacheManager := kubeproxy.NewTokenCacheManager()
+	kubernetesClusterAdminAuditLog := kubeproxy.NewClusterAdminAuditLog(kubeproxy.DefaultClusterAdminAuditLogSize)
 
 	kubeClusterAccessService := kubernetes.NewKubeClusterAccessService(*flags.BaseURL, *flags.AddrHTTPS, sslSettings.CertPath)
 
@@ -438,7 +519,9 @@ func buildServer(flags *portainer.CLIFlags) portainer.Server {
 		log.Fatal().Err(err).Msg("failed initializing compose deployer")
 	}
 
-	composeStackManager := initComposeStackManager(composeDeployer, proxyManager)
+	composeBinaries := exec.NewComposeBinaries(fileService.GetBinaryFolder(), dockerConfigPath)
+
+	composeStackManager := initComposeStackManager(composeDeployer, composeBinaries, proxyManager)
 
 	swarmStackManager, err := initSwarmStackManager(*flags.Assets, dockerConfigPath, signatureService, fileService, reverseTunnelService, dataStore)
 	if err != nil {
@@ -447,9 +530,19 @@ func buildServer(flags *portainer.CLIFlags) portainer.Server {
 
 	kubernetesDeployer := initKubernetesDeployer(kubernetesTokenCacheManager, kubernetesClientFactory, dataStore, reverseTunnelService, signatureService, proxyManager, *flags.Assets)
 
+	deploymentHistoryService := deploymenthistory.NewService(dataStore)
+	deploymentHistoryService.SetValidator(deploymentvalidation.NewService(dataStore))
+	composeStackManager = deploymenthistory.NewComposeStackManager(composeStackManager, deploymentHistoryService)
+	swarmStackManager = deploymenthistory.NewSwarmStackManager(swarmStackManager, deploymentHistoryService)
+	kubernetesDeployer = deploymenthistory.NewKubernetesDeployer(kubernetesDeployer, deploymentHistoryService)
+
+	stackDeployer := deployments.NewStackDeployer(swarmStackManager, composeStackManager, kubernetesDeployer, dockerClientFactory, dataStore)
+
 	pendingActionsService := pendingactions.NewService(dataStore, kubernetesClientFactory)
 	pendingActionsService.RegisterHandler(actions.CleanNAPWithOverridePolicies, handlers.NewHandlerCleanNAPWithOverridePolicies(authorizationService, dataStore))
+	pendingActionsService.RegisterHandler(actions.CollectEdgeJobLogs, handlers.NewHandlerCollectEdgeJobLogs(dataStore))
 	pendingActionsService.RegisterHandler(actions.DeletePortainerK8sRegistrySecrets, handlers.NewHandlerDeleteRegistrySecrets(authorizationService, dataStore, kubernetesClientFactory))
+	pendingActionsService.RegisterHandler(actions.DeployStack, handlers.NewHandlerDeployStack(dataStore, fileService, stackDeployer, dockerClientFactory))
 	pendingActionsService.RegisterHandler(actions.PostInitMigrateEnvironment, handlers.NewHandlerPostInitMigrateEnvironment(authorizationService, dataStore, kubernetesClientFactory, dockerClientFactory, *flags.Assets, kubernetesDeployer))
 
 	snapshotService, err := initSnapshotService(*flags.SnapshotInterval, dataStore, dockerClientFactory, kubernetesClientFactory, shutdownCtx, pendingActionsService)
@@ -457,9 +550,20 @@ func buildServer(flags *portainer.CLIFlags) portainer.Server {
 		log.Fatal().Err(err).Msg("failed initializing snapshot service")
 	}
 
+	azureClientFactory := azure.NewClientFactory()
+
+	snapshotService.SetNotifier(snapshotwebhook.NewService(dataStore))
+	snapshotService.SetAzureSnapshotter(azure.NewSnapshotter(azureClientFactory))
 	snapshotService.Start()
 
-	proxyManager.NewProxyFactory(dataStore, signatureService, reverseTunnelService, dockerClientFactory, kubernetesClientFactory, kubernetesTokenCacheManager, gitService, snapshotService)
+	settingsBus := settingsbus.New()
+	settingsBus.Subscribe(snapshotService.SettingsChanged)
+	settingsBus.Subscribe(reverseTunnelService.SettingsChanged)
+	settingsBus.Subscribe(objectstorage.SettingsListener(fileService))
+
+	quotaService := quotas.NewService(dataStore, dockerClientFactory)
+
+	proxyManager.NewProxyFactory(dataStore, signatureService, reverseTunnelService, dockerClientFactory, kubernetesClientFactory, kubernetesTokenCacheManager, kubernetesClusterAdminAuditLog, gitService, snapshotService, quotaService)
 
 	helmPackageManager, err := initHelmPackageManager(*flags.Assets)
 	if err != nil {
@@ -519,15 +623,71 @@ func buildServer(flags *portainer.CLIFlags) portainer.Server {
 		log.Fatal().Err(err).Msg("failed starting tunnel server")
 	}
 
+	ociArtifactService := ociartifact.NewService(dataStore)
+
 	scheduler := scheduler.NewScheduler(shutdownCtx)
-	stackDeployer := deployments.NewStackDeployer(swarmStackManager, composeStackManager, kubernetesDeployer, dockerClientFactory, dataStore)
-	deployments.StartStackSchedules(scheduler, stackDeployer, dataStore, gitService)
+	deployments.StartStackSchedules(scheduler, stackDeployer, dataStore, gitService, ociArtifactService)
+	scheduler.StartJobEvery(edgestacks.RolloutProgressInterval, edgeStacksService.ProgressRollouts)
+
+	templateSourcesService := templatesources.NewService(dataStore, gitService, fileService)
+	settingsBus.Subscribe(templateSourcesService.SettingsChanged)
+	scheduler.StartJobEvery(templatesources.RefreshInterval, templateSourcesService.RefreshAll)
+
+	edgeUpdateService := updateschedules.NewService(dataStore, fileService)
+	scheduler.StartJobEvery(updateschedules.ProgressInterval, edgeUpdateService.ProgressSchedules)
+
+	imageUpdateService := imageupdate.NewService(shutdownCtx, dataStore, dockerClientFactory, docker.NewContainerService(dockerClientFactory, dataStore), scheduler)
+	if err := imageUpdateService.Start(); err != nil {
+		log.Fatal().Err(err).Msg("failed starting image update jobs")
+	}
+
+	reportService := reports.NewService(dataStore, notifications.NewSMTPSender(), scheduler)
+	if err := reportService.Start(); err != nil {
+		log.Fatal().Err(err).Msg("failed starting group reports")
+	}
+
+	notificationService := notifications.NewService(dataStore, notifications.NewSMTPSender())
+	events.Subscribe(notificationService.EventPublished)
+
+	failoverService := failover.NewService(dataStore, dockerClientFactory, stackDeployer)
+	scheduler.StartJobEvery(failover.CheckInterval, failoverService.CheckPolicies)
+	scheduler.StartJobEvery(amtpower.EvaluationInterval, amtPowerService.EvaluateSchedules)
+
+	registryTokensService := registrytokens.NewService(dataStore, kubernetesClientFactory)
+	scheduler.StartJobEvery(registrytokens.RefreshInterval, registryTokensService.RefreshTokens)
+
+	registryAccessService := registryaccess.NewService(dataStore, kubernetesClientFactory)
+	scheduler.StartJobEvery(registryaccess.ReconcileInterval, registryAccessService.Reconcile)
+
+	acmeService := acme.NewService(dataStore, fileService, sslService)
+	scheduler.StartJobEvery(acme.RenewalCheckInterval, acmeService.RenewIfNeeded)
+
+	cloudService := cloud.NewService(shutdownCtx, dataStore, snapshotService)
+	if err := cloudService.Start(); err != nil {
+		log.Fatal().Err(err).Msg("failed starting cloud provisionings")
+	}
 
 	sslDBSettings, err := dataStore.SSLSettings().Settings()
 	if err != nil {
 		log.Fatal().Msg("failed to fetch SSL settings from DB")
 	}
 
+	insightsService := insights.NewService(dataStore, dockerClientFactory)
+	scheduler.StartJobEvery(insights.CheckInterval, insightsService.CheckEndpoints)
+
+	dockerHubService := dockerhub.NewService(dataStore)
+
+	activityConsumer := activity.NewConsumer(dataStore, dockerClientFactory, shutdownCtx)
+	if err := activityConsumer.Sync(); err != nil {
+		log.Warn().Err(err).Msg("failed subscribing to the Docker events of the environments")
+	}
+	scheduler.StartJobEvery(activity.SyncInterval, activityConsumer.Sync)
+
+	gcService := gc.NewService(dataStore, dockerClientFactory, fileService, scheduler)
+	if err := gcService.StartPrunePolicies(); err != nil {
+		log.Fatal().Err(err).Msg("failed starting prune policies")
+	}
+
 	platformService, err := platform.NewService(dataStore)
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed initializing platform service")
@@ -562,43 +722,63 @@ func buildServer(flags *portainer.CLIFlags) portainer.Server {
 	}
 
 	return &http.Server{
-		AuthorizationService:        authorizationService,
-		ReverseTunnelService:        reverseTunnelService,
-		Status:                      applicationStatus,
-		BindAddress:                 *flags.Addr,
-		BindAddressHTTPS:            *flags.AddrHTTPS,
-		HTTPEnabled:                 sslDBSettings.HTTPEnabled,
-		AssetsPath:                  *flags.Assets,
-		DataStore:                   dataStore,
-		EdgeStacksService:           edgeStacksService,
-		SwarmStackManager:           swarmStackManager,
-		ComposeStackManager:         composeStackManager,
-		KubernetesDeployer:          kubernetesDeployer,
-		HelmPackageManager:          helmPackageManager,
-		APIKeyService:               apiKeyService,
-		CryptoService:               cryptoService,
-		JWTService:                  jwtService,
-		FileService:                 fileService,
-		LDAPService:                 ldapService,
-		OAuthService:                oauthService,
-		GitService:                  gitService,
-		OpenAMTService:              openAMTService,
-		ProxyManager:                proxyManager,
-		KubernetesTokenCacheManager: kubernetesTokenCacheManager,
-		KubeClusterAccessService:    kubeClusterAccessService,
-		SignatureService:            signatureService,
-		SnapshotService:             snapshotService,
-		SSLService:                  sslService,
-		DockerClientFactory:         dockerClientFactory,
-		KubernetesClientFactory:     kubernetesClientFactory,
-		Scheduler:                   scheduler,
-		ShutdownCtx:                 shutdownCtx,
-		ShutdownTrigger:             shutdownTrigger,
-		StackDeployer:               stackDeployer,
-		UpgradeService:              upgradeService,
-		AdminCreationDone:           adminCreationDone,
-		PendingActionsService:       pendingActionsService,
-		PlatformService:             platformService,
+		ACMEService:                    acmeService,
+		AuthorizationService:           authorizationService,
+		ReverseTunnelService:           reverseTunnelService,
+		Status:                         applicationStatus,
+		BindAddress:                    *flags.Addr,
+		BindAddressHTTPS:               *flags.AddrHTTPS,
+		HTTPEnabled:                    sslDBSettings.HTTPEnabled,
+		MetricsEnabled:                 *flags.Metrics,
+		AssetsPath:                     *flags.Assets,
+		DataStore:                      dataStore,
+		EdgeStacksService:              edgeStacksService,
+		SwarmStackManager:              swarmStackManager,
+		ComposeStackManager:            composeStackManager,
+		KubernetesDeployer:             kubernetesDeployer,
+		HelmPackageManager:             helmPackageManager,
+		InsightsService:                insightsService,
+		DockerHubService:               dockerHubService,
+		ReportService:                  reportService,
+		ImageUpdateService:             imageUpdateService,
+		FailoverService:                failoverService,
+		RegistryAccessService:          registryAccessService,
+		NotificationService:            notificationService,
+		EdgeUpdateService:              edgeUpdateService,
+		QuotaService:                   quotaService,
+		GCService:                      gcService,
+		APIKeyService:                  apiKeyService,
+		CryptoService:                  cryptoService,
+		DeploymentHistoryService:       deploymentHistoryService,
+		JWTService:                     jwtService,
+		FileService:                    fileService,
+		LDAPService:                    ldapService,
+		OAuthService:                   oauthService,
+		GitService:                     gitService,
+		OCIArtifactService:             ociArtifactService,
+		TemplateSources:                templateSourcesService,
+		OpenAMTService:                 openAMTService,
+		AMTPowerService:                amtPowerService,
+		ProxyManager:                   proxyManager,
+		KubernetesTokenCacheManager:    kubernetesTokenCacheManager,
+		KubernetesClusterAdminAuditLog: kubernetesClusterAdminAuditLog,
+		KubeClusterAccessService:       kubeClusterAccessService,
+		SignatureService:               signatureService,
+		SnapshotService:                snapshotService,
+		SettingsBus:                    settingsBus,
+		SSLService:                     sslService,
+		DockerClientFactory:            dockerClientFactory,
+		AzureClientFactory:             azureClientFactory,
+		CloudService:                   cloudService,
+		KubernetesClientFactory:        kubernetesClientFactory,
+		Scheduler:                      scheduler,
+		ShutdownCtx:                    shutdownCtx,
+		ShutdownTrigger:                shutdownTrigger,
+		StackDeployer:                  stackDeployer,
+		UpgradeService:                 upgradeService,
+		AdminCreationDone:              adminCreationDone,
+		PendingActionsService:          pendingActionsService,
+		PlatformService:                platformService,
 	}
 }
 
@@ -4,6 +4,20 @@ import (
 	"io"
 )
 
+// SlowQuery is a database query that lasted longer than the slow query threshold, the content of the
+// objects queried is never recorded
+type SlowQuery struct {
+	// Bucket of the dataservice queried
+	Bucket    string `json:"Bucket" example:"endpoints"`
+	Operation string `json:"Operation" example:"GetAll"`
+	// Key of the object queried, empty for the operations on the whole bucket
+	Key string `json:"Key,omitempty" example:"1"`
+	// Duration of the query in milliseconds
+	Duration float64 `json:"Duration" example:"153.2"`
+	// Unix timestamp of the query
+	Time int64 `json:"Time" example:"1708000000"`
+}
+
 type ReadTransaction interface {
 	GetObject(bucketName string, key []byte, object any) error
 	GetAll(bucketName string, obj any, append func(o any) (any, error)) error
@@ -44,10 +58,15 @@ type Connection interface {
 	IsEncryptedStore() bool
 	NeedsEncryptionMigration() (bool, error)
 	SetEncrypted(encrypted bool)
+	// Reencrypt encrypts every object with the given key in a single transaction, replacing the current key
+	Reencrypt(newKey []byte) error
 
 	BackupMetadata() (map[string]any, error)
 	RestoreMetadata(s map[string]any) error
 
 	UpdateObjectFunc(bucketName string, key []byte, object any, updateFn func()) error
 	ConvertToKey(v int) []byte
+
+	// SlowQueries returns the slowest queries recorded since the start, the slowest first
+	SlowQueries() []SlowQuery
 }
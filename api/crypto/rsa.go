@@ -0,0 +1,103 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+)
+
+const (
+	// RSAPrivateKeyPemHeader represents the header that is appended to the PEM file when
+	// storing the private key.
+	RSAPrivateKeyPemHeader = "RSA PRIVATE KEY"
+	// RSAPublicKeyPemHeader represents the header that is appended to the PEM file when
+	// storing the public key.
+	RSAPublicKeyPemHeader = "PUBLIC KEY"
+
+	rsaKeySize = 2048
+)
+
+// RSAService is a service used to create digital signatures with a RSA key pair. It will
+// automatically generate a key pair or can reuse an existing one.
+type RSAService struct {
+	privateKey    *rsa.PrivateKey
+	publicKey     *rsa.PublicKey
+	encodedPubKey string
+}
+
+// NewRSAService returns a pointer to a RSAService.
+func NewRSAService() *RSAService {
+	return &RSAService{}
+}
+
+// EncodedPublicKey returns the hexadecimal encoding of the public key content.
+func (service *RSAService) EncodedPublicKey() string {
+	return service.encodedPubKey
+}
+
+// PEMHeaders returns the RSA PEM headers.
+func (service *RSAService) PEMHeaders() (string, string) {
+	return RSAPrivateKeyPemHeader, RSAPublicKeyPemHeader
+}
+
+// ParseKeyPair parses existing private/public key pair content and associate
+// the parsed keys to the service.
+func (service *RSAService) ParseKeyPair(private, public []byte) error {
+	privateKey, err := x509.ParsePKCS1PrivateKey(private)
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(public)
+	if err != nil {
+		return err
+	}
+
+	rsaPublicKey, ok := publicKey.(*rsa.PublicKey)
+	if !ok {
+		return errors.New("the public key is not a RSA key")
+	}
+
+	service.privateKey = privateKey
+	service.publicKey = rsaPublicKey
+	service.encodedPubKey = hex.EncodeToString(public)
+
+	return nil
+}
+
+// GenerateKeyPair will create a new RSA key pair.
+func (service *RSAService) GenerateKeyPair() ([]byte, []byte, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, rsaKeySize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	public, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	service.privateKey = privateKey
+	service.publicKey = &privateKey.PublicKey
+	service.encodedPubKey = hex.EncodeToString(public)
+
+	return x509.MarshalPKCS1PrivateKey(privateKey), public, nil
+}
+
+// CreateSignature hashes the message using SHA-256, signs the hash with RSASSA-PKCS1-v1_5
+// and encodes the signature in base64.
+func (service *RSAService) CreateSignature(message string) (string, error) {
+	hash := sha256.Sum256([]byte(message))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, service.privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawStdEncoding.EncodeToString(signature), nil
+}
@@ -9,10 +9,12 @@ import (
 	"os"
 	"path"
 	"strconv"
+	"sync"
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
 	dserrors "github.com/portainer/portainer/api/dataservices/errors"
+	"github.com/portainer/portainer/api/internal/metrics"
 
 	"github.com/rs/zerolog/log"
 	bolt "go.etcd.io/bbolt"
@@ -34,7 +36,18 @@ type DbConnection struct {
 	MaxBatchDelay   time.Duration
 	InitialMmapSize int
 	EncryptionKey   []byte
-	isEncrypted     bool
+	// Duration above which the queries are logged as slow, the slow query log is disabled when 0
+	SlowQueryThreshold time.Duration
+	isEncrypted        bool
+	slowQueries        slowQueryLog
+
+	// keyMu guards the encryption key, it is replaced when the database is re-encrypted
+	keyMu sync.RWMutex
+	// Key used before the last re-encryption
+	previousEncryptionKey []byte
+	// Whether the database was not encrypted before the last re-encryption
+	previouslyUnencrypted bool
+	reencrypted           bool
 
 	*bolt.DB
 }
@@ -63,6 +76,9 @@ func (connection *DbConnection) GetStorePath() string {
 }
 
 func (connection *DbConnection) SetEncrypted(flag bool) {
+	connection.keyMu.Lock()
+	defer connection.keyMu.Unlock()
+
 	connection.isEncrypted = flag
 }
 
@@ -161,6 +177,8 @@ func (connection *DbConnection) txFn(fn func(portainer.Transaction) error) func(
 
 // UpdateTx executes the given function inside a read-write transaction
 func (connection *DbConnection) UpdateTx(fn func(portainer.Transaction) error) error {
+	defer metrics.TransactionStarted("write")()
+
 	if connection.MaxBatchDelay > 0 && connection.MaxBatchSize > 1 {
 		return connection.Batch(connection.txFn(fn))
 	}
@@ -170,6 +188,8 @@ func (connection *DbConnection) UpdateTx(fn func(portainer.Transaction) error) e
 
 // ViewTx executes the given function inside a read-only transaction
 func (connection *DbConnection) ViewTx(fn func(portainer.Transaction) error) error {
+	defer metrics.TransactionStarted("read")()
+
 	return connection.View(connection.txFn(fn))
 }
 
@@ -236,6 +256,9 @@ func (connection *DbConnection) GetObject(bucketName string, key []byte, object
 }
 
 func (connection *DbConnection) getEncryptionKey() []byte {
+	connection.keyMu.RLock()
+	defer connection.keyMu.RUnlock()
+
 	if !connection.isEncrypted {
 		return nil
 	}
@@ -252,6 +275,8 @@ func (connection *DbConnection) UpdateObject(bucketName string, key []byte, obje
 
 // UpdateObjectFunc is a generic function used to update an object safely without race conditions.
 func (connection *DbConnection) UpdateObjectFunc(bucketName string, key []byte, object any, updateFn func()) error {
+	defer connection.observeQuery(bucketName, "UpdateObjectFunc", key, time.Now())
+
 	return connection.Batch(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(bucketName))
 
@@ -40,11 +40,17 @@ func (connection *DbConnection) MarshalObject(object any) ([]byte, error) {
 // UnmarshalObject decodes an object from binary data
 func (connection *DbConnection) UnmarshalObject(data []byte, object any) error {
 	var err error
-	if connection.getEncryptionKey() != nil {
-		data, err = decrypt(data, connection.getEncryptionKey())
+	if key := connection.getEncryptionKey(); key != nil {
+		plaintext, err := decrypt(data, key)
 		if err != nil {
-			return errors.Wrap(err, "Failed decrypting object")
+			// The transactions started before a re-encryption still read the objects encrypted with the previous key
+			var previousErr error
+			if plaintext, previousErr = connection.decryptWithPreviousKey(data); previousErr != nil {
+				return errors.Wrap(err, "Failed decrypting object")
+			}
 		}
+
+		data = plaintext
 	}
 
 	if e := json.Unmarshal(data, object); e != nil {
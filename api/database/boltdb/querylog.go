@@ -0,0 +1,123 @@
+package boltdb
+
+import (
+	"encoding/binary"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+	"unicode"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/metrics"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// slowQueryLogSize is the number of slow queries kept in the slow query log
+	slowQueryLogSize = 50
+	// maxLoggedKeyLength is the length above which the keys are not recorded
+	maxLoggedKeyLength = 64
+	redactedKey        = "(redacted)"
+)
+
+// slowQueryLog keeps the slowest queries, the slowest first
+type slowQueryLog struct {
+	mu      sync.Mutex
+	queries []portainer.SlowQuery
+}
+
+func (l *slowQueryLog) add(query portainer.SlowQuery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	i, _ := slices.BinarySearchFunc(l.queries, query.Duration, func(q portainer.SlowQuery, duration float64) int {
+		switch {
+		case q.Duration > duration:
+			return -1
+		case q.Duration < duration:
+			return 1
+		}
+
+		return 0
+	})
+
+	if i >= slowQueryLogSize {
+		return
+	}
+
+	l.queries = slices.Insert(l.queries, i, query)
+	if len(l.queries) > slowQueryLogSize {
+		l.queries = l.queries[:slowQueryLogSize]
+	}
+}
+
+func (l *slowQueryLog) list() []portainer.SlowQuery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return slices.Clone(l.queries)
+}
+
+// SlowQueries returns the slowest queries recorded since the start, the slowest first
+func (connection *DbConnection) SlowQueries() []portainer.SlowQuery {
+	queries := connection.slowQueries.list()
+	if queries == nil {
+		return []portainer.SlowQuery{}
+	}
+
+	return queries
+}
+
+// observeQuery records the duration of a query started at start, the queries lasting longer than the
+// slow query threshold are logged along with their bucket and key
+func (connection *DbConnection) observeQuery(bucketName, operation string, key []byte, start time.Time) {
+	elapsed := time.Since(start)
+
+	metrics.ObserveDatabaseQuery(bucketName, operation, elapsed.Seconds())
+
+	if connection.SlowQueryThreshold <= 0 || elapsed < connection.SlowQueryThreshold {
+		return
+	}
+
+	query := portainer.SlowQuery{
+		Bucket:    bucketName,
+		Operation: operation,
+		Key:       sanitizeKey(key),
+		Duration:  float64(elapsed.Microseconds()) / 1000,
+		Time:      start.Unix(),
+	}
+
+	log.Warn().
+		Str("bucket", query.Bucket).
+		Str("operation", query.Operation).
+		Str("key", query.Key).
+		Dur("duration", elapsed).
+		Msg("slow database query")
+
+	connection.slowQueries.add(query)
+}
+
+// sanitizeKey returns a printable representation of a key, the identifiers are decoded and
+// the other binary keys are left out
+func sanitizeKey(key []byte) string {
+	if len(key) == 8 {
+		if v := binary.BigEndian.Uint64(key); v <= math.MaxInt32 {
+			return strconv.FormatUint(v, 10)
+		}
+	}
+
+	if len(key) > maxLoggedKeyLength {
+		return redactedKey
+	}
+
+	for _, r := range string(key) {
+		if !unicode.IsPrint(r) {
+			return redactedKey
+		}
+	}
+
+	return string(key)
+}
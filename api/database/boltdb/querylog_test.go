@@ -0,0 +1,50 @@
+package boltdb
+
+import (
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	var log slowQueryLog
+
+	for i := range slowQueryLogSize + 10 {
+		log.add(portainer.SlowQuery{Bucket: "endpoints", Duration: float64(i)})
+	}
+
+	queries := log.list()
+	require.Len(t, queries, slowQueryLogSize)
+
+	// The slowest queries are kept, the slowest first
+	assert.InDelta(t, slowQueryLogSize+9, queries[0].Duration, 0)
+	assert.InDelta(t, 10, queries[slowQueryLogSize-1].Duration, 0)
+}
+
+func TestObserveQuery(t *testing.T) {
+	conn := DbConnection{SlowQueryThreshold: time.Millisecond}
+
+	conn.observeQuery("endpoints", "GetObject", conn.ConvertToKey(7), time.Now())
+	assert.Empty(t, conn.SlowQueries())
+
+	conn.observeQuery("endpoints", "GetObject", conn.ConvertToKey(7), time.Now().Add(-time.Second))
+	conn.observeQuery("settings", "GetObject", []byte("SETTINGS"), time.Now().Add(-2*time.Second))
+	conn.observeQuery("stacks", "GetAllWithKeyPrefix", []byte{0x00, 0x01, 0xff}, time.Now().Add(-3*time.Millisecond))
+
+	queries := conn.SlowQueries()
+	require.Len(t, queries, 3)
+
+	assert.Equal(t, "SETTINGS", queries[0].Key)
+	assert.Equal(t, "7", queries[1].Key)
+	assert.Equal(t, redactedKey, queries[2].Key)
+	assert.GreaterOrEqual(t, queries[1].Duration, 1000.0)
+
+	// The slow query log is disabled without threshold
+	disabled := DbConnection{}
+	disabled.observeQuery("endpoints", "GetAll", nil, time.Now().Add(-time.Second))
+	assert.Empty(t, disabled.SlowQueries())
+}
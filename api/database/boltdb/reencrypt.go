@@ -0,0 +1,181 @@
+package boltdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+	bolt "go.etcd.io/bbolt"
+)
+
+// encryptionKeySize is the size of the AES-256 keys derived from the secret keys
+const encryptionKeySize = 32
+
+var (
+	ErrInvalidEncryptionKey = errors.New("the encryption key must be 32 bytes long")
+	ErrSameEncryptionKey    = errors.New("the database is already encrypted with this key")
+
+	errNotReencrypted = errors.New("the database was not re-encrypted")
+)
+
+// Reencrypt encrypts every object of the database with newKey inside a single transaction, an unencrypted
+// database is encrypted and its file renamed. Every object is read back and verified before the transaction
+// is committed, nothing is changed when an object cannot be re-encrypted or verified.
+func (connection *DbConnection) Reencrypt(newKey []byte) error {
+	if len(newKey) != encryptionKeySize {
+		return ErrInvalidEncryptionKey
+	}
+
+	connection.keyMu.RLock()
+	encrypted := connection.isEncrypted
+	connection.keyMu.RUnlock()
+
+	currentKey := connection.getEncryptionKey()
+	if encrypted && len(currentKey) == 0 {
+		return ErrHaveEncryptedWithNoKey
+	}
+
+	if bytes.Equal(currentKey, newKey) {
+		return ErrSameEncryptionKey
+	}
+
+	databasePath := path.Join(connection.Path, DatabaseFileName)
+	encryptedDatabasePath := path.Join(connection.Path, EncryptedDatabaseFileName)
+
+	renamed := false
+	switched := false
+
+	err := connection.Update(func(tx *bolt.Tx) error {
+		// The objects cannot be read while they are re-encrypted
+		connection.keyMu.Lock()
+		defer connection.keyMu.Unlock()
+
+		if err := reencryptBuckets(tx, encrypted, currentKey, newKey); err != nil {
+			return err
+		}
+
+		// The file is renamed before the commit so that a failure leaves the database untouched,
+		// the open file descriptor keeps pointing to the renamed file
+		if !encrypted {
+			if _, err := os.Stat(encryptedDatabasePath); err == nil {
+				return ErrHaveEncryptedAndUnencrypted
+			}
+
+			if err := os.Rename(databasePath, encryptedDatabasePath); err != nil {
+				return fmt.Errorf("unable to rename the database file: %w", err)
+			}
+
+			renamed = true
+		}
+
+		connection.previousEncryptionKey = currentKey
+		connection.previouslyUnencrypted = !encrypted
+		connection.reencrypted = true
+		connection.EncryptionKey = newKey
+		connection.isEncrypted = true
+		switched = true
+
+		return nil
+	})
+	if err == nil {
+		log.Info().Msg("database successfully re-encrypted")
+
+		return nil
+	}
+
+	// The commit failed after the key was replaced, the database still holds the previous objects
+	if switched {
+		connection.keyMu.Lock()
+		connection.EncryptionKey = currentKey
+		connection.isEncrypted = encrypted
+		connection.previousEncryptionKey = nil
+		connection.previouslyUnencrypted = false
+		connection.reencrypted = false
+		connection.keyMu.Unlock()
+	}
+
+	if renamed {
+		if renameErr := os.Rename(encryptedDatabasePath, databasePath); renameErr != nil {
+			log.Error().Err(renameErr).Msg("unable to restore the name of the database file")
+		}
+	}
+
+	return err
+}
+
+// reencryptBuckets replaces every object of the database by the object encrypted with newKey, the objects
+// are read as plaintext only when the database is not encrypted. The transaction fails when an object cannot
+// be decrypted or read back.
+func reencryptBuckets(tx *bolt.Tx, encrypted bool, currentKey, newKey []byte) error {
+	type object struct {
+		key       []byte
+		plaintext []byte
+	}
+
+	return tx.ForEach(func(name []byte, bucket *bolt.Bucket) error {
+		var objects []object
+
+		if err := bucket.ForEach(func(k, v []byte) error {
+			// Nested buckets hold no object
+			if v == nil {
+				return nil
+			}
+
+			plaintext := v
+			if encrypted {
+				var err error
+				if plaintext, err = decrypt(v, currentKey); err != nil {
+					return fmt.Errorf("unable to decrypt the object %s of the bucket %s: %w", keyToString(k), name, err)
+				}
+			}
+
+			objects = append(objects, object{key: slices.Clone(k), plaintext: slices.Clone(plaintext)})
+
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		for _, o := range objects {
+			data, err := encrypt(o.plaintext, newKey)
+			if err != nil {
+				return fmt.Errorf("unable to encrypt the object %s of the bucket %s: %w", keyToString(o.key), name, err)
+			}
+
+			if err := bucket.Put(o.key, data); err != nil {
+				return err
+			}
+		}
+
+		for _, o := range objects {
+			plaintext, err := decrypt(bucket.Get(o.key), newKey)
+			if err != nil || !bytes.Equal(plaintext, o.plaintext) {
+				return fmt.Errorf("unable to verify the object %s of the bucket %s", keyToString(o.key), name)
+			}
+		}
+
+		return nil
+	})
+}
+
+// decryptWithPreviousKey decrypts an object with the key used before the last re-encryption, the object is
+// returned as is only when the database was not encrypted before. An error is returned when the database was
+// not re-encrypted or the object cannot be decrypted
+func (connection *DbConnection) decryptWithPreviousKey(data []byte) ([]byte, error) {
+	connection.keyMu.RLock()
+	defer connection.keyMu.RUnlock()
+
+	if !connection.reencrypted {
+		return nil, errNotReencrypted
+	}
+
+	if connection.previouslyUnencrypted {
+		return data, nil
+	}
+
+	return decrypt(data, connection.previousEncryptionKey)
+}
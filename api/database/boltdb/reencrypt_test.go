@@ -0,0 +1,122 @@
+package boltdb
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestReencrypt(t *testing.T) {
+	dir := t.TempDir()
+
+	conn := &DbConnection{Path: dir}
+	require.NoError(t, conn.Open())
+
+	newObj := testStruct{Key: "key", Value: "value"}
+	require.NoError(t, conn.UpdateTx(func(tx portainer.Transaction) error {
+		if err := tx.SetServiceName(testBucketName); err != nil {
+			return err
+		}
+
+		return tx.CreateObjectWithId(testBucketName, testId, newObj)
+	}))
+
+	readObject := func(conn *DbConnection) testStruct {
+		var obj testStruct
+		require.NoError(t, conn.GetObject(testBucketName, conn.ConvertToKey(testId), &obj))
+
+		return obj
+	}
+
+	// The unencrypted database is encrypted and renamed
+	firstKey := secretToEncryptionKey("first")
+	require.NoError(t, conn.Reencrypt(firstKey))
+	assert.True(t, conn.IsEncryptedStore())
+	assert.Equal(t, newObj, readObject(conn))
+	assert.NoFileExists(t, path.Join(dir, DatabaseFileName))
+	assert.FileExists(t, path.Join(dir, EncryptedDatabaseFileName))
+
+	require.ErrorIs(t, conn.Reencrypt(firstKey), ErrSameEncryptionKey)
+	require.ErrorIs(t, conn.Reencrypt([]byte("short")), ErrInvalidEncryptionKey)
+
+	// The key is rotated
+	secondKey := secretToEncryptionKey("second")
+	require.NoError(t, conn.Reencrypt(secondKey))
+	assert.Equal(t, newObj, readObject(conn))
+	require.NoError(t, conn.Close())
+
+	reopened := &DbConnection{Path: dir, EncryptionKey: secondKey}
+	needsMigration, err := reopened.NeedsEncryptionMigration()
+	require.NoError(t, err)
+	require.False(t, needsMigration)
+	require.NoError(t, reopened.Open())
+	defer reopened.Close()
+
+	assert.Equal(t, newObj, readObject(reopened))
+}
+
+func TestReencryptRollback(t *testing.T) {
+	dir := t.TempDir()
+
+	firstKey := secretToEncryptionKey("first")
+	conn := &DbConnection{Path: dir, EncryptionKey: firstKey}
+	conn.SetEncrypted(true)
+	require.NoError(t, conn.Open())
+	defer conn.Close()
+
+	newObj := testStruct{Key: "key", Value: "value"}
+	require.NoError(t, conn.UpdateTx(func(tx portainer.Transaction) error {
+		if err := tx.SetServiceName(testBucketName); err != nil {
+			return err
+		}
+
+		return tx.CreateObjectWithId(testBucketName, testId, newObj)
+	}))
+
+	// An object that cannot be decrypted makes the whole re-encryption fail
+	require.NoError(t, conn.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(testBucketName)).Put(conn.ConvertToKey(testId+1), []byte("not encrypted with the key"))
+	}))
+
+	require.Error(t, conn.Reencrypt(secretToEncryptionKey("second")))
+
+	var obj testStruct
+	require.NoError(t, conn.GetObject(testBucketName, conn.ConvertToKey(testId), &obj))
+	assert.Equal(t, newObj, obj)
+
+	_, err := os.Stat(path.Join(dir, EncryptedDatabaseFileName))
+	require.NoError(t, err)
+}
+
+func TestDecryptWithPreviousKey(t *testing.T) {
+	firstKey := secretToEncryptionKey("first")
+	conn := &DbConnection{Path: t.TempDir(), EncryptionKey: firstKey}
+	conn.SetEncrypted(true)
+	require.NoError(t, conn.Open())
+	defer conn.Close()
+
+	_, err := conn.decryptWithPreviousKey([]byte("plaintext"))
+	require.Error(t, err, "the database was not re-encrypted")
+
+	require.NoError(t, conn.Reencrypt(secretToEncryptionKey("second")))
+
+	encrypted, err := encrypt([]byte("previous"), firstKey)
+	require.NoError(t, err)
+
+	plaintext, err := conn.decryptWithPreviousKey(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(plaintext))
+
+	// The objects of an encrypted database are never read as plaintext
+	_, err = conn.decryptWithPreviousKey([]byte("plaintext"))
+	require.Error(t, err)
+
+	var obj testStruct
+	require.Error(t, conn.UnmarshalObject([]byte(`{"Key":"key","Value":"value"}`), &obj))
+}
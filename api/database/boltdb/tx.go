@@ -3,6 +3,7 @@ package boltdb
 import (
 	"bytes"
 	"fmt"
+	"time"
 
 	dserrors "github.com/portainer/portainer/api/dataservices/errors"
 
@@ -21,6 +22,8 @@ func (tx *DbTransaction) SetServiceName(bucketName string) error {
 }
 
 func (tx *DbTransaction) GetObject(bucketName string, key []byte, object any) error {
+	defer tx.conn.observeQuery(bucketName, "GetObject", key, time.Now())
+
 	bucket := tx.tx.Bucket([]byte(bucketName))
 
 	value := bucket.Get(key)
@@ -32,6 +35,8 @@ func (tx *DbTransaction) GetObject(bucketName string, key []byte, object any) er
 }
 
 func (tx *DbTransaction) UpdateObject(bucketName string, key []byte, object any) error {
+	defer tx.conn.observeQuery(bucketName, "UpdateObject", key, time.Now())
+
 	data, err := tx.conn.MarshalObject(object)
 	if err != nil {
 		return err
@@ -42,11 +47,15 @@ func (tx *DbTransaction) UpdateObject(bucketName string, key []byte, object any)
 }
 
 func (tx *DbTransaction) DeleteObject(bucketName string, key []byte) error {
+	defer tx.conn.observeQuery(bucketName, "DeleteObject", key, time.Now())
+
 	bucket := tx.tx.Bucket([]byte(bucketName))
 	return bucket.Delete(key)
 }
 
 func (tx *DbTransaction) DeleteAllObjects(bucketName string, obj any, matchingFn func(o any) (id int, ok bool)) error {
+	defer tx.conn.observeQuery(bucketName, "DeleteAllObjects", nil, time.Now())
+
 	var ids []int
 
 	bucket := tx.tx.Bucket([]byte(bucketName))
@@ -73,6 +82,8 @@ func (tx *DbTransaction) DeleteAllObjects(bucketName string, obj any, matchingFn
 }
 
 func (tx *DbTransaction) GetNextIdentifier(bucketName string) int {
+	defer tx.conn.observeQuery(bucketName, "GetNextIdentifier", nil, time.Now())
+
 	bucket := tx.tx.Bucket([]byte(bucketName))
 
 	id, err := bucket.NextSequence()
@@ -86,6 +97,8 @@ func (tx *DbTransaction) GetNextIdentifier(bucketName string) int {
 }
 
 func (tx *DbTransaction) CreateObject(bucketName string, fn func(uint64) (int, any)) error {
+	defer tx.conn.observeQuery(bucketName, "CreateObject", nil, time.Now())
+
 	bucket := tx.tx.Bucket([]byte(bucketName))
 
 	seqId, _ := bucket.NextSequence()
@@ -100,6 +113,8 @@ func (tx *DbTransaction) CreateObject(bucketName string, fn func(uint64) (int, a
 }
 
 func (tx *DbTransaction) CreateObjectWithId(bucketName string, id int, obj any) error {
+	defer tx.conn.observeQuery(bucketName, "CreateObjectWithId", tx.conn.ConvertToKey(id), time.Now())
+
 	bucket := tx.tx.Bucket([]byte(bucketName))
 	data, err := tx.conn.MarshalObject(obj)
 	if err != nil {
@@ -110,6 +125,8 @@ func (tx *DbTransaction) CreateObjectWithId(bucketName string, id int, obj any)
 }
 
 func (tx *DbTransaction) CreateObjectWithStringId(bucketName string, id []byte, obj any) error {
+	defer tx.conn.observeQuery(bucketName, "CreateObjectWithStringId", id, time.Now())
+
 	bucket := tx.tx.Bucket([]byte(bucketName))
 	data, err := tx.conn.MarshalObject(obj)
 	if err != nil {
@@ -120,6 +137,8 @@ func (tx *DbTransaction) CreateObjectWithStringId(bucketName string, id []byte,
 }
 
 func (tx *DbTransaction) GetAll(bucketName string, obj any, appendFn func(o any) (any, error)) error {
+	defer tx.conn.observeQuery(bucketName, "GetAll", nil, time.Now())
+
 	bucket := tx.tx.Bucket([]byte(bucketName))
 
 	return bucket.ForEach(func(k []byte, v []byte) error {
@@ -133,6 +152,8 @@ func (tx *DbTransaction) GetAll(bucketName string, obj any, appendFn func(o any)
 }
 
 func (tx *DbTransaction) GetAllWithKeyPrefix(bucketName string, keyPrefix []byte, obj any, appendFn func(o any) (any, error)) error {
+	defer tx.conn.observeQuery(bucketName, "GetAllWithKeyPrefix", keyPrefix, time.Now())
+
 	cursor := tx.tx.Bucket([]byte(bucketName)).Cursor()
 
 	for k, v := cursor.Seek(keyPrefix); k != nil && bytes.HasPrefix(k, keyPrefix); k, v = cursor.Next() {
@@ -0,0 +1,30 @@
+package database
+
+import (
+	"crypto/sha256"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// secretsPath is the directory holding the secret keys, the Docker secrets are mounted there
+const secretsPath = "/run/secrets"
+
+// LoadEncryptionKey reads the secret key /run/secrets/<secretKeyName> and returns the 32 bytes key derived
+// from it, the key used to encrypt the database
+func LoadEncryptionKey(secretKeyName string) ([]byte, error) {
+	if secretKeyName == "" || secretKeyName == "." || secretKeyName == ".." || filepath.Base(secretKeyName) != secretKeyName {
+		return nil, errors.New("invalid secret key name")
+	}
+
+	content, err := os.ReadFile(path.Join(secretsPath, secretKeyName))
+	if err != nil {
+		return nil, err
+	}
+
+	// return a 32 byte hash of the secret (required for AES)
+	hash := sha256.Sum256(content)
+
+	return hash[:], nil
+}
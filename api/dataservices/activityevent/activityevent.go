@@ -0,0 +1,110 @@
+package activityevent
+
+import (
+	"fmt"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "activity_events"
+
+// Service represents a service for managing activity events.
+type Service struct {
+	dataservices.BaseDataService[portainer.ActivityEvent, portainer.ActivityEventID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.ActivityEvent, portainer.ActivityEventID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.ActivityEvent, portainer.ActivityEventID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.ActivityEvent, portainer.ActivityEventID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new activity event and saves it.
+func (service *Service) Create(event *portainer.ActivityEvent) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(event)
+	})
+}
+
+// ReadAllByEndpointID returns the activity events of an environment(endpoint).
+func (service *Service) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.ActivityEvent, error) {
+	var events = make([]portainer.ActivityEvent, 0)
+
+	return events, service.Connection.GetAll(
+		BucketName,
+		&portainer.ActivityEvent{},
+		dataservices.FilterFn(&events, func(e portainer.ActivityEvent) bool {
+			return e.EndpointID == endpointID
+		}),
+	)
+}
+
+// DeleteByEndpointID removes all the activity events of an environment(endpoint).
+func (service *Service) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).DeleteByEndpointID(endpointID)
+	})
+}
+
+// Create assigns an ID to a new activity event and saves it.
+func (service ServiceTx) Create(event *portainer.ActivityEvent) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		event.ID = portainer.ActivityEventID(id)
+
+		return int(event.ID), event
+	})
+}
+
+// ReadAllByEndpointID returns the activity events of an environment(endpoint).
+func (service ServiceTx) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.ActivityEvent, error) {
+	var events = make([]portainer.ActivityEvent, 0)
+
+	return events, service.Tx.GetAll(
+		BucketName,
+		&portainer.ActivityEvent{},
+		dataservices.FilterFn(&events, func(e portainer.ActivityEvent) bool {
+			return e.EndpointID == endpointID
+		}),
+	)
+}
+
+// DeleteByEndpointID removes all the activity events of an environment(endpoint).
+func (service ServiceTx) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	events, err := service.ReadAllByEndpointID(endpointID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve activity events for endpoint (%d): %w", endpointID, err)
+	}
+
+	for _, event := range events {
+		if err := service.Delete(event.ID); err != nil {
+			return fmt.Errorf("failed to delete activity event (%d): %w", event.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package cloudprovisioning
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "cloud_provisionings"
+
+// Service represents a service for managing cloud provisioning data.
+type Service struct {
+	dataservices.BaseDataService[portainer.CloudProvisioning, portainer.CloudProvisioningID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.CloudProvisioning, portainer.CloudProvisioningID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.CloudProvisioning, portainer.CloudProvisioningID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.CloudProvisioning, portainer.CloudProvisioningID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new cloud provisioning and saves it.
+func (service *Service) Create(provisioning *portainer.CloudProvisioning) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(provisioning)
+	})
+}
+
+// Create assigns an ID to a new cloud provisioning and saves it.
+func (service ServiceTx) Create(provisioning *portainer.CloudProvisioning) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		provisioning.ID = portainer.CloudProvisioningID(id)
+
+		return int(provisioning.ID), provisioning
+	})
+}
@@ -0,0 +1,81 @@
+package deployment
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "deployments"
+
+// Service represents a service for managing deployment history data.
+type Service struct {
+	dataservices.BaseDataService[portainer.Deployment, portainer.DeploymentID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.Deployment, portainer.DeploymentID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.Deployment, portainer.DeploymentID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.Deployment, portainer.DeploymentID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new deployment and saves it.
+func (service *Service) Create(deployment *portainer.Deployment) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(deployment)
+	})
+}
+
+// ReadAllByFilter returns the deployments matching the given filter.
+func (service *Service) ReadAllByFilter(filter func(portainer.Deployment) bool) ([]portainer.Deployment, error) {
+	var deployments = make([]portainer.Deployment, 0)
+
+	return deployments, service.Connection.GetAll(
+		BucketName,
+		&portainer.Deployment{},
+		dataservices.FilterFn(&deployments, filter),
+	)
+}
+
+// Create assigns an ID to a new deployment and saves it.
+func (service ServiceTx) Create(deployment *portainer.Deployment) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		deployment.ID = portainer.DeploymentID(id)
+
+		return int(deployment.ID), deployment
+	})
+}
+
+// ReadAllByFilter returns the deployments matching the given filter.
+func (service ServiceTx) ReadAllByFilter(filter func(portainer.Deployment) bool) ([]portainer.Deployment, error) {
+	var deployments = make([]portainer.Deployment, 0)
+
+	return deployments, service.Tx.GetAll(
+		BucketName,
+		&portainer.Deployment{},
+		dataservices.FilterFn(&deployments, filter),
+	)
+}
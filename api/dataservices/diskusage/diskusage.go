@@ -0,0 +1,110 @@
+package diskusage
+
+import (
+	"fmt"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "disk_usage_samples"
+
+// Service represents a service for managing disk usage samples.
+type Service struct {
+	dataservices.BaseDataService[portainer.DiskUsageSample, portainer.DiskUsageSampleID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.DiskUsageSample, portainer.DiskUsageSampleID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.DiskUsageSample, portainer.DiskUsageSampleID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.DiskUsageSample, portainer.DiskUsageSampleID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new disk usage sample and saves it.
+func (service *Service) Create(sample *portainer.DiskUsageSample) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(sample)
+	})
+}
+
+// ReadAllByEndpointID returns the disk usage samples of an environment(endpoint).
+func (service *Service) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.DiskUsageSample, error) {
+	var samples = make([]portainer.DiskUsageSample, 0)
+
+	return samples, service.Connection.GetAll(
+		BucketName,
+		&portainer.DiskUsageSample{},
+		dataservices.FilterFn(&samples, func(s portainer.DiskUsageSample) bool {
+			return s.EndpointID == endpointID
+		}),
+	)
+}
+
+// DeleteByEndpointID removes all the disk usage samples of an environment(endpoint).
+func (service *Service) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).DeleteByEndpointID(endpointID)
+	})
+}
+
+// Create assigns an ID to a new disk usage sample and saves it.
+func (service ServiceTx) Create(sample *portainer.DiskUsageSample) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		sample.ID = portainer.DiskUsageSampleID(id)
+
+		return int(sample.ID), sample
+	})
+}
+
+// ReadAllByEndpointID returns the disk usage samples of an environment(endpoint).
+func (service ServiceTx) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.DiskUsageSample, error) {
+	var samples = make([]portainer.DiskUsageSample, 0)
+
+	return samples, service.Tx.GetAll(
+		BucketName,
+		&portainer.DiskUsageSample{},
+		dataservices.FilterFn(&samples, func(s portainer.DiskUsageSample) bool {
+			return s.EndpointID == endpointID
+		}),
+	)
+}
+
+// DeleteByEndpointID removes all the disk usage samples of an environment(endpoint).
+func (service ServiceTx) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	samples, err := service.ReadAllByEndpointID(endpointID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve disk usage samples for endpoint (%d): %w", endpointID, err)
+	}
+
+	for _, sample := range samples {
+		if err := service.Delete(sample.ID); err != nil {
+			return fmt.Errorf("failed to delete disk usage sample (%d): %w", sample.ID, err)
+		}
+	}
+
+	return nil
+}
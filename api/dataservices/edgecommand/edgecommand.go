@@ -0,0 +1,172 @@
+package edgecommand
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "edge_commands"
+
+// ErrInvalidStatusTransition is returned when the status of a command cannot be changed to the requested one
+var ErrInvalidStatusTransition = errors.New("invalid edge command status transition")
+
+// statusTransitions lists the statuses a command can move to from each status,
+// failed and cancelled commands are final
+var statusTransitions = map[portainer.EdgeCommandStatus][]portainer.EdgeCommandStatus{
+	portainer.EdgeCommandStatusPending: {
+		portainer.EdgeCommandStatusDelivered,
+		portainer.EdgeCommandStatusFailed,
+		portainer.EdgeCommandStatusCancelled,
+	},
+	portainer.EdgeCommandStatusDelivered: {
+		portainer.EdgeCommandStatusFailed,
+	},
+}
+
+// CanTransition returns whether a command can move from a status to another one
+func CanTransition(from, to portainer.EdgeCommandStatus) bool {
+	return slices.Contains(statusTransitions[from], to)
+}
+
+// Service represents a service for managing edge command data.
+type Service struct {
+	dataservices.BaseDataService[portainer.EdgeCommand, portainer.EdgeCommandID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.EdgeCommand, portainer.EdgeCommandID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.EdgeCommand, portainer.EdgeCommandID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.EdgeCommand, portainer.EdgeCommandID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new edge command and saves it as pending.
+func (service *Service) Create(command *portainer.EdgeCommand) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(command)
+	})
+}
+
+// ReadAllByEndpointID returns the edge commands of an environment(endpoint), oldest first.
+func (service *Service) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.EdgeCommand, error) {
+	var commands []portainer.EdgeCommand
+
+	return commands, service.Connection.ViewTx(func(tx portainer.Transaction) error {
+		var err error
+		commands, err = service.Tx(tx).ReadAllByEndpointID(endpointID)
+
+		return err
+	})
+}
+
+// UpdateStatus moves an edge command to a new status, ErrInvalidStatusTransition is returned
+// when the current status of the command does not allow it.
+func (service *Service) UpdateStatus(ID portainer.EdgeCommandID, status portainer.EdgeCommandStatus, message string) (*portainer.EdgeCommand, error) {
+	var command *portainer.EdgeCommand
+
+	return command, service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		var err error
+		command, err = service.Tx(tx).UpdateStatus(ID, status, message)
+
+		return err
+	})
+}
+
+// DeleteByEndpointID removes all the edge commands of an environment(endpoint).
+func (service *Service) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).DeleteByEndpointID(endpointID)
+	})
+}
+
+// Create assigns an ID to a new edge command and saves it as pending.
+func (service ServiceTx) Create(command *portainer.EdgeCommand) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		command.ID = portainer.EdgeCommandID(id)
+		command.Status = portainer.EdgeCommandStatusPending
+		command.CreatedAt = time.Now().Unix()
+		command.UpdatedAt = command.CreatedAt
+
+		return int(command.ID), command
+	})
+}
+
+// ReadAllByEndpointID returns the edge commands of an environment(endpoint), oldest first.
+func (service ServiceTx) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.EdgeCommand, error) {
+	var commands = make([]portainer.EdgeCommand, 0)
+
+	err := service.Tx.GetAll(
+		BucketName,
+		&portainer.EdgeCommand{},
+		dataservices.FilterFn(&commands, func(c portainer.EdgeCommand) bool {
+			return c.EndpointID == endpointID
+		}),
+	)
+
+	slices.SortFunc(commands, func(a, b portainer.EdgeCommand) int { return int(a.ID) - int(b.ID) })
+
+	return commands, err
+}
+
+// UpdateStatus moves an edge command to a new status, ErrInvalidStatusTransition is returned
+// when the current status of the command does not allow it.
+func (service ServiceTx) UpdateStatus(ID portainer.EdgeCommandID, status portainer.EdgeCommandStatus, message string) (*portainer.EdgeCommand, error) {
+	command, err := service.Read(ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !CanTransition(command.Status, status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, command.Status, status)
+	}
+
+	command.Status = status
+	command.Error = message
+	command.UpdatedAt = time.Now().Unix()
+
+	return command, service.Update(ID, command)
+}
+
+// DeleteByEndpointID removes all the edge commands of an environment(endpoint).
+func (service ServiceTx) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	commands, err := service.ReadAllByEndpointID(endpointID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve edge commands for endpoint (%d): %w", endpointID, err)
+	}
+
+	for _, command := range commands {
+		if err := service.Delete(command.ID); err != nil {
+			return fmt.Errorf("failed to delete edge command (%d): %w", command.ID, err)
+		}
+	}
+
+	return nil
+}
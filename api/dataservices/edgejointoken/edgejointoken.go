@@ -0,0 +1,71 @@
+package edgejointoken
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	dserrors "github.com/portainer/portainer/api/dataservices/errors"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "edge_join_tokens"
+
+// Service represents a service for managing Edge join token data.
+type Service struct {
+	dataservices.BaseDataService[portainer.EdgeJoinToken, portainer.EdgeJoinTokenID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.EdgeJoinToken, portainer.EdgeJoinTokenID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.EdgeJoinToken, portainer.EdgeJoinTokenID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new Edge join token and saves it.
+func (service *Service) Create(token *portainer.EdgeJoinToken) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(token)
+	})
+}
+
+// ReadByDigest returns the Edge join token matching the digest.
+func (service *Service) ReadByDigest(digest string) (*portainer.EdgeJoinToken, error) {
+	var token *portainer.EdgeJoinToken
+
+	err := service.Connection.ViewTx(func(tx portainer.Transaction) error {
+		var err error
+		token, err = service.Tx(tx).ReadByDigest(digest)
+
+		return err
+	})
+
+	return token, err
+}
+
+func findByDigest(tokens []portainer.EdgeJoinToken, digest string) (*portainer.EdgeJoinToken, error) {
+	for i := range tokens {
+		if tokens[i].Digest == digest {
+			return &tokens[i], nil
+		}
+	}
+
+	return nil, dserrors.ErrObjectNotFound
+}
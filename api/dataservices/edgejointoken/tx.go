@@ -0,0 +1,29 @@
+package edgejointoken
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.EdgeJoinToken, portainer.EdgeJoinTokenID]
+}
+
+// Create assigns an ID to a new Edge join token and saves it.
+func (service ServiceTx) Create(token *portainer.EdgeJoinToken) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		token.ID = portainer.EdgeJoinTokenID(id)
+
+		return int(token.ID), token
+	})
+}
+
+// ReadByDigest returns the Edge join token matching the digest.
+func (service ServiceTx) ReadByDigest(digest string) (*portainer.EdgeJoinToken, error) {
+	tokens, err := service.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	return findByDigest(tokens, digest)
+}
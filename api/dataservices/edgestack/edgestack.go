@@ -14,6 +14,7 @@ const BucketName = "edge_stack"
 type Service struct {
 	connection          portainer.Connection
 	idxVersion          map[portainer.EdgeStackID]int
+	idxRollout          map[portainer.EdgeStackID]*portainer.EdgeStackRollout
 	mu                  sync.RWMutex
 	cacheInvalidationFn func(portainer.EdgeStackID)
 }
@@ -32,6 +33,7 @@ func NewService(connection portainer.Connection, cacheInvalidationFn func(portai
 	s := &Service{
 		connection:          connection,
 		idxVersion:          make(map[portainer.EdgeStackID]int),
+		idxRollout:          make(map[portainer.EdgeStackID]*portainer.EdgeStackRollout),
 		cacheInvalidationFn: cacheInvalidationFn,
 	}
 
@@ -44,8 +46,8 @@ func NewService(connection portainer.Connection, cacheInvalidationFn func(portai
 		return nil, err
 	}
 
-	for _, e := range es {
-		s.idxVersion[e.ID] = e.Version
+	for i := range es {
+		s.index(es[i].ID, &es[i])
 	}
 
 	return s, nil
@@ -91,6 +93,20 @@ func (service *Service) EdgeStackVersion(ID portainer.EdgeStackID) (int, bool) {
 	return v, ok
 }
 
+// EdgeStackVersionForEndpoint returns the version of the given edge stack ID that the environment
+// should deploy, taking a staged rollout into account, directly from an in-memory index
+func (service *Service) EdgeStackVersionForEndpoint(ID portainer.EdgeStackID, endpointID portainer.EndpointID) (int, bool) {
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	v, ok := service.idxVersion[ID]
+	if !ok {
+		return 0, false
+	}
+
+	return service.idxRollout[ID].EndpointVersion(v, endpointID), true
+}
+
 // CreateEdgeStack saves an Edge stack object to db.
 func (service *Service) Create(id portainer.EdgeStackID, edgeStack *portainer.EdgeStack) error {
 	edgeStack.ID = id
@@ -105,7 +121,7 @@ func (service *Service) Create(id portainer.EdgeStackID, edgeStack *portainer.Ed
 	}
 
 	service.mu.Lock()
-	service.idxVersion[id] = edgeStack.Version
+	service.index(id, edgeStack)
 	service.cacheInvalidationFn(id)
 	service.mu.Unlock()
 
@@ -124,7 +140,7 @@ func (service *Service) UpdateEdgeStack(ID portainer.EdgeStackID, edgeStack *por
 		return err
 	}
 
-	service.idxVersion[ID] = edgeStack.Version
+	service.index(ID, edgeStack)
 	service.cacheInvalidationFn(ID)
 
 	return nil
@@ -141,7 +157,7 @@ func (service *Service) UpdateEdgeStackFunc(ID portainer.EdgeStackID, updateFunc
 	return service.connection.UpdateObjectFunc(BucketName, id, edgeStack, func() {
 		updateFunc(edgeStack)
 
-		service.idxVersion[ID] = edgeStack.Version
+		service.index(ID, edgeStack)
 		service.cacheInvalidationFn(ID)
 	})
 }
@@ -163,13 +179,31 @@ func (service *Service) DeleteEdgeStack(ID portainer.EdgeStackID) error {
 		return err
 	}
 
-	delete(service.idxVersion, ID)
+	service.unindex(ID)
 
 	service.cacheInvalidationFn(ID)
 
 	return nil
 }
 
+// index must be called with the lock held
+func (service *Service) index(ID portainer.EdgeStackID, edgeStack *portainer.EdgeStack) {
+	service.idxVersion[ID] = edgeStack.Version
+
+	if edgeStack.Rollout != nil && !edgeStack.Rollout.Completed {
+		rollout := *edgeStack.Rollout
+		service.idxRollout[ID] = &rollout
+	} else {
+		delete(service.idxRollout, ID)
+	}
+}
+
+// unindex must be called with the lock held
+func (service *Service) unindex(ID portainer.EdgeStackID) {
+	delete(service.idxVersion, ID)
+	delete(service.idxRollout, ID)
+}
+
 // GetNextIdentifier returns the next identifier for an environment(endpoint).
 func (service *Service) GetNextIdentifier() int {
 	return service.connection.GetNextIdentifier(BucketName)
@@ -61,6 +61,12 @@ func (service ServiceTx) EdgeStackVersion(ID portainer.EdgeStackID) (int, bool)
 	return v, ok
 }
 
+// EdgeStackVersionForEndpoint returns the version of the given edge stack ID that the environment
+// should deploy, taking a staged rollout into account, directly from an in-memory index
+func (service ServiceTx) EdgeStackVersionForEndpoint(ID portainer.EdgeStackID, endpointID portainer.EndpointID) (int, bool) {
+	return service.service.EdgeStackVersionForEndpoint(ID, endpointID)
+}
+
 // CreateEdgeStack saves an Edge stack object to db.
 func (service ServiceTx) Create(id portainer.EdgeStackID, edgeStack *portainer.EdgeStack) error {
 	edgeStack.ID = id
@@ -75,7 +81,7 @@ func (service ServiceTx) Create(id portainer.EdgeStackID, edgeStack *portainer.E
 	}
 
 	service.service.mu.Lock()
-	service.service.idxVersion[id] = edgeStack.Version
+	service.service.index(id, edgeStack)
 	service.service.cacheInvalidationFn(id)
 	service.service.mu.Unlock()
 
@@ -94,7 +100,7 @@ func (service ServiceTx) UpdateEdgeStack(ID portainer.EdgeStackID, edgeStack *po
 		return err
 	}
 
-	service.service.idxVersion[ID] = edgeStack.Version
+	service.service.index(ID, edgeStack)
 	service.service.cacheInvalidationFn(ID)
 
 	return nil
@@ -124,7 +130,7 @@ func (service ServiceTx) DeleteEdgeStack(ID portainer.EdgeStackID) error {
 		return err
 	}
 
-	delete(service.service.idxVersion, ID)
+	service.service.unindex(ID)
 
 	service.service.cacheInvalidationFn(ID)
 
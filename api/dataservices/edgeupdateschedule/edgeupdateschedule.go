@@ -0,0 +1,59 @@
+package edgeupdateschedule
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "edge_update_schedules"
+
+// Service represents a service for managing edge update schedule data.
+type Service struct {
+	dataservices.BaseDataService[portainer.EdgeUpdateSchedule, portainer.EdgeUpdateScheduleID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.EdgeUpdateSchedule, portainer.EdgeUpdateScheduleID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.EdgeUpdateSchedule, portainer.EdgeUpdateScheduleID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.EdgeUpdateSchedule, portainer.EdgeUpdateScheduleID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new edge update schedule and saves it.
+func (service *Service) Create(schedule *portainer.EdgeUpdateSchedule) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(schedule)
+	})
+}
+
+// Create assigns an ID to a new edge update schedule and saves it.
+func (service ServiceTx) Create(schedule *portainer.EdgeUpdateSchedule) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		schedule.ID = portainer.EdgeUpdateScheduleID(id)
+
+		return int(schedule.ID), schedule
+	})
+}
@@ -13,6 +13,14 @@ import (
 // BucketName represents the name of the bucket where this service stores data.
 const BucketName = "endpoints"
 
+// indexEntry holds the fields of an environment(endpoint) kept in memory, the environments are
+// decoded into it at startup instead of being fully loaded
+type indexEntry struct {
+	ID              portainer.EndpointID `json:"Id"`
+	EdgeID          string               `json:"EdgeID"`
+	LastCheckInDate int64                `json:"LastCheckInDate"`
+}
+
 // Service represents a service for managing environment(endpoint) data.
 type Service struct {
 	connection portainer.Connection
@@ -37,12 +45,12 @@ func NewService(connection portainer.Connection) (*Service, error) {
 		idxEdgeID:  make(map[string]portainer.EndpointID),
 	}
 
-	es, err := s.endpoints()
-	if err != nil {
+	var entries []indexEntry
+	if err := connection.GetAll(BucketName, &indexEntry{}, dataservices.AppendFn(&entries)); err != nil {
 		return nil, err
 	}
 
-	for _, e := range es {
+	for _, e := range entries {
 		if len(e.EdgeID) > 0 {
 			s.idxEdgeID[e.EdgeID] = e.ID
 		}
@@ -119,6 +127,29 @@ func (service *Service) Endpoints() ([]portainer.Endpoint, error) {
 	return endpoints, nil
 }
 
+// EndpointsPaginated returns the environments(endpoints) matching the filters, skipping the first offset ones
+// and returning at most limit of them, along with the total number of matching environments(endpoints).
+// A zero limit means no limit.
+func (service *Service) EndpointsPaginated(offset, limit int, filters dataservices.EndpointFilters) ([]portainer.Endpoint, int, error) {
+	var endpoints []portainer.Endpoint
+	var total int
+	var err error
+
+	err = service.connection.ViewTx(func(tx portainer.Transaction) error {
+		endpoints, total, err = service.Tx(tx).EndpointsPaginated(offset, limit, filters)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i, e := range endpoints {
+		endpoints[i].LastCheckInDate, _ = service.Heartbeat(e.ID)
+	}
+
+	return endpoints, total, nil
+}
+
 // EndpointIDByEdgeID returns the EndpointID from the given EdgeID using an in-memory index
 func (service *Service) EndpointIDByEdgeID(edgeID string) (portainer.EndpointID, bool) {
 	service.mu.RLock()
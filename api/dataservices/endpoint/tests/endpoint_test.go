@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/dataservices/endpoint"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func endpointNames(endpoints []portainer.Endpoint) []string {
+	names := make([]string, 0, len(endpoints))
+	for _, e := range endpoints {
+		names = append(names, e.Name)
+	}
+
+	return names
+}
+
+func Test_EndpointsPaginated(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	endpoints := []portainer.Endpoint{
+		{ID: 1, Name: "prod-docker", GroupID: 1, Type: portainer.DockerEnvironment, Status: portainer.EndpointStatusUp, TagIDs: []portainer.TagID{1, 2}},
+		{ID: 2, Name: "prod-k8s", GroupID: 2, Type: portainer.KubernetesLocalEnvironment, Status: portainer.EndpointStatusUp, TagIDs: []portainer.TagID{1}},
+		{ID: 3, Name: "staging-docker", GroupID: 2, Type: portainer.DockerEnvironment, Status: portainer.EndpointStatusDown, TagIDs: []portainer.TagID{2}},
+		{ID: 4, Name: "edge-trusted", GroupID: 1, Type: portainer.EdgeAgentOnDockerEnvironment, UserTrusted: true},
+		{ID: 5, Name: "edge-untrusted", GroupID: 1, Type: portainer.EdgeAgentOnDockerEnvironment},
+	}
+
+	for i := range endpoints {
+		require.NoError(t, store.Endpoint().Create(&endpoints[i]))
+	}
+
+	untrusted := false
+
+	tests := []struct {
+		name          string
+		offset, limit int
+		filters       dataservices.EndpointFilters
+		expected      []string
+		expectedTotal int
+	}{
+		{
+			name:          "no filters and no limit return everything",
+			expected:      []string{"prod-docker", "prod-k8s", "staging-docker", "edge-trusted", "edge-untrusted"},
+			expectedTotal: 5,
+		},
+		{
+			name:          "offset and limit paginate the results",
+			offset:        1,
+			limit:         2,
+			expected:      []string{"prod-k8s", "staging-docker"},
+			expectedTotal: 5,
+		},
+		{
+			name:          "offset past the end returns nothing",
+			offset:        10,
+			limit:         2,
+			expected:      []string{},
+			expectedTotal: 5,
+		},
+		{
+			name:          "group filter",
+			filters:       dataservices.EndpointFilters{GroupIDs: []portainer.EndpointGroupID{2}},
+			expected:      []string{"prod-k8s", "staging-docker"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "all tags must match",
+			filters:       dataservices.EndpointFilters{TagIDs: []portainer.TagID{1, 2}},
+			expected:      []string{"prod-docker"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "any tag matches on partial match",
+			filters:       dataservices.EndpointFilters{TagIDs: []portainer.TagID{1, 2}, TagsPartialMatch: true},
+			expected:      []string{"prod-docker", "prod-k8s", "staging-docker"},
+			expectedTotal: 3,
+		},
+		{
+			name:          "status and type filters",
+			filters:       dataservices.EndpointFilters{Statuses: []portainer.EndpointStatus{portainer.EndpointStatusUp}, Types: []portainer.EndpointType{portainer.DockerEnvironment}},
+			expected:      []string{"prod-docker"},
+			expectedTotal: 1,
+		},
+		{
+			name:          "case-insensitive name search with a limit",
+			limit:         1,
+			filters:       dataservices.EndpointFilters{Search: "DOCKER"},
+			expected:      []string{"prod-docker"},
+			expectedTotal: 2,
+		},
+		{
+			name:          "untrusted edge environments are excluded",
+			filters:       dataservices.EndpointFilters{GroupIDs: []portainer.EndpointGroupID{1}, EdgeDeviceUntrusted: &untrusted},
+			expected:      []string{"prod-docker", "edge-trusted"},
+			expectedTotal: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, total, err := store.Endpoint().EndpointsPaginated(tt.offset, tt.limit, tt.filters)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, endpointNames(result))
+			assert.Equal(t, tt.expectedTotal, total)
+		})
+	}
+}
+
+func Test_NewServiceBuildsTheIndexes(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1, Name: "local", Type: portainer.DockerEnvironment}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{
+		ID:              2,
+		Name:            "edge",
+		Type:            portainer.EdgeAgentOnDockerEnvironment,
+		EdgeID:          "edge-id",
+		LastCheckInDate: 1708000000,
+	}))
+
+	// The indexes are rebuilt from the stored environments, as on startup
+	service, err := endpoint.NewService(store.Connection())
+	require.NoError(t, err)
+
+	endpointID, ok := service.EndpointIDByEdgeID("edge-id")
+	require.True(t, ok)
+	assert.Equal(t, portainer.EndpointID(2), endpointID)
+
+	heartbeat, ok := service.Heartbeat(2)
+	require.True(t, ok)
+	assert.Equal(t, int64(1708000000), heartbeat)
+
+	_, ok = service.Heartbeat(1)
+	assert.True(t, ok)
+}
@@ -89,6 +89,22 @@ func (service ServiceTx) Endpoints() ([]portainer.Endpoint, error) {
 	)
 }
 
+// EndpointsPaginated returns the environments(endpoints) matching the filters, skipping the first offset ones
+// and returning at most limit of them, along with the total number of matching environments(endpoints).
+// A zero limit means no limit.
+func (service ServiceTx) EndpointsPaginated(offset, limit int, filters dataservices.EndpointFilters) ([]portainer.Endpoint, int, error) {
+	var endpoints = make([]portainer.Endpoint, 0)
+	var total int
+
+	return endpoints, total, service.tx.GetAll(
+		BucketName,
+		&portainer.Endpoint{},
+		dataservices.PaginateFn(&endpoints, &total, offset, limit, func(e portainer.Endpoint) bool {
+			return filters.Match(&e)
+		}),
+	)
+}
+
 func (service ServiceTx) EndpointIDByEdgeID(edgeID string) (portainer.EndpointID, bool) {
 	log.Error().Str("func", "EndpointIDByEdgeID").Msg("cannot be called inside a transaction")
 
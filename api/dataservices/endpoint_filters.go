@@ -0,0 +1,76 @@
+package dataservices
+
+import (
+	"slices"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+// EndpointFilters represents the criteria used to query environments(endpoints) from the database.
+// Empty criteria match every environment(endpoint).
+type EndpointFilters struct {
+	GroupIDs []portainer.EndpointGroupID
+	// Tags of the environment(endpoint) itself, the tags of its group are not considered
+	TagIDs []portainer.TagID
+	// If true, the environments(endpoints) having one of TagIDs match, otherwise they must have all of them
+	TagsPartialMatch bool
+	// Status stored for the environment(endpoint), it is not computed from the Edge check-ins
+	Statuses []portainer.EndpointStatus
+	Types    []portainer.EndpointType
+	// Case-insensitive part of the environment(endpoint) name
+	Search string
+	// When set, only the Edge environments(endpoints) with the opposite trust are matched,
+	// the other environments(endpoints) are not affected
+	EdgeDeviceUntrusted *bool
+}
+
+// Match returns true when the environment(endpoint) satisfies all the criteria
+func (filters EndpointFilters) Match(endpoint *portainer.Endpoint) bool {
+	if len(filters.GroupIDs) > 0 && !slices.Contains(filters.GroupIDs, endpoint.GroupID) {
+		return false
+	}
+
+	if len(filters.TagIDs) > 0 && !matchTags(endpoint.TagIDs, filters.TagIDs, filters.TagsPartialMatch) {
+		return false
+	}
+
+	if len(filters.Statuses) > 0 && !slices.Contains(filters.Statuses, endpoint.Status) {
+		return false
+	}
+
+	if len(filters.Types) > 0 && !slices.Contains(filters.Types, endpoint.Type) {
+		return false
+	}
+
+	if filters.Search != "" && !strings.Contains(strings.ToLower(endpoint.Name), strings.ToLower(filters.Search)) {
+		return false
+	}
+
+	if filters.EdgeDeviceUntrusted != nil && isEdgeEndpoint(endpoint) && endpoint.UserTrusted == *filters.EdgeDeviceUntrusted {
+		return false
+	}
+
+	return true
+}
+
+func matchTags(endpointTagIDs, tagIDs []portainer.TagID, partialMatch bool) bool {
+	for _, tagID := range tagIDs {
+		found := slices.Contains(endpointTagIDs, tagID)
+
+		if partialMatch && found {
+			return true
+		}
+
+		if !partialMatch && !found {
+			return false
+		}
+	}
+
+	return !partialMatch
+}
+
+// isEdgeEndpoint mirrors endpointutils.IsEdgeEndpoint, which cannot be imported from this package
+func isEdgeEndpoint(endpoint *portainer.Endpoint) bool {
+	return endpoint.Type == portainer.EdgeAgentOnDockerEnvironment || endpoint.Type == portainer.EdgeAgentOnKubernetesEnvironment
+}
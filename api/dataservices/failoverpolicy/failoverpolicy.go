@@ -0,0 +1,59 @@
+package failoverpolicy
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "failover_policies"
+
+// Service represents a service for managing failover policy data.
+type Service struct {
+	dataservices.BaseDataService[portainer.FailoverPolicy, portainer.FailoverPolicyID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.FailoverPolicy, portainer.FailoverPolicyID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.FailoverPolicy, portainer.FailoverPolicyID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.FailoverPolicy, portainer.FailoverPolicyID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new failover policy and saves it.
+func (service *Service) Create(policy *portainer.FailoverPolicy) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(policy)
+	})
+}
+
+// Create assigns an ID to a new failover policy and saves it.
+func (service ServiceTx) Create(policy *portainer.FailoverPolicy) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		policy.ID = portainer.FailoverPolicyID(id)
+
+		return int(policy.ID), policy
+	})
+}
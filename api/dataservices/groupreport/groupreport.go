@@ -0,0 +1,59 @@
+package groupreport
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "group_reports"
+
+// Service represents a service for managing group report data.
+type Service struct {
+	dataservices.BaseDataService[portainer.GroupReport, portainer.GroupReportID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.GroupReport, portainer.GroupReportID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.GroupReport, portainer.GroupReportID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.GroupReport, portainer.GroupReportID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new group report and saves it.
+func (service *Service) Create(report *portainer.GroupReport) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(report)
+	})
+}
+
+// Create assigns an ID to a new group report and saves it.
+func (service ServiceTx) Create(report *portainer.GroupReport) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		report.ID = portainer.GroupReportID(id)
+
+		return int(report.ID), report
+	})
+}
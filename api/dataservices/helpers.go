@@ -16,6 +16,22 @@ func IsErrObjectNotFound(e error) bool {
 	return errors.Is(e, perrors.ErrObjectNotFound)
 }
 
+// UpdateTx executes fn inside a single read-write transaction of the data store and returns its result. The changes
+// made through the services of the transaction are only written when fn succeeds, so that an operation spanning
+// several buckets is never partially applied
+func UpdateTx[T any](store DataStore, fn func(DataStoreTx) (T, error)) (T, error) {
+	var result T
+
+	err := store.UpdateTx(func(tx DataStoreTx) error {
+		var err error
+		result, err = fn(tx)
+
+		return err
+	})
+
+	return result, err
+}
+
 // AppendFn appends elements to the given collection slice
 func AppendFn[T any](collection *[]T) func(obj any) (any, error) {
 	return func(obj any) (any, error) {
@@ -48,6 +64,30 @@ func FilterFn[T any](collection *[]T, predicate func(T) bool) func(obj any) (any
 	}
 }
 
+// PaginateFn counts the elements for which the predicate is true into total and appends to the given collection
+// the ones found after skipping offset of them, up to limit elements. A zero limit means no limit.
+func PaginateFn[T any](collection *[]T, total *int, offset, limit int, predicate func(T) bool) func(obj any) (any, error) {
+	return func(obj any) (any, error) {
+		element, ok := obj.(*T)
+		if !ok {
+			log.Debug().Str("obj", fmt.Sprintf("%#v", obj)).Msg("type assertion failed")
+			return nil, fmt.Errorf("failed to convert to %T object: %#v", new(T), obj)
+		}
+
+		if !predicate(*element) {
+			return new(T), nil
+		}
+
+		if *total >= offset && (limit == 0 || len(*collection) < limit) {
+			*collection = append(*collection, *element)
+		}
+
+		*total++
+
+		return new(T), nil
+	}
+}
+
 // FirstFn sets the element to the first one that satisfies the predicate and stops the computation, returns ErrStop on
 // success
 func FirstFn[T any](element *T, predicate func(T) bool) func(obj any) (any, error) {
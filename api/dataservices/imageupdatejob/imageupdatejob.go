@@ -0,0 +1,59 @@
+package imageupdatejob
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "image_update_jobs"
+
+// Service represents a service for managing image update job data.
+type Service struct {
+	dataservices.BaseDataService[portainer.ImageUpdateJob, portainer.ImageUpdateJobID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.ImageUpdateJob, portainer.ImageUpdateJobID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.ImageUpdateJob, portainer.ImageUpdateJobID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.ImageUpdateJob, portainer.ImageUpdateJobID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new image update job and saves it.
+func (service *Service) Create(job *portainer.ImageUpdateJob) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(job)
+	})
+}
+
+// Create assigns an ID to a new image update job and saves it.
+func (service ServiceTx) Create(job *portainer.ImageUpdateJob) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		job.ID = portainer.ImageUpdateJobID(id)
+
+		return int(job.ID), job
+	})
+}
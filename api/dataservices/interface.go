@@ -32,6 +32,31 @@ type (
 		Version() VersionService
 		Webhook() WebhookService
 		PendingActions() PendingActionsService
+		ImageUpdateJob() ImageUpdateJobService
+		FailoverPolicy() FailoverPolicyService
+		Quota() QuotaService
+		DiskUsageSample() DiskUsageSampleService
+		SnapshotRecord() SnapshotRecordService
+		Deployment() DeploymentService
+		EdgeCommand() EdgeCommandService
+		ActivityEvent() ActivityEventService
+		GroupReport() GroupReportService
+		ReportTemplate() ReportTemplateService
+		ValidationWebhook() ValidationWebhookService
+		SnapshotWebhook() SnapshotWebhookService
+		NotificationChannel() NotificationChannelService
+		UserSession() UserSessionService
+		StackGitOpsStatus() StackGitOpsStatusService
+		EdgeUpdateSchedule() EdgeUpdateScheduleService
+		TemplateSource() TemplateSourceService
+		WebhookExecution() WebhookExecutionService
+		CloudProvisioning() CloudProvisioningService
+		JWTSigningKey() JWTSigningKeyService
+		OpenAMTPowerSchedule() OpenAMTPowerScheduleService
+		OpenAMTDeviceAction() OpenAMTDeviceActionService
+		UserSettings() UserSettingsService
+		EdgeJoinToken() EdgeJoinTokenService
+		PruneRun() PruneRunService
 	}
 
 	DataStore interface {
@@ -56,6 +81,19 @@ type (
 		GetNextIdentifier() int
 	}
 
+	// DeploymentService represents a service to manage the deployment history
+	DeploymentService interface {
+		BaseCRUD[portainer.Deployment, portainer.DeploymentID]
+		ReadAllByFilter(filter func(portainer.Deployment) bool) ([]portainer.Deployment, error)
+	}
+
+	// DiskUsageSampleService represents a service to manage the disk usage history of environments(endpoints)
+	DiskUsageSampleService interface {
+		BaseCRUD[portainer.DiskUsageSample, portainer.DiskUsageSampleID]
+		ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.DiskUsageSample, error)
+		DeleteByEndpointID(endpointID portainer.EndpointID) error
+	}
+
 	// EdgeGroupService represents a service to manage Edge groups
 	EdgeGroupService interface {
 		BaseCRUD[portainer.EdgeGroup, portainer.EdgeGroupID]
@@ -76,11 +114,20 @@ type (
 		DeleteByEndpointID(ID portainer.EndpointID) error
 	}
 
+	// EdgeCommandService represents a service to manage the commands queued for Edge environments(endpoints)
+	EdgeCommandService interface {
+		BaseCRUD[portainer.EdgeCommand, portainer.EdgeCommandID]
+		ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.EdgeCommand, error)
+		UpdateStatus(ID portainer.EdgeCommandID, status portainer.EdgeCommandStatus, message string) (*portainer.EdgeCommand, error)
+		DeleteByEndpointID(endpointID portainer.EndpointID) error
+	}
+
 	// EdgeStackService represents a service to manage Edge stacks
 	EdgeStackService interface {
 		EdgeStacks() ([]portainer.EdgeStack, error)
 		EdgeStack(ID portainer.EdgeStackID) (*portainer.EdgeStack, error)
 		EdgeStackVersion(ID portainer.EdgeStackID) (int, bool)
+		EdgeStackVersionForEndpoint(ID portainer.EdgeStackID, endpointID portainer.EndpointID) (int, bool)
 		Create(id portainer.EdgeStackID, edgeStack *portainer.EdgeStack) error
 		UpdateEdgeStack(ID portainer.EdgeStackID, edgeStack *portainer.EdgeStack) error
 		UpdateEdgeStackFunc(ID portainer.EdgeStackID, updateFunc func(edgeStack *portainer.EdgeStack)) error
@@ -97,6 +144,7 @@ type (
 		Heartbeat(endpointID portainer.EndpointID) (int64, bool)
 		UpdateHeartbeat(endpointID portainer.EndpointID)
 		Endpoints() ([]portainer.Endpoint, error)
+		EndpointsPaginated(offset, limit int, filters EndpointFilters) ([]portainer.Endpoint, int, error)
 		Create(endpoint *portainer.Endpoint) error
 		UpdateEndpoint(ID portainer.EndpointID, endpoint *portainer.Endpoint) error
 		DeleteEndpoint(ID portainer.EndpointID) error
@@ -125,6 +173,108 @@ type (
 		HelmUserRepositoryByUserID(userID portainer.UserID) ([]portainer.HelmUserRepository, error)
 	}
 
+	// FailoverPolicyService represents a service to manage failover policies
+	FailoverPolicyService interface {
+		BaseCRUD[portainer.FailoverPolicy, portainer.FailoverPolicyID]
+	}
+
+	// QuotaService represents a service to manage quotas
+	QuotaService interface {
+		BaseCRUD[portainer.Quota, portainer.QuotaID]
+	}
+
+	// ImageUpdateJobService represents a service to manage image update jobs
+	ImageUpdateJobService interface {
+		BaseCRUD[portainer.ImageUpdateJob, portainer.ImageUpdateJobID]
+	}
+
+	// GroupReportService represents a service to manage the scheduled reports of environment(endpoint) groups
+	GroupReportService interface {
+		BaseCRUD[portainer.GroupReport, portainer.GroupReportID]
+	}
+
+	// ReportTemplateService represents a service to manage the templates of the group reports
+	ReportTemplateService interface {
+		BaseCRUD[portainer.ReportTemplate, portainer.ReportTemplateID]
+	}
+
+	// ValidationWebhookService represents a service to manage the validation webhooks of the deployments
+	ValidationWebhookService interface {
+		BaseCRUD[portainer.ValidationWebhook, portainer.ValidationWebhookID]
+	}
+
+	// SnapshotWebhookService represents a service to manage the webhooks receiving the summaries of the snapshots
+	SnapshotWebhookService interface {
+		BaseCRUD[portainer.SnapshotWebhook, portainer.SnapshotWebhookID]
+	}
+
+	// NotificationChannelService represents a service to manage the destinations of the notifications of the system events
+	NotificationChannelService interface {
+		BaseCRUD[portainer.NotificationChannel, portainer.NotificationChannelID]
+	}
+
+	// StackGitOpsStatusService represents a service to manage the GitOps status of the stacks
+	StackGitOpsStatusService interface {
+		BaseCRUD[portainer.StackGitOpsStatus, portainer.StackID]
+	}
+
+	// EdgeUpdateScheduleService represents a service to manage the updates of the Edge agents
+	EdgeUpdateScheduleService interface {
+		BaseCRUD[portainer.EdgeUpdateSchedule, portainer.EdgeUpdateScheduleID]
+	}
+
+	// TemplateSourceService represents a service to manage the sources of the application templates
+	TemplateSourceService interface {
+		BaseCRUD[portainer.TemplateSource, portainer.TemplateSourceID]
+	}
+
+	// WebhookExecutionService represents a service to manage the delivery log of the webhooks
+	WebhookExecutionService interface {
+		BaseCRUD[portainer.WebhookExecution, portainer.WebhookExecutionID]
+		ReadAllByWebhookID(webhookID portainer.WebhookID) ([]portainer.WebhookExecution, error)
+		DeleteByWebhookID(webhookID portainer.WebhookID) error
+	}
+
+	// CloudProvisioningService represents a service to manage the provisionings of environments on cloud providers
+	CloudProvisioningService interface {
+		BaseCRUD[portainer.CloudProvisioning, portainer.CloudProvisioningID]
+	}
+
+	// JWTSigningKeyService represents a service to manage the key pairs signing the session tokens
+	JWTSigningKeyService interface {
+		BaseCRUD[portainer.JWTSigningKey, portainer.JWTSigningKeyID]
+	}
+
+	// OpenAMTPowerScheduleService represents a service to manage the power schedules of the AMT managed devices
+	OpenAMTPowerScheduleService interface {
+		BaseCRUD[portainer.OpenAMTPowerSchedule, portainer.OpenAMTPowerScheduleID]
+	}
+
+	// OpenAMTDeviceActionService represents a service to manage the history of the actions executed on the AMT managed devices
+	OpenAMTDeviceActionService interface {
+		BaseCRUD[portainer.OpenAMTDeviceAction, portainer.OpenAMTDeviceActionID]
+		ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.OpenAMTDeviceAction, error)
+		DeleteByEndpointID(endpointID portainer.EndpointID) error
+	}
+
+	// UserSettingsService represents a service to manage the UI preferences of the users
+	UserSettingsService interface {
+		BaseCRUD[portainer.UserSettings, portainer.UserID]
+	}
+
+	// EdgeJoinTokenService represents a service to manage the tokens used by the Edge agents to register themselves
+	EdgeJoinTokenService interface {
+		BaseCRUD[portainer.EdgeJoinToken, portainer.EdgeJoinTokenID]
+		ReadByDigest(digest string) (*portainer.EdgeJoinToken, error)
+	}
+
+	// PruneRunService represents a service to manage the history of the runs of the prune policies
+	PruneRunService interface {
+		BaseCRUD[portainer.PruneRun, portainer.PruneRunID]
+		ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.PruneRun, error)
+		DeleteByEndpointID(endpointID portainer.EndpointID) error
+	}
+
 	// RegistryService represents a service for managing registry data
 	RegistryService interface {
 		BaseCRUD[portainer.Registry, portainer.RegistryID]
@@ -157,6 +307,23 @@ type (
 
 	SnapshotService interface {
 		BaseCRUD[portainer.Snapshot, portainer.EndpointID]
+		ReadSummary(endpointID portainer.EndpointID) (*portainer.Snapshot, error)
+	}
+
+	// SnapshotRecordService represents a service to manage the snapshot history of environments(endpoints)
+	SnapshotRecordService interface {
+		Create(record *portainer.SnapshotRecord) error
+		ReadAll() ([]portainer.SnapshotRecord, error)
+		ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.SnapshotRecord, error)
+		Delete(endpointID portainer.EndpointID, time int64) error
+		DeleteByEndpointID(endpointID portainer.EndpointID) error
+	}
+
+	// ActivityEventService represents a service to manage the activity feed of environments(endpoints)
+	ActivityEventService interface {
+		BaseCRUD[portainer.ActivityEvent, portainer.ActivityEventID]
+		ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.ActivityEvent, error)
+		DeleteByEndpointID(endpointID portainer.EndpointID) error
 	}
 
 	// SSLSettingsService represents a service for managing application settings
@@ -212,6 +379,13 @@ type (
 		UsersByRole(role portainer.UserRole) ([]portainer.User, error)
 	}
 
+	// UserSessionService represents a service for managing the sessions of the users
+	UserSessionService interface {
+		BaseCRUD[portainer.UserSession, portainer.UserSessionID]
+		UserSessionsByUserID(userID portainer.UserID) ([]portainer.UserSession, error)
+		UserSessionByTokenID(tokenID string) (*portainer.UserSession, error)
+	}
+
 	// VersionService represents a service for managing version data
 	VersionService interface {
 		InstanceID() (string, error)
@@ -0,0 +1,59 @@
+package jwtsigningkey
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "jwt_signing_keys"
+
+// Service represents a service for managing JWT signing key data.
+type Service struct {
+	dataservices.BaseDataService[portainer.JWTSigningKey, portainer.JWTSigningKeyID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.JWTSigningKey, portainer.JWTSigningKeyID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.JWTSigningKey, portainer.JWTSigningKeyID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.JWTSigningKey, portainer.JWTSigningKeyID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new JWT signing key and saves it.
+func (service *Service) Create(key *portainer.JWTSigningKey) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(key)
+	})
+}
+
+// Create assigns an ID to a new JWT signing key and saves it.
+func (service ServiceTx) Create(key *portainer.JWTSigningKey) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		key.ID = portainer.JWTSigningKeyID(id)
+
+		return int(key.ID), key
+	})
+}
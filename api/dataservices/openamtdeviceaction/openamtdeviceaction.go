@@ -0,0 +1,110 @@
+package openamtdeviceaction
+
+import (
+	"fmt"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "openamt_device_actions"
+
+// Service represents a service for managing the history of the actions executed on the AMT managed devices.
+type Service struct {
+	dataservices.BaseDataService[portainer.OpenAMTDeviceAction, portainer.OpenAMTDeviceActionID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.OpenAMTDeviceAction, portainer.OpenAMTDeviceActionID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.OpenAMTDeviceAction, portainer.OpenAMTDeviceActionID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.OpenAMTDeviceAction, portainer.OpenAMTDeviceActionID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new device action and saves it.
+func (service *Service) Create(action *portainer.OpenAMTDeviceAction) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(action)
+	})
+}
+
+// ReadAllByEndpointID returns the actions executed on the device of an environment(endpoint).
+func (service *Service) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.OpenAMTDeviceAction, error) {
+	var actions = make([]portainer.OpenAMTDeviceAction, 0)
+
+	return actions, service.Connection.GetAll(
+		BucketName,
+		&portainer.OpenAMTDeviceAction{},
+		dataservices.FilterFn(&actions, func(a portainer.OpenAMTDeviceAction) bool {
+			return a.EndpointID == endpointID
+		}),
+	)
+}
+
+// DeleteByEndpointID removes the actions executed on the device of an environment(endpoint).
+func (service *Service) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).DeleteByEndpointID(endpointID)
+	})
+}
+
+// Create assigns an ID to a new device action and saves it.
+func (service ServiceTx) Create(action *portainer.OpenAMTDeviceAction) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		action.ID = portainer.OpenAMTDeviceActionID(id)
+
+		return int(action.ID), action
+	})
+}
+
+// ReadAllByEndpointID returns the actions executed on the device of an environment(endpoint).
+func (service ServiceTx) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.OpenAMTDeviceAction, error) {
+	var actions = make([]portainer.OpenAMTDeviceAction, 0)
+
+	return actions, service.Tx.GetAll(
+		BucketName,
+		&portainer.OpenAMTDeviceAction{},
+		dataservices.FilterFn(&actions, func(a portainer.OpenAMTDeviceAction) bool {
+			return a.EndpointID == endpointID
+		}),
+	)
+}
+
+// DeleteByEndpointID removes the actions executed on the device of an environment(endpoint).
+func (service ServiceTx) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	actions, err := service.ReadAllByEndpointID(endpointID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the device actions of environment (%d): %w", endpointID, err)
+	}
+
+	for _, action := range actions {
+		if err := service.Delete(action.ID); err != nil {
+			return fmt.Errorf("failed to delete device action (%d): %w", action.ID, err)
+		}
+	}
+
+	return nil
+}
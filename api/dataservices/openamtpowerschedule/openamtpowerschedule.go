@@ -0,0 +1,59 @@
+package openamtpowerschedule
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "openamt_power_schedules"
+
+// Service represents a service for managing OpenAMT power schedule data.
+type Service struct {
+	dataservices.BaseDataService[portainer.OpenAMTPowerSchedule, portainer.OpenAMTPowerScheduleID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.OpenAMTPowerSchedule, portainer.OpenAMTPowerScheduleID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.OpenAMTPowerSchedule, portainer.OpenAMTPowerScheduleID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.OpenAMTPowerSchedule, portainer.OpenAMTPowerScheduleID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new power schedule and saves it.
+func (service *Service) Create(schedule *portainer.OpenAMTPowerSchedule) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(schedule)
+	})
+}
+
+// Create assigns an ID to a new power schedule and saves it.
+func (service ServiceTx) Create(schedule *portainer.OpenAMTPowerSchedule) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		schedule.ID = portainer.OpenAMTPowerScheduleID(id)
+
+		return int(schedule.ID), schedule
+	})
+}
@@ -0,0 +1,110 @@
+package prunerun
+
+import (
+	"fmt"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "prune_runs"
+
+// Service represents a service for managing the history of the runs of the prune policies.
+type Service struct {
+	dataservices.BaseDataService[portainer.PruneRun, portainer.PruneRunID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.PruneRun, portainer.PruneRunID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.PruneRun, portainer.PruneRunID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.PruneRun, portainer.PruneRunID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new prune run and saves it.
+func (service *Service) Create(run *portainer.PruneRun) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(run)
+	})
+}
+
+// ReadAllByEndpointID returns the prune runs of an environment(endpoint).
+func (service *Service) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.PruneRun, error) {
+	var runs = make([]portainer.PruneRun, 0)
+
+	return runs, service.Connection.GetAll(
+		BucketName,
+		&portainer.PruneRun{},
+		dataservices.FilterFn(&runs, func(r portainer.PruneRun) bool {
+			return r.EndpointID == endpointID
+		}),
+	)
+}
+
+// DeleteByEndpointID removes the prune runs of an environment(endpoint).
+func (service *Service) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).DeleteByEndpointID(endpointID)
+	})
+}
+
+// Create assigns an ID to a new prune run and saves it.
+func (service ServiceTx) Create(run *portainer.PruneRun) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		run.ID = portainer.PruneRunID(id)
+
+		return int(run.ID), run
+	})
+}
+
+// ReadAllByEndpointID returns the prune runs of an environment(endpoint).
+func (service ServiceTx) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.PruneRun, error) {
+	var runs = make([]portainer.PruneRun, 0)
+
+	return runs, service.Tx.GetAll(
+		BucketName,
+		&portainer.PruneRun{},
+		dataservices.FilterFn(&runs, func(r portainer.PruneRun) bool {
+			return r.EndpointID == endpointID
+		}),
+	)
+}
+
+// DeleteByEndpointID removes the prune runs of an environment(endpoint).
+func (service ServiceTx) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	runs, err := service.ReadAllByEndpointID(endpointID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the prune runs of environment (%d): %w", endpointID, err)
+	}
+
+	for _, run := range runs {
+		if err := service.Delete(run.ID); err != nil {
+			return fmt.Errorf("failed to delete prune run (%d): %w", run.ID, err)
+		}
+	}
+
+	return nil
+}
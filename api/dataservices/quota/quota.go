@@ -0,0 +1,59 @@
+package quota
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "quotas"
+
+// Service represents a service for managing quota data.
+type Service struct {
+	dataservices.BaseDataService[portainer.Quota, portainer.QuotaID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.Quota, portainer.QuotaID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.Quota, portainer.QuotaID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.Quota, portainer.QuotaID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new quota and saves it.
+func (service *Service) Create(quota *portainer.Quota) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(quota)
+	})
+}
+
+// Create assigns an ID to a new quota and saves it.
+func (service ServiceTx) Create(quota *portainer.Quota) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		quota.ID = portainer.QuotaID(id)
+
+		return int(quota.ID), quota
+	})
+}
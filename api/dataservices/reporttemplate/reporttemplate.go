@@ -0,0 +1,59 @@
+package reporttemplate
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "report_templates"
+
+// Service represents a service for managing report template data.
+type Service struct {
+	dataservices.BaseDataService[portainer.ReportTemplate, portainer.ReportTemplateID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.ReportTemplate, portainer.ReportTemplateID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.ReportTemplate, portainer.ReportTemplateID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.ReportTemplate, portainer.ReportTemplateID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new report template and saves it.
+func (service *Service) Create(template *portainer.ReportTemplate) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(template)
+	})
+}
+
+// Create assigns an ID to a new report template and saves it.
+func (service ServiceTx) Create(template *portainer.ReportTemplate) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		template.ID = portainer.ReportTemplateID(id)
+
+		return int(template.ID), template
+	})
+}
@@ -5,7 +5,12 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 )
 
-const BucketName = "snapshots"
+const (
+	BucketName = "snapshots"
+	// RawBucketName is the bucket storing the raw Docker data of the snapshots, kept apart so that the
+	// summaries of the snapshots can be read without deserializing it
+	RawBucketName = "snapshot_raws"
+)
 
 type Service struct {
 	dataservices.BaseDataService[portainer.Snapshot, portainer.EndpointID]
@@ -17,6 +22,10 @@ func NewService(connection portainer.Connection) (*Service, error) {
 		return nil, err
 	}
 
+	if err := connection.SetServiceName(RawBucketName); err != nil {
+		return nil, err
+	}
+
 	return &Service{
 		BaseDataService: dataservices.BaseDataService[portainer.Snapshot, portainer.EndpointID]{
 			Bucket:     BucketName,
@@ -36,5 +45,55 @@ func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
 }
 
 func (service *Service) Create(snapshot *portainer.Snapshot) error {
-	return service.Connection.CreateObjectWithId(BucketName, int(snapshot.EndpointID), snapshot)
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(snapshot)
+	})
+}
+
+// Read returns the snapshot of an environment(endpoint) including its raw Docker data
+func (service *Service) Read(endpointID portainer.EndpointID) (*portainer.Snapshot, error) {
+	var snapshot *portainer.Snapshot
+
+	return snapshot, service.Connection.ViewTx(func(tx portainer.Transaction) error {
+		var err error
+		snapshot, err = service.Tx(tx).Read(endpointID)
+
+		return err
+	})
+}
+
+// ReadSummary returns the snapshot of an environment(endpoint) without its raw Docker data
+func (service *Service) ReadSummary(endpointID portainer.EndpointID) (*portainer.Snapshot, error) {
+	var snapshot *portainer.Snapshot
+
+	return snapshot, service.Connection.ViewTx(func(tx portainer.Transaction) error {
+		var err error
+		snapshot, err = service.Tx(tx).ReadSummary(endpointID)
+
+		return err
+	})
+}
+
+// ReadAll returns the snapshots of all the environments(endpoints) including their raw Docker data
+func (service *Service) ReadAll() ([]portainer.Snapshot, error) {
+	var snapshots []portainer.Snapshot
+
+	return snapshots, service.Connection.ViewTx(func(tx portainer.Transaction) error {
+		var err error
+		snapshots, err = service.Tx(tx).ReadAll()
+
+		return err
+	})
+}
+
+func (service *Service) Update(endpointID portainer.EndpointID, snapshot *portainer.Snapshot) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Update(endpointID, snapshot)
+	})
+}
+
+func (service *Service) Delete(endpointID portainer.EndpointID) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Delete(endpointID)
+	})
 }
@@ -1,8 +1,11 @@
 package snapshot
 
 import (
+	"errors"
+
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
+	dserrors "github.com/portainer/portainer/api/dataservices/errors"
 )
 
 type ServiceTx struct {
@@ -10,5 +13,110 @@ type ServiceTx struct {
 }
 
 func (service ServiceTx) Create(snapshot *portainer.Snapshot) error {
-	return service.Tx.CreateObjectWithId(BucketName, int(snapshot.EndpointID), snapshot)
+	summary, err := service.storeRaw(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return service.Tx.CreateObjectWithId(BucketName, int(snapshot.EndpointID), summary)
+}
+
+// Read returns the snapshot of an environment(endpoint) including its raw Docker data
+func (service ServiceTx) Read(endpointID portainer.EndpointID) (*portainer.Snapshot, error) {
+	snapshot, err := service.BaseDataServiceTx.Read(endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, service.loadRaw(snapshot)
+}
+
+// ReadSummary returns the snapshot of an environment(endpoint) without its raw Docker data
+func (service ServiceTx) ReadSummary(endpointID portainer.EndpointID) (*portainer.Snapshot, error) {
+	snapshot, err := service.BaseDataServiceTx.Read(endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Snapshots stored before the raw data was moved to its own bucket still hold it
+	if snapshot.Docker != nil {
+		snapshot.Docker.SnapshotRaw = portainer.DockerSnapshotRaw{}
+	}
+
+	return snapshot, nil
+}
+
+// ReadAll returns the snapshots of all the environments(endpoints) including their raw Docker data
+func (service ServiceTx) ReadAll() ([]portainer.Snapshot, error) {
+	snapshots, err := service.BaseDataServiceTx.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range snapshots {
+		if err := service.loadRaw(&snapshots[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshots, nil
+}
+
+func (service ServiceTx) Update(endpointID portainer.EndpointID, snapshot *portainer.Snapshot) error {
+	summary, err := service.storeRaw(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return service.BaseDataServiceTx.Update(endpointID, summary)
+}
+
+func (service ServiceTx) Delete(endpointID portainer.EndpointID) error {
+	if err := service.Tx.DeleteObject(RawBucketName, service.Connection.ConvertToKey(int(endpointID))); err != nil {
+		return err
+	}
+
+	return service.BaseDataServiceTx.Delete(endpointID)
+}
+
+// storeRaw saves the raw Docker data of a snapshot in its own bucket and returns a copy of the snapshot without it
+func (service ServiceTx) storeRaw(snapshot *portainer.Snapshot) (*portainer.Snapshot, error) {
+	key := service.Connection.ConvertToKey(int(snapshot.EndpointID))
+
+	if snapshot.Docker == nil {
+		return snapshot, service.Tx.DeleteObject(RawBucketName, key)
+	}
+
+	if err := service.Tx.UpdateObject(RawBucketName, key, &snapshot.Docker.SnapshotRaw); err != nil {
+		return nil, err
+	}
+
+	docker := *snapshot.Docker
+	docker.SnapshotRaw = portainer.DockerSnapshotRaw{}
+
+	summary := *snapshot
+	summary.Docker = &docker
+
+	return &summary, nil
+}
+
+// loadRaw fills the raw Docker data of a snapshot from its own bucket
+func (service ServiceTx) loadRaw(snapshot *portainer.Snapshot) error {
+	if snapshot.Docker == nil {
+		return nil
+	}
+
+	var raw portainer.DockerSnapshotRaw
+
+	err := service.Tx.GetObject(RawBucketName, service.Connection.ConvertToKey(int(snapshot.EndpointID)), &raw)
+	if errors.Is(err, dserrors.ErrObjectNotFound) {
+		// Snapshots stored before the raw data was moved to its own bucket still hold it
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	snapshot.Docker.SnapshotRaw = raw
+
+	return nil
 }
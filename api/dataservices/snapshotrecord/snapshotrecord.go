@@ -0,0 +1,146 @@
+package snapshotrecord
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "snapshot_records"
+
+// Service represents a service for managing snapshot records.
+// The records are keyed by their environment(endpoint) followed by their time, so that the history of an
+// environment is read in chronological order without scanning the records of the other environments.
+type Service struct {
+	connection portainer.Connection
+}
+
+type ServiceTx struct {
+	service *Service
+	tx      portainer.Transaction
+}
+
+func (service *Service) BucketName() string {
+	return BucketName
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		connection: connection,
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		service: service,
+		tx:      tx,
+	}
+}
+
+// Create saves a snapshot record, replacing the record of the environment(endpoint) taken at the same time.
+func (service *Service) Create(record *portainer.SnapshotRecord) error {
+	return service.connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(record)
+	})
+}
+
+// ReadAll returns the snapshot records of all the environments(endpoints).
+func (service *Service) ReadAll() ([]portainer.SnapshotRecord, error) {
+	var records []portainer.SnapshotRecord
+
+	return records, service.connection.ViewTx(func(tx portainer.Transaction) error {
+		var err error
+		records, err = service.Tx(tx).ReadAll()
+
+		return err
+	})
+}
+
+// ReadAllByEndpointID returns the snapshot records of an environment(endpoint), sorted by time.
+func (service *Service) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.SnapshotRecord, error) {
+	var records []portainer.SnapshotRecord
+
+	return records, service.connection.ViewTx(func(tx portainer.Transaction) error {
+		var err error
+		records, err = service.Tx(tx).ReadAllByEndpointID(endpointID)
+
+		return err
+	})
+}
+
+// Delete removes the snapshot record of an environment(endpoint) taken at the given time.
+func (service *Service) Delete(endpointID portainer.EndpointID, time int64) error {
+	return service.connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Delete(endpointID, time)
+	})
+}
+
+// DeleteByEndpointID removes all the snapshot records of an environment(endpoint).
+func (service *Service) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	return service.connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).DeleteByEndpointID(endpointID)
+	})
+}
+
+// Create saves a snapshot record, replacing the record of the environment(endpoint) taken at the same time.
+func (service ServiceTx) Create(record *portainer.SnapshotRecord) error {
+	return service.tx.CreateObjectWithStringId(BucketName, service.key(record.EndpointID, record.Time), record)
+}
+
+// ReadAll returns the snapshot records of all the environments(endpoints).
+func (service ServiceTx) ReadAll() ([]portainer.SnapshotRecord, error) {
+	var records = make([]portainer.SnapshotRecord, 0)
+
+	return records, service.tx.GetAll(
+		BucketName,
+		&portainer.SnapshotRecord{},
+		dataservices.AppendFn(&records),
+	)
+}
+
+// ReadAllByEndpointID returns the snapshot records of an environment(endpoint), sorted by time.
+func (service ServiceTx) ReadAllByEndpointID(endpointID portainer.EndpointID) ([]portainer.SnapshotRecord, error) {
+	var records = make([]portainer.SnapshotRecord, 0)
+
+	return records, service.tx.GetAllWithKeyPrefix(
+		BucketName,
+		service.service.connection.ConvertToKey(int(endpointID)),
+		&portainer.SnapshotRecord{},
+		dataservices.AppendFn(&records),
+	)
+}
+
+// Delete removes the snapshot record of an environment(endpoint) taken at the given time.
+func (service ServiceTx) Delete(endpointID portainer.EndpointID, time int64) error {
+	return service.tx.DeleteObject(BucketName, service.key(endpointID, time))
+}
+
+// DeleteByEndpointID removes all the snapshot records of an environment(endpoint).
+func (service ServiceTx) DeleteByEndpointID(endpointID portainer.EndpointID) error {
+	records, err := service.ReadAllByEndpointID(endpointID)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if err := service.Delete(record.EndpointID, record.Time); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// key returns the identifier of a record, the environment(endpoint) identifier followed by the time
+// so that the records of an environment share the same prefix
+func (service ServiceTx) key(endpointID portainer.EndpointID, time int64) []byte {
+	connection := service.service.connection
+
+	return append(connection.ConvertToKey(int(endpointID)), connection.ConvertToKey(int(time))...)
+}
@@ -0,0 +1,44 @@
+package stackgitopsstatus
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "stack_gitops_status"
+
+// Service represents a service for managing the GitOps status of the stacks.
+type Service struct {
+	dataservices.BaseDataService[portainer.StackGitOpsStatus, portainer.StackID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.StackGitOpsStatus, portainer.StackID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.StackGitOpsStatus, portainer.StackID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create saves the GitOps status of a stack, it is identified by the stack identifier.
+func (service *Service) Create(status *portainer.StackGitOpsStatus) error {
+	return service.Connection.CreateObjectWithId(BucketName, int(status.StackID), status)
+}
@@ -0,0 +1,15 @@
+package stackgitopsstatus
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.StackGitOpsStatus, portainer.StackID]
+}
+
+// Create saves the GitOps status of a stack, it is identified by the stack identifier.
+func (service ServiceTx) Create(status *portainer.StackGitOpsStatus) error {
+	return service.Tx.CreateObjectWithId(BucketName, int(status.StackID), status)
+}
@@ -0,0 +1,59 @@
+package templatesource
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "template_sources"
+
+// Service represents a service for managing template source data.
+type Service struct {
+	dataservices.BaseDataService[portainer.TemplateSource, portainer.TemplateSourceID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.TemplateSource, portainer.TemplateSourceID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.TemplateSource, portainer.TemplateSourceID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.TemplateSource, portainer.TemplateSourceID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new template source and saves it.
+func (service *Service) Create(source *portainer.TemplateSource) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(source)
+	})
+}
+
+// Create assigns an ID to a new template source and saves it.
+func (service ServiceTx) Create(source *portainer.TemplateSource) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		source.ID = portainer.TemplateSourceID(id)
+
+		return int(source.ID), source
+	})
+}
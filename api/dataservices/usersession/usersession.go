@@ -0,0 +1,191 @@
+package usersession
+
+import (
+	"sync"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	dserrors "github.com/portainer/portainer/api/dataservices/errors"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "user_sessions"
+
+// indexEntry holds the fields of a user session kept in memory, the sessions are
+// decoded into it at startup instead of being fully loaded
+type indexEntry struct {
+	ID      portainer.UserSessionID `json:"Id"`
+	TokenID string                  `json:"TokenID"`
+}
+
+// Service represents a service for managing user session data.
+type Service struct {
+	dataservices.BaseDataService[portainer.UserSession, portainer.UserSessionID]
+	mu         sync.RWMutex
+	idxTokenID map[string]portainer.UserSessionID
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.UserSession, portainer.UserSessionID]
+	service *Service
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.UserSession, portainer.UserSessionID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+		idxTokenID: make(map[string]portainer.UserSessionID),
+	}
+
+	var entries []indexEntry
+	if err := connection.GetAll(BucketName, &indexEntry{}, dataservices.AppendFn(&entries)); err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		s.idxTokenID[e.TokenID] = e.ID
+	}
+
+	return s, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.UserSession, portainer.UserSessionID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+		service: service,
+	}
+}
+
+// Create assigns an ID to a new user session and saves it.
+func (service *Service) Create(session *portainer.UserSession) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(session)
+	})
+}
+
+// Update saves a user session.
+func (service *Service) Update(ID portainer.UserSessionID, session *portainer.UserSession) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Update(ID, session)
+	})
+}
+
+// Delete removes a user session.
+func (service *Service) Delete(ID portainer.UserSessionID) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Delete(ID)
+	})
+}
+
+// UserSessionsByUserID returns the sessions of a user.
+func (service *Service) UserSessionsByUserID(userID portainer.UserID) ([]portainer.UserSession, error) {
+	var sessions []portainer.UserSession
+
+	return sessions, service.Connection.ViewTx(func(tx portainer.Transaction) error {
+		var err error
+		sessions, err = service.Tx(tx).UserSessionsByUserID(userID)
+
+		return err
+	})
+}
+
+// UserSessionByTokenID returns the session of a JWT.
+func (service *Service) UserSessionByTokenID(tokenID string) (*portainer.UserSession, error) {
+	var session *portainer.UserSession
+
+	return session, service.Connection.ViewTx(func(tx portainer.Transaction) error {
+		var err error
+		session, err = service.Tx(tx).UserSessionByTokenID(tokenID)
+
+		return err
+	})
+}
+
+// Create assigns an ID to a new user session and saves it.
+func (service ServiceTx) Create(session *portainer.UserSession) error {
+	err := service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		session.ID = portainer.UserSessionID(id)
+
+		return int(session.ID), session
+	})
+	if err != nil {
+		return err
+	}
+
+	service.service.mu.Lock()
+	service.service.idxTokenID[session.TokenID] = session.ID
+	service.service.mu.Unlock()
+
+	return nil
+}
+
+// Update saves a user session.
+func (service ServiceTx) Update(ID portainer.UserSessionID, session *portainer.UserSession) error {
+	if err := service.BaseDataServiceTx.Update(ID, session); err != nil {
+		return err
+	}
+
+	service.service.mu.Lock()
+	service.service.idxTokenID[session.TokenID] = ID
+	service.service.mu.Unlock()
+
+	return nil
+}
+
+// Delete removes a user session.
+func (service ServiceTx) Delete(ID portainer.UserSessionID) error {
+	if err := service.BaseDataServiceTx.Delete(ID); err != nil {
+		return err
+	}
+
+	service.service.mu.Lock()
+	defer service.service.mu.Unlock()
+
+	for tokenID, sessionID := range service.service.idxTokenID {
+		if sessionID == ID {
+			delete(service.service.idxTokenID, tokenID)
+
+			break
+		}
+	}
+
+	return nil
+}
+
+// UserSessionsByUserID returns the sessions of a user.
+func (service ServiceTx) UserSessionsByUserID(userID portainer.UserID) ([]portainer.UserSession, error) {
+	var sessions = make([]portainer.UserSession, 0)
+
+	return sessions, service.Tx.GetAll(
+		BucketName,
+		&portainer.UserSession{},
+		dataservices.FilterFn(&sessions, func(e portainer.UserSession) bool {
+			return e.UserID == userID
+		}),
+	)
+}
+
+// UserSessionByTokenID returns the session of a JWT.
+func (service ServiceTx) UserSessionByTokenID(tokenID string) (*portainer.UserSession, error) {
+	service.service.mu.RLock()
+	ID, ok := service.service.idxTokenID[tokenID]
+	service.service.mu.RUnlock()
+
+	if !ok {
+		return nil, dserrors.ErrObjectNotFound
+	}
+
+	return service.Read(ID)
+}
@@ -0,0 +1,15 @@
+package usersettings
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.UserSettings, portainer.UserID]
+}
+
+// Create saves the settings of a user, they are identified by the user identifier.
+func (service ServiceTx) Create(settings *portainer.UserSettings) error {
+	return service.Tx.CreateObjectWithId(BucketName, int(settings.UserID), settings)
+}
@@ -0,0 +1,44 @@
+package usersettings
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "user_settings"
+
+// Service represents a service for managing the settings of the users.
+type Service struct {
+	dataservices.BaseDataService[portainer.UserSettings, portainer.UserID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.UserSettings, portainer.UserID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.UserSettings, portainer.UserID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create saves the settings of a user, they are identified by the user identifier.
+func (service *Service) Create(settings *portainer.UserSettings) error {
+	return service.Connection.CreateObjectWithId(BucketName, int(settings.UserID), settings)
+}
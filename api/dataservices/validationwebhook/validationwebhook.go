@@ -0,0 +1,59 @@
+package validationwebhook
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "validation_webhooks"
+
+// Service represents a service for managing validation webhook data.
+type Service struct {
+	dataservices.BaseDataService[portainer.ValidationWebhook, portainer.ValidationWebhookID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.ValidationWebhook, portainer.ValidationWebhookID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.ValidationWebhook, portainer.ValidationWebhookID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.ValidationWebhook, portainer.ValidationWebhookID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new validation webhook and saves it.
+func (service *Service) Create(webhook *portainer.ValidationWebhook) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(webhook)
+	})
+}
+
+// Create assigns an ID to a new validation webhook and saves it.
+func (service ServiceTx) Create(webhook *portainer.ValidationWebhook) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		webhook.ID = portainer.ValidationWebhookID(id)
+
+		return int(webhook.ID), webhook
+	})
+}
@@ -0,0 +1,110 @@
+package webhookexecution
+
+import (
+	"fmt"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// BucketName represents the name of the bucket where this service stores data.
+const BucketName = "webhook_executions"
+
+// Service represents a service for managing webhook executions.
+type Service struct {
+	dataservices.BaseDataService[portainer.WebhookExecution, portainer.WebhookExecutionID]
+}
+
+type ServiceTx struct {
+	dataservices.BaseDataServiceTx[portainer.WebhookExecution, portainer.WebhookExecutionID]
+}
+
+// NewService creates a new instance of a service.
+func NewService(connection portainer.Connection) (*Service, error) {
+	err := connection.SetServiceName(BucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		BaseDataService: dataservices.BaseDataService[portainer.WebhookExecution, portainer.WebhookExecutionID]{
+			Bucket:     BucketName,
+			Connection: connection,
+		},
+	}, nil
+}
+
+func (service *Service) Tx(tx portainer.Transaction) ServiceTx {
+	return ServiceTx{
+		BaseDataServiceTx: dataservices.BaseDataServiceTx[portainer.WebhookExecution, portainer.WebhookExecutionID]{
+			Bucket:     BucketName,
+			Connection: service.Connection,
+			Tx:         tx,
+		},
+	}
+}
+
+// Create assigns an ID to a new webhook execution and saves it.
+func (service *Service) Create(execution *portainer.WebhookExecution) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).Create(execution)
+	})
+}
+
+// ReadAllByWebhookID returns the executions of a webhook.
+func (service *Service) ReadAllByWebhookID(webhookID portainer.WebhookID) ([]portainer.WebhookExecution, error) {
+	var executions = make([]portainer.WebhookExecution, 0)
+
+	return executions, service.Connection.GetAll(
+		BucketName,
+		&portainer.WebhookExecution{},
+		dataservices.FilterFn(&executions, func(e portainer.WebhookExecution) bool {
+			return e.WebhookID == webhookID
+		}),
+	)
+}
+
+// DeleteByWebhookID removes all the executions of a webhook.
+func (service *Service) DeleteByWebhookID(webhookID portainer.WebhookID) error {
+	return service.Connection.UpdateTx(func(tx portainer.Transaction) error {
+		return service.Tx(tx).DeleteByWebhookID(webhookID)
+	})
+}
+
+// Create assigns an ID to a new webhook execution and saves it.
+func (service ServiceTx) Create(execution *portainer.WebhookExecution) error {
+	return service.Tx.CreateObject(BucketName, func(id uint64) (int, any) {
+		execution.ID = portainer.WebhookExecutionID(id)
+
+		return int(execution.ID), execution
+	})
+}
+
+// ReadAllByWebhookID returns the executions of a webhook.
+func (service ServiceTx) ReadAllByWebhookID(webhookID portainer.WebhookID) ([]portainer.WebhookExecution, error) {
+	var executions = make([]portainer.WebhookExecution, 0)
+
+	return executions, service.Tx.GetAll(
+		BucketName,
+		&portainer.WebhookExecution{},
+		dataservices.FilterFn(&executions, func(e portainer.WebhookExecution) bool {
+			return e.WebhookID == webhookID
+		}),
+	)
+}
+
+// DeleteByWebhookID removes all the executions of a webhook.
+func (service ServiceTx) DeleteByWebhookID(webhookID portainer.WebhookID) error {
+	executions, err := service.ReadAllByWebhookID(webhookID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the executions of webhook (%d): %w", webhookID, err)
+	}
+
+	for _, execution := range executions {
+		if err := service.Delete(execution.ID); err != nil {
+			return fmt.Errorf("failed to delete webhook execution (%d): %w", execution.ID, err)
+		}
+	}
+
+	return nil
+}
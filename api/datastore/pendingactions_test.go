@@ -2,10 +2,17 @@ package datastore
 
 import (
 	"testing"
+	"time"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/pendingactions"
 	"github.com/portainer/portainer/api/pendingactions/actions"
 	"github.com/portainer/portainer/api/pendingactions/handlers"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type cleanNAPWithOverridePolicies struct {
@@ -96,3 +103,90 @@ func Test_ConvertCleanNAPWithOverridePoliciesPayload(t *testing.T) {
 		}
 	})
 }
+
+func Test_CollectEdgeJobLogs(t *testing.T) {
+	_, store := MustNewTestStore(t, true, false)
+
+	endpoint := &portainer.Endpoint{ID: 1, Name: "edge", Type: portainer.EdgeAgentOnDockerEnvironment}
+	require.NoError(t, store.Endpoint().Create(endpoint))
+
+	edgeJob := &portainer.EdgeJob{
+		Endpoints: map[portainer.EndpointID]portainer.EdgeJobEndpointMeta{
+			endpoint.ID: {CollectLogs: true, LogsStatus: portainer.EdgeJobLogsStatusPending},
+		},
+	}
+	require.NoError(t, store.EdgeJob().Create(edgeJob))
+
+	pendingCollections := func() []handlers.PendingEdgeJobLogsCollection {
+		var collections []handlers.PendingEdgeJobLogsCollection
+		require.NoError(t, store.ViewTx(func(tx dataservices.DataStoreTx) error {
+			var err error
+			collections, err = handlers.PendingEdgeJobLogsCollections(tx, endpoint.ID)
+
+			return err
+		}))
+
+		return collections
+	}
+
+	execute := func() error {
+		pendingActions, err := store.PendingActions().ReadAll()
+		require.NoError(t, err)
+		require.Len(t, pendingActions, 1)
+
+		return handlers.NewHandlerCollectEdgeJobLogs(store).Execute(pendingActions[0], endpoint)
+	}
+
+	queue := func() {
+		require.NoError(t, store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+			return handlers.QueueEdgeJobLogsCollection(tx, endpoint.ID, edgeJob.ID)
+		}))
+	}
+
+	// Requesting the logs twice does not create a second collection
+	queue()
+	queue()
+
+	collections := pendingCollections()
+	require.Len(t, collections, 1)
+	assert.Equal(t, edgeJob.ID, collections[0].EdgeJobID)
+	assert.Zero(t, collections[0].Attempts)
+
+	// The agent lost the request, it is sent again once
+	edgeJob.Endpoints[endpoint.ID] = portainer.EdgeJobEndpointMeta{LogsStatus: portainer.EdgeJobLogsStatusPending}
+	require.NoError(t, store.EdgeJob().Update(edgeJob.ID, edgeJob))
+
+	require.ErrorIs(t, execute(), pendingactions.ErrRetryLater)
+	require.ErrorIs(t, execute(), pendingactions.ErrRetryLater)
+
+	collections = pendingCollections()
+	require.Len(t, collections, 1)
+	assert.Equal(t, 1, collections[0].Attempts)
+
+	edgeJob, err := store.EdgeJob().Read(edgeJob.ID)
+	require.NoError(t, err)
+	assert.True(t, edgeJob.Endpoints[endpoint.ID].CollectLogs)
+
+	// The collection is abandoned once expired
+	pendingActions, err := store.PendingActions().ReadAll()
+	require.NoError(t, err)
+	require.Len(t, pendingActions, 1)
+
+	pendingActions[0].CreatedAt = time.Now().Add(-handlers.EdgeJobLogsCollectionExpiry - time.Minute).Unix()
+	// Keep the stored action data as is instead of encoding it again
+	pendingActions[0].ActionData = json.RawMessage(pendingActions[0].ActionData.(string))
+	require.NoError(t, store.PendingActions().Update(pendingActions[0].ID, &pendingActions[0]))
+
+	require.NoError(t, execute())
+
+	edgeJob, err = store.EdgeJob().Read(edgeJob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, portainer.EdgeJobLogsStatusIdle, edgeJob.Endpoints[endpoint.ID].LogsStatus)
+
+	// Receiving the logs removes the collection
+	require.NoError(t, store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return handlers.DequeueEdgeJobLogsCollection(tx, endpoint.ID, edgeJob.ID)
+	}))
+
+	assert.Empty(t, pendingCollections())
+}
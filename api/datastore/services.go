@@ -7,33 +7,58 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/database/models"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/dataservices/activityevent"
 	"github.com/portainer/portainer/api/dataservices/apikeyrepository"
+	"github.com/portainer/portainer/api/dataservices/cloudprovisioning"
 	"github.com/portainer/portainer/api/dataservices/customtemplate"
+	"github.com/portainer/portainer/api/dataservices/deployment"
+	"github.com/portainer/portainer/api/dataservices/diskusage"
 	"github.com/portainer/portainer/api/dataservices/dockerhub"
+	"github.com/portainer/portainer/api/dataservices/edgecommand"
 	"github.com/portainer/portainer/api/dataservices/edgegroup"
 	"github.com/portainer/portainer/api/dataservices/edgejob"
+	"github.com/portainer/portainer/api/dataservices/edgejointoken"
 	"github.com/portainer/portainer/api/dataservices/edgestack"
+	"github.com/portainer/portainer/api/dataservices/edgeupdateschedule"
 	"github.com/portainer/portainer/api/dataservices/endpoint"
 	"github.com/portainer/portainer/api/dataservices/endpointgroup"
 	"github.com/portainer/portainer/api/dataservices/endpointrelation"
 	"github.com/portainer/portainer/api/dataservices/extension"
+	"github.com/portainer/portainer/api/dataservices/failoverpolicy"
+	"github.com/portainer/portainer/api/dataservices/groupreport"
 	"github.com/portainer/portainer/api/dataservices/helmuserrepository"
+	"github.com/portainer/portainer/api/dataservices/imageupdatejob"
+	"github.com/portainer/portainer/api/dataservices/jwtsigningkey"
+	"github.com/portainer/portainer/api/dataservices/notificationchannel"
+	"github.com/portainer/portainer/api/dataservices/openamtdeviceaction"
+	"github.com/portainer/portainer/api/dataservices/openamtpowerschedule"
 	"github.com/portainer/portainer/api/dataservices/pendingactions"
+	"github.com/portainer/portainer/api/dataservices/prunerun"
+	"github.com/portainer/portainer/api/dataservices/quota"
 	"github.com/portainer/portainer/api/dataservices/registry"
+	"github.com/portainer/portainer/api/dataservices/reporttemplate"
 	"github.com/portainer/portainer/api/dataservices/resourcecontrol"
 	"github.com/portainer/portainer/api/dataservices/role"
 	"github.com/portainer/portainer/api/dataservices/schedule"
 	"github.com/portainer/portainer/api/dataservices/settings"
 	"github.com/portainer/portainer/api/dataservices/snapshot"
+	"github.com/portainer/portainer/api/dataservices/snapshotrecord"
+	"github.com/portainer/portainer/api/dataservices/snapshotwebhook"
 	"github.com/portainer/portainer/api/dataservices/ssl"
 	"github.com/portainer/portainer/api/dataservices/stack"
+	"github.com/portainer/portainer/api/dataservices/stackgitopsstatus"
 	"github.com/portainer/portainer/api/dataservices/tag"
 	"github.com/portainer/portainer/api/dataservices/team"
 	"github.com/portainer/portainer/api/dataservices/teammembership"
+	"github.com/portainer/portainer/api/dataservices/templatesource"
 	"github.com/portainer/portainer/api/dataservices/tunnelserver"
 	"github.com/portainer/portainer/api/dataservices/user"
+	"github.com/portainer/portainer/api/dataservices/usersession"
+	"github.com/portainer/portainer/api/dataservices/usersettings"
+	"github.com/portainer/portainer/api/dataservices/validationwebhook"
 	"github.com/portainer/portainer/api/dataservices/version"
 	"github.com/portainer/portainer/api/dataservices/webhook"
+	"github.com/portainer/portainer/api/dataservices/webhookexecution"
 
 	"github.com/rs/zerolog/log"
 	"github.com/segmentio/encoding/json"
@@ -44,34 +69,59 @@ import (
 type Store struct {
 	connection portainer.Connection
 
-	fileService               portainer.FileService
-	CustomTemplateService     *customtemplate.Service
-	DockerHubService          *dockerhub.Service
-	EdgeGroupService          *edgegroup.Service
-	EdgeJobService            *edgejob.Service
-	EdgeStackService          *edgestack.Service
-	EndpointGroupService      *endpointgroup.Service
-	EndpointService           *endpoint.Service
-	EndpointRelationService   *endpointrelation.Service
-	ExtensionService          *extension.Service
-	HelmUserRepositoryService *helmuserrepository.Service
-	RegistryService           *registry.Service
-	ResourceControlService    *resourcecontrol.Service
-	RoleService               *role.Service
-	APIKeyRepositoryService   *apikeyrepository.Service
-	ScheduleService           *schedule.Service
-	SettingsService           *settings.Service
-	SnapshotService           *snapshot.Service
-	SSLSettingsService        *ssl.Service
-	StackService              *stack.Service
-	TagService                *tag.Service
-	TeamMembershipService     *teammembership.Service
-	TeamService               *team.Service
-	TunnelServerService       *tunnelserver.Service
-	UserService               *user.Service
-	VersionService            *version.Service
-	WebhookService            *webhook.Service
-	PendingActionsService     *pendingactions.Service
+	fileService                 portainer.FileService
+	CustomTemplateService       *customtemplate.Service
+	DockerHubService            *dockerhub.Service
+	EdgeGroupService            *edgegroup.Service
+	EdgeJobService              *edgejob.Service
+	EdgeStackService            *edgestack.Service
+	EndpointGroupService        *endpointgroup.Service
+	EndpointService             *endpoint.Service
+	EndpointRelationService     *endpointrelation.Service
+	ExtensionService            *extension.Service
+	HelmUserRepositoryService   *helmuserrepository.Service
+	RegistryService             *registry.Service
+	ResourceControlService      *resourcecontrol.Service
+	RoleService                 *role.Service
+	APIKeyRepositoryService     *apikeyrepository.Service
+	ScheduleService             *schedule.Service
+	SettingsService             *settings.Service
+	SnapshotService             *snapshot.Service
+	SSLSettingsService          *ssl.Service
+	StackService                *stack.Service
+	TagService                  *tag.Service
+	TeamMembershipService       *teammembership.Service
+	TeamService                 *team.Service
+	TunnelServerService         *tunnelserver.Service
+	UserService                 *user.Service
+	VersionService              *version.Service
+	WebhookService              *webhook.Service
+	PendingActionsService       *pendingactions.Service
+	ImageUpdateJobService       *imageupdatejob.Service
+	FailoverPolicyService       *failoverpolicy.Service
+	QuotaService                *quota.Service
+	DiskUsageSampleService      *diskusage.Service
+	SnapshotRecordService       *snapshotrecord.Service
+	DeploymentService           *deployment.Service
+	EdgeCommandService          *edgecommand.Service
+	ActivityEventService        *activityevent.Service
+	GroupReportService          *groupreport.Service
+	ReportTemplateService       *reporttemplate.Service
+	ValidationWebhookService    *validationwebhook.Service
+	SnapshotWebhookService      *snapshotwebhook.Service
+	NotificationChannelService  *notificationchannel.Service
+	UserSessionService          *usersession.Service
+	StackGitOpsStatusService    *stackgitopsstatus.Service
+	EdgeUpdateScheduleService   *edgeupdateschedule.Service
+	TemplateSourceService       *templatesource.Service
+	WebhookExecutionService     *webhookexecution.Service
+	CloudProvisioningService    *cloudprovisioning.Service
+	JWTSigningKeyService        *jwtsigningkey.Service
+	OpenAMTPowerScheduleService *openamtpowerschedule.Service
+	OpenAMTDeviceActionService  *openamtdeviceaction.Service
+	UserSettingsService         *usersettings.Service
+	EdgeJoinTokenService        *edgejointoken.Service
+	PruneRunService             *prunerun.Service
 }
 
 func (store *Store) initServices() error {
@@ -238,6 +288,156 @@ func (store *Store) initServices() error {
 	}
 	store.PendingActionsService = pendingActionsService
 
+	imageUpdateJobService, err := imageupdatejob.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.ImageUpdateJobService = imageUpdateJobService
+
+	failoverPolicyService, err := failoverpolicy.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.FailoverPolicyService = failoverPolicyService
+
+	quotaService, err := quota.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.QuotaService = quotaService
+
+	diskUsageSampleService, err := diskusage.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.DiskUsageSampleService = diskUsageSampleService
+
+	snapshotRecordService, err := snapshotrecord.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.SnapshotRecordService = snapshotRecordService
+
+	deploymentService, err := deployment.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.DeploymentService = deploymentService
+
+	edgeCommandService, err := edgecommand.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.EdgeCommandService = edgeCommandService
+
+	activityEventService, err := activityevent.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.ActivityEventService = activityEventService
+
+	groupReportService, err := groupreport.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.GroupReportService = groupReportService
+
+	reportTemplateService, err := reporttemplate.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.ReportTemplateService = reportTemplateService
+
+	validationWebhookService, err := validationwebhook.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.ValidationWebhookService = validationWebhookService
+
+	snapshotWebhookService, err := snapshotwebhook.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.SnapshotWebhookService = snapshotWebhookService
+
+	notificationChannelService, err := notificationchannel.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.NotificationChannelService = notificationChannelService
+
+	userSessionService, err := usersession.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.UserSessionService = userSessionService
+
+	stackGitOpsStatusService, err := stackgitopsstatus.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.StackGitOpsStatusService = stackGitOpsStatusService
+
+	edgeUpdateScheduleService, err := edgeupdateschedule.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.EdgeUpdateScheduleService = edgeUpdateScheduleService
+
+	templateSourceService, err := templatesource.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.TemplateSourceService = templateSourceService
+
+	webhookExecutionService, err := webhookexecution.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.WebhookExecutionService = webhookExecutionService
+
+	cloudProvisioningService, err := cloudprovisioning.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.CloudProvisioningService = cloudProvisioningService
+
+	jwtSigningKeyService, err := jwtsigningkey.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.JWTSigningKeyService = jwtSigningKeyService
+
+	openAMTPowerScheduleService, err := openamtpowerschedule.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.OpenAMTPowerScheduleService = openAMTPowerScheduleService
+
+	openAMTDeviceActionService, err := openamtdeviceaction.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.OpenAMTDeviceActionService = openAMTDeviceActionService
+
+	userSettingsService, err := usersettings.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.UserSettingsService = userSettingsService
+
+	edgeJoinTokenService, err := edgejointoken.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.EdgeJoinTokenService = edgeJoinTokenService
+
+	pruneRunService, err := prunerun.NewService(store.connection)
+	if err != nil {
+		return err
+	}
+	store.PruneRunService = pruneRunService
+
 	return nil
 }
 
@@ -246,6 +446,131 @@ func (store *Store) PendingActions() dataservices.PendingActionsService {
 	return store.PendingActionsService
 }
 
+// ImageUpdateJob gives access to the ImageUpdateJob data management layer
+func (store *Store) ImageUpdateJob() dataservices.ImageUpdateJobService {
+	return store.ImageUpdateJobService
+}
+
+// FailoverPolicy gives access to the FailoverPolicy data management layer
+func (store *Store) FailoverPolicy() dataservices.FailoverPolicyService {
+	return store.FailoverPolicyService
+}
+
+// Quota gives access to the Quota data management layer
+func (store *Store) Quota() dataservices.QuotaService {
+	return store.QuotaService
+}
+
+// DiskUsageSample gives access to the DiskUsageSample data management layer
+func (store *Store) DiskUsageSample() dataservices.DiskUsageSampleService {
+	return store.DiskUsageSampleService
+}
+
+// SnapshotRecord gives access to the SnapshotRecord data management layer
+func (store *Store) SnapshotRecord() dataservices.SnapshotRecordService {
+	return store.SnapshotRecordService
+}
+
+// Deployment gives access to the Deployment data management layer
+func (store *Store) Deployment() dataservices.DeploymentService {
+	return store.DeploymentService
+}
+
+// EdgeCommand gives access to the EdgeCommand data management layer
+func (store *Store) EdgeCommand() dataservices.EdgeCommandService {
+	return store.EdgeCommandService
+}
+
+// ActivityEvent gives access to the ActivityEvent data management layer
+func (store *Store) ActivityEvent() dataservices.ActivityEventService {
+	return store.ActivityEventService
+}
+
+// GroupReport gives access to the GroupReport data management layer
+func (store *Store) GroupReport() dataservices.GroupReportService {
+	return store.GroupReportService
+}
+
+// ReportTemplate gives access to the ReportTemplate data management layer
+func (store *Store) ReportTemplate() dataservices.ReportTemplateService {
+	return store.ReportTemplateService
+}
+
+// ValidationWebhook gives access to the ValidationWebhook data management layer
+func (store *Store) ValidationWebhook() dataservices.ValidationWebhookService {
+	return store.ValidationWebhookService
+}
+
+// SnapshotWebhook gives access to the SnapshotWebhook data management layer
+func (store *Store) SnapshotWebhook() dataservices.SnapshotWebhookService {
+	return store.SnapshotWebhookService
+}
+
+// NotificationChannel gives access to the NotificationChannel data management layer
+func (store *Store) NotificationChannel() dataservices.NotificationChannelService {
+	return store.NotificationChannelService
+}
+
+// UserSession gives access to the UserSession data management layer
+func (store *Store) UserSession() dataservices.UserSessionService {
+	return store.UserSessionService
+}
+
+// StackGitOpsStatus gives access to the StackGitOpsStatus data management layer
+func (store *Store) StackGitOpsStatus() dataservices.StackGitOpsStatusService {
+	return store.StackGitOpsStatusService
+}
+
+// EdgeUpdateSchedule gives access to the EdgeUpdateSchedule data management layer
+func (store *Store) EdgeUpdateSchedule() dataservices.EdgeUpdateScheduleService {
+	return store.EdgeUpdateScheduleService
+}
+
+// TemplateSource gives access to the TemplateSource data management layer
+func (store *Store) TemplateSource() dataservices.TemplateSourceService {
+	return store.TemplateSourceService
+}
+
+// WebhookExecution gives access to the WebhookExecution data management layer
+func (store *Store) WebhookExecution() dataservices.WebhookExecutionService {
+	return store.WebhookExecutionService
+}
+
+// CloudProvisioning gives access to the CloudProvisioning data management layer
+func (store *Store) CloudProvisioning() dataservices.CloudProvisioningService {
+	return store.CloudProvisioningService
+}
+
+// JWTSigningKey gives access to the JWTSigningKey data management layer
+func (store *Store) JWTSigningKey() dataservices.JWTSigningKeyService {
+	return store.JWTSigningKeyService
+}
+
+// OpenAMTPowerSchedule gives access to the OpenAMTPowerSchedule data management layer
+func (store *Store) OpenAMTPowerSchedule() dataservices.OpenAMTPowerScheduleService {
+	return store.OpenAMTPowerScheduleService
+}
+
+// OpenAMTDeviceAction gives access to the OpenAMTDeviceAction data management layer
+func (store *Store) OpenAMTDeviceAction() dataservices.OpenAMTDeviceActionService {
+	return store.OpenAMTDeviceActionService
+}
+
+// UserSettings gives access to the UserSettings data management layer
+func (store *Store) UserSettings() dataservices.UserSettingsService {
+	return store.UserSettingsService
+}
+
+// EdgeJoinToken gives access to the EdgeJoinToken data management layer
+func (store *Store) EdgeJoinToken() dataservices.EdgeJoinTokenService {
+	return store.EdgeJoinTokenService
+}
+
+// PruneRun gives access to the PruneRun data management layer
+func (store *Store) PruneRun() dataservices.PruneRunService {
+	return store.PruneRunService
+}
+
 // CustomTemplate gives access to the CustomTemplate data management layer
 func (store *Store) CustomTemplate() dataservices.CustomTemplateService {
 	return store.CustomTemplateService
@@ -361,31 +686,52 @@ func (store *Store) Webhook() dataservices.WebhookService {
 }
 
 type storeExport struct {
-	CustomTemplate     []portainer.CustomTemplate     `json:"customtemplates,omitempty"`
-	EdgeGroup          []portainer.EdgeGroup          `json:"edgegroups,omitempty"`
-	EdgeJob            []portainer.EdgeJob            `json:"edgejobs,omitempty"`
-	EdgeStack          []portainer.EdgeStack          `json:"edge_stack,omitempty"`
-	Endpoint           []portainer.Endpoint           `json:"endpoints,omitempty"`
-	EndpointGroup      []portainer.EndpointGroup      `json:"endpoint_groups,omitempty"`
-	EndpointRelation   []portainer.EndpointRelation   `json:"endpoint_relations,omitempty"`
-	Extensions         []portainer.Extension          `json:"extension,omitempty"`
-	HelmUserRepository []portainer.HelmUserRepository `json:"helm_user_repository,omitempty"`
-	Registry           []portainer.Registry           `json:"registries,omitempty"`
-	ResourceControl    []portainer.ResourceControl    `json:"resource_control,omitempty"`
-	Role               []portainer.Role               `json:"roles,omitempty"`
-	Schedules          []portainer.Schedule           `json:"schedules,omitempty"`
-	Settings           portainer.Settings             `json:"settings,omitempty"`
-	Snapshot           []portainer.Snapshot           `json:"snapshots,omitempty"`
-	SSLSettings        portainer.SSLSettings          `json:"ssl,omitempty"`
-	Stack              []portainer.Stack              `json:"stacks,omitempty"`
-	Tag                []portainer.Tag                `json:"tags,omitempty"`
-	TeamMembership     []portainer.TeamMembership     `json:"team_membership,omitempty"`
-	Team               []portainer.Team               `json:"teams,omitempty"`
-	TunnelServer       portainer.TunnelServerInfo     `json:"tunnel_server,omitempty"`
-	User               []portainer.User               `json:"users,omitempty"`
-	Version            models.Version                 `json:"version,omitempty"`
-	Webhook            []portainer.Webhook            `json:"webhooks,omitempty"`
-	Metadata           map[string]any                 `json:"metadata,omitempty"`
+	CustomTemplate       []portainer.CustomTemplate       `json:"customtemplates,omitempty"`
+	EdgeGroup            []portainer.EdgeGroup            `json:"edgegroups,omitempty"`
+	EdgeJob              []portainer.EdgeJob              `json:"edgejobs,omitempty"`
+	EdgeStack            []portainer.EdgeStack            `json:"edge_stack,omitempty"`
+	Endpoint             []portainer.Endpoint             `json:"endpoints,omitempty"`
+	EndpointGroup        []portainer.EndpointGroup        `json:"endpoint_groups,omitempty"`
+	EndpointRelation     []portainer.EndpointRelation     `json:"endpoint_relations,omitempty"`
+	Extensions           []portainer.Extension            `json:"extension,omitempty"`
+	HelmUserRepository   []portainer.HelmUserRepository   `json:"helm_user_repository,omitempty"`
+	Registry             []portainer.Registry             `json:"registries,omitempty"`
+	ResourceControl      []portainer.ResourceControl      `json:"resource_control,omitempty"`
+	Role                 []portainer.Role                 `json:"roles,omitempty"`
+	Schedules            []portainer.Schedule             `json:"schedules,omitempty"`
+	Settings             portainer.Settings               `json:"settings,omitempty"`
+	Snapshot             []portainer.Snapshot             `json:"snapshots,omitempty"`
+	SSLSettings          portainer.SSLSettings            `json:"ssl,omitempty"`
+	Stack                []portainer.Stack                `json:"stacks,omitempty"`
+	Tag                  []portainer.Tag                  `json:"tags,omitempty"`
+	TeamMembership       []portainer.TeamMembership       `json:"team_membership,omitempty"`
+	Team                 []portainer.Team                 `json:"teams,omitempty"`
+	TunnelServer         portainer.TunnelServerInfo       `json:"tunnel_server,omitempty"`
+	User                 []portainer.User                 `json:"users,omitempty"`
+	Version              models.Version                   `json:"version,omitempty"`
+	Webhook              []portainer.Webhook              `json:"webhooks,omitempty"`
+	Deployment           []portainer.Deployment           `json:"deployments,omitempty"`
+	ImageUpdateJob       []portainer.ImageUpdateJob       `json:"image_update_jobs,omitempty"`
+	FailoverPolicy       []portainer.FailoverPolicy       `json:"failover_policies,omitempty"`
+	Quota                []portainer.Quota                `json:"quotas,omitempty"`
+	GroupReport          []portainer.GroupReport          `json:"group_reports,omitempty"`
+	ReportTemplate       []portainer.ReportTemplate       `json:"report_templates,omitempty"`
+	ValidationWebhook    []portainer.ValidationWebhook    `json:"validation_webhooks,omitempty"`
+	SnapshotWebhook      []portainer.SnapshotWebhook      `json:"snapshot_webhooks,omitempty"`
+	NotificationChannel  []portainer.NotificationChannel  `json:"notification_channels,omitempty"`
+	UserSession          []portainer.UserSession          `json:"user_sessions,omitempty"`
+	StackGitOpsStatus    []portainer.StackGitOpsStatus    `json:"stack_gitops_status,omitempty"`
+	EdgeUpdateSchedule   []portainer.EdgeUpdateSchedule   `json:"edge_update_schedules,omitempty"`
+	TemplateSource       []portainer.TemplateSource       `json:"template_sources,omitempty"`
+	WebhookExecution     []portainer.WebhookExecution     `json:"webhook_executions,omitempty"`
+	CloudProvisioning    []portainer.CloudProvisioning    `json:"cloud_provisionings,omitempty"`
+	JWTSigningKey        []portainer.JWTSigningKey        `json:"jwt_signing_keys,omitempty"`
+	OpenAMTPowerSchedule []portainer.OpenAMTPowerSchedule `json:"openamt_power_schedules,omitempty"`
+	OpenAMTDeviceAction  []portainer.OpenAMTDeviceAction  `json:"openamt_device_actions,omitempty"`
+	UserSettings         []portainer.UserSettings         `json:"user_settings,omitempty"`
+	EdgeJoinToken        []portainer.EdgeJoinToken        `json:"edge_join_tokens,omitempty"`
+	PruneRun             []portainer.PruneRun             `json:"prune_runs,omitempty"`
+	Metadata             map[string]any                   `json:"metadata,omitempty"`
 }
 
 func (store *Store) Export(filename string) (err error) {
@@ -575,6 +921,174 @@ func (store *Store) Export(filename string) (err error) {
 		backup.Webhook = webhooks
 	}
 
+	if v, err := store.Deployment().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting Deployments")
+		}
+	} else {
+		backup.Deployment = v
+	}
+
+	if v, err := store.ImageUpdateJob().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting ImageUpdateJobs")
+		}
+	} else {
+		backup.ImageUpdateJob = v
+	}
+
+	if v, err := store.FailoverPolicy().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting FailoverPolicies")
+		}
+	} else {
+		backup.FailoverPolicy = v
+	}
+
+	if v, err := store.Quota().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting Quotas")
+		}
+	} else {
+		backup.Quota = v
+	}
+
+	if v, err := store.GroupReport().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting GroupReports")
+		}
+	} else {
+		backup.GroupReport = v
+	}
+
+	if v, err := store.ReportTemplate().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting ReportTemplates")
+		}
+	} else {
+		backup.ReportTemplate = v
+	}
+
+	if v, err := store.ValidationWebhook().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting ValidationWebhooks")
+		}
+	} else {
+		backup.ValidationWebhook = v
+	}
+
+	if v, err := store.SnapshotWebhook().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting SnapshotWebhooks")
+		}
+	} else {
+		backup.SnapshotWebhook = v
+	}
+
+	if v, err := store.NotificationChannel().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting NotificationChannels")
+		}
+	} else {
+		backup.NotificationChannel = v
+	}
+
+	if v, err := store.UserSession().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting UserSessions")
+		}
+	} else {
+		backup.UserSession = v
+	}
+
+	if v, err := store.StackGitOpsStatus().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting StackGitOpsStatus")
+		}
+	} else {
+		backup.StackGitOpsStatus = v
+	}
+
+	if v, err := store.EdgeUpdateSchedule().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting EdgeUpdateSchedules")
+		}
+	} else {
+		backup.EdgeUpdateSchedule = v
+	}
+
+	if v, err := store.TemplateSource().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting TemplateSources")
+		}
+	} else {
+		backup.TemplateSource = v
+	}
+
+	if v, err := store.WebhookExecution().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting WebhookExecutions")
+		}
+	} else {
+		backup.WebhookExecution = v
+	}
+
+	if v, err := store.CloudProvisioning().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting CloudProvisionings")
+		}
+	} else {
+		backup.CloudProvisioning = v
+	}
+
+	if v, err := store.JWTSigningKey().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting JWTSigningKeys")
+		}
+	} else {
+		backup.JWTSigningKey = v
+	}
+
+	if v, err := store.OpenAMTPowerSchedule().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting OpenAMTPowerSchedules")
+		}
+	} else {
+		backup.OpenAMTPowerSchedule = v
+	}
+
+	if v, err := store.OpenAMTDeviceAction().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting OpenAMTDeviceActions")
+		}
+	} else {
+		backup.OpenAMTDeviceAction = v
+	}
+
+	if v, err := store.UserSettings().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting UserSettings")
+		}
+	} else {
+		backup.UserSettings = v
+	}
+
+	if v, err := store.EdgeJoinToken().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting EdgeJoinTokens")
+		}
+	} else {
+		backup.EdgeJoinToken = v
+	}
+
+	if v, err := store.PruneRun().ReadAll(); err != nil {
+		if !store.IsErrObjectNotFound(err) {
+			log.Error().Err(err).Msg("exporting PruneRuns")
+		}
+	} else {
+		backup.PruneRun = v
+	}
+
 	if version, err := store.Version().Version(); err != nil {
 		if !store.IsErrObjectNotFound(err) {
 			log.Error().Err(err).Msg("exporting Version")
@@ -689,5 +1203,89 @@ func (store *Store) Import(filename string) (err error) {
 		store.Webhook().Update(v.ID, &v)
 	}
 
+	for _, v := range backup.Deployment {
+		store.Deployment().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.ImageUpdateJob {
+		store.ImageUpdateJob().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.FailoverPolicy {
+		store.FailoverPolicy().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.Quota {
+		store.Quota().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.GroupReport {
+		store.GroupReport().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.ReportTemplate {
+		store.ReportTemplate().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.ValidationWebhook {
+		store.ValidationWebhook().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.SnapshotWebhook {
+		store.SnapshotWebhook().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.NotificationChannel {
+		store.NotificationChannel().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.UserSession {
+		store.UserSession().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.StackGitOpsStatus {
+		store.StackGitOpsStatus().Update(v.StackID, &v)
+	}
+
+	for _, v := range backup.EdgeUpdateSchedule {
+		store.EdgeUpdateSchedule().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.TemplateSource {
+		store.TemplateSource().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.WebhookExecution {
+		store.WebhookExecution().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.CloudProvisioning {
+		store.CloudProvisioning().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.JWTSigningKey {
+		store.JWTSigningKey().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.OpenAMTPowerSchedule {
+		store.OpenAMTPowerSchedule().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.OpenAMTDeviceAction {
+		store.OpenAMTDeviceAction().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.UserSettings {
+		store.UserSettings().Update(v.UserID, &v)
+	}
+
+	for _, v := range backup.EdgeJoinToken {
+		store.EdgeJoinToken().Update(v.ID, &v)
+	}
+
+	for _, v := range backup.PruneRun {
+		store.PruneRun().Update(v.ID, &v)
+	}
+
 	return store.connection.RestoreMetadata(backup.Metadata)
 }
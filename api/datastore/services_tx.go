@@ -20,6 +20,106 @@ func (tx *StoreTx) PendingActions() dataservices.PendingActionsService {
 	return tx.store.PendingActionsService.Tx(tx.tx)
 }
 
+func (tx *StoreTx) ImageUpdateJob() dataservices.ImageUpdateJobService {
+	return tx.store.ImageUpdateJobService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) FailoverPolicy() dataservices.FailoverPolicyService {
+	return tx.store.FailoverPolicyService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) Quota() dataservices.QuotaService {
+	return tx.store.QuotaService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) DiskUsageSample() dataservices.DiskUsageSampleService {
+	return tx.store.DiskUsageSampleService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) SnapshotRecord() dataservices.SnapshotRecordService {
+	return tx.store.SnapshotRecordService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) Deployment() dataservices.DeploymentService {
+	return tx.store.DeploymentService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) EdgeCommand() dataservices.EdgeCommandService {
+	return tx.store.EdgeCommandService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) ActivityEvent() dataservices.ActivityEventService {
+	return tx.store.ActivityEventService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) GroupReport() dataservices.GroupReportService {
+	return tx.store.GroupReportService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) ReportTemplate() dataservices.ReportTemplateService {
+	return tx.store.ReportTemplateService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) ValidationWebhook() dataservices.ValidationWebhookService {
+	return tx.store.ValidationWebhookService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) SnapshotWebhook() dataservices.SnapshotWebhookService {
+	return tx.store.SnapshotWebhookService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) NotificationChannel() dataservices.NotificationChannelService {
+	return tx.store.NotificationChannelService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) UserSession() dataservices.UserSessionService {
+	return tx.store.UserSessionService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) StackGitOpsStatus() dataservices.StackGitOpsStatusService {
+	return tx.store.StackGitOpsStatusService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) EdgeUpdateSchedule() dataservices.EdgeUpdateScheduleService {
+	return tx.store.EdgeUpdateScheduleService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) TemplateSource() dataservices.TemplateSourceService {
+	return tx.store.TemplateSourceService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) WebhookExecution() dataservices.WebhookExecutionService {
+	return tx.store.WebhookExecutionService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) CloudProvisioning() dataservices.CloudProvisioningService {
+	return tx.store.CloudProvisioningService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) JWTSigningKey() dataservices.JWTSigningKeyService {
+	return tx.store.JWTSigningKeyService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) OpenAMTPowerSchedule() dataservices.OpenAMTPowerScheduleService {
+	return tx.store.OpenAMTPowerScheduleService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) OpenAMTDeviceAction() dataservices.OpenAMTDeviceActionService {
+	return tx.store.OpenAMTDeviceActionService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) UserSettings() dataservices.UserSettingsService {
+	return tx.store.UserSettingsService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) EdgeJoinToken() dataservices.EdgeJoinTokenService {
+	return tx.store.EdgeJoinTokenService.Tx(tx.tx)
+}
+
+func (tx *StoreTx) PruneRun() dataservices.PruneRunService {
+	return tx.store.PruneRunService.Tx(tx.tx)
+}
+
 func (tx *StoreTx) EdgeGroup() dataservices.EdgeGroupService {
 	return tx.store.EdgeGroupService.Tx(tx.tx)
 }
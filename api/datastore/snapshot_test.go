@@ -0,0 +1,77 @@
+package datastore
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRawDataIsStoredApart(t *testing.T) {
+	_, store := MustNewTestStore(t, true, true)
+
+	snapshot := &portainer.Snapshot{
+		EndpointID: 1,
+		Docker: &portainer.DockerSnapshot{
+			RunningContainerCount: 2,
+			SnapshotRaw:           portainer.DockerSnapshotRaw{Images: []image.Summary{{ID: "sha256:1"}}},
+		},
+	}
+	require.NoError(t, store.Snapshot().Create(snapshot))
+
+	// The snapshot given to Create is left untouched
+	assert.NotNil(t, snapshot.Docker.SnapshotRaw.Images)
+
+	full, err := store.Snapshot().Read(1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, full.Docker.RunningContainerCount)
+	assert.Equal(t, snapshot.Docker.SnapshotRaw.Images, full.Docker.SnapshotRaw.Images)
+
+	summary, err := store.Snapshot().ReadSummary(1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Docker.RunningContainerCount)
+	assert.Nil(t, summary.Docker.SnapshotRaw.Images)
+
+	require.NoError(t, store.Snapshot().Delete(1))
+
+	_, err = store.Snapshot().Read(1)
+	assert.True(t, store.IsErrObjectNotFound(err))
+}
+
+func TestSnapshotRecordsAreKeyedByEndpointAndTime(t *testing.T) {
+	_, store := MustNewTestStore(t, true, true)
+
+	for _, record := range []portainer.SnapshotRecord{
+		{EndpointID: 2, Time: 300},
+		{EndpointID: 1, Time: 200},
+		{EndpointID: 2, Time: 100},
+		{EndpointID: 1, Time: 100},
+	} {
+		require.NoError(t, store.SnapshotRecord().Create(&record))
+	}
+
+	records, err := store.SnapshotRecord().ReadAllByEndpointID(2)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, int64(100), records[0].Time)
+	assert.Equal(t, int64(300), records[1].Time)
+
+	err = store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return tx.SnapshotRecord().Delete(2, 100)
+	})
+	require.NoError(t, err)
+
+	records, err = store.SnapshotRecord().ReadAllByEndpointID(2)
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	require.NoError(t, store.SnapshotRecord().DeleteByEndpointID(1))
+
+	records, err = store.SnapshotRecord().ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 1)
+}
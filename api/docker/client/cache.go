@@ -0,0 +1,67 @@
+package client
+
+import (
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/docker/docker/client"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// DefaultClientCacheSize is the number of Docker clients kept by a ClientFactory
+const DefaultClientCacheSize = 256
+
+// clientKey identifies the configuration of a Docker client, a change of the configuration of an environment
+// results in a new key so that a stale client is never returned
+type clientKey struct {
+	endpointID   portainer.EndpointID
+	endpointType portainer.EndpointType
+	url          string
+	nodeName     string
+	timeout      time.Duration
+	tls          portainer.TLSConfiguration
+}
+
+// clientCache is a concurrency-safe, bounded cache of the Docker clients, the least recently used client is
+// evicted first. The clients are shared, they keep their connections open across the requests.
+type clientCache struct {
+	cache *lru.Cache
+}
+
+func newClientCache(size int) *clientCache {
+	cache, _ := lru.NewWithEvict(size, func(_, value any) {
+		closeIdleConnections(value.(*client.Client))
+	})
+
+	return &clientCache{cache: cache}
+}
+
+func (c *clientCache) get(key clientKey) (*client.Client, bool) {
+	value, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	return value.(*client.Client), true
+}
+
+func (c *clientCache) add(key clientKey, cli *client.Client) {
+	c.cache.Add(key, cli)
+}
+
+// removeEndpoint removes the clients of an environment
+func (c *clientCache) removeEndpoint(endpointID portainer.EndpointID) {
+	for _, key := range c.cache.Keys() {
+		if key.(clientKey).endpointID == endpointID {
+			c.cache.Remove(key)
+		}
+	}
+}
+
+// closeIdleConnections releases the connections of an evicted client, the requests in progress are not interrupted
+func closeIdleConnections(cli *client.Client) {
+	if transport, ok := cli.HTTPClient().Transport.(interface{ CloseIdleConnections() }); ok {
+		transport.CloseIdleConnections()
+	}
+}
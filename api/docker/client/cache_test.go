@@ -0,0 +1,47 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateClientCache(t *testing.T) {
+	factory := NewClientFactory(nil, nil)
+
+	endpoint := &portainer.Endpoint{ID: 1, Type: portainer.DockerEnvironment, URL: "tcp://10.0.0.1:2375"}
+
+	cli, err := factory.CreateClient(endpoint, "", nil)
+	require.NoError(t, err)
+
+	// The client is reused, closing it does not prevent its reuse
+	require.NoError(t, cli.Close())
+
+	cached, err := factory.CreateClient(endpoint, "", nil)
+	require.NoError(t, err)
+	assert.Same(t, cli, cached)
+
+	// A different timeout requires another client
+	timeout := 5 * time.Second
+	other, err := factory.CreateClient(endpoint, "", &timeout)
+	require.NoError(t, err)
+	assert.NotSame(t, cli, other)
+
+	// A change of the configuration of the environment requires another client
+	updated := *endpoint
+	updated.URL = "tcp://10.0.0.2:2375"
+
+	other, err = factory.CreateClient(&updated, "", nil)
+	require.NoError(t, err)
+	assert.NotSame(t, cli, other)
+
+	factory.RemoveEndpointClients(endpoint.ID)
+
+	other, err = factory.CreateClient(endpoint, "", nil)
+	require.NoError(t, err)
+	assert.NotSame(t, cli, other)
+}
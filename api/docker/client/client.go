@@ -7,6 +7,7 @@ import (
 	"maps"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
@@ -28,6 +29,7 @@ const (
 type ClientFactory struct {
 	signatureService     portainer.DigitalSignatureService
 	reverseTunnelService portainer.ReverseTunnelService
+	clients              *clientCache
 }
 
 // NewClientFactory returns a new instance of a ClientFactory
@@ -35,6 +37,7 @@ func NewClientFactory(signatureService portainer.DigitalSignatureService, revers
 	return &ClientFactory{
 		signatureService:     signatureService,
 		reverseTunnelService: reverseTunnelService,
+		clients:              newClientCache(DefaultClientCacheSize),
 	}
 }
 
@@ -42,20 +45,57 @@ func NewClientFactory(signatureService portainer.DigitalSignatureService, revers
 // a specific environment(endpoint) configuration. The nodeName parameter can be used
 // with an agent enabled environment(endpoint) to target a specific node in an agent cluster.
 // The underlying http client timeout may be specified, a default value is used otherwise.
+// The clients are cached and shared, closing a client does not prevent its reuse.
 func (factory *ClientFactory) CreateClient(endpoint *portainer.Endpoint, nodeName string, timeout *time.Duration) (*client.Client, error) {
+	endpointURL := endpoint.URL
+
 	switch endpoint.Type {
 	case portainer.AzureEnvironment:
 		return nil, errUnsupportedEnvironmentType
-	case portainer.AgentOnDockerEnvironment:
-		return createAgentClient(endpoint, endpoint.URL, factory.signatureService, nodeName, timeout)
 	case portainer.EdgeAgentOnDockerEnvironment:
 		tunnelAddr, err := factory.reverseTunnelService.TunnelAddr(endpoint)
 		if err != nil {
 			return nil, err
 		}
 
-		endpointURL := "http://" + tunnelAddr
+		endpointURL = "http://" + tunnelAddr
+	}
+
+	key := clientKey{
+		endpointID:   endpoint.ID,
+		endpointType: endpoint.Type,
+		url:          endpointURL,
+		nodeName:     nodeName,
+		timeout:      defaultDockerRequestTimeout,
+		tls:          endpoint.TLSConfig,
+	}
+
+	if timeout != nil {
+		key.timeout = *timeout
+	}
+
+	if cli, ok := factory.clients.get(key); ok {
+		return cli, nil
+	}
+
+	cli, err := factory.createClient(endpoint, endpointURL, nodeName, timeout)
+	if err != nil {
+		return nil, err
+	}
 
+	factory.clients.add(key, cli)
+
+	return cli, nil
+}
+
+// RemoveEndpointClients removes the cached clients of an environment so that new ones are created
+func (factory *ClientFactory) RemoveEndpointClients(endpointID portainer.EndpointID) {
+	factory.clients.removeEndpoint(endpointID)
+}
+
+func (factory *ClientFactory) createClient(endpoint *portainer.Endpoint, endpointURL, nodeName string, timeout *time.Duration) (*client.Client, error) {
+	switch endpoint.Type {
+	case portainer.AgentOnDockerEnvironment, portainer.EdgeAgentOnDockerEnvironment:
 		return createAgentClient(endpoint, endpointURL, factory.signatureService, nodeName, timeout)
 	}
 
@@ -111,20 +151,11 @@ func createAgentClient(endpoint *portainer.Endpoint, endpointURL string, signatu
 		return nil, err
 	}
 
-	signature, err := signatureService.CreateSignature(portainer.PortainerAgentSignatureMessage)
+	headers, err := agentHeaders(signatureService, nodeName)
 	if err != nil {
 		return nil, err
 	}
 
-	headers := map[string]string{
-		portainer.PortainerAgentPublicKeyHeader: signatureService.EncodedPublicKey(),
-		portainer.PortainerAgentSignatureHeader: signature,
-	}
-
-	if nodeName != "" {
-		headers[portainer.PortainerAgentTargetHeader] = nodeName
-	}
-
 	opts := []client.Opt{
 		client.WithHost(endpointURL),
 		client.WithAPIVersionNegotiation(),
@@ -139,8 +170,33 @@ func createAgentClient(endpoint *portainer.Endpoint, endpointURL string, signatu
 	return client.NewClientWithOpts(opts...)
 }
 
+// AgentHeaders returns the headers authenticating a request sent directly to an agent, the nodeName parameter
+// can be used to target a specific node in an agent cluster
+func (factory *ClientFactory) AgentHeaders(nodeName string) (map[string]string, error) {
+	return agentHeaders(factory.signatureService, nodeName)
+}
+
+func agentHeaders(signatureService portainer.DigitalSignatureService, nodeName string) (map[string]string, error) {
+	signature, err := signatureService.CreateSignature(portainer.PortainerAgentSignatureMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := map[string]string{
+		portainer.PortainerAgentPublicKeyHeader: signatureService.EncodedPublicKey(),
+		portainer.PortainerAgentSignatureHeader: signature,
+	}
+
+	if nodeName != "" {
+		headers[portainer.PortainerAgentTargetHeader] = nodeName
+	}
+
+	return headers, nil
+}
+
 type NodeNameTransport struct {
 	*http.Transport
+	mu        sync.RWMutex
 	nodeNames map[string]string
 }
 
@@ -176,15 +232,22 @@ func (t *NodeNameTransport) RoundTrip(req *http.Request) (*http.Response, error)
 		return resp, nil
 	}
 
-	t.nodeNames = make(map[string]string)
+	nodeNames := make(map[string]string)
 	for _, r := range rs {
-		t.nodeNames[r.ID] = r.Portainer.Agent.NodeName
+		nodeNames[r.ID] = r.Portainer.Agent.NodeName
 	}
 
+	t.mu.Lock()
+	t.nodeNames = nodeNames
+	t.mu.Unlock()
+
 	return resp, err
 }
 
 func (t *NodeNameTransport) NodeNames() map[string]string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
 	return maps.Clone(t.nodeNames)
 }
 
@@ -71,6 +71,43 @@ func clearMacAddrs(n network.NetworkingConfig) network.NetworkingConfig {
 
 // Recreate a container
 func (c *ContainerService) Recreate(ctx context.Context, endpoint *portainer.Endpoint, containerId string, forcePullImage bool, imageTag, nodeName string) (*types.ContainerJSON, error) {
+	return c.recreate(ctx, endpoint, containerId, forcePullImage, "", imageTag, nodeName)
+}
+
+// RecreateWithImage recreates a container with the same configuration from another image, the image of
+// the container is kept when the given image is empty
+func (c *ContainerService) RecreateWithImage(ctx context.Context, endpoint *portainer.Endpoint, containerId string, forcePullImage bool, image, nodeName string) (*types.ContainerJSON, error) {
+	return c.recreate(ctx, endpoint, containerId, forcePullImage, image, "", nodeName)
+}
+
+// ReassignResources moves the resource control and the webhook of a recreated container to its new identifier
+func (c *ContainerService) ReassignResources(oldContainerID, newContainerID string) error {
+	resourceControl, err := c.dataStore.ResourceControl().ResourceControlByResourceIDAndType(oldContainerID, portainer.ContainerResourceControl)
+	if err != nil {
+		return errors.WithMessage(err, "unable to retrieve the resource control of the container")
+	}
+
+	// The resource controls inherited from a stack or a service are kept as is
+	if resourceControl != nil && resourceControl.ResourceID == oldContainerID {
+		resourceControl.ResourceID = newContainerID
+		if err := c.dataStore.ResourceControl().Update(resourceControl.ID, resourceControl); err != nil {
+			return errors.WithMessage(err, "unable to update the resource control of the container")
+		}
+	}
+
+	webhook, err := c.dataStore.Webhook().WebhookByResourceID(oldContainerID)
+	if c.dataStore.IsErrObjectNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.WithMessage(err, "unable to retrieve the webhook of the container")
+	}
+
+	webhook.ResourceID = newContainerID
+
+	return c.dataStore.Webhook().Update(webhook.ID, webhook)
+}
+
+func (c *ContainerService) recreate(ctx context.Context, endpoint *portainer.Endpoint, containerId string, forcePullImage bool, image, imageTag, nodeName string) (*types.ContainerJSON, error) {
 	cli, err := c.factory.CreateClient(endpoint, nodeName, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "create client error")
@@ -84,6 +121,10 @@ func (c *ContainerService) Recreate(ctx context.Context, endpoint *portainer.End
 		return nil, errors.Wrap(err, "fetch container information error")
 	}
 
+	if image != "" {
+		container.Config.Image = image
+	}
+
 	log.Debug().Str("image", container.Config.Image).Msg("starting to parse image")
 	img, err := images.ParseImage(images.ParseImageOptions{
 		Name: container.Config.Image,
@@ -3,7 +3,11 @@ package docker
 import (
 	"testing"
 
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+
 	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -50,3 +54,30 @@ func TestApplyVersionConstraint(t *testing.T) {
 	f("a.b.", "< 1.44", true, false)  // Invalid current version
 	f("1.45", "z 1.44", false, false) // Invalid version constraint
 }
+
+func TestReassignResources(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, false)
+
+	resourceControl := &portainer.ResourceControl{ResourceID: "old", Type: portainer.ContainerResourceControl, UserAccesses: []portainer.UserResourceAccess{{UserID: 2}}}
+	require.NoError(t, store.ResourceControl().Create(resourceControl))
+
+	webhook := &portainer.Webhook{Token: "token", ResourceID: "old", WebhookType: portainer.ContainerWebhook}
+	require.NoError(t, store.Webhook().Create(webhook))
+
+	service := NewContainerService(nil, store)
+	require.NoError(t, service.ReassignResources("old", "new"))
+
+	// The resource control is moved rather than copied
+	resourceControls, err := store.ResourceControl().ReadAll()
+	require.NoError(t, err)
+	require.Len(t, resourceControls, 1)
+	assert.Equal(t, "new", resourceControls[0].ResourceID)
+	assert.Equal(t, resourceControl.UserAccesses, resourceControls[0].UserAccesses)
+
+	webhook, err = store.Webhook().Read(webhook.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "new", webhook.ResourceID)
+
+	// A container without resource control nor webhook has nothing to move
+	require.NoError(t, service.ReassignResources("other", "another"))
+}
@@ -79,6 +79,17 @@ func (c *RegistryClient) EncodedCertainRegistryAuth(registry *portainer.Registry
 	return registryutils.GetRegistryAuthHeader(registry)
 }
 
+// MatchRegistry returns the registry whose credentials are used to pull the image among the given registries, nil
+// when none of them matches
+func MatchRegistry(image Image, registries []portainer.Registry) *portainer.Registry {
+	registry, err := findBestMatchRegistry(image.opts.Name, registries)
+	if err != nil {
+		return nil
+	}
+
+	return registry
+}
+
 // findBestMatchRegistry finds out the best match registry for repository @Meng
 // matching precedence:
 // 1. both domain name and username matched (for dockerhub only)
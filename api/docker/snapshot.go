@@ -2,10 +2,14 @@ package docker
 
 import (
 	"context"
+	"errors"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/agent"
 	dockerclient "github.com/portainer/portainer/api/docker/client"
 	"github.com/portainer/portainer/api/docker/consts"
 
@@ -20,12 +24,17 @@ import (
 // Snapshotter represents a service used to create environment(endpoint) snapshots
 type Snapshotter struct {
 	clientFactory *dockerclient.ClientFactory
+
+	diskUsagesMu sync.Mutex
+	// Last disk usage computed for each environment, reported by the snapshots until it is computed again
+	diskUsages map[portainer.EndpointID]*portainer.DockerDiskUsage
 }
 
 // NewSnapshotter returns a new Snapshotter instance
 func NewSnapshotter(clientFactory *dockerclient.ClientFactory) *Snapshotter {
 	return &Snapshotter{
 		clientFactory: clientFactory,
+		diskUsages:    make(map[portainer.EndpointID]*portainer.DockerDiskUsage),
 	}
 }
 
@@ -37,10 +46,10 @@ func (snapshotter *Snapshotter) CreateSnapshot(endpoint *portainer.Endpoint) (*p
 	}
 	defer cli.Close()
 
-	return snapshot(cli, endpoint)
+	return snapshotter.snapshot(cli, endpoint)
 }
 
-func snapshot(cli *client.Client, endpoint *portainer.Endpoint) (*portainer.DockerSnapshot, error) {
+func (snapshotter *Snapshotter) snapshot(cli *client.Client, endpoint *portainer.Endpoint) (*portainer.DockerSnapshot, error) {
 	if _, err := cli.Ping(context.Background()); err != nil {
 		return nil, err
 	}
@@ -63,26 +72,51 @@ func snapshot(cli *client.Client, endpoint *portainer.Endpoint) (*portainer.Dock
 		}
 	}
 
-	if err := snapshotContainers(snapshot, cli); err != nil {
-		log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to snapshot containers")
-	}
+	agentEnvironment := endpoint.Type == portainer.AgentOnDockerEnvironment || endpoint.Type == portainer.EdgeAgentOnDockerEnvironment
 
-	if err := snapshotImages(snapshot, cli); err != nil {
-		log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to snapshot images")
+	clusterSnapshotted := false
+	if snapshot.Swarm && agentEnvironment {
+		if err := snapshotter.snapshotAgentCluster(snapshot, cli, endpoint); err != nil {
+			log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to snapshot the nodes of the agent cluster, falling back to a cluster-wide snapshot")
+		} else {
+			clusterSnapshotted = true
+		}
 	}
 
-	if err := snapshotVolumes(snapshot, cli); err != nil {
-		log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to snapshot volumes")
+	if !clusterSnapshotted {
+		if err := snapshotContainers(snapshot, cli); err != nil {
+			log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to snapshot containers")
+		}
+
+		if err := snapshotImages(snapshot, cli); err != nil {
+			log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to snapshot images")
+		}
+
+		if err := snapshotVolumes(snapshot, cli); err != nil {
+			log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to snapshot volumes")
+		}
 	}
 
 	if err := snapshotNetworks(snapshot, cli); err != nil {
 		log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to snapshot networks")
 	}
 
+	if err := snapshotter.snapshotDiskUsage(snapshot, cli, endpoint); err != nil {
+		log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to snapshot disk usage")
+	}
+
 	if err := snapshotVersion(snapshot, cli); err != nil {
 		log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to snapshot engine version")
 	}
 
+	if agentEnvironment {
+		if err := snapshotter.snapshotGPUs(snapshot, cli); errors.Is(err, agent.ErrGPUInventoryNotSupported) {
+			log.Debug().Str("environment", endpoint.Name).Msg("the agent does not report the GPUs")
+		} else if err != nil {
+			log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to snapshot GPUs")
+		}
+	}
+
 	snapshot.Time = time.Now().Unix()
 
 	return snapshot, nil
@@ -100,6 +134,10 @@ func snapshotInfo(snapshot *portainer.DockerSnapshot, cli *client.Client) error
 	snapshot.TotalMemory = info.MemTotal
 	snapshot.SnapshotRaw.Info = info
 
+	if systemTime, err := time.Parse(time.RFC3339Nano, info.SystemTime); err == nil {
+		snapshot.ClockSkew = int64(time.Until(systemTime).Round(time.Second) / time.Second)
+	}
+
 	return nil
 }
 
@@ -142,6 +180,7 @@ func snapshotSwarmServices(snapshot *portainer.DockerSnapshot, cli *client.Clien
 
 	snapshot.ServiceCount = len(services)
 	snapshot.StackCount += len(stacks)
+	snapshot.SnapshotRaw.Services = services
 
 	return nil
 }
@@ -249,6 +288,88 @@ func snapshotVolumes(snapshot *portainer.DockerSnapshot, cli *client.Client) err
 	return nil
 }
 
+// snapshotDiskUsage computes the disk usage of the environment at most once per portainer.DiskUsageSampleInterval,
+// the last computed usage is reported in between
+func (snapshotter *Snapshotter) snapshotDiskUsage(snapshot *portainer.DockerSnapshot, cli *client.Client, endpoint *portainer.Endpoint) error {
+	snapshotter.diskUsagesMu.Lock()
+	previous := snapshotter.diskUsages[endpoint.ID]
+	snapshotter.diskUsagesMu.Unlock()
+
+	if previous != nil && time.Since(time.Unix(previous.ComputedAt, 0)) < portainer.DiskUsageSampleInterval {
+		snapshot.DiskUsage = previous
+
+		return nil
+	}
+
+	du, err := cli.DiskUsage(context.Background(), types.DiskUsageOptions{})
+	if err != nil {
+		return err
+	}
+
+	usage := &portainer.DockerDiskUsage{
+		ComputedAt:   time.Now().Unix(),
+		DataRootUsed: du.LayersSize,
+		Volumes:      make(map[string]int64),
+	}
+
+	for _, c := range du.Containers {
+		usage.DataRootUsed += c.SizeRw
+	}
+
+	for _, b := range du.BuildCache {
+		usage.DataRootUsed += b.Size
+	}
+
+	for _, v := range du.Volumes {
+		// Size is -1 when the engine was not able to compute it
+		if v.UsageData == nil || v.UsageData.Size < 0 {
+			continue
+		}
+
+		usage.Volumes[v.Name] = v.UsageData.Size
+		if v.Driver == "local" {
+			usage.DataRootUsed += v.UsageData.Size
+		}
+	}
+
+	if endpoint.Type == portainer.AgentOnDockerEnvironment || endpoint.Type == portainer.EdgeAgentOnDockerEnvironment {
+		usage.DataRootCapacity, err = snapshotter.dataRootCapacity(cli)
+		if errors.Is(err, agent.ErrHostInfoNotSupported) {
+			log.Debug().Str("environment", endpoint.Name).Msg("the agent does not report the size of the Docker data directory filesystem")
+		} else if err != nil {
+			log.Warn().Str("environment", endpoint.Name).Err(err).Msg("unable to retrieve the size of the Docker data directory filesystem")
+		}
+	}
+
+	snapshot.DiskUsage = usage
+
+	snapshotter.diskUsagesMu.Lock()
+	snapshotter.diskUsages[endpoint.ID] = usage
+	snapshotter.diskUsagesMu.Unlock()
+
+	return nil
+}
+
+// dataRootCapacity returns the size of the filesystem holding the Docker data directory reported by the agent
+func (snapshotter *Snapshotter) dataRootCapacity(cli *client.Client) (int64, error) {
+	agentURL, err := agentURL(cli)
+	if err != nil {
+		return 0, err
+	}
+
+	headers, err := snapshotter.clientFactory.AgentHeaders("")
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := agent.GetHostInfo(context.Background(), cli.HTTPClient(), agentURL, headers)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.DataRootFilesystemSize, nil
+}
+
 func snapshotNetworks(snapshot *portainer.DockerSnapshot, cli *client.Client) error {
 	networks, err := cli.NetworkList(context.Background(), types.NetworkListOptions{})
 	if err != nil {
@@ -271,6 +392,70 @@ func snapshotVersion(snapshot *portainer.DockerSnapshot, cli *client.Client) err
 	return nil
 }
 
+// snapshotGPUs retrieves the NVIDIA GPUs from the agent, from every node of an agent cluster
+func (snapshotter *Snapshotter) snapshotGPUs(snapshot *portainer.DockerSnapshot, cli *client.Client) error {
+	agentURL, err := agentURL(cli)
+	if err != nil {
+		return err
+	}
+
+	nodeNames := []string{""}
+	if snapshot.Swarm {
+		nodes, err := cli.NodeList(context.Background(), types.NodeListOptions{})
+		if err != nil {
+			return err
+		}
+
+		nodeNames = nodeNames[:0]
+		for _, node := range nodes {
+			nodeNames = append(nodeNames, node.Description.Hostname)
+		}
+	}
+
+	gpus := []portainer.DockerSnapshotGPU{}
+	for _, nodeName := range nodeNames {
+		headers, err := snapshotter.clientFactory.AgentHeaders(nodeName)
+		if err != nil {
+			return err
+		}
+
+		nodeGPUs, err := agent.GetGPUs(context.Background(), cli.HTTPClient(), agentURL, headers)
+		if errors.Is(err, agent.ErrGPUInventoryNotSupported) {
+			return err
+		} else if err != nil {
+			// The GPUs of the other nodes are still reported
+			log.Warn().Str("node", nodeName).Err(err).Msg("unable to retrieve the GPUs of the node")
+
+			continue
+		}
+
+		for i := range nodeGPUs {
+			nodeGPUs[i].NodeName = nodeName
+		}
+
+		gpus = append(gpus, nodeGPUs...)
+	}
+
+	snapshot.Gpus = gpus
+
+	return nil
+}
+
+// agentURL returns the URL of the agent the Docker client is connected to
+func agentURL(cli *client.Client) (string, error) {
+	host, err := url.Parse(cli.DaemonHost())
+	if err != nil {
+		return "", err
+	}
+
+	scheme := "http"
+	if transport, ok := cli.HTTPClient().Transport.(*dockerclient.NodeNameTransport); ok && transport.TLSClientConfig != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + host.Host, nil
+}
+
 // isPodman checks if the version is for Podman by checking if any of the components contain "podman".
 // If it's podman, a component name should be "Podman Engine"
 func isPodman(version types.Version) bool {
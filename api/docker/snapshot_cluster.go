@@ -0,0 +1,155 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker/consts"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/errgroup"
+)
+
+// maxParallelNodeSnapshots is the number of nodes of an agent cluster snapshotted at the same time
+const maxParallelNodeSnapshots = 10
+
+// nodeSnapshotResult holds the snapshot of a single node of an agent cluster
+type nodeSnapshotResult struct {
+	nodeName string
+	snapshot *portainer.DockerSnapshot
+	err      error
+}
+
+// snapshotAgentCluster snapshots the containers, images and volumes of every node of an agent cluster in parallel,
+// each node being targeted through the agent, and aggregates them into the snapshot of the cluster
+func (snapshotter *Snapshotter) snapshotAgentCluster(snapshot *portainer.DockerSnapshot, cli *client.Client, endpoint *portainer.Endpoint) error {
+	nodes, err := cli.NodeList(context.Background(), types.NodeListOptions{})
+	if err != nil {
+		return err
+	}
+
+	results := make([]nodeSnapshotResult, len(nodes))
+
+	var g errgroup.Group
+	g.SetLimit(maxParallelNodeSnapshots)
+
+	for i, node := range nodes {
+		nodeName := node.Description.Hostname
+
+		g.Go(func() error {
+			nodeSnapshot, err := snapshotter.snapshotAgentNode(endpoint, nodeName)
+			results[i] = nodeSnapshotResult{nodeName: nodeName, snapshot: nodeSnapshot, err: err}
+
+			// A failing node does not prevent the other nodes from being snapshotted
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return aggregateNodeSnapshots(snapshot, results)
+}
+
+// snapshotAgentNode snapshots the containers, images and volumes of a single node of an agent cluster
+func (snapshotter *Snapshotter) snapshotAgentNode(endpoint *portainer.Endpoint, nodeName string) (*portainer.DockerSnapshot, error) {
+	cli, err := snapshotter.clientFactory.CreateClient(endpoint, nodeName, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	snapshot := &portainer.DockerSnapshot{}
+
+	if err := snapshotContainers(snapshot, cli); err != nil {
+		return snapshot, fmt.Errorf("unable to snapshot containers: %w", err)
+	}
+
+	if err := snapshotImages(snapshot, cli); err != nil {
+		return snapshot, fmt.Errorf("unable to snapshot images: %w", err)
+	}
+
+	if err := snapshotVolumes(snapshot, cli); err != nil {
+		return snapshot, fmt.Errorf("unable to snapshot volumes: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// aggregateNodeSnapshots adds up the snapshots of the nodes of an agent cluster and records the breakdown per node,
+// it fails without altering the snapshot when no node could be snapshotted
+func aggregateNodeSnapshots(snapshot *portainer.DockerSnapshot, results []nodeSnapshotResult) error {
+	if len(results) == 0 {
+		return errors.New("the cluster has no node")
+	}
+
+	if !slices.ContainsFunc(results, func(result nodeSnapshotResult) bool { return result.err == nil }) {
+		return errors.New("unable to snapshot any node of the cluster")
+	}
+
+	stacks := make(map[string]struct{})
+	gpuUseSet := make(map[string]struct{})
+	nodes := make([]portainer.DockerNodeSnapshot, 0, len(results))
+
+	for _, result := range results {
+		node := portainer.DockerNodeSnapshot{NodeName: result.nodeName}
+
+		if result.err != nil {
+			log.Warn().Str("node", result.nodeName).Err(result.err).Msg("unable to snapshot the node")
+
+			node.Error = result.err.Error()
+		}
+
+		if nodeSnapshot := result.snapshot; nodeSnapshot != nil {
+			node.ContainerCount = nodeSnapshot.ContainerCount
+			node.RunningContainerCount = nodeSnapshot.RunningContainerCount
+			node.StoppedContainerCount = nodeSnapshot.StoppedContainerCount
+			node.HealthyContainerCount = nodeSnapshot.HealthyContainerCount
+			node.UnhealthyContainerCount = nodeSnapshot.UnhealthyContainerCount
+			node.ImageCount = nodeSnapshot.ImageCount
+			node.VolumeCount = nodeSnapshot.VolumeCount
+
+			snapshot.ContainerCount += nodeSnapshot.ContainerCount
+			snapshot.RunningContainerCount += nodeSnapshot.RunningContainerCount
+			snapshot.StoppedContainerCount += nodeSnapshot.StoppedContainerCount
+			snapshot.HealthyContainerCount += nodeSnapshot.HealthyContainerCount
+			snapshot.UnhealthyContainerCount += nodeSnapshot.UnhealthyContainerCount
+			snapshot.ImageCount += nodeSnapshot.ImageCount
+			snapshot.VolumeCount += nodeSnapshot.VolumeCount
+
+			snapshot.SnapshotRaw.Containers = append(snapshot.SnapshotRaw.Containers, nodeSnapshot.SnapshotRaw.Containers...)
+			snapshot.SnapshotRaw.Images = append(snapshot.SnapshotRaw.Images, nodeSnapshot.SnapshotRaw.Images...)
+			snapshot.SnapshotRaw.Volumes.Volumes = append(snapshot.SnapshotRaw.Volumes.Volumes, nodeSnapshot.SnapshotRaw.Volumes.Volumes...)
+			snapshot.SnapshotRaw.Volumes.Warnings = append(snapshot.SnapshotRaw.Volumes.Warnings, nodeSnapshot.SnapshotRaw.Volumes.Warnings...)
+
+			// The same compose project can run on several nodes, it is counted once
+			for _, container := range nodeSnapshot.SnapshotRaw.Containers {
+				if name, ok := container.Labels[consts.ComposeStackNameLabel]; ok {
+					stacks[name] = struct{}{}
+				}
+			}
+
+			snapshot.GpuUseAll = snapshot.GpuUseAll || nodeSnapshot.GpuUseAll
+			for _, gpuUse := range nodeSnapshot.GpuUseList {
+				gpuUseSet[gpuUse] = struct{}{}
+			}
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	gpuUseList := make([]string, 0, len(gpuUseSet))
+	for gpuUse := range gpuUseSet {
+		gpuUseList = append(gpuUseList, gpuUse)
+	}
+
+	snapshot.GpuUseList = gpuUseList
+	snapshot.StackCount += len(stacks)
+	snapshot.SnapshotRaw.Nodes = nodes
+
+	return nil
+}
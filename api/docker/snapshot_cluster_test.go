@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker/consts"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateNodeSnapshots(t *testing.T) {
+	composeContainer := portainer.DockerContainerSnapshot{Container: types.Container{Labels: map[string]string{consts.ComposeStackNameLabel: "web"}}}
+
+	results := []nodeSnapshotResult{
+		{
+			nodeName: "node-1",
+			snapshot: &portainer.DockerSnapshot{
+				ContainerCount:        2,
+				RunningContainerCount: 2,
+				ImageCount:            3,
+				VolumeCount:           1,
+				GpuUseList:            []string{"gpu-0"},
+				SnapshotRaw:           portainer.DockerSnapshotRaw{Containers: []portainer.DockerContainerSnapshot{composeContainer, {}}},
+			},
+		},
+		{
+			nodeName: "node-2",
+			snapshot: &portainer.DockerSnapshot{
+				ContainerCount:        1,
+				StoppedContainerCount: 1,
+				SnapshotRaw:           portainer.DockerSnapshotRaw{Containers: []portainer.DockerContainerSnapshot{composeContainer}},
+			},
+			err: errors.New("unable to snapshot images: timeout"),
+		},
+		{
+			nodeName: "node-3",
+			err:      errors.New("unreachable"),
+		},
+	}
+
+	snapshot := &portainer.DockerSnapshot{StackCount: 1}
+	require.NoError(t, aggregateNodeSnapshots(snapshot, results))
+
+	assert.Equal(t, 3, snapshot.ContainerCount)
+	assert.Equal(t, 2, snapshot.RunningContainerCount)
+	assert.Equal(t, 1, snapshot.StoppedContainerCount)
+	assert.Equal(t, 3, snapshot.ImageCount)
+	assert.Equal(t, 1, snapshot.VolumeCount)
+	assert.Len(t, snapshot.SnapshotRaw.Containers, 3)
+	assert.Equal(t, []string{"gpu-0"}, snapshot.GpuUseList)
+
+	// The compose project running on both nodes is counted once, on top of the Swarm stacks
+	assert.Equal(t, 2, snapshot.StackCount)
+
+	require.Len(t, snapshot.SnapshotRaw.Nodes, 3)
+	assert.Equal(t, portainer.DockerNodeSnapshot{NodeName: "node-1", ContainerCount: 2, RunningContainerCount: 2, ImageCount: 3, VolumeCount: 1}, snapshot.SnapshotRaw.Nodes[0])
+	assert.Equal(t, "unable to snapshot images: timeout", snapshot.SnapshotRaw.Nodes[1].Error)
+	assert.Equal(t, portainer.DockerNodeSnapshot{NodeName: "node-3", Error: "unreachable"}, snapshot.SnapshotRaw.Nodes[2])
+}
+
+func TestAggregateNodeSnapshotsAllNodesFailed(t *testing.T) {
+	snapshot := &portainer.DockerSnapshot{}
+
+	err := aggregateNodeSnapshots(snapshot, []nodeSnapshotResult{{nodeName: "node-1", err: errors.New("unreachable")}})
+	require.Error(t, err)
+	assert.Empty(t, snapshot.SnapshotRaw.Nodes)
+}
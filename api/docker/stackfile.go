@@ -0,0 +1,350 @@
+package docker
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	composeServiceLabel       = "com.docker.compose.service"
+	composeLabelPrefix        = "com.docker.compose."
+	swarmStackLabelPrefix     = "com.docker.stack."
+	swarmStackFileVersion     = "3.8"
+	composeDefaultNetworkName = "default"
+)
+
+type stackFile struct {
+	Version  string                   `yaml:"version,omitempty"`
+	Services map[string]stackService  `yaml:"services"`
+	Networks map[string]stackResource `yaml:"networks,omitempty"`
+	Volumes  map[string]stackResource `yaml:"volumes,omitempty"`
+}
+
+type stackService struct {
+	Image       string            `yaml:"image"`
+	Entrypoint  []string          `yaml:"entrypoint,omitempty"`
+	Command     []string          `yaml:"command,omitempty"`
+	WorkingDir  string            `yaml:"working_dir,omitempty"`
+	User        string            `yaml:"user,omitempty"`
+	Environment []string          `yaml:"environment,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Ports       []stackPort       `yaml:"ports,omitempty"`
+	Volumes     []string          `yaml:"volumes,omitempty"`
+	NetworkMode string            `yaml:"network_mode,omitempty"`
+	Networks    []string          `yaml:"networks,omitempty"`
+	Privileged  bool              `yaml:"privileged,omitempty"`
+	Restart     string            `yaml:"restart,omitempty"`
+	Deploy      *stackDeploy      `yaml:"deploy,omitempty"`
+}
+
+type stackPort struct {
+	Target    int    `yaml:"target"`
+	Published string `yaml:"published,omitempty"`
+	HostIP    string `yaml:"host_ip,omitempty"`
+	Protocol  string `yaml:"protocol,omitempty"`
+	Mode      string `yaml:"mode,omitempty"`
+}
+
+type stackDeploy struct {
+	Mode          string              `yaml:"mode,omitempty"`
+	Replicas      *uint64             `yaml:"replicas,omitempty"`
+	Labels        map[string]string   `yaml:"labels,omitempty"`
+	Placement     *stackPlacement     `yaml:"placement,omitempty"`
+	RestartPolicy *stackRestartPolicy `yaml:"restart_policy,omitempty"`
+}
+
+type stackPlacement struct {
+	Constraints []string `yaml:"constraints,omitempty"`
+}
+
+type stackRestartPolicy struct {
+	Condition string `yaml:"condition,omitempty"`
+}
+
+type stackResource struct {
+	Name     string `yaml:"name,omitempty"`
+	External bool   `yaml:"external,omitempty"`
+}
+
+// ReassembleComposeFile rebuilds the compose file of a project deployed outside of Portainer from the specs of its
+// containers. The image configurations, by image identifier, are used to leave out the settings inherited from the
+// images. The networks and the volumes which are not scoped to the project are declared as external so that they are
+// reused as they are
+func ReassembleComposeFile(project string, containers []types.ContainerJSON, imageConfigs map[string]*container.Config) ([]byte, error) {
+	if len(containers) == 0 {
+		return nil, errors.Errorf("no container found for the project %s", project)
+	}
+
+	file := stackFile{
+		Services: map[string]stackService{},
+		Networks: map[string]stackResource{},
+		Volumes:  map[string]stackResource{},
+	}
+
+	byService := map[string][]types.ContainerJSON{}
+	for _, c := range containers {
+		if c.ContainerJSONBase == nil || c.Config == nil || c.HostConfig == nil {
+			continue
+		}
+
+		name := cmp.Or(c.Config.Labels[composeServiceLabel], strings.TrimPrefix(c.Name, "/"))
+		byService[name] = append(byService[name], c)
+	}
+
+	for name, replicas := range byService {
+		slices.SortFunc(replicas, func(a, b types.ContainerJSON) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		c := replicas[0]
+		imageConfig := imageConfigs[c.Image]
+		if imageConfig == nil {
+			imageConfig = &container.Config{}
+		}
+
+		service := stackService{
+			Image:       c.Config.Image,
+			Environment: withoutItems(c.Config.Env, imageConfig.Env),
+			Labels:      withoutLabels(c.Config.Labels, imageConfig.Labels, composeLabelPrefix),
+			Privileged:  c.HostConfig.Privileged,
+		}
+
+		if !slices.Equal(c.Config.Entrypoint, imageConfig.Entrypoint) {
+			service.Entrypoint = c.Config.Entrypoint
+		}
+
+		if !slices.Equal(c.Config.Cmd, imageConfig.Cmd) {
+			service.Command = c.Config.Cmd
+		}
+
+		if c.Config.WorkingDir != imageConfig.WorkingDir {
+			service.WorkingDir = c.Config.WorkingDir
+		}
+
+		if c.Config.User != imageConfig.User {
+			service.User = c.Config.User
+		}
+
+		if policy := c.HostConfig.RestartPolicy.Name; policy != "" && policy != container.RestartPolicyDisabled {
+			service.Restart = string(policy)
+		}
+
+		for _, port := range sortedKeys(c.HostConfig.PortBindings) {
+			target, _ := strconv.Atoi(port.Port())
+			for _, binding := range c.HostConfig.PortBindings[port] {
+				service.Ports = append(service.Ports, stackPort{
+					Target:    target,
+					Published: binding.HostPort,
+					HostIP:    binding.HostIP,
+					Protocol:  port.Proto(),
+				})
+			}
+		}
+
+		for _, m := range c.Mounts {
+			switch m.Type {
+			case mount.TypeBind:
+				service.Volumes = append(service.Volumes, volumeSpec(m.Source, m.Destination, !m.RW))
+			case mount.TypeVolume:
+				volume := declareResource(file.Volumes, project, m.Name)
+				service.Volumes = append(service.Volumes, volumeSpec(volume, m.Destination, !m.RW))
+			}
+		}
+
+		switch networkMode := c.HostConfig.NetworkMode; {
+		case networkMode.IsHost(), networkMode.IsNone(), networkMode.IsContainer():
+			service.NetworkMode = string(networkMode)
+		case c.NetworkSettings != nil:
+			for _, network := range sortedKeys(c.NetworkSettings.Networks) {
+				if network == project+"_"+composeDefaultNetworkName {
+					continue
+				}
+
+				service.Networks = append(service.Networks, declareResource(file.Networks, project, network))
+			}
+		}
+
+		if len(replicas) > 1 {
+			count := uint64(len(replicas))
+			service.Deploy = &stackDeploy{Replicas: &count}
+		}
+
+		file.Services[name] = service
+	}
+
+	return yaml.Marshal(file)
+}
+
+// ReassembleSwarmStackFile rebuilds the stack file of a Swarm stack deployed outside of Portainer from the specs of
+// its services. The names of the networks, by network identifier, are used to resolve the networks of the services
+func ReassembleSwarmStackFile(namespace string, services []swarm.Service, networkNames map[string]string) ([]byte, error) {
+	if len(services) == 0 {
+		return nil, errors.Errorf("no service found for the stack %s", namespace)
+	}
+
+	file := stackFile{
+		Version:  swarmStackFileVersion,
+		Services: map[string]stackService{},
+		Networks: map[string]stackResource{},
+		Volumes:  map[string]stackResource{},
+	}
+
+	for _, s := range services {
+		spec := s.Spec.TaskTemplate.ContainerSpec
+		if spec == nil {
+			continue
+		}
+
+		// The images are pinned to their digest when the services are created
+		image, _, _ := strings.Cut(spec.Image, "@")
+
+		service := stackService{
+			Image:       image,
+			Entrypoint:  spec.Command,
+			Command:     spec.Args,
+			WorkingDir:  spec.Dir,
+			User:        spec.User,
+			Environment: spec.Env,
+			Labels:      withoutLabels(spec.Labels, nil, swarmStackLabelPrefix),
+			Deploy: &stackDeploy{
+				Labels: withoutLabels(s.Spec.Labels, nil, swarmStackLabelPrefix),
+			},
+		}
+
+		switch {
+		case s.Spec.Mode.Global != nil:
+			service.Deploy.Mode = "global"
+		case s.Spec.Mode.Replicated != nil:
+			service.Deploy.Replicas = s.Spec.Mode.Replicated.Replicas
+		}
+
+		if placement := s.Spec.TaskTemplate.Placement; placement != nil && len(placement.Constraints) > 0 {
+			service.Deploy.Placement = &stackPlacement{Constraints: placement.Constraints}
+		}
+
+		if policy := s.Spec.TaskTemplate.RestartPolicy; policy != nil && policy.Condition != "" {
+			service.Deploy.RestartPolicy = &stackRestartPolicy{Condition: string(policy.Condition)}
+		}
+
+		if s.Spec.EndpointSpec != nil {
+			for _, port := range s.Spec.EndpointSpec.Ports {
+				published := ""
+				if port.PublishedPort != 0 {
+					published = strconv.FormatUint(uint64(port.PublishedPort), 10)
+				}
+
+				service.Ports = append(service.Ports, stackPort{
+					Target:    int(port.TargetPort),
+					Published: published,
+					Protocol:  string(port.Protocol),
+					Mode:      string(port.PublishMode),
+				})
+			}
+		}
+
+		for _, m := range spec.Mounts {
+			switch m.Type {
+			case mount.TypeBind:
+				service.Volumes = append(service.Volumes, volumeSpec(m.Source, m.Target, m.ReadOnly))
+			case mount.TypeVolume:
+				volume := declareResource(file.Volumes, namespace, m.Source)
+				service.Volumes = append(service.Volumes, volumeSpec(volume, m.Target, m.ReadOnly))
+			}
+		}
+
+		for _, attachment := range s.Spec.TaskTemplate.Networks {
+			network, ok := networkNames[attachment.Target]
+			if !ok {
+				network = attachment.Target
+			}
+
+			if network == namespace+"_"+composeDefaultNetworkName {
+				continue
+			}
+
+			service.Networks = append(service.Networks, declareResource(file.Networks, namespace, network))
+		}
+
+		name := strings.TrimPrefix(s.Spec.Name, namespace+"_")
+		file.Services[name] = service
+	}
+
+	return yaml.Marshal(file)
+}
+
+// declareResource declares a top level network or volume of a stack file and returns its key. The resources created
+// for the project are referenced by their short name, the other ones are external
+func declareResource(resources map[string]stackResource, project, name string) string {
+	if key, ok := strings.CutPrefix(name, project+"_"); ok && key != "" {
+		resources[key] = stackResource{}
+
+		return key
+	}
+
+	resources[name] = stackResource{Name: name, External: true}
+
+	return name
+}
+
+func volumeSpec(source, target string, readOnly bool) string {
+	spec := source + ":" + target
+	if readOnly {
+		spec += ":ro"
+	}
+
+	return spec
+}
+
+// withoutItems returns the items that are not part of the inherited items
+func withoutItems(items, inherited []string) []string {
+	var result []string
+	for _, item := range items {
+		if !slices.Contains(inherited, item) {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// withoutLabels returns the labels that are neither inherited nor set by the orchestrator
+func withoutLabels(labels, inherited map[string]string, orchestratorPrefix string) map[string]string {
+	result := map[string]string{}
+	for key, value := range labels {
+		if strings.HasPrefix(key, orchestratorPrefix) {
+			continue
+		}
+
+		if inheritedValue, ok := inherited[key]; ok && inheritedValue == value {
+			continue
+		}
+
+		result[key] = value
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
@@ -0,0 +1,156 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestReassembleComposeFile(t *testing.T) {
+	webContainer := func(name string) types.ContainerJSON {
+		return types.ContainerJSON{
+			ContainerJSONBase: &types.ContainerJSONBase{
+				Name:  "/" + name,
+				Image: "sha256:web",
+				HostConfig: &container.HostConfig{
+					RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyUnlessStopped},
+				},
+			},
+			Config: &container.Config{
+				Image:  "nginx:alpine",
+				Cmd:    []string{"nginx", "-g", "daemon off;"},
+				Env:    []string{"PATH=/usr/bin", "MODE=prod"},
+				Labels: map[string]string{composeServiceLabel: "web", "com.docker.compose.project": "shop", "team": "front"},
+			},
+			NetworkSettings: &types.NetworkSettings{
+				Networks: map[string]*network.EndpointSettings{"shop_default": {}, "proxy": {}},
+			},
+		}
+	}
+
+	db := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			Name:  "/shop-db-1",
+			Image: "sha256:db",
+			HostConfig: &container.HostConfig{
+				PortBindings: nat.PortMap{"5432/tcp": {{HostIP: "127.0.0.1", HostPort: "5432"}}},
+			},
+		},
+		Config: &container.Config{
+			Image:  "postgres:16",
+			Labels: map[string]string{composeServiceLabel: "db"},
+		},
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeVolume, Name: "shop_data", Destination: "/var/lib/postgresql/data", RW: true},
+			{Type: mount.TypeBind, Source: "/etc/shop/init.sql", Destination: "/docker-entrypoint-initdb.d/init.sql"},
+		},
+		NetworkSettings: &types.NetworkSettings{
+			Networks: map[string]*network.EndpointSettings{"shop_default": {}},
+		},
+	}
+
+	imageConfigs := map[string]*container.Config{
+		"sha256:web": {Cmd: []string{"nginx", "-g", "daemon off;"}, Env: []string{"PATH=/usr/bin"}},
+	}
+
+	content, err := ReassembleComposeFile("shop", []types.ContainerJSON{webContainer("shop-web-2"), db, webContainer("shop-web-1")}, imageConfigs)
+	require.NoError(t, err)
+
+	var file stackFile
+	require.NoError(t, yaml.Unmarshal(content, &file))
+
+	assert.Empty(t, file.Version)
+	require.Len(t, file.Services, 2)
+
+	web := file.Services["web"]
+	assert.Equal(t, "nginx:alpine", web.Image)
+	assert.Empty(t, web.Command)
+	assert.Equal(t, []string{"MODE=prod"}, web.Environment)
+	assert.Equal(t, map[string]string{"team": "front"}, web.Labels)
+	assert.Equal(t, []string{"proxy"}, web.Networks)
+	assert.Equal(t, "unless-stopped", web.Restart)
+	require.NotNil(t, web.Deploy)
+	assert.Equal(t, uint64(2), *web.Deploy.Replicas)
+
+	dbService := file.Services["db"]
+	assert.Equal(t, []stackPort{{Target: 5432, Published: "5432", HostIP: "127.0.0.1", Protocol: "tcp"}}, dbService.Ports)
+	assert.Equal(t, []string{"data:/var/lib/postgresql/data", "/etc/shop/init.sql:/docker-entrypoint-initdb.d/init.sql:ro"}, dbService.Volumes)
+	assert.Empty(t, dbService.Networks)
+	assert.Nil(t, dbService.Deploy)
+
+	assert.Equal(t, map[string]stackResource{"data": {}}, file.Volumes)
+	assert.Equal(t, map[string]stackResource{"proxy": {Name: "proxy", External: true}}, file.Networks)
+
+	_, err = ReassembleComposeFile("shop", nil, nil)
+	require.Error(t, err)
+}
+
+func TestReassembleSwarmStackFile(t *testing.T) {
+	replicas := uint64(3)
+
+	services := []swarm.Service{
+		{
+			Spec: swarm.ServiceSpec{
+				Annotations: swarm.Annotations{
+					Name:   "shop_web",
+					Labels: map[string]string{"com.docker.stack.namespace": "shop", "traefik.enable": "true"},
+				},
+				TaskTemplate: swarm.TaskSpec{
+					ContainerSpec: &swarm.ContainerSpec{
+						Image:  "nginx:alpine@sha256:abcdef",
+						Args:   []string{"nginx-debug"},
+						Labels: map[string]string{"com.docker.stack.namespace": "shop"},
+						Mounts: []mount.Mount{{Type: mount.TypeVolume, Source: "shop_static", Target: "/usr/share/nginx/html", ReadOnly: true}},
+					},
+					Placement: &swarm.Placement{Constraints: []string{"node.role == worker"}},
+					Networks:  []swarm.NetworkAttachmentConfig{{Target: "net1"}, {Target: "net2"}},
+				},
+				Mode: swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+				EndpointSpec: &swarm.EndpointSpec{
+					Ports: []swarm.PortConfig{{Protocol: swarm.PortConfigProtocolTCP, TargetPort: 80, PublishedPort: 8080, PublishMode: swarm.PortConfigPublishModeIngress}},
+				},
+			},
+		},
+		{
+			Spec: swarm.ServiceSpec{
+				Annotations:  swarm.Annotations{Name: "shop_agent"},
+				TaskTemplate: swarm.TaskSpec{ContainerSpec: &swarm.ContainerSpec{Image: "agent:latest"}},
+				Mode:         swarm.ServiceMode{Global: &swarm.GlobalService{}},
+			},
+		},
+	}
+
+	content, err := ReassembleSwarmStackFile("shop", services, map[string]string{"net1": "shop_default", "net2": "traefik-public"})
+	require.NoError(t, err)
+
+	var file stackFile
+	require.NoError(t, yaml.Unmarshal(content, &file))
+
+	assert.Equal(t, swarmStackFileVersion, file.Version)
+	require.Len(t, file.Services, 2)
+
+	web := file.Services["web"]
+	assert.Equal(t, "nginx:alpine", web.Image)
+	assert.Equal(t, []string{"nginx-debug"}, web.Command)
+	assert.Empty(t, web.Labels)
+	assert.Equal(t, []string{"static:/usr/share/nginx/html:ro"}, web.Volumes)
+	assert.Equal(t, []string{"traefik-public"}, web.Networks)
+	assert.Equal(t, []stackPort{{Target: 80, Published: "8080", Protocol: "tcp", Mode: "ingress"}}, web.Ports)
+	require.NotNil(t, web.Deploy)
+	assert.Equal(t, uint64(3), *web.Deploy.Replicas)
+	assert.Equal(t, map[string]string{"traefik.enable": "true"}, web.Deploy.Labels)
+	assert.Equal(t, []string{"node.role == worker"}, web.Deploy.Placement.Constraints)
+
+	assert.Equal(t, "global", file.Services["agent"].Deploy.Mode)
+
+	assert.Equal(t, map[string]stackResource{"static": {}}, file.Volumes)
+	assert.Equal(t, map[string]stackResource{"traefik-public": {Name: "traefik-public", External: true}}, file.Networks)
+}
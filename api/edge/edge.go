@@ -28,8 +28,7 @@ type (
 		// RollbackTo specifies the stack file version to rollback to (only support to rollback to the last version currently)
 		RollbackTo *int
 
-		// RegistryCredentials holds the credentials for a Docker registry.
-		// Used only for EE
+		// RegistryCredentials holds the credentials of the registries of the stack
 		RegistryCredentials []RegistryCredentials
 		// PrePullImage is a flag indicating if the agent should pull the image before deploying the stack.
 		// Used only for EE
@@ -61,6 +60,7 @@ type (
 	RegistryCredentials struct {
 		ServerURL string
 		Username  string
-		Secret    string
+		// Password of the registry encrypted with the edge key of the environment(endpoint), base64 encoded
+		Secret string
 	}
 )
@@ -0,0 +1,28 @@
+package portainer
+
+// Released returns true when the environment is allowed to deploy the version being rolled out.
+// Environments that are not part of any batch, e.g. added after the rollout started, are always released.
+func (rollout *EdgeStackRollout) Released(endpointID EndpointID) bool {
+	if rollout == nil || rollout.Completed {
+		return true
+	}
+
+	for i, batch := range rollout.Batches {
+		for _, id := range batch {
+			if id == endpointID {
+				return i <= rollout.CurrentBatch
+			}
+		}
+	}
+
+	return true
+}
+
+// EndpointVersion returns the version of the edge stack that the environment should deploy
+func (rollout *EdgeStackRollout) EndpointVersion(version int, endpointID EndpointID) int {
+	if rollout.Released(endpointID) {
+		return version
+	}
+
+	return rollout.PreviousVersion
+}
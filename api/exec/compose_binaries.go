@@ -0,0 +1,233 @@
+package exec
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	httpclient "github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/pkg/libstack"
+	"github.com/portainer/portainer/pkg/libstack/compose"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	composeReleasesURL     = "https://github.com/docker/compose/releases/download"
+	composeBinariesFolder  = "compose"
+	composeDownloadTimeout = 10 * time.Minute
+)
+
+// composeVersionRegex matches the docker compose releases, v1 (docker-compose) and v2 (docker compose plugin)
+var composeVersionRegex = regexp.MustCompile(`^[12]\.\d+\.\d+$`)
+
+var composeArchitectures = map[string]string{
+	"amd64":   "x86_64",
+	"arm64":   "aarch64",
+	"arm":     "armv7",
+	"ppc64le": "ppc64le",
+	"s390x":   "s390x",
+	"riscv64": "riscv64",
+}
+
+// ValidateComposeVersion checks that a version pinned for an environment(endpoint) is a docker compose release,
+// such as 1.29.2 or 2.24.6
+func ValidateComposeVersion(version string) error {
+	if !composeVersionRegex.MatchString(version) {
+		return fmt.Errorf("invalid docker compose version %q, expected a release such as 1.29.2 or 2.24.6", version)
+	}
+
+	return nil
+}
+
+// isLegacyComposeVersion returns true for the docker compose v1 releases, which only support the legacy compose syntax
+func isLegacyComposeVersion(version string) bool {
+	return strings.HasPrefix(version, "1.")
+}
+
+// ComposeBinaries downloads the docker compose binaries pinned by the environments(endpoints) into the binary
+// folder, they are kept across restarts and downloaded only once
+type ComposeBinaries struct {
+	binaryFolder string
+	configPath   string
+	releasesURL  string
+	client       *http.Client
+
+	mu        sync.Mutex
+	deployers map[string]libstack.Deployer
+	group     singleflight.Group
+}
+
+// NewComposeBinaries creates a store of docker compose binaries in binaryFolder, the binaries use the docker
+// config of configPath
+func NewComposeBinaries(binaryFolder, configPath string) *ComposeBinaries {
+	return &ComposeBinaries{
+		binaryFolder: binaryFolder,
+		configPath:   configPath,
+		releasesURL:  composeReleasesURL,
+		client: &http.Client{
+			Timeout:   composeDownloadTimeout,
+			Transport: httpclient.NewTransport(),
+		},
+		deployers: make(map[string]libstack.Deployer),
+	}
+}
+
+// Deployer returns a deployer running the given version of docker compose, its binary is downloaded
+// and verified against the checksum of the release the first time
+func (binaries *ComposeBinaries) Deployer(ctx context.Context, version string) (libstack.Deployer, error) {
+	if err := ValidateComposeVersion(version); err != nil {
+		return nil, err
+	}
+
+	binaries.mu.Lock()
+	deployer, ok := binaries.deployers[version]
+	binaries.mu.Unlock()
+
+	if ok {
+		return deployer, nil
+	}
+
+	result, err, _ := binaries.group.Do(version, func() (any, error) {
+		binaryPath := filepath.Join(binaries.binaryFolder, composeBinariesFolder, version)
+
+		if _, err := os.Stat(filepath.Join(binaryPath, composeProgram())); errors.Is(err, os.ErrNotExist) {
+			if err := binaries.download(ctx, version, binaryPath); err != nil {
+				return nil, err
+			}
+		} else if err != nil {
+			return nil, err
+		}
+
+		deployer, err := compose.NewComposeDeployer(binaryPath, binaries.configPath)
+		if err != nil {
+			return nil, err
+		}
+
+		binaries.mu.Lock()
+		binaries.deployers[version] = deployer
+		binaries.mu.Unlock()
+
+		return deployer, nil
+	})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "unable to get the docker compose binary %s", version)
+	}
+
+	return result.(libstack.Deployer), nil
+}
+
+// download saves the binary of a release in binaryPath once its checksum matches the published one
+func (binaries *ComposeBinaries) download(ctx context.Context, version, binaryPath string) error {
+	asset, err := composeReleaseAsset(version, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+
+	assetURL := binaries.releasesURL + "/" + asset
+
+	log.Info().Str("version", version).Str("url", assetURL).Msg("downloading the docker compose binary")
+
+	checksum, err := binaries.get(ctx, assetURL+".sha256")
+	if err != nil {
+		return errors.WithMessage(err, "unable to download the checksum of the binary")
+	}
+
+	expectedChecksum, _, _ := strings.Cut(strings.TrimSpace(string(checksum)), " ")
+
+	binary, err := binaries.get(ctx, assetURL)
+	if err != nil {
+		return errors.WithMessage(err, "unable to download the binary")
+	}
+
+	sum := sha256.Sum256(binary)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), expectedChecksum) {
+		return errors.New("the checksum of the binary does not match the checksum of the release")
+	}
+
+	if err := os.MkdirAll(binaryPath, 0o755); err != nil {
+		return err
+	}
+
+	// The binary is renamed once written so that an interrupted download is not mistaken for a complete one
+	tmpFile, err := os.CreateTemp(binaryPath, "download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(binary); err != nil {
+		tmpFile.Close()
+
+		return err
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmpFile.Name(), 0o755); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), filepath.Join(binaryPath, composeProgram()))
+}
+
+func (binaries *ComposeBinaries) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := binaries.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q for %s", resp.Status, url)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// composeReleaseAsset returns the path of the binary of a release for a platform, relative to the releases URL.
+// The v1 releases are tagged without the "v" prefix and were only published for x86_64
+func composeReleaseAsset(version, goos, goarch string) (string, error) {
+	arch, ok := composeArchitectures[goarch]
+	if !ok || (isLegacyComposeVersion(version) && goarch != "amd64") {
+		return "", fmt.Errorf("docker compose %s is not available for %s/%s", version, goos, goarch)
+	}
+
+	extension := ""
+	if goos == "windows" {
+		extension = ".exe"
+	}
+
+	if isLegacyComposeVersion(version) {
+		return fmt.Sprintf("%s/docker-compose-%s-%s%s", version, strings.ToUpper(goos[:1])+goos[1:], arch, extension), nil
+	}
+
+	return fmt.Sprintf("v%s/docker-compose-%s-%s%s", version, goos, arch, extension), nil
+}
+
+func composeProgram() string {
+	if runtime.GOOS == "windows" {
+		return "docker-compose.exe"
+	}
+
+	return "docker-compose"
+}
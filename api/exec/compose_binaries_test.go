@@ -0,0 +1,103 @@
+package exec
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_composeReleaseAsset(t *testing.T) {
+	tests := []struct {
+		version, goos, goarch string
+		expected              string
+		wantErr               bool
+	}{
+		{version: "2.24.6", goos: "linux", goarch: "amd64", expected: "v2.24.6/docker-compose-linux-x86_64"},
+		{version: "2.24.6", goos: "linux", goarch: "arm64", expected: "v2.24.6/docker-compose-linux-aarch64"},
+		{version: "2.24.6", goos: "windows", goarch: "amd64", expected: "v2.24.6/docker-compose-windows-x86_64.exe"},
+		{version: "1.29.2", goos: "linux", goarch: "amd64", expected: "1.29.2/docker-compose-Linux-x86_64"},
+		{version: "1.29.2", goos: "linux", goarch: "arm64", wantErr: true},
+		{version: "2.24.6", goos: "linux", goarch: "mips", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		asset, err := composeReleaseAsset(tt.version, tt.goos, tt.goarch)
+		if tt.wantErr {
+			assert.Error(t, err, tt.version+" "+tt.goos+"/"+tt.goarch)
+
+			continue
+		}
+
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, asset)
+	}
+}
+
+func Test_ValidateComposeVersion(t *testing.T) {
+	for _, version := range []string{"1.29.2", "2.24.6"} {
+		assert.NoError(t, ValidateComposeVersion(version))
+	}
+
+	for _, version := range []string{"", "v2.24.6", "3.0.0", "2.24", "../2.24.6", "latest"} {
+		assert.Error(t, ValidateComposeVersion(version), version)
+	}
+}
+
+func Test_ComposeBinaries_Deployer(t *testing.T) {
+	binary := []byte("#!/bin/sh\n")
+	sum := sha256.Sum256(binary)
+
+	var downloads atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ".sha256") {
+			checksum := hex.EncodeToString(sum[:])
+			if strings.HasPrefix(r.URL.Path, "/v2.23.0/") {
+				checksum = strings.Repeat("0", 64)
+			}
+
+			w.Write([]byte(checksum + " *docker-compose\n"))
+
+			return
+		}
+
+		downloads.Add(1)
+		w.Write(binary)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+
+	binaries := NewComposeBinaries(dir, "")
+	binaries.releasesURL = srv.URL
+
+	deployer, err := binaries.Deployer(context.Background(), "2.24.6")
+	require.NoError(t, err)
+	assert.NotNil(t, deployer)
+	assert.FileExists(t, filepath.Join(dir, composeBinariesFolder, "2.24.6", composeProgram()))
+
+	_, err = binaries.Deployer(context.Background(), "2.24.6")
+	require.NoError(t, err)
+
+	// The binary downloaded before a restart is reused
+	_, err = NewComposeBinaries(dir, "").Deployer(context.Background(), "2.24.6")
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, downloads.Load())
+
+	// A binary that does not match the checksum of the release is discarded
+	_, err = binaries.Deployer(context.Background(), "2.23.0")
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, composeBinariesFolder, "2.23.0", composeProgram()))
+
+	_, err = binaries.Deployer(context.Background(), "../2.24.6")
+	require.Error(t, err)
+}
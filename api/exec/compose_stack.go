@@ -11,6 +11,7 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/http/proxy"
 	"github.com/portainer/portainer/api/http/proxy/factory"
+	"github.com/portainer/portainer/api/internal/endpointutils"
 	"github.com/portainer/portainer/api/stacks/stackutils"
 	"github.com/portainer/portainer/pkg/libstack"
 
@@ -20,25 +21,49 @@ import (
 // ComposeStackManager is a wrapper for docker-compose binary
 type ComposeStackManager struct {
 	deployer     libstack.Deployer
+	binaries     *ComposeBinaries
 	proxyManager *proxy.Manager
 }
 
-// NewComposeStackManager returns a docker-compose wrapper if corresponding binary present, otherwise nil
-func NewComposeStackManager(deployer libstack.Deployer, proxyManager *proxy.Manager) (*ComposeStackManager, error) {
+// NewComposeStackManager returns a docker-compose wrapper if corresponding binary present, otherwise nil.
+// The environments pinning a docker compose version are deployed with the binaries of binaries
+func NewComposeStackManager(deployer libstack.Deployer, binaries *ComposeBinaries, proxyManager *proxy.Manager) (*ComposeStackManager, error) {
 
 	return &ComposeStackManager{
 		deployer:     deployer,
+		binaries:     binaries,
 		proxyManager: proxyManager,
 	}, nil
 }
 
-// ComposeSyntaxMaxVersion returns the maximum supported version of the docker compose syntax
-func (manager *ComposeStackManager) ComposeSyntaxMaxVersion() string {
+// ComposeSyntaxMaxVersion returns the maximum supported version of the docker compose syntax for an environment.
+// The Compose stacks of the Docker environments are deployed with docker compose v2 which supports the Compose Specification,
+// unless the environment pins a docker compose v1 release
+func (manager *ComposeStackManager) ComposeSyntaxMaxVersion(endpoint *portainer.Endpoint) string {
+	if endpointutils.IsDockerEndpoint(endpoint) && !isLegacyComposeVersion(endpoint.ComposeVersion) {
+		return portainer.ComposeSpecSyntaxMaxVersion
+	}
+
 	return portainer.ComposeSyntaxMaxVersion
 }
 
+// endpointDeployer returns the deployer running the docker compose version pinned by the environment,
+// the bundled binary is used when no version is pinned
+func (manager *ComposeStackManager) endpointDeployer(ctx context.Context, endpoint *portainer.Endpoint) (libstack.Deployer, error) {
+	if endpoint.ComposeVersion == "" || manager.binaries == nil {
+		return manager.deployer, nil
+	}
+
+	return manager.binaries.Deployer(ctx, endpoint.ComposeVersion)
+}
+
 // Up builds, (re)creates and starts containers in the background. Wraps `docker-compose up -d` command
 func (manager *ComposeStackManager) Up(ctx context.Context, stack *portainer.Stack, endpoint *portainer.Endpoint, options portainer.ComposeUpOptions) error {
+	deployer, err := manager.endpointDeployer(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
 	url, proxy, err := manager.fetchEndpointProxy(endpoint)
 	if err != nil {
 		return errors.Wrap(err, "failed to fetch environment proxy")
@@ -54,12 +79,13 @@ func (manager *ComposeStackManager) Up(ctx context.Context, stack *portainer.Sta
 	}
 
 	filePaths := stackutils.GetStackFilePaths(stack, true)
-	err = manager.deployer.Deploy(ctx, filePaths, libstack.DeployOptions{
+	err = deployer.Deploy(ctx, filePaths, libstack.DeployOptions{
 		Options: libstack.Options{
 			WorkingDir:  stack.ProjectPath,
 			EnvFilePath: envFilePath,
 			Host:        url,
 			ProjectName: stack.Name,
+			Profiles:    stack.Profiles,
 		},
 		ForceRecreate:        options.ForceRecreate,
 		AbortOnContainerExit: options.AbortOnContainerExit,
@@ -69,6 +95,11 @@ func (manager *ComposeStackManager) Up(ctx context.Context, stack *portainer.Sta
 
 // Run runs a one-off command on a service. Wraps `docker-compose run` command
 func (manager *ComposeStackManager) Run(ctx context.Context, stack *portainer.Stack, endpoint *portainer.Endpoint, serviceName string, options portainer.ComposeRunOptions) error {
+	deployer, err := manager.endpointDeployer(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
 	url, proxy, err := manager.fetchEndpointProxy(endpoint)
 	if err != nil {
 		return errors.Wrap(err, "failed to fetch environment proxy")
@@ -84,12 +115,13 @@ func (manager *ComposeStackManager) Run(ctx context.Context, stack *portainer.St
 	}
 
 	filePaths := stackutils.GetStackFilePaths(stack, true)
-	err = manager.deployer.Run(ctx, filePaths, serviceName, libstack.RunOptions{
+	err = deployer.Run(ctx, filePaths, serviceName, libstack.RunOptions{
 		Options: libstack.Options{
 			WorkingDir:  stack.ProjectPath,
 			EnvFilePath: envFilePath,
 			Host:        url,
 			ProjectName: stack.Name,
+			Profiles:    stack.Profiles,
 		},
 		Remove:   options.Remove,
 		Args:     options.Args,
@@ -100,6 +132,11 @@ func (manager *ComposeStackManager) Run(ctx context.Context, stack *portainer.St
 
 // Down stops and removes containers, networks, images, and volumes
 func (manager *ComposeStackManager) Down(ctx context.Context, stack *portainer.Stack, endpoint *portainer.Endpoint) error {
+	deployer, err := manager.endpointDeployer(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
 	url, proxy, err := manager.fetchEndpointProxy(endpoint)
 	if err != nil {
 		return err
@@ -108,7 +145,7 @@ func (manager *ComposeStackManager) Down(ctx context.Context, stack *portainer.S
 		defer proxy.Close()
 	}
 
-	err = manager.deployer.Remove(ctx, stack.Name, nil, libstack.Options{
+	err = deployer.Remove(ctx, stack.Name, nil, libstack.Options{
 		WorkingDir: "",
 		Host:       url,
 	})
@@ -119,6 +156,11 @@ func (manager *ComposeStackManager) Down(ctx context.Context, stack *portainer.S
 // Pull an image associated with a service defined in a docker-compose.yml or docker-stack.yml file,
 // but does not start containers based on those images.
 func (manager *ComposeStackManager) Pull(ctx context.Context, stack *portainer.Stack, endpoint *portainer.Endpoint) error {
+	deployer, err := manager.endpointDeployer(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
 	url, proxy, err := manager.fetchEndpointProxy(endpoint)
 	if err != nil {
 		return err
@@ -133,11 +175,12 @@ func (manager *ComposeStackManager) Pull(ctx context.Context, stack *portainer.S
 	}
 
 	filePaths := stackutils.GetStackFilePaths(stack, true)
-	err = manager.deployer.Pull(ctx, filePaths, libstack.Options{
+	err = deployer.Pull(ctx, filePaths, libstack.Options{
 		WorkingDir:  stack.ProjectPath,
 		EnvFilePath: envFilePath,
 		Host:        url,
 		ProjectName: stack.Name,
+		Profiles:    stack.Profiles,
 	})
 	return errors.Wrap(err, "failed to pull images of the stack")
 }
@@ -160,10 +203,12 @@ func (manager *ComposeStackManager) fetchEndpointProxy(endpoint *portainer.Endpo
 	return fmt.Sprintf("tcp://127.0.0.1:%d", proxy.Port), proxy, nil
 }
 
-// createEnvFile creates a file that would hold both "in-place" and default environment variables.
-// It will return the name of the file if the stack has "in-place" env vars, otherwise empty string.
+// createEnvFile creates a file that would hold the default, uploaded and "in-place" environment variables.
+// The variables defined last take precedence, so the "in-place" variables override the ones of the uploaded .env file,
+// which override the ones of the default .env file of the project.
+// It will return the name of the file if the stack has "in-place" env vars or an uploaded .env file, otherwise empty string.
 func createEnvFile(stack *portainer.Stack) (string, error) {
-	if len(stack.Env) == 0 {
+	if len(stack.Env) == 0 && stack.EnvFilePath == "" {
 		return "", nil
 	}
 
@@ -180,6 +225,13 @@ func createEnvFile(stack *portainer.Stack) (string, error) {
 		return "", err
 	}
 
+	// Copy from the uploaded .env file
+	if stack.EnvFilePath != "" {
+		if err = copyUploadedEnvFile(envfile, stack.EnvFilePath); err != nil {
+			return "", err
+		}
+	}
+
 	// Copy from stack env vars
 	if err = copyConfigEnvVars(envfile, stack.Env); err != nil {
 		return "", err
@@ -188,6 +240,26 @@ func createEnvFile(stack *portainer.Stack) (string, error) {
 	return "stack.env", nil
 }
 
+// copyUploadedEnvFile copies the .env file uploaded for the stack to the provided writer, unlike the default
+// .env file it must be readable as the user explicitly provided it
+func copyUploadedEnvFile(w io.Writer, envFilePath string) error {
+	envFile, err := os.Open(envFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to open the stack env file: %w", err)
+	}
+	defer envFile.Close()
+
+	if _, err := io.Copy(w, envFile); err != nil {
+		return fmt.Errorf("failed to copy the stack env file: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, "\n"); err != nil {
+		return fmt.Errorf("failed to copy the stack env file: %w", err)
+	}
+
+	return nil
+}
+
 // copyDefaultEnvFile copies the default .env file if it exists to the provided writer
 func copyDefaultEnvFile(w io.Writer, defaultEnvFilePath string) error {
 	defaultEnvFile, err := os.Open(defaultEnvFilePath)
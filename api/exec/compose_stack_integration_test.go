@@ -52,7 +52,7 @@ func Test_UpAndDown(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	w, err := NewComposeStackManager(deployer, nil)
+	w, err := NewComposeStackManager(deployer, nil, nil)
 	if err != nil {
 		t.Fatalf("Failed creating manager: %s", err)
 	}
@@ -85,3 +85,35 @@ func Test_createEnvFile_mergesDefultAndInplaceEnvVars(t *testing.T) {
 
 	assert.Equal(t, []byte("VAR1=VAL1\nVAR2=VAL2\n\nVAR1=NEW_VAL1\nVAR3=VAL3\n"), content)
 }
+
+func Test_createEnvFile_mergesUploadedEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(path.Join(dir, ".env"), []byte("VAR1=VAL1\nVAR2=VAL2\n"), 0600)
+
+	envFilePath := path.Join(t.TempDir(), ".env")
+	os.WriteFile(envFilePath, []byte("VAR2=UPLOADED_VAL2\nVAR3=UPLOADED_VAL3\n"), 0600)
+
+	stack := &portainer.Stack{
+		ProjectPath: dir,
+		EnvFilePath: envFilePath,
+		Env: []portainer.Pair{
+			{Name: "VAR3", Value: "VAL3"},
+		},
+	}
+	result, err := createEnvFile(stack)
+	assert.NoError(t, err)
+	assert.Equal(t, "stack.env", result)
+
+	content, err := os.ReadFile(path.Join(dir, "stack.env"))
+	assert.NoError(t, err)
+	assert.Equal(t, "VAR1=VAL1\nVAR2=VAL2\n\nVAR2=UPLOADED_VAL2\nVAR3=UPLOADED_VAL3\n\nVAR3=VAL3\n", string(content))
+
+	stack.Env = nil
+	result, err = createEnvFile(stack)
+	assert.NoError(t, err)
+	assert.Equal(t, "stack.env", result)
+
+	stack.EnvFilePath = path.Join(dir, "missing.env")
+	_, err = createEnvFile(stack)
+	assert.Error(t, err)
+}
@@ -18,3 +18,7 @@ func (deployer *kubernetesMockDeployer) Deploy(userID portainer.UserID, endpoint
 func (deployer *kubernetesMockDeployer) Remove(userID portainer.UserID, endpoint *portainer.Endpoint, manifestFiles []string, namespace string) (string, error) {
 	return "", nil
 }
+
+func (deployer *kubernetesMockDeployer) Validate(userID portainer.UserID, endpoint *portainer.Endpoint, manifestFiles []string, namespace string, strict bool) ([]portainer.KubernetesManifestError, error) {
+	return nil, nil
+}
@@ -88,41 +88,64 @@ func (deployer *KubernetesDeployer) Remove(userID portainer.UserID, endpoint *po
 }
 
 func (deployer *KubernetesDeployer) command(operation string, userID portainer.UserID, endpoint *portainer.Endpoint, manifestFiles []string, namespace string) (string, error) {
+	command, args, closeProxy, err := deployer.kubectl(userID, endpoint, namespace)
+	if err != nil {
+		return "", err
+	}
+	defer closeProxy()
+
+	if operation == "delete" {
+		args = append(args, "--ignore-not-found=true")
+	}
+
+	args = append(args, operation)
+	for _, path := range manifestFiles {
+		args = append(args, "-f", strings.TrimSpace(path))
+	}
+
+	output, stderr, err := runKubectl(command, args)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to execute kubectl command: %q", stderr)
+	}
+
+	return output, nil
+}
+
+// kubectl returns the kubectl binary and the arguments connecting it to the environment as the user,
+// closeProxy must be called once the commands are run
+func (deployer *KubernetesDeployer) kubectl(userID portainer.UserID, endpoint *portainer.Endpoint, namespace string) (command string, args []string, closeProxy func(), err error) {
 	token, err := deployer.getToken(userID, endpoint, endpoint.Type == portainer.KubernetesLocalEnvironment)
 	if err != nil {
-		return "", errors.Wrap(err, "failed generating a user token")
+		return "", nil, nil, errors.Wrap(err, "failed generating a user token")
 	}
 
-	command := path.Join(deployer.binaryPath, "kubectl")
+	command = path.Join(deployer.binaryPath, "kubectl")
 	if runtime.GOOS == "windows" {
 		command = path.Join(deployer.binaryPath, "kubectl.exe")
 	}
 
-	args := []string{"--token", token}
+	args = []string{"--token", token}
 	if namespace != "" {
 		args = append(args, "--namespace", namespace)
 	}
 
+	closeProxy = func() {}
+
 	if endpoint.Type == portainer.AgentOnKubernetesEnvironment || endpoint.Type == portainer.EdgeAgentOnKubernetesEnvironment {
 		url, proxy, err := deployer.getAgentURL(endpoint)
 		if err != nil {
-			return "", errors.WithMessage(err, "failed generating endpoint URL")
+			return "", nil, nil, errors.WithMessage(err, "failed generating endpoint URL")
 		}
 
-		defer proxy.Close()
+		closeProxy = proxy.Close
 		args = append(args, "--server", url)
 		args = append(args, "--insecure-skip-tls-verify")
 	}
 
-	if operation == "delete" {
-		args = append(args, "--ignore-not-found=true")
-	}
-
-	args = append(args, operation)
-	for _, path := range manifestFiles {
-		args = append(args, "-f", strings.TrimSpace(path))
-	}
+	return command, args, closeProxy, nil
+}
 
+func runKubectl(command string, args []string) (string, string, error) {
 	var stderr bytes.Buffer
 	cmd := exec.Command(command, args...)
 	cmd.Env = os.Environ()
@@ -130,11 +153,8 @@ func (deployer *KubernetesDeployer) command(operation string, userID portainer.U
 	cmd.Stderr = &stderr
 
 	output, err := cmd.Output()
-	if err != nil {
-		return "", errors.Wrapf(err, "failed to execute kubectl command: %q", stderr.String())
-	}
 
-	return string(output), nil
+	return string(output), stderr.String(), err
 }
 
 func (deployer *KubernetesDeployer) getAgentURL(endpoint *portainer.Endpoint) (string, *factory.ProxyServer, error) {
@@ -0,0 +1,161 @@
+package exec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// manifestDocument is a document of a manifest file, checked on its own
+type manifestDocument struct {
+	file     string
+	position int
+	kind     string
+	name     string
+	content  []byte
+}
+
+// Validate checks the manifests against the Kubernetes API of the environment with a server-side dry run, nothing is
+// applied. Each document is checked on its own so that the errors are reported per document. When strict is set,
+// the fields that are not part of the schemas of the resources are rejected as well.
+// The returned error is set when the manifests cannot be checked, e.g. when the environment is unreachable
+func (deployer *KubernetesDeployer) Validate(userID portainer.UserID, endpoint *portainer.Endpoint, manifestFiles []string, namespace string, strict bool) ([]portainer.KubernetesManifestError, error) {
+	documents, manifestErrors := splitManifestDocuments(manifestFiles)
+	if len(documents) == 0 {
+		return manifestErrors, nil
+	}
+
+	tmpDir, err := os.MkdirTemp("", "kube_validation")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp validation directory")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	command, args, closeProxy, err := deployer.kubectl(userID, endpoint, namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer closeProxy()
+
+	validate := "--validate=false"
+	if strict {
+		validate = "--validate=strict"
+	}
+
+	for i, document := range documents {
+		documentPath := filepath.Join(tmpDir, fmt.Sprintf("document-%d.yaml", i))
+		if err := os.WriteFile(documentPath, document.content, 0o600); err != nil {
+			return nil, errors.Wrap(err, "failed to create temp manifest file")
+		}
+
+		_, stderr, err := runKubectl(command, append(args, "apply", "--dry-run=server", validate, "-f", documentPath))
+		if err == nil {
+			continue
+		}
+
+		if isKubectlConnectionError(stderr) {
+			return nil, errors.Errorf("failed to reach the Kubernetes API: %s", strings.TrimSpace(stderr))
+		}
+
+		manifestErrors = append(manifestErrors, portainer.KubernetesManifestError{
+			File:     document.file,
+			Document: document.position,
+			Kind:     document.kind,
+			Name:     document.name,
+			Message:  kubectlErrorMessage(stderr, documentPath, err),
+		})
+	}
+
+	return manifestErrors, nil
+}
+
+// splitManifestDocuments returns the documents of the manifest files, along with the errors of the files that
+// cannot be read or parsed. The parsing of a file stops at its first invalid document
+func splitManifestDocuments(manifestFiles []string) ([]manifestDocument, []portainer.KubernetesManifestError) {
+	var documents []manifestDocument
+	var manifestErrors []portainer.KubernetesManifestError
+
+	for _, file := range manifestFiles {
+		file = strings.TrimSpace(file)
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			manifestErrors = append(manifestErrors, portainer.KubernetesManifestError{File: file, Message: err.Error()})
+
+			continue
+		}
+
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		for position := 1; ; {
+			var document map[string]any
+			if err := decoder.Decode(&document); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				manifestErrors = append(manifestErrors, portainer.KubernetesManifestError{
+					File:     file,
+					Document: position,
+					Message:  "invalid YAML: " + err.Error(),
+				})
+
+				break
+			}
+
+			// Empty documents are skipped by kubectl
+			if document == nil {
+				continue
+			}
+
+			documentContent, err := yaml.Marshal(document)
+			if err != nil {
+				manifestErrors = append(manifestErrors, portainer.KubernetesManifestError{File: file, Document: position, Message: err.Error()})
+
+				break
+			}
+
+			kind, _ := document["kind"].(string)
+
+			var name string
+			if metadata, ok := document["metadata"].(map[string]any); ok {
+				name, _ = metadata["name"].(string)
+			}
+
+			documents = append(documents, manifestDocument{
+				file:     file,
+				position: position,
+				kind:     kind,
+				name:     name,
+				content:  documentContent,
+			})
+
+			position++
+		}
+	}
+
+	return documents, manifestErrors
+}
+
+// isKubectlConnectionError tells the failures to reach the Kubernetes API from the errors of the documents
+func isKubectlConnectionError(stderr string) bool {
+	return strings.Contains(stderr, "Unable to connect to the server") ||
+		strings.Contains(stderr, "The connection to the server")
+}
+
+// kubectlErrorMessage returns the error of kubectl without the path of the temporary file of the document
+func kubectlErrorMessage(stderr, documentPath string, err error) string {
+	message := strings.TrimSpace(stderr)
+	if message == "" {
+		return err.Error()
+	}
+
+	message = strings.ReplaceAll(message, fmt.Sprintf(" %q", documentPath), "")
+
+	return strings.ReplaceAll(message, documentPath, "")
+}
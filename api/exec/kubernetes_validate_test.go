@@ -0,0 +1,66 @@
+package exec
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitManifestDocuments(t *testing.T) {
+	dir := t.TempDir()
+
+	app := filepath.Join(dir, "app.yaml")
+	require.NoError(t, os.WriteFile(app, []byte(`apiVersion: v1
+kind: Namespace
+metadata:
+  name: web
+---
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: nginx
+  namespace: web
+`), 0o600))
+
+	broken := filepath.Join(dir, "broken.yaml")
+	require.NoError(t, os.WriteFile(broken, []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: nginx
+---
+kind: ConfigMap
+metadata: [name: settings
+`), 0o600))
+
+	documents, manifestErrors := splitManifestDocuments([]string{app, broken, filepath.Join(dir, "missing.yaml")})
+
+	require.Len(t, documents, 3)
+	assert.Equal(t, manifestDocument{file: app, position: 1, kind: "Namespace", name: "web", content: documents[0].content}, documents[0])
+	assert.Equal(t, 2, documents[1].position)
+	assert.Equal(t, "Deployment", documents[1].kind)
+	assert.Equal(t, "nginx", documents[1].name)
+	assert.Equal(t, broken, documents[2].file)
+
+	require.Len(t, manifestErrors, 2)
+	assert.Equal(t, broken, manifestErrors[0].File)
+	assert.Equal(t, 2, manifestErrors[0].Document)
+	assert.Contains(t, manifestErrors[0].Message, "invalid YAML")
+	assert.Equal(t, filepath.Join(dir, "missing.yaml"), manifestErrors[1].File)
+}
+
+func TestKubectlErrorMessage(t *testing.T) {
+	stderr := `Error from server (Invalid): error when creating "/tmp/kube_validation/document-0.yaml": Deployment.apps "nginx" is invalid: spec.replicas: Invalid value: -1
+`
+
+	assert.Equal(t,
+		`Error from server (Invalid): error when creating: Deployment.apps "nginx" is invalid: spec.replicas: Invalid value: -1`,
+		kubectlErrorMessage(stderr, "/tmp/kube_validation/document-0.yaml", errors.New("exit status 1")),
+	)
+
+	assert.Equal(t, "exit status 1", kubectlErrorMessage("", "/tmp/kube_validation/document-0.yaml", errors.New("exit status 1")))
+}
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	portainer "github.com/portainer/portainer/api"
 
@@ -31,6 +33,11 @@ const (
 	TLSKeyFile = "key.pem"
 	// ComposeStorePath represents the subfolder where compose files are stored in the file store folder.
 	ComposeStorePath = "compose"
+	// StackEnvStorePath represents the subfolder where the .env files uploaded for the stacks are stored in the file store folder.
+	// They are kept out of the stack project folder so that they survive the redeployments of the git stacks.
+	StackEnvStorePath = "stack_env"
+	// StackEnvFileName represents the name on disk of the .env file uploaded for a stack.
+	StackEnvFileName = ".env"
 	// ComposeFileDefaultName represents the default name of a compose file.
 	ComposeFileDefaultName = "docker-compose.yml"
 	// ManifestFileDefaultName represents the default name of a k8s manifest file.
@@ -67,19 +74,42 @@ const (
 	MTLSCACertFilename = "mtls-ca-cert.pem"
 	MTLSKeyFilename    = "mtls-key.pem"
 
+	// ACMEPath represents the subfolder where the state of the ACME client is stored
+	ACMEPath = "acme"
+	// ACMEAccountKeyFilename represents the ACME account private key file name
+	ACMEAccountKeyFilename = "account-key.pem"
+
 	// ChiselPath represents the default chisel path
 	ChiselPath = "chisel"
 	// ChiselPrivateKeyFilename represents the chisel private key file name
 	ChiselPrivateKeyFilename = "private-key.pem"
+	// UIOverridesStorePath represents the subfolder where the UI asset overrides are stored in the file store folder.
+	UIOverridesStorePath = "ui_overrides"
+	// UICustomCSSFilename represents the name of the custom stylesheet override
+	UICustomCSSFilename = "custom.css"
+	// UIFaviconFilename represents the name of the favicon override
+	UIFaviconFilename = "favicon"
+	// UILoginTextFilename represents the name of the login page text override
+	UILoginTextFilename = "login.txt"
 )
 
 // ErrUndefinedTLSFileType represents an error returned on undefined TLS file type
 var ErrUndefinedTLSFileType = errors.New("Undefined TLS file type")
 
+var (
+	// ErrPathTraversal is returned when an untrusted path goes higher up than its trusted root
+	ErrPathTraversal = errors.New("the path goes outside of its root folder")
+	// ErrSymlinkInPath is returned when an untrusted path goes through a symbolic link below its trusted root
+	ErrSymlinkInPath = errors.New("the path goes through a symbolic link")
+)
+
 // Service represents a service for managing files and directories.
 type Service struct {
 	dataStorePath string
 	fileStorePath string
+
+	objectStorageMu sync.RWMutex
+	objectStorage   portainer.ObjectStorage
 }
 
 // JoinPaths takes a trusted root path and a list of untrusted paths and joins
@@ -101,6 +131,50 @@ func JoinPaths(trustedRoot string, untrustedPaths ...string) string {
 	return p
 }
 
+// SafeJoin takes a trusted root path and a list of untrusted paths and joins them together like JoinPaths,
+// but it returns an error instead of confining the untrusted paths that go higher up than the trusted root
+// or that go through a symbolic link below the trusted root
+func SafeJoin(trustedRoot string, untrustedPaths ...string) (string, error) {
+	p, err := joinLocalPaths(trustedRoot, untrustedPaths...)
+	if err != nil {
+		return "", err
+	}
+
+	current := JoinPaths(trustedRoot)
+	for _, part := range strings.Split(filepath.Join(untrustedPaths...), string(filepath.Separator)) {
+		if part == "" || part == "." {
+			continue
+		}
+
+		current = filepath.Join(current, part)
+
+		info, err := os.Lstat(current)
+		if errors.Is(err, fs.ErrNotExist) {
+			// The remaining components cannot exist either
+			break
+		} else if err != nil {
+			return "", err
+		}
+
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return "", fmt.Errorf("%w: %s", ErrSymlinkInPath, current)
+		}
+	}
+
+	return p, nil
+}
+
+// joinLocalPaths joins the untrusted paths to the trusted root, it returns an error when they go higher up
+// than the trusted root. The file system is not read, the trusted root can be relative to the file store
+func joinLocalPaths(trustedRoot string, untrustedPaths ...string) (string, error) {
+	rel := filepath.Join(untrustedPaths...)
+	if rel != "" && !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%w: %s", ErrPathTraversal, rel)
+	}
+
+	return JoinPaths(trustedRoot, rel), nil
+}
+
 // NewService initializes a new service. It creates a data directory and a directory to store files
 // inside this directory if they don't exist.
 func NewService(dataStorePath, fileStorePath string) (*Service, error) {
@@ -246,7 +320,11 @@ func (service *Service) StoreStackFileFromBytes(stackIdentifier, fileName string
 		return "", err
 	}
 
-	composeFilePath := JoinPaths(stackStorePath, fileName)
+	composeFilePath, err := joinLocalPaths(stackStorePath, fileName)
+	if err != nil {
+		return "", err
+	}
+
 	r := bytes.NewReader(data)
 
 	err = service.createFileInStore(composeFilePath, r)
@@ -271,7 +349,11 @@ func (service *Service) StoreStackFileFromBytesByVersion(stackIdentifier, fileNa
 		return "", err
 	}
 
-	composeFilePath := JoinPaths(stackVersionPath, fileName)
+	composeFilePath, err := joinLocalPaths(stackVersionPath, fileName)
+	if err != nil {
+		return "", err
+	}
+
 	r := bytes.NewReader(data)
 
 	err = service.createFileInStore(composeFilePath, r)
@@ -286,8 +368,12 @@ func (service *Service) StoreStackFileFromBytesByVersion(stackIdentifier, fileNa
 // It returns the path to the folder where the file is stored.
 func (service *Service) UpdateStoreStackFileFromBytes(stackIdentifier, fileName string, data []byte) (string, error) {
 	stackStorePath := JoinPaths(ComposeStorePath, stackIdentifier)
-	composeFilePath := JoinPaths(stackStorePath, fileName)
-	err := service.createBackupFileInStore(composeFilePath)
+	composeFilePath, err := joinLocalPaths(stackStorePath, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	err = service.createBackupFileInStore(composeFilePath)
 	if err != nil {
 		return "", err
 	}
@@ -318,8 +404,12 @@ func (service *Service) UpdateStoreStackFileFromBytesByVersion(stackIdentifier,
 		stackStorePath = JoinPaths(stackStorePath, versionStr)
 	}
 
-	composeFilePath := JoinPaths(stackStorePath, fileName)
-	err := service.createBackupFileInStore(composeFilePath)
+	composeFilePath, err := joinLocalPaths(stackStorePath, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	err = service.createBackupFileInStore(composeFilePath)
 	if err != nil {
 		return "", err
 	}
@@ -336,11 +426,34 @@ func (service *Service) UpdateStoreStackFileFromBytesByVersion(stackIdentifier,
 // RemoveStackFileBackup removes the stack file backup in the ComposeStorePath.
 func (service *Service) RemoveStackFileBackup(stackIdentifier, fileName string) error {
 	stackStorePath := JoinPaths(ComposeStorePath, stackIdentifier)
-	composeFilePath := JoinPaths(stackStorePath, fileName)
+	composeFilePath, err := joinLocalPaths(stackStorePath, fileName)
+	if err != nil {
+		return err
+	}
 
 	return service.removeBackupFileInStore(composeFilePath)
 }
 
+// RemoveStackFile removes a single stack file and its backup from the ComposeStorePath.
+func (service *Service) RemoveStackFile(stackIdentifier, fileName string) error {
+	stackStorePath := JoinPaths(ComposeStorePath, stackIdentifier)
+	composeFilePath, err := joinLocalPaths(stackStorePath, fileName)
+	if err != nil {
+		return err
+	}
+
+	if err := service.removeBackupFileInStore(composeFilePath); err != nil {
+		return err
+	}
+
+	err = os.Remove(service.wrapFileStore(composeFilePath))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
 // RemoveStackFileBackupByVersion removes the stack file backup by version in the ComposeStorePath.
 func (service *Service) RemoveStackFileBackupByVersion(stackIdentifier string, version int, fileName string) error {
 	versionStr := ""
@@ -348,7 +461,10 @@ func (service *Service) RemoveStackFileBackupByVersion(stackIdentifier string, v
 		versionStr = fmt.Sprintf("v%d", version)
 	}
 	stackStorePath := JoinPaths(ComposeStorePath, stackIdentifier, versionStr)
-	composeFilePath := JoinPaths(stackStorePath, fileName)
+	composeFilePath, err := joinLocalPaths(stackStorePath, fileName)
+	if err != nil {
+		return err
+	}
 
 	return service.removeBackupFileInStore(composeFilePath)
 }
@@ -356,8 +472,16 @@ func (service *Service) RemoveStackFileBackupByVersion(stackIdentifier string, v
 // RollbackStackFile rollbacks the stack file backup in the ComposeStorePath.
 func (service *Service) RollbackStackFile(stackIdentifier, fileName string) error {
 	stackStorePath := JoinPaths(ComposeStorePath, stackIdentifier)
-	composeFilePath := JoinPaths(stackStorePath, fileName)
-	path := service.wrapFileStore(composeFilePath)
+	composeFilePath, err := joinLocalPaths(stackStorePath, fileName)
+	if err != nil {
+		return err
+	}
+
+	path, err := service.safeWrapFileStore(composeFilePath)
+	if err != nil {
+		return err
+	}
+
 	backupPath := path + ".bak"
 
 	exists, err := service.FileExists(backupPath)
@@ -385,8 +509,16 @@ func (service *Service) RollbackStackFileByVersion(stackIdentifier string, versi
 		versionStr = "v" + strconv.Itoa(version)
 	}
 	stackStorePath := JoinPaths(ComposeStorePath, stackIdentifier, versionStr)
-	composeFilePath := JoinPaths(stackStorePath, fileName)
-	path := service.wrapFileStore(composeFilePath)
+	composeFilePath, err := joinLocalPaths(stackStorePath, fileName)
+	if err != nil {
+		return err
+	}
+
+	path, err := service.safeWrapFileStore(composeFilePath)
+	if err != nil {
+		return err
+	}
+
 	backupPath := path + ".bak"
 
 	exists, err := service.FileExists(backupPath)
@@ -422,7 +554,11 @@ func (service *Service) StoreEdgeStackFileFromBytes(edgeStackIdentifier, fileNam
 		return "", err
 	}
 
-	composeFilePath := JoinPaths(stackStorePath, fileName)
+	composeFilePath, err := joinLocalPaths(stackStorePath, fileName)
+	if err != nil {
+		return "", err
+	}
+
 	r := bytes.NewReader(data)
 
 	err = service.createFileInStore(composeFilePath, r)
@@ -433,6 +569,49 @@ func (service *Service) StoreEdgeStackFileFromBytes(edgeStackIdentifier, fileNam
 	return service.wrapFileStore(stackStorePath), nil
 }
 
+// StoreEdgeStackDeploymentLogFromBytes stores the deployment logs of an edge stack sent by the agent of an
+// environment(endpoint), replacing the previous ones.
+func (service *Service) StoreEdgeStackDeploymentLogFromBytes(edgeStackID, endpointID string, data []byte) error {
+	if storage := service.getObjectStorage(); storage != nil {
+		return putObject(storage, edgeStackDeploymentLogKey(edgeStackID, endpointID), data)
+	}
+
+	stackStorePath := JoinPaths(EdgeStackStorePath, edgeStackID)
+	if err := service.createDirectoryInStore(stackStorePath); err != nil {
+		return err
+	}
+
+	return service.createFileInStore(JoinPaths(stackStorePath, "logs_"+endpointID), bytes.NewReader(data))
+}
+
+// GetEdgeStackDeploymentLogFileContent fetches the deployment logs of an edge stack for an environment(endpoint)
+func (service *Service) GetEdgeStackDeploymentLogFileContent(edgeStackID, endpointID string) (string, error) {
+	if storage := service.getObjectStorage(); storage != nil {
+		content, err := getObject(storage, edgeStackDeploymentLogKey(edgeStackID, endpointID))
+		return string(content), err
+	}
+
+	fileContent, err := os.ReadFile(service.getEdgeStackDeploymentLogPath(edgeStackID, endpointID))
+	if err != nil {
+		return "", err
+	}
+
+	return string(fileContent), nil
+}
+
+// ClearEdgeStackDeploymentLog removes the deployment logs of an edge stack for an environment(endpoint)
+func (service *Service) ClearEdgeStackDeploymentLog(edgeStackID, endpointID string) error {
+	if storage := service.getObjectStorage(); storage != nil {
+		return deleteObject(storage, edgeStackDeploymentLogKey(edgeStackID, endpointID))
+	}
+
+	return os.Remove(service.getEdgeStackDeploymentLogPath(edgeStackID, endpointID))
+}
+
+func (service *Service) getEdgeStackDeploymentLogPath(edgeStackID, endpointID string) string {
+	return JoinPaths(service.GetEdgeStackProjectPath(edgeStackID), "logs_"+endpointID)
+}
+
 // GetEdgeStackProjectPathByVersion returns the absolute path on the FS for a edge stack based
 // on its identifier and version.
 // EE only feature
@@ -464,7 +643,11 @@ func (service *Service) StoreEdgeStackFileFromBytesByVersion(edgeStackIdentifier
 		return "", err
 	}
 
-	composeFilePath := JoinPaths(stackStorePath, fileName)
+	composeFilePath, err := joinLocalPaths(stackStorePath, fileName)
+	if err != nil {
+		return "", err
+	}
+
 	r := bytes.NewReader(data)
 
 	err = service.createFileInStore(composeFilePath, r)
@@ -499,7 +682,11 @@ func (service *Service) StoreRegistryManagementFileFromBytes(folder, fileName st
 		return "", err
 	}
 
-	file := JoinPaths(extensionStorePath, fileName)
+	file, err := joinLocalPaths(extensionStorePath, fileName)
+	if err != nil {
+		return "", err
+	}
+
 	r := bytes.NewReader(data)
 
 	err = service.createFileInStore(file, r)
@@ -583,7 +770,12 @@ func (service *Service) DeleteTLSFile(folder string, fileType portainer.TLSFileT
 
 // GetFileContent returns the content of a file as bytes.
 func (service *Service) GetFileContent(trustedRoot, filePath string) ([]byte, error) {
-	content, err := os.ReadFile(JoinPaths(trustedRoot, filePath))
+	path, err := SafeJoin(trustedRoot, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
 	if err != nil {
 		if filePath == "" {
 			filePath = trustedRoot
@@ -658,20 +850,31 @@ func (service *Service) LoadKeyPair() ([]byte, []byte, error) {
 
 // createDirectoryInStore creates a new directory in the file store
 func (service *Service) createDirectoryInStore(name string) error {
-	path := service.wrapFileStore(name)
+	path, err := service.safeWrapFileStore(name)
+	if err != nil {
+		return err
+	}
+
 	return os.MkdirAll(path, 0700)
 }
 
 // createFile creates a new file in the file store with the content from r.
 func (service *Service) createFileInStore(filePath string, r io.Reader) error {
-	path := service.wrapFileStore(filePath)
+	path, err := service.safeWrapFileStore(filePath)
+	if err != nil {
+		return err
+	}
 
 	return CreateFile(path, r)
 }
 
 // createBackupFileInStore makes a copy in the file store.
 func (service *Service) createBackupFileInStore(filePath string) error {
-	path := service.wrapFileStore(filePath)
+	path, err := service.safeWrapFileStore(filePath)
+	if err != nil {
+		return err
+	}
+
 	backupPath := path + ".bak"
 
 	return service.Copy(path, backupPath, true)
@@ -679,7 +882,11 @@ func (service *Service) createBackupFileInStore(filePath string) error {
 
 // removeBackupFileInStore removes the copy in the file store.
 func (service *Service) removeBackupFileInStore(filePath string) error {
-	path := service.wrapFileStore(filePath)
+	path, err := service.safeWrapFileStore(filePath)
+	if err != nil {
+		return err
+	}
+
 	backupPath := path + ".bak"
 
 	exists, err := service.FileExists(backupPath)
@@ -695,7 +902,11 @@ func (service *Service) removeBackupFileInStore(filePath string) error {
 }
 
 func (service *Service) createPEMFileInStore(content []byte, fileType, filePath string) error {
-	path := service.wrapFileStore(filePath)
+	path, err := service.safeWrapFileStore(filePath)
+	if err != nil {
+		return err
+	}
+
 	block := &pem.Block{Type: fileType, Bytes: content}
 
 	out, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
@@ -708,7 +919,10 @@ func (service *Service) createPEMFileInStore(content []byte, fileType, filePath
 }
 
 func (service *Service) getContentFromPEMFile(filePath string) ([]byte, error) {
-	path := service.wrapFileStore(filePath)
+	path, err := service.safeWrapFileStore(filePath)
+	if err != nil {
+		return nil, err
+	}
 
 	fileContent, err := os.ReadFile(path)
 	if err != nil {
@@ -734,7 +948,11 @@ func (service *Service) StoreCustomTemplateFileFromBytes(identifier, fileName st
 		return "", err
 	}
 
-	templateFilePath := JoinPaths(customTemplateStorePath, fileName)
+	templateFilePath, err := joinLocalPaths(customTemplateStorePath, fileName)
+	if err != nil {
+		return "", err
+	}
+
 	r := bytes.NewReader(data)
 
 	err = service.createFileInStore(templateFilePath, r)
@@ -776,12 +994,21 @@ func createEdgeJobFileName(identifier string) string {
 
 // ClearEdgeJobTaskLogs clears the Edge job task logs
 func (service *Service) ClearEdgeJobTaskLogs(edgeJobID string, taskID string) error {
+	if storage := service.getObjectStorage(); storage != nil {
+		return deleteObject(storage, edgeJobTaskLogKey(edgeJobID, taskID))
+	}
+
 	path := service.getEdgeJobTaskLogPath(edgeJobID, taskID)
 	return os.Remove(path)
 }
 
 // GetEdgeJobTaskLogFileContent fetches the Edge job task logs
 func (service *Service) GetEdgeJobTaskLogFileContent(edgeJobID string, taskID string) (string, error) {
+	if storage := service.getObjectStorage(); storage != nil {
+		content, err := getObject(storage, edgeJobTaskLogKey(edgeJobID, taskID))
+		return string(content), err
+	}
+
 	path := service.getEdgeJobTaskLogPath(edgeJobID, taskID)
 
 	fileContent, err := os.ReadFile(path)
@@ -794,6 +1021,10 @@ func (service *Service) GetEdgeJobTaskLogFileContent(edgeJobID string, taskID st
 
 // StoreEdgeJobTaskLogFileFromBytes stores the log file
 func (service *Service) StoreEdgeJobTaskLogFileFromBytes(edgeJobID, taskID string, data []byte) error {
+	if storage := service.getObjectStorage(); storage != nil {
+		return putObject(storage, edgeJobTaskLogKey(edgeJobID, taskID), data)
+	}
+
 	edgeJobStorePath := JoinPaths(EdgeJobStorePath, edgeJobID)
 	err := service.createDirectoryInStore(edgeJobStorePath)
 	if err != nil {
@@ -805,10 +1036,69 @@ func (service *Service) StoreEdgeJobTaskLogFileFromBytes(edgeJobID, taskID strin
 	return service.createFileInStore(filePath, r)
 }
 
+// OpenEdgeJobTaskLogWriter returns a writer appending to the log file of an Edge job task, the file is created when missing.
+// The writer must be closed by the caller
+func (service *Service) OpenEdgeJobTaskLogWriter(edgeJobID, taskID string) (io.WriteCloser, error) {
+	if storage := service.getObjectStorage(); storage != nil {
+		return newObjectAppendWriter(storage, edgeJobTaskLogKey(edgeJobID, taskID))
+	}
+
+	if err := service.createDirectoryInStore(JoinPaths(EdgeJobStorePath, edgeJobID)); err != nil {
+		return nil, err
+	}
+
+	return os.OpenFile(service.getEdgeJobTaskLogPath(edgeJobID, taskID), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+}
+
 func (service *Service) getEdgeJobTaskLogPath(edgeJobID string, taskID string) string {
 	return fmt.Sprintf("%s/logs_%s", service.GetEdgeJobFolder(edgeJobID), taskID)
 }
 
+// GetUIOverridesPath returns the absolute path on the filesystem of the folder holding the UI asset overrides.
+func (service *Service) GetUIOverridesPath() string {
+	return service.wrapFileStore(UIOverridesStorePath)
+}
+
+// StoreUIOverrideFileFromBytes stores a UI asset override, replacing the previous one.
+// It returns the path to the folder where the file is stored.
+func (service *Service) StoreUIOverrideFileFromBytes(fileName string, data []byte) (string, error) {
+	if err := service.createDirectoryInStore(UIOverridesStorePath); err != nil {
+		return "", err
+	}
+
+	filePath, err := joinLocalPaths(UIOverridesStorePath, fileName)
+	if err != nil {
+		return "", err
+	}
+
+	if err := service.createFileInStore(filePath, bytes.NewReader(data)); err != nil {
+		return "", err
+	}
+
+	return service.GetUIOverridesPath(), nil
+}
+
+// StoreStackEnvFileFromBytes stores the .env file of a stack, replacing the previous one.
+// It returns the path to the file.
+func (service *Service) StoreStackEnvFileFromBytes(stackIdentifier string, data []byte) (string, error) {
+	envStorePath := JoinPaths(StackEnvStorePath, stackIdentifier)
+	if err := service.createDirectoryInStore(envStorePath); err != nil {
+		return "", err
+	}
+
+	envFilePath := JoinPaths(envStorePath, StackEnvFileName)
+	if err := service.createFileInStore(envFilePath, bytes.NewReader(data)); err != nil {
+		return "", err
+	}
+
+	return service.wrapFileStore(envFilePath), nil
+}
+
+// RemoveStackEnvFile removes the .env file of a stack.
+func (service *Service) RemoveStackEnvFile(stackIdentifier string) error {
+	return os.RemoveAll(service.wrapFileStore(JoinPaths(StackEnvStorePath, stackIdentifier)))
+}
+
 // GetTemporaryPath returns a temp folder
 func (service *Service) GetTemporaryPath() (string, error) {
 	uid, err := uuid.NewV4()
@@ -828,6 +1118,12 @@ func (service *Service) wrapFileStore(filepath string) string {
 	return JoinPaths(service.fileStorePath, filepath)
 }
 
+// safeWrapFileStore returns the absolute path of a file of the file store, it fails when the path goes through
+// a symbolic link so that the files outside of the file store cannot be read or overwritten
+func (service *Service) safeWrapFileStore(filepath string) (string, error) {
+	return SafeJoin(service.fileStorePath, filepath)
+}
+
 func defaultCertPathUnderFileStore() (string, string) {
 	certPath := JoinPaths(SSLCertPath, SSLCertFilename)
 	keyPath := JoinPaths(SSLCertPath, SSLKeyFilename)
@@ -918,6 +1214,22 @@ func (service *Service) CopySSLCACert(caCertPath string) (string, error) {
 	return toFilePath, nil
 }
 
+// StoreACMEAccountKey stores the private key of the ACME account on disk.
+func (service *Service) StoreACMEAccountKey(key []byte) error {
+	err := service.createDirectoryInStore(ACMEPath)
+	if err != nil && !os.IsExist(err) {
+		return err
+	}
+
+	return service.createFileInStore(JoinPaths(ACMEPath, ACMEAccountKeyFilename), bytes.NewReader(key))
+}
+
+// GetACMEAccountKey returns the private key of the ACME account, the error wraps os.ErrNotExist when no
+// account was created yet.
+func (service *Service) GetACMEAccountKey() ([]byte, error) {
+	return os.ReadFile(service.wrapFileStore(JoinPaths(ACMEPath, ACMEAccountKeyFilename)))
+}
+
 // FileExists checks for the existence of the specified file.
 func FileExists(filePath string) (bool, error) {
 	if _, err := os.Stat(filePath); err != nil {
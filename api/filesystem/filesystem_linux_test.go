@@ -1,6 +1,13 @@
 package filesystem
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
 
 func TestJoinPaths(t *testing.T) {
 	var ts = []struct {
@@ -68,3 +75,50 @@ func TestJoinPaths(t *testing.T) {
 		}
 	}
 }
+
+func TestSafeJoin(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "stack"), 0700))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "link")))
+	require.NoError(t, os.Symlink("/etc/passwd", filepath.Join(root, "stack", "passwd")))
+
+	p, err := SafeJoin(root, "stack", "docker-compose.yml")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "stack", "docker-compose.yml"), p)
+
+	p, err = SafeJoin(root, "stack/../missing/file")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "missing", "file"), p)
+
+	_, err = SafeJoin(root, "../etc/shadow")
+	assert.ErrorIs(t, err, ErrPathTraversal)
+
+	_, err = SafeJoin(root, "/etc/shadow")
+	assert.ErrorIs(t, err, ErrPathTraversal)
+
+	_, err = SafeJoin(root, "link", "file")
+	assert.ErrorIs(t, err, ErrSymlinkInPath)
+
+	_, err = SafeJoin(root, "stack", "passwd")
+	assert.ErrorIs(t, err, ErrSymlinkInPath)
+}
+
+func TestStoreStackFileRejectsUnsafePaths(t *testing.T) {
+	service := createService(t)
+
+	_, err := service.StoreStackFileFromBytes("1", "../2/docker-compose.yml", []byte("services: {}"))
+	assert.ErrorIs(t, err, ErrPathTraversal)
+
+	stackPath, err := service.StoreStackFileFromBytes("1", "docker-compose.yml", []byte("services: {}"))
+	require.NoError(t, err)
+
+	require.NoError(t, os.Symlink("/etc/hostname", filepath.Join(stackPath, "hostname")))
+
+	_, err = service.UpdateStoreStackFileFromBytes("1", "hostname", []byte("overwritten"))
+	assert.ErrorIs(t, err, ErrSymlinkInPath)
+
+	_, err = service.GetFileContent(stackPath, "hostname")
+	assert.ErrorIs(t, err, ErrSymlinkInPath)
+}
@@ -20,3 +20,20 @@ func createService(t *testing.T) *Service {
 
 	return service
 }
+
+func TestOpenEdgeJobTaskLogWriter(t *testing.T) {
+	service := createService(t)
+
+	for _, chunk := range []string{"first line\n", "second line\n"} {
+		writer, err := service.OpenEdgeJobTaskLogWriter("1", "2")
+		assert.NoError(t, err)
+
+		_, err = writer.Write([]byte(chunk))
+		assert.NoError(t, err)
+		assert.NoError(t, writer.Close())
+	}
+
+	content, err := service.GetEdgeJobTaskLogFileContent("1", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, "first line\nsecond line\n", content)
+}
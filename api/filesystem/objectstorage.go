@@ -0,0 +1,117 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+const (
+	// BackupStorePath represents the prefix of the keys of the backup archives in the object storage.
+	BackupStorePath = "backups"
+
+	objectStorageTimeout       = time.Minute
+	backupObjectStorageTimeout = 30 * time.Minute
+)
+
+// ErrObjectStorageDisabled is returned when a file can only be stored in the object storage and none is configured
+var ErrObjectStorageDisabled = errors.New("the object storage is not enabled")
+
+// SetObjectStorage delegates the storage of the backup archives and of the Edge job task logs to an object storage.
+// They are stored in the data directory when storage is nil
+func (service *Service) SetObjectStorage(storage portainer.ObjectStorage) {
+	service.objectStorageMu.Lock()
+	defer service.objectStorageMu.Unlock()
+
+	service.objectStorage = storage
+}
+
+func (service *Service) getObjectStorage() portainer.ObjectStorage {
+	service.objectStorageMu.RLock()
+	defer service.objectStorageMu.RUnlock()
+
+	return service.objectStorage
+}
+
+// StoreBackupArchive uploads a backup archive to the object storage
+func (service *Service) StoreBackupArchive(name string, r io.ReadSeeker) error {
+	storage := service.getObjectStorage()
+	if storage == nil {
+		return ErrObjectStorageDisabled
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backupObjectStorageTimeout)
+	defer cancel()
+
+	return storage.PutObject(ctx, path.Join(BackupStorePath, path.Base(name)), r)
+}
+
+func edgeJobTaskLogKey(edgeJobID, taskID string) string {
+	return path.Join(EdgeJobStorePath, path.Base(edgeJobID), "logs_"+path.Base(taskID))
+}
+
+func edgeStackDeploymentLogKey(edgeStackID, endpointID string) string {
+	return path.Join(EdgeStackStorePath, path.Base(edgeStackID), "logs_"+path.Base(endpointID))
+}
+
+func getObject(storage portainer.ObjectStorage, key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), objectStorageTimeout)
+	defer cancel()
+
+	r, err := storage.GetObject(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+func putObject(storage portainer.ObjectStorage, key string, data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), objectStorageTimeout)
+	defer cancel()
+
+	return storage.PutObject(ctx, key, bytes.NewReader(data))
+}
+
+func deleteObject(storage portainer.ObjectStorage, key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), objectStorageTimeout)
+	defer cancel()
+
+	return storage.DeleteObject(ctx, key)
+}
+
+// objectAppendWriter appends to an object. Objects cannot be modified in place, the whole content is
+// uploaded again when the writer is closed
+type objectAppendWriter struct {
+	storage portainer.ObjectStorage
+	key     string
+	buf     bytes.Buffer
+}
+
+func newObjectAppendWriter(storage portainer.ObjectStorage, key string) (io.WriteCloser, error) {
+	writer := &objectAppendWriter{storage: storage, key: key}
+
+	content, err := getObject(storage, key)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	writer.buf.Write(content)
+
+	return writer, nil
+}
+
+func (writer *objectAppendWriter) Write(p []byte) (int, error) {
+	return writer.buf.Write(p)
+}
+
+func (writer *objectAppendWriter) Close() error {
+	return putObject(writer.storage, writer.key, writer.buf.Bytes())
+}
@@ -0,0 +1,79 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryObjectStorage map[string][]byte
+
+func (storage memoryObjectStorage) PutObject(ctx context.Context, key string, body io.ReadSeeker) error {
+	data, err := io.ReadAll(body)
+	storage[key] = data
+
+	return err
+}
+
+func (storage memoryObjectStorage) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := storage[key]
+	if !ok {
+		return nil, fmt.Errorf("object %s: %w", key, fs.ErrNotExist)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (storage memoryObjectStorage) DeleteObject(ctx context.Context, key string) error {
+	delete(storage, key)
+
+	return nil
+}
+
+func TestEdgeJobTaskLogsInObjectStorage(t *testing.T) {
+	service, err := NewService(t.TempDir(), "")
+	require.NoError(t, err)
+
+	storage := memoryObjectStorage{}
+	service.SetObjectStorage(storage)
+
+	_, err = service.GetEdgeJobTaskLogFileContent("1", "2")
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	for _, chunk := range []string{"hello ", "world"} {
+		writer, err := service.OpenEdgeJobTaskLogWriter("1", "2")
+		require.NoError(t, err)
+
+		_, err = writer.Write([]byte(chunk))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+	}
+
+	assert.Equal(t, "hello world", string(storage["edge_jobs/1/logs_2"]))
+
+	content, err := service.GetEdgeJobTaskLogFileContent("1", "2")
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", content)
+
+	require.NoError(t, service.ClearEdgeJobTaskLogs("1", "2"))
+	assert.Empty(t, storage)
+
+	require.NoError(t, service.StoreBackupArchive("../portainer-backup.tar.gz", strings.NewReader("archive")))
+	assert.Equal(t, "archive", string(storage["backups/portainer-backup.tar.gz"]))
+
+	// The files are stored in the data directory once the object storage is disabled
+	service.SetObjectStorage(nil)
+	require.ErrorIs(t, service.StoreBackupArchive("portainer-backup.tar.gz", strings.NewReader("archive")), ErrObjectStorageDisabled)
+
+	require.NoError(t, service.StoreEdgeJobTaskLogFileFromBytes("1", "2", []byte("local")))
+	content, err = service.GetEdgeJobTaskLogFileContent("1", "2")
+	require.NoError(t, err)
+	assert.Equal(t, "local", content)
+}
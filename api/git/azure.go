@@ -13,6 +13,7 @@ import (
 	"github.com/portainer/portainer/api/archive"
 	"github.com/portainer/portainer/api/crypto"
 	gittypes "github.com/portainer/portainer/api/git/types"
+	httpclient "github.com/portainer/portainer/api/http/client"
 
 	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/pkg/errors"
@@ -49,6 +50,17 @@ type azureItem struct {
 	Path     string `json:"path"`
 }
 
+// azureCommit abstracts from the response of https://docs.microsoft.com/en-us/rest/api/azure/devops/git/commits/get?view=azure-devops-rest-6.0
+type azureCommit struct {
+	CommitID string `json:"commitId"`
+	Comment  string `json:"comment"`
+	Author   struct {
+		Name  string    `json:"name"`
+		Email string    `json:"email"`
+		Date  time.Time `json:"date"`
+	} `json:"author"`
+}
+
 type azureClient struct {
 	baseUrl string
 }
@@ -69,7 +81,7 @@ func newHttpClientForAzure(insecureSkipVerify bool) *http.Client {
 	httpsCli := &http.Client{
 		Transport: &http.Transport{
 			TLSClientConfig: tlsConfig,
-			Proxy:           http.ProxyFromEnvironment,
+			Proxy:           httpclient.Proxy,
 		},
 		Timeout: 300 * time.Second,
 	}
@@ -77,6 +89,8 @@ func newHttpClientForAzure(insecureSkipVerify bool) *http.Client {
 	return httpsCli
 }
 
+// download extracts an archive of the whole tree of the repository, it never holds any history so the
+// depth and the sparse checkout directories of the options are not used
 func (a *azureClient) download(ctx context.Context, destination string, opt cloneOption) error {
 	zipFilepath, err := a.downloadZipFromAzureDevOps(ctx, opt)
 	if err != nil {
@@ -152,6 +166,60 @@ func (a *azureClient) latestCommitID(ctx context.Context, opt fetchOption) (stri
 	return rootItem.CommitId, nil
 }
 
+func (a *azureClient) latestCommit(ctx context.Context, opt fetchOption) (*gittypes.CommitInfo, error) {
+	rootItem, err := a.getRootItem(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := parseUrl(opt.repositoryUrl)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to parse url")
+	}
+
+	commitUrl, err := a.buildCommitUrl(config, rootItem.CommitId)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to build azure commit url")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", commitUrl, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create a new HTTP request")
+	}
+
+	if opt.username != "" || opt.password != "" {
+		req.SetBasicAuth(opt.username, opt.password)
+	} else if config.username != "" || config.password != "" {
+		req.SetBasicAuth(config.username, config.password)
+	}
+
+	client := newHttpClientForAzure(opt.tlsSkipVerify)
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to make an HTTP request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, checkAzureStatusCode(fmt.Errorf("failed to get the commit with a status \"%v\"", resp.Status), resp.StatusCode)
+	}
+
+	var commit azureCommit
+	if err := json.NewDecoder(resp.Body).Decode(&commit); err != nil {
+		return nil, errors.Wrap(err, "could not parse Azure commit response")
+	}
+
+	return &gittypes.CommitInfo{
+		Hash:        commit.CommitID,
+		Author:      commit.Author.Name,
+		AuthorEmail: commit.Author.Email,
+		Message:     strings.TrimSpace(commit.Comment),
+		Date:        commit.Author.Date.Unix(),
+	}, nil
+}
+
 func (a *azureClient) getRootItem(ctx context.Context, opt fetchOption) (*azureItem, error) {
 	config, err := parseUrl(opt.repositoryUrl)
 	if err != nil {
@@ -341,6 +409,28 @@ func (a *azureClient) buildRefsUrl(config *azureOptions) (string, error) {
 	return u.String(), nil
 }
 
+func (a *azureClient) buildCommitUrl(config *azureOptions, commitID string) (string, error) {
+	// ref@https://docs.microsoft.com/en-us/rest/api/azure/devops/git/commits/get?view=azure-devops-rest-6.0
+	rawUrl := fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s/commits/%s",
+		a.baseUrl,
+		url.PathEscape(config.organisation),
+		url.PathEscape(config.project),
+		url.PathEscape(config.repository),
+		url.PathEscape(commitID),
+	)
+	u, err := url.Parse(rawUrl)
+
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse commit url path %s", rawUrl)
+	}
+
+	q := u.Query()
+	q.Set("api-version", "6.0")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
 func (a *azureClient) buildTreeUrl(config *azureOptions, rootObjectHash string) (string, error) {
 	// ref@https://docs.microsoft.com/en-us/rest/api/azure/devops/git/trees/get?view=azure-devops-rest-6.0
 	rawUrl := fmt.Sprintf("%s/%s/%s/_apis/git/repositories/%s/trees/%s",
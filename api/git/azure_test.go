@@ -63,6 +63,23 @@ func Test_buildRefsUrl(t *testing.T) {
 	assert.Equal(t, expectedUrl.Query(), actualUrl.Query())
 }
 
+func Test_buildCommitUrl(t *testing.T) {
+	a := NewAzureClient()
+	u, err := a.buildCommitUrl(&azureOptions{
+		organisation: "organisation",
+		project:      "project",
+		repository:   "repository",
+	}, "sha1")
+
+	expectedUrl, _ := url.Parse("https://dev.azure.com/organisation/project/_apis/git/repositories/repository/commits/sha1?api-version=6.0")
+	actualUrl, _ := url.Parse(u)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUrl.Host, actualUrl.Host)
+	assert.Equal(t, expectedUrl.Scheme, actualUrl.Scheme)
+	assert.Equal(t, expectedUrl.Path, actualUrl.Path)
+	assert.Equal(t, expectedUrl.Query(), actualUrl.Query())
+}
+
 func Test_buildTreeUrl(t *testing.T) {
 	a := NewAzureClient()
 	u, err := a.buildTreeUrl(&azureOptions{
@@ -375,6 +392,10 @@ func (t *testRepoManager) latestCommitID(_ context.Context, _ fetchOption) (stri
 	return "", nil
 }
 
+func (t *testRepoManager) latestCommit(_ context.Context, _ fetchOption) (*gittypes.CommitInfo, error) {
+	return nil, nil
+}
+
 func (t *testRepoManager) listRefs(_ context.Context, _ baseOption) ([]string, error) {
 	return nil, nil
 }
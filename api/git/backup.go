@@ -19,12 +19,14 @@ type CloneOptions struct {
 	ReferenceName string
 	Username      string
 	Password      string
+	SSHPrivateKey string
+	SSHPassphrase string
 	// TLSSkipVerify skips SSL verification when cloning the Git repository
 	TLSSkipVerify bool `example:"false"`
 }
 
 func CloneWithBackup(gitService portainer.GitService, fileService portainer.FileService, options CloneOptions) (clean func(), err error) {
-	backupProjectPath := options.ProjectPath + "-old"
+	backupProjectPath := backupPath(options.ProjectPath)
 	cleanUp := false
 	cleanFn := func() {
 		if !cleanUp {
@@ -42,7 +44,7 @@ func CloneWithBackup(gitService portainer.GitService, fileService portainer.File
 
 	cleanUp = true
 
-	if err := gitService.CloneRepository(options.ProjectPath, options.URL, options.ReferenceName, options.Username, options.Password, options.TLSSkipVerify); err != nil {
+	if err := gitService.CloneRepository(options.ProjectPath, options.URL, options.ReferenceName, options.Username, options.Password, options.SSHPrivateKey, options.SSHPassphrase, options.TLSSkipVerify, gittypes.CloneOptions{}); err != nil {
 		cleanUp = false
 		if err := filesystem.MoveDirectory(backupProjectPath, options.ProjectPath, false); err != nil {
 			log.Warn().Err(err).Msg("failed restoring backup folder")
@@ -57,3 +59,13 @@ func CloneWithBackup(gitService portainer.GitService, fileService portainer.File
 
 	return cleanFn, nil
 }
+
+// RestoreBackup replaces a repository cloned by CloneWithBackup with its backup,
+// it must be called before the clean function returned by CloneWithBackup
+func RestoreBackup(projectPath string) error {
+	return filesystem.MoveDirectory(backupPath(projectPath), projectPath, true)
+}
+
+func backupPath(projectPath string) string {
+	return projectPath + "-old"
+}
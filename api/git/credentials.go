@@ -11,3 +11,12 @@ func GetCredentials(auth *gittypes.GitAuthentication) (string, string, error) {
 
 	return auth.Username, auth.Password, nil
 }
+
+// GetSSHCredentials returns the SSH private key and its passphrase, empty when the authentication does not use SSH
+func GetSSHCredentials(auth *gittypes.GitAuthentication) (string, string) {
+	if auth == nil {
+		return "", ""
+	}
+
+	return auth.SSHPrivateKey, auth.SSHPassphrase
+}
@@ -2,18 +2,22 @@ package git
 
 import (
 	"context"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 
 	gittypes "github.com/portainer/portainer/api/git/types"
+	httpclient "github.com/portainer/portainer/api/http/client"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/pkg/errors"
 )
@@ -29,19 +33,28 @@ func NewGitClient(preserveGitDir bool) *gitClient {
 }
 
 func (c *gitClient) download(ctx context.Context, dst string, opt cloneOption) error {
+	auth, err := getAuth(opt.baseOption)
+	if err != nil {
+		return err
+	}
+
 	gitOptions := git.CloneOptions{
 		URL:             opt.repositoryUrl,
 		Depth:           opt.depth,
 		InsecureSkipTLS: opt.tlsSkipVerify,
-		Auth:            getAuth(opt.username, opt.password),
+		Auth:            auth,
 		Tags:            git.NoTags,
+		ProxyOptions:    getProxyOptions(opt.repositoryUrl),
 	}
 
 	if opt.referenceName != "" {
 		gitOptions.ReferenceName = plumbing.ReferenceName(opt.referenceName)
 	}
 
-	_, err := git.PlainCloneContext(ctx, dst, false, &gitOptions)
+	// The files are checked out once the directories to keep are known
+	gitOptions.NoCheckout = len(opt.sparseCheckoutDirectories) > 0
+
+	repository, err := git.PlainCloneContext(ctx, dst, false, &gitOptions)
 
 	if err != nil {
 		if err.Error() == "authentication required" {
@@ -50,6 +63,12 @@ func (c *gitClient) download(ctx context.Context, dst string, opt cloneOption) e
 		return errors.Wrap(err, "failed to clone git repository")
 	}
 
+	if gitOptions.NoCheckout {
+		if err := sparseCheckout(repository, opt.sparseCheckoutDirectories); err != nil {
+			return errors.Wrap(err, "failed to check out the git repository")
+		}
+	}
+
 	if !c.preserveGitDirectory {
 		os.RemoveAll(filepath.Join(dst, ".git"))
 	}
@@ -57,15 +76,51 @@ func (c *gitClient) download(ctx context.Context, dst string, opt cloneOption) e
 	return nil
 }
 
+// sparseCheckout checks out only the given directories of the cloned commit
+func sparseCheckout(repository *git.Repository, directories []string) error {
+	head, err := repository.Head()
+	if err != nil {
+		return err
+	}
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		return err
+	}
+
+	checkoutOptions := git.CheckoutOptions{
+		Force: true,
+	}
+
+	// The trailing slash prevents checking out the directories sharing a prefix with the requested ones, e.g. app2 for app
+	for _, directory := range directories {
+		checkoutOptions.SparseCheckoutDirectories = append(checkoutOptions.SparseCheckoutDirectories, strings.TrimSuffix(directory, "/")+"/")
+	}
+
+	if head.Name().IsBranch() {
+		checkoutOptions.Branch = head.Name()
+	} else {
+		checkoutOptions.Hash = head.Hash()
+	}
+
+	return worktree.Checkout(&checkoutOptions)
+}
+
 func (c *gitClient) latestCommitID(ctx context.Context, opt fetchOption) (string, error) {
+	auth, err := getAuth(opt.baseOption)
+	if err != nil {
+		return "", err
+	}
+
 	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
 		Name: "origin",
 		URLs: []string{opt.repositoryUrl},
 	})
 
 	listOptions := &git.ListOptions{
-		Auth:            getAuth(opt.username, opt.password),
+		Auth:            auth,
 		InsecureSkipTLS: opt.tlsSkipVerify,
+		ProxyOptions:    getProxyOptions(opt.repositoryUrl),
 	}
 
 	refs, err := remote.List(listOptions)
@@ -94,29 +149,129 @@ func (c *gitClient) latestCommitID(ctx context.Context, opt fetchOption) (string
 	return "", errors.Errorf("could not find ref %q in the repository", opt.referenceName)
 }
 
-func getAuth(username, password string) *githttp.BasicAuth {
-	if password != "" {
+// latestCommit fetches the latest commit of the reference without its files
+func (c *gitClient) latestCommit(ctx context.Context, opt fetchOption) (*gittypes.CommitInfo, error) {
+	auth, err := getAuth(opt.baseOption)
+	if err != nil {
+		return nil, err
+	}
+
+	cloneOption := &git.CloneOptions{
+		URL:             opt.repositoryUrl,
+		NoCheckout:      true,
+		Depth:           1,
+		SingleBranch:    true,
+		Auth:            auth,
+		InsecureSkipTLS: opt.tlsSkipVerify,
+		Tags:            git.NoTags,
+		ProxyOptions:    getProxyOptions(opt.repositoryUrl),
+	}
+
+	if opt.referenceName != "" {
+		cloneOption.ReferenceName = plumbing.ReferenceName(opt.referenceName)
+	}
+
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, cloneOption)
+	if err != nil {
+		return nil, checkGitError(err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	return &gittypes.CommitInfo{
+		Hash:        commit.Hash.String(),
+		Author:      commit.Author.Name,
+		AuthorEmail: commit.Author.Email,
+		Message:     strings.TrimSpace(commit.Message),
+		Date:        commit.Author.When.Unix(),
+	}, nil
+}
+
+// getProxyOptions returns the outbound proxy used to reach a repository over HTTP, the repositories
+// reached over SSH are always reached directly
+func getProxyOptions(repositoryURL string) transport.ProxyOptions {
+	target, err := url.Parse(repositoryURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		return transport.ProxyOptions{}
+	}
+
+	proxyURL, err := httpclient.ProxyForURL(target)
+	if err != nil || proxyURL == nil {
+		return transport.ProxyOptions{}
+	}
+
+	options := transport.ProxyOptions{URL: proxyURL.Scheme + "://" + proxyURL.Host}
+	if proxyURL.User != nil {
+		options.Username = proxyURL.User.Username()
+		options.Password, _ = proxyURL.User.Password()
+	}
+
+	return options
+}
+
+func getAuth(opt baseOption) (transport.AuthMethod, error) {
+	if opt.sshPrivateKey != "" {
+		return getSSHAuth(opt)
+	}
+
+	if opt.password != "" {
+		username := opt.username
 		if username == "" {
 			username = "token"
 		}
 
 		return &githttp.BasicAuth{
 			Username: username,
-			Password: password,
-		}
+			Password: opt.password,
+		}, nil
 	}
-	return nil
+
+	return nil, nil
+}
+
+// getSSHAuth authenticates with a private key, the host key is checked with the host key callback
+// of the options or against the known_hosts file of the user
+func getSSHAuth(opt baseOption) (transport.AuthMethod, error) {
+	username := opt.username
+	if username == "" {
+		username = "git"
+	}
+
+	auth, err := gitssh.NewPublicKeys(username, []byte(opt.sshPrivateKey), opt.sshPassphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the SSH private key")
+	}
+
+	if opt.hostKeyCallback != nil {
+		auth.HostKeyCallback = opt.hostKeyCallback
+	}
+
+	return auth, nil
 }
 
 func (c *gitClient) listRefs(ctx context.Context, opt baseOption) ([]string, error) {
+	auth, err := getAuth(opt)
+	if err != nil {
+		return nil, err
+	}
+
 	rem := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
 		Name: "origin",
 		URLs: []string{opt.repositoryUrl},
 	})
 
 	listOptions := &git.ListOptions{
-		Auth:            getAuth(opt.username, opt.password),
+		Auth:            auth,
 		InsecureSkipTLS: opt.tlsSkipVerify,
+		ProxyOptions:    getProxyOptions(opt.repositoryUrl),
 	}
 
 	refs, err := rem.List(listOptions)
@@ -137,15 +292,21 @@ func (c *gitClient) listRefs(ctx context.Context, opt baseOption) ([]string, err
 
 // listFiles list all filenames under the specific repository
 func (c *gitClient) listFiles(ctx context.Context, opt fetchOption) ([]string, error) {
+	auth, err := getAuth(opt.baseOption)
+	if err != nil {
+		return nil, err
+	}
+
 	cloneOption := &git.CloneOptions{
 		URL:             opt.repositoryUrl,
 		NoCheckout:      true,
 		Depth:           1,
 		SingleBranch:    true,
 		ReferenceName:   plumbing.ReferenceName(opt.referenceName),
-		Auth:            getAuth(opt.username, opt.password),
+		Auth:            auth,
 		InsecureSkipTLS: opt.tlsSkipVerify,
 		Tags:            git.NoTags,
+		ProxyOptions:    getProxyOptions(opt.repositoryUrl),
 	}
 
 	repo, err := git.Clone(memory.NewStorage(), nil, cloneOption)
@@ -2,6 +2,9 @@ package git
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"os"
 	"path/filepath"
 	"testing"
@@ -11,8 +14,11 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
 )
 
 func setup(t *testing.T) string {
@@ -38,7 +44,7 @@ func Test_ClonePublicRepository_Shallow(t *testing.T) {
 
 	dir := t.TempDir()
 	t.Logf("Cloning into %s", dir)
-	err := service.CloneRepository(dir, repositoryURL, referenceName, "", "", false)
+	err := service.CloneRepository(dir, repositoryURL, referenceName, "", "", "", "", false, gittypes.CloneOptions{})
 	assert.NoError(t, err)
 	assert.Equal(t, 1, getCommitHistoryLength(t, err, dir), "cloned repo has incorrect depth")
 }
@@ -50,7 +56,7 @@ func Test_ClonePublicRepository_NoGitDirectory(t *testing.T) {
 
 	dir := t.TempDir()
 	t.Logf("Cloning into %s", dir)
-	err := service.CloneRepository(dir, repositoryURL, referenceName, "", "", false)
+	err := service.CloneRepository(dir, repositoryURL, referenceName, "", "", "", "", false, gittypes.CloneOptions{})
 	assert.NoError(t, err)
 	assert.NoDirExists(t, filepath.Join(dir, ".git"))
 }
@@ -84,18 +90,36 @@ func Test_latestCommitID(t *testing.T) {
 	repositoryURL := setup(t)
 	referenceName := "refs/heads/main"
 
-	id, err := service.LatestCommitID(repositoryURL, referenceName, "", "", false)
+	id, err := service.LatestCommitID(repositoryURL, referenceName, "", "", "", "", false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "68dcaa7bd452494043c64252ab90db0f98ecf8d2", id)
 }
 
+func Test_latestCommit(t *testing.T) {
+	service := Service{git: NewGitClient(true)} // no need for http client since the test access the repo via file system.
+
+	repositoryURL := setup(t)
+	referenceName := "refs/heads/main"
+
+	commit, err := service.LatestCommit(repositoryURL, referenceName, "", "", "", "", false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &gittypes.CommitInfo{
+		Hash:        "68dcaa7bd452494043c64252ab90db0f98ecf8d2",
+		Author:      "Dennis Buduev",
+		AuthorEmail: "dennis.buduev@portainer.io",
+		Message:     "Add package.json",
+		Date:        1622088275,
+	}, commit)
+}
+
 func Test_ListRefs(t *testing.T) {
 	service := Service{git: NewGitClient(true)}
 
 	repositoryURL := setup(t)
 
-	fs, err := service.ListRefs(repositoryURL, "", "", false, false)
+	fs, err := service.ListRefs(repositoryURL, "", "", "", "", false, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, []string{"refs/heads/main"}, fs)
@@ -107,7 +131,7 @@ func Test_ListFiles(t *testing.T) {
 	repositoryURL := setup(t)
 	referenceName := "refs/heads/main"
 
-	fs, err := service.ListFiles(repositoryURL, referenceName, "", "", false, false, []string{".yml"}, false)
+	fs, err := service.ListFiles(repositoryURL, referenceName, "", "", "", "", false, false, []string{".yml"}, false)
 
 	assert.NoError(t, err)
 	assert.Equal(t, []string{"docker-compose.yml"}, fs)
@@ -329,3 +353,32 @@ func Test_listFilesPrivateRepository(t *testing.T) {
 		})
 	}
 }
+
+func Test_getAuth(t *testing.T) {
+	auth, err := getAuth(baseOption{})
+	assert.NoError(t, err)
+	assert.Nil(t, auth)
+
+	auth, err = getAuth(baseOption{password: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, &githttp.BasicAuth{Username: "token", Password: "secret"}, auth)
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	assert.NoError(t, err)
+
+	auth, err = getAuth(baseOption{sshPrivateKey: string(pem.EncodeToMemory(block)), password: "ignored"})
+	assert.NoError(t, err)
+	assert.IsType(t, &gitssh.PublicKeys{}, auth)
+	assert.Equal(t, "git", auth.(*gitssh.PublicKeys).User)
+
+	// The TLS verification setting does not disable the verification of the SSH host key
+	auth, err = getAuth(baseOption{sshPrivateKey: string(pem.EncodeToMemory(block)), tlsSkipVerify: true, hostKeyCallback: NewKnownHosts(filepath.Join(t.TempDir(), "known_hosts")).HostKeyCallback})
+	assert.NoError(t, err)
+	assert.NotNil(t, auth.(*gitssh.PublicKeys).HostKeyCallback)
+
+	_, err = getAuth(baseOption{sshPrivateKey: "not a key"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package git
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// KnownHosts verifies the host keys of the git servers reached over SSH against a known_hosts file.
+// The key of a server seen for the first time is trusted and stored, a server presenting a key that
+// differs from the stored one is rejected
+type KnownHosts struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewKnownHosts creates a store of the SSH host keys kept in the known_hosts file of path
+func NewKnownHosts(path string) *KnownHosts {
+	return &KnownHosts{path: path}
+}
+
+// HostKeyCallback checks the key presented by a git server, it implements ssh.HostKeyCallback
+func (k *KnownHosts) HostKeyCallback(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(k.path), 0o700); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(k.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	callback, err := knownhosts.New(k.path)
+	if err != nil {
+		return err
+	}
+
+	err = callback(hostname, remote, key)
+
+	var keyErr *knownhosts.KeyError
+	if !errors.As(err, &keyErr) || len(keyErr.Want) > 0 {
+		return err
+	}
+
+	_, err = file.WriteString(knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key) + "\n")
+
+	return err
+}
@@ -0,0 +1,44 @@
+package git
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func newHostKey(t *testing.T) ssh.PublicKey {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	key, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+
+	return key
+}
+
+func TestKnownHosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ssh", "known_hosts")
+	remote := &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 22}
+
+	key := newHostKey(t)
+
+	// The key of a server seen for the first time is trusted and stored
+	knownHosts := NewKnownHosts(path)
+	require.NoError(t, knownHosts.HostKeyCallback("github.com:22", remote, key))
+	assert.FileExists(t, path)
+
+	// The stored key is kept across restarts
+	knownHosts = NewKnownHosts(path)
+	require.NoError(t, knownHosts.HostKeyCallback("github.com:22", remote, key))
+
+	// A server presenting another key is rejected
+	assert.Error(t, knownHosts.HostKeyCallback("github.com:22", remote, newHostKey(t)))
+
+	require.NoError(t, knownHosts.HostKeyCallback("gitlab.com:22", remote, newHostKey(t)))
+}
@@ -7,8 +7,11 @@ import (
 	"sync"
 	"time"
 
+	gittypes "github.com/portainer/portainer/api/git/types"
+
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/ssh"
 	"golang.org/x/sync/singleflight"
 )
 
@@ -22,7 +25,11 @@ type baseOption struct {
 	repositoryUrl string
 	username      string
 	password      string
+	sshPrivateKey string
+	sshPassphrase string
 	tlsSkipVerify bool
+	// hostKeyCallback verifies the host key of the SSH servers, the known_hosts file of the user is used when nil
+	hostKeyCallback ssh.HostKeyCallback
 }
 
 // fetchOption allows to specify the reference name of the target repository
@@ -32,15 +39,18 @@ type fetchOption struct {
 	dirOnly       bool
 }
 
-// cloneOption allows to add a history truncated to the specified number of commits
+// cloneOption allows to add a history truncated to the specified number of commits and to check out
+// only some directories of the repository
 type cloneOption struct {
 	fetchOption
-	depth int
+	depth                     int
+	sparseCheckoutDirectories []string
 }
 
 type repoManager interface {
 	download(ctx context.Context, dst string, opt cloneOption) error
 	latestCommitID(ctx context.Context, opt fetchOption) (string, error)
+	latestCommit(ctx context.Context, opt fetchOption) (*gittypes.CommitInfo, error)
 	listRefs(ctx context.Context, opt baseOption) ([]string, error)
 	listFiles(ctx context.Context, opt fetchOption) ([]string, error)
 }
@@ -53,6 +63,8 @@ type Service struct {
 	timerStopped bool
 	mut          sync.Mutex
 
+	knownHosts *KnownHosts
+
 	cacheEnabled bool
 	// Cache the result of repository refs, key is repository URL
 	repoRefCache *lru.Cache
@@ -65,6 +77,12 @@ func NewService(ctx context.Context) *Service {
 	return newService(ctx, repositoryCacheSize, repositoryCacheTTL)
 }
 
+// SetKnownHosts sets the store the SSH host keys of the git servers are verified against,
+// the known_hosts file of the user is used until it is set
+func (service *Service) SetKnownHosts(knownHosts *KnownHosts) {
+	service.knownHosts = knownHosts
+}
+
 func newService(ctx context.Context, cacheSize int, cacheTTL time.Duration) *Service {
 	service := &Service{
 		shutdownCtx:  ctx,
@@ -121,20 +139,33 @@ func (service *Service) timerHasStopped() bool {
 	return ret
 }
 
+// hostKeyCallback returns the verification of the SSH host keys against the stored known hosts
+func (service *Service) hostKeyCallback() ssh.HostKeyCallback {
+	if service.knownHosts == nil {
+		return nil
+	}
+
+	return service.knownHosts.HostKeyCallback
+}
+
 // CloneRepository clones a git repository using the specified URL in the specified
-// destination folder.
-func (service *Service) CloneRepository(destination, repositoryURL, referenceName, username, password string, tlsSkipVerify bool) error {
+// destination folder. Only the latest commit is fetched unless cloneOptions asks for a deeper history.
+func (service *Service) CloneRepository(destination, repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, tlsSkipVerify bool, cloneOptions gittypes.CloneOptions) error {
 	options := cloneOption{
 		fetchOption: fetchOption{
 			baseOption: baseOption{
-				repositoryUrl: repositoryURL,
-				username:      username,
-				password:      password,
-				tlsSkipVerify: tlsSkipVerify,
+				repositoryUrl:   repositoryURL,
+				username:        username,
+				password:        password,
+				sshPrivateKey:   sshPrivateKey,
+				sshPassphrase:   sshPassphrase,
+				tlsSkipVerify:   tlsSkipVerify,
+				hostKeyCallback: service.hostKeyCallback(),
 			},
 			referenceName: referenceName,
 		},
-		depth: 1,
+		depth:                     max(cloneOptions.Depth, 1),
+		sparseCheckoutDirectories: cloneOptions.SparseCheckoutDirectories,
 	}
 
 	return service.cloneRepository(destination, options)
@@ -143,7 +174,8 @@ func (service *Service) CloneRepository(destination, repositoryURL, referenceNam
 func (service *Service) repoManager(options baseOption) repoManager {
 	repoManager := service.git
 
-	if isAzureUrl(options.repositoryUrl) {
+	// The Azure client relies on the HTTP API, SSH keys are only supported by the git protocol
+	if isAzureUrl(options.repositoryUrl) && options.sshPrivateKey == "" {
 		repoManager = service.azure
 	}
 
@@ -155,13 +187,16 @@ func (service *Service) cloneRepository(destination string, options cloneOption)
 }
 
 // LatestCommitID returns SHA1 of the latest commit of the specified reference
-func (service *Service) LatestCommitID(repositoryURL, referenceName, username, password string, tlsSkipVerify bool) (string, error) {
+func (service *Service) LatestCommitID(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, tlsSkipVerify bool) (string, error) {
 	options := fetchOption{
 		baseOption: baseOption{
-			repositoryUrl: repositoryURL,
-			username:      username,
-			password:      password,
-			tlsSkipVerify: tlsSkipVerify,
+			repositoryUrl:   repositoryURL,
+			username:        username,
+			password:        password,
+			sshPrivateKey:   sshPrivateKey,
+			sshPassphrase:   sshPassphrase,
+			tlsSkipVerify:   tlsSkipVerify,
+			hostKeyCallback: service.hostKeyCallback(),
 		},
 		referenceName: referenceName,
 	}
@@ -169,9 +204,27 @@ func (service *Service) LatestCommitID(repositoryURL, referenceName, username, p
 	return service.repoManager(options.baseOption).latestCommitID(context.TODO(), options)
 }
 
+// LatestCommit returns the hash, author, message and date of the latest commit of the specified reference
+func (service *Service) LatestCommit(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, tlsSkipVerify bool) (*gittypes.CommitInfo, error) {
+	options := fetchOption{
+		baseOption: baseOption{
+			repositoryUrl:   repositoryURL,
+			username:        username,
+			password:        password,
+			sshPrivateKey:   sshPrivateKey,
+			sshPassphrase:   sshPassphrase,
+			tlsSkipVerify:   tlsSkipVerify,
+			hostKeyCallback: service.hostKeyCallback(),
+		},
+		referenceName: referenceName,
+	}
+
+	return service.repoManager(options.baseOption).latestCommit(context.TODO(), options)
+}
+
 // ListRefs will list target repository's references without cloning the repository
-func (service *Service) ListRefs(repositoryURL, username, password string, hardRefresh bool, tlsSkipVerify bool) ([]string, error) {
-	refCacheKey := generateCacheKey(repositoryURL, username, password, strconv.FormatBool(tlsSkipVerify))
+func (service *Service) ListRefs(repositoryURL, username, password, sshPrivateKey, sshPassphrase string, hardRefresh bool, tlsSkipVerify bool) ([]string, error) {
+	refCacheKey := generateCacheKey(repositoryURL, username, password, sshPrivateKey, strconv.FormatBool(tlsSkipVerify))
 	if service.cacheEnabled && hardRefresh {
 		// Should remove the cache explicitly, so that the following normal list can show the correct result
 		service.repoRefCache.Remove(refCacheKey)
@@ -193,10 +246,13 @@ func (service *Service) ListRefs(repositoryURL, username, password string, hardR
 	}
 
 	options := baseOption{
-		repositoryUrl: repositoryURL,
-		username:      username,
-		password:      password,
-		tlsSkipVerify: tlsSkipVerify,
+		repositoryUrl:   repositoryURL,
+		username:        username,
+		password:        password,
+		sshPrivateKey:   sshPrivateKey,
+		sshPassphrase:   sshPassphrase,
+		tlsSkipVerify:   tlsSkipVerify,
+		hostKeyCallback: service.hostKeyCallback(),
 	}
 
 	refs, err := service.repoManager(options).listRefs(context.TODO(), options)
@@ -215,18 +271,18 @@ var singleflightGroup = &singleflight.Group{}
 
 // ListFiles will list all the files of the target repository with specific extensions.
 // If extension is not provided, it will list all the files under the target repository
-func (service *Service) ListFiles(repositoryURL, referenceName, username, password string, dirOnly, hardRefresh bool, includedExts []string, tlsSkipVerify bool) ([]string, error) {
-	repoKey := generateCacheKey(repositoryURL, referenceName, username, password, strconv.FormatBool(tlsSkipVerify), strconv.FormatBool(dirOnly))
+func (service *Service) ListFiles(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, dirOnly, hardRefresh bool, includedExts []string, tlsSkipVerify bool) ([]string, error) {
+	repoKey := generateCacheKey(repositoryURL, referenceName, username, password, sshPrivateKey, strconv.FormatBool(tlsSkipVerify), strconv.FormatBool(dirOnly))
 
 	fs, err, _ := singleflightGroup.Do(repoKey, func() (any, error) {
-		return service.listFiles(repositoryURL, referenceName, username, password, dirOnly, hardRefresh, tlsSkipVerify)
+		return service.listFiles(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase, dirOnly, hardRefresh, tlsSkipVerify)
 	})
 
 	return filterFiles(fs.([]string), includedExts), err
 }
 
-func (service *Service) listFiles(repositoryURL, referenceName, username, password string, dirOnly, hardRefresh bool, tlsSkipVerify bool) ([]string, error) {
-	repoKey := generateCacheKey(repositoryURL, referenceName, username, password, strconv.FormatBool(tlsSkipVerify), strconv.FormatBool(dirOnly))
+func (service *Service) listFiles(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, dirOnly, hardRefresh bool, tlsSkipVerify bool) ([]string, error) {
+	repoKey := generateCacheKey(repositoryURL, referenceName, username, password, sshPrivateKey, strconv.FormatBool(tlsSkipVerify), strconv.FormatBool(dirOnly))
 
 	if service.cacheEnabled && hardRefresh {
 		// Should remove the cache explicitly, so that the following normal list can show the correct result
@@ -244,10 +300,13 @@ func (service *Service) listFiles(repositoryURL, referenceName, username, passwo
 
 	options := fetchOption{
 		baseOption: baseOption{
-			repositoryUrl: repositoryURL,
-			username:      username,
-			password:      password,
-			tlsSkipVerify: tlsSkipVerify,
+			repositoryUrl:   repositoryURL,
+			username:        username,
+			password:        password,
+			sshPrivateKey:   sshPrivateKey,
+			sshPassphrase:   sshPassphrase,
+			tlsSkipVerify:   tlsSkipVerify,
+			hostKeyCallback: service.hostKeyCallback(),
 		},
 		referenceName: referenceName,
 		dirOnly:       dirOnly,
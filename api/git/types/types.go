@@ -1,6 +1,11 @@
 package gittypes
 
-import "errors"
+import (
+	"errors"
+	"path"
+	"slices"
+	"strings"
+)
 
 var (
 	ErrIncorrectRepositoryURL = errors.New("git repository could not be found, please ensure that the URL is correct")
@@ -21,13 +26,78 @@ type RepoConfig struct {
 	ConfigHash string `example:"bc4c183d756879ea4d173315338110b31004b8e0"`
 	// TLSSkipVerify skips SSL verification when cloning the Git repository
 	TLSSkipVerify bool `example:"false"`
+	// Number of commits fetched when cloning the repository, only the latest commit is fetched when 0
+	CloneDepth int `example:"1"`
+	// Whether only the directory of the config file and SparseCheckoutPaths are checked out when cloning the repository
+	SparseCheckout bool `example:"false"`
+	// Directories checked out in addition to the directory of the config file when SparseCheckout is enabled,
+	// such as the build contexts or the directories of the additional files
+	SparseCheckoutPaths []string `example:"shared/config"`
+	// Author, message and date of the commit ConfigHash, set when it is deployed
+	ConfigCommit *CommitInfo
+}
+
+// CommitInfo describes a commit of a repository
+type CommitInfo struct {
+	// Commit hash
+	Hash string `example:"bc4c183d756879ea4d173315338110b31004b8e0"`
+	// Name of the commit author
+	Author string `example:"John Doe"`
+	// Email of the commit author
+	AuthorEmail string `example:"john.doe@example.com"`
+	// Commit message
+	Message string `example:"Update the compose file"`
+	// Commit date, as a Unix timestamp
+	Date int64 `example:"1700000000"`
+}
+
+// CloneOptions limits what is downloaded when cloning a repository
+type CloneOptions struct {
+	// Number of commits fetched, only the latest commit is fetched when 0
+	Depth int
+	// Directories checked out, the whole tree is checked out when empty
+	SparseCheckoutDirectories []string
+}
+
+// CloneOptions returns the options used to clone the repository. The whole tree is checked out when the
+// config file is at the root of the repository, as its directory contains every other one.
+func (config *RepoConfig) CloneOptions() CloneOptions {
+	options := CloneOptions{Depth: config.CloneDepth}
+
+	if !config.SparseCheckout {
+		return options
+	}
+
+	directories := []string{repositoryPath(path.Dir(config.ConfigFilePath))}
+	for _, sparsePath := range config.SparseCheckoutPaths {
+		directories = append(directories, repositoryPath(sparsePath))
+	}
+
+	if slices.Contains(directories, "") {
+		return options
+	}
+
+	slices.Sort(directories)
+	options.SparseCheckoutDirectories = slices.Compact(directories)
+
+	return options
 }
 
 type GitAuthentication struct {
 	Username string
-	Password string
+	Password string `redact:"true"`
 	// Git credentials identifier when the value is not 0
 	// When the value is 0, Username and Password are set without using saved credential
 	// This is introduced since 2.15.0
 	GitCredentialID int `example:"0"`
+	// PEM encoded private key used to authenticate over SSH, as an alternative to Username and Password.
+	// Username is then the SSH user, "git" when empty
+	SSHPrivateKey string `redact:"true"`
+	// Passphrase of the SSH private key, when it is encrypted
+	SSHPassphrase string `redact:"true"`
+}
+
+// repositoryPath returns a path relative to the root of the repository, empty for the root itself
+func repositoryPath(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
 }
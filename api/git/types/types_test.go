@@ -0,0 +1,20 @@
+package gittypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoConfigCloneOptions(t *testing.T) {
+	config := RepoConfig{ConfigFilePath: "stacks/app/docker-compose.yml", CloneDepth: 5}
+	assert.Equal(t, CloneOptions{Depth: 5}, config.CloneOptions())
+
+	config.SparseCheckout = true
+	config.SparseCheckoutPaths = []string{"shared/", "stacks/app", "./build"}
+	assert.Equal(t, []string{"build", "shared", "stacks/app"}, config.CloneOptions().SparseCheckoutDirectories)
+
+	// The config file at the root of the repository requires the whole tree
+	config.ConfigFilePath = "docker-compose.yml"
+	assert.Empty(t, config.CloneOptions().SparseCheckoutDirectories)
+}
@@ -29,7 +29,9 @@ func UpdateGitObject(gitService portainer.GitService, objId string, gitConfig *g
 		return false, "", errors.WithMessagef(err, "failed to get credentials for %v", objId)
 	}
 
-	newHash, err := gitService.LatestCommitID(gitConfig.URL, gitConfig.ReferenceName, username, password, gitConfig.TLSSkipVerify)
+	sshPrivateKey, sshPassphrase := git.GetSSHCredentials(gitConfig.Authentication)
+
+	newHash, err := gitService.LatestCommitID(gitConfig.URL, gitConfig.ReferenceName, username, password, sshPrivateKey, sshPassphrase, gitConfig.TLSSkipVerify)
 	if err != nil {
 		return false, "", errors.WithMessagef(err, "failed to fetch latest commit id of %v", objId)
 	}
@@ -57,11 +59,14 @@ func UpdateGitObject(gitService portainer.GitService, objId string, gitConfig *g
 		ref:           gitConfig.ReferenceName,
 		toDir:         toDir,
 		tlsSkipVerify: gitConfig.TLSSkipVerify,
+		cloneOptions:  gitConfig.CloneOptions(),
 	}
 	if gitConfig.Authentication != nil {
 		cloneParams.auth = &gitAuth{
-			username: username,
-			password: password,
+			username:      username,
+			password:      password,
+			sshPrivateKey: sshPrivateKey,
+			sshPassphrase: sshPassphrase,
 		}
 	}
 
@@ -86,17 +91,20 @@ type cloneRepositoryParameters struct {
 	auth  *gitAuth
 	// tlsSkipVerify skips SSL verification when cloning the Git repository
 	tlsSkipVerify bool `example:"false"`
+	cloneOptions  gittypes.CloneOptions
 }
 
 type gitAuth struct {
-	username string
-	password string
+	username      string
+	password      string
+	sshPrivateKey string
+	sshPassphrase string
 }
 
 func cloneGitRepository(gitService portainer.GitService, cloneParams *cloneRepositoryParameters) error {
 	if cloneParams.auth != nil {
-		return gitService.CloneRepository(cloneParams.toDir, cloneParams.url, cloneParams.ref, cloneParams.auth.username, cloneParams.auth.password, cloneParams.tlsSkipVerify)
+		return gitService.CloneRepository(cloneParams.toDir, cloneParams.url, cloneParams.ref, cloneParams.auth.username, cloneParams.auth.password, cloneParams.auth.sshPrivateKey, cloneParams.auth.sshPassphrase, cloneParams.tlsSkipVerify, cloneParams.cloneOptions)
 	}
 
-	return gitService.CloneRepository(cloneParams.toDir, cloneParams.url, cloneParams.ref, "", "", cloneParams.tlsSkipVerify)
+	return gitService.CloneRepository(cloneParams.toDir, cloneParams.url, cloneParams.ref, "", "", "", "", cloneParams.tlsSkipVerify, cloneParams.cloneOptions)
 }
@@ -1,14 +1,32 @@
 package git
 
 import (
+	"regexp"
+	"slices"
+	"strings"
+
 	"github.com/asaskevich/govalidator"
 
 	gittypes "github.com/portainer/portainer/api/git/types"
 	httperrors "github.com/portainer/portainer/api/http/errors"
 )
 
+// scpLikeURLRegex matches the scp-like syntax of the SSH repository URLs, e.g. git@github.com:portainer/portainer.git
+var scpLikeURLRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+@[A-Za-z0-9_.-]+:[^\s]+$`)
+
+// IsSSHURL returns true when the repository URL uses the SSH transport
+func IsSSHURL(url string) bool {
+	return (strings.HasPrefix(url, "ssh://") && govalidator.IsURL(strings.Replace(url, "ssh://", "https://", 1))) ||
+		scpLikeURLRegex.MatchString(url)
+}
+
+// IsValidRepositoryURL returns true when the URL can be used to reach a git repository over HTTP(S) or SSH
+func IsValidRepositoryURL(url string) bool {
+	return len(url) > 0 && (govalidator.IsURL(url) || IsSSHURL(url))
+}
+
 func ValidateRepoConfig(repoConfig *gittypes.RepoConfig) error {
-	if len(repoConfig.URL) == 0 || !govalidator.IsURL(repoConfig.URL) {
+	if !IsValidRepositoryURL(repoConfig.URL) {
 		return httperrors.NewInvalidPayloadError("Invalid repository URL. Must correspond to a valid URL format")
 	}
 
@@ -17,8 +35,23 @@ func ValidateRepoConfig(repoConfig *gittypes.RepoConfig) error {
 }
 
 func ValidateRepoAuthentication(auth *gittypes.GitAuthentication) error {
-	if auth != nil && len(auth.Password) == 0 && auth.GitCredentialID == 0 {
-		return httperrors.NewInvalidPayloadError("Invalid repository credentials. Password or GitCredentialID must be specified when authentication is enabled")
+	if auth != nil && len(auth.Password) == 0 && len(auth.SSHPrivateKey) == 0 && auth.GitCredentialID == 0 {
+		return httperrors.NewInvalidPayloadError("Invalid repository credentials. Password, SSH private key or GitCredentialID must be specified when authentication is enabled")
+	}
+
+	return nil
+}
+
+// ValidateCloneSettings validates the depth and the sparse checkout directories used to clone a repository
+func ValidateCloneSettings(cloneDepth int, sparseCheckoutPaths []string) error {
+	if cloneDepth < 0 {
+		return httperrors.NewInvalidPayloadError("Invalid clone depth. The number of commits cannot be negative")
+	}
+
+	for _, sparsePath := range sparseCheckoutPaths {
+		if sparsePath == "" || strings.HasPrefix(sparsePath, "/") || slices.Contains(strings.Split(sparsePath, "/"), "..") {
+			return httperrors.NewInvalidPayloadError("Invalid sparse checkout path. The paths must be relative to the root of the repository")
+		}
 	}
 
 	return nil
@@ -0,0 +1,37 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidRepositoryURL(t *testing.T) {
+	tests := []struct {
+		url      string
+		expected bool
+	}{
+		{"https://github.com/portainer/portainer.git", true},
+		{"http://gitlab.local/group/project", true},
+		{"ssh://git@github.com/portainer/portainer.git", true},
+		{"git@github.com:portainer/portainer.git", true},
+		{"deploy@git.local:repos/app.git", true},
+		{"", false},
+		{"git@github.com:", false},
+		{"not a url", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, IsValidRepositoryURL(tt.url), tt.url)
+	}
+}
+
+func TestValidateCloneSettings(t *testing.T) {
+	assert.NoError(t, ValidateCloneSettings(0, nil))
+	assert.NoError(t, ValidateCloneSettings(10, []string{"stacks/app", "shared/"}))
+
+	assert.Error(t, ValidateCloneSettings(-1, nil))
+	assert.Error(t, ValidateCloneSettings(0, []string{""}))
+	assert.Error(t, ValidateCloneSettings(0, []string{"/etc"}))
+	assert.Error(t, ValidateCloneSettings(0, []string{"stacks/../../etc"}))
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/crypto"
+	"github.com/portainer/portainer/api/http/client"
 
 	"github.com/segmentio/encoding/json"
 	"golang.org/x/sync/errgroup"
@@ -24,6 +26,9 @@ const (
 	powerOnState  portainer.PowerState = 2
 	powerOffState portainer.PowerState = 8
 	restartState  portainer.PowerState = 5
+
+	// maxConcurrentDeviceActions is the number of devices an action is sent to at the same time
+	maxConcurrentDeviceActions = 10
 )
 
 // Service represents a service for managing an OpenAMT server.
@@ -41,6 +46,7 @@ func NewService() *Service {
 			Timeout: httpClientTimeout,
 			Transport: &http.Transport{
 				TLSClientConfig: tlsConfig,
+				Proxy:           client.Proxy,
 			},
 		},
 	}
@@ -239,6 +245,43 @@ func (service *Service) ExecuteDeviceAction(configuration portainer.OpenAMTConfi
 	return service.executeDeviceAction(configuration, deviceGUID, int(parsedAction))
 }
 
+// ExecuteDeviceActions executes an action on several devices. The returned map holds the error of each device
+// the action failed on, the error is only returned when the action cannot be sent to any device
+func (service *Service) ExecuteDeviceActions(configuration portainer.OpenAMTConfiguration, deviceGUIDs []string, action string) (map[string]error, error) {
+	parsedAction, err := parseAction(action)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := service.Authorization(configuration)
+	if err != nil {
+		return nil, err
+	}
+	configuration.MPSToken = token
+
+	var mu sync.Mutex
+	failures := make(map[string]error)
+
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentDeviceActions)
+
+	for _, deviceGUID := range deviceGUIDs {
+		g.Go(func() error {
+			if err := service.executeDeviceAction(configuration, deviceGUID, int(parsedAction)); err != nil {
+				mu.Lock()
+				failures[deviceGUID] = err
+				mu.Unlock()
+			}
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	return failures, nil
+}
+
 func (service *Service) EnableDeviceFeatures(configuration portainer.OpenAMTConfiguration, deviceGUID string, features portainer.OpenAMTDeviceEnabledFeatures) (string, error) {
 	token, err := service.Authorization(configuration)
 	if err != nil {
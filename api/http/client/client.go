@@ -29,7 +29,8 @@ type HTTPClient struct {
 func NewHTTPClient() *HTTPClient {
 	return &HTTPClient{
 		&http.Client{
-			Timeout: time.Second * time.Duration(defaultHTTPTimeout),
+			Timeout:   time.Second * time.Duration(defaultHTTPTimeout),
+			Transport: NewTransport(),
 		},
 	}
 }
@@ -79,7 +80,8 @@ func Get(url string, timeout int) ([]byte, error) {
 	}
 
 	client := &http.Client{
-		Timeout: time.Second * time.Duration(timeout),
+		Timeout:   time.Second * time.Duration(timeout),
+		Transport: NewTransport(),
 	}
 
 	response, err := client.Get(url)
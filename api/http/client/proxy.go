@@ -0,0 +1,115 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"sync/atomic"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// proxyFunc selects the proxy of the outbound calls, it is nil when the proxy is defined by the environment
+var proxyFunc atomic.Pointer[func(*url.URL) (*url.URL, error)]
+
+// SetProxyConfig replaces the proxy used by the outbound calls. When no proxy URL is configured,
+// the proxy defined by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables is used.
+func SetProxyConfig(config portainer.ProxyConfig) error {
+	if config.URL == "" {
+		proxyFunc.Store(nil)
+
+		return nil
+	}
+
+	proxyURL, err := parseProxyURL(config)
+	if err != nil {
+		return err
+	}
+
+	fn := (&httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    config.NoProxy,
+	}).ProxyFunc()
+
+	proxyFunc.Store(&fn)
+
+	return nil
+}
+
+// ValidateProxyConfig ensures that the proxy URL is an absolute http, https or socks5 URL
+func ValidateProxyConfig(config portainer.ProxyConfig) error {
+	if config.URL == "" {
+		return nil
+	}
+
+	_, err := parseProxyURL(config)
+
+	return err
+}
+
+// ProxyConfigFromURL builds a proxy configuration from a proxy URL, the credentials included in the URL are moved to the configuration
+func ProxyConfigFromURL(rawURL string) (portainer.ProxyConfig, error) {
+	proxyURL, err := parseProxyURL(portainer.ProxyConfig{URL: rawURL})
+	if err != nil {
+		return portainer.ProxyConfig{}, err
+	}
+
+	config := portainer.ProxyConfig{}
+	if proxyURL.User != nil {
+		config.Username = proxyURL.User.Username()
+		config.Password, _ = proxyURL.User.Password()
+		proxyURL.User = nil
+	}
+
+	config.URL = proxyURL.String()
+
+	return config, nil
+}
+
+// Proxy returns the proxy to use for the request, it is meant to be used as the Proxy of the transports of outbound calls
+func Proxy(req *http.Request) (*url.URL, error) {
+	return ProxyForURL(req.URL)
+}
+
+// ProxyForURL returns the proxy to use to reach the URL, nil when the URL is reached directly
+func ProxyForURL(target *url.URL) (*url.URL, error) {
+	if fn := proxyFunc.Load(); fn != nil {
+		return (*fn)(target)
+	}
+
+	return http.ProxyFromEnvironment(&http.Request{URL: target})
+}
+
+// NewTransport returns a transport based on the default transport, using the proxy of the outbound calls
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = Proxy
+
+	return transport
+}
+
+func parseProxyURL(config portainer.ProxyConfig) (*url.URL, error) {
+	proxyURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid proxy URL")
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.Errorf("invalid proxy URL scheme %q, must be one of http, https or socks5", proxyURL.Scheme)
+	}
+
+	if proxyURL.Host == "" {
+		return nil, errors.New("invalid proxy URL, the host is missing")
+	}
+
+	if config.Username != "" {
+		proxyURL.User = url.UserPassword(config.Username, config.Password)
+	}
+
+	return proxyURL, nil
+}
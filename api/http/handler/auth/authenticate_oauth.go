@@ -3,9 +3,12 @@ package auth
 import (
 	"errors"
 	"net/http"
+	"regexp"
+	"slices"
 
 	portainer "github.com/portainer/portainer/api"
 	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 
@@ -25,21 +28,16 @@ func (payload *oauthPayload) Validate(r *http.Request) error {
 	return nil
 }
 
-func (handler *Handler) authenticateOAuth(code string, settings *portainer.OAuthSettings) (string, error) {
+func (handler *Handler) authenticateOAuth(code string, settings *portainer.OAuthSettings) (*portainer.OAuthInfo, error) {
 	if code == "" {
-		return "", errors.New("Invalid OAuth authorization code")
+		return nil, errors.New("Invalid OAuth authorization code")
 	}
 
 	if settings == nil {
-		return "", errors.New("Invalid OAuth configuration")
+		return nil, errors.New("Invalid OAuth configuration")
 	}
 
-	username, err := handler.OAuthService.Authenticate(code, settings)
-	if err != nil {
-		return "", err
-	}
-
-	return username, nil
+	return handler.OAuthService.Authenticate(code, settings)
 }
 
 // @id ValidateOAuth
@@ -70,25 +68,29 @@ func (handler *Handler) validateOAuth(w http.ResponseWriter, r *http.Request) *h
 		return httperror.Forbidden("OAuth authentication is not enabled", errors.New("OAuth authentication is not enabled"))
 	}
 
-	username, err := handler.authenticateOAuth(payload.Code, &settings.OAuthSettings)
+	info, err := handler.authenticateOAuth(payload.Code, &settings.OAuthSettings)
 	if err != nil {
 		log.Debug().Err(err).Msg("OAuth authentication error")
 
 		return httperror.InternalServerError("Unable to authenticate through OAuth", httperrors.ErrUnauthorized)
 	}
 
-	user, err := handler.DataStore.User().UserByUsername(username)
+	user, err := handler.DataStore.User().UserByUsername(info.Username)
 	if err != nil && !handler.DataStore.IsErrObjectNotFound(err) {
 		return httperror.InternalServerError("Unable to retrieve a user with the specified username from the database", err)
 	}
 
+	if user != nil && user.ServiceAccount {
+		return httperror.Forbidden("Service accounts can only authenticate with API keys", errServiceAccountLogin)
+	}
+
 	if user == nil && !settings.OAuthSettings.OAuthAutoCreateUsers {
 		return httperror.Forbidden("Account not created beforehand in Portainer and automatic user provisioning not enabled", httperrors.ErrUnauthorized)
 	}
 
 	if user == nil {
 		user = &portainer.User{
-			Username: username,
+			Username: info.Username,
 			Role:     portainer.StandardUserRole,
 		}
 
@@ -109,8 +111,149 @@ func (handler *Handler) validateOAuth(w http.ResponseWriter, r *http.Request) *h
 				return httperror.InternalServerError("Unable to persist team membership inside the database", err)
 			}
 		}
+	}
+
+	if err := handler.syncOAuthTeamMemberships(user, info.Groups, settings.OAuthSettings.ClaimMappings); err != nil {
+		return httperror.InternalServerError("Unable to update the team memberships of the user", err)
+	}
+
+	handler.storeOAuthRefreshToken(user.ID, info.RefreshToken)
+
+	return handler.writeToken(w, r, user, false, portainer.AuthenticationOAuth)
+}
+
+// syncOAuthTeamMemberships adds the user to the teams mapped to its groups and removes it from the mapped
+// teams it no longer belongs to. The memberships of the teams that are not part of any mapping are left untouched.
+func (handler *Handler) syncOAuthTeamMemberships(user *portainer.User, groups []string, mappings []portainer.OAuthClaimMapping) error {
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	// matched tells, for each mapped team, whether one of the groups of the user matches its mappings
+	matched := make(map[portainer.TeamID]bool)
+	for _, mapping := range mappings {
+		re, err := regexp.Compile(mapping.ClaimValRegex)
+		if err != nil {
+			log.Warn().Err(err).Str("regex", mapping.ClaimValRegex).Msg("ignoring invalid OAuth claim mapping")
+
+			continue
+		}
+
+		matched[mapping.Team] = matched[mapping.Team] || slices.ContainsFunc(groups, re.MatchString)
+	}
+
+	memberships, err := handler.DataStore.TeamMembership().TeamMembershipsByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, membership := range memberships {
+		if isMatched, isMapped := matched[membership.TeamID]; isMapped && !isMatched {
+			if err := handler.DataStore.TeamMembership().Delete(membership.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	for teamID, isMatched := range matched {
+		if !isMatched || slices.ContainsFunc(memberships, func(membership portainer.TeamMembership) bool {
+			return membership.TeamID == teamID
+		}) {
+			continue
+		}
+
+		if _, err := handler.DataStore.Team().Read(teamID); err != nil {
+			if handler.DataStore.IsErrObjectNotFound(err) {
+				log.Warn().Int("team_id", int(teamID)).Msg("ignoring OAuth claim mapping of a team that does not exist")
+
+				continue
+			}
+
+			return err
+		}
+
+		if err := handler.DataStore.TeamMembership().Create(&portainer.TeamMembership{
+			UserID: user.ID,
+			TeamID: teamID,
+			Role:   portainer.TeamMember,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// storeOAuthRefreshToken keeps the refresh token of the last OAuth session of the user in memory
+func (handler *Handler) storeOAuthRefreshToken(userID portainer.UserID, refreshToken string) {
+	if refreshToken == "" {
+		handler.oauthRefreshTokens.Delete(userID)
 
+		return
 	}
 
-	return handler.writeToken(w, user, false)
+	handler.oauthRefreshTokens.Store(userID, refreshToken)
+}
+
+// @id RefreshOAuth
+// @summary Refresh an OAuth session
+// @description Uses the refresh token obtained from the OAuth provider to issue a new token, the team memberships
+// @description of the user are updated from the claims returned by the provider.
+// @description **Access policy**: authenticated
+// @security ApiKeyAuth
+// @security jwt
+// @tags auth
+// @produce json
+// @success 200 {object} authenticateResponse "Success"
+// @failure 401 "No refresh token available or the provider rejected it"
+// @failure 403 "OAuth authentication is not enabled"
+// @failure 500 "Server error"
+// @router /auth/oauth/refresh [post]
+func (handler *Handler) refreshOAuth(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve user authentication token", err)
+	}
+
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve settings from the database", err)
+	}
+
+	if settings.AuthenticationMethod != portainer.AuthenticationOAuth {
+		return httperror.Forbidden("OAuth authentication is not enabled", errors.New("OAuth authentication is not enabled"))
+	}
+
+	refreshToken, ok := handler.oauthRefreshTokens.Load(tokenData.ID)
+	if !ok {
+		return httperror.Unauthorized("No OAuth refresh token available for the user", httperrors.ErrUnauthorized)
+	}
+
+	info, err := handler.OAuthService.Refresh(refreshToken.(string), &settings.OAuthSettings)
+	if err != nil {
+		log.Debug().Err(err).Msg("OAuth refresh error")
+
+		handler.oauthRefreshTokens.Delete(tokenData.ID)
+
+		return httperror.Unauthorized("Unable to refresh the OAuth session", httperrors.ErrUnauthorized)
+	}
+
+	user, err := handler.DataStore.User().Read(tokenData.ID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the user from the database", err)
+	}
+
+	if info.Username != user.Username {
+		handler.oauthRefreshTokens.Delete(tokenData.ID)
+
+		return httperror.Unauthorized("The OAuth session belongs to another user", httperrors.ErrUnauthorized)
+	}
+
+	if err := handler.syncOAuthTeamMemberships(user, info.Groups, settings.OAuthSettings.ClaimMappings); err != nil {
+		return httperror.InternalServerError("Unable to update the team memberships of the user", err)
+	}
+
+	handler.storeOAuthRefreshToken(user.ID, info.RefreshToken)
+
+	return handler.writeToken(w, r, user, false, portainer.AuthenticationOAuth)
 }
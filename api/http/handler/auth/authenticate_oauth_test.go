@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncOAuthTeamMemberships(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	user := &portainer.User{Username: "alice", Role: portainer.StandardUserRole}
+	require.NoError(t, store.User().Create(user))
+
+	dev, ops, other := &portainer.Team{Name: "dev"}, &portainer.Team{Name: "ops"}, &portainer.Team{Name: "other"}
+	for _, team := range []*portainer.Team{dev, ops, other} {
+		require.NoError(t, store.Team().Create(team))
+	}
+
+	// The user belongs to a team that is not mapped, this membership must be kept
+	require.NoError(t, store.TeamMembership().Create(&portainer.TeamMembership{UserID: user.ID, TeamID: other.ID, Role: portainer.TeamMember}))
+
+	handler := NewHandler(testhelpers.NewTestRequestBouncer(), security.NewRateLimiter(10, time.Second, time.Hour), nil)
+	handler.DataStore = store
+
+	mappings := []portainer.OAuthClaimMapping{
+		{ClaimValRegex: "^developers$", Team: dev.ID},
+		{ClaimValRegex: "^(ops|sre)$", Team: ops.ID},
+	}
+
+	teamIDs := func() []portainer.TeamID {
+		memberships, err := store.TeamMembership().TeamMembershipsByUserID(user.ID)
+		require.NoError(t, err)
+
+		ids := make([]portainer.TeamID, 0, len(memberships))
+		for _, membership := range memberships {
+			ids = append(ids, membership.TeamID)
+		}
+
+		return ids
+	}
+
+	require.NoError(t, handler.syncOAuthTeamMemberships(user, []string{"developers", "sre"}, mappings))
+	assert.ElementsMatch(t, []portainer.TeamID{other.ID, dev.ID, ops.ID}, teamIDs())
+
+	// Synchronizing again does not duplicate the memberships
+	require.NoError(t, handler.syncOAuthTeamMemberships(user, []string{"developers", "sre"}, mappings))
+	assert.ElementsMatch(t, []portainer.TeamID{other.ID, dev.ID, ops.ID}, teamIDs())
+
+	require.NoError(t, handler.syncOAuthTeamMemberships(user, []string{"sre"}, mappings))
+	assert.ElementsMatch(t, []portainer.TeamID{other.ID, ops.ID}, teamIDs())
+
+	// Without mappings, the memberships are left untouched
+	require.NoError(t, handler.syncOAuthTeamMemberships(user, nil, nil))
+	assert.ElementsMatch(t, []portainer.TeamID{other.ID, ops.ID}, teamIDs())
+}
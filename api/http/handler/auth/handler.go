@@ -1,7 +1,9 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
+	"sync"
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
@@ -13,6 +15,8 @@ import (
 	"github.com/gorilla/mux"
 )
 
+var errServiceAccountLogin = errors.New("service accounts can only authenticate with API keys")
+
 // Handler is the HTTP handler used to handle authentication operations.
 type Handler struct {
 	*mux.Router
@@ -25,6 +29,8 @@ type Handler struct {
 	KubernetesTokenCacheManager *kubernetes.TokenCacheManager
 	passwordStrengthChecker     security.PasswordStrengthChecker
 	bouncer                     security.BouncerService
+	// oauthRefreshTokens holds the refresh token of the last OAuth session of each user, by user ID
+	oauthRefreshTokens sync.Map
 }
 
 // NewHandler creates a handler to manage authentication operations.
@@ -37,6 +43,8 @@ func NewHandler(bouncer security.BouncerService, rateLimiter *security.RateLimit
 
 	h.Handle("/auth/oauth/validate",
 		rateLimiter.LimitAccess(bouncer.PublicAccess(httperror.LoggerHandler(h.validateOAuth)))).Methods(http.MethodPost)
+	h.Handle("/auth/oauth/refresh",
+		rateLimiter.LimitAccess(bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.refreshOAuth)))).Methods(http.MethodPost)
 	h.Handle("/auth",
 		rateLimiter.LimitAccess(bouncer.PublicAccess(httperror.LoggerHandler(h.authenticate)))).Methods(http.MethodPost)
 	h.Handle("/auth/logout",
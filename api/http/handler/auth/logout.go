@@ -7,6 +7,8 @@ import (
 	"github.com/portainer/portainer/api/logoutcontext"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/rs/zerolog/log"
 )
 
 // @id Logout
@@ -23,7 +25,12 @@ func (handler *Handler) logout(w http.ResponseWriter, r *http.Request) *httperro
 
 	if tokenData != nil {
 		handler.KubernetesTokenCacheManager.RemoveUserFromCache(tokenData.ID)
+		handler.oauthRefreshTokens.Delete(tokenData.ID)
 		logoutcontext.Cancel(tokenData.Token)
+
+		if err := handler.revokeSession(tokenData.Token); err != nil {
+			log.Warn().Err(err).Msg("unable to revoke the user session")
+		}
 	}
 
 	security.RemoveAuthCookie(w)
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// createSession records the session opened by the JWT issued to the user, the session lasts as long as the JWT
+func (handler *Handler) createSession(r *http.Request, userID portainer.UserID, token string) error {
+	_, tokenID, expiresAt, err := handler.JWTService.ParseAndVerifyToken(token)
+	if err != nil {
+		return err
+	}
+
+	session := &portainer.UserSession{
+		UserID:    userID,
+		TokenID:   tokenID,
+		IssuedAt:  time.Now().Unix(),
+		IP:        security.StripAddrPort(r.RemoteAddr),
+		UserAgent: r.UserAgent(),
+	}
+
+	if !expiresAt.IsZero() {
+		session.ExpiresAt = expiresAt.Unix()
+	}
+
+	var revoked []portainer.UserSession
+
+	// The session is created and the limit enforced in the same transaction so that concurrent
+	// authentications cannot exceed the limit
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := tx.UserSession().Create(session); err != nil {
+			return err
+		}
+
+		var err error
+		revoked, err = limitSessions(tx, userID)
+
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for i := range revoked {
+		handler.bouncer.RevokeSessionJWT(&revoked[i])
+	}
+
+	return nil
+}
+
+// limitSessions revokes the oldest open sessions of a user above the maximum number of concurrent sessions,
+// the revoked sessions are returned so that their JWT can be revoked once the transaction is committed
+func limitSessions(tx dataservices.DataStoreTx, userID portainer.UserID) ([]portainer.UserSession, error) {
+	settings, err := tx.Settings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	maxSessions := settings.UserSessionLimits.MaxConcurrentSessions
+	if maxSessions <= 0 {
+		return nil, nil
+	}
+
+	sessions, err := tx.UserSession().UserSessionsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+
+	openSessions := make([]portainer.UserSession, 0, len(sessions))
+	for _, session := range sessions {
+		if !session.Revoked && (session.ExpiresAt == 0 || session.ExpiresAt > now) {
+			openSessions = append(openSessions, session)
+		}
+	}
+
+	if len(openSessions) <= maxSessions {
+		return nil, nil
+	}
+
+	// Newest sessions first
+	slices.SortFunc(openSessions, func(a, b portainer.UserSession) int {
+		return cmp.Or(cmp.Compare(b.IssuedAt, a.IssuedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	revoked := openSessions[maxSessions:]
+	for i := range revoked {
+		revoked[i].RevokedReason = portainer.UserSessionRevokedSessionLimit
+
+		if err := security.RevokeSessionTx(tx, &revoked[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return revoked, nil
+}
+
+// revokeSession revokes the session opened by a JWT, if any
+func (handler *Handler) revokeSession(token string) error {
+	_, tokenID, _, err := handler.JWTService.ParseAndVerifyToken(token)
+	if err != nil {
+		// The JWT is no longer valid, there is nothing left to revoke
+		return nil
+	}
+
+	session, err := handler.DataStore.UserSession().UserSessionByTokenID(tokenID)
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return handler.bouncer.RevokeSession(session)
+}
@@ -0,0 +1,88 @@
+package azure
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/portainer/portainer/api/azure"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type containerExecPayload struct {
+	// Command to run in the container
+	Command string `validate:"required" example:"/bin/sh"`
+	// Size of the terminal, defaults to 24 rows and 80 columns
+	Rows int `example:"24"`
+	Cols int `example:"80"`
+}
+
+func (payload *containerExecPayload) Validate(r *http.Request) error {
+	if payload.Command == "" {
+		return errors.New("invalid command")
+	}
+
+	if payload.Rows < 0 || payload.Cols < 0 {
+		return errors.New("invalid terminal size")
+	}
+
+	if payload.Rows == 0 {
+		payload.Rows = 24
+	}
+
+	if payload.Cols == 0 {
+		payload.Cols = 80
+	}
+
+	return nil
+}
+
+// @id AzureContainerExec
+// @summary Run a command in a container
+// @description Run a command in a container of a container group. The client interacts with the command through
+// @description the returned websocket, the password must be sent as the first message on the websocket.
+// @description **Access policy**: authenticated
+// @tags azure
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param subscriptionId path string true "Subscription identifier"
+// @param resourceGroup path string true "Resource group"
+// @param name path string true "Container group name"
+// @param container path string true "Container name"
+// @param body body containerExecPayload true "Command"
+// @success 200 {object} azure.ExecResponse "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Container not found"
+// @failure 500 "Server error"
+// @router /azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups/{name}/containers/{container}/exec [post]
+func (handler *Handler) containerExec(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	container, err := request.RetrieveRouteVariableValue(r, "container")
+	if err != nil {
+		return httperror.BadRequest("Invalid container route variable", err)
+	}
+
+	var payload containerExecPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	client, id, _, httpErr := handler.retrieveContainerGroup(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	exec, err := client.ExecContainer(r.Context(), id, container, azure.ExecRequest{
+		Command:      payload.Command,
+		TerminalSize: azure.TerminalSize{Rows: payload.Rows, Cols: payload.Cols},
+	})
+	if err != nil {
+		return azureError("Unable to run the command in the container", err)
+	}
+
+	return response.JSON(w, exec)
+}
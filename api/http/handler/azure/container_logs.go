@@ -0,0 +1,60 @@
+package azure
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type containerLogsResponse struct {
+	Content string `json:"Content"`
+}
+
+// @id AzureContainerLogs
+// @summary Retrieve the logs of a container
+// @description Retrieve the logs of a container of a container group.
+// @description **Access policy**: authenticated
+// @tags azure
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param subscriptionId path string true "Subscription identifier"
+// @param resourceGroup path string true "Resource group"
+// @param name path string true "Container group name"
+// @param container path string true "Container name"
+// @param tail query int false "Only return this number of lines from the end of the logs"
+// @param timestamps query bool false "Prefix each line with its timestamp"
+// @success 200 {object} containerLogsResponse "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Container not found"
+// @failure 500 "Server error"
+// @router /azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups/{name}/containers/{container}/logs [get]
+func (handler *Handler) containerLogs(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	container, err := request.RetrieveRouteVariableValue(r, "container")
+	if err != nil {
+		return httperror.BadRequest("Invalid container route variable", err)
+	}
+
+	tail, err := request.RetrieveNumericQueryParameter(r, "tail", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: tail", err)
+	}
+
+	timestamps, _ := request.RetrieveBooleanQueryParameter(r, "timestamps", true)
+
+	client, id, _, httpErr := handler.retrieveContainerGroup(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	logs, err := client.ContainerLogs(r.Context(), id, container, tail, timestamps)
+	if err != nil {
+		return azureError("Unable to retrieve the logs of the container", err)
+	}
+
+	return response.JSON(w, containerLogsResponse{Content: logs})
+}
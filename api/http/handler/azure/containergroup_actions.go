@@ -0,0 +1,98 @@
+package azure
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id AzureContainerGroupStart
+// @summary Start a container group
+// @description Start the containers of a stopped container group.
+// @description **Access policy**: authenticated
+// @tags azure
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Environment(Endpoint) identifier"
+// @param subscriptionId path string true "Subscription identifier"
+// @param resourceGroup path string true "Resource group"
+// @param name path string true "Container group name"
+// @success 204 "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Container group not found"
+// @failure 500 "Server error"
+// @router /azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups/{name}/start [post]
+func (handler *Handler) containerGroupStart(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	client, id, _, httpErr := handler.retrieveContainerGroup(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if err := client.StartContainerGroup(r.Context(), id); err != nil {
+		return azureError("Unable to start the container group", err)
+	}
+
+	return response.Empty(w)
+}
+
+// @id AzureContainerGroupRestart
+// @summary Restart a container group
+// @description Restart the containers of a container group in place.
+// @description **Access policy**: authenticated
+// @tags azure
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Environment(Endpoint) identifier"
+// @param subscriptionId path string true "Subscription identifier"
+// @param resourceGroup path string true "Resource group"
+// @param name path string true "Container group name"
+// @success 204 "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Container group not found"
+// @failure 500 "Server error"
+// @router /azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups/{name}/restart [post]
+func (handler *Handler) containerGroupRestart(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	client, id, _, httpErr := handler.retrieveContainerGroup(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if err := client.RestartContainerGroup(r.Context(), id); err != nil {
+		return azureError("Unable to restart the container group", err)
+	}
+
+	return response.Empty(w)
+}
+
+// @id AzureContainerGroupStop
+// @summary Stop a container group
+// @description Stop the containers of a container group, its compute resources are released.
+// @description **Access policy**: authenticated
+// @tags azure
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Environment(Endpoint) identifier"
+// @param subscriptionId path string true "Subscription identifier"
+// @param resourceGroup path string true "Resource group"
+// @param name path string true "Container group name"
+// @success 204 "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Container group not found"
+// @failure 500 "Server error"
+// @router /azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups/{name}/stop [post]
+func (handler *Handler) containerGroupStop(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	client, id, _, httpErr := handler.retrieveContainerGroup(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if err := client.StopContainerGroup(r.Context(), id); err != nil {
+		return azureError("Unable to stop the container group", err)
+	}
+
+	return response.Empty(w)
+}
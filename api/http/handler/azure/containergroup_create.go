@@ -0,0 +1,241 @@
+package azure
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/azure"
+	"github.com/portainer/portainer/api/http/middlewares"
+	"github.com/portainer/portainer/api/internal/authorization"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// namePattern matches the names accepted by Azure for the container groups and their containers
+var namePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+var errContainerGroupExists = errors.New("a container group with the same name already exists inside the resource group")
+
+type containerGroupCreatePayload struct {
+	// Name of the container group
+	Name string `validate:"required" example:"nginx"`
+	// Azure region of the container group
+	Location string `validate:"required" example:"westeurope"`
+	// Operating system of the containers, Linux or Windows. Defaults to Linux
+	OSType string `example:"Linux" enums:"Linux,Windows"`
+	// Restart policy of the containers, Always, OnFailure or Never. Defaults to Always
+	RestartPolicy string `example:"Always" enums:"Always,OnFailure,Never"`
+	// Whether the ports of the containers are exposed on a public IP address
+	PublicIP   bool `example:"true"`
+	Containers []containerPayload
+}
+
+type containerPayload struct {
+	Name    string   `validate:"required" example:"web"`
+	Image   string   `validate:"required" example:"nginx:latest"`
+	Command []string `example:"nginx,-g,daemon off;"`
+	// Number of CPU cores requested by the container
+	CPU float64 `validate:"required" example:"1"`
+	// Memory requested by the container in GB
+	MemoryInGB float64 `validate:"required" example:"1.5"`
+	Ports      []portPayload
+	Env        []envPayload
+}
+
+type portPayload struct {
+	Port int `validate:"required" example:"80"`
+	// TCP or UDP, defaults to TCP
+	Protocol string `example:"TCP" enums:"TCP,UDP"`
+}
+
+type envPayload struct {
+	Name  string `validate:"required" example:"MODE"`
+	Value string `example:"production"`
+	// Whether the value is a secret, its value is not returned by Azure
+	Secure bool `example:"false"`
+}
+
+func (payload *containerGroupCreatePayload) Validate(r *http.Request) error {
+	if !namePattern.MatchString(payload.Name) {
+		return errors.New("invalid container group name, it must be made of lowercase letters, digits and dashes")
+	}
+
+	if payload.Location == "" {
+		return errors.New("invalid location")
+	}
+
+	if payload.OSType == "" {
+		payload.OSType = "Linux"
+	} else if payload.OSType != "Linux" && payload.OSType != "Windows" {
+		return errors.New("invalid operating system, it must be Linux or Windows")
+	}
+
+	if payload.RestartPolicy == "" {
+		payload.RestartPolicy = "Always"
+	} else if !slices.Contains([]string{"Always", "OnFailure", "Never"}, payload.RestartPolicy) {
+		return errors.New("invalid restart policy, it must be Always, OnFailure or Never")
+	}
+
+	if len(payload.Containers) == 0 {
+		return errors.New("at least one container is required")
+	}
+
+	names := make(map[string]bool)
+	for i := range payload.Containers {
+		container := &payload.Containers[i]
+
+		if !namePattern.MatchString(container.Name) || names[container.Name] {
+			return fmt.Errorf("invalid or duplicate container name: %q", container.Name)
+		}
+		names[container.Name] = true
+
+		if container.Image == "" {
+			return fmt.Errorf("invalid image for container %q", container.Name)
+		}
+
+		if container.CPU <= 0 || container.MemoryInGB <= 0 {
+			return fmt.Errorf("invalid resources for container %q, the CPU and the memory must be positive", container.Name)
+		}
+
+		for j := range container.Ports {
+			port := &container.Ports[j]
+
+			if port.Port < 1 || port.Port > 65535 {
+				return fmt.Errorf("invalid port %d for container %q", port.Port, container.Name)
+			}
+
+			if port.Protocol == "" {
+				port.Protocol = "TCP"
+			} else if port.Protocol != "TCP" && port.Protocol != "UDP" {
+				return fmt.Errorf("invalid protocol %q for container %q, it must be TCP or UDP", port.Protocol, container.Name)
+			}
+		}
+
+		for _, env := range container.Env {
+			if env.Name == "" {
+				return fmt.Errorf("invalid environment variable name for container %q", container.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// containerGroup returns the container group described by the payload
+func (payload *containerGroupCreatePayload) containerGroup() *azure.ContainerGroup {
+	group := &azure.ContainerGroup{
+		Location: payload.Location,
+		Properties: azure.ContainerGroupProperties{
+			Containers:    []azure.Container{},
+			OSType:        payload.OSType,
+			RestartPolicy: payload.RestartPolicy,
+		},
+	}
+
+	var exposedPorts []azure.Port
+	for _, container := range payload.Containers {
+		properties := azure.ContainerProperties{
+			Image:     container.Image,
+			Command:   container.Command,
+			Resources: azure.ResourceRequirements{Requests: azure.ResourceRequests{CPU: container.CPU, MemoryInGB: container.MemoryInGB}},
+		}
+
+		for _, port := range container.Ports {
+			properties.Ports = append(properties.Ports, azure.Port{Port: port.Port, Protocol: port.Protocol})
+			exposedPorts = append(exposedPorts, azure.Port{Port: port.Port, Protocol: port.Protocol})
+		}
+
+		for _, env := range container.Env {
+			variable := azure.EnvironmentVariable{Name: env.Name, Value: env.Value}
+			if env.Secure {
+				variable = azure.EnvironmentVariable{Name: env.Name, SecureValue: env.Value}
+			}
+
+			properties.EnvironmentVariables = append(properties.EnvironmentVariables, variable)
+		}
+
+		group.Properties.Containers = append(group.Properties.Containers, azure.Container{Name: container.Name, Properties: properties})
+	}
+
+	if payload.PublicIP && len(exposedPorts) > 0 {
+		group.Properties.IPAddress = &azure.IPAddress{Type: "Public", Ports: exposedPorts}
+	}
+
+	return group
+}
+
+// @id AzureContainerGroupCreate
+// @summary Create a container group
+// @description Create a container group in a resource group, its containers are started once they are provisioned.
+// @description The container group is private to the user who created it.
+// @description **Access policy**: authenticated
+// @tags azure
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param subscriptionId path string true "Subscription identifier"
+// @param resourceGroup path string true "Resource group"
+// @param body body containerGroupCreatePayload true "Container group details"
+// @success 200 {object} containerGroupResponse "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 409 "A container group with the same name already exists"
+// @failure 500 "Server error"
+// @router /azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups [post]
+func (handler *Handler) containerGroupCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload containerGroupCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	endpoint, err := middlewares.FetchEndpoint(r)
+	if err != nil {
+		return httperror.NotFound("Unable to find an environment on request context", err)
+	}
+
+	id, httpErr := containerGroupIDFromRequest(r)
+	if httpErr != nil {
+		return httpErr
+	}
+	id.Name = payload.Name
+
+	context, httpErr := handler.accessContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	client := handler.clientFactory.GetClient(endpoint)
+
+	// A PUT replaces an existing container group, it would hand over a container group the user may not have access to
+	if _, err := client.ContainerGroup(r.Context(), id); err == nil {
+		return httperror.Conflict("A container group with the same name already exists inside the resource group", errContainerGroupExists)
+	} else if !azure.IsNotFound(err) {
+		return azureError("Unable to check the existence of the container group", err)
+	}
+
+	group, err := client.CreateContainerGroup(r.Context(), id, payload.containerGroup())
+	if err != nil {
+		return azureError("Unable to create the container group", err)
+	}
+
+	resourceID := group.ID
+	if resourceID == "" {
+		resourceID = id.String()
+	}
+
+	resourceControl := authorization.NewPrivateResourceControl(resourceID, portainer.ContainerGroupResourceControl, context.userID)
+	if err := handler.dataStore.ResourceControl().Create(resourceControl); err != nil {
+		return httperror.InternalServerError("Unable to persist the resource control of the container group", err)
+	}
+
+	context.resourceControls = append(context.resourceControls, *resourceControl)
+
+	return response.JSON(w, context.decorate(group))
+}
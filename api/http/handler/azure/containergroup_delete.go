@@ -0,0 +1,47 @@
+package azure
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api/azure"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/rs/zerolog/log"
+)
+
+// @id AzureContainerGroupDelete
+// @summary Delete a container group
+// @description Delete a container group along with its containers and its resource control.
+// @description **Access policy**: authenticated
+// @tags azure
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Environment(Endpoint) identifier"
+// @param subscriptionId path string true "Subscription identifier"
+// @param resourceGroup path string true "Resource group"
+// @param name path string true "Container group name"
+// @success 204 "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Container group not found"
+// @failure 500 "Server error"
+// @router /azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups/{name} [delete]
+func (handler *Handler) containerGroupDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	client, id, context, httpErr := handler.retrieveContainerGroup(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if err := client.DeleteContainerGroup(r.Context(), id); err != nil && !azure.IsNotFound(err) {
+		return azureError("Unable to delete the container group", err)
+	}
+
+	if resourceControl := context.resourceControl(id.String()); resourceControl != nil {
+		if err := handler.dataStore.ResourceControl().Delete(resourceControl.ID); err != nil {
+			log.Warn().Err(err).Str("container_group", id.String()).Msg("unable to remove the resource control of the container group")
+		}
+	}
+
+	return response.Empty(w)
+}
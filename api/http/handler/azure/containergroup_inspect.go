@@ -0,0 +1,40 @@
+package azure
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id AzureContainerGroupInspect
+// @summary Inspect a container group
+// @description Retrieve a container group along with the state of its containers.
+// @description **Access policy**: authenticated
+// @tags azure
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param subscriptionId path string true "Subscription identifier"
+// @param resourceGroup path string true "Resource group"
+// @param name path string true "Container group name"
+// @success 200 {object} containerGroupResponse "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Container group not found"
+// @failure 500 "Server error"
+// @router /azure/{id}/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups/{name} [get]
+func (handler *Handler) containerGroupInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	client, id, context, httpErr := handler.retrieveContainerGroup(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	group, err := client.ContainerGroup(r.Context(), id)
+	if err != nil {
+		return azureError("Unable to retrieve the container group", err)
+	}
+
+	return response.JSON(w, context.decorate(group))
+}
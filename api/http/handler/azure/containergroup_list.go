@@ -0,0 +1,58 @@
+package azure
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api/http/middlewares"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id AzureContainerGroupList
+// @summary List the container groups of a subscription
+// @description List the container groups of an Azure subscription, decorated with their resource control.
+// @description Non-administrator users only see the container groups they have access to.
+// @description **Access policy**: authenticated
+// @tags azure
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param subscriptionId path string true "Subscription identifier"
+// @success 200 {array} containerGroupResponse "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /azure/{id}/subscriptions/{subscriptionId}/container_groups [get]
+func (handler *Handler) containerGroupList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	subscriptionID, err := request.RetrieveRouteVariableValue(r, "subscriptionId")
+	if err != nil {
+		return httperror.BadRequest("Invalid subscription identifier route variable", err)
+	}
+
+	endpoint, err := middlewares.FetchEndpoint(r)
+	if err != nil {
+		return httperror.NotFound("Unable to find an environment on request context", err)
+	}
+
+	context, httpErr := handler.accessContext(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	groups, err := handler.clientFactory.GetClient(endpoint).ContainerGroups(r.Context(), subscriptionID)
+	if err != nil {
+		return azureError("Unable to retrieve the container groups", err)
+	}
+
+	filtered := make([]containerGroupResponse, 0, len(groups))
+	for i := range groups {
+		if context.canAccess(groups[i].ID) {
+			filtered = append(filtered, context.decorate(&groups[i]))
+		}
+	}
+
+	return response.JSON(w, filtered)
+}
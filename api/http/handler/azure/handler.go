@@ -0,0 +1,70 @@
+package azure
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/azure"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/middlewares"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is the HTTP handler used to manage the container instances of the Azure environments(endpoints)
+type Handler struct {
+	*mux.Router
+	requestBouncer security.BouncerService
+	dataStore      dataservices.DataStore
+	clientFactory  *azure.ClientFactory
+}
+
+// NewHandler creates a handler to process non-proxied requests to the Azure API
+func NewHandler(bouncer security.BouncerService, dataStore dataservices.DataStore, clientFactory *azure.ClientFactory) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		requestBouncer: bouncer,
+		dataStore:      dataStore,
+		clientFactory:  clientFactory,
+	}
+
+	endpointRouter := h.PathPrefix("/azure/{id}").Subrouter()
+	endpointRouter.Use(bouncer.AuthenticatedAccess)
+	endpointRouter.Use(middlewares.WithEndpoint(dataStore.Endpoint(), "id"), azureOnlyMiddleware, middlewares.CheckEndpointAuthorization(bouncer))
+
+	endpointRouter.Handle("/subscriptions", httperror.LoggerHandler(h.subscriptionList)).Methods(http.MethodGet)
+	endpointRouter.Handle("/subscriptions/{subscriptionId}/container_groups", httperror.LoggerHandler(h.containerGroupList)).Methods(http.MethodGet)
+
+	groupsRouter := endpointRouter.PathPrefix("/subscriptions/{subscriptionId}/resource_groups/{resourceGroup}/container_groups").Subrouter()
+	groupsRouter.Handle("", httperror.LoggerHandler(h.containerGroupCreate)).Methods(http.MethodPost)
+	groupsRouter.Handle("/{name}", httperror.LoggerHandler(h.containerGroupInspect)).Methods(http.MethodGet)
+	groupsRouter.Handle("/{name}", httperror.LoggerHandler(h.containerGroupDelete)).Methods(http.MethodDelete)
+	groupsRouter.Handle("/{name}/start", httperror.LoggerHandler(h.containerGroupStart)).Methods(http.MethodPost)
+	groupsRouter.Handle("/{name}/restart", httperror.LoggerHandler(h.containerGroupRestart)).Methods(http.MethodPost)
+	groupsRouter.Handle("/{name}/stop", httperror.LoggerHandler(h.containerGroupStop)).Methods(http.MethodPost)
+	groupsRouter.Handle("/{name}/containers/{container}/logs", httperror.LoggerHandler(h.containerLogs)).Methods(http.MethodGet)
+	groupsRouter.Handle("/{name}/containers/{container}/exec", httperror.LoggerHandler(h.containerExec)).Methods(http.MethodPost)
+
+	return h
+}
+
+func azureOnlyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		endpoint, err := middlewares.FetchEndpoint(request)
+		if err != nil {
+			httperror.WriteError(rw, http.StatusInternalServerError, "Unable to find an environment on request context", err)
+			return
+		}
+
+		if endpoint.Type != portainer.AzureEnvironment {
+			errMessage := "environment is not an Azure environment"
+			httperror.WriteError(rw, http.StatusBadRequest, errMessage, errors.New(errMessage))
+			return
+		}
+
+		next.ServeHTTP(rw, request)
+	})
+}
@@ -0,0 +1,38 @@
+package azure
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api/http/middlewares"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id AzureSubscriptionList
+// @summary List the Azure subscriptions
+// @description List the subscriptions the credentials of an Azure environment(endpoint) have access to.
+// @description **Access policy**: authenticated
+// @tags azure
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @success 200 {array} azure.Subscription "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /azure/{id}/subscriptions [get]
+func (handler *Handler) subscriptionList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, err := middlewares.FetchEndpoint(r)
+	if err != nil {
+		return httperror.NotFound("Unable to find an environment on request context", err)
+	}
+
+	subscriptions, err := handler.clientFactory.GetClient(endpoint).Subscriptions(r.Context())
+	if err != nil {
+		return azureError("Unable to retrieve the Azure subscriptions", err)
+	}
+
+	return response.JSON(w, subscriptions)
+}
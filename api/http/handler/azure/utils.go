@@ -0,0 +1,153 @@
+package azure
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/azure"
+	"github.com/portainer/portainer/api/http/middlewares"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+var errContainerGroupAccessDenied = errors.New("access denied to the container group")
+
+// containerGroupResponse is a container group decorated with its resource control
+type containerGroupResponse struct {
+	azure.ContainerGroup
+	Portainer *portainerMetadata `json:"Portainer,omitempty"`
+}
+
+type portainerMetadata struct {
+	ResourceControl *portainer.ResourceControl `json:"ResourceControl"`
+}
+
+// accessContext holds what is needed to check the access of a user to the container groups
+type accessContext struct {
+	isAdmin          bool
+	userID           portainer.UserID
+	teamIDs          []portainer.TeamID
+	resourceControls []portainer.ResourceControl
+}
+
+func (handler *Handler) accessContext(r *http.Request) (*accessContext, *httperror.HandlerError) {
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve info from request context", err)
+	}
+
+	resourceControls, err := handler.dataStore.ResourceControl().ReadAll()
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve the resource controls from the database", err)
+	}
+
+	context := &accessContext{
+		isAdmin:          securityContext.IsAdmin,
+		userID:           securityContext.UserID,
+		resourceControls: resourceControls,
+	}
+
+	for _, membership := range securityContext.UserMemberships {
+		context.teamIDs = append(context.teamIDs, membership.TeamID)
+	}
+
+	return context, nil
+}
+
+// resourceControl returns the resource control of a container group. The identifiers are compared without case
+// as Azure does not preserve the case of the resource groups in the identifiers it returns
+func (context *accessContext) resourceControl(containerGroupID string) *portainer.ResourceControl {
+	for i := range context.resourceControls {
+		resourceControl := &context.resourceControls[i]
+		if resourceControl.Type == portainer.ContainerGroupResourceControl && strings.EqualFold(resourceControl.ResourceID, containerGroupID) {
+			return resourceControl
+		}
+	}
+
+	return nil
+}
+
+// canAccess returns true when the user can access the container group, the container groups without
+// resource control are only accessible to the administrators
+func (context *accessContext) canAccess(containerGroupID string) bool {
+	if context.isAdmin {
+		return true
+	}
+
+	resourceControl := context.resourceControl(containerGroupID)
+
+	return resourceControl != nil && authorization.UserCanAccessResource(context.userID, context.teamIDs, resourceControl)
+}
+
+func (context *accessContext) decorate(group *azure.ContainerGroup) containerGroupResponse {
+	response := containerGroupResponse{ContainerGroup: *group}
+	if resourceControl := context.resourceControl(group.ID); resourceControl != nil {
+		response.Portainer = &portainerMetadata{ResourceControl: resourceControl}
+	}
+
+	return response
+}
+
+// retrieveContainerGroup returns the client of the environment and the identifier of the container group
+// targeted by the request, after checking that the user can access the container group
+func (handler *Handler) retrieveContainerGroup(r *http.Request) (*azure.Client, azure.ContainerGroupID, *accessContext, *httperror.HandlerError) {
+	endpoint, err := middlewares.FetchEndpoint(r)
+	if err != nil {
+		return nil, azure.ContainerGroupID{}, nil, httperror.NotFound("Unable to find an environment on request context", err)
+	}
+
+	id, httpErr := containerGroupIDFromRequest(r)
+	if httpErr != nil {
+		return nil, azure.ContainerGroupID{}, nil, httpErr
+	}
+
+	context, httpErr := handler.accessContext(r)
+	if httpErr != nil {
+		return nil, azure.ContainerGroupID{}, nil, httpErr
+	}
+
+	if !context.canAccess(id.String()) {
+		return nil, azure.ContainerGroupID{}, nil, httperror.Forbidden("Permission denied to access the container group", errContainerGroupAccessDenied)
+	}
+
+	return handler.clientFactory.GetClient(endpoint), id, context, nil
+}
+
+func containerGroupIDFromRequest(r *http.Request) (azure.ContainerGroupID, *httperror.HandlerError) {
+	subscriptionID, err := request.RetrieveRouteVariableValue(r, "subscriptionId")
+	if err != nil {
+		return azure.ContainerGroupID{}, httperror.BadRequest("Invalid subscription identifier route variable", err)
+	}
+
+	resourceGroup, err := request.RetrieveRouteVariableValue(r, "resourceGroup")
+	if err != nil {
+		return azure.ContainerGroupID{}, httperror.BadRequest("Invalid resource group route variable", err)
+	}
+
+	name, _ := request.RetrieveRouteVariableValue(r, "name")
+
+	return azure.ContainerGroupID{SubscriptionID: subscriptionID, ResourceGroup: resourceGroup, Name: name}, nil
+}
+
+// azureError converts an error of the Azure API into an HTTP error
+func azureError(message string, err error) *httperror.HandlerError {
+	var apiErr *azure.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusNotFound:
+			return httperror.NotFound(message, err)
+		case http.StatusBadRequest:
+			return httperror.BadRequest(message, err)
+		case http.StatusConflict:
+			return httperror.Conflict(message, err)
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return httperror.Forbidden(message, err)
+		}
+	}
+
+	return httperror.InternalServerError(message, err)
+}
@@ -0,0 +1,159 @@
+package cloudprovisionings
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// resourceNamePattern matches the names accepted by all the providers for the clusters and the virtual machines
+var resourceNamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,30}[a-z0-9])?$`)
+
+type cloudProvisioningCreatePayload struct {
+	Provider portainer.CloudProvider `example:"digitalocean" enums:"digitalocean,civo,linode" validate:"required"`
+	// Kind of environment to create
+	Type portainer.CloudProvisioningType `example:"kubernetes" enums:"kubernetes,docker" validate:"required"`
+	// Name of the environment and of the resources created at the provider
+	Name   string `example:"production" validate:"required"`
+	Region string `example:"fra1" validate:"required"`
+	// Size of the nodes or of the virtual machine, as named by the provider
+	NodeSize string `example:"s-2vcpu-4gb" validate:"required"`
+	// Number of nodes of the Kubernetes cluster
+	NodeCount int `example:"3"`
+	// Kubernetes version of the cluster, the default version of the provider is used when empty
+	KubernetesVersion string `example:"1.29.1-do.0"`
+	// Group of the registered environment, the unassigned group is used when empty
+	GroupID portainer.EndpointGroupID `example:"1"`
+	TagIDs  []portainer.TagID
+	// API token of the provider, it is only kept in memory during the provisioning
+	APIToken string `validate:"required"`
+}
+
+func (payload *cloudProvisioningCreatePayload) Validate(r *http.Request) error {
+	switch payload.Provider {
+	case portainer.CloudProviderDigitalOcean, portainer.CloudProviderCivo, portainer.CloudProviderLinode:
+	default:
+		return errors.New("invalid provider, must be one of digitalocean, civo or linode")
+	}
+
+	switch payload.Type {
+	case portainer.CloudProvisioningKubernetes:
+		if payload.NodeCount <= 0 {
+			return errors.New("invalid node count, must be a positive number")
+		}
+	case portainer.CloudProvisioningDocker:
+		payload.NodeCount = 0
+		payload.KubernetesVersion = ""
+	default:
+		return errors.New("invalid type, must be kubernetes or docker")
+	}
+
+	if !resourceNamePattern.MatchString(payload.Name) {
+		return errors.New("invalid name, must be made of up to 32 lowercase letters, digits and dashes")
+	}
+
+	if payload.Region == "" {
+		return errors.New("invalid region")
+	}
+
+	if payload.NodeSize == "" {
+		return errors.New("invalid node size")
+	}
+
+	if payload.APIToken == "" {
+		return errors.New("invalid API token")
+	}
+
+	if payload.GroupID == 0 {
+		payload.GroupID = 1
+	}
+
+	return nil
+}
+
+// @id CloudProvisioningCreate
+// @summary Provision an environment on a cloud provider
+// @description Create a managed Kubernetes cluster or a virtual machine running Docker on a cloud provider, deploy
+// @description the agent on it and register it as an environment once the agent answers. The provisioning runs
+// @description in the background, its progress is retrieved with the inspect operation.
+// @description **Access policy**: administrator
+// @tags cloud_provisionings
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body cloudProvisioningCreatePayload true "Provisioning details"
+// @success 200 {object} portainer.CloudProvisioning
+// @failure 400
+// @failure 500
+// @router /cloud_provisionings [post]
+func (handler *Handler) cloudProvisioningCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload cloudProvisioningCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve user details from authentication token", err)
+	}
+
+	if err := handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		return validateGroupAndTags(tx, payload.GroupID, payload.TagIDs)
+	}); err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	provisioning := &portainer.CloudProvisioning{
+		Provider:          payload.Provider,
+		Type:              payload.Type,
+		Name:              payload.Name,
+		Region:            payload.Region,
+		NodeSize:          payload.NodeSize,
+		NodeCount:         payload.NodeCount,
+		KubernetesVersion: payload.KubernetesVersion,
+		GroupID:           payload.GroupID,
+		TagIDs:            payload.TagIDs,
+		CreatedBy:         tokenData.ID,
+	}
+
+	if provisioning.TagIDs == nil {
+		provisioning.TagIDs = []portainer.TagID{}
+	}
+
+	if err := handler.CloudService.Provision(provisioning, payload.APIToken); err != nil {
+		return httperror.InternalServerError("Unable to start the provisioning", err)
+	}
+
+	return response.JSON(w, provisioning)
+}
+
+func validateGroupAndTags(tx dataservices.DataStoreTx, groupID portainer.EndpointGroupID, tagIDs []portainer.TagID) error {
+	if _, err := tx.EndpointGroup().Read(groupID); tx.IsErrObjectNotFound(err) {
+		return httperror.BadRequest("Unable to find an environment group with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an environment group with the specified identifier inside the database", err)
+	}
+
+	for _, tagID := range tagIDs {
+		if _, err := tx.Tag().Read(tagID); tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find a tag with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a tag with the specified identifier inside the database", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package cloudprovisionings
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id CloudProvisioningDelete
+// @summary Delete a cloud provisioning
+// @description Remove a finished provisioning from the history. The resources created at the provider and
+// @description the registered environment are left untouched.
+// @description **Access policy**: administrator
+// @tags cloud_provisionings
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Cloud provisioning identifier"
+// @success 204
+// @failure 400
+// @failure 404
+// @failure 409 "The provisioning is still running"
+// @failure 500
+// @router /cloud_provisionings/{id} [delete]
+func (handler *Handler) cloudProvisioningDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	provisioningID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid cloud provisioning identifier route variable", err)
+	}
+
+	id := portainer.CloudProvisioningID(provisioningID)
+
+	provisioning, err := handler.DataStore.CloudProvisioning().Read(id)
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a cloud provisioning with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a cloud provisioning with the specified identifier inside the database", err)
+	}
+
+	if provisioning.Status != portainer.CloudProvisioningReady && provisioning.Status != portainer.CloudProvisioningFailed {
+		return httperror.Conflict("Unable to delete the cloud provisioning", errors.New("the provisioning is still running"))
+	}
+
+	if err := handler.DataStore.CloudProvisioning().Delete(id); err != nil {
+		return httperror.InternalServerError("Unable to remove the cloud provisioning from the database", err)
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,40 @@
+package cloudprovisionings
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id CloudProvisioningInspect
+// @summary Inspect a cloud provisioning
+// @description Retrieve the progress of a provisioning, the environment is registered once its status is ready.
+// @description **Access policy**: administrator
+// @tags cloud_provisionings
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Cloud provisioning identifier"
+// @success 200 {object} portainer.CloudProvisioning
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /cloud_provisionings/{id} [get]
+func (handler *Handler) cloudProvisioningInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	provisioningID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid cloud provisioning identifier route variable", err)
+	}
+
+	provisioning, err := handler.DataStore.CloudProvisioning().Read(portainer.CloudProvisioningID(provisioningID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a cloud provisioning with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a cloud provisioning with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, provisioning)
+}
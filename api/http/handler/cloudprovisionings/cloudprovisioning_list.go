@@ -0,0 +1,27 @@
+package cloudprovisionings
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id CloudProvisioningList
+// @summary List the cloud provisionings
+// @description **Access policy**: administrator
+// @tags cloud_provisionings
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.CloudProvisioning
+// @failure 500
+// @router /cloud_provisionings [get]
+func (handler *Handler) cloudProvisioningList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	provisionings, err := handler.DataStore.CloudProvisioning().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the cloud provisionings from the database", err)
+	}
+
+	return response.JSON(w, provisionings)
+}
@@ -0,0 +1,37 @@
+package cloudprovisionings
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api/cloud"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is the HTTP handler used to handle the provisioning of environments on cloud providers.
+type Handler struct {
+	*mux.Router
+	DataStore    dataservices.DataStore
+	CloudService *cloud.Service
+}
+
+// NewHandler creates a handler to manage the provisioning of environments on cloud providers.
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+
+	h.Handle("/cloud_provisionings",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.cloudProvisioningList))).Methods(http.MethodGet)
+	h.Handle("/cloud_provisionings",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.cloudProvisioningCreate))).Methods(http.MethodPost)
+	h.Handle("/cloud_provisionings/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.cloudProvisioningInspect))).Methods(http.MethodGet)
+	h.Handle("/cloud_provisionings/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.cloudProvisioningDelete))).Methods(http.MethodDelete)
+
+	return h
+}
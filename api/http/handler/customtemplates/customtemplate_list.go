@@ -26,6 +26,7 @@ import (
 // @produce json
 // @param type query []int true "Template types" Enums(1,2,3)
 // @param edge query boolean false "Filter by edge templates"
+// @param approved query boolean false "Filter by approved templates"
 // @success 200 {array} portainer.CustomTemplate "Success"
 // @failure 500 "Server error"
 // @router /custom_templates [get]
@@ -35,7 +36,8 @@ func (handler *Handler) customTemplateList(w http.ResponseWriter, r *http.Reques
 		return httperror.BadRequest("Invalid Custom template type", err)
 	}
 
-	edge := retrieveEdgeParam(r)
+	edge := retrieveBoolParam(r, "edge")
+	approved := retrieveBoolParam(r, "approved")
 
 	customTemplates, err := handler.DataStore.CustomTemplate().ReadAll()
 	if err != nil {
@@ -76,29 +78,28 @@ func (handler *Handler) customTemplateList(w http.ResponseWriter, r *http.Reques
 		})
 	}
 
-	for i := range customTemplates {
-		customTemplate := &customTemplates[i]
-		if customTemplate.GitConfig != nil && customTemplate.GitConfig.Authentication != nil {
-			customTemplate.GitConfig.Authentication.Password = ""
-		}
+	if approved != nil {
+		customTemplates = slicesx.Filter(customTemplates, func(customTemplate portainer.CustomTemplate) bool {
+			return customTemplate.Approved == *approved
+		})
 	}
 
 	return response.JSON(w, customTemplates)
 }
 
-func retrieveEdgeParam(r *http.Request) *bool {
-	var edge *bool
-	edgeParam, _ := request.RetrieveQueryParameter(r, "edge", true)
-	if edgeParam != "" {
-		edgeVal, err := strconv.ParseBool(edgeParam)
+func retrieveBoolParam(r *http.Request, name string) *bool {
+	var value *bool
+	param, _ := request.RetrieveQueryParameter(r, name, true)
+	if param != "" {
+		parsed, err := strconv.ParseBool(param)
 		if err != nil {
-			log.Warn().Err(err).Msg("failed parsing edge param")
+			log.Warn().Err(err).Str("param", name).Msg("failed parsing boolean param")
 			return nil
 		}
 
-		edge = &edgeVal
+		value = &parsed
 	}
-	return edge
+	return value
 }
 
 func parseTemplateTypes(r *http.Request) ([]portainer.StackType, error) {
@@ -0,0 +1,103 @@
+package customtemplates
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+	"github.com/portainer/portainer/api/jwt"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_customTemplateListSharing(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	users := []portainer.User{
+		{ID: 1, Username: "owner", Role: portainer.StandardUserRole},
+		{ID: 2, Username: "shared-user", Role: portainer.StandardUserRole},
+		{ID: 3, Username: "team-member", Role: portainer.StandardUserRole},
+		{ID: 4, Username: "other", Role: portainer.StandardUserRole},
+	}
+	for i := range users {
+		users[i].PortainerAuthorizations = authorization.DefaultPortainerAuthorizations()
+		require.NoError(t, store.User().Create(&users[i]))
+	}
+
+	require.NoError(t, store.Team().Create(&portainer.Team{ID: 1, Name: "team"}))
+	require.NoError(t, store.TeamMembership().Create(&portainer.TeamMembership{ID: 1, UserID: 3, TeamID: 1, Role: portainer.TeamMember}))
+
+	require.NoError(t, store.CustomTemplate().Create(&portainer.CustomTemplate{ID: 1, Title: "private", CreatedByUserID: 1}))
+	require.NoError(t, store.CustomTemplate().Create(&portainer.CustomTemplate{ID: 2, Title: "shared-with-user", CreatedByUserID: 1, SharedUserIDs: []portainer.UserID{2}}))
+	require.NoError(t, store.CustomTemplate().Create(&portainer.CustomTemplate{ID: 3, Title: "shared-with-team", CreatedByUserID: 1, SharedTeamIDs: []portainer.TeamID{1}}))
+	require.NoError(t, store.CustomTemplate().Create(&portainer.CustomTemplate{ID: 4, Title: "approved", CreatedByUserID: 1, Approved: true}))
+
+	jwtService, err := jwt.NewService("1h", store)
+	require.NoError(t, err)
+
+	h := NewHandler(security.NewRequestBouncer(store, jwtService, nil), store, &TestFileService{}, &TestGitService{})
+
+	list := func(user portainer.User, query string) []portainer.CustomTemplateID {
+		token, _, err := jwtService.GenerateToken(&portainer.TokenData{ID: user.ID, Username: user.Username, Role: user.Role})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/custom_templates"+query, nil)
+		testhelpers.AddTestSecurityCookie(req, token)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var templates []portainer.CustomTemplate
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&templates))
+
+		ids := []portainer.CustomTemplateID{}
+		for _, template := range templates {
+			ids = append(ids, template.ID)
+		}
+
+		return ids
+	}
+
+	assert.ElementsMatch(t, []portainer.CustomTemplateID{1, 2, 3, 4}, list(users[0], ""))
+	assert.ElementsMatch(t, []portainer.CustomTemplateID{2, 4}, list(users[1], ""))
+	assert.ElementsMatch(t, []portainer.CustomTemplateID{3, 4}, list(users[2], ""))
+	assert.ElementsMatch(t, []portainer.CustomTemplateID{4}, list(users[3], ""))
+
+	assert.ElementsMatch(t, []portainer.CustomTemplateID{4}, list(users[0], "?approved=true"))
+	assert.ElementsMatch(t, []portainer.CustomTemplateID{1, 2, 3}, list(users[0], "?approved=false"))
+
+	t.Run("a standard user cannot approve a template", func(t *testing.T) {
+		token, _, err := jwtService.GenerateToken(&portainer.TokenData{ID: users[0].ID, Username: users[0].Username, Role: users[0].Role})
+		require.NoError(t, err)
+
+		payload, err := json.Marshal(map[string]any{
+			"Title":       "private",
+			"Description": "description",
+			"Type":        portainer.DockerComposeStack,
+			"Platform":    portainer.CustomTemplatePlatformLinux,
+			"FileContent": "services: {}",
+			"Approved":    true,
+		})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPut, "/custom_templates/1", bytes.NewBuffer(payload))
+		testhelpers.AddTestSecurityCookie(req, token)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		template, err := store.CustomTemplate().Read(1)
+		require.NoError(t, err)
+		assert.False(t, template.Approved)
+	})
+}
@@ -61,6 +61,12 @@ type customTemplateUpdatePayload struct {
 	IsComposeFormat bool `example:"false"`
 	// EdgeTemplate indicates if this template purpose for Edge Stack
 	EdgeTemplate bool `example:"false"`
+	// Users the template is shared with, the sharing is left unchanged when omitted
+	SharedUserIDs []portainer.UserID `json:"SharedUserIds" example:"2,3"`
+	// Teams the template is shared with, the sharing is left unchanged when omitted
+	SharedTeamIDs []portainer.TeamID `json:"SharedTeamIds" example:"1"`
+	// Approve the template for every user, only an administrator can change it
+	Approved *bool `example:"true"`
 }
 
 func (payload *customTemplateUpdatePayload) Validate(r *http.Request) error {
@@ -116,7 +122,7 @@ func (payload *customTemplateUpdatePayload) Validate(r *http.Request) error {
 // @param body body customTemplateUpdatePayload true "Template details"
 // @success 200 {object} portainer.CustomTemplate "Success"
 // @failure 400 "Invalid request"
-// @failure 403 "Permission denied to access template"
+// @failure 403 "Permission denied to access template or to approve it"
 // @failure 404 "Template not found"
 // @failure 500 "Server error"
 // @router /custom_templates/{id} [put]
@@ -169,6 +175,38 @@ func (handler *Handler) customTemplateUpdate(w http.ResponseWriter, r *http.Requ
 	customTemplate.IsComposeFormat = payload.IsComposeFormat
 	customTemplate.EdgeTemplate = payload.EdgeTemplate
 
+	if payload.Approved != nil && *payload.Approved != customTemplate.Approved {
+		if !securityContext.IsAdmin {
+			return httperror.Forbidden("Only administrators can approve a custom template", httperrors.ErrResourceAccessDenied)
+		}
+
+		customTemplate.Approved = *payload.Approved
+	}
+
+	if payload.SharedUserIDs != nil {
+		for _, userID := range payload.SharedUserIDs {
+			if _, err := handler.DataStore.User().Read(userID); handler.DataStore.IsErrObjectNotFound(err) {
+				return httperror.BadRequest("Unable to find a user the template is shared with", err)
+			} else if err != nil {
+				return httperror.InternalServerError("Unable to find a user the template is shared with", err)
+			}
+		}
+
+		customTemplate.SharedUserIDs = payload.SharedUserIDs
+	}
+
+	if payload.SharedTeamIDs != nil {
+		for _, teamID := range payload.SharedTeamIDs {
+			if _, err := handler.DataStore.Team().Read(teamID); handler.DataStore.IsErrObjectNotFound(err) {
+				return httperror.BadRequest("Unable to find a team the template is shared with", err)
+			} else if err != nil {
+				return httperror.InternalServerError("Unable to find a team the template is shared with", err)
+			}
+		}
+
+		customTemplate.SharedTeamIDs = payload.SharedTeamIDs
+	}
+
 	if payload.RepositoryURL != "" {
 		if !govalidator.IsURL(payload.RepositoryURL) {
 			return httperror.BadRequest("Invalid repository URL. Must correspond to a valid URL format", err)
@@ -206,7 +244,7 @@ func (handler *Handler) customTemplateUpdate(w http.ResponseWriter, r *http.Requ
 
 		defer cleanBackup()
 
-		commitHash, err := handler.GitService.LatestCommitID(gitConfig.URL, gitConfig.ReferenceName, repositoryUsername, repositoryPassword, gitConfig.TLSSkipVerify)
+		commitHash, err := handler.GitService.LatestCommitID(gitConfig.URL, gitConfig.ReferenceName, repositoryUsername, repositoryPassword, "", "", gitConfig.TLSSkipVerify)
 		if err != nil {
 			return httperror.InternalServerError("Unable get latest commit id", fmt.Errorf("failed to fetch latest commit id of the template %v: %w", customTemplate.ID, err))
 		}
@@ -2,6 +2,7 @@ package customtemplates
 
 import (
 	"net/http"
+	"strconv"
 	"sync"
 
 	"github.com/gorilla/mux"
@@ -9,6 +10,7 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/middlewares"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 )
 
@@ -19,6 +21,7 @@ type Handler struct {
 	FileService    portainer.FileService
 	GitService     portainer.GitService
 	gitFetchMutexs map[portainer.TemplateID]*sync.Mutex
+	requestBouncer security.BouncerService
 }
 
 // NewHandler creates a handler to manage environment(endpoint) group operations.
@@ -29,6 +32,7 @@ func NewHandler(bouncer security.BouncerService, dataStore dataservices.DataStor
 		FileService:    fileService,
 		GitService:     gitService,
 		gitFetchMutexs: make(map[portainer.TemplateID]*sync.Mutex),
+		requestBouncer: bouncer,
 	}
 
 	h.Handle("/custom_templates/create/{method}",
@@ -40,6 +44,8 @@ func NewHandler(bouncer security.BouncerService, dataStore dataservices.DataStor
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplateInspect))).Methods(http.MethodGet)
 	h.Handle("/custom_templates/{id}/file",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplateFile))).Methods(http.MethodGet)
+	h.Handle("/custom_templates/{id}/variables",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplateVariables))).Methods(http.MethodGet)
 	h.Handle("/custom_templates/{id}",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.customTemplateUpdate))).Methods(http.MethodPut)
 	h.Handle("/custom_templates/{id}",
@@ -52,3 +58,25 @@ func NewHandler(bouncer security.BouncerService, dataStore dataservices.DataStor
 func userCanEditTemplate(customTemplate *portainer.CustomTemplate, securityContext *security.RestrictedRequestContext) bool {
 	return securityContext.IsAdmin || customTemplate.CreatedByUserID == securityContext.UserID
 }
+
+// userCanAccessTemplate returns true when the user can use the template, it looks up the resource control of the
+// template when the user cannot edit it
+func (handler *Handler) userCanAccessTemplate(customTemplate *portainer.CustomTemplate, securityContext *security.RestrictedRequestContext) (bool, error) {
+	if userCanEditTemplate(customTemplate, securityContext) {
+		return true, nil
+	}
+
+	resourceControl, err := handler.DataStore.ResourceControl().ResourceControlByResourceIDAndType(strconv.Itoa(int(customTemplate.ID)), portainer.CustomTemplateResourceControl)
+	if err != nil {
+		return false, err
+	}
+
+	customTemplate.ResourceControl = resourceControl
+
+	userTeamIDs := make([]portainer.TeamID, 0, len(securityContext.UserMemberships))
+	for _, membership := range securityContext.UserMemberships {
+		userTeamIDs = append(userTeamIDs, membership.TeamID)
+	}
+
+	return authorization.UserCanAccessCustomTemplate(customTemplate, securityContext.UserID, userTeamIDs), nil
+}
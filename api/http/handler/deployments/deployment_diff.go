@@ -0,0 +1,60 @@
+package deployments
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/deploymenthistory"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type deploymentDiffResponse struct {
+	From  portainer.DeploymentID           `json:"From" example:"1"`
+	To    portainer.DeploymentID           `json:"To" example:"2"`
+	Files []deploymenthistory.ArtifactDiff `json:"Files"`
+}
+
+// @id DeploymentDiff
+// @summary Compare two deployments
+// @description Compute a line based diff of the artifacts of two deployments.
+// @description **Access policy**: administrator
+// @tags deployments
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param from query int true "Identifier of the base deployment"
+// @param to query int true "Identifier of the deployment to compare with the base"
+// @success 200 {object} deploymentDiffResponse "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Deployment not found"
+// @failure 500 "Server error"
+// @router /deployments/diff [get]
+func (handler *Handler) deploymentDiff(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	fromID, err := request.RetrieveNumericQueryParameter(r, "from", false)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: from", err)
+	}
+
+	toID, err := request.RetrieveNumericQueryParameter(r, "to", false)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: to", err)
+	}
+
+	from, herr := handler.readDeployment(portainer.DeploymentID(fromID))
+	if herr != nil {
+		return herr
+	}
+
+	to, herr := handler.readDeployment(portainer.DeploymentID(toID))
+	if herr != nil {
+		return herr
+	}
+
+	return response.JSON(w, deploymentDiffResponse{
+		From:  from.ID,
+		To:    to.ID,
+		Files: deploymenthistory.Diff(from, to),
+	})
+}
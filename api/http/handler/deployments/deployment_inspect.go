@@ -0,0 +1,49 @@
+package deployments
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id DeploymentInspect
+// @summary Inspect a deployment
+// @description Retrieve details about a deployment, including its artifacts.
+// @description **Access policy**: administrator
+// @tags deployments
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Deployment identifier"
+// @success 200 {object} portainer.Deployment "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Deployment not found"
+// @failure 500 "Server error"
+// @router /deployments/{id} [get]
+func (handler *Handler) deploymentInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	id, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid deployment identifier route variable", err)
+	}
+
+	deployment, herr := handler.readDeployment(portainer.DeploymentID(id))
+	if herr != nil {
+		return herr
+	}
+
+	return response.JSON(w, deployment)
+}
+
+func (handler *Handler) readDeployment(id portainer.DeploymentID) (*portainer.Deployment, *httperror.HandlerError) {
+	deployment, err := handler.DataStore.Deployment().Read(id)
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find a deployment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find a deployment with the specified identifier inside the database", err)
+	}
+
+	return deployment, nil
+}
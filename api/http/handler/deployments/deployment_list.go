@@ -0,0 +1,59 @@
+package deployments
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id DeploymentList
+// @summary List deployments
+// @description List the recorded deployments, most recent first.
+// @description **Access policy**: administrator
+// @tags deployments
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param endpointId query int false "Only return deployments made on this environment(endpoint)"
+// @param stackId query int false "Only return deployments of this stack"
+// @param edgeStackId query int false "Only return deployments of this edge stack"
+// @success 200 {array} portainer.Deployment "Success"
+// @failure 400 "Invalid request"
+// @failure 500 "Server error"
+// @router /deployments [get]
+func (handler *Handler) deploymentList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericQueryParameter(r, "endpointId", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: endpointId", err)
+	}
+
+	stackID, err := request.RetrieveNumericQueryParameter(r, "stackId", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: stackId", err)
+	}
+
+	edgeStackID, err := request.RetrieveNumericQueryParameter(r, "edgeStackId", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: edgeStackId", err)
+	}
+
+	deployments, err := handler.DataStore.Deployment().ReadAllByFilter(func(d portainer.Deployment) bool {
+		return (endpointID == 0 || d.EndpointID == portainer.EndpointID(endpointID)) &&
+			(stackID == 0 || d.StackID == portainer.StackID(stackID)) &&
+			(edgeStackID == 0 || d.EdgeStackID == portainer.EdgeStackID(edgeStackID))
+	})
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve deployments from the database", err)
+	}
+
+	slices.SortFunc(deployments, func(a, b portainer.Deployment) int {
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	return response.JSON(w, deployments)
+}
@@ -0,0 +1,32 @@
+package deployments
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is the HTTP handler used to handle deployment history operations.
+type Handler struct {
+	*mux.Router
+	DataStore dataservices.DataStore
+}
+
+// NewHandler creates a handler to manage deployment history operations.
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+	h.Handle("/deployments",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.deploymentList))).Methods(http.MethodGet)
+	h.Handle("/deployments/diff",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.deploymentDiff))).Methods(http.MethodGet)
+	h.Handle("/deployments/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.deploymentInspect))).Methods(http.MethodGet)
+
+	return h
+}
@@ -1,13 +1,15 @@
 package containers
 
 import (
+	"fmt"
 	"net/http"
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/docker/consts"
 	"github.com/portainer/portainer/api/docker/images"
 	"github.com/portainer/portainer/api/http/middlewares"
-	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/registryutils/access"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -18,12 +20,44 @@ import (
 type RecreatePayload struct {
 	// PullImage if true will pull the image
 	PullImage bool `json:"PullImage"`
+	// Image of the new container, the image of the current container is used when empty.
+	// The image is pulled when set, with the credentials of its registry only if the user can access it
+	Image string `json:"Image" example:"nginx:1.27"`
 }
 
 func (r RecreatePayload) Validate(request *http.Request) error {
+	if r.Image == "" {
+		return nil
+	}
+
+	if _, err := images.ParseImage(images.ParseImageOptions{Name: r.Image}); err != nil {
+		return fmt.Errorf("invalid image: %w", err)
+	}
+
 	return nil
 }
 
+// @id DockerContainerRecreate
+// @summary Recreate a container
+// @description Stop and recreate a container with the same configuration (networks, mounts, labels, environment),
+// @description from the latest version of its image or from another image. The resource control and the webhook
+// @description of the container are transferred to the new container.
+// @description **Access policy**: authenticated
+// @tags docker
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Environment identifier"
+// @param containerId path string true "Container identifier"
+// @param body body RecreatePayload true "Recreate options"
+// @success 200 {object} types.ContainerJSON "Success"
+// @failure 400 "Bad request"
+// @failure 403 "Permission denied"
+// @failure 404 "Environment not found"
+// @failure 500 "Server error"
+// @router /docker/{id}/containers/{containerId}/recreate [post]
+// @router /endpoints/{id}/docker/containers/{containerId}/recreate [post]
 func (handler *Handler) recreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
 	containerID, err := request.RetrieveRouteVariableValue(r, "containerId")
 	if err != nil {
@@ -44,15 +78,22 @@ func (handler *Handler) recreate(w http.ResponseWriter, r *http.Request) *httper
 		return httperror.Forbidden("Permission denied to force update service", err)
 	}
 
+	if payload.Image != "" {
+		if err := handler.checkImageRegistryAccess(r, endpoint, payload.Image); err != nil {
+			return httperror.Forbidden("Permission denied to pull the image", err)
+		}
+	}
+
 	agentTargetHeader := r.Header.Get(portainer.PortainerAgentTargetHeader)
 
-	newContainer, err := handler.containerService.Recreate(r.Context(), endpoint, containerID, payload.PullImage, "", agentTargetHeader)
+	newContainer, err := handler.containerService.RecreateWithImage(r.Context(), endpoint, containerID, payload.PullImage || payload.Image != "", payload.Image, agentTargetHeader)
 	if err != nil {
 		return httperror.InternalServerError("Error recreating container", err)
 	}
 
-	handler.updateWebhook(containerID, newContainer.ID)
-	handler.createResourceControl(containerID, newContainer.ID)
+	if err := handler.containerService.ReassignResources(containerID, newContainer.ID); err != nil {
+		log.Error().Err(err).Str("containerId", newContainer.ID).Msg("unable to transfer the resource control and the webhook to the new container")
+	}
 
 	go func() {
 		images.EvictImageStatus(containerID)
@@ -63,35 +104,49 @@ func (handler *Handler) recreate(w http.ResponseWriter, r *http.Request) *httper
 	return response.JSON(w, newContainer)
 }
 
-func (handler *Handler) createResourceControl(oldContainerId string, newContainerId string) {
-	resourceControls, err := handler.dataStore.ResourceControl().ReadAll()
+// checkImageRegistryAccess ensures that the user can access the registry whose credentials are used to pull the image,
+// the images of the registries without authentication or not managed by Portainer are pulled anonymously
+func (handler *Handler) checkImageRegistryAccess(r *http.Request, endpoint *portainer.Endpoint, imageName string) error {
+	image, err := images.ParseImage(images.ParseImageOptions{Name: imageName})
 	if err != nil {
-		log.Error().Err(err).Msg("Exporting Resource Controls")
+		return err
+	}
 
-		return
+	registries, err := handler.dataStore.Registry().ReadAll()
+	if err != nil {
+		return err
 	}
 
-	resourceControl := authorization.GetResourceControlByResourceIDAndType(oldContainerId, portainer.ContainerResourceControl, resourceControls)
-	if resourceControl == nil {
-		return
+	registry := images.MatchRegistry(image, registries)
+	if registry == nil || !registry.Authentication {
+		return nil
 	}
 
-	resourceControl.ResourceID = newContainerId
-	if err := handler.dataStore.ResourceControl().Create(resourceControl); err != nil {
-		log.Error().Err(err).Str("containerId", newContainerId).Msg("Failed to create new resource control for container")
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return err
+	}
+
+	user, err := handler.dataStore.User().Read(tokenData.ID)
+	if err != nil {
+		return err
 	}
-}
 
-func (handler *Handler) updateWebhook(oldContainerId string, newContainerId string) {
-	webhook, err := handler.dataStore.Webhook().WebhookByResourceID(oldContainerId)
+	teamMemberships, err := handler.dataStore.TeamMembership().TeamMembershipsByUserID(user.ID)
 	if err != nil {
-		log.Error().Err(err).Str("containerId", oldContainerId).Msg("cannot find webhook by containerId")
+		return err
+	}
 
-		return
+	accessibleRegistries, err := access.FilterDeploymentRegistries(handler.dataStore, registries, user, teamMemberships, endpoint.ID)
+	if err != nil {
+		return err
 	}
 
-	webhook.ResourceID = newContainerId
-	if err := handler.dataStore.Webhook().Update(webhook.ID, webhook); err != nil {
-		log.Error().Err(err).Int("webhookId", int(webhook.ID)).Msg("cannot update webhook")
+	for _, accessibleRegistry := range accessibleRegistries {
+		if accessibleRegistry.ID == registry.ID {
+			return nil
+		}
 	}
+
+	return fmt.Errorf("the user cannot access the registry %s of the image %s", registry.Name, imageName)
 }
@@ -0,0 +1,69 @@
+package containers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecreatePayloadValidate(t *testing.T) {
+	assert.NoError(t, RecreatePayload{}.Validate(nil))
+	assert.NoError(t, RecreatePayload{PullImage: true}.Validate(nil))
+	assert.NoError(t, RecreatePayload{Image: "registry.example.com/team/app:1.2"}.Validate(nil))
+	assert.ErrorContains(t, RecreatePayload{Image: "Invalid Image"}.Validate(nil), "invalid image")
+}
+
+func TestCheckImageRegistryAccess(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.User().Create(&portainer.User{ID: 1, Username: "admin", Role: portainer.AdministratorRole}))
+	require.NoError(t, store.User().Create(&portainer.User{ID: 2, Username: "granted", Role: portainer.StandardUserRole}))
+	require.NoError(t, store.User().Create(&portainer.User{ID: 3, Username: "denied", Role: portainer.StandardUserRole}))
+
+	require.NoError(t, store.Registry().Create(&portainer.Registry{
+		ID:             1,
+		Name:           "private",
+		Type:           portainer.CustomRegistry,
+		URL:            "registry.example.com",
+		Authentication: true,
+		RegistryAccesses: portainer.RegistryAccesses{
+			1: {UserAccessPolicies: portainer.UserAccessPolicies{2: {}}},
+		},
+	}))
+	require.NoError(t, store.Registry().Create(&portainer.Registry{
+		ID:   2,
+		Name: "anonymous",
+		Type: portainer.CustomRegistry,
+		URL:  "public.example.com",
+	}))
+
+	h := NewHandler("/docker/{id}/containers", testhelpers.NewTestRequestBouncer(), store, nil, nil)
+	endpoint := &portainer.Endpoint{ID: 1}
+
+	checkAccess := func(user *portainer.TokenData, image string) error {
+		r := httptest.NewRequest(http.MethodPost, "/docker/1/containers/abc/recreate", nil)
+		r = r.WithContext(security.StoreTokenData(r, user))
+
+		return h.checkImageRegistryAccess(r, endpoint, image)
+	}
+
+	admin := &portainer.TokenData{ID: 1, Role: portainer.AdministratorRole}
+	granted := &portainer.TokenData{ID: 2, Role: portainer.StandardUserRole}
+	denied := &portainer.TokenData{ID: 3, Role: portainer.StandardUserRole}
+
+	assert.NoError(t, checkAccess(admin, "registry.example.com/team/app:1.2"))
+	assert.NoError(t, checkAccess(granted, "registry.example.com/team/app:1.2"))
+	assert.ErrorContains(t, checkAccess(denied, "registry.example.com/team/app:1.2"), "cannot access the registry private")
+
+	// The images pulled anonymously do not use the credentials of any registry
+	assert.NoError(t, checkAccess(denied, "public.example.com/team/app:1.2"))
+	assert.NoError(t, checkAccess(denied, "nginx:1.27"))
+}
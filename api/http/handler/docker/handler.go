@@ -9,6 +9,7 @@ import (
 	dockerclient "github.com/portainer/portainer/api/docker/client"
 	"github.com/portainer/portainer/api/http/handler/docker/containers"
 	"github.com/portainer/portainer/api/http/handler/docker/images"
+	"github.com/portainer/portainer/api/http/handler/docker/nodes"
 	"github.com/portainer/portainer/api/http/middlewares"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
@@ -51,6 +52,19 @@ func NewHandler(bouncer security.BouncerService, authorizationService *authoriza
 
 	imagesHandler := images.NewHandler("/docker/{id}/images", bouncer, dockerClientFactory)
 	endpointRouter.PathPrefix("/images").Handler(imagesHandler)
+
+	nodesHandler := nodes.NewHandler("/docker/{id}/nodes", bouncer, dockerClientFactory)
+	endpointRouter.PathPrefix("/nodes").Handler(nodesHandler)
+
+	// native container operations also exposed next to the Docker API of the environments, the environment proxy
+	// handler forwards them without the /endpoints prefix of their route
+	proxiedEndpointRouter := h.PathPrefix("/{id}/docker").Subrouter()
+	proxiedEndpointRouter.Use(bouncer.AuthenticatedAccess)
+	proxiedEndpointRouter.Use(middlewares.WithEndpoint(dataStore.Endpoint(), "id"), dockerOnlyMiddleware)
+
+	proxiedContainersHandler := containers.NewHandler("/{id}/docker/containers", bouncer, dataStore, dockerClientFactory, containerService)
+	proxiedEndpointRouter.PathPrefix("/containers").Handler(proxiedContainersHandler)
+
 	return h
 }
 
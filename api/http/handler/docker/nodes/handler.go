@@ -0,0 +1,47 @@
+package nodes
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/portainer/portainer/api/docker/client"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/gorilla/mux"
+)
+
+// nodeClient is the part of the Docker client used to manage the availability of the Swarm nodes
+type nodeClient interface {
+	NodeInspectWithRaw(ctx context.Context, nodeID string) (swarm.Node, []byte, error)
+	NodeUpdate(ctx context.Context, nodeID string, version swarm.Version, node swarm.NodeSpec) error
+	TaskList(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error)
+}
+
+type Handler struct {
+	*mux.Router
+	dockerClientFactory *client.ClientFactory
+	bouncer             security.BouncerService
+}
+
+// NewHandler creates a handler to manage the availability of the Swarm nodes. As for the proxied node
+// updates, the operations are restricted to the administrators
+func NewHandler(routePrefix string, bouncer security.BouncerService, dockerClientFactory *client.ClientFactory) *Handler {
+	h := &Handler{
+		Router:              mux.NewRouter(),
+		dockerClientFactory: dockerClientFactory,
+		bouncer:             bouncer,
+	}
+
+	router := h.PathPrefix(routePrefix).Subrouter()
+	router.Use(bouncer.AdminAccess)
+
+	router.Handle("/{nodeId}/pause", httperror.LoggerHandler(h.nodePause)).Methods(http.MethodPost)
+	router.Handle("/{nodeId}/activate", httperror.LoggerHandler(h.nodeActivate)).Methods(http.MethodPost)
+	router.Handle("/{nodeId}/drain", httperror.LoggerHandler(h.nodeDrain)).Methods(http.MethodPost)
+	router.Handle("/{nodeId}/drain", httperror.LoggerHandler(h.nodeDrainStatus)).Methods(http.MethodGet)
+
+	return h
+}
@@ -0,0 +1,97 @@
+package nodes
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/portainer/portainer/api/http/handler/docker/utils"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+)
+
+// @id dockerNodePause
+// @summary Pause a Swarm node
+// @description Cordon a Swarm node, no new task is scheduled on it but its running tasks are kept.
+// @description **Access policy**: administrator
+// @tags docker
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param environmentId path int true "Environment identifier"
+// @param nodeId path string true "Node identifier"
+// @success 200 {object} nodeDrainStatus "Success"
+// @failure 400 "Bad request"
+// @failure 404 "Environment or node not found"
+// @failure 500 "Internal server error"
+// @router /docker/{environmentId}/nodes/{nodeId}/pause [post]
+func (handler *Handler) nodePause(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.updateAvailability(w, r, swarm.NodeAvailabilityPause)
+}
+
+// @id dockerNodeActivate
+// @summary Activate a Swarm node
+// @description Make a paused or drained Swarm node available for scheduling again.
+// @description **Access policy**: administrator
+// @tags docker
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param environmentId path int true "Environment identifier"
+// @param nodeId path string true "Node identifier"
+// @success 200 {object} nodeDrainStatus "Success"
+// @failure 400 "Bad request"
+// @failure 404 "Environment or node not found"
+// @failure 500 "Internal server error"
+// @router /docker/{environmentId}/nodes/{nodeId}/activate [post]
+func (handler *Handler) nodeActivate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.updateAvailability(w, r, swarm.NodeAvailabilityActive)
+}
+
+func (handler *Handler) updateAvailability(w http.ResponseWriter, r *http.Request, availability swarm.NodeAvailability) *httperror.HandlerError {
+	nodeID, err := request.RetrieveRouteVariableValue(r, "nodeId")
+	if err != nil {
+		return httperror.BadRequest("Invalid node identifier route variable", err)
+	}
+
+	cli, httpErr := utils.GetClient(r, handler.dockerClientFactory)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	node, httpErr := setAvailability(r.Context(), cli, nodeID, availability)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	status, err := drainStatus(r.Context(), cli, node)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the tasks of the node", err)
+	}
+
+	return response.JSON(w, status)
+}
+
+// setAvailability changes the availability of a node and returns the updated node
+func setAvailability(ctx context.Context, cli nodeClient, nodeID string, availability swarm.NodeAvailability) (*swarm.Node, *httperror.HandlerError) {
+	node, _, err := cli.NodeInspectWithRaw(ctx, nodeID)
+	if errdefs.IsNotFound(err) {
+		return nil, httperror.NotFound("Unable to find the node", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to inspect the node", err)
+	}
+
+	if node.Spec.Availability == availability {
+		return &node, nil
+	}
+
+	node.Spec.Availability = availability
+	if err := cli.NodeUpdate(ctx, node.ID, node.Version, node.Spec); err != nil {
+		return nil, httperror.InternalServerError("Unable to update the availability of the node", err)
+	}
+
+	return &node, nil
+}
@@ -0,0 +1,297 @@
+package nodes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/portainer/portainer/api/http/handler/docker/utils"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/errdefs"
+)
+
+const (
+	// defaultDrainTimeout is how long a drain waits for the tasks to leave the node when no timeout is given
+	defaultDrainTimeout = 5 * time.Minute
+	// maxDrainTimeout bounds how long a drain request can wait
+	maxDrainTimeout = time.Hour
+	// drainPollInterval is how often the tasks of a node being drained are checked
+	drainPollInterval = 2 * time.Second
+)
+
+type nodeDrainPayload struct {
+	// If true, the request waits for the tasks to be migrated to other nodes
+	Wait bool `example:"true"`
+	// How long to wait for the tasks to be migrated, in seconds. Defaults to 300
+	Timeout int `example:"300"`
+}
+
+func (payload *nodeDrainPayload) Validate(r *http.Request) error {
+	if payload.Timeout < 0 || time.Duration(payload.Timeout)*time.Second > maxDrainTimeout {
+		return errors.New("invalid timeout, it must be between 0 and 3600 seconds")
+	}
+
+	return nil
+}
+
+// nodeDrainTask is a task of a node being drained
+type nodeDrainTask struct {
+	ID        string          `json:"Id"`
+	ServiceID string          `json:"ServiceId"`
+	Slot      int             `json:"Slot,omitempty"`
+	State     swarm.TaskState `json:"State"`
+	// Error reported by the Swarm scheduler for the task
+	Error string `json:"Error,omitempty"`
+}
+
+// nodeDrainStatus reports the progress of the migration of the tasks of a node
+type nodeDrainStatus struct {
+	NodeID       string                 `json:"NodeId"`
+	Hostname     string                 `json:"Hostname"`
+	Availability swarm.NodeAvailability `json:"Availability"`
+	// Tasks still running on the node
+	RemainingTasks []nodeDrainTask `json:"RemainingTasks"`
+	// Replacement tasks of the services of the node that cannot be scheduled on another node
+	FailedTasks []nodeDrainTask `json:"FailedTasks"`
+	// Whether the node is drained and no task runs on it anymore
+	Completed bool `json:"Completed"`
+	// Whether the wait for the migration of the tasks timed out
+	TimedOut bool `json:"TimedOut,omitempty"`
+}
+
+// @id dockerNodeDrain
+// @summary Drain a Swarm node
+// @description Drain a Swarm node, its tasks are migrated to the other nodes. The request can wait for the
+// @description migration to complete, the progress and the tasks that cannot be migrated are reported.
+// @description **Access policy**: administrator
+// @tags docker
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param environmentId path int true "Environment identifier"
+// @param nodeId path string true "Node identifier"
+// @param body body nodeDrainPayload false "Drain options"
+// @success 200 {object} nodeDrainStatus "Success"
+// @failure 400 "Bad request"
+// @failure 404 "Environment or node not found"
+// @failure 500 "Internal server error"
+// @router /docker/{environmentId}/nodes/{nodeId}/drain [post]
+func (handler *Handler) nodeDrain(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	nodeID, err := request.RetrieveRouteVariableValue(r, "nodeId")
+	if err != nil {
+		return httperror.BadRequest("Invalid node identifier route variable", err)
+	}
+
+	var payload nodeDrainPayload
+	if r.ContentLength != 0 {
+		if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+			return httperror.BadRequest("Invalid request payload", err)
+		}
+	}
+
+	cli, httpErr := utils.GetClient(r, handler.dockerClientFactory)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	node, httpErr := setAvailability(r.Context(), cli, nodeID, swarm.NodeAvailabilityDrain)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if !payload.Wait {
+		status, err := drainStatus(r.Context(), cli, node)
+		if err != nil {
+			return httperror.InternalServerError("Unable to retrieve the tasks of the node", err)
+		}
+
+		return response.JSON(w, status)
+	}
+
+	timeout := defaultDrainTimeout
+	if payload.Timeout > 0 {
+		timeout = time.Duration(payload.Timeout) * time.Second
+	}
+
+	status, err := waitForDrain(r.Context(), cli, node, timeout, drainPollInterval)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the tasks of the node", err)
+	}
+
+	return response.JSON(w, status)
+}
+
+// @id dockerNodeDrainStatus
+// @summary Inspect the drain of a Swarm node
+// @description Report the tasks still running on a Swarm node and the tasks that cannot be migrated to other nodes.
+// @description **Access policy**: administrator
+// @tags docker
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param environmentId path int true "Environment identifier"
+// @param nodeId path string true "Node identifier"
+// @success 200 {object} nodeDrainStatus "Success"
+// @failure 400 "Bad request"
+// @failure 404 "Environment or node not found"
+// @failure 500 "Internal server error"
+// @router /docker/{environmentId}/nodes/{nodeId}/drain [get]
+func (handler *Handler) nodeDrainStatus(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	nodeID, err := request.RetrieveRouteVariableValue(r, "nodeId")
+	if err != nil {
+		return httperror.BadRequest("Invalid node identifier route variable", err)
+	}
+
+	cli, httpErr := utils.GetClient(r, handler.dockerClientFactory)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	node, _, err := cli.NodeInspectWithRaw(r.Context(), nodeID)
+	if errdefs.IsNotFound(err) {
+		return httperror.NotFound("Unable to find the node", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to inspect the node", err)
+	}
+
+	status, err := drainStatus(r.Context(), cli, &node)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the tasks of the node", err)
+	}
+
+	return response.JSON(w, status)
+}
+
+// waitForDrain polls the tasks of a drained node until none runs on it anymore or the timeout expires
+func waitForDrain(ctx context.Context, cli nodeClient, node *swarm.Node, timeout, pollInterval time.Duration) (*nodeDrainStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := drainStatus(ctx, cli, node)
+		if err != nil {
+			if ctx.Err() != nil {
+				return lastStatus(cli, node)
+			}
+
+			return nil, err
+		}
+
+		if status.Completed {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return lastStatus(cli, node)
+		case <-ticker.C:
+		}
+	}
+}
+
+// lastStatus returns the status of a node once the wait timed out, it does not rely on the expired context
+func lastStatus(cli nodeClient, node *swarm.Node) (*nodeDrainStatus, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	status, err := drainStatus(ctx, cli, node)
+	if err != nil {
+		return nil, err
+	}
+
+	status.TimedOut = !status.Completed
+
+	return status, nil
+}
+
+// drainStatus reports the tasks still running on a node and the replacement tasks of its services that cannot be scheduled
+func drainStatus(ctx context.Context, cli nodeClient, node *swarm.Node) (*nodeDrainStatus, error) {
+	tasks, err := cli.TaskList(ctx, types.TaskListOptions{
+		Filters: filters.NewArgs(filters.Arg("node", node.ID)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := &nodeDrainStatus{
+		NodeID:         node.ID,
+		Hostname:       node.Description.Hostname,
+		Availability:   node.Spec.Availability,
+		RemainingTasks: []nodeDrainTask{},
+		FailedTasks:    []nodeDrainTask{},
+	}
+
+	serviceIDs := []string{}
+	for _, task := range tasks {
+		if !slices.Contains(serviceIDs, task.ServiceID) {
+			serviceIDs = append(serviceIDs, task.ServiceID)
+		}
+
+		if isTerminal(task.Status.State) {
+			continue
+		}
+
+		status.RemainingTasks = append(status.RemainingTasks, newNodeDrainTask(task))
+	}
+
+	for _, serviceID := range serviceIDs {
+		serviceTasks, err := cli.TaskList(ctx, types.TaskListOptions{
+			Filters: filters.NewArgs(
+				filters.Arg("service", serviceID),
+				filters.Arg("desired-state", string(swarm.TaskStateRunning)),
+			),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, task := range serviceTasks {
+			if task.NodeID == node.ID || task.Status.Err == "" || !isPending(task.Status.State) {
+				continue
+			}
+
+			status.FailedTasks = append(status.FailedTasks, newNodeDrainTask(task))
+		}
+	}
+
+	status.Completed = node.Spec.Availability == swarm.NodeAvailabilityDrain && len(status.RemainingTasks) == 0
+
+	return status, nil
+}
+
+func newNodeDrainTask(task swarm.Task) nodeDrainTask {
+	return nodeDrainTask{
+		ID:        task.ID,
+		ServiceID: task.ServiceID,
+		Slot:      task.Slot,
+		State:     task.Status.State,
+		Error:     task.Status.Err,
+	}
+}
+
+// isTerminal returns true when a task does not run and will not run anymore
+func isTerminal(state swarm.TaskState) bool {
+	switch state {
+	case swarm.TaskStateComplete, swarm.TaskStateShutdown, swarm.TaskStateFailed, swarm.TaskStateRejected, swarm.TaskStateRemove, swarm.TaskStateOrphaned:
+		return true
+	}
+
+	return false
+}
+
+// isPending returns true when a task waits to be scheduled on a node
+func isPending(state swarm.TaskState) bool {
+	return state == swarm.TaskStateNew || state == swarm.TaskStatePending || state == swarm.TaskStateAllocated
+}
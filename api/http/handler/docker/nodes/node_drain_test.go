@@ -0,0 +1,137 @@
+package nodes
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testNodeClient struct {
+	mu    sync.Mutex
+	node  swarm.Node
+	tasks []swarm.Task
+	// onTaskList is called on each task list, it simulates the progress of the migration
+	onTaskList func(tasks []swarm.Task) []swarm.Task
+}
+
+func (cli *testNodeClient) NodeInspectWithRaw(ctx context.Context, nodeID string) (swarm.Node, []byte, error) {
+	return cli.node, nil, nil
+}
+
+func (cli *testNodeClient) NodeUpdate(ctx context.Context, nodeID string, version swarm.Version, spec swarm.NodeSpec) error {
+	cli.node.Spec = spec
+
+	return nil
+}
+
+func (cli *testNodeClient) TaskList(ctx context.Context, options types.TaskListOptions) ([]swarm.Task, error) {
+	cli.mu.Lock()
+	defer cli.mu.Unlock()
+
+	if options.Filters.Contains("node") && cli.onTaskList != nil {
+		cli.tasks = cli.onTaskList(cli.tasks)
+	}
+
+	tasks := []swarm.Task{}
+	for _, task := range cli.tasks {
+		if options.Filters.Contains("node") && !options.Filters.ExactMatch("node", task.NodeID) {
+			continue
+		}
+
+		if options.Filters.Contains("service") && !options.Filters.ExactMatch("service", task.ServiceID) {
+			continue
+		}
+
+		if options.Filters.Contains("desired-state") && !options.Filters.ExactMatch("desired-state", string(task.DesiredState)) {
+			continue
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
+func newTask(id, serviceID, nodeID string, desiredState, state swarm.TaskState, errMessage string) swarm.Task {
+	return swarm.Task{
+		ID:           id,
+		ServiceID:    serviceID,
+		NodeID:       nodeID,
+		DesiredState: desiredState,
+		Status:       swarm.TaskStatus{State: state, Err: errMessage},
+	}
+}
+
+func TestDrainStatus(t *testing.T) {
+	cli := &testNodeClient{
+		node: swarm.Node{ID: "node1", Spec: swarm.NodeSpec{Availability: swarm.NodeAvailabilityActive}},
+		tasks: []swarm.Task{
+			newTask("web.1", "web", "node1", swarm.TaskStateShutdown, swarm.TaskStateRunning, ""),
+			newTask("db.1", "db", "node1", swarm.TaskStateShutdown, swarm.TaskStateShutdown, ""),
+			newTask("db.2", "db", "", swarm.TaskStateRunning, swarm.TaskStatePending, "no suitable node (scheduling constraints not satisfied on 2 nodes)"),
+			newTask("web.2", "web", "node2", swarm.TaskStateRunning, swarm.TaskStateStarting, ""),
+		},
+	}
+
+	node, httpErr := setAvailability(context.Background(), cli, "node1", swarm.NodeAvailabilityDrain)
+	require.Nil(t, httpErr)
+	assert.Equal(t, swarm.NodeAvailabilityDrain, cli.node.Spec.Availability)
+
+	status, err := drainStatus(context.Background(), cli, node)
+	require.NoError(t, err)
+
+	assert.False(t, status.Completed)
+	require.Len(t, status.RemainingTasks, 1)
+	assert.Equal(t, "web.1", status.RemainingTasks[0].ID)
+	require.Len(t, status.FailedTasks, 1)
+	assert.Equal(t, "db.2", status.FailedTasks[0].ID)
+	assert.NotEmpty(t, status.FailedTasks[0].Error)
+}
+
+func TestWaitForDrain(t *testing.T) {
+	polls := 0
+	cli := &testNodeClient{
+		node: swarm.Node{ID: "node1", Spec: swarm.NodeSpec{Availability: swarm.NodeAvailabilityActive}},
+		tasks: []swarm.Task{
+			newTask("web.1", "web", "node1", swarm.TaskStateShutdown, swarm.TaskStateRunning, ""),
+		},
+		onTaskList: func(tasks []swarm.Task) []swarm.Task {
+			polls++
+			if polls == 3 {
+				tasks[0].Status.State = swarm.TaskStateShutdown
+			}
+
+			return tasks
+		},
+	}
+
+	node, httpErr := setAvailability(context.Background(), cli, "node1", swarm.NodeAvailabilityDrain)
+	require.Nil(t, httpErr)
+
+	status, err := waitForDrain(context.Background(), cli, node, 5*time.Second, time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, status.Completed)
+	assert.False(t, status.TimedOut)
+	assert.Empty(t, status.RemainingTasks)
+}
+
+func TestWaitForDrainTimeout(t *testing.T) {
+	cli := &testNodeClient{
+		node: swarm.Node{ID: "node1", Spec: swarm.NodeSpec{Availability: swarm.NodeAvailabilityDrain}},
+		tasks: []swarm.Task{
+			newTask("web.1", "web", "node1", swarm.TaskStateShutdown, swarm.TaskStateRunning, ""),
+		},
+	}
+
+	status, err := waitForDrain(context.Background(), cli, &cli.node, 20*time.Millisecond, time.Millisecond)
+	require.NoError(t, err)
+	assert.False(t, status.Completed)
+	assert.True(t, status.TimedOut)
+	assert.Len(t, status.RemainingTasks, 1)
+}
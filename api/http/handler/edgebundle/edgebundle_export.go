@@ -0,0 +1,63 @@
+package edgebundle
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/edge/bundle"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type edgeBundleExportPayload struct {
+	// Password encrypting the Edge keys and the private key of the tunnel server
+	Password string `example:"secret"`
+}
+
+func (payload *edgeBundleExportPayload) Validate(r *http.Request) error {
+	if payload.Password == "" {
+		return errors.New("a password is required to encrypt the secrets of the bundle")
+	}
+
+	return nil
+}
+
+// @id EdgeBundleExport
+// @summary Export the Edge configuration
+// @description Export the Edge environments, Edge groups, Edge stacks with their files and Edge jobs with their scripts,
+// @description to rebuild the Edge fleet on another instance. The Edge keys and the private key of the tunnel server are
+// @description encrypted with the password.
+// @description **Access policy**: administrator
+// @tags edge
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body edgeBundleExportPayload true "Export details"
+// @success 200 {object} bundle.Bundle
+// @failure 400 "Invalid request"
+// @failure 500 "Server error"
+// @router /edge_bundle/export [post]
+func (handler *Handler) edgeBundleExport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload edgeBundleExportPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var edgeBundle *bundle.Bundle
+	if err := handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) (err error) {
+		edgeBundle, err = bundle.Export(tx, handler.FileService, payload.Password)
+
+		return err
+	}); err != nil {
+		return httperror.InternalServerError("Unable to export the Edge configuration", err)
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=portainer-edge-bundle_%s.json", time.Now().Format("20060102-150405")))
+
+	return response.JSON(w, edgeBundle)
+}
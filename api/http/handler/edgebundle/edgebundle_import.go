@@ -0,0 +1,68 @@
+package edgebundle
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/edge/bundle"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/segmentio/encoding/json"
+)
+
+// @id EdgeBundleImport
+// @summary Import an Edge configuration
+// @description Import a bundle exported by another instance. The instance must not have any Edge group, stack or job and
+// @description the Edge environments keep their identifiers so that their agents reconnect without being enrolled again.
+// @description The access policies are not imported. Portainer must be restarted when the private key of the tunnel server
+// @description is restored.
+// @description **Access policy**: administrator
+// @tags edge
+// @security ApiKeyAuth
+// @security jwt
+// @accept multipart/form-data
+// @produce json
+// @param file formData file true "Bundle exported by another instance"
+// @param Password formData string true "Password used for the export"
+// @success 200 {object} bundle.ImportResult
+// @failure 400 "Invalid request"
+// @failure 409 "The Edge configuration of the instance conflicts with the one of the bundle"
+// @failure 500 "Server error"
+// @router /edge_bundle/import [post]
+func (handler *Handler) edgeBundleImport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	content, _, err := request.RetrieveMultiPartFormFile(r, "file")
+	if err != nil {
+		return httperror.BadRequest("Invalid bundle file", err)
+	}
+
+	password, err := request.RetrieveMultiPartFormValue(r, "Password", false)
+	if err != nil {
+		return httperror.BadRequest("Invalid password", err)
+	}
+
+	var edgeBundle bundle.Bundle
+	if err := json.Unmarshal(content, &edgeBundle); err != nil {
+		return httperror.BadRequest("Invalid bundle file", err)
+	}
+
+	var result *bundle.ImportResult
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		result, err = bundle.Import(tx, handler.FileService, &edgeBundle, password)
+
+		return err
+	})
+
+	switch {
+	case errors.Is(err, bundle.ErrInvalidPassword), errors.Is(err, bundle.ErrUnsupportedVersion):
+		return httperror.BadRequest("Unable to import the bundle", err)
+	case errors.Is(err, bundle.ErrConflict):
+		return httperror.Conflict("Unable to import the bundle", err)
+	case err != nil:
+		return httperror.InternalServerError("Unable to import the bundle", err)
+	}
+
+	return response.JSON(w, result)
+}
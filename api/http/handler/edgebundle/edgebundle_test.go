@@ -0,0 +1,90 @@
+package edgebundle
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/internal/edge/bundle"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHandler(t *testing.T) *Handler {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	fileService, err := filesystem.NewService(t.TempDir(), "")
+	require.NoError(t, err)
+
+	h := NewHandler(testhelpers.NewTestRequestBouncer())
+	h.DataStore = store
+	h.FileService = fileService
+
+	return h
+}
+
+func TestEdgeBundleExportImport(t *testing.T) {
+	source := newHandler(t)
+
+	require.NoError(t, source.FileService.StoreChiselPrivateKey([]byte("tunnel-private-key")))
+	require.NoError(t, source.DataStore.Endpoint().Create(&portainer.Endpoint{
+		ID:      7,
+		Name:    "store-42",
+		Type:    portainer.EdgeAgentOnDockerEnvironment,
+		EdgeID:  "edge-id-42",
+		EdgeKey: "edge-key-42",
+		GroupID: 1,
+	}))
+
+	payload, err := json.Marshal(edgeBundleExportPayload{Password: "password"})
+	require.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	source.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/edge_bundle/export", bytes.NewReader(payload)))
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	exported := rr.Body.Bytes()
+	assert.NotContains(t, string(exported), "edge-key-42")
+
+	// The encrypted secrets of the downloaded bundle are imported on another instance
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	file, err := writer.CreateFormFile("file", "bundle.json")
+	require.NoError(t, err)
+	_, err = file.Write(exported)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteField("Password", "password"))
+	require.NoError(t, writer.Close())
+
+	target := newHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/edge_bundle/import", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	rr = httptest.NewRecorder()
+	target.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var result bundle.ImportResult
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &result))
+	assert.Equal(t, bundle.ImportResult{Endpoints: 1, RestartRequired: true}, result)
+
+	endpoint, err := target.DataStore.Endpoint().Endpoint(7)
+	require.NoError(t, err)
+	assert.Equal(t, "edge-key-42", endpoint.EdgeKey)
+	assert.Equal(t, "edge-id-42", endpoint.EdgeID)
+
+	key, err := os.ReadFile(target.FileService.GetDefaultChiselPrivateKeyPath())
+	require.NoError(t, err)
+	assert.Equal(t, "tunnel-private-key", string(key))
+}
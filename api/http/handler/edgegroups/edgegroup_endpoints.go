@@ -0,0 +1,111 @@
+package edgegroups
+
+import (
+	"net/http"
+	"strconv"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// edgeGroupEndpoint is the summary of an environment(endpoint) member of an Edge group
+type edgeGroupEndpoint struct {
+	ID          portainer.EndpointID      `json:"Id" example:"1"`
+	Name        string                    `json:"Name" example:"my-environment"`
+	Type        portainer.EndpointType    `json:"Type" example:"4"`
+	GroupID     portainer.EndpointGroupID `json:"GroupId" example:"1"`
+	TagIDs      []portainer.TagID         `json:"TagIds"`
+	UserTrusted bool                      `json:"UserTrusted"`
+}
+
+// @id EdgeGroupEndpoints
+// @summary List the members of an EdgeGroup
+// @description List the environments(endpoints) that are members of an Edge group, the members of a dynamic Edge group
+// @description are evaluated from its tags. The total number of members is returned in the X-Total-Count header.
+// @description **Access policy**: administrator
+// @tags edge_groups
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "EdgeGroup Id"
+// @param start query int false "Start searching from"
+// @param limit query int false "Limit results to this value"
+// @success 200 {array} edgeGroupEndpoint
+// @failure 400 "Invalid request"
+// @failure 404 "Edge group not found"
+// @failure 503 "Edge compute features are disabled"
+// @failure 500
+// @router /edge_groups/{id}/endpoints [get]
+func (handler *Handler) edgeGroupEndpoints(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	edgeGroupID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid Edge group identifier route variable", err)
+	}
+
+	start, _ := request.RetrieveNumericQueryParameter(r, "start", true)
+	if start != 0 {
+		start--
+	}
+
+	limit, _ := request.RetrieveNumericQueryParameter(r, "limit", true)
+
+	var endpoints []edgeGroupEndpoint
+	var totalCount int
+	err = handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		edgeGroup, err := getEdgeGroup(tx, portainer.EdgeGroupID(edgeGroupID))
+		if err != nil {
+			return err
+		}
+
+		totalCount = len(edgeGroup.Endpoints)
+
+		endpoints, err = getEdgeGroupEndpoints(tx, paginate(edgeGroup.Endpoints, start, limit))
+
+		return err
+	})
+	if err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(totalCount))
+
+	return response.JSON(w, endpoints)
+}
+
+func getEdgeGroupEndpoints(tx dataservices.DataStoreTx, endpointIDs []portainer.EndpointID) ([]edgeGroupEndpoint, error) {
+	endpoints := make([]edgeGroupEndpoint, 0, len(endpointIDs))
+
+	for _, endpointID := range endpointIDs {
+		endpoint, err := tx.Endpoint().Endpoint(endpointID)
+		if tx.IsErrObjectNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, httperror.InternalServerError("Unable to retrieve environment from the database", err)
+		}
+
+		endpoints = append(endpoints, edgeGroupEndpoint{
+			ID:          endpoint.ID,
+			Name:        endpoint.Name,
+			Type:        endpoint.Type,
+			GroupID:     endpoint.GroupID,
+			TagIDs:      endpoint.TagIDs,
+			UserTrusted: endpoint.UserTrusted,
+		})
+	}
+
+	return endpoints, nil
+}
+
+func paginate(endpointIDs []portainer.EndpointID, start, limit int) []portainer.EndpointID {
+	if limit == 0 {
+		return endpointIDs
+	}
+
+	start = min(max(start, 0), len(endpointIDs))
+	end := min(start+limit, len(endpointIDs))
+
+	return endpointIDs[start:end]
+}
@@ -0,0 +1,65 @@
+package edgegroups
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type edgeGroupPreviewPayload struct {
+	// Tags an environment(endpoint) must be associated with, directly or through its group, to be a member
+	TagIDs []portainer.TagID
+	// If true, an environment(endpoint) associated with any of the tags is a member, otherwise it must be associated with all of them
+	PartialMatch bool
+}
+
+func (payload *edgeGroupPreviewPayload) Validate(r *http.Request) error {
+	if len(payload.TagIDs) == 0 {
+		return errors.New("tagIDs is mandatory for a dynamic Edge group")
+	}
+
+	return nil
+}
+
+// @id EdgeGroupPreview
+// @summary Preview the members of a dynamic EdgeGroup
+// @description Evaluates a dynamic Edge group definition against the current environments(endpoints) and returns the matching ones,
+// @description without saving the Edge group.
+// @description **Access policy**: administrator
+// @tags edge_groups
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body edgeGroupPreviewPayload true "Dynamic EdgeGroup definition"
+// @success 200 {array} edgeGroupEndpoint
+// @failure 400 "Invalid request payload"
+// @failure 503 "Edge compute features are disabled"
+// @failure 500
+// @router /edge_groups/preview [post]
+func (handler *Handler) edgeGroupPreview(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload edgeGroupPreviewPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var endpoints []edgeGroupEndpoint
+	err := handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		endpointIDs, err := GetEndpointsByTags(tx, payload.TagIDs, payload.PartialMatch)
+		if tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find a tag with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to retrieve the environments matching the tags", err)
+		}
+
+		endpoints, err = getEdgeGroupEndpoints(tx, endpointIDs)
+
+		return err
+	})
+
+	return txResponse(w, endpoints, err)
+}
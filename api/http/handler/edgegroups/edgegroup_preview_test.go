@@ -0,0 +1,83 @@
+package edgegroups
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgeGroupPreviewAndEndpoints(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Tag().Create(&portainer.Tag{ID: 1, Name: "production", Endpoints: map[portainer.EndpointID]bool{1: true, 2: true, 3: true}}))
+	require.NoError(t, store.Tag().Create(&portainer.Tag{ID: 2, Name: "europe", Endpoints: map[portainer.EndpointID]bool{2: true}}))
+
+	for _, endpoint := range []portainer.Endpoint{
+		{ID: 1, Name: "edge-1", Type: portainer.EdgeAgentOnDockerEnvironment, UserTrusted: true, TagIDs: []portainer.TagID{1}},
+		{ID: 2, Name: "edge-2", Type: portainer.EdgeAgentOnDockerEnvironment, UserTrusted: true, TagIDs: []portainer.TagID{1, 2}},
+		// Untrusted environments are not members of the dynamic Edge groups
+		{ID: 3, Name: "edge-3", Type: portainer.EdgeAgentOnDockerEnvironment, TagIDs: []portainer.TagID{1}},
+	} {
+		require.NoError(t, store.Endpoint().Create(&endpoint))
+	}
+
+	require.NoError(t, store.EdgeGroup().Create(&portainer.EdgeGroup{ID: 1, Name: "static", Endpoints: []portainer.EndpointID{1, 2, 3}}))
+
+	h := NewHandler(testhelpers.NewTestRequestBouncer())
+	h.DataStore = store
+
+	serve := func(method, url string, payload any) *httptest.ResponseRecorder {
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(method, url, bytes.NewReader(body)))
+
+		return rr
+	}
+
+	memberIDs := func(rr *httptest.ResponseRecorder) []portainer.EndpointID {
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		var endpoints []edgeGroupEndpoint
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&endpoints))
+
+		ids := []portainer.EndpointID{}
+		for _, endpoint := range endpoints {
+			ids = append(ids, endpoint.ID)
+		}
+
+		return ids
+	}
+
+	rr := serve(http.MethodPost, "/edge_groups/preview", edgeGroupPreviewPayload{TagIDs: []portainer.TagID{1, 2}})
+	assert.Equal(t, []portainer.EndpointID{2}, memberIDs(rr))
+
+	rr = serve(http.MethodPost, "/edge_groups/preview", edgeGroupPreviewPayload{TagIDs: []portainer.TagID{1, 2}, PartialMatch: true})
+	assert.Equal(t, []portainer.EndpointID{1, 2}, memberIDs(rr))
+
+	rr = serve(http.MethodPost, "/edge_groups/preview", edgeGroupPreviewPayload{TagIDs: []portainer.TagID{42}})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = serve(http.MethodPost, "/edge_groups/preview", edgeGroupPreviewPayload{})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = serve(http.MethodGet, "/edge_groups/1/endpoints?start=2&limit=1", nil)
+	assert.Equal(t, "3", rr.Header().Get("X-Total-Count"))
+	assert.Equal(t, []portainer.EndpointID{2}, memberIDs(rr))
+
+	rr = serve(http.MethodGet, "/edge_groups/1/endpoints", nil)
+	assert.Equal(t, []portainer.EndpointID{1, 2, 3}, memberIDs(rr))
+
+	rr = serve(http.MethodGet, "/edge_groups/2/endpoints", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/portainer/portainer/api/internal/edge"
 	"github.com/portainer/portainer/api/internal/edge/cache"
 	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/scheduler"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 
@@ -25,6 +26,10 @@ type edgeJobBasePayload struct {
 	Recurring      bool
 	Endpoints      []portainer.EndpointID
 	EdgeGroups     []portainer.EdgeGroupID
+	// IANA time zone the cron expression runs in, defaults to the time zone of each environment
+	TimeZone string `example:"Europe/Paris"`
+	// Unix timestamp of the single run of a one-time job, it replaces the cron expression
+	RunAt int64 `example:"1735689600"`
 }
 
 func (handler *Handler) edgeJobCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
@@ -57,8 +62,12 @@ func (payload *edgeJobCreateFromFileContentPayload) Validate(r *http.Request) er
 		return errors.New("invalid Edge job name format. Allowed characters are: [a-zA-Z0-9_.-]")
 	}
 
-	if len(payload.CronExpression) == 0 {
-		return errors.New("invalid cron expression")
+	if err := validateSchedule(payload.CronExpression, payload.RunAt, payload.Recurring); err != nil {
+		return err
+	}
+
+	if err := validateRunAt(payload.RunAt); err != nil {
+		return err
 	}
 
 	if len(payload.Endpoints) == 0 && len(payload.EdgeGroups) == 0 {
@@ -69,7 +78,7 @@ func (payload *edgeJobCreateFromFileContentPayload) Validate(r *http.Request) er
 		return errors.New("invalid script file content")
 	}
 
-	return nil
+	return scheduler.ValidateTimeZone(payload.TimeZone)
 }
 
 // @id EdgeJobCreateString
@@ -141,11 +150,28 @@ func (payload *edgeJobCreateFromFilePayload) Validate(r *http.Request) error {
 	}
 	payload.Name = name
 
-	cronExpression, err := request.RetrieveMultiPartFormValue(r, "CronExpression", false)
+	cronExpression, _ := request.RetrieveMultiPartFormValue(r, "CronExpression", true)
+	payload.CronExpression = cronExpression
+
+	runAt, err := request.RetrieveNumericMultiPartFormValue(r, "RunAt", true)
 	if err != nil {
-		return errors.New("invalid cron expression")
+		return errors.New("invalid run time")
 	}
-	payload.CronExpression = cronExpression
+	payload.RunAt = int64(runAt)
+
+	if err := validateSchedule(payload.CronExpression, payload.RunAt, payload.Recurring); err != nil {
+		return err
+	}
+
+	if err := validateRunAt(payload.RunAt); err != nil {
+		return err
+	}
+
+	timeZone, _ := request.RetrieveMultiPartFormValue(r, "TimeZone", true)
+	if err := scheduler.ValidateTimeZone(timeZone); err != nil {
+		return err
+	}
+	payload.TimeZone = timeZone
 
 	var endpoints []portainer.EndpointID
 	if err := request.RetrieveMultiPartFormJSONValue(r, "Endpoints", &endpoints, true); err != nil {
@@ -182,10 +208,12 @@ func (payload *edgeJobCreateFromFilePayload) Validate(r *http.Request) error {
 // @produce json
 // @param file formData file true "Content of the Stack file"
 // @param Name formData string true "Name of the stack"
-// @param CronExpression formData string true "A cron expression to schedule this job"
+// @param CronExpression formData string false "A cron expression to schedule this job, required without RunAt"
 // @param EdgeGroups formData string true "JSON stringified array of Edge Groups ids"
 // @param Endpoints formData string true "JSON stringified array of Environment ids"
 // @param Recurring formData bool false "If recurring"
+// @param TimeZone formData string false "IANA time zone the cron expression runs in, defaults to the time zone of each environment"
+// @param RunAt formData int false "Unix timestamp of the single run of a one-time job, it replaces the cron expression"
 // @success 200 {object} portainer.EdgeGroup
 // @failure 503 "Edge compute features are disabled"
 // @failure 500
@@ -213,6 +241,8 @@ func (handler *Handler) createEdgeJobObjectFromPayload(tx dataservices.DataStore
 		Name:                payload.Name,
 		CronExpression:      payload.CronExpression,
 		Recurring:           payload.Recurring,
+		TimeZone:            payload.TimeZone,
+		RunAt:               payload.RunAt,
 		Created:             time.Now().Unix(),
 		Endpoints:           convertEndpointsToMetaObject(payload.Endpoints),
 		EdgeGroups:          payload.EdgeGroups,
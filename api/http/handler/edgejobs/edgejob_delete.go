@@ -2,6 +2,7 @@ package edgejobs
 
 import (
 	"errors"
+	"io/fs"
 	"maps"
 	"net/http"
 	"strconv"
@@ -77,6 +78,11 @@ func (handler *Handler) deleteEdgeJob(tx dataservices.DataStoreTx, edgeJobID por
 
 	for endpointID := range endpointsMap {
 		cache.Del(endpointID)
+
+		// The logs are not in the folder of the Edge job when they are stored in the object storage
+		if err := handler.FileService.ClearEdgeJobTaskLogs(strconv.Itoa(int(edgeJobID)), strconv.Itoa(int(endpointID))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Warn().Err(err).Msg("Unable to remove the logs of the Edge job")
+		}
 	}
 
 	if err := tx.EdgeJob().Delete(edgeJob.ID); err != nil {
@@ -0,0 +1,121 @@
+package edgejobs
+
+import (
+	"cmp"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/edge"
+	"github.com/portainer/portainer/api/scheduler"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+const defaultPreviewedRuns = 5
+
+type edgeJobNextRuns struct {
+	EndpointID portainer.EndpointID `json:"EndpointId" example:"1"`
+	// IANA time zone the run times are computed in, empty for the time zone of the server
+	TimeZone string `json:"TimeZone" example:"Europe/Paris"`
+	// Next run times, with the offset of the time zone at that time
+	Runs []time.Time `json:"Runs"`
+}
+
+// @id EdgeJobNextRuns
+// @summary Preview the next run times of an EdgeJob
+// @description Compute the next run times of an EdgeJob on each of its environments(endpoints), in the time zone
+// @description of the job or of the environment. A one-time job has a single run until its run time has passed.
+// @description **Access policy**: administrator
+// @tags edge_jobs
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "EdgeJob Id"
+// @param count query int false "Number of run times, defaults to 5, up to 100"
+// @success 200 {array} edgeJobNextRuns
+// @failure 400
+// @failure 404
+// @failure 500
+// @failure 503 "Edge compute features are disabled"
+// @router /edge_jobs/{id}/next_runs [get]
+func (handler *Handler) edgeJobNextRuns(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	edgeJobID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid Edge job identifier route variable", err)
+	}
+
+	count, err := request.RetrieveNumericQueryParameter(r, "count", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: count", err)
+	} else if count < 0 || count > scheduler.MaxPreviewedRuns {
+		return httperror.BadRequest("Invalid query parameter: count", errors.New("count must be between 1 and 100"))
+	}
+
+	var nextRuns []edgeJobNextRuns
+	err = handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		nextRuns, err = computeNextRuns(tx, portainer.EdgeJobID(edgeJobID), time.Now(), cmp.Or(count, defaultPreviewedRuns))
+
+		return err
+	})
+
+	return txResponse(w, nextRuns, err)
+}
+
+func computeNextRuns(tx dataservices.DataStoreTx, edgeJobID portainer.EdgeJobID, after time.Time, count int) ([]edgeJobNextRuns, error) {
+	edgeJob, err := tx.EdgeJob().Read(edgeJobID)
+	if tx.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find an Edge job with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find an Edge job with the specified identifier inside the database", err)
+	}
+
+	endpointIDs, err := edge.GetEndpointsFromEdgeGroups(edgeJob.EdgeGroups, tx)
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to get Endpoints from EdgeGroups", err)
+	}
+
+	for endpointID := range edgeJob.Endpoints {
+		if !slices.Contains(endpointIDs, endpointID) {
+			endpointIDs = append(endpointIDs, endpointID)
+		}
+	}
+
+	slices.Sort(endpointIDs)
+
+	nextRuns := make([]edgeJobNextRuns, 0, len(endpointIDs))
+
+	for _, endpointID := range endpointIDs {
+		endpoint, err := tx.Endpoint().Endpoint(endpointID)
+		if tx.IsErrObjectNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+		}
+
+		if edgeJob.RunAt != 0 {
+			runs := []time.Time{}
+			if runAt := time.Unix(edgeJob.RunAt, 0).UTC(); runAt.After(after) {
+				runs = append(runs, runAt)
+			}
+
+			nextRuns = append(nextRuns, edgeJobNextRuns{EndpointID: endpointID, TimeZone: "UTC", Runs: runs})
+
+			continue
+		}
+
+		timeZone := cmp.Or(edgeJob.TimeZone, endpoint.TimeZone)
+
+		runs, err := scheduler.NextRuns(edgeJob.CronExpression, timeZone, after, count)
+		if err != nil {
+			return nil, httperror.InternalServerError("Unable to compute the run times of the Edge job", err)
+		}
+
+		nextRuns = append(nextRuns, edgeJobNextRuns{EndpointID: endpointID, TimeZone: timeZone, Runs: runs})
+	}
+
+	return nextRuns, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/internal/edge"
 	"github.com/portainer/portainer/api/internal/edge/cache"
+	"github.com/portainer/portainer/api/pendingactions/handlers"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -95,6 +96,10 @@ func (handler *Handler) clearEdgeJobTaskLogs(tx dataservices.DataStoreTx, edgeJo
 		return httperror.InternalServerError("Unable to persist Edge job changes in the database", err)
 	}
 
+	if err := handlers.DequeueEdgeJobLogsCollection(tx, endpointID, edgeJobID); err != nil {
+		return httperror.InternalServerError("Unable to remove the logs collection request from the database", err)
+	}
+
 	cache.Del(endpointID)
 
 	return nil
@@ -9,6 +9,7 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/internal/edge"
 	"github.com/portainer/portainer/api/internal/edge/cache"
+	"github.com/portainer/portainer/api/pendingactions/handlers"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -69,6 +70,10 @@ func (handler *Handler) edgeJobTasksCollect(w http.ResponseWriter, r *http.Reque
 			return httperror.InternalServerError("Unable to persist Edge job changes in the database", err)
 		}
 
+		if err := handlers.QueueEdgeJobLogsCollection(tx, endpointID, edgeJob.ID); err != nil {
+			return httperror.InternalServerError("Unable to persist the logs collection request in the database", err)
+		}
+
 		endpoint, err := tx.Endpoint().Endpoint(endpointID)
 		if err != nil {
 			return httperror.InternalServerError("Unable to retrieve environment from the database", err)
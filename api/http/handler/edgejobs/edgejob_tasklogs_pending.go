@@ -0,0 +1,44 @@
+package edgejobs
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/pendingactions/handlers"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+// @id EdgeJobTaskLogsPendingList
+// @summary Fetch the pending logs collections
+// @description Fetch the logs collections that were requested but not fulfilled by the agents yet.
+// @description **Access policy**: administrator
+// @tags edge_jobs
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param endpointId query int false "Only return the logs collections of this environment(endpoint)"
+// @success 200 {array} handlers.PendingEdgeJobLogsCollection
+// @failure 500
+// @failure 400
+// @failure 503 "Edge compute features are disabled"
+// @router /edge_jobs/logs/pending [get]
+func (handler *Handler) edgeJobTaskLogsPendingList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericQueryParameter(r, "endpointId", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: endpointId", err)
+	}
+
+	var collections []handlers.PendingEdgeJobLogsCollection
+	err = handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		collections, err = handlers.PendingEdgeJobLogsCollections(tx, portainer.EndpointID(endpointID))
+		if err != nil {
+			return httperror.InternalServerError("Unable to retrieve the pending logs collections from the database", err)
+		}
+
+		return nil
+	})
+
+	return txResponse(w, collections, err)
+}
@@ -0,0 +1,124 @@
+package edgejobs
+
+import (
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+const logStreamWriteTimeout = 10 * time.Second
+
+// @id EdgeJobTaskLogsStream
+// @summary Stream the log of a specific task on an EdgeJob
+// @description Open a websocket sending the output of the task collected so far, followed by its live output while the task is running.
+// @description The connection is closed by the server once the task is over. The token can be passed with the token query parameter.
+// @description **Access policy**: administrator
+// @tags edge_jobs
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "EdgeJob Id"
+// @param taskID path int true "Task Id"
+// @success 101 "Switching protocols"
+// @failure 400
+// @failure 404
+// @failure 500
+// @failure 503 "Edge compute features are disabled"
+// @router /edge_jobs/{id}/tasks/{taskID}/logs/stream [get]
+func (handler *Handler) edgeJobTaskLogsStream(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	edgeJobID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid Edge job identifier route variable", err)
+	}
+
+	taskID, err := request.RetrieveNumericRouteVariableValue(r, "taskID")
+	if err != nil {
+		return httperror.BadRequest("Invalid Task identifier route variable", err)
+	}
+
+	edgeJob, err := handler.DataStore.EdgeJob().Read(portainer.EdgeJobID(edgeJobID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an Edge job with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an Edge job with the specified identifier inside the database", err)
+	}
+
+	backlog, subscription, err := handler.EdgeJobLogStreamer.Follow(edgeJob.ID, portainer.EndpointID(taskID))
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve log file from disk", err)
+	}
+	defer subscription.Close()
+
+	// Only the tasks still producing output, or whose logs collection is pending, are followed
+	follow := handler.EdgeJobLogStreamer.IsRunning(edgeJob.ID, portainer.EndpointID(taskID)) ||
+		taskLogsStatus(edgeJob, portainer.EndpointID(taskID)) == portainer.EdgeJobLogsStatusPending
+
+	websocketConn, err := handler.connectionUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return httperror.InternalServerError("Unable to upgrade the connection", err)
+	}
+	defer websocketConn.Close()
+
+	// The client never sends anything, reading is only needed to notice that the connection was closed
+	clientGone := make(chan struct{})
+	go func() {
+		defer close(clientGone)
+
+		for {
+			if _, _, err := websocketConn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if backlog != "" {
+		if err := writeLogMessage(websocketConn, []byte(backlog)); err != nil {
+			return nil
+		}
+	}
+
+	for follow {
+		select {
+		case chunk, ok := <-subscription.C:
+			if !ok {
+				follow = false
+
+				continue
+			}
+
+			if err := writeLogMessage(websocketConn, chunk); err != nil {
+				return nil
+			}
+		case <-clientGone:
+			return nil
+		}
+	}
+
+	closeMessage := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err := websocketConn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(logStreamWriteTimeout)); err != nil {
+		log.Debug().Err(err).Msg("unable to close the edge job logs websocket")
+	}
+
+	return nil
+}
+
+func writeLogMessage(websocketConn *websocket.Conn, data []byte) error {
+	if err := websocketConn.SetWriteDeadline(time.Now().Add(logStreamWriteTimeout)); err != nil {
+		return err
+	}
+
+	return websocketConn.WriteMessage(websocket.TextMessage, data)
+}
+
+func taskLogsStatus(edgeJob *portainer.EdgeJob, endpointID portainer.EndpointID) portainer.EdgeJobLogsStatus {
+	if meta, ok := edgeJob.GroupLogsCollection[endpointID]; ok {
+		return meta.LogsStatus
+	}
+
+	return edgeJob.Endpoints[endpointID].LogsStatus
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/portainer/portainer/api/internal/edge"
 	"github.com/portainer/portainer/api/internal/edge/cache"
 	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/scheduler"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 
@@ -25,6 +26,10 @@ type edgeJobUpdatePayload struct {
 	Endpoints      []portainer.EndpointID
 	EdgeGroups     []portainer.EdgeGroupID
 	FileContent    *string
+	// IANA time zone the cron expression runs in, empty for the time zone of each environment
+	TimeZone *string `example:"Europe/Paris"`
+	// Unix timestamp of the single run of a one-time job, 0 to run the job on its cron expression again
+	RunAt *int64 `example:"1735689600"`
 }
 
 func (payload *edgeJobUpdatePayload) Validate(r *http.Request) error {
@@ -32,6 +37,16 @@ func (payload *edgeJobUpdatePayload) Validate(r *http.Request) error {
 		return errors.New("invalid Edge job name format. Allowed characters are: [a-zA-Z0-9_.-]")
 	}
 
+	if payload.TimeZone != nil {
+		if err := scheduler.ValidateTimeZone(*payload.TimeZone); err != nil {
+			return err
+		}
+	}
+
+	if payload.RunAt != nil {
+		return validateRunAt(*payload.RunAt)
+	}
+
 	return nil
 }
 
@@ -82,6 +97,10 @@ func (handler *Handler) updateEdgeJob(tx dataservices.DataStoreTx, edgeJobID por
 		return nil, httperror.InternalServerError("Unable to update Edge job", err)
 	}
 
+	if err := validateSchedule(edgeJob.CronExpression, edgeJob.RunAt, edgeJob.Recurring); err != nil {
+		return nil, httperror.BadRequest("Invalid Edge job schedule", err)
+	}
+
 	if err := tx.EdgeJob().Update(edgeJob.ID, edgeJob); err != nil {
 		return nil, httperror.InternalServerError("Unable to persist Edge job changes inside the database", err)
 	}
@@ -212,6 +231,17 @@ func (handler *Handler) updateEdgeSchedule(tx dataservices.DataStoreTx, edgeJob
 		updateVersion = true
 	}
 
+	if payload.TimeZone != nil && *payload.TimeZone != edgeJob.TimeZone {
+		edgeJob.TimeZone = *payload.TimeZone
+		updateVersion = true
+	}
+
+	// The agents learn about the new run time through the version of the job
+	if payload.RunAt != nil && *payload.RunAt != edgeJob.RunAt {
+		edgeJob.RunAt = *payload.RunAt
+		updateVersion = true
+	}
+
 	if updateVersion {
 		edgeJob.Version++
 	}
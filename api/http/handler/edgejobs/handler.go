@@ -3,15 +3,18 @@ package edgejobs
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/middlewares"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/edge/joblogs"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/response"
 
 	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
 )
 
 // Handler is the HTTP handler used to handle Edge job operations.
@@ -20,12 +23,15 @@ type Handler struct {
 	DataStore            dataservices.DataStore
 	FileService          portainer.FileService
 	ReverseTunnelService portainer.ReverseTunnelService
+	EdgeJobLogStreamer   *joblogs.Streamer
+	connectionUpgrader   websocket.Upgrader
 }
 
 // NewHandler creates a handler to manage Edge job operations.
 func NewHandler(bouncer security.BouncerService) *Handler {
 	h := &Handler{
-		Router: mux.NewRouter(),
+		Router:             mux.NewRouter(),
+		connectionUpgrader: websocket.Upgrader{},
 	}
 
 	h.Handle("/edge_jobs",
@@ -34,12 +40,16 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 		bouncer.AdminAccess(bouncer.EdgeComputeOperation(middlewares.Deprecated(h, deprecatedEdgeJobCreateUrlParser)))).Methods(http.MethodPost)
 	h.Handle("/edge_jobs/create/{method}",
 		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeJobCreate)))).Methods(http.MethodPost)
+	h.Handle("/edge_jobs/logs/pending",
+		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeJobTaskLogsPendingList)))).Methods(http.MethodGet)
 	h.Handle("/edge_jobs/{id}",
 		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeJobInspect)))).Methods(http.MethodGet)
 	h.Handle("/edge_jobs/{id}",
 		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeJobUpdate)))).Methods(http.MethodPut)
 	h.Handle("/edge_jobs/{id}",
 		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeJobDelete)))).Methods(http.MethodDelete)
+	h.Handle("/edge_jobs/{id}/next_runs",
+		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeJobNextRuns)))).Methods(http.MethodGet)
 	h.Handle("/edge_jobs/{id}/file",
 		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeJobFile)))).Methods(http.MethodGet)
 	h.Handle("/edge_jobs/{id}/tasks",
@@ -50,6 +60,8 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeJobTasksCollect)))).Methods(http.MethodPost)
 	h.Handle("/edge_jobs/{id}/tasks/{taskID}/logs",
 		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeJobTasksClear)))).Methods(http.MethodDelete)
+	h.Handle("/edge_jobs/{id}/tasks/{taskID}/logs/stream",
+		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeJobTaskLogsStream)))).Methods(http.MethodGet)
 
 	return h
 }
@@ -76,3 +88,26 @@ func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError
 
 	return response.JSON(w, r)
 }
+
+// validateSchedule ensures that an Edge job runs either on a cron expression or once at a given time
+func validateSchedule(cronExpression string, runAt int64, recurring bool) error {
+	switch {
+	case cronExpression == "" && runAt == 0:
+		return errors.New("invalid cron expression")
+	case cronExpression != "" && runAt != 0:
+		return errors.New("an Edge job cannot have both a cron expression and a run time")
+	case runAt != 0 && recurring:
+		return errors.New("a one-time Edge job cannot be recurring")
+	}
+
+	return nil
+}
+
+// validateRunAt ensures that the run time of a one-time Edge job is in the future
+func validateRunAt(runAt int64) error {
+	if runAt != 0 && !time.Unix(runAt, 0).After(time.Now()) {
+		return errors.New("the run time of the Edge job must be in the future")
+	}
+
+	return nil
+}
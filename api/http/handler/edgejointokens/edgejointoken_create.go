@@ -0,0 +1,132 @@
+package edgejointokens
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/edge"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+// maxTokensPerRequest is the largest number of tokens generated by a single request
+const maxTokensPerRequest = 500
+
+type edgeJoinTokenCreatePayload struct {
+	// Number of tokens to generate, defaults to 1
+	Count int `example:"10"`
+	// Description of the tokens
+	Description string `example:"warehouse gateways"`
+	// Environment(Endpoint) group the registered environments(endpoints) are added to, defaults to the Unassigned group
+	GroupID portainer.EndpointGroupID `example:"1"`
+	// Tags assigned to the registered environments(endpoints)
+	TagIDs []portainer.TagID `example:"1,2"`
+	// Whether the registered environments(endpoints) are trusted right away
+	AutoTrust bool `example:"true"`
+	// Validity of the tokens in seconds, defaults to 24 hours and cannot exceed 30 days
+	ExpiresIn int `example:"3600"`
+}
+
+type edgeJoinTokenCreateResponse struct {
+	// Token to hand to the Edge agent, it is only returned once
+	Token string `json:"Token" example:"ejt_4f9a..."`
+	portainer.EdgeJoinToken
+}
+
+func (payload *edgeJoinTokenCreatePayload) Validate(r *http.Request) error {
+	if payload.Count == 0 {
+		payload.Count = 1
+	}
+
+	if payload.Count < 0 || payload.Count > maxTokensPerRequest {
+		return errors.New("invalid count. Must be between 1 and 500")
+	}
+
+	if payload.ExpiresIn < 0 || time.Duration(payload.ExpiresIn)*time.Second > edge.JoinTokenMaxTTL {
+		return errors.New("invalid validity. Must not exceed 30 days")
+	}
+
+	if payload.GroupID == 0 {
+		payload.GroupID = 1
+	}
+
+	return nil
+}
+
+// @id EdgeJoinTokenCreate
+// @summary Generate Edge join tokens
+// @description Generate single-use tokens an Edge agent can redeem, through /endpoints/edge/join, to register itself
+// @description as a new Edge environment without a pre-created Edge key. The registered environment is added to
+// @description the group and tags of the token and is trusted right away when the token auto-trusts them.
+// @description **Access policy**: administrator
+// @tags edge_join_tokens
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body edgeJoinTokenCreatePayload true "Token details"
+// @success 200 {array} edgeJoinTokenCreateResponse
+// @failure 400
+// @failure 500
+// @router /edge_join_tokens [post]
+func (handler *Handler) edgeJoinTokenCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload edgeJoinTokenCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var tokens []edgeJoinTokenCreateResponse
+	err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		var err error
+		tokens, err = createTokens(tx, &payload, time.Now())
+
+		return err
+	})
+
+	return txResponse(w, tokens, err)
+}
+
+func createTokens(tx dataservices.DataStoreTx, payload *edgeJoinTokenCreatePayload, now time.Time) ([]edgeJoinTokenCreateResponse, error) {
+	if _, err := tx.EndpointGroup().Read(payload.GroupID); tx.IsErrObjectNotFound(err) {
+		return nil, httperror.BadRequest("Unable to find an environment group with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find an environment group with the specified identifier inside the database", err)
+	}
+
+	for _, tagID := range payload.TagIDs {
+		if _, err := tx.Tag().Read(tagID); tx.IsErrObjectNotFound(err) {
+			return nil, httperror.BadRequest("Unable to find a tag with the specified identifier inside the database", err)
+		} else if err != nil {
+			return nil, httperror.InternalServerError("Unable to find a tag with the specified identifier inside the database", err)
+		}
+	}
+
+	ttl := edge.JoinTokenDefaultTTL
+	if payload.ExpiresIn > 0 {
+		ttl = time.Duration(payload.ExpiresIn) * time.Second
+	}
+
+	tokens := make([]edgeJoinTokenCreateResponse, 0, payload.Count)
+	for i := 0; i < payload.Count; i++ {
+		rawToken, token, err := edge.NewJoinToken(now, ttl)
+		if err != nil {
+			return nil, httperror.InternalServerError("Unable to generate the Edge join token", err)
+		}
+
+		token.Description = payload.Description
+		token.GroupID = payload.GroupID
+		token.TagIDs = payload.TagIDs
+		token.AutoTrust = payload.AutoTrust
+
+		if err := tx.EdgeJoinToken().Create(token); err != nil {
+			return nil, httperror.InternalServerError("Unable to persist the Edge join token inside the database", err)
+		}
+
+		tokens = append(tokens, edgeJoinTokenCreateResponse{Token: rawToken, EdgeJoinToken: hideDigest(*token)})
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,44 @@
+package edgejointokens
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id EdgeJoinTokenDelete
+// @summary Revoke an Edge join token
+// @description The environment registered with the token, if any, is left untouched.
+// @description **Access policy**: administrator
+// @tags edge_join_tokens
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Edge join token identifier"
+// @success 204
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /edge_join_tokens/{id} [delete]
+func (handler *Handler) edgeJoinTokenDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokenID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid Edge join token identifier route variable", err)
+	}
+
+	id := portainer.EdgeJoinTokenID(tokenID)
+
+	if _, err := handler.DataStore.EdgeJoinToken().Read(id); handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an Edge join token with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an Edge join token with the specified identifier inside the database", err)
+	}
+
+	if err := handler.DataStore.EdgeJoinToken().Delete(id); err != nil {
+		return httperror.InternalServerError("Unable to remove the Edge join token from the database", err)
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,34 @@
+package edgejointokens
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id EdgeJoinTokenList
+// @summary List the Edge join tokens
+// @description The tokens themselves are only returned on creation.
+// @description **Access policy**: administrator
+// @tags edge_join_tokens
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.EdgeJoinToken
+// @failure 500
+// @router /edge_join_tokens [get]
+func (handler *Handler) edgeJoinTokenList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokens, err := handler.DataStore.EdgeJoinToken().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the Edge join tokens from the database", err)
+	}
+
+	list := make([]portainer.EdgeJoinToken, 0, len(tokens))
+	for _, token := range tokens {
+		list = append(list, hideDigest(token))
+	}
+
+	return response.JSON(w, list)
+}
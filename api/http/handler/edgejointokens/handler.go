@@ -0,0 +1,56 @@
+package edgejointokens
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is the HTTP handler used to handle Edge join token operations.
+type Handler struct {
+	*mux.Router
+	DataStore dataservices.DataStore
+}
+
+// NewHandler creates a handler to manage Edge join token operations.
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+
+	h.Handle("/edge_join_tokens",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.edgeJoinTokenList))).Methods(http.MethodGet)
+	h.Handle("/edge_join_tokens",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.edgeJoinTokenCreate))).Methods(http.MethodPost)
+	h.Handle("/edge_join_tokens/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.edgeJoinTokenDelete))).Methods(http.MethodDelete)
+
+	return h
+}
+
+// hideDigest removes the digest of the token from the responses
+func hideDigest(token portainer.EdgeJoinToken) portainer.EdgeJoinToken {
+	token.Digest = ""
+
+	return token
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
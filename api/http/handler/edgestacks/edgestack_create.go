@@ -25,10 +25,8 @@ func (handler *Handler) edgeStackCreate(w http.ResponseWriter, r *http.Request)
 		return httperror.InternalServerError("Unable to retrieve user details from authentication token", err)
 	}
 
-	var edgeStack *portainer.EdgeStack
-	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
-		edgeStack, err = handler.createSwarmStack(tx, method, dryrun, tokenData.ID, r)
-		return err
+	edgeStack, err := dataservices.UpdateTx(handler.DataStore, func(tx dataservices.DataStoreTx) (*portainer.EdgeStack, error) {
+		return handler.createSwarmStack(tx, method, dryrun, tokenData.ID, r)
 	})
 	if err != nil {
 		switch {
@@ -41,6 +39,10 @@ func (handler *Handler) edgeStackCreate(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	if !dryrun {
+		handler.recordDeployment(edgeStack, tokenData.Username)
+	}
+
 	return response.JSON(w, edgeStack)
 }
 
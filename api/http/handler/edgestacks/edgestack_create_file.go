@@ -6,6 +6,7 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
 	httperrors "github.com/portainer/portainer/api/http/errors"
+	edgestackutils "github.com/portainer/portainer/api/internal/edge/edgestacks"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 
 	"github.com/pkg/errors"
@@ -23,6 +24,8 @@ type edgeStackFromFileUploadPayload struct {
 	Registries     []portainer.RegistryID
 	// Uses the manifest's namespaces instead of the default one
 	UseManifestNamespaces bool
+	// Rolls out the new versions in stages, the whole stack is updated at once when empty
+	RolloutPolicy *portainer.EdgeStackRolloutPolicy
 }
 
 func (payload *edgeStackFromFileUploadPayload) Validate(r *http.Request) error {
@@ -64,6 +67,17 @@ func (payload *edgeStackFromFileUploadPayload) Validate(r *http.Request) error {
 	useManifestNamespaces, _ := request.RetrieveBooleanMultiPartFormValue(r, "UseManifestNamespaces", true)
 	payload.UseManifestNamespaces = useManifestNamespaces
 
+	var rolloutPolicy *portainer.EdgeStackRolloutPolicy
+	err = request.RetrieveMultiPartFormJSONValue(r, "RolloutPolicy", &rolloutPolicy, true)
+	if err != nil {
+		return httperrors.NewInvalidPayloadError("Invalid rollout policy")
+	}
+
+	if err := edgestackutils.ValidateRolloutPolicy(rolloutPolicy); err != nil {
+		return httperrors.NewInvalidPayloadError(err.Error())
+	}
+	payload.RolloutPolicy = rolloutPolicy
+
 	return nil
 }
 
@@ -80,6 +94,7 @@ func (payload *edgeStackFromFileUploadPayload) Validate(r *http.Request) error {
 // @param EdgeGroups formData string true "JSON stringified array of Edge Groups ids"
 // @param DeploymentType formData int true "deploy type 0 - 'compose', 1 - 'kubernetes'"
 // @param Registries formData string false "JSON stringified array of Registry ids to use for this stack"
+// @param RolloutPolicy formData string false "JSON stringified rollout policy applied to the new versions of the stack"
 // @param UseManifestNamespaces formData bool false "Uses the manifest's namespaces instead of the default one, relevant only for kube environments"
 // @param PrePullImage formData bool false "Pre Pull image"
 // @param RetryDeploy formData bool false "Retry deploy"
@@ -101,6 +116,8 @@ func (handler *Handler) createEdgeStackFromFileUpload(r *http.Request, tx datase
 		return nil, errors.Wrap(err, "failed to create edge stack object")
 	}
 
+	stack.RolloutPolicy = payload.RolloutPolicy
+
 	if dryrun {
 		return stack, nil
 	}
@@ -7,8 +7,10 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/git"
 	gittypes "github.com/portainer/portainer/api/git/types"
 	httperrors "github.com/portainer/portainer/api/http/errors"
+	edgestackutils "github.com/portainer/portainer/api/internal/edge/edgestacks"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 
 	"github.com/asaskevich/govalidator"
@@ -41,6 +43,8 @@ type edgeStackFromGitRepositoryPayload struct {
 	Registries []portainer.RegistryID
 	// Uses the manifest's namespaces instead of the default one
 	UseManifestNamespaces bool
+	// Rolls out the new versions in stages, the whole stack is updated at once when empty
+	RolloutPolicy *portainer.EdgeStackRolloutPolicy
 	// TLSSkipVerify skips SSL verification when cloning the Git repository
 	TLSSkipVerify bool `example:"false"`
 }
@@ -75,6 +79,10 @@ func (payload *edgeStackFromGitRepositoryPayload) Validate(r *http.Request) erro
 		return httperrors.NewInvalidPayloadError("Invalid edge groups. At least one edge group must be specified")
 	}
 
+	if err := edgestackutils.ValidateRolloutPolicy(payload.RolloutPolicy); err != nil {
+		return httperrors.NewInvalidPayloadError(err.Error())
+	}
+
 	return nil
 }
 
@@ -104,6 +112,8 @@ func (handler *Handler) createEdgeStackFromGitRepository(r *http.Request, tx dat
 		return nil, errors.Wrap(err, "failed to create edge stack object")
 	}
 
+	stack.RolloutPolicy = payload.RolloutPolicy
+
 	if dryrun {
 		return stack, nil
 	}
@@ -143,8 +153,9 @@ func (handler *Handler) storeManifestFromGitRepository(tx dataservices.DataStore
 		repositoryUsername = repositoryConfig.Authentication.Username
 		repositoryPassword = repositoryConfig.Authentication.Password
 	}
+	sshPrivateKey, sshPassphrase := git.GetSSHCredentials(repositoryConfig.Authentication)
 
-	err = handler.GitService.CloneRepository(projectPath, repositoryConfig.URL, repositoryConfig.ReferenceName, repositoryUsername, repositoryPassword, repositoryConfig.TLSSkipVerify)
+	err = handler.GitService.CloneRepository(projectPath, repositoryConfig.URL, repositoryConfig.ReferenceName, repositoryUsername, repositoryPassword, sshPrivateKey, sshPassphrase, repositoryConfig.TLSSkipVerify, repositoryConfig.CloneOptions())
 	if err != nil {
 		return "", "", "", err
 	}
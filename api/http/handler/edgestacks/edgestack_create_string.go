@@ -8,6 +8,7 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/filesystem"
 	httperrors "github.com/portainer/portainer/api/http/errors"
+	edgestackutils "github.com/portainer/portainer/api/internal/edge/edgestacks"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 
 	"github.com/pkg/errors"
@@ -29,6 +30,8 @@ type edgeStackFromStringPayload struct {
 	Registries []portainer.RegistryID
 	// Uses the manifest's namespaces instead of the default one
 	UseManifestNamespaces bool
+	// Rolls out the new versions in stages, the whole stack is updated at once when empty
+	RolloutPolicy *portainer.EdgeStackRolloutPolicy
 }
 
 func (payload *edgeStackFromStringPayload) Validate(r *http.Request) error {
@@ -48,6 +51,10 @@ func (payload *edgeStackFromStringPayload) Validate(r *http.Request) error {
 		return httperrors.NewInvalidPayloadError("Invalid deployment type")
 	}
 
+	if err := edgestackutils.ValidateRolloutPolicy(payload.RolloutPolicy); err != nil {
+		return httperrors.NewInvalidPayloadError(err.Error())
+	}
+
 	return nil
 }
 
@@ -77,6 +84,8 @@ func (handler *Handler) createEdgeStackFromFileContent(r *http.Request, tx datas
 		return nil, errors.Wrap(err, "failed to create Edge stack object")
 	}
 
+	stack.RolloutPolicy = payload.RolloutPolicy
+
 	if dryrun {
 		return stack, nil
 	}
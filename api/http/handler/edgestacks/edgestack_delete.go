@@ -2,13 +2,17 @@ package edgestacks
 
 import (
 	"errors"
+	"io/fs"
 	"net/http"
+	"strconv"
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/rs/zerolog/log"
 )
 
 // @id EdgeStackDelete
@@ -57,5 +61,11 @@ func (handler *Handler) deleteEdgeStack(tx dataservices.DataStoreTx, edgeStackID
 		return httperror.InternalServerError("Unable to delete edge stack", err)
 	}
 
+	for endpointID := range edgeStack.Status {
+		if err := handler.FileService.ClearEdgeStackDeploymentLog(strconv.Itoa(int(edgeStack.ID)), strconv.Itoa(int(endpointID))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Warn().Err(err).Int("endpoint_id", int(endpointID)).Msg("Unable to remove the deployment logs of the edge stack")
+		}
+	}
+
 	return nil
 }
@@ -2,6 +2,9 @@ package edgestacks
 
 import (
 	"net/http"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
 
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -25,5 +28,10 @@ func (handler *Handler) edgeStackList(w http.ResponseWriter, r *http.Request) *h
 		return httperror.InternalServerError("Unable to retrieve edge stacks from the database", err)
 	}
 
+	// The stacks of the edge update schedules are managed by their schedule
+	edgeStacks = slices.DeleteFunc(edgeStacks, func(stack portainer.EdgeStack) bool {
+		return stack.EdgeUpdateID != 0
+	})
+
 	return response.JSON(w, edgeStacks)
 }
@@ -0,0 +1,58 @@
+package edgestacks
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"strconv"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type deploymentLogsResponse struct {
+	FileContent string `json:"FileContent"`
+}
+
+// @id EdgeStackLogsInspect
+// @summary Fetch the deployment logs of an EdgeStack for an environment
+// @description Fetch the output of the last deployment of an edge stack, as sent by the agent of the environment.
+// @description **Access policy**: administrator
+// @tags edge_stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "EdgeStack Id"
+// @param endpointId path int true "Environment Id"
+// @success 200 {object} deploymentLogsResponse
+// @failure 500
+// @failure 400
+// @failure 404 "No logs were sent by the environment"
+// @failure 503 "Edge compute features are disabled"
+// @router /edge_stacks/{id}/logs/{endpointId} [get]
+func (handler *Handler) edgeStackLogsInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	edgeStackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid edge stack identifier route variable", err)
+	}
+
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "endpointId")
+	if err != nil {
+		return httperror.BadRequest("Invalid environment identifier route variable", err)
+	}
+
+	if _, err := handler.DataStore.EdgeStack().EdgeStack(portainer.EdgeStackID(edgeStackID)); err != nil {
+		return handler.handlerDBErr(err, "Unable to find an edge stack with the specified identifier inside the database")
+	}
+
+	logFileContent, err := handler.FileService.GetEdgeStackDeploymentLogFileContent(strconv.Itoa(edgeStackID), strconv.Itoa(endpointID))
+	if errors.Is(err, fs.ErrNotExist) {
+		return httperror.NotFound("No deployment logs were sent by the environment", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the deployment logs", err)
+	}
+
+	return response.JSON(w, &deploymentLogsResponse{FileContent: logFileContent})
+}
@@ -0,0 +1,44 @@
+package edgestacks
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgeStackLogsInspect(t *testing.T) {
+	handler, rawAPIKey := setupHandler(t)
+
+	endpoint := createEndpoint(t, handler.DataStore)
+	edgeStack := createEdgeStack(t, handler.DataStore, endpoint.ID)
+
+	inspect := func() *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/edge_stacks/%d/logs/%d", edgeStack.ID, endpoint.ID), nil)
+		require.NoError(t, err)
+
+		req.Header.Add("x-api-key", rawAPIKey)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	// No logs were sent by the agent yet
+	assert.Equal(t, http.StatusNotFound, inspect().Code)
+
+	err := handler.FileService.StoreEdgeStackDeploymentLogFromBytes(strconv.Itoa(int(edgeStack.ID)), strconv.Itoa(int(endpoint.ID)), []byte("service failed to start"))
+	require.NoError(t, err)
+
+	rec := inspect()
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var data deploymentLogsResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&data))
+	assert.Equal(t, "service failed to start", data.FileContent)
+}
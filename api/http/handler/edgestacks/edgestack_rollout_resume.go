@@ -0,0 +1,64 @@
+package edgestacks
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id EdgeStackRolloutResume
+// @summary Resume a halted EdgeStack rollout
+// @description Releases the next batch of a rollout that was halted because its error threshold was exceeded.
+// @description **Access policy**: administrator
+// @tags edge_stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "EdgeStack Id"
+// @success 200 {object} portainer.EdgeStack
+// @failure 400
+// @failure 404
+// @failure 409 "The rollout is not halted"
+// @failure 500
+// @failure 503 "Edge compute features are disabled"
+// @router /edge_stacks/{id}/rollout/resume [post]
+func (handler *Handler) edgeStackRolloutResume(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid stack identifier route variable", err)
+	}
+
+	var stack *portainer.EdgeStack
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		stack, err = tx.EdgeStack().EdgeStack(portainer.EdgeStackID(stackID))
+		if err != nil {
+			return handler.handlerDBErr(err, "Unable to find a stack with the specified identifier inside the database")
+		}
+
+		rollout := stack.Rollout
+		if rollout == nil || !rollout.Halted {
+			return httperror.Conflict("The rollout of this stack is not halted", errors.New("rollout is not halted"))
+		}
+
+		rollout.Halted = false
+		rollout.CurrentBatch++
+		rollout.Completed = rollout.CurrentBatch >= len(rollout.Batches)
+
+		return tx.EdgeStack().UpdateEdgeStack(stack.ID, stack)
+	})
+	if err != nil {
+		var httpErr *httperror.HandlerError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+
+		return httperror.InternalServerError("Unable to persist the stack changes inside the database", err)
+	}
+
+	return response.JSON(w, stack)
+}
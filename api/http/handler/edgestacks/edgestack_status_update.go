@@ -8,6 +8,7 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/events"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -80,6 +81,15 @@ func (handler *Handler) edgeStackStatusUpdate(w http.ResponseWriter, r *http.Req
 		return httperror.InternalServerError("Unexpected error", err)
 	}
 
+	if stack != nil && *payload.Status == portainer.EdgeStackStatusError {
+		events.Publish(events.Event{
+			Type:       portainer.NotificationEventEdgeStackFailure,
+			EndpointID: payload.EndpointID,
+			Title:      "Edge stack deployment failed",
+			Message:    fmt.Sprintf("The edge stack %s failed to deploy: %s", stack.Name, payload.Error),
+		})
+	}
+
 	return response.JSON(w, stack)
 }
 
@@ -5,7 +5,9 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/edge"
+	edgestackutils "github.com/portainer/portainer/api/internal/edge/edgestacks"
 	"github.com/portainer/portainer/api/set"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
@@ -21,6 +23,10 @@ type updateEdgeStackPayload struct {
 	DeploymentType   portainer.EdgeStackDeploymentType
 	// Uses the manifest's namespaces instead of the default one
 	UseManifestNamespaces bool
+	// Rolls out new versions in stages, the whole stack is updated at once when empty
+	RolloutPolicy *portainer.EdgeStackRolloutPolicy
+	// Registries whose credentials are sent to the agents, unchanged when omitted
+	Registries []portainer.RegistryID
 }
 
 func (payload *updateEdgeStackPayload) Validate(r *http.Request) error {
@@ -32,7 +38,7 @@ func (payload *updateEdgeStackPayload) Validate(r *http.Request) error {
 		return errors.New("edge Groups are mandatory for an Edge stack")
 	}
 
-	return nil
+	return edgestackutils.ValidateRolloutPolicy(payload.RolloutPolicy)
 }
 
 // @id EdgeStackUpdate
@@ -62,10 +68,13 @@ func (handler *Handler) edgeStackUpdate(w http.ResponseWriter, r *http.Request)
 		return httperror.BadRequest("Invalid request payload", err)
 	}
 
-	var stack *portainer.EdgeStack
-	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
-		stack, err = handler.updateEdgeStack(tx, portainer.EdgeStackID(stackID), payload)
-		return err
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve user details from authentication token", err)
+	}
+
+	stack, err := dataservices.UpdateTx(handler.DataStore, func(tx dataservices.DataStoreTx) (*portainer.EdgeStack, error) {
+		return handler.updateEdgeStack(tx, portainer.EdgeStackID(stackID), payload)
 	})
 	if err != nil {
 		var httpErr *httperror.HandlerError
@@ -76,6 +85,10 @@ func (handler *Handler) edgeStackUpdate(w http.ResponseWriter, r *http.Request)
 		return httperror.InternalServerError("Unexpected error", err)
 	}
 
+	if payload.UpdateVersion {
+		handler.recordDeployment(stack, tokenData.Username)
+	}
+
 	return response.JSON(w, stack)
 }
 
@@ -96,14 +109,16 @@ func (handler *Handler) updateEdgeStack(tx dataservices.DataStoreTx, stackID por
 	}
 
 	groupsIds := stack.EdgeGroups
+	addedEndpoints := set.Set[portainer.EndpointID]{}
 	if payload.EdgeGroups != nil {
-		newRelated, _, err := handler.handleChangeEdgeGroups(tx, stack.ID, payload.EdgeGroups, relatedEndpointIds, relationConfig)
+		newRelated, endpointsToAdd, err := handler.handleChangeEdgeGroups(tx, stack.ID, payload.EdgeGroups, relatedEndpointIds, relationConfig)
 		if err != nil {
 			return nil, httperror.InternalServerError("Unable to handle edge groups change", err)
 		}
 
 		groupsIds = payload.EdgeGroups
 		relatedEndpointIds = newRelated
+		addedEndpoints = endpointsToAdd
 
 	}
 
@@ -121,11 +136,52 @@ func (handler *Handler) updateEdgeStack(tx dataservices.DataStoreTx, stackID por
 
 	stack.EdgeGroups = groupsIds
 
+	stack.RolloutPolicy = payload.RolloutPolicy
+
+	if payload.Registries != nil {
+		if err := edgestackutils.ValidateRegistries(tx, payload.Registries); err != nil {
+			return nil, httperror.BadRequest("Invalid registries", err)
+		}
+
+		stack.Registries = payload.Registries
+	}
+
 	if payload.UpdateVersion {
+		previousVersion := stack.Version
+		previousRollout := stack.Rollout
+
+		// A change of deployment type targets other environments, it is deployed at once
+		staged := stack.RolloutPolicy != nil && payload.DeploymentType == stack.DeploymentType
+		if staged {
+			if err := handler.storeStackFileVersion(stack); err != nil {
+				return nil, httperror.InternalServerError("Unable to keep the stack file of the previous version", err)
+			}
+		}
+
 		err := handler.updateStackVersion(stack, payload.DeploymentType, []byte(payload.StackFileContent), "", relatedEndpointIds)
 		if err != nil {
 			return nil, httperror.InternalServerError("Unable to update stack version", err)
 		}
+
+		stack.Rollout = nil
+		if staged {
+			if err := handler.storeStackFileVersion(stack); err != nil {
+				return nil, httperror.InternalServerError("Unable to store the stack file of the new version", err)
+			}
+
+			stack.Rollout, err = edgestackutils.NewRollout(stack, previousVersion, relatedEndpointIds, relationConfig)
+			if err != nil {
+				return nil, httperror.BadRequest("Unable to compute the rollout batches", err)
+			}
+		}
+
+		handler.removeStackFileVersions(stack, previousRollout)
+
+		// The environments that were just added already received the deployment of the new version
+		updatedEndpoints := set.ToSet(relatedEndpointIds).Difference(addedEndpoints).Keys()
+		if err := edge.QueueCommands(tx, updatedEndpoints, portainer.EdgeCommandResourceEdgeStack, int(stack.ID), portainer.EdgeCommandOperationUpdate); err != nil {
+			return nil, httperror.InternalServerError("Unable to queue the update of the edge stack", err)
+		}
 	}
 
 	err = tx.EdgeStack().UpdateEdgeStack(stack.ID, stack)
@@ -166,6 +222,10 @@ func (handler *Handler) handleChangeEdgeGroups(tx dataservices.DataStoreTx, edge
 		}
 	}
 
+	if err := edge.QueueCommands(tx, endpointsToRemove.Keys(), portainer.EdgeCommandResourceEdgeStack, int(edgeStackID), portainer.EdgeCommandOperationRemove); err != nil {
+		return nil, nil, errors.WithMessage(err, "Unable to queue the removal of the edge stack")
+	}
+
 	endpointsToAdd := set.Set[portainer.EndpointID]{}
 	for endpointID := range newRelatedSet {
 		if !oldRelatedSet[endpointID] {
@@ -187,5 +247,9 @@ func (handler *Handler) handleChangeEdgeGroups(tx dataservices.DataStoreTx, edge
 		}
 	}
 
+	if err := edge.QueueCommands(tx, endpointsToAdd.Keys(), portainer.EdgeCommandResourceEdgeStack, int(edgeStackID), portainer.EdgeCommandOperationAdd); err != nil {
+		return nil, nil, errors.WithMessage(err, "Unable to queue the deployment of the edge stack")
+	}
+
 	return newRelatedEnvironmentIDs, endpointsToAdd, nil
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/deploymenthistory"
 	"github.com/stretchr/testify/require"
 
 	"github.com/segmentio/encoding/json"
@@ -17,6 +18,7 @@ import (
 // Update
 func TestUpdateAndInspect(t *testing.T) {
 	handler, rawAPIKey := setupHandler(t)
+	handler.DeploymentHistoryService = deploymenthistory.NewService(handler.DataStore)
 
 	endpoint := createEndpoint(t, handler.DataStore)
 	edgeStack := createEdgeStack(t, handler.DataStore, endpoint.ID)
@@ -109,6 +111,12 @@ func TestUpdateAndInspect(t *testing.T) {
 	if !reflect.DeepEqual(updatedStack.EdgeGroups, payload.EdgeGroups) {
 		t.Fatalf("expected EdgeGroups to be equal")
 	}
+
+	deployments, err := handler.DataStore.Deployment().ReadAll()
+	require.NoError(t, err)
+	require.Len(t, deployments, 1)
+	require.Equal(t, edgeStack.ID, deployments[0].EdgeStackID)
+	require.Equal(t, portainer.EdgeStackDeployment, deployments[0].Type)
 }
 
 func TestUpdateWithInvalidEdgeGroups(t *testing.T) {
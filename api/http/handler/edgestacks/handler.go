@@ -7,6 +7,7 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/middlewares"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/deploymenthistory"
 	edgestackservice "github.com/portainer/portainer/api/internal/edge/edgestacks"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 
@@ -22,6 +23,8 @@ type Handler struct {
 	GitService         portainer.GitService
 	edgeStacksService  *edgestackservice.Service
 	KubernetesDeployer portainer.KubernetesDeployer
+	// DeploymentHistoryService records each new version of an edge stack, it is optional
+	DeploymentHistoryService *deploymenthistory.Service
 }
 
 // NewHandler creates a handler to manage environment(endpoint) group operations.
@@ -45,8 +48,12 @@ func NewHandler(bouncer security.BouncerService, dataStore dataservices.DataStor
 		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackUpdate)))).Methods(http.MethodPut)
 	h.Handle("/edge_stacks/{id}",
 		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackDelete)))).Methods(http.MethodDelete)
+	h.Handle("/edge_stacks/{id}/rollout/resume",
+		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackRolloutResume)))).Methods(http.MethodPost)
 	h.Handle("/edge_stacks/{id}/file",
 		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackFile)))).Methods(http.MethodGet)
+	h.Handle("/edge_stacks/{id}/logs/{endpointId}",
+		bouncer.AdminAccess(bouncer.EdgeComputeOperation(httperror.LoggerHandler(h.edgeStackLogsInspect)))).Methods(http.MethodGet)
 	h.Handle("/edge_stacks/{id}/status",
 		bouncer.PublicAccess(httperror.LoggerHandler(h.edgeStackStatusUpdate))).Methods(http.MethodPut)
 
@@ -67,3 +74,9 @@ func (handler *Handler) handlerDBErr(err error, msg string) *httperror.HandlerEr
 
 	return httpErr
 }
+
+func (handler *Handler) recordDeployment(edgeStack *portainer.EdgeStack, initiator string) {
+	if handler.DeploymentHistoryService != nil {
+		handler.DeploymentHistoryService.RecordEdgeStack(edgeStack, initiator)
+	}
+}
@@ -54,3 +54,42 @@ func (handler *Handler) storeStackFile(stack *portainer.EdgeStack, deploymentTyp
 
 	return nil
 }
+
+// storeStackFileVersion keeps a copy of the current stack file under its version, it is served to the environments
+// that are not released yet while a newer version is rolled out
+func (handler *Handler) storeStackFileVersion(stack *portainer.EdgeStack) error {
+	entryPoint := stack.EntryPoint
+	if stack.DeploymentType == portainer.EdgeStackDeploymentKubernetes {
+		entryPoint = stack.ManifestPath
+	}
+
+	content, err := handler.FileService.GetFileContent(stack.ProjectPath, entryPoint)
+	if err != nil {
+		return fmt.Errorf("unable to read the stack file: %w", err)
+	}
+
+	if _, err := handler.FileService.StoreEdgeStackFileFromBytesByVersion(strconv.Itoa(int(stack.ID)), entryPoint, stack.Version, content); err != nil {
+		return fmt.Errorf("unable to persist the stack file of version %d on disk: %w", stack.Version, err)
+	}
+
+	return nil
+}
+
+// removeStackFileVersions removes the copies of the stack files kept for a rollout that was replaced,
+// except the ones still used by the current rollout of the stack
+func (handler *Handler) removeStackFileVersions(stack *portainer.EdgeStack, rollout *portainer.EdgeStackRollout) {
+	if rollout == nil {
+		return
+	}
+
+	for _, version := range []int{rollout.PreviousVersion, rollout.Version} {
+		if version == 0 || (stack.Rollout != nil && (version == stack.Rollout.PreviousVersion || version == stack.Rollout.Version)) {
+			continue
+		}
+
+		versionPath := handler.FileService.GetEdgeStackProjectPathByVersion(strconv.Itoa(int(stack.ID)), version, "")
+		if err := handler.FileService.RemoveDirectory(versionPath); err != nil {
+			log.Warn().Err(err).Int("stack_id", int(stack.ID)).Int("version", version).Msg("unable to remove the stack file of a previous version")
+		}
+	}
+}
@@ -5,18 +5,10 @@ import (
 	"slices"
 
 	portainer "github.com/portainer/portainer/api"
-	"github.com/portainer/portainer/api/http/client"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/response"
-
-	"github.com/segmentio/encoding/json"
 )
 
-type templateFileFormat struct {
-	Version   string               `json:"version"`
-	Templates []portainer.Template `json:"templates"`
-}
-
 // @id EdgeTemplateList
 // @deprecated
 // @summary Fetches the list of Edge Templates
@@ -30,29 +22,11 @@ type templateFileFormat struct {
 // @failure 500
 // @router /edge_templates [get]
 func (handler *Handler) edgeTemplateList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
-	settings, err := handler.DataStore.Settings().Settings()
-	if err != nil {
-		return httperror.InternalServerError("Unable to retrieve settings from the database", err)
-	}
-
-	url := portainer.DefaultTemplatesURL
-	if settings.TemplatesURL != "" {
-		url = settings.TemplatesURL
-	}
-
-	var templateData []byte
-	templateData, err = client.Get(url, 10)
+	templateFile, err := handler.TemplateSources.Templates(true, nil)
 	if err != nil {
 		return httperror.InternalServerError("Unable to retrieve external templates", err)
 	}
 
-	var templateFile templateFileFormat
-
-	err = json.Unmarshal(templateData, &templateFile)
-	if err != nil {
-		return httperror.InternalServerError("Unable to parse template file", err)
-	}
-
 	// We only support version 3 of the template format
 	// this is only a temporary fix until we have custom edge templates
 	if templateFile.Version != "3" {
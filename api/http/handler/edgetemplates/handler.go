@@ -6,6 +6,7 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/middlewares"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/templatesources"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 
 	"github.com/gorilla/mux"
@@ -14,8 +15,9 @@ import (
 // Handler is the HTTP handler used to handle edge environment(endpoint) operations.
 type Handler struct {
 	*mux.Router
-	requestBouncer security.BouncerService
-	DataStore      dataservices.DataStore
+	requestBouncer  security.BouncerService
+	DataStore       dataservices.DataStore
+	TemplateSources *templatesources.Service
 }
 
 // NewHandler creates a handler to manage environment(endpoint) operations.
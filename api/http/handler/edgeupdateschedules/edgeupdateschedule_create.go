@@ -0,0 +1,125 @@
+package edgeupdateschedules
+
+import (
+	"cmp"
+	"errors"
+	"net/http"
+	"regexp"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/edge/updateschedules"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+// maxRetries is the highest number of retries of a failed update
+const maxRetries = 10
+
+// tagPattern matches the versions that can be used as the tag of the image of the agent
+var tagPattern = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+type edgeUpdateScheduleCreatePayload struct {
+	Name string `example:"agents-2.21"`
+	// Edge groups whose environments are updated
+	EdgeGroupIDs []portainer.EdgeGroupID `example:"1"`
+	// Version of the agent the environments are updated to
+	AgentVersion string `example:"2.21.0"`
+	// Image of the agent, defaults to portainer/agent
+	AgentImage string `example:"portainer/agent"`
+	// Image of the updater replacing the container of the agent, defaults to portainer/portainer-updater:latest
+	UpdaterImage string `example:"portainer/portainer-updater:latest"`
+	// Window in which the updates are started, they can start at any time when it is not set
+	MaintenanceWindow *portainer.EdgeUpdateMaintenanceWindow
+	// Maximum number of environments updated at the same time, defaults to 1
+	Concurrency int `example:"5"`
+	// Number of times a failed update is retried before the agent is rolled back to its previous version
+	MaxRetries int `example:"1"`
+}
+
+func (payload *edgeUpdateScheduleCreatePayload) Validate(r *http.Request) error {
+	if payload.Name == "" {
+		return errors.New("invalid edge update schedule name")
+	}
+
+	if len(payload.EdgeGroupIDs) == 0 {
+		return errors.New("edge update schedule requires at least one edge group")
+	}
+
+	if !tagPattern.MatchString(payload.AgentVersion) {
+		return errors.New("invalid agent version, it must be a valid image tag")
+	}
+
+	if payload.Concurrency < 0 {
+		return errors.New("invalid edge update schedule concurrency, it cannot be negative")
+	}
+
+	if payload.MaxRetries < 0 || payload.MaxRetries > maxRetries {
+		return errors.New("invalid edge update schedule retries, they must be between 0 and 10")
+	}
+
+	return updateschedules.ValidateMaintenanceWindow(payload.MaintenanceWindow)
+}
+
+// @id EdgeUpdateScheduleCreate
+// @summary Create an edge update schedule
+// @description Update the agents of the Edge environments of the edge groups to the given version.
+// @description The updates start within the maintenance window, a limited number of environments at a time.
+// @description A failed update is retried, then the agent is rolled back to its previous version.
+// @description Only the agents running on Docker can be updated, this feature requires the edgeRemoteUpdate feature flag.
+// @description **Access policy**: administrator
+// @tags edge_update_schedules
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body edgeUpdateScheduleCreatePayload true "Edge update schedule details"
+// @success 200 {object} portainer.EdgeUpdateSchedule
+// @failure 400
+// @failure 403 "The edgeRemoteUpdate feature flag is not enabled"
+// @failure 500
+// @router /edge_update_schedules [post]
+func (handler *Handler) edgeUpdateScheduleCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload edgeUpdateScheduleCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve user details from authentication token", err)
+	}
+
+	schedule := &portainer.EdgeUpdateSchedule{
+		Name:              payload.Name,
+		EdgeGroupIDs:      payload.EdgeGroupIDs,
+		AgentVersion:      payload.AgentVersion,
+		AgentImage:        cmp.Or(payload.AgentImage, updateschedules.DefaultAgentImage),
+		UpdaterImage:      cmp.Or(payload.UpdaterImage, updateschedules.DefaultUpdaterImage),
+		MaintenanceWindow: payload.MaintenanceWindow,
+		Concurrency:       max(payload.Concurrency, 1),
+		MaxRetries:        payload.MaxRetries,
+		Created:           time.Now().Unix(),
+		CreatedBy:         tokenData.ID,
+	}
+
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		for _, edgeGroupID := range schedule.EdgeGroupIDs {
+			if _, err := tx.EdgeGroup().Read(edgeGroupID); tx.IsErrObjectNotFound(err) {
+				return httperror.BadRequest("Unable to find an edge group with the specified identifier inside the database", err)
+			} else if err != nil {
+				return httperror.InternalServerError("Unable to find an edge group with the specified identifier inside the database", err)
+			}
+		}
+
+		if err := handler.UpdateService.Create(tx, schedule); err != nil {
+			return httperror.InternalServerError("Unable to persist the edge update schedule inside the database", err)
+		}
+
+		return nil
+	})
+
+	return txResponse(w, schedule, err)
+}
@@ -0,0 +1,51 @@
+package edgeupdateschedules
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id EdgeUpdateScheduleDelete
+// @summary Delete an edge update schedule
+// @description Delete an edge update schedule, the environments whose update did not start yet keep their agent.
+// @description **Access policy**: administrator
+// @tags edge_update_schedules
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Edge update schedule identifier"
+// @success 204
+// @failure 400
+// @failure 403 "The edgeRemoteUpdate feature flag is not enabled"
+// @failure 404
+// @failure 500
+// @router /edge_update_schedules/{id} [delete]
+func (handler *Handler) edgeUpdateScheduleDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	scheduleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid edge update schedule identifier route variable", err)
+	}
+
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		schedule, err := tx.EdgeUpdateSchedule().Read(portainer.EdgeUpdateScheduleID(scheduleID))
+		if tx.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find an edge update schedule with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find an edge update schedule with the specified identifier inside the database", err)
+		}
+
+		if err := handler.UpdateService.Delete(tx, schedule); err != nil {
+			return httperror.InternalServerError("Unable to remove the edge update schedule", err)
+		}
+
+		return nil
+	}); err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,41 @@
+package edgeupdateschedules
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id EdgeUpdateScheduleInspect
+// @summary Inspect an edge update schedule
+// @description Retrieve an edge update schedule along with the progress of the update of each environment.
+// @description **Access policy**: administrator
+// @tags edge_update_schedules
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Edge update schedule identifier"
+// @success 200 {object} portainer.EdgeUpdateSchedule
+// @failure 400
+// @failure 403 "The edgeRemoteUpdate feature flag is not enabled"
+// @failure 404
+// @failure 500
+// @router /edge_update_schedules/{id} [get]
+func (handler *Handler) edgeUpdateScheduleInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	scheduleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid edge update schedule identifier route variable", err)
+	}
+
+	schedule, err := handler.DataStore.EdgeUpdateSchedule().Read(portainer.EdgeUpdateScheduleID(scheduleID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an edge update schedule with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an edge update schedule with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, schedule)
+}
@@ -0,0 +1,28 @@
+package edgeupdateschedules
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id EdgeUpdateScheduleList
+// @summary List the edge update schedules
+// @description **Access policy**: administrator
+// @tags edge_update_schedules
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.EdgeUpdateSchedule
+// @failure 403 "The edgeRemoteUpdate feature flag is not enabled"
+// @failure 500
+// @router /edge_update_schedules [get]
+func (handler *Handler) edgeUpdateScheduleList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	schedules, err := handler.DataStore.EdgeUpdateSchedule().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve edge update schedules from the database", err)
+	}
+
+	return response.JSON(w, schedules)
+}
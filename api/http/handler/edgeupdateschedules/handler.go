@@ -0,0 +1,54 @@
+package edgeupdateschedules
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/middlewares"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/edge/updateschedules"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is the HTTP handler used to handle edge update schedule operations.
+type Handler struct {
+	*mux.Router
+	DataStore     dataservices.DataStore
+	UpdateService *updateschedules.Service
+}
+
+// NewHandler creates a handler to manage edge update schedule operations.
+func NewHandler(bouncer security.BouncerService, dataStore dataservices.DataStore) *Handler {
+	h := &Handler{
+		Router:    mux.NewRouter(),
+		DataStore: dataStore,
+	}
+
+	router := h.PathPrefix("/edge_update_schedules").Subrouter()
+	router.Use(bouncer.AdminAccess, bouncer.EdgeComputeOperation, middlewares.FeatureFlag(dataStore.Settings(), portainer.FeatureFlagEdgeRemoteUpdate))
+
+	router.Handle("", httperror.LoggerHandler(h.edgeUpdateScheduleList)).Methods(http.MethodGet)
+	router.Handle("", httperror.LoggerHandler(h.edgeUpdateScheduleCreate)).Methods(http.MethodPost)
+	router.Handle("/{id}", httperror.LoggerHandler(h.edgeUpdateScheduleInspect)).Methods(http.MethodGet)
+	router.Handle("/{id}", httperror.LoggerHandler(h.edgeUpdateScheduleDelete)).Methods(http.MethodDelete)
+
+	return h
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
@@ -0,0 +1,235 @@
+package endpointedge
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/dataservices/edgecommand"
+	"github.com/portainer/portainer/api/http/middlewares"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type edgeCommandResponse struct {
+	// EdgeCommand Identifier
+	ID           portainer.EdgeCommandID           `json:"Id" example:"1"`
+	ResourceType portainer.EdgeCommandResourceType `json:"ResourceType" example:"edgeStack"`
+	ResourceID   int                               `json:"ResourceId" example:"1"`
+	Operation    portainer.EdgeCommandOperation    `json:"Operation" example:"add"`
+}
+
+type edgeCommandFailurePayload struct {
+	// Error encountered by the agent while applying the command
+	Error string `example:"unable to pull the image"`
+}
+
+func (payload *edgeCommandFailurePayload) Validate(r *http.Request) error {
+	if payload.Error == "" {
+		return errors.New("invalid error message")
+	}
+
+	return nil
+}
+
+var edgeCommandStatuses = []portainer.EdgeCommandStatus{
+	portainer.EdgeCommandStatusPending,
+	portainer.EdgeCommandStatusDelivered,
+	portainer.EdgeCommandStatusFailed,
+	portainer.EdgeCommandStatusCancelled,
+}
+
+// @id EndpointEdgeCommandList
+// @summary List the commands of an Edge environment(endpoint)
+// @description List the commands queued for an Edge environment(endpoint) running in async mode, oldest first.
+// @description **Access policy**: administrator
+// @tags edge, endpoints
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param status query string false "Only list the commands with this status" Enums(pending,delivered,failed,cancelled)
+// @success 200 {array} portainer.EdgeCommand "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /endpoints/{id}/edge/commands [get]
+func (handler *Handler) endpointEdgeCommandList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, err := middlewares.FetchEndpoint(r)
+	if err != nil {
+		return httperror.BadRequest("Unable to find an environment on request context", err)
+	}
+
+	if !endpointutils.IsEdgeEndpoint(endpoint) {
+		return httperror.BadRequest("The environment is not an Edge environment", errors.New("edge commands are only available for Edge environments"))
+	}
+
+	status, _ := request.RetrieveQueryParameter(r, "status", true)
+	if status != "" && !slices.Contains(edgeCommandStatuses, portainer.EdgeCommandStatus(status)) {
+		return httperror.BadRequest("Invalid status query parameter", fmt.Errorf("unknown edge command status: %s", status))
+	}
+
+	commands, err := handler.DataStore.EdgeCommand().ReadAllByEndpointID(endpoint.ID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the edge commands from the database", err)
+	}
+
+	if status != "" {
+		commands = slices.DeleteFunc(commands, func(command portainer.EdgeCommand) bool {
+			return command.Status != portainer.EdgeCommandStatus(status)
+		})
+	}
+
+	return response.JSON(w, commands)
+}
+
+// @id EndpointEdgeCommandCancel
+// @summary Cancel a command of an Edge environment(endpoint)
+// @description Cancel a command queued for an Edge environment(endpoint), only the commands that were not delivered
+// @description to the agent yet can be cancelled.
+// @description **Access policy**: administrator
+// @tags edge, endpoints
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param commandId path int true "Command identifier"
+// @success 200 {object} portainer.EdgeCommand "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Environment(Endpoint) or command not found"
+// @failure 409 "The command was already delivered"
+// @failure 500 "Server error"
+// @router /endpoints/{id}/edge/commands/{commandId}/cancel [post]
+func (handler *Handler) endpointEdgeCommandCancel(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, err := middlewares.FetchEndpoint(r)
+	if err != nil {
+		return httperror.BadRequest("Unable to find an environment on request context", err)
+	}
+
+	commandID, err := request.RetrieveNumericRouteVariableValue(r, "commandId")
+	if err != nil {
+		return httperror.BadRequest("Invalid command identifier route variable", err)
+	}
+
+	var command *portainer.EdgeCommand
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		command, err = updateEdgeCommandStatus(tx, endpoint.ID, portainer.EdgeCommandID(commandID), portainer.EdgeCommandStatusCancelled, "")
+		return err
+	})
+
+	return txResponse(w, command, err)
+}
+
+// @id EndpointEdgeCommandFailure
+// @summary Report the failure of a command
+// @description Used by the Edge agent to report that a command it received could not be applied.
+// @description **Access policy**: restricted only to Edge environments(endpoints)
+// @tags edge, endpoints
+// @accept json
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param commandId path int true "Command identifier"
+// @param body body edgeCommandFailurePayload true "Failure details"
+// @success 200 {object} portainer.EdgeCommand "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied to access environment(endpoint)"
+// @failure 404 "Command not found"
+// @failure 409 "The command cannot be marked as failed"
+// @failure 500 "Server error"
+// @router /endpoints/{id}/edge/commands/{commandId}/failure [post]
+func (handler *Handler) endpointEdgeCommandFailure(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, err := middlewares.FetchEndpoint(r)
+	if err != nil {
+		return httperror.BadRequest("Unable to find an environment on request context", err)
+	}
+
+	if err := handler.requestBouncer.AuthorizedEdgeEndpointOperation(r, endpoint); err != nil {
+		return httperror.Forbidden("Permission denied to access environment", fmt.Errorf("unauthorized edge endpoint operation: %w. Environment name: %s", err, endpoint.Name))
+	}
+
+	commandID, err := request.RetrieveNumericRouteVariableValue(r, "commandId")
+	if err != nil {
+		return httperror.BadRequest("Invalid command identifier route variable", err)
+	}
+
+	var payload edgeCommandFailurePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var command *portainer.EdgeCommand
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		command, err = updateEdgeCommandStatus(tx, endpoint.ID, portainer.EdgeCommandID(commandID), portainer.EdgeCommandStatusFailed, payload.Error)
+		return err
+	})
+
+	return txResponse(w, command, err)
+}
+
+func updateEdgeCommandStatus(tx dataservices.DataStoreTx, endpointID portainer.EndpointID, commandID portainer.EdgeCommandID, status portainer.EdgeCommandStatus, message string) (*portainer.EdgeCommand, error) {
+	command, err := tx.EdgeCommand().Read(commandID)
+	if tx.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find an edge command with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find an edge command with the specified identifier inside the database", err)
+	}
+
+	if command.EndpointID != endpointID {
+		return nil, httperror.NotFound("Unable to find an edge command with the specified identifier inside the database", errors.New("the command belongs to another environment"))
+	}
+
+	updated, err := tx.EdgeCommand().UpdateStatus(commandID, status, message)
+	if errors.Is(err, edgecommand.ErrInvalidStatusTransition) {
+		return nil, httperror.Conflict(fmt.Sprintf("The edge command is already %s", command.Status), err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to update the edge command inside the database", err)
+	}
+
+	return updated, nil
+}
+
+// deliverEdgeCommands returns the pending commands of the environment and marks them as delivered
+func (handler *Handler) deliverEdgeCommands(tx dataservices.DataStoreTx, endpointID portainer.EndpointID) ([]edgeCommandResponse, *httperror.HandlerError) {
+	commands, err := tx.EdgeCommand().ReadAllByEndpointID(endpointID)
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve the edge commands from the database", err)
+	}
+
+	delivered := []edgeCommandResponse{}
+	for _, command := range commands {
+		if command.Status != portainer.EdgeCommandStatusPending {
+			continue
+		}
+
+		if _, err := tx.EdgeCommand().UpdateStatus(command.ID, portainer.EdgeCommandStatusDelivered, ""); err != nil {
+			return nil, httperror.InternalServerError("Unable to update the edge command inside the database", err)
+		}
+
+		delivered = append(delivered, edgeCommandResponse{
+			ID:           command.ID,
+			ResourceType: command.ResourceType,
+			ResourceID:   command.ResourceID,
+			Operation:    command.Operation,
+		})
+	}
+
+	return delivered, nil
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
@@ -0,0 +1,99 @@
+package endpointedge
+
+import (
+	"net/http"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/internal/edge"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgeCommandsLifecycle(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	asyncEndpoint := &portainer.Endpoint{ID: 1, Name: "async", Type: portainer.EdgeAgentOnDockerEnvironment, Edge: portainer.EnvironmentEdgeSettings{AsyncMode: true}}
+	require.NoError(t, store.Endpoint().Create(asyncEndpoint))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 2, Name: "standard", Type: portainer.EdgeAgentOnDockerEnvironment}))
+
+	h := &Handler{DataStore: store}
+
+	err := store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := edge.QueueCommands(tx, []portainer.EndpointID{1, 2}, portainer.EdgeCommandResourceEdgeStack, 5, portainer.EdgeCommandOperationAdd); err != nil {
+			return err
+		}
+
+		return edge.QueueCommands(tx, []portainer.EndpointID{1}, portainer.EdgeCommandResourceEdgeStack, 6, portainer.EdgeCommandOperationRemove)
+	})
+	require.NoError(t, err)
+
+	// Only the environments running in async mode receive commands
+	commands, err := store.EdgeCommand().ReadAllByEndpointID(2)
+	require.NoError(t, err)
+	assert.Empty(t, commands)
+
+	commands, err = store.EdgeCommand().ReadAllByEndpointID(1)
+	require.NoError(t, err)
+	require.Len(t, commands, 2)
+	assert.Equal(t, portainer.EdgeCommandStatusPending, commands[0].Status)
+
+	statusFor := func(commandID portainer.EdgeCommandID, status portainer.EdgeCommandStatus) *httperror.HandlerError {
+		var handlerErr *httperror.HandlerError
+
+		err := store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+			_, err := updateEdgeCommandStatus(tx, 1, commandID, status, "")
+			if err != nil {
+				handlerErr = err.(*httperror.HandlerError)
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		return handlerErr
+	}
+
+	require.Nil(t, statusFor(commands[1].ID, portainer.EdgeCommandStatusCancelled))
+
+	var delivered []edgeCommandResponse
+	err = store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		var handlerErr *httperror.HandlerError
+		delivered, handlerErr = h.deliverEdgeCommands(tx, asyncEndpoint.ID)
+		if handlerErr != nil {
+			return handlerErr
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The cancelled command is not delivered
+	require.Len(t, delivered, 1)
+	assert.Equal(t, edgeCommandResponse{
+		ID:           commands[0].ID,
+		ResourceType: portainer.EdgeCommandResourceEdgeStack,
+		ResourceID:   5,
+		Operation:    portainer.EdgeCommandOperationAdd,
+	}, delivered[0])
+
+	// A delivered command can no longer be cancelled
+	handlerErr := statusFor(commands[0].ID, portainer.EdgeCommandStatusCancelled)
+	require.NotNil(t, handlerErr)
+	assert.Equal(t, http.StatusConflict, handlerErr.StatusCode)
+
+	require.Nil(t, statusFor(commands[0].ID, portainer.EdgeCommandStatusFailed))
+
+	// The commands of the other environments are not reachable
+	handlerErr = statusFor(999, portainer.EdgeCommandStatusCancelled)
+	require.NotNil(t, handlerErr)
+	assert.Equal(t, http.StatusNotFound, handlerErr.StatusCode)
+
+	command, err := store.EdgeCommand().Read(commands[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, portainer.EdgeCommandStatusFailed, command.Status)
+}
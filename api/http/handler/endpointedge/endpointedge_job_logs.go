@@ -10,6 +10,7 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/middlewares"
 	"github.com/portainer/portainer/api/internal/edge/cache"
+	"github.com/portainer/portainer/api/pendingactions/handlers"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -17,6 +18,10 @@ import (
 
 type logsPayload struct {
 	FileContent string
+	// Whether FileContent is a chunk of the output of a running task, appended to the logs collected so far
+	Append bool
+	// Whether the task is over, only used along with Append
+	Completed bool
 }
 
 func (payload *logsPayload) Validate(r *http.Request) error {
@@ -25,6 +30,8 @@ func (payload *logsPayload) Validate(r *http.Request) error {
 
 // endpointEdgeJobsLogs
 // @summary Inspect an EdgeJob Log
+// @description Store the logs of an Edge job task. The output of a running task can be sent in chunks using Append,
+// @description the logs are marked as collected once the chunk flagged as Completed is received.
 // @description **Access policy**: public
 // @tags edge, endpoints
 // @accept json
@@ -85,8 +92,20 @@ func (handler *Handler) getEdgeJobLobs(tx dataservices.DataStoreTx, endpointID p
 		return httperror.InternalServerError("Unable to find an edge job with the specified identifier inside the database", err)
 	}
 
-	if err := handler.FileService.StoreEdgeJobTaskLogFileFromBytes(strconv.Itoa(int(edgeJobID)), strconv.Itoa(int(endpoint.ID)), []byte(payload.FileContent)); err != nil {
-		return httperror.InternalServerError("Unable to save task log to the filesystem", err)
+	if payload.Append {
+		if err := handler.EdgeJobLogStreamer.Append(edgeJobID, endpoint.ID, []byte(payload.FileContent), payload.Completed); err != nil {
+			return httperror.InternalServerError("Unable to save task log to the filesystem", err)
+		}
+
+		if !payload.Completed {
+			return nil
+		}
+	} else {
+		if err := handler.FileService.StoreEdgeJobTaskLogFileFromBytes(strconv.Itoa(int(edgeJobID)), strconv.Itoa(int(endpoint.ID)), []byte(payload.FileContent)); err != nil {
+			return httperror.InternalServerError("Unable to save task log to the filesystem", err)
+		}
+
+		handler.EdgeJobLogStreamer.Complete(edgeJobID, endpoint.ID)
 	}
 
 	meta := portainer.EdgeJobEndpointMeta{CollectLogs: false, LogsStatus: portainer.EdgeJobLogsStatusCollected}
@@ -100,6 +119,10 @@ func (handler *Handler) getEdgeJobLobs(tx dataservices.DataStoreTx, endpointID p
 		return httperror.InternalServerError("Unable to persist edge job changes to the database", err)
 	}
 
+	if err := handlers.DequeueEdgeJobLogsCollection(tx, endpoint.ID, edgeJob.ID); err != nil {
+		return httperror.InternalServerError("Unable to remove the logs collection request from the database", err)
+	}
+
 	cache.Del(endpointID)
 
 	return nil
@@ -0,0 +1,207 @@
+package endpointedge
+
+import (
+	"cmp"
+	"errors"
+	"net/http"
+	"slices"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/edge"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+
+	"github.com/rs/zerolog/log"
+)
+
+type endpointEdgeJoinPayload struct {
+	// Edge join token generated by an administrator
+	Token string `validate:"required" example:"ejt_4f9a..."`
+	// Name of the environment(endpoint), defaults to the Edge identifier
+	Name string `example:"warehouse-gateway-12"`
+	// URL of the Portainer instance used by the agent, it is used when no Edge URL is defined in the settings
+	PortainerURL string `example:"https://portainer.example.com"`
+}
+
+type endpointEdgeJoinResponse struct {
+	// Identifier of the registered environment(endpoint)
+	EndpointID portainer.EndpointID `json:"endpointId" example:"12"`
+	// Edge key the agent uses to poll Portainer from now on
+	EdgeKey string `json:"edgeKey"`
+	// Whether the environment(endpoint) is trusted, otherwise it waits for an administrator to trust it
+	Trusted bool `json:"trusted" example:"true"`
+}
+
+func (payload *endpointEdgeJoinPayload) Validate(r *http.Request) error {
+	if payload.Token == "" {
+		return errors.New("missing join token")
+	}
+
+	return nil
+}
+
+// @id EndpointEdgeJoin
+// @summary Register an Edge agent with a join token
+// @description Create an Edge environment for the agent sending the request, in exchange of a single-use join token.
+// @description The environment is added to the group and tags of the token, the returned Edge key replaces the
+// @description static Edge key in the configuration of the agent.
+// @description **Access policy**: public, restricted to the holders of a join token
+// @tags endpoints
+// @accept json
+// @produce json
+// @param body body endpointEdgeJoinPayload true "Join details"
+// @success 200 {object} endpointEdgeJoinResponse "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Invalid, expired or already used join token"
+// @failure 409 "An environment is already registered with the Edge identifier or the name"
+// @failure 500 "Server error"
+// @router /endpoints/edge/join [post]
+func (handler *Handler) endpointEdgeJoin(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload endpointEdgeJoinPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	edgeID := r.Header.Get(portainer.PortainerAgentEdgeIDHeader)
+	if edgeID == "" {
+		return httperror.BadRequest("Missing Edge identifier", errors.New("the Edge identifier header is required"))
+	}
+
+	endpointType, err := parseAgentPlatform(r)
+	if err != nil {
+		return httperror.BadRequest("Invalid agent platform header", err)
+	}
+
+	var resp *endpointEdgeJoinResponse
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		resp, err = handler.joinEdgeEndpoint(tx, &payload, edgeID, endpointType, r.Header.Get(portainer.PortainerAgentHeader), time.Now())
+
+		return err
+	})
+
+	return txResponse(w, resp, err)
+}
+
+func (handler *Handler) joinEdgeEndpoint(tx dataservices.DataStoreTx, payload *endpointEdgeJoinPayload, edgeID string, endpointType portainer.EndpointType, agentVersion string, now time.Time) (*endpointEdgeJoinResponse, error) {
+	token, err := edge.FindUsableJoinToken(tx, payload.Token, now)
+	if errors.Is(err, edge.ErrInvalidJoinToken) {
+		return nil, httperror.Forbidden("Invalid, expired or already used join token", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve the join token from the database", err)
+	}
+
+	if _, ok := tx.Endpoint().EndpointIDByEdgeID(edgeID); ok {
+		return nil, httperror.Conflict("An environment is already registered with this Edge identifier", errors.New("duplicate Edge identifier"))
+	}
+
+	name := cmp.Or(payload.Name, edgeID)
+
+	endpoints, err := tx.Endpoint().Endpoints()
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve environments from the database", err)
+	}
+
+	if slices.ContainsFunc(endpoints, func(e portainer.Endpoint) bool { return e.Name == name }) {
+		return nil, httperror.Conflict("Name is not unique", errors.New("duplicate environment name"))
+	}
+
+	settings, err := tx.Settings().Settings()
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve the settings from the database", err)
+	}
+
+	portainerURL := cmp.Or(settings.EdgePortainerURL, payload.PortainerURL)
+
+	portainerHost, err := edge.ParseHostForEdge(portainerURL)
+	if err != nil {
+		return nil, httperror.BadRequest("Invalid Portainer URL", err)
+	}
+
+	endpointGroup, err := tx.EndpointGroup().Read(token.GroupID)
+	if tx.IsErrObjectNotFound(err) {
+		// The group was removed after the token was generated
+		endpointGroup, err = tx.EndpointGroup().Read(1)
+	}
+
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to find an environment group inside the database", err)
+	}
+
+	tagIDs := []portainer.TagID{}
+	for _, tagID := range token.TagIDs {
+		if _, err := tx.Tag().Read(tagID); err == nil {
+			tagIDs = append(tagIDs, tagID)
+		}
+	}
+
+	endpointID := tx.Endpoint().GetNextIdentifier()
+
+	endpoint := &portainer.Endpoint{
+		ID:                 portainer.EndpointID(endpointID),
+		Name:               name,
+		URL:                portainerHost,
+		Type:               endpointType,
+		GroupID:            endpointGroup.ID,
+		TLSConfig:          portainer.TLSConfiguration{TLS: false},
+		UserAccessPolicies: portainer.UserAccessPolicies{},
+		TeamAccessPolicies: portainer.TeamAccessPolicies{},
+		TagIDs:             tagIDs,
+		Status:             portainer.EndpointStatusUp,
+		Snapshots:          []portainer.DockerSnapshot{},
+		EdgeKey:            handler.ReverseTunnelService.GenerateEdgeKey(portainerURL, portainerHost, endpointID),
+		EdgeID:             edgeID,
+		Kubernetes:         portainer.KubernetesDefault(),
+		UserTrusted:        token.AutoTrust,
+	}
+	endpoint.Agent.Version = agentVersion
+
+	if err := endpointutils.CreateEndpoint(tx, endpoint); err != nil {
+		return nil, httperror.InternalServerError("An error occurred while trying to create the environment", err)
+	}
+
+	if err := createEndpointRelation(tx, endpoint, endpointGroup); err != nil {
+		return nil, httperror.InternalServerError("Unable to persist the relation object inside the database", err)
+	}
+
+	token.UsedAt = now.Unix()
+	token.EndpointID = endpoint.ID
+
+	if err := tx.EdgeJoinToken().Update(token.ID, token); err != nil {
+		return nil, httperror.InternalServerError("Unable to persist the join token changes inside the database", err)
+	}
+
+	log.Info().Str("edge_id", edgeID).Int("endpoint_id", int(endpoint.ID)).Int("token_id", int(token.ID)).Msg("Edge agent registered with a join token")
+
+	return &endpointEdgeJoinResponse{
+		EndpointID: endpoint.ID,
+		EdgeKey:    endpoint.EdgeKey,
+		Trusted:    endpoint.UserTrusted,
+	}, nil
+}
+
+// createEndpointRelation relates the new Edge environment(endpoint) to the Edge stacks of its Edge groups
+func createEndpointRelation(tx dataservices.DataStoreTx, endpoint *portainer.Endpoint, endpointGroup *portainer.EndpointGroup) error {
+	edgeGroups, err := tx.EdgeGroup().ReadAll()
+	if err != nil {
+		return err
+	}
+
+	edgeStacks, err := tx.EdgeStack().EdgeStacks()
+	if err != nil {
+		return err
+	}
+
+	relation := &portainer.EndpointRelation{
+		EndpointID: endpoint.ID,
+		EdgeStacks: map[portainer.EdgeStackID]bool{},
+	}
+
+	for _, stackID := range edge.EndpointRelatedEdgeStacks(endpoint, endpointGroup, edgeGroups, edgeStacks) {
+		relation.EdgeStacks[stackID] = true
+	}
+
+	return tx.EndpointRelation().Create(relation)
+}
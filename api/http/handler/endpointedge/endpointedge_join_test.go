@@ -0,0 +1,68 @@
+package endpointedge
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/edge"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgeJoin(t *testing.T) {
+	handler := mustSetupHandler(t)
+
+	rawToken, token, err := edge.NewJoinToken(time.Now(), time.Hour)
+	require.NoError(t, err)
+	token.GroupID = 1
+	token.AutoTrust = true
+	require.NoError(t, handler.DataStore.EdgeJoinToken().Create(token))
+
+	join := func(rawToken, edgeID string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(endpointEdgeJoinPayload{Token: rawToken, PortainerURL: "https://portainer.io:9443"})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/endpoints/edge/join", bytes.NewReader(body))
+		req.Header.Set(portainer.PortainerAgentEdgeIDHeader, edgeID)
+		req.Header.Set(portainer.HTTPResponseAgentPlatform, "1")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := join(rawToken, "edge-join-1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp endpointEdgeJoinResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.True(t, resp.Trusted)
+	assert.NotEmpty(t, resp.EdgeKey)
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(resp.EndpointID)
+	require.NoError(t, err)
+	assert.Equal(t, "edge-join-1", endpoint.EdgeID)
+	assert.Equal(t, "edge-join-1", endpoint.Name)
+	assert.Equal(t, portainer.EdgeAgentOnDockerEnvironment, endpoint.Type)
+
+	_, err = handler.DataStore.EndpointRelation().EndpointRelation(resp.EndpointID)
+	require.NoError(t, err)
+
+	used, err := handler.DataStore.EdgeJoinToken().Read(token.ID)
+	require.NoError(t, err)
+	assert.NotZero(t, used.UsedAt)
+	assert.Equal(t, resp.EndpointID, used.EndpointID)
+
+	// The token can only be used once
+	assert.Equal(t, http.StatusForbidden, join(rawToken, "edge-join-2").Code)
+
+	// Unknown tokens are rejected
+	assert.Equal(t, http.StatusForbidden, join("ejt_unknown", "edge-join-3").Code)
+}
@@ -3,11 +3,14 @@ package endpointedge
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/edge"
 	"github.com/portainer/portainer/api/filesystem"
 	"github.com/portainer/portainer/api/http/middlewares"
+	"github.com/portainer/portainer/api/internal/edge/edgestacks"
+	"github.com/portainer/portainer/api/internal/edge/updateschedules"
 	"github.com/portainer/portainer/api/internal/endpointutils"
 	"github.com/portainer/portainer/api/kubernetes"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
@@ -69,7 +72,13 @@ func (handler *Handler) endpointEdgeStackInspect(w http.ResponseWriter, r *http.
 		}
 	}
 
-	dirEntries, err := filesystem.LoadDir(edgeStack.ProjectPath)
+	// The environments that are not released yet by a staged rollout keep deploying the previous version
+	projectPath := edgeStack.ProjectPath
+	if version := edgeStack.Rollout.EndpointVersion(edgeStack.Version, endpoint.ID); version != edgeStack.Version {
+		projectPath = handler.FileService.GetEdgeStackProjectPathByVersion(strconv.Itoa(int(edgeStack.ID)), version, "")
+	}
+
+	dirEntries, err := filesystem.LoadDir(projectPath)
 	if err != nil {
 		return httperror.InternalServerError("Unable to load repository", fmt.Errorf("failed to load project directory: %w. Environment name: %s", err, endpoint.Name))
 	}
@@ -81,11 +90,29 @@ func (handler *Handler) endpointEdgeStackInspect(w http.ResponseWriter, r *http.
 
 	dirEntries = filesystem.FilterDirForEntryFile(dirEntries, fileName)
 
-	return response.JSON(w, edge.StackPayload{
+	payload := edge.StackPayload{
 		DirEntries:       dirEntries,
 		EntryFileName:    fileName,
 		StackFileContent: fileContent,
 		Name:             edgeStack.Name,
 		Namespace:        namespace,
-	})
+	}
+
+	payload.RegistryCredentials, err = edgestacks.RegistryCredentials(handler.DataStore, edgeStack, endpoint)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the registry credentials of the stack", fmt.Errorf("failed to retrieve the registry credentials: %w. Environment name: %s", err, endpoint.Name))
+	}
+
+	// The updater of the agent receives the image to deploy on this environment
+	if edgeStack.EdgeUpdateID != 0 {
+		schedule, err := handler.DataStore.EdgeUpdateSchedule().Read(edgeStack.EdgeUpdateID)
+		if err != nil {
+			return httperror.InternalServerError("Unable to find the edge update schedule of the stack inside the database", fmt.Errorf("failed to find the edge update schedule: %w. Environment name: %s", err, endpoint.Name))
+		}
+
+		payload.EdgeUpdateID = int(schedule.ID)
+		payload.EnvVars = updateschedules.StackEnvVars(schedule, endpoint.ID)
+	}
+
+	return response.JSON(w, payload)
 }
@@ -0,0 +1,85 @@
+package endpointedge
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/middlewares"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type edgeStackLogsPayload struct {
+	// Output of the docker compose or kubectl commands run to deploy the edge stack
+	FileContent string
+}
+
+func (payload *edgeStackLogsPayload) Validate(r *http.Request) error {
+	if payload.FileContent == "" {
+		return errors.New("invalid log content")
+	}
+
+	return nil
+}
+
+// endpointEdgeStackLogs
+// @summary Store the deployment logs of an Edge Stack for an Environment(Endpoint)
+// @description Store the output of the deployment of an edge stack, replacing the logs of the previous deployment.
+// @description **Access policy**: public
+// @tags edge, endpoints, edge_stacks
+// @accept json
+// @produce json
+// @param id path int true "environment(endpoint) Id"
+// @param stackId path int true "EdgeStack Id"
+// @param body body edgeStackLogsPayload true "Deployment logs"
+// @success 204
+// @failure 500
+// @failure 400
+// @failure 403
+// @failure 404
+// @router /endpoints/{id}/edge/stacks/{stackId}/logs [post]
+func (handler *Handler) endpointEdgeStackLogs(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, err := middlewares.FetchEndpoint(r)
+	if err != nil {
+		return httperror.BadRequest("Unable to find an environment on request context", err)
+	}
+
+	if err := handler.requestBouncer.AuthorizedEdgeEndpointOperation(r, endpoint); err != nil {
+		return httperror.Forbidden("Permission denied to access environment", fmt.Errorf("unauthorized edge endpoint operation: %w. Environment name: %s", err, endpoint.Name))
+	}
+
+	edgeStackID, err := request.RetrieveNumericRouteVariableValue(r, "stackId")
+	if err != nil {
+		return httperror.BadRequest("Invalid edge stack identifier route variable", fmt.Errorf("invalid Edge stack route variable: %w. Environment name: %s", err, endpoint.Name))
+	}
+
+	var payload edgeStackLogsPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", fmt.Errorf("invalid Edge stack logs payload: %w. Environment name: %s", err, endpoint.Name))
+	}
+
+	if _, err := handler.DataStore.EdgeStack().EdgeStack(portainer.EdgeStackID(edgeStackID)); handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an edge stack with the specified identifier inside the database", fmt.Errorf("unable to find the Edge stack from database: %w. Environment name: %s", err, endpoint.Name))
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an edge stack with the specified identifier inside the database", fmt.Errorf("failed to find the Edge stack from database: %w. Environment name: %s", err, endpoint.Name))
+	}
+
+	relation, err := handler.DataStore.EndpointRelation().EndpointRelation(endpoint.ID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to find the environment relations inside the database", fmt.Errorf("failed to find the environment relations from database: %w. Environment name: %s", err, endpoint.Name))
+	}
+
+	if !relation.EdgeStacks[portainer.EdgeStackID(edgeStackID)] {
+		return httperror.Forbidden("The edge stack is not deployed to this environment", fmt.Errorf("edge stack %d is not related to the environment. Environment name: %s", edgeStackID, endpoint.Name))
+	}
+
+	if err := handler.FileService.StoreEdgeStackDeploymentLogFromBytes(strconv.Itoa(edgeStackID), strconv.Itoa(int(endpoint.ID)), []byte(payload.FileContent)); err != nil {
+		return httperror.InternalServerError("Unable to save the edge stack logs", fmt.Errorf("failed to store the Edge stack logs: %w. Environment name: %s", err, endpoint.Name))
+	}
+
+	return response.Empty(w)
+}
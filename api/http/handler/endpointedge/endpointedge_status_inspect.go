@@ -16,8 +16,10 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/clockskew"
 	"github.com/portainer/portainer/api/internal/edge"
 	"github.com/portainer/portainer/api/internal/edge/cache"
+	"github.com/portainer/portainer/api/scheduler"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -36,7 +38,7 @@ type edgeJobResponse struct {
 	ID portainer.EdgeJobID `json:"Id" example:"2"`
 	// Whether to collect logs
 	CollectLogs bool `json:"CollectLogs" example:"true"`
-	// A cron expression to schedule this job
+	// A cron expression to schedule this job, prefixed with CRON_TZ= when it runs in a given time zone
 	CronExpression string `json:"CronExpression" example:"* * * * *"`
 	// Script to run
 	Script string `json:"Script" example:"echo hello"`
@@ -57,6 +59,8 @@ type endpointEdgeStatusInspectResponse struct {
 	Credentials string `json:"credentials"`
 	// List of stacks to be deployed on the environments(endpoints)
 	Stacks []stackStatusResponse `json:"stacks"`
+	// List of the commands queued for the environment(endpoint), only used in async mode
+	Commands []edgeCommandResponse `json:"commands,omitempty"`
 }
 
 // @id EndpointEdgeStatusInspect
@@ -120,6 +124,11 @@ func (handler *Handler) endpointEdgeStatusInspect(w http.ResponseWriter, r *http
 		return httperror.InternalServerError("Unexpected error", fmt.Errorf("edge polling error: %w. Environment name: %s", err, endpoint.Name))
 	}
 
+	// Retry the pending actions of the environment, such as the logs collections that were not fulfilled yet
+	if handler.PendingActionsService != nil {
+		handler.PendingActionsService.Execute(endpoint.ID)
+	}
+
 	return cacheResponse(w, endpoint.ID, *statusResponse)
 }
 
@@ -135,6 +144,15 @@ func (handler *Handler) parseHeaders(r *http.Request, endpoint *portainer.Endpoi
 	version := r.Header.Get(portainer.PortainerAgentHeader)
 	endpoint.Agent.Version = version
 
+	if timestamp := r.Header.Get(portainer.PortainerAgentTimestampHeader); timestamp != "" {
+		agentTime, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return httperror.BadRequest("agent timestamp header is not valid", err)
+		}
+
+		clockskew.Update(endpoint, time.Until(time.Unix(agentTime, 0)))
+	}
+
 	return nil
 }
 
@@ -170,7 +188,7 @@ func (handler *Handler) inspectStatus(tx dataservices.DataStoreTx, r *http.Reque
 		Credentials:     tunnel.Credentials,
 	}
 
-	schedules, handlerErr := handler.buildSchedules(tx, endpoint.ID)
+	schedules, handlerErr := handler.buildSchedules(tx, endpoint)
 	if handlerErr != nil {
 		return nil, handlerErr
 	}
@@ -182,6 +200,14 @@ func (handler *Handler) inspectStatus(tx dataservices.DataStoreTx, r *http.Reque
 	}
 	statusResponse.Stacks = edgeStacksStatus
 
+	if endpoint.Edge.AsyncMode {
+		commands, handlerErr := handler.deliverEdgeCommands(tx, endpoint.ID)
+		if handlerErr != nil {
+			return nil, handlerErr
+		}
+		statusResponse.Commands = commands
+	}
+
 	return &statusResponse, nil
 }
 
@@ -208,7 +234,8 @@ func parseAgentPlatform(r *http.Request) (portainer.EndpointType, error) {
 	}
 }
 
-func (handler *Handler) buildSchedules(tx dataservices.DataStoreTx, endpointID portainer.EndpointID) ([]edgeJobResponse, *httperror.HandlerError) {
+func (handler *Handler) buildSchedules(tx dataservices.DataStoreTx, endpoint *portainer.Endpoint) ([]edgeJobResponse, *httperror.HandlerError) {
+	endpointID := endpoint.ID
 	schedules := []edgeJobResponse{}
 
 	edgeJobs, err := tx.EdgeJob().ReadAll()
@@ -242,9 +269,15 @@ func (handler *Handler) buildSchedules(tx dataservices.DataStoreTx, endpointID p
 			collectLogs = job.Endpoints[endpointID].CollectLogs
 		}
 
+		// The job runs in local time of the environment unless it has its own time zone
+		cronExpression := scheduler.CronWithTimeZone(job.CronExpression, cmp.Or(job.TimeZone, endpoint.TimeZone))
+		if job.RunAt != 0 {
+			cronExpression = scheduler.CronAt(time.Unix(job.RunAt, 0))
+		}
+
 		schedule := edgeJobResponse{
 			ID:             job.ID,
-			CronExpression: job.CronExpression,
+			CronExpression: cronExpression,
 			CollectLogs:    collectLogs,
 			Version:        job.Version,
 		}
@@ -269,7 +302,7 @@ func (handler *Handler) buildEdgeStacks(tx dataservices.DataStoreTx, endpointID
 
 	edgeStacksStatus := []stackStatusResponse{}
 	for stackID := range relation.EdgeStacks {
-		version, ok := tx.EdgeStack().EdgeStackVersion(stackID)
+		version, ok := tx.EdgeStack().EdgeStackVersionForEndpoint(stackID, endpointID)
 		if !ok {
 			return nil, httperror.InternalServerError("Unable to retrieve edge stack from the database", err)
 		}
@@ -229,6 +229,47 @@ func TestLastCheckInDateIncreases(t *testing.T) {
 	assert.Greater(t, updatedEndpoint.LastCheckInDate, endpoint.LastCheckInDate)
 }
 
+func TestClockSkewDetected(t *testing.T) {
+	handler := mustSetupHandler(t)
+
+	endpoint := portainer.Endpoint{
+		ID:              portainer.EndpointID(57),
+		Name:            "test-endpoint-57",
+		Type:            portainer.EdgeAgentOnDockerEnvironment,
+		URL:             "https://portainer.io:9443",
+		EdgeID:          "edge-id",
+		LastCheckInDate: time.Now().Unix(),
+	}
+
+	if err := createEndpoint(handler, endpoint, portainer.EndpointRelation{EndpointID: endpoint.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/endpoints/%d/edge/status", endpoint.ID), nil)
+	if err != nil {
+		t.Fatal("request error:", err)
+	}
+
+	req.Header.Set(portainer.PortainerAgentEdgeIDHeader, "edge-id")
+	req.Header.Set(portainer.HTTPResponseAgentPlatform, "1")
+	req.Header.Set(portainer.PortainerAgentTimestampHeader, strconv.FormatInt(time.Now().Add(-5*time.Minute).Unix(), 10))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected a %d response, found: %d", http.StatusOK, rec.Code)
+	}
+
+	updatedEndpoint, err := handler.DataStore.Endpoint().Endpoint(endpoint.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, updatedEndpoint.ClockSkewDetected)
+	assert.InDelta(t, -300, updatedEndpoint.ClockSkew, 2)
+}
+
 func TestEmptyEdgeIdWithAgentPlatformHeader(t *testing.T) {
 	handler := mustSetupHandler(t)
 
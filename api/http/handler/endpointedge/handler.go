@@ -7,6 +7,8 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/middlewares"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/edge/joblogs"
+	"github.com/portainer/portainer/api/pendingactions"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 
 	"github.com/gorilla/mux"
@@ -15,10 +17,12 @@ import (
 // Handler is the HTTP handler used to handle edge environment(endpoint) operations.
 type Handler struct {
 	*mux.Router
-	requestBouncer       security.BouncerService
-	DataStore            dataservices.DataStore
-	FileService          portainer.FileService
-	ReverseTunnelService portainer.ReverseTunnelService
+	requestBouncer        security.BouncerService
+	DataStore             dataservices.DataStore
+	FileService           portainer.FileService
+	ReverseTunnelService  portainer.ReverseTunnelService
+	EdgeJobLogStreamer    *joblogs.Streamer
+	PendingActionsService *pendingactions.PendingActionsService
 }
 
 // NewHandler creates a handler to manage environment(endpoint) operations.
@@ -31,16 +35,27 @@ func NewHandler(bouncer security.BouncerService, dataStore dataservices.DataStor
 		ReverseTunnelService: reverseTunnelService,
 	}
 
+	h.Handle("/api/endpoints/edge/join", bouncer.PublicAccess(httperror.LoggerHandler(h.endpointEdgeJoin))).Methods(http.MethodPost)
 	h.Handle("/api/endpoints/{id}/edge/status", bouncer.PublicAccess(httperror.LoggerHandler(h.endpointEdgeStatusInspect))).Methods(http.MethodGet)
 
 	endpointRouter := h.PathPrefix("/api/endpoints/{id}").Subrouter()
 	endpointRouter.Use(middlewares.WithEndpoint(dataStore.Endpoint(), "id"))
 
+	endpointRouter.Handle("/edge/stacks/{stackId}/logs",
+		bouncer.PublicAccess(httperror.LoggerHandler(h.endpointEdgeStackLogs))).Methods(http.MethodPost)
+
 	endpointRouter.PathPrefix("/edge/stacks/{stackId}").Handler(
 		bouncer.PublicAccess(httperror.LoggerHandler(h.endpointEdgeStackInspect))).Methods(http.MethodGet)
 
 	endpointRouter.PathPrefix("/edge/jobs/{jobID}/logs").Handler(
 		bouncer.PublicAccess(httperror.LoggerHandler(h.endpointEdgeJobsLogs))).Methods(http.MethodPost)
 
+	endpointRouter.Handle("/edge/commands",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointEdgeCommandList))).Methods(http.MethodGet)
+	endpointRouter.Handle("/edge/commands/{commandId}/cancel",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointEdgeCommandCancel))).Methods(http.MethodPost)
+	endpointRouter.Handle("/edge/commands/{commandId}/failure",
+		bouncer.PublicAccess(httperror.LoggerHandler(h.endpointEdgeCommandFailure))).Methods(http.MethodPost)
+
 	return h
 }
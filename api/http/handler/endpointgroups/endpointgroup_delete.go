@@ -6,6 +6,7 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/endpointutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -67,9 +68,15 @@ func (handler *Handler) deleteEndpointGroup(tx dataservices.DataStoreTx, endpoin
 		return httperror.InternalServerError("Unable to retrieve environment from the database", err)
 	}
 
+	unassignedGroup, err := tx.EndpointGroup().Read(portainer.EndpointGroupID(1))
+	if err != nil && !tx.IsErrObjectNotFound(err) {
+		return httperror.InternalServerError("Unable to find an environment group with the specified identifier inside the database", err)
+	}
+
 	for _, endpoint := range endpoints {
 		if endpoint.GroupID == endpointGroupID {
 			endpoint.GroupID = portainer.EndpointGroupID(1)
+			endpointutils.ApplyGroupSecuritySettings(&endpoint, unassignedGroup)
 			err = tx.Endpoint().UpdateEndpoint(endpoint.ID, &endpoint)
 			if err != nil {
 				return httperror.InternalServerError("Unable to update environment", err)
@@ -96,5 +103,31 @@ func (handler *Handler) deleteEndpointGroup(tx dataservices.DataStoreTx, endpoin
 		}
 	}
 
+	quotas, err := tx.Quota().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve quotas from the database", err)
+	}
+
+	for _, quota := range quotas {
+		if quota.EndpointGroupID == endpointGroupID {
+			if err := tx.Quota().Delete(quota.ID); err != nil {
+				return httperror.InternalServerError("Unable to remove the quota of the environment group from the database", err)
+			}
+		}
+	}
+
+	groupReports, err := tx.GroupReport().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve group reports from the database", err)
+	}
+
+	for _, groupReport := range groupReports {
+		if groupReport.EndpointGroupID == endpointGroupID {
+			if err := tx.GroupReport().Delete(groupReport.ID); err != nil {
+				return httperror.InternalServerError("Unable to remove the report of the environment group from the database", err)
+			}
+		}
+	}
+
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/endpointutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -67,6 +68,7 @@ func (handler *Handler) addEndpoint(tx dataservices.DataStoreTx, endpointGroupID
 	}
 
 	endpoint.GroupID = endpointGroup.ID
+	endpointutils.ApplyGroupSecuritySettings(endpoint, endpointGroup)
 
 	err = tx.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
 	if err != nil {
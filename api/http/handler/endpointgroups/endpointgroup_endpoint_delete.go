@@ -6,6 +6,7 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/endpointutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -65,7 +66,13 @@ func (handler *Handler) removeEndpoint(tx dataservices.DataStoreTx, endpointGrou
 		return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
 	}
 
+	unassignedGroup, err := tx.EndpointGroup().Read(portainer.EndpointGroupID(1))
+	if err != nil && !tx.IsErrObjectNotFound(err) {
+		return httperror.InternalServerError("Unable to find an environment group with the specified identifier inside the database", err)
+	}
+
 	endpoint.GroupID = portainer.EndpointGroupID(1)
+	endpointutils.ApplyGroupSecuritySettings(endpoint, unassignedGroup)
 
 	err = tx.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
 	if err != nil {
@@ -0,0 +1,107 @@
+package endpointgroups
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type securitySettingsPropagateResponse struct {
+	// Environments(Endpoints) whose security settings were changed
+	EndpointIDs []portainer.EndpointID `example:"1,2"`
+}
+
+// @id EndpointGroupSecuritySettingsPropagate
+// @summary Apply the default security settings of an environment(endpoint) group to its environments
+// @description Apply the default security settings of the group to the environments(endpoints) inheriting them.
+// @description When resetOverrides is set, the environments overriding their security settings inherit them again.
+// @description **Access policy**: administrator
+// @tags endpoint_groups
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "EndpointGroup identifier"
+// @param resetOverrides query bool false "Also apply the default security settings to the environments overriding them"
+// @success 200 {object} securitySettingsPropagateResponse "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "EndpointGroup not found"
+// @failure 500 "Server error"
+// @router /endpoint_groups/{id}/security_settings/propagate [post]
+func (handler *Handler) endpointGroupSecuritySettingsPropagate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointGroupID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid environment group identifier route variable", err)
+	}
+
+	resetOverrides, _ := request.RetrieveBooleanQueryParameter(r, "resetOverrides", true)
+
+	resp := securitySettingsPropagateResponse{}
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		endpointGroup, err := tx.EndpointGroup().Read(portainer.EndpointGroupID(endpointGroupID))
+		if tx.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find an environment group with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find an environment group with the specified identifier inside the database", err)
+		}
+
+		if endpointGroup.SecuritySettings == nil {
+			return httperror.BadRequest("The environment group does not define default security settings", nil)
+		}
+
+		resp.EndpointIDs, err = propagateSecuritySettings(tx, endpointGroup, resetOverrides)
+		if err != nil {
+			return httperror.InternalServerError("Unable to apply the security settings of the group to its environments", err)
+		}
+
+		return nil
+	}); err != nil {
+		var httpErr *httperror.HandlerError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, resp)
+}
+
+// propagateSecuritySettings applies the default security settings of the group to its environments and returns
+// the environments that were changed. The environments overriding their settings are included when resetOverrides is set
+func propagateSecuritySettings(tx dataservices.DataStoreTx, endpointGroup *portainer.EndpointGroup, resetOverrides bool) ([]portainer.EndpointID, error) {
+	endpoints, err := tx.Endpoint().Endpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	updated := []portainer.EndpointID{}
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if endpoint.GroupID != endpointGroup.ID {
+			continue
+		}
+
+		overridden := endpoint.SecuritySettingsOverridden
+		if resetOverrides {
+			endpoint.SecuritySettingsOverridden = false
+		}
+
+		if !endpointutils.ApplyGroupSecuritySettings(endpoint, endpointGroup) && overridden == endpoint.SecuritySettingsOverridden {
+			continue
+		}
+
+		if err := tx.Endpoint().UpdateEndpoint(endpoint.ID, endpoint); err != nil {
+			return nil, err
+		}
+
+		updated = append(updated, endpoint.ID)
+	}
+
+	return updated, nil
+}
@@ -7,8 +7,11 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/authorization"
 	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/templatevariables"
 	"github.com/portainer/portainer/api/pendingactions/handlers"
+	"github.com/portainer/portainer/api/stacks/stackutils"
 	"github.com/portainer/portainer/api/tag"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
@@ -26,10 +29,39 @@ type endpointGroupUpdatePayload struct {
 	TagIDs             []portainer.TagID `example:"3,4"`
 	UserAccessPolicies portainer.UserAccessPolicies
 	TeamAccessPolicies portainer.TeamAccessPolicies
+	// Docker operations denied to non-administrators on the environments(endpoints) of the group
+	DeniedOperationsForRegularUsers []portainer.Authorization `example:"DockerImageBuild"`
+	// Values pre-filled for the custom template variables when deploying on the environments(endpoints) of the group
+	CustomTemplateVariablePresets []portainer.CustomTemplateVariablePreset
+	// Default security settings of the environments(endpoints) of the group, they are applied to the environments
+	// that do not override them
+	SecuritySettings *portainer.EndpointSecuritySettings
+	// Remove the default security settings of the group, the environments(endpoints) keep their current settings
+	RemoveSecuritySettings bool `example:"false"`
+	// Whether the default security settings apply to every environment(endpoint) of the group and cannot be overridden
+	EnforceSecuritySettings *bool `example:"false"`
 }
 
 func (payload *endpointGroupUpdatePayload) Validate(r *http.Request) error {
-	return nil
+	if err := authorization.ValidateDockerOperations(payload.DeniedOperationsForRegularUsers); err != nil {
+		return err
+	}
+
+	if payload.SecuritySettings != nil {
+		if payload.RemoveSecuritySettings {
+			return errors.New("the security settings cannot be both set and removed")
+		}
+
+		if err := authorization.ValidateDockerOperations(payload.SecuritySettings.DeniedOperationsForRegularUsers); err != nil {
+			return err
+		}
+
+		if err := stackutils.ValidateBlockedBindMountPrefixes(payload.SecuritySettings.BlockedBindMountPrefixes); err != nil {
+			return err
+		}
+	}
+
+	return templatevariables.ValidatePresets(payload.CustomTemplateVariablePresets)
 }
 
 // @id EndpointGroupUpdate
@@ -93,6 +125,29 @@ func (handler *Handler) updateEndpointGroup(tx dataservices.DataStoreTx, endpoin
 		endpointGroup.Description = payload.Description
 	}
 
+	if payload.DeniedOperationsForRegularUsers != nil {
+		endpointGroup.DeniedOperationsForRegularUsers = payload.DeniedOperationsForRegularUsers
+	}
+
+	if payload.CustomTemplateVariablePresets != nil {
+		endpointGroup.CustomTemplateVariablePresets = payload.CustomTemplateVariablePresets
+	}
+
+	securitySettingsChanged := false
+	if payload.SecuritySettings != nil && !reflect.DeepEqual(payload.SecuritySettings, endpointGroup.SecuritySettings) {
+		endpointGroup.SecuritySettings = payload.SecuritySettings
+		securitySettingsChanged = true
+	}
+
+	if payload.RemoveSecuritySettings {
+		endpointGroup.SecuritySettings = nil
+	}
+
+	if payload.EnforceSecuritySettings != nil && *payload.EnforceSecuritySettings != endpointGroup.EnforceSecuritySettings {
+		endpointGroup.EnforceSecuritySettings = *payload.EnforceSecuritySettings
+		securitySettingsChanged = true
+	}
+
 	tagsChanged := false
 	if payload.TagIDs != nil {
 		payloadTagSet := tag.Set(payload.TagIDs)
@@ -135,6 +190,12 @@ func (handler *Handler) updateEndpointGroup(tx dataservices.DataStoreTx, endpoin
 		}
 	}
 
+	if err := authorization.ValidateAccessPolicyRoles(tx, payload.UserAccessPolicies, payload.TeamAccessPolicies); errors.Is(err, authorization.ErrUnknownRole) {
+		return nil, httperror.BadRequest("Invalid access policies", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve the roles from the database", err)
+	}
+
 	updateAuthorizations := false
 	if payload.UserAccessPolicies != nil && !reflect.DeepEqual(payload.UserAccessPolicies, endpointGroup.UserAccessPolicies) {
 		endpointGroup.UserAccessPolicies = payload.UserAccessPolicies
@@ -170,6 +231,12 @@ func (handler *Handler) updateEndpointGroup(tx dataservices.DataStoreTx, endpoin
 		return nil, httperror.InternalServerError("Unable to persist environment group changes inside the database", err)
 	}
 
+	if securitySettingsChanged {
+		if _, err := propagateSecuritySettings(tx, endpointGroup, false); err != nil {
+			return nil, httperror.InternalServerError("Unable to apply the security settings of the group to its environments", err)
+		}
+	}
+
 	if tagsChanged {
 		endpoints, err := tx.Endpoint().Endpoints()
 		if err != nil {
@@ -39,5 +39,7 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointGroupAddEndpoint))).Methods(http.MethodPut)
 	h.Handle("/endpoint_groups/{id}/endpoints/{endpointId}",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointGroupDeleteEndpoint))).Methods(http.MethodDelete)
+	h.Handle("/endpoint_groups/{id}/security_settings/propagate",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointGroupSecuritySettingsPropagate))).Methods(http.MethodPost)
 	return h
 }
@@ -1,6 +1,8 @@
 package endpointproxy
 
 import (
+	"net/http"
+
 	"github.com/gorilla/mux"
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
@@ -18,21 +20,23 @@ type Handler struct {
 	ReverseTunnelService portainer.ReverseTunnelService
 }
 
-// NewHandler creates a handler to proxy requests to external APIs.
-func NewHandler(bouncer security.BouncerService) *Handler {
+// NewHandler creates a handler to proxy requests to external APIs. The native Docker operations exposed next to the
+// Docker API of the environments are served by dockerHandler.
+func NewHandler(bouncer security.BouncerService, rateLimiter *security.ProxyRateLimiter, dockerHandler http.Handler) *Handler {
 	h := &Handler{
 		Router:         mux.NewRouter(),
 		requestBouncer: bouncer,
 	}
+	h.Handle("/{id}/docker/containers/{containerId}/recreate", dockerHandler).Methods(http.MethodPost)
 	h.PathPrefix("/{id}/azure").Handler(
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.proxyRequestsToAzureAPI)))
 	h.PathPrefix("/{id}/docker").Handler(
-		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.proxyRequestsToDockerAPI)))
+		bouncer.AuthenticatedAccess(rateLimiter.LimitAccess(httperror.LoggerHandler(h.proxyRequestsToDockerAPI))))
 	h.PathPrefix("/{id}/kubernetes").Handler(
-		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.proxyRequestsToKubernetesAPI)))
+		bouncer.AuthenticatedAccess(rateLimiter.LimitAccess(httperror.LoggerHandler(h.proxyRequestsToKubernetesAPI))))
 	h.PathPrefix("/{id}/agent/docker").Handler(
-		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.proxyRequestsToDockerAPI)))
+		bouncer.AuthenticatedAccess(rateLimiter.LimitAccess(httperror.LoggerHandler(h.proxyRequestsToDockerAPI))))
 	h.PathPrefix("/{id}/agent/kubernetes").Handler(
-		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.proxyRequestsToKubernetesAPI)))
+		bouncer.AuthenticatedAccess(rateLimiter.LimitAccess(httperror.LoggerHandler(h.proxyRequestsToKubernetesAPI))))
 	return h
 }
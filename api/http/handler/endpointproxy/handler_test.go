@@ -0,0 +1,51 @@
+package endpointproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	dockerhandler "github.com/portainer/portainer/api/http/handler/docker"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerRecreateRoute(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1, Name: "docker", Type: portainer.DockerEnvironment}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 2, Name: "kubernetes", Type: portainer.KubernetesLocalEnvironment}))
+
+	bouncer := testhelpers.NewTestRequestBouncer()
+	dockerHandler := dockerhandler.NewHandler(bouncer, nil, store, nil, nil)
+	h := NewHandler(bouncer, security.NewProxyRateLimiter(portainer.ProxyRateLimitSettings{}), dockerHandler)
+	h.DataStore = store
+
+	recreate := func(endpointID, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/"+endpointID+"/docker/containers/abc/recreate", strings.NewReader(body))
+		r = r.WithContext(security.StoreTokenData(r, &portainer.TokenData{ID: 1, Role: portainer.AdministratorRole}))
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, r)
+
+		return rr
+	}
+
+	// The requests are served by the native Docker handler instead of being proxied to the environment
+	rr := recreate("1", `{"Image": "Invalid Image"}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Invalid request payload")
+
+	rr = recreate("2", `{}`)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "not a docker environment")
+
+	rr = recreate("3", `{}`)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,228 @@
+package endpoints
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type endpointBulkSecuritySettingsPayload struct {
+	// Whether non-administrator should be able to use bind mounts when creating containers
+	AllowBindMountsForRegularUsers *bool `json:"allowBindMountsForRegularUsers" example:"false"`
+	// Whether non-administrator should be able to use privileged mode when creating containers
+	AllowPrivilegedModeForRegularUsers *bool `json:"allowPrivilegedModeForRegularUsers" example:"false"`
+	// Whether non-administrator should be able to browse volumes
+	AllowVolumeBrowserForRegularUsers *bool `json:"allowVolumeBrowserForRegularUsers" example:"true"`
+	// Whether non-administrator should be able to use the host pid
+	AllowHostNamespaceForRegularUsers *bool `json:"allowHostNamespaceForRegularUsers" example:"true"`
+	// Whether non-administrator should be able to use device mapping
+	AllowDeviceMappingForRegularUsers *bool `json:"allowDeviceMappingForRegularUsers" example:"true"`
+	// Whether non-administrator should be able to manage stacks
+	AllowStackManagementForRegularUsers *bool `json:"allowStackManagementForRegularUsers" example:"true"`
+	// Whether non-administrator should be able to use container capabilities
+	AllowContainerCapabilitiesForRegularUsers *bool `json:"allowContainerCapabilitiesForRegularUsers" example:"true"`
+	// Whether non-administrator should be able to use sysctl settings
+	AllowSysctlSettingForRegularUsers *bool `json:"allowSysctlSettingForRegularUsers" example:"true"`
+	// Whether host management features are enabled
+	EnableHostManagementFeatures *bool `json:"enableHostManagementFeatures" example:"true"`
+}
+
+// apply toggles the security settings set in the payload, the other ones are kept
+func (payload *endpointBulkSecuritySettingsPayload) apply(securitySettings *portainer.EndpointSecuritySettings) {
+	toggles := []struct {
+		value   *bool
+		setting *bool
+	}{
+		{payload.AllowBindMountsForRegularUsers, &securitySettings.AllowBindMountsForRegularUsers},
+		{payload.AllowPrivilegedModeForRegularUsers, &securitySettings.AllowPrivilegedModeForRegularUsers},
+		{payload.AllowVolumeBrowserForRegularUsers, &securitySettings.AllowVolumeBrowserForRegularUsers},
+		{payload.AllowHostNamespaceForRegularUsers, &securitySettings.AllowHostNamespaceForRegularUsers},
+		{payload.AllowDeviceMappingForRegularUsers, &securitySettings.AllowDeviceMappingForRegularUsers},
+		{payload.AllowStackManagementForRegularUsers, &securitySettings.AllowStackManagementForRegularUsers},
+		{payload.AllowContainerCapabilitiesForRegularUsers, &securitySettings.AllowContainerCapabilitiesForRegularUsers},
+		{payload.AllowSysctlSettingForRegularUsers, &securitySettings.AllowSysctlSettingForRegularUsers},
+		{payload.EnableHostManagementFeatures, &securitySettings.EnableHostManagementFeatures},
+	}
+
+	for _, toggle := range toggles {
+		if toggle.value != nil {
+			*toggle.setting = *toggle.value
+		}
+	}
+}
+
+type endpointBulkUpdatePayload struct {
+	// Environments(Endpoints) to update
+	EndpointIDs []portainer.EndpointID `json:"endpointIds" validate:"required" example:"1,2"`
+	// Group the environments are moved to
+	GroupID *portainer.EndpointGroupID `json:"groupId" example:"2"`
+	// Tags added to the environments
+	AddTagIDs []portainer.TagID `json:"addTagIds" example:"1"`
+	// Tags removed from the environments
+	RemoveTagIDs []portainer.TagID `json:"removeTagIds" example:"3"`
+	// Security settings toggled on the environments, they override the default security settings of their group
+	SecuritySettings *endpointBulkSecuritySettingsPayload `json:"securitySettings"`
+}
+
+func (payload *endpointBulkUpdatePayload) Validate(r *http.Request) error {
+	if len(payload.EndpointIDs) == 0 {
+		return errors.New("invalid environment identifiers. At least one environment is required")
+	}
+
+	if slices.Contains(payload.EndpointIDs, 0) {
+		return errors.New("missing environment identifier")
+	}
+
+	if payload.GroupID != nil && *payload.GroupID == 0 {
+		return errors.New("invalid environment group identifier")
+	}
+
+	for _, tagID := range payload.AddTagIDs {
+		if slices.Contains(payload.RemoveTagIDs, tagID) {
+			return fmt.Errorf("the tag %d cannot be both added and removed", tagID)
+		}
+	}
+
+	if payload.GroupID == nil && len(payload.AddTagIDs) == 0 && len(payload.RemoveTagIDs) == 0 && payload.SecuritySettings == nil {
+		return errors.New("nothing to update. A group, tags or security settings are required")
+	}
+
+	return nil
+}
+
+// @id EndpointBulkUpdate
+// @summary Update many environments(endpoints) at once
+// @description Move environments to a group, add and remove tags and toggle security settings across many environments.
+// @description The edge group memberships and the environment relations are recomputed in the same transaction,
+// @description either every environment is updated or none is.
+// @description The security settings cannot be changed on the environments whose group enforces its defaults.
+// @description **Access policy**: administrator
+// @tags endpoints
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body endpointBulkUpdatePayload true "Changes applied to the environments"
+// @success 200 {array} portainer.Endpoint "Success"
+// @failure 400 "Invalid request, unknown group or tag"
+// @failure 403 "The security settings are enforced by the group of an environment"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /endpoints/bulk [patch]
+func (handler *Handler) endpointBulkUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	payload, err := request.GetPayload[endpointBulkUpdatePayload](r)
+	if err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	endpoints, err := dataservices.UpdateTx(handler.DataStore, func(tx dataservices.DataStoreTx) ([]portainer.Endpoint, error) {
+		return handler.bulkUpdateEndpoints(tx, payload)
+	})
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unable to update the environments", err)
+	}
+
+	return response.JSON(w, endpoints)
+}
+
+func (handler *Handler) bulkUpdateEndpoints(tx dataservices.DataStoreTx, payload *endpointBulkUpdatePayload) ([]portainer.Endpoint, error) {
+	var group *portainer.EndpointGroup
+	if payload.GroupID != nil {
+		var err error
+		group, err = tx.EndpointGroup().Read(*payload.GroupID)
+		if tx.IsErrObjectNotFound(err) {
+			return nil, httperror.BadRequest("Unable to find an environment group with the specified identifier inside the database", err)
+		} else if err != nil {
+			return nil, httperror.InternalServerError("Unable to find an environment group with the specified identifier inside the database", err)
+		}
+	}
+
+	for _, tagID := range slices.Concat(payload.AddTagIDs, payload.RemoveTagIDs) {
+		if _, err := tx.Tag().Read(tagID); tx.IsErrObjectNotFound(err) {
+			return nil, httperror.BadRequest("Unable to find a tag with the specified identifier inside the database", err)
+		} else if err != nil {
+			return nil, httperror.InternalServerError("Unable to find a tag with the specified identifier inside the database", err)
+		}
+	}
+
+	endpoints := make([]portainer.Endpoint, 0, len(payload.EndpointIDs))
+
+	for _, endpointID := range payload.EndpointIDs {
+		endpoint, err := tx.Endpoint().Endpoint(endpointID)
+		if tx.IsErrObjectNotFound(err) {
+			return nil, httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+		} else if err != nil {
+			return nil, httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+		}
+
+		updateRelations := false
+
+		if group != nil && endpoint.GroupID != group.ID {
+			endpoint.GroupID = group.ID
+			endpointutils.ApplyGroupSecuritySettings(endpoint, group)
+			updateRelations = true
+		}
+
+		if len(payload.AddTagIDs) > 0 || len(payload.RemoveTagIDs) > 0 {
+			tagIDs := slices.Clone(endpoint.TagIDs)
+			for _, tagID := range payload.AddTagIDs {
+				if !slices.Contains(tagIDs, tagID) {
+					tagIDs = append(tagIDs, tagID)
+				}
+			}
+
+			tagIDs = slices.DeleteFunc(tagIDs, func(tagID portainer.TagID) bool {
+				return slices.Contains(payload.RemoveTagIDs, tagID)
+			})
+
+			tagsChanged, err := updateEnvironmentTags(tx, tagIDs, endpoint.TagIDs, endpoint.ID)
+			if err != nil {
+				return nil, httperror.InternalServerError("Unable to update environment tags", err)
+			}
+
+			endpoint.TagIDs = tagIDs
+			updateRelations = updateRelations || tagsChanged
+		}
+
+		if payload.SecuritySettings != nil {
+			endpointGroup, err := tx.EndpointGroup().Read(endpoint.GroupID)
+			if err != nil && !tx.IsErrObjectNotFound(err) {
+				return nil, httperror.InternalServerError("Unable to find an environment group inside the database", err)
+			}
+
+			if endpointutils.SecuritySettingsLocked(endpointGroup) {
+				return nil, httperror.Forbidden(fmt.Sprintf("The security settings of the environment %s are enforced by its group", endpoint.Name), errors.New("security settings locked by the environment group"))
+			}
+
+			payload.SecuritySettings.apply(&endpoint.SecuritySettings)
+			endpoint.SecuritySettingsOverridden = true
+		}
+
+		if err := tx.Endpoint().UpdateEndpoint(endpoint.ID, endpoint); err != nil {
+			return nil, httperror.InternalServerError("Unable to persist environment changes inside the database", err)
+		}
+
+		if updateRelations {
+			if err := handler.updateEdgeRelations(tx, endpoint); err != nil {
+				return nil, httperror.InternalServerError("Unable to update environment relations", err)
+			}
+		}
+
+		endpoints = append(endpoints, *endpoint)
+	}
+
+	return endpoints, nil
+}
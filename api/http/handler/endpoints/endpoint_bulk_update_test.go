@@ -0,0 +1,103 @@
+package endpoints
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointBulkUpdate(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, false)
+
+	handler := NewHandler(testhelpers.NewTestRequestBouncer())
+	handler.DataStore = store
+
+	group := &portainer.EndpointGroup{Name: "production"}
+	require.NoError(t, store.EndpointGroup().Create(group))
+
+	lockedGroup := &portainer.EndpointGroup{
+		Name:                    "locked",
+		SecuritySettings:        &portainer.EndpointSecuritySettings{},
+		EnforceSecuritySettings: true,
+	}
+	require.NoError(t, store.EndpointGroup().Create(lockedGroup))
+
+	var tagIDs []portainer.TagID
+	for range 2 {
+		tag := &portainer.Tag{
+			Endpoints:      map[portainer.EndpointID]bool{1: true},
+			EndpointGroups: map[portainer.EndpointGroupID]bool{},
+		}
+		require.NoError(t, store.Tag().Create(tag))
+
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1, Name: "env-1", GroupID: 1, TagIDs: tagIDs}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 2, Name: "env-2", GroupID: 1}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 3, Name: "env-3", GroupID: lockedGroup.ID}))
+
+	bulkUpdate := func(payload endpointBulkUpdatePayload) *httptest.ResponseRecorder {
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPatch, "/endpoints/bulk", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	allow := true
+
+	rec := bulkUpdate(endpointBulkUpdatePayload{
+		EndpointIDs:      []portainer.EndpointID{1, 2},
+		GroupID:          &group.ID,
+		AddTagIDs:        tagIDs[1:],
+		RemoveTagIDs:     tagIDs[:1],
+		SecuritySettings: &endpointBulkSecuritySettingsPayload{AllowVolumeBrowserForRegularUsers: &allow},
+	})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	for _, endpointID := range []portainer.EndpointID{1, 2} {
+		endpoint, err := store.Endpoint().Endpoint(endpointID)
+		require.NoError(t, err)
+		assert.Equal(t, group.ID, endpoint.GroupID)
+		assert.Equal(t, tagIDs[1:], endpoint.TagIDs)
+		assert.True(t, endpoint.SecuritySettings.AllowVolumeBrowserForRegularUsers)
+		assert.True(t, endpoint.SecuritySettingsOverridden)
+	}
+
+	tag, err := store.Tag().Read(tagIDs[0])
+	require.NoError(t, err)
+	assert.Empty(t, tag.Endpoints)
+
+	tag, err = store.Tag().Read(tagIDs[1])
+	require.NoError(t, err)
+	assert.Equal(t, map[portainer.EndpointID]bool{1: true, 2: true}, tag.Endpoints)
+
+	// The security settings of env-3 are enforced by its group, no environment is updated
+	rec = bulkUpdate(endpointBulkUpdatePayload{
+		EndpointIDs:      []portainer.EndpointID{1, 3},
+		AddTagIDs:        tagIDs[:1],
+		SecuritySettings: &endpointBulkSecuritySettingsPayload{AllowVolumeBrowserForRegularUsers: &allow},
+	})
+	require.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+
+	endpoint, err := store.Endpoint().Endpoint(1)
+	require.NoError(t, err)
+	assert.Equal(t, tagIDs[1:], endpoint.TagIDs)
+
+	// Unknown tags are rejected
+	rec = bulkUpdate(endpointBulkUpdatePayload{EndpointIDs: []portainer.EndpointID{1}, AddTagIDs: []portainer.TagID{42}})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -360,7 +360,7 @@ func (handler *Handler) createAzureEndpoint(tx dataservices.DataStoreTx, payload
 		Kubernetes:         portainer.KubernetesDefault(),
 	}
 
-	if err := handler.saveEndpointAndUpdateAuthorizations(tx, endpoint); err != nil {
+	if err := endpointutils.CreateEndpoint(tx, endpoint); err != nil {
 		return nil, httperror.InternalServerError("An error occurred while trying to create the environment", err)
 	}
 
@@ -413,7 +413,7 @@ func (handler *Handler) createEdgeAgentEndpoint(tx dataservices.DataStoreTx, pay
 		endpoint.EdgeID = edgeID.String()
 	}
 
-	if err := handler.saveEndpointAndUpdateAuthorizations(tx, endpoint); err != nil {
+	if err := endpointutils.CreateEndpoint(tx, endpoint); err != nil {
 		return nil, httperror.InternalServerError("An error occurred while trying to create the environment", err)
 	}
 
@@ -538,42 +538,13 @@ func (handler *Handler) snapshotAndPersistEndpoint(tx dataservices.DataStoreTx,
 		return httperror.InternalServerError("Unable to initiate communications with environment", err)
 	}
 
-	if err := handler.saveEndpointAndUpdateAuthorizations(tx, endpoint); err != nil {
+	if err := endpointutils.CreateEndpoint(tx, endpoint); err != nil {
 		return httperror.InternalServerError("An error occurred while trying to create the environment", err)
 	}
 
 	return nil
 }
 
-func (handler *Handler) saveEndpointAndUpdateAuthorizations(tx dataservices.DataStoreTx, endpoint *portainer.Endpoint) error {
-	endpoint.SecuritySettings = portainer.EndpointSecuritySettings{
-		AllowVolumeBrowserForRegularUsers: false,
-		EnableHostManagementFeatures:      false,
-
-		AllowSysctlSettingForRegularUsers:         true,
-		AllowBindMountsForRegularUsers:            true,
-		AllowPrivilegedModeForRegularUsers:        true,
-		AllowHostNamespaceForRegularUsers:         true,
-		AllowContainerCapabilitiesForRegularUsers: true,
-		AllowDeviceMappingForRegularUsers:         true,
-		AllowStackManagementForRegularUsers:       true,
-	}
-
-	if err := tx.Endpoint().Create(endpoint); err != nil {
-		return err
-	}
-
-	for _, tagID := range endpoint.TagIDs {
-		if err := tx.Tag().UpdateTagFunc(tagID, func(tag *portainer.Tag) {
-			tag.Endpoints[endpoint.ID] = true
-		}); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
 func (handler *Handler) storeTLSFiles(endpoint *portainer.Endpoint, payload *endpointCreatePayload) *httperror.HandlerError {
 	folder := strconv.Itoa(int(endpoint.ID))
 
@@ -63,9 +63,10 @@ func (handler *Handler) endpointDelete(w http.ResponseWriter, r *http.Request) *
 		return httperror.BadRequest("Invalid boolean query parameter", err)
 	}
 
-	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+	endpoint, err := dataservices.UpdateTx(handler.DataStore, func(tx dataservices.DataStoreTx) (*portainer.Endpoint, error) {
 		return handler.deleteEndpoint(tx, portainer.EndpointID(endpointID), deleteCluster)
-	}); err != nil {
+	})
+	if err != nil {
 		var handlerError *httperror.HandlerError
 		if errors.As(err, &handlerError) {
 			return handlerError
@@ -74,6 +75,8 @@ func (handler *Handler) endpointDelete(w http.ResponseWriter, r *http.Request) *
 		return httperror.InternalServerError("Unexpected error", err)
 	}
 
+	handler.cleanUpEndpoint(endpoint)
+
 	return response.Empty(w)
 }
 
@@ -103,21 +106,20 @@ func (handler *Handler) endpointDeleteBatch(w http.ResponseWriter, r *http.Reque
 		Errors:  []int{},
 	}
 
-	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
-		for _, e := range p.Endpoints {
-			if err := handler.deleteEndpoint(tx, portainer.EndpointID(e.ID), e.DeleteCluster); err != nil {
-				resp.Errors = append(resp.Errors, e.ID)
-				log.Warn().Err(err).Int("environment_id", e.ID).Msg("Unable to remove environment")
-
-				continue
-			}
+	// Each environment is removed in its own transaction so that a failure only leaves that environment untouched
+	for _, e := range p.Endpoints {
+		endpoint, err := dataservices.UpdateTx(handler.DataStore, func(tx dataservices.DataStoreTx) (*portainer.Endpoint, error) {
+			return handler.deleteEndpoint(tx, portainer.EndpointID(e.ID), e.DeleteCluster)
+		})
+		if err != nil {
+			resp.Errors = append(resp.Errors, e.ID)
+			log.Warn().Err(err).Int("environment_id", e.ID).Msg("Unable to remove environment")
 
-			resp.Deleted = append(resp.Deleted, e.ID)
+			continue
 		}
 
-		return nil
-	}); err != nil {
-		return httperror.InternalServerError("Unable to delete environments", err)
+		handler.cleanUpEndpoint(endpoint)
+		resp.Deleted = append(resp.Deleted, e.ID)
 	}
 
 	if len(resp.Errors) > 0 {
@@ -127,70 +129,60 @@ func (handler *Handler) endpointDeleteBatch(w http.ResponseWriter, r *http.Reque
 	return response.Empty(w)
 }
 
-func (handler *Handler) deleteEndpoint(tx dataservices.DataStoreTx, endpointID portainer.EndpointID, deleteCluster bool) error {
+// deleteEndpoint removes an environment along with the references of the other objects to it. The removal is
+// aborted when one of the references cannot be removed so that the transaction is rolled back as a whole
+func (handler *Handler) deleteEndpoint(tx dataservices.DataStoreTx, endpointID portainer.EndpointID, deleteCluster bool) (*portainer.Endpoint, error) {
 	endpoint, err := tx.Endpoint().Endpoint(endpointID)
 	if tx.IsErrObjectNotFound(err) {
-		return httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+		return nil, httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
 	} else if err != nil {
-		return httperror.InternalServerError("Unable to read the environment record from the database", err)
-	}
-
-	if endpoint.TLSConfig.TLS {
-		folder := strconv.Itoa(int(endpointID))
-		if err := handler.FileService.DeleteTLSFiles(folder); err != nil {
-			log.Error().Err(err).Msgf("Unable to remove TLS files from disk when deleting endpoint %d", endpointID)
-		}
+		return nil, httperror.InternalServerError("Unable to read the environment record from the database", err)
 	}
 
 	if err := tx.Snapshot().Delete(endpointID); err != nil {
-		log.Warn().Err(err).Msg("Unable to remove the snapshot from the database")
-	}
-
-	handler.ProxyManager.DeleteEndpointProxy(endpoint.ID)
-
-	if len(endpoint.UserAccessPolicies) > 0 || len(endpoint.TeamAccessPolicies) > 0 {
-		if err := handler.AuthorizationService.UpdateUsersAuthorizationsTx(tx); err != nil {
-			log.Warn().Err(err).Msg("Unable to update user authorizations")
-		}
+		return nil, httperror.InternalServerError("Unable to remove the snapshot from the database", err)
 	}
 
 	if err := tx.EndpointRelation().DeleteEndpointRelation(endpoint.ID); err != nil {
-		log.Warn().Err(err).Msg("Unable to remove environment relation from the database")
+		return nil, httperror.InternalServerError("Unable to remove environment relation from the database", err)
 	}
 
 	for _, tagID := range endpoint.TagIDs {
-		var tag *portainer.Tag
-		tag, err = tx.Tag().Read(tagID)
+		tag, err := tx.Tag().Read(tagID)
 		if err == nil {
 			delete(tag.Endpoints, endpoint.ID)
 			err = tx.Tag().Update(tagID, tag)
 		}
 
-		if handler.DataStore.IsErrObjectNotFound(err) {
+		if tx.IsErrObjectNotFound(err) {
 			log.Warn().Err(err).Msg("Unable to find tag inside the database")
 		} else if err != nil {
-			log.Warn().Err(err).Msg("Unable to delete tag relation from the database")
+			return nil, httperror.InternalServerError("Unable to delete tag relation from the database", err)
 		}
 	}
 
 	edgeGroups, err := tx.EdgeGroup().ReadAll()
 	if err != nil {
-		log.Warn().Err(err).Msgf("Unable to retrieve edge groups from the database")
+		return nil, httperror.InternalServerError("Unable to retrieve edge groups from the database", err)
 	}
 
 	for _, edgeGroup := range edgeGroups {
+		if !slices.Contains(edgeGroup.Endpoints, endpoint.ID) {
+			continue
+		}
+
 		edgeGroup.Endpoints = slices.DeleteFunc(edgeGroup.Endpoints, func(e portainer.EndpointID) bool {
 			return e == endpoint.ID
 		})
 
 		if err := tx.EdgeGroup().Update(edgeGroup.ID, &edgeGroup); err != nil {
-			log.Warn().Err(err).Msg("Unable to update edge group")
+			return nil, httperror.InternalServerError("Unable to update edge group", err)
 		}
 	}
 
 	edgeStacks, err := tx.EdgeStack().EdgeStacks()
 	if err != nil {
-		log.Warn().Err(err).Msg("Unable to retrieve edge stacks from the database")
+		return nil, httperror.InternalServerError("Unable to retrieve edge stacks from the database", err)
 	}
 
 	for idx := range edgeStacks {
@@ -199,14 +191,14 @@ func (handler *Handler) deleteEndpoint(tx dataservices.DataStoreTx, endpointID p
 			delete(edgeStack.Status, endpoint.ID)
 
 			if err := tx.EdgeStack().UpdateEdgeStack(edgeStack.ID, edgeStack); err != nil {
-				log.Warn().Err(err).Msg("Unable to update edge stack")
+				return nil, httperror.InternalServerError("Unable to update edge stack", err)
 			}
 		}
 	}
 
 	registries, err := tx.Registry().ReadAll()
 	if err != nil {
-		log.Warn().Err(err).Msg("Unable to retrieve registries from the database")
+		return nil, httperror.InternalServerError("Unable to retrieve registries from the database", err)
 	}
 
 	for idx := range registries {
@@ -215,15 +207,39 @@ func (handler *Handler) deleteEndpoint(tx dataservices.DataStoreTx, endpointID p
 			delete(registry.RegistryAccesses, endpoint.ID)
 
 			if err := tx.Registry().Update(registry.ID, registry); err != nil {
-				log.Warn().Err(err).Msg("Unable to update registry accesses")
+				return nil, httperror.InternalServerError("Unable to update registry accesses", err)
 			}
 		}
 	}
 
+	userSettings, err := tx.UserSettings().ReadAll()
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve user settings from the database", err)
+	}
+
+	for idx := range userSettings {
+		settings := &userSettings[idx]
+		if settings.DefaultEndpointID != endpoint.ID && !slices.Contains(settings.FavoriteEndpoints, endpoint.ID) {
+			continue
+		}
+
+		if settings.DefaultEndpointID == endpoint.ID {
+			settings.DefaultEndpointID = 0
+		}
+
+		settings.FavoriteEndpoints = slices.DeleteFunc(settings.FavoriteEndpoints, func(e portainer.EndpointID) bool {
+			return e == endpoint.ID
+		})
+
+		if err := tx.UserSettings().Update(settings.UserID, settings); err != nil {
+			return nil, httperror.InternalServerError("Unable to update user settings", err)
+		}
+	}
+
 	if endpointutils.IsEdgeEndpoint(endpoint) {
-		edgeJobs, err := handler.DataStore.EdgeJob().ReadAll()
+		edgeJobs, err := tx.EdgeJob().ReadAll()
 		if err != nil {
-			log.Warn().Err(err).Msg("Unable to retrieve edge jobs from the database")
+			return nil, httperror.InternalServerError("Unable to retrieve edge jobs from the database", err)
 		}
 
 		for idx := range edgeJobs {
@@ -232,20 +248,63 @@ func (handler *Handler) deleteEndpoint(tx dataservices.DataStoreTx, endpointID p
 				delete(edgeJob.Endpoints, endpoint.ID)
 
 				if err := tx.EdgeJob().Update(edgeJob.ID, edgeJob); err != nil {
-					log.Warn().Err(err).Msg("Unable to update edge job")
+					return nil, httperror.InternalServerError("Unable to update edge job", err)
 				}
 			}
 		}
 	}
 
-	// delete the pending actions
 	if err := tx.PendingActions().DeleteByEndpointID(endpoint.ID); err != nil {
-		log.Warn().Err(err).Int("endpointId", int(endpoint.ID)).Msg("Unable to delete pending actions")
+		return nil, httperror.InternalServerError("Unable to delete pending actions", err)
+	}
+
+	if err := tx.DiskUsageSample().DeleteByEndpointID(endpoint.ID); err != nil {
+		return nil, httperror.InternalServerError("Unable to delete disk usage samples", err)
+	}
+
+	if err := tx.SnapshotRecord().DeleteByEndpointID(endpoint.ID); err != nil {
+		return nil, httperror.InternalServerError("Unable to delete snapshot records", err)
+	}
+
+	if err := tx.EdgeCommand().DeleteByEndpointID(endpoint.ID); err != nil {
+		return nil, httperror.InternalServerError("Unable to delete edge commands", err)
+	}
+
+	if err := tx.ActivityEvent().DeleteByEndpointID(endpoint.ID); err != nil {
+		return nil, httperror.InternalServerError("Unable to delete activity events", err)
+	}
+
+	if err := tx.OpenAMTDeviceAction().DeleteByEndpointID(endpoint.ID); err != nil {
+		return nil, httperror.InternalServerError("Unable to delete OpenAMT device actions", err)
+	}
+
+	if err := tx.PruneRun().DeleteByEndpointID(endpoint.ID); err != nil {
+		return nil, httperror.InternalServerError("Unable to delete prune runs", err)
 	}
 
 	if err := tx.Endpoint().DeleteEndpoint(endpointID); err != nil {
-		return httperror.InternalServerError("Unable to delete the environment from the database", err)
+		return nil, httperror.InternalServerError("Unable to delete the environment from the database", err)
 	}
 
-	return nil
+	// The authorizations are computed from the remaining environments so they are updated once it is removed
+	if len(endpoint.UserAccessPolicies) > 0 || len(endpoint.TeamAccessPolicies) > 0 {
+		if err := handler.AuthorizationService.UpdateUsersAuthorizationsTx(tx); err != nil {
+			return nil, httperror.InternalServerError("Unable to update user authorizations", err)
+		}
+	}
+
+	return endpoint, nil
+}
+
+// cleanUpEndpoint releases the resources of a removed environment that are kept outside of the database,
+// it is only called once the removal of the environment is committed
+func (handler *Handler) cleanUpEndpoint(endpoint *portainer.Endpoint) {
+	if endpoint.TLSConfig.TLS {
+		folder := strconv.Itoa(int(endpoint.ID))
+		if err := handler.FileService.DeleteTLSFiles(folder); err != nil {
+			log.Error().Err(err).Msgf("Unable to remove TLS files from disk when deleting endpoint %d", endpoint.ID)
+		}
+	}
+
+	handler.ProxyManager.DeleteEndpointProxy(endpoint.ID)
 }
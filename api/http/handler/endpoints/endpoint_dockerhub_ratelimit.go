@@ -0,0 +1,56 @@
+package endpoints
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/dockerhub"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id EndpointDockerHubRateLimit
+// @summary Retrieve the DockerHub pull quota of an environment(endpoint)
+// @description Retrieve the number of pulls left on DockerHub for an environment(endpoint), using the credentials of the first
+// @description authenticated DockerHub registry available in the environment, or the anonymous quota of a local environment.
+// @description The quota is cached for a minute.
+// @description **Access policy**: restricted
+// @tags endpoints
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @success 200 {object} dockerhub.RateLimit "Success"
+// @failure 400 "Invalid request or no DockerHub credentials available for a remote environment"
+// @failure 403 "Permission denied"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /endpoints/{id}/dockerhub/ratelimit [get]
+func (handler *Handler) endpointDockerHubRateLimit(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid environment identifier route variable", err)
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	if err := handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint); err != nil {
+		return httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	rateLimit, err := handler.DockerHubService.EndpointRateLimit(endpoint)
+	if errors.Is(err, dockerhub.ErrAnonymousRemoteEnvironment) {
+		return httperror.BadRequest("No DockerHub credentials available for the environment", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to retrieve DockerHub rate limits from DockerHub", err)
+	}
+
+	return response.JSON(w, rateLimit)
+}
@@ -0,0 +1,87 @@
+package endpoints
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/activity"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id EndpointEvents
+// @summary List the activity of an environment(endpoint)
+// @description List the Docker events recorded for an environment(endpoint), from the most recent to the oldest.
+// @description Only the latest events of each environment are kept.
+// @description **Access policy**: restricted
+// @tags endpoints
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param type query string false "Only list the events of this type" Enums(container, image, volume, network, service, node, secret, config, plugin, daemon)
+// @param since query int false "Unix timestamp, only list the events that happened at or after it"
+// @param until query int false "Unix timestamp, only list the events that happened at or before it"
+// @success 200 {array} portainer.ActivityEvent "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /endpoints/{id}/events [get]
+func (handler *Handler) endpointEvents(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid environment identifier route variable", err)
+	}
+
+	eventType, _ := request.RetrieveQueryParameter(r, "type", true)
+
+	since, err := request.RetrieveNumericQueryParameter(r, "since", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: since", err)
+	}
+
+	until, err := request.RetrieveNumericQueryParameter(r, "until", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: until", err)
+	}
+
+	if until != 0 && until < since {
+		return httperror.BadRequest("Invalid query parameter: until", errors.New("until must not be before since"))
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	if err := handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint); err != nil {
+		return httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	events, err := handler.DataStore.ActivityEvent().ReadAllByEndpointID(endpoint.ID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the activity of the environment", err)
+	}
+
+	filtered := make([]portainer.ActivityEvent, 0, len(events))
+	for _, event := range events {
+		if eventType != "" && event.Type != eventType {
+			continue
+		}
+
+		if event.Time < int64(since) || (until != 0 && event.Time > int64(until)) {
+			continue
+		}
+
+		filtered = append(filtered, event)
+	}
+
+	activity.SortEvents(filtered)
+
+	return response.JSON(w, filtered)
+}
@@ -0,0 +1,48 @@
+package endpoints
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id EndpointInsights
+// @summary Retrieve the insights of an environment(endpoint)
+// @description Retrieve advisory information about an environment(endpoint), such as the containers stuck in a restart loop
+// @description along with their last exit codes and log tail, and the projected disk pressure.
+// @description The names and logs of every container are returned, regardless of their resource controls.
+// @description **Access policy**: administrator
+// @tags endpoints
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @success 200 {object} insights.Insights "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /endpoints/{id}/insights [get]
+func (handler *Handler) endpointInsights(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid environment identifier route variable", err)
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	insights, err := handler.InsightsService.EndpointInsights(r.Context(), endpoint)
+	if err != nil {
+		return httperror.InternalServerError("Unable to compute the environment insights", err)
+	}
+
+	return response.JSON(w, insights)
+}
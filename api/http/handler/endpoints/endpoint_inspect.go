@@ -49,7 +49,7 @@ func (handler *Handler) endpointInspect(w http.ResponseWriter, r *http.Request)
 
 	hideFields(endpoint)
 	endpointutils.UpdateEdgeEndpointHeartbeat(endpoint, settings)
-	endpoint.ComposeSyntaxMaxVersion = handler.ComposeStackManager.ComposeSyntaxMaxVersion()
+	endpoint.ComposeSyntaxMaxVersion = handler.ComposeStackManager.ComposeSyntaxMaxVersion(endpoint)
 
 	if !excludeSnapshot(r) {
 		err = handler.SnapshotService.FillSnapshotData(endpoint)
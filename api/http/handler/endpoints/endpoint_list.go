@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/endpointutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
@@ -43,7 +44,7 @@ const (
 // @param edgeAsync query bool false "if exists true show only edge async agents, false show only standard edge agents. if missing, will show both types (relevant only for edge agents)"
 // @param edgeDeviceUntrusted query bool false "if true, show only untrusted edge agents, if false show only trusted edge agents (relevant only for edge agents)"
 // @param edgeCheckInPassedSeconds query number false "if bigger then zero, show only edge agents that checked-in in the last provided seconds (relevant only for edge agents)"
-// @param excludeSnapshots query bool false "if true, the snapshot data won't be retrieved"
+// @param excludeSnapshots query bool false "if true, the snapshot data won't be retrieved. The raw Docker data of the snapshots is never retrieved, use the environment inspect to get it"
 // @param name query string false "will return only environments(endpoints) with this name"
 // @param edgeStackId query portainer.EdgeStackID false "will return the environements of the specified edge stack"
 // @param edgeStackStatus query string false "only applied when edgeStackId exists. Filter the returned environments based on their deployment status in the stack (not the environment status!)" Enum("Pending", "Ok", "Error", "Acknowledged", "Remove", "RemoteUpdateSuccess", "ImagesPulled")
@@ -70,11 +71,6 @@ func (handler *Handler) endpointList(w http.ResponseWriter, r *http.Request) *ht
 		return httperror.InternalServerError("Unable to retrieve edge groups from the database", err)
 	}
 
-	endpoints, err := handler.DataStore.Endpoint().Endpoints()
-	if err != nil {
-		return httperror.InternalServerError("Unable to retrieve environments from the database", err)
-	}
-
 	settings, err := handler.DataStore.Settings().Settings()
 	if err != nil {
 		return httperror.InternalServerError("Unable to retrieve settings from the database", err)
@@ -90,28 +86,48 @@ func (handler *Handler) endpointList(w http.ResponseWriter, r *http.Request) *ht
 		return httperror.BadRequest("Invalid query parameters", err)
 	}
 
-	filteredEndpoints := security.FilterEndpoints(endpoints, endpointGroups, securityContext)
+	var paginatedEndpoints []portainer.Endpoint
+	var filteredEndpointCount, totalAvailableEndpoints int
 
-	filteredEndpoints, totalAvailableEndpoints, err := handler.filterEndpointsByQuery(filteredEndpoints, query, endpointGroups, edgeGroups, settings)
-	if err != nil {
-		return httperror.InternalServerError("Unable to filter endpoints", err)
-	}
+	// Administrators see every environment, so unsorted lists can be filtered and paginated by the database
+	if filters, ok := query.databaseFilters(); ok && securityContext.IsAdmin && getSortKey(sortField) == "" {
+		paginatedEndpoints, filteredEndpointCount, err = handler.DataStore.Endpoint().EndpointsPaginated(start, limit, filters)
+		if err != nil {
+			return httperror.InternalServerError("Unable to retrieve environments from the database", err)
+		}
 
-	sortEnvironmentsByField(filteredEndpoints, endpointGroups, getSortKey(sortField), sortOrder == "desc")
+		if _, totalAvailableEndpoints, err = handler.DataStore.Endpoint().EndpointsPaginated(0, 1, dataservices.EndpointFilters{}); err != nil {
+			return httperror.InternalServerError("Unable to retrieve environments from the database", err)
+		}
+	} else {
+		endpoints, err := handler.DataStore.Endpoint().Endpoints()
+		if err != nil {
+			return httperror.InternalServerError("Unable to retrieve environments from the database", err)
+		}
+
+		filteredEndpoints := security.FilterEndpoints(endpoints, endpointGroups, securityContext)
+
+		filteredEndpoints, totalAvailableEndpoints, err = handler.filterEndpointsByQuery(filteredEndpoints, query, endpointGroups, edgeGroups, settings)
+		if err != nil {
+			return httperror.InternalServerError("Unable to filter endpoints", err)
+		}
+
+		sortEnvironmentsByField(filteredEndpoints, endpointGroups, getSortKey(sortField), sortOrder == "desc")
 
-	filteredEndpointCount := len(filteredEndpoints)
+		filteredEndpointCount = len(filteredEndpoints)
 
-	paginatedEndpoints := paginateEndpoints(filteredEndpoints, start, limit)
+		paginatedEndpoints = paginateEndpoints(filteredEndpoints, start, limit)
+	}
 
 	for idx := range paginatedEndpoints {
 		hideFields(&paginatedEndpoints[idx])
-		paginatedEndpoints[idx].ComposeSyntaxMaxVersion = handler.ComposeStackManager.ComposeSyntaxMaxVersion()
+		paginatedEndpoints[idx].ComposeSyntaxMaxVersion = handler.ComposeStackManager.ComposeSyntaxMaxVersion(&paginatedEndpoints[idx])
 		if paginatedEndpoints[idx].EdgeCheckinInterval == 0 {
 			paginatedEndpoints[idx].EdgeCheckinInterval = settings.EdgeAgentCheckinInterval
 		}
 		endpointutils.UpdateEdgeEndpointHeartbeat(&paginatedEndpoints[idx], settings)
 		if !query.excludeSnapshots {
-			err = handler.SnapshotService.FillSnapshotData(&paginatedEndpoints[idx])
+			err = handler.SnapshotService.FillSnapshotSummary(&paginatedEndpoints[idx])
 			if err != nil {
 				return httperror.InternalServerError("Unable to add snapshot data", err)
 			}
@@ -219,7 +219,6 @@ func (handler *Handler) userNamespaces(endpoint *portainer.Endpoint, user *porta
 }
 
 func hideRegistryFields(registry *portainer.Registry, hideAccesses bool) {
-	registry.Password = ""
 	registry.ManagementConfiguration = nil
 	if hideAccesses {
 		registry.RegistryAccesses = nil
@@ -0,0 +1,83 @@
+package endpoints
+
+import (
+	"cmp"
+	"errors"
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/scheduler"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+const defaultPreviewedRuns = 5
+
+type endpointSchedulePreviewResponse struct {
+	// IANA time zone the run times are computed in, empty for the time zone of the server
+	TimeZone string `json:"TimeZone" example:"America/New_York"`
+	// Next run times, with the offset of the time zone at that time
+	Runs []time.Time `json:"Runs"`
+}
+
+// @id EndpointSchedulePreview
+// @summary Preview the run times of a cron expression on an environment(endpoint)
+// @description Compute the next run times of a cron expression in the time zone of an environment(endpoint),
+// @description taking the daylight saving time changes into account.
+// @description **Access policy**: restricted
+// @tags endpoints
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param cronExpression query string true "Cron expression"
+// @param timeZone query string false "IANA time zone overriding the time zone of the environment, as set on an Edge job"
+// @param count query int false "Number of run times, defaults to 5, up to 100"
+// @success 200 {object} endpointSchedulePreviewResponse "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /endpoints/{id}/schedules/preview [get]
+func (handler *Handler) endpointSchedulePreview(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid environment identifier route variable", err)
+	}
+
+	cronExpression, err := request.RetrieveQueryParameter(r, "cronExpression", false)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: cronExpression", err)
+	}
+
+	timeZone, _ := request.RetrieveQueryParameter(r, "timeZone", true)
+
+	count, err := request.RetrieveNumericQueryParameter(r, "count", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: count", err)
+	} else if count < 0 || count > scheduler.MaxPreviewedRuns {
+		return httperror.BadRequest("Invalid query parameter: count", errors.New("count must be between 1 and 100"))
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	if err := handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint); err != nil {
+		return httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	timeZone = cmp.Or(timeZone, endpoint.TimeZone)
+
+	runs, err := scheduler.NextRuns(cronExpression, timeZone, time.Now(), cmp.Or(count, defaultPreviewedRuns))
+	if err != nil {
+		return httperror.BadRequest("Invalid cron expression or time zone", err)
+	}
+
+	return response.JSON(w, endpointSchedulePreviewResponse{TimeZone: timeZone, Runs: runs})
+}
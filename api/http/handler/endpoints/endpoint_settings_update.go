@@ -1,9 +1,13 @@
 package endpoints
 
 import (
+	"errors"
 	"net/http"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/stacks/stackutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -29,18 +33,52 @@ type endpointSettingsUpdatePayload struct {
 	// Whether host management features are enabled
 	EnableHostManagementFeatures *bool `json:"enableHostManagementFeatures" example:"true"`
 
+	// Docker operations denied to non-administrators
+	DeniedOperationsForRegularUsers []portainer.Authorization `json:"deniedOperationsForRegularUsers" example:"DockerImageBuild"`
+
+	// Host paths that non-administrators cannot bind mount, even when the bind mounts are allowed
+	BlockedBindMountPrefixes []string `json:"blockedBindMountPrefixes" example:"/etc,/var/run/docker.sock"`
+
 	EnableGPUManagement *bool `json:"enableGPUManagement" example:"false"`
 
+	// GPUs of the environment, they replace the GPUs detected by the snapshots. An empty list turns the detection back on
 	Gpus []portainer.Pair `json:"gpus"`
+
+	// Reset the security settings to the default security settings of the group of the environment
+	InheritGroupSecuritySettings bool `json:"inheritGroupSecuritySettings" example:"false"`
 }
 
 func (payload *endpointSettingsUpdatePayload) Validate(r *http.Request) error {
-	return nil
+	if payload.InheritGroupSecuritySettings && payload.hasSecuritySettings() {
+		return errors.New("the security settings cannot be both set and inherited from the group")
+	}
+
+	if err := stackutils.ValidateBlockedBindMountPrefixes(payload.BlockedBindMountPrefixes); err != nil {
+		return err
+	}
+
+	return authorization.ValidateDockerOperations(payload.DeniedOperationsForRegularUsers)
+}
+
+func (payload *endpointSettingsUpdatePayload) hasSecuritySettings() bool {
+	return payload.AllowBindMountsForRegularUsers != nil ||
+		payload.AllowPrivilegedModeForRegularUsers != nil ||
+		payload.AllowVolumeBrowserForRegularUsers != nil ||
+		payload.AllowHostNamespaceForRegularUsers != nil ||
+		payload.AllowDeviceMappingForRegularUsers != nil ||
+		payload.AllowStackManagementForRegularUsers != nil ||
+		payload.AllowContainerCapabilitiesForRegularUsers != nil ||
+		payload.AllowSysctlSettingForRegularUsers != nil ||
+		payload.EnableHostManagementFeatures != nil ||
+		payload.DeniedOperationsForRegularUsers != nil ||
+		payload.BlockedBindMountPrefixes != nil
 }
 
 // @id EndpointSettingsUpdate
 // @summary Update settings for an environment(endpoint)
 // @description Update settings for an environment(endpoint).
+// @description Setting the security settings overrides the default security settings of the group of the environment,
+// @description they cannot be changed when the group enforces its defaults.
 // @description **Access policy**: authenticated
 // @security ApiKeyAuth
 // @security jwt
@@ -51,6 +89,7 @@ func (payload *endpointSettingsUpdatePayload) Validate(r *http.Request) error {
 // @param body body endpointSettingsUpdatePayload true "Environment(Endpoint) details"
 // @success 200 {object} portainer.Endpoint "Success"
 // @failure 400 "Invalid request"
+// @failure 403 "The security settings are enforced by the group of the environment"
 // @failure 404 "Environment(Endpoint) not found"
 // @failure 500 "Server error"
 // @router /endpoints/{id}/settings [put]
@@ -73,6 +112,15 @@ func (handler *Handler) endpointSettingsUpdate(w http.ResponseWriter, r *http.Re
 		return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
 	}
 
+	endpointGroup, err := handler.DataStore.EndpointGroup().Read(endpoint.GroupID)
+	if err != nil && !handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.InternalServerError("Unable to find an environment group inside the database", err)
+	}
+
+	if payload.hasSecuritySettings() && endpointutils.SecuritySettingsLocked(endpointGroup) {
+		return httperror.Forbidden("The security settings of the environment are enforced by its group", errors.New("security settings locked by the environment group"))
+	}
+
 	securitySettings := endpoint.SecuritySettings
 
 	if payload.AllowBindMountsForRegularUsers != nil {
@@ -111,16 +159,34 @@ func (handler *Handler) endpointSettingsUpdate(w http.ResponseWriter, r *http.Re
 		securitySettings.EnableHostManagementFeatures = *payload.EnableHostManagementFeatures
 	}
 
+	if payload.DeniedOperationsForRegularUsers != nil {
+		securitySettings.DeniedOperationsForRegularUsers = payload.DeniedOperationsForRegularUsers
+	}
+
+	if payload.BlockedBindMountPrefixes != nil {
+		securitySettings.BlockedBindMountPrefixes = payload.BlockedBindMountPrefixes
+	}
+
 	if payload.EnableGPUManagement != nil {
 		endpoint.EnableGPUManagement = *payload.EnableGPUManagement
 	}
 
 	if payload.Gpus != nil {
 		endpoint.Gpus = payload.Gpus
+		endpoint.GpusDetected = false
 	}
 
 	endpoint.SecuritySettings = securitySettings
 
+	if payload.hasSecuritySettings() {
+		endpoint.SecuritySettingsOverridden = true
+	}
+
+	if payload.InheritGroupSecuritySettings {
+		endpoint.SecuritySettingsOverridden = false
+		endpointutils.ApplyGroupSecuritySettings(endpoint, endpointGroup)
+	}
+
 	err = handler.DataStore.Endpoint().UpdateEndpoint(portainer.EndpointID(endpointID), endpoint)
 	if err != nil {
 		return httperror.InternalServerError("Failed persisting environment in database", err)
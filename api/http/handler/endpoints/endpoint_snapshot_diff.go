@@ -0,0 +1,79 @@
+package endpoints
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/snapshot"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id EndpointSnapshotDiff
+// @summary Compare two snapshots of an environment(endpoint)
+// @description Compare two recorded snapshots of an environment(endpoint) and list the containers, images, volumes and services
+// @description created, removed or changed between them. For each timestamp, the latest snapshot taken at or before it is used.
+// @description **Access policy**: restricted
+// @tags endpoints
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param from query int true "Unix timestamp of the oldest snapshot to compare"
+// @param to query int false "Unix timestamp of the newest snapshot to compare, defaults to the latest snapshot"
+// @success 200 {object} snapshot.SnapshotDiff "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Environment(Endpoint) or snapshot not found"
+// @failure 500 "Server error"
+// @router /endpoints/{id}/snapshots/diff [get]
+func (handler *Handler) endpointSnapshotDiff(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid environment identifier route variable", err)
+	}
+
+	from, err := request.RetrieveNumericQueryParameter(r, "from", false)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: from", err)
+	}
+
+	to, err := request.RetrieveNumericQueryParameter(r, "to", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: to", err)
+	}
+
+	if to != 0 && to < from {
+		return httperror.BadRequest("Invalid query parameter: to", errors.New("to must not be before from"))
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	if err := handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint); err != nil {
+		return httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	records, err := handler.DataStore.SnapshotRecord().ReadAllByEndpointID(endpoint.ID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the snapshot history of the environment", err)
+	}
+
+	fromRecord := snapshot.LatestRecord(records, int64(from))
+	if fromRecord == nil {
+		return httperror.NotFound("Unable to find a snapshot of the environment taken at or before the from timestamp", errors.New("snapshot not found"))
+	}
+
+	toRecord := snapshot.LatestRecord(records, int64(to))
+	if toRecord == nil {
+		return httperror.NotFound("Unable to find a snapshot of the environment taken at or before the to timestamp", errors.New("snapshot not found"))
+	}
+
+	return response.JSON(w, snapshot.DiffSnapshots(fromRecord, toRecord))
+}
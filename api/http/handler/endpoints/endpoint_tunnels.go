@@ -0,0 +1,59 @@
+package endpoints
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id EndpointTunnelList
+// @summary List the tunnels of the Edge environments
+// @description List the open reverse tunnels of the Edge environments along with their traffic and their age.
+// @description **Access policy**: administrator
+// @tags endpoints
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.TunnelMetrics "Success"
+// @failure 500 "Server error"
+// @router /endpoints/tunnels [get]
+func (handler *Handler) endpointTunnelList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return response.JSON(w, handler.ReverseTunnelService.TunnelMetrics())
+}
+
+// @id EndpointTunnelClose
+// @summary Close the tunnel of an Edge environment
+// @description Close the reverse tunnel of an Edge environment and the connections going through it,
+// @description the agent opens a new tunnel the next time the environment is accessed.
+// @description **Access policy**: administrator
+// @tags endpoints
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Environment(Endpoint) identifier"
+// @success 204 "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Environment(Endpoint) not found or no tunnel is open"
+// @failure 500 "Server error"
+// @router /endpoints/{id}/tunnel [delete]
+func (handler *Handler) endpointTunnelClose(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid environment identifier route variable", err)
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	if err := handler.ReverseTunnelService.ForceClose(endpoint.ID); err != nil {
+		return httperror.NotFound("Unable to find an open tunnel for the environment", err)
+	}
+
+	return response.Empty(w)
+}
@@ -2,6 +2,7 @@ package endpoints
 
 import (
 	"cmp"
+	"errors"
 	"net/http"
 	"reflect"
 	"strconv"
@@ -9,9 +10,15 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/exec"
 	"github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/edge"
+	"github.com/portainer/portainer/api/internal/edge/cache"
 	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/templatevariables"
 	"github.com/portainer/portainer/api/pendingactions/handlers"
+	"github.com/portainer/portainer/api/scheduler"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -53,10 +60,36 @@ type endpointUpdatePayload struct {
 	EdgeCheckinInterval *int `example:"5"`
 	// Associated Kubernetes data
 	Kubernetes *portainer.KubernetesData
+	// Size in bytes of the disk holding the Docker data directory, used to project disk pressure.
+	// It overrides the size reported by the agent, 0 to use the reported size
+	DiskCapacity *int64 `example:"107374182400"`
+	// Values pre-filled for the custom template variables when deploying on the environment(endpoint)
+	CustomTemplateVariablePresets []portainer.CustomTemplateVariablePreset
+	// IANA time zone the cron expressions of the Edge jobs run in, empty for the time zone of the device
+	TimeZone *string `example:"America/New_York"`
+	// Version of docker compose deploying the Compose stacks, such as 1.29.2 for the legacy v1 syntax.
+	// An empty version uses the bundled docker compose
+	ComposeVersion *string `example:"2.24.6"`
 }
 
 func (payload *endpointUpdatePayload) Validate(r *http.Request) error {
-	return nil
+	if payload.DiskCapacity != nil && *payload.DiskCapacity < 0 {
+		return errors.New("Invalid disk capacity")
+	}
+
+	if payload.TimeZone != nil {
+		if err := scheduler.ValidateTimeZone(*payload.TimeZone); err != nil {
+			return err
+		}
+	}
+
+	if payload.ComposeVersion != nil && *payload.ComposeVersion != "" {
+		if err := exec.ValidateComposeVersion(*payload.ComposeVersion); err != nil {
+			return err
+		}
+	}
+
+	return templatevariables.ValidatePresets(payload.CustomTemplateVariablePresets)
 }
 
 // @id EndpointUpdate
@@ -116,32 +149,37 @@ func (handler *Handler) endpointUpdate(w http.ResponseWriter, r *http.Request) *
 		endpoint.Gpus = payload.Gpus
 	}
 
+	if payload.CustomTemplateVariablePresets != nil {
+		endpoint.CustomTemplateVariablePresets = payload.CustomTemplateVariablePresets
+	}
+
 	endpoint.PublicURL = *cmp.Or(payload.PublicURL, &endpoint.PublicURL)
 	endpoint.EdgeCheckinInterval = *cmp.Or(payload.EdgeCheckinInterval, &endpoint.EdgeCheckinInterval)
+	endpoint.DiskCapacity = *cmp.Or(payload.DiskCapacity, &endpoint.DiskCapacity)
+
+	rescheduleEdgeJobs := payload.TimeZone != nil && *payload.TimeZone != endpoint.TimeZone
+	endpoint.TimeZone = *cmp.Or(payload.TimeZone, &endpoint.TimeZone)
+
+	if payload.ComposeVersion != nil {
+		endpoint.ComposeVersion = *payload.ComposeVersion
+	}
 
 	updateRelations := false
 
 	if payload.GroupID != nil {
 		groupID := portainer.EndpointGroupID(*payload.GroupID)
 
-		updateRelations = updateRelations || groupID != endpoint.GroupID
-		endpoint.GroupID = groupID
-	}
-
-	if payload.TagIDs != nil {
-		if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
-			tagsChanged, err := updateEnvironmentTags(tx, payload.TagIDs, endpoint.TagIDs, endpoint.ID)
-			if err != nil {
-				return err
+		if groupID != endpoint.GroupID {
+			endpointGroup, err := handler.DataStore.EndpointGroup().Read(groupID)
+			if err != nil && !handler.DataStore.IsErrObjectNotFound(err) {
+				return httperror.InternalServerError("Unable to find an environment group inside the database", err)
 			}
 
-			endpoint.TagIDs = payload.TagIDs
-			updateRelations = updateRelations || tagsChanged
-
-			return nil
-		}); err != nil {
-			return httperror.InternalServerError("Unable to update environment tags", err)
+			endpointutils.ApplyGroupSecuritySettings(endpoint, endpointGroup)
 		}
+
+		updateRelations = updateRelations || groupID != endpoint.GroupID
+		endpoint.GroupID = groupID
 	}
 
 	updateAuthorizations := false
@@ -155,6 +193,12 @@ func (handler *Handler) endpointUpdate(w http.ResponseWriter, r *http.Request) *
 		endpoint.Kubernetes = *payload.Kubernetes
 	}
 
+	if err := authorization.ValidateAccessPolicyRoles(handler.DataStore, payload.UserAccessPolicies, payload.TeamAccessPolicies); errors.Is(err, authorization.ErrUnknownRole) {
+		return httperror.BadRequest("Invalid access policies", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the roles from the database", err)
+	}
+
 	if payload.UserAccessPolicies != nil && !reflect.DeepEqual(payload.UserAccessPolicies, endpoint.UserAccessPolicies) {
 		updateAuthorizations = true
 		endpoint.UserAccessPolicies = payload.UserAccessPolicies
@@ -260,16 +304,49 @@ func (handler *Handler) endpointUpdate(w http.ResponseWriter, r *http.Request) *
 		}
 	}
 
-	if err := handler.DataStore.Endpoint().UpdateEndpoint(endpoint.ID, endpoint); err != nil {
-		return httperror.InternalServerError("Unable to persist environment changes inside the database", err)
+	// The environment, its tags, its Edge relations and its Edge jobs are updated in a single transaction
+	// so that a failure does not leave them out of sync
+	previousTagIDs := endpoint.TagIDs
+	if payload.TagIDs != nil {
+		endpoint.TagIDs = payload.TagIDs
 	}
 
-	if updateRelations {
-		if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
-			return handler.updateEdgeRelations(tx, endpoint)
-		}); err != nil {
-			return httperror.InternalServerError("Unable to update environment relations", err)
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		updateRelations := updateRelations
+
+		if payload.TagIDs != nil {
+			tagsChanged, err := updateEnvironmentTags(tx, payload.TagIDs, previousTagIDs, endpoint.ID)
+			if err != nil {
+				return httperror.InternalServerError("Unable to update environment tags", err)
+			}
+
+			updateRelations = updateRelations || tagsChanged
 		}
+
+		if err := tx.Endpoint().UpdateEndpoint(endpoint.ID, endpoint); err != nil {
+			return httperror.InternalServerError("Unable to persist environment changes inside the database", err)
+		}
+
+		if updateRelations {
+			if err := handler.updateEdgeRelations(tx, endpoint); err != nil {
+				return httperror.InternalServerError("Unable to update environment relations", err)
+			}
+		}
+
+		if rescheduleEdgeJobs && endpointutils.IsEdgeEndpoint(endpoint) {
+			if err := rescheduleEndpointEdgeJobs(tx, endpoint.ID); err != nil {
+				return httperror.InternalServerError("Unable to reschedule the Edge jobs of the environment", err)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
 	}
 
 	if err := handler.SnapshotService.FillSnapshotData(endpoint); err != nil {
@@ -299,3 +376,42 @@ func shouldReloadTLSConfiguration(endpoint *portainer.Endpoint, payload *endpoin
 
 	return payload.TLSSkipClientVerify != nil && !*payload.TLSSkipClientVerify
 }
+
+// rescheduleEndpointEdgeJobs bumps the version of the Edge jobs of an environment so that its agent
+// schedules them again, in the new time zone of the environment
+func rescheduleEndpointEdgeJobs(tx dataservices.DataStoreTx, endpointID portainer.EndpointID) error {
+	edgeJobs, err := tx.EdgeJob().ReadAll()
+	if err != nil {
+		return err
+	}
+
+	for _, edgeJob := range edgeJobs {
+		if edgeJob.TimeZone != "" {
+			continue
+		}
+
+		_, endpointHasJob := edgeJob.Endpoints[endpointID]
+		for _, edgeGroupID := range edgeJob.EdgeGroups {
+			if endpointHasJob {
+				break
+			}
+
+			if endpointHasJob, _, err = edge.EndpointInEdgeGroup(tx, endpointID, edgeGroupID); err != nil {
+				return err
+			}
+		}
+
+		if !endpointHasJob {
+			continue
+		}
+
+		edgeJob.Version++
+		if err := tx.EdgeJob().Update(edgeJob.ID, &edgeJob); err != nil {
+			return err
+		}
+	}
+
+	cache.Del(endpointID)
+
+	return nil
+}
@@ -120,6 +120,29 @@ func parseQuery(r *http.Request) (EnvironmentsQuery, error) {
 	}, nil
 }
 
+// databaseFilters returns the filters to apply when querying the database, it fails when the query uses
+// criteria that are computed from other objects than the environments(endpoints) themselves
+func (query EnvironmentsQuery) databaseFilters() (dataservices.EndpointFilters, bool) {
+	if query.search != "" ||
+		query.name != "" ||
+		len(query.tagIds) > 0 ||
+		len(query.endpointIds) > 0 ||
+		len(query.excludeIds) > 0 ||
+		len(query.status) > 0 ||
+		len(query.agentVersions) > 0 ||
+		query.edgeAsync != nil ||
+		query.edgeCheckInPassedSeconds > 0 ||
+		query.edgeStackId != 0 {
+		return dataservices.EndpointFilters{}, false
+	}
+
+	return dataservices.EndpointFilters{
+		GroupIDs:            query.groupIds,
+		Types:               query.types,
+		EdgeDeviceUntrusted: BoolAddr(query.edgeDeviceUntrusted),
+	}, true
+}
+
 func (handler *Handler) filterEndpointsByQuery(
 	filteredEndpoints []portainer.Endpoint,
 	query EnvironmentsQuery,
@@ -9,6 +9,8 @@ import (
 	"github.com/portainer/portainer/api/http/proxy"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/dockerhub"
+	"github.com/portainer/portainer/api/internal/insights"
 	"github.com/portainer/portainer/api/kubernetes/cli"
 	"github.com/portainer/portainer/api/pendingactions"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
@@ -39,6 +41,8 @@ type Handler struct {
 	BindAddress           string
 	BindAddressHTTPS      string
 	PendingActionsService *pendingactions.PendingActionsService
+	InsightsService       *insights.Service
+	DockerHubService      *dockerhub.Service
 }
 
 // NewHandler creates a handler to manage environment(endpoint) operations.
@@ -61,6 +65,10 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 	h.Handle("/endpoints/agent_versions",
 		bouncer.RestrictedAccess(httperror.LoggerHandler(h.agentVersions))).Methods(http.MethodGet)
 	h.Handle("/endpoints/relations", bouncer.RestrictedAccess(httperror.LoggerHandler(h.updateRelations))).Methods(http.MethodPut)
+	h.Handle("/endpoints/bulk",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointBulkUpdate))).Methods(http.MethodPatch)
+	h.Handle("/endpoints/tunnels",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointTunnelList))).Methods(http.MethodGet)
 
 	h.Handle("/endpoints/{id}",
 		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointInspect))).Methods(http.MethodGet)
@@ -70,8 +78,20 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointDelete))).Methods(http.MethodDelete)
 	h.Handle("/endpoints",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointDeleteBatch))).Methods(http.MethodDelete)
+	h.Handle("/endpoints/{id}/dockerhub/ratelimit",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointDockerHubRateLimit))).Methods(http.MethodGet)
 	h.Handle("/endpoints/{id}/dockerhub/{registryId}",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.endpointDockerhubStatus))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/insights",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointInsights))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/events",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointEvents))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/snapshots/diff",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointSnapshotDiff))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/schedules/preview",
+		bouncer.RestrictedAccess(httperror.LoggerHandler(h.endpointSchedulePreview))).Methods(http.MethodGet)
+	h.Handle("/endpoints/{id}/tunnel",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointTunnelClose))).Methods(http.MethodDelete)
 	h.Handle("/endpoints/{id}/snapshot",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.endpointSnapshot))).Methods(http.MethodPost)
 	h.Handle("/endpoints/{id}/registries",
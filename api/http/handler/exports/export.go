@@ -0,0 +1,95 @@
+package exports
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	formatCSV  = "csv"
+	formatJSON = "json"
+)
+
+// @id ExportInventory
+// @summary Export an inventory
+// @description Export the environments, the containers of all the Docker environments (as seen by their last snapshot), the stacks,
+// @description the users or the registries of the instance as CSV or JSON. Credentials are never exported.
+// @description **Access policy**: administrator
+// @tags exports
+// @security ApiKeyAuth
+// @security jwt
+// @produce text/csv
+// @produce json
+// @param inventory path string true "Inventory to export" Enums(environments, containers, stacks, users, registries)
+// @param format query string false "Format of the export, defaults to csv" Enums(csv, json)
+// @param columns query string false "Comma separated list of the columns to export, in order. Defaults to all the columns"
+// @success 200 "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Inventory not found"
+// @failure 500 "Server error"
+// @router /exports/{inventory} [get]
+func (handler *Handler) exportInventory(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	inventory, err := request.RetrieveRouteVariableValue(r, "inventory")
+	if err != nil {
+		return httperror.BadRequest("Invalid inventory route variable", err)
+	}
+
+	buildTable, ok := inventories[inventory]
+	if !ok {
+		return httperror.NotFound("Unable to find the inventory", fmt.Errorf("unknown inventory %q", inventory))
+	}
+
+	format, _ := request.RetrieveQueryParameter(r, "format", true)
+	if format == "" {
+		format = formatCSV
+	}
+
+	if format != formatCSV && format != formatJSON {
+		return httperror.BadRequest("Invalid query parameter: format", errors.New("format must be csv or json"))
+	}
+
+	var columns []string
+	if value, _ := request.RetrieveQueryParameter(r, "columns", true); value != "" {
+		for _, column := range strings.Split(value, ",") {
+			columns = append(columns, strings.TrimSpace(column))
+		}
+	}
+
+	var t table
+	if err := handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		t, err = buildTable(tx)
+
+		return err
+	}); err != nil {
+		return httperror.InternalServerError("Unable to retrieve the inventory from the database", err)
+	}
+
+	t, err = t.project(columns)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: columns", err)
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	write := t.writeCSV
+	if format == formatJSON {
+		contentType = "application/json"
+		write = t.writeJSON
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.%s", inventory, format))
+
+	if err := write(w); err != nil {
+		log.Warn().Err(err).Str("inventory", inventory).Msg("unable to write the inventory export")
+	}
+
+	return nil
+}
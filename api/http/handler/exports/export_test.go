@@ -0,0 +1,84 @@
+package exports
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableProject(t *testing.T) {
+	users := table{
+		columns: []string{"Id", "Username", "Role"},
+		rows:    [][]any{{1, "admin", "administrator"}},
+	}
+
+	projected, err := users.project([]string{"Username", "Id"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Username", "Id"}, projected.columns)
+	assert.Equal(t, [][]any{{"admin", 1}}, projected.rows)
+
+	_, err = users.project([]string{"Password"})
+	assert.Error(t, err)
+}
+
+func TestFormatCSVValue(t *testing.T) {
+	assert.Equal(t, "web", formatCSVValue("web"))
+	assert.Equal(t, "'=HYPERLINK(\"x\")", formatCSVValue("=HYPERLINK(\"x\")"))
+	assert.Equal(t, "dev;ops", formatCSVValue([]string{"dev", "ops"}))
+	assert.Equal(t, "-1", formatCSVValue(-1))
+	assert.Equal(t, "true", formatCSVValue(true))
+}
+
+func TestExportInventory(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	admin := &portainer.User{Username: "admin", Role: portainer.AdministratorRole}
+	bob := &portainer.User{Username: "bob", Role: portainer.StandardUserRole}
+	require.NoError(t, store.User().Create(admin))
+	require.NoError(t, store.User().Create(bob))
+
+	team := &portainer.Team{Name: "ops"}
+	require.NoError(t, store.Team().Create(team))
+	require.NoError(t, store.TeamMembership().Create(&portainer.TeamMembership{UserID: bob.ID, TeamID: team.ID}))
+
+	handler := NewHandler(testhelpers.NewTestRequestBouncer())
+	handler.DataStore = store
+
+	export := func(url string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req = req.WithContext(security.StoreTokenData(req, &portainer.TokenData{ID: admin.ID, Role: portainer.AdministratorRole}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	rr := export("/exports/users?columns=Username,Teams")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "Username,Teams\nadmin,\nbob,ops\n", rr.Body.String())
+
+	rr = export("/exports/users?format=json&columns=Username,Role")
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var users []map[string]any
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&users))
+	assert.Equal(t, []map[string]any{
+		{"Username": "admin", "Role": "administrator"},
+		{"Username": "bob", "Role": "user"},
+	}, users)
+
+	assert.Equal(t, http.StatusNotFound, export("/exports/secrets").Code)
+	assert.Equal(t, http.StatusBadRequest, export("/exports/users?format=xml").Code)
+	assert.Equal(t, http.StatusBadRequest, export("/exports/users?columns=Password").Code)
+}
@@ -0,0 +1,278 @@
+package exports
+
+import (
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/docker/consts"
+)
+
+// inventories lists the builders of the exportable inventories, by name
+var inventories = map[string]func(tx dataservices.DataStoreTx) (table, error){
+	"environments": environmentsTable,
+	"containers":   containersTable,
+	"stacks":       stacksTable,
+	"users":        usersTable,
+	"registries":   registriesTable,
+}
+
+var endpointTypeNames = map[portainer.EndpointType]string{
+	portainer.DockerEnvironment:                "docker",
+	portainer.AgentOnDockerEnvironment:         "agent-docker",
+	portainer.AzureEnvironment:                 "azure",
+	portainer.EdgeAgentOnDockerEnvironment:     "edge-agent-docker",
+	portainer.KubernetesLocalEnvironment:       "kubernetes-local",
+	portainer.AgentOnKubernetesEnvironment:     "agent-kubernetes",
+	portainer.EdgeAgentOnKubernetesEnvironment: "edge-agent-kubernetes",
+}
+
+var stackTypeNames = map[portainer.StackType]string{
+	portainer.DockerSwarmStack:   "swarm",
+	portainer.DockerComposeStack: "compose",
+	portainer.KubernetesStack:    "kubernetes",
+}
+
+var registryTypeNames = map[portainer.RegistryType]string{
+	portainer.QuayRegistry:      "quay",
+	portainer.AzureRegistry:     "azure",
+	portainer.CustomRegistry:    "custom",
+	portainer.GitlabRegistry:    "gitlab",
+	portainer.ProGetRegistry:    "proget",
+	portainer.DockerHubRegistry: "dockerhub",
+	portainer.EcrRegistry:       "ecr",
+}
+
+func environmentsTable(tx dataservices.DataStoreTx) (table, error) {
+	endpoints, err := tx.Endpoint().Endpoints()
+	if err != nil {
+		return table{}, err
+	}
+
+	groupNames, err := readEndpointGroupNames(tx)
+	if err != nil {
+		return table{}, err
+	}
+
+	tags, err := tx.Tag().ReadAll()
+	if err != nil {
+		return table{}, err
+	}
+
+	tagNames := make(map[portainer.TagID]string, len(tags))
+	for _, tag := range tags {
+		tagNames[tag.ID] = tag.Name
+	}
+
+	t := table{columns: []string{"Id", "Name", "Type", "URL", "PublicURL", "GroupId", "Group", "Tags", "Status", "EdgeId", "LastCheckInDate"}}
+	for _, endpoint := range endpoints {
+		endpointTags := make([]string, 0, len(endpoint.TagIDs))
+		for _, tagID := range endpoint.TagIDs {
+			endpointTags = append(endpointTags, tagNames[tagID])
+		}
+
+		status := "up"
+		if endpoint.Status == portainer.EndpointStatusDown {
+			status = "down"
+		}
+
+		t.rows = append(t.rows, []any{
+			int(endpoint.ID),
+			endpoint.Name,
+			endpointTypeNames[endpoint.Type],
+			endpoint.URL,
+			endpoint.PublicURL,
+			int(endpoint.GroupID),
+			groupNames[endpoint.GroupID],
+			endpointTags,
+			status,
+			endpoint.EdgeID,
+			endpoint.LastCheckInDate,
+		})
+	}
+
+	return t, nil
+}
+
+// containersTable lists the containers of all the Docker environments, as seen by their last snapshot
+func containersTable(tx dataservices.DataStoreTx) (table, error) {
+	endpointNames, err := readEndpointNames(tx)
+	if err != nil {
+		return table{}, err
+	}
+
+	snapshots, err := tx.Snapshot().ReadAll()
+	if err != nil {
+		return table{}, err
+	}
+
+	t := table{columns: []string{"EnvironmentId", "Environment", "Id", "Name", "Image", "State", "Status", "Created", "Stack", "SnapshotTime"}}
+	for _, snapshot := range snapshots {
+		if snapshot.Docker == nil {
+			continue
+		}
+
+		for _, container := range snapshot.Docker.SnapshotRaw.Containers {
+			name := ""
+			if len(container.Names) > 0 {
+				name = strings.TrimPrefix(container.Names[0], "/")
+			}
+
+			stack := container.Labels[consts.ComposeStackNameLabel]
+			if stack == "" {
+				stack = container.Labels[consts.SwarmStackNameLabel]
+			}
+
+			t.rows = append(t.rows, []any{
+				int(snapshot.EndpointID),
+				endpointNames[snapshot.EndpointID],
+				container.ID,
+				name,
+				container.Image,
+				container.State,
+				container.Status,
+				container.Created,
+				stack,
+				snapshot.Docker.Time,
+			})
+		}
+	}
+
+	return t, nil
+}
+
+func stacksTable(tx dataservices.DataStoreTx) (table, error) {
+	stacks, err := tx.Stack().ReadAll()
+	if err != nil {
+		return table{}, err
+	}
+
+	endpointNames, err := readEndpointNames(tx)
+	if err != nil {
+		return table{}, err
+	}
+
+	t := table{columns: []string{"Id", "Name", "Type", "EnvironmentId", "Environment", "Status", "GitURL", "CreatedBy", "CreationDate", "UpdatedBy", "UpdateDate"}}
+	for _, stack := range stacks {
+		status := "active"
+		if stack.Status == portainer.StackStatusInactive {
+			status = "inactive"
+		}
+
+		gitURL := ""
+		if stack.GitConfig != nil {
+			gitURL = stack.GitConfig.URL
+		}
+
+		t.rows = append(t.rows, []any{
+			int(stack.ID),
+			stack.Name,
+			stackTypeNames[stack.Type],
+			int(stack.EndpointID),
+			endpointNames[stack.EndpointID],
+			status,
+			gitURL,
+			stack.CreatedBy,
+			stack.CreationDate,
+			stack.UpdatedBy,
+			stack.UpdateDate,
+		})
+	}
+
+	return t, nil
+}
+
+func usersTable(tx dataservices.DataStoreTx) (table, error) {
+	users, err := tx.User().ReadAll()
+	if err != nil {
+		return table{}, err
+	}
+
+	teams, err := tx.Team().ReadAll()
+	if err != nil {
+		return table{}, err
+	}
+
+	teamNames := make(map[portainer.TeamID]string, len(teams))
+	for _, team := range teams {
+		teamNames[team.ID] = team.Name
+	}
+
+	memberships, err := tx.TeamMembership().ReadAll()
+	if err != nil {
+		return table{}, err
+	}
+
+	userTeams := make(map[portainer.UserID][]string)
+	for _, membership := range memberships {
+		userTeams[membership.UserID] = append(userTeams[membership.UserID], teamNames[membership.TeamID])
+	}
+
+	t := table{columns: []string{"Id", "Username", "Role", "Teams"}}
+	for _, user := range users {
+		role := "user"
+		if user.Role == portainer.AdministratorRole {
+			role = "administrator"
+		}
+
+		names := userTeams[user.ID]
+		if names == nil {
+			names = []string{}
+		}
+
+		t.rows = append(t.rows, []any{int(user.ID), user.Username, role, names})
+	}
+
+	return t, nil
+}
+
+// registriesTable lists the registries, their credentials are never exported
+func registriesTable(tx dataservices.DataStoreTx) (table, error) {
+	registries, err := tx.Registry().ReadAll()
+	if err != nil {
+		return table{}, err
+	}
+
+	t := table{columns: []string{"Id", "Name", "Type", "URL", "BaseURL", "Authentication", "Username"}}
+	for _, registry := range registries {
+		t.rows = append(t.rows, []any{
+			int(registry.ID),
+			registry.Name,
+			registryTypeNames[registry.Type],
+			registry.URL,
+			registry.BaseURL,
+			registry.Authentication,
+			registry.Username,
+		})
+	}
+
+	return t, nil
+}
+
+func readEndpointNames(tx dataservices.DataStoreTx) (map[portainer.EndpointID]string, error) {
+	endpoints, err := tx.Endpoint().Endpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[portainer.EndpointID]string, len(endpoints))
+	for _, endpoint := range endpoints {
+		names[endpoint.ID] = endpoint.Name
+	}
+
+	return names, nil
+}
+
+func readEndpointGroupNames(tx dataservices.DataStoreTx) (map[portainer.EndpointGroupID]string, error) {
+	groups, err := tx.EndpointGroup().ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[portainer.EndpointGroupID]string, len(groups))
+	for _, group := range groups {
+		names[group.ID] = group.Name
+	}
+
+	return names, nil
+}
@@ -0,0 +1,112 @@
+package exports
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// table is an inventory ready to be exported, each row holds one value per column
+type table struct {
+	columns []string
+	rows    [][]any
+}
+
+// project returns a table holding only the selected columns, in the order of the selection.
+// All the columns are kept when the selection is empty
+func (t table) project(selection []string) (table, error) {
+	if len(selection) == 0 {
+		return t, nil
+	}
+
+	indexes := make([]int, 0, len(selection))
+	for _, column := range selection {
+		index := slices.Index(t.columns, column)
+		if index == -1 {
+			return table{}, fmt.Errorf("unknown column %q, available columns are: %s", column, strings.Join(t.columns, ","))
+		}
+
+		indexes = append(indexes, index)
+	}
+
+	projected := table{columns: selection, rows: make([][]any, 0, len(t.rows))}
+	for _, row := range t.rows {
+		values := make([]any, 0, len(indexes))
+		for _, index := range indexes {
+			values = append(values, row[index])
+		}
+
+		projected.rows = append(projected.rows, values)
+	}
+
+	return projected, nil
+}
+
+func (t table) writeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(t.columns); err != nil {
+		return err
+	}
+
+	for _, row := range t.rows {
+		record := make([]string, 0, len(row))
+		for _, value := range row {
+			record = append(record, formatCSVValue(value))
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// writeJSON writes the table as an array of objects keyed by column
+func (t table) writeJSON(w io.Writer) error {
+	objects := make([]map[string]any, 0, len(t.rows))
+	for _, row := range t.rows {
+		object := make(map[string]any, len(t.columns))
+		for i, column := range t.columns {
+			object[column] = row[i]
+		}
+
+		objects = append(objects, object)
+	}
+
+	return json.NewEncoder(w).Encode(objects)
+}
+
+// formatCSVValue formats a value for a CSV cell. Text starting like a formula is escaped so that spreadsheets
+// opening the export do not evaluate it
+func formatCSVValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return escapeFormula(v)
+	case []string:
+		return escapeFormula(strings.Join(v, ";"))
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
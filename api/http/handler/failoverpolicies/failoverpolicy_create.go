@@ -0,0 +1,90 @@
+package failoverpolicies
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type failoverPolicyCreatePayload struct {
+	Name string `example:"web-failover"`
+	// Environment(Endpoint) watched by the policy
+	PrimaryEndpointID portainer.EndpointID `example:"1"`
+	// Environment(Endpoint) where the stacks are redeployed
+	BackupEndpointID portainer.EndpointID `example:"2"`
+	// Docker compose stacks of the primary environment redeployed on failover
+	StackIDs []portainer.StackID
+	// Number of seconds the primary environment must stay unreachable before failing over
+	DownThreshold int `example:"300"`
+	// Whether the primary environment is watched
+	Enabled bool `example:"true"`
+}
+
+func (payload *failoverPolicyCreatePayload) Validate(r *http.Request) error {
+	if payload.Name == "" {
+		return errors.New("invalid failover policy name")
+	}
+
+	if payload.PrimaryEndpointID == 0 || payload.BackupEndpointID == 0 {
+		return errors.New("invalid environment identifiers, the primary and backup environments are required")
+	}
+
+	if len(payload.StackIDs) == 0 {
+		return errors.New("invalid stack identifiers, at least one stack is required")
+	}
+
+	if payload.DownThreshold <= 0 {
+		return errors.New("invalid down threshold, must be a positive number of seconds")
+	}
+
+	return nil
+}
+
+// @id FailoverPolicyCreate
+// @summary Create a failover policy
+// @description Create a policy watching a standalone Docker environment. When the environment stays unreachable
+// @description for longer than the down threshold, the selected stacks are redeployed onto the backup environment.
+// @description **Access policy**: administrator
+// @tags failover_policies
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body failoverPolicyCreatePayload true "Failover policy details"
+// @success 200 {object} portainer.FailoverPolicy
+// @failure 400
+// @failure 500
+// @router /failover_policies [post]
+func (handler *Handler) failoverPolicyCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload failoverPolicyCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	policy := &portainer.FailoverPolicy{
+		Name:              payload.Name,
+		PrimaryEndpointID: payload.PrimaryEndpointID,
+		BackupEndpointID:  payload.BackupEndpointID,
+		StackIDs:          payload.StackIDs,
+		DownThreshold:     payload.DownThreshold,
+		Enabled:           payload.Enabled,
+		Status:            portainer.FailoverStatusWatching,
+		Created:           time.Now().Unix(),
+		Events:            []portainer.FailoverEvent{},
+	}
+
+	err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := validatePolicy(tx, policy); err != nil {
+			return err
+		}
+
+		return tx.FailoverPolicy().Create(policy)
+	})
+
+	return txResponse(w, policy, err)
+}
@@ -0,0 +1,44 @@
+package failoverpolicies
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id FailoverPolicyDelete
+// @summary Delete a failover policy
+// @description The stacks already redeployed onto the backup environment are left untouched.
+// @description **Access policy**: administrator
+// @tags failover_policies
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Failover policy identifier"
+// @success 204
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /failover_policies/{id} [delete]
+func (handler *Handler) failoverPolicyDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	policyID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid failover policy identifier route variable", err)
+	}
+
+	id := portainer.FailoverPolicyID(policyID)
+
+	if _, err := handler.DataStore.FailoverPolicy().Read(id); handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a failover policy with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a failover policy with the specified identifier inside the database", err)
+	}
+
+	if err := handler.DataStore.FailoverPolicy().Delete(id); err != nil {
+		return httperror.InternalServerError("Unable to remove the failover policy from the database", err)
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,56 @@
+package failoverpolicies
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/failover"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id FailoverPolicyFailover
+// @summary Fail over
+// @description Redeploy the stacks of a failover policy onto its backup environment without waiting for the primary environment to go down.
+// @description The outcome is recorded as a failover event of the policy.
+// @description **Access policy**: administrator
+// @tags failover_policies
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Failover policy identifier"
+// @success 200 {object} portainer.FailoverPolicy
+// @failure 400
+// @failure 404
+// @failure 409 "The policy already failed over"
+// @failure 500
+// @router /failover_policies/{id}/failover [post]
+func (handler *Handler) failoverPolicyFailover(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	policyID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid failover policy identifier route variable", err)
+	}
+
+	id := portainer.FailoverPolicyID(policyID)
+
+	if _, err := handler.DataStore.FailoverPolicy().Read(id); handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a failover policy with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a failover policy with the specified identifier inside the database", err)
+	}
+
+	if err := handler.FailoverService.Failover(id); errors.Is(err, failover.ErrFailedOver) {
+		return httperror.Conflict("The failover policy already failed over", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to fail over", err)
+	}
+
+	policy, err := handler.DataStore.FailoverPolicy().Read(id)
+	if err != nil {
+		return httperror.InternalServerError("Unable to find a failover policy with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, policy)
+}
@@ -0,0 +1,40 @@
+package failoverpolicies
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id FailoverPolicyInspect
+// @summary Inspect a failover policy
+// @description Retrieve a failover policy along with its failover events.
+// @description **Access policy**: administrator
+// @tags failover_policies
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Failover policy identifier"
+// @success 200 {object} portainer.FailoverPolicy
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /failover_policies/{id} [get]
+func (handler *Handler) failoverPolicyInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	policyID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid failover policy identifier route variable", err)
+	}
+
+	policy, err := handler.DataStore.FailoverPolicy().Read(portainer.FailoverPolicyID(policyID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a failover policy with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a failover policy with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, policy)
+}
@@ -0,0 +1,27 @@
+package failoverpolicies
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id FailoverPolicyList
+// @summary List the failover policies
+// @description **Access policy**: administrator
+// @tags failover_policies
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.FailoverPolicy
+// @failure 500
+// @router /failover_policies [get]
+func (handler *Handler) failoverPolicyList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	policies, err := handler.DataStore.FailoverPolicy().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve failover policies from the database", err)
+	}
+
+	return response.JSON(w, policies)
+}
@@ -0,0 +1,49 @@
+package failoverpolicies
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+// @id FailoverPolicyReset
+// @summary Reset a failover policy
+// @description Resume the watch of the primary environment of a policy that failed over.
+// @description The stacks are not moved back, only the stacks deployed on the primary environment fail over again.
+// @description **Access policy**: administrator
+// @tags failover_policies
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Failover policy identifier"
+// @success 200 {object} portainer.FailoverPolicy
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /failover_policies/{id}/reset [post]
+func (handler *Handler) failoverPolicyReset(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	policyID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid failover policy identifier route variable", err)
+	}
+
+	var policy *portainer.FailoverPolicy
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		policy, err = tx.FailoverPolicy().Read(portainer.FailoverPolicyID(policyID))
+		if tx.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a failover policy with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a failover policy with the specified identifier inside the database", err)
+		}
+
+		policy.Status = portainer.FailoverStatusWatching
+		policy.DownSince = 0
+
+		return tx.FailoverPolicy().Update(policy.ID, policy)
+	})
+
+	return txResponse(w, policy, err)
+}
@@ -0,0 +1,124 @@
+package failoverpolicies
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type failoverPolicyUpdatePayload struct {
+	Name *string `example:"web-failover"`
+	// Environment(Endpoint) where the stacks are redeployed
+	BackupEndpointID *portainer.EndpointID `example:"2"`
+	// Docker compose stacks of the primary environment redeployed on failover
+	StackIDs []portainer.StackID
+	// Number of seconds the primary environment must stay unreachable before failing over
+	DownThreshold *int `example:"300"`
+	// Whether the primary environment is watched
+	Enabled *bool `example:"true"`
+}
+
+func (payload *failoverPolicyUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("invalid failover policy name")
+	}
+
+	if payload.BackupEndpointID != nil && *payload.BackupEndpointID == 0 {
+		return errors.New("invalid backup environment identifier")
+	}
+
+	if payload.StackIDs != nil && len(payload.StackIDs) == 0 {
+		return errors.New("invalid stack identifiers, at least one stack is required")
+	}
+
+	if payload.DownThreshold != nil && *payload.DownThreshold <= 0 {
+		return errors.New("invalid down threshold, must be a positive number of seconds")
+	}
+
+	return nil
+}
+
+// @id FailoverPolicyUpdate
+// @summary Update a failover policy
+// @description The primary environment of a policy cannot be changed.
+// @description **Access policy**: administrator
+// @tags failover_policies
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Failover policy identifier"
+// @param body body failoverPolicyUpdatePayload true "Failover policy details"
+// @success 200 {object} portainer.FailoverPolicy
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /failover_policies/{id} [put]
+func (handler *Handler) failoverPolicyUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	policyID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid failover policy identifier route variable", err)
+	}
+
+	var payload failoverPolicyUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var policy *portainer.FailoverPolicy
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		policy, err = updateFailoverPolicy(tx, portainer.FailoverPolicyID(policyID), payload)
+		return err
+	})
+
+	return txResponse(w, policy, err)
+}
+
+func updateFailoverPolicy(tx dataservices.DataStoreTx, policyID portainer.FailoverPolicyID, payload failoverPolicyUpdatePayload) (*portainer.FailoverPolicy, error) {
+	policy, err := tx.FailoverPolicy().Read(policyID)
+	if tx.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find a failover policy with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find a failover policy with the specified identifier inside the database", err)
+	}
+
+	if payload.Name != nil {
+		policy.Name = *payload.Name
+	}
+
+	if payload.BackupEndpointID != nil {
+		policy.BackupEndpointID = *payload.BackupEndpointID
+	}
+
+	if payload.StackIDs != nil {
+		policy.StackIDs = payload.StackIDs
+	}
+
+	if payload.DownThreshold != nil {
+		policy.DownThreshold = *payload.DownThreshold
+	}
+
+	if payload.Enabled != nil {
+		policy.Enabled = *payload.Enabled
+		if !policy.Enabled {
+			policy.DownSince = 0
+		}
+	}
+
+	// The stacks of a policy that failed over run on the backup environment until the policy is reset
+	if policy.Status == portainer.FailoverStatusWatching && (payload.BackupEndpointID != nil || payload.StackIDs != nil) {
+		if err := validatePolicy(tx, policy); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.FailoverPolicy().Update(policy.ID, policy); err != nil {
+		return nil, httperror.InternalServerError("Unable to persist failover policy changes inside the database", err)
+	}
+
+	return policy, nil
+}
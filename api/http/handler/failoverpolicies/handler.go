@@ -0,0 +1,105 @@
+package failoverpolicies
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/failover"
+	"github.com/portainer/portainer/api/internal/snapshot"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is the HTTP handler used to handle failover policy operations.
+type Handler struct {
+	*mux.Router
+	DataStore       dataservices.DataStore
+	FailoverService *failover.Service
+}
+
+// NewHandler creates a handler to manage failover policy operations.
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+
+	h.Handle("/failover_policies",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.failoverPolicyList))).Methods(http.MethodGet)
+	h.Handle("/failover_policies",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.failoverPolicyCreate))).Methods(http.MethodPost)
+	h.Handle("/failover_policies/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.failoverPolicyInspect))).Methods(http.MethodGet)
+	h.Handle("/failover_policies/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.failoverPolicyUpdate))).Methods(http.MethodPut)
+	h.Handle("/failover_policies/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.failoverPolicyDelete))).Methods(http.MethodDelete)
+	h.Handle("/failover_policies/{id}/failover",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.failoverPolicyFailover))).Methods(http.MethodPost)
+	h.Handle("/failover_policies/{id}/reset",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.failoverPolicyReset))).Methods(http.MethodPost)
+
+	return h
+}
+
+// validatePolicy ensures that both environments are distinct standalone Docker environments
+// and that the stacks are Docker compose stacks of the primary environment
+func validatePolicy(tx dataservices.DataStoreTx, policy *portainer.FailoverPolicy) error {
+	if policy.PrimaryEndpointID == policy.BackupEndpointID {
+		return httperror.BadRequest("Invalid environments", errors.New("the primary and backup environments must be different"))
+	}
+
+	for _, endpointID := range []portainer.EndpointID{policy.PrimaryEndpointID, policy.BackupEndpointID} {
+		endpoint, err := tx.Endpoint().Endpoint(endpointID)
+		if tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find an environment with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+		}
+
+		if !endpointutils.IsDockerEndpoint(endpoint) || endpointutils.IsEdgeEndpoint(endpoint) {
+			return httperror.BadRequest("Invalid environments", errors.New("failover is only supported between Docker environments"))
+		}
+
+		if err := snapshot.FillSnapshotData(tx, endpoint); err != nil {
+			return httperror.InternalServerError("Unable to retrieve the snapshot of the environment", err)
+		}
+
+		if len(endpoint.Snapshots) > 0 && endpoint.Snapshots[0].Swarm {
+			return httperror.BadRequest("Invalid environments", errors.New("failover is not supported for Swarm environments"))
+		}
+	}
+
+	for _, stackID := range policy.StackIDs {
+		stack, err := tx.Stack().Read(stackID)
+		if tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find a stack with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a stack with the specified identifier inside the database", err)
+		}
+
+		if stack.Type != portainer.DockerComposeStack || stack.EndpointID != policy.PrimaryEndpointID {
+			return httperror.BadRequest("Invalid stacks", errors.New("only the Docker compose stacks of the primary environment can fail over"))
+		}
+	}
+
+	return nil
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
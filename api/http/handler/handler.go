@@ -5,28 +5,44 @@ import (
 	"strings"
 
 	"github.com/portainer/portainer/api/http/handler/auth"
+	"github.com/portainer/portainer/api/http/handler/azure"
 	"github.com/portainer/portainer/api/http/handler/backup"
+	"github.com/portainer/portainer/api/http/handler/cloudprovisionings"
 	"github.com/portainer/portainer/api/http/handler/customtemplates"
+	"github.com/portainer/portainer/api/http/handler/deployments"
 	"github.com/portainer/portainer/api/http/handler/docker"
+	"github.com/portainer/portainer/api/http/handler/edgebundle"
 	"github.com/portainer/portainer/api/http/handler/edgegroups"
 	"github.com/portainer/portainer/api/http/handler/edgejobs"
+	"github.com/portainer/portainer/api/http/handler/edgejointokens"
 	"github.com/portainer/portainer/api/http/handler/edgestacks"
 	"github.com/portainer/portainer/api/http/handler/edgetemplates"
+	"github.com/portainer/portainer/api/http/handler/edgeupdateschedules"
 	"github.com/portainer/portainer/api/http/handler/endpointedge"
 	"github.com/portainer/portainer/api/http/handler/endpointgroups"
 	"github.com/portainer/portainer/api/http/handler/endpointproxy"
 	"github.com/portainer/portainer/api/http/handler/endpoints"
+	"github.com/portainer/portainer/api/http/handler/exports"
+	"github.com/portainer/portainer/api/http/handler/failoverpolicies"
 	"github.com/portainer/portainer/api/http/handler/file"
 	"github.com/portainer/portainer/api/http/handler/gitops"
 	"github.com/portainer/portainer/api/http/handler/helm"
 	"github.com/portainer/portainer/api/http/handler/hostmanagement/openamt"
+	"github.com/portainer/portainer/api/http/handler/imageupdatejobs"
+	"github.com/portainer/portainer/api/http/handler/inactiveresources"
 	"github.com/portainer/portainer/api/http/handler/kubernetes"
 	"github.com/portainer/portainer/api/http/handler/ldap"
+	"github.com/portainer/portainer/api/http/handler/metrics"
 	"github.com/portainer/portainer/api/http/handler/motd"
+	"github.com/portainer/portainer/api/http/handler/notificationchannels"
+	"github.com/portainer/portainer/api/http/handler/quotas"
 	"github.com/portainer/portainer/api/http/handler/registries"
+	"github.com/portainer/portainer/api/http/handler/reports"
 	"github.com/portainer/portainer/api/http/handler/resourcecontrols"
 	"github.com/portainer/portainer/api/http/handler/roles"
+	"github.com/portainer/portainer/api/http/handler/serviceaccounts"
 	"github.com/portainer/portainer/api/http/handler/settings"
+	"github.com/portainer/portainer/api/http/handler/snapshotwebhooks"
 	"github.com/portainer/portainer/api/http/handler/ssl"
 	"github.com/portainer/portainer/api/http/handler/stacks"
 	"github.com/portainer/portainer/api/http/handler/storybook"
@@ -35,51 +51,73 @@ import (
 	"github.com/portainer/portainer/api/http/handler/teammemberships"
 	"github.com/portainer/portainer/api/http/handler/teams"
 	"github.com/portainer/portainer/api/http/handler/templates"
+	"github.com/portainer/portainer/api/http/handler/uioverrides"
 	"github.com/portainer/portainer/api/http/handler/upload"
 	"github.com/portainer/portainer/api/http/handler/users"
+	"github.com/portainer/portainer/api/http/handler/validationwebhooks"
 	"github.com/portainer/portainer/api/http/handler/webhooks"
 	"github.com/portainer/portainer/api/http/handler/websocket"
 )
 
 // Handler is a collection of all the service handlers.
 type Handler struct {
-	AuthHandler            *auth.Handler
-	BackupHandler          *backup.Handler
-	CustomTemplatesHandler *customtemplates.Handler
-	DockerHandler          *docker.Handler
-	EdgeGroupsHandler      *edgegroups.Handler
-	EdgeJobsHandler        *edgejobs.Handler
-	EdgeStacksHandler      *edgestacks.Handler
-	EdgeTemplatesHandler   *edgetemplates.Handler
-	EndpointEdgeHandler    *endpointedge.Handler
-	EndpointGroupHandler   *endpointgroups.Handler
-	EndpointHandler        *endpoints.Handler
-	EndpointHelmHandler    *helm.Handler
-	EndpointProxyHandler   *endpointproxy.Handler
-	GitOperationHandler    *gitops.Handler
-	HelmTemplatesHandler   *helm.Handler
-	KubernetesHandler      *kubernetes.Handler
-	FileHandler            *file.Handler
-	LDAPHandler            *ldap.Handler
-	MOTDHandler            *motd.Handler
-	RegistryHandler        *registries.Handler
-	ResourceControlHandler *resourcecontrols.Handler
-	RoleHandler            *roles.Handler
-	SettingsHandler        *settings.Handler
-	SSLHandler             *ssl.Handler
-	OpenAMTHandler         *openamt.Handler
-	StackHandler           *stacks.Handler
-	StorybookHandler       *storybook.Handler
-	SystemHandler          *system.Handler
-	TagHandler             *tags.Handler
-	TeamMembershipHandler  *teammemberships.Handler
-	TeamHandler            *teams.Handler
-	TemplatesHandler       *templates.Handler
-	UploadHandler          *upload.Handler
-	UserHandler            *users.Handler
-	WebSocketHandler       *websocket.Handler
-	WebhookHandler         *webhooks.Handler
-	UserHelmHandler        *helm.Handler
+	// ACMEChallengeHandler serves the tokens of the HTTP-01 challenges used to obtain the HTTPS certificate
+	ACMEChallengeHandler     http.Handler
+	AuthHandler              *auth.Handler
+	AzureHandler             *azure.Handler
+	BackupHandler            *backup.Handler
+	CloudProvisioningHandler *cloudprovisionings.Handler
+	CustomTemplatesHandler   *customtemplates.Handler
+	DeploymentsHandler       *deployments.Handler
+	DockerHandler            *docker.Handler
+	EdgeBundleHandler        *edgebundle.Handler
+	EdgeUpdateHandler        *edgeupdateschedules.Handler
+	EdgeGroupsHandler        *edgegroups.Handler
+	EdgeJobsHandler          *edgejobs.Handler
+	EdgeJoinTokensHandler    *edgejointokens.Handler
+	EdgeStacksHandler        *edgestacks.Handler
+	EdgeTemplatesHandler     *edgetemplates.Handler
+	EndpointEdgeHandler      *endpointedge.Handler
+	EndpointGroupHandler     *endpointgroups.Handler
+	EndpointHandler          *endpoints.Handler
+	EndpointHelmHandler      *helm.Handler
+	EndpointProxyHandler     *endpointproxy.Handler
+	ExportsHandler           *exports.Handler
+	FailoverPoliciesHandler  *failoverpolicies.Handler
+	GitOperationHandler      *gitops.Handler
+	GroupReportsHandler      *reports.Handler
+	HelmTemplatesHandler     *helm.Handler
+	ImageUpdateJobsHandler   *imageupdatejobs.Handler
+	InactiveResourcesHandler *inactiveresources.Handler
+	KubernetesHandler        *kubernetes.Handler
+	FileHandler              *file.Handler
+	LDAPHandler              *ldap.Handler
+	MetricsHandler           *metrics.Handler
+	MOTDHandler              *motd.Handler
+	RegistryHandler          *registries.Handler
+	ResourceControlHandler   *resourcecontrols.Handler
+	RoleHandler              *roles.Handler
+	ServiceAccountHandler    *serviceaccounts.Handler
+	SettingsHandler          *settings.Handler
+	SnapshotWebhookHandler   *snapshotwebhooks.Handler
+	NotificationHandler      *notificationchannels.Handler
+	SSLHandler               *ssl.Handler
+	OpenAMTHandler           *openamt.Handler
+	QuotasHandler            *quotas.Handler
+	StackHandler             *stacks.Handler
+	StorybookHandler         *storybook.Handler
+	SystemHandler            *system.Handler
+	TagHandler               *tags.Handler
+	TeamMembershipHandler    *teammemberships.Handler
+	TeamHandler              *teams.Handler
+	TemplatesHandler         *templates.Handler
+	UIOverridesHandler       *uioverrides.Handler
+	UploadHandler            *upload.Handler
+	UserHandler              *users.Handler
+	ValidationWebhookHandler *validationwebhooks.Handler
+	WebSocketHandler         *websocket.Handler
+	WebhookHandler           *webhooks.Handler
+	UserHelmHandler          *helm.Handler
 }
 
 // @title PortainerCE API
@@ -108,8 +146,12 @@ type Handler struct {
 // @tag.description Authenticate against Portainer HTTP API
 // @tag.name backup
 // @tag.description Manage backups
+// @tag.name cloud_provisionings
+// @tag.description Provision environments(endpoints) on cloud providers
 // @tag.name custom_templates
 // @tag.description Manage Custom Templates
+// @tag.name deployments
+// @tag.description Manage the deployment history
 // @tag.name docker
 // @tag.description Manage Docker resources
 // @tag.name edge
@@ -118,18 +160,32 @@ type Handler struct {
 // @tag.description Manage Edge Groups
 // @tag.name edge_jobs
 // @tag.description Manage Edge Jobs
+// @tag.name edge_join_tokens
+// @tag.description Manage the tokens Edge agents use to register themselves
 // @tag.name edge_stacks
 // @tag.description Manage Edge Stacks
 // @tag.name edge_templates
 // @tag.description Manage Edge Templates
+// @tag.name edge_update_schedules
+// @tag.description Manage the updates of the Edge agents
 // @tag.name endpoint_groups
 // @tag.description Manage environment(endpoint) groups
 // @tag.name endpoints
 // @tag.description Manage Docker environments(endpoints)
 // @tag.name gitops
 // @tag.description Operate git repository
+// @tag.name exports
+// @tag.description Export the inventories of the instance
+// @tag.name failover_policies
+// @tag.description Manage the failover of stacks between standalone Docker environments
+// @tag.name group_reports
+// @tag.description Manage the scheduled reports of environment(endpoint) groups sent by email
 // @tag.name helm
 // @tag.description Manage Helm charts
+// @tag.name image_update_jobs
+// @tag.description Manage scheduled container image update jobs
+// @tag.name inactive_resources
+// @tag.description Report and remove inactive Docker resources, and run the prune policies of the environments
 // @tag.name intel
 // @tag.description Manage Intel AMT settings
 // @tag.name kubernetes
@@ -138,14 +194,20 @@ type Handler struct {
 // @tag.description Manage LDAP settings
 // @tag.name motd
 // @tag.description Fetch the message of the day
+// @tag.name quotas
+// @tag.description Manage the quotas of resources deployed by non-administrators
 // @tag.name registries
 // @tag.description Manage Docker registries
 // @tag.name resource_controls
 // @tag.description Manage access control on Docker resources
 // @tag.name roles
 // @tag.description Manage roles
+// @tag.name service_accounts
+// @tag.description Manage the service accounts used by automation
 // @tag.name settings
 // @tag.description Manage Portainer settings
+// @tag.name snapshot_webhooks
+// @tag.description Manage the external inventories receiving the snapshots of the environments
 // @tag.name ssl
 // @tag.description Manage ssl settings
 // @tag.name stacks
@@ -162,10 +224,14 @@ type Handler struct {
 // @tag.description Manage teams
 // @tag.name templates
 // @tag.description Manage App Templates
+// @tag.name ui_overrides
+// @tag.description Manage the UI asset overrides
 // @tag.name upload
 // @tag.description Upload files
 // @tag.name users
 // @tag.description Manage users
+// @tag.name validation_webhooks
+// @tag.description Manage the external services validating the deployments
 // @tag.name webhooks
 // @tag.description Manage webhooks
 // @tag.name websocket
@@ -178,22 +244,42 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.EndpointEdgeHandler.ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/auth"):
 		http.StripPrefix("/api", h.AuthHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/azure"):
+		http.StripPrefix("/api", h.AzureHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/backup"):
 		http.StripPrefix("/api", h.BackupHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/restore"):
 		http.StripPrefix("/api", h.BackupHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/cloud_provisionings"):
+		http.StripPrefix("/api", h.CloudProvisioningHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/custom_templates"):
 		http.StripPrefix("/api", h.CustomTemplatesHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/deployments"):
+		http.StripPrefix("/api", h.DeploymentsHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/edge_stacks"):
 		http.StripPrefix("/api", h.EdgeStacksHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/edge_update_schedules"):
+		http.StripPrefix("/api", h.EdgeUpdateHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/edge_bundle"):
+		http.StripPrefix("/api", h.EdgeBundleHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/edge_groups"):
 		http.StripPrefix("/api", h.EdgeGroupsHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/edge_jobs"):
 		http.StripPrefix("/api", h.EdgeJobsHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/edge_join_tokens"):
+		http.StripPrefix("/api", h.EdgeJoinTokensHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/edge_templates"):
 		http.StripPrefix("/api", h.EdgeTemplatesHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/endpoint_groups"):
 		http.StripPrefix("/api", h.EndpointGroupHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/exports"):
+		http.StripPrefix("/api", h.ExportsHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/failover_policies"):
+		http.StripPrefix("/api", h.FailoverPoliciesHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/image_update_jobs"):
+		http.StripPrefix("/api", h.ImageUpdateJobsHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/inactive_resources"):
+		http.StripPrefix("/api", h.InactiveResourcesHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/kubernetes"):
 		http.StripPrefix("/api", h.KubernetesHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/docker"):
@@ -216,18 +302,26 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		default:
 			http.StripPrefix("/api", h.EndpointHandler).ServeHTTP(w, r)
 		}
+	case strings.HasPrefix(r.URL.Path, "/api/group_reports"), strings.HasPrefix(r.URL.Path, "/api/report_templates"):
+		http.StripPrefix("/api", h.GroupReportsHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/gitops"):
 		http.StripPrefix("/api", h.GitOperationHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/ldap"):
 		http.StripPrefix("/api", h.LDAPHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/metrics") && h.MetricsHandler != nil:
+		http.StripPrefix("/api", h.MetricsHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/motd"):
 		http.StripPrefix("/api", h.MOTDHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/quotas"):
+		http.StripPrefix("/api", h.QuotasHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/registries"):
 		http.StripPrefix("/api", h.RegistryHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/resource_controls"):
 		http.StripPrefix("/api", h.ResourceControlHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/roles"):
 		http.StripPrefix("/api", h.RoleHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/service_accounts"):
+		http.StripPrefix("/api", h.ServiceAccountHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/settings"):
 		http.StripPrefix("/api", h.SettingsHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/stacks"):
@@ -242,10 +336,18 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.StripPrefix("/api", h.HelmTemplatesHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/templates"):
 		http.StripPrefix("/api", h.TemplatesHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/ui_overrides"):
+		http.StripPrefix("/api", h.UIOverridesHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/upload"):
 		http.StripPrefix("/api", h.UploadHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/users"):
 		http.StripPrefix("/api", h.UserHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/validation_webhooks"):
+		http.StripPrefix("/api", h.ValidationWebhookHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/snapshot_webhooks"):
+		http.StripPrefix("/api", h.SnapshotWebhookHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/api/notification_channels"):
+		http.StripPrefix("/api", h.NotificationHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/ssl"):
 		http.StripPrefix("/api", h.SSLHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/open_amt"):
@@ -258,6 +360,8 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.StripPrefix("/api", h.WebSocketHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/api/webhooks"):
 		http.StripPrefix("/api", h.WebhookHandler).ServeHTTP(w, r)
+	case strings.HasPrefix(r.URL.Path, "/.well-known/acme-challenge/") && h.ACMEChallengeHandler != nil:
+		h.ACMEChallengeHandler.ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/storybook"):
 		http.StripPrefix("/storybook", h.StorybookHandler).ServeHTTP(w, r)
 	case strings.HasPrefix(r.URL.Path, "/"):
@@ -0,0 +1,104 @@
+package openamt
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/amtpower"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type bulkActionPayload struct {
+	// Edge groups whose AMT managed devices receive the action
+	EdgeGroupIDs []portainer.EdgeGroupID `example:"1"`
+	Action       string                  `example:"power on" enums:"power on,power off,restart"`
+}
+
+func (payload *bulkActionPayload) Validate(r *http.Request) error {
+	if len(payload.EdgeGroupIDs) == 0 {
+		return errors.New("at least one edge group must be provided")
+	}
+
+	if payload.Action == "" {
+		return errors.New("device action must be provided")
+	}
+
+	return nil
+}
+
+// @id OpenAMTBulkAction
+// @summary Execute out of band action on the AMT managed devices of edge groups
+// @description Execute an out of band action on the AMT managed devices of the environments of edge groups.
+// @description The outcome of the action on each device is returned and recorded in the history of the device.
+// @description **Access policy**: administrator
+// @tags intel
+// @security jwt
+// @accept json
+// @produce json
+// @param body body bulkActionPayload true "Device Action"
+// @success 200 {array} portainer.OpenAMTDeviceAction "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied to access settings"
+// @failure 500 "Server error"
+// @router /open_amt/actions [post]
+func (handler *Handler) openAMTBulkAction(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload bulkActionPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	for _, edgeGroupID := range payload.EdgeGroupIDs {
+		if _, err := handler.DataStore.EdgeGroup().Read(edgeGroupID); handler.DataStore.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find an edge group with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find an edge group with the specified identifier inside the database", err)
+		}
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve user details from authentication token", err)
+	}
+
+	deviceActions, err := handler.PowerService.ExecuteAction(payload.EdgeGroupIDs, payload.Action, 0, tokenData.Username)
+	if errors.Is(err, amtpower.ErrOpenAMTDisabled) {
+		return httperror.BadRequest("OpenAMT is not enabled", err)
+	} else if err != nil {
+		return httperror.BadRequest("Error executing device action", err)
+	}
+
+	return response.JSON(w, deviceActions)
+}
+
+// @id OpenAMTDeviceActions
+// @summary List the actions executed on the AMT managed device of an environment
+// @description List the out of band actions executed on the AMT managed device of an environment, from the most recent.
+// @description **Access policy**: administrator
+// @tags intel
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @success 200 {array} portainer.OpenAMTDeviceAction "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied to access settings"
+// @failure 500 "Server error"
+// @router /open_amt/{id}/actions [get]
+func (handler *Handler) openAMTDeviceActions(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid environment identifier route variable", err)
+	}
+
+	deviceActions, err := handler.DataStore.OpenAMTDeviceAction().ReadAllByEndpointID(portainer.EndpointID(endpointID))
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the device actions from the database", err)
+	}
+
+	amtpower.SortDeviceActions(deviceActions)
+
+	return response.JSON(w, deviceActions)
+}
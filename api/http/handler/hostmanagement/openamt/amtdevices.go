@@ -3,8 +3,12 @@ package openamt
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/amtpower"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -73,7 +77,7 @@ func (payload *deviceActionPayload) Validate(r *http.Request) error {
 
 // @id DeviceAction
 // @summary Execute out of band action on an AMT managed device
-// @description Execute out of band action on an AMT managed device
+// @description Execute out of band action on an AMT managed device, the action is recorded in the history of the device
 // @description **Access policy**: administrator
 // @tags intel
 // @security jwt
@@ -88,6 +92,11 @@ func (payload *deviceActionPayload) Validate(r *http.Request) error {
 // @failure 500 "Server error"
 // @router /open_amt/{id}/devices/{deviceId}/action [post]
 func (handler *Handler) deviceAction(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid environment identifier route variable", err)
+	}
+
 	deviceID, err := request.RetrieveRouteVariableValue(r, "deviceId")
 	if err != nil {
 		return httperror.BadRequest("Invalid device identifier route variable", err)
@@ -107,6 +116,7 @@ func (handler *Handler) deviceAction(w http.ResponseWriter, r *http.Request) *ht
 	}
 
 	err = handler.OpenAMTService.ExecuteDeviceAction(settings.OpenAMTConfiguration, deviceID, payload.Action)
+	handler.recordDeviceAction(r, portainer.EndpointID(endpointID), deviceID, payload.Action, err)
 	if err != nil {
 		log.Error().Err(err).Msg("error executing device action")
 
@@ -116,6 +126,30 @@ func (handler *Handler) deviceAction(w http.ResponseWriter, r *http.Request) *ht
 	return response.Empty(w)
 }
 
+// recordDeviceAction records an action executed by a user in the history of the device
+func (handler *Handler) recordDeviceAction(r *http.Request, endpointID portainer.EndpointID, deviceID, action string, actionErr error) {
+	deviceAction := portainer.OpenAMTDeviceAction{
+		EndpointID: endpointID,
+		DeviceGUID: deviceID,
+		Action:     action,
+		Time:       time.Now().Unix(),
+	}
+
+	if tokenData, err := security.RetrieveTokenData(r); err == nil {
+		deviceAction.Username = tokenData.Username
+	}
+
+	if actionErr != nil {
+		deviceAction.Error = actionErr.Error()
+	}
+
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return amtpower.RecordDeviceAction(tx, &deviceAction)
+	}); err != nil {
+		log.Warn().Err(err).Str("device_id", deviceID).Msg("unable to record the device action")
+	}
+}
+
 type deviceFeaturesPayload struct {
 	Features portainer.OpenAMTDeviceEnabledFeatures
 }
@@ -0,0 +1,287 @@
+package openamt
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/amtpower"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type powerScheduleCreatePayload struct {
+	Name string `example:"office-hours"`
+	// Edge groups whose AMT managed devices are powered on and off
+	EdgeGroupIDs []portainer.EdgeGroupID `example:"1"`
+	// Time of the day the devices are powered on in the HH:MM format, the devices are not powered on when empty
+	PowerOnTime string `example:"07:00"`
+	// Time of the day the devices are powered off in the HH:MM format, the devices are not powered off when empty
+	PowerOffTime string `example:"20:00"`
+	// IANA time zone of the power on and off times, UTC when empty
+	TimeZone string `example:"Europe/Paris"`
+	Enabled  bool   `example:"true"`
+}
+
+func (payload *powerScheduleCreatePayload) Validate(r *http.Request) error {
+	if payload.Name == "" {
+		return errors.New("invalid power schedule name")
+	}
+
+	if len(payload.EdgeGroupIDs) == 0 {
+		return errors.New("at least one edge group must be provided")
+	}
+
+	return amtpower.ValidateSchedule(payload.PowerOnTime, payload.PowerOffTime, payload.TimeZone)
+}
+
+type powerScheduleUpdatePayload struct {
+	Name *string `example:"office-hours"`
+	// Edge groups whose AMT managed devices are powered on and off
+	EdgeGroupIDs []portainer.EdgeGroupID `example:"1"`
+	// Time of the day the devices are powered on in the HH:MM format, an empty time stops powering on the devices
+	PowerOnTime *string `example:"07:00"`
+	// Time of the day the devices are powered off in the HH:MM format, an empty time stops powering off the devices
+	PowerOffTime *string `example:"20:00"`
+	// IANA time zone of the power on and off times, UTC when empty
+	TimeZone *string `example:"Europe/Paris"`
+	Enabled  *bool   `example:"true"`
+}
+
+func (payload *powerScheduleUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("invalid power schedule name")
+	}
+
+	if payload.EdgeGroupIDs != nil && len(payload.EdgeGroupIDs) == 0 {
+		return errors.New("at least one edge group must be provided")
+	}
+
+	return nil
+}
+
+// @id OpenAMTPowerScheduleList
+// @summary List the OpenAMT power schedules
+// @description **Access policy**: administrator
+// @tags intel
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.OpenAMTPowerSchedule "Success"
+// @failure 500 "Server error"
+// @router /open_amt/power_schedules [get]
+func (handler *Handler) powerScheduleList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	schedules, err := handler.DataStore.OpenAMTPowerSchedule().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the power schedules from the database", err)
+	}
+
+	return response.JSON(w, schedules)
+}
+
+// @id OpenAMTPowerScheduleInspect
+// @summary Inspect an OpenAMT power schedule
+// @description **Access policy**: administrator
+// @tags intel
+// @security jwt
+// @produce json
+// @param scheduleId path int true "Power schedule identifier"
+// @success 200 {object} portainer.OpenAMTPowerSchedule "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Power schedule not found"
+// @failure 500 "Server error"
+// @router /open_amt/power_schedules/{scheduleId} [get]
+func (handler *Handler) powerScheduleInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	scheduleID, err := request.RetrieveNumericRouteVariableValue(r, "scheduleId")
+	if err != nil {
+		return httperror.BadRequest("Invalid power schedule identifier route variable", err)
+	}
+
+	schedule, err := handler.DataStore.OpenAMTPowerSchedule().Read(portainer.OpenAMTPowerScheduleID(scheduleID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a power schedule with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a power schedule with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, schedule)
+}
+
+// @id OpenAMTPowerScheduleCreate
+// @summary Create an OpenAMT power schedule
+// @description Create a schedule powering on and off the AMT managed devices of edge groups at fixed times of the day.
+// @description **Access policy**: administrator
+// @tags intel
+// @security jwt
+// @accept json
+// @produce json
+// @param body body powerScheduleCreatePayload true "Power schedule details"
+// @success 200 {object} portainer.OpenAMTPowerSchedule "Success"
+// @failure 400 "Invalid request"
+// @failure 500 "Server error"
+// @router /open_amt/power_schedules [post]
+func (handler *Handler) powerScheduleCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload powerScheduleCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	schedule := &portainer.OpenAMTPowerSchedule{
+		Name:         payload.Name,
+		EdgeGroupIDs: payload.EdgeGroupIDs,
+		PowerOnTime:  payload.PowerOnTime,
+		PowerOffTime: payload.PowerOffTime,
+		TimeZone:     payload.TimeZone,
+		Enabled:      payload.Enabled,
+	}
+
+	err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := validateEdgeGroups(tx, schedule.EdgeGroupIDs); err != nil {
+			return err
+		}
+
+		return tx.OpenAMTPowerSchedule().Create(schedule)
+	})
+
+	return txResponse(w, schedule, err)
+}
+
+// @id OpenAMTPowerScheduleUpdate
+// @summary Update an OpenAMT power schedule
+// @description **Access policy**: administrator
+// @tags intel
+// @security jwt
+// @accept json
+// @produce json
+// @param scheduleId path int true "Power schedule identifier"
+// @param body body powerScheduleUpdatePayload true "Power schedule details"
+// @success 200 {object} portainer.OpenAMTPowerSchedule "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Power schedule not found"
+// @failure 500 "Server error"
+// @router /open_amt/power_schedules/{scheduleId} [put]
+func (handler *Handler) powerScheduleUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	scheduleID, err := request.RetrieveNumericRouteVariableValue(r, "scheduleId")
+	if err != nil {
+		return httperror.BadRequest("Invalid power schedule identifier route variable", err)
+	}
+
+	var payload powerScheduleUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var schedule *portainer.OpenAMTPowerSchedule
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		schedule, err = updatePowerSchedule(tx, portainer.OpenAMTPowerScheduleID(scheduleID), payload)
+
+		return err
+	})
+
+	return txResponse(w, schedule, err)
+}
+
+func updatePowerSchedule(tx dataservices.DataStoreTx, scheduleID portainer.OpenAMTPowerScheduleID, payload powerScheduleUpdatePayload) (*portainer.OpenAMTPowerSchedule, error) {
+	schedule, err := tx.OpenAMTPowerSchedule().Read(scheduleID)
+	if tx.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find a power schedule with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find a power schedule with the specified identifier inside the database", err)
+	}
+
+	if payload.Name != nil {
+		schedule.Name = *payload.Name
+	}
+
+	if payload.PowerOnTime != nil {
+		schedule.PowerOnTime = *payload.PowerOnTime
+	}
+
+	if payload.PowerOffTime != nil {
+		schedule.PowerOffTime = *payload.PowerOffTime
+	}
+
+	if payload.TimeZone != nil {
+		schedule.TimeZone = *payload.TimeZone
+	}
+
+	if payload.Enabled != nil {
+		schedule.Enabled = *payload.Enabled
+	}
+
+	if payload.EdgeGroupIDs != nil {
+		if err := validateEdgeGroups(tx, payload.EdgeGroupIDs); err != nil {
+			return nil, err
+		}
+
+		schedule.EdgeGroupIDs = payload.EdgeGroupIDs
+	}
+
+	if err := amtpower.ValidateSchedule(schedule.PowerOnTime, schedule.PowerOffTime, schedule.TimeZone); err != nil {
+		return nil, httperror.BadRequest("Invalid power schedule", err)
+	}
+
+	if err := tx.OpenAMTPowerSchedule().Update(schedule.ID, schedule); err != nil {
+		return nil, httperror.InternalServerError("Unable to persist the power schedule changes inside the database", err)
+	}
+
+	return schedule, nil
+}
+
+// @id OpenAMTPowerScheduleDelete
+// @summary Delete an OpenAMT power schedule
+// @description **Access policy**: administrator
+// @tags intel
+// @security jwt
+// @param scheduleId path int true "Power schedule identifier"
+// @success 204 "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Power schedule not found"
+// @failure 500 "Server error"
+// @router /open_amt/power_schedules/{scheduleId} [delete]
+func (handler *Handler) powerScheduleDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	scheduleID, err := request.RetrieveNumericRouteVariableValue(r, "scheduleId")
+	if err != nil {
+		return httperror.BadRequest("Invalid power schedule identifier route variable", err)
+	}
+
+	id := portainer.OpenAMTPowerScheduleID(scheduleID)
+
+	if _, err := handler.DataStore.OpenAMTPowerSchedule().Read(id); handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a power schedule with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a power schedule with the specified identifier inside the database", err)
+	}
+
+	if err := handler.DataStore.OpenAMTPowerSchedule().Delete(id); err != nil {
+		return httperror.InternalServerError("Unable to remove the power schedule from the database", err)
+	}
+
+	return response.Empty(w)
+}
+
+func validateEdgeGroups(tx dataservices.DataStoreTx, edgeGroupIDs []portainer.EdgeGroupID) error {
+	for _, edgeGroupID := range edgeGroupIDs {
+		if _, err := tx.EdgeGroup().Read(edgeGroupID); tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find an edge group with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find an edge group with the specified identifier inside the database", err)
+		}
+	}
+
+	return nil
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
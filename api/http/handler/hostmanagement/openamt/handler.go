@@ -9,6 +9,7 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	dockerclient "github.com/portainer/portainer/api/docker/client"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/amtpower"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 )
 
@@ -18,6 +19,7 @@ type Handler struct {
 	OpenAMTService      portainer.OpenAMTService
 	DataStore           dataservices.DataStore
 	DockerClientFactory *dockerclient.ClientFactory
+	PowerService        *amtpower.Service
 }
 
 // NewHandler returns a new Handler
@@ -27,8 +29,15 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 	}
 
 	h.Handle("/open_amt/configure", bouncer.AdminAccess(httperror.LoggerHandler(h.openAMTConfigure))).Methods(http.MethodPost)
+	h.Handle("/open_amt/actions", bouncer.AdminAccess(httperror.LoggerHandler(h.openAMTBulkAction))).Methods(http.MethodPost)
+	h.Handle("/open_amt/power_schedules", bouncer.AdminAccess(httperror.LoggerHandler(h.powerScheduleList))).Methods(http.MethodGet)
+	h.Handle("/open_amt/power_schedules", bouncer.AdminAccess(httperror.LoggerHandler(h.powerScheduleCreate))).Methods(http.MethodPost)
+	h.Handle("/open_amt/power_schedules/{scheduleId}", bouncer.AdminAccess(httperror.LoggerHandler(h.powerScheduleInspect))).Methods(http.MethodGet)
+	h.Handle("/open_amt/power_schedules/{scheduleId}", bouncer.AdminAccess(httperror.LoggerHandler(h.powerScheduleUpdate))).Methods(http.MethodPut)
+	h.Handle("/open_amt/power_schedules/{scheduleId}", bouncer.AdminAccess(httperror.LoggerHandler(h.powerScheduleDelete))).Methods(http.MethodDelete)
 	h.Handle("/open_amt/{id}/info", bouncer.AdminAccess(httperror.LoggerHandler(h.openAMTHostInfo))).Methods(http.MethodGet)
 	h.Handle("/open_amt/{id}/activate", bouncer.AdminAccess(httperror.LoggerHandler(h.openAMTActivate))).Methods(http.MethodPost)
+	h.Handle("/open_amt/{id}/actions", bouncer.AdminAccess(httperror.LoggerHandler(h.openAMTDeviceActions))).Methods(http.MethodGet)
 	h.Handle("/open_amt/{id}/devices", bouncer.AdminAccess(httperror.LoggerHandler(h.openAMTDevices))).Methods(http.MethodGet)
 	h.Handle("/open_amt/{id}/devices/{deviceId}/action", bouncer.AdminAccess(httperror.LoggerHandler(h.deviceAction))).Methods(http.MethodPost)
 	h.Handle("/open_amt/{id}/devices/{deviceId}/features", bouncer.AdminAccess(httperror.LoggerHandler(h.deviceFeatures))).Methods(http.MethodPost)
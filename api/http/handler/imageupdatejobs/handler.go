@@ -0,0 +1,65 @@
+package imageupdatejobs
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/imageupdate"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/gorilla/mux"
+	"github.com/robfig/cron/v3"
+)
+
+// Handler is the HTTP handler used to handle image update job operations.
+type Handler struct {
+	*mux.Router
+	DataStore          dataservices.DataStore
+	ImageUpdateService *imageupdate.Service
+}
+
+// NewHandler creates a handler to manage image update job operations.
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+
+	h.Handle("/image_update_jobs",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.imageUpdateJobList))).Methods(http.MethodGet)
+	h.Handle("/image_update_jobs",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.imageUpdateJobCreate))).Methods(http.MethodPost)
+	h.Handle("/image_update_jobs/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.imageUpdateJobInspect))).Methods(http.MethodGet)
+	h.Handle("/image_update_jobs/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.imageUpdateJobUpdate))).Methods(http.MethodPut)
+	h.Handle("/image_update_jobs/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.imageUpdateJobDelete))).Methods(http.MethodDelete)
+	h.Handle("/image_update_jobs/{id}/run",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.imageUpdateJobRun))).Methods(http.MethodPost)
+
+	return h
+}
+
+func validateCronExpression(cronExpression string) error {
+	if _, err := cron.ParseStandard(cronExpression); err != nil {
+		return errors.New("invalid cron expression")
+	}
+
+	return nil
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
@@ -0,0 +1,96 @@
+package imageupdatejobs
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type imageUpdateJobCreatePayload struct {
+	Name           string `example:"nightly-updates"`
+	CronExpression string `example:"0 3 * * *"`
+	// Environments(Endpoints) checked by the job
+	EndpointIDs []portainer.EndpointID
+	// Whether outdated containers and services are updated to the latest image
+	AutoUpdate bool `example:"false"`
+	// Whether the job is scheduled
+	Enabled bool `example:"true"`
+}
+
+func (payload *imageUpdateJobCreatePayload) Validate(r *http.Request) error {
+	if payload.Name == "" {
+		return errors.New("invalid image update job name")
+	}
+
+	if len(payload.EndpointIDs) == 0 {
+		return errors.New("invalid environment identifiers, at least one environment is required")
+	}
+
+	return validateCronExpression(payload.CronExpression)
+}
+
+// @id ImageUpdateJobCreate
+// @summary Create an image update job
+// @description Create a job checking on a schedule whether the images of the containers and services of some environments
+// @description have a newer version in their registry, and optionally updating them.
+// @description The Edge environments are not checked, they are reported as skipped in the results of the job.
+// @description **Access policy**: administrator
+// @tags image_update_jobs
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body imageUpdateJobCreatePayload true "Image update job details"
+// @success 200 {object} portainer.ImageUpdateJob
+// @failure 400
+// @failure 500
+// @router /image_update_jobs [post]
+func (handler *Handler) imageUpdateJobCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload imageUpdateJobCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	job := &portainer.ImageUpdateJob{
+		Name:           payload.Name,
+		CronExpression: payload.CronExpression,
+		EndpointIDs:    payload.EndpointIDs,
+		AutoUpdate:     payload.AutoUpdate,
+		Enabled:        payload.Enabled,
+		Created:        time.Now().Unix(),
+		Results:        []portainer.ImageUpdateJobResult{},
+	}
+
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := validateEndpoints(tx, job.EndpointIDs); err != nil {
+			return err
+		}
+
+		return tx.ImageUpdateJob().Create(job)
+	}); err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	if err := handler.ImageUpdateService.Schedule(job); err != nil {
+		return httperror.InternalServerError("Unable to schedule the image update job", err)
+	}
+
+	return txResponse(w, job, nil)
+}
+
+func validateEndpoints(tx dataservices.DataStoreTx, endpointIDs []portainer.EndpointID) error {
+	for _, endpointID := range endpointIDs {
+		if _, err := tx.Endpoint().Endpoint(endpointID); tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find an environment with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package imageupdatejobs
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id ImageUpdateJobDelete
+// @summary Delete an image update job
+// @description **Access policy**: administrator
+// @tags image_update_jobs
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Image update job identifier"
+// @success 204
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /image_update_jobs/{id} [delete]
+func (handler *Handler) imageUpdateJobDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	jobID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid image update job identifier route variable", err)
+	}
+
+	id := portainer.ImageUpdateJobID(jobID)
+
+	if _, err := handler.DataStore.ImageUpdateJob().Read(id); handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an image update job with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an image update job with the specified identifier inside the database", err)
+	}
+
+	handler.ImageUpdateService.Unschedule(id)
+
+	if err := handler.DataStore.ImageUpdateJob().Delete(id); err != nil {
+		return httperror.InternalServerError("Unable to remove the image update job from the database", err)
+	}
+
+	return response.Empty(w)
+}
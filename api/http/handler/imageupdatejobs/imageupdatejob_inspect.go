@@ -0,0 +1,40 @@
+package imageupdatejobs
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id ImageUpdateJobInspect
+// @summary Inspect an image update job
+// @description Retrieve an image update job along with the results of its last run.
+// @description **Access policy**: administrator
+// @tags image_update_jobs
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Image update job identifier"
+// @success 200 {object} portainer.ImageUpdateJob
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /image_update_jobs/{id} [get]
+func (handler *Handler) imageUpdateJobInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	jobID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid image update job identifier route variable", err)
+	}
+
+	job, err := handler.DataStore.ImageUpdateJob().Read(portainer.ImageUpdateJobID(jobID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an image update job with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an image update job with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, job)
+}
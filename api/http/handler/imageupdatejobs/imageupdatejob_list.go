@@ -0,0 +1,27 @@
+package imageupdatejobs
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id ImageUpdateJobList
+// @summary List the image update jobs
+// @description **Access policy**: administrator
+// @tags image_update_jobs
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.ImageUpdateJob
+// @failure 500
+// @router /image_update_jobs [get]
+func (handler *Handler) imageUpdateJobList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	jobs, err := handler.DataStore.ImageUpdateJob().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve image update jobs from the database", err)
+	}
+
+	return response.JSON(w, jobs)
+}
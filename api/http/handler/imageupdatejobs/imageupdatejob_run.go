@@ -0,0 +1,49 @@
+package imageupdatejobs
+
+import (
+	"context"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/rs/zerolog/log"
+)
+
+// @id ImageUpdateJobRun
+// @summary Run an image update job
+// @description Run an image update job immediately, in the background. The results are available on the job once the run is over.
+// @description **Access policy**: administrator
+// @tags image_update_jobs
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Image update job identifier"
+// @success 204
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /image_update_jobs/{id}/run [post]
+func (handler *Handler) imageUpdateJobRun(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	jobID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid image update job identifier route variable", err)
+	}
+
+	id := portainer.ImageUpdateJobID(jobID)
+
+	if _, err := handler.DataStore.ImageUpdateJob().Read(id); handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an image update job with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an image update job with the specified identifier inside the database", err)
+	}
+
+	go func() {
+		if err := handler.ImageUpdateService.Run(context.WithoutCancel(r.Context()), id); err != nil {
+			log.Warn().Err(err).Int("job_id", jobID).Msg("image update job run failed")
+		}
+	}()
+
+	return response.Empty(w)
+}
@@ -0,0 +1,118 @@
+package imageupdatejobs
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type imageUpdateJobUpdatePayload struct {
+	Name           *string `example:"nightly-updates"`
+	CronExpression *string `example:"0 3 * * *"`
+	// Environments(Endpoints) checked by the job
+	EndpointIDs []portainer.EndpointID
+	// Whether outdated containers and services are updated to the latest image
+	AutoUpdate *bool `example:"false"`
+	// Whether the job is scheduled
+	Enabled *bool `example:"true"`
+}
+
+func (payload *imageUpdateJobUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("invalid image update job name")
+	}
+
+	if payload.EndpointIDs != nil && len(payload.EndpointIDs) == 0 {
+		return errors.New("invalid environment identifiers, at least one environment is required")
+	}
+
+	if payload.CronExpression != nil {
+		return validateCronExpression(*payload.CronExpression)
+	}
+
+	return nil
+}
+
+// @id ImageUpdateJobUpdate
+// @summary Update an image update job
+// @description **Access policy**: administrator
+// @tags image_update_jobs
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Image update job identifier"
+// @param body body imageUpdateJobUpdatePayload true "Image update job details"
+// @success 200 {object} portainer.ImageUpdateJob
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /image_update_jobs/{id} [put]
+func (handler *Handler) imageUpdateJobUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	jobID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid image update job identifier route variable", err)
+	}
+
+	var payload imageUpdateJobUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var job *portainer.ImageUpdateJob
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		job, err = updateImageUpdateJob(tx, portainer.ImageUpdateJobID(jobID), payload)
+		return err
+	}); err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	if err := handler.ImageUpdateService.Schedule(job); err != nil {
+		return httperror.InternalServerError("Unable to schedule the image update job", err)
+	}
+
+	return txResponse(w, job, nil)
+}
+
+func updateImageUpdateJob(tx dataservices.DataStoreTx, jobID portainer.ImageUpdateJobID, payload imageUpdateJobUpdatePayload) (*portainer.ImageUpdateJob, error) {
+	job, err := tx.ImageUpdateJob().Read(jobID)
+	if tx.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find an image update job with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find an image update job with the specified identifier inside the database", err)
+	}
+
+	if payload.Name != nil {
+		job.Name = *payload.Name
+	}
+
+	if payload.CronExpression != nil {
+		job.CronExpression = *payload.CronExpression
+	}
+
+	if payload.EndpointIDs != nil {
+		if err := validateEndpoints(tx, payload.EndpointIDs); err != nil {
+			return nil, err
+		}
+
+		job.EndpointIDs = payload.EndpointIDs
+	}
+
+	if payload.AutoUpdate != nil {
+		job.AutoUpdate = *payload.AutoUpdate
+	}
+
+	if payload.Enabled != nil {
+		job.Enabled = *payload.Enabled
+	}
+
+	if err := tx.ImageUpdateJob().Update(job.ID, job); err != nil {
+		return nil, httperror.InternalServerError("Unable to persist image update job changes inside the database", err)
+	}
+
+	return job, nil
+}
@@ -0,0 +1,83 @@
+package inactiveresources
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/gc"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is the HTTP handler used to report and remove inactive resources.
+type Handler struct {
+	*mux.Router
+	DataStore dataservices.DataStore
+	GCService *gc.Service
+}
+
+// NewHandler creates a handler to report and remove inactive resources.
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+
+	h.Handle("/inactive_resources",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.inactiveResourcesList))).Methods(http.MethodGet)
+	h.Handle("/inactive_resources/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.inactiveResourcesInspect))).Methods(http.MethodGet)
+	h.Handle("/inactive_resources/{id}/cleanup",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.inactiveResourcesCleanup))).Methods(http.MethodPost)
+	h.Handle("/inactive_resources/{id}/prune_policy",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.prunePolicyUpdate))).Methods(http.MethodPut)
+	h.Handle("/inactive_resources/{id}/prune_policy/run",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.prunePolicyRun))).Methods(http.MethodPost)
+	h.Handle("/inactive_resources/{id}/prune_runs",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.pruneRunsList))).Methods(http.MethodGet)
+
+	return h
+}
+
+func buildOptions(containerAgeDays int, stackInactiveSince int64) (gc.Options, error) {
+	if containerAgeDays < 0 {
+		return gc.Options{}, errors.New("invalid container age, it must be a positive number of days")
+	}
+
+	if stackInactiveSince < 0 {
+		return gc.Options{}, errors.New("invalid stack inactivity date")
+	}
+
+	options := gc.Options{
+		ContainerAge:       gc.DefaultContainerAge,
+		StackInactiveSince: time.Now().Add(-gc.DefaultStackInactivity),
+	}
+
+	if containerAgeDays > 0 {
+		options.ContainerAge = time.Duration(containerAgeDays) * 24 * time.Hour
+	}
+
+	if stackInactiveSince > 0 {
+		options.StackInactiveSince = time.Unix(stackInactiveSince, 0)
+	}
+
+	return options, nil
+}
+
+func retrieveOptions(r *http.Request) (gc.Options, error) {
+	containerAgeDays, err := request.RetrieveNumericQueryParameter(r, "containerAgeDays", true)
+	if err != nil {
+		return gc.Options{}, err
+	}
+
+	stackInactiveSince, err := request.RetrieveNumericQueryParameter(r, "stackInactiveSince", true)
+	if err != nil {
+		return gc.Options{}, err
+	}
+
+	return buildOptions(containerAgeDays, int64(stackInactiveSince))
+}
@@ -0,0 +1,81 @@
+package inactiveresources
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/gc"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type inactiveResourcesCleanupPayload struct {
+	// Minimum number of days since a container stopped, defaults to 7
+	ContainerAgeDays int `example:"7"`
+	// Unix timestamp before which a stopped stack was last deployed, defaults to 30 days ago
+	StackInactiveSince int64 `example:"1700000000"`
+	// Identifiers of the containers to remove
+	ContainerIDs []string
+	// Identifiers of the images to remove
+	ImageIDs []string
+	// Names of the volumes to remove
+	VolumeNames []string
+	// Identifiers of the stacks to remove
+	StackIDs []portainer.StackID
+	// Remove all the inactive resources of the environment, the selected resources are ignored
+	All bool `example:"false"`
+}
+
+func (payload *inactiveResourcesCleanupPayload) Validate(r *http.Request) error {
+	_, err := buildOptions(payload.ContainerAgeDays, payload.StackInactiveSince)
+
+	return err
+}
+
+// @id InactiveResourcesCleanup
+// @summary Remove inactive resources of an environment
+// @description Remove the selected inactive resources of an environment, or all of them. The resources that are no longer
+// @description inactive are skipped. The containers are removed first so that the images and volumes they held can be removed too.
+// @description **Access policy**: administrator
+// @tags inactive_resources
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param body body inactiveResourcesCleanupPayload true "Resources to remove"
+// @success 200 {array} gc.CleanupResult "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /inactive_resources/{id}/cleanup [post]
+func (handler *Handler) inactiveResourcesCleanup(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, httpErr := handler.retrieveEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	var payload inactiveResourcesCleanupPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	options, err := buildOptions(payload.ContainerAgeDays, payload.StackInactiveSince)
+	if err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	results, err := handler.GCService.Cleanup(r.Context(), endpoint, options, gc.Selection{
+		ContainerIDs: payload.ContainerIDs,
+		ImageIDs:     payload.ImageIDs,
+		VolumeNames:  payload.VolumeNames,
+		StackIDs:     payload.StackIDs,
+		All:          payload.All,
+	})
+	if err != nil {
+		return httperror.InternalServerError("Unable to remove the inactive resources of the environment", err)
+	}
+
+	return response.JSON(w, results)
+}
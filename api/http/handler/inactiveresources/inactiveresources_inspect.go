@@ -0,0 +1,68 @@
+package inactiveresources
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/gc"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id InactiveResourcesInspect
+// @summary List the inactive resources of an environment
+// @description List the stopped containers older than a number of days, the images unused by any container,
+// @description the volumes not mounted anywhere and the stopped stacks inactive since a date.
+// @description **Access policy**: administrator
+// @tags inactive_resources
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param containerAgeDays query int false "Minimum number of days since a container stopped, defaults to 7"
+// @param stackInactiveSince query int false "Unix timestamp before which a stopped stack was last deployed, defaults to 30 days ago"
+// @success 200 {object} gc.Report "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /inactive_resources/{id} [get]
+func (handler *Handler) inactiveResourcesInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, httpErr := handler.retrieveEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	options, err := retrieveOptions(r)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameters", err)
+	}
+
+	report, err := handler.GCService.EndpointReport(r.Context(), endpoint, options)
+	if err != nil {
+		return httperror.InternalServerError("Unable to list the inactive resources of the environment", err)
+	}
+
+	return response.JSON(w, report)
+}
+
+func (handler *Handler) retrieveEndpoint(r *http.Request) (*portainer.Endpoint, *httperror.HandlerError) {
+	endpointID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, httperror.BadRequest("Invalid environment identifier route variable", err)
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	if !gc.IsSupported(endpoint) {
+		return nil, httperror.BadRequest("Inactive resources are only reported for non-Edge Docker environments", errors.New("unsupported environment type"))
+	}
+
+	return endpoint, nil
+}
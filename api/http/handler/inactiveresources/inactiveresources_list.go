@@ -0,0 +1,38 @@
+package inactiveresources
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id InactiveResourcesList
+// @summary List the inactive resources of all the environments
+// @description List, for each Docker environment, the stopped containers older than a number of days, the images unused
+// @description by any container, the volumes not mounted anywhere and the stopped stacks inactive since a date.
+// @description The environments that cannot be reached are reported with an error.
+// @description **Access policy**: administrator
+// @tags inactive_resources
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param containerAgeDays query int false "Minimum number of days since a container stopped, defaults to 7"
+// @param stackInactiveSince query int false "Unix timestamp before which a stopped stack was last deployed, defaults to 30 days ago"
+// @success 200 {array} gc.Report "Success"
+// @failure 400 "Invalid request"
+// @failure 500 "Server error"
+// @router /inactive_resources [get]
+func (handler *Handler) inactiveResourcesList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	options, err := retrieveOptions(r)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameters", err)
+	}
+
+	reports, err := handler.GCService.FleetReport(r.Context(), options)
+	if err != nil {
+		return httperror.InternalServerError("Unable to list the inactive resources", err)
+	}
+
+	return response.JSON(w, reports)
+}
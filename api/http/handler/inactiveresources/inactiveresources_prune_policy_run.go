@@ -0,0 +1,55 @@
+package inactiveresources
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id InactiveResourcesPrunePolicyRun
+// @summary Run the prune policy of an environment
+// @description Remove the unused resources of an environment selected by its prune policy, or only report them with a dry run.
+// @description The run is recorded in the prune history of the environment along with the reclaimed space.
+// @description **Access policy**: administrator
+// @tags inactive_resources
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param dryRun query bool false "Only report the resources that would be removed"
+// @success 200 {object} portainer.PruneRun "Success"
+// @failure 400 "Invalid request or the environment has no prune policy"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /inactive_resources/{id}/prune_policy/run [post]
+func (handler *Handler) prunePolicyRun(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, httpErr := handler.retrieveEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	dryRun, err := request.RetrieveBooleanQueryParameter(r, "dryRun", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: dryRun", err)
+	}
+
+	if endpoint.PrunePolicy == nil {
+		return httperror.BadRequest("The environment has no prune policy", errors.New("missing prune policy"))
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve user authentication token", err)
+	}
+
+	run, err := handler.GCService.Prune(r.Context(), endpoint, dryRun, tokenData.Username)
+	if err != nil {
+		return httperror.InternalServerError("Unable to prune the environment", err)
+	}
+
+	return response.JSON(w, run)
+}
@@ -0,0 +1,109 @@
+package inactiveresources
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/robfig/cron/v3"
+)
+
+type prunePolicyUpdatePayload struct {
+	// Whether the policy is scheduled
+	Enabled bool `example:"true"`
+	// Cron expression of the scheduled runs, required when the policy is enabled
+	CronExpression string `example:"0 4 * * *"`
+	// Remove the dangling images created more than the given number of days ago, 0 keeps them
+	DanglingImagesAgeDays int `example:"7"`
+	// Remove the containers stopped for more than the given number of days, 0 keeps them
+	StoppedContainersAgeDays int `example:"14"`
+	// Remove the networks that no container is connected to
+	UnusedNetworks bool `example:"true"`
+	// Remove the volumes that no container mounts
+	UnusedVolumes bool `example:"false"`
+	// Only report the resources the scheduled runs would remove
+	DryRun bool `example:"false"`
+}
+
+func (payload *prunePolicyUpdatePayload) Validate(r *http.Request) error {
+	if payload.Enabled {
+		if _, err := cron.ParseStandard(payload.CronExpression); err != nil {
+			return errors.New("invalid cron expression")
+		}
+	}
+
+	if payload.DanglingImagesAgeDays < 0 || payload.StoppedContainersAgeDays < 0 {
+		return errors.New("invalid age, it must be a positive number of days")
+	}
+
+	if payload.DanglingImagesAgeDays == 0 && payload.StoppedContainersAgeDays == 0 && !payload.UnusedNetworks && !payload.UnusedVolumes {
+		return errors.New("the policy does not remove any resource")
+	}
+
+	return nil
+}
+
+// @id InactiveResourcesPrunePolicyUpdate
+// @summary Update the prune policy of an environment
+// @description Set which unused resources of an environment are removed by its prune policy and when the policy runs.
+// @description The dangling images and the stopped containers are removed once they are older than a number of days.
+// @description **Access policy**: administrator
+// @tags inactive_resources
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @param body body prunePolicyUpdatePayload true "Prune policy"
+// @success 200 {object} portainer.EndpointPrunePolicy "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /inactive_resources/{id}/prune_policy [put]
+func (handler *Handler) prunePolicyUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, httpErr := handler.retrieveEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	var payload prunePolicyUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	policy := &portainer.EndpointPrunePolicy{
+		Enabled:                  payload.Enabled,
+		CronExpression:           payload.CronExpression,
+		DanglingImagesAgeDays:    payload.DanglingImagesAgeDays,
+		StoppedContainersAgeDays: payload.StoppedContainersAgeDays,
+		UnusedNetworks:           payload.UnusedNetworks,
+		UnusedVolumes:            payload.UnusedVolumes,
+		DryRun:                   payload.DryRun,
+	}
+
+	err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		var err error
+		endpoint, err = tx.Endpoint().Endpoint(endpoint.ID)
+		if err != nil {
+			return err
+		}
+
+		endpoint.PrunePolicy = policy
+
+		return tx.Endpoint().UpdateEndpoint(endpoint.ID, endpoint)
+	})
+	if err != nil {
+		return httperror.InternalServerError("Unable to persist the prune policy inside the database", err)
+	}
+
+	if err := handler.GCService.SchedulePrunePolicy(endpoint); err != nil {
+		return httperror.InternalServerError("Unable to schedule the prune policy", err)
+	}
+
+	return response.JSON(w, policy)
+}
@@ -0,0 +1,40 @@
+package inactiveresources
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api/internal/gc"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id InactiveResourcesPruneRunsList
+// @summary List the prune runs of an environment
+// @description List the runs of the prune policy of an environment, most recent first, with the removed resources
+// @description and the reclaimed space. Only the latest runs are kept.
+// @description **Access policy**: administrator
+// @tags inactive_resources
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Environment(Endpoint) identifier"
+// @success 200 {array} portainer.PruneRun "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /inactive_resources/{id}/prune_runs [get]
+func (handler *Handler) pruneRunsList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, httpErr := handler.retrieveEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	runs, err := handler.DataStore.PruneRun().ReadAllByEndpointID(endpoint.ID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the prune runs from the database", err)
+	}
+
+	gc.SortPruneRuns(runs)
+
+	return response.JSON(w, runs)
+}
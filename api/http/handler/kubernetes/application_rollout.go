@@ -0,0 +1,140 @@
+package kubernetes
+
+import (
+	"errors"
+	"net/http"
+
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
+	"github.com/portainer/portainer/api/kubernetes/cli"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+	"github.com/rs/zerolog/log"
+)
+
+// @id RestartKubernetesApplication
+// @summary Restart a Kubernetes application
+// @description Restart the pods of a deployment, in the same way as kubectl rollout restart.
+// @description **Access policy**: Authenticated user.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @param id path int true "Environment identifier"
+// @param namespace path string true "Namespace of the application"
+// @param name path string true "Name of the deployment"
+// @success 204 "Success"
+// @failure 403 "Unauthorized access or operation not allowed."
+// @failure 404 "Unable to find the deployment."
+// @failure 500 "Server error occurred while attempting to restart the deployment."
+// @router /kubernetes/{id}/namespaces/{namespace}/applications/{name}/restart [post]
+func (handler *Handler) restartKubernetesApplication(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, name, kcl, httpErr := handler.prepareApplicationRollout(r, "restartKubernetesApplication")
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if err := kcl.RestartDeployment(namespace, name); err != nil {
+		return configurationErrorResponse(err, "restartKubernetesApplication", namespace, name, "Unable to restart the deployment")
+	}
+
+	return response.Empty(w)
+}
+
+// @id ScaleKubernetesApplication
+// @summary Scale a Kubernetes application
+// @description Set the number of replicas of a deployment.
+// @description **Access policy**: Authenticated user.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @accept json
+// @param id path int true "Environment identifier"
+// @param namespace path string true "Namespace of the application"
+// @param name path string true "Name of the deployment"
+// @param body body kubernetes.K8sApplicationScalePayload true "Number of replicas"
+// @success 204 "Success"
+// @failure 400 "Invalid request payload."
+// @failure 403 "Unauthorized access or operation not allowed."
+// @failure 404 "Unable to find the deployment."
+// @failure 500 "Server error occurred while attempting to scale the deployment."
+// @router /kubernetes/{id}/namespaces/{namespace}/applications/{name}/scale [put]
+func (handler *Handler) scaleKubernetesApplication(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload models.K8sApplicationScalePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		log.Error().Err(err).Str("context", "scaleKubernetesApplication").Msg("Unable to decode and validate the request payload")
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	namespace, name, kcl, httpErr := handler.prepareApplicationRollout(r, "scaleKubernetesApplication")
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if err := kcl.ScaleDeployment(namespace, name, *payload.Replicas); err != nil {
+		return configurationErrorResponse(err, "scaleKubernetesApplication", namespace, name, "Unable to scale the deployment")
+	}
+
+	return response.Empty(w)
+}
+
+// @id RollbackKubernetesApplication
+// @summary Roll back a Kubernetes application
+// @description Restore the pod template of a previous revision of a deployment, in the same way as kubectl rollout undo.
+// @description **Access policy**: Authenticated user.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @accept json
+// @param id path int true "Environment identifier"
+// @param namespace path string true "Namespace of the application"
+// @param name path string true "Name of the deployment"
+// @param body body kubernetes.K8sApplicationRollbackPayload true "Revision to roll back to"
+// @success 204 "Success"
+// @failure 400 "Invalid request payload."
+// @failure 403 "Unauthorized access or operation not allowed."
+// @failure 404 "Unable to find the deployment or its revision."
+// @failure 500 "Server error occurred while attempting to roll back the deployment."
+// @router /kubernetes/{id}/namespaces/{namespace}/applications/{name}/rollback [post]
+func (handler *Handler) rollbackKubernetesApplication(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload models.K8sApplicationRollbackPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		log.Error().Err(err).Str("context", "rollbackKubernetesApplication").Msg("Unable to decode and validate the request payload")
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	namespace, name, kcl, httpErr := handler.prepareApplicationRollout(r, "rollbackKubernetesApplication")
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if err := kcl.RollbackDeployment(namespace, name, payload.Revision); err != nil {
+		if errors.Is(err, cli.ErrDeploymentRevisionNotFound) {
+			return httperror.NotFound(err.Error(), err)
+		}
+
+		return configurationErrorResponse(err, "rollbackKubernetesApplication", namespace, name, "Unable to roll back the deployment")
+	}
+
+	return response.Empty(w)
+}
+
+// prepareApplicationRollout returns the namespace and the name of the application of the request, with the
+// Kubernetes client of the user so that the rollout actions are subject to their permissions
+func (handler *Handler) prepareApplicationRollout(r *http.Request, context string) (string, string, *cli.KubeClient, *httperror.HandlerError) {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		log.Error().Err(err).Str("context", context).Msg("Unable to retrieve namespace identifier route variable")
+		return "", "", nil, httperror.BadRequest("Unable to retrieve namespace identifier route variable", err)
+	}
+
+	name, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		log.Error().Err(err).Str("context", context).Str("namespace", namespace).Msg("Unable to retrieve application name route variable")
+		return "", "", nil, httperror.BadRequest("Unable to retrieve application name route variable", err)
+	}
+
+	kcl, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Str("context", context).Str("namespace", namespace).Str("application", name).Msg("Unable to get a Kubernetes client for the user")
+		return "", "", nil, httperror.InternalServerError("Unable to get a Kubernetes client for the user", httpErr)
+	}
+
+	return namespace, name, kcl, nil
+}
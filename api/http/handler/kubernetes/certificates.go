@@ -0,0 +1,114 @@
+package kubernetes
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	kcli "github.com/portainer/portainer/api/kubernetes/cli"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+	"github.com/rs/zerolog/log"
+)
+
+// @id GetKubernetesCertificates
+// @summary Get the ingress TLS certificates of all the Kubernetes environments
+// @description Get the certificates held by the TLS secrets of the ingresses of every Kubernetes environment that is up, sorted by expiry date.
+// @description Edge environments are not included as reaching them requires an open tunnel.
+// @description Certificates expiring within the given number of days are flagged as expiring soon and reported in the server logs.
+// @description **Access policy**: Administrator.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @produce json
+// @param expiringWithinDays query int false "Number of days before the expiry of a certificate during which it is flagged as expiring soon. Defaults to 30"
+// @param expiringOnly query boolean false "Only return the certificates that are expired or expiring soon"
+// @success 200 {array} models.K8sIngressCertificate "Success"
+// @failure 400 "Invalid query parameters."
+// @failure 403 "Permission denied."
+// @failure 500 "Server error occurred while attempting to retrieve the environments."
+// @router /kubernetes/certificates [get]
+func (handler *Handler) getKubernetesCertificates(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	expiringWithinDays, err := request.RetrieveNumericQueryParameter(r, "expiringWithinDays", true)
+	if err != nil || expiringWithinDays < 0 {
+		log.Error().Err(err).Str("context", "getKubernetesCertificates").Msg("Invalid expiringWithinDays query parameter")
+		return httperror.BadRequest("Invalid expiringWithinDays query parameter", err)
+	}
+
+	expiryWarning := time.Duration(expiringWithinDays) * 24 * time.Hour
+	if expiringWithinDays == 0 {
+		expiryWarning = kcli.DefaultCertificateExpiryWarning
+	}
+
+	expiringOnly, err := request.RetrieveBooleanQueryParameter(r, "expiringOnly", true)
+	if err != nil {
+		log.Error().Err(err).Str("context", "getKubernetesCertificates").Msg("Invalid expiringOnly query parameter")
+		return httperror.BadRequest("Invalid expiringOnly query parameter", err)
+	}
+
+	endpoints, err := handler.DataStore.Endpoint().Endpoints()
+	if err != nil {
+		log.Error().Err(err).Str("context", "getKubernetesCertificates").Msg("Unable to retrieve the environments from the database")
+		return httperror.InternalServerError("Unable to retrieve the environments from the database", err)
+	}
+
+	results := []models.K8sIngressCertificate{}
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if !endpointutils.IsKubernetesEndpoint(endpoint) || endpointutils.IsEdgeEndpoint(endpoint) || endpoint.Status != portainer.EndpointStatusUp {
+			continue
+		}
+
+		certificates, err := handler.getEndpointCertificates(endpoint, expiryWarning)
+		if err != nil {
+			log.Warn().Err(err).Str("context", "getKubernetesCertificates").Str("endpoint", endpoint.Name).Msg("Unable to retrieve the ingress certificates of the environment")
+			continue
+		}
+
+		for _, certificate := range certificates {
+			if expiringOnly && !certificate.Certificate.ExpiringSoon {
+				continue
+			}
+
+			results = append(results, certificate)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Certificate.NotAfter.Before(results[j].Certificate.NotAfter)
+	})
+
+	return response.JSON(w, results)
+}
+
+func (handler *Handler) getEndpointCertificates(endpoint *portainer.Endpoint, expiryWarning time.Duration) ([]models.K8sIngressCertificate, error) {
+	cli, err := handler.KubernetesClientFactory.GetPrivilegedKubeClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates, err := cli.GetIngressCertificates(expiryWarning)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range certificates {
+		certificates[i].EndpointID = int(endpoint.ID)
+		certificates[i].EndpointName = endpoint.Name
+
+		if certificates[i].Certificate.ExpiringSoon {
+			log.Warn().
+				Str("endpoint", endpoint.Name).
+				Str("namespace", certificates[i].Namespace).
+				Str("ingress", certificates[i].IngressName).
+				Str("secret", certificates[i].SecretName).
+				Time("not_after", certificates[i].Certificate.NotAfter).
+				Msg("ingress TLS certificate is expired or expiring soon")
+		}
+	}
+
+	return certificates, nil
+}
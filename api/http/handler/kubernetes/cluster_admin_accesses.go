@@ -0,0 +1,56 @@
+package kubernetes
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	kubeproxy "github.com/portainer/portainer/api/http/proxy/factory/kubernetes"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id GetKubernetesClusterAdminAccesses
+// @summary Get the Kubernetes requests proxied with the cluster-admin token
+// @description Get the most recent Kubernetes requests that were proxied with the cluster-admin token instead of the service account of the user, most recent first.
+// @description Accesses are only recorded while the EnableKubernetesClusterAdminAudit setting is enabled and are kept in memory; the server logs hold the full record.
+// @description **Access policy**: Administrator.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @produce json
+// @param endpointId query int false "Only return the accesses to this environment"
+// @param userId query int false "Only return the accesses made by this user"
+// @success 200 {array} kubeproxy.ClusterAdminAccess "Success"
+// @failure 400 "Invalid query parameters."
+// @failure 403 "Permission denied."
+// @router /kubernetes/cluster_admin_accesses [get]
+func (handler *Handler) getKubernetesClusterAdminAccesses(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericQueryParameter(r, "endpointId", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid endpointId query parameter", err)
+	}
+
+	userID, err := request.RetrieveNumericQueryParameter(r, "userId", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid userId query parameter", err)
+	}
+
+	accesses := make([]kubeproxy.ClusterAdminAccess, 0)
+	if handler.ClusterAdminAuditLog == nil {
+		return response.JSON(w, accesses)
+	}
+
+	for _, access := range handler.ClusterAdminAuditLog.Accesses() {
+		if endpointID != 0 && access.EndpointID != portainer.EndpointID(endpointID) {
+			continue
+		}
+
+		if userID != 0 && access.UserID != portainer.UserID(userID) {
+			continue
+		}
+
+		accesses = append(accesses, access)
+	}
+
+	return response.JSON(w, accesses)
+}
@@ -26,6 +26,8 @@ import (
 // @produce application/json, application/yaml
 // @param ids query []int false "will include only these environments(endpoints)"
 // @param excludeIds query []int false "will exclude these environments(endpoints)"
+// @param namespaces query bool false "create one context per namespace the user can access instead of one context per environment"
+// @param exec query bool false "retrieve the token through an exec plugin calling /kubernetes/config/credential, the kubeconfig then keeps working once the token expires. The plugin requires curl and a Portainer access token in the PORTAINER_API_KEY environment variable"
 // @success 200 {object} interface{} "Success"
 // @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
 // @failure 401 "Unauthorized access - the user is not authenticated or does not have the necessary permissions. Ensure that you have provided a valid API key or JWT token, and that you have the required permissions."
@@ -34,6 +36,16 @@ import (
 // @failure 500 "Server error occurred while attempting to generate the kubeconfig file."
 // @router /kubernetes/config [get]
 func (handler *Handler) getKubernetesConfig(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespaceContexts, err := request.RetrieveBooleanQueryParameter(r, "namespaces", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: namespaces", err)
+	}
+
+	execCredential, err := request.RetrieveBooleanQueryParameter(r, "exec", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: exec", err)
+	}
+
 	tokenData, err := security.RetrieveTokenData(r)
 	if err != nil {
 		log.Error().Err(err).Str("context", "getKubernetesConfig").Msg("Permission denied to access environment")
@@ -59,6 +71,14 @@ func (handler *Handler) getKubernetesConfig(w http.ResponseWriter, r *http.Reque
 
 	config := handler.buildConfig(r, tokenData, bearerToken, endpoints, false)
 
+	if namespaceContexts {
+		handler.useNamespaceContexts(config, tokenData, endpoints)
+	}
+
+	if execCredential {
+		handler.useExecCredential(config, r)
+	}
+
 	return writeFileContent(w, r, endpoints, tokenData, config)
 }
 
@@ -215,3 +235,62 @@ func writeFileContent(w http.ResponseWriter, r *http.Request, endpoints []portai
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; %s.json", filenameBase))
 	return response.JSON(w, config)
 }
+
+// useNamespaceContexts replaces the context of each environment with one context per namespace the user can access.
+// The context of an environment is kept when its namespaces cannot be retrieved.
+func (handler *Handler) useNamespaceContexts(config *clientV1.Config, tokenData *portainer.TokenData, endpoints []portainer.Endpoint) {
+	contexts := make([]clientV1.NamedContext, 0, len(config.Contexts))
+
+	for idx, endpoint := range endpoints {
+		namespaces, err := handler.userNamespaceNames(tokenData, &endpoint)
+		if err != nil || len(namespaces) == 0 {
+			log.Warn().Err(err).Str("context", "getKubernetesConfig").Str("environment", endpoint.Name).Msg("Unable to retrieve the namespaces of the user, the environment context is kept")
+
+			contexts = append(contexts, config.Contexts[idx])
+
+			continue
+		}
+
+		for _, namespace := range namespaces {
+			namedContext := config.Contexts[idx]
+			namedContext.Name += "-" + namespace
+			namedContext.Context.Namespace = namespace
+
+			contexts = append(contexts, namedContext)
+		}
+	}
+
+	config.Contexts = contexts
+	config.CurrentContext = contexts[0].Name
+}
+
+func (handler *Handler) userNamespaceNames(tokenData *portainer.TokenData, endpoint *portainer.Endpoint) ([]string, error) {
+	pcli, err := handler.KubernetesClientFactory.GetPrivilegedKubeClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return pcli.GetUserNamespaceNames(int(tokenData.ID), tokenData.Role == portainer.AdministratorRole, endpoint.Kubernetes.Configuration.RestrictDefaultNamespace)
+}
+
+// useExecCredential replaces the token of each user with an exec plugin retrieving a fresh token from Portainer
+func (handler *Handler) useExecCredential(config *clientV1.Config, r *http.Request) {
+	curlOptions := "-fsS"
+	if !handler.kubeClusterAccessService.IsSecure() {
+		curlOptions += "k"
+	}
+
+	command := fmt.Sprintf(`curl %s -H "X-API-Key: ${%s}" '%s'`, curlOptions, kubeconfigAPIKeyEnv, handler.kubeClusterAccessService.GetCredentialURL(r.Host))
+
+	for idx := range config.AuthInfos {
+		config.AuthInfos[idx].AuthInfo = clientV1.AuthInfo{
+			Exec: &clientV1.ExecConfig{
+				APIVersion:      execCredentialAPIVersion,
+				Command:         "sh",
+				Args:            []string{"-c", command},
+				InstallHint:     fmt.Sprintf("curl is required and the %s environment variable must contain a Portainer access token", kubeconfigAPIKeyEnv),
+				InteractiveMode: clientV1.NeverExecInteractiveMode,
+			},
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package kubernetes
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthenticationv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
+)
+
+const (
+	// execCredentialAPIVersion is the version of the credentials exchanged with the kubeconfig exec plugin
+	execCredentialAPIVersion = "client.authentication.k8s.io/v1"
+	// kubeconfigAPIKeyEnv is the environment variable holding the access token used by the kubeconfig exec plugin
+	kubeconfigAPIKeyEnv = "PORTAINER_API_KEY"
+)
+
+// @id GetKubernetesConfigCredential
+// @summary Generate a kubeconfig credential
+// @description Generate a token for the Kubernetes environments in the format expected by a kubeconfig exec plugin.
+// @description It is called by the kubeconfig files generated with the exec option each time their token expires.
+// @description **Access policy**: Authenticated user.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @produce json
+// @success 200 {object} interface{} "Success"
+// @failure 401 "Unauthorized access - the user is not authenticated."
+// @failure 403 "Permission denied."
+// @failure 500 "Server error occurred while attempting to generate the token."
+// @router /kubernetes/config/credential [get]
+func (handler *Handler) getKubernetesConfigCredential(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	// The expiry is computed before the token is generated so that it is never reported later than the actual one
+	now := time.Now()
+
+	bearerToken, err := handler.JwtService.GenerateTokenForKubeconfig(tokenData)
+	if err != nil {
+		return httperror.InternalServerError("Unable to generate JWT token", err)
+	}
+
+	settings, err := handler.DataStore.Settings().Settings()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the settings from the database", err)
+	}
+
+	credential := clientauthenticationv1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{APIVersion: execCredentialAPIVersion, Kind: "ExecCredential"},
+		Status:   &clientauthenticationv1.ExecCredentialStatus{Token: bearerToken},
+	}
+
+	// The token never expires when no expiry is set
+	if expiry, err := time.ParseDuration(settings.KubeconfigExpiry); err == nil && expiry > 0 {
+		expiresAt := metav1.NewTime(now.Add(expiry))
+		credential.Status.ExpirationTimestamp = &expiresAt
+	}
+
+	return response.JSON(w, credential)
+}
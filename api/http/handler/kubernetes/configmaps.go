@@ -1,9 +1,12 @@
 package kubernetes
 
 import (
+	"errors"
 	"net/http"
 
 	models "github.com/portainer/portainer/api/http/models/kubernetes"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/kubernetes/cli"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -72,6 +75,213 @@ func (handler *Handler) getKubernetesConfigMap(w http.ResponseWriter, r *http.Re
 	return response.JSON(w, configMapWithApplications)
 }
 
+// @id CreateKubernetesConfigMap
+// @summary Create a ConfigMap
+// @description Create a ConfigMap in a given namespace. The creation is recorded in the change history of the ConfigMap.
+// @description **Access policy**: Authenticated user with access to the namespace.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @accept json
+// @produce json
+// @param id path int true "Environment identifier"
+// @param namespace path string true "The namespace name where the configmap will be created"
+// @param body body models.K8sConfigMapPayload true "ConfigMap details"
+// @success 200 {object} models.K8sConfigMap "Success"
+// @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
+// @failure 403 "Permission denied - the user does not have access to the namespace."
+// @failure 409 "A configmap with the same name already exists in the namespace."
+// @failure 500 "Server error occurred while attempting to create the configmap."
+// @router /kubernetes/{id}/namespaces/{namespace}/configmaps [post]
+func (handler *Handler) createKubernetesConfigMap(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		log.Error().Err(err).Str("context", "createKubernetesConfigMap").Msg("Unable to retrieve namespace identifier route variable")
+		return httperror.BadRequest("Unable to retrieve namespace identifier route variable", err)
+	}
+
+	var payload models.K8sConfigMapPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		log.Error().Err(err).Str("context", "createKubernetesConfigMap").Str("namespace", namespace).Msg("Invalid request payload")
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	if payload.Name == "" {
+		return httperror.BadRequest("Invalid request payload", errors.New("missing configmap name from the request payload"))
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.Forbidden("Unable to retrieve user details from authentication token", err)
+	}
+
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Str("context", "createKubernetesConfigMap").Str("namespace", namespace).Msg("Unable to get a Kubernetes client for the user")
+		return httperror.InternalServerError("Unable to get a Kubernetes client for the user", httpErr)
+	}
+
+	configMap, err := cli.CreateConfigMap(namespace, payload, tokenData.Username)
+	if err != nil {
+		return configurationErrorResponse(err, "createKubernetesConfigMap", namespace, payload.Name, "Unable to create configMap")
+	}
+
+	return response.JSON(w, configMap)
+}
+
+// @id UpdateKubernetesConfigMap
+// @summary Update a ConfigMap
+// @description Replace the data of a ConfigMap. The update is rejected when the ConfigMap was modified since the given resource version.
+// @description The changed keys are recorded in the change history of the ConfigMap.
+// @description **Access policy**: Authenticated user with access to the namespace.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @accept json
+// @produce json
+// @param id path int true "Environment identifier"
+// @param namespace path string true "The namespace name where the configmap is located"
+// @param configmap path string true "The configmap name"
+// @param body body models.K8sConfigMapPayload true "ConfigMap details"
+// @success 200 {object} models.K8sConfigMap "Success"
+// @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
+// @failure 403 "Permission denied - the user does not have access to the namespace."
+// @failure 404 "Unable to find a configmap with the specified name in the given namespace."
+// @failure 409 "The configmap was modified since the given resource version."
+// @failure 500 "Server error occurred while attempting to update the configmap."
+// @router /kubernetes/{id}/namespaces/{namespace}/configmaps/{configmap} [put]
+func (handler *Handler) updateKubernetesConfigMap(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		log.Error().Err(err).Str("context", "updateKubernetesConfigMap").Msg("Unable to retrieve namespace identifier route variable")
+		return httperror.BadRequest("Unable to retrieve namespace identifier route variable", err)
+	}
+
+	configMapName, err := request.RetrieveRouteVariableValue(r, "configmap")
+	if err != nil {
+		log.Error().Err(err).Str("context", "updateKubernetesConfigMap").Str("namespace", namespace).Msg("Unable to retrieve configMap identifier route variable")
+		return httperror.BadRequest("Unable to retrieve configMap identifier route variable", err)
+	}
+
+	var payload models.K8sConfigMapPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		log.Error().Err(err).Str("context", "updateKubernetesConfigMap").Str("namespace", namespace).Str("configMap", configMapName).Msg("Invalid request payload")
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	if payload.ResourceVersion == "" {
+		return httperror.BadRequest("Invalid request payload", errors.New("missing resource version from the request payload"))
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.Forbidden("Unable to retrieve user details from authentication token", err)
+	}
+
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Str("context", "updateKubernetesConfigMap").Str("namespace", namespace).Str("configMap", configMapName).Msg("Unable to get a Kubernetes client for the user")
+		return httperror.InternalServerError("Unable to get a Kubernetes client for the user", httpErr)
+	}
+
+	configMap, err := cli.UpdateConfigMap(namespace, configMapName, payload, tokenData.Username)
+	if err != nil {
+		return configurationErrorResponse(err, "updateKubernetesConfigMap", namespace, configMapName, "Unable to update configMap")
+	}
+
+	return response.JSON(w, configMap)
+}
+
+// @id DiffKubernetesConfigMap
+// @summary Preview the changes of a ConfigMap update
+// @description Compare the data of a ConfigMap with the given data, without applying the changes.
+// @description **Access policy**: Authenticated user with access to the namespace.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @accept json
+// @produce json
+// @param id path int true "Environment identifier"
+// @param namespace path string true "The namespace name where the configmap is located"
+// @param configmap path string true "The configmap name"
+// @param body body models.K8sConfigMapPayload true "ConfigMap details"
+// @success 200 {object} models.K8sConfigurationDiff "Success"
+// @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
+// @failure 403 "Permission denied - the user does not have access to the namespace."
+// @failure 404 "Unable to find a configmap with the specified name in the given namespace."
+// @failure 500 "Server error occurred while attempting to compare the configmap."
+// @router /kubernetes/{id}/namespaces/{namespace}/configmaps/{configmap}/diff [post]
+func (handler *Handler) diffKubernetesConfigMap(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		log.Error().Err(err).Str("context", "diffKubernetesConfigMap").Msg("Unable to retrieve namespace identifier route variable")
+		return httperror.BadRequest("Unable to retrieve namespace identifier route variable", err)
+	}
+
+	configMapName, err := request.RetrieveRouteVariableValue(r, "configmap")
+	if err != nil {
+		log.Error().Err(err).Str("context", "diffKubernetesConfigMap").Str("namespace", namespace).Msg("Unable to retrieve configMap identifier route variable")
+		return httperror.BadRequest("Unable to retrieve configMap identifier route variable", err)
+	}
+
+	var payload models.K8sConfigMapPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		log.Error().Err(err).Str("context", "diffKubernetesConfigMap").Str("namespace", namespace).Str("configMap", configMapName).Msg("Invalid request payload")
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Str("context", "diffKubernetesConfigMap").Str("namespace", namespace).Str("configMap", configMapName).Msg("Unable to get a Kubernetes client for the user")
+		return httperror.InternalServerError("Unable to get a Kubernetes client for the user", httpErr)
+	}
+
+	diff, err := cli.DiffConfigMap(namespace, configMapName, payload)
+	if err != nil {
+		return configurationErrorResponse(err, "diffKubernetesConfigMap", namespace, configMapName, "Unable to compare configMap")
+	}
+
+	return response.JSON(w, diff)
+}
+
+// @id DeleteKubernetesConfigMap
+// @summary Delete a ConfigMap
+// @description Delete a ConfigMap from a given namespace.
+// @description **Access policy**: Authenticated user with access to the namespace.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @param id path int true "Environment identifier"
+// @param namespace path string true "The namespace name where the configmap is located"
+// @param configmap path string true "The configmap name"
+// @success 204 "Success"
+// @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
+// @failure 403 "Permission denied - the user does not have access to the namespace."
+// @failure 404 "Unable to find a configmap with the specified name in the given namespace."
+// @failure 500 "Server error occurred while attempting to delete the configmap."
+// @router /kubernetes/{id}/namespaces/{namespace}/configmaps/{configmap} [delete]
+func (handler *Handler) deleteKubernetesConfigMap(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		log.Error().Err(err).Str("context", "deleteKubernetesConfigMap").Msg("Unable to retrieve namespace identifier route variable")
+		return httperror.BadRequest("Unable to retrieve namespace identifier route variable", err)
+	}
+
+	configMapName, err := request.RetrieveRouteVariableValue(r, "configmap")
+	if err != nil {
+		log.Error().Err(err).Str("context", "deleteKubernetesConfigMap").Str("namespace", namespace).Msg("Unable to retrieve configMap identifier route variable")
+		return httperror.BadRequest("Unable to retrieve configMap identifier route variable", err)
+	}
+
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Str("context", "deleteKubernetesConfigMap").Str("namespace", namespace).Str("configMap", configMapName).Msg("Unable to get a Kubernetes client for the user")
+		return httperror.InternalServerError("Unable to get a Kubernetes client for the user", httpErr)
+	}
+
+	if err := cli.DeleteConfigMap(namespace, configMapName); err != nil {
+		return configurationErrorResponse(err, "deleteKubernetesConfigMap", namespace, configMapName, "Unable to delete configMap")
+	}
+
+	return response.Empty(w)
+}
+
 // @id GetAllKubernetesConfigMaps
 // @summary Get a list of ConfigMaps
 // @description Get a list of ConfigMaps across all namespaces in the cluster. For non-admin users, it will only return ConfigMaps based on the namespaces that they have access to.
@@ -157,3 +367,26 @@ func (handler *Handler) getAllKubernetesConfigMaps(r *http.Request) ([]models.K8
 
 	return configMaps, nil
 }
+
+// configurationErrorResponse maps the errors of the ConfigMap and Secret operations to an HTTP error
+func configurationErrorResponse(err error, context, namespace, name, message string) *httperror.HandlerError {
+	logger := log.Error().Err(err).Str("context", context).Str("namespace", namespace).Str("name", name)
+
+	switch {
+	case errors.Is(err, cli.ErrNamespaceAccessDenied), k8serrors.IsUnauthorized(err), k8serrors.IsForbidden(err):
+		logger.Msg("Unauthorized access to the Kubernetes API")
+		return httperror.Forbidden("Unauthorized access to the Kubernetes API", err)
+	case errors.Is(err, cli.ErrConfigurationConflict), k8serrors.IsConflict(err), k8serrors.IsAlreadyExists(err):
+		logger.Msg(message)
+		return httperror.Conflict(message, err)
+	case k8serrors.IsNotFound(err):
+		logger.Msg(message)
+		return httperror.NotFound(message, err)
+	case k8serrors.IsInvalid(err):
+		logger.Msg(message)
+		return httperror.BadRequest(message, err)
+	}
+
+	logger.Msg(message)
+	return httperror.InternalServerError(message, err)
+}
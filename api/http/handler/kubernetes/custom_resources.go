@@ -0,0 +1,146 @@
+package kubernetes
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/portainer/portainer/api/kubernetes/cli"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+const (
+	defaultCustomResourcesPageSize = 100
+	maxCustomResourcesPageSize     = 500
+)
+
+// @id GetKubernetesCustomResourceDefinitions
+// @summary Get the custom resource definitions of the cluster
+// @description Get the custom resource definitions of the cluster, sorted by name.
+// @description **Access policy**: Authenticated user allowed to list the custom resource definitions of the cluster.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @produce json
+// @param id path int true "Environment identifier"
+// @success 200 {array} kubernetes.K8sCustomResourceDefinition "Success"
+// @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
+// @failure 403 "Permission denied - the user is not allowed to list the custom resource definitions."
+// @failure 500 "Server error occurred while attempting to retrieve the custom resource definitions."
+// @router /kubernetes/{id}/custom_resource_definitions [get]
+func (handler *Handler) getKubernetesCustomResourceDefinitions(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	definitions, err := cli.GetCustomResourceDefinitions()
+	if err != nil {
+		return customResourceErrorResponse(err, "getKubernetesCustomResourceDefinitions", "", "", "Unable to retrieve the custom resource definitions")
+	}
+
+	return response.JSON(w, definitions)
+}
+
+// @id GetKubernetesCustomResources
+// @summary Get the resources of a custom resource definition
+// @description Get a page of the resources of a custom resource definition. The namespace is omitted for the cluster scoped
+// @description resources, it can be omitted by the cluster administrators to list the namespaced resources of every namespace.
+// @description **Access policy**: Authenticated user with access to the namespace.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @produce json
+// @param id path int true "Environment identifier"
+// @param namespace path string false "The namespace name"
+// @param crd path string true "Name of the custom resource definition, e.g. certificates.cert-manager.io"
+// @param limit query int false "Maximum number of resources of the page, 100 by default and at most 500"
+// @param continue query string false "Token returned with the previous page"
+// @success 200 {object} kubernetes.K8sCustomResourceList "Success"
+// @failure 400 "Invalid request payload, or the scope of the custom resource definition does not match the request."
+// @failure 403 "Permission denied - the user does not have access to the namespace or to the resources."
+// @failure 404 "Unable to find the custom resource definition."
+// @failure 500 "Server error occurred while attempting to retrieve the resources."
+// @router /kubernetes/{id}/namespaces/{namespace}/custom_resources/{crd} [get]
+// @router /kubernetes/{id}/custom_resources/{crd} [get]
+func (handler *Handler) getKubernetesCustomResources(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, _ := request.RetrieveRouteVariableValue(r, "namespace")
+
+	crd, err := request.RetrieveRouteVariableValue(r, "crd")
+	if err != nil {
+		return httperror.BadRequest("Unable to retrieve the custom resource definition route variable", err)
+	}
+
+	limit, err := request.RetrieveNumericQueryParameter(r, "limit", true)
+	if err != nil || limit < 0 || limit > maxCustomResourcesPageSize {
+		return httperror.BadRequest("Invalid query parameter: limit", errors.New("limit must be between 1 and 500"))
+	} else if limit == 0 {
+		limit = defaultCustomResourcesPageSize
+	}
+
+	continueToken, _ := request.RetrieveQueryParameter(r, "continue", true)
+
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	resources, err := cli.GetCustomResources(crd, namespace, int64(limit), continueToken)
+	if err != nil {
+		return customResourceErrorResponse(err, "getKubernetesCustomResources", namespace, crd, "Unable to retrieve the custom resources")
+	}
+
+	return response.JSON(w, resources)
+}
+
+// @id GetKubernetesCustomResource
+// @summary Get a resource of a custom resource definition
+// @description Get a resource of a custom resource definition along with its full content. The namespace is omitted
+// @description for the cluster scoped resources.
+// @description **Access policy**: Authenticated user with access to the namespace.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @produce json
+// @param id path int true "Environment identifier"
+// @param namespace path string false "The namespace name"
+// @param crd path string true "Name of the custom resource definition, e.g. certificates.cert-manager.io"
+// @param name path string true "Name of the resource"
+// @success 200 {object} kubernetes.K8sCustomResource "Success"
+// @failure 400 "Invalid request payload, or the scope of the custom resource definition does not match the request."
+// @failure 403 "Permission denied - the user does not have access to the namespace or to the resource."
+// @failure 404 "Unable to find the custom resource definition or the resource."
+// @failure 500 "Server error occurred while attempting to retrieve the resource."
+// @router /kubernetes/{id}/namespaces/{namespace}/custom_resources/{crd}/{name} [get]
+// @router /kubernetes/{id}/custom_resources/{crd}/{name} [get]
+func (handler *Handler) getKubernetesCustomResource(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, _ := request.RetrieveRouteVariableValue(r, "namespace")
+
+	crd, err := request.RetrieveRouteVariableValue(r, "crd")
+	if err != nil {
+		return httperror.BadRequest("Unable to retrieve the custom resource definition route variable", err)
+	}
+
+	name, err := request.RetrieveRouteVariableValue(r, "name")
+	if err != nil {
+		return httperror.BadRequest("Unable to retrieve the resource name route variable", err)
+	}
+
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	resource, err := cli.GetCustomResource(crd, namespace, name)
+	if err != nil {
+		return customResourceErrorResponse(err, "getKubernetesCustomResource", namespace, name, "Unable to retrieve the custom resource")
+	}
+
+	return response.JSON(w, resource)
+}
+
+func customResourceErrorResponse(err error, context, namespace, name, message string) *httperror.HandlerError {
+	if errors.Is(err, cli.ErrInvalidCustomResourceScope) {
+		return httperror.BadRequest(message, err)
+	}
+
+	return configurationErrorResponse(err, context, namespace, name, message)
+}
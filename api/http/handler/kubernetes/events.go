@@ -0,0 +1,67 @@
+package kubernetes
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id GetKubernetesEvents
+// @summary Get the events of the cluster
+// @description Get the events of all the namespaces the user has access to, sorted from the oldest to the most recent.
+// @description **Access policy**: Authenticated user.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @produce json
+// @param id path int true "Environment identifier"
+// @param resource query string false "Only return the events of the resources with this name"
+// @success 200 {array} models.K8sEvent "Success"
+// @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
+// @failure 403 "Permission denied - the user is authenticated but does not have the necessary permissions to access the requested resource or perform the specified operation. Check your user roles and permissions."
+// @failure 500 "Server error occurred while attempting to retrieve the events."
+// @router /kubernetes/{id}/events [get]
+func (handler *Handler) getKubernetesEvents(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.respondWithEvents(w, r, "")
+}
+
+// @id GetKubernetesEventsForNamespace
+// @summary Get the events of a namespace
+// @description Get the events of a namespace, sorted from the oldest to the most recent.
+// @description **Access policy**: Authenticated user with access to the namespace.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @produce json
+// @param id path int true "Environment identifier"
+// @param namespace path string true "The namespace name"
+// @param resource query string false "Only return the events of the resources with this name, e.g. a pod or a deployment"
+// @success 200 {array} models.K8sEvent "Success"
+// @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
+// @failure 403 "Permission denied - the user does not have access to the namespace."
+// @failure 500 "Server error occurred while attempting to retrieve the events of the namespace."
+// @router /kubernetes/{id}/namespaces/{namespace}/events [get]
+func (handler *Handler) getKubernetesEventsForNamespace(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		return httperror.BadRequest("Unable to retrieve namespace identifier route variable", err)
+	}
+
+	return handler.respondWithEvents(w, r, namespace)
+}
+
+func (handler *Handler) respondWithEvents(w http.ResponseWriter, r *http.Request, namespace string) *httperror.HandlerError {
+	resource, _ := request.RetrieveQueryParameter(r, "resource", true)
+
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	events, err := cli.GetEvents(namespace, resource)
+	if err != nil {
+		return configurationErrorResponse(err, "getKubernetesEvents", namespace, resource, "Unable to retrieve the events")
+	}
+
+	return response.JSON(w, events)
+}
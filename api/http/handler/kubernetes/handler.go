@@ -9,6 +9,7 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/middlewares"
+	kubeproxy "github.com/portainer/portainer/api/http/proxy/factory/kubernetes"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
 	"github.com/portainer/portainer/api/kubernetes"
@@ -28,6 +29,7 @@ type Handler struct {
 	KubernetesClientFactory  *cli.ClientFactory
 	JwtService               portainer.JWTService
 	kubeClusterAccessService kubernetes.KubeClusterAccessService
+	ClusterAdminAuditLog     *kubeproxy.ClusterAdminAuditLog
 }
 
 // NewHandler creates a handler to process pre-proxied requests to external APIs.
@@ -43,8 +45,14 @@ func NewHandler(bouncer security.BouncerService, authorizationService *authoriza
 
 	kubeRouter := h.PathPrefix("/kubernetes").Subrouter()
 	kubeRouter.Use(bouncer.AuthenticatedAccess)
+	kubeRouter.Handle("/config/credential",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.getKubernetesConfigCredential))).Methods(http.MethodGet)
 	kubeRouter.PathPrefix("/config").Handler(
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.getKubernetesConfig))).Methods(http.MethodGet)
+	kubeRouter.Handle("/certificates",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.getKubernetesCertificates))).Methods(http.MethodGet)
+	kubeRouter.Handle("/cluster_admin_accesses",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.getKubernetesClusterAdminAccesses))).Methods(http.MethodGet)
 
 	// endpoints
 	endpointRouter := kubeRouter.PathPrefix("/{id}").Subrouter()
@@ -59,6 +67,10 @@ func NewHandler(bouncer security.BouncerService, authorizationService *authoriza
 	endpointRouter.Handle("/cluster_role_bindings", httperror.LoggerHandler(h.getAllKubernetesClusterRoleBindings)).Methods(http.MethodGet)
 	endpointRouter.Handle("/configmaps", httperror.LoggerHandler(h.GetAllKubernetesConfigMaps)).Methods(http.MethodGet)
 	endpointRouter.Handle("/configmaps/count", httperror.LoggerHandler(h.getAllKubernetesConfigMapsCount)).Methods(http.MethodGet)
+	endpointRouter.Handle("/events", httperror.LoggerHandler(h.getKubernetesEvents)).Methods(http.MethodGet)
+	endpointRouter.Handle("/custom_resource_definitions", httperror.LoggerHandler(h.getKubernetesCustomResourceDefinitions)).Methods(http.MethodGet)
+	endpointRouter.Handle("/custom_resources/{crd}", httperror.LoggerHandler(h.getKubernetesCustomResources)).Methods(http.MethodGet)
+	endpointRouter.Handle("/custom_resources/{crd}/{name}", httperror.LoggerHandler(h.getKubernetesCustomResource)).Methods(http.MethodGet)
 	endpointRouter.Handle("/dashboard", httperror.LoggerHandler(h.getKubernetesDashboard)).Methods(http.MethodGet)
 	endpointRouter.Handle("/nodes_limits", httperror.LoggerHandler(h.getKubernetesNodesLimits)).Methods(http.MethodGet)
 	endpointRouter.Handle("/max_resource_limits", httperror.LoggerHandler(h.getKubernetesMaxResourceLimits)).Methods(http.MethodGet)
@@ -89,12 +101,23 @@ func NewHandler(bouncer security.BouncerService, authorizationService *authoriza
 	endpointRouter.Handle("/namespaces/{namespace}", httperror.LoggerHandler(h.getKubernetesNamespace)).Methods(http.MethodGet)
 	endpointRouter.Handle("/volumes", httperror.LoggerHandler(h.GetAllKubernetesVolumes)).Methods(http.MethodGet)
 	endpointRouter.Handle("/volumes/count", httperror.LoggerHandler(h.getAllKubernetesVolumesCount)).Methods(http.MethodGet)
+	endpointRouter.Handle("/persistent_volumes", httperror.LoggerHandler(h.getAllKubernetesPersistentVolumes)).Methods(http.MethodGet)
 
 	// namespaces
 	// in the future this piece of code might be in another package (or a few different packages - namespaces/namespace?)
 	// to keep it simple, we've decided to leave it like this.
 	namespaceRouter := endpointRouter.PathPrefix("/namespaces/{namespace}").Subrouter()
+	namespaceRouter.Handle("/applications/{name}/restart", httperror.LoggerHandler(h.restartKubernetesApplication)).Methods(http.MethodPost)
+	namespaceRouter.Handle("/applications/{name}/scale", httperror.LoggerHandler(h.scaleKubernetesApplication)).Methods(http.MethodPut)
+	namespaceRouter.Handle("/applications/{name}/rollback", httperror.LoggerHandler(h.rollbackKubernetesApplication)).Methods(http.MethodPost)
+	namespaceRouter.Handle("/configmaps", httperror.LoggerHandler(h.createKubernetesConfigMap)).Methods(http.MethodPost)
 	namespaceRouter.Handle("/configmaps/{configmap}", httperror.LoggerHandler(h.getKubernetesConfigMap)).Methods(http.MethodGet)
+	namespaceRouter.Handle("/configmaps/{configmap}", httperror.LoggerHandler(h.updateKubernetesConfigMap)).Methods(http.MethodPut)
+	namespaceRouter.Handle("/configmaps/{configmap}", httperror.LoggerHandler(h.deleteKubernetesConfigMap)).Methods(http.MethodDelete)
+	namespaceRouter.Handle("/configmaps/{configmap}/diff", httperror.LoggerHandler(h.diffKubernetesConfigMap)).Methods(http.MethodPost)
+	namespaceRouter.Handle("/events", httperror.LoggerHandler(h.getKubernetesEventsForNamespace)).Methods(http.MethodGet)
+	namespaceRouter.Handle("/custom_resources/{crd}", httperror.LoggerHandler(h.getKubernetesCustomResources)).Methods(http.MethodGet)
+	namespaceRouter.Handle("/custom_resources/{crd}/{name}", httperror.LoggerHandler(h.getKubernetesCustomResource)).Methods(http.MethodGet)
 	namespaceRouter.Handle("/system", bouncer.RestrictedAccess(httperror.LoggerHandler(h.namespacesToggleSystem))).Methods(http.MethodPut)
 	namespaceRouter.Handle("/ingresscontrollers", httperror.LoggerHandler(h.getKubernetesIngressControllersByNamespace)).Methods(http.MethodGet)
 	namespaceRouter.Handle("/ingresscontrollers", httperror.LoggerHandler(h.updateKubernetesIngressControllersByNamespace)).Methods(http.MethodPut)
@@ -102,11 +125,16 @@ func NewHandler(bouncer security.BouncerService, authorizationService *authoriza
 	namespaceRouter.Handle("/ingresses", httperror.LoggerHandler(h.createKubernetesIngress)).Methods(http.MethodPost)
 	namespaceRouter.Handle("/ingresses", httperror.LoggerHandler(h.updateKubernetesIngress)).Methods(http.MethodPut)
 	namespaceRouter.Handle("/ingresses", httperror.LoggerHandler(h.getKubernetesIngresses)).Methods(http.MethodGet)
+	namespaceRouter.Handle("/secrets", httperror.LoggerHandler(h.createKubernetesSecret)).Methods(http.MethodPost)
 	namespaceRouter.Handle("/secrets/{secret}", httperror.LoggerHandler(h.getKubernetesSecret)).Methods(http.MethodGet)
+	namespaceRouter.Handle("/secrets/{secret}", httperror.LoggerHandler(h.updateKubernetesSecret)).Methods(http.MethodPut)
+	namespaceRouter.Handle("/secrets/{secret}", httperror.LoggerHandler(h.deleteKubernetesSecret)).Methods(http.MethodDelete)
+	namespaceRouter.Handle("/secrets/{secret}/diff", httperror.LoggerHandler(h.diffKubernetesSecret)).Methods(http.MethodPost)
 	namespaceRouter.Handle("/services", httperror.LoggerHandler(h.createKubernetesService)).Methods(http.MethodPost)
 	namespaceRouter.Handle("/services", httperror.LoggerHandler(h.updateKubernetesService)).Methods(http.MethodPut)
 	namespaceRouter.Handle("/services", httperror.LoggerHandler(h.getKubernetesServicesByNamespace)).Methods(http.MethodGet)
 	namespaceRouter.Handle("/volumes/{volume}", httperror.LoggerHandler(h.getKubernetesVolume)).Methods(http.MethodGet)
+	namespaceRouter.Handle("/volumes/{volume}/expand", httperror.LoggerHandler(h.expandKubernetesVolume)).Methods(http.MethodPut)
 
 	return h
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/portainer/portainer/api/http/middlewares"
 	models "github.com/portainer/portainer/api/http/models/kubernetes"
 	"github.com/portainer/portainer/api/http/security"
+	kcli "github.com/portainer/portainer/api/kubernetes/cli"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -482,6 +483,7 @@ PayloadLoop:
 // @id GetAllKubernetesClusterIngresses
 // @summary Get kubernetes ingresses at the cluster level
 // @description Get kubernetes ingresses at the cluster level for the provided environment.
+// @description The certificates held by the TLS secrets of the ingresses are resolved, and flagged when they expire within 30 days.
 // @description **Access policy**: Authenticated user.
 // @tags kubernetes
 // @security ApiKeyAuth || jwt
@@ -496,7 +498,7 @@ PayloadLoop:
 // @failure 500 "Server error occurred while attempting to retrieve ingresses."
 // @router /kubernetes/{id}/ingresses [get]
 func (handler *Handler) GetAllKubernetesClusterIngresses(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
-	ingresses, err := handler.getKubernetesClusterIngresses(r)
+	ingresses, err := handler.getKubernetesClusterIngresses(r, true)
 	if err != nil {
 		return err
 	}
@@ -520,7 +522,7 @@ func (handler *Handler) GetAllKubernetesClusterIngresses(w http.ResponseWriter,
 // @failure 500 "Server error occurred while attempting to retrieve ingresses count."
 // @router /kubernetes/{id}/ingresses/count [get]
 func (handler *Handler) getAllKubernetesClusterIngressesCount(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
-	ingresses, err := handler.getKubernetesClusterIngresses(r)
+	ingresses, err := handler.getKubernetesClusterIngresses(r, false)
 	if err != nil {
 		return err
 	}
@@ -528,7 +530,7 @@ func (handler *Handler) getAllKubernetesClusterIngressesCount(w http.ResponseWri
 	return response.JSON(w, len(ingresses))
 }
 
-func (handler *Handler) getKubernetesClusterIngresses(r *http.Request) ([]models.K8sIngressInfo, *httperror.HandlerError) {
+func (handler *Handler) getKubernetesClusterIngresses(r *http.Request, withCertificates bool) ([]models.K8sIngressInfo, *httperror.HandlerError) {
 	withServices, err := request.RetrieveBooleanQueryParameter(r, "withServices", true)
 	if err != nil {
 		log.Error().Err(err).Str("context", "getKubernetesClusterIngresses").Msg("Unable to retrieve withApplications query parameter")
@@ -557,6 +559,10 @@ func (handler *Handler) getKubernetesClusterIngresses(r *http.Request) ([]models
 		return nil, httperror.InternalServerError("Unable to retrieve ingresses from the Kubernetes for a cluster level user", err)
 	}
 
+	if withCertificates {
+		ingresses = cli.CombineIngressesWithCertificates(ingresses, kcli.DefaultCertificateExpiryWarning)
+	}
+
 	if withServices {
 		ingressesWithServices, err := cli.CombineIngressesWithServices(ingresses)
 		if err != nil {
@@ -573,6 +579,7 @@ func (handler *Handler) getKubernetesClusterIngresses(r *http.Request) ([]models
 // @id GetAllKubernetesIngresses
 // @summary Get a list of Ingresses
 // @description Get a list of Ingresses. If namespace is provided, it will return the list of Ingresses in that namespace.
+// @description The certificates held by the TLS secrets of the ingresses are resolved, and flagged when they expire within 30 days.
 // @description **Access policy**: Authenticated user.
 // @tags kubernetes
 // @security ApiKeyAuth || jwt
@@ -609,7 +616,7 @@ func (handler *Handler) getKubernetesIngresses(w http.ResponseWriter, r *http.Re
 		return httperror.InternalServerError("Unable to retrieve ingresses from the Kubernetes for a namespace level user", err)
 	}
 
-	return response.JSON(w, ingresses)
+	return response.JSON(w, cli.CombineIngressesWithCertificates(ingresses, kcli.DefaultCertificateExpiryWarning))
 }
 
 // @id GetKubernetesIngress
@@ -147,7 +147,8 @@ func (handler *Handler) getKubernetesNamespace(w http.ResponseWriter, r *http.Re
 
 // @id CreateKubernetesNamespace
 // @summary Create a namespace
-// @description Create a namespace within the given environment.
+// @description Create a namespace within the given environment. The resource quota and the limit range of the namespace
+// @description are created with the permissions of the user on the cluster.
 // @description **Access policy**: Authenticated user.
 // @tags kubernetes
 // @security ApiKeyAuth || jwt
@@ -184,6 +185,10 @@ func (handler *Handler) createKubernetesNamespace(w http.ResponseWriter, r *http
 			return httperror.Conflict(fmt.Sprintf("an error occurred during the CreateKubernetesNamespace operation, the namespace %s already exists. Error: ", namespaceName), err)
 		}
 
+		if k8serrors.IsForbidden(err) {
+			return httperror.Forbidden(fmt.Sprintf("an error occurred during the CreateKubernetesNamespace operation, the user is not allowed to create the namespace %s or its resource limits. Error: ", namespaceName), err)
+		}
+
 		log.Error().Err(err).Str("context", "CreateKubernetesNamespace").Str("namespace", namespaceName).Msg("Unable to create the namespace")
 		return httperror.InternalServerError("an error occurred during the CreateKubernetesNamespace operation, unable to create the namespace: "+namespaceName, err)
 	}
@@ -243,7 +248,8 @@ func (payload deleteKubernetesNamespacePayload) Validate(r *http.Request) error
 
 // @id UpdateKubernetesNamespace
 // @summary Update a namespace
-// @description Update a namespace within the given environment.
+// @description Update a namespace within the given environment. The resource quota and the limit range of the namespace
+// @description are updated when specified, and removed when disabled.
 // @description **Access policy**: Authenticated user.
 // @tags kubernetes
 // @security ApiKeyAuth || jwt
@@ -273,6 +279,10 @@ func (handler *Handler) updateKubernetesNamespace(w http.ResponseWriter, r *http
 
 	namespace, err := cli.UpdateNamespace(payload)
 	if err != nil {
+		if k8serrors.IsForbidden(err) {
+			return httperror.Forbidden(fmt.Sprintf("an error occurred during the UpdateKubernetesNamespace operation, the user is not allowed to update the namespace %s or its resource limits. Error: ", namespaceName), err)
+		}
+
 		return httperror.InternalServerError(fmt.Sprintf("an error occurred during the UpdateKubernetesNamespace operation for the namespace %s, unable to update the Kubernetes namespace. Error: ", namespaceName), err)
 	}
 
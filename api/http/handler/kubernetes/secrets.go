@@ -1,9 +1,11 @@
 package kubernetes
 
 import (
+	"errors"
 	"net/http"
 
 	models "github.com/portainer/portainer/api/http/models/kubernetes"
+	"github.com/portainer/portainer/api/http/security"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -61,6 +63,213 @@ func (handler *Handler) getKubernetesSecret(w http.ResponseWriter, r *http.Reque
 	return response.JSON(w, secretWithApplication)
 }
 
+// @id CreateKubernetesSecret
+// @summary Create a Secret
+// @description Create a Secret in a given namespace. The creation is recorded in the change history of the Secret.
+// @description **Access policy**: Authenticated user with access to the namespace.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @accept json
+// @produce json
+// @param id path int true "Environment identifier"
+// @param namespace path string true "The namespace name where the secret will be created"
+// @param body body models.K8sSecretPayload true "Secret details, the values of Data are base64 decoded when Base64Encoded is true"
+// @success 200 {object} models.K8sSecret "Success"
+// @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
+// @failure 403 "Permission denied - the user does not have access to the namespace."
+// @failure 409 "A secret with the same name already exists in the namespace."
+// @failure 500 "Server error occurred while attempting to create the secret."
+// @router /kubernetes/{id}/namespaces/{namespace}/secrets [post]
+func (handler *Handler) createKubernetesSecret(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		log.Error().Err(err).Str("context", "createKubernetesSecret").Msg("Unable to retrieve namespace identifier route variable")
+		return httperror.BadRequest("Unable to retrieve namespace identifier route variable", err)
+	}
+
+	var payload models.K8sSecretPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		log.Error().Err(err).Str("context", "createKubernetesSecret").Str("namespace", namespace).Msg("Invalid request payload")
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	if payload.Name == "" {
+		return httperror.BadRequest("Invalid request payload", errors.New("missing secret name from the request payload"))
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.Forbidden("Unable to retrieve user details from authentication token", err)
+	}
+
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Str("context", "createKubernetesSecret").Str("namespace", namespace).Msg("Unable to get a Kubernetes client for the user")
+		return httperror.InternalServerError("Unable to get a Kubernetes client for the user", httpErr)
+	}
+
+	secret, err := cli.CreateSecret(namespace, payload, tokenData.Username)
+	if err != nil {
+		return configurationErrorResponse(err, "createKubernetesSecret", namespace, payload.Name, "Unable to create secret")
+	}
+
+	return response.JSON(w, secret)
+}
+
+// @id UpdateKubernetesSecret
+// @summary Update a Secret
+// @description Replace the data of a Secret. The update is rejected when the Secret was modified since the given resource version.
+// @description The changed keys, never their values, are recorded in the change history of the Secret.
+// @description **Access policy**: Authenticated user with access to the namespace.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @accept json
+// @produce json
+// @param id path int true "Environment identifier"
+// @param namespace path string true "The namespace name where the secret is located"
+// @param secret path string true "The secret name"
+// @param body body models.K8sSecretPayload true "Secret details, the values of Data are base64 decoded when Base64Encoded is true"
+// @success 200 {object} models.K8sSecret "Success"
+// @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
+// @failure 403 "Permission denied - the user does not have access to the namespace."
+// @failure 404 "Unable to find a secret with the specified name in the given namespace."
+// @failure 409 "The secret was modified since the given resource version."
+// @failure 500 "Server error occurred while attempting to update the secret."
+// @router /kubernetes/{id}/namespaces/{namespace}/secrets/{secret} [put]
+func (handler *Handler) updateKubernetesSecret(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		log.Error().Err(err).Str("context", "updateKubernetesSecret").Msg("Unable to retrieve namespace identifier route variable")
+		return httperror.BadRequest("Unable to retrieve namespace identifier route variable", err)
+	}
+
+	secretName, err := request.RetrieveRouteVariableValue(r, "secret")
+	if err != nil {
+		log.Error().Err(err).Str("context", "updateKubernetesSecret").Str("namespace", namespace).Msg("Unable to retrieve secret identifier route variable")
+		return httperror.BadRequest("Unable to retrieve secret identifier route variable", err)
+	}
+
+	var payload models.K8sSecretPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		log.Error().Err(err).Str("context", "updateKubernetesSecret").Str("namespace", namespace).Str("secret", secretName).Msg("Invalid request payload")
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	if payload.ResourceVersion == "" {
+		return httperror.BadRequest("Invalid request payload", errors.New("missing resource version from the request payload"))
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.Forbidden("Unable to retrieve user details from authentication token", err)
+	}
+
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Str("context", "updateKubernetesSecret").Str("namespace", namespace).Str("secret", secretName).Msg("Unable to get a Kubernetes client for the user")
+		return httperror.InternalServerError("Unable to get a Kubernetes client for the user", httpErr)
+	}
+
+	secret, err := cli.UpdateSecret(namespace, secretName, payload, tokenData.Username)
+	if err != nil {
+		return configurationErrorResponse(err, "updateKubernetesSecret", namespace, secretName, "Unable to update secret")
+	}
+
+	return response.JSON(w, secret)
+}
+
+// @id DiffKubernetesSecret
+// @summary Preview the changes of a Secret update
+// @description Compare the keys of a Secret with the given data, without applying the changes. The values of the Secret are never returned.
+// @description **Access policy**: Authenticated user with access to the namespace.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @accept json
+// @produce json
+// @param id path int true "Environment identifier"
+// @param namespace path string true "The namespace name where the secret is located"
+// @param secret path string true "The secret name"
+// @param body body models.K8sSecretPayload true "Secret details, the values of Data are base64 decoded when Base64Encoded is true"
+// @success 200 {object} models.K8sConfigurationDiff "Success"
+// @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
+// @failure 403 "Permission denied - the user does not have access to the namespace."
+// @failure 404 "Unable to find a secret with the specified name in the given namespace."
+// @failure 500 "Server error occurred while attempting to compare the secret."
+// @router /kubernetes/{id}/namespaces/{namespace}/secrets/{secret}/diff [post]
+func (handler *Handler) diffKubernetesSecret(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		log.Error().Err(err).Str("context", "diffKubernetesSecret").Msg("Unable to retrieve namespace identifier route variable")
+		return httperror.BadRequest("Unable to retrieve namespace identifier route variable", err)
+	}
+
+	secretName, err := request.RetrieveRouteVariableValue(r, "secret")
+	if err != nil {
+		log.Error().Err(err).Str("context", "diffKubernetesSecret").Str("namespace", namespace).Msg("Unable to retrieve secret identifier route variable")
+		return httperror.BadRequest("Unable to retrieve secret identifier route variable", err)
+	}
+
+	var payload models.K8sSecretPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		log.Error().Err(err).Str("context", "diffKubernetesSecret").Str("namespace", namespace).Str("secret", secretName).Msg("Invalid request payload")
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Str("context", "diffKubernetesSecret").Str("namespace", namespace).Str("secret", secretName).Msg("Unable to get a Kubernetes client for the user")
+		return httperror.InternalServerError("Unable to get a Kubernetes client for the user", httpErr)
+	}
+
+	diff, err := cli.DiffSecret(namespace, secretName, payload)
+	if err != nil {
+		return configurationErrorResponse(err, "diffKubernetesSecret", namespace, secretName, "Unable to compare secret")
+	}
+
+	return response.JSON(w, diff)
+}
+
+// @id DeleteKubernetesSecret
+// @summary Delete a Secret
+// @description Delete a Secret from a given namespace.
+// @description **Access policy**: Authenticated user with access to the namespace.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @param id path int true "Environment identifier"
+// @param namespace path string true "The namespace name where the secret is located"
+// @param secret path string true "The secret name"
+// @success 204 "Success"
+// @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
+// @failure 403 "Permission denied - the user does not have access to the namespace."
+// @failure 404 "Unable to find a secret with the specified name in the given namespace."
+// @failure 500 "Server error occurred while attempting to delete the secret."
+// @router /kubernetes/{id}/namespaces/{namespace}/secrets/{secret} [delete]
+func (handler *Handler) deleteKubernetesSecret(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		log.Error().Err(err).Str("context", "deleteKubernetesSecret").Msg("Unable to retrieve namespace identifier route variable")
+		return httperror.BadRequest("Unable to retrieve namespace identifier route variable", err)
+	}
+
+	secretName, err := request.RetrieveRouteVariableValue(r, "secret")
+	if err != nil {
+		log.Error().Err(err).Str("context", "deleteKubernetesSecret").Str("namespace", namespace).Msg("Unable to retrieve secret identifier route variable")
+		return httperror.BadRequest("Unable to retrieve secret identifier route variable", err)
+	}
+
+	cli, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Str("context", "deleteKubernetesSecret").Str("namespace", namespace).Str("secret", secretName).Msg("Unable to get a Kubernetes client for the user")
+		return httperror.InternalServerError("Unable to get a Kubernetes client for the user", httpErr)
+	}
+
+	if err := cli.DeleteSecret(namespace, secretName); err != nil {
+		return configurationErrorResponse(err, "deleteKubernetesSecret", namespace, secretName, "Unable to delete secret")
+	}
+
+	return response.Empty(w)
+}
+
 // @id GetKubernetesSecrets
 // @summary Get a list of Secrets
 // @description Get a list of Secrets for a given namespace. If isUsed is set to true, information about the applications that use the secrets is also returned.
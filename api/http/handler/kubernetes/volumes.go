@@ -1,11 +1,14 @@
 package kubernetes
 
 import (
+	"errors"
 	"net/http"
 
 	models "github.com/portainer/portainer/api/http/models/kubernetes"
+	"github.com/portainer/portainer/api/kubernetes/cli"
 	"github.com/rs/zerolog/log"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
@@ -21,6 +24,7 @@ import (
 // @produce json
 // @param id path int true "Environment identifier"
 // @param withApplications query boolean false "When set to True, include the applications that are using the volumes. It is set to false by default"
+// @param withUsage query boolean false "When set to True, include the disk usage reported by the kubelets for the volumes whose driver exposes metrics. It is set to false by default"
 // @success 200 {object} map[string]kubernetes.K8sVolumeInfo "Success"
 // @failure 400 "Invalid request payload, such as missing required fields or fields not meeting validation criteria."
 // @failure 403 "Unauthorized access or operation not allowed."
@@ -109,6 +113,103 @@ func (handler *Handler) getKubernetesVolume(w http.ResponseWriter, r *http.Reque
 	return response.JSON(w, volume)
 }
 
+// @id ExpandKubernetesVolume
+// @summary Expand a Kubernetes volume within the given Portainer environment
+// @description Increase the requested size of a persistent volume claim. The storage class of the volume must allow volume expansion.
+// @description **Access policy**: Authenticated user.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @accept json
+// @param id path int true "Environment identifier"
+// @param namespace path string true "Namespace identifier"
+// @param volume path string true "Volume name"
+// @param body body kubernetes.K8sVolumeExpandPayload true "New size of the volume"
+// @success 204 "Success"
+// @failure 400 "Invalid request payload, or the volume cannot be expanded to the given size."
+// @failure 403 "Unauthorized access or operation not allowed."
+// @failure 404 "Unable to find the volume."
+// @failure 500 "Server error occurred while attempting to expand the volume."
+// @router /kubernetes/{id}/namespaces/{namespace}/volumes/{volume}/expand [put]
+func (handler *Handler) expandKubernetesVolume(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	namespace, err := request.RetrieveRouteVariableValue(r, "namespace")
+	if err != nil {
+		log.Error().Err(err).Str("context", "ExpandKubernetesVolume").Msg("Unable to retrieve namespace identifier")
+		return httperror.BadRequest("Invalid namespace identifier", err)
+	}
+
+	volumeName, err := request.RetrieveRouteVariableValue(r, "volume")
+	if err != nil {
+		log.Error().Err(err).Str("context", "ExpandKubernetesVolume").Msg("Unable to retrieve volume name")
+		return httperror.BadRequest("Invalid volume name", err)
+	}
+
+	var payload models.K8sVolumeExpandPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		log.Error().Err(err).Str("context", "ExpandKubernetesVolume").Msg("Unable to decode and validate the request payload")
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	kcl, httpErr := handler.getProxyKubeClient(r)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Str("context", "ExpandKubernetesVolume").Msg("Unable to get Kubernetes client")
+		return httperror.InternalServerError("Failed to prepare Kubernetes client", httpErr)
+	}
+
+	if err := kcl.ExpandVolume(namespace, volumeName, resource.MustParse(payload.Size)); err != nil {
+		if errors.Is(err, cli.ErrVolumeExpansionNotAllowed) || errors.Is(err, cli.ErrVolumeSizeNotIncreased) {
+			return httperror.BadRequest(err.Error(), err)
+		}
+
+		if k8serrors.IsUnauthorized(err) || k8serrors.IsForbidden(err) {
+			log.Error().Err(err).Str("context", "ExpandKubernetesVolume").Str("namespace", namespace).Str("volume", volumeName).Msg("Unauthorized access")
+			return httperror.Forbidden("Unauthorized access to volume", err)
+		}
+
+		if k8serrors.IsNotFound(err) {
+			log.Error().Err(err).Str("context", "ExpandKubernetesVolume").Str("namespace", namespace).Str("volume", volumeName).Msg("Volume not found")
+			return httperror.NotFound("Volume not found", err)
+		}
+
+		log.Error().Err(err).Str("context", "ExpandKubernetesVolume").Str("namespace", namespace).Str("volume", volumeName).Msg("Failed to expand volume")
+		return httperror.InternalServerError("Failed to expand volume", err)
+	}
+
+	return response.Empty(w)
+}
+
+// @id GetAllKubernetesPersistentVolumes
+// @summary Get the Kubernetes persistent volumes within the given Portainer environment
+// @description Get a list of all the persistent volumes of the cluster, with their capacity, storage class and claim.
+// @description **Access policy**: Authenticated user with cluster administrator access.
+// @tags kubernetes
+// @security ApiKeyAuth || jwt
+// @produce json
+// @param id path int true "Environment identifier"
+// @success 200 {array} kubernetes.K8sPersistentVolume "Success"
+// @failure 403 "Unauthorized access or operation not allowed."
+// @failure 500 "Server error occurred while attempting to retrieve the persistent volumes."
+// @router /kubernetes/{id}/persistent_volumes [get]
+func (handler *Handler) getAllKubernetesPersistentVolumes(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	cli, httpErr := handler.prepareKubeClient(r)
+	if httpErr != nil {
+		log.Error().Err(httpErr).Str("context", "GetAllKubernetesPersistentVolumes").Msg("Unable to get Kubernetes client")
+		return httperror.InternalServerError("Failed to prepare Kubernetes client", httpErr)
+	}
+
+	if !cli.IsKubeAdmin {
+		log.Error().Str("context", "GetAllKubernetesPersistentVolumes").Msg("user is not authorized to fetch persistent volumes from the Kubernetes cluster.")
+		return httperror.Forbidden("User is not authorized to fetch persistent volumes from the Kubernetes cluster.", nil)
+	}
+
+	persistentVolumes, err := cli.GetPersistentVolumes()
+	if err != nil {
+		log.Error().Err(err).Str("context", "GetAllKubernetesPersistentVolumes").Msg("Failed to retrieve persistent volumes")
+		return httperror.InternalServerError("Failed to retrieve persistent volumes", err)
+	}
+
+	return response.JSON(w, persistentVolumes)
+}
+
 func (handler *Handler) getKubernetesVolumes(r *http.Request) ([]models.K8sVolumeInfo, *httperror.HandlerError) {
 	withApplications, err := request.RetrieveBooleanQueryParameter(r, "withApplications", true)
 	if err != nil {
@@ -116,6 +217,12 @@ func (handler *Handler) getKubernetesVolumes(r *http.Request) ([]models.K8sVolum
 		return nil, httperror.BadRequest("Invalid 'withApplications' parameter", err)
 	}
 
+	withUsage, err := request.RetrieveBooleanQueryParameter(r, "withUsage", true)
+	if err != nil {
+		log.Error().Err(err).Str("context", "GetKubernetesVolumes").Bool("withUsage", withUsage).Msg("Unable to parse query parameter")
+		return nil, httperror.BadRequest("Invalid 'withUsage' parameter", err)
+	}
+
 	cli, httpErr := handler.prepareKubeClient(r)
 	if httpErr != nil {
 		log.Error().Err(httpErr).Str("context", "GetKubernetesVolumes").Msg("Unable to get Kubernetes client")
@@ -133,6 +240,13 @@ func (handler *Handler) getKubernetesVolumes(r *http.Request) ([]models.K8sVolum
 		return nil, httperror.InternalServerError("Failed to retrieve volumes", err)
 	}
 
+	if withUsage {
+		if err := cli.CombineVolumesWithUsage(volumes); err != nil {
+			log.Error().Err(err).Str("context", "GetKubernetesVolumes").Msg("Failed to combine volumes with their usage")
+			return nil, httperror.InternalServerError("Failed to combine volumes with their usage", err)
+		}
+	}
+
 	if withApplications {
 		volumesWithApplications, err := cli.CombineVolumesWithApplications(&volumes)
 		if err != nil {
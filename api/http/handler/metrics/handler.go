@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api/http/security"
+	internalmetrics "github.com/portainer/portainer/api/internal/metrics"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is the HTTP handler used to expose the metrics of the Portainer server.
+type Handler struct {
+	*mux.Router
+	metricsHandler http.Handler
+}
+
+// NewHandler returns a new Handler
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router:         mux.NewRouter(),
+		metricsHandler: internalmetrics.Handler(),
+	}
+
+	h.Handle("/metrics",
+		bouncer.AdminAccess(http.HandlerFunc(h.metricsInspect))).Methods(http.MethodGet)
+
+	return h
+}
+
+// @id MetricsInspect
+// @summary Retrieve the metrics of the Portainer server
+// @description Retrieve the internal metrics of the Portainer server in the Prometheus exposition format:
+// @description API request latency, snapshot durations and failures, reverse tunnels by status, open database transactions and database query latency.
+// @description Only available when Portainer is started with the --metrics flag.
+// @description **Access policy**: administrator
+// @tags system
+// @security ApiKeyAuth || jwt
+// @produce plain
+// @success 200 "Success"
+// @failure 403 "Permission denied"
+// @failure 404 "Metrics are not enabled"
+// @router /metrics [get]
+func (h *Handler) metricsInspect(w http.ResponseWriter, r *http.Request) {
+	h.metricsHandler.ServeHTTP(w, r)
+}
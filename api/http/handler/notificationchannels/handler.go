@@ -0,0 +1,89 @@
+package notificationchannels
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/notifications"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is the HTTP handler used to handle notification channel operations.
+type Handler struct {
+	*mux.Router
+	DataStore           dataservices.DataStore
+	NotificationService *notifications.Service
+}
+
+// NewHandler creates a handler to manage notification channel operations.
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+
+	h.Handle("/notification_channels",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.notificationChannelList))).Methods(http.MethodGet)
+	h.Handle("/notification_channels",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.notificationChannelCreate))).Methods(http.MethodPost)
+	h.Handle("/notification_channels/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.notificationChannelInspect))).Methods(http.MethodGet)
+	h.Handle("/notification_channels/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.notificationChannelUpdate))).Methods(http.MethodPut)
+	h.Handle("/notification_channels/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.notificationChannelDelete))).Methods(http.MethodDelete)
+	h.Handle("/notification_channels/{id}/test",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.notificationChannelTest))).Methods(http.MethodPost)
+
+	return h
+}
+
+// validateChannel returns an error when the settings of the channel do not match its type
+func validateChannel(channel *portainer.NotificationChannel) error {
+	switch channel.Type {
+	case portainer.NotificationChannelEmail:
+		if len(channel.Recipients) == 0 {
+			return errors.New("an email notification channel requires at least one recipient")
+		}
+
+		if err := notifications.ValidateRecipients(channel.Recipients); err != nil {
+			return err
+		}
+	case portainer.NotificationChannelSlack, portainer.NotificationChannelWebhook:
+		u, err := url.ParseRequestURI(channel.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("invalid notification channel URL, it must be an http or https URL")
+		}
+	default:
+		return errors.New("invalid notification channel type, it must be one of: email, slack, webhook")
+	}
+
+	for _, eventType := range channel.Subscriptions {
+		if !slices.Contains(notifications.EventTypes, eventType) {
+			return fmt.Errorf("invalid subscription, unknown event %q", eventType)
+		}
+	}
+
+	return notifications.ValidateTemplate(channel.Type, channel.Template)
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
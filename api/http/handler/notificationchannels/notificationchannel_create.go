@@ -0,0 +1,84 @@
+package notificationchannels
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type notificationChannelCreatePayload struct {
+	Name string                            `example:"ops"`
+	Type portainer.NotificationChannelType `example:"slack" enums:"email,slack,webhook"`
+	// Email addresses receiving the notifications of an email channel
+	Recipients []string `example:"ops@example.com"`
+	// URL receiving the notifications of a Slack or webhook channel, the incoming webhook URL for Slack
+	URL string `example:"https://hooks.slack.com/services/T000/B000/XXXX"`
+	// Events notified to the channel
+	Subscriptions []portainer.NotificationEventType `example:"endpoint_down" enums:"endpoint_down,edge_stack_failure,auto_update_applied,backup_failed"`
+	// Go template rendering the notifications, the default template of the channel type is used when empty
+	Template string `example:"{{ .Title }}: {{ .Message }}"`
+	Enabled  bool   `example:"true"`
+}
+
+func (payload *notificationChannelCreatePayload) Validate(r *http.Request) error {
+	if payload.Name == "" {
+		return errors.New("invalid notification channel name")
+	}
+
+	return nil
+}
+
+// @id NotificationChannelCreate
+// @summary Create a notification channel
+// @description Create a channel notified of the system events it subscribes to, by email, on Slack or with a webhook.
+// @description The notifications are rendered with the Go template of the channel, which receives the event as
+// @description {Type, Time, EndpointID, EndpointName, Title, Message}. The webhooks receive the event as JSON when no template is set.
+// @description **Access policy**: administrator
+// @tags notification_channels
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body notificationChannelCreatePayload true "Notification channel details"
+// @success 200 {object} portainer.NotificationChannel
+// @failure 400
+// @failure 500
+// @router /notification_channels [post]
+func (handler *Handler) notificationChannelCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload notificationChannelCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	channel := &portainer.NotificationChannel{
+		Name:          payload.Name,
+		Type:          payload.Type,
+		Recipients:    payload.Recipients,
+		URL:           payload.URL,
+		Subscriptions: payload.Subscriptions,
+		Template:      payload.Template,
+		Enabled:       payload.Enabled,
+	}
+
+	if channel.Recipients == nil {
+		channel.Recipients = []string{}
+	}
+
+	if channel.Subscriptions == nil {
+		channel.Subscriptions = []portainer.NotificationEventType{}
+	}
+
+	if err := validateChannel(channel); err != nil {
+		return httperror.BadRequest("Invalid notification channel", err)
+	}
+
+	if err := handler.DataStore.NotificationChannel().Create(channel); err != nil {
+		return httperror.InternalServerError("Unable to persist the notification channel inside the database", err)
+	}
+
+	return response.JSON(w, channel)
+}
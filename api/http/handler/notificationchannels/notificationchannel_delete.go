@@ -0,0 +1,50 @@
+package notificationchannels
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id NotificationChannelDelete
+// @summary Delete a notification channel
+// @description **Access policy**: administrator
+// @tags notification_channels
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Notification channel identifier"
+// @success 204
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /notification_channels/{id} [delete]
+func (handler *Handler) notificationChannelDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	channelID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid notification channel identifier route variable", err)
+	}
+
+	id := portainer.NotificationChannelID(channelID)
+
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if _, err := tx.NotificationChannel().Read(id); tx.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a notification channel with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a notification channel with the specified identifier inside the database", err)
+		}
+
+		if err := tx.NotificationChannel().Delete(id); err != nil {
+			return httperror.InternalServerError("Unable to remove the notification channel from the database", err)
+		}
+
+		return nil
+	}); err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	return response.Empty(w)
+}
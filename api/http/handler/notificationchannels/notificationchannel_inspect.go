@@ -0,0 +1,39 @@
+package notificationchannels
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id NotificationChannelInspect
+// @summary Inspect a notification channel
+// @description **Access policy**: administrator
+// @tags notification_channels
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Notification channel identifier"
+// @success 200 {object} portainer.NotificationChannel
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /notification_channels/{id} [get]
+func (handler *Handler) notificationChannelInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	channelID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid notification channel identifier route variable", err)
+	}
+
+	channel, err := handler.DataStore.NotificationChannel().Read(portainer.NotificationChannelID(channelID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a notification channel with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a notification channel with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, channel)
+}
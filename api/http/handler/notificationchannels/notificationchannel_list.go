@@ -0,0 +1,27 @@
+package notificationchannels
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id NotificationChannelList
+// @summary List the notification channels
+// @description **Access policy**: administrator
+// @tags notification_channels
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.NotificationChannel
+// @failure 500
+// @router /notification_channels [get]
+func (handler *Handler) notificationChannelList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	channels, err := handler.DataStore.NotificationChannel().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the notification channels from the database", err)
+	}
+
+	return response.JSON(w, channels)
+}
@@ -0,0 +1,44 @@
+package notificationchannels
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/notifications"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id NotificationChannelTest
+// @summary Send a test notification
+// @description Send a test notification to a channel to check its configuration, the channel does not need to be enabled.
+// @description **Access policy**: administrator
+// @tags notification_channels
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Notification channel identifier"
+// @success 204
+// @failure 400 "Invalid request or the notification could not be delivered"
+// @failure 404
+// @failure 500
+// @router /notification_channels/{id}/test [post]
+func (handler *Handler) notificationChannelTest(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	channelID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid notification channel identifier route variable", err)
+	}
+
+	channel, err := handler.DataStore.NotificationChannel().Read(portainer.NotificationChannelID(channelID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a notification channel with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a notification channel with the specified identifier inside the database", err)
+	}
+
+	if err := handler.NotificationService.Deliver(channel, notifications.TestEvent()); err != nil {
+		return httperror.BadRequest("Unable to deliver the test notification", err)
+	}
+
+	return response.Empty(w)
+}
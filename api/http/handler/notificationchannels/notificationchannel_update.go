@@ -0,0 +1,110 @@
+package notificationchannels
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type notificationChannelUpdatePayload struct {
+	Name *string                            `example:"ops"`
+	Type *portainer.NotificationChannelType `example:"slack" enums:"email,slack,webhook"`
+	// Email addresses receiving the notifications of an email channel, the recipients are replaced when set
+	Recipients []string `example:"ops@example.com"`
+	// URL receiving the notifications of a Slack or webhook channel, the incoming webhook URL for Slack
+	URL *string `example:"https://hooks.slack.com/services/T000/B000/XXXX"`
+	// Events notified to the channel, the subscriptions are replaced when set
+	Subscriptions []portainer.NotificationEventType `example:"endpoint_down" enums:"endpoint_down,edge_stack_failure,auto_update_applied,backup_failed"`
+	// Go template rendering the notifications, an empty template selects the default template of the channel type
+	Template *string `example:"{{ .Title }}: {{ .Message }}"`
+	Enabled  *bool   `example:"true"`
+}
+
+func (payload *notificationChannelUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("invalid notification channel name")
+	}
+
+	return nil
+}
+
+// @id NotificationChannelUpdate
+// @summary Update a notification channel
+// @description **Access policy**: administrator
+// @tags notification_channels
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Notification channel identifier"
+// @param body body notificationChannelUpdatePayload true "Notification channel details"
+// @success 200 {object} portainer.NotificationChannel
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /notification_channels/{id} [put]
+func (handler *Handler) notificationChannelUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	channelID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid notification channel identifier route variable", err)
+	}
+
+	var payload notificationChannelUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var channel *portainer.NotificationChannel
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		channel, err = tx.NotificationChannel().Read(portainer.NotificationChannelID(channelID))
+		if tx.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a notification channel with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a notification channel with the specified identifier inside the database", err)
+		}
+
+		if payload.Name != nil {
+			channel.Name = *payload.Name
+		}
+
+		if payload.Type != nil {
+			channel.Type = *payload.Type
+		}
+
+		if payload.Recipients != nil {
+			channel.Recipients = payload.Recipients
+		}
+
+		if payload.URL != nil {
+			channel.URL = *payload.URL
+		}
+
+		if payload.Subscriptions != nil {
+			channel.Subscriptions = payload.Subscriptions
+		}
+
+		if payload.Template != nil {
+			channel.Template = *payload.Template
+		}
+
+		if payload.Enabled != nil {
+			channel.Enabled = *payload.Enabled
+		}
+
+		if err := validateChannel(channel); err != nil {
+			return httperror.BadRequest("Invalid notification channel", err)
+		}
+
+		if err := tx.NotificationChannel().Update(channel.ID, channel); err != nil {
+			return httperror.InternalServerError("Unable to persist notification channel changes inside the database", err)
+		}
+
+		return nil
+	})
+
+	return txResponse(w, channel, err)
+}
@@ -0,0 +1,92 @@
+package quotas
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is the HTTP handler used to handle quota operations.
+type Handler struct {
+	*mux.Router
+	DataStore dataservices.DataStore
+}
+
+// NewHandler creates a handler to manage quota operations.
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+
+	h.Handle("/quotas",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.quotaList))).Methods(http.MethodGet)
+	h.Handle("/quotas",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.quotaCreate))).Methods(http.MethodPost)
+	h.Handle("/quotas/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.quotaInspect))).Methods(http.MethodGet)
+	h.Handle("/quotas/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.quotaUpdate))).Methods(http.MethodPut)
+	h.Handle("/quotas/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.quotaDelete))).Methods(http.MethodDelete)
+
+	return h
+}
+
+func validateLimits(maxStacks, maxContainers int, maxCPU float64, maxMemory int64) error {
+	if maxStacks < 0 || maxContainers < 0 || maxCPU < 0 || maxMemory < 0 {
+		return errors.New("invalid limits, must be positive numbers or zero for no limit")
+	}
+
+	return nil
+}
+
+// validateScope ensures that the quota applies to an existing team or environment group, and that
+// a team or an environment group is subject to a single quota
+func validateScope(tx dataservices.DataStoreTx, quota *portainer.Quota) error {
+	if quota.TeamID != 0 {
+		if _, err := tx.Team().Read(quota.TeamID); tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find a team with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a team with the specified identifier inside the database", err)
+		}
+	} else {
+		if _, err := tx.EndpointGroup().Read(quota.EndpointGroupID); tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find an environment group with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find an environment group with the specified identifier inside the database", err)
+		}
+	}
+
+	quotas, err := tx.Quota().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve quotas from the database", err)
+	}
+
+	for _, existing := range quotas {
+		if existing.ID != quota.ID && existing.TeamID == quota.TeamID && existing.EndpointGroupID == quota.EndpointGroupID {
+			return httperror.Conflict("A quota already applies to this team or environment group", errors.New("quota already exists"))
+		}
+	}
+
+	return nil
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
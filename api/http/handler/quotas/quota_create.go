@@ -0,0 +1,82 @@
+package quotas
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type quotaCreatePayload struct {
+	Name string `example:"dev-team"`
+	// Team subject to the quota, exclusive with EndpointGroupID
+	TeamID portainer.TeamID `example:"1"`
+	// Environment(Endpoint) group subject to the quota, exclusive with TeamID
+	EndpointGroupID portainer.EndpointGroupID `example:"1"`
+	// Maximum number of stacks per environment, 0 for no limit
+	MaxStacks int `example:"5"`
+	// Maximum number of containers per environment, 0 for no limit
+	MaxContainers int `example:"20"`
+	// Maximum number of CPUs used by the containers per environment, 0 for no limit
+	MaxCPU float64 `example:"4"`
+	// Maximum memory in bytes used by the containers per environment, 0 for no limit
+	MaxMemory int64 `example:"8589934592"`
+}
+
+func (payload *quotaCreatePayload) Validate(r *http.Request) error {
+	if payload.Name == "" {
+		return errors.New("invalid quota name")
+	}
+
+	if (payload.TeamID == 0) == (payload.EndpointGroupID == 0) {
+		return errors.New("invalid scope, either a team or an environment group is required")
+	}
+
+	return validateLimits(payload.MaxStacks, payload.MaxContainers, payload.MaxCPU, payload.MaxMemory)
+}
+
+// @id QuotaCreate
+// @summary Create a quota
+// @description Create a quota limiting the resources that non-administrators can deploy on each environment.
+// @description A team quota counts the resources owned by the team, an environment group quota counts all the resources of the environments of the group.
+// @description **Access policy**: administrator
+// @tags quotas
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body quotaCreatePayload true "Quota details"
+// @success 200 {object} portainer.Quota
+// @failure 400
+// @failure 409 "A quota already applies to the team or environment group"
+// @failure 500
+// @router /quotas [post]
+func (handler *Handler) quotaCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload quotaCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	quota := &portainer.Quota{
+		Name:            payload.Name,
+		TeamID:          payload.TeamID,
+		EndpointGroupID: payload.EndpointGroupID,
+		MaxStacks:       payload.MaxStacks,
+		MaxContainers:   payload.MaxContainers,
+		MaxCPU:          payload.MaxCPU,
+		MaxMemory:       payload.MaxMemory,
+	}
+
+	err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := validateScope(tx, quota); err != nil {
+			return err
+		}
+
+		return tx.Quota().Create(quota)
+	})
+
+	return txResponse(w, quota, err)
+}
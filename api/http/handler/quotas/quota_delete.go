@@ -0,0 +1,43 @@
+package quotas
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id QuotaDelete
+// @summary Delete a quota
+// @description **Access policy**: administrator
+// @tags quotas
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Quota identifier"
+// @success 204
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /quotas/{id} [delete]
+func (handler *Handler) quotaDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	quotaID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid quota identifier route variable", err)
+	}
+
+	id := portainer.QuotaID(quotaID)
+
+	if _, err := handler.DataStore.Quota().Read(id); handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a quota with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a quota with the specified identifier inside the database", err)
+	}
+
+	if err := handler.DataStore.Quota().Delete(id); err != nil {
+		return httperror.InternalServerError("Unable to remove the quota from the database", err)
+	}
+
+	return response.Empty(w)
+}
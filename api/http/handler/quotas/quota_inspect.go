@@ -0,0 +1,39 @@
+package quotas
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id QuotaInspect
+// @summary Inspect a quota
+// @description **Access policy**: administrator
+// @tags quotas
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Quota identifier"
+// @success 200 {object} portainer.Quota
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /quotas/{id} [get]
+func (handler *Handler) quotaInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	quotaID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid quota identifier route variable", err)
+	}
+
+	quota, err := handler.DataStore.Quota().Read(portainer.QuotaID(quotaID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a quota with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a quota with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, quota)
+}
@@ -0,0 +1,27 @@
+package quotas
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id QuotaList
+// @summary List the quotas
+// @description **Access policy**: administrator
+// @tags quotas
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.Quota
+// @failure 500
+// @router /quotas [get]
+func (handler *Handler) quotaList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	quotas, err := handler.DataStore.Quota().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve quotas from the database", err)
+	}
+
+	return response.JSON(w, quotas)
+}
@@ -0,0 +1,122 @@
+package quotas
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type quotaUpdatePayload struct {
+	Name *string `example:"dev-team"`
+	// Maximum number of stacks per environment, 0 for no limit
+	MaxStacks *int `example:"5"`
+	// Maximum number of containers per environment, 0 for no limit
+	MaxContainers *int `example:"20"`
+	// Maximum number of CPUs used by the containers per environment, 0 for no limit
+	MaxCPU *float64 `example:"4"`
+	// Maximum memory in bytes used by the containers per environment, 0 for no limit
+	MaxMemory *int64 `example:"8589934592"`
+}
+
+func (payload *quotaUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("invalid quota name")
+	}
+
+	var maxStacks, maxContainers int
+	var maxCPU float64
+	var maxMemory int64
+
+	if payload.MaxStacks != nil {
+		maxStacks = *payload.MaxStacks
+	}
+
+	if payload.MaxContainers != nil {
+		maxContainers = *payload.MaxContainers
+	}
+
+	if payload.MaxCPU != nil {
+		maxCPU = *payload.MaxCPU
+	}
+
+	if payload.MaxMemory != nil {
+		maxMemory = *payload.MaxMemory
+	}
+
+	return validateLimits(maxStacks, maxContainers, maxCPU, maxMemory)
+}
+
+// @id QuotaUpdate
+// @summary Update a quota
+// @description The team or environment group of a quota cannot be changed.
+// @description **Access policy**: administrator
+// @tags quotas
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Quota identifier"
+// @param body body quotaUpdatePayload true "Quota details"
+// @success 200 {object} portainer.Quota
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /quotas/{id} [put]
+func (handler *Handler) quotaUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	quotaID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid quota identifier route variable", err)
+	}
+
+	var payload quotaUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var quota *portainer.Quota
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		quota, err = updateQuota(tx, portainer.QuotaID(quotaID), payload)
+		return err
+	})
+
+	return txResponse(w, quota, err)
+}
+
+func updateQuota(tx dataservices.DataStoreTx, quotaID portainer.QuotaID, payload quotaUpdatePayload) (*portainer.Quota, error) {
+	quota, err := tx.Quota().Read(quotaID)
+	if tx.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find a quota with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find a quota with the specified identifier inside the database", err)
+	}
+
+	if payload.Name != nil {
+		quota.Name = *payload.Name
+	}
+
+	if payload.MaxStacks != nil {
+		quota.MaxStacks = *payload.MaxStacks
+	}
+
+	if payload.MaxContainers != nil {
+		quota.MaxContainers = *payload.MaxContainers
+	}
+
+	if payload.MaxCPU != nil {
+		quota.MaxCPU = *payload.MaxCPU
+	}
+
+	if payload.MaxMemory != nil {
+		quota.MaxMemory = *payload.MaxMemory
+	}
+
+	if err := tx.Quota().Update(quota.ID, quota); err != nil {
+		return nil, httperror.InternalServerError("Unable to persist quota changes inside the database", err)
+	}
+
+	return quota, nil
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/portainer/portainer/api/http/proxy"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/registryaccess"
+	"github.com/portainer/portainer/api/internal/registryclient"
 	"github.com/portainer/portainer/api/kubernetes"
 	"github.com/portainer/portainer/api/kubernetes/cli"
 	"github.com/portainer/portainer/api/pendingactions"
@@ -20,7 +22,6 @@ import (
 )
 
 func hideFields(registry *portainer.Registry, hideAccesses bool) {
-	registry.Password = ""
 	registry.ManagementConfiguration = nil
 	if hideAccesses {
 		registry.RegistryAccesses = nil
@@ -36,6 +37,8 @@ type Handler struct {
 	ProxyManager          *proxy.Manager
 	K8sClientFactory      *cli.ClientFactory
 	PendingActionsService *pendingactions.PendingActionsService
+	RegistryClientService *registryclient.Service
+	RegistryAccessService *registryaccess.Service
 }
 
 // NewHandler creates a handler to manage registry operations.
@@ -62,11 +65,17 @@ func (handler *Handler) initRouter(bouncer accessGuard) {
 
 	adminRouter.Handle("/registries", httperror.LoggerHandler(handler.registryList)).Methods(http.MethodGet)
 	adminRouter.Handle("/registries", httperror.LoggerHandler(handler.registryCreate)).Methods(http.MethodPost)
+	adminRouter.Handle("/registries/namespace_access/drift", httperror.LoggerHandler(handler.registryNamespaceAccessDrift)).Methods(http.MethodGet)
 	adminRouter.Handle("/registries/{id}", httperror.LoggerHandler(handler.registryUpdate)).Methods(http.MethodPut)
 	adminRouter.Handle("/registries/{id}/configure", httperror.LoggerHandler(handler.registryConfigure)).Methods(http.MethodPost)
 	adminRouter.Handle("/registries/{id}", httperror.LoggerHandler(handler.registryDelete)).Methods(http.MethodDelete)
+	adminRouter.Handle("/registries/{id}/v2/repositories/{repository:.+}/tags/{tag}", httperror.LoggerHandler(handler.registryTagDelete)).Methods(http.MethodDelete)
+	adminRouter.Handle("/registries/{id}/v2/repositories/{repository:.+}/manifests/{reference}", httperror.LoggerHandler(handler.registryManifestDelete)).Methods(http.MethodDelete)
 
 	authenticatedRouter.Handle("/registries/{id}", httperror.LoggerHandler(handler.registryInspect)).Methods(http.MethodGet)
+	authenticatedRouter.Handle("/registries/{id}/v2/repositories", httperror.LoggerHandler(handler.registryRepositoryList)).Methods(http.MethodGet)
+	authenticatedRouter.Handle("/registries/{id}/v2/repositories/{repository:.+}/tags", httperror.LoggerHandler(handler.registryTagList)).Methods(http.MethodGet)
+	authenticatedRouter.Handle("/registries/{id}/v2/repositories/{repository:.+}/manifests/{reference}", httperror.LoggerHandler(handler.registryManifestInspect)).Methods(http.MethodGet)
 	authenticatedRouter.PathPrefix("/registries/proxies/gitlab").Handler(httperror.LoggerHandler(handler.proxyRequestsToGitlabAPIWithoutRegistry))
 }
 
@@ -0,0 +1,294 @@
+package registries
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/internal/registryclient"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type registryTagDeleteResponse struct {
+	// Digest of the deleted manifest
+	Digest string `example:"sha256:2a8f2d5c1b8bfa4f8e2a1c1f8f5d41e3c3d5f3e8c1f6b2e0f7e9d4b3a2c1e0f9"`
+}
+
+// @id RegistryRepositoryList
+// @summary List the repositories of a registry
+// @description List the repositories of a registry through the Docker Registry HTTP API V2.
+// @description Docker Hub does not support listing repositories, GitLab registries list the repositories of the configured project.
+// @description **Access policy**: restricted
+// @tags registries
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Registry identifier"
+// @param endpointId query int false "Environment identifier used to validate the access to the registry, required for non administrators"
+// @param n query int false "Maximum number of repositories to return"
+// @param last query string false "Value of the Next property of the previous page"
+// @success 200 {object} registryclient.Page "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied to access registry"
+// @failure 404 "Registry not found"
+// @failure 500 "Server error"
+// @router /registries/{id}/v2/repositories [get]
+func (handler *Handler) registryRepositoryList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	pageSize, last, err := retrievePageParameters(r)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameters", err)
+	}
+
+	client, httpErr := handler.registryClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	page, err := client.Repositories(r.Context(), pageSize, last)
+	if err != nil {
+		return registryClientErrorResponse("Unable to list the repositories of the registry", err)
+	}
+
+	return response.JSON(w, page)
+}
+
+// @id RegistryTagList
+// @summary List the tags of a repository
+// @description List the tags of a repository of a registry through the Docker Registry HTTP API V2.
+// @description **Access policy**: restricted
+// @tags registries
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Registry identifier"
+// @param repository path string true "Repository name"
+// @param endpointId query int false "Environment identifier used to validate the access to the registry, required for non administrators"
+// @param n query int false "Maximum number of tags to return"
+// @param last query string false "Value of the Next property of the previous page"
+// @success 200 {object} registryclient.Page "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied to access registry"
+// @failure 404 "Registry or repository not found"
+// @failure 500 "Server error"
+// @router /registries/{id}/v2/repositories/{repository}/tags [get]
+func (handler *Handler) registryTagList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	repository, err := retrieveRepository(r)
+	if err != nil {
+		return httperror.BadRequest("Invalid repository route variable", err)
+	}
+
+	pageSize, last, err := retrievePageParameters(r)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameters", err)
+	}
+
+	client, httpErr := handler.registryClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	page, err := client.Tags(r.Context(), repository, pageSize, last)
+	if err != nil {
+		return registryClientErrorResponse("Unable to list the tags of the repository", err)
+	}
+
+	return response.JSON(w, page)
+}
+
+// @id RegistryManifestInspect
+// @summary Inspect a manifest
+// @description Retrieve the manifest of a repository matching a tag or a digest.
+// @description **Access policy**: restricted
+// @tags registries
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Registry identifier"
+// @param repository path string true "Repository name"
+// @param reference path string true "Tag or digest"
+// @param endpointId query int false "Environment identifier used to validate the access to the registry, required for non administrators"
+// @success 200 {object} registryclient.Manifest "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied to access registry"
+// @failure 404 "Registry or manifest not found"
+// @failure 500 "Server error"
+// @router /registries/{id}/v2/repositories/{repository}/manifests/{reference} [get]
+func (handler *Handler) registryManifestInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	repository, err := retrieveRepository(r)
+	if err != nil {
+		return httperror.BadRequest("Invalid repository route variable", err)
+	}
+
+	reference, err := request.RetrieveRouteVariableValue(r, "reference")
+	if err != nil {
+		return httperror.BadRequest("Invalid reference route variable", err)
+	}
+
+	if err := registryclient.ValidateReference(reference); err != nil {
+		return httperror.BadRequest("Invalid reference route variable", err)
+	}
+
+	client, httpErr := handler.registryClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	manifest, err := client.Manifest(r.Context(), repository, reference)
+	if err != nil {
+		return registryClientErrorResponse("Unable to retrieve the manifest", err)
+	}
+
+	return response.JSON(w, manifest)
+}
+
+// @id RegistryTagDelete
+// @summary Delete a tag
+// @description Delete the manifest referenced by a tag. Every other tag referencing the same manifest is deleted as well.
+// @description **Access policy**: administrator
+// @tags registries
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Registry identifier"
+// @param repository path string true "Repository name"
+// @param tag path string true "Tag"
+// @success 200 {object} registryTagDeleteResponse "Success"
+// @failure 400 "Invalid request or the registry does not allow deletions"
+// @failure 403 "Permission denied"
+// @failure 404 "Registry or tag not found"
+// @failure 500 "Server error"
+// @router /registries/{id}/v2/repositories/{repository}/tags/{tag} [delete]
+func (handler *Handler) registryTagDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	repository, err := retrieveRepository(r)
+	if err != nil {
+		return httperror.BadRequest("Invalid repository route variable", err)
+	}
+
+	tag, err := request.RetrieveRouteVariableValue(r, "tag")
+	if err != nil {
+		return httperror.BadRequest("Invalid tag route variable", err)
+	}
+
+	if err := registryclient.ValidateTag(tag); err != nil {
+		return httperror.BadRequest("Invalid tag route variable", err)
+	}
+
+	client, httpErr := handler.registryClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	digest, err := client.DeleteTag(r.Context(), repository, tag)
+	if err != nil {
+		return registryClientErrorResponse("Unable to delete the tag", err)
+	}
+
+	return response.JSON(w, registryTagDeleteResponse{Digest: digest})
+}
+
+// @id RegistryManifestDelete
+// @summary Delete a manifest
+// @description Delete a manifest by digest. Every tag referencing the manifest is deleted as well.
+// @description **Access policy**: administrator
+// @tags registries
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Registry identifier"
+// @param repository path string true "Repository name"
+// @param reference path string true "Manifest digest"
+// @success 204 "Success"
+// @failure 400 "Invalid request or the registry does not allow deletions"
+// @failure 403 "Permission denied"
+// @failure 404 "Registry or manifest not found"
+// @failure 500 "Server error"
+// @router /registries/{id}/v2/repositories/{repository}/manifests/{reference} [delete]
+func (handler *Handler) registryManifestDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	repository, err := retrieveRepository(r)
+	if err != nil {
+		return httperror.BadRequest("Invalid repository route variable", err)
+	}
+
+	digest, err := request.RetrieveRouteVariableValue(r, "reference")
+	if err != nil {
+		return httperror.BadRequest("Invalid digest route variable", err)
+	}
+
+	client, httpErr := handler.registryClient(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if err := client.DeleteManifest(r.Context(), repository, digest); err != nil {
+		return registryClientErrorResponse("Unable to delete the manifest", err)
+	}
+
+	return response.Empty(w)
+}
+
+// registryClient reads the registry targeted by the request, verifies that the user can access it and returns a client for it
+func (handler *Handler) registryClient(r *http.Request) (*registryclient.Client, *httperror.HandlerError) {
+	registryID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, httperror.BadRequest("Invalid registry identifier route variable", err)
+	}
+
+	registry, err := handler.DataStore.Registry().Read(portainer.RegistryID(registryID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find a registry with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find a registry with the specified identifier inside the database", err)
+	}
+
+	hasAccess, _, err := handler.userHasRegistryAccess(r, registry)
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve info from request context", err)
+	}
+	if !hasAccess {
+		return nil, httperror.Forbidden("Access denied to resource", httperrors.ErrResourceAccessDenied)
+	}
+
+	client, err := handler.RegistryClientService.NewClient(registry)
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to create a client for the registry", err)
+	}
+
+	return client, nil
+}
+
+func retrieveRepository(r *http.Request) (string, error) {
+	repository, err := request.RetrieveRouteVariableValue(r, "repository")
+	if err != nil {
+		return "", err
+	}
+
+	return repository, registryclient.ValidateRepository(repository)
+}
+
+func retrievePageParameters(r *http.Request) (int, string, error) {
+	pageSize, err := request.RetrieveNumericQueryParameter(r, "n", true)
+	if err != nil {
+		return 0, "", err
+	}
+
+	if pageSize < 0 {
+		return 0, "", errors.New("the page size must be positive")
+	}
+
+	last, _ := request.RetrieveQueryParameter(r, "last", true)
+
+	return pageSize, last, nil
+}
+
+func registryClientErrorResponse(message string, err error) *httperror.HandlerError {
+	switch {
+	case errors.Is(err, registryclient.ErrNotFound):
+		return httperror.NotFound(message, err)
+	case errors.Is(err, registryclient.ErrCatalogNotSupported), errors.Is(err, registryclient.ErrDeleteNotSupported):
+		return httperror.BadRequest(message, err)
+	}
+
+	return httperror.InternalServerError(message, err)
+}
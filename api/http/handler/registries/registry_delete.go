@@ -1,10 +1,13 @@
 package registries
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
 	httperrors "github.com/portainer/portainer/api/http/errors"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/pendingactions/handlers"
@@ -41,13 +44,46 @@ func (handler *Handler) registryDelete(w http.ResponseWriter, r *http.Request) *
 		return httperror.BadRequest("Invalid registry identifier route variable", err)
 	}
 
-	registry, err := handler.DataStore.Registry().Read(portainer.RegistryID(registryID))
+	registry, err := dataservices.UpdateTx(handler.DataStore, func(tx dataservices.DataStoreTx) (*portainer.Registry, error) {
+		registry, err := tx.Registry().Read(portainer.RegistryID(registryID))
+		if tx.IsErrObjectNotFound(err) {
+			return nil, httperror.NotFound("Unable to find a registry with the specified identifier inside the database", err)
+		} else if err != nil {
+			return nil, httperror.InternalServerError(fmt.Sprintf("Unable to load registry %d from the database", registryID), err)
+		}
+
+		if err := tx.Registry().Delete(registry.ID); err != nil {
+			return nil, httperror.InternalServerError("Unable to remove the registry from the database", err)
+		}
+
+		teams, err := tx.Team().ReadAll()
+		if err != nil {
+			return nil, httperror.InternalServerError("Unable to retrieve teams from the database", err)
+		}
+
+		for _, team := range teams {
+			if !slices.Contains(team.DefaultRegistries, registry.ID) {
+				continue
+			}
+
+			team.DefaultRegistries = slices.DeleteFunc(team.DefaultRegistries, func(id portainer.RegistryID) bool {
+				return id == registry.ID
+			})
+
+			if err := tx.Team().Update(team.ID, &team); err != nil {
+				return nil, httperror.InternalServerError("Unable to persist team changes inside the database", err)
+			}
+		}
+
+		return registry, nil
+	})
 	if err != nil {
-		return httperror.InternalServerError(fmt.Sprintf("Unable to load registry %q from the database", registry.Name), err)
-	}
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
 
-	if err := handler.DataStore.Registry().Delete(portainer.RegistryID(registryID)); err != nil {
-		return httperror.InternalServerError("Unable to remove the registry from the database", err)
+		return httperror.InternalServerError("Unexpected error", err)
 	}
 
 	handler.deleteKubernetesSecrets(registry)
@@ -0,0 +1,25 @@
+package registries
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id RegistryNamespaceAccessDrift
+// @summary Inspect the drifts of the registry pull secrets
+// @description Retrieve the report of the last reconciliation of the registry pull secrets with the Kubernetes
+// @description namespaces the registries are assigned to. The missing secrets are created and the stale secrets are removed
+// @description by the reconciliation.
+// @description **Access policy**: administrator
+// @tags registries
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {object} registryaccess.Report "Success"
+// @failure 500 "Server error"
+// @router /registries/namespace_access/drift [get]
+func (handler *Handler) registryNamespaceAccessDrift(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return response.JSON(w, handler.RegistryAccessService.LastReport())
+}
@@ -0,0 +1,140 @@
+package reports
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/notifications"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+const (
+	defaultCronExpression = "0 8 * * 1"
+	defaultPeriod         = 7
+	maxPeriod             = 366
+)
+
+type groupReportCreatePayload struct {
+	Name string `example:"weekly-production"`
+	// Environment(Endpoint) group summarized by the report
+	EndpointGroupID portainer.EndpointGroupID `example:"1"`
+	// Template rendering the report, the built-in template is used when 0
+	TemplateID portainer.ReportTemplateID `example:"0"`
+	// Schedule of the report, every Monday at 8:00 when empty
+	CronExpression string `example:"0 8 * * 1"`
+	// Number of days covered by the report, 7 when 0
+	Period int `example:"7"`
+	// Email addresses the report is sent to
+	Recipients []string `example:"ops@example.com"`
+	// Whether the report is scheduled
+	Enabled bool `example:"true"`
+}
+
+func (payload *groupReportCreatePayload) Validate(r *http.Request) error {
+	if payload.Name == "" {
+		return errors.New("invalid group report name")
+	}
+
+	if payload.EndpointGroupID == 0 {
+		return errors.New("invalid environment group identifier")
+	}
+
+	if payload.CronExpression == "" {
+		payload.CronExpression = defaultCronExpression
+	}
+
+	if payload.Period == 0 {
+		payload.Period = defaultPeriod
+	}
+
+	return validateGroupReport(payload.CronExpression, payload.Period, payload.Recipients)
+}
+
+func validateGroupReport(cronExpression string, period int, recipients []string) error {
+	if err := validateCronExpression(cronExpression); err != nil {
+		return err
+	}
+
+	if period < 1 || period > maxPeriod {
+		return errors.New("invalid period, it must be between 1 and 366 days")
+	}
+
+	if len(recipients) == 0 {
+		return errors.New("invalid recipients, at least one email address is required")
+	}
+
+	return notifications.ValidateRecipients(recipients)
+}
+
+// @id GroupReportCreate
+// @summary Create a group report
+// @description Create a report summarizing on a schedule the deployments, incidents, resource usage and offline environments
+// @description of an environment group. The report is sent by email through the SMTP server defined in the settings.
+// @description **Access policy**: administrator
+// @tags group_reports
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body groupReportCreatePayload true "Group report details"
+// @success 200 {object} portainer.GroupReport
+// @failure 400
+// @failure 500
+// @router /group_reports [post]
+func (handler *Handler) groupReportCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload groupReportCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	groupReport := &portainer.GroupReport{
+		Name:            payload.Name,
+		EndpointGroupID: payload.EndpointGroupID,
+		TemplateID:      payload.TemplateID,
+		CronExpression:  payload.CronExpression,
+		Period:          payload.Period,
+		Recipients:      payload.Recipients,
+		Enabled:         payload.Enabled,
+		Created:         time.Now().Unix(),
+	}
+
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := validateReferences(tx, groupReport.EndpointGroupID, groupReport.TemplateID); err != nil {
+			return err
+		}
+
+		return tx.GroupReport().Create(groupReport)
+	}); err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	if err := handler.ReportService.Schedule(groupReport); err != nil {
+		return httperror.InternalServerError("Unable to schedule the group report", err)
+	}
+
+	return txResponse(w, groupReport, nil)
+}
+
+func validateReferences(tx dataservices.DataStoreTx, endpointGroupID portainer.EndpointGroupID, templateID portainer.ReportTemplateID) error {
+	if _, err := tx.EndpointGroup().Read(endpointGroupID); tx.IsErrObjectNotFound(err) {
+		return httperror.BadRequest("Unable to find an environment group with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an environment group with the specified identifier inside the database", err)
+	}
+
+	if templateID == 0 {
+		return nil
+	}
+
+	if _, err := tx.ReportTemplate().Read(templateID); tx.IsErrObjectNotFound(err) {
+		return httperror.BadRequest("Unable to find a report template with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a report template with the specified identifier inside the database", err)
+	}
+
+	return nil
+}
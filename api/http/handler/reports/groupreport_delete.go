@@ -0,0 +1,45 @@
+package reports
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id GroupReportDelete
+// @summary Delete a group report
+// @description **Access policy**: administrator
+// @tags group_reports
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Group report identifier"
+// @success 204
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /group_reports/{id} [delete]
+func (handler *Handler) groupReportDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	reportID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid group report identifier route variable", err)
+	}
+
+	id := portainer.GroupReportID(reportID)
+
+	if _, err := handler.DataStore.GroupReport().Read(id); handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a group report with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a group report with the specified identifier inside the database", err)
+	}
+
+	handler.ReportService.Unschedule(id)
+
+	if err := handler.DataStore.GroupReport().Delete(id); err != nil {
+		return httperror.InternalServerError("Unable to remove the group report from the database", err)
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,39 @@
+package reports
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id GroupReportInspect
+// @summary Inspect a group report
+// @description **Access policy**: administrator
+// @tags group_reports
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Group report identifier"
+// @success 200 {object} portainer.GroupReport
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /group_reports/{id} [get]
+func (handler *Handler) groupReportInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	reportID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid group report identifier route variable", err)
+	}
+
+	groupReport, err := handler.DataStore.GroupReport().Read(portainer.GroupReportID(reportID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a group report with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a group report with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, groupReport)
+}
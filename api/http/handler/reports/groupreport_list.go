@@ -0,0 +1,27 @@
+package reports
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id GroupReportList
+// @summary List the group reports
+// @description **Access policy**: administrator
+// @tags group_reports
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.GroupReport
+// @failure 500
+// @router /group_reports [get]
+func (handler *Handler) groupReportList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	groupReports, err := handler.DataStore.GroupReport().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve group reports from the database", err)
+	}
+
+	return response.JSON(w, groupReports)
+}
@@ -0,0 +1,53 @@
+package reports
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type groupReportPreviewResponse struct {
+	// Subject of the email
+	Subject string `example:"production report, 2024-01-01 to 2024-01-08"`
+	// HTML body of the email
+	Body string
+}
+
+// @id GroupReportPreview
+// @summary Preview a group report
+// @description Render the email of a group report as it would be sent now, without sending it.
+// @description **Access policy**: administrator
+// @tags group_reports
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Group report identifier"
+// @success 200 {object} groupReportPreviewResponse
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /group_reports/{id}/preview [get]
+func (handler *Handler) groupReportPreview(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	reportID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid group report identifier route variable", err)
+	}
+
+	id := portainer.GroupReportID(reportID)
+
+	if _, err := handler.DataStore.GroupReport().Read(id); handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a group report with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a group report with the specified identifier inside the database", err)
+	}
+
+	subject, body, err := handler.ReportService.Preview(id)
+	if err != nil {
+		return httperror.InternalServerError("Unable to render the group report", err)
+	}
+
+	return response.JSON(w, groupReportPreviewResponse{Subject: subject, Body: body})
+}
@@ -0,0 +1,131 @@
+package reports
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type groupReportUpdatePayload struct {
+	Name *string `example:"weekly-production"`
+	// Environment(Endpoint) group summarized by the report
+	EndpointGroupID *portainer.EndpointGroupID `example:"1"`
+	// Template rendering the report, the built-in template is used when 0
+	TemplateID *portainer.ReportTemplateID `example:"0"`
+	// Schedule of the report
+	CronExpression *string `example:"0 8 * * 1"`
+	// Number of days covered by the report
+	Period *int `example:"7"`
+	// Email addresses the report is sent to
+	Recipients []string `example:"ops@example.com"`
+	// Whether the report is scheduled
+	Enabled *bool `example:"true"`
+}
+
+func (payload *groupReportUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("invalid group report name")
+	}
+
+	if payload.EndpointGroupID != nil && *payload.EndpointGroupID == 0 {
+		return errors.New("invalid environment group identifier")
+	}
+
+	return nil
+}
+
+// @id GroupReportUpdate
+// @summary Update a group report
+// @description **Access policy**: administrator
+// @tags group_reports
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Group report identifier"
+// @param body body groupReportUpdatePayload true "Group report details"
+// @success 200 {object} portainer.GroupReport
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /group_reports/{id} [put]
+func (handler *Handler) groupReportUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	reportID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid group report identifier route variable", err)
+	}
+
+	var payload groupReportUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var groupReport *portainer.GroupReport
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		groupReport, err = updateGroupReport(tx, portainer.GroupReportID(reportID), payload)
+		return err
+	}); err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	if err := handler.ReportService.Schedule(groupReport); err != nil {
+		return httperror.InternalServerError("Unable to schedule the group report", err)
+	}
+
+	return txResponse(w, groupReport, nil)
+}
+
+func updateGroupReport(tx dataservices.DataStoreTx, reportID portainer.GroupReportID, payload groupReportUpdatePayload) (*portainer.GroupReport, error) {
+	groupReport, err := tx.GroupReport().Read(reportID)
+	if tx.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find a group report with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find a group report with the specified identifier inside the database", err)
+	}
+
+	if payload.Name != nil {
+		groupReport.Name = *payload.Name
+	}
+
+	if payload.EndpointGroupID != nil {
+		groupReport.EndpointGroupID = *payload.EndpointGroupID
+	}
+
+	if payload.TemplateID != nil {
+		groupReport.TemplateID = *payload.TemplateID
+	}
+
+	if payload.CronExpression != nil {
+		groupReport.CronExpression = *payload.CronExpression
+	}
+
+	if payload.Period != nil {
+		groupReport.Period = *payload.Period
+	}
+
+	if payload.Recipients != nil {
+		groupReport.Recipients = payload.Recipients
+	}
+
+	if payload.Enabled != nil {
+		groupReport.Enabled = *payload.Enabled
+	}
+
+	if err := validateGroupReport(groupReport.CronExpression, groupReport.Period, groupReport.Recipients); err != nil {
+		return nil, httperror.BadRequest("Invalid request payload", err)
+	}
+
+	if err := validateReferences(tx, groupReport.EndpointGroupID, groupReport.TemplateID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.GroupReport().Update(groupReport.ID, groupReport); err != nil {
+		return nil, httperror.InternalServerError("Unable to persist group report changes inside the database", err)
+	}
+
+	return groupReport, nil
+}
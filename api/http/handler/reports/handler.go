@@ -0,0 +1,78 @@
+package reports
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/reports"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/gorilla/mux"
+	"github.com/robfig/cron/v3"
+)
+
+// Handler is the HTTP handler used to handle group report and report template operations.
+type Handler struct {
+	*mux.Router
+	DataStore     dataservices.DataStore
+	ReportService *reports.Service
+}
+
+// NewHandler creates a handler to manage group report and report template operations.
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+
+	h.Handle("/group_reports",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.groupReportList))).Methods(http.MethodGet)
+	h.Handle("/group_reports",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.groupReportCreate))).Methods(http.MethodPost)
+	h.Handle("/group_reports/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.groupReportInspect))).Methods(http.MethodGet)
+	h.Handle("/group_reports/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.groupReportUpdate))).Methods(http.MethodPut)
+	h.Handle("/group_reports/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.groupReportDelete))).Methods(http.MethodDelete)
+	h.Handle("/group_reports/{id}/preview",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.groupReportPreview))).Methods(http.MethodGet)
+	h.Handle("/group_reports/{id}/run",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.groupReportRun))).Methods(http.MethodPost)
+
+	h.Handle("/report_templates",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.reportTemplateList))).Methods(http.MethodGet)
+	h.Handle("/report_templates",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.reportTemplateCreate))).Methods(http.MethodPost)
+	h.Handle("/report_templates/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.reportTemplateInspect))).Methods(http.MethodGet)
+	h.Handle("/report_templates/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.reportTemplateUpdate))).Methods(http.MethodPut)
+	h.Handle("/report_templates/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.reportTemplateDelete))).Methods(http.MethodDelete)
+
+	return h
+}
+
+func validateCronExpression(cronExpression string) error {
+	if _, err := cron.ParseStandard(cronExpression); err != nil {
+		return errors.New("invalid cron expression")
+	}
+
+	return nil
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
@@ -0,0 +1,68 @@
+package reports
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/reports"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type reportTemplateCreatePayload struct {
+	Name string `example:"weekly-summary"`
+	// Subject of the emails, a Go text template
+	Subject string `example:"{{ .GroupName }} weekly report"`
+	// Body of the emails, a Go HTML template
+	Body string
+}
+
+func (payload *reportTemplateCreatePayload) Validate(r *http.Request) error {
+	if payload.Name == "" {
+		return errors.New("invalid report template name")
+	}
+
+	if payload.Subject == "" || payload.Body == "" {
+		return errors.New("invalid report template, the subject and the body are required")
+	}
+
+	return reports.ValidateTemplate(payload.Subject, payload.Body)
+}
+
+// @id ReportTemplateCreate
+// @summary Create a report template
+// @description Create a template rendering the emails of the group reports. The subject is a Go text template and the body
+// @description a Go HTML template, both rendered with the summary of the group.
+// @description **Access policy**: administrator
+// @tags group_reports
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body reportTemplateCreatePayload true "Report template details"
+// @success 200 {object} portainer.ReportTemplate
+// @failure 400
+// @failure 500
+// @router /report_templates [post]
+func (handler *Handler) reportTemplateCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload reportTemplateCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	template := &portainer.ReportTemplate{
+		Name:    payload.Name,
+		Subject: payload.Subject,
+		Body:    payload.Body,
+		Created: time.Now().Unix(),
+	}
+
+	if err := handler.DataStore.ReportTemplate().Create(template); err != nil {
+		return httperror.InternalServerError("Unable to persist the report template inside the database", err)
+	}
+
+	return response.JSON(w, template)
+}
@@ -0,0 +1,64 @@
+package reports
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id ReportTemplateDelete
+// @summary Delete a report template
+// @description Delete a report template, the templates used by group reports cannot be deleted.
+// @description **Access policy**: administrator
+// @tags group_reports
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Report template identifier"
+// @success 204
+// @failure 400
+// @failure 404
+// @failure 409 "The template is used by a group report"
+// @failure 500
+// @router /report_templates/{id} [delete]
+func (handler *Handler) reportTemplateDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	templateID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid report template identifier route variable", err)
+	}
+
+	id := portainer.ReportTemplateID(templateID)
+
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if _, err := tx.ReportTemplate().Read(id); tx.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a report template with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a report template with the specified identifier inside the database", err)
+		}
+
+		groupReports, err := tx.GroupReport().ReadAll()
+		if err != nil {
+			return httperror.InternalServerError("Unable to retrieve group reports from the database", err)
+		}
+
+		for _, groupReport := range groupReports {
+			if groupReport.TemplateID == id {
+				return httperror.Conflict("The report template is used by a group report", errors.New("report template in use"))
+			}
+		}
+
+		if err := tx.ReportTemplate().Delete(id); err != nil {
+			return httperror.InternalServerError("Unable to remove the report template from the database", err)
+		}
+
+		return nil
+	}); err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,39 @@
+package reports
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id ReportTemplateInspect
+// @summary Inspect a report template
+// @description **Access policy**: administrator
+// @tags group_reports
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Report template identifier"
+// @success 200 {object} portainer.ReportTemplate
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /report_templates/{id} [get]
+func (handler *Handler) reportTemplateInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	templateID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid report template identifier route variable", err)
+	}
+
+	template, err := handler.DataStore.ReportTemplate().Read(portainer.ReportTemplateID(templateID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a report template with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a report template with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, template)
+}
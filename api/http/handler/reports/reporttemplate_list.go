@@ -0,0 +1,27 @@
+package reports
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id ReportTemplateList
+// @summary List the report templates
+// @description **Access policy**: administrator
+// @tags group_reports
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.ReportTemplate
+// @failure 500
+// @router /report_templates [get]
+func (handler *Handler) reportTemplateList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	templates, err := handler.DataStore.ReportTemplate().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve report templates from the database", err)
+	}
+
+	return response.JSON(w, templates)
+}
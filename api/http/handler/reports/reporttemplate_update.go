@@ -0,0 +1,88 @@
+package reports
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/reports"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type reportTemplateUpdatePayload struct {
+	Name *string `example:"weekly-summary"`
+	// Subject of the emails, a Go text template
+	Subject *string `example:"{{ .GroupName }} weekly report"`
+	// Body of the emails, a Go HTML template
+	Body *string
+}
+
+func (payload *reportTemplateUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("invalid report template name")
+	}
+
+	if (payload.Subject != nil && *payload.Subject == "") || (payload.Body != nil && *payload.Body == "") {
+		return errors.New("invalid report template, the subject and the body are required")
+	}
+
+	return nil
+}
+
+// @id ReportTemplateUpdate
+// @summary Update a report template
+// @description **Access policy**: administrator
+// @tags group_reports
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Report template identifier"
+// @param body body reportTemplateUpdatePayload true "Report template details"
+// @success 200 {object} portainer.ReportTemplate
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /report_templates/{id} [put]
+func (handler *Handler) reportTemplateUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	templateID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid report template identifier route variable", err)
+	}
+
+	var payload reportTemplateUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	template, err := handler.DataStore.ReportTemplate().Read(portainer.ReportTemplateID(templateID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a report template with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a report template with the specified identifier inside the database", err)
+	}
+
+	if payload.Name != nil {
+		template.Name = *payload.Name
+	}
+
+	if payload.Subject != nil {
+		template.Subject = *payload.Subject
+	}
+
+	if payload.Body != nil {
+		template.Body = *payload.Body
+	}
+
+	if err := reports.ValidateTemplate(template.Subject, template.Body); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	if err := handler.DataStore.ReportTemplate().Update(template.ID, template); err != nil {
+		return httperror.InternalServerError("Unable to persist report template changes inside the database", err)
+	}
+
+	return response.JSON(w, template)
+}
@@ -14,8 +14,9 @@ type resourceControlCreatePayload struct {
 	//
 	ResourceID string `example:"617c5f22bb9b023d6daab7cba43a57576f83492867bc767d1c59416b065e5f08" validate:"required"`
 	// Type of Resource. Valid values are: 1 - container, 2 - service
-	// 3 - volume, 4 - network, 5 - secret, 6 - stack, 7 - config, 8 - custom template, 9 - azure-container-group
-	Type portainer.ResourceControlType `example:"1" validate:"required" enums:"1,2,3,4,5,6,7,8,9"`
+	// 3 - volume, 4 - network, 5 - secret, 6 - stack, 7 - config, 8 - custom template, 9 - azure-container-group,
+	// 10 - kubernetes-namespace (the identifier of the resource is <environment id>_<namespace>)
+	Type portainer.ResourceControlType `example:"1" validate:"required" enums:"1,2,3,4,5,6,7,8,9,10"`
 	// Permit access to the associated resource to any user
 	Public bool `example:"true"`
 	// Permit access to resource only to admins
@@ -35,8 +36,8 @@ func (payload *resourceControlCreatePayload) Validate(r *http.Request) error {
 		return errors.New("invalid payload: invalid resource identifier")
 	}
 
-	if payload.Type <= 0 || payload.Type >= 10 {
-		return errors.New("invalid payload: Invalid type value. Value must be one of: 1 - container, 2 - service, 3 - volume, 4 - network, 5 - secret, 6 - stack, 7 - config, 8 - custom template, 9 - azure-container-group")
+	if payload.Type <= 0 || payload.Type > portainer.KubernetesNamespaceResourceControl {
+		return errors.New("invalid payload: Invalid type value. Value must be one of: 1 - container, 2 - service, 3 - volume, 4 - network, 5 - secret, 6 - stack, 7 - config, 8 - custom template, 9 - azure-container-group, 10 - kubernetes-namespace")
 	}
 
 	if len(payload.Users) == 0 && len(payload.Teams) == 0 && !payload.Public && !payload.AdministratorsOnly {
@@ -51,7 +52,7 @@ func (payload *resourceControlCreatePayload) Validate(r *http.Request) error {
 
 // @id ResourceControlCreate
 // @summary Create a new resource control
-// @description Create a new resource control to restrict access to a Docker resource.
+// @description Create a new resource control to restrict access to a Docker resource or a Kubernetes namespace.
 // @description **Access policy**: administrator
 // @tags resource_controls
 // @security ApiKeyAuth
@@ -1,19 +1,29 @@
 package roles
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
 
 	"github.com/gorilla/mux"
 )
 
+var (
+	errBuiltInRole    = errors.New("The built-in roles cannot be modified")
+	errRoleNameExists = errors.New("A role with the same name already exists")
+	errRoleInUse      = errors.New("The role is used by access policies")
+)
+
 // Handler is the HTTP handler used to handle role operations.
 type Handler struct {
 	*mux.Router
-	DataStore dataservices.DataStore
+	DataStore            dataservices.DataStore
+	AuthorizationService *authorization.Service
 }
 
 // NewHandler creates a handler to manage role operations.
@@ -23,6 +33,29 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 	}
 	h.Handle("/roles",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.roleList))).Methods(http.MethodGet)
+	h.Handle("/roles",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.roleCreate))).Methods(http.MethodPost)
+	h.Handle("/roles/authorizations",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.roleAuthorizations))).Methods(http.MethodGet)
+	h.Handle("/roles/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.roleInspect))).Methods(http.MethodGet)
+	h.Handle("/roles/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.roleUpdate))).Methods(http.MethodPut)
+	h.Handle("/roles/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.roleDelete))).Methods(http.MethodDelete)
 
 	return h
 }
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
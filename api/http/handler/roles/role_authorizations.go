@@ -0,0 +1,34 @@
+package roles
+
+import (
+	"net/http"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/authorization"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id RoleAuthorizations
+// @summary List the authorizations of the roles
+// @description List the authorizations which can be granted by a custom role on an environment(endpoint).
+// @description **Access policy**: administrator
+// @tags roles
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} string "Success"
+// @router /roles/authorizations [get]
+func (handler *Handler) roleAuthorizations(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	available := authorization.RoleAuthorizations()
+
+	authorizations := make([]portainer.Authorization, 0, len(available))
+	for a := range available {
+		authorizations = append(authorizations, a)
+	}
+
+	slices.Sort(authorizations)
+
+	return response.JSON(w, authorizations)
+}
@@ -0,0 +1,121 @@
+package roles
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/authorization"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type roleCreatePayload struct {
+	// Role name
+	Name string `validate:"required" example:"Operator"`
+	// Role description
+	Description string `example:"Start, stop and inspect the containers of an environment"`
+	// Authorizations granted by the role, see /roles/authorizations for the available authorizations
+	Authorizations portainer.Authorizations `validate:"required"`
+	// When a user is given several roles on an environment through their teams, the authorizations of the role with
+	// the highest priority are used. Defaults to a priority above the priorities of the existing roles
+	Priority int `example:"5"`
+}
+
+func (payload *roleCreatePayload) Validate(r *http.Request) error {
+	if strings.TrimSpace(payload.Name) == "" {
+		return errors.New("invalid role name")
+	}
+
+	return validateRoleAuthorizations(payload.Authorizations, payload.Priority)
+}
+
+func validateRoleAuthorizations(authorizations portainer.Authorizations, priority int) error {
+	if len(authorizations) == 0 {
+		return errors.New("a role must grant at least one authorization")
+	}
+
+	if priority < 0 {
+		return errors.New("invalid priority. Must be a positive number")
+	}
+
+	return authorization.ValidateRoleAuthorizations(authorizations)
+}
+
+// @id RoleCreate
+// @summary Create a custom role
+// @description Create a role granting a set of authorizations, it can then be used in the user and team access
+// @description policies of the environments(endpoints) and of the environment(endpoint) groups.
+// @description **Access policy**: administrator
+// @tags roles
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body roleCreatePayload true "Role details"
+// @success 200 {object} portainer.Role "Success"
+// @failure 400 "Invalid request"
+// @failure 409 "A role with the same name already exists"
+// @failure 500 "Server error"
+// @router /roles [post]
+func (handler *Handler) roleCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload roleCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var role *portainer.Role
+	err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		var err error
+		role, err = createRole(tx, &payload)
+
+		return err
+	})
+
+	return txResponse(w, role, err)
+}
+
+func createRole(tx dataservices.DataStoreTx, payload *roleCreatePayload) (*portainer.Role, error) {
+	roles, err := tx.Role().ReadAll()
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve the roles from the database", err)
+	}
+
+	if !isRoleNameUnique(roles, payload.Name, 0) {
+		return nil, httperror.Conflict("A role with the same name already exists", errRoleNameExists)
+	}
+
+	role := &portainer.Role{
+		Name:           payload.Name,
+		Description:    payload.Description,
+		Authorizations: payload.Authorizations,
+		Priority:       payload.Priority,
+		IsCustom:       true,
+	}
+
+	if role.Priority == 0 {
+		for _, r := range roles {
+			role.Priority = max(role.Priority, r.Priority)
+		}
+
+		role.Priority++
+	}
+
+	if err := tx.Role().Create(role); err != nil {
+		return nil, httperror.InternalServerError("Unable to persist the role inside the database", err)
+	}
+
+	return role, nil
+}
+
+func isRoleNameUnique(roles []portainer.Role, name string, roleID portainer.RoleID) bool {
+	for _, role := range roles {
+		if role.ID != roleID && strings.EqualFold(role.Name, name) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,128 @@
+package roles
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/apikey"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+	"github.com/portainer/portainer/api/jwt"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_customRoles(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	adminUser := &portainer.User{ID: 1, Username: "admin", Role: portainer.AdministratorRole}
+	require.NoError(t, store.User().Create(adminUser))
+
+	builtInRole := &portainer.Role{Name: "Environment administrator", Priority: 1}
+	require.NoError(t, store.Role().Create(builtInRole))
+
+	jwtService, err := jwt.NewService("1h", store)
+	require.NoError(t, err)
+	apiKeyService := apikey.NewAPIKeyService(store.APIKeyRepository(), store.User())
+	requestBouncer := security.NewRequestBouncer(store, jwtService, apiKeyService)
+
+	h := NewHandler(requestBouncer)
+	h.DataStore = store
+	h.AuthorizationService = authorization.NewService(store)
+
+	token, _, err := jwtService.GenerateToken(&portainer.TokenData{ID: adminUser.ID, Username: adminUser.Username, Role: adminUser.Role})
+	require.NoError(t, err)
+
+	_, tokenID, expiresAt, err := jwtService.ParseAndVerifyToken(token)
+	require.NoError(t, err)
+
+	session := &portainer.UserSession{UserID: adminUser.ID, TokenID: tokenID, IssuedAt: time.Now().Unix(), ExpiresAt: expiresAt.Unix()}
+	require.NoError(t, store.UserSession().Create(session))
+
+	do := func(method, url, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		testhelpers.AddTestSecurityCookie(req, token)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	var role portainer.Role
+
+	t.Run("admin can create a custom role", func(t *testing.T) {
+		rr := do(http.MethodPost, "/roles", `{"Name": "Operator", "Authorizations": {"DockerContainerStart": true, "DockerContainerStop": true}}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&role))
+		assert.True(t, role.IsCustom)
+		assert.Equal(t, "Operator", role.Name)
+
+		roles, err := store.Role().ReadAll()
+		require.NoError(t, err)
+
+		for _, r := range roles {
+			if r.ID != role.ID {
+				assert.Greater(t, role.Priority, r.Priority)
+			}
+		}
+	})
+
+	t.Run("duplicate role names are rejected", func(t *testing.T) {
+		rr := do(http.MethodPost, "/roles", `{"Name": "operator", "Authorizations": {"DockerContainerStart": true}}`)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+
+	t.Run("unknown authorizations are rejected", func(t *testing.T) {
+		rr := do(http.MethodPost, "/roles", `{"Name": "Invalid", "Authorizations": {"NotAnAuthorization": true}}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("admin can update a custom role", func(t *testing.T) {
+		rr := do(http.MethodPut, "/roles/"+strconv.Itoa(int(role.ID)), `{"Description": "Start and stop the containers"}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		updated, err := store.Role().Read(role.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Start and stop the containers", updated.Description)
+		assert.Len(t, updated.Authorizations, 2)
+	})
+
+	t.Run("built-in roles cannot be modified or removed", func(t *testing.T) {
+		rr := do(http.MethodPut, "/roles/"+strconv.Itoa(int(builtInRole.ID)), `{"Name": "Renamed"}`)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = do(http.MethodDelete, "/roles/"+strconv.Itoa(int(builtInRole.ID)), "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("roles used by access policies cannot be removed", func(t *testing.T) {
+		endpoint := &portainer.Endpoint{
+			ID:                 1,
+			Name:               "local",
+			UserAccessPolicies: portainer.UserAccessPolicies{adminUser.ID: {RoleID: role.ID}},
+		}
+		require.NoError(t, store.Endpoint().Create(endpoint))
+
+		rr := do(http.MethodDelete, "/roles/"+strconv.Itoa(int(role.ID)), "")
+		assert.Equal(t, http.StatusConflict, rr.Code)
+
+		require.NoError(t, store.Endpoint().DeleteEndpoint(endpoint.ID))
+
+		rr = do(http.MethodDelete, "/roles/"+strconv.Itoa(int(role.ID)), "")
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+
+		_, err := store.Role().Read(role.ID)
+		assert.True(t, store.IsErrObjectNotFound(err))
+	})
+}
@@ -0,0 +1,93 @@
+package roles
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id RoleDelete
+// @summary Remove a custom role
+// @description Remove a custom role. A role used by the access policies of an environment(endpoint) or of an
+// @description environment(endpoint) group cannot be removed, and the built-in roles cannot be removed.
+// @description **Access policy**: administrator
+// @tags roles
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Role identifier"
+// @success 204 "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "The built-in roles cannot be removed"
+// @failure 404 "Role not found"
+// @failure 409 "The role is used by access policies"
+// @failure 500 "Server error"
+// @router /roles/{id} [delete]
+func (handler *Handler) roleDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	roleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid role identifier route variable", err)
+	}
+
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return deleteRole(tx, portainer.RoleID(roleID))
+	})
+	if err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	return response.Empty(w)
+}
+
+func deleteRole(tx dataservices.DataStoreTx, roleID portainer.RoleID) error {
+	if _, err := readCustomRole(tx, roleID); err != nil {
+		return err
+	}
+
+	endpoints, err := tx.Endpoint().Endpoints()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve environments from the database", err)
+	}
+
+	for _, endpoint := range endpoints {
+		if usesRole(endpoint.UserAccessPolicies, endpoint.TeamAccessPolicies, roleID) {
+			return httperror.Conflict("The role is used by the access policies of the environment "+endpoint.Name, errRoleInUse)
+		}
+	}
+
+	endpointGroups, err := tx.EndpointGroup().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve environment groups from the database", err)
+	}
+
+	for _, endpointGroup := range endpointGroups {
+		if usesRole(endpointGroup.UserAccessPolicies, endpointGroup.TeamAccessPolicies, roleID) {
+			return httperror.Conflict("The role is used by the access policies of the environment group "+endpointGroup.Name, errRoleInUse)
+		}
+	}
+
+	if err := tx.Role().Delete(roleID); err != nil {
+		return httperror.InternalServerError("Unable to remove the role from the database", err)
+	}
+
+	return nil
+}
+
+func usesRole(userPolicies portainer.UserAccessPolicies, teamPolicies portainer.TeamAccessPolicies, roleID portainer.RoleID) bool {
+	for _, policy := range userPolicies {
+		if policy.RoleID == roleID {
+			return true
+		}
+	}
+
+	for _, policy := range teamPolicies {
+		if policy.RoleID == roleID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,144 @@
+package roles
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type roleUpdatePayload struct {
+	// Role name
+	Name string `example:"Operator"`
+	// Role description
+	Description *string `example:"Start, stop and inspect the containers of an environment"`
+	// Authorizations granted by the role, they replace the authorizations of the role when specified
+	Authorizations portainer.Authorizations
+	// Priority of the role
+	Priority *int `example:"5"`
+}
+
+func (payload *roleUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != "" && strings.TrimSpace(payload.Name) == "" {
+		return errors.New("invalid role name")
+	}
+
+	if payload.Priority != nil && *payload.Priority < 1 {
+		return errors.New("invalid priority. Must be a positive number")
+	}
+
+	if payload.Authorizations != nil {
+		return validateRoleAuthorizations(payload.Authorizations, 0)
+	}
+
+	return nil
+}
+
+// @id RoleUpdate
+// @summary Update a custom role
+// @description Update a custom role, the authorizations of the users are updated when the authorizations or the
+// @description priority of the role change. The built-in roles cannot be modified.
+// @description **Access policy**: administrator
+// @tags roles
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Role identifier"
+// @param body body roleUpdatePayload true "Role details"
+// @success 200 {object} portainer.Role "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "The built-in roles cannot be modified"
+// @failure 404 "Role not found"
+// @failure 409 "A role with the same name already exists"
+// @failure 500 "Server error"
+// @router /roles/{id} [put]
+func (handler *Handler) roleUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	roleID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid role identifier route variable", err)
+	}
+
+	var payload roleUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var role *portainer.Role
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		role, err = handler.updateRole(tx, portainer.RoleID(roleID), &payload)
+
+		return err
+	})
+
+	return txResponse(w, role, err)
+}
+
+func (handler *Handler) updateRole(tx dataservices.DataStoreTx, roleID portainer.RoleID, payload *roleUpdatePayload) (*portainer.Role, error) {
+	role, err := readCustomRole(tx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Name != "" {
+		roles, err := tx.Role().ReadAll()
+		if err != nil {
+			return nil, httperror.InternalServerError("Unable to retrieve the roles from the database", err)
+		}
+
+		if !isRoleNameUnique(roles, payload.Name, role.ID) {
+			return nil, httperror.Conflict("A role with the same name already exists", errRoleNameExists)
+		}
+
+		role.Name = payload.Name
+	}
+
+	if payload.Description != nil {
+		role.Description = *payload.Description
+	}
+
+	updateAuthorizations := false
+
+	if payload.Authorizations != nil && !maps.Equal(payload.Authorizations, role.Authorizations) {
+		role.Authorizations = payload.Authorizations
+		updateAuthorizations = true
+	}
+
+	if payload.Priority != nil && *payload.Priority != role.Priority {
+		role.Priority = *payload.Priority
+		updateAuthorizations = true
+	}
+
+	if err := tx.Role().Update(role.ID, role); err != nil {
+		return nil, httperror.InternalServerError("Unable to persist the role changes inside the database", err)
+	}
+
+	if updateAuthorizations {
+		if err := handler.AuthorizationService.UpdateUsersAuthorizationsTx(tx); err != nil {
+			return nil, httperror.InternalServerError("Unable to update user authorizations", err)
+		}
+	}
+
+	return role, nil
+}
+
+// readCustomRole returns the role with the specified identifier, the built-in roles are rejected
+func readCustomRole(tx dataservices.DataStoreTx, roleID portainer.RoleID) (*portainer.Role, error) {
+	role, err := tx.Role().Read(roleID)
+	if tx.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find a role with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find a role with the specified identifier inside the database", err)
+	}
+
+	if !role.IsCustom {
+		return nil, httperror.Forbidden("The built-in roles cannot be modified", errBuiltInRole)
+	}
+
+	return role, nil
+}
@@ -0,0 +1,201 @@
+package serviceaccounts
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/apikey"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/gorilla/mux"
+)
+
+var errServiceAccountNotFound = errors.New("the user is not a service account")
+
+// Handler is the HTTP handler used to handle service account operations.
+type Handler struct {
+	*mux.Router
+	DataStore            dataservices.DataStore
+	AuthorizationService *authorization.Service
+	apiKeyService        apikey.APIKeyService
+}
+
+// NewHandler creates a handler to manage service account operations.
+func NewHandler(bouncer security.BouncerService, apiKeyService apikey.APIKeyService) *Handler {
+	h := &Handler{
+		Router:        mux.NewRouter(),
+		apiKeyService: apiKeyService,
+	}
+
+	h.Handle("/service_accounts",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.serviceAccountList))).Methods(http.MethodGet)
+	h.Handle("/service_accounts",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.serviceAccountCreate))).Methods(http.MethodPost)
+	h.Handle("/service_accounts/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.serviceAccountInspect))).Methods(http.MethodGet)
+	h.Handle("/service_accounts/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.serviceAccountUpdate))).Methods(http.MethodPut)
+	h.Handle("/service_accounts/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.serviceAccountDelete))).Methods(http.MethodDelete)
+	h.Handle("/service_accounts/{id}/tokens",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.serviceAccountTokenCreate))).Methods(http.MethodPost)
+
+	return h
+}
+
+// EndpointAccess represents the role granted to a service account on an environment(endpoint)
+type EndpointAccess struct {
+	EndpointID portainer.EndpointID `json:"EndpointId" example:"1"`
+	RoleID     portainer.RoleID     `json:"RoleId" example:"1"`
+}
+
+// ServiceAccount represents a machine account used by automation, authenticated with API keys
+type ServiceAccount struct {
+	ID   portainer.UserID `json:"Id" example:"3"`
+	Name string           `json:"Name" example:"ci-pipeline"`
+	// Environments(endpoints) the service account can access, with the role granted on each of them
+	EndpointAccesses []EndpointAccess `json:"EndpointAccesses"`
+}
+
+func validateEndpointAccesses(accesses []EndpointAccess) error {
+	seen := make(map[portainer.EndpointID]struct{}, len(accesses))
+
+	for _, access := range accesses {
+		if access.EndpointID == 0 {
+			return errors.New("invalid environment identifier")
+		}
+
+		if access.RoleID == 0 {
+			return errors.New("invalid role identifier")
+		}
+
+		if _, ok := seen[access.EndpointID]; ok {
+			return errors.New("an environment can only be granted once")
+		}
+
+		seen[access.EndpointID] = struct{}{}
+	}
+
+	return nil
+}
+
+// readServiceAccount returns the user matching the identifier, a regular user is reported as not found
+func readServiceAccount(tx dataservices.DataStoreTx, id portainer.UserID) (*portainer.User, error) {
+	user, err := tx.User().Read(id)
+	if tx.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find a service account with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find a service account with the specified identifier inside the database", err)
+	}
+
+	if !user.ServiceAccount {
+		return nil, httperror.NotFound("Unable to find a service account with the specified identifier inside the database", errServiceAccountNotFound)
+	}
+
+	return user, nil
+}
+
+// setEndpointAccesses replaces the environment(endpoint) access policies of the service account
+func (handler *Handler) setEndpointAccesses(tx dataservices.DataStoreTx, userID portainer.UserID, accesses []EndpointAccess) error {
+	roles := make(map[portainer.EndpointID]portainer.RoleID, len(accesses))
+
+	for _, access := range accesses {
+		if _, err := tx.Role().Read(access.RoleID); tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find a role with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a role with the specified identifier inside the database", err)
+		}
+
+		if _, err := tx.Endpoint().Endpoint(access.EndpointID); tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find an environment with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+		}
+
+		roles[access.EndpointID] = access.RoleID
+	}
+
+	endpoints, err := tx.Endpoint().Endpoints()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve environments from the database", err)
+	}
+
+	for _, endpoint := range endpoints {
+		policy, granted := endpoint.UserAccessPolicies[userID]
+		roleID, requested := roles[endpoint.ID]
+
+		if granted == requested && policy.RoleID == roleID {
+			continue
+		}
+
+		if requested {
+			if endpoint.UserAccessPolicies == nil {
+				endpoint.UserAccessPolicies = portainer.UserAccessPolicies{}
+			}
+
+			endpoint.UserAccessPolicies[userID] = portainer.AccessPolicy{RoleID: roleID}
+		} else {
+			delete(endpoint.UserAccessPolicies, userID)
+		}
+
+		if err := tx.Endpoint().UpdateEndpoint(endpoint.ID, &endpoint); err != nil {
+			return httperror.InternalServerError("Unable to persist environment changes inside the database", err)
+		}
+	}
+
+	if err := handler.AuthorizationService.UpdateUserAuthorizations(tx, userID); err != nil {
+		return httperror.InternalServerError("Unable to update the authorizations of the service account", err)
+	}
+
+	return nil
+}
+
+func endpointAccesses(tx dataservices.DataStoreTx, userID portainer.UserID) ([]EndpointAccess, error) {
+	endpoints, err := tx.Endpoint().Endpoints()
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve environments from the database", err)
+	}
+
+	accesses := []EndpointAccess{}
+	for _, endpoint := range endpoints {
+		if policy, ok := endpoint.UserAccessPolicies[userID]; ok {
+			accesses = append(accesses, EndpointAccess{EndpointID: endpoint.ID, RoleID: policy.RoleID})
+		}
+	}
+
+	slices.SortFunc(accesses, func(a, b EndpointAccess) int { return int(a.EndpointID) - int(b.EndpointID) })
+
+	return accesses, nil
+}
+
+func toServiceAccount(tx dataservices.DataStoreTx, user *portainer.User) (*ServiceAccount, error) {
+	accesses, err := endpointAccesses(tx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ServiceAccount{
+		ID:               user.ID,
+		Name:             user.Username,
+		EndpointAccesses: accesses,
+	}, nil
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
@@ -0,0 +1,90 @@
+package serviceaccounts
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/authorization"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type serviceAccountCreatePayload struct {
+	// Name of the service account, it must be unique among the users and the service accounts
+	Name string `validate:"required" example:"ci-pipeline"`
+	// Environments(endpoints) the service account can access, with the role granted on each of them
+	EndpointAccesses []EndpointAccess
+}
+
+func (payload *serviceAccountCreatePayload) Validate(r *http.Request) error {
+	if len(payload.Name) == 0 || strings.Contains(payload.Name, " ") {
+		return errors.New("invalid name, must not be empty nor contain any whitespace")
+	}
+
+	return validateEndpointAccesses(payload.EndpointAccesses)
+}
+
+// @id ServiceAccountCreate
+// @summary Create a service account
+// @description Create a service account. A service account can only authenticate with API keys, it is never an administrator
+// @description and can only access the environments it is granted.
+// @description **Access policy**: administrator
+// @tags service_accounts
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body serviceAccountCreatePayload true "Service account details"
+// @success 200 {object} ServiceAccount "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 409 "A user or a service account with the same name already exists"
+// @failure 500 "Server error"
+// @router /service_accounts [post]
+func (handler *Handler) serviceAccountCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload serviceAccountCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var serviceAccount *ServiceAccount
+	err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		var err error
+		serviceAccount, err = handler.createServiceAccount(tx, payload)
+
+		return err
+	})
+
+	return txResponse(w, serviceAccount, err)
+}
+
+func (handler *Handler) createServiceAccount(tx dataservices.DataStoreTx, payload serviceAccountCreatePayload) (*ServiceAccount, error) {
+	user, err := tx.User().UserByUsername(payload.Name)
+	if err != nil && !tx.IsErrObjectNotFound(err) {
+		return nil, httperror.InternalServerError("Unable to retrieve users from the database", err)
+	}
+
+	if user != nil {
+		return nil, httperror.Conflict("A user or a service account with the same name already exists", errors.New("user already exists"))
+	}
+
+	user = &portainer.User{
+		Username:                payload.Name,
+		Role:                    portainer.StandardUserRole,
+		ServiceAccount:          true,
+		PortainerAuthorizations: authorization.DefaultPortainerAuthorizations(),
+	}
+
+	if err := tx.User().Create(user); err != nil {
+		return nil, httperror.InternalServerError("Unable to persist the service account inside the database", err)
+	}
+
+	if err := handler.setEndpointAccesses(tx, user.ID, payload.EndpointAccesses); err != nil {
+		return nil, err
+	}
+
+	return toServiceAccount(tx, user)
+}
@@ -0,0 +1,129 @@
+package serviceaccounts
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/apikey"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceAccountLifecycle(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Role().Create(&portainer.Role{ID: 1, Name: "Environment administrator"}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1, Name: "production"}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 2, Name: "staging"}))
+
+	user := &portainer.User{Username: "bob", Role: portainer.StandardUserRole}
+	require.NoError(t, store.User().Create(user))
+
+	apiKeyService := apikey.NewAPIKeyService(store.APIKeyRepository(), store.User())
+
+	h := NewHandler(testhelpers.NewTestRequestBouncer(), apiKeyService)
+	h.DataStore = store
+	h.AuthorizationService = authorization.NewService(store)
+
+	serve := func(method, url string, payload any) *httptest.ResponseRecorder {
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(method, url, bytes.NewReader(body)))
+
+		return rr
+	}
+
+	userPolicy := func(endpointID portainer.EndpointID, userID portainer.UserID) (portainer.AccessPolicy, bool) {
+		endpoint, err := store.Endpoint().Endpoint(endpointID)
+		require.NoError(t, err)
+
+		policy, ok := endpoint.UserAccessPolicies[userID]
+
+		return policy, ok
+	}
+
+	// The name is shared with the users
+	rr := serve(http.MethodPost, "/service_accounts", serviceAccountCreatePayload{Name: "bob"})
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = serve(http.MethodPost, "/service_accounts", serviceAccountCreatePayload{
+		Name:             "ci-pipeline",
+		EndpointAccesses: []EndpointAccess{{EndpointID: 1, RoleID: 1}},
+	})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	var serviceAccount ServiceAccount
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&serviceAccount))
+	assert.Equal(t, "ci-pipeline", serviceAccount.Name)
+	assert.Equal(t, []EndpointAccess{{EndpointID: 1, RoleID: 1}}, serviceAccount.EndpointAccesses)
+
+	account, err := store.User().Read(serviceAccount.ID)
+	require.NoError(t, err)
+	assert.True(t, account.ServiceAccount)
+	assert.Equal(t, portainer.StandardUserRole, account.Role)
+
+	policy, ok := userPolicy(1, serviceAccount.ID)
+	require.True(t, ok)
+	assert.Equal(t, portainer.RoleID(1), policy.RoleID)
+
+	// Unknown roles are rejected
+	rr = serve(http.MethodPut, "/service_accounts/"+strconv.Itoa(int(serviceAccount.ID)), serviceAccountUpdatePayload{
+		EndpointAccesses: []EndpointAccess{{EndpointID: 2, RoleID: 42}},
+	})
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+
+	// The accesses are replaced
+	rr = serve(http.MethodPut, "/service_accounts/"+strconv.Itoa(int(serviceAccount.ID)), serviceAccountUpdatePayload{
+		EndpointAccesses: []EndpointAccess{{EndpointID: 2, RoleID: 1}},
+	})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	_, ok = userPolicy(1, serviceAccount.ID)
+	assert.False(t, ok)
+	_, ok = userPolicy(2, serviceAccount.ID)
+	assert.True(t, ok)
+
+	rr = serve(http.MethodPost, "/service_accounts/"+strconv.Itoa(int(serviceAccount.ID))+"/tokens", serviceAccountTokenCreatePayload{Description: "github-actions"})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+
+	var token accessTokenResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&token))
+	require.NotEmpty(t, token.RawAPIKey)
+
+	keyUser, _, err := apiKeyService.GetDigestUserAndKey(apiKeyService.HashRaw(token.RawAPIKey))
+	require.NoError(t, err)
+	assert.Equal(t, serviceAccount.ID, keyUser.ID)
+
+	// Regular users are neither listed nor managed as service accounts
+	rr = serve(http.MethodGet, "/service_accounts", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var serviceAccounts []ServiceAccount
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&serviceAccounts))
+	require.Len(t, serviceAccounts, 1)
+	assert.Equal(t, serviceAccount.ID, serviceAccounts[0].ID)
+
+	rr = serve(http.MethodDelete, "/service_accounts/"+strconv.Itoa(int(user.ID)), nil)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = serve(http.MethodDelete, "/service_accounts/"+strconv.Itoa(int(serviceAccount.ID)), nil)
+	require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+
+	_, ok = userPolicy(2, serviceAccount.ID)
+	assert.False(t, ok)
+
+	apiKeys, err := apiKeyService.GetAPIKeys(serviceAccount.ID)
+	require.NoError(t, err)
+	assert.Empty(t, apiKeys)
+}
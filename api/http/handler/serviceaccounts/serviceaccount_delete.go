@@ -0,0 +1,64 @@
+package serviceaccounts
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id ServiceAccountDelete
+// @summary Remove a service account
+// @description Remove a service account, its API keys and its environment accesses.
+// @description **Access policy**: administrator
+// @tags service_accounts
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Service account identifier"
+// @success 204 "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Service account not found"
+// @failure 500 "Server error"
+// @router /service_accounts/{id} [delete]
+func (handler *Handler) serviceAccountDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	serviceAccountID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid service account identifier route variable", err)
+	}
+
+	userID := portainer.UserID(serviceAccountID)
+
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if _, err := readServiceAccount(tx, userID); err != nil {
+			return err
+		}
+
+		if err := handler.AuthorizationService.RemoveUserAccessPolicies(tx, userID); err != nil {
+			return httperror.InternalServerError("Unable to remove the environment accesses of the service account", err)
+		}
+
+		if err := tx.User().Delete(userID); err != nil {
+			return httperror.InternalServerError("Unable to remove the service account from the database", err)
+		}
+
+		return nil
+	}); err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	apiKeys, err := handler.apiKeyService.GetAPIKeys(userID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the API keys of the service account from the database", err)
+	}
+
+	for _, apiKey := range apiKeys {
+		if err := handler.apiKeyService.DeleteAPIKey(apiKey.ID); err != nil {
+			return httperror.InternalServerError("Unable to remove the API key of the service account from the database", err)
+		}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,45 @@
+package serviceaccounts
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+// @id ServiceAccountInspect
+// @summary Inspect a service account
+// @description Retrieve details about a service account.
+// @description **Access policy**: administrator
+// @tags service_accounts
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Service account identifier"
+// @success 200 {object} ServiceAccount "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Service account not found"
+// @failure 500 "Server error"
+// @router /service_accounts/{id} [get]
+func (handler *Handler) serviceAccountInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	serviceAccountID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid service account identifier route variable", err)
+	}
+
+	var serviceAccount *ServiceAccount
+	err = handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		user, err := readServiceAccount(tx, portainer.UserID(serviceAccountID))
+		if err != nil {
+			return err
+		}
+
+		serviceAccount, err = toServiceAccount(tx, user)
+
+		return err
+	})
+
+	return txResponse(w, serviceAccount, err)
+}
@@ -0,0 +1,47 @@
+package serviceaccounts
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+)
+
+// @id ServiceAccountList
+// @summary List service accounts
+// @description List the service accounts and the environments they can access.
+// @description **Access policy**: administrator
+// @tags service_accounts
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} ServiceAccount "Success"
+// @failure 500 "Server error"
+// @router /service_accounts [get]
+func (handler *Handler) serviceAccountList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	serviceAccounts := []ServiceAccount{}
+
+	err := handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		users, err := tx.User().ReadAll()
+		if err != nil {
+			return httperror.InternalServerError("Unable to retrieve users from the database", err)
+		}
+
+		for _, user := range users {
+			if !user.ServiceAccount {
+				continue
+			}
+
+			serviceAccount, err := toServiceAccount(tx, &user)
+			if err != nil {
+				return err
+			}
+
+			serviceAccounts = append(serviceAccounts, *serviceAccount)
+		}
+
+		return nil
+	})
+
+	return txResponse(w, serviceAccounts, err)
+}
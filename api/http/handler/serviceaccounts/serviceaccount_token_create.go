@@ -0,0 +1,82 @@
+package serviceaccounts
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/asaskevich/govalidator"
+)
+
+type serviceAccountTokenCreatePayload struct {
+	Description string `validate:"required" example:"github-actions" json:"description"`
+}
+
+func (payload *serviceAccountTokenCreatePayload) Validate(r *http.Request) error {
+	if len(payload.Description) == 0 || govalidator.HasWhitespaceOnly(payload.Description) {
+		return errors.New("invalid description: cannot be empty")
+	}
+
+	if govalidator.MinStringLength(payload.Description, "128") {
+		return errors.New("invalid description: cannot be longer than 128 characters")
+	}
+
+	return nil
+}
+
+type accessTokenResponse struct {
+	RawAPIKey string           `json:"rawAPIKey"`
+	APIKey    portainer.APIKey `json:"apiKey"`
+}
+
+// @id ServiceAccountTokenCreate
+// @summary Generate an API key for a service account
+// @description Generate an API key for a service account, the raw key is only returned once.
+// @description The API keys of a service account are listed and removed with the user access tokens API.
+// @description **Access policy**: administrator
+// @tags service_accounts
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Service account identifier"
+// @param body body serviceAccountTokenCreatePayload true "API key details"
+// @success 201 {object} accessTokenResponse "Created"
+// @failure 400 "Invalid request"
+// @failure 404 "Service account not found"
+// @failure 500 "Server error"
+// @router /service_accounts/{id}/tokens [post]
+func (handler *Handler) serviceAccountTokenCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	serviceAccountID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid service account identifier route variable", err)
+	}
+
+	var payload serviceAccountTokenCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var user *portainer.User
+	if err := handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		user, err = readServiceAccount(tx, portainer.UserID(serviceAccountID))
+
+		return err
+	}); err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	rawAPIKey, apiKey, err := handler.apiKeyService.GenerateApiKey(*user, payload.Description)
+	if err != nil {
+		return httperror.InternalServerError("Unable to generate the API key", err)
+	}
+
+	apiKey.Digest = ""
+
+	return response.JSONWithStatus(w, accessTokenResponse{rawAPIKey, *apiKey}, http.StatusCreated)
+}
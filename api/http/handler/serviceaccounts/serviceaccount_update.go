@@ -0,0 +1,102 @@
+package serviceaccounts
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type serviceAccountUpdatePayload struct {
+	// Name of the service account, it must be unique among the users and the service accounts
+	Name *string `example:"ci-pipeline"`
+	// Environments(endpoints) the service account can access, replaces the current accesses when specified
+	EndpointAccesses []EndpointAccess
+}
+
+func (payload *serviceAccountUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && (len(*payload.Name) == 0 || strings.Contains(*payload.Name, " ")) {
+		return errors.New("invalid name, must not be empty nor contain any whitespace")
+	}
+
+	return validateEndpointAccesses(payload.EndpointAccesses)
+}
+
+// @id ServiceAccountUpdate
+// @summary Update a service account
+// @description Update the name or the environment accesses of a service account.
+// @description **Access policy**: administrator
+// @tags service_accounts
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Service account identifier"
+// @param body body serviceAccountUpdatePayload true "Service account details"
+// @success 200 {object} ServiceAccount "Success"
+// @failure 400 "Invalid request"
+// @failure 404 "Service account not found"
+// @failure 409 "A user or a service account with the same name already exists"
+// @failure 500 "Server error"
+// @router /service_accounts/{id} [put]
+func (handler *Handler) serviceAccountUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	serviceAccountID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid service account identifier route variable", err)
+	}
+
+	var payload serviceAccountUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var serviceAccount *ServiceAccount
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		var err error
+		serviceAccount, err = handler.updateServiceAccount(tx, portainer.UserID(serviceAccountID), payload)
+
+		return err
+	})
+	if err == nil {
+		// The API keys cache holds the name of the service account
+		handler.apiKeyService.InvalidateUserKeyCache(serviceAccount.ID)
+	}
+
+	return txResponse(w, serviceAccount, err)
+}
+
+func (handler *Handler) updateServiceAccount(tx dataservices.DataStoreTx, id portainer.UserID, payload serviceAccountUpdatePayload) (*ServiceAccount, error) {
+	user, err := readServiceAccount(tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Name != nil && *payload.Name != user.Username {
+		sameNameUser, err := tx.User().UserByUsername(*payload.Name)
+		if err != nil && !tx.IsErrObjectNotFound(err) {
+			return nil, httperror.InternalServerError("Unable to retrieve users from the database", err)
+		}
+
+		if sameNameUser != nil {
+			return nil, httperror.Conflict("A user or a service account with the same name already exists", errors.New("user already exists"))
+		}
+
+		user.Username = *payload.Name
+
+		if err := tx.User().Update(user.ID, user); err != nil {
+			return nil, httperror.InternalServerError("Unable to persist the service account inside the database", err)
+		}
+	}
+
+	if payload.EndpointAccesses != nil {
+		if err := handler.setEndpointAccesses(tx, user.ID, payload.EndpointAccesses); err != nil {
+			return nil, err
+		}
+	}
+
+	return toServiceAccount(tx, user)
+}
@@ -6,25 +6,22 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/settingsbus"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 
 	"github.com/gorilla/mux"
 )
 
-func hideFields(settings *portainer.Settings) {
-	settings.LDAPSettings.Password = ""
-	settings.OAuthSettings.ClientSecret = ""
-	settings.OAuthSettings.KubeSecretKey = nil
-}
-
 // Handler is the HTTP handler used to handle settings operations.
 type Handler struct {
 	*mux.Router
-	DataStore       dataservices.DataStore
-	FileService     portainer.FileService
-	JWTService      portainer.JWTService
-	LDAPService     portainer.LDAPService
-	SnapshotService portainer.SnapshotService
+	DataStore    dataservices.DataStore
+	FileService  portainer.FileService
+	JWTService   portainer.JWTService
+	LDAPService  portainer.LDAPService
+	OAuthService portainer.OAuthService
+	// SettingsBus notifies the background services of the changes of the settings, it is optional
+	SettingsBus *settingsbus.Bus
 }
 
 // NewHandler creates a handler to manage settings operations.
@@ -37,6 +34,8 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 		bouncer.AdminAccess(httperror.LoggerHandler(h.settingsInspect))).Methods(http.MethodGet)
 	h.Handle("/settings",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.settingsUpdate))).Methods(http.MethodPut)
+	h.Handle("/settings/jwt/rotate",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.settingsJWTKeyRotate))).Methods(http.MethodPost)
 	h.Handle("/settings/public",
 		bouncer.PublicAccess(httperror.LoggerHandler(h.settingsPublic))).Methods(http.MethodGet)
 
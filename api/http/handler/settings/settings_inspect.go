@@ -24,6 +24,5 @@ func (handler *Handler) settingsInspect(w http.ResponseWriter, r *http.Request)
 		return httperror.InternalServerError("Unable to retrieve the settings from the database", err)
 	}
 
-	hideFields(settings)
 	return response.JSON(w, settings)
 }
@@ -0,0 +1,27 @@
+package settings
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id SettingsJWTKeyRotate
+// @summary Rotate the JWT signing key
+// @description Replace the key signing the session tokens with a new key using the configured signing algorithm.
+// @description The previous key keeps verifying the tokens it signed until they expire.
+// @description **Access policy**: administrator
+// @tags settings
+// @security ApiKeyAuth
+// @security jwt
+// @success 204 "Success"
+// @failure 500 "Server error"
+// @router /settings/jwt/rotate [post]
+func (handler *Handler) settingsJWTKeyRotate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	if err := handler.JWTService.RotateSigningKey(); err != nil {
+		return httperror.InternalServerError("Unable to rotate the JWT signing key", err)
+	}
+
+	return response.Empty(w)
+}
@@ -3,13 +3,19 @@ package settings
 import (
 	"cmp"
 	"net/http"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/backup"
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/http/client"
 	"github.com/portainer/portainer/api/internal/edge"
+	"github.com/portainer/portainer/api/internal/notifications"
+	"github.com/portainer/portainer/api/objectstorage"
 	"github.com/portainer/portainer/pkg/libhelm"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
@@ -32,7 +38,9 @@ type settingsUpdatePayload struct {
 	OAuthSettings        *portainer.OAuthSettings
 	// The interval in which environment(endpoint) snapshots are created
 	SnapshotInterval *string `example:"5m"`
-	// URL to the templates that will be displayed in the UI when navigating to App Templates
+	// How long the past snapshots of the environments are kept
+	SnapshotRetention *portainer.SnapshotRetentionSettings
+	// URL to the templates that will be displayed in the UI when navigating to App Templates, used while no template source is defined
 	TemplatesURL *string `example:"https://raw.githubusercontent.com/portainer/templates/master/templates.json"`
 	// Deployment options for encouraging deployment as code
 	GlobalDeploymentOptions  *portainer.GlobalDeploymentOptions // The default check in interval for edge agent (in seconds)
@@ -41,6 +49,12 @@ type settingsUpdatePayload struct {
 	EnableEdgeComputeFeatures *bool `example:"true"`
 	// The duration of a user session
 	UserSessionTimeout *string `example:"5m"`
+	// The duration of the user sessions per authentication method (1 for internal, 2 for LDAP/AD or 3 for OAuth), overrides UserSessionTimeout
+	AuthenticationSessionTimeouts map[portainer.AuthenticationMethod]string
+	// The limits enforced on the sessions of the users
+	UserSessionLimits *portainer.UserSessionLimitsSettings
+	// The algorithm signing the session tokens, changing it rotates the signing key. Valid values are: HS256, RS256 or ES256
+	JWTSigningAlgorithm *portainer.JWTSigningAlgorithm `example:"ES256"`
 	// The expiry of a Kubeconfig
 	KubeconfigExpiry *string `example:"24h" default:"0"`
 	// Whether telemetry is enabled
@@ -55,6 +69,18 @@ type settingsUpdatePayload struct {
 	EnforceEdgeID *bool `example:"false"`
 	// EdgePortainerURL is the URL that is exposed to edge agents
 	EdgePortainerURL *string `json:"EdgePortainerURL"`
+	// Whether Kubernetes requests proxied with the cluster-admin token are audited
+	EnableKubernetesClusterAdminAudit *bool `example:"false"`
+	// HTTP proxy used by the outbound calls, the password is kept when empty
+	ProxyConfig *portainer.ProxyConfig
+	// SMTP server used to send the email notifications, the password is kept when empty
+	SMTPSettings *portainer.SMTPSettings
+	// Periodic backups of the Portainer data, the password is kept when empty
+	BackupSchedule *portainer.BackupSchedule
+	// S3 compatible bucket storing the backup archives and the Edge job task logs, the secret key is kept when empty
+	ObjectStorage *portainer.ObjectStorageSettings
+	// Rate limit of the Docker and Kubernetes API calls proxied to the environments by each user
+	ProxyRateLimit *portainer.ProxyRateLimitSettings
 }
 
 func (payload *settingsUpdatePayload) Validate(r *http.Request) error {
@@ -74,12 +100,48 @@ func (payload *settingsUpdatePayload) Validate(r *http.Request) error {
 		return errors.New("Invalid Helm repository URL. Must correspond to a valid URL format")
 	}
 
+	if payload.SnapshotInterval != nil {
+		if interval, err := time.ParseDuration(*payload.SnapshotInterval); err != nil || interval <= 0 {
+			return errors.New("Invalid snapshot interval")
+		}
+	}
+
+	if payload.SnapshotRetention != nil {
+		if payload.SnapshotRetention.MaxRecords < 0 {
+			return errors.New("Invalid snapshot retention. The number of snapshots cannot be negative")
+		}
+
+		if payload.SnapshotRetention.MaxAge != "" {
+			if maxAge, err := time.ParseDuration(payload.SnapshotRetention.MaxAge); err != nil || maxAge < time.Hour {
+				return errors.New("Invalid snapshot retention. The maximum age must be a duration of at least 1h")
+			}
+		}
+	}
+
 	if payload.UserSessionTimeout != nil {
 		if _, err := time.ParseDuration(*payload.UserSessionTimeout); err != nil {
 			return errors.New("Invalid user session timeout")
 		}
 	}
 
+	for authMethod, timeout := range payload.AuthenticationSessionTimeouts {
+		if authMethod < portainer.AuthenticationInternal || authMethod > portainer.AuthenticationOAuth {
+			return errors.New("Invalid authentication method of the session timeouts. Value must be one of: 1 (internal), 2 (LDAP/AD) or 3 (OAuth)")
+		}
+
+		if duration, err := time.ParseDuration(timeout); err != nil || duration <= 0 {
+			return errors.Errorf("Invalid session timeout of the authentication method %d", authMethod)
+		}
+	}
+
+	if payload.JWTSigningAlgorithm != nil {
+		switch *payload.JWTSigningAlgorithm {
+		case portainer.JWTSigningAlgorithmHS256, portainer.JWTSigningAlgorithmRS256, portainer.JWTSigningAlgorithmES256:
+		default:
+			return errors.New("Invalid JWT signing algorithm. Value must be one of: HS256, RS256 or ES256")
+		}
+	}
+
 	if payload.KubeconfigExpiry != nil {
 		if _, err := time.ParseDuration(*payload.KubeconfigExpiry); err != nil {
 			return errors.New("Invalid Kubeconfig Expiry")
@@ -96,6 +158,72 @@ func (payload *settingsUpdatePayload) Validate(r *http.Request) error {
 		if payload.OAuthSettings.AuthStyle < oauth2.AuthStyleAutoDetect || payload.OAuthSettings.AuthStyle > oauth2.AuthStyleInHeader {
 			return errors.New("Invalid OAuth AuthStyle")
 		}
+
+		if payload.OAuthSettings.OIDC {
+			if payload.OAuthSettings.IssuerURL == "" {
+				return errors.New("The issuer URL is required when OpenID Connect is enabled")
+			}
+
+			if !slices.Contains(strings.Fields(strings.ReplaceAll(payload.OAuthSettings.Scopes, ",", " ")), "openid") {
+				return errors.New("The openid scope is required when OpenID Connect is enabled")
+			}
+		}
+
+		for _, mapping := range payload.OAuthSettings.ClaimMappings {
+			if _, err := regexp.Compile(mapping.ClaimValRegex); err != nil {
+				return errors.Wrapf(err, "Invalid OAuth claim mapping regular expression %q", mapping.ClaimValRegex)
+			}
+
+			if mapping.Team == 0 {
+				return errors.New("Invalid OAuth claim mapping team")
+			}
+		}
+	}
+
+	if payload.ProxyConfig != nil {
+		if err := client.ValidateProxyConfig(*payload.ProxyConfig); err != nil {
+			return err
+		}
+	}
+
+	if payload.SMTPSettings != nil {
+		if err := notifications.ValidateSMTPSettings(*payload.SMTPSettings); err != nil {
+			return err
+		}
+	}
+
+	if payload.BackupSchedule != nil {
+		if err := backup.ValidateSchedule(*payload.BackupSchedule); err != nil {
+			return err
+		}
+	}
+
+	if payload.UserSessionLimits != nil {
+		if payload.UserSessionLimits.MaxConcurrentSessions < 0 {
+			return errors.New("Invalid user session limits. The maximum number of concurrent sessions cannot be negative")
+		}
+
+		if payload.UserSessionLimits.IdleTimeout != "" {
+			if idleTimeout, err := time.ParseDuration(payload.UserSessionLimits.IdleTimeout); err != nil || idleTimeout < time.Minute {
+				return errors.New("Invalid user session limits. The idle timeout must be a duration of at least 1m")
+			}
+		}
+	}
+
+	if payload.ObjectStorage != nil {
+		if err := objectstorage.ValidateSettings(*payload.ObjectStorage); err != nil {
+			return err
+		}
+	}
+
+	if payload.ProxyRateLimit != nil {
+		if payload.ProxyRateLimit.RequestsPerSecond < 0 {
+			return errors.New("Invalid proxy rate limit. The number of requests per second cannot be negative")
+		}
+
+		if payload.ProxyRateLimit.RequestsPerSecond > 0 && payload.ProxyRateLimit.Burst < 1 {
+			return errors.New("Invalid proxy rate limit. The burst must be at least 1")
+		}
 	}
 
 	return nil
@@ -122,8 +250,12 @@ func (handler *Handler) settingsUpdate(w http.ResponseWriter, r *http.Request) *
 		return httperror.BadRequest("Invalid request payload", err)
 	}
 
-	var settings *portainer.Settings
+	var previous, settings *portainer.Settings
 	if err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if previous, err = tx.Settings().Settings(); err != nil {
+			return httperror.InternalServerError("Unable to retrieve the settings from the database", err)
+		}
+
 		settings, err = handler.updateSettings(tx, payload)
 
 		return err
@@ -136,7 +268,16 @@ func (handler *Handler) settingsUpdate(w http.ResponseWriter, r *http.Request) *
 		return httperror.InternalServerError("Unexpected error", err)
 	}
 
-	hideFields(settings)
+	if signingAlgorithm(previous) != signingAlgorithm(settings) {
+		if err := handler.JWTService.RotateSigningKey(); err != nil {
+			return httperror.InternalServerError("Unable to rotate the JWT signing key", err)
+		}
+	}
+
+	if handler.SettingsBus != nil {
+		handler.SettingsBus.Publish(previous, settings)
+	}
+
 	return response.JSON(w, settings)
 }
 
@@ -203,6 +344,12 @@ func (handler *Handler) updateSettings(tx dataservices.DataStoreTx, payload sett
 		settings.OAuthSettings.ClientSecret = clientSecret
 		settings.OAuthSettings.KubeSecretKey = kubeSecret
 		settings.OAuthSettings.AuthStyle = payload.OAuthSettings.AuthStyle
+
+		if settings.OAuthSettings.OIDC {
+			if err := handler.OAuthService.Discover(&settings.OAuthSettings); err != nil {
+				return nil, httperror.BadRequest("Unable to discover the OpenID Connect provider", err)
+			}
+		}
 	}
 
 	settings.EnableEdgeComputeFeatures = *cmp.Or(payload.EnableEdgeComputeFeatures, &settings.EnableEdgeComputeFeatures)
@@ -210,12 +357,8 @@ func (handler *Handler) updateSettings(tx dataservices.DataStoreTx, payload sett
 	settings.EnforceEdgeID = *cmp.Or(payload.EnforceEdgeID, &settings.EnforceEdgeID)
 	settings.EdgePortainerURL = *cmp.Or(payload.EdgePortainerURL, &settings.EdgePortainerURL)
 
-	if payload.SnapshotInterval != nil && *payload.SnapshotInterval != settings.SnapshotInterval {
-		if err := handler.updateSnapshotInterval(settings, *payload.SnapshotInterval); err != nil {
-			return nil, httperror.InternalServerError("Unable to update snapshot interval", err)
-		}
-	}
-
+	settings.SnapshotInterval = *cmp.Or(payload.SnapshotInterval, &settings.SnapshotInterval)
+	settings.SnapshotRetention = *cmp.Or(payload.SnapshotRetention, &settings.SnapshotRetention)
 	settings.EdgeAgentCheckinInterval = *cmp.Or(payload.EdgeAgentCheckinInterval, &settings.EdgeAgentCheckinInterval)
 	settings.KubeconfigExpiry = *cmp.Or(payload.KubeconfigExpiry, &settings.KubeconfigExpiry)
 
@@ -227,7 +370,14 @@ func (handler *Handler) updateSettings(tx dataservices.DataStoreTx, payload sett
 		handler.JWTService.SetUserSessionDuration(userSessionDuration)
 	}
 
+	if payload.AuthenticationSessionTimeouts != nil {
+		settings.AuthenticationSessionTimeouts = payload.AuthenticationSessionTimeouts
+	}
+
+	settings.JWTSigningAlgorithm = *cmp.Or(payload.JWTSigningAlgorithm, &settings.JWTSigningAlgorithm)
+
 	settings.EnableTelemetry = *cmp.Or(payload.EnableTelemetry, &settings.EnableTelemetry)
+	settings.EnableKubernetesClusterAdminAudit = *cmp.Or(payload.EnableKubernetesClusterAdminAudit, &settings.EnableKubernetesClusterAdminAudit)
 
 	if err := handler.updateTLS(settings); err != nil {
 		return nil, err
@@ -235,6 +385,62 @@ func (handler *Handler) updateSettings(tx dataservices.DataStoreTx, payload sett
 
 	settings.KubectlShellImage = *cmp.Or(payload.KubectlShellImage, &settings.KubectlShellImage)
 
+	if payload.ProxyConfig != nil {
+		proxyPassword := payload.ProxyConfig.Password
+		if proxyPassword == "" && payload.ProxyConfig.Username == settings.ProxyConfig.Username {
+			proxyPassword = settings.ProxyConfig.Password
+		}
+
+		settings.ProxyConfig = *payload.ProxyConfig
+		settings.ProxyConfig.Password = proxyPassword
+
+		if err := client.SetProxyConfig(settings.ProxyConfig); err != nil {
+			return nil, httperror.BadRequest("Invalid proxy configuration", err)
+		}
+	}
+
+	if payload.SMTPSettings != nil {
+		smtpPassword := payload.SMTPSettings.Password
+		if smtpPassword == "" && payload.SMTPSettings.Username == settings.SMTPSettings.Username {
+			smtpPassword = settings.SMTPSettings.Password
+		}
+
+		settings.SMTPSettings = *payload.SMTPSettings
+		settings.SMTPSettings.Password = smtpPassword
+	}
+
+	if payload.BackupSchedule != nil {
+		backupPassword := payload.BackupSchedule.Password
+		if backupPassword == "" {
+			backupPassword = settings.BackupSchedule.Password
+		}
+
+		settings.BackupSchedule = *payload.BackupSchedule
+		settings.BackupSchedule.Password = backupPassword
+	}
+
+	if payload.ObjectStorage != nil {
+		secretAccessKey := payload.ObjectStorage.SecretAccessKey
+		if secretAccessKey == "" && payload.ObjectStorage.AccessKeyID == settings.ObjectStorage.AccessKeyID {
+			secretAccessKey = settings.ObjectStorage.SecretAccessKey
+		}
+
+		settings.ObjectStorage = *payload.ObjectStorage
+		settings.ObjectStorage.SecretAccessKey = secretAccessKey
+	}
+
+	if payload.ProxyRateLimit != nil {
+		settings.ProxyRateLimit = *payload.ProxyRateLimit
+	}
+
+	if payload.UserSessionLimits != nil {
+		settings.UserSessionLimits = *payload.UserSessionLimits
+	}
+
+	if settings.BackupSchedule.Enabled && settings.BackupSchedule.Destination == portainer.BackupDestinationS3 && !settings.ObjectStorage.Enabled {
+		return nil, httperror.BadRequest("Invalid backup schedule", errors.New("the object storage must be enabled to upload the backups"))
+	}
+
 	if err := tx.Settings().UpdateSettings(settings); err != nil {
 		return nil, httperror.InternalServerError("Unable to persist settings changes inside the database", err)
 	}
@@ -242,10 +448,9 @@ func (handler *Handler) updateSettings(tx dataservices.DataStoreTx, payload sett
 	return settings, nil
 }
 
-func (handler *Handler) updateSnapshotInterval(settings *portainer.Settings, snapshotInterval string) error {
-	settings.SnapshotInterval = snapshotInterval
-
-	return handler.SnapshotService.SetSnapshotInterval(snapshotInterval)
+// signingAlgorithm returns the algorithm signing the session tokens, HS256 when the settings do not define one
+func signingAlgorithm(settings *portainer.Settings) portainer.JWTSigningAlgorithm {
+	return cmp.Or(settings.JWTSigningAlgorithm, portainer.JWTSigningAlgorithmHS256)
 }
 
 func (handler *Handler) updateTLS(settings *portainer.Settings) error {
@@ -0,0 +1,109 @@
+package snapshotwebhooks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/snapshotwebhook"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/gorilla/mux"
+)
+
+// maxTimeout is the longest time a snapshot webhook can take to respond, in seconds
+const maxTimeout = 60
+
+// Handler is the HTTP handler used to handle snapshot webhook operations.
+type Handler struct {
+	*mux.Router
+	DataStore dataservices.DataStore
+}
+
+// NewHandler creates a handler to manage snapshot webhook operations.
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+
+	h.Handle("/snapshot_webhooks",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.snapshotWebhookList))).Methods(http.MethodGet)
+	h.Handle("/snapshot_webhooks",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.snapshotWebhookCreate))).Methods(http.MethodPost)
+	h.Handle("/snapshot_webhooks/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.snapshotWebhookInspect))).Methods(http.MethodGet)
+	h.Handle("/snapshot_webhooks/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.snapshotWebhookUpdate))).Methods(http.MethodPut)
+	h.Handle("/snapshot_webhooks/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.snapshotWebhookDelete))).Methods(http.MethodDelete)
+
+	return h
+}
+
+func validateURL(rawURL string) error {
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("invalid snapshot webhook URL, it must be an http or https URL")
+	}
+
+	return nil
+}
+
+func validateTimeout(timeout int) error {
+	if timeout < 0 || timeout > maxTimeout {
+		return errors.New("invalid snapshot webhook timeout, it must be between 0 and 60 seconds")
+	}
+
+	return nil
+}
+
+func validateMode(mode portainer.SnapshotWebhookMode) error {
+	if mode != portainer.SnapshotWebhookAlways && mode != portainer.SnapshotWebhookOnChange {
+		return errors.New("invalid snapshot webhook mode, it must be always or change")
+	}
+
+	return nil
+}
+
+// validateFieldMapping returns an error when the mapping renames an unknown field
+func validateFieldMapping(mapping map[string]string) error {
+	for field := range mapping {
+		if !slices.Contains(snapshotwebhook.Fields, field) {
+			return fmt.Errorf("invalid field mapping, unknown field %q", field)
+		}
+	}
+
+	return nil
+}
+
+// checkEndpointGroups returns an error when one of the groups does not exist
+func checkEndpointGroups(tx dataservices.DataStoreTx, groupIDs []portainer.EndpointGroupID) error {
+	for _, groupID := range groupIDs {
+		if _, err := tx.EndpointGroup().Read(groupID); tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find an environment group with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find an environment group with the specified identifier inside the database", err)
+		}
+	}
+
+	return nil
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
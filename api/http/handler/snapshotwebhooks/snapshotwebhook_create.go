@@ -0,0 +1,104 @@
+package snapshotwebhooks
+
+import (
+	"cmp"
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type snapshotWebhookCreatePayload struct {
+	Name string `example:"cmdb"`
+	// URL receiving the summaries with a POST request
+	URL string `example:"https://cmdb.corp.internal/api/portainer"`
+	// Secret used to sign the requests, the requests are not signed when empty
+	Secret string `example:"secret"`
+	// Environment(Endpoint) groups whose summaries are sent, all the environments are included when empty
+	EndpointGroupIDs []portainer.EndpointGroupID `example:"1"`
+	// Whether the summary is sent after every snapshot or only when the inventory changed, defaults to change
+	Mode portainer.SnapshotWebhookMode `example:"change" enums:"always,change"`
+	// Names given to the fields of the summary, indexed by their path. The fields mapped to an empty name are left out
+	FieldMapping map[string]string `example:"containers.image:ci_image"`
+	// Time to wait for the response in seconds, defaults to 10
+	Timeout int  `example:"10"`
+	Enabled bool `example:"true"`
+}
+
+func (payload *snapshotWebhookCreatePayload) Validate(r *http.Request) error {
+	if payload.Name == "" {
+		return errors.New("invalid snapshot webhook name")
+	}
+
+	if err := validateURL(payload.URL); err != nil {
+		return err
+	}
+
+	if payload.Mode != "" {
+		if err := validateMode(payload.Mode); err != nil {
+			return err
+		}
+	}
+
+	if err := validateFieldMapping(payload.FieldMapping); err != nil {
+		return err
+	}
+
+	return validateTimeout(payload.Timeout)
+}
+
+// @id SnapshotWebhookCreate
+// @summary Create a snapshot webhook
+// @description Create a webhook receiving a summary of the hosts, containers and images of the environments after their snapshots,
+// @description to keep an external inventory such as a CMDB up to date.
+// @description The summary is POSTed as {"endpoint": {...}, "time": int, "hosts": [...], "containers": [...], "images": [...]}
+// @description and its fields can be renamed with the field mapping.
+// @description **Access policy**: administrator
+// @tags snapshot_webhooks
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body snapshotWebhookCreatePayload true "Snapshot webhook details"
+// @success 200 {object} portainer.SnapshotWebhook
+// @failure 400
+// @failure 500
+// @router /snapshot_webhooks [post]
+func (handler *Handler) snapshotWebhookCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload snapshotWebhookCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	webhook := &portainer.SnapshotWebhook{
+		Name:             payload.Name,
+		URL:              payload.URL,
+		Secret:           payload.Secret,
+		EndpointGroupIDs: payload.EndpointGroupIDs,
+		Mode:             cmp.Or(payload.Mode, portainer.SnapshotWebhookOnChange),
+		FieldMapping:     payload.FieldMapping,
+		Timeout:          payload.Timeout,
+		Enabled:          payload.Enabled,
+	}
+
+	if webhook.EndpointGroupIDs == nil {
+		webhook.EndpointGroupIDs = []portainer.EndpointGroupID{}
+	}
+
+	err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := checkEndpointGroups(tx, webhook.EndpointGroupIDs); err != nil {
+			return err
+		}
+
+		if err := tx.SnapshotWebhook().Create(webhook); err != nil {
+			return httperror.InternalServerError("Unable to persist the snapshot webhook inside the database", err)
+		}
+
+		return nil
+	})
+
+	return txResponse(w, webhook, err)
+}
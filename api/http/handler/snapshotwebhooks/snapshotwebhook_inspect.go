@@ -0,0 +1,39 @@
+package snapshotwebhooks
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id SnapshotWebhookInspect
+// @summary Inspect a snapshot webhook
+// @description **Access policy**: administrator
+// @tags snapshot_webhooks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Snapshot webhook identifier"
+// @success 200 {object} portainer.SnapshotWebhook
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /snapshot_webhooks/{id} [get]
+func (handler *Handler) snapshotWebhookInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	webhookID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid snapshot webhook identifier route variable", err)
+	}
+
+	webhook, err := handler.DataStore.SnapshotWebhook().Read(portainer.SnapshotWebhookID(webhookID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a snapshot webhook with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a snapshot webhook with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, webhook)
+}
@@ -0,0 +1,27 @@
+package snapshotwebhooks
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id SnapshotWebhookList
+// @summary List the snapshot webhooks
+// @description **Access policy**: administrator
+// @tags snapshot_webhooks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.SnapshotWebhook
+// @failure 500
+// @router /snapshot_webhooks [get]
+func (handler *Handler) snapshotWebhookList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	webhooks, err := handler.DataStore.SnapshotWebhook().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve snapshot webhooks from the database", err)
+	}
+
+	return response.JSON(w, webhooks)
+}
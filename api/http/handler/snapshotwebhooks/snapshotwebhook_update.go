@@ -0,0 +1,137 @@
+package snapshotwebhooks
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type snapshotWebhookUpdatePayload struct {
+	Name *string `example:"cmdb"`
+	// URL receiving the summaries with a POST request
+	URL *string `example:"https://cmdb.corp.internal/api/portainer"`
+	// Secret used to sign the requests, an empty secret disables the signature and the secret is kept when omitted
+	Secret *string `example:"secret"`
+	// Environment(Endpoint) groups whose summaries are sent, all the environments are included when empty
+	EndpointGroupIDs []portainer.EndpointGroupID `example:"1"`
+	// Whether the summary is sent after every snapshot or only when the inventory changed
+	Mode *portainer.SnapshotWebhookMode `example:"change" enums:"always,change"`
+	// Names given to the fields of the summary, indexed by their path. The mapping is replaced when set
+	FieldMapping map[string]string `example:"containers.image:ci_image"`
+	// Time to wait for the response in seconds
+	Timeout *int  `example:"10"`
+	Enabled *bool `example:"true"`
+}
+
+func (payload *snapshotWebhookUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("invalid snapshot webhook name")
+	}
+
+	if payload.URL != nil {
+		if err := validateURL(*payload.URL); err != nil {
+			return err
+		}
+	}
+
+	if payload.Mode != nil {
+		if err := validateMode(*payload.Mode); err != nil {
+			return err
+		}
+	}
+
+	if err := validateFieldMapping(payload.FieldMapping); err != nil {
+		return err
+	}
+
+	if payload.Timeout != nil {
+		return validateTimeout(*payload.Timeout)
+	}
+
+	return nil
+}
+
+// @id SnapshotWebhookUpdate
+// @summary Update a snapshot webhook
+// @description **Access policy**: administrator
+// @tags snapshot_webhooks
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Snapshot webhook identifier"
+// @param body body snapshotWebhookUpdatePayload true "Snapshot webhook details"
+// @success 200 {object} portainer.SnapshotWebhook
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /snapshot_webhooks/{id} [put]
+func (handler *Handler) snapshotWebhookUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	webhookID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid snapshot webhook identifier route variable", err)
+	}
+
+	var payload snapshotWebhookUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var webhook *portainer.SnapshotWebhook
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		webhook, err = tx.SnapshotWebhook().Read(portainer.SnapshotWebhookID(webhookID))
+		if tx.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a snapshot webhook with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a snapshot webhook with the specified identifier inside the database", err)
+		}
+
+		if payload.Name != nil {
+			webhook.Name = *payload.Name
+		}
+
+		if payload.URL != nil {
+			webhook.URL = *payload.URL
+		}
+
+		if payload.Secret != nil {
+			webhook.Secret = *payload.Secret
+		}
+
+		if payload.EndpointGroupIDs != nil {
+			if err := checkEndpointGroups(tx, payload.EndpointGroupIDs); err != nil {
+				return err
+			}
+
+			webhook.EndpointGroupIDs = payload.EndpointGroupIDs
+		}
+
+		if payload.Mode != nil {
+			webhook.Mode = *payload.Mode
+		}
+
+		if payload.FieldMapping != nil {
+			webhook.FieldMapping = payload.FieldMapping
+		}
+
+		if payload.Timeout != nil {
+			webhook.Timeout = *payload.Timeout
+		}
+
+		if payload.Enabled != nil {
+			webhook.Enabled = *payload.Enabled
+		}
+
+		if err := tx.SnapshotWebhook().Update(webhook.ID, webhook); err != nil {
+			return httperror.InternalServerError("Unable to persist snapshot webhook changes inside the database", err)
+		}
+
+		return nil
+	})
+
+	return txResponse(w, webhook, err)
+}
@@ -0,0 +1,37 @@
+package ssl
+
+import (
+	"errors"
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id SSLACMERenew
+// @summary Renew the ACME certificate
+// @description Request a new certificate from the ACME certificate authority in the background, regardless of the
+// @description expiry of the current certificate. The outcome is available through the ssl settings.
+// @description **Access policy**: administrator
+// @tags ssl
+// @security ApiKeyAuth
+// @security jwt
+// @success 204 "Success"
+// @failure 400 "ACME is not enabled"
+// @failure 403 "Permission denied to access settings"
+// @failure 500 "Server error"
+// @router /ssl/acme/renew [post]
+func (handler *Handler) acmeRenew(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	settings, err := handler.SSLService.GetSSLSettings()
+	if err != nil {
+		return httperror.InternalServerError("Failed to fetch certificate info", err)
+	}
+
+	if settings.ACME == nil || !settings.ACME.Enabled {
+		return httperror.BadRequest("ACME is not enabled", errors.New("the ACME certificate management is not enabled"))
+	}
+
+	handler.ACMEService.Trigger()
+
+	return response.Empty(w)
+}
@@ -0,0 +1,78 @@
+package ssl
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/acme"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type acmeUpdatePayload struct {
+	// Whether the certificate is obtained and renewed automatically
+	Enabled bool `example:"true"`
+	// Contact email of the ACME account
+	Email string `example:"admin@example.com"`
+	// Domains covered by the certificate
+	Domains []string `example:"portainer.example.com"`
+	// Directory URL of the certificate authority, defaults to the Let's Encrypt production directory
+	DirectoryURL string `example:"https://acme-staging-v02.api.letsencrypt.org/directory"`
+	// Challenge used to prove the control of the domains, http-01 or dns-01
+	ChallengeType portainer.ACMEChallengeType `example:"http-01"`
+	// DNS provider used by the dns-01 challenge, cloudflare or httpreq
+	DNSProvider string `example:"cloudflare"`
+	// Credentials of the DNS provider, the current credentials are kept when omitted
+	DNSCredentials map[string]string
+}
+
+func (payload *acmeUpdatePayload) Validate(r *http.Request) error {
+	return nil
+}
+
+// @id SSLACMEUpdate
+// @summary Update the ACME settings
+// @description Update the settings used to obtain the HTTPS certificate from an ACME certificate authority such as
+// @description Let's Encrypt. When enabled, a certificate is requested in the background and replaces the current
+// @description certificate without a restart, it is then renewed before it expires. The http-01 challenge requires
+// @description Portainer to be reachable on port 80 of the domains, the outcome of the last attempt is available
+// @description through the ssl settings.
+// @description **Access policy**: administrator
+// @tags ssl
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @param body body acmeUpdatePayload true "ACME settings"
+// @success 204 "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied to access settings"
+// @failure 500 "Server error"
+// @router /ssl/acme [put]
+func (handler *Handler) acmeUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload acmeUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	settings := &portainer.ACMESettings{
+		Enabled:        payload.Enabled,
+		Email:          payload.Email,
+		Domains:        payload.Domains,
+		DirectoryURL:   payload.DirectoryURL,
+		ChallengeType:  payload.ChallengeType,
+		DNSProvider:    payload.DNSProvider,
+		DNSCredentials: payload.DNSCredentials,
+	}
+
+	if err := handler.ACMEService.UpdateSettings(settings); err != nil {
+		if errors.Is(err, acme.ErrInvalidSettings) {
+			return httperror.BadRequest("Invalid ACME settings", err)
+		}
+
+		return httperror.InternalServerError("Unable to persist the ACME settings", err)
+	}
+
+	return response.Empty(w)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/acme"
 	"github.com/portainer/portainer/api/internal/ssl"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 
@@ -13,7 +14,8 @@ import (
 // Handler is the HTTP handler used to handle MOTD operations.
 type Handler struct {
 	*mux.Router
-	SSLService *ssl.Service
+	SSLService  *ssl.Service
+	ACMEService *acme.Service
 }
 
 // NewHandler returns a new Handler
@@ -25,6 +27,10 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 		bouncer.AdminAccess(httperror.LoggerHandler(h.sslInspect))).Methods(http.MethodGet)
 	h.Handle("/ssl",
 		bouncer.AdminAccess(httperror.LoggerHandler(h.sslUpdate))).Methods(http.MethodPut)
+	h.Handle("/ssl/acme",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.acmeUpdate))).Methods(http.MethodPut)
+	h.Handle("/ssl/acme/renew",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.acmeRenew))).Methods(http.MethodPost)
 
 	return h
 }
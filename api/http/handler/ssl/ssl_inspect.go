@@ -9,7 +9,7 @@ import (
 
 // @id SSLInspect
 // @summary Inspect the ssl settings
-// @description Retrieve the ssl settings.
+// @description Retrieve the ssl settings. The credentials of the DNS provider used by ACME are not returned.
 // @description **Access policy**: administrator
 // @tags ssl
 // @security ApiKeyAuth
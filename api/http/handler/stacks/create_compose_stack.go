@@ -7,15 +7,17 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/git"
 	"github.com/portainer/portainer/api/git/update"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/ociartifact"
+	"github.com/portainer/portainer/api/internal/registryutils/access"
 	"github.com/portainer/portainer/api/stacks/deployments"
 	"github.com/portainer/portainer/api/stacks/stackbuilders"
 	"github.com/portainer/portainer/api/stacks/stackutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 
-	"github.com/asaskevich/govalidator"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 )
@@ -27,6 +29,8 @@ type composeStackFromFileContentPayload struct {
 	StackFileContent string `example:"version: 3\n services:\n web:\n image:nginx" validate:"required"`
 	// A list of environment variables used during stack deployment
 	Env []portainer.Pair
+	// Compose profiles to enable, the services assigned to other profiles are not deployed
+	Profiles []string `example:"debug"`
 	// Whether the stack is from a app template
 	FromAppTemplate bool `example:"false"`
 }
@@ -39,7 +43,8 @@ func (payload *composeStackFromFileContentPayload) Validate(r *http.Request) err
 	if len(payload.StackFileContent) == 0 {
 		return errors.New("Invalid stack file content")
 	}
-	return nil
+
+	return stackutils.ValidateComposeProfiles(payload.Profiles)
 }
 
 func createStackPayloadFromComposeFileContentPayload(name string, fileContent string, env []portainer.Pair, fromAppTemplate bool) stackbuilders.StackPayload {
@@ -140,6 +145,7 @@ func (handler *Handler) createComposeStackFromFileContent(w http.ResponseWriter,
 	}
 
 	stackPayload := createStackPayloadFromComposeFileContentPayload(payload.Name, payload.StackFileContent, payload.Env, payload.FromAppTemplate)
+	stackPayload.Profiles = payload.Profiles
 
 	composeStackBuilder := stackbuilders.CreateComposeStackFileContentBuilder(securityContext,
 		handler.DataStore,
@@ -168,6 +174,17 @@ type composeStackFromGitRepositoryPayload struct {
 	RepositoryUsername string `example:"myGitUsername"`
 	// Password used in basic authentication. Required when RepositoryAuthentication is true.
 	RepositoryPassword string `example:"myGitPassword"`
+	// PEM encoded private key used to authenticate over SSH, as an alternative to RepositoryPassword
+	RepositorySSHPrivateKey string
+	// Passphrase of the SSH private key
+	RepositorySSHPassphrase string
+	// Number of commits fetched when cloning the repository, only the latest commit is fetched when 0
+	RepositoryCloneDepth int `example:"1"`
+	// Whether only the directories of the stack files and RepositorySparseCheckoutPaths are checked out,
+	// to avoid copying the whole tree of a large repository
+	RepositorySparseCheckout bool `example:"false"`
+	// Directories checked out in addition to the directories of the stack files, such as the build contexts
+	RepositorySparseCheckoutPaths []string `example:"shared/config"`
 	// Path to the Stack file inside the Git repository
 	ComposeFile string `example:"docker-compose.yml" default:"docker-compose.yml"`
 	// Applicable when deploying with multiple stack files
@@ -176,13 +193,15 @@ type composeStackFromGitRepositoryPayload struct {
 	AutoUpdate *portainer.AutoUpdateSettings
 	// A list of environment variables used during stack deployment
 	Env []portainer.Pair
+	// Compose profiles to enable, the services assigned to other profiles are not deployed
+	Profiles []string `example:"debug"`
 	// Whether the stack is from a app template
 	FromAppTemplate bool `example:"false"`
 	// TLSSkipVerify skips SSL verification when cloning the Git repository
 	TLSSkipVerify bool `example:"false"`
 }
 
-func createStackPayloadFromComposeGitPayload(name, repoUrl, repoReference, repoUsername, repoPassword string, repoAuthentication bool, composeFile string, additionalFiles []string, autoUpdate *portainer.AutoUpdateSettings, env []portainer.Pair, fromAppTemplate bool, repoSkipSSLVerify bool) stackbuilders.StackPayload {
+func createStackPayloadFromComposeGitPayload(name, repoUrl, repoReference, repoUsername, repoPassword, repoSSHPrivateKey, repoSSHPassphrase string, repoAuthentication bool, composeFile string, additionalFiles []string, autoUpdate *portainer.AutoUpdateSettings, env []portainer.Pair, fromAppTemplate bool, repoSkipSSLVerify bool) stackbuilders.StackPayload {
 	return stackbuilders.StackPayload{
 		Name: name,
 		RepositoryConfigPayload: stackbuilders.RepositoryConfigPayload{
@@ -191,6 +210,8 @@ func createStackPayloadFromComposeGitPayload(name, repoUrl, repoReference, repoU
 			Authentication: repoAuthentication,
 			Username:       repoUsername,
 			Password:       repoPassword,
+			SSHPrivateKey:  repoSSHPrivateKey,
+			SSHPassphrase:  repoSSHPassphrase,
 			TLSSkipVerify:  repoSkipSSLVerify,
 		},
 		ComposeFile:     composeFile,
@@ -205,16 +226,19 @@ func (payload *composeStackFromGitRepositoryPayload) Validate(r *http.Request) e
 	if len(payload.Name) == 0 {
 		return errors.New("Invalid stack name")
 	}
-	if len(payload.RepositoryURL) == 0 || !govalidator.IsURL(payload.RepositoryURL) {
+	if !git.IsValidRepositoryURL(payload.RepositoryURL) {
 		return errors.New("Invalid repository URL. Must correspond to a valid URL format")
 	}
-	if payload.RepositoryAuthentication && len(payload.RepositoryPassword) == 0 {
-		return errors.New("Invalid repository credentials. Password must be specified when authentication is enabled")
+	if payload.RepositoryAuthentication && len(payload.RepositoryPassword) == 0 && len(payload.RepositorySSHPrivateKey) == 0 {
+		return errors.New("Invalid repository credentials. Password or SSH private key must be specified when authentication is enabled")
 	}
 	if err := update.ValidateAutoUpdateSettings(payload.AutoUpdate); err != nil {
 		return err
 	}
-	return nil
+	if err := git.ValidateCloneSettings(payload.RepositoryCloneDepth, payload.RepositorySparseCheckoutPaths); err != nil {
+		return err
+	}
+	return stackutils.ValidateComposeProfiles(payload.Profiles)
 }
 
 // @id StackCreateDockerStandaloneRepository
@@ -288,6 +312,8 @@ func (handler *Handler) createComposeStackFromGitRepository(w http.ResponseWrite
 		payload.RepositoryReferenceName,
 		payload.RepositoryUsername,
 		payload.RepositoryPassword,
+		payload.RepositorySSHPrivateKey,
+		payload.RepositorySSHPassphrase,
 		payload.RepositoryAuthentication,
 		payload.ComposeFile,
 		payload.AdditionalFiles,
@@ -296,6 +322,10 @@ func (handler *Handler) createComposeStackFromGitRepository(w http.ResponseWrite
 		payload.FromAppTemplate,
 		payload.TLSSkipVerify,
 	)
+	stackPayload.Profiles = payload.Profiles
+	stackPayload.CloneDepth = payload.RepositoryCloneDepth
+	stackPayload.SparseCheckout = payload.RepositorySparseCheckout
+	stackPayload.SparseCheckoutPaths = payload.RepositorySparseCheckoutPaths
 
 	composeStackBuilder := stackbuilders.CreateComposeStackGitBuilder(securityContext,
 		handler.DataStore,
@@ -313,10 +343,139 @@ func (handler *Handler) createComposeStackFromGitRepository(w http.ResponseWrite
 	return handler.decorateStackResponse(w, stack, userID)
 }
 
+type composeStackFromOCIArtifactPayload struct {
+	// Name of the stack
+	Name string `example:"myStack" validate:"required"`
+	// Identifier of the registry hosting the artifact
+	RegistryID portainer.RegistryID `example:"1" validate:"required"`
+	// Repository of the artifact inside the registry
+	Repository string `example:"myorg/mystack" validate:"required"`
+	// Tag or digest of the artifact. A digest pins the stack to a single version of the artifact
+	Reference string `example:"latest" validate:"required"`
+	// Name of the Stack file inside the artifact
+	ComposeFile string `example:"docker-compose.yml" default:"docker-compose.yml"`
+	// Applicable when deploying with multiple stack files
+	AdditionalFiles []string `example:"[nz.compose.yml, uat.compose.yml]"`
+	// Optional update configuration, the artifact is redeployed when its tag points to a new version
+	AutoUpdate *portainer.AutoUpdateSettings
+	// A list of environment variables used during stack deployment
+	Env []portainer.Pair
+	// Compose profiles to enable, the services assigned to other profiles are not deployed
+	Profiles []string `example:"debug"`
+}
+
+func (payload *composeStackFromOCIArtifactPayload) Validate(r *http.Request) error {
+	if len(payload.Name) == 0 {
+		return errors.New("Invalid stack name")
+	}
+
+	config := &portainer.OCIConfig{RegistryID: payload.RegistryID, Repository: payload.Repository, Reference: payload.Reference}
+	if err := ociartifact.ValidateConfig(config); err != nil {
+		return err
+	}
+
+	if err := update.ValidateAutoUpdateSettings(payload.AutoUpdate); err != nil {
+		return err
+	}
+
+	if payload.AutoUpdate != nil && ociartifact.IsPinned(config) {
+		return errors.New("A stack pinned to a digest cannot be updated automatically")
+	}
+
+	return stackutils.ValidateComposeProfiles(payload.Profiles)
+}
+
+// @id StackCreateDockerStandaloneOCI
+// @summary Deploy a new compose stack from an OCI artifact
+// @description Deploy a new stack into a Docker environment specified via the environment identifier.
+// @description The Stack files are pulled from an OCI artifact, as pushed by oras, hosted by a registry managed by Portainer.
+// @description **Access policy**: authenticated
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @accept json
+// @param endpointId query int true "Identifier of the environment that will be used to deploy the stack"
+// @param body body composeStackFromOCIArtifactPayload true "stack config"
+// @success 200 {object} portainer.Stack
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied to access the registry"
+// @failure 409 "Stack name or webhook ID already exists"
+// @failure 500 "Server error"
+// @router /stacks/create/standalone/oci [post]
+func (handler *Handler) createComposeStackFromOCIArtifact(w http.ResponseWriter, r *http.Request, endpoint *portainer.Endpoint, userID portainer.UserID) *httperror.HandlerError {
+	var payload composeStackFromOCIArtifactPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	payload.Name = handler.ComposeStackManager.NormalizeStackName(payload.Name)
+	if payload.ComposeFile == "" {
+		payload.ComposeFile = filesystem.ComposeFileDefaultName
+	}
+
+	if _, err := access.GetAccessibleRegistry(handler.DataStore, userID, endpoint.ID, payload.RegistryID); handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a registry with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.Forbidden("Permission denied to access the registry", err)
+	}
+
+	isUnique, err := handler.checkUniqueStackNameInDocker(endpoint, payload.Name, 0, false)
+	if err != nil {
+		return httperror.InternalServerError("Unable to check for name collision", err)
+	} else if !isUnique {
+		return stackExistsError(payload.Name)
+	}
+
+	if payload.AutoUpdate != nil && payload.AutoUpdate.Webhook != "" {
+		isUnique, err := handler.checkUniqueWebhookID(payload.AutoUpdate.Webhook)
+		if err != nil {
+			return httperror.InternalServerError("Unable to check for webhook ID collision", err)
+		} else if !isUnique {
+			return httperror.Conflict(fmt.Sprintf("Webhook ID: %s already exists", payload.AutoUpdate.Webhook), stackutils.ErrWebhookIDAlreadyExists)
+		}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve info from request context", err)
+	}
+
+	stackPayload := stackbuilders.StackPayload{
+		Name: payload.Name,
+		OCIConfig: &portainer.OCIConfig{
+			RegistryID: payload.RegistryID,
+			Repository: payload.Repository,
+			Reference:  payload.Reference,
+		},
+		ComposeFile:     payload.ComposeFile,
+		AdditionalFiles: payload.AdditionalFiles,
+		AutoUpdate:      payload.AutoUpdate,
+		Env:             payload.Env,
+		Profiles:        payload.Profiles,
+	}
+
+	composeStackBuilder := stackbuilders.CreateComposeStackOCIBuilder(securityContext,
+		handler.DataStore,
+		handler.FileService,
+		handler.OCIArtifactService,
+		handler.Scheduler,
+		handler.StackDeployer)
+
+	stackBuilderDirector := stackbuilders.NewStackBuilderDirector(composeStackBuilder)
+	stack, httpErr := stackBuilderDirector.Build(&stackPayload, endpoint)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	return handler.decorateStackResponse(w, stack, userID)
+}
+
 type composeStackFromFileUploadPayload struct {
 	Name             string
 	StackFileContent []byte
 	Env              []portainer.Pair
+	Profiles         []string
 }
 
 func createStackPayloadFromComposeFileUploadPayload(name string, fileContentBytes []byte, env []portainer.Pair) stackbuilders.StackPayload {
@@ -347,6 +506,17 @@ func decodeRequestForm(r *http.Request) (*composeStackFromFileUploadPayload, err
 		return nil, errors.New("Invalid Env parameter")
 	}
 	payload.Env = env
+
+	var profiles []string
+	if err := request.RetrieveMultiPartFormJSONValue(r, "Profiles", &profiles, true); err != nil {
+		return nil, errors.New("Invalid Profiles parameter")
+	}
+
+	if err := stackutils.ValidateComposeProfiles(profiles); err != nil {
+		return nil, err
+	}
+	payload.Profiles = profiles
+
 	return payload, nil
 }
 
@@ -361,6 +531,7 @@ func decodeRequestForm(r *http.Request) (*composeStackFromFileUploadPayload, err
 // @produce json
 // @param Name formData string true "Name of the stack"
 // @param Env formData string false "Environment variables passed during deployment, represented as a JSON array [{'name': 'name', 'value': 'value'}]."
+// @param Profiles formData string false "Compose profiles to enable, represented as a JSON array ['debug']"
 // @param file formData file false "Stack file"
 // @param endpointId query int true "Identifier of the environment that will be used to deploy the stack"
 // @success 200 {object} portainer.Stack
@@ -403,6 +574,7 @@ func (handler *Handler) createComposeStackFromFileUpload(w http.ResponseWriter,
 	}
 
 	stackPayload := createStackPayloadFromComposeFileUploadPayload(payload.Name, payload.StackFileContent, payload.Env)
+	stackPayload.Profiles = payload.Profiles
 
 	composeStackBuilder := stackbuilders.CreateComposeStackFileUploadBuilder(securityContext,
 		handler.DataStore,
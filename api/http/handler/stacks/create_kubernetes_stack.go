@@ -5,9 +5,9 @@ import (
 	"net/http"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/git"
 	"github.com/portainer/portainer/api/git/update"
 	"github.com/portainer/portainer/api/internal/endpointutils"
-	"github.com/portainer/portainer/api/internal/registryutils"
 	k "github.com/portainer/portainer/api/kubernetes"
 	"github.com/portainer/portainer/api/stacks/deployments"
 	"github.com/portainer/portainer/api/stacks/stackbuilders"
@@ -27,6 +27,10 @@ type kubernetesStringDeploymentPayload struct {
 	StackFileContent string
 	// Whether the stack is from a app template
 	FromAppTemplate bool `example:"false"`
+	// Run a server-side dry run of the manifests before deploying them, the stack is not created when they are invalid
+	ValidateManifests bool `example:"false"`
+	// Reject the fields of the manifests that are not part of the schemas of the resources, requires ValidateManifests
+	StrictValidation bool `example:"false"`
 }
 
 func createStackPayloadFromK8sFileContentPayload(name, namespace, fileContent string, composeFormat, fromAppTemplate bool) stackbuilders.StackPayload {
@@ -47,14 +51,27 @@ type kubernetesGitDeploymentPayload struct {
 	RepositoryAuthentication bool
 	RepositoryUsername       string
 	RepositoryPassword       string
+	RepositorySSHPrivateKey  string
+	RepositorySSHPassphrase  string
 	ManifestFile             string
 	AdditionalFiles          []string
 	AutoUpdate               *portainer.AutoUpdateSettings
 	// TLSSkipVerify skips SSL verification when cloning the Git repository
 	TLSSkipVerify bool `example:"false"`
+	// Number of commits fetched when cloning the repository, only the latest commit is fetched when 0
+	RepositoryCloneDepth int `example:"1"`
+	// Whether only the directories of the stack files and RepositorySparseCheckoutPaths are checked out,
+	// to avoid copying the whole tree of a large repository
+	RepositorySparseCheckout bool `example:"false"`
+	// Directories checked out in addition to the directories of the stack files, such as the build contexts
+	RepositorySparseCheckoutPaths []string `example:"shared/config"`
+	// Run a server-side dry run of the manifests before deploying them, the stack is not created when they are invalid
+	ValidateManifests bool `example:"false"`
+	// Reject the fields of the manifests that are not part of the schemas of the resources, requires ValidateManifests
+	StrictValidation bool `example:"false"`
 }
 
-func createStackPayloadFromK8sGitPayload(name, repoUrl, repoReference, repoUsername, repoPassword string, repoAuthentication, composeFormat bool, namespace, manifest string, additionalFiles []string, autoUpdate *portainer.AutoUpdateSettings, repoSkipSSLVerify bool) stackbuilders.StackPayload {
+func createStackPayloadFromK8sGitPayload(name, repoUrl, repoReference, repoUsername, repoPassword, repoSSHPrivateKey, repoSSHPassphrase string, repoAuthentication, composeFormat bool, namespace, manifest string, additionalFiles []string, autoUpdate *portainer.AutoUpdateSettings, repoSkipSSLVerify bool) stackbuilders.StackPayload {
 	return stackbuilders.StackPayload{
 		StackName: name,
 		RepositoryConfigPayload: stackbuilders.RepositoryConfigPayload{
@@ -63,6 +80,8 @@ func createStackPayloadFromK8sGitPayload(name, repoUrl, repoReference, repoUsern
 			Authentication: repoAuthentication,
 			Username:       repoUsername,
 			Password:       repoPassword,
+			SSHPrivateKey:  repoSSHPrivateKey,
+			SSHPassphrase:  repoSSHPassphrase,
 			TLSSkipVerify:  repoSkipSSLVerify,
 		},
 		Namespace:       namespace,
@@ -77,6 +96,10 @@ type kubernetesManifestURLDeploymentPayload struct {
 	Namespace     string
 	ComposeFormat bool
 	ManifestURL   string
+	// Run a server-side dry run of the manifests before deploying them, the stack is not created when they are invalid
+	ValidateManifests bool `example:"false"`
+	// Reject the fields of the manifests that are not part of the schemas of the resources, requires ValidateManifests
+	StrictValidation bool `example:"false"`
 }
 
 func createStackPayloadFromK8sUrlPayload(name, namespace, manifestUrl string, composeFormat bool) stackbuilders.StackPayload {
@@ -92,22 +115,30 @@ func (payload *kubernetesStringDeploymentPayload) Validate(r *http.Request) erro
 		return errors.New("Invalid stack file content")
 	}
 
-	return nil
+	return validateManifestValidation(payload.ValidateManifests, payload.StrictValidation)
 }
 
 func (payload *kubernetesGitDeploymentPayload) Validate(r *http.Request) error {
-	if len(payload.RepositoryURL) == 0 || !govalidator.IsURL(payload.RepositoryURL) {
+	if !git.IsValidRepositoryURL(payload.RepositoryURL) {
 		return errors.New("Invalid repository URL. Must correspond to a valid URL format")
 	}
 
-	if payload.RepositoryAuthentication && len(payload.RepositoryPassword) == 0 {
-		return errors.New("Invalid repository credentials. Password must be specified when authentication is enabled")
+	if payload.RepositoryAuthentication && len(payload.RepositoryPassword) == 0 && len(payload.RepositorySSHPrivateKey) == 0 {
+		return errors.New("Invalid repository credentials. Password or SSH private key must be specified when authentication is enabled")
 	}
 
 	if len(payload.ManifestFile) == 0 {
 		return errors.New("Invalid manifest file in repository")
 	}
 
+	if err := git.ValidateCloneSettings(payload.RepositoryCloneDepth, payload.RepositorySparseCheckoutPaths); err != nil {
+		return err
+	}
+
+	if err := validateManifestValidation(payload.ValidateManifests, payload.StrictValidation); err != nil {
+		return err
+	}
+
 	return update.ValidateAutoUpdateSettings(payload.AutoUpdate)
 }
 
@@ -116,6 +147,14 @@ func (payload *kubernetesManifestURLDeploymentPayload) Validate(r *http.Request)
 		return errors.New("Invalid manifest URL")
 	}
 
+	return validateManifestValidation(payload.ValidateManifests, payload.StrictValidation)
+}
+
+func validateManifestValidation(validateManifests, strictValidation bool) error {
+	if strictValidation && !validateManifests {
+		return errors.New("Invalid StrictValidation, ValidateManifests must be enabled")
+	}
+
 	return nil
 }
 
@@ -123,6 +162,27 @@ type createKubernetesStackResponse struct {
 	Output string `json:"Output"`
 }
 
+type kubernetesManifestValidationResponse struct {
+	Message string `json:"message"`
+	Details string `json:"details"`
+	// Errors found in the documents of the manifests
+	Errors []portainer.KubernetesManifestError `json:"Errors"`
+}
+
+// writeKubernetesStackBuildError writes the errors found by the validation of the manifests along with the error of the build
+func writeKubernetesStackBuildError(w http.ResponseWriter, handlerErr *httperror.HandlerError) *httperror.HandlerError {
+	var validationErr *deployments.ManifestValidationError
+	if !errors.As(handlerErr.Err, &validationErr) {
+		return handlerErr
+	}
+
+	return response.JSONWithStatus(w, kubernetesManifestValidationResponse{
+		Message: handlerErr.Message,
+		Details: validationErr.Error(),
+		Errors:  validationErr.Errors,
+	}, http.StatusBadRequest)
+}
+
 // @id StackCreateKubernetesFile
 // @summary Deploy a new kubernetes stack from a file
 // @description Deploy a new stack into a Docker environment specified via the environment identifier.
@@ -134,7 +194,7 @@ type createKubernetesStackResponse struct {
 // @param body body kubernetesStringDeploymentPayload true "stack config"
 // @param endpointId query int true "Identifier of the environment that will be used to deploy the stack"
 // @success 200 {object} portainer.Stack
-// @failure 400 "Invalid request"
+// @failure 400 {object} kubernetesManifestValidationResponse "Invalid request or invalid manifests"
 // @failure 500 "Server error"
 // @router /stacks/create/kubernetes/string [post]
 func (handler *Handler) createKubernetesStackFromFileContent(w http.ResponseWriter, r *http.Request, endpoint *portainer.Endpoint, userID portainer.UserID) *httperror.HandlerError {
@@ -153,6 +213,8 @@ func (handler *Handler) createKubernetesStackFromFileContent(w http.ResponseWrit
 	}
 
 	stackPayload := createStackPayloadFromK8sFileContentPayload(payload.StackName, payload.Namespace, payload.StackFileContent, payload.ComposeFormat, payload.FromAppTemplate)
+	stackPayload.ValidateManifests = payload.ValidateManifests
+	stackPayload.StrictValidation = payload.StrictValidation
 
 	k8sStackBuilder := stackbuilders.CreateK8sStackFileContentBuilder(handler.DataStore,
 		handler.FileService,
@@ -160,17 +222,9 @@ func (handler *Handler) createKubernetesStackFromFileContent(w http.ResponseWrit
 		handler.KubernetesDeployer,
 		user)
 
-	// Refresh ECR registry secret if needed
-	// RefreshEcrSecret method checks if the namespace has any ECR registry
-	// otherwise return nil
-	cli, err := handler.KubernetesClientFactory.GetPrivilegedKubeClient(endpoint)
-	if err == nil {
-		registryutils.RefreshEcrSecret(cli, endpoint, handler.DataStore, payload.Namespace)
-	}
-
 	stackBuilderDirector := stackbuilders.NewStackBuilderDirector(k8sStackBuilder)
 	if _, err := stackBuilderDirector.Build(&stackPayload, endpoint); err != nil {
-		return err
+		return writeKubernetesStackBuildError(w, err)
 	}
 
 	resp := &createKubernetesStackResponse{
@@ -191,7 +245,7 @@ func (handler *Handler) createKubernetesStackFromFileContent(w http.ResponseWrit
 // @param body body kubernetesGitDeploymentPayload true "stack config"
 // @param endpointId query int true "Identifier of the environment that will be used to deploy the stack"
 // @success 200 {object} portainer.Stack
-// @failure 400 "Invalid request"
+// @failure 400 {object} kubernetesManifestValidationResponse "Invalid request or invalid manifests"
 // @failure 409 "Stack name or webhook ID already exists"
 // @failure 500 "Server error"
 // @router /stacks/create/kubernetes/repository [post]
@@ -224,6 +278,8 @@ func (handler *Handler) createKubernetesStackFromGitRepository(w http.ResponseWr
 		payload.RepositoryReferenceName,
 		payload.RepositoryUsername,
 		payload.RepositoryPassword,
+		payload.RepositorySSHPrivateKey,
+		payload.RepositorySSHPassphrase,
 		payload.RepositoryAuthentication,
 		payload.ComposeFormat,
 		payload.Namespace,
@@ -232,6 +288,11 @@ func (handler *Handler) createKubernetesStackFromGitRepository(w http.ResponseWr
 		payload.AutoUpdate,
 		payload.TLSSkipVerify,
 	)
+	stackPayload.ValidateManifests = payload.ValidateManifests
+	stackPayload.StrictValidation = payload.StrictValidation
+	stackPayload.CloneDepth = payload.RepositoryCloneDepth
+	stackPayload.SparseCheckout = payload.RepositorySparseCheckout
+	stackPayload.SparseCheckoutPaths = payload.RepositorySparseCheckoutPaths
 
 	k8sStackBuilder := stackbuilders.CreateKubernetesStackGitBuilder(handler.DataStore,
 		handler.FileService,
@@ -243,7 +304,7 @@ func (handler *Handler) createKubernetesStackFromGitRepository(w http.ResponseWr
 
 	stackBuilderDirector := stackbuilders.NewStackBuilderDirector(k8sStackBuilder)
 	if _, err := stackBuilderDirector.Build(&stackPayload, endpoint); err != nil {
-		return err
+		return writeKubernetesStackBuildError(w, err)
 	}
 
 	return response.JSON(w, &createKubernetesStackResponse{
@@ -262,7 +323,7 @@ func (handler *Handler) createKubernetesStackFromGitRepository(w http.ResponseWr
 // @param body body kubernetesManifestURLDeploymentPayload true "stack config"
 // @param endpointId query int true "Identifier of the environment that will be used to deploy the stack"
 // @success 200 {object} portainer.Stack
-// @failure 400 "Invalid request"
+// @failure 400 {object} kubernetesManifestValidationResponse "Invalid request or invalid manifests"
 // @failure 500 "Server error"
 // @router /stacks/create/kubernetes/url [post]
 func (handler *Handler) createKubernetesStackFromManifestURL(w http.ResponseWriter, r *http.Request, endpoint *portainer.Endpoint, userID portainer.UserID) *httperror.HandlerError {
@@ -280,6 +341,8 @@ func (handler *Handler) createKubernetesStackFromManifestURL(w http.ResponseWrit
 		payload.Namespace,
 		payload.ManifestURL,
 		payload.ComposeFormat)
+	stackPayload.ValidateManifests = payload.ValidateManifests
+	stackPayload.StrictValidation = payload.StrictValidation
 
 	k8sStackBuilder := stackbuilders.CreateKubernetesStackUrlBuilder(handler.DataStore,
 		handler.FileService,
@@ -289,7 +352,7 @@ func (handler *Handler) createKubernetesStackFromManifestURL(w http.ResponseWrit
 
 	stackBuilderDirector := stackbuilders.NewStackBuilderDirector(k8sStackBuilder)
 	if _, err := stackBuilderDirector.Build(&stackPayload, endpoint); err != nil {
-		return err
+		return writeKubernetesStackBuildError(w, err)
 	}
 
 	return response.JSON(w, &createKubernetesStackResponse{
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/git"
 	"github.com/portainer/portainer/api/git/update"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/stacks/stackbuilders"
@@ -12,7 +13,6 @@ import (
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 
-	"github.com/asaskevich/govalidator"
 	"github.com/pkg/errors"
 )
 
@@ -123,6 +123,17 @@ type swarmStackFromGitRepositoryPayload struct {
 	RepositoryUsername string `example:"myGitUsername"`
 	// Password used in basic authentication. Required when RepositoryAuthentication is true.
 	RepositoryPassword string `example:"myGitPassword"`
+	// PEM encoded private key used to authenticate over SSH, as an alternative to RepositoryPassword
+	RepositorySSHPrivateKey string
+	// Passphrase of the SSH private key
+	RepositorySSHPassphrase string
+	// Number of commits fetched when cloning the repository, only the latest commit is fetched when 0
+	RepositoryCloneDepth int `example:"1"`
+	// Whether only the directories of the stack files and RepositorySparseCheckoutPaths are checked out,
+	// to avoid copying the whole tree of a large repository
+	RepositorySparseCheckout bool `example:"false"`
+	// Directories checked out in addition to the directories of the stack files, such as the build contexts
+	RepositorySparseCheckoutPaths []string `example:"shared/config"`
 	// Whether the stack is from a app template
 	FromAppTemplate bool `example:"false"`
 	// Path to the Stack file inside the Git repository
@@ -142,19 +153,22 @@ func (payload *swarmStackFromGitRepositoryPayload) Validate(r *http.Request) err
 	if len(payload.SwarmID) == 0 {
 		return errors.New("Invalid Swarm ID")
 	}
-	if len(payload.RepositoryURL) == 0 || !govalidator.IsURL(payload.RepositoryURL) {
+	if !git.IsValidRepositoryURL(payload.RepositoryURL) {
 		return errors.New("Invalid repository URL. Must correspond to a valid URL format")
 	}
-	if payload.RepositoryAuthentication && len(payload.RepositoryPassword) == 0 {
-		return errors.New("Invalid repository credentials. Password must be specified when authentication is enabled")
+	if payload.RepositoryAuthentication && len(payload.RepositoryPassword) == 0 && len(payload.RepositorySSHPrivateKey) == 0 {
+		return errors.New("Invalid repository credentials. Password or SSH private key must be specified when authentication is enabled")
 	}
 	if err := update.ValidateAutoUpdateSettings(payload.AutoUpdate); err != nil {
 		return err
 	}
+	if err := git.ValidateCloneSettings(payload.RepositoryCloneDepth, payload.RepositorySparseCheckoutPaths); err != nil {
+		return err
+	}
 	return nil
 }
 
-func createStackPayloadFromSwarmGitPayload(name, swarmID, repoUrl, repoReference, repoUsername, repoPassword string, repoAuthentication bool, composeFile string, additionalFiles []string, autoUpdate *portainer.AutoUpdateSettings, env []portainer.Pair, fromAppTemplate bool, repoSkipSSLVerify bool) stackbuilders.StackPayload {
+func createStackPayloadFromSwarmGitPayload(name, swarmID, repoUrl, repoReference, repoUsername, repoPassword, repoSSHPrivateKey, repoSSHPassphrase string, repoAuthentication bool, composeFile string, additionalFiles []string, autoUpdate *portainer.AutoUpdateSettings, env []portainer.Pair, fromAppTemplate bool, repoSkipSSLVerify bool) stackbuilders.StackPayload {
 	return stackbuilders.StackPayload{
 		Name:    name,
 		SwarmID: swarmID,
@@ -164,6 +178,8 @@ func createStackPayloadFromSwarmGitPayload(name, swarmID, repoUrl, repoReference
 			Authentication: repoAuthentication,
 			Username:       repoUsername,
 			Password:       repoPassword,
+			SSHPrivateKey:  repoSSHPrivateKey,
+			SSHPassphrase:  repoSSHPassphrase,
 			TLSSkipVerify:  repoSkipSSLVerify,
 		},
 		ComposeFile:     composeFile,
@@ -229,6 +245,8 @@ func (handler *Handler) createSwarmStackFromGitRepository(w http.ResponseWriter,
 		payload.RepositoryReferenceName,
 		payload.RepositoryUsername,
 		payload.RepositoryPassword,
+		payload.RepositorySSHPrivateKey,
+		payload.RepositorySSHPassphrase,
 		payload.RepositoryAuthentication,
 		payload.ComposeFile,
 		payload.AdditionalFiles,
@@ -237,6 +255,9 @@ func (handler *Handler) createSwarmStackFromGitRepository(w http.ResponseWriter,
 		payload.FromAppTemplate,
 		payload.TLSSkipVerify,
 	)
+	stackPayload.CloneDepth = payload.RepositoryCloneDepth
+	stackPayload.SparseCheckout = payload.RepositorySparseCheckout
+	stackPayload.SparseCheckoutPaths = payload.RepositorySparseCheckoutPaths
 
 	swarmStackBuilder := stackbuilders.CreateSwarmStackGitBuilder(securityContext,
 		handler.DataStore,
@@ -11,15 +11,19 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	dockerclient "github.com/portainer/portainer/api/docker/client"
 	"github.com/portainer/portainer/api/docker/consts"
+	httperrors "github.com/portainer/portainer/api/http/errors"
 	"github.com/portainer/portainer/api/http/middlewares"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/deploymenthistory"
 	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/quotas"
 	"github.com/portainer/portainer/api/kubernetes/cli"
 	"github.com/portainer/portainer/api/scheduler"
 	"github.com/portainer/portainer/api/stacks/deployments"
 	"github.com/portainer/portainer/api/stacks/stackutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
@@ -37,12 +41,30 @@ type Handler struct {
 	DockerClientFactory     *dockerclient.ClientFactory
 	FileService             portainer.FileService
 	GitService              portainer.GitService
+	OCIArtifactService      deployments.OCIArtifactService
 	SwarmStackManager       portainer.SwarmStackManager
 	ComposeStackManager     portainer.ComposeStackManager
 	KubernetesDeployer      portainer.KubernetesDeployer
 	KubernetesClientFactory *cli.ClientFactory
 	Scheduler               *scheduler.Scheduler
 	StackDeployer           deployments.StackDeployer
+	QuotaService            *quotas.Service
+	// DeploymentHistoryService marks the deployments whose services never became healthy as failed, it is optional
+	DeploymentHistoryService *deploymenthistory.Service
+}
+
+// txResponse writes the result of a transaction, the handler errors returned by the transaction are kept as is
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
 }
 
 func stackExistsError(name string) *httperror.HandlerError {
@@ -66,6 +88,14 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 		bouncer.AuthenticatedAccess(middlewares.Deprecated(h, deprecatedStackCreateUrlParser))).Methods(http.MethodPost) // Deprecated
 	h.Handle("/stacks",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackList))).Methods(http.MethodGet)
+	h.Handle("/stacks/queued",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackQueuedDeployments))).Methods(http.MethodGet)
+	h.Handle("/stacks/external",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackExternalList))).Methods(http.MethodGet)
+	h.Handle("/stacks/deploy_group",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackDeployGroup))).Methods(http.MethodPost)
+	h.Handle("/stacks/external/import",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackExternalImport))).Methods(http.MethodPost)
 	h.Handle("/stacks/{id}",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackInspect))).Methods(http.MethodGet)
 	h.Handle("/stacks/{id}",
@@ -80,8 +110,32 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackUpdateGit))).Methods(http.MethodPost)
 	h.Handle("/stacks/{id}/git/redeploy",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackGitRedeploy))).Methods(http.MethodPut)
+	h.Handle("/stacks/{id}/gitops/status",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackGitOpsStatus))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/gitops/resync",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackGitOpsResync))).Methods(http.MethodPost)
+	h.Handle("/stacks/{id}/gitops/pause",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackGitOpsPause))).Methods(http.MethodPost)
+	h.Handle("/stacks/{id}/gitops/resume",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackGitOpsResume))).Methods(http.MethodPost)
 	h.Handle("/stacks/{id}/file",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackFile))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/files",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackFileList))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/files",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackFileUpload))).Methods(http.MethodPost)
+	h.Handle("/stacks/{id}/files/{file}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackFileUpdate))).Methods(http.MethodPut)
+	h.Handle("/stacks/{id}/files/{file}",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackFileDelete))).Methods(http.MethodDelete)
+	h.Handle("/stacks/{id}/env",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackEnvFileInspect))).Methods(http.MethodGet)
+	h.Handle("/stacks/{id}/env",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackEnvFileUpdate))).Methods(http.MethodPut)
+	h.Handle("/stacks/{id}/env",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackEnvFileDelete))).Methods(http.MethodDelete)
+	h.Handle("/stacks/{id}/dependencies",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackDependenciesUpdate))).Methods(http.MethodPut)
 	h.Handle("/stacks/{id}/migrate",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.stackMigrate))).Methods(http.MethodPost)
 	h.Handle("/stacks/{id}/start",
@@ -132,6 +186,44 @@ func (handler *Handler) userCanCreateStack(securityContext *security.RestrictedR
 	return stackutils.UserIsAdminOrEndpointAdmin(user, endpointID)
 }
 
+// authorizeStackManagement ensures that the user of the request can access and manage the stack, it returns the
+// environment of the stack
+func (handler *Handler) authorizeStackManagement(r *http.Request, securityContext *security.RestrictedRequestContext, stack *portainer.Stack) (*portainer.Endpoint, *httperror.HandlerError) {
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(stack.EndpointID)
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find the environment associated to the stack inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find the environment associated to the stack inside the database", err)
+	}
+
+	if err := handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint); err != nil {
+		return nil, httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	if stack.Type == portainer.DockerSwarmStack || stack.Type == portainer.DockerComposeStack {
+		resourceControl, err := handler.DataStore.ResourceControl().ResourceControlByResourceIDAndType(stackutils.ResourceControlID(stack.EndpointID, stack.Name), portainer.StackResourceControl)
+		if err != nil {
+			return nil, httperror.InternalServerError("Unable to retrieve a resource control associated to the stack", err)
+		}
+
+		if access, err := handler.userCanAccessStack(securityContext, endpoint.ID, resourceControl); err != nil {
+			return nil, httperror.InternalServerError("Unable to verify user authorizations to validate stack access", err)
+		} else if !access {
+			return nil, httperror.Forbidden("Access denied to resource", httperrors.ErrResourceAccessDenied)
+		}
+	}
+
+	if canManage, err := handler.userCanManageStacks(securityContext, endpoint); err != nil {
+		return nil, httperror.InternalServerError("Unable to verify user authorizations to validate stack management", err)
+	} else if !canManage {
+		errMsg := "stack management is disabled for non-admin users"
+
+		return nil, httperror.Forbidden(errMsg, errors.New(errMsg))
+	}
+
+	return endpoint, nil
+}
+
 // if stack management is disabled for non admins and the user isn't an admin, then return false. Otherwise return true
 func (handler *Handler) userCanManageStacks(securityContext *security.RestrictedRequestContext, endpoint *portainer.Endpoint) (bool, error) {
 	// When the endpoint is deleted, stacks that the deleted endpoint created will be tagged as an orphan stack
@@ -215,3 +307,12 @@ func (handler *Handler) checkUniqueWebhookID(webhookID string) (bool, error) {
 	}
 	return false, err
 }
+
+// startAutoupdate schedules the polling of the git repository or of the OCI artifact of a stack
+func (handler *Handler) startAutoupdate(stack *portainer.Stack) (string, *httperror.HandlerError) {
+	if stack.OCIConfig != nil {
+		return deployments.StartOCIAutoupdate(stack.ID, stack.AutoUpdate.Interval, handler.Scheduler, handler.StackDeployer, handler.DataStore, handler.OCIArtifactService)
+	}
+
+	return deployments.StartAutoupdate(stack.ID, stack.AutoUpdate.Interval, handler.Scheduler, handler.StackDeployer, handler.DataStore, handler.GitService)
+}
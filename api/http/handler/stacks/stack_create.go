@@ -7,6 +7,7 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/quotas"
 	"github.com/portainer/portainer/api/stacks/stackutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
@@ -62,6 +63,12 @@ func (handler *Handler) stackCreate(w http.ResponseWriter, r *http.Request) *htt
 		return httperror.InternalServerError("Unable to retrieve user details from authentication token", err)
 	}
 
+	if err := handler.QuotaService.Check(r.Context(), endpoint, tokenData.ID, quotas.Usage{Stacks: 1}); quotas.IsViolation(err) {
+		return httperror.Forbidden("Stack creation is not allowed by the quotas of the environment", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to verify the quotas of the environment", err)
+	}
+
 	switch stackType {
 	case "swarm":
 		return handler.createSwarmStack(w, r, method, endpoint, tokenData.ID)
@@ -82,9 +89,11 @@ func (handler *Handler) createComposeStack(w http.ResponseWriter, r *http.Reques
 		return handler.createComposeStackFromGitRepository(w, r, endpoint, userID)
 	case "file":
 		return handler.createComposeStackFromFileUpload(w, r, endpoint, userID)
+	case "oci":
+		return handler.createComposeStackFromOCIArtifact(w, r, endpoint, userID)
 	}
 
-	return httperror.BadRequest("Invalid value for query parameter: method. Value must be one of: string, repository or file", errors.New(request.ErrInvalidQueryParameter))
+	return httperror.BadRequest("Invalid value for query parameter: method. Value must be one of: string, repository, file or oci", errors.New(request.ErrInvalidQueryParameter))
 }
 
 func (handler *Handler) createSwarmStack(w http.ResponseWriter, r *http.Request, method string, endpoint *portainer.Endpoint, userID portainer.UserID) *httperror.HandlerError {
@@ -134,11 +143,6 @@ func (handler *Handler) decorateStackResponse(w http.ResponseWriter, stack *port
 
 	stack.ResourceControl = resourceControl
 
-	if stack.GitConfig != nil && stack.GitConfig.Authentication != nil && stack.GitConfig.Authentication.Password != "" {
-		// sanitize password in the http response to minimise possible security leaks
-		stack.GitConfig.Authentication.Password = ""
-	}
-
 	return response.JSON(w, stack)
 }
 
@@ -125,6 +125,8 @@ func (handler *Handler) stackDelete(w http.ResponseWriter, r *http.Request) *htt
 		return httperror.InternalServerError("Unable to remove the stack from the database", err)
 	}
 
+	deployments.DeleteGitOpsStatus(handler.DataStore, stack.ID)
+
 	if resourceControl != nil {
 		if err := handler.DataStore.ResourceControl().Delete(resourceControl.ID); err != nil {
 			return httperror.InternalServerError("Unable to remove the associated resource control from the database", err)
@@ -135,6 +137,12 @@ func (handler *Handler) stackDelete(w http.ResponseWriter, r *http.Request) *htt
 		log.Warn().Err(err).Msg("Unable to remove stack files from disk")
 	}
 
+	if stack.EnvFilePath != "" {
+		if err := handler.FileService.RemoveStackEnvFile(strconv.Itoa(int(stack.ID))); err != nil {
+			log.Warn().Err(err).Msg("Unable to remove the stack env file from disk")
+		}
+	}
+
 	return response.Empty(w)
 }
 
@@ -340,6 +348,8 @@ func (handler *Handler) stackDeleteKubernetesByName(w http.ResponseWriter, r *ht
 			continue
 		}
 
+		deployments.DeleteGitOpsStatus(handler.DataStore, stack.ID)
+
 		if err := handler.FileService.RemoveDirectory(stack.ProjectPath); err != nil {
 			errors = append(errors, err)
 			log.Warn().Err(err).Msg("Unable to remove stack files from disk")
@@ -0,0 +1,126 @@
+package stacks
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/stacks/stackutils"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type stackDependenciesUpdatePayload struct {
+	// Stacks deployed before the stack when they are deployed together, an empty list removes the dependencies
+	DependsOn []portainer.StackID `example:"1"`
+}
+
+func (payload *stackDependenciesUpdatePayload) Validate(r *http.Request) error {
+	return nil
+}
+
+// @id StackDependenciesUpdate
+// @summary Update the dependencies of a stack
+// @description Set the stacks deployed before this stack when they are deployed together through /stacks/deploy_group,
+// @description for example the database stack of an application stack. The dependencies cannot contain a cycle.
+// @description **Access policy**: authenticated
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Stack identifier"
+// @param body body stackDependenciesUpdatePayload true "Stack dependencies"
+// @success 200 {object} portainer.Stack "Success"
+// @failure 400 "Invalid request, unknown dependency or dependency cycle"
+// @failure 403 "Permission denied"
+// @failure 404 "Stack not found"
+// @failure 500 "Server error"
+// @router /stacks/{id}/dependencies [put]
+func (handler *Handler) stackDependenciesUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid stack identifier route variable", err)
+	}
+
+	var payload stackDependenciesUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve info from request context", err)
+	}
+
+	stack, err := handler.DataStore.Stack().Read(portainer.StackID(stackID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a stack with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a stack with the specified identifier inside the database", err)
+	}
+
+	if _, handlerErr := handler.authorizeStackManagement(r, securityContext, stack); handlerErr != nil {
+		return handlerErr
+	}
+
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		stack, err = tx.Stack().Read(stack.ID)
+		if err != nil {
+			return httperror.InternalServerError("Unable to find a stack with the specified identifier inside the database", err)
+		}
+
+		if err := validateStackDependencies(tx, stack.ID, payload.DependsOn); err != nil {
+			return err
+		}
+
+		stack.DependsOn = payload.DependsOn
+		if len(stack.DependsOn) == 0 {
+			stack.DependsOn = nil
+		}
+
+		if err := tx.Stack().Update(stack.ID, stack); err != nil {
+			return httperror.InternalServerError("Unable to persist the stack changes inside the database", err)
+		}
+
+		return nil
+	})
+
+	return txResponse(w, stack, err)
+}
+
+// validateStackDependencies ensures that the dependencies of a stack exist and do not lead back to the stack
+func validateStackDependencies(tx dataservices.DataStoreTx, stackID portainer.StackID, dependsOn []portainer.StackID) error {
+	for _, dependencyID := range dependsOn {
+		if _, err := tx.Stack().Read(dependencyID); tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find a dependency of the stack inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a dependency of the stack inside the database", err)
+		}
+	}
+
+	_, err := stackutils.DeployOrder([]portainer.StackID{stackID}, func(id portainer.StackID) ([]portainer.StackID, error) {
+		if id == stackID {
+			return dependsOn, nil
+		}
+
+		// The dependencies removed since they were set are ignored
+		stack, err := tx.Stack().Read(id)
+		if tx.IsErrObjectNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		return stack.DependsOn, nil
+	})
+	if errors.Is(err, stackutils.ErrDependencyCycle) {
+		return httperror.BadRequest("Invalid stack dependencies", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the dependencies of the stack", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package stacks
+
+import (
+	"net/http"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/datastore"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStackDependencies(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Stack().Create(&portainer.Stack{ID: 1, Name: "database"}))
+	require.NoError(t, store.Stack().Create(&portainer.Stack{ID: 2, Name: "cache", DependsOn: []portainer.StackID{1}}))
+	require.NoError(t, store.Stack().Create(&portainer.Stack{ID: 3, Name: "app"}))
+
+	validate := func(stackID portainer.StackID, dependsOn ...portainer.StackID) *httperror.HandlerError {
+		var handlerErr *httperror.HandlerError
+
+		err := store.ViewTx(func(tx dataservices.DataStoreTx) error {
+			if err := validateStackDependencies(tx, stackID, dependsOn); err != nil {
+				handlerErr = err.(*httperror.HandlerError)
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		return handlerErr
+	}
+
+	assert.Nil(t, validate(3, 2, 1))
+	assert.Nil(t, validate(3))
+
+	// Unknown dependency
+	handlerErr := validate(3, 42)
+	require.NotNil(t, handlerErr)
+	assert.Equal(t, http.StatusBadRequest, handlerErr.StatusCode)
+
+	// The database cannot depend on the cache which depends on the database
+	handlerErr = validate(1, 2)
+	require.NotNil(t, handlerErr)
+	assert.Equal(t, http.StatusBadRequest, handlerErr.StatusCode)
+
+	handlerErr = validate(3, 3)
+	require.NotNil(t, handlerErr)
+	assert.Equal(t, http.StatusBadRequest, handlerErr.StatusCode)
+}
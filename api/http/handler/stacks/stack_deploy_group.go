@@ -0,0 +1,244 @@
+package stacks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/registryutils/access"
+	"github.com/portainer/portainer/api/stacks/stackutils"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/rs/zerolog/log"
+)
+
+// stackDeployGroupStatus represents the outcome of the deployment of a stack of a deploy group
+type stackDeployGroupStatus string
+
+const (
+	stackDeployGroupDeployed stackDeployGroupStatus = "deployed"
+	stackDeployGroupFailed   stackDeployGroupStatus = "failed"
+	// The stack is not deployed because one of its dependencies was not deployed
+	stackDeployGroupSkipped stackDeployGroupStatus = "skipped"
+)
+
+type stackDeployGroupPayload struct {
+	// Stacks to deploy, their dependencies are deployed as well
+	StackIDs []portainer.StackID `validate:"required" example:"3"`
+	// Force a pulling to current image with the original tag though the image is already the latest
+	PullImage bool `example:"false"`
+	// Wait for the services of each stack to become healthy before deploying the stacks depending on it
+	HealthGate *portainer.StackHealthGate
+}
+
+func (payload *stackDeployGroupPayload) Validate(r *http.Request) error {
+	if len(payload.StackIDs) == 0 {
+		return errors.New("invalid stack identifiers. At least one stack is required")
+	}
+
+	if payload.HealthGate != nil && payload.HealthGate.Rollback {
+		return errors.New("the rollback is not available for a deploy group")
+	}
+
+	return validateHealthGate(payload.HealthGate)
+}
+
+type stackDeployGroupResult struct {
+	StackID portainer.StackID `json:"StackId" example:"3"`
+	Name    string            `json:"Name" example:"app"`
+	// Outcome of the deployment, deployed, failed or skipped
+	Status stackDeployGroupStatus `json:"Status" example:"deployed"`
+	// Reason of the failure or why the stack was skipped
+	Error  string                       `json:"Error,omitempty"`
+	Health *portainer.StackHealthReport `json:"Health,omitempty"`
+}
+
+type stackDeployGroupResponse struct {
+	// Outcome of each stack, in deployment order
+	Results []stackDeployGroupResult `json:"Results"`
+	// Whether at least one stack failed or was skipped
+	Failed bool `json:"Failed" example:"false"`
+}
+
+// @id StackDeployGroup
+// @summary Deploy stacks in dependency order
+// @description Redeploy the given stacks along with the stacks they depend on, every stack being deployed after its
+// @description dependencies. When the deployment of a stack fails, the stacks depending on it are skipped while the
+// @description other stacks are still deployed. With a health gate, a stack counts as deployed once its services are healthy.
+// @description **Access policy**: authenticated
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body stackDeployGroupPayload true "Stacks to deploy"
+// @success 200 {object} stackDeployGroupResponse "Success"
+// @failure 400 "Invalid request or dependency cycle"
+// @failure 404 "Stack not found"
+// @failure 422 {object} stackDeployGroupResponse "At least one stack failed or was skipped"
+// @failure 500 "Server error"
+// @router /stacks/deploy_group [post]
+func (handler *Handler) stackDeployGroup(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload stackDeployGroupPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve info from request context", err)
+	}
+
+	stacks := make(map[portainer.StackID]*portainer.Stack)
+	for _, stackID := range payload.StackIDs {
+		stack, err := handler.DataStore.Stack().Read(stackID)
+		if handler.DataStore.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a stack with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a stack with the specified identifier inside the database", err)
+		}
+
+		stacks[stack.ID] = stack
+	}
+
+	order, err := stackutils.DeployOrder(payload.StackIDs, func(stackID portainer.StackID) ([]portainer.StackID, error) {
+		stack, ok := stacks[stackID]
+		if !ok {
+			var err error
+			stack, err = handler.DataStore.Stack().Read(stackID)
+			if err != nil {
+				return nil, err
+			}
+
+			stacks[stackID] = stack
+		}
+
+		// The dependencies removed since they were set are ignored
+		dependencies := make([]portainer.StackID, 0, len(stack.DependsOn))
+		for _, dependencyID := range stack.DependsOn {
+			if _, err := handler.DataStore.Stack().Read(dependencyID); err == nil {
+				dependencies = append(dependencies, dependencyID)
+			} else if !handler.DataStore.IsErrObjectNotFound(err) {
+				return nil, err
+			}
+		}
+
+		return dependencies, nil
+	})
+	if errors.Is(err, stackutils.ErrDependencyCycle) {
+		return httperror.BadRequest("Invalid stack dependencies", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the dependencies of the stacks", err)
+	}
+
+	user, err := handler.DataStore.User().Read(securityContext.UserID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to load user information from the database", err)
+	}
+
+	resp := stackDeployGroupResponse{Results: make([]stackDeployGroupResult, 0, len(order))}
+	statuses := make(map[portainer.StackID]stackDeployGroupStatus)
+
+	for _, stackID := range order {
+		stack := stacks[stackID]
+		result := stackDeployGroupResult{StackID: stack.ID, Name: stack.Name, Status: stackDeployGroupDeployed}
+
+		for _, dependencyID := range stack.DependsOn {
+			if status, ok := statuses[dependencyID]; ok && status != stackDeployGroupDeployed {
+				result.Status = stackDeployGroupSkipped
+				result.Error = fmt.Sprintf("the dependency %d was not deployed", dependencyID)
+
+				break
+			}
+		}
+
+		if result.Status == stackDeployGroupDeployed {
+			result.Health, err = handler.deployGroupStack(r, securityContext, user, stack, &payload)
+			if err != nil {
+				log.Warn().Int("stack_id", int(stack.ID)).Err(err).Msg("unable to deploy a stack of the deploy group")
+
+				result.Status = stackDeployGroupFailed
+				result.Error = err.Error()
+			}
+		}
+
+		statuses[stack.ID] = result.Status
+		resp.Failed = resp.Failed || result.Status != stackDeployGroupDeployed
+		resp.Results = append(resp.Results, result)
+	}
+
+	if resp.Failed {
+		return response.JSONWithStatus(w, resp, http.StatusUnprocessableEntity)
+	}
+
+	return response.JSON(w, resp)
+}
+
+// deployGroupStack redeploys a stack of a deploy group and waits for its services to become healthy when requested
+func (handler *Handler) deployGroupStack(r *http.Request, securityContext *security.RestrictedRequestContext, user *portainer.User, stack *portainer.Stack, payload *stackDeployGroupPayload) (*portainer.StackHealthReport, error) {
+	endpoint, handlerErr := handler.authorizeStackManagement(r, securityContext, stack)
+	if handlerErr != nil {
+		return nil, errors.New(handlerErr.Message)
+	}
+
+	if err := handler.redeployStack(stack, endpoint, user, securityContext, payload.PullImage); err != nil {
+		return nil, err
+	}
+
+	stack.UpdatedBy = user.Username
+	stack.UpdateDate = time.Now().Unix()
+	stack.Status = portainer.StackStatusActive
+
+	if err := handler.DataStore.Stack().Update(stack.ID, stack); err != nil {
+		return nil, fmt.Errorf("unable to persist the stack changes inside the database: %w", err)
+	}
+
+	health := handler.gateStackHealth(r.Context(), stack, endpoint, payload.HealthGate, nil)
+	if health != nil && !health.Healthy {
+		return health, errors.New("the services of the stack did not become healthy")
+	}
+
+	return health, nil
+}
+
+// redeployStack deploys a stack again from its current files
+func (handler *Handler) redeployStack(stack *portainer.Stack, endpoint *portainer.Endpoint, user *portainer.User, securityContext *security.RestrictedRequestContext, pullImage bool) error {
+	registries, err := handler.DataStore.Registry().ReadAll()
+	if err != nil {
+		return fmt.Errorf("unable to retrieve registries from the database: %w", err)
+	}
+
+	filteredRegistries, err := access.FilterDeploymentRegistries(handler.DataStore, registries, user, securityContext.UserMemberships, endpoint.ID)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the default registries of the teams of the user: %w", err)
+	}
+
+	switch stack.Type {
+	case portainer.DockerComposeStack:
+		stack.Name = handler.ComposeStackManager.NormalizeStackName(stack.Name)
+
+		if stackutils.IsRelativePathStack(stack) {
+			return handler.StackDeployer.DeployRemoteComposeStack(stack, endpoint, filteredRegistries, pullImage, false)
+		}
+
+		return handler.StackDeployer.DeployComposeStack(stack, endpoint, filteredRegistries, pullImage, false)
+	case portainer.DockerSwarmStack:
+		stack.Name = handler.SwarmStackManager.NormalizeStackName(stack.Name)
+		prune := stack.Option != nil && stack.Option.Prune
+
+		if stackutils.IsRelativePathStack(stack) {
+			return handler.StackDeployer.DeployRemoteSwarmStack(stack, endpoint, filteredRegistries, prune, pullImage)
+		}
+
+		return handler.StackDeployer.DeploySwarmStack(stack, endpoint, filteredRegistries, prune, pullImage)
+	case portainer.KubernetesStack:
+		return handler.StackDeployer.DeployKubernetesStack(stack, endpoint, user)
+	}
+
+	return fmt.Errorf("unsupported stack type: %v", stack.Type)
+}
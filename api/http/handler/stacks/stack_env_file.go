@@ -0,0 +1,169 @@
+package stacks
+
+import (
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/joho/godotenv"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type stackEnvFileResponse struct {
+	// Content of the .env file
+	Content string `example:"DB_HOST=db\nDB_PORT=5432"`
+}
+
+type stackEnvFileUpdatePayload struct {
+	// New content of the .env file
+	Content string `example:"DB_HOST=db\nDB_PORT=5432" validate:"required"`
+}
+
+func (payload *stackEnvFileUpdatePayload) Validate(r *http.Request) error {
+	if len(payload.Content) == 0 {
+		return errors.New("Invalid env file content")
+	}
+
+	if _, err := godotenv.Parse(strings.NewReader(payload.Content)); err != nil {
+		return errors.Wrap(err, "Invalid env file content")
+	}
+
+	return nil
+}
+
+// @id StackEnvFileInspect
+// @summary Retrieve the .env file of a stack
+// @description Retrieve the content of the .env file uploaded for a Compose stack.
+// @description **Access policy**: restricted
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Stack identifier"
+// @success 200 {object} stackEnvFileResponse "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Stack or env file not found"
+// @failure 500 "Server error"
+// @router /stacks/{id}/env [get]
+func (handler *Handler) stackEnvFileInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stack, _, httpErr := handler.retrieveStackForEnvFileOperation(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if stack.EnvFilePath == "" {
+		return httperror.NotFound("The stack does not have an env file", errors.New("stack env file not found"))
+	}
+
+	content, err := handler.FileService.GetFileContent(filepath.Dir(stack.EnvFilePath), filepath.Base(stack.EnvFilePath))
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the stack env file from disk", err)
+	}
+
+	return response.JSON(w, stackEnvFileResponse{Content: string(content)})
+}
+
+// @id StackEnvFileUpdate
+// @summary Create or update the .env file of a stack
+// @description Create or replace the .env file of a Compose stack. It is merged with the environment variables of the stack
+// @description the next time the stack is deployed: the variables of the stack override the ones of the .env file, which
+// @description override the ones of the default .env file next to the compose file.
+// @description **Access policy**: restricted
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Stack identifier"
+// @param body body stackEnvFileUpdatePayload true "Env file content"
+// @success 200 {object} portainer.Stack "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Stack not found"
+// @failure 500 "Server error"
+// @router /stacks/{id}/env [put]
+func (handler *Handler) stackEnvFileUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload stackEnvFileUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	stack, userID, httpErr := handler.retrieveStackForEnvFileOperation(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	envFilePath, err := handler.FileService.StoreStackEnvFileFromBytes(strconv.Itoa(int(stack.ID)), []byte(payload.Content))
+	if err != nil {
+		return httperror.InternalServerError("Unable to persist the stack env file on disk", err)
+	}
+
+	stack.EnvFilePath = envFilePath
+
+	if httpErr := handler.persistStackFileChange(stack, userID); httpErr != nil {
+		return httpErr
+	}
+
+	return response.JSON(w, stack)
+}
+
+// @id StackEnvFileDelete
+// @summary Remove the .env file of a stack
+// @description Remove the .env file uploaded for a Compose stack. The change is used the next time the stack is deployed.
+// @description **Access policy**: restricted
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Stack identifier"
+// @success 200 {object} portainer.Stack "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Stack or env file not found"
+// @failure 500 "Server error"
+// @router /stacks/{id}/env [delete]
+func (handler *Handler) stackEnvFileDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stack, userID, httpErr := handler.retrieveStackForEnvFileOperation(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if stack.EnvFilePath == "" {
+		return httperror.NotFound("The stack does not have an env file", errors.New("stack env file not found"))
+	}
+
+	stack.EnvFilePath = ""
+
+	if httpErr := handler.persistStackFileChange(stack, userID); httpErr != nil {
+		return httpErr
+	}
+
+	if err := handler.FileService.RemoveStackEnvFile(strconv.Itoa(int(stack.ID))); err != nil {
+		log.Warn().Err(err).Msg("unable to remove the stack env file from disk")
+	}
+
+	return response.JSON(w, stack)
+}
+
+// retrieveStackForEnvFileOperation reads the stack targeted by the request and verifies that the user can manage it
+// and that it is a Compose stack. Unlike the stack files, the .env file of a git based stack can be managed.
+func (handler *Handler) retrieveStackForEnvFileOperation(r *http.Request) (*portainer.Stack, portainer.UserID, *httperror.HandlerError) {
+	stack, userID, httpErr := handler.retrieveStackForFileOperation(r, false)
+	if httpErr != nil {
+		return nil, 0, httpErr
+	}
+
+	if stack.Type != portainer.DockerComposeStack {
+		return nil, 0, httperror.BadRequest("Only Compose stacks support env files", errors.New("unsupported stack type"))
+	}
+
+	return stack, userID, nil
+}
@@ -0,0 +1,416 @@
+package stacks
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker"
+	"github.com/portainer/portainer/api/docker/consts"
+	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/quotas"
+	"github.com/portainer/portainer/api/stacks/stackutils"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type externalStack struct {
+	// Name of the stack, the Compose project or the Swarm stack namespace
+	Name string `json:"Name" example:"myStack"`
+	// Type of the stack, 1 for a Swarm stack and 2 for a Compose stack
+	Type portainer.StackType `json:"Type" example:"2"`
+	// Names of the services of the stack
+	Services []string `json:"Services" example:"web"`
+	// Whether at least one container or task of the stack is running
+	Running bool `json:"Running" example:"true"`
+}
+
+type stackExternalImportPayload struct {
+	// Name of the stack, the Compose project or the Swarm stack namespace
+	Name string `example:"myStack" validate:"required"`
+	// Type of the stack, 1 for a Swarm stack and 2 for a Compose stack
+	Type portainer.StackType `example:"2" validate:"required"`
+	// Content of the stack file, used as is when specified
+	StackFileContent string `example:"services:\n web:\n image: nginx"`
+	// Reassemble the stack file from the specs of the containers or of the services of the stack, when the
+	// original stack file is not available
+	ReassembleStackFile bool `example:"true"`
+	// A list of environment variables used during the next deployments of the stack
+	Env []portainer.Pair
+}
+
+func (payload *stackExternalImportPayload) Validate(r *http.Request) error {
+	if len(payload.Name) == 0 {
+		return errors.New("Invalid stack name")
+	}
+
+	if payload.Type != portainer.DockerSwarmStack && payload.Type != portainer.DockerComposeStack {
+		return errors.New("Invalid stack type. Value must be one of: 1 (Swarm stack) or 2 (Compose stack)")
+	}
+
+	if len(payload.StackFileContent) == 0 && !payload.ReassembleStackFile {
+		return errors.New("Either the stack file content or the reassembly of the stack file must be specified")
+	}
+
+	if len(payload.StackFileContent) > 0 && payload.ReassembleStackFile {
+		return errors.New("The stack file content cannot be specified when the stack file is reassembled")
+	}
+
+	return nil
+}
+
+// @id StackExternalList
+// @summary List the stacks deployed outside of Portainer
+// @description List the Compose projects and the Swarm stacks of a Docker environment(endpoint) which are not
+// @description managed by Portainer, they are detected with the com.docker.compose.project and the
+// @description com.docker.stack.namespace labels.
+// @description **Access policy**: administrator or environment administrator
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param endpointId query int true "Environment(Endpoint) identifier"
+// @success 200 {array} externalStack "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /stacks/external [get]
+func (handler *Handler) stackExternalList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpoint, httpErr := handler.retrieveExternalStacksEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, "", nil)
+	if err != nil {
+		return httperror.InternalServerError("Unable to create a Docker client for the environment", err)
+	}
+	defer cli.Close()
+
+	discovered, _, httpErr := handler.discoverExternalStacks(r.Context(), cli, endpoint)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	return response.JSON(w, discovered)
+}
+
+// @id StackExternalImport
+// @summary Import a stack deployed outside of Portainer
+// @description Create the stack record of a Compose project or of a Swarm stack deployed outside of Portainer so that
+// @description it can be managed by Portainer. The stack is not redeployed, its stack file is either specified or
+// @description reassembled from the specs of its containers or of its services.
+// @description **Access policy**: administrator or environment administrator
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param endpointId query int true "Environment(Endpoint) identifier"
+// @param body body stackExternalImportPayload true "Stack to import"
+// @success 200 {object} portainer.Stack "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Environment(Endpoint) or stack not found"
+// @failure 500 "Server error"
+// @router /stacks/external/import [post]
+func (handler *Handler) stackExternalImport(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload stackExternalImportPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	endpoint, httpErr := handler.retrieveExternalStacksEndpoint(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve user details from authentication token", err)
+	}
+
+	if err := handler.QuotaService.Check(r.Context(), endpoint, tokenData.ID, quotas.Usage{Stacks: 1}); quotas.IsViolation(err) {
+		return httperror.Forbidden("Stack creation is not allowed by the quotas of the environment", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to verify the quotas of the environment", err)
+	}
+
+	cli, err := handler.DockerClientFactory.CreateClient(endpoint, "", nil)
+	if err != nil {
+		return httperror.InternalServerError("Unable to create a Docker client for the environment", err)
+	}
+	defer cli.Close()
+
+	discovered, swarmID, httpErr := handler.discoverExternalStacks(r.Context(), cli, endpoint)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if !slices.ContainsFunc(discovered, func(s externalStack) bool {
+		return s.Name == payload.Name && s.Type == payload.Type
+	}) {
+		return httperror.NotFound("Unable to find a stack deployed outside of Portainer with the specified name", errors.New("stack not found"))
+	}
+
+	stackFileContent := []byte(payload.StackFileContent)
+	if payload.ReassembleStackFile {
+		if payload.Type == portainer.DockerSwarmStack {
+			stackFileContent, err = reassembleSwarmStackFile(r.Context(), cli, payload.Name)
+		} else {
+			stackFileContent, err = reassembleComposeFile(r.Context(), cli, payload.Name)
+		}
+
+		if err != nil {
+			return httperror.InternalServerError("Unable to reassemble the stack file", err)
+		}
+	}
+
+	user, err := handler.DataStore.User().Read(tokenData.ID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to load user information from the database", err)
+	}
+
+	stack := &portainer.Stack{
+		ID:           portainer.StackID(handler.DataStore.Stack().GetNextIdentifier()),
+		Name:         payload.Name,
+		Type:         payload.Type,
+		EndpointID:   endpoint.ID,
+		EntryPoint:   filesystem.ComposeFileDefaultName,
+		Env:          payload.Env,
+		Status:       portainer.StackStatusActive,
+		CreationDate: time.Now().Unix(),
+		CreatedBy:    user.Username,
+	}
+
+	if payload.Type == portainer.DockerSwarmStack {
+		stack.SwarmID = swarmID
+	}
+
+	stack.ProjectPath, err = handler.FileService.StoreStackFileFromBytes(strconv.Itoa(int(stack.ID)), stack.EntryPoint, stackFileContent)
+	if err != nil {
+		return httperror.InternalServerError("Unable to persist the stack file on disk", err)
+	}
+
+	if err := handler.DataStore.Stack().Create(stack); err != nil {
+		if err := handler.FileService.RemoveDirectory(stack.ProjectPath); err != nil {
+			log.Warn().Err(err).Int("stack_id", int(stack.ID)).Msg("unable to remove the stack files from disk")
+		}
+
+		return httperror.InternalServerError("Unable to persist the stack inside the database", err)
+	}
+
+	// The ownership of the stack may have been set before it was imported
+	resourceControl, err := handler.DataStore.ResourceControl().ResourceControlByResourceIDAndType(stackutils.ResourceControlID(stack.EndpointID, stack.Name), portainer.StackResourceControl)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve a resource control associated to the stack", err)
+	}
+
+	if resourceControl != nil {
+		stack.ResourceControl = resourceControl
+
+		return response.JSON(w, stack)
+	}
+
+	return handler.decorateStackResponse(w, stack, user.ID)
+}
+
+// retrieveExternalStacksEndpoint returns the Docker environment of the request, the stacks deployed outside of
+// Portainer are only visible to the administrators of the environment as they are not subject to resource controls
+func (handler *Handler) retrieveExternalStacksEndpoint(r *http.Request) (*portainer.Endpoint, *httperror.HandlerError) {
+	endpointID, err := request.RetrieveNumericQueryParameter(r, "endpointId", false)
+	if err != nil {
+		return nil, httperror.BadRequest("Invalid query parameter: endpointId", err)
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	if !endpointutils.IsDockerEndpoint(endpoint) {
+		return nil, httperror.BadRequest("The environment is not a Docker environment", errors.New("environment is not a docker environment"))
+	}
+
+	if err := handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint); err != nil {
+		return nil, httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve info from request context", err)
+	}
+
+	canCreate, err := handler.userCanCreateStack(securityContext, endpoint.ID)
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to verify user authorizations to import stacks", err)
+	} else if !canCreate {
+		errMsg := "Stack import is only allowed to the administrators of the environment"
+		return nil, httperror.Forbidden(errMsg, errors.New(errMsg))
+	}
+
+	return endpoint, nil
+}
+
+// discoverExternalStacks returns the stacks of the environment which are not managed by Portainer, along with the
+// identifier of the Swarm cluster when the environment is a Swarm manager
+func (handler *Handler) discoverExternalStacks(ctx context.Context, cli *client.Client, endpoint *portainer.Endpoint) ([]externalStack, string, *httperror.HandlerError) {
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, "", httperror.InternalServerError("Unable to retrieve Docker containers", err)
+	}
+
+	info, err := cli.Info(ctx)
+	if err != nil {
+		return nil, "", httperror.InternalServerError("Unable to retrieve Docker info", err)
+	}
+
+	var services []swarm.Service
+	swarmID := ""
+	if info.Swarm.ControlAvailable && info.Swarm.NodeID != "" {
+		swarmID = info.Swarm.Cluster.ID
+
+		services, err = cli.ServiceList(ctx, types.ServiceListOptions{Status: true})
+		if err != nil {
+			return nil, "", httperror.InternalServerError("Unable to retrieve Docker services", err)
+		}
+	}
+
+	stacks, err := handler.DataStore.Stack().ReadAll()
+	if err != nil {
+		return nil, "", httperror.InternalServerError("Unable to retrieve stacks from the database", err)
+	}
+
+	managed := map[string]bool{}
+	for _, stack := range stacks {
+		if stack.EndpointID == endpoint.ID {
+			managed[strings.ToLower(stack.Name)] = true
+		}
+	}
+
+	return externalStacks(managed, containers, services), swarmID, nil
+}
+
+// externalStacks groups the containers and the services which are part of a stack unknown to Portainer by stack,
+// the stacks hidden with the io.portainer.hideStack label are left out
+func externalStacks(managed map[string]bool, containers []types.Container, services []swarm.Service) []externalStack {
+	stacks := []externalStack{}
+
+	add := func(name string, stackType portainer.StackType, service string, running bool) {
+		idx := slices.IndexFunc(stacks, func(s externalStack) bool { return s.Name == name && s.Type == stackType })
+		if idx == -1 {
+			stacks = append(stacks, externalStack{Name: name, Type: stackType, Services: []string{}})
+			idx = len(stacks) - 1
+		}
+
+		if !slices.Contains(stacks[idx].Services, service) {
+			stacks[idx].Services = append(stacks[idx].Services, service)
+		}
+
+		stacks[idx].Running = stacks[idx].Running || running
+	}
+
+	for _, c := range containers {
+		name := c.Labels[consts.ComposeStackNameLabel]
+		if name == "" || managed[strings.ToLower(name)] || c.Labels[consts.HideStackLabel] != "" {
+			continue
+		}
+
+		add(name, portainer.DockerComposeStack, c.Labels["com.docker.compose.service"], c.State == "running")
+	}
+
+	for _, s := range services {
+		name := s.Spec.Labels[consts.SwarmStackNameLabel]
+		if name == "" || managed[strings.ToLower(name)] || s.Spec.Labels[consts.HideStackLabel] != "" {
+			continue
+		}
+
+		running := s.ServiceStatus != nil && s.ServiceStatus.RunningTasks > 0
+		add(name, portainer.DockerSwarmStack, strings.TrimPrefix(s.Spec.Name, name+"_"), running)
+	}
+
+	slices.SortFunc(stacks, func(a, b externalStack) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	for i := range stacks {
+		slices.Sort(stacks[i].Services)
+	}
+
+	return stacks
+}
+
+func reassembleComposeFile(ctx context.Context, cli *client.Client, project string) ([]byte, error) {
+	containers, err := cli.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", consts.ComposeStackNameLabel+"="+project)),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the containers of the stack")
+	}
+
+	specs := make([]types.ContainerJSON, 0, len(containers))
+	imageConfigs := map[string]*container.Config{}
+
+	for _, c := range containers {
+		spec, err := cli.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to inspect the container %s", c.ID)
+		}
+
+		specs = append(specs, spec)
+
+		if _, ok := imageConfigs[spec.Image]; ok {
+			continue
+		}
+
+		// The image may have been removed since the container was created, all the settings are kept then
+		image, _, err := cli.ImageInspectWithRaw(ctx, spec.Image)
+		if err != nil {
+			log.Debug().Err(err).Str("image", spec.Image).Msg("unable to inspect the image of the container")
+		}
+
+		imageConfigs[spec.Image] = image.Config
+	}
+
+	return docker.ReassembleComposeFile(project, specs, imageConfigs)
+}
+
+func reassembleSwarmStackFile(ctx context.Context, cli *client.Client, namespace string) ([]byte, error) {
+	services, err := cli.ServiceList(ctx, types.ServiceListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", consts.SwarmStackNameLabel+"="+namespace)),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the services of the stack")
+	}
+
+	networks, err := cli.NetworkList(ctx, types.NetworkListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the networks")
+	}
+
+	networkNames := make(map[string]string, len(networks))
+	for _, network := range networks {
+		networkNames[network.ID] = network.Name
+	}
+
+	return docker.ReassembleSwarmStackFile(namespace, services, networkNames)
+}
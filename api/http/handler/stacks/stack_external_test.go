@@ -0,0 +1,49 @@
+package stacks
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker/consts"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalStacks(t *testing.T) {
+	composeContainer := func(project, service, state string) types.Container {
+		return types.Container{
+			State:  state,
+			Labels: map[string]string{consts.ComposeStackNameLabel: project, "com.docker.compose.service": service},
+		}
+	}
+
+	containers := []types.Container{
+		composeContainer("shop", "web", "exited"),
+		composeContainer("shop", "db", "running"),
+		composeContainer("shop", "web", "exited"),
+		composeContainer("Managed", "app", "running"),
+		composeContainer("tools", "cron", "exited"),
+		{Labels: map[string]string{consts.ComposeStackNameLabel: "hidden", consts.HideStackLabel: "true"}},
+		{State: "running"},
+	}
+
+	services := []swarm.Service{
+		{
+			Spec:          swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "monitoring_grafana", Labels: map[string]string{consts.SwarmStackNameLabel: "monitoring"}}},
+			ServiceStatus: &swarm.ServiceStatus{RunningTasks: 1, DesiredTasks: 1},
+		},
+		{
+			Spec: swarm.ServiceSpec{Annotations: swarm.Annotations{Name: "standalone"}},
+		},
+	}
+
+	stacks := externalStacks(map[string]bool{"managed": true}, containers, services)
+
+	assert.Equal(t, []externalStack{
+		{Name: "monitoring", Type: portainer.DockerSwarmStack, Services: []string{"grafana"}, Running: true},
+		{Name: "shop", Type: portainer.DockerComposeStack, Services: []string{"db", "web"}, Running: true},
+		{Name: "tools", Type: portainer.DockerComposeStack, Services: []string{"cron"}, Running: false},
+	}, stacks)
+}
@@ -0,0 +1,361 @@
+package stacks
+
+import (
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/stacks/stackutils"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type stackFileEntry struct {
+	// Name of the file, relative to the stack project folder
+	Name string `example:"docker-compose.yml"`
+	// Whether the file is the stack entry point
+	EntryPoint bool `example:"true"`
+	// Content of the file
+	Content string `example:"version: 3\n services:\n web:\n image:nginx"`
+}
+
+type stackFileUpdatePayload struct {
+	// New content of the file
+	Content string `example:"version: 3\n services:\n web:\n image:nginx" validate:"required"`
+}
+
+func (payload *stackFileUpdatePayload) Validate(r *http.Request) error {
+	if len(payload.Content) == 0 {
+		return errors.New("Invalid stack file content")
+	}
+
+	return nil
+}
+
+// validateStackFileName ensures that a file name refers to a file at the root of the stack project folder
+func validateStackFileName(name string) error {
+	if len(name) == 0 || name == "." || name == ".." {
+		return errors.New("Invalid file name")
+	}
+
+	if strings.ContainsAny(name, `/\`) || filepath.Base(name) != name {
+		return errors.New("File name must not contain a path")
+	}
+
+	return nil
+}
+
+// @id StackFileList
+// @summary List the files of a stack
+// @description List the entry point and the additional files of a stack with their content.
+// @description Files are returned in the order they are passed to docker compose.
+// @description **Access policy**: restricted
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Stack identifier"
+// @success 200 {array} stackFileEntry "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Stack not found"
+// @failure 500 "Server error"
+// @router /stacks/{id}/files [get]
+func (handler *Handler) stackFileList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stack, _, httpErr := handler.retrieveStackForFileOperation(r, false)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	files := make([]stackFileEntry, 0, len(stack.AdditionalFiles)+1)
+	for i, name := range stackutils.GetStackFilePaths(stack, false) {
+		content, err := handler.FileService.GetFileContent(stack.ProjectPath, name)
+		if err != nil {
+			return httperror.InternalServerError("Unable to retrieve stack file from disk", err)
+		}
+
+		files = append(files, stackFileEntry{
+			Name:       name,
+			EntryPoint: i == 0,
+			Content:    string(content),
+		})
+	}
+
+	return response.JSON(w, files)
+}
+
+// @id StackFileUpload
+// @summary Upload an additional file to a stack
+// @description Upload a new additional file to a file based Docker stack. The file is appended to the list of
+// @description additional files and is used the next time the stack is deployed.
+// @description **Access policy**: restricted
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @accept multipart/form-data
+// @produce json
+// @param id path int true "Stack identifier"
+// @param file formData file true "File to upload"
+// @param Name formData string false "Name of the file inside the stack project folder. Defaults to the uploaded file name"
+// @success 200 {object} portainer.Stack "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Stack not found"
+// @failure 409 "A file with the same name already exists"
+// @failure 500 "Server error"
+// @router /stacks/{id}/files [post]
+func (handler *Handler) stackFileUpload(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	content, uploadedName, err := request.RetrieveMultiPartFormFile(r, "file")
+	if err != nil {
+		return httperror.BadRequest("Invalid stack file. Ensure that the file is uploaded correctly", err)
+	}
+
+	if len(content) == 0 {
+		return httperror.BadRequest("Invalid stack file content", errors.New("uploaded file is empty"))
+	}
+
+	name, _ := request.RetrieveMultiPartFormValue(r, "Name", true)
+	if name == "" {
+		name = uploadedName
+	}
+
+	if err := validateStackFileName(name); err != nil {
+		return httperror.BadRequest("Invalid file name", err)
+	}
+
+	stack, userID, httpErr := handler.retrieveStackForFileOperation(r, true)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if name == stack.EntryPoint || slices.Contains(stack.AdditionalFiles, name) {
+		return httperror.Conflict("A file with the same name already exists in the stack", errors.New("stack file already exists"))
+	}
+
+	stackFolder := strconv.Itoa(int(stack.ID))
+	if _, err := handler.FileService.StoreStackFileFromBytes(stackFolder, name, content); err != nil {
+		return httperror.InternalServerError("Unable to persist the stack file on disk", err)
+	}
+
+	stack.AdditionalFiles = append(stack.AdditionalFiles, name)
+
+	if httpErr := handler.persistStackFileChange(stack, userID); httpErr != nil {
+		if err := handler.FileService.RemoveStackFile(stackFolder, name); err != nil {
+			log.Warn().Err(err).Msg("unable to remove the uploaded stack file")
+		}
+
+		return httpErr
+	}
+
+	return response.JSON(w, stack)
+}
+
+// @id StackFileUpdate
+// @summary Update the content of a stack file
+// @description Update the content of the entry point or of an additional file of a file based Docker stack.
+// @description The change is used the next time the stack is deployed.
+// @description **Access policy**: restricted
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Stack identifier"
+// @param file path string true "File name"
+// @param body body stackFileUpdatePayload true "File content"
+// @success 200 {object} portainer.Stack "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Stack or file not found"
+// @failure 500 "Server error"
+// @router /stacks/{id}/files/{file} [put]
+func (handler *Handler) stackFileUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	name, err := request.RetrieveRouteVariableValue(r, "file")
+	if err != nil {
+		return httperror.BadRequest("Invalid file route variable", err)
+	}
+
+	var payload stackFileUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	stack, userID, httpErr := handler.retrieveStackForFileOperation(r, true)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if name != stack.EntryPoint && !slices.Contains(stack.AdditionalFiles, name) {
+		return httperror.NotFound("Unable to find the file in the stack", errors.New("stack file not found"))
+	}
+
+	stackFolder := strconv.Itoa(int(stack.ID))
+	if _, err := handler.FileService.UpdateStoreStackFileFromBytes(stackFolder, name, []byte(payload.Content)); err != nil {
+		if rollbackErr := handler.FileService.RollbackStackFile(stackFolder, name); rollbackErr != nil {
+			log.Warn().Err(rollbackErr).Msg("rollback stack file error")
+		}
+
+		return httperror.InternalServerError("Unable to persist the stack file on disk", err)
+	}
+
+	if httpErr := handler.persistStackFileChange(stack, userID); httpErr != nil {
+		if rollbackErr := handler.FileService.RollbackStackFile(stackFolder, name); rollbackErr != nil {
+			log.Warn().Err(rollbackErr).Msg("rollback stack file error")
+		}
+
+		return httpErr
+	}
+
+	handler.FileService.RemoveStackFileBackup(stackFolder, name)
+
+	return response.JSON(w, stack)
+}
+
+// @id StackFileDelete
+// @summary Remove an additional file from a stack
+// @description Remove an additional file from a file based Docker stack. The entry point cannot be removed.
+// @description **Access policy**: restricted
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Stack identifier"
+// @param file path string true "File name"
+// @success 200 {object} portainer.Stack "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Stack or file not found"
+// @failure 500 "Server error"
+// @router /stacks/{id}/files/{file} [delete]
+func (handler *Handler) stackFileDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	name, err := request.RetrieveRouteVariableValue(r, "file")
+	if err != nil {
+		return httperror.BadRequest("Invalid file route variable", err)
+	}
+
+	stack, userID, httpErr := handler.retrieveStackForFileOperation(r, true)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if name == stack.EntryPoint {
+		return httperror.BadRequest("The stack entry point cannot be removed", errors.New("cannot remove the stack entry point"))
+	}
+
+	index := slices.Index(stack.AdditionalFiles, name)
+	if index == -1 {
+		return httperror.NotFound("Unable to find the file in the stack", errors.New("stack file not found"))
+	}
+
+	stack.AdditionalFiles = slices.Delete(stack.AdditionalFiles, index, index+1)
+
+	if httpErr := handler.persistStackFileChange(stack, userID); httpErr != nil {
+		return httpErr
+	}
+
+	if err := handler.FileService.RemoveStackFile(strconv.Itoa(int(stack.ID)), name); err != nil {
+		log.Warn().Err(err).Str("file", name).Msg("unable to remove the stack file from disk")
+	}
+
+	return response.JSON(w, stack)
+}
+
+// retrieveStackForFileOperation reads the stack targeted by the request and verifies that the user can manage it.
+// When write is true, the stack must also be a file based Docker stack.
+func (handler *Handler) retrieveStackForFileOperation(r *http.Request, write bool) (*portainer.Stack, portainer.UserID, *httperror.HandlerError) {
+	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, 0, httperror.BadRequest("Invalid stack identifier route variable", err)
+	}
+
+	stack, err := handler.DataStore.Stack().Read(portainer.StackID(stackID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return nil, 0, httperror.NotFound("Unable to find a stack with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, 0, httperror.InternalServerError("Unable to find a stack with the specified identifier inside the database", err)
+	}
+
+	if write {
+		if stack.Type != portainer.DockerSwarmStack && stack.Type != portainer.DockerComposeStack {
+			return nil, 0, httperror.BadRequest("Only Docker stacks support file management", errors.New("unsupported stack type"))
+		}
+
+		if stackutils.IsGitStack(stack) {
+			return nil, 0, httperror.BadRequest("The files of a git based stack cannot be modified", errors.New("stack is git based"))
+		}
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return nil, 0, httperror.InternalServerError("Unable to retrieve info from request context", err)
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(stack.EndpointID)
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		if write || !securityContext.IsAdmin {
+			return nil, 0, httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+		}
+	} else if err != nil {
+		return nil, 0, httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	canManage, err := handler.userCanManageStacks(securityContext, endpoint)
+	if err != nil {
+		return nil, 0, httperror.InternalServerError("Unable to verify user authorizations to validate stack management", err)
+	}
+	if !canManage {
+		errMsg := "Stack management is disabled for non-admin users"
+		return nil, 0, httperror.Forbidden(errMsg, errors.New(errMsg))
+	}
+
+	if endpoint == nil {
+		return stack, securityContext.UserID, nil
+	}
+
+	if err := handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint); err != nil {
+		return nil, 0, httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	if stack.Type == portainer.DockerSwarmStack || stack.Type == portainer.DockerComposeStack {
+		resourceControl, err := handler.DataStore.ResourceControl().ResourceControlByResourceIDAndType(stackutils.ResourceControlID(stack.EndpointID, stack.Name), portainer.StackResourceControl)
+		if err != nil {
+			return nil, 0, httperror.InternalServerError("Unable to retrieve a resource control associated to the stack", err)
+		}
+
+		access, err := handler.userCanAccessStack(securityContext, endpoint.ID, resourceControl)
+		if err != nil {
+			return nil, 0, httperror.InternalServerError("Unable to verify user authorizations to validate stack access", err)
+		}
+		if !access {
+			return nil, 0, httperror.Forbidden("Access denied to resource", httperrors.ErrResourceAccessDenied)
+		}
+	}
+
+	return stack, securityContext.UserID, nil
+}
+
+func (handler *Handler) persistStackFileChange(stack *portainer.Stack, userID portainer.UserID) *httperror.HandlerError {
+	user, err := handler.DataStore.User().Read(userID)
+	if err != nil {
+		return httperror.BadRequest("Cannot find context user", errors.Wrap(err, "failed to fetch the user"))
+	}
+
+	stack.UpdatedBy = user.Username
+	stack.UpdateDate = time.Now().Unix()
+
+	if err := handler.DataStore.Stack().Update(stack.ID, stack); err != nil {
+		return httperror.InternalServerError("Unable to persist the stack changes inside the database", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package stacks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateStackFileName(t *testing.T) {
+	valid := []string{"docker-compose.yml", "override.yaml", ".env.yml"}
+	for _, name := range valid {
+		assert.NoError(t, validateStackFileName(name), name)
+	}
+
+	invalid := []string{"", ".", "..", "../docker-compose.yml", "sub/override.yml", `sub\override.yml`, "/etc/passwd"}
+	for _, name := range invalid {
+		assert.Error(t, validateStackFileName(name), name)
+	}
+}
@@ -0,0 +1,194 @@
+package stacks
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/stacks/deployments"
+	"github.com/portainer/portainer/api/stacks/stackutils"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id StackGitOpsStatus
+// @summary Retrieve the GitOps status of a stack
+// @description Retrieve the last checked and applied commits of a git stack, whether they differ and the error of the last automatic update.
+// @description **Access policy**: authenticated
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Stack identifier"
+// @success 200 {object} portainer.StackGitOpsStatus "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Not found"
+// @failure 500 "Server error"
+// @router /stacks/{id}/gitops/status [get]
+func (handler *Handler) stackGitOpsStatus(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stack, httpErr := handler.retrieveGitOpsStack(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	return handler.writeGitOpsStatus(w, stack)
+}
+
+// @id StackGitOpsResync
+// @summary Resync a stack with its repository
+// @description Pull and redeploy a git stack from the latest commit of its repository, even when it did not change or its automatic updates are paused.
+// @description **Access policy**: authenticated
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Stack identifier"
+// @success 200 {object} portainer.StackGitOpsStatus "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Not found"
+// @failure 500 "Server error"
+// @router /stacks/{id}/gitops/resync [post]
+func (handler *Handler) stackGitOpsResync(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	stack, httpErr := handler.retrieveGitOpsStack(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if err := deployments.ForceResync(stack.ID, handler.StackDeployer, handler.DataStore, handler.GitService); err != nil {
+		return httperror.InternalServerError("Unable to resync the stack", err)
+	}
+
+	return handler.writeGitOpsStatus(w, stack)
+}
+
+// @id StackGitOpsPause
+// @summary Pause the automatic updates of a stack
+// @description The polling and the webhook of the stack no longer redeploy it until its automatic updates are resumed.
+// @description **Access policy**: authenticated
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Stack identifier"
+// @success 200 {object} portainer.StackGitOpsStatus "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Not found"
+// @failure 500 "Server error"
+// @router /stacks/{id}/gitops/pause [post]
+func (handler *Handler) stackGitOpsPause(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.setGitOpsPaused(w, r, true)
+}
+
+// @id StackGitOpsResume
+// @summary Resume the automatic updates of a stack
+// @description **Access policy**: authenticated
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Stack identifier"
+// @success 200 {object} portainer.StackGitOpsStatus "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Not found"
+// @failure 500 "Server error"
+// @router /stacks/{id}/gitops/resume [post]
+func (handler *Handler) stackGitOpsResume(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	return handler.setGitOpsPaused(w, r, false)
+}
+
+func (handler *Handler) setGitOpsPaused(w http.ResponseWriter, r *http.Request, paused bool) *httperror.HandlerError {
+	stack, httpErr := handler.retrieveGitOpsStack(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	if stack.AutoUpdate == nil {
+		return httperror.BadRequest("The automatic updates of the stack are not enabled", errors.New("the automatic updates of the stack are not enabled"))
+	}
+
+	if err := deployments.SetGitOpsPaused(handler.DataStore, stack.ID, paused); err != nil {
+		return httperror.InternalServerError("Unable to persist the GitOps status of the stack inside the database", err)
+	}
+
+	return handler.writeGitOpsStatus(w, stack)
+}
+
+func (handler *Handler) writeGitOpsStatus(w http.ResponseWriter, stack *portainer.Stack) *httperror.HandlerError {
+	// The stack is read again since a resync updates its commit
+	stack, err := handler.DataStore.Stack().Read(stack.ID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to find a stack with the specified identifier inside the database", err)
+	}
+
+	status, err := deployments.GitOpsStatus(handler.DataStore, stack)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the GitOps status of the stack from the database", err)
+	}
+
+	return response.JSON(w, status)
+}
+
+// retrieveGitOpsStack retrieves the git stack of the request and makes sure the user can manage it
+func (handler *Handler) retrieveGitOpsStack(r *http.Request) (*portainer.Stack, *httperror.HandlerError) {
+	stackID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return nil, httperror.BadRequest("Invalid stack identifier route variable", err)
+	}
+
+	stack, err := handler.DataStore.Stack().Read(portainer.StackID(stackID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find a stack with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find a stack with the specified identifier inside the database", err)
+	}
+
+	if stack.GitConfig == nil {
+		return nil, httperror.BadRequest("Stack is not created from git", errors.New("stack is not created from git"))
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(stack.EndpointID)
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find the environment associated to the stack inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find the environment associated to the stack inside the database", err)
+	}
+
+	if err := handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint); err != nil {
+		return nil, httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve info from request context", err)
+	}
+
+	// Only check resource control when it is a DockerSwarmStack or a DockerComposeStack
+	if stack.Type == portainer.DockerSwarmStack || stack.Type == portainer.DockerComposeStack {
+		resourceControl, err := handler.DataStore.ResourceControl().ResourceControlByResourceIDAndType(stackutils.ResourceControlID(stack.EndpointID, stack.Name), portainer.StackResourceControl)
+		if err != nil {
+			return nil, httperror.InternalServerError("Unable to retrieve a resource control associated to the stack", err)
+		}
+
+		if access, err := handler.userCanAccessStack(securityContext, endpoint.ID, resourceControl); err != nil {
+			return nil, httperror.InternalServerError("Unable to verify user authorizations to validate stack access", err)
+		} else if !access {
+			return nil, httperror.Forbidden("Access denied to resource", httperrors.ErrResourceAccessDenied)
+		}
+	}
+
+	if canManage, err := handler.userCanManageStacks(securityContext, endpoint); err != nil {
+		return nil, httperror.InternalServerError("Unable to verify user authorizations to validate stack management", err)
+	} else if !canManage {
+		errMsg := "Stack management is disabled for non-admin users"
+		return nil, httperror.Forbidden(errMsg, errors.New(errMsg))
+	}
+
+	return stack, nil
+}
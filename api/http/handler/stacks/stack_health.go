@@ -0,0 +1,125 @@
+package stacks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/pendingactions/handlers"
+	"github.com/portainer/portainer/api/stacks/deployments"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxHealthGateTimeout is the longest time a request can wait for the services of a stack to become healthy
+const maxHealthGateTimeout = 30 * time.Minute
+
+// stackDeployResponse is the response of the operations deploying a stack, it includes the
+// health of the services of the stack when the deployment was gated on it
+type stackDeployResponse struct {
+	*portainer.Stack
+	Health *portainer.StackHealthReport `json:"Health,omitempty"`
+	// Deployment queued until the environment is reachable again
+	Queued *handlers.QueuedStackDeployment `json:"Queued,omitempty"`
+}
+
+func validateHealthGate(gate *portainer.StackHealthGate) error {
+	if gate == nil {
+		return nil
+	}
+
+	if gate.Timeout <= 0 || time.Duration(gate.Timeout)*time.Second > maxHealthGateTimeout {
+		return fmt.Errorf("invalid health gate timeout, it must be between 1 and %d seconds", int(maxHealthGateTimeout.Seconds()))
+	}
+
+	return nil
+}
+
+// gateStackHealth waits for the services of a freshly deployed stack to become healthy. When they never do, the
+// deployment is marked as failed in the history and the rollback function is called if the gate requests it.
+// It returns nil when there is no gate.
+func (handler *Handler) gateStackHealth(ctx context.Context, stack *portainer.Stack, endpoint *portainer.Endpoint, gate *portainer.StackHealthGate, rollback func() error) *portainer.StackHealthReport {
+	if gate == nil {
+		return nil
+	}
+
+	check, err := handler.stackHealthChecker(stack, endpoint)
+	if err != nil {
+		return &portainer.StackHealthReport{
+			Message:  err.Error(),
+			Services: []portainer.StackServiceHealth{},
+		}
+	}
+
+	report := deployments.WaitForHealthy(ctx, time.Duration(gate.Timeout)*time.Second, check)
+	if report.Healthy {
+		return report
+	}
+
+	if handler.DeploymentHistoryService != nil {
+		handler.DeploymentHistoryService.FailLatestStackDeployment(stack.ID, report.Message)
+	}
+
+	if !gate.Rollback || rollback == nil {
+		return report
+	}
+
+	if err := rollback(); err != nil {
+		log.Warn().Err(err).Int("stack_id", int(stack.ID)).Msg("unable to roll back the unhealthy stack")
+
+		report.Message = fmt.Sprintf("%s, the rollback failed: %s", report.Message, err)
+
+		return report
+	}
+
+	report.RolledBack = true
+
+	return report
+}
+
+func (handler *Handler) stackHealthChecker(stack *portainer.Stack, endpoint *portainer.Endpoint) (deployments.HealthChecker, error) {
+	switch stack.Type {
+	case portainer.DockerComposeStack, portainer.DockerSwarmStack:
+		cli, err := handler.DockerClientFactory.CreateClient(endpoint, "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create a Docker client: %w", err)
+		}
+
+		if stack.Type == portainer.DockerSwarmStack {
+			return deployments.SwarmHealthChecker(cli, stack.Name), nil
+		}
+
+		return deployments.ComposeHealthChecker(cli, stack.Name), nil
+	case portainer.KubernetesStack:
+		cli, err := handler.KubernetesClientFactory.GetPrivilegedKubeClient(endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create a Kubernetes client: %w", err)
+		}
+
+		return func(ctx context.Context) ([]portainer.StackServiceHealth, error) {
+			return cli.GetStackRolloutStatus(ctx, int(stack.ID))
+		}, nil
+	}
+
+	return nil, errors.New("unsupported stack type")
+}
+
+// writeStackDeployResponse writes the stack with the result of its health gate, an unhealthy stack
+// is reported with the 422 status code
+func writeStackDeployResponse(w http.ResponseWriter, stack *portainer.Stack, health *portainer.StackHealthReport) *httperror.HandlerError {
+	if health == nil {
+		return response.JSON(w, stack)
+	}
+
+	status := http.StatusOK
+	if !health.Healthy {
+		status = http.StatusUnprocessableEntity
+	}
+
+	return response.JSONWithStatus(w, stackDeployResponse{Stack: stack, Health: health}, status)
+}
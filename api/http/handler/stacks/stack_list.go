@@ -82,13 +82,6 @@ func (handler *Handler) stackList(w http.ResponseWriter, r *http.Request) *httpe
 		stacks = authorization.FilterAuthorizedStacks(stacks, user, userTeamIDs)
 	}
 
-	for _, stack := range stacks {
-		if stack.GitConfig != nil && stack.GitConfig.Authentication != nil && stack.GitConfig.Authentication.Password != "" {
-			// sanitize password in the http response to minimise possible security leaks
-			stack.GitConfig.Authentication.Password = ""
-		}
-	}
-
 	return response.JSON(w, stacks)
 }
 
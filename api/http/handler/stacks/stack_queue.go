@@ -0,0 +1,131 @@
+package stacks
+
+import (
+	"cmp"
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/pendingactions/handlers"
+	"github.com/portainer/portainer/api/stacks/deployments"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// deploymentQueueOptions tells how a deployment failing because the environment is unreachable is queued
+type deploymentQueueOptions struct {
+	enabled   bool
+	ttl       int
+	pullImage bool
+	prune     bool
+}
+
+func validateDeploymentQueue(ttl int) error {
+	if ttl < 0 || time.Duration(ttl)*time.Second > handlers.StackDeploymentQueueMaxTTL {
+		return errors.Errorf("Invalid QueueTTL, must be at most %d seconds", int(handlers.StackDeploymentQueueMaxTTL.Seconds()))
+	}
+
+	return nil
+}
+
+// deployOrQueue runs the deployment of a Docker stack. When it fails because the environment cannot be reached,
+// the deployment is queued if requested and run once the environment is back. Edge environments are never queued
+func (handler *Handler) deployOrQueue(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint, options deploymentQueueOptions, deploy func() *httperror.HandlerError) (*handlers.QueuedStackDeployment, *httperror.HandlerError) {
+	if handlerErr := deploy(); handlerErr != nil {
+		if !options.enabled || endpointutils.IsEdgeEndpoint(endpoint) || deployments.IsEnvironmentReachable(handler.DockerClientFactory, endpoint) {
+			return nil, handlerErr
+		}
+
+		return handler.queueDeployment(r, stack, options)
+	}
+
+	// The deployment queued by a previous request is outdated
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return handlers.DequeueStackDeployment(tx, stack.ID)
+	}); err != nil {
+		log.Warn().Err(err).Int("stack_id", int(stack.ID)).Msg("unable to remove the queued deployment of the stack")
+	}
+
+	return nil, nil
+}
+
+func (handler *Handler) queueDeployment(r *http.Request, stack *portainer.Stack, options deploymentQueueOptions) (*handlers.QueuedStackDeployment, *httperror.HandlerError) {
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve info from request context", err)
+	}
+
+	ttl := cmp.Or(time.Duration(options.ttl)*time.Second, handlers.StackDeploymentQueueDefaultTTL)
+
+	var queued *handlers.QueuedStackDeployment
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		queued, err = handlers.QueueStackDeployment(tx, stack, securityContext.UserID, options.pullImage, options.prune, ttl)
+
+		return err
+	}); err != nil {
+		return nil, httperror.InternalServerError("Unable to queue the deployment of the stack", err)
+	}
+
+	log.Info().
+		Int("stack_id", int(stack.ID)).
+		Int("endpoint_id", int(stack.EndpointID)).
+		Time("expires_at", time.Unix(queued.ExpiresAt, 0)).
+		Msg("the environment of the stack is unreachable, the deployment is queued")
+
+	return queued, nil
+}
+
+func writeStackQueuedResponse(w http.ResponseWriter, stack *portainer.Stack, queued *handlers.QueuedStackDeployment) *httperror.HandlerError {
+	return response.JSONWithStatus(w, stackDeployResponse{Stack: stack, Queued: queued}, http.StatusAccepted)
+}
+
+// @id StackQueuedDeployments
+// @summary List the queued deployments of an environment
+// @description List the deployments of stacks waiting for their environment(endpoint) to be reachable again.
+// @description **Access policy**: authenticated
+// @tags stacks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param endpointId query int true "Environment(Endpoint) identifier"
+// @success 200 {array} handlers.QueuedStackDeployment "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Environment(Endpoint) not found"
+// @failure 500 "Server error"
+// @router /stacks/queued [get]
+func (handler *Handler) stackQueuedDeployments(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericQueryParameter(r, "endpointId", false)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: endpointId", err)
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	if err := handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint); err != nil {
+		return httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	var queued []handlers.QueuedStackDeployment
+	if err := handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		queued, err = handlers.QueuedStackDeployments(tx, endpoint.ID)
+
+		return err
+	}); err != nil {
+		return httperror.InternalServerError("Unable to retrieve the queued deployments", err)
+	}
+
+	return response.JSON(w, queued)
+}
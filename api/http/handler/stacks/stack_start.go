@@ -9,27 +9,30 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	httperrors "github.com/portainer/portainer/api/http/errors"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/registryutils/access"
 	"github.com/portainer/portainer/api/stacks/deployments"
 	"github.com/portainer/portainer/api/stacks/stackutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
-	"github.com/portainer/portainer/pkg/libhttp/response"
 )
 
 // @id StackStart
 // @summary Starts a stopped Stack
 // @description Starts a stopped Stack.
+// @description When a health timeout is provided, the request waits for the services of the stack to become healthy.
 // @description **Access policy**: authenticated
 // @tags stacks
 // @security ApiKeyAuth
 // @security jwt
 // @param id path int true "Stack identifier"
 // @param endpointId query int true "Environment identifier"
-// @success 200 {object} portainer.Stack "Success"
+// @param healthTimeout query int false "Time to wait for the services of the stack to become healthy, in seconds"
+// @success 200 {object} stackDeployResponse "Success"
 // @failure 400 "Invalid request"
 // @failure 403 "Permission denied"
 // @failure 404 "Not found"
 // @failure 409 "Stack name is not unique"
+// @failure 422 {object} stackDeployResponse "The services of the stack did not become healthy"
 // @failure 500 "Server error"
 // @router /stacks/{id}/start [post]
 func (handler *Handler) stackStart(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
@@ -59,6 +62,19 @@ func (handler *Handler) stackStart(w http.ResponseWriter, r *http.Request) *http
 		return httperror.BadRequest("Invalid query parameter: endpointId", err)
 	}
 
+	healthTimeout, err := request.RetrieveNumericQueryParameter(r, "healthTimeout", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: healthTimeout", err)
+	}
+
+	var healthGate *portainer.StackHealthGate
+	if healthTimeout != 0 {
+		healthGate = &portainer.StackHealthGate{Timeout: healthTimeout}
+		if err := validateHealthGate(healthGate); err != nil {
+			return httperror.BadRequest("Invalid query parameter: healthTimeout", err)
+		}
+	}
+
 	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
 	if handler.DataStore.IsErrObjectNotFound(err) {
 		return httperror.NotFound("Unable to find an endpoint with the specified identifier inside the database", err)
@@ -109,7 +125,7 @@ func (handler *Handler) stackStart(w http.ResponseWriter, r *http.Request) *http
 	if stack.AutoUpdate != nil && stack.AutoUpdate.Interval != "" {
 		deployments.StopAutoupdate(stack.ID, stack.AutoUpdate.JobID, handler.Scheduler)
 
-		jobID, e := deployments.StartAutoupdate(stack.ID, stack.AutoUpdate.Interval, handler.Scheduler, handler.StackDeployer, handler.DataStore, handler.GitService)
+		jobID, e := handler.startAutoupdate(stack)
 		if e != nil {
 			return e
 		}
@@ -122,18 +138,15 @@ func (handler *Handler) stackStart(w http.ResponseWriter, r *http.Request) *http
 		return httperror.InternalServerError("Unable to start stack", err)
 	}
 
+	health := handler.gateStackHealth(r.Context(), stack, endpoint, healthGate, nil)
+
 	stack.Status = portainer.StackStatusActive
 	err = handler.DataStore.Stack().Update(stack.ID, stack)
 	if err != nil {
 		return httperror.InternalServerError("Unable to update stack status", err)
 	}
 
-	if stack.GitConfig != nil && stack.GitConfig.Authentication != nil && stack.GitConfig.Authentication.Password != "" {
-		// sanitize password in the http response to minimise possible security leaks
-		stack.GitConfig.Authentication.Password = ""
-	}
-
-	return response.JSON(w, stack)
+	return writeStackDeployResponse(w, stack, health)
 }
 
 func (handler *Handler) startStack(
@@ -151,7 +164,10 @@ func (handler *Handler) startStack(
 		return fmt.Errorf("unable to retrieve registries from the database: %w", err)
 	}
 
-	filteredRegistries := security.FilterRegistries(registries, user, securityContext.UserMemberships, endpoint.ID)
+	filteredRegistries, err := access.FilterDeploymentRegistries(handler.DataStore, registries, user, securityContext.UserMemberships, endpoint.ID)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the default registries of the teams of the user: %w", err)
+	}
 
 	switch stack.Type {
 	case portainer.DockerComposeStack:
@@ -8,11 +8,11 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	httperrors "github.com/portainer/portainer/api/http/errors"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/pendingactions/handlers"
 	"github.com/portainer/portainer/api/stacks/deployments"
 	"github.com/portainer/portainer/api/stacks/stackutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
-	"github.com/portainer/portainer/pkg/libhttp/response"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
@@ -23,8 +23,18 @@ type updateComposeStackPayload struct {
 	StackFileContent string `example:"version: 3\n services:\n web:\n image:nginx"`
 	// A list of environment(endpoint) variables used during stack deployment
 	Env []portainer.Pair
+	// Compose profiles to enable, the services assigned to other profiles are not deployed.
+	// The profiles of the stack are kept when omitted, an empty list disables them
+	Profiles []string `example:"debug"`
 	// Force a pulling to current image with the original tag though the image is already the latest
 	PullImage bool `example:"false"`
+	// Wait for the services of the stack to become healthy after the deployment
+	HealthGate *portainer.StackHealthGate
+	// Queue the deployment when the environment cannot be reached, it is run once the environment is reachable again.
+	// Not available for Edge environments
+	QueueIfUnreachable bool `example:"false"`
+	// Number of seconds after which a queued deployment is abandoned, defaults to a day, up to a week
+	QueueTTL int `example:"3600"`
 }
 
 func (payload *updateComposeStackPayload) Validate(r *http.Request) error {
@@ -32,7 +42,15 @@ func (payload *updateComposeStackPayload) Validate(r *http.Request) error {
 		return errors.New("Invalid stack file content")
 	}
 
-	return nil
+	if err := stackutils.ValidateComposeProfiles(payload.Profiles); err != nil {
+		return err
+	}
+
+	if err := validateDeploymentQueue(payload.QueueTTL); err != nil {
+		return err
+	}
+
+	return validateHealthGate(payload.HealthGate)
 }
 
 type updateSwarmStackPayload struct {
@@ -44,6 +62,13 @@ type updateSwarmStackPayload struct {
 	Prune bool `example:"true"`
 	// Force a pulling to current image with the original tag though the image is already the latest
 	PullImage bool `example:"false"`
+	// Wait for the services of the stack to become healthy after the deployment
+	HealthGate *portainer.StackHealthGate
+	// Queue the deployment when the environment cannot be reached, it is run once the environment is reachable again.
+	// Not available for Edge environments
+	QueueIfUnreachable bool `example:"false"`
+	// Number of seconds after which a queued deployment is abandoned, defaults to a day, up to a week
+	QueueTTL int `example:"3600"`
 }
 
 func (payload *updateSwarmStackPayload) Validate(r *http.Request) error {
@@ -51,12 +76,19 @@ func (payload *updateSwarmStackPayload) Validate(r *http.Request) error {
 		return errors.New("Invalid stack file content")
 	}
 
-	return nil
+	if err := validateDeploymentQueue(payload.QueueTTL); err != nil {
+		return err
+	}
+
+	return validateHealthGate(payload.HealthGate)
 }
 
 // @id StackUpdate
 // @summary Update a stack
 // @description Update a stack, only for file based stacks.
+// @description When a health gate is provided, the request waits for the services of the stack to become healthy
+// @description and optionally redeploys the previous version of the stack when they never do.
+// @description When the deployment is queued because the environment is unreachable, the request returns 202.
 // @description **Access policy**: authenticated
 // @tags stacks
 // @security ApiKeyAuth
@@ -66,10 +98,12 @@ func (payload *updateSwarmStackPayload) Validate(r *http.Request) error {
 // @param id path int true "Stack identifier"
 // @param endpointId query int true "Environment identifier"
 // @param body body updateSwarmStackPayload true "Stack details"
-// @success 200 {object} portainer.Stack "Success"
+// @success 200 {object} stackDeployResponse "Success"
+// @success 202 {object} stackDeployResponse "The deployment is queued until the environment is reachable again"
 // @failure 400 "Invalid request"
 // @failure 403 "Permission denied"
 // @failure 404 "Not found"
+// @failure 422 {object} stackDeployResponse "The services of the stack did not become healthy"
 // @failure 500 "Server error"
 // @router /stacks/{id} [put]
 func (handler *Handler) stackUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
@@ -134,8 +168,9 @@ func (handler *Handler) stackUpdate(w http.ResponseWriter, r *http.Request) *htt
 		return httperror.Forbidden(errMsg, errors.New(errMsg))
 	}
 
-	if err := handler.updateAndDeployStack(r, stack, endpoint); err != nil {
-		return err
+	health, queued, handlerErr := handler.updateAndDeployStack(r, stack, endpoint)
+	if handlerErr != nil {
+		return handlerErr
 	}
 
 	user, err := handler.DataStore.User().Read(securityContext.UserID)
@@ -145,21 +180,22 @@ func (handler *Handler) stackUpdate(w http.ResponseWriter, r *http.Request) *htt
 
 	stack.UpdatedBy = user.Username
 	stack.UpdateDate = time.Now().Unix()
-	stack.Status = portainer.StackStatusActive
+	if queued == nil {
+		stack.Status = portainer.StackStatusActive
+	}
 
 	if err := handler.DataStore.Stack().Update(stack.ID, stack); err != nil {
 		return httperror.InternalServerError("Unable to persist the stack changes inside the database", err)
 	}
 
-	if stack.GitConfig != nil && stack.GitConfig.Authentication != nil && stack.GitConfig.Authentication.Password != "" {
-		// Sanitize password in the http response to minimise possible security leaks
-		stack.GitConfig.Authentication.Password = ""
+	if queued != nil {
+		return writeStackQueuedResponse(w, stack, queued)
 	}
 
-	return response.JSON(w, stack)
+	return writeStackDeployResponse(w, stack, health)
 }
 
-func (handler *Handler) updateAndDeployStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) *httperror.HandlerError {
+func (handler *Handler) updateAndDeployStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) (*portainer.StackHealthReport, *handlers.QueuedStackDeployment, *httperror.HandlerError) {
 	switch stack.Type {
 	case portainer.DockerSwarmStack:
 		stack.Name = handler.SwarmStackManager.NormalizeStackName(stack.Name)
@@ -170,13 +206,15 @@ func (handler *Handler) updateAndDeployStack(r *http.Request, stack *portainer.S
 
 		return handler.updateComposeStack(r, stack, endpoint)
 	case portainer.KubernetesStack:
-		return handler.updateKubernetesStack(r, stack, endpoint)
+		health, handlerErr := handler.updateKubernetesStack(r, stack, endpoint)
+
+		return health, nil, handlerErr
 	}
 
-	return httperror.InternalServerError("Unsupported stack", errors.Errorf("unsupported stack type: %v", stack.Type))
+	return nil, nil, httperror.InternalServerError("Unsupported stack", errors.Errorf("unsupported stack type: %v", stack.Type))
 }
 
-func (handler *Handler) updateComposeStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) *httperror.HandlerError {
+func (handler *Handler) updateComposeStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) (*portainer.StackHealthReport, *handlers.QueuedStackDeployment, *httperror.HandlerError) {
 	// Must not be git based stack. stop the auto update job if there is any
 	if stack.AutoUpdate != nil {
 		deployments.StopAutoupdate(stack.ID, stack.AutoUpdate.JobID, handler.Scheduler)
@@ -188,11 +226,17 @@ func (handler *Handler) updateComposeStack(r *http.Request, stack *portainer.Sta
 
 	var payload updateComposeStackPayload
 	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
-		return httperror.BadRequest("Invalid request payload", err)
+		return nil, nil, httperror.BadRequest("Invalid request payload", err)
 	}
 
+	previousEnv := stack.Env
 	stack.Env = payload.Env
 
+	previousProfiles := stack.Profiles
+	if payload.Profiles != nil {
+		stack.Profiles = payload.Profiles
+	}
+
 	if stack.GitConfig != nil {
 		// detach from git
 		stack.GitConfig = nil
@@ -204,13 +248,13 @@ func (handler *Handler) updateComposeStack(r *http.Request, stack *portainer.Sta
 			log.Warn().Err(rollbackErr).Msg("rollback stack file error")
 		}
 
-		return httperror.InternalServerError("Unable to persist updated Compose file on disk", err)
+		return nil, nil, httperror.InternalServerError("Unable to persist updated Compose file on disk", err)
 	}
 
 	// Create compose deployment config
 	securityContext, err := security.RetrieveRestrictedRequestContext(r)
 	if err != nil {
-		return httperror.InternalServerError("Unable to retrieve info from request context", err)
+		return nil, nil, httperror.InternalServerError("Unable to retrieve info from request context", err)
 	}
 
 	composeDeploymentConfig, err := deployments.CreateComposeStackDeploymentConfig(securityContext,
@@ -226,24 +270,49 @@ func (handler *Handler) updateComposeStack(r *http.Request, stack *portainer.Sta
 			log.Warn().Err(rollbackErr).Msg("rollback stack file error")
 		}
 
-		return httperror.InternalServerError(err.Error(), err)
+		return nil, nil, httperror.InternalServerError(err.Error(), err)
 	}
 
 	// Deploy the stack
-	if err := composeDeploymentConfig.Deploy(); err != nil {
+	queued, handlerErr := handler.deployOrQueue(r, stack, endpoint, deploymentQueueOptions{enabled: payload.QueueIfUnreachable, ttl: payload.QueueTTL, pullImage: payload.PullImage}, func() *httperror.HandlerError {
+		if err := composeDeploymentConfig.Deploy(); err != nil {
+			return httperror.InternalServerError(err.Error(), err)
+		}
+
+		return nil
+	})
+	if handlerErr != nil {
 		if rollbackErr := handler.FileService.RollbackStackFile(stackFolder, stack.EntryPoint); rollbackErr != nil {
 			log.Warn().Err(rollbackErr).Msg("rollback stack file error")
 		}
 
-		return httperror.InternalServerError(err.Error(), err)
+		return nil, nil, handlerErr
 	}
 
+	// The services of the stack are checked once the queued deployment runs
+	if queued != nil {
+		handler.FileService.RemoveStackFileBackup(stackFolder, stack.EntryPoint)
+
+		return nil, queued, nil
+	}
+
+	health := handler.gateStackHealth(r.Context(), stack, endpoint, payload.HealthGate, func() error {
+		if err := handler.FileService.RollbackStackFile(stackFolder, stack.EntryPoint); err != nil {
+			return err
+		}
+
+		stack.Env = previousEnv
+		stack.Profiles = previousProfiles
+
+		return composeDeploymentConfig.Deploy()
+	})
+
 	handler.FileService.RemoveStackFileBackup(stackFolder, stack.EntryPoint)
 
-	return nil
+	return health, nil, nil
 }
 
-func (handler *Handler) updateSwarmStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) *httperror.HandlerError {
+func (handler *Handler) updateSwarmStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) (*portainer.StackHealthReport, *handlers.QueuedStackDeployment, *httperror.HandlerError) {
 	// Must not be git based stack. stop the auto update job if there is any
 	if stack.AutoUpdate != nil {
 		deployments.StopAutoupdate(stack.ID, stack.AutoUpdate.JobID, handler.Scheduler)
@@ -255,9 +324,10 @@ func (handler *Handler) updateSwarmStack(r *http.Request, stack *portainer.Stack
 
 	var payload updateSwarmStackPayload
 	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
-		return httperror.BadRequest("Invalid request payload", err)
+		return nil, nil, httperror.BadRequest("Invalid request payload", err)
 	}
 
+	previousEnv := stack.Env
 	stack.Env = payload.Env
 
 	if stack.GitConfig != nil {
@@ -271,13 +341,13 @@ func (handler *Handler) updateSwarmStack(r *http.Request, stack *portainer.Stack
 			log.Warn().Err(rollbackErr).Msg("rollback stack file error")
 		}
 
-		return httperror.InternalServerError("Unable to persist updated Compose file on disk", err)
+		return nil, nil, httperror.InternalServerError("Unable to persist updated Compose file on disk", err)
 	}
 
 	// Create swarm deployment config
 	securityContext, err := security.RetrieveRestrictedRequestContext(r)
 	if err != nil {
-		return httperror.InternalServerError("Unable to retrieve info from request context", err)
+		return nil, nil, httperror.InternalServerError("Unable to retrieve info from request context", err)
 	}
 
 	swarmDeploymentConfig, err := deployments.CreateSwarmStackDeploymentConfig(securityContext,
@@ -293,19 +363,43 @@ func (handler *Handler) updateSwarmStack(r *http.Request, stack *portainer.Stack
 			log.Warn().Err(rollbackErr).Msg("rollback stack file error")
 		}
 
-		return httperror.InternalServerError(err.Error(), err)
+		return nil, nil, httperror.InternalServerError(err.Error(), err)
 	}
 
 	// Deploy the stack
-	if err := swarmDeploymentConfig.Deploy(); err != nil {
+	queued, handlerErr := handler.deployOrQueue(r, stack, endpoint, deploymentQueueOptions{enabled: payload.QueueIfUnreachable, ttl: payload.QueueTTL, pullImage: payload.PullImage, prune: payload.Prune}, func() *httperror.HandlerError {
+		if err := swarmDeploymentConfig.Deploy(); err != nil {
+			return httperror.InternalServerError(err.Error(), err)
+		}
+
+		return nil
+	})
+	if handlerErr != nil {
 		if rollbackErr := handler.FileService.RollbackStackFile(stackFolder, stack.EntryPoint); rollbackErr != nil {
 			log.Warn().Err(rollbackErr).Msg("rollback stack file error")
 		}
 
-		return httperror.InternalServerError(err.Error(), err)
+		return nil, nil, handlerErr
+	}
+
+	// The services of the stack are checked once the queued deployment runs
+	if queued != nil {
+		handler.FileService.RemoveStackFileBackup(stackFolder, stack.EntryPoint)
+
+		return nil, queued, nil
 	}
 
+	health := handler.gateStackHealth(r.Context(), stack, endpoint, payload.HealthGate, func() error {
+		if err := handler.FileService.RollbackStackFile(stackFolder, stack.EntryPoint); err != nil {
+			return err
+		}
+
+		stack.Env = previousEnv
+
+		return swarmDeploymentConfig.Deploy()
+	})
+
 	handler.FileService.RemoveStackFileBackup(stackFolder, stack.EntryPoint)
 
-	return nil
+	return health, nil, nil
 }
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/git"
 	gittypes "github.com/portainer/portainer/api/git/types"
 	"github.com/portainer/portainer/api/git/update"
 	httperrors "github.com/portainer/portainer/api/http/errors"
@@ -26,10 +27,28 @@ type stackGitUpdatePayload struct {
 	RepositoryAuthentication bool
 	RepositoryUsername       string
 	RepositoryPassword       string
+	RepositorySSHPrivateKey  string
+	RepositorySSHPassphrase  string
 	TLSSkipVerify            bool
+	// Number of commits fetched when cloning the repository, kept when not specified
+	RepositoryCloneDepth *int `example:"1"`
+	// Whether only the directories of the stack files and RepositorySparseCheckoutPaths are checked out, kept when not specified
+	RepositorySparseCheckout *bool `example:"false"`
+	// Directories checked out in addition to the directories of the stack files, kept when not specified
+	RepositorySparseCheckoutPaths []string `example:"shared/config"`
 }
 
 func (payload *stackGitUpdatePayload) Validate(r *http.Request) error {
+	if payload.RepositoryCloneDepth != nil {
+		if err := git.ValidateCloneSettings(*payload.RepositoryCloneDepth, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := git.ValidateCloneSettings(0, payload.RepositorySparseCheckoutPaths); err != nil {
+		return err
+	}
+
 	return update.ValidateAutoUpdateSettings(payload.AutoUpdate)
 }
 
@@ -132,6 +151,21 @@ func (handler *Handler) stackUpdateGit(w http.ResponseWriter, r *http.Request) *
 	//update retrieved stack data based on the payload
 	stack.GitConfig.ReferenceName = payload.RepositoryReferenceName
 	stack.GitConfig.TLSSkipVerify = payload.TLSSkipVerify
+
+	if payload.RepositoryCloneDepth != nil {
+		stack.GitConfig.CloneDepth = *payload.RepositoryCloneDepth
+	}
+
+	if payload.RepositorySparseCheckout != nil {
+		stack.GitConfig.SparseCheckout = *payload.RepositorySparseCheckout
+	}
+
+	if payload.RepositorySparseCheckoutPaths != nil {
+		stack.GitConfig.SparseCheckoutPaths = payload.RepositorySparseCheckoutPaths
+	}
+
+	stackutils.AddAdditionalFilesToSparseCheckout(stack.GitConfig, stack.AdditionalFiles)
+
 	stack.AutoUpdate = payload.AutoUpdate
 	stack.Env = payload.Env
 	stack.UpdatedBy = user.Username
@@ -143,6 +177,8 @@ func (handler *Handler) stackUpdateGit(w http.ResponseWriter, r *http.Request) *
 
 	if payload.RepositoryAuthentication {
 		password := payload.RepositoryPassword
+		sshPrivateKey := payload.RepositorySSHPrivateKey
+		sshPassphrase := payload.RepositorySSHPassphrase
 
 		// When the existing stack is using the custom username/password and the password is not updated,
 		// the stack should keep using the saved username/password
@@ -150,12 +186,20 @@ func (handler *Handler) stackUpdateGit(w http.ResponseWriter, r *http.Request) *
 			password = stack.GitConfig.Authentication.Password
 		}
 
+		// The same applies to the saved SSH key
+		if sshPrivateKey == "" && stack.GitConfig != nil && stack.GitConfig.Authentication != nil {
+			sshPrivateKey = stack.GitConfig.Authentication.SSHPrivateKey
+			sshPassphrase = stack.GitConfig.Authentication.SSHPassphrase
+		}
+
 		stack.GitConfig.Authentication = &gittypes.GitAuthentication{
-			Username: payload.RepositoryUsername,
-			Password: password,
+			Username:      payload.RepositoryUsername,
+			Password:      password,
+			SSHPrivateKey: sshPrivateKey,
+			SSHPassphrase: sshPassphrase,
 		}
 
-		if _, err := handler.GitService.LatestCommitID(stack.GitConfig.URL, stack.GitConfig.ReferenceName, stack.GitConfig.Authentication.Username, stack.GitConfig.Authentication.Password, stack.GitConfig.TLSSkipVerify); err != nil {
+		if _, err := handler.GitService.LatestCommitID(stack.GitConfig.URL, stack.GitConfig.ReferenceName, stack.GitConfig.Authentication.Username, stack.GitConfig.Authentication.Password, sshPrivateKey, sshPassphrase, stack.GitConfig.TLSSkipVerify); err != nil {
 			return httperror.InternalServerError("Unable to fetch git repository", err)
 		}
 	} else {
@@ -175,10 +219,5 @@ func (handler *Handler) stackUpdateGit(w http.ResponseWriter, r *http.Request) *
 		return httperror.InternalServerError("Unable to persist the stack changes inside the database", err)
 	}
 
-	if stack.GitConfig != nil && stack.GitConfig.Authentication != nil && stack.GitConfig.Authentication.Password != "" {
-		// sanitize password in the http response to minimise possible security leaks
-		stack.GitConfig.Authentication.Password = ""
-	}
-
 	return response.JSON(w, stack)
 }
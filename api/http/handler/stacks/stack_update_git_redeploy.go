@@ -13,7 +13,6 @@ import (
 	"github.com/portainer/portainer/api/stacks/stackutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
-	"github.com/portainer/portainer/pkg/libhttp/response"
 
 	"github.com/pkg/errors"
 )
@@ -23,21 +22,43 @@ type stackGitRedployPayload struct {
 	RepositoryAuthentication bool
 	RepositoryUsername       string
 	RepositoryPassword       string
+	RepositorySSHPrivateKey  string
+	RepositorySSHPassphrase  string
 	Env                      []portainer.Pair
 	Prune                    bool
+	// Compose profiles to enable, the profiles of the stack are kept when omitted and an empty list disables them
+	Profiles []string `example:"debug"`
 	// Force a pulling to current image with the original tag though the image is already the latest
 	PullImage bool `example:"false"`
 
 	StackName string
+	// Wait for the services of the stack to become healthy after the deployment
+	HealthGate *portainer.StackHealthGate
+	// Queue the deployment when the environment cannot be reached, it is run once the environment is reachable again.
+	// Only available for Docker stacks outside of Edge environments
+	QueueIfUnreachable bool `example:"false"`
+	// Number of seconds after which a queued deployment is abandoned, defaults to a day, up to a week
+	QueueTTL int `example:"3600"`
 }
 
 func (payload *stackGitRedployPayload) Validate(r *http.Request) error {
-	return nil
+	if err := validateDeploymentQueue(payload.QueueTTL); err != nil {
+		return err
+	}
+
+	if err := stackutils.ValidateComposeProfiles(payload.Profiles); err != nil {
+		return err
+	}
+
+	return validateHealthGate(payload.HealthGate)
 }
 
 // @id StackGitRedeploy
 // @summary Redeploy a stack
 // @description Pull and redeploy a stack via Git
+// @description When a health gate is provided, the request waits for the services of the stack to become healthy
+// @description and optionally redeploys the previous commit when they never do.
+// @description When the deployment is queued because the environment is unreachable, the request returns 202.
 // @description **Access policy**: authenticated
 // @tags stacks
 // @security ApiKeyAuth
@@ -47,10 +68,12 @@ func (payload *stackGitRedployPayload) Validate(r *http.Request) error {
 // @param id path int true "Stack identifier"
 // @param endpointId query int false "Stacks created before version 1.18.0 might not have an associated environment(endpoint) identifier. Use this optional parameter to set the environment(endpoint) identifier used by the stack."
 // @param body body stackGitRedployPayload true "Git configs for pull and redeploy of a stack. **StackName** may only be populated for Kuberenetes stacks, and if specified with a blank string, it will be set to blank"
-// @success 200 {object} portainer.Stack "Success"
+// @success 200 {object} stackDeployResponse "Success"
+// @success 202 {object} stackDeployResponse "The deployment is queued until the environment is reachable again"
 // @failure 400 "Invalid request"
 // @failure 403 "Permission denied"
 // @failure 404 "Not found"
+// @failure 422 {object} stackDeployResponse "The services of the stack did not become healthy"
 // @failure 500 "Server error"
 // @router /stacks/{id}/git/redeploy [put]
 func (handler *Handler) stackGitRedeploy(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
@@ -123,8 +146,19 @@ func (handler *Handler) stackGitRedeploy(w http.ResponseWriter, r *http.Request)
 		return httperror.BadRequest("Invalid request payload", err)
 	}
 
+	previous := struct {
+		referenceName string
+		env           []portainer.Pair
+		profiles      []string
+		option        *portainer.StackOption
+		name          string
+	}{stack.GitConfig.ReferenceName, stack.Env, stack.Profiles, stack.Option, stack.Name}
+
 	stack.GitConfig.ReferenceName = payload.RepositoryReferenceName
 	stack.Env = payload.Env
+	if stack.Type == portainer.DockerComposeStack && payload.Profiles != nil {
+		stack.Profiles = payload.Profiles
+	}
 	if stack.Type == portainer.DockerSwarmStack {
 		stack.Option = &portainer.StackOption{Prune: payload.Prune}
 	}
@@ -135,14 +169,24 @@ func (handler *Handler) stackGitRedeploy(w http.ResponseWriter, r *http.Request)
 
 	repositoryUsername := ""
 	repositoryPassword := ""
+	repositorySSHPrivateKey := ""
+	repositorySSHPassphrase := ""
 	if payload.RepositoryAuthentication {
 		repositoryPassword = payload.RepositoryPassword
+		repositorySSHPrivateKey = payload.RepositorySSHPrivateKey
+		repositorySSHPassphrase = payload.RepositorySSHPassphrase
 
 		// When the existing stack is using the custom username/password and the password is not updated,
 		// the stack should keep using the saved username/password
 		if repositoryPassword == "" && stack.GitConfig != nil && stack.GitConfig.Authentication != nil {
 			repositoryPassword = stack.GitConfig.Authentication.Password
 		}
+
+		// The same applies to the saved SSH key
+		if repositorySSHPrivateKey == "" && stack.GitConfig != nil && stack.GitConfig.Authentication != nil {
+			repositorySSHPrivateKey = stack.GitConfig.Authentication.SSHPrivateKey
+			repositorySSHPassphrase = stack.GitConfig.Authentication.SSHPassphrase
+		}
 		repositoryUsername = payload.RepositoryUsername
 	}
 
@@ -152,6 +196,8 @@ func (handler *Handler) stackGitRedeploy(w http.ResponseWriter, r *http.Request)
 		ReferenceName: stack.GitConfig.ReferenceName,
 		Username:      repositoryUsername,
 		Password:      repositoryPassword,
+		SSHPrivateKey: repositorySSHPrivateKey,
+		SSHPassphrase: repositorySSHPassphrase,
 		TLSSkipVerify: stack.GitConfig.TLSSkipVerify,
 	}
 
@@ -162,15 +208,52 @@ func (handler *Handler) stackGitRedeploy(w http.ResponseWriter, r *http.Request)
 
 	defer clean()
 
-	if err := handler.deployStack(r, stack, payload.PullImage, endpoint); err != nil {
-		return err
+	queueOptions := deploymentQueueOptions{
+		enabled:   payload.QueueIfUnreachable && stack.Type != portainer.KubernetesStack,
+		ttl:       payload.QueueTTL,
+		pullImage: payload.PullImage,
+		prune:     stack.Option != nil && stack.Option.Prune,
 	}
 
-	newHash, err := handler.GitService.LatestCommitID(stack.GitConfig.URL, stack.GitConfig.ReferenceName, repositoryUsername, repositoryPassword, stack.GitConfig.TLSSkipVerify)
-	if err != nil {
-		return httperror.InternalServerError("Unable get latest commit id", errors.WithMessagef(err, "failed to fetch latest commit id of the stack %v", stack.ID))
+	queued, handlerErr := handler.deployOrQueue(r, stack, endpoint, queueOptions, func() *httperror.HandlerError {
+		return handler.deployStack(r, stack, payload.PullImage, endpoint)
+	})
+	if handlerErr != nil {
+		return handlerErr
+	}
+
+	// The services of the stack are checked once the queued deployment runs
+	var health *portainer.StackHealthReport
+	if queued == nil {
+		health = handler.gateStackHealth(r.Context(), stack, endpoint, payload.HealthGate, func() error {
+			if err := git.RestoreBackup(stack.ProjectPath); err != nil {
+				return err
+			}
+
+			stack.GitConfig.ReferenceName = previous.referenceName
+			stack.Env = previous.env
+			stack.Profiles = previous.profiles
+			stack.Option = previous.option
+			stack.Name = previous.name
+
+			if err := handler.deployStack(r, stack, payload.PullImage, endpoint); err != nil {
+				return err
+			}
+
+			return nil
+		})
+	}
+
+	// The previous commit is deployed again after a rollback
+	if health == nil || !health.RolledBack {
+		newHash, err := handler.GitService.LatestCommitID(stack.GitConfig.URL, stack.GitConfig.ReferenceName, repositoryUsername, repositoryPassword, repositorySSHPrivateKey, repositorySSHPassphrase, stack.GitConfig.TLSSkipVerify)
+		if err != nil {
+			return httperror.InternalServerError("Unable get latest commit id", errors.WithMessagef(err, "failed to fetch latest commit id of the stack %v", stack.ID))
+		}
+		stack.GitConfig.ConfigHash = newHash
+
+		stackutils.SetConfigCommit(handler.GitService, stack.GitConfig, repositoryUsername, repositoryPassword, repositorySSHPrivateKey, repositorySSHPassphrase)
 	}
-	stack.GitConfig.ConfigHash = newHash
 
 	user, err := handler.DataStore.User().Read(securityContext.UserID)
 	if err != nil {
@@ -178,18 +261,19 @@ func (handler *Handler) stackGitRedeploy(w http.ResponseWriter, r *http.Request)
 	}
 	stack.UpdatedBy = user.Username
 	stack.UpdateDate = time.Now().Unix()
-	stack.Status = portainer.StackStatusActive
+	if queued == nil {
+		stack.Status = portainer.StackStatusActive
+	}
 
 	if err := handler.DataStore.Stack().Update(stack.ID, stack); err != nil {
 		return httperror.InternalServerError("Unable to persist the stack changes inside the database", errors.Wrap(err, "failed to update the stack"))
 	}
 
-	if stack.GitConfig != nil && stack.GitConfig.Authentication != nil && stack.GitConfig.Authentication.Password != "" {
-		// Sanitize password in the http response to minimise possible security leaks
-		stack.GitConfig.Authentication.Password = ""
+	if queued != nil {
+		return writeStackQueuedResponse(w, stack, queued)
 	}
 
-	return response.JSON(w, stack)
+	return writeStackDeployResponse(w, stack, health)
 }
 
 func (handler *Handler) deployStack(r *http.Request, stack *portainer.Stack, pullImage bool, endpoint *portainer.Endpoint) *httperror.HandlerError {
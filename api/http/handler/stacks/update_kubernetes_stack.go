@@ -10,7 +10,6 @@ import (
 	gittypes "github.com/portainer/portainer/api/git/types"
 	"github.com/portainer/portainer/api/git/update"
 	"github.com/portainer/portainer/api/http/security"
-	"github.com/portainer/portainer/api/internal/registryutils"
 	k "github.com/portainer/portainer/api/kubernetes"
 	"github.com/portainer/portainer/api/stacks/deployments"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
@@ -24,6 +23,8 @@ type kubernetesFileStackUpdatePayload struct {
 	StackFileContent string
 	// Name of the stack
 	StackName string
+	// Wait for the workloads of the stack to complete their rollout after the deployment
+	HealthGate *portainer.StackHealthGate
 }
 
 type kubernetesGitStackUpdatePayload struct {
@@ -31,6 +32,8 @@ type kubernetesGitStackUpdatePayload struct {
 	RepositoryAuthentication bool
 	RepositoryUsername       string
 	RepositoryPassword       string
+	RepositorySSHPrivateKey  string
+	RepositorySSHPassphrase  string
 	AutoUpdate               *portainer.AutoUpdateSettings
 	TLSSkipVerify            bool
 }
@@ -40,7 +43,7 @@ func (payload *kubernetesFileStackUpdatePayload) Validate(r *http.Request) error
 		return errors.New("Invalid stack file content")
 	}
 
-	return nil
+	return validateHealthGate(payload.HealthGate)
 }
 
 func (payload *kubernetesGitStackUpdatePayload) Validate(r *http.Request) error {
@@ -51,7 +54,7 @@ func (payload *kubernetesGitStackUpdatePayload) Validate(r *http.Request) error
 	return nil
 }
 
-func (handler *Handler) updateKubernetesStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) *httperror.HandlerError {
+func (handler *Handler) updateKubernetesStack(r *http.Request, stack *portainer.Stack, endpoint *portainer.Endpoint) (*portainer.StackHealthReport, *httperror.HandlerError) {
 	if stack.GitConfig != nil {
 		// Stop the autoupdate job if there is any
 		if stack.AutoUpdate != nil {
@@ -61,7 +64,7 @@ func (handler *Handler) updateKubernetesStack(r *http.Request, stack *portainer.
 		var payload kubernetesGitStackUpdatePayload
 
 		if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
-			return httperror.BadRequest("Invalid request payload", err)
+			return nil, httperror.BadRequest("Invalid request payload", err)
 		}
 
 		stack.GitConfig.ReferenceName = payload.RepositoryReferenceName
@@ -75,71 +78,74 @@ func (handler *Handler) updateKubernetesStack(r *http.Request, stack *portainer.
 				password = stack.GitConfig.Authentication.Password
 			}
 
+			sshPrivateKey := payload.RepositorySSHPrivateKey
+			sshPassphrase := payload.RepositorySSHPassphrase
+			if sshPrivateKey == "" && stack.GitConfig != nil && stack.GitConfig.Authentication != nil {
+				sshPrivateKey = stack.GitConfig.Authentication.SSHPrivateKey
+				sshPassphrase = stack.GitConfig.Authentication.SSHPassphrase
+			}
+
 			stack.GitConfig.Authentication = &gittypes.GitAuthentication{
-				Username: payload.RepositoryUsername,
-				Password: password,
+				Username:      payload.RepositoryUsername,
+				Password:      password,
+				SSHPrivateKey: sshPrivateKey,
+				SSHPassphrase: sshPassphrase,
 			}
 
-			if _, err := handler.GitService.LatestCommitID(stack.GitConfig.URL, stack.GitConfig.ReferenceName, stack.GitConfig.Authentication.Username, stack.GitConfig.Authentication.Password, stack.GitConfig.TLSSkipVerify); err != nil {
-				return httperror.InternalServerError("Unable to fetch git repository", err)
+			if _, err := handler.GitService.LatestCommitID(stack.GitConfig.URL, stack.GitConfig.ReferenceName, stack.GitConfig.Authentication.Username, stack.GitConfig.Authentication.Password, sshPrivateKey, sshPassphrase, stack.GitConfig.TLSSkipVerify); err != nil {
+				return nil, httperror.InternalServerError("Unable to fetch git repository", err)
 			}
 		}
 
 		if payload.AutoUpdate != nil && payload.AutoUpdate.Interval != "" {
 			jobID, e := deployments.StartAutoupdate(stack.ID, stack.AutoUpdate.Interval, handler.Scheduler, handler.StackDeployer, handler.DataStore, handler.GitService)
 			if e != nil {
-				return e
+				return nil, e
 			}
 			stack.AutoUpdate.JobID = jobID
 		}
 
-		return nil
+		return nil, nil
 	}
 
 	var payload kubernetesFileStackUpdatePayload
 
 	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
-		return httperror.BadRequest("Invalid request payload", err)
+		return nil, httperror.BadRequest("Invalid request payload", err)
 	}
 
 	tokenData, err := security.RetrieveTokenData(r)
 	if err != nil {
-		return httperror.BadRequest("Failed to retrieve user token data", err)
+		return nil, httperror.BadRequest("Failed to retrieve user token data", err)
 	}
 
 	tempFileDir, _ := os.MkdirTemp("", "kub_file_content")
 	defer os.RemoveAll(tempFileDir)
 
 	if err := filesystem.WriteToFile(filesystem.JoinPaths(tempFileDir, stack.EntryPoint), []byte(payload.StackFileContent)); err != nil {
-		return httperror.InternalServerError("Failed to persist deployment file in a temp directory", err)
+		return nil, httperror.InternalServerError("Failed to persist deployment file in a temp directory", err)
 	}
 
 	if payload.StackName != stack.Name {
 		stack.Name = payload.StackName
 		if err := handler.DataStore.Stack().Update(stack.ID, stack); err != nil {
-			return httperror.InternalServerError("Failed to update stack name", err)
+			return nil, httperror.InternalServerError("Failed to update stack name", err)
 		}
 	}
 
-	// Refresh ECR registry secret if needed
-	// RefreshEcrSecret method checks if the namespace has any ECR registry
-	// otherwise return nil
-	cli, err := handler.KubernetesClientFactory.GetPrivilegedKubeClient(endpoint)
-	if err == nil {
-		registryutils.RefreshEcrSecret(cli, endpoint, handler.DataStore, stack.Namespace)
-	}
-
 	// Use temp dir as the stack project path for deployment
 	// so if the deployment failed, the original file won't be over-written
 	stack.ProjectPath = tempFileDir
 
-	if _, err := handler.deployKubernetesStack(tokenData.ID, endpoint, stack, k.KubeAppLabels{
+	appLabels := k.KubeAppLabels{
 		StackID:   int(stack.ID),
 		StackName: stack.Name,
 		Owner:     stack.CreatedBy,
 		Kind:      "content",
-	}); err != nil {
-		return httperror.InternalServerError("Unable to deploy Kubernetes stack via file content", err)
+	}
+
+	if _, err := handler.deployKubernetesStack(tokenData.ID, endpoint, stack, appLabels); err != nil {
+		return nil, httperror.InternalServerError("Unable to deploy Kubernetes stack via file content", err)
 	}
 
 	stackFolder := strconv.Itoa(int(stack.ID))
@@ -149,11 +155,22 @@ func (handler *Handler) updateKubernetesStack(r *http.Request, stack *portainer.
 			log.Warn().Err(rollbackErr).Msg("rollback stack file error")
 		}
 
-		return httperror.InternalServerError("Unable to persist Kubernetes Manifest file on disk", err)
+		return nil, httperror.InternalServerError("Unable to persist Kubernetes Manifest file on disk", err)
 	}
 	stack.ProjectPath = projectPath
 
+	// The resources that were added by the new manifest are kept by the rollback
+	health := handler.gateStackHealth(r.Context(), stack, endpoint, payload.HealthGate, func() error {
+		if err := handler.FileService.RollbackStackFile(stackFolder, stack.EntryPoint); err != nil {
+			return err
+		}
+
+		_, err := handler.deployKubernetesStack(tokenData.ID, endpoint, stack, appLabels)
+
+		return err
+	})
+
 	handler.FileService.RemoveStackFileBackup(stackFolder, stack.EntryPoint)
 
-	return nil
+	return health, nil
 }
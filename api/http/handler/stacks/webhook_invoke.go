@@ -13,7 +13,7 @@ import (
 )
 
 // @id WebhookInvoke
-// @summary Webhook for triggering stack updates from git
+// @summary Webhook for triggering stack updates from git or from an OCI artifact
 // @description **Access policy**: public
 // @tags stacks
 // @param webhookID path string true "Stack identifier"
@@ -38,7 +38,13 @@ func (handler *Handler) webhookInvoke(w http.ResponseWriter, r *http.Request) *h
 		return httperror.NewError(statusCode, "Unable to find the stack by webhook ID", err)
 	}
 
-	if err = deployments.RedeployWhenChanged(stack.ID, handler.StackDeployer, handler.DataStore, handler.GitService); err != nil {
+	if stack.OCIConfig != nil {
+		err = deployments.RedeployOCIWhenChanged(stack.ID, handler.StackDeployer, handler.DataStore, handler.OCIArtifactService)
+	} else {
+		err = deployments.RedeployWhenChanged(stack.ID, handler.StackDeployer, handler.DataStore, handler.GitService)
+	}
+
+	if err != nil {
 		var StackAuthorMissingErr *deployments.StackAuthorMissingErr
 		if errors.As(err, &StackAuthorMissingErr) {
 			return httperror.Conflict("Autoupdate for the stack isn't available", err)
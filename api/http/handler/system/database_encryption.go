@@ -0,0 +1,71 @@
+package system
+
+import (
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/portainer/portainer/api/database"
+	"github.com/portainer/portainer/api/database/boltdb"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type databaseEncryptionPayload struct {
+	// Name of the secret key the database is encrypted with, read from /run/secrets/<SecretKeyName>
+	SecretKeyName string `validate:"required" example:"portainer-2024"`
+}
+
+func (payload *databaseEncryptionPayload) Validate(r *http.Request) error {
+	if payload.SecretKeyName == "" {
+		return errors.New("invalid secret key name")
+	}
+
+	return nil
+}
+
+type databaseEncryptionResponse struct {
+	// Name of the secret key to set with --secret-key-name when Portainer restarts
+	SecretKeyName string `json:"SecretKeyName" example:"portainer-2024"`
+}
+
+// @id systemDatabaseEncryption
+// @summary Encrypt the database with a new secret key
+// @description Encrypt every object of the database with the secret key mounted in /run/secrets, an unencrypted database
+// @description is encrypted and the current key is replaced otherwise. The objects are re-encrypted in a single transaction
+// @description and verified before it is committed, the database is left unchanged when any of them fails.
+// @description Portainer must be restarted with --secret-key-name set to the new secret key name.
+// @description **Access policy**: administrator
+// @security ApiKeyAuth
+// @security jwt
+// @tags system
+// @accept json
+// @produce json
+// @param body body databaseEncryptionPayload true "Secret key"
+// @success 200 {object} databaseEncryptionResponse "Success"
+// @failure 400 "Invalid request, missing or unchanged secret key"
+// @failure 403 "Permission denied"
+// @failure 500 "Server error"
+// @router /system/database/encryption [post]
+func (handler *Handler) systemDatabaseEncryption(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload databaseEncryptionPayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	key, err := database.LoadEncryptionKey(payload.SecretKeyName)
+	if errors.Is(err, os.ErrNotExist) {
+		return httperror.BadRequest("Unable to find the secret key in /run/secrets", err)
+	} else if err != nil {
+		return httperror.BadRequest("Unable to load the secret key", err)
+	}
+
+	if err := handler.dataStore.Connection().Reencrypt(key); errors.Is(err, boltdb.ErrSameEncryptionKey) {
+		return httperror.BadRequest("The database is already encrypted with this secret key", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to encrypt the database, it was left unchanged", err)
+	}
+
+	return response.JSON(w, databaseEncryptionResponse{SecretKeyName: payload.SecretKeyName})
+}
@@ -43,6 +43,8 @@ func NewHandler(bouncer security.BouncerService,
 	adminRouter.Use(bouncer.AdminAccess)
 
 	adminRouter.Handle("/upgrade", httperror.LoggerHandler(h.systemUpgrade)).Methods(http.MethodPost)
+	adminRouter.Handle("/database/slow_queries", httperror.LoggerHandler(h.systemSlowQueries)).Methods(http.MethodGet)
+	adminRouter.Handle("/database/encryption", httperror.LoggerHandler(h.systemDatabaseEncryption)).Methods(http.MethodPost)
 
 	authenticatedRouter := router.PathPrefix("/").Subrouter()
 	authenticatedRouter.Use(bouncer.AuthenticatedAccess)
@@ -32,7 +32,7 @@ func (handler *Handler) systemNodesCount(w http.ResponseWriter, r *http.Request)
 	}
 
 	for i := range endpoints {
-		err = snapshot.FillSnapshotData(handler.dataStore, &endpoints[i])
+		err = snapshot.FillSnapshotSummary(handler.dataStore, &endpoints[i])
 		if err != nil {
 			return httperror.InternalServerError("Unable to add snapshot data", err)
 		}
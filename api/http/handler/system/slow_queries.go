@@ -0,0 +1,38 @@
+package system
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id systemSlowQueries
+// @summary Retrieve the slowest database queries
+// @description List the slowest database queries since the start of Portainer, the slowest first.
+// @description Only the queries lasting longer than the threshold set with the --slow-query-threshold flag are recorded.
+// @description **Access policy**: administrator
+// @security ApiKeyAuth
+// @security jwt
+// @tags system
+// @produce json
+// @param limit query int false "Maximum number of queries to return, all the recorded queries are returned when omitted"
+// @success 200 {array} portainer.SlowQuery "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 500 "Server error"
+// @router /system/database/slow_queries [get]
+func (handler *Handler) systemSlowQueries(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	limit, err := request.RetrieveNumericQueryParameter(r, "limit", true)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: limit", err)
+	}
+
+	queries := handler.dataStore.Connection().SlowQueries()
+	if limit > 0 && limit < len(queries) {
+		queries = queries[:limit]
+	}
+
+	return response.JSON(w, queries)
+}
@@ -48,6 +48,7 @@ func (payload *teamMembershipCreatePayload) Validate(r *http.Request) error {
 // @success 204 "Success"
 // @failure 400 "Invalid request"
 // @failure 403 "Permission denied to manage memberships"
+// @failure 404 "User not found"
 // @failure 409 "Team membership already registered"
 // @failure 500 "Server error"
 // @router /team_memberships [post]
@@ -67,6 +68,18 @@ func (handler *Handler) teamMembershipCreate(w http.ResponseWriter, r *http.Requ
 		return httperror.Forbidden("Permission denied to manage team memberships", httperrors.ErrResourceAccessDenied)
 	}
 
+	user, err := handler.DataStore.User().Read(portainer.UserID(payload.UserID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a user with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a user with the specified identifier inside the database", err)
+	}
+
+	// The accesses of a service account are limited to the environments it was explicitly granted
+	if user.ServiceAccount {
+		return httperror.BadRequest("A service account cannot be a member of a team", errors.New("service accounts cannot join teams"))
+	}
+
 	memberships, err := handler.DataStore.TeamMembership().TeamMembershipsByUserID(portainer.UserID(payload.UserID))
 	if err != nil {
 		return httperror.InternalServerError("Unable to retrieve team memberships from the database", err)
@@ -82,6 +82,10 @@ func createTeam(tx dataservices.DataStoreTx, payload teamCreatePayload) (*portai
 	}
 
 	for _, teamLeader := range payload.TeamLeaders {
+		if user, err := tx.User().Read(teamLeader); err == nil && user.ServiceAccount {
+			return nil, httperror.BadRequest("A service account cannot be a member of a team", errors.New("service accounts cannot join teams"))
+		}
+
 		membership := &portainer.TeamMembership{
 			UserID: teamLeader,
 			TeamID: team.ID,
@@ -48,6 +48,19 @@ func (handler *Handler) teamDelete(w http.ResponseWriter, r *http.Request) *http
 		return httperror.InternalServerError("Unable to delete associated team memberships from the database", err)
 	}
 
+	quotas, err := handler.DataStore.Quota().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve quotas from the database", err)
+	}
+
+	for _, quota := range quotas {
+		if quota.TeamID == portainer.TeamID(teamID) {
+			if err := handler.DataStore.Quota().Delete(quota.ID); err != nil {
+				return httperror.InternalServerError("Unable to delete the quota of the team from the database", err)
+			}
+		}
+	}
+
 	// update default team if deleted team was default
 	err = handler.updateDefaultTeamIfDeleted(portainer.TeamID(teamID))
 	if err != nil {
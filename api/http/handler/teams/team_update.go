@@ -12,6 +12,8 @@ import (
 type teamUpdatePayload struct {
 	// Name
 	Name string `example:"developers"`
+	// Registries whose credentials are used when the team members deploy their images, an empty list clears them
+	DefaultRegistries []portainer.RegistryID `example:"1"`
 }
 
 func (payload *teamUpdatePayload) Validate(r *http.Request) error {
@@ -20,7 +22,8 @@ func (payload *teamUpdatePayload) Validate(r *http.Request) error {
 
 // @id TeamUpdate
 // @summary Update a team
-// @description Update a team.
+// @description Update a team. The credentials of the default registries of the team are used when the team members
+// @description deploy images hosted by these registries, without granting them access to the registries.
 // @description **Access policy**: administrator
 // @tags teams
 // @security ApiKeyAuth
@@ -58,6 +61,18 @@ func (handler *Handler) teamUpdate(w http.ResponseWriter, r *http.Request) *http
 		team.Name = payload.Name
 	}
 
+	if payload.DefaultRegistries != nil {
+		for _, registryID := range payload.DefaultRegistries {
+			if _, err := handler.DataStore.Registry().Read(registryID); handler.DataStore.IsErrObjectNotFound(err) {
+				return httperror.BadRequest("Unable to find a default registry inside the database", err)
+			} else if err != nil {
+				return httperror.InternalServerError("Unable to find a default registry inside the database", err)
+			}
+		}
+
+		team.DefaultRegistries = payload.DefaultRegistries
+	}
+
 	if err := handler.DataStore.Team().Update(team.ID, team); err != nil {
 		return httperror.NotFound("Unable to persist team changes inside the database", err)
 	}
@@ -6,6 +6,7 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/templatesources"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 
 	"github.com/gorilla/mux"
@@ -14,9 +15,10 @@ import (
 // Handler represents an HTTP API handler for managing templates.
 type Handler struct {
 	*mux.Router
-	DataStore   dataservices.DataStore
-	GitService  portainer.GitService
-	FileService portainer.FileService
+	DataStore       dataservices.DataStore
+	GitService      portainer.GitService
+	FileService     portainer.FileService
+	TemplateSources *templatesources.Service
 }
 
 // NewHandler returns a new instance of Handler.
@@ -31,5 +33,19 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.templateFile))).Methods(http.MethodPost)
 	h.Handle("/templates/file",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.templateFileOld))).Methods(http.MethodPost)
+
+	h.Handle("/templates/sources",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.templateSourceList))).Methods(http.MethodGet)
+	h.Handle("/templates/sources",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.templateSourceCreate))).Methods(http.MethodPost)
+	h.Handle("/templates/sources/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.templateSourceInspect))).Methods(http.MethodGet)
+	h.Handle("/templates/sources/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.templateSourceUpdate))).Methods(http.MethodPut)
+	h.Handle("/templates/sources/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.templateSourceDelete))).Methods(http.MethodDelete)
+	h.Handle("/templates/sources/{id}/refresh",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.templateSourceRefresh))).Methods(http.MethodPost)
+
 	return h
 }
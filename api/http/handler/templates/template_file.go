@@ -5,6 +5,7 @@ import (
 	"slices"
 
 	portainer "github.com/portainer/portainer/api"
+	gittypes "github.com/portainer/portainer/api/git/types"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -37,7 +38,7 @@ func (handler *Handler) templateFile(w http.ResponseWriter, r *http.Request) *ht
 		return httperror.BadRequest("Invalid template identifier", err)
 	}
 
-	templatesResponse, httpErr := handler.fetchTemplates()
+	templatesResponse, httpErr := handler.fetchTemplates(r)
 	if httpErr != nil {
 		return httpErr
 	}
@@ -71,7 +72,7 @@ func (handler *Handler) templateFile(w http.ResponseWriter, r *http.Request) *ht
 
 	defer handler.cleanUp(projectPath)
 
-	if err := handler.GitService.CloneRepository(projectPath, template.Repository.URL, "", "", "", false); err != nil {
+	if err := handler.GitService.CloneRepository(projectPath, template.Repository.URL, "", "", "", "", "", false, gittypes.CloneOptions{}); err != nil {
 		return httperror.InternalServerError("Unable to clone git repository", err)
 	}
 
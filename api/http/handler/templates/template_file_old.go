@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	gittypes "github.com/portainer/portainer/api/git/types"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
@@ -29,8 +30,8 @@ func (payload *filePayload) Validate(r *http.Request) error {
 	return nil
 }
 
-func (handler *Handler) ifRequestedTemplateExists(payload *filePayload) *httperror.HandlerError {
-	response, httpErr := handler.fetchTemplates()
+func (handler *Handler) ifRequestedTemplateExists(r *http.Request, payload *filePayload) *httperror.HandlerError {
+	response, httpErr := handler.fetchTemplates(r)
 	if httpErr != nil {
 		return httpErr
 	}
@@ -67,7 +68,7 @@ func (handler *Handler) templateFileOld(w http.ResponseWriter, r *http.Request)
 		return httperror.BadRequest("Invalid request payload", err)
 	}
 
-	if err := handler.ifRequestedTemplateExists(&payload); err != nil {
+	if err := handler.ifRequestedTemplateExists(r, &payload); err != nil {
 		return err
 	}
 
@@ -78,7 +79,7 @@ func (handler *Handler) templateFileOld(w http.ResponseWriter, r *http.Request)
 
 	defer handler.cleanUp(projectPath)
 
-	err = handler.GitService.CloneRepository(projectPath, payload.RepositoryURL, "", "", "", false)
+	err = handler.GitService.CloneRepository(projectPath, payload.RepositoryURL, "", "", "", "", "", false, gittypes.CloneOptions{})
 	if err != nil {
 		return httperror.InternalServerError("Unable to clone git repository", err)
 	}
@@ -15,11 +15,11 @@ import (
 // @security ApiKeyAuth
 // @security jwt
 // @produce json
-// @success 200 {object} listResponse "Success"
+// @success 200 {object} templatesources.Templates "Success"
 // @failure 500 "Server error"
 // @router /templates [get]
 func (handler *Handler) templateList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
-	templates, httpErr := handler.fetchTemplates()
+	templates, httpErr := handler.fetchTemplates(r)
 	if httpErr != nil {
 		return httpErr
 	}
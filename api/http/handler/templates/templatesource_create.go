@@ -0,0 +1,48 @@
+package templates
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+// @id TemplateSourceCreate
+// @summary Create a template source
+// @description Add a file of templates fetched from a URL or from a git repository. The templates of all the sources
+// @description visible to a user are merged, a template replaces the templates with the same title of the sources with a lower priority.
+// @description While no source is defined, the templates are fetched from the templates URL of the settings.
+// @description **Access policy**: administrator
+// @tags templates
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body templateSourcePayload true "Template source details"
+// @success 200 {object} portainer.TemplateSource
+// @failure 400
+// @failure 500
+// @router /templates/sources [post]
+func (handler *Handler) templateSourceCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload templateSourcePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	source := &portainer.TemplateSource{}
+	err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := applyPayload(tx, source, &payload); err != nil {
+			return err
+		}
+
+		if err := tx.TemplateSource().Create(source); err != nil {
+			return httperror.InternalServerError("Unable to persist the template source inside the database", err)
+		}
+
+		return nil
+	})
+
+	return sourceTxResponse(w, source, err)
+}
@@ -0,0 +1,52 @@
+package templates
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id TemplateSourceDelete
+// @summary Delete a template source
+// @description **Access policy**: administrator
+// @tags templates
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Template source identifier"
+// @success 204
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /templates/sources/{id} [delete]
+func (handler *Handler) templateSourceDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	sourceID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid template source identifier route variable", err)
+	}
+
+	id := portainer.TemplateSourceID(sourceID)
+
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if _, err := tx.TemplateSource().Read(id); tx.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a template source with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a template source with the specified identifier inside the database", err)
+		}
+
+		if err := tx.TemplateSource().Delete(id); err != nil {
+			return httperror.InternalServerError("Unable to remove the template source from the database", err)
+		}
+
+		return nil
+	}); err != nil {
+		return sourceTxResponse(w, nil, err)
+	}
+
+	handler.TemplateSources.Invalidate(id)
+
+	return response.Empty(w)
+}
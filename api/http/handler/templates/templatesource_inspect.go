@@ -0,0 +1,39 @@
+package templates
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id TemplateSourceInspect
+// @summary Inspect a template source
+// @description **Access policy**: administrator
+// @tags templates
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Template source identifier"
+// @success 200 {object} portainer.TemplateSource
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /templates/sources/{id} [get]
+func (handler *Handler) templateSourceInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	sourceID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid template source identifier route variable", err)
+	}
+
+	source, err := handler.DataStore.TemplateSource().Read(portainer.TemplateSourceID(sourceID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a template source with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a template source with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, hideSecrets(*source))
+}
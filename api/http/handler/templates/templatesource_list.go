@@ -0,0 +1,35 @@
+package templates
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api/internal/templatesources"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id TemplateSourceList
+// @summary List the template sources
+// @description The sources are listed in the order their templates are merged, the highest priority first.
+// @description **Access policy**: administrator
+// @tags templates
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.TemplateSource
+// @failure 500
+// @router /templates/sources [get]
+func (handler *Handler) templateSourceList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	sources, err := handler.DataStore.TemplateSource().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve template sources from the database", err)
+	}
+
+	templatesources.SortByPriority(sources)
+
+	for i := range sources {
+		sources[i] = hideSecrets(sources[i])
+	}
+
+	return response.JSON(w, sources)
+}
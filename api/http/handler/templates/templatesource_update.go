@@ -0,0 +1,69 @@
+package templates
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+// @id TemplateSourceUpdate
+// @summary Update a template source
+// @description The templates of the source are fetched again on the next request.
+// @description **Access policy**: administrator
+// @tags templates
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Template source identifier"
+// @param body body templateSourcePayload true "Template source details"
+// @success 200 {object} portainer.TemplateSource
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /templates/sources/{id} [put]
+func (handler *Handler) templateSourceUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	sourceID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid template source identifier route variable", err)
+	}
+
+	var payload templateSourcePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var source *portainer.TemplateSource
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		source, err = tx.TemplateSource().Read(portainer.TemplateSourceID(sourceID))
+		if tx.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a template source with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a template source with the specified identifier inside the database", err)
+		}
+
+		if err := applyPayload(tx, source, &payload); err != nil {
+			return err
+		}
+
+		// The status of the last refresh describes the previous configuration
+		source.LastRefresh = 0
+		source.LastChecksum = ""
+		source.LastError = ""
+
+		if err := tx.TemplateSource().Update(source.ID, source); err != nil {
+			return httperror.InternalServerError("Unable to persist template source changes inside the database", err)
+		}
+
+		return nil
+	})
+
+	if err == nil {
+		handler.TemplateSources.Invalidate(source.ID)
+	}
+
+	return sourceTxResponse(w, source, err)
+}
@@ -4,38 +4,27 @@ import (
 	"net/http"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/templatesources"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
-	"github.com/segmentio/encoding/json"
 )
 
-type listResponse struct {
-	Version   string               `json:"version"`
-	Templates []portainer.Template `json:"templates"`
-}
-
-func (handler *Handler) fetchTemplates() (*listResponse, *httperror.HandlerError) {
-	settings, err := handler.DataStore.Settings().Settings()
+// fetchTemplates returns the merged templates of the sources visible to the user of the request
+func (handler *Handler) fetchTemplates(r *http.Request) (*templatesources.Templates, *httperror.HandlerError) {
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
 	if err != nil {
-		return nil, httperror.InternalServerError("Unable to retrieve settings from the database", err)
+		return nil, httperror.InternalServerError("Unable to retrieve info from request context", err)
 	}
 
-	templatesURL := settings.TemplatesURL
-	if templatesURL == "" {
-		templatesURL = portainer.DefaultTemplatesURL
+	teamIDs := make([]portainer.TeamID, 0, len(securityContext.UserMemberships))
+	for _, membership := range securityContext.UserMemberships {
+		teamIDs = append(teamIDs, membership.TeamID)
 	}
 
-	resp, err := http.Get(templatesURL)
+	templates, err := handler.TemplateSources.Templates(securityContext.IsAdmin, teamIDs)
 	if err != nil {
 		return nil, httperror.InternalServerError("Unable to retrieve templates via the network", err)
 	}
-	defer resp.Body.Close()
-
-	var body *listResponse
-	err = json.NewDecoder(resp.Body).Decode(&body)
-	if err != nil {
-		return nil, httperror.InternalServerError("Unable to parse template file", err)
-	}
-
-	return body, nil
 
+	return templates, nil
 }
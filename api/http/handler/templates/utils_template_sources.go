@@ -0,0 +1,145 @@
+package templates
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	gittypes "github.com/portainer/portainer/api/git/types"
+	"github.com/portainer/portainer/api/internal/templatesources"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type templateSourcePayload struct {
+	Name string `example:"my-templates" validate:"required"`
+	// URL of the templates file, leave empty when the file is stored in a git repository
+	URL string `example:"https://example.com/templates.json"`
+	// URL of the git repository storing the templates file, leave empty when the file is fetched from URL
+	RepositoryURL string `example:"https://github.com/myorg/templates"`
+	// Reference name of the git repository, the default branch is used when empty
+	RepositoryReferenceName string `example:"refs/heads/main"`
+	// Path of the templates file inside the git repository, defaults to templates.json
+	FilePathInRepository string `example:"templates.json"`
+	// Use basic authentication to clone the git repository
+	RepositoryAuthentication bool   `example:"true"`
+	RepositoryUsername       string `example:"myGitUsername"`
+	// Password used to clone the git repository, the current password is kept when empty on update
+	RepositoryPassword string `example:"myGitPassword"`
+	// Skip the verification of the TLS certificate of the git repository
+	TLSSkipVerify bool `example:"false"`
+	// SHA-256 checksum the content of the templates file must match, in hexadecimal. Not checked when empty
+	Checksum string `example:"5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"`
+	// The templates of the sources with a higher priority replace the templates with the same title of the other sources
+	Priority int `example:"10"`
+	// Teams allowed to see the templates of the source, the source is visible to every user when empty
+	TeamIDs []portainer.TeamID `example:"1"`
+}
+
+func (payload *templateSourcePayload) Validate(r *http.Request) error {
+	if payload.Name == "" {
+		return errors.New("invalid template source name")
+	}
+
+	if (payload.URL == "") == (payload.RepositoryURL == "") {
+		return errors.New("either the URL of the templates file or the URL of a git repository must be specified")
+	}
+
+	if payload.URL != "" {
+		if err := validateURL(payload.URL); err != nil {
+			return err
+		}
+	}
+
+	if payload.RepositoryAuthentication && payload.RepositoryUsername == "" {
+		return errors.New("invalid repository credentials, a username is required")
+	}
+
+	return templatesources.ValidateChecksum(payload.Checksum)
+}
+
+func validateURL(rawURL string) error {
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("invalid templates URL, it must be an http or https URL")
+	}
+
+	return nil
+}
+
+// applyPayload sets the configuration of the payload on the source. The password of the git repository
+// is kept when the payload leaves it empty and targets the same repository
+func applyPayload(tx dataservices.DataStoreTx, source *portainer.TemplateSource, payload *templateSourcePayload) error {
+	for _, teamID := range payload.TeamIDs {
+		if _, err := tx.Team().Read(teamID); tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find a team with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a team with the specified identifier inside the database", err)
+		}
+	}
+
+	previous := source.GitConfig
+
+	source.Name = payload.Name
+	source.URL = payload.URL
+	source.GitConfig = nil
+	source.Checksum = payload.Checksum
+	source.Priority = payload.Priority
+	source.TeamIDs = payload.TeamIDs
+
+	if payload.RepositoryURL == "" {
+		return nil
+	}
+
+	source.GitConfig = &gittypes.RepoConfig{
+		URL:            payload.RepositoryURL,
+		ReferenceName:  payload.RepositoryReferenceName,
+		ConfigFilePath: payload.FilePathInRepository,
+		TLSSkipVerify:  payload.TLSSkipVerify,
+	}
+
+	if !payload.RepositoryAuthentication {
+		return nil
+	}
+
+	source.GitConfig.Authentication = &gittypes.GitAuthentication{
+		Username: payload.RepositoryUsername,
+		Password: payload.RepositoryPassword,
+	}
+
+	if payload.RepositoryPassword == "" && previous != nil && previous.URL == payload.RepositoryURL && previous.Authentication != nil {
+		source.GitConfig.Authentication.Password = previous.Authentication.Password
+	}
+
+	if source.GitConfig.Authentication.Password == "" {
+		return httperror.BadRequest("Invalid request payload", errors.New("invalid repository credentials, a password is required"))
+	}
+
+	return nil
+}
+
+// hideSecrets returns a copy of the source without the credentials of its git repository
+func hideSecrets(source portainer.TemplateSource) portainer.TemplateSource {
+	if source.GitConfig != nil && source.GitConfig.Authentication != nil {
+		gitConfig := *source.GitConfig
+		gitConfig.Authentication = &gittypes.GitAuthentication{Username: source.GitConfig.Authentication.Username}
+		source.GitConfig = &gitConfig
+	}
+
+	return source
+}
+
+func sourceTxResponse(w http.ResponseWriter, source *portainer.TemplateSource, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, hideSecrets(*source))
+}
@@ -0,0 +1,50 @@
+package uioverrides
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler is the HTTP handler used to handle the UI asset overrides.
+type Handler struct {
+	*mux.Router
+	FileService portainer.FileService
+}
+
+// NewHandler returns a new Handler
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+
+	h.Handle("/ui_overrides",
+		bouncer.PublicAccess(httperror.LoggerHandler(h.uiOverridesInspect))).Methods(http.MethodGet)
+	h.Handle("/ui_overrides",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.uiOverridesUpdate))).Methods(http.MethodPut)
+	h.Handle("/ui_overrides",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.uiOverridesDelete))).Methods(http.MethodDelete)
+	h.Handle("/ui_overrides/"+filesystem.UICustomCSSFilename,
+		bouncer.PublicAccess(httperror.LoggerHandler(h.uiOverridesCustomCSS))).Methods(http.MethodGet)
+	h.Handle("/ui_overrides/"+filesystem.UIFaviconFilename,
+		bouncer.PublicAccess(httperror.LoggerHandler(h.uiOverridesFavicon))).Methods(http.MethodGet)
+
+	return h
+}
+
+// readOverride returns the content of an override, or nil when it is not set
+func (handler *Handler) readOverride(fileName string) ([]byte, error) {
+	overridesPath := handler.FileService.GetUIOverridesPath()
+
+	exists, err := handler.FileService.FileExists(filesystem.JoinPaths(overridesPath, fileName))
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	return handler.FileService.GetFileContent(overridesPath, fileName)
+}
@@ -0,0 +1,64 @@
+package uioverrides
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/portainer/portainer/api/filesystem"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+)
+
+// @id UIOverridesCustomCSS
+// @summary Retrieve the custom stylesheet
+// @description Serve the custom stylesheet applied on top of the UI theme. An empty stylesheet is served when none is set,
+// @description so that the UI can always reference this path.
+// @description **Access policy**: public
+// @tags ui_overrides
+// @produce text/css
+// @success 200 "Success"
+// @failure 500 "Server error"
+// @router /ui_overrides/custom.css [get]
+func (handler *Handler) uiOverridesCustomCSS(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	content, err := handler.readOverride(filesystem.UICustomCSSFilename)
+	if err != nil {
+		return httperror.InternalServerError("Unable to read the custom stylesheet", err)
+	}
+
+	writeAsset(w, "text/css; charset=utf-8", content)
+
+	return nil
+}
+
+// @id UIOverridesFavicon
+// @summary Retrieve the favicon override
+// @description **Access policy**: public
+// @tags ui_overrides
+// @produce image/png
+// @produce image/x-icon
+// @success 200 "Success"
+// @failure 404 "No favicon override is set"
+// @failure 500 "Server error"
+// @router /ui_overrides/favicon [get]
+func (handler *Handler) uiOverridesFavicon(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	content, err := handler.readOverride(filesystem.UIFaviconFilename)
+	if err != nil {
+		return httperror.InternalServerError("Unable to read the favicon", err)
+	}
+
+	if content == nil {
+		return httperror.NotFound("No favicon override is set", errors.New("favicon not found"))
+	}
+
+	writeAsset(w, http.DetectContentType(content), content)
+
+	return nil
+}
+
+// writeAsset writes an override, which can change at any time and must not be cached by the browsers
+func writeAsset(w http.ResponseWriter, contentType string, content []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
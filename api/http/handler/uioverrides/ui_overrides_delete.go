@@ -0,0 +1,26 @@
+package uioverrides
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id UIOverridesDelete
+// @summary Remove the UI asset overrides
+// @description Remove all the UI asset overrides, restoring the assets bundled with the frontend.
+// @description **Access policy**: administrator
+// @tags ui_overrides
+// @security ApiKeyAuth
+// @security jwt
+// @success 204 "Success"
+// @failure 500 "Server error"
+// @router /ui_overrides [delete]
+func (handler *Handler) uiOverridesDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	if err := handler.FileService.RemoveDirectory(handler.FileService.GetUIOverridesPath()); err != nil {
+		return httperror.InternalServerError("Unable to remove the UI asset overrides from disk", err)
+	}
+
+	return response.Empty(w)
+}
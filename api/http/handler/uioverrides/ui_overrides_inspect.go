@@ -0,0 +1,52 @@
+package uioverrides
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api/filesystem"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+type uiOverridesInspectResponse struct {
+	// Whether a custom stylesheet is served under /api/ui_overrides/custom.css
+	CustomCSS bool `json:"CustomCSS" example:"true"`
+	// Whether a favicon is served under /api/ui_overrides/favicon
+	Favicon bool `json:"Favicon" example:"false"`
+	// Text displayed on the login page
+	LoginText string `json:"LoginText" example:"Authorized personnel only"`
+}
+
+// @id UIOverridesInspect
+// @summary Retrieve the UI asset overrides
+// @description List the UI asset overrides set on this instance, along with the text displayed on the login page.
+// @description **Access policy**: public
+// @tags ui_overrides
+// @produce json
+// @success 200 {object} uiOverridesInspectResponse "Success"
+// @failure 500 "Server error"
+// @router /ui_overrides [get]
+func (handler *Handler) uiOverridesInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	overridesPath := handler.FileService.GetUIOverridesPath()
+
+	customCSS, err := handler.FileService.FileExists(filesystem.JoinPaths(overridesPath, filesystem.UICustomCSSFilename))
+	if err != nil {
+		return httperror.InternalServerError("Unable to check the custom stylesheet", err)
+	}
+
+	favicon, err := handler.FileService.FileExists(filesystem.JoinPaths(overridesPath, filesystem.UIFaviconFilename))
+	if err != nil {
+		return httperror.InternalServerError("Unable to check the favicon", err)
+	}
+
+	loginText, err := handler.readOverride(filesystem.UILoginTextFilename)
+	if err != nil {
+		return httperror.InternalServerError("Unable to read the login page text", err)
+	}
+
+	return response.JSON(w, uiOverridesInspectResponse{
+		CustomCSS: customCSS,
+		Favicon:   favicon,
+		LoginText: string(loginText),
+	})
+}
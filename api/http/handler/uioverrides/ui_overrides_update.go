@@ -0,0 +1,133 @@
+package uioverrides
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/portainer/portainer/api/filesystem"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+const (
+	maxCustomCSSSize = 1 << 20
+	maxFaviconSize   = 512 << 10
+	maxLoginTextSize = 4 << 10
+)
+
+var faviconContentTypes = []string{"image/png", "image/x-icon", "image/vnd.microsoft.icon"}
+
+type uiOverridesUpdatePayload struct {
+	CustomCSS []byte
+	Favicon   []byte
+	LoginText *string
+}
+
+func (payload *uiOverridesUpdatePayload) Validate(r *http.Request) error {
+	customCSS, err := retrieveOptionalFile(r, "CustomCSS")
+	if err != nil {
+		return errors.New("invalid custom stylesheet. Ensure that the file is uploaded correctly")
+	}
+
+	if len(customCSS) > maxCustomCSSSize {
+		return errors.New("the custom stylesheet must not exceed 1MB")
+	}
+	payload.CustomCSS = customCSS
+
+	favicon, err := retrieveOptionalFile(r, "Favicon")
+	if err != nil {
+		return errors.New("invalid favicon. Ensure that the file is uploaded correctly")
+	}
+
+	if favicon != nil {
+		if len(favicon) > maxFaviconSize {
+			return errors.New("the favicon must not exceed 512KB")
+		}
+
+		if !slices.Contains(faviconContentTypes, http.DetectContentType(favicon)) {
+			return errors.New("the favicon must be a PNG or ICO image")
+		}
+	}
+	payload.Favicon = favicon
+
+	// An empty login text is accepted and clears the previous one
+	if values, ok := r.MultipartForm.Value["LoginText"]; ok && len(values) > 0 {
+		if len(values[0]) > maxLoginTextSize {
+			return errors.New("the login page text must not exceed 4KB")
+		}
+		payload.LoginText = &values[0]
+	}
+
+	if payload.CustomCSS == nil && payload.Favicon == nil && payload.LoginText == nil {
+		return errors.New("no override has been provided")
+	}
+
+	return nil
+}
+
+// retrieveOptionalFile returns the content of an uploaded file, or nil when the file is not part of the request
+func retrieveOptionalFile(r *http.Request, name string) ([]byte, error) {
+	content, _, err := request.RetrieveMultiPartFormFile(r, name)
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if content == nil {
+		content = []byte{}
+	}
+
+	return content, nil
+}
+
+// @id UIOverridesUpdate
+// @summary Upload UI asset overrides
+// @description Upload an override bundle applied on top of the UI theme, without rebuilding the frontend.
+// @description Only the overrides part of the request are replaced.
+// @description **Access policy**: administrator
+// @tags ui_overrides
+// @security ApiKeyAuth
+// @security jwt
+// @accept multipart/form-data
+// @produce json
+// @param CustomCSS formData file false "Stylesheet applied on top of the UI theme"
+// @param Favicon formData file false "PNG or ICO favicon"
+// @param LoginText formData string false "Text displayed on the login page, an empty value clears it"
+// @success 200 {object} uiOverridesInspectResponse "Success"
+// @failure 400 "Invalid request"
+// @failure 500 "Server error"
+// @router /ui_overrides [put]
+func (handler *Handler) uiOverridesUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	if err := r.ParseMultipartForm(maxCustomCSSSize + maxFaviconSize + maxLoginTextSize); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	payload := &uiOverridesUpdatePayload{}
+	if err := payload.Validate(r); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	if payload.CustomCSS != nil {
+		if _, err := handler.FileService.StoreUIOverrideFileFromBytes(filesystem.UICustomCSSFilename, payload.CustomCSS); err != nil {
+			return httperror.InternalServerError("Unable to persist the custom stylesheet on disk", err)
+		}
+	}
+
+	if payload.Favicon != nil {
+		if _, err := handler.FileService.StoreUIOverrideFileFromBytes(filesystem.UIFaviconFilename, payload.Favicon); err != nil {
+			return httperror.InternalServerError("Unable to persist the favicon on disk", err)
+		}
+	}
+
+	if payload.LoginText != nil {
+		if _, err := handler.FileService.StoreUIOverrideFileFromBytes(filesystem.UILoginTextFilename, []byte(*payload.LoginText)); err != nil {
+			return httperror.InternalServerError("Unable to persist the login page text on disk", err)
+		}
+	}
+
+	return handler.uiOverridesInspect(w, r)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/security"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
 
 	"github.com/gorilla/mux"
 )
@@ -19,12 +20,10 @@ var (
 	errAdminCannotRemoveSelf      = errors.New("Cannot remove your own user account. Contact another administrator")
 	errCannotRemoveLastLocalAdmin = errors.New("Cannot remove the last local administrator account")
 	errCryptoHashFailure          = errors.New("Unable to hash data")
+	errServiceAccountAdmin        = errors.New("A service account cannot be an administrator")
+	errServiceAccountPassword     = errors.New("A service account can only authenticate with API keys")
 )
 
-func hideFields(user *portainer.User) {
-	user.Password = ""
-}
-
 // Handler is the HTTP handler used to handle user operations.
 type Handler struct {
 	*mux.Router
@@ -72,6 +71,11 @@ func NewHandler(bouncer security.BouncerService, rateLimiter *security.RateLimit
 	restrictedRouter.Handle("/users/{id}/tokens", httperror.LoggerHandler(h.userGetAccessTokens)).Methods(http.MethodGet)
 	restrictedRouter.Handle("/users/{id}/tokens", rateLimiter.LimitAccess(httperror.LoggerHandler(h.userCreateAccessToken))).Methods(http.MethodPost)
 	restrictedRouter.Handle("/users/{id}/tokens/{keyID}", httperror.LoggerHandler(h.userRemoveAccessToken)).Methods(http.MethodDelete)
+	restrictedRouter.Handle("/users/{id}/sessions", httperror.LoggerHandler(h.userGetSessions)).Methods(http.MethodGet)
+	adminRouter.Handle("/users/{id}/sessions", httperror.LoggerHandler(h.userRevokeSessions)).Methods(http.MethodDelete)
+	restrictedRouter.Handle("/users/{id}/sessions/{sessionID}", httperror.LoggerHandler(h.userRevokeSession)).Methods(http.MethodDelete)
+	authenticatedRouter.Handle("/users/{id}/settings", httperror.LoggerHandler(h.userSettingsInspect)).Methods(http.MethodGet)
+	authenticatedRouter.Handle("/users/{id}/settings", httperror.LoggerHandler(h.userSettingsUpdate)).Methods(http.MethodPut)
 	restrictedRouter.Handle("/users/{id}/memberships", httperror.LoggerHandler(h.userMemberships)).Methods(http.MethodGet)
 	authenticatedRouter.Handle("/users/{id}/passwd", rateLimiter.LimitAccess(httperror.LoggerHandler(h.userUpdatePassword))).Methods(http.MethodPut)
 
@@ -85,3 +89,16 @@ func NewHandler(bouncer security.BouncerService, rateLimiter *security.RateLimit
 
 	return h
 }
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
@@ -15,8 +15,8 @@ import (
 type userCreatePayload struct {
 	Username string `validate:"required" example:"bob"`
 	Password string `validate:"required" example:"cg9Wgky3"`
-	// User role (1 for administrator account and 2 for regular account)
-	Role int `validate:"required" enums:"1,2" example:"2"`
+	// User role (1 for administrator account, 2 for regular account and 3 for read-only account)
+	Role int `validate:"required" enums:"1,2,3" example:"2"`
 }
 
 func (payload *userCreatePayload) Validate(r *http.Request) error {
@@ -24,8 +24,8 @@ func (payload *userCreatePayload) Validate(r *http.Request) error {
 		return errors.New("Invalid username. Must not contain any whitespace")
 	}
 
-	if payload.Role != 1 && payload.Role != 2 {
-		return errors.New("Invalid role value. Value must be one of: 1 (administrator) or 2 (regular user)")
+	if payload.Role != 1 && payload.Role != 2 && payload.Role != 3 {
+		return errors.New("Invalid role value. Value must be one of: 1 (administrator), 2 (regular user) or 3 (read-only user)")
 	}
 
 	return nil
@@ -114,7 +114,5 @@ func (handler *Handler) createUser(tx dataservices.DataStoreTx, payload userCrea
 		return nil, httperror.InternalServerError("Unable to persist user inside the database", err)
 	}
 
-	hideFields(user)
-
 	return user, nil
 }
@@ -94,6 +94,21 @@ func (handler *Handler) deleteUser(w http.ResponseWriter, user *portainer.User)
 		return httperror.InternalServerError("Unable to remove user memberships from the database", err)
 	}
 
+	if err := handler.DataStore.UserSettings().Delete(user.ID); err != nil && !handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.InternalServerError("Unable to remove user settings from the database", err)
+	}
+
+	sessions, err := handler.DataStore.UserSession().UserSessionsByUserID(user.ID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve user sessions from the database", err)
+	}
+	for _, session := range sessions {
+		err = handler.DataStore.UserSession().Delete(session.ID)
+		if err != nil {
+			return httperror.InternalServerError("Unable to remove user session from the database", err)
+		}
+	}
+
 	// Remove all of the users persisted API keys
 	apiKeys, err := handler.apiKeyService.GetAPIKeys(user.ID)
 	if err != nil {
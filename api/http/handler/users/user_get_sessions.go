@@ -0,0 +1,93 @@
+package users
+
+import (
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+// @id UserGetSessions
+// @summary Get the active sessions of a user
+// @description Gets the sessions opened by a user that are neither expired nor revoked.
+// @description Only the calling user or admin can retrieve the sessions.
+// @description **Access policy**: authenticated
+// @tags users
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "User identifier"
+// @success 200 {array} portainer.UserSession "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "User not found"
+// @failure 500 "Server error"
+// @router /users/{id}/sessions [get]
+func (handler *Handler) userGetSessions(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid user identifier route variable", err)
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve user authentication token", err)
+	}
+
+	if tokenData.Role != portainer.AdministratorRole && tokenData.ID != portainer.UserID(userID) {
+		return httperror.Forbidden("Permission denied to get user sessions", httperrors.ErrUnauthorized)
+	}
+
+	var sessions []portainer.UserSession
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if _, err := tx.User().Read(portainer.UserID(userID)); err != nil {
+			if tx.IsErrObjectNotFound(err) {
+				return httperror.NotFound("Unable to find a user with the specified identifier inside the database", err)
+			}
+
+			return httperror.InternalServerError("Unable to find a user with the specified identifier inside the database", err)
+		}
+
+		sessions, err = activeSessions(tx, portainer.UserID(userID))
+		if err != nil {
+			return httperror.InternalServerError("Unable to retrieve the user sessions from the database", err)
+		}
+
+		return nil
+	})
+
+	return txResponse(w, sessions, err)
+}
+
+// activeSessions returns the sessions of a user that are neither expired nor revoked,
+// the expired sessions are removed along the way
+func activeSessions(tx dataservices.DataStoreTx, userID portainer.UserID) ([]portainer.UserSession, error) {
+	sessions, err := tx.UserSession().UserSessionsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	active := make([]portainer.UserSession, 0, len(sessions))
+
+	for _, session := range sessions {
+		if session.ExpiresAt != 0 && session.ExpiresAt < now {
+			if err := tx.UserSession().Delete(session.ID); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		if !session.Revoked {
+			active = append(active, session)
+		}
+	}
+
+	return active, nil
+}
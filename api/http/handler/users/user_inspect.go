@@ -49,6 +49,5 @@ func (handler *Handler) userInspect(w http.ResponseWriter, r *http.Request) *htt
 		return httperror.InternalServerError("Unable to find a user with the specified identifier inside the database", err)
 	}
 
-	hideFields(user)
 	return response.JSON(w, user)
 }
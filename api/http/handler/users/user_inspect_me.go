@@ -47,7 +47,6 @@ func (handler *Handler) userInspectMe(w http.ResponseWriter, r *http.Request) *h
 		return httperror.InternalServerError("Unable to find a user with the specified identifier inside the database", err)
 	}
 
-	hideFields(user)
 	return response.JSON(
 		w,
 		&CurrentUserInspectResponse{
@@ -2,6 +2,7 @@ package users
 
 import (
 	"net/http"
+	"slices"
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/http/security"
@@ -47,6 +48,9 @@ func (handler *Handler) userList(w http.ResponseWriter, r *http.Request) *httper
 		return httperror.InternalServerError("Unable to retrieve users from the database", err)
 	}
 
+	// Service accounts are listed separately
+	users = slices.DeleteFunc(users, func(user portainer.User) bool { return user.ServiceAccount })
+
 	availableUsers := security.FilterUsers(users, securityContext)
 
 	endpointID, _ := request.RetrieveNumericQueryParameter(r, "environmentId", true)
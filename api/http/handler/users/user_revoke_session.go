@@ -0,0 +1,79 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id UserRevokeSession
+// @summary Revoke a session of a user
+// @description Revoke a session of a user, the JWT of the session can no longer be used.
+// @description Only the calling user or admin can revoke a session.
+// @description **Access policy**: authenticated
+// @tags users
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "User identifier"
+// @param sessionID path int true "Session identifier"
+// @success 204 "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "Not found"
+// @failure 500 "Server error"
+// @router /users/{id}/sessions/{sessionID} [delete]
+func (handler *Handler) userRevokeSession(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid user identifier route variable", err)
+	}
+
+	sessionID, err := request.RetrieveNumericRouteVariableValue(r, "sessionID")
+	if err != nil {
+		return httperror.BadRequest("Invalid session identifier route variable", err)
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve user authentication token", err)
+	}
+
+	if tokenData.Role != portainer.AdministratorRole && tokenData.ID != portainer.UserID(userID) {
+		return httperror.Forbidden("Permission denied to revoke user sessions", httperrors.ErrUnauthorized)
+	}
+
+	if _, err := handler.DataStore.User().Read(portainer.UserID(userID)); err != nil {
+		if handler.DataStore.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a user with the specified identifier inside the database", err)
+		}
+
+		return httperror.InternalServerError("Unable to find a user with the specified identifier inside the database", err)
+	}
+
+	session, err := handler.DataStore.UserSession().Read(portainer.UserSessionID(sessionID))
+	if err != nil {
+		if handler.DataStore.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a session with the specified identifier inside the database", err)
+		}
+
+		return httperror.InternalServerError("Unable to find a session with the specified identifier inside the database", err)
+	}
+
+	if session.UserID != portainer.UserID(userID) {
+		return httperror.NotFound("Unable to find a session with the specified identifier inside the database", errors.New("the session belongs to another user"))
+	}
+
+	if !session.Revoked {
+		if err := handler.bouncer.RevokeSession(session); err != nil {
+			return httperror.InternalServerError("Unable to revoke the user session", err)
+		}
+	}
+
+	return response.Empty(w)
+}
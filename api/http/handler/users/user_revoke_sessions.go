@@ -0,0 +1,66 @@
+package users
+
+import (
+	"net/http"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id UserRevokeSessions
+// @summary Force the logout of a user
+// @description Revoke all the sessions of a user, every JWT issued to the user so far can no longer be used.
+// @description The API keys of the user are left untouched.
+// @description **Access policy**: administrator
+// @tags users
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "User identifier"
+// @success 204 "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "User not found"
+// @failure 500 "Server error"
+// @router /users/{id}/sessions [delete]
+func (handler *Handler) userRevokeSessions(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid user identifier route variable", err)
+	}
+
+	user, err := handler.DataStore.User().Read(portainer.UserID(userID))
+	if err != nil {
+		if handler.DataStore.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a user with the specified identifier inside the database", err)
+		}
+
+		return httperror.InternalServerError("Unable to find a user with the specified identifier inside the database", err)
+	}
+
+	// Invalidates the JWTs issued before now, including the ones without a session
+	user.TokenIssueAt = time.Now().Unix()
+	if err := handler.DataStore.User().Update(user.ID, user); err != nil {
+		return httperror.InternalServerError("Unable to persist user changes inside the database", err)
+	}
+
+	sessions, err := handler.DataStore.UserSession().UserSessionsByUserID(user.ID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the user sessions from the database", err)
+	}
+
+	// The JWTs issued during the current second are still accepted by the check above
+	for i := range sessions {
+		if sessions[i].Revoked {
+			continue
+		}
+
+		if err := handler.bouncer.RevokeSession(&sessions[i]); err != nil {
+			return httperror.InternalServerError("Unable to revoke the user session", err)
+		}
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,122 @@
+package users
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/apikey"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+	"github.com/portainer/portainer/api/jwt"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_userSessions(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	adminUser := &portainer.User{ID: 1, Username: "admin", Role: portainer.AdministratorRole}
+	require.NoError(t, store.User().Create(adminUser))
+
+	user := &portainer.User{ID: 2, Username: "standard", Role: portainer.StandardUserRole}
+	require.NoError(t, store.User().Create(user))
+
+	jwtService, err := jwt.NewService("1h", store)
+	require.NoError(t, err)
+	apiKeyService := apikey.NewAPIKeyService(store.APIKeyRepository(), store.User())
+	requestBouncer := security.NewRequestBouncer(store, jwtService, apiKeyService)
+	rateLimiter := security.NewRateLimiter(10, 1*time.Second, 1*time.Hour)
+	passwordChecker := security.NewPasswordStrengthChecker(store.SettingsService)
+
+	h := NewHandler(requestBouncer, rateLimiter, apiKeyService, passwordChecker)
+	h.DataStore = store
+
+	login := func(u *portainer.User) (string, *portainer.UserSession) {
+		token, _, err := jwtService.GenerateToken(&portainer.TokenData{ID: u.ID, Username: u.Username, Role: u.Role})
+		require.NoError(t, err)
+
+		_, tokenID, expiresAt, err := jwtService.ParseAndVerifyToken(token)
+		require.NoError(t, err)
+
+		session := &portainer.UserSession{UserID: u.ID, TokenID: tokenID, IssuedAt: time.Now().Unix(), ExpiresAt: expiresAt.Unix(), IP: "10.0.0.12"}
+		require.NoError(t, store.UserSession().Create(session))
+
+		return token, session
+	}
+
+	do := func(method, url, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, nil)
+		testhelpers.AddTestSecurityCookie(req, token)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	adminJWT, _ := login(adminUser)
+	userJWT, userSession := login(user)
+	otherJWT, _ := login(user)
+
+	// The expired sessions are removed when the sessions are listed
+	expired := &portainer.UserSession{UserID: user.ID, TokenID: "expired", ExpiresAt: time.Now().Add(-time.Hour).Unix()}
+	require.NoError(t, store.UserSession().Create(expired))
+
+	t.Run("user can list their sessions", func(t *testing.T) {
+		rr := do(http.MethodGet, "/users/2/sessions", userJWT)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var sessions []portainer.UserSession
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&sessions))
+		assert.Len(t, sessions, 2)
+
+		_, err := store.UserSession().Read(expired.ID)
+		assert.True(t, store.IsErrObjectNotFound(err))
+	})
+
+	t.Run("user cannot list the sessions of another user", func(t *testing.T) {
+		rr := do(http.MethodGet, "/users/1/sessions", userJWT)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("admin can revoke a session", func(t *testing.T) {
+		rr := do(http.MethodDelete, "/users/2/sessions/"+strconv.Itoa(int(userSession.ID)), adminJWT)
+		require.Equal(t, http.StatusNoContent, rr.Code)
+
+		rr = do(http.MethodGet, "/users/2/sessions", userJWT)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+		rr = do(http.MethodGet, "/users/2/sessions", otherJWT)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var sessions []portainer.UserSession
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&sessions))
+		assert.Len(t, sessions, 1)
+	})
+
+	t.Run("standard user cannot force the logout of a user", func(t *testing.T) {
+		rr := do(http.MethodDelete, "/users/2/sessions", otherJWT)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("admin can force the logout of a user", func(t *testing.T) {
+		rr := do(http.MethodDelete, "/users/2/sessions", adminJWT)
+		require.Equal(t, http.StatusNoContent, rr.Code)
+
+		rr = do(http.MethodGet, "/users/2/sessions", otherJWT)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+		sessions, err := store.UserSession().UserSessionsByUserID(user.ID)
+		require.NoError(t, err)
+		for _, session := range sessions {
+			assert.True(t, session.Revoked)
+		}
+	})
+}
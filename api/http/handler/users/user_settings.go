@@ -0,0 +1,216 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+const maxItemsPerPage = 1000
+
+type userSettingsUpdatePayload struct {
+	// Environment(Endpoint) opened after the login, 0 for the home page
+	DefaultEndpointID *portainer.EndpointID `json:"DefaultEndpointId" example:"1"`
+	// Number of items per page of the tables, 0 for the default of the UI
+	ItemsPerPage *int `json:"ItemsPerPage" example:"25"`
+	// Hidden columns, by table identifier. Replaces the hidden columns when specified
+	HiddenColumns map[string][]string `json:"HiddenColumns"`
+	// Favorite environments(endpoints). Replaces the favorite environments when specified
+	FavoriteEndpoints []portainer.EndpointID `json:"FavoriteEndpoints"`
+}
+
+func (payload *userSettingsUpdatePayload) Validate(r *http.Request) error {
+	if payload.ItemsPerPage != nil && (*payload.ItemsPerPage < 0 || *payload.ItemsPerPage > maxItemsPerPage) {
+		return errors.New("invalid items per page. Must be between 0 and 1000")
+	}
+
+	for table := range payload.HiddenColumns {
+		if strings.TrimSpace(table) == "" {
+			return errors.New("invalid hidden columns. Table identifiers must not be empty")
+		}
+	}
+
+	return nil
+}
+
+// @id UserSettingsInspect
+// @summary Inspect the settings of a user
+// @description Retrieve the UI preferences of a user, the default settings are returned when the user has none.
+// @description A regular user account can only inspect their own settings.
+// @description **Access policy**: authenticated
+// @tags users
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "User identifier"
+// @success 200 {object} portainer.UserSettings "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "User not found"
+// @failure 500 "Server error"
+// @router /users/{id}/settings [get]
+func (handler *Handler) userSettingsInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userID, httpErr := retrieveSettingsUserID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	var settings *portainer.UserSettings
+	err := handler.DataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		var err error
+		settings, err = readUserSettings(tx, userID)
+
+		return err
+	})
+
+	return txResponse(w, settings, err)
+}
+
+// @id UserSettingsUpdate
+// @summary Update the settings of a user
+// @description Update the UI preferences of a user. The fields which are not specified are left unchanged.
+// @description A regular user account can only update their own settings, an administrator can update the settings
+// @description of any user, to provision them for example.
+// @description **Access policy**: authenticated
+// @tags users
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "User identifier"
+// @param body body userSettingsUpdatePayload true "User settings"
+// @success 200 {object} portainer.UserSettings "Success"
+// @failure 400 "Invalid request"
+// @failure 403 "Permission denied"
+// @failure 404 "User not found"
+// @failure 500 "Server error"
+// @router /users/{id}/settings [put]
+func (handler *Handler) userSettingsUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	userID, httpErr := retrieveSettingsUserID(r)
+	if httpErr != nil {
+		return httpErr
+	}
+
+	var payload userSettingsUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var settings *portainer.UserSettings
+	err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		var err error
+		settings, err = updateUserSettings(tx, userID, &payload)
+
+		return err
+	})
+
+	return txResponse(w, settings, err)
+}
+
+// retrieveSettingsUserID returns the identifier of the user of the request, the settings of a user can only be
+// accessed by the user and by the administrators
+func retrieveSettingsUserID(r *http.Request) (portainer.UserID, *httperror.HandlerError) {
+	id, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return 0, httperror.BadRequest("Invalid user identifier route variable", err)
+	}
+
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return 0, httperror.InternalServerError("Unable to retrieve user authentication token", err)
+	}
+
+	userID := portainer.UserID(id)
+	if tokenData.Role != portainer.AdministratorRole && tokenData.ID != userID {
+		return 0, httperror.Forbidden("Permission denied to access the settings of another user", httperrors.ErrUnauthorized)
+	}
+
+	return userID, nil
+}
+
+func readUserSettings(tx dataservices.DataStoreTx, userID portainer.UserID) (*portainer.UserSettings, error) {
+	if _, err := tx.User().Read(userID); tx.IsErrObjectNotFound(err) {
+		return nil, httperror.NotFound("Unable to find a user with the specified identifier inside the database", err)
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to find a user with the specified identifier inside the database", err)
+	}
+
+	settings, err := tx.UserSettings().Read(userID)
+	if tx.IsErrObjectNotFound(err) {
+		return &portainer.UserSettings{
+			UserID:            userID,
+			HiddenColumns:     map[string][]string{},
+			FavoriteEndpoints: []portainer.EndpointID{},
+		}, nil
+	} else if err != nil {
+		return nil, httperror.InternalServerError("Unable to retrieve the user settings from the database", err)
+	}
+
+	return settings, nil
+}
+
+func updateUserSettings(tx dataservices.DataStoreTx, userID portainer.UserID, payload *userSettingsUpdatePayload) (*portainer.UserSettings, error) {
+	settings, err := readUserSettings(tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.DefaultEndpointID != nil {
+		if *payload.DefaultEndpointID != 0 {
+			if err := checkEndpointExists(tx, *payload.DefaultEndpointID); err != nil {
+				return nil, err
+			}
+		}
+
+		settings.DefaultEndpointID = *payload.DefaultEndpointID
+	}
+
+	if payload.ItemsPerPage != nil {
+		settings.ItemsPerPage = *payload.ItemsPerPage
+	}
+
+	if payload.HiddenColumns != nil {
+		settings.HiddenColumns = payload.HiddenColumns
+	}
+
+	if payload.FavoriteEndpoints != nil {
+		favorites := make([]portainer.EndpointID, 0, len(payload.FavoriteEndpoints))
+		for _, endpointID := range payload.FavoriteEndpoints {
+			if slices.Contains(favorites, endpointID) {
+				continue
+			}
+
+			if err := checkEndpointExists(tx, endpointID); err != nil {
+				return nil, err
+			}
+
+			favorites = append(favorites, endpointID)
+		}
+
+		settings.FavoriteEndpoints = favorites
+	}
+
+	if err := tx.UserSettings().Update(userID, settings); err != nil {
+		return nil, httperror.InternalServerError("Unable to persist the user settings inside the database", err)
+	}
+
+	return settings, nil
+}
+
+func checkEndpointExists(tx dataservices.DataStoreTx, endpointID portainer.EndpointID) error {
+	if _, err := tx.Endpoint().Endpoint(endpointID); tx.IsErrObjectNotFound(err) {
+		return httperror.BadRequest("Unable to find an environment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	return nil
+}
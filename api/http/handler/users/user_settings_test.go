@@ -0,0 +1,141 @@
+package users
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/apikey"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+	"github.com/portainer/portainer/api/jwt"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_userSettings(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	adminUser := &portainer.User{ID: 1, Username: "admin", Role: portainer.AdministratorRole}
+	require.NoError(t, store.User().Create(adminUser))
+
+	user := &portainer.User{ID: 2, Username: "standard", Role: portainer.StandardUserRole}
+	require.NoError(t, store.User().Create(user))
+
+	other := &portainer.User{ID: 3, Username: "other", Role: portainer.StandardUserRole}
+	require.NoError(t, store.User().Create(other))
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1, Name: "local"}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 2, Name: "remote"}))
+
+	jwtService, err := jwt.NewService("1h", store)
+	require.NoError(t, err)
+	apiKeyService := apikey.NewAPIKeyService(store.APIKeyRepository(), store.User())
+	requestBouncer := security.NewRequestBouncer(store, jwtService, apiKeyService)
+	rateLimiter := security.NewRateLimiter(10, 1*time.Second, 1*time.Hour)
+	passwordChecker := security.NewPasswordStrengthChecker(store.SettingsService)
+
+	h := NewHandler(requestBouncer, rateLimiter, apiKeyService, passwordChecker)
+	h.DataStore = store
+
+	login := func(u *portainer.User) string {
+		token, _, err := jwtService.GenerateToken(&portainer.TokenData{ID: u.ID, Username: u.Username, Role: u.Role})
+		require.NoError(t, err)
+
+		_, tokenID, expiresAt, err := jwtService.ParseAndVerifyToken(token)
+		require.NoError(t, err)
+
+		session := &portainer.UserSession{UserID: u.ID, TokenID: tokenID, IssuedAt: time.Now().Unix(), ExpiresAt: expiresAt.Unix()}
+		require.NoError(t, store.UserSession().Create(session))
+
+		return token
+	}
+
+	do := func(method, url, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, url, strings.NewReader(body))
+		testhelpers.AddTestSecurityCookie(req, token)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	adminJWT := login(adminUser)
+	userJWT := login(user)
+
+	t.Run("default settings are returned when the user has none", func(t *testing.T) {
+		rr := do(http.MethodGet, "/users/2/settings", userJWT, "")
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		var settings portainer.UserSettings
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&settings))
+		assert.Equal(t, user.ID, settings.UserID)
+		assert.Zero(t, settings.DefaultEndpointID)
+		assert.Empty(t, settings.FavoriteEndpoints)
+	})
+
+	t.Run("user can update their settings", func(t *testing.T) {
+		rr := do(http.MethodPut, "/users/2/settings", userJWT, `{"DefaultEndpointId": 1, "ItemsPerPage": 50, "HiddenColumns": {"containers": ["ports"]}, "FavoriteEndpoints": [2, 1, 2]}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		settings, err := store.UserSettings().Read(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, portainer.EndpointID(1), settings.DefaultEndpointID)
+		assert.Equal(t, 50, settings.ItemsPerPage)
+		assert.Equal(t, map[string][]string{"containers": {"ports"}}, settings.HiddenColumns)
+		assert.Equal(t, []portainer.EndpointID{2, 1}, settings.FavoriteEndpoints)
+	})
+
+	t.Run("unspecified fields are left unchanged", func(t *testing.T) {
+		rr := do(http.MethodPut, "/users/2/settings", userJWT, `{"ItemsPerPage": 10}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		settings, err := store.UserSettings().Read(user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 10, settings.ItemsPerPage)
+		assert.Equal(t, portainer.EndpointID(1), settings.DefaultEndpointID)
+		assert.Equal(t, []portainer.EndpointID{2, 1}, settings.FavoriteEndpoints)
+	})
+
+	t.Run("unknown environments are rejected", func(t *testing.T) {
+		rr := do(http.MethodPut, "/users/2/settings", userJWT, `{"FavoriteEndpoints": [42]}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		rr = do(http.MethodPut, "/users/2/settings", userJWT, `{"ItemsPerPage": -1}`)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("user cannot access the settings of another user", func(t *testing.T) {
+		rr := do(http.MethodGet, "/users/3/settings", userJWT, "")
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = do(http.MethodPut, "/users/3/settings", userJWT, `{"ItemsPerPage": 10}`)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("admin can provision the settings of another user", func(t *testing.T) {
+		rr := do(http.MethodPut, "/users/3/settings", adminJWT, `{"DefaultEndpointId": 2}`)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		settings, err := store.UserSettings().Read(other.ID)
+		require.NoError(t, err)
+		assert.Equal(t, portainer.EndpointID(2), settings.DefaultEndpointID)
+
+		rr = do(http.MethodGet, "/users/4/settings", adminJWT, "")
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("settings are removed with the user", func(t *testing.T) {
+		h.deleteUser(httptest.NewRecorder(), other)
+
+		_, err := store.UserSettings().Read(other.ID)
+		assert.True(t, store.IsErrObjectNotFound(err))
+	})
+}
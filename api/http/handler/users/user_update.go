@@ -27,8 +27,8 @@ type userUpdatePayload struct {
 	UseCache    *bool  `validate:"required" example:"true"`
 	Theme       *themePayload
 
-	// User role (1 for administrator account and 2 for regular account)
-	Role int `validate:"required" enums:"1,2" example:"2"`
+	// User role (1 for administrator account, 2 for regular account and 3 for read-only account)
+	Role int `validate:"required" enums:"1,2,3" example:"2"`
 }
 
 func (payload *userUpdatePayload) Validate(r *http.Request) error {
@@ -36,8 +36,8 @@ func (payload *userUpdatePayload) Validate(r *http.Request) error {
 		return errors.New("invalid username. Must not contain any whitespace")
 	}
 
-	if payload.Role != 0 && payload.Role != 1 && payload.Role != 2 {
-		return errors.New("invalid role value. Value must be one of: 1 (administrator) or 2 (regular user)")
+	if payload.Role != 0 && payload.Role != 1 && payload.Role != 2 && payload.Role != 3 {
+		return errors.New("invalid role value. Value must be one of: 1 (administrator), 2 (regular user) or 3 (read-only user)")
 	}
 
 	return nil
@@ -93,6 +93,16 @@ func (handler *Handler) userUpdate(w http.ResponseWriter, r *http.Request) *http
 		return httperror.InternalServerError("Unable to find a user with the specified identifier inside the database", err)
 	}
 
+	if user.ServiceAccount {
+		if payload.Role == int(portainer.AdministratorRole) {
+			return httperror.Forbidden("A service account cannot be promoted to the administrator role", errServiceAccountAdmin)
+		}
+
+		if payload.NewPassword != "" {
+			return httperror.BadRequest("A service account cannot have a password", errServiceAccountPassword)
+		}
+	}
+
 	if payload.Username != "" && payload.Username != user.Username {
 		if tokenData.Role != portainer.AdministratorRole {
 			return httperror.Forbidden("Permission denied. Unable to update username", httperrors.ErrResourceAccessDenied)
@@ -152,8 +162,5 @@ func (handler *Handler) userUpdate(w http.ResponseWriter, r *http.Request) *http
 	// remove all of the users persisted API keys
 	handler.apiKeyService.InvalidateUserKeyCache(user.ID)
 
-	// hide the password field in the response payload
-	user.Password = ""
-
 	return response.JSON(w, user)
 }
@@ -0,0 +1,87 @@
+package validationwebhooks
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+
+	"github.com/gorilla/mux"
+)
+
+// maxTimeout is the longest time a deployment can wait for a validation webhook, in seconds
+const maxTimeout = 60
+
+// Handler is the HTTP handler used to handle validation webhook operations.
+type Handler struct {
+	*mux.Router
+	DataStore dataservices.DataStore
+}
+
+// NewHandler creates a handler to manage validation webhook operations.
+func NewHandler(bouncer security.BouncerService) *Handler {
+	h := &Handler{
+		Router: mux.NewRouter(),
+	}
+
+	h.Handle("/validation_webhooks",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.validationWebhookList))).Methods(http.MethodGet)
+	h.Handle("/validation_webhooks",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.validationWebhookCreate))).Methods(http.MethodPost)
+	h.Handle("/validation_webhooks/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.validationWebhookInspect))).Methods(http.MethodGet)
+	h.Handle("/validation_webhooks/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.validationWebhookUpdate))).Methods(http.MethodPut)
+	h.Handle("/validation_webhooks/{id}",
+		bouncer.AdminAccess(httperror.LoggerHandler(h.validationWebhookDelete))).Methods(http.MethodDelete)
+
+	return h
+}
+
+func validateURL(rawURL string) error {
+	u, err := url.ParseRequestURI(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("invalid validation webhook URL, it must be an http or https URL")
+	}
+
+	return nil
+}
+
+func validateTimeout(timeout int) error {
+	if timeout < 0 || timeout > maxTimeout {
+		return errors.New("invalid validation webhook timeout, it must be between 0 and 60 seconds")
+	}
+
+	return nil
+}
+
+// checkEndpointGroups returns an error when one of the groups does not exist
+func checkEndpointGroups(tx dataservices.DataStoreTx, groupIDs []portainer.EndpointGroupID) error {
+	for _, groupID := range groupIDs {
+		if _, err := tx.EndpointGroup().Read(groupID); tx.IsErrObjectNotFound(err) {
+			return httperror.BadRequest("Unable to find an environment group with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find an environment group with the specified identifier inside the database", err)
+		}
+	}
+
+	return nil
+}
+
+func txResponse(w http.ResponseWriter, r any, err error) *httperror.HandlerError {
+	if err != nil {
+		var handlerError *httperror.HandlerError
+		if errors.As(err, &handlerError) {
+			return handlerError
+		}
+
+		return httperror.InternalServerError("Unexpected error", err)
+	}
+
+	return response.JSON(w, r)
+}
@@ -0,0 +1,89 @@
+package validationwebhooks
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type validationWebhookCreatePayload struct {
+	Name string `example:"opa"`
+	// URL receiving the deployments with a POST request
+	URL string `example:"https://policies.corp.internal/portainer"`
+	// Secret used to sign the requests, the requests are not signed when empty
+	Secret string `example:"secret"`
+	// Environment(Endpoint) groups whose deployments are validated, all the deployments are validated when empty
+	EndpointGroupIDs []portainer.EndpointGroupID `example:"1"`
+	// Whether the deployments proceed when the webhook cannot be reached
+	FailOpen bool `example:"false"`
+	// Time to wait for the response in seconds, defaults to 10
+	Timeout int  `example:"10"`
+	Enabled bool `example:"true"`
+}
+
+func (payload *validationWebhookCreatePayload) Validate(r *http.Request) error {
+	if payload.Name == "" {
+		return errors.New("invalid validation webhook name")
+	}
+
+	if err := validateURL(payload.URL); err != nil {
+		return err
+	}
+
+	return validateTimeout(payload.Timeout)
+}
+
+// @id ValidationWebhookCreate
+// @summary Create a validation webhook
+// @description Create a webhook receiving the deployments of stacks and Kubernetes manifests before they are made.
+// @description The webhook responds with {"allowed": bool, "message": string, "warnings": [string]}, the deployment is blocked
+// @description when it is not allowed and the warnings are recorded in the deployment history.
+// @description **Access policy**: administrator
+// @tags validation_webhooks
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param body body validationWebhookCreatePayload true "Validation webhook details"
+// @success 200 {object} portainer.ValidationWebhook
+// @failure 400
+// @failure 500
+// @router /validation_webhooks [post]
+func (handler *Handler) validationWebhookCreate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	var payload validationWebhookCreatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	webhook := &portainer.ValidationWebhook{
+		Name:             payload.Name,
+		URL:              payload.URL,
+		Secret:           payload.Secret,
+		EndpointGroupIDs: payload.EndpointGroupIDs,
+		FailOpen:         payload.FailOpen,
+		Timeout:          payload.Timeout,
+		Enabled:          payload.Enabled,
+	}
+
+	if webhook.EndpointGroupIDs == nil {
+		webhook.EndpointGroupIDs = []portainer.EndpointGroupID{}
+	}
+
+	err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := checkEndpointGroups(tx, webhook.EndpointGroupIDs); err != nil {
+			return err
+		}
+
+		if err := tx.ValidationWebhook().Create(webhook); err != nil {
+			return httperror.InternalServerError("Unable to persist the validation webhook inside the database", err)
+		}
+
+		return nil
+	})
+
+	return txResponse(w, webhook, err)
+}
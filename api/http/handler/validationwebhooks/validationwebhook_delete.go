@@ -0,0 +1,50 @@
+package validationwebhooks
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id ValidationWebhookDelete
+// @summary Delete a validation webhook
+// @description **Access policy**: administrator
+// @tags validation_webhooks
+// @security ApiKeyAuth
+// @security jwt
+// @param id path int true "Validation webhook identifier"
+// @success 204
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /validation_webhooks/{id} [delete]
+func (handler *Handler) validationWebhookDelete(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	webhookID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid validation webhook identifier route variable", err)
+	}
+
+	id := portainer.ValidationWebhookID(webhookID)
+
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if _, err := tx.ValidationWebhook().Read(id); tx.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a validation webhook with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a validation webhook with the specified identifier inside the database", err)
+		}
+
+		if err := tx.ValidationWebhook().Delete(id); err != nil {
+			return httperror.InternalServerError("Unable to remove the validation webhook from the database", err)
+		}
+
+		return nil
+	}); err != nil {
+		return txResponse(w, nil, err)
+	}
+
+	return response.Empty(w)
+}
@@ -0,0 +1,39 @@
+package validationwebhooks
+
+import (
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id ValidationWebhookInspect
+// @summary Inspect a validation webhook
+// @description **Access policy**: administrator
+// @tags validation_webhooks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @param id path int true "Validation webhook identifier"
+// @success 200 {object} portainer.ValidationWebhook
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /validation_webhooks/{id} [get]
+func (handler *Handler) validationWebhookInspect(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	webhookID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid validation webhook identifier route variable", err)
+	}
+
+	webhook, err := handler.DataStore.ValidationWebhook().Read(portainer.ValidationWebhookID(webhookID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a validation webhook with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a validation webhook with the specified identifier inside the database", err)
+	}
+
+	return response.JSON(w, webhook)
+}
@@ -0,0 +1,27 @@
+package validationwebhooks
+
+import (
+	"net/http"
+
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @id ValidationWebhookList
+// @summary List the validation webhooks
+// @description **Access policy**: administrator
+// @tags validation_webhooks
+// @security ApiKeyAuth
+// @security jwt
+// @produce json
+// @success 200 {array} portainer.ValidationWebhook
+// @failure 500
+// @router /validation_webhooks [get]
+func (handler *Handler) validationWebhookList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	webhooks, err := handler.DataStore.ValidationWebhook().ReadAll()
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve validation webhooks from the database", err)
+	}
+
+	return response.JSON(w, webhooks)
+}
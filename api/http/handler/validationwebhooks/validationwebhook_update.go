@@ -0,0 +1,121 @@
+package validationwebhooks
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+)
+
+type validationWebhookUpdatePayload struct {
+	Name *string `example:"opa"`
+	// URL receiving the deployments with a POST request
+	URL *string `example:"https://policies.corp.internal/portainer"`
+	// Secret used to sign the requests, an empty secret disables the signature and the secret is kept when omitted
+	Secret *string `example:"secret"`
+	// Environment(Endpoint) groups whose deployments are validated, all the deployments are validated when empty
+	EndpointGroupIDs []portainer.EndpointGroupID `example:"1"`
+	// Whether the deployments proceed when the webhook cannot be reached
+	FailOpen *bool `example:"false"`
+	// Time to wait for the response in seconds
+	Timeout *int  `example:"10"`
+	Enabled *bool `example:"true"`
+}
+
+func (payload *validationWebhookUpdatePayload) Validate(r *http.Request) error {
+	if payload.Name != nil && *payload.Name == "" {
+		return errors.New("invalid validation webhook name")
+	}
+
+	if payload.URL != nil {
+		if err := validateURL(*payload.URL); err != nil {
+			return err
+		}
+	}
+
+	if payload.Timeout != nil {
+		return validateTimeout(*payload.Timeout)
+	}
+
+	return nil
+}
+
+// @id ValidationWebhookUpdate
+// @summary Update a validation webhook
+// @description **Access policy**: administrator
+// @tags validation_webhooks
+// @security ApiKeyAuth
+// @security jwt
+// @accept json
+// @produce json
+// @param id path int true "Validation webhook identifier"
+// @param body body validationWebhookUpdatePayload true "Validation webhook details"
+// @success 200 {object} portainer.ValidationWebhook
+// @failure 400
+// @failure 404
+// @failure 500
+// @router /validation_webhooks/{id} [put]
+func (handler *Handler) validationWebhookUpdate(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	webhookID, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid validation webhook identifier route variable", err)
+	}
+
+	var payload validationWebhookUpdatePayload
+	if err := request.DecodeAndValidateJSONPayload(r, &payload); err != nil {
+		return httperror.BadRequest("Invalid request payload", err)
+	}
+
+	var webhook *portainer.ValidationWebhook
+	err = handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		webhook, err = tx.ValidationWebhook().Read(portainer.ValidationWebhookID(webhookID))
+		if tx.IsErrObjectNotFound(err) {
+			return httperror.NotFound("Unable to find a validation webhook with the specified identifier inside the database", err)
+		} else if err != nil {
+			return httperror.InternalServerError("Unable to find a validation webhook with the specified identifier inside the database", err)
+		}
+
+		if payload.Name != nil {
+			webhook.Name = *payload.Name
+		}
+
+		if payload.URL != nil {
+			webhook.URL = *payload.URL
+		}
+
+		if payload.Secret != nil {
+			webhook.Secret = *payload.Secret
+		}
+
+		if payload.EndpointGroupIDs != nil {
+			if err := checkEndpointGroups(tx, payload.EndpointGroupIDs); err != nil {
+				return err
+			}
+
+			webhook.EndpointGroupIDs = payload.EndpointGroupIDs
+		}
+
+		if payload.FailOpen != nil {
+			webhook.FailOpen = *payload.FailOpen
+		}
+
+		if payload.Timeout != nil {
+			webhook.Timeout = *payload.Timeout
+		}
+
+		if payload.Enabled != nil {
+			webhook.Enabled = *payload.Enabled
+		}
+
+		if err := tx.ValidationWebhook().Update(webhook.ID, webhook); err != nil {
+			return httperror.InternalServerError("Unable to persist validation webhook changes inside the database", err)
+		}
+
+		return nil
+	})
+
+	return txResponse(w, webhook, err)
+}
@@ -2,8 +2,10 @@ package webhooks
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/docker"
 	dockerclient "github.com/portainer/portainer/api/docker/client"
 	"github.com/portainer/portainer/api/http/security"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
@@ -17,6 +19,9 @@ type Handler struct {
 	requestBouncer      security.BouncerService
 	DataStore           dataservices.DataStore
 	DockerClientFactory *dockerclient.ClientFactory
+	ContainerService    *docker.ContainerService
+	// retryDelay is the delay before the first retry of a failed webhook action
+	retryDelay time.Duration
 }
 
 // NewHandler creates a handler to manage webhooks operations.
@@ -24,6 +29,7 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 	h := &Handler{
 		Router:         mux.NewRouter(),
 		requestBouncer: bouncer,
+		retryDelay:     defaultRetryDelay,
 	}
 	h.Handle("/webhooks",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.webhookCreate))).Methods(http.MethodPost)
@@ -33,6 +39,8 @@ func NewHandler(bouncer security.BouncerService) *Handler {
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.webhookList))).Methods(http.MethodGet)
 	h.Handle("/webhooks/{id}",
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.webhookDelete))).Methods(http.MethodDelete)
+	h.Handle("/webhooks/{id}/executions",
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.webhookExecutionList))).Methods(http.MethodGet)
 	h.Handle("/webhooks/{token}",
 		bouncer.PublicAccess(httperror.LoggerHandler(h.webhookExecute))).Methods(http.MethodPost)
 
@@ -1,8 +1,10 @@
 package webhooks
 
 import (
+	"cmp"
 	"errors"
 	"net/http"
+	"strings"
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/http/security"
@@ -18,23 +20,45 @@ type webhookCreatePayload struct {
 	ResourceID string
 	EndpointID portainer.EndpointID
 	RegistryID portainer.RegistryID
-	// Type of webhook (1 - service)
+	// Type of webhook (1 - service, 2 - container)
 	WebhookType portainer.WebhookType
+	// Action run by a container webhook, recreate (default) or restart. The ResourceID of a
+	// container webhook is the name of the container, it is kept when the container is recreated
+	ContainerAction portainer.ContainerWebhookAction `example:"recreate"`
 }
 
 func (payload *webhookCreatePayload) Validate(r *http.Request) error {
-	if len(payload.ResourceID) == 0 {
+	if len(strings.TrimPrefix(payload.ResourceID, "/")) == 0 {
 		return errors.New("Invalid ResourceID")
 	}
 	if payload.EndpointID == 0 {
 		return errors.New("Invalid EndpointID")
 	}
-	if payload.WebhookType != portainer.ServiceWebhook {
+	switch payload.WebhookType {
+	case portainer.ServiceWebhook:
+		if payload.ContainerAction != "" {
+			return errors.New("ContainerAction is only supported by container webhooks")
+		}
+	case portainer.ContainerWebhook:
+		payload.ResourceID = strings.TrimPrefix(payload.ResourceID, "/")
+		if err := validateContainerAction(payload.ContainerAction); err != nil {
+			return err
+		}
+	default:
 		return errors.New("Invalid WebhookType")
 	}
 	return nil
 }
 
+func validateContainerAction(action portainer.ContainerWebhookAction) error {
+	switch action {
+	case "", portainer.ContainerWebhookRecreate, portainer.ContainerWebhookRestart:
+		return nil
+	}
+
+	return errors.New("Invalid ContainerAction, must be recreate or restart")
+}
+
 // @summary Create a webhook
 // @description **Access policy**: authenticated
 // @security ApiKeyAuth
@@ -99,6 +123,10 @@ func (handler *Handler) webhookCreate(w http.ResponseWriter, r *http.Request) *h
 		WebhookType: payload.WebhookType,
 	}
 
+	if webhook.WebhookType == portainer.ContainerWebhook {
+		webhook.ContainerAction = cmp.Or(payload.ContainerAction, portainer.ContainerWebhookRecreate)
+	}
+
 	err = handler.DataStore.Webhook().Create(webhook)
 	if err != nil {
 		return httperror.InternalServerError("Unable to persist the webhook inside the database", err)
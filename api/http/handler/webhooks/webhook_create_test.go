@@ -0,0 +1,32 @@
+package webhooks
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookCreatePayloadValidate(t *testing.T) {
+	payload := webhookCreatePayload{ResourceID: "/web", EndpointID: 1, WebhookType: portainer.ContainerWebhook}
+	require.NoError(t, payload.Validate(nil))
+	assert.Equal(t, "web", payload.ResourceID)
+
+	payload.ContainerAction = portainer.ContainerWebhookRestart
+	require.NoError(t, payload.Validate(nil))
+
+	payload.ContainerAction = "stop"
+	require.Error(t, payload.Validate(nil))
+
+	// Services are always updated
+	payload = webhookCreatePayload{ResourceID: "ozcdu9mgjgs1ziofq2xpssegi", EndpointID: 1, WebhookType: portainer.ServiceWebhook}
+	require.NoError(t, payload.Validate(nil))
+
+	payload.ContainerAction = portainer.ContainerWebhookRestart
+	require.Error(t, payload.Validate(nil))
+
+	payload = webhookCreatePayload{ResourceID: "/", EndpointID: 1, WebhookType: portainer.ContainerWebhook}
+	require.Error(t, payload.Validate(nil))
+}
@@ -4,22 +4,33 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/registryutils"
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
 	"github.com/portainer/portainer/pkg/libhttp/request"
 	"github.com/portainer/portainer/pkg/libhttp/response"
 
 	dockertypes "github.com/docker/docker/api/types"
+	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/rs/zerolog/log"
 )
 
 // @summary Execute a webhook
-// @description Acts on a passed in token UUID to restart the docker service
+// @description Acts on a passed in token UUID to restart the docker service, or to restart or recreate the docker container.
+// @description A container is looked up by name on the environment and recreated with the same networks and volumes.
+// @description Each invocation is recorded in the executions of the webhook, the actions failing with a server error are retried in the background.
 // @description **Access policy**: public
 // @tags webhooks
 // @param id path string true "Webhook token"
+// @param tag query string false "Image tag to update the service or the recreated container to"
 // @success 202 "Webhook executed"
 // @failure 400
 // @failure 500
@@ -37,12 +48,7 @@ func (handler *Handler) webhookExecute(w http.ResponseWriter, r *http.Request) *
 		return httperror.InternalServerError("Unable to retrieve webhook from the database", err)
 	}
 
-	resourceID := webhook.ResourceID
-	endpointID := webhook.EndpointID
-	registryID := webhook.RegistryID
-	webhookType := webhook.WebhookType
-
-	endpoint, err := handler.DataStore.Endpoint().Endpoint(endpointID)
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(webhook.EndpointID)
 	if handler.DataStore.IsErrObjectNotFound(err) {
 		return httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
 	} else if err != nil {
@@ -51,16 +57,46 @@ func (handler *Handler) webhookExecute(w http.ResponseWriter, r *http.Request) *
 
 	imageTag, _ := request.RetrieveQueryParameter(r, "tag", true)
 
-	switch webhookType {
+	payloadHash, err := hashPayload(w, r)
+	if err != nil {
+		return httperror.BadRequest("Unable to read the request body", err)
+	}
+
+	execution := &portainer.WebhookExecution{
+		WebhookID:   webhook.ID,
+		Time:        time.Now().Unix(),
+		SourceIP:    security.StripAddrPort(r.RemoteAddr),
+		PayloadHash: payloadHash,
+		EndpointID:  endpoint.ID,
+		ResourceID:  webhook.ResourceID,
+		ImageTag:    imageTag,
+	}
+
+	// The action is not interrupted when the client disconnects so that its outcome is recorded
+	httpErr := handler.runWebhook(context.WithoutCancel(r.Context()), webhook, endpoint, imageTag)
+	handler.recordExecution(execution, httpErr)
+
+	if httpErr != nil {
+		return httpErr
+	}
+
+	return response.Empty(w)
+}
+
+// runWebhook runs the action of a webhook on its resource
+func (handler *Handler) runWebhook(ctx context.Context, webhook *portainer.Webhook, endpoint *portainer.Endpoint, imageTag string) *httperror.HandlerError {
+	switch webhook.WebhookType {
 	case portainer.ServiceWebhook:
-		return handler.executeServiceWebhook(w, endpoint, resourceID, registryID, imageTag)
+		return handler.executeServiceWebhook(ctx, endpoint, webhook.ResourceID, webhook.RegistryID, imageTag)
+	case portainer.ContainerWebhook:
+		return handler.executeContainerWebhook(ctx, endpoint, webhook.ResourceID, webhook.ContainerAction, imageTag)
 	default:
 		return httperror.InternalServerError("Unsupported webhook type", errors.New("Webhooks for this resource are not currently supported"))
 	}
 }
 
 func (handler *Handler) executeServiceWebhook(
-	w http.ResponseWriter,
+	ctx context.Context,
 	endpoint *portainer.Endpoint,
 	resourceID string,
 	registryID portainer.RegistryID,
@@ -72,7 +108,7 @@ func (handler *Handler) executeServiceWebhook(
 	}
 	defer dockerClient.Close()
 
-	service, _, err := dockerClient.ServiceInspectWithRaw(context.Background(), resourceID, dockertypes.ServiceInspectOptions{InsertDefaults: true})
+	service, _, err := dockerClient.ServiceInspectWithRaw(ctx, resourceID, dockertypes.ServiceInspectOptions{InsertDefaults: true})
 	if err != nil {
 		return httperror.InternalServerError("Error looking up service", err)
 	}
@@ -110,16 +146,83 @@ func (handler *Handler) executeServiceWebhook(
 		}
 	}
 	if imageTag != "" {
-		rc, err := dockerClient.ImagePull(context.Background(), service.Spec.TaskTemplate.ContainerSpec.Image, dockertypes.ImagePullOptions{RegistryAuth: serviceUpdateOptions.EncodedRegistryAuth})
+		rc, err := dockerClient.ImagePull(ctx, service.Spec.TaskTemplate.ContainerSpec.Image, dockertypes.ImagePullOptions{RegistryAuth: serviceUpdateOptions.EncodedRegistryAuth})
 		if err != nil {
 			return httperror.NotFound("Error pulling image with the specified tag", err)
 		}
 		defer rc.Close()
 	}
 
-	if _, err := dockerClient.ServiceUpdate(context.Background(), resourceID, service.Version, service.Spec, serviceUpdateOptions); err != nil {
+	if _, err := dockerClient.ServiceUpdate(ctx, resourceID, service.Version, service.Spec, serviceUpdateOptions); err != nil {
 		return httperror.InternalServerError("Error updating service", err)
 	}
 
-	return response.Empty(w)
+	return nil
+}
+
+func (handler *Handler) executeContainerWebhook(
+	ctx context.Context,
+	endpoint *portainer.Endpoint,
+	containerName string,
+	action portainer.ContainerWebhookAction,
+	imageTag string,
+) *httperror.HandlerError {
+	if action == portainer.ContainerWebhookRestart && imageTag != "" {
+		return httperror.BadRequest("Invalid query parameter: tag", errors.New("the image tag can only be set when the container is recreated"))
+	}
+
+	dockerClient, err := handler.DockerClientFactory.CreateClient(endpoint, "", nil)
+	if err != nil {
+		return httperror.InternalServerError("Error creating docker client", err)
+	}
+	defer dockerClient.Close()
+
+	// The container is looked up by name as its identifier changes every time it is recreated
+	containerID, err := findContainerByName(ctx, dockerClient, containerName)
+	if err != nil {
+		return httperror.InternalServerError("Error looking up container", err)
+	} else if containerID == "" {
+		return httperror.NotFound("Unable to find a container with this name on the environment", errors.New("container not found"))
+	}
+
+	switch action {
+	case portainer.ContainerWebhookRestart:
+		if err := dockerClient.ContainerRestart(ctx, containerID, dockercontainer.StopOptions{}); err != nil {
+			return httperror.InternalServerError("Error restarting container", err)
+		}
+	default:
+		newContainer, err := handler.ContainerService.Recreate(ctx, endpoint, containerID, true, imageTag, "")
+		if err != nil {
+			return httperror.InternalServerError("Error recreating container", err)
+		}
+
+		if err := handler.ContainerService.ReassignResources(containerID, newContainer.ID); err != nil {
+			log.Warn().Err(err).Str("container_id", newContainer.ID).Msg("unable to transfer the resource control to the recreated container")
+		}
+	}
+
+	return nil
+}
+
+// findContainerByName returns the identifier of the container with the exact given name, or an
+// empty string when there is none
+func findContainerByName(ctx context.Context, dockerClient *client.Client, name string) (string, error) {
+	name = "/" + strings.TrimPrefix(name, "/")
+
+	// The name filter of the Docker API matches substrings, the names are compared afterwards
+	containers, err := dockerClient.ContainerList(ctx, dockercontainer.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("name", "^"+regexp.QuoteMeta(name)+"$")),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, container := range containers {
+		if slices.Contains(container.Names, name) {
+			return container.ID, nil
+		}
+	}
+
+	return "", nil
 }
@@ -0,0 +1,167 @@
+package webhooks
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxPayloadSize bounds the size of the body of a webhook invocation
+	maxPayloadSize = 1 << 20
+	// maxAttempts bounds the number of times the action of a webhook is run for an invocation
+	maxAttempts = 4
+	// defaultRetryDelay is the delay before the first retry of a failed action, it doubles with each retry
+	defaultRetryDelay = 30 * time.Second
+	// executionTimeout bounds the duration of a retried action
+	executionTimeout = 10 * time.Minute
+	// ExecutionRetention is the number of executions kept for each webhook
+	ExecutionRetention = 50
+)
+
+// hashPayload returns the SHA-256 checksum of the body of the request, or an empty string when the body is empty
+func hashPayload(w http.ResponseWriter, r *http.Request) (string, error) {
+	if r.Body == nil {
+		return "", nil
+	}
+
+	hash := sha256.New()
+
+	n, err := io.Copy(hash, http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil || n == 0 {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// isRetryable returns true when the action failed on the side of the environment, the invalid invocations
+// and the missing resources are not retried
+func isRetryable(httpErr *httperror.HandlerError) bool {
+	return httpErr.StatusCode >= http.StatusInternalServerError
+}
+
+// recordExecution records the outcome of an attempt of an execution and schedules the next attempt
+// when the action can be retried
+func (handler *Handler) recordExecution(execution *portainer.WebhookExecution, httpErr *httperror.HandlerError) {
+	execution.Attempts++
+	execution.LastAttempt = time.Now().Unix()
+
+	switch {
+	case httpErr == nil:
+		execution.Outcome = portainer.WebhookExecutionSuccess
+		execution.Error = ""
+	case isRetryable(httpErr) && execution.Attempts < maxAttempts:
+		execution.Outcome = portainer.WebhookExecutionRetrying
+		execution.Error = executionError(httpErr)
+	default:
+		execution.Outcome = portainer.WebhookExecutionFailure
+		execution.Error = executionError(httpErr)
+	}
+
+	if err := handler.DataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return saveExecution(tx, execution)
+	}); err != nil {
+		log.Warn().Err(err).Int("webhook_id", int(execution.WebhookID)).Msg("unable to record the webhook execution")
+
+		return
+	}
+
+	if execution.Outcome != portainer.WebhookExecutionRetrying {
+		return
+	}
+
+	delay := handler.retryDelay << (execution.Attempts - 1)
+	time.AfterFunc(delay, func() {
+		handler.retryExecution(execution.ID)
+	})
+}
+
+// retryExecution runs the action of a webhook again for an execution, the retries stop when the webhook
+// or its environment(endpoint) was removed in the meantime
+func (handler *Handler) retryExecution(executionID portainer.WebhookExecutionID) {
+	execution, err := handler.DataStore.WebhookExecution().Read(executionID)
+	if err != nil {
+		if !handler.DataStore.IsErrObjectNotFound(err) {
+			log.Warn().Err(err).Int("execution_id", int(executionID)).Msg("unable to retrieve the webhook execution")
+		}
+
+		return
+	}
+
+	webhook, err := handler.DataStore.Webhook().Read(execution.WebhookID)
+	if err != nil {
+		handler.recordExecution(execution, httperror.NotFound("Unable to find the webhook", err))
+
+		return
+	}
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(webhook.EndpointID)
+	if err != nil {
+		handler.recordExecution(execution, httperror.NotFound("Unable to find the environment of the webhook", err))
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), executionTimeout)
+	defer cancel()
+
+	handler.recordExecution(execution, handler.runWebhook(ctx, webhook, endpoint, execution.ImageTag))
+}
+
+// saveExecution saves an execution and removes the oldest executions of its webhook beyond the retention
+func saveExecution(tx dataservices.DataStoreTx, execution *portainer.WebhookExecution) error {
+	if execution.ID != 0 {
+		return tx.WebhookExecution().Update(execution.ID, execution)
+	}
+
+	if err := tx.WebhookExecution().Create(execution); err != nil {
+		return err
+	}
+
+	executions, err := tx.WebhookExecution().ReadAllByWebhookID(execution.WebhookID)
+	if err != nil {
+		return err
+	}
+
+	if len(executions) <= ExecutionRetention {
+		return nil
+	}
+
+	SortExecutions(executions)
+
+	for _, previous := range executions[ExecutionRetention:] {
+		if err := tx.WebhookExecution().Delete(previous.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SortExecutions sorts the executions from the most recent to the oldest
+func SortExecutions(executions []portainer.WebhookExecution) {
+	slices.SortFunc(executions, func(a, b portainer.WebhookExecution) int {
+		return cmp.Or(cmp.Compare(b.Time, a.Time), cmp.Compare(b.ID, a.ID))
+	})
+}
+
+func executionError(httpErr *httperror.HandlerError) string {
+	if httpErr.Err == nil {
+		return httpErr.Message
+	}
+
+	return fmt.Sprintf("%s: %s", httpErr.Message, httpErr.Err)
+}
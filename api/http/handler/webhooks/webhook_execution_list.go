@@ -0,0 +1,59 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+	"github.com/portainer/portainer/pkg/libhttp/response"
+)
+
+// @summary List the executions of a webhook
+// @description List the invocations of a webhook along with the outcome of their action, from the most recent to the oldest.
+// @description Only the latest executions of each webhook are kept.
+// @description **Access policy**: administrator
+// @security ApiKeyAuth
+// @security jwt
+// @tags webhooks
+// @produce json
+// @param id path int true "Webhook id"
+// @success 200 {array} portainer.WebhookExecution
+// @failure 400
+// @failure 403
+// @failure 404
+// @failure 500
+// @router /webhooks/{id}/executions [get]
+func (handler *Handler) webhookExecutionList(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	id, err := request.RetrieveNumericRouteVariableValue(r, "id")
+	if err != nil {
+		return httperror.BadRequest("Invalid webhook id", err)
+	}
+
+	securityContext, err := security.RetrieveRestrictedRequestContext(r)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve user info from request context", err)
+	}
+
+	if !securityContext.IsAdmin {
+		return httperror.Forbidden("Not authorized to list the executions of a webhook", errors.New("not authorized to list the executions of a webhook"))
+	}
+
+	webhook, err := handler.DataStore.Webhook().Read(portainer.WebhookID(id))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find a webhook with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find a webhook with the specified identifier inside the database", err)
+	}
+
+	executions, err := handler.DataStore.WebhookExecution().ReadAllByWebhookID(webhook.ID)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the executions of the webhook", err)
+	}
+
+	SortExecutions(executions)
+
+	return response.JSON(w, executions)
+}
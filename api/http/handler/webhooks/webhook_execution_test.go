@@ -0,0 +1,85 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashPayload(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/token", strings.NewReader("{}"))
+	hash, err := hashPayload(httptest.NewRecorder(), r)
+	require.NoError(t, err)
+	assert.Equal(t, "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", hash)
+
+	r = httptest.NewRequest(http.MethodPost, "/webhooks/token", nil)
+	hash, err = hashPayload(httptest.NewRecorder(), r)
+	require.NoError(t, err)
+	assert.Empty(t, hash)
+}
+
+func TestRecordExecution(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+	handler := &Handler{DataStore: store, retryDelay: time.Millisecond}
+
+	// The environment of the webhook does not exist, the retries stop as soon as it is found missing
+	webhook := &portainer.Webhook{Token: "token", ResourceID: "web", EndpointID: 99, WebhookType: portainer.ContainerWebhook}
+	require.NoError(t, store.Webhook().Create(webhook))
+
+	succeeded := &portainer.WebhookExecution{WebhookID: webhook.ID, Time: 1}
+	handler.recordExecution(succeeded, nil)
+	assert.Equal(t, portainer.WebhookExecutionSuccess, succeeded.Outcome)
+	assert.Equal(t, 1, succeeded.Attempts)
+
+	rejected := &portainer.WebhookExecution{WebhookID: webhook.ID, Time: 2}
+	handler.recordExecution(rejected, httperror.BadRequest("Invalid query parameter: tag", errors.New("invalid tag")))
+	assert.Equal(t, portainer.WebhookExecutionFailure, rejected.Outcome)
+	assert.Equal(t, "Invalid query parameter: tag: invalid tag", rejected.Error)
+
+	failed := &portainer.WebhookExecution{WebhookID: webhook.ID, Time: 3}
+	handler.recordExecution(failed, httperror.InternalServerError("Error recreating container", errors.New("pull failed")))
+	assert.Equal(t, portainer.WebhookExecutionRetrying, failed.Outcome)
+
+	require.Eventually(t, func() bool {
+		execution, err := store.WebhookExecution().Read(failed.ID)
+		require.NoError(t, err)
+
+		return execution.Outcome == portainer.WebhookExecutionFailure && execution.Attempts == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	executions, err := store.WebhookExecution().ReadAllByWebhookID(webhook.ID)
+	require.NoError(t, err)
+	assert.Len(t, executions, 3)
+}
+
+func TestSaveExecutionRetention(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+	handler := &Handler{DataStore: store, retryDelay: time.Millisecond}
+
+	for i := range ExecutionRetention + 5 {
+		handler.recordExecution(&portainer.WebhookExecution{WebhookID: 1, Time: int64(i)}, nil)
+	}
+	handler.recordExecution(&portainer.WebhookExecution{WebhookID: 2, Time: 1}, nil)
+
+	executions, err := store.WebhookExecution().ReadAllByWebhookID(1)
+	require.NoError(t, err)
+	require.Len(t, executions, ExecutionRetention)
+
+	SortExecutions(executions)
+	assert.Equal(t, int64(ExecutionRetention+4), executions[0].Time)
+	assert.Equal(t, int64(5), executions[ExecutionRetention-1].Time)
+
+	executions, err = store.WebhookExecution().ReadAllByWebhookID(2)
+	require.NoError(t, err)
+	assert.Len(t, executions, 1)
+}
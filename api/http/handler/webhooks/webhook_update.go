@@ -14,10 +14,12 @@ import (
 
 type webhookUpdatePayload struct {
 	RegistryID portainer.RegistryID
+	// Action run by a container webhook, recreate or restart. Left unchanged when empty
+	ContainerAction portainer.ContainerWebhookAction `example:"restart"`
 }
 
 func (payload *webhookUpdatePayload) Validate(r *http.Request) error {
-	return nil
+	return validateContainerAction(payload.ContainerAction)
 }
 
 // @summary Update a webhook
@@ -75,6 +77,14 @@ func (handler *Handler) webhookUpdate(w http.ResponseWriter, r *http.Request) *h
 		}
 	}
 
+	if payload.ContainerAction != "" {
+		if webhook.WebhookType != portainer.ContainerWebhook {
+			return httperror.BadRequest("Invalid request payload", errors.New("ContainerAction is only supported by container webhooks"))
+		}
+
+		webhook.ContainerAction = payload.ContainerAction
+	}
+
 	webhook.RegistryID = payload.RegistryID
 
 	err = handler.DataStore.Webhook().Update(portainer.WebhookID(id), webhook)
@@ -36,6 +36,9 @@ func NewHandler(kubernetesTokenCacheManager *kubernetes.TokenCacheManager, bounc
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.websocketExec)))
 	h.PathPrefix("/websocket/attach").Handler(
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.websocketAttach)))
+	// registered before /websocket/pod as it shares its prefix
+	h.PathPrefix("/websocket/pod-logs").Handler(
+		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.websocketPodLogs)))
 	h.PathPrefix("/websocket/pod").Handler(
 		bouncer.AuthenticatedAccess(httperror.LoggerHandler(h.websocketPodExec)))
 	h.PathPrefix("/websocket/kubernetes-shell").Handler(
@@ -0,0 +1,151 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/kubernetes/cli"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/libhttp/request"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// @summary Stream the logs of a container of a pod
+// @description The request will be upgraded to the websocket protocol. The logs of the container are sent as text messages,
+// @description the connection is closed once the logs are sent unless follow is set.
+// @description **Access policy**: authenticated, with access to the namespace
+// @security ApiKeyAuth
+// @security jwt
+// @tags websocket
+// @produce json
+// @param endpointId query int true "environment(endpoint) ID of the environment(endpoint) where the resource is located"
+// @param namespace query string true "namespace where the pod is located"
+// @param podName query string true "name of the pod"
+// @param containerName query string false "name of the container, can be omitted when the pod has a single container"
+// @param tail query int false "number of lines to return from the end of the logs, all the logs are returned when omitted"
+// @param follow query bool false "keep the connection open and stream the new lines"
+// @param token query string true "JWT token used for authentication against this environment(endpoint)"
+// @success 200
+// @failure 400
+// @failure 403
+// @failure 404
+// @failure 500
+// @router /websocket/pod-logs [get]
+func (handler *Handler) websocketPodLogs(w http.ResponseWriter, r *http.Request) *httperror.HandlerError {
+	endpointID, err := request.RetrieveNumericQueryParameter(r, "endpointId", false)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: endpointId", err)
+	}
+
+	namespace, err := request.RetrieveQueryParameter(r, "namespace", false)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: namespace", err)
+	}
+
+	podName, err := request.RetrieveQueryParameter(r, "podName", false)
+	if err != nil {
+		return httperror.BadRequest("Invalid query parameter: podName", err)
+	}
+
+	containerName, _ := request.RetrieveQueryParameter(r, "containerName", true)
+
+	tail, err := request.RetrieveNumericQueryParameter(r, "tail", true)
+	if err != nil || tail < 0 {
+		return httperror.BadRequest("Invalid query parameter: tail", err)
+	}
+
+	follow, _ := request.RetrieveBooleanQueryParameter(r, "follow", true)
+
+	endpoint, err := handler.DataStore.Endpoint().Endpoint(portainer.EndpointID(endpointID))
+	if handler.DataStore.IsErrObjectNotFound(err) {
+		return httperror.NotFound("Unable to find an environment with the specified identifier inside the database", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to find an environment with the specified identifier inside the database", err)
+	}
+
+	if !endpointutils.IsKubernetesEndpoint(endpoint) {
+		return httperror.BadRequest("The environment is not a Kubernetes environment", nil)
+	}
+
+	if err := handler.requestBouncer.AuthorizedEndpointOperation(r, endpoint); err != nil {
+		return httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	kubeCli, err := handler.KubernetesClientFactory.GetPrivilegedKubeClient(endpoint)
+	if err != nil {
+		return httperror.InternalServerError("Unable to create Kubernetes client", err)
+	}
+
+	if httpErr := handler.checkNamespaceAccess(r, kubeCli, endpoint, namespace); httpErr != nil {
+		return httpErr
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logs, err := kubeCli.GetPodLogs(ctx, namespace, podName, containerName, int64(tail), follow)
+	if k8serrors.IsNotFound(err) {
+		return httperror.NotFound("Unable to find the pod", err)
+	} else if k8serrors.IsBadRequest(err) {
+		return httperror.BadRequest("Unable to retrieve the logs of the container", err)
+	} else if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the logs of the container", err)
+	}
+	defer logs.Close()
+
+	websocketConn, err := handler.connectionUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return httperror.InternalServerError("Unable to upgrade the connection", err)
+	}
+	defer websocketConn.Close()
+
+	// errorChan receives the end of the logs as well as the disconnection of the client, the messages
+	// sent by the client are discarded
+	errorChan := make(chan error, 2)
+	go streamFromReaderToWebsocket(websocketConn, logs, errorChan)
+	go streamFromWebsocketToWriter(websocketConn, io.Discard, errorChan)
+
+	err = <-errorChan
+
+	if errors.Is(err, io.EOF) {
+		websocketConn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+
+		return nil
+	}
+
+	log.Debug().Err(err).Msg("websocket error")
+
+	return nil
+}
+
+// checkNamespaceAccess ensures that a non-administrator user has access to a namespace of a Kubernetes environment
+func (handler *Handler) checkNamespaceAccess(r *http.Request, kubeCli *cli.KubeClient, endpoint *portainer.Endpoint, namespace string) *httperror.HandlerError {
+	tokenData, err := security.RetrieveTokenData(r)
+	if err != nil {
+		return httperror.Forbidden("Permission denied to access environment", err)
+	}
+
+	if tokenData.Role == portainer.AdministratorRole {
+		return nil
+	}
+
+	namespaces, err := kubeCli.GetNonAdminNamespaces(int(tokenData.ID), endpoint.Kubernetes.Configuration.RestrictDefaultNamespace)
+	if err != nil {
+		return httperror.InternalServerError("Unable to retrieve the namespaces of the user", err)
+	}
+
+	if !slices.Contains(namespaces, namespace) {
+		return httperror.Forbidden("Permission denied to access the namespace", errors.New("the user does not have access to the namespace"))
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package kubernetes
 
 import (
+	"errors"
+	"net/http"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -75,3 +77,33 @@ type K8sApplicationResource struct {
 	MemoryRequest int64   `json:"MemoryRequest"`
 	MemoryLimit   int64   `json:"MemoryLimit"`
 }
+
+type K8sApplicationScalePayload struct {
+	// Number of replicas of the application
+	Replicas *int32 `json:"replicas" example:"3"`
+}
+
+func (payload *K8sApplicationScalePayload) Validate(request *http.Request) error {
+	if payload.Replicas == nil {
+		return errors.New("missing replicas from the request payload")
+	}
+
+	if *payload.Replicas < 0 {
+		return errors.New("the number of replicas cannot be negative")
+	}
+
+	return nil
+}
+
+type K8sApplicationRollbackPayload struct {
+	// Revision to roll back to, 0 for the revision preceding the current one
+	Revision int64 `json:"revision" example:"2"`
+}
+
+func (payload *K8sApplicationRollbackPayload) Validate(request *http.Request) error {
+	if payload.Revision < 0 {
+		return errors.New("the revision cannot be negative")
+	}
+
+	return nil
+}
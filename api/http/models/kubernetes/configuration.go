@@ -1,5 +1,14 @@
 package kubernetes
 
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
 type (
 	K8sConfigMap struct {
 		K8sConfiguration
@@ -15,6 +24,7 @@ type (
 		Name                        string                          `json:"Name"`
 		Namespace                   string                          `json:"Namespace"`
 		CreationDate                string                          `json:"CreationDate"`
+		ResourceVersion             string                          `json:"ResourceVersion"`
 		Annotations                 map[string]string               `json:"Annotations"`
 		Data                        map[string]string               `json:"Data"`
 		IsUsed                      bool                            `json:"IsUsed"`
@@ -22,6 +32,7 @@ type (
 		ConfigurationOwnerResources []K8sConfigurationOwnerResource `json:"ConfigurationOwners"`
 		ConfigurationOwner          string                          `json:"ConfigurationOwner"`
 		ConfigurationOwnerId        string                          `json:"ConfigurationOwnerId"`
+		History                     []K8sConfigurationHistoryEntry  `json:"History,omitempty"`
 	}
 
 	K8sConfigurationOwnerResource struct {
@@ -29,4 +40,104 @@ type (
 		Name         string `json:"Name"`
 		ResourceKind string `json:"ResourceKind"`
 	}
+
+	// K8sConfigurationChange is a change made to a single key of a ConfigMap or a Secret.
+	// The values are never set for Secrets.
+	K8sConfigurationChange struct {
+		Key      string `json:"Key"`
+		Type     string `json:"Type" example:"modified" enums:"added,removed,modified"`
+		OldValue string `json:"OldValue,omitempty"`
+		NewValue string `json:"NewValue,omitempty"`
+	}
+
+	// K8sConfigurationHistoryEntry is an entry of the change history stored in the annotations
+	// of a ConfigMap or a Secret edited through Portainer
+	K8sConfigurationHistoryEntry struct {
+		Date     string                   `json:"Date"`
+		Username string                   `json:"Username"`
+		Changes  []K8sConfigurationChange `json:"Changes"`
+	}
+
+	// K8sConfigurationDiff is the preview of the changes an update would make to a ConfigMap or a Secret
+	K8sConfigurationDiff struct {
+		// Resource version the changes were computed against
+		ResourceVersion string                   `json:"ResourceVersion"`
+		Changes         []K8sConfigurationChange `json:"Changes"`
+		// True when the resource version of the payload does not match the current one
+		Conflict bool `json:"Conflict"`
+	}
+
+	K8sConfigMapPayload struct {
+		// Name of the ConfigMap, only used on creation
+		Name   string            `json:"Name" example:"app-config"`
+		Data   map[string]string `json:"Data"`
+		Labels map[string]string `json:"Labels"`
+		// Resource version the changes are based on, required on update
+		ResourceVersion string `json:"ResourceVersion" example:"123456"`
+	}
+
+	K8sSecretPayload struct {
+		// Name of the Secret, only used on creation
+		Name string            `json:"Name" example:"app-secret"`
+		Data map[string]string `json:"Data"`
+		// When set to true, the values of Data are base64 encoded
+		Base64Encoded bool              `json:"Base64Encoded" example:"false"`
+		SecretType    string            `json:"SecretType" example:"Opaque"`
+		Labels        map[string]string `json:"Labels"`
+		// Resource version the changes are based on, required on update
+		ResourceVersion string `json:"ResourceVersion" example:"123456"`
+	}
+)
+
+const (
+	K8sConfigurationChangeAdded    = "added"
+	K8sConfigurationChangeRemoved  = "removed"
+	K8sConfigurationChangeModified = "modified"
 )
+
+func (payload *K8sConfigMapPayload) Validate(request *http.Request) error {
+	return validateConfigurationKeys(payload.Data)
+}
+
+func (payload *K8sSecretPayload) Validate(request *http.Request) error {
+	if err := validateConfigurationKeys(payload.Data); err != nil {
+		return err
+	}
+
+	_, err := payload.DecodedData()
+	return err
+}
+
+// DecodedData returns the data of the secret payload, decoding its values when they are base64 encoded
+func (payload *K8sSecretPayload) DecodedData() (map[string][]byte, error) {
+	data := make(map[string][]byte, len(payload.Data))
+	for key, value := range payload.Data {
+		if !payload.Base64Encoded {
+			data[key] = []byte(value)
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("the value of the key %q is not valid base64", key)
+		}
+
+		data[key] = decoded
+	}
+
+	return data, nil
+}
+
+func validateConfigurationKeys(data map[string]string) error {
+	if data == nil {
+		return errors.New("missing data from the request payload")
+	}
+
+	for key := range data {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return fmt.Errorf("invalid key %q: %s", key, errs[0])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package kubernetes
+
+import "time"
+
+type (
+	K8sCustomResourceDefinition struct {
+		Name  string `json:"name"`
+		Group string `json:"group"`
+		Kind  string `json:"kind"`
+		// Plural name of the resources, used in the API paths
+		Plural string `json:"plural"`
+		// Scope of the resources, Namespaced or Cluster
+		Scope string `json:"scope"`
+		// Versions served by the API server
+		Versions []string `json:"versions"`
+		// Version used to browse the resources, the storage version when it is served
+		PreferredVersion string    `json:"preferredVersion"`
+		CreationDate     time.Time `json:"creationDate"`
+	}
+
+	K8sCustomResource struct {
+		Name         string            `json:"name"`
+		Namespace    string            `json:"namespace,omitempty"`
+		UID          string            `json:"uid"`
+		Kind         string            `json:"kind"`
+		APIVersion   string            `json:"apiVersion"`
+		Labels       map[string]string `json:"labels,omitempty"`
+		CreationDate time.Time         `json:"creationDate"`
+		// Full content of the resource, only set when a single resource is inspected
+		Manifest map[string]any `json:"manifest,omitempty"`
+	}
+
+	K8sCustomResourceList struct {
+		Items []K8sCustomResource `json:"items"`
+		// Token to pass to retrieve the next page, empty on the last page
+		Continue string `json:"continue,omitempty"`
+		// Number of resources left after this page, when the API server reports it
+		RemainingItemCount *int64 `json:"remainingItemCount,omitempty"`
+	}
+)
@@ -0,0 +1,23 @@
+package kubernetes
+
+import "time"
+
+type (
+	K8sEvent struct {
+		UID            string                 `json:"uid"`
+		Type           string                 `json:"type"`
+		Reason         string                 `json:"reason"`
+		Message        string                 `json:"message"`
+		Namespace      string                 `json:"namespace"`
+		Count          int32                  `json:"count"`
+		FirstTimestamp time.Time              `json:"firstTimestamp"`
+		LastTimestamp  time.Time              `json:"lastTimestamp"`
+		InvolvedObject K8sEventInvolvedObject `json:"involvedObject"`
+	}
+
+	K8sEventInvolvedObject struct {
+		Kind string `json:"kind"`
+		Name string `json:"name"`
+		UID  string `json:"uid"`
+	}
+)
@@ -35,6 +35,32 @@ type (
 	K8sIngressTLS struct {
 		Hosts      []string `json:"Hosts"`
 		SecretName string   `json:"SecretName"`
+		// Certificate held by the TLS secret, omitted when the secret cannot be resolved
+		Certificate *K8sCertificate `json:"Certificate,omitempty"`
+	}
+
+	// K8sCertificate is the leaf certificate of a kubernetes.io/tls secret
+	K8sCertificate struct {
+		Subject         string    `json:"Subject" example:"example.com"`
+		Issuer          string    `json:"Issuer" example:"R3"`
+		DNSNames        []string  `json:"DNSNames"`
+		NotBefore       time.Time `json:"NotBefore"`
+		NotAfter        time.Time `json:"NotAfter"`
+		DaysUntilExpiry int       `json:"DaysUntilExpiry" example:"42"`
+		Expired         bool      `json:"Expired"`
+		// True when the certificate expires within the warning period
+		ExpiringSoon bool `json:"ExpiringSoon"`
+	}
+
+	// K8sIngressCertificate is a certificate referenced by the TLS configuration of an ingress
+	K8sIngressCertificate struct {
+		EndpointID   int            `json:"EndpointId,omitempty" example:"1"`
+		EndpointName string         `json:"EndpointName,omitempty" example:"production"`
+		Namespace    string         `json:"Namespace" example:"default"`
+		IngressName  string         `json:"IngressName" example:"web"`
+		SecretName   string         `json:"SecretName" example:"web-tls"`
+		Hosts        []string       `json:"Hosts"`
+		Certificate  K8sCertificate `json:"Certificate"`
 	}
 
 	K8sIngressPath struct {
@@ -1,6 +1,7 @@
 package kubernetes
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -11,6 +12,7 @@ type K8sNamespaceDetails struct {
 	Name          string            `json:"Name"`
 	Annotations   map[string]string `json:"Annotations"`
 	ResourceQuota *K8sResourceQuota `json:"ResourceQuota"`
+	LimitRange    *K8sLimitRange    `json:"LimitRange"`
 	Owner         string            `json:"Owner"`
 }
 
@@ -20,6 +22,19 @@ type K8sResourceQuota struct {
 	CPU     string `json:"cpu"`
 }
 
+// K8sLimitRange defines the resources assigned to the containers of a namespace that do not define theirs.
+// Containers must define their limits when a resource quota applies to the namespace, the limit range sets
+// the values used for the containers that do not.
+type K8sLimitRange struct {
+	Enabled bool `json:"enabled"`
+	// Default limits of the containers
+	DefaultCPU    string `json:"defaultCpu" example:"500m"`
+	DefaultMemory string `json:"defaultMemory" example:"512Mi"`
+	// Default requests of the containers, the default limits are used when not set
+	DefaultRequestCPU    string `json:"defaultRequestCpu" example:"250m"`
+	DefaultRequestMemory string `json:"defaultRequestMemory" example:"256Mi"`
+}
+
 func (r *K8sNamespaceDetails) Validate(request *http.Request) error {
 	if r.ResourceQuota != nil && r.ResourceQuota.Enabled {
 		if _, err := resource.ParseQuantity(r.ResourceQuota.Memory); err != nil {
@@ -31,5 +46,48 @@ func (r *K8sNamespaceDetails) Validate(request *http.Request) error {
 		}
 	}
 
+	if r.LimitRange != nil && r.LimitRange.Enabled {
+		return r.LimitRange.validate()
+	}
+
+	return nil
+}
+
+func (r *K8sLimitRange) validate() error {
+	if r.DefaultCPU == "" && r.DefaultMemory == "" {
+		return errors.New("the default cpu or memory limit is required")
+	}
+
+	for _, pair := range []struct{ name, limit, request string }{
+		{"cpu", r.DefaultCPU, r.DefaultRequestCPU},
+		{"memory", r.DefaultMemory, r.DefaultRequestMemory},
+	} {
+		if pair.limit == "" {
+			if pair.request != "" {
+				return fmt.Errorf("the default %s request requires a default %s limit", pair.name, pair.name)
+			}
+
+			continue
+		}
+
+		limit, err := resource.ParseQuantity(pair.limit)
+		if err != nil {
+			return fmt.Errorf("error parsing default %s limit value: %w", pair.name, err)
+		}
+
+		if pair.request == "" {
+			continue
+		}
+
+		request, err := resource.ParseQuantity(pair.request)
+		if err != nil {
+			return fmt.Errorf("error parsing default %s request value: %w", pair.name, err)
+		}
+
+		if request.Cmp(limit) > 0 {
+			return fmt.Errorf("the default %s request cannot exceed the default %s limit", pair.name, pair.name)
+		}
+	}
+
 	return nil
 }
@@ -1,9 +1,12 @@
 package kubernetes
 
 import (
+	"errors"
+	"net/http"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 type (
@@ -23,6 +26,7 @@ type (
 		PersistentVolumeReclaimPolicy corev1.PersistentVolumeReclaimPolicy `json:"persistentVolumeReclaimPolicy"`
 		VolumeMode                    *corev1.PersistentVolumeMode         `json:"volumeMode"`
 		CSI                           *corev1.CSIPersistentVolumeSource    `json:"csi,omitempty"`
+		Phase                         corev1.PersistentVolumePhase         `json:"phase,omitempty"`
 	}
 
 	K8sPersistentVolumeClaim struct {
@@ -30,6 +34,7 @@ type (
 		Name               string                              `json:"name"`
 		Namespace          string                              `json:"namespace"`
 		Storage            int64                               `json:"storage"`
+		Capacity           int64                               `json:"capacity"`
 		CreationDate       time.Time                           `json:"creationDate"`
 		AccessModes        []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
 		VolumeName         string                              `json:"volumeName"`
@@ -39,6 +44,19 @@ type (
 		OwningApplications []K8sApplication                    `json:"owningApplications,omitempty"`
 		Phase              corev1.PersistentVolumeClaimPhase   `json:"phase"`
 		Labels             map[string]string                   `json:"labels"`
+		Usage              *K8sVolumeUsage                     `json:"usage,omitempty"`
+	}
+
+	// K8sVolumeUsage is the usage reported by the kubelet for the volumes whose driver exposes metrics
+	K8sVolumeUsage struct {
+		UsedBytes      int64 `json:"usedBytes"`
+		CapacityBytes  int64 `json:"capacityBytes"`
+		AvailableBytes int64 `json:"availableBytes"`
+	}
+
+	K8sVolumeExpandPayload struct {
+		// New requested size of the volume, e.g. 20Gi
+		Size string `json:"size" example:"20Gi"`
 	}
 
 	K8sStorageClass struct {
@@ -48,3 +66,15 @@ type (
 		AllowVolumeExpansion *bool                                 `json:"allowVolumeExpansion"`
 	}
 )
+
+func (payload *K8sVolumeExpandPayload) Validate(request *http.Request) error {
+	if payload.Size == "" {
+		return errors.New("missing volume size from the request payload")
+	}
+
+	if _, err := resource.ParseQuantity(payload.Size); err != nil {
+		return errors.New("invalid volume size, it must be a quantity such as 20Gi")
+	}
+
+	return nil
+}
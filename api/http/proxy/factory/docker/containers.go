@@ -12,7 +12,10 @@ import (
 	"github.com/portainer/portainer/api/http/proxy/factory/utils"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/quotas"
+	"github.com/portainer/portainer/api/stacks/stackutils"
 
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/client"
 	"github.com/segmentio/encoding/json"
 )
@@ -166,6 +169,32 @@ func containerHasBlackListedLabel(containerLabels map[string]any, labelBlackList
 	return false
 }
 
+// isBindMountForbidden returns true when a container mounts a host path that regular users cannot mount,
+// either with the binds or with the bind mounts of its host configuration
+func isBindMountForbidden(securitySettings *portainer.EndpointSecuritySettings, binds []string, mounts []mount.Mount) bool {
+	sources := make([]string, 0, len(binds)+len(mounts))
+
+	for _, bind := range binds {
+		if source, _, _ := strings.Cut(bind, ":"); strings.HasPrefix(source, "/") {
+			sources = append(sources, source)
+		}
+	}
+
+	for _, m := range mounts {
+		if m.Type == mount.TypeBind {
+			sources = append(sources, m.Source)
+		}
+	}
+
+	for _, source := range sources {
+		if !securitySettings.AllowBindMountsForRegularUsers || stackutils.IsBindMountBlocked(securitySettings, source) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (transport *Transport) decorateContainerCreationOperation(request *http.Request, resourceIdentifierAttribute string, resourceType portainer.ResourceControlType) (*http.Response, error) {
 	type PartialContainer struct {
 		HostConfig struct {
@@ -176,6 +205,9 @@ func (transport *Transport) decorateContainerCreationOperation(request *http.Req
 			CapAdd     []string       `json:"CapAdd"`
 			CapDrop    []string       `json:"CapDrop"`
 			Binds      []string       `json:"Binds"`
+			Mounts     []mount.Mount  `json:"Mounts"`
+			NanoCPUs   int64          `json:"NanoCpus"`
+			Memory     int64          `json:"Memory"`
 		} `json:"HostConfig"`
 	}
 
@@ -229,12 +261,18 @@ func (transport *Transport) decorateContainerCreationOperation(request *http.Req
 			return nil, ErrContainerCapabilitiesForbidden
 		}
 
-		if !securitySettings.AllowBindMountsForRegularUsers && (len(partialContainer.HostConfig.Binds) > 0) {
-			for _, bind := range partialContainer.HostConfig.Binds {
-				if strings.HasPrefix(bind, "/") {
-					return forbiddenResponse, ErrBindMountsForbidden
-				}
-			}
+		if isBindMountForbidden(securitySettings, partialContainer.HostConfig.Binds, partialContainer.HostConfig.Mounts) {
+			return forbiddenResponse, ErrBindMountsForbidden
+		}
+
+		requested := quotas.Usage{
+			Containers: 1,
+			NanoCPUs:   partialContainer.HostConfig.NanoCPUs,
+			Memory:     partialContainer.HostConfig.Memory,
+		}
+
+		if response, err := transport.checkQuotas(request, tokenData.ID, requested); response != nil || err != nil {
+			return response, err
 		}
 
 		request.Body = io.NopCloser(bytes.NewBuffer(body))
@@ -0,0 +1,42 @@
+package docker
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_isBindMountForbidden(t *testing.T) {
+	allowed := &portainer.EndpointSecuritySettings{
+		AllowBindMountsForRegularUsers: true,
+		BlockedBindMountPrefixes:       []string{"/etc", "/var/run/docker.sock"},
+	}
+	denied := &portainer.EndpointSecuritySettings{}
+
+	tests := []struct {
+		name             string
+		securitySettings *portainer.EndpointSecuritySettings
+		binds            []string
+		mounts           []mount.Mount
+		expected         bool
+	}{
+		{name: "no mounts", securitySettings: denied},
+		{name: "named volume", securitySettings: denied, binds: []string{"data:/data"}, mounts: []mount.Mount{{Type: mount.TypeVolume, Source: "data", Target: "/data"}}},
+		{name: "bind when bind mounts are denied", securitySettings: denied, binds: []string{"/srv:/srv"}, expected: true},
+		{name: "bind mount when bind mounts are denied", securitySettings: denied, mounts: []mount.Mount{{Type: mount.TypeBind, Source: "/srv", Target: "/srv"}}, expected: true},
+		{name: "allowed bind", securitySettings: allowed, binds: []string{"/srv:/srv:ro"}},
+		{name: "allowed bind mount", securitySettings: allowed, mounts: []mount.Mount{{Type: mount.TypeBind, Source: "/srv", Target: "/srv"}}},
+		{name: "blocked bind", securitySettings: allowed, binds: []string{"/etc/ssl:/etc/ssl"}, expected: true},
+		{name: "blocked bind mount", securitySettings: allowed, mounts: []mount.Mount{{Type: mount.TypeBind, Source: "/var/run/docker.sock", Target: "/var/run/docker.sock"}}, expected: true},
+		{name: "blocked bind mount with a traversal", securitySettings: allowed, mounts: []mount.Mount{{Type: mount.TypeBind, Source: "/srv/../etc", Target: "/host"}}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isBindMountForbidden(tt.securitySettings, tt.binds, tt.mounts))
+		})
+	}
+}
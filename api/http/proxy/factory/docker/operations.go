@@ -0,0 +1,408 @@
+package docker
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/security"
+)
+
+// dockerOperation returns the authorization matching a Docker API request.
+// The path must not contain the API version prefix.
+func dockerOperation(method, unversionedPath string) portainer.Authorization {
+	segments := strings.Split(strings.Trim(unversionedPath, "/"), "/")
+	resource, rest := segments[0], segments[1:]
+
+	switch resource {
+	case "containers":
+		return containerOperation(method, rest)
+	case "images":
+		return imageOperation(method, rest)
+	case "build":
+		return buildOperationAuthorization(rest)
+	case "commit":
+		return portainer.OperationDockerImageCommit
+	case "networks":
+		return crudOperation(method, rest, networkOperations)
+	case "volumes":
+		return crudOperation(method, rest, volumeOperations)
+	case "exec":
+		return execOperation(rest)
+	case "swarm":
+		return swarmOperation(rest)
+	case "nodes":
+		return crudOperation(method, rest, nodeOperations)
+	case "services":
+		return crudOperation(method, rest, serviceOperations)
+	case "secrets":
+		return crudOperation(method, rest, secretOperations)
+	case "configs":
+		return crudOperation(method, rest, configOperations)
+	case "tasks":
+		return crudOperation(method, rest, taskOperations)
+	case "plugins":
+		return pluginOperation(method, rest)
+	case "session":
+		return portainer.OperationDockerSessionStart
+	case "distribution":
+		return portainer.OperationDockerDistributionInspect
+	case "_ping":
+		return portainer.OperationDockerPing
+	case "info":
+		return portainer.OperationDockerInfo
+	case "events":
+		return portainer.OperationDockerEvents
+	case "system":
+		return portainer.OperationDockerSystem
+	case "version":
+		return portainer.OperationDockerVersion
+	case "v2":
+		return agentOperation(rest)
+	}
+
+	return portainer.OperationDockerUndefined
+}
+
+// resourceOperations maps the operations of a Docker resource exposing the common list/inspect/create/update/delete routes
+type resourceOperations struct {
+	list, inspect, create, update, delete, prune, logs portainer.Authorization
+	actions                                            map[string]portainer.Authorization
+}
+
+var networkOperations = resourceOperations{
+	list:    portainer.OperationDockerNetworkList,
+	inspect: portainer.OperationDockerNetworkInspect,
+	create:  portainer.OperationDockerNetworkCreate,
+	delete:  portainer.OperationDockerNetworkDelete,
+	prune:   portainer.OperationDockerNetworkPrune,
+	actions: map[string]portainer.Authorization{
+		"connect":    portainer.OperationDockerNetworkConnect,
+		"disconnect": portainer.OperationDockerNetworkDisconnect,
+	},
+}
+
+var volumeOperations = resourceOperations{
+	list:    portainer.OperationDockerVolumeList,
+	inspect: portainer.OperationDockerVolumeInspect,
+	create:  portainer.OperationDockerVolumeCreate,
+	delete:  portainer.OperationDockerVolumeDelete,
+	prune:   portainer.OperationDockerVolumePrune,
+}
+
+var nodeOperations = resourceOperations{
+	list:    portainer.OperationDockerNodeList,
+	inspect: portainer.OperationDockerNodeInspect,
+	update:  portainer.OperationDockerNodeUpdate,
+	delete:  portainer.OperationDockerNodeDelete,
+}
+
+var serviceOperations = resourceOperations{
+	list:    portainer.OperationDockerServiceList,
+	inspect: portainer.OperationDockerServiceInspect,
+	create:  portainer.OperationDockerServiceCreate,
+	update:  portainer.OperationDockerServiceUpdate,
+	delete:  portainer.OperationDockerServiceDelete,
+	logs:    portainer.OperationDockerServiceLogs,
+}
+
+var secretOperations = resourceOperations{
+	list:    portainer.OperationDockerSecretList,
+	inspect: portainer.OperationDockerSecretInspect,
+	create:  portainer.OperationDockerSecretCreate,
+	update:  portainer.OperationDockerSecretUpdate,
+	delete:  portainer.OperationDockerSecretDelete,
+}
+
+var configOperations = resourceOperations{
+	list:    portainer.OperationDockerConfigList,
+	inspect: portainer.OperationDockerConfigInspect,
+	create:  portainer.OperationDockerConfigCreate,
+	update:  portainer.OperationDockerConfigUpdate,
+	delete:  portainer.OperationDockerConfigDelete,
+}
+
+var taskOperations = resourceOperations{
+	list:    portainer.OperationDockerTaskList,
+	inspect: portainer.OperationDockerTaskInspect,
+	logs:    portainer.OperationDockerTaskLogs,
+}
+
+func crudOperation(method string, rest []string, operations resourceOperations) portainer.Authorization {
+	var operation portainer.Authorization
+
+	switch {
+	case len(rest) == 0 || rest[0] == "" || rest[0] == "json":
+		operation = operations.list
+	case rest[0] == "create":
+		operation = operations.create
+	case rest[0] == "prune":
+		operation = operations.prune
+	case len(rest) == 1 && method == http.MethodDelete:
+		operation = operations.delete
+	case len(rest) == 1:
+		operation = operations.inspect
+	case rest[1] == "update":
+		operation = operations.update
+	case rest[1] == "logs":
+		operation = operations.logs
+	default:
+		operation = operations.actions[rest[1]]
+	}
+
+	if operation == "" {
+		return portainer.OperationDockerUndefined
+	}
+
+	return operation
+}
+
+var containerActionOperations = map[string]portainer.Authorization{
+	"top":     portainer.OperationDockerContainerTop,
+	"logs":    portainer.OperationDockerContainerLogs,
+	"changes": portainer.OperationDockerContainerChanges,
+	"export":  portainer.OperationDockerContainerExport,
+	"stats":   portainer.OperationDockerContainerStats,
+	"resize":  portainer.OperationDockerContainerResize,
+	"start":   portainer.OperationDockerContainerStart,
+	"stop":    portainer.OperationDockerContainerStop,
+	"restart": portainer.OperationDockerContainerRestart,
+	"kill":    portainer.OperationDockerContainerKill,
+	"update":  portainer.OperationDockerContainerUpdate,
+	"rename":  portainer.OperationDockerContainerRename,
+	"pause":   portainer.OperationDockerContainerPause,
+	"unpause": portainer.OperationDockerContainerUnpause,
+	"attach":  portainer.OperationDockerContainerAttach,
+	"wait":    portainer.OperationDockerContainerWait,
+	"exec":    portainer.OperationDockerContainerExec,
+}
+
+func containerOperation(method string, rest []string) portainer.Authorization {
+	switch {
+	case len(rest) == 0 || rest[0] == "json":
+		return portainer.OperationDockerContainerList
+	case rest[0] == "create":
+		return portainer.OperationDockerContainerCreate
+	case rest[0] == "prune":
+		return portainer.OperationDockerContainerPrune
+	case len(rest) == 1 && method == http.MethodDelete:
+		return portainer.OperationDockerContainerDelete
+	case len(rest) == 1 || rest[1] == "json":
+		return portainer.OperationDockerContainerInspect
+	case rest[1] == "attach" && len(rest) > 2 && rest[2] == "ws":
+		return portainer.OperationDockerContainerAttachWebsocket
+	case rest[1] == "archive":
+		switch method {
+		case http.MethodHead:
+			return portainer.OperationDockerContainerArchiveInfo
+		case http.MethodPut:
+			return portainer.OperationDockerContainerPutContainerArchive
+		default:
+			return portainer.OperationDockerContainerArchive
+		}
+	}
+
+	if operation, ok := containerActionOperations[rest[1]]; ok {
+		return operation
+	}
+
+	return portainer.OperationDockerUndefined
+}
+
+// imageOperation handles image names containing slashes by matching the last segment of the path
+func imageOperation(method string, rest []string) portainer.Authorization {
+	if len(rest) == 0 {
+		return portainer.OperationDockerImageList
+	}
+
+	if len(rest) == 1 {
+		switch rest[0] {
+		case "json":
+			return portainer.OperationDockerImageList
+		case "create":
+			return portainer.OperationDockerImageCreate
+		case "search":
+			return portainer.OperationDockerImageSearch
+		case "prune":
+			return portainer.OperationDockerImagePrune
+		case "get":
+			return portainer.OperationDockerImageGetAll
+		case "load":
+			return portainer.OperationDockerImageLoad
+		}
+	}
+
+	if method == http.MethodDelete {
+		return portainer.OperationDockerImageDelete
+	}
+
+	switch rest[len(rest)-1] {
+	case "json":
+		return portainer.OperationDockerImageInspect
+	case "history":
+		return portainer.OperationDockerImageHistory
+	case "push":
+		return portainer.OperationDockerImagePush
+	case "tag":
+		return portainer.OperationDockerImageTag
+	case "get":
+		return portainer.OperationDockerImageGet
+	}
+
+	return portainer.OperationDockerUndefined
+}
+
+func buildOperationAuthorization(rest []string) portainer.Authorization {
+	if len(rest) == 0 {
+		return portainer.OperationDockerImageBuild
+	}
+
+	switch rest[0] {
+	case "prune":
+		return portainer.OperationDockerBuildPrune
+	case "cancel":
+		return portainer.OperationDockerBuildCancel
+	}
+
+	return portainer.OperationDockerUndefined
+}
+
+func execOperation(rest []string) portainer.Authorization {
+	if len(rest) < 2 {
+		return portainer.OperationDockerUndefined
+	}
+
+	switch rest[1] {
+	case "json":
+		return portainer.OperationDockerExecInspect
+	case "start":
+		return portainer.OperationDockerExecStart
+	case "resize":
+		return portainer.OperationDockerExecResize
+	}
+
+	return portainer.OperationDockerUndefined
+}
+
+func swarmOperation(rest []string) portainer.Authorization {
+	if len(rest) == 0 {
+		return portainer.OperationDockerSwarmInspect
+	}
+
+	switch rest[0] {
+	case "unlockkey":
+		return portainer.OperationDockerSwarmUnlockKey
+	case "init":
+		return portainer.OperationDockerSwarmInit
+	case "join":
+		return portainer.OperationDockerSwarmJoin
+	case "leave":
+		return portainer.OperationDockerSwarmLeave
+	case "update":
+		return portainer.OperationDockerSwarmUpdate
+	case "unlock":
+		return portainer.OperationDockerSwarmUnlock
+	}
+
+	return portainer.OperationDockerUndefined
+}
+
+var pluginActionOperations = map[string]portainer.Authorization{
+	"json":    portainer.OperationDockerPluginInspect,
+	"enable":  portainer.OperationDockerPluginEnable,
+	"disable": portainer.OperationDockerPluginDisable,
+	"upgrade": portainer.OperationDockerPluginUpgrade,
+	"push":    portainer.OperationDockerPluginPush,
+	"set":     portainer.OperationDockerPluginSet,
+}
+
+func pluginOperation(method string, rest []string) portainer.Authorization {
+	if len(rest) == 0 {
+		return portainer.OperationDockerPluginList
+	}
+
+	switch rest[0] {
+	case "privileges":
+		return portainer.OperationDockerPluginPrivileges
+	case "pull":
+		return portainer.OperationDockerPluginPull
+	case "create":
+		return portainer.OperationDockerPluginCreate
+	}
+
+	if method == http.MethodDelete {
+		return portainer.OperationDockerPluginDelete
+	}
+
+	if operation, ok := pluginActionOperations[rest[len(rest)-1]]; ok {
+		return operation
+	}
+
+	return portainer.OperationDockerUndefined
+}
+
+var agentBrowseOperations = map[string]portainer.Authorization{
+	"delete": portainer.OperationDockerAgentBrowseDelete,
+	"get":    portainer.OperationDockerAgentBrowseGet,
+	"ls":     portainer.OperationDockerAgentBrowseList,
+	"put":    portainer.OperationDockerAgentBrowsePut,
+	"rename": portainer.OperationDockerAgentBrowseRename,
+}
+
+func agentOperation(rest []string) portainer.Authorization {
+	if len(rest) == 0 {
+		return portainer.OperationDockerAgentUndefined
+	}
+
+	switch rest[0] {
+	case "ping":
+		return portainer.OperationDockerAgentPing
+	case "agents":
+		return portainer.OperationDockerAgentList
+	case "host":
+		return portainer.OperationDockerAgentHostInfo
+	case "browse":
+		if len(rest) > 1 {
+			if operation, ok := agentBrowseOperations[rest[1]]; ok {
+				return operation
+			}
+		}
+	}
+
+	return portainer.OperationDockerAgentUndefined
+}
+
+// isOperationDenied verifies whether the Docker operation of the request is part of the operations denied
+// to non-administrators on the environment or on its group.
+// Requests without JWT data are issued by Portainer itself and are never denied.
+func (transport *Transport) isOperationDenied(request *http.Request, unversionedPath string) (bool, error) {
+	tokenData, err := security.RetrieveTokenData(request)
+	if err != nil || tokenData.Role == portainer.AdministratorRole {
+		return false, nil
+	}
+
+	endpoint, err := transport.dataStore.Endpoint().Endpoint(transport.endpoint.ID)
+	if err != nil {
+		return false, err
+	}
+
+	deniedOperations := endpoint.SecuritySettings.DeniedOperationsForRegularUsers
+
+	if endpoint.GroupID != 0 {
+		group, err := transport.dataStore.EndpointGroup().Read(endpoint.GroupID)
+		if err != nil && !transport.dataStore.IsErrObjectNotFound(err) {
+			return false, err
+		}
+
+		if group != nil {
+			deniedOperations = append(slices.Clone(deniedOperations), group.DeniedOperationsForRegularUsers...)
+		}
+	}
+
+	if len(deniedOperations) == 0 {
+		return false, nil
+	}
+
+	return slices.Contains(deniedOperations, dockerOperation(request.Method, unversionedPath)), nil
+}
@@ -0,0 +1,52 @@
+package docker
+
+import (
+	"net/http"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_dockerOperation(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		expected portainer.Authorization
+	}{
+		{http.MethodGet, "/containers/json", portainer.OperationDockerContainerList},
+		{http.MethodPost, "/containers/create", portainer.OperationDockerContainerCreate},
+		{http.MethodGet, "/containers/abc/json", portainer.OperationDockerContainerInspect},
+		{http.MethodPost, "/containers/abc/start", portainer.OperationDockerContainerStart},
+		{http.MethodDelete, "/containers/abc", portainer.OperationDockerContainerDelete},
+		{http.MethodPut, "/containers/abc/archive", portainer.OperationDockerContainerPutContainerArchive},
+		{http.MethodHead, "/containers/abc/archive", portainer.OperationDockerContainerArchiveInfo},
+		{http.MethodGet, "/containers/abc/attach/ws", portainer.OperationDockerContainerAttachWebsocket},
+		{http.MethodPost, "/build", portainer.OperationDockerImageBuild},
+		{http.MethodPost, "/build/prune", portainer.OperationDockerBuildPrune},
+		{http.MethodGet, "/images/json", portainer.OperationDockerImageList},
+		{http.MethodPost, "/images/create", portainer.OperationDockerImageCreate},
+		{http.MethodGet, "/images/library/nginx/json", portainer.OperationDockerImageInspect},
+		{http.MethodPost, "/images/registry.local/team/app/push", portainer.OperationDockerImagePush},
+		{http.MethodDelete, "/images/library/nginx", portainer.OperationDockerImageDelete},
+		{http.MethodGet, "/networks", portainer.OperationDockerNetworkList},
+		{http.MethodPost, "/networks/abc/connect", portainer.OperationDockerNetworkConnect},
+		{http.MethodDelete, "/volumes/data", portainer.OperationDockerVolumeDelete},
+		{http.MethodPost, "/volumes/prune", portainer.OperationDockerVolumePrune},
+		{http.MethodPost, "/exec/abc/start", portainer.OperationDockerExecStart},
+		{http.MethodPost, "/swarm/leave", portainer.OperationDockerSwarmLeave},
+		{http.MethodPost, "/services/abc/update", portainer.OperationDockerServiceUpdate},
+		{http.MethodGet, "/services/abc/logs", portainer.OperationDockerServiceLogs},
+		{http.MethodGet, "/tasks/abc", portainer.OperationDockerTaskInspect},
+		{http.MethodPost, "/plugins/vieux/sshfs/enable", portainer.OperationDockerPluginEnable},
+		{http.MethodGet, "/_ping", portainer.OperationDockerPing},
+		{http.MethodGet, "/system/df", portainer.OperationDockerSystem},
+		{http.MethodGet, "/v2/browse/ls", portainer.OperationDockerAgentBrowseList},
+		{http.MethodGet, "/v2/unknown", portainer.OperationDockerAgentUndefined},
+		{http.MethodGet, "/unknown", portainer.OperationDockerUndefined},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, dockerOperation(test.method, test.path), "%s %s", test.method, test.path)
+	}
+}
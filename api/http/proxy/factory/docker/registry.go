@@ -1,8 +1,11 @@
 package docker
 
 import (
+	"strings"
+
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/docker/images"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/registryutils"
 )
@@ -14,6 +17,9 @@ type (
 		endpointID      portainer.EndpointID
 		teamMemberships []portainer.TeamMembership
 		registries      []portainer.Registry
+		// default registries of the teams of the user, their credentials are used even though the user
+		// is not authorized to access them
+		defaultRegistryIDs map[portainer.RegistryID]bool
 	}
 
 	registryAuthenticationHeader struct {
@@ -43,7 +49,7 @@ func createRegistryAuthenticationHeader(
 
 	for _, registry := range accessContext.registries {
 		if registry.ID == registryID &&
-			(accessContext.isAdmin ||
+			(accessContext.isAdmin || accessContext.defaultRegistryIDs[registry.ID] ||
 				security.AuthorizedRegistryAccess(&registry, accessContext.user, accessContext.teamMemberships, accessContext.endpointID)) {
 			matchingRegistry = &registry
 
@@ -64,3 +70,23 @@ func createRegistryAuthenticationHeader(
 
 	return
 }
+
+// findTeamDefaultRegistry returns the default registry of the teams of the user hosting an image
+func findTeamDefaultRegistry(imageName string, accessContext *registryAccessContext) *portainer.Registry {
+	if len(accessContext.defaultRegistryIDs) == 0 {
+		return nil
+	}
+
+	image, err := images.ParseImage(images.ParseImageOptions{Name: imageName})
+	if err != nil {
+		return nil
+	}
+
+	for _, registry := range accessContext.registries {
+		if accessContext.defaultRegistryIDs[registry.ID] && strings.HasPrefix(image.Name(), strings.TrimSuffix(registry.URL, "/")+"/") {
+			return &registry
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package docker
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindTeamDefaultRegistry(t *testing.T) {
+	accessContext := &registryAccessContext{
+		registries: []portainer.Registry{
+			{ID: 1, URL: "registry.example.com"},
+			{ID: 2, URL: "registry.example.com:5000/"},
+			{ID: 3, URL: "docker.io"},
+		},
+		defaultRegistryIDs: map[portainer.RegistryID]bool{2: true, 3: true},
+	}
+
+	registry := findTeamDefaultRegistry("registry.example.com:5000/team/app:1.0", accessContext)
+	require.NotNil(t, registry)
+	assert.Equal(t, portainer.RegistryID(2), registry.ID)
+
+	registry = findTeamDefaultRegistry("nginx", accessContext)
+	require.NotNil(t, registry)
+	assert.Equal(t, portainer.RegistryID(3), registry.ID)
+
+	// The registry is not a default registry of the teams of the user
+	assert.Nil(t, findTeamDefaultRegistry("registry.example.com/app", accessContext))
+
+	assert.Nil(t, findTeamDefaultRegistry("nginx", &registryAccessContext{registries: accessContext.registries}))
+}
@@ -9,9 +9,13 @@ import (
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/http/proxy/factory/utils"
+	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/quotas"
+	"github.com/portainer/portainer/api/stacks/stackutils"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/swarm"
 	"github.com/docker/docker/client"
 	"github.com/segmentio/encoding/json"
 )
@@ -91,12 +95,15 @@ func selectorServiceLabels(responseObject map[string]any) map[string]any {
 
 func (transport *Transport) decorateServiceCreationOperation(request *http.Request) (*http.Response, error) {
 	type PartialService struct {
+		Mode         swarm.ServiceMode
 		TaskTemplate struct {
 			ContainerSpec struct {
 				Mounts []struct {
-					Type string
+					Type   string
+					Source string
 				}
 			}
+			Resources *swarm.ResourceRequirements
 		}
 	}
 
@@ -128,14 +135,30 @@ func (transport *Transport) decorateServiceCreationOperation(request *http.Reque
 		return nil, err
 	}
 
-	if !securitySettings.AllowBindMountsForRegularUsers && (len(partialService.TaskTemplate.ContainerSpec.Mounts) > 0) {
-		for _, mount := range partialService.TaskTemplate.ContainerSpec.Mounts {
-			if mount.Type == "bind" {
-				return forbiddenResponse, errors.New("forbidden to use bind mounts")
-			}
+	for _, mount := range partialService.TaskTemplate.ContainerSpec.Mounts {
+		if mount.Type == "bind" && (!securitySettings.AllowBindMountsForRegularUsers || stackutils.IsBindMountBlocked(securitySettings, mount.Source)) {
+			return forbiddenResponse, errors.New("forbidden to use bind mounts")
 		}
 	}
 
+	tokenData, err := security.RetrieveTokenData(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// The number of nodes is not known here, a global service is counted as a single task
+	replicas := quotas.ServiceReplicas(partialService.Mode, 1)
+	requested := quotas.Usage{Containers: replicas}
+
+	if resources := partialService.TaskTemplate.Resources; resources != nil && resources.Limits != nil {
+		requested.NanoCPUs = int64(replicas) * resources.Limits.NanoCPUs
+		requested.Memory = int64(replicas) * resources.Limits.MemoryBytes
+	}
+
+	if response, err := transport.checkQuotas(request, tokenData.ID, requested); response != nil || err != nil {
+		return response, err
+	}
+
 	request.Body = io.NopCloser(bytes.NewBuffer(body))
 
 	return transport.replaceRegistryAuthenticationHeader(request)
@@ -17,6 +17,8 @@ import (
 	"github.com/portainer/portainer/api/http/proxy/factory/utils"
 	"github.com/portainer/portainer/api/http/security"
 	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/quotas"
+	"github.com/portainer/portainer/api/internal/registryutils/access"
 
 	dockerclient "github.com/portainer/portainer/api/docker/client"
 	"github.com/rs/zerolog/log"
@@ -37,6 +39,7 @@ type (
 		dockerClientFactory  *dockerclient.ClientFactory
 		gitService           portainer.GitService
 		snapshotService      portainer.SnapshotService
+		quotaService         *quotas.Service
 	}
 
 	// TransportParameters is used to create a new Transport
@@ -46,6 +49,7 @@ type (
 		SignatureService     portainer.DigitalSignatureService
 		ReverseTunnelService portainer.ReverseTunnelService
 		DockerClientFactory  *dockerclient.ClientFactory
+		QuotaService         *quotas.Service
 	}
 
 	restrictedDockerOperationContext struct {
@@ -75,6 +79,7 @@ func NewTransport(parameters *TransportParameters, httpTransport *http.Transport
 		HTTPTransport:        httpTransport,
 		gitService:           gitService,
 		snapshotService:      snapshotService,
+		quotaService:         parameters.QuotaService,
 	}
 
 	return transport, nil
@@ -115,6 +120,15 @@ func (transport *Transport) ProxyDockerRequest(request *http.Request) (*http.Res
 		request.Header.Set(portainer.PortainerAgentSignatureHeader, signature)
 	}
 
+	denied, err := transport.isOperationDenied(request, unversionedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if denied {
+		return utils.WriteAccessDeniedResponse()
+	}
+
 	prefix := strings.Split(strings.TrimPrefix(unversionedPath, "/"), "/")[0]
 
 	if proxyFunc := prefixProxyFuncMap[prefix]; proxyFunc != nil {
@@ -415,7 +429,7 @@ func (transport *Transport) updateDefaultGitBranch(request *http.Request) error
 	}
 
 	repositoryURL := remote[:len(remote)-4]
-	latestCommitID, err := transport.gitService.LatestCommitID(repositoryURL, "", "", "", false)
+	latestCommitID, err := transport.gitService.LatestCommitID(repositoryURL, "", "", "", "", "", false)
 	if err != nil {
 		return err
 	}
@@ -459,7 +473,7 @@ func (transport *Transport) decorateRegistryAuthenticationHeader(request *http.R
 	originalHeader := request.Header.Get("X-Registry-Auth")
 
 	if originalHeader == "" {
-		return nil
+		return transport.decorateTeamDefaultRegistryAuthenticationHeader(request, accessContext)
 	}
 
 	decodedHeaderData, err := base64.StdEncoding.DecodeString(originalHeader)
@@ -478,7 +492,7 @@ func (transport *Transport) decorateRegistryAuthenticationHeader(request *http.R
 	if string(decodedHeaderData) == "{}" {
 		request.Header.Del("X-Registry-Auth")
 
-		return nil
+		return transport.decorateTeamDefaultRegistryAuthenticationHeader(request, accessContext)
 	}
 
 	// Only set X-Registry-Auth if registryId is defined
@@ -486,7 +500,27 @@ func (transport *Transport) decorateRegistryAuthenticationHeader(request *http.R
 		return nil
 	}
 
-	authenticationHeader, err := createRegistryAuthenticationHeader(transport.dataStore, *originalHeaderData.RegistryId, accessContext)
+	return transport.setRegistryAuthenticationHeader(request, *originalHeaderData.RegistryId, accessContext)
+}
+
+// decorateTeamDefaultRegistryAuthenticationHeader uses the credentials of the default registry of the teams of the user
+// hosting the pulled image, when no registry is selected
+func (transport *Transport) decorateTeamDefaultRegistryAuthenticationHeader(request *http.Request, accessContext *registryAccessContext) error {
+	imageName := request.URL.Query().Get("fromImage")
+	if imageName == "" {
+		return nil
+	}
+
+	registry := findTeamDefaultRegistry(imageName, accessContext)
+	if registry == nil {
+		return nil
+	}
+
+	return transport.setRegistryAuthenticationHeader(request, registry.ID, accessContext)
+}
+
+func (transport *Transport) setRegistryAuthenticationHeader(request *http.Request, registryID portainer.RegistryID, accessContext *registryAccessContext) error {
+	authenticationHeader, err := createRegistryAuthenticationHeader(transport.dataStore, registryID, accessContext)
 	if err != nil {
 		return err
 	}
@@ -751,6 +785,11 @@ func (transport *Transport) createRegistryAccessContext(request *http.Request) (
 
 	accessContext.teamMemberships = teamMemberships
 
+	accessContext.defaultRegistryIDs, err = access.TeamDefaultRegistryIDs(transport.dataStore, teamMemberships)
+	if err != nil {
+		return nil, err
+	}
+
 	return accessContext, nil
 }
 
@@ -802,6 +841,27 @@ func (transport *Transport) isAdminOrEndpointAdmin(request *http.Request) (bool,
 	return tokenData.Role == portainer.AdministratorRole, nil
 }
 
+// checkQuotas returns a forbidden response when deploying the requested resources on the environment
+// would not comply with the quotas that apply to the user
+func (transport *Transport) checkQuotas(request *http.Request, userID portainer.UserID, requested quotas.Usage) (*http.Response, error) {
+	if transport.quotaService == nil {
+		return nil, nil
+	}
+
+	endpoint, err := transport.dataStore.Endpoint().Endpoint(transport.endpoint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := transport.quotaService.Check(request.Context(), endpoint, userID, requested); quotas.IsViolation(err) {
+		return utils.WriteErrorResponse(err.Error(), http.StatusForbidden)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
 func (transport *Transport) fetchEndpointSecuritySettings() (*portainer.EndpointSecuritySettings, error) {
 	endpoint, err := transport.dataStore.Endpoint().Endpoint(transport.endpoint.ID)
 	if err != nil {
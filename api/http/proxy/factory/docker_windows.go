@@ -19,6 +19,7 @@ func (factory ProxyFactory) newOSBasedLocalProxy(path string, endpoint *portaine
 		ReverseTunnelService: factory.reverseTunnelService,
 		SignatureService:     factory.signatureService,
 		DockerClientFactory:  factory.dockerClientFactory,
+		QuotaService:         factory.quotaService,
 	}
 
 	proxy := &dockerLocalProxy{}
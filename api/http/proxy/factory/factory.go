@@ -7,6 +7,7 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 	dockerclient "github.com/portainer/portainer/api/docker/client"
 	"github.com/portainer/portainer/api/http/proxy/factory/kubernetes"
+	"github.com/portainer/portainer/api/internal/quotas"
 
 	"github.com/portainer/portainer/api/kubernetes/cli"
 )
@@ -16,28 +17,32 @@ const azureAPIBaseURL = "https://management.azure.com"
 type (
 	// ProxyFactory is a factory to create reverse proxies
 	ProxyFactory struct {
-		dataStore                   dataservices.DataStore
-		signatureService            portainer.DigitalSignatureService
-		reverseTunnelService        portainer.ReverseTunnelService
-		dockerClientFactory         *dockerclient.ClientFactory
-		kubernetesClientFactory     *cli.ClientFactory
-		kubernetesTokenCacheManager *kubernetes.TokenCacheManager
-		gitService                  portainer.GitService
-		snapshotService             portainer.SnapshotService
+		dataStore                      dataservices.DataStore
+		signatureService               portainer.DigitalSignatureService
+		reverseTunnelService           portainer.ReverseTunnelService
+		dockerClientFactory            *dockerclient.ClientFactory
+		kubernetesClientFactory        *cli.ClientFactory
+		kubernetesTokenCacheManager    *kubernetes.TokenCacheManager
+		kubernetesClusterAdminAuditLog *kubernetes.ClusterAdminAuditLog
+		gitService                     portainer.GitService
+		snapshotService                portainer.SnapshotService
+		quotaService                   *quotas.Service
 	}
 )
 
 // NewProxyFactory returns a pointer to a new instance of a ProxyFactory
-func NewProxyFactory(dataStore dataservices.DataStore, signatureService portainer.DigitalSignatureService, tunnelService portainer.ReverseTunnelService, clientFactory *dockerclient.ClientFactory, kubernetesClientFactory *cli.ClientFactory, kubernetesTokenCacheManager *kubernetes.TokenCacheManager, gitService portainer.GitService, snapshotService portainer.SnapshotService) *ProxyFactory {
+func NewProxyFactory(dataStore dataservices.DataStore, signatureService portainer.DigitalSignatureService, tunnelService portainer.ReverseTunnelService, clientFactory *dockerclient.ClientFactory, kubernetesClientFactory *cli.ClientFactory, kubernetesTokenCacheManager *kubernetes.TokenCacheManager, kubernetesClusterAdminAuditLog *kubernetes.ClusterAdminAuditLog, gitService portainer.GitService, snapshotService portainer.SnapshotService, quotaService *quotas.Service) *ProxyFactory {
 	return &ProxyFactory{
-		dataStore:                   dataStore,
-		signatureService:            signatureService,
-		reverseTunnelService:        tunnelService,
-		dockerClientFactory:         clientFactory,
-		kubernetesClientFactory:     kubernetesClientFactory,
-		kubernetesTokenCacheManager: kubernetesTokenCacheManager,
-		gitService:                  gitService,
-		snapshotService:             snapshotService,
+		dataStore:                      dataStore,
+		signatureService:               signatureService,
+		reverseTunnelService:           tunnelService,
+		dockerClientFactory:            clientFactory,
+		kubernetesClientFactory:        kubernetesClientFactory,
+		kubernetesTokenCacheManager:    kubernetesTokenCacheManager,
+		kubernetesClusterAdminAuditLog: kubernetesClusterAdminAuditLog,
+		gitService:                     gitService,
+		snapshotService:                snapshotService,
+		quotaService:                   quotaService,
 	}
 }
 
@@ -0,0 +1,219 @@
+package kubernetes
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/proxy/factory/utils"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+
+	"github.com/rs/zerolog/log"
+)
+
+// namespacedPathRe matches the requests on the resources of a namespace, for the core API and the API groups
+var namespacedPathRe = regexp.MustCompile(`^(?:/kubernetes)?/(?:api/v[0-9][^/]*|apis/[^/]+/[^/]+)/namespaces/([^/]+)`)
+
+// namespacesPathRe matches the requests on the namespaces themselves
+var namespacesPathRe = regexp.MustCompile(`^(?:/kubernetes)?/api/v[0-9][^/]*/namespaces/?$`)
+
+type kubernetesRequestContext struct {
+	isAdmin          bool
+	userID           portainer.UserID
+	userTeamIDs      []portainer.TeamID
+	resourceControls []portainer.ResourceControl
+}
+
+func (transport *baseTransport) createKubernetesRequestContext(request *http.Request) (*kubernetesRequestContext, error) {
+	tokenData, err := security.RetrieveTokenData(request)
+	if err != nil {
+		return nil, err
+	}
+
+	context := &kubernetesRequestContext{
+		isAdmin: tokenData.Role == portainer.AdministratorRole,
+		userID:  tokenData.ID,
+	}
+
+	if context.isAdmin {
+		return context, nil
+	}
+
+	resourceControls, err := transport.dataStore.ResourceControl().ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resourceControl := range resourceControls {
+		if resourceControl.Type == portainer.KubernetesNamespaceResourceControl {
+			context.resourceControls = append(context.resourceControls, resourceControl)
+		}
+	}
+
+	teamMemberships, err := transport.dataStore.TeamMembership().TeamMembershipsByUserID(tokenData.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, membership := range teamMemberships {
+		context.userTeamIDs = append(context.userTeamIDs, membership.TeamID)
+	}
+
+	return context, nil
+}
+
+// findNamespaceResourceControl returns the resource control of the namespace of the environment, if any
+func (transport *baseTransport) findNamespaceResourceControl(namespace string, context *kubernetesRequestContext) *portainer.ResourceControl {
+	resourceID := authorization.KubernetesNamespaceResourceControlID(transport.endpoint.ID, namespace)
+
+	return authorization.GetResourceControlByResourceIDAndType(resourceID, portainer.KubernetesNamespaceResourceControl, context.resourceControls)
+}
+
+// userCanAccessNamespace returns true when the user can access the namespace and the workloads it holds,
+// the namespaces without resource control are only restricted by the access policies of the environment
+func (transport *baseTransport) userCanAccessNamespace(namespace string, context *kubernetesRequestContext) bool {
+	if context.isAdmin {
+		return true
+	}
+
+	resourceControl := transport.findNamespaceResourceControl(namespace, context)
+	if resourceControl == nil {
+		return true
+	}
+
+	return authorization.UserCanAccessResource(context.userID, context.userTeamIDs, resourceControl)
+}
+
+// hasRestrictedNamespaces returns true when some namespaces of the environment are hidden from the user
+func (transport *baseTransport) hasRestrictedNamespaces(context *kubernetesRequestContext) bool {
+	if context.isAdmin {
+		return false
+	}
+
+	prefix := authorization.KubernetesNamespaceResourceControlID(transport.endpoint.ID, "")
+	for i := range context.resourceControls {
+		if strings.HasPrefix(context.resourceControls[i].ResourceID, prefix) && !authorization.UserCanAccessResource(context.userID, context.userTeamIDs, &context.resourceControls[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// proxyAccessControlledRequest enforces the resource controls of the namespaces like the ones of the Docker resources:
+// the requests on a namespace the user cannot access are denied, as are the requests on the workloads it holds,
+// and the namespace is left out of the lists spanning the whole cluster
+func (transport *baseTransport) proxyAccessControlledRequest(request *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	context, err := transport.createKubernetesRequestContext(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if context.isAdmin {
+		return next(request)
+	}
+
+	if match := namespacedPathRe.FindStringSubmatch(request.URL.Path); match != nil {
+		if !transport.userCanAccessNamespace(match[1], context) {
+			return utils.WriteAccessDeniedResponse()
+		}
+
+		return next(request)
+	}
+
+	if request.Method == http.MethodPost && namespacesPathRe.MatchString(request.URL.Path) {
+		return transport.proxyNamespaceCreateRequest(request, context, next)
+	}
+
+	if request.Method != http.MethodGet || !transport.hasRestrictedNamespaces(context) {
+		return next(request)
+	}
+
+	// The events of the watches are streamed and cannot be filtered
+	if request.URL.Query().Get("watch") == "true" || strings.Contains(request.URL.Path, "/watch/") {
+		return utils.WriteAccessDeniedResponse()
+	}
+
+	request.Header.Set("Accept", "application/json")
+
+	response, err := next(request)
+	if err != nil || response.StatusCode != http.StatusOK {
+		return response, err
+	}
+
+	return response, transport.filterNamespacedItems(response, namespacesPathRe.MatchString(request.URL.Path), context)
+}
+
+// filterNamespacedItems removes the items of a list that belong to the namespaces the user cannot access
+func (transport *baseTransport) filterNamespacedItems(response *http.Response, namespaces bool, context *kubernetesRequestContext) error {
+	list, err := utils.GetResponseAsJSONObject(response)
+	if err != nil || list == nil {
+		return err
+	}
+
+	items, ok := list["items"].([]any)
+	if !ok {
+		return utils.RewriteResponse(response, list, response.StatusCode)
+	}
+
+	filteredItems := make([]any, 0, len(items))
+	for _, item := range items {
+		object, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		metadata := utils.GetJSONObject(object, "metadata")
+
+		namespace, _ := metadata["namespace"].(string)
+		if namespaces {
+			namespace, _ = metadata["name"].(string)
+		}
+
+		if namespace == "" || transport.userCanAccessNamespace(namespace, context) {
+			filteredItems = append(filteredItems, item)
+		}
+	}
+
+	list["items"] = filteredItems
+
+	return utils.RewriteResponse(response, list, response.StatusCode)
+}
+
+// proxyNamespaceCreateRequest makes the user the owner of the namespace it creates
+func (transport *baseTransport) proxyNamespaceCreateRequest(request *http.Request, context *kubernetesRequestContext, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	request.Header.Set("Accept", "application/json")
+
+	response, err := next(request)
+	if err != nil || response.StatusCode != http.StatusCreated {
+		return response, err
+	}
+
+	namespace, err := utils.GetResponseAsJSONObject(response)
+	if err != nil || namespace == nil {
+		return response, err
+	}
+
+	name, _ := utils.GetJSONObject(namespace, "metadata")["name"].(string)
+	if name != "" {
+		resourceID := authorization.KubernetesNamespaceResourceControlID(transport.endpoint.ID, name)
+
+		// A resource control left by a namespace of the same name, deleted outside of Portainer, is replaced
+		if resourceControl := transport.findNamespaceResourceControl(name, context); resourceControl != nil {
+			if err := transport.dataStore.ResourceControl().Delete(resourceControl.ID); err != nil {
+				return response, err
+			}
+		}
+
+		resourceControl := authorization.NewPrivateResourceControl(resourceID, portainer.KubernetesNamespaceResourceControl, context.userID)
+		if err := transport.dataStore.ResourceControl().Create(resourceControl); err != nil {
+			log.Error().Str("namespace", name).Err(err).Msg("unable to persist resource control")
+
+			return response, err
+		}
+	}
+
+	return response, utils.RewriteResponse(response, namespace, response.StatusCode)
+}
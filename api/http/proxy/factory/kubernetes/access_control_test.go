@@ -0,0 +1,92 @@
+package kubernetes
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/authorization"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUserRequest(method, path string, userID portainer.UserID, role portainer.UserRole) *http.Request {
+	request := httptest.NewRequest(method, path, nil)
+
+	return request.WithContext(security.StoreTokenData(request, &portainer.TokenData{ID: userID, Role: role}))
+}
+
+func jsonResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+	}
+}
+
+func TestNamespaceAccessControl(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.ResourceControl().Create(authorization.NewPrivateResourceControl(
+		authorization.KubernetesNamespaceResourceControlID(1, "finance"), portainer.KubernetesNamespaceResourceControl, 2,
+	)))
+
+	transport := &baseTransport{endpoint: &portainer.Endpoint{ID: 1}, dataStore: store}
+
+	next := func(*http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusOK, `{"kind":"PodList","items":[{"metadata":{"name":"api","namespace":"finance"}},{"metadata":{"name":"web","namespace":"default"}}]}`), nil
+	}
+
+	// The workloads of the namespace are restricted, whatever their API group
+	response, err := transport.proxyAccessControlledRequest(newUserRequest(http.MethodGet, "/kubernetes/apis/batch/v1/namespaces/finance/jobs", 3, portainer.StandardUserRole), next)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, response.StatusCode)
+
+	response, err = transport.proxyAccessControlledRequest(newUserRequest(http.MethodGet, "/kubernetes/api/v1/namespaces/finance/pods", 2, portainer.StandardUserRole), next)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	// The namespace is left out of the lists spanning the cluster
+	response, err = transport.proxyAccessControlledRequest(newUserRequest(http.MethodGet, "/kubernetes/api/v1/pods", 3, portainer.StandardUserRole), next)
+	require.NoError(t, err)
+
+	var list struct {
+		Items []struct {
+			Metadata struct{ Name string } `json:"metadata"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&list))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "web", list.Items[0].Metadata.Name)
+
+	// The administrators are not restricted
+	response, err = transport.proxyAccessControlledRequest(newUserRequest(http.MethodDelete, "/kubernetes/api/v1/namespaces/finance", 1, portainer.AdministratorRole), next)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
+
+func TestNamespaceCreateOwnership(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	transport := &baseTransport{endpoint: &portainer.Endpoint{ID: 1}, dataStore: store}
+
+	next := func(*http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusCreated, `{"kind":"Namespace","metadata":{"name":"marketing"}}`), nil
+	}
+
+	_, err := transport.proxyAccessControlledRequest(newUserRequest(http.MethodPost, "/kubernetes/api/v1/namespaces", 3, portainer.StandardUserRole), next)
+	require.NoError(t, err)
+
+	resourceControl, err := store.ResourceControl().ResourceControlByResourceIDAndType("1_marketing", portainer.KubernetesNamespaceResourceControl)
+	require.NoError(t, err)
+	require.NotNil(t, resourceControl)
+	assert.True(t, authorization.UserCanAccessResource(3, nil, resourceControl))
+	assert.False(t, authorization.UserCanAccessResource(4, nil, resourceControl))
+}
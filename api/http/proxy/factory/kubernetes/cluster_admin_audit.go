@@ -0,0 +1,111 @@
+package kubernetes
+
+import (
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultClusterAdminAuditLogSize is the number of accesses kept in memory by the audit log
+const DefaultClusterAdminAuditLogSize = 1000
+
+// ClusterAdminAccess represents a Kubernetes request proxied with the cluster-admin token
+// instead of the service account of the user
+type ClusterAdminAccess struct {
+	Date         int64                `json:"Date" example:"1700000000"`
+	UserID       portainer.UserID     `json:"UserId" example:"1"`
+	Username     string               `json:"Username" example:"admin"`
+	EndpointID   portainer.EndpointID `json:"EndpointId" example:"1"`
+	EndpointName string               `json:"EndpointName" example:"local"`
+	Method       string               `json:"Method" example:"GET"`
+	Path         string               `json:"Path" example:"/api/v1/namespaces/kube-system/secrets"`
+	Reason       string               `json:"Reason" example:"incident 42"`
+}
+
+// ClusterAdminAuditLog keeps the most recent cluster-admin accesses in memory.
+// Every access is also written to the server logs, which remain the durable record.
+type ClusterAdminAuditLog struct {
+	mu       sync.Mutex
+	entries  []ClusterAdminAccess
+	capacity int
+}
+
+// NewClusterAdminAuditLog returns a pointer to a new instance of ClusterAdminAuditLog keeping at most capacity entries
+func NewClusterAdminAuditLog(capacity int) *ClusterAdminAuditLog {
+	if capacity <= 0 {
+		capacity = DefaultClusterAdminAuditLogSize
+	}
+
+	return &ClusterAdminAuditLog{capacity: capacity}
+}
+
+// Record adds an access to the log, discarding the oldest one when the log is full
+func (l *ClusterAdminAuditLog) Record(access ClusterAdminAccess) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, access)
+	if len(l.entries) > l.capacity {
+		l.entries = slices.Clone(l.entries[len(l.entries)-l.capacity:])
+	}
+}
+
+// Accesses returns the recorded accesses, most recent first
+func (l *ClusterAdminAuditLog) Accesses() []ClusterAdminAccess {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	accesses := slices.Clone(l.entries)
+	slices.Reverse(accesses)
+
+	return accesses
+}
+
+// auditClusterAdminAccess records a request proxied with the cluster-admin token when the audit is enabled.
+// The reason header is always removed so that it is never forwarded to the Kubernetes API.
+func (transport *baseTransport) auditClusterAdminAccess(request *http.Request, tokenData *portainer.TokenData) {
+	reason := request.Header.Get(portainer.PortainerAccessReasonHeader)
+	request.Header.Del(portainer.PortainerAccessReasonHeader)
+
+	if transport.clusterAdminAuditLog == nil {
+		return
+	}
+
+	settings, err := transport.dataStore.Settings().Settings()
+	if err != nil {
+		log.Warn().Err(err).Msg("unable to retrieve the settings to audit a cluster-admin access")
+		return
+	}
+
+	if !settings.EnableKubernetesClusterAdminAudit {
+		return
+	}
+
+	access := ClusterAdminAccess{
+		Date:         time.Now().Unix(),
+		UserID:       tokenData.ID,
+		Username:     tokenData.Username,
+		EndpointID:   transport.endpoint.ID,
+		EndpointName: transport.endpoint.Name,
+		Method:       request.Method,
+		Path:         request.URL.Path,
+		Reason:       reason,
+	}
+
+	transport.clusterAdminAuditLog.Record(access)
+
+	log.Info().
+		Str("tag", "cluster-admin-access").
+		Int("user_id", int(access.UserID)).
+		Str("username", access.Username).
+		Int("endpoint_id", int(access.EndpointID)).
+		Str("method", access.Method).
+		Str("path", access.Path).
+		Str("reason", access.Reason).
+		Msg("Kubernetes request proxied with the cluster-admin token")
+}
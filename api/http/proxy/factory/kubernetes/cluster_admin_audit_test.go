@@ -0,0 +1,23 @@
+package kubernetes
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterAdminAuditLog(t *testing.T) {
+	auditLog := NewClusterAdminAuditLog(2)
+
+	assert.Empty(t, auditLog.Accesses())
+
+	for i := 1; i <= 3; i++ {
+		auditLog.Record(ClusterAdminAccess{UserID: portainer.UserID(i)})
+	}
+
+	accesses := auditLog.Accesses()
+	assert.Len(t, accesses, 2)
+	assert.Equal(t, portainer.UserID(3), accesses[0].UserID, "most recent access should be first")
+	assert.Equal(t, portainer.UserID(2), accesses[1].UserID, "oldest access should be discarded")
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/pkg/errors"
 	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/authorization"
 )
 
 func (transport *baseTransport) proxyNamespaceDeleteOperation(request *http.Request, namespace string) (*http.Response, error) {
@@ -60,5 +61,21 @@ func (transport *baseTransport) proxyNamespaceDeleteOperation(request *http.Requ
 		}
 	}
 
-	return transport.executeKubernetesRequest(request)
+	response, err := transport.executeKubernetesRequest(request)
+	if err != nil || response.StatusCode >= http.StatusBadRequest {
+		return response, err
+	}
+
+	resourceID := authorization.KubernetesNamespaceResourceControlID(transport.endpoint.ID, namespace)
+
+	resourceControl, err := transport.dataStore.ResourceControl().ResourceControlByResourceIDAndType(resourceID, portainer.KubernetesNamespaceResourceControl)
+	if err != nil {
+		return nil, err
+	} else if resourceControl != nil {
+		if err := transport.dataStore.ResourceControl().Delete(resourceControl.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
 }
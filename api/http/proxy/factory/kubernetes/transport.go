@@ -21,20 +21,22 @@ import (
 )
 
 type baseTransport struct {
-	httpTransport    *http.Transport
-	tokenManager     *tokenManager
-	endpoint         *portainer.Endpoint
-	k8sClientFactory *cli.ClientFactory
-	dataStore        dataservices.DataStore
+	httpTransport        *http.Transport
+	tokenManager         *tokenManager
+	endpoint             *portainer.Endpoint
+	k8sClientFactory     *cli.ClientFactory
+	dataStore            dataservices.DataStore
+	clusterAdminAuditLog *ClusterAdminAuditLog
 }
 
-func newBaseTransport(httpTransport *http.Transport, tokenManager *tokenManager, endpoint *portainer.Endpoint, k8sClientFactory *cli.ClientFactory, dataStore dataservices.DataStore) *baseTransport {
+func newBaseTransport(httpTransport *http.Transport, tokenManager *tokenManager, endpoint *portainer.Endpoint, k8sClientFactory *cli.ClientFactory, dataStore dataservices.DataStore, clusterAdminAuditLog *ClusterAdminAuditLog) *baseTransport {
 	return &baseTransport{
-		httpTransport:    httpTransport,
-		tokenManager:     tokenManager,
-		endpoint:         endpoint,
-		k8sClientFactory: k8sClientFactory,
-		dataStore:        dataStore,
+		httpTransport:        httpTransport,
+		tokenManager:         tokenManager,
+		endpoint:             endpoint,
+		k8sClientFactory:     k8sClientFactory,
+		dataStore:            dataStore,
+		clusterAdminAuditLog: clusterAdminAuditLog,
 	}
 }
 
@@ -43,6 +45,10 @@ func newBaseTransport(httpTransport *http.Transport, tokenManager *tokenManager,
 // proxyKubernetesRequest intercepts a Kubernetes API request and apply logic based
 // on the requested operation.
 func (transport *baseTransport) proxyKubernetesRequest(request *http.Request) (*http.Response, error) {
+	return transport.proxyAccessControlledRequest(request, transport.dispatchKubernetesRequest)
+}
+
+func (transport *baseTransport) dispatchKubernetesRequest(request *http.Request) (*http.Response, error) {
 	// URL path examples:
 	// http://localhost:9000/api/endpoints/3/kubernetes/api/v1/namespaces
 	// http://localhost:9000/api/endpoints/3/kubernetes/apis/apps/v1/namespaces/default/deployments
@@ -80,10 +86,6 @@ func (transport *baseTransport) proxyNamespacedRequest(request *http.Request, fu
 	}
 
 	switch {
-	case strings.HasPrefix(requestPath, "pods"):
-		return transport.proxyPodsRequest(request, namespace, requestPath)
-	case strings.HasPrefix(requestPath, "deployments"):
-		return transport.proxyDeploymentsRequest(request, namespace, requestPath)
 	case requestPath == "" && request.Method == "DELETE":
 		return transport.proxyNamespaceDeleteOperation(request, namespace)
 	default:
@@ -139,6 +141,7 @@ func (transport *baseTransport) getRoundTripToken(request *http.Request, tokenMa
 	var token string
 	if tokenData.Role == portainer.AdministratorRole {
 		token = tokenManager.GetAdminServiceAccountToken()
+		transport.auditClusterAdminAccess(request, tokenData)
 	} else {
 		token, err = tokenManager.GetUserServiceAccountToken(int(tokenData.ID), transport.endpoint.ID)
 		if err != nil {
@@ -63,8 +63,13 @@ type errorResponse struct {
 
 // WriteAccessDeniedResponse will create a new access denied response
 func WriteAccessDeniedResponse() (*http.Response, error) {
-	response := &http.Response{}
-	err := RewriteResponse(response, errorResponse{Message: "access denied to resource"}, http.StatusForbidden)
+	return WriteErrorResponse("access denied to resource", http.StatusForbidden)
+}
+
+// WriteErrorResponse will create a new response with the specified error message and status code
+func WriteErrorResponse(message string, statusCode int) (*http.Response, error) {
+	response := &http.Response{Header: http.Header{"Content-Type": []string{"application/json"}}}
+	err := RewriteResponse(response, errorResponse{Message: message}, statusCode)
 
 	return response, err
 }
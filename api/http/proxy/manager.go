@@ -10,6 +10,7 @@ import (
 	dockerclient "github.com/portainer/portainer/api/docker/client"
 	"github.com/portainer/portainer/api/http/proxy/factory"
 	"github.com/portainer/portainer/api/http/proxy/factory/kubernetes"
+	"github.com/portainer/portainer/api/internal/quotas"
 	"github.com/portainer/portainer/api/kubernetes/cli"
 
 	cmap "github.com/orcaman/concurrent-map"
@@ -19,9 +20,10 @@ var ErrProxyFactoryNotInitialized = errors.New("proxy factory not initialized")
 
 // Manager represents a service used to manage proxies to environments (endpoints) and extensions.
 type Manager struct {
-	proxyFactory     *factory.ProxyFactory
-	endpointProxies  cmap.ConcurrentMap
-	k8sClientFactory *cli.ClientFactory
+	proxyFactory        *factory.ProxyFactory
+	endpointProxies     cmap.ConcurrentMap
+	k8sClientFactory    *cli.ClientFactory
+	dockerClientFactory *dockerclient.ClientFactory
 }
 
 // NewManager initializes a new proxy Service
@@ -32,8 +34,9 @@ func NewManager(kubernetesClientFactory *cli.ClientFactory) *Manager {
 	}
 }
 
-func (manager *Manager) NewProxyFactory(dataStore dataservices.DataStore, signatureService portainer.DigitalSignatureService, tunnelService portainer.ReverseTunnelService, clientFactory *dockerclient.ClientFactory, kubernetesClientFactory *cli.ClientFactory, kubernetesTokenCacheManager *kubernetes.TokenCacheManager, gitService portainer.GitService, snapshotService portainer.SnapshotService) {
-	manager.proxyFactory = factory.NewProxyFactory(dataStore, signatureService, tunnelService, clientFactory, kubernetesClientFactory, kubernetesTokenCacheManager, gitService, snapshotService)
+func (manager *Manager) NewProxyFactory(dataStore dataservices.DataStore, signatureService portainer.DigitalSignatureService, tunnelService portainer.ReverseTunnelService, clientFactory *dockerclient.ClientFactory, kubernetesClientFactory *cli.ClientFactory, kubernetesTokenCacheManager *kubernetes.TokenCacheManager, kubernetesClusterAdminAuditLog *kubernetes.ClusterAdminAuditLog, gitService portainer.GitService, snapshotService portainer.SnapshotService, quotaService *quotas.Service) {
+	manager.dockerClientFactory = clientFactory
+	manager.proxyFactory = factory.NewProxyFactory(dataStore, signatureService, tunnelService, clientFactory, kubernetesClientFactory, kubernetesTokenCacheManager, kubernetesClusterAdminAuditLog, gitService, snapshotService, quotaService)
 }
 
 // CreateAndRegisterEndpointProxy creates a new HTTP reverse proxy based on environment(endpoint) properties and adds it to the registered proxies.
@@ -74,7 +77,7 @@ func (manager *Manager) GetEndpointProxy(endpoint *portainer.Endpoint) http.Hand
 }
 
 // DeleteEndpointProxy deletes the proxy associated to a key
-// and cleans the k8s environment(endpoint) client cache. DeleteEndpointProxy
+// and cleans the k8s and Docker environment(endpoint) client caches. DeleteEndpointProxy
 // is currently only called for edge connection clean up and when endpoint is updated
 func (manager *Manager) DeleteEndpointProxy(endpointID portainer.EndpointID) {
 	manager.endpointProxies.Remove(fmt.Sprint(endpointID))
@@ -82,6 +85,10 @@ func (manager *Manager) DeleteEndpointProxy(endpointID portainer.EndpointID) {
 	if manager.k8sClientFactory != nil {
 		manager.k8sClientFactory.RemoveKubeClient(endpointID)
 	}
+
+	if manager.dockerClientFactory != nil {
+		manager.dockerClientFactory.RemoveEndpointClients(endpointID)
+	}
 }
 
 // CreateGitlabProxy creates a new HTTP reverse proxy that can be used to send requests to the Gitlab API
@@ -35,6 +35,8 @@ type (
 		JWTAuthLookup(*http.Request) (*portainer.TokenData, error)
 		TrustedEdgeEnvironmentAccess(dataservices.DataStoreTx, *portainer.Endpoint) error
 		RevokeJWT(string)
+		RevokeSession(*portainer.UserSession) error
+		RevokeSessionJWT(*portainer.UserSession)
 	}
 
 	// RequestBouncer represents an entity that manages API request accesses
@@ -45,6 +47,18 @@ type (
 		revokedJWT    sync.Map
 		hsts          bool
 		csp           bool
+
+		sessionsMu sync.Mutex
+		// idleTimeout is the duration without any request after which a user session is closed, 0 when disabled
+		idleTimeout time.Duration
+		// sessionActivities tracks the activity of the JWTs used since the start of the server, by JWT identifier
+		sessionActivities map[string]*sessionActivity
+	}
+
+	// revokedJWTEntry is kept for a revoked JWT until it expires
+	revokedJWTEntry struct {
+		expiresAt time.Time
+		reason    portainer.UserSessionRevokedReason
 	}
 
 	// RestrictedRequestContext is a data structure containing information
@@ -63,6 +77,10 @@ type (
 var (
 	ErrInvalidKey = errors.New("Invalid API key")
 	ErrRevokedJWT = errors.New("the JWT has been revoked")
+	// ErrIdleSession is returned for the JWT of a session closed after the idle timeout
+	ErrIdleSession = errors.New("the session was closed after a period of inactivity")
+	// ErrSessionLimitReached is returned for the JWT of a session closed because the user opened too many sessions
+	ErrSessionLimitReached = errors.New("the session was closed because the user opened too many sessions")
 )
 
 // NewRequestBouncer initializes a new RequestBouncer
@@ -73,9 +91,20 @@ func NewRequestBouncer(dataStore dataservices.DataStore, jwtService portainer.JW
 		apiKeyService: apiKeyService,
 		hsts:          featureflags.IsEnabled("hsts"),
 		csp:           featureflags.IsEnabled("csp"),
+
+		sessionActivities: make(map[string]*sessionActivity),
 	}
 
+	if settings, err := dataStore.Settings().Settings(); err == nil {
+		b.setIdleTimeout(settings.UserSessionLimits)
+	} else {
+		log.Warn().Err(err).Msg("unable to retrieve the settings, the user sessions never go idle")
+	}
+
+	b.loadRevokedSessions()
+
 	go b.cleanUpExpiredJWT()
+	go b.persistSessionActivitiesLoop()
 
 	return b
 }
@@ -211,6 +240,7 @@ func (bouncer *RequestBouncer) TrustedEdgeEnvironmentAccess(tx dataservices.Data
 // - adding a secure handlers to the response
 // - authenticating the request with a valid token
 func (bouncer *RequestBouncer) mwAuthenticatedUser(h http.Handler) http.Handler {
+	h = mwReadOnlyUser(h)
 	h = bouncer.mwAuthenticateFirst([]tokenLookup{
 		bouncer.apiKeyLookup,
 		bouncer.CookieAuthLookup,
@@ -303,7 +333,12 @@ func (bouncer *RequestBouncer) mwAuthenticateFirst(tokenLookups []tokenLookup, n
 
 		for _, lookup := range tokenLookups {
 			resultToken, err := lookup(r)
-			if err != nil {
+			if reason := logoutReason(err); reason != "" {
+				w.Header().Set(portainer.PortainerLogoutReasonHeader, string(reason))
+				httperror.WriteError(w, http.StatusUnauthorized, "Session closed", err)
+
+				return
+			} else if err != nil {
 				httperror.WriteError(w, http.StatusUnauthorized, "Invalid JWT token", httperrors.ErrUnauthorized)
 
 				return
@@ -329,6 +364,11 @@ func (bouncer *RequestBouncer) mwAuthenticateFirst(tokenLookups []tokenLookup, n
 			return
 		}
 
+		// The tokens issued before the user was made read-only must not grant more than the current role
+		if user.Role == portainer.ReadOnlyRole {
+			token.Role = portainer.ReadOnlyRole
+		}
+
 		ctx := StoreTokenData(r, token)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -347,8 +387,8 @@ func (bouncer *RequestBouncer) CookieAuthLookup(r *http.Request) (*portainer.Tok
 		return nil, err
 	}
 
-	if _, ok := bouncer.revokedJWT.Load(jti); ok {
-		return nil, ErrRevokedJWT
+	if err := bouncer.checkSession(jti, time.Now()); err != nil {
+		return nil, err
 	}
 
 	return tokenData, nil
@@ -367,8 +407,8 @@ func (bouncer *RequestBouncer) JWTAuthLookup(r *http.Request) (*portainer.TokenD
 		return nil, err
 	}
 
-	if _, ok := bouncer.revokedJWT.Load(jti); ok {
-		return nil, ErrRevokedJWT
+	if err := bouncer.checkSession(jti, time.Now()); err != nil {
+		return nil, err
 	}
 
 	return tokenData, nil
@@ -380,12 +420,96 @@ func (bouncer *RequestBouncer) RevokeJWT(token string) {
 		return
 	}
 
-	bouncer.revokedJWT.Store(jti, exp)
+	bouncer.revokedJWT.Store(jti, revokedJWTEntry{expiresAt: exp, reason: portainer.UserSessionRevokedByUser})
+}
+
+// RevokeSession revokes the JWT of a user session, the session is kept as revoked so that
+// the JWT stays revoked after a restart. The reason of the session defaults to UserSessionRevokedByUser.
+func (bouncer *RequestBouncer) RevokeSession(session *portainer.UserSession) error {
+	if err := bouncer.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return RevokeSessionTx(tx, session)
+	}); err != nil {
+		return err
+	}
+
+	bouncer.RevokeSessionJWT(session)
+
+	return nil
+}
+
+// RevokeSessionTx marks a user session as revoked within a transaction, RevokeSessionJWT must be called
+// once the transaction is committed. The reason of the session defaults to UserSessionRevokedByUser.
+func RevokeSessionTx(tx dataservices.DataStoreTx, session *portainer.UserSession) error {
+	session.Revoked = true
+	if session.RevokedReason == "" {
+		session.RevokedReason = portainer.UserSessionRevokedByUser
+	}
+
+	return tx.UserSession().Update(session.ID, session)
+}
+
+// RevokeSessionJWT revokes the JWT of a user session revoked by RevokeSessionTx
+func (bouncer *RequestBouncer) RevokeSessionJWT(session *portainer.UserSession) {
+	bouncer.revokedJWT.Store(session.TokenID, revokedJWTEntry{expiresAt: sessionExpiry(session), reason: session.RevokedReason})
+
+	bouncer.sessionsMu.Lock()
+	delete(bouncer.sessionActivities, session.TokenID)
+	bouncer.sessionsMu.Unlock()
+}
+
+// loadRevokedSessions revokes the JWTs of the sessions revoked before the start of the server
+func (bouncer *RequestBouncer) loadRevokedSessions() {
+	sessions, err := bouncer.dataStore.UserSession().ReadAll()
+	if err != nil {
+		log.Warn().Err(err).Msg("unable to retrieve the user sessions")
+
+		return
+	}
+
+	for _, session := range sessions {
+		if session.Revoked {
+			bouncer.revokedJWT.Store(session.TokenID, revokedJWTEntry{expiresAt: sessionExpiry(&session), reason: session.RevokedReason})
+		}
+	}
+}
+
+func sessionExpiry(session *portainer.UserSession) time.Time {
+	if session.ExpiresAt == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(session.ExpiresAt, 0)
+}
+
+// revokedError returns the error explaining why a JWT was revoked
+func revokedError(reason portainer.UserSessionRevokedReason) error {
+	switch reason {
+	case portainer.UserSessionRevokedIdle:
+		return ErrIdleSession
+	case portainer.UserSessionRevokedSessionLimit:
+		return ErrSessionLimitReached
+	}
+
+	return ErrRevokedJWT
+}
+
+// logoutReason returns the reason sent to the clients when the error of a token lookup is caused by a closed session
+func logoutReason(err error) portainer.UserSessionRevokedReason {
+	switch {
+	case errors.Is(err, ErrIdleSession):
+		return portainer.UserSessionRevokedIdle
+	case errors.Is(err, ErrSessionLimitReached):
+		return portainer.UserSessionRevokedSessionLimit
+	case errors.Is(err, ErrRevokedJWT):
+		return portainer.UserSessionRevokedByUser
+	}
+
+	return ""
 }
 
 func (bouncer *RequestBouncer) cleanUpExpiredJWTPass() {
 	bouncer.revokedJWT.Range(func(key, value any) bool {
-		if t := value.(time.Time); t.IsZero() {
+		if t := value.(revokedJWTEntry).expiresAt; t.IsZero() {
 			return true
 		} else if time.Now().After(t) {
 			bouncer.revokedJWT.Delete(key)
@@ -393,6 +517,34 @@ func (bouncer *RequestBouncer) cleanUpExpiredJWTPass() {
 
 		return true
 	})
+
+	bouncer.cleanUpSessionActivities(time.Now())
+
+	if err := bouncer.deleteExpiredSessions(time.Now()); err != nil {
+		log.Warn().Err(err).Msg("unable to remove the expired user sessions")
+	}
+}
+
+// deleteExpiredSessions removes the sessions whose JWT expired, revoked or not, as their JWT can no longer be used
+func (bouncer *RequestBouncer) deleteExpiredSessions(now time.Time) error {
+	return bouncer.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		sessions, err := tx.UserSession().ReadAll()
+		if err != nil {
+			return err
+		}
+
+		for _, session := range sessions {
+			if session.ExpiresAt == 0 || session.ExpiresAt >= now.Unix() {
+				continue
+			}
+
+			if err := tx.UserSession().Delete(session.ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
 func (bouncer *RequestBouncer) cleanUpExpiredJWT() {
@@ -424,9 +576,24 @@ func (bouncer *RequestBouncer) apiKeyLookup(r *http.Request) (*portainer.TokenDa
 	}
 
 	tokenData := &portainer.TokenData{
-		ID:       user.ID,
-		Username: user.Username,
-		Role:     user.Role,
+		ID:             user.ID,
+		Username:       user.Username,
+		Role:           user.Role,
+		ServiceAccount: user.ServiceAccount,
+	}
+
+	if user.ServiceAccount && user.Role != portainer.ReadOnlyRole {
+		// A service account is never granted the administrator privileges
+		tokenData.Role = portainer.StandardUserRole
+
+		log.Info().
+			Str("tag", "service-account-access").
+			Int("service_account_id", int(user.ID)).
+			Str("service_account", user.Username).
+			Int("api_key_id", int(apiKey.ID)).
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Msg("API request authenticated with a service account key")
 	}
 	if _, _, err := bouncer.jwtService.GenerateToken(tokenData); err != nil {
 		log.Debug().Err(err).Msg("Failed to generate token")
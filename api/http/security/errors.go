@@ -4,4 +4,5 @@ import "errors"
 
 var (
 	ErrAuthorizationRequired = errors.New("Authorization required for this operation")
+	ErrTooManyRequests       = errors.New("Too many requests, retry later")
 )
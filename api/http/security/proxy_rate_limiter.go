@@ -0,0 +1,129 @@
+package security
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/settingsbus"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/time/rate"
+)
+
+// proxyLimiterIdleTimeout is how long the limiter of a user on an environment is kept without any request
+const proxyLimiterIdleTimeout = 10 * time.Minute
+
+type proxyLimiterKey struct {
+	endpointID string
+	userID     portainer.UserID
+}
+
+type proxyLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// ProxyRateLimiter limits the rate of the API calls proxied to an environment by each user so that the
+// small devices are not overwhelmed by an aggressive polling
+type ProxyRateLimiter struct {
+	mu        sync.Mutex
+	settings  portainer.ProxyRateLimitSettings
+	limiters  map[proxyLimiterKey]*proxyLimiter
+	lastSweep time.Time
+}
+
+// NewProxyRateLimiter returns a new instance of ProxyRateLimiter
+func NewProxyRateLimiter(settings portainer.ProxyRateLimitSettings) *ProxyRateLimiter {
+	return &ProxyRateLimiter{
+		settings:  settings,
+		limiters:  make(map[proxyLimiterKey]*proxyLimiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// SettingsChanged applies the new rate limit of the settings, the users start again with a full burst
+func (limiter *ProxyRateLimiter) SettingsChanged(change settingsbus.Change) {
+	if change.Current.ProxyRateLimit == change.Previous.ProxyRateLimit {
+		return
+	}
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	limiter.settings = change.Current.ProxyRateLimit
+	clear(limiter.limiters)
+}
+
+// LimitAccess wraps the proxied requests of an environment, identified by the id route variable, with a check
+// of the rate of the requests of the user. It must be used behind a bouncer so that the user is known.
+func (limiter *ProxyRateLimiter) LimitAccess(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenData, err := RetrieveTokenData(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		key := proxyLimiterKey{endpointID: mux.Vars(r)["id"], userID: tokenData.ID}
+
+		if allowed, retryAfter := limiter.allow(key, time.Now()); !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			httperror.WriteError(w, http.StatusTooManyRequests, "Too many requests to the environment", ErrTooManyRequests)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow returns whether a request of a user on an environment is allowed, and otherwise how long to wait
+// before the next request
+func (limiter *ProxyRateLimiter) allow(key proxyLimiterKey, now time.Time) (bool, time.Duration) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	if limiter.settings.RequestsPerSecond <= 0 {
+		return true, 0
+	}
+
+	if now.Sub(limiter.lastSweep) > proxyLimiterIdleTimeout {
+		limiter.sweep(now)
+	}
+
+	entry, ok := limiter.limiters[key]
+	if !ok {
+		entry = &proxyLimiter{
+			limiter: rate.NewLimiter(rate.Limit(limiter.settings.RequestsPerSecond), max(limiter.settings.Burst, 1)),
+		}
+		limiter.limiters[key] = entry
+	}
+
+	entry.lastSeen = now
+
+	reservation := entry.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+
+		return false, delay
+	}
+
+	return true, 0
+}
+
+// sweep drops the limiters of the users that stopped sending requests, it must be called with the lock held
+func (limiter *ProxyRateLimiter) sweep(now time.Time) {
+	for key, entry := range limiter.limiters {
+		if now.Sub(entry.lastSeen) > proxyLimiterIdleTimeout {
+			delete(limiter.limiters, key)
+		}
+	}
+
+	limiter.lastSweep = now
+}
@@ -0,0 +1,61 @@
+package security
+
+import (
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/settingsbus"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProxyRateLimiter(t *testing.T) {
+	limiter := NewProxyRateLimiter(portainer.ProxyRateLimitSettings{RequestsPerSecond: 1, Burst: 2})
+
+	now := time.Now()
+	user1 := proxyLimiterKey{endpointID: "1", userID: 1}
+
+	allowed, _ := limiter.allow(user1, now)
+	assert.True(t, allowed)
+	allowed, _ = limiter.allow(user1, now)
+	assert.True(t, allowed)
+
+	allowed, retryAfter := limiter.allow(user1, now)
+	assert.False(t, allowed)
+	assert.InDelta(t, time.Second, retryAfter, float64(10*time.Millisecond))
+
+	// The other users and environments have their own limit
+	allowed, _ = limiter.allow(proxyLimiterKey{endpointID: "1", userID: 2}, now)
+	assert.True(t, allowed)
+	allowed, _ = limiter.allow(proxyLimiterKey{endpointID: "2", userID: 1}, now)
+	assert.True(t, allowed)
+
+	allowed, _ = limiter.allow(user1, now.Add(time.Second))
+	assert.True(t, allowed)
+
+	// The limit is lifted once disabled in the settings
+	limiter.SettingsChanged(settingsbus.Change{
+		Previous: portainer.Settings{ProxyRateLimit: portainer.ProxyRateLimitSettings{RequestsPerSecond: 1, Burst: 2}},
+	})
+
+	for range 10 {
+		allowed, _ = limiter.allow(user1, now.Add(time.Second))
+		assert.True(t, allowed)
+	}
+}
+
+func TestProxyRateLimiterSweep(t *testing.T) {
+	limiter := NewProxyRateLimiter(portainer.ProxyRateLimitSettings{RequestsPerSecond: 1, Burst: 1})
+
+	now := time.Now()
+	limiter.lastSweep = now
+
+	limiter.allow(proxyLimiterKey{endpointID: "1", userID: 1}, now)
+	limiter.allow(proxyLimiterKey{endpointID: "1", userID: 2}, now.Add(proxyLimiterIdleTimeout/2))
+
+	// The limiter of the first user is dropped once idle
+	limiter.allow(proxyLimiterKey{endpointID: "1", userID: 2}, now.Add(proxyLimiterIdleTimeout+time.Minute))
+	assert.Len(t, limiter.limiters, 1)
+	assert.Contains(t, limiter.limiters, proxyLimiterKey{endpointID: "1", userID: 2})
+}
@@ -0,0 +1,57 @@
+package security
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+)
+
+// readOnlyAllowedRequests are the requests changing a resource that a read-only user can still issue,
+// they only affect the account of the user
+var readOnlyAllowedRequests = []struct {
+	method string
+	path   *regexp.Regexp
+}{
+	{http.MethodPost, regexp.MustCompile(`^/auth/logout$`)},
+	{http.MethodPut, regexp.MustCompile(`^/users/\d+/passwd$`)},
+}
+
+// IsReadOnlyRequest returns true when the request only reads resources. The websocket upgrades are excluded
+// as they open interactive sessions such as the container consoles
+func IsReadOnlyRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+
+	return !strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// mwReadOnlyUser denies the requests of the read-only users that would change a resource
+func mwReadOnlyUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenData, err := RetrieveTokenData(r)
+		if err != nil || tokenData.Role != portainer.ReadOnlyRole || IsReadOnlyRequest(r) || readOnlyAllowed(r) {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		httperror.WriteError(w, http.StatusForbidden, "Access denied, the user has a read-only role", httperrors.ErrResourceAccessDenied)
+	})
+}
+
+func readOnlyAllowed(r *http.Request) bool {
+	for _, allowed := range readOnlyAllowedRequests {
+		if r.Method == allowed.method && allowed.path.MatchString(r.URL.Path) {
+			return true
+		}
+	}
+
+	return false
+}
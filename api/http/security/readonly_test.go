@@ -0,0 +1,45 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_mwReadOnlyUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       portainer.UserRole
+		method     string
+		path       string
+		websocket  bool
+		wantStatus int
+	}{
+		{name: "read-only user can read", role: portainer.ReadOnlyRole, method: http.MethodGet, path: "/endpoints", wantStatus: http.StatusOK},
+		{name: "read-only user cannot create", role: portainer.ReadOnlyRole, method: http.MethodPost, path: "/stacks/create/standalone/string", wantStatus: http.StatusForbidden},
+		{name: "read-only user cannot delete", role: portainer.ReadOnlyRole, method: http.MethodDelete, path: "/endpoints/1/docker/containers/abc", wantStatus: http.StatusForbidden},
+		{name: "read-only user cannot open a console", role: portainer.ReadOnlyRole, method: http.MethodGet, path: "/websocket/exec", websocket: true, wantStatus: http.StatusForbidden},
+		{name: "read-only user can log out", role: portainer.ReadOnlyRole, method: http.MethodPost, path: "/auth/logout", wantStatus: http.StatusOK},
+		{name: "read-only user can change the password", role: portainer.ReadOnlyRole, method: http.MethodPut, path: "/users/3/passwd", wantStatus: http.StatusOK},
+		{name: "standard user can create", role: portainer.StandardUserRole, method: http.MethodPost, path: "/stacks/create/standalone/string", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.websocket {
+				req.Header.Set("Upgrade", "websocket")
+			}
+			req = req.WithContext(StoreTokenData(req, &portainer.TokenData{ID: 3, Role: tt.role}))
+
+			rr := httptest.NewRecorder()
+			mwReadOnlyUser(testHandler200).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantStatus, rr.Code)
+		})
+	}
+}
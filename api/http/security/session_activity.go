@@ -0,0 +1,202 @@
+package security
+
+import (
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/settingsbus"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// sessionActivityPersistInterval is how often the last activity of the sessions is recorded in the database
+	sessionActivityPersistInterval = time.Minute
+	// sessionActivityRetention is how long the activity of an unused JWT is kept in memory, it is read again
+	// from the database when the JWT is used afterwards
+	sessionActivityRetention = time.Hour
+)
+
+type sessionActivity struct {
+	// sessionID is 0 when the JWT does not belong to a user session, such as the JWT of a kubeconfig
+	sessionID    portainer.UserSessionID
+	lastActivity time.Time
+	persistedAt  time.Time
+}
+
+// SettingsChanged applies the new idle timeout of the user sessions
+func (bouncer *RequestBouncer) SettingsChanged(change settingsbus.Change) {
+	if change.Current.UserSessionLimits.IdleTimeout == change.Previous.UserSessionLimits.IdleTimeout {
+		return
+	}
+
+	bouncer.sessionsMu.Lock()
+	defer bouncer.sessionsMu.Unlock()
+
+	bouncer.setIdleTimeout(change.Current.UserSessionLimits)
+}
+
+// setIdleTimeout must be called with the lock held or before the bouncer is used
+func (bouncer *RequestBouncer) setIdleTimeout(limits portainer.UserSessionLimitsSettings) {
+	bouncer.idleTimeout = 0
+
+	if limits.IdleTimeout == "" {
+		return
+	}
+
+	idleTimeout, err := time.ParseDuration(limits.IdleTimeout)
+	if err != nil {
+		log.Warn().Err(err).Str("idle_timeout", limits.IdleTimeout).Msg("invalid idle timeout, the user sessions never go idle")
+
+		return
+	}
+
+	bouncer.idleTimeout = idleTimeout
+}
+
+// checkSession returns an error when the JWT was revoked or when its session went idle,
+// otherwise the activity of the session is recorded
+func (bouncer *RequestBouncer) checkSession(jti string, now time.Time) error {
+	if value, ok := bouncer.revokedJWT.Load(jti); ok {
+		return revokedError(value.(revokedJWTEntry).reason)
+	}
+
+	activity, err := bouncer.loadSessionActivity(jti, now)
+	if err != nil {
+		return err
+	}
+
+	bouncer.sessionsMu.Lock()
+	idle := activity.sessionID != 0 && bouncer.idleTimeout > 0 && now.Sub(activity.lastActivity) > bouncer.idleTimeout
+
+	if !idle {
+		activity.lastActivity = now
+	}
+	bouncer.sessionsMu.Unlock()
+
+	if idle {
+		if err := bouncer.revokeIdleSession(activity.sessionID); err != nil {
+			log.Warn().Err(err).Int("session_id", int(activity.sessionID)).Msg("unable to revoke the idle session")
+		}
+
+		return ErrIdleSession
+	}
+
+	return nil
+}
+
+// loadSessionActivity returns the activity of a JWT, reading its session from the database when the JWT
+// was not used since the start of the server or for a while
+func (bouncer *RequestBouncer) loadSessionActivity(jti string, now time.Time) (*sessionActivity, error) {
+	bouncer.sessionsMu.Lock()
+	activity, ok := bouncer.sessionActivities[jti]
+	bouncer.sessionsMu.Unlock()
+
+	if ok {
+		return activity, nil
+	}
+
+	activity = &sessionActivity{lastActivity: now}
+
+	session, err := bouncer.dataStore.UserSession().UserSessionByTokenID(jti)
+	if err != nil && !bouncer.dataStore.IsErrObjectNotFound(err) {
+		return nil, err
+	}
+
+	if session != nil {
+		activity.sessionID = session.ID
+		activity.lastActivity = time.Unix(max(session.IssuedAt, session.LastActivity), 0)
+		activity.persistedAt = time.Unix(session.LastActivity, 0)
+	}
+
+	bouncer.sessionsMu.Lock()
+	defer bouncer.sessionsMu.Unlock()
+
+	// Another request of the same JWT may have loaded it in the meantime
+	if existing, ok := bouncer.sessionActivities[jti]; ok {
+		return existing, nil
+	}
+
+	bouncer.sessionActivities[jti] = activity
+
+	return activity, nil
+}
+
+func (bouncer *RequestBouncer) revokeIdleSession(sessionID portainer.UserSessionID) error {
+	session, err := bouncer.dataStore.UserSession().Read(sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session.Revoked {
+		return nil
+	}
+
+	session.RevokedReason = portainer.UserSessionRevokedIdle
+
+	return bouncer.RevokeSession(session)
+}
+
+// persistSessionActivities records in a single transaction the last activity of the sessions used since
+// it was last recorded, so that the requests themselves never write to the database
+func (bouncer *RequestBouncer) persistSessionActivities() {
+	lastActivities := make(map[portainer.UserSessionID]time.Time)
+
+	bouncer.sessionsMu.Lock()
+	for _, activity := range bouncer.sessionActivities {
+		if activity.sessionID != 0 && activity.lastActivity.After(activity.persistedAt) {
+			lastActivities[activity.sessionID] = activity.lastActivity
+			activity.persistedAt = activity.lastActivity
+		}
+	}
+	bouncer.sessionsMu.Unlock()
+
+	if len(lastActivities) == 0 {
+		return
+	}
+
+	err := bouncer.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		for sessionID, lastActivity := range lastActivities {
+			session, err := tx.UserSession().Read(sessionID)
+			if tx.IsErrObjectNotFound(err) {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			session.LastActivity = lastActivity.Unix()
+
+			if err := tx.UserSession().Update(session.ID, session); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Debug().Err(err).Msg("unable to record the activity of the user sessions")
+	}
+}
+
+func (bouncer *RequestBouncer) persistSessionActivitiesLoop() {
+	ticker := time.NewTicker(sessionActivityPersistInterval)
+
+	for range ticker.C {
+		bouncer.persistSessionActivities()
+	}
+}
+
+// cleanUpSessionActivities drops the activity of the JWTs that were not used for a while
+func (bouncer *RequestBouncer) cleanUpSessionActivities(now time.Time) {
+	bouncer.sessionsMu.Lock()
+	defer bouncer.sessionsMu.Unlock()
+
+	retention := max(sessionActivityRetention, bouncer.idleTimeout)
+
+	for jti, activity := range bouncer.sessionActivities {
+		if now.Sub(activity.lastActivity) > retention {
+			delete(bouncer.sessionActivities, jti)
+		}
+	}
+}
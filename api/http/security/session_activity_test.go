@@ -0,0 +1,132 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/internal/settingsbus"
+	"github.com/portainer/portainer/api/jwt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdleSession(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.User().Create(&portainer.User{ID: 1}))
+
+	settings, err := store.Settings().Settings()
+	require.NoError(t, err)
+
+	settings.UserSessionLimits.IdleTimeout = "30m"
+	require.NoError(t, store.Settings().UpdateSettings(settings))
+
+	jwtService, err := jwt.NewService("1h", store)
+	require.NoError(t, err)
+
+	bouncer := NewRequestBouncer(store, jwtService, nil)
+
+	token, _, err := jwtService.GenerateToken(&portainer.TokenData{ID: 1})
+	require.NoError(t, err)
+
+	_, jti, _, err := jwtService.ParseAndVerifyToken(token)
+	require.NoError(t, err)
+
+	now := time.Now()
+	session := &portainer.UserSession{UserID: 1, TokenID: jti, IssuedAt: now.Unix()}
+	require.NoError(t, store.UserSession().Create(session))
+
+	require.NoError(t, bouncer.checkSession(jti, now.Add(10*time.Minute)))
+
+	// The activity is recorded in the background rather than by the requests
+	session, err = store.UserSession().Read(session.ID)
+	require.NoError(t, err)
+	assert.Zero(t, session.LastActivity)
+
+	bouncer.persistSessionActivities()
+
+	session, err = store.UserSession().Read(session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(10*time.Minute).Unix(), session.LastActivity)
+
+	// Each request restarts the idle timeout
+	require.NoError(t, bouncer.checkSession(jti, now.Add(35*time.Minute)))
+
+	require.ErrorIs(t, bouncer.checkSession(jti, now.Add(70*time.Minute)), ErrIdleSession)
+
+	session, err = store.UserSession().Read(session.ID)
+	require.NoError(t, err)
+	assert.True(t, session.Revoked)
+	assert.Equal(t, portainer.UserSessionRevokedIdle, session.RevokedReason)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Add(jwtTokenHeader, "Bearer "+token)
+	rr := httptest.NewRecorder()
+
+	bouncer.mwAuthenticateFirst([]tokenLookup{bouncer.JWTAuthLookup}, testHandler200).ServeHTTP(rr, r)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, string(portainer.UserSessionRevokedIdle), rr.Header().Get(portainer.PortainerLogoutReasonHeader))
+
+	// The closed session stays closed after a restart
+	restartedBouncer := NewRequestBouncer(store, jwtService, nil)
+	require.ErrorIs(t, restartedBouncer.checkSession(jti, now.Add(71*time.Minute)), ErrIdleSession)
+}
+
+func TestSessionWithoutIdleTimeout(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	jwtService, err := jwt.NewService("1h", store)
+	require.NoError(t, err)
+
+	bouncer := NewRequestBouncer(store, jwtService, nil)
+
+	now := time.Now()
+	session := &portainer.UserSession{UserID: 1, TokenID: "token", IssuedAt: now.Unix()}
+	require.NoError(t, store.UserSession().Create(session))
+
+	require.NoError(t, bouncer.checkSession("token", now.Add(24*time.Hour)))
+
+	// The JWTs without session, such as the ones of the kubeconfigs, are never idle
+	settings, err := store.Settings().Settings()
+	require.NoError(t, err)
+
+	previous := *settings
+	settings.UserSessionLimits.IdleTimeout = "1m"
+	bouncer.SettingsChanged(settingsbus.Change{Previous: previous, Current: *settings})
+
+	require.NoError(t, bouncer.checkSession("kubeconfig", now))
+	require.NoError(t, bouncer.checkSession("kubeconfig", now.Add(time.Hour)))
+	require.ErrorIs(t, bouncer.checkSession("token", now.Add(26*time.Hour)), ErrIdleSession)
+}
+
+func TestDeleteExpiredSessions(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	jwtService, err := jwt.NewService("1h", store)
+	require.NoError(t, err)
+
+	bouncer := NewRequestBouncer(store, jwtService, nil)
+
+	now := time.Now()
+
+	expired := &portainer.UserSession{UserID: 1, TokenID: "expired", ExpiresAt: now.Add(-time.Minute).Unix()}
+	require.NoError(t, store.UserSession().Create(expired))
+
+	open := &portainer.UserSession{UserID: 1, TokenID: "open", ExpiresAt: now.Add(time.Minute).Unix()}
+	require.NoError(t, store.UserSession().Create(open))
+
+	require.NoError(t, bouncer.deleteExpiredSessions(now))
+
+	_, err = store.UserSession().UserSessionByTokenID("expired")
+	require.True(t, store.IsErrObjectNotFound(err))
+
+	session, err := store.UserSession().UserSessionByTokenID("open")
+	require.NoError(t, err)
+	assert.Equal(t, open.ID, session.ID)
+}
@@ -11,6 +11,9 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/adminmonitor"
 	"github.com/portainer/portainer/api/apikey"
+	"github.com/portainer/portainer/api/azure"
+	operations "github.com/portainer/portainer/api/backup"
+	"github.com/portainer/portainer/api/cloud"
 	"github.com/portainer/portainer/api/crypto"
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/docker"
@@ -18,28 +21,44 @@ import (
 	"github.com/portainer/portainer/api/http/csrf"
 	"github.com/portainer/portainer/api/http/handler"
 	"github.com/portainer/portainer/api/http/handler/auth"
+	azurehandler "github.com/portainer/portainer/api/http/handler/azure"
 	"github.com/portainer/portainer/api/http/handler/backup"
+	"github.com/portainer/portainer/api/http/handler/cloudprovisionings"
 	"github.com/portainer/portainer/api/http/handler/customtemplates"
+	deploymentshandler "github.com/portainer/portainer/api/http/handler/deployments"
 	dockerhandler "github.com/portainer/portainer/api/http/handler/docker"
+	"github.com/portainer/portainer/api/http/handler/edgebundle"
 	"github.com/portainer/portainer/api/http/handler/edgegroups"
 	"github.com/portainer/portainer/api/http/handler/edgejobs"
+	"github.com/portainer/portainer/api/http/handler/edgejointokens"
 	"github.com/portainer/portainer/api/http/handler/edgestacks"
 	"github.com/portainer/portainer/api/http/handler/edgetemplates"
+	"github.com/portainer/portainer/api/http/handler/edgeupdateschedules"
 	"github.com/portainer/portainer/api/http/handler/endpointedge"
 	"github.com/portainer/portainer/api/http/handler/endpointgroups"
 	"github.com/portainer/portainer/api/http/handler/endpointproxy"
 	"github.com/portainer/portainer/api/http/handler/endpoints"
+	"github.com/portainer/portainer/api/http/handler/exports"
+	"github.com/portainer/portainer/api/http/handler/failoverpolicies"
 	"github.com/portainer/portainer/api/http/handler/file"
 	"github.com/portainer/portainer/api/http/handler/gitops"
 	"github.com/portainer/portainer/api/http/handler/helm"
 	"github.com/portainer/portainer/api/http/handler/hostmanagement/openamt"
+	"github.com/portainer/portainer/api/http/handler/imageupdatejobs"
+	"github.com/portainer/portainer/api/http/handler/inactiveresources"
 	kubehandler "github.com/portainer/portainer/api/http/handler/kubernetes"
 	"github.com/portainer/portainer/api/http/handler/ldap"
+	metricshandler "github.com/portainer/portainer/api/http/handler/metrics"
 	"github.com/portainer/portainer/api/http/handler/motd"
+	"github.com/portainer/portainer/api/http/handler/notificationchannels"
+	quotahandler "github.com/portainer/portainer/api/http/handler/quotas"
 	"github.com/portainer/portainer/api/http/handler/registries"
+	reportshandler "github.com/portainer/portainer/api/http/handler/reports"
 	"github.com/portainer/portainer/api/http/handler/resourcecontrols"
 	"github.com/portainer/portainer/api/http/handler/roles"
+	"github.com/portainer/portainer/api/http/handler/serviceaccounts"
 	"github.com/portainer/portainer/api/http/handler/settings"
+	"github.com/portainer/portainer/api/http/handler/snapshotwebhooks"
 	sslhandler "github.com/portainer/portainer/api/http/handler/ssl"
 	"github.com/portainer/portainer/api/http/handler/stacks"
 	"github.com/portainer/portainer/api/http/handler/storybook"
@@ -48,8 +67,10 @@ import (
 	"github.com/portainer/portainer/api/http/handler/teammemberships"
 	"github.com/portainer/portainer/api/http/handler/teams"
 	"github.com/portainer/portainer/api/http/handler/templates"
+	"github.com/portainer/portainer/api/http/handler/uioverrides"
 	"github.com/portainer/portainer/api/http/handler/upload"
 	"github.com/portainer/portainer/api/http/handler/users"
+	"github.com/portainer/portainer/api/http/handler/validationwebhooks"
 	"github.com/portainer/portainer/api/http/handler/webhooks"
 	"github.com/portainer/portainer/api/http/handler/websocket"
 	"github.com/portainer/portainer/api/http/middlewares"
@@ -57,10 +78,29 @@ import (
 	"github.com/portainer/portainer/api/http/proxy"
 	"github.com/portainer/portainer/api/http/proxy/factory/kubernetes"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/acme"
+	"github.com/portainer/portainer/api/internal/amtpower"
 	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/deploymenthistory"
+	"github.com/portainer/portainer/api/internal/dockerhub"
 	edgestackservice "github.com/portainer/portainer/api/internal/edge/edgestacks"
+	"github.com/portainer/portainer/api/internal/edge/joblogs"
+	"github.com/portainer/portainer/api/internal/edge/updateschedules"
+	"github.com/portainer/portainer/api/internal/failover"
+	"github.com/portainer/portainer/api/internal/gc"
+	"github.com/portainer/portainer/api/internal/imageupdate"
+	"github.com/portainer/portainer/api/internal/insights"
+	"github.com/portainer/portainer/api/internal/metrics"
+	"github.com/portainer/portainer/api/internal/notifications"
+	"github.com/portainer/portainer/api/internal/ociartifact"
+	"github.com/portainer/portainer/api/internal/quotas"
+	"github.com/portainer/portainer/api/internal/registryaccess"
+	"github.com/portainer/portainer/api/internal/registryclient"
+	"github.com/portainer/portainer/api/internal/reports"
+	"github.com/portainer/portainer/api/internal/settingsbus"
 	"github.com/portainer/portainer/api/internal/snapshot"
 	"github.com/portainer/portainer/api/internal/ssl"
+	"github.com/portainer/portainer/api/internal/templatesources"
 	"github.com/portainer/portainer/api/internal/upgrade"
 	k8s "github.com/portainer/portainer/api/kubernetes"
 	"github.com/portainer/portainer/api/kubernetes/cli"
@@ -75,44 +115,64 @@ import (
 
 // Server implements the portainer.Server interface
 type Server struct {
-	AuthorizationService        *authorization.Service
-	BindAddress                 string
-	BindAddressHTTPS            string
-	HTTPEnabled                 bool
-	AssetsPath                  string
-	Status                      *portainer.Status
-	ReverseTunnelService        portainer.ReverseTunnelService
-	ComposeStackManager         portainer.ComposeStackManager
-	CryptoService               portainer.CryptoService
-	EdgeStacksService           *edgestackservice.Service
-	SignatureService            portainer.DigitalSignatureService
-	SnapshotService             portainer.SnapshotService
-	FileService                 portainer.FileService
-	DataStore                   dataservices.DataStore
-	GitService                  portainer.GitService
-	OpenAMTService              portainer.OpenAMTService
-	APIKeyService               apikey.APIKeyService
-	JWTService                  portainer.JWTService
-	LDAPService                 portainer.LDAPService
-	OAuthService                portainer.OAuthService
-	SwarmStackManager           portainer.SwarmStackManager
-	ProxyManager                *proxy.Manager
-	KubernetesTokenCacheManager *kubernetes.TokenCacheManager
-	KubeClusterAccessService    k8s.KubeClusterAccessService
-	Handler                     *handler.Handler
-	SSLService                  *ssl.Service
-	DockerClientFactory         *dockerclient.ClientFactory
-	KubernetesClientFactory     *cli.ClientFactory
-	KubernetesDeployer          portainer.KubernetesDeployer
-	HelmPackageManager          libhelm.HelmPackageManager
-	Scheduler                   *scheduler.Scheduler
-	ShutdownCtx                 context.Context
-	ShutdownTrigger             context.CancelFunc
-	StackDeployer               deployments.StackDeployer
-	UpgradeService              upgrade.Service
-	AdminCreationDone           chan struct{}
-	PendingActionsService       *pendingactions.PendingActionsService
-	PlatformService             platform.Service
+	ACMEService                    *acme.Service
+	AuthorizationService           *authorization.Service
+	BindAddress                    string
+	BindAddressHTTPS               string
+	HTTPEnabled                    bool
+	MetricsEnabled                 bool
+	AssetsPath                     string
+	Status                         *portainer.Status
+	ReverseTunnelService           portainer.ReverseTunnelService
+	ComposeStackManager            portainer.ComposeStackManager
+	CryptoService                  portainer.CryptoService
+	DeploymentHistoryService       *deploymenthistory.Service
+	EdgeStacksService              *edgestackservice.Service
+	SignatureService               portainer.DigitalSignatureService
+	SnapshotService                portainer.SnapshotService
+	SettingsBus                    *settingsbus.Bus
+	FileService                    portainer.FileService
+	DataStore                      dataservices.DataStore
+	GitService                     portainer.GitService
+	OCIArtifactService             *ociartifact.Service
+	TemplateSources                *templatesources.Service
+	OpenAMTService                 portainer.OpenAMTService
+	AMTPowerService                *amtpower.Service
+	APIKeyService                  apikey.APIKeyService
+	JWTService                     portainer.JWTService
+	LDAPService                    portainer.LDAPService
+	OAuthService                   portainer.OAuthService
+	SwarmStackManager              portainer.SwarmStackManager
+	ProxyManager                   *proxy.Manager
+	KubernetesTokenCacheManager    *kubernetes.TokenCacheManager
+	KubernetesClusterAdminAuditLog *kubernetes.ClusterAdminAuditLog
+	KubeClusterAccessService       k8s.KubeClusterAccessService
+	Handler                        *handler.Handler
+	SSLService                     *ssl.Service
+	DockerClientFactory            *dockerclient.ClientFactory
+	AzureClientFactory             *azure.ClientFactory
+	CloudService                   *cloud.Service
+	KubernetesClientFactory        *cli.ClientFactory
+	KubernetesDeployer             portainer.KubernetesDeployer
+	HelmPackageManager             libhelm.HelmPackageManager
+	InsightsService                *insights.Service
+	DockerHubService               *dockerhub.Service
+	ImageUpdateService             *imageupdate.Service
+	FailoverService                *failover.Service
+	RegistryAccessService          *registryaccess.Service
+	NotificationService            *notifications.Service
+	EdgeUpdateService              *updateschedules.Service
+	QuotaService                   *quotas.Service
+	ReportService                  *reports.Service
+	GCService                      *gc.Service
+	Scheduler                      *scheduler.Scheduler
+	ShutdownCtx                    context.Context
+	ShutdownTrigger                context.CancelFunc
+	StackDeployer                  deployments.StackDeployer
+	UpgradeService                 upgrade.Service
+	AdminCreationDone              chan struct{}
+	PendingActionsService          *pendingactions.PendingActionsService
+	PlatformService                platform.Service
 }
 
 // Start starts the HTTP server
@@ -120,6 +180,7 @@ func (server *Server) Start() error {
 	kubernetesTokenCacheManager := server.KubernetesTokenCacheManager
 
 	requestBouncer := security.NewRequestBouncer(server.DataStore, server.JWTService, server.APIKeyService)
+	server.SettingsBus.Subscribe(requestBouncer.SettingsChanged)
 
 	rateLimiter := security.NewRateLimiter(10, 1*time.Second, 1*time.Hour)
 	offlineGate := offlinegate.NewOfflineGate()
@@ -147,27 +208,89 @@ func (server *Server) Start() error {
 		adminMonitor,
 	)
 
+	scheduledBackups := operations.NewScheduledBackups(server.DataStore, offlineGate, server.FileService, server.Scheduler)
+	if err := scheduledBackups.Start(); err != nil {
+		log.Warn().Err(err).Msg("unable to schedule the periodic backups")
+	}
+	server.SettingsBus.Subscribe(scheduledBackups.SettingsChanged)
+
 	var roleHandler = roles.NewHandler(requestBouncer)
 	roleHandler.DataStore = server.DataStore
+	roleHandler.AuthorizationService = server.AuthorizationService
+
+	var serviceAccountHandler = serviceaccounts.NewHandler(requestBouncer, server.APIKeyService)
+	serviceAccountHandler.DataStore = server.DataStore
+	serviceAccountHandler.AuthorizationService = server.AuthorizationService
+
+	var cloudProvisioningHandler = cloudprovisionings.NewHandler(requestBouncer)
+	cloudProvisioningHandler.DataStore = server.DataStore
+	cloudProvisioningHandler.CloudService = server.CloudService
 
 	var customTemplatesHandler = customtemplates.NewHandler(requestBouncer, server.DataStore, server.FileService, server.GitService)
 
+	var deploymentsHandler = deploymentshandler.NewHandler(requestBouncer)
+	deploymentsHandler.DataStore = server.DataStore
+
+	var imageUpdateJobsHandler = imageupdatejobs.NewHandler(requestBouncer)
+	imageUpdateJobsHandler.DataStore = server.DataStore
+	imageUpdateJobsHandler.ImageUpdateService = server.ImageUpdateService
+
+	var inactiveResourcesHandler = inactiveresources.NewHandler(requestBouncer)
+	inactiveResourcesHandler.DataStore = server.DataStore
+	inactiveResourcesHandler.GCService = server.GCService
+
+	var groupReportsHandler = reportshandler.NewHandler(requestBouncer)
+	groupReportsHandler.DataStore = server.DataStore
+	groupReportsHandler.ReportService = server.ReportService
+
+	var validationWebhooksHandler = validationwebhooks.NewHandler(requestBouncer)
+	validationWebhooksHandler.DataStore = server.DataStore
+
+	var snapshotWebhooksHandler = snapshotwebhooks.NewHandler(requestBouncer)
+	snapshotWebhooksHandler.DataStore = server.DataStore
+
+	var notificationChannelsHandler = notificationchannels.NewHandler(requestBouncer)
+	notificationChannelsHandler.DataStore = server.DataStore
+	notificationChannelsHandler.NotificationService = server.NotificationService
+
+	var quotasHandler = quotahandler.NewHandler(requestBouncer)
+	quotasHandler.DataStore = server.DataStore
+
+	var failoverPoliciesHandler = failoverpolicies.NewHandler(requestBouncer)
+	failoverPoliciesHandler.DataStore = server.DataStore
+	failoverPoliciesHandler.FailoverService = server.FailoverService
+
+	var edgeUpdateSchedulesHandler = edgeupdateschedules.NewHandler(requestBouncer, server.DataStore)
+	edgeUpdateSchedulesHandler.UpdateService = server.EdgeUpdateService
+
+	var edgeBundleHandler = edgebundle.NewHandler(requestBouncer)
+	edgeBundleHandler.DataStore = server.DataStore
+	edgeBundleHandler.FileService = server.FileService
+
 	var edgeGroupsHandler = edgegroups.NewHandler(requestBouncer)
 	edgeGroupsHandler.DataStore = server.DataStore
 	edgeGroupsHandler.ReverseTunnelService = server.ReverseTunnelService
 
+	edgeJobLogStreamer := joblogs.NewStreamer(server.FileService)
+
 	var edgeJobsHandler = edgejobs.NewHandler(requestBouncer)
 	edgeJobsHandler.DataStore = server.DataStore
 	edgeJobsHandler.FileService = server.FileService
 	edgeJobsHandler.ReverseTunnelService = server.ReverseTunnelService
+	edgeJobsHandler.EdgeJobLogStreamer = edgeJobLogStreamer
+
+	var edgeJoinTokensHandler = edgejointokens.NewHandler(requestBouncer)
+	edgeJoinTokensHandler.DataStore = server.DataStore
 
 	var edgeStacksHandler = edgestacks.NewHandler(requestBouncer, server.DataStore, server.EdgeStacksService)
 	edgeStacksHandler.FileService = server.FileService
 	edgeStacksHandler.GitService = server.GitService
 	edgeStacksHandler.KubernetesDeployer = server.KubernetesDeployer
+	edgeStacksHandler.DeploymentHistoryService = server.DeploymentHistoryService
 
 	var edgeTemplatesHandler = edgetemplates.NewHandler(requestBouncer)
 	edgeTemplatesHandler.DataStore = server.DataStore
+	edgeTemplatesHandler.TemplateSources = server.TemplateSources
 
 	var endpointHandler = endpoints.NewHandler(requestBouncer)
 	endpointHandler.DataStore = server.DataStore
@@ -182,25 +305,45 @@ func (server *Server) Start() error {
 	endpointHandler.BindAddress = server.BindAddress
 	endpointHandler.BindAddressHTTPS = server.BindAddressHTTPS
 	endpointHandler.PendingActionsService = server.PendingActionsService
+	endpointHandler.InsightsService = server.InsightsService
+	endpointHandler.DockerHubService = server.DockerHubService
 
 	var endpointEdgeHandler = endpointedge.NewHandler(requestBouncer, server.DataStore, server.FileService, server.ReverseTunnelService)
+	endpointEdgeHandler.EdgeJobLogStreamer = edgeJobLogStreamer
+	endpointEdgeHandler.PendingActionsService = server.PendingActionsService
 
 	var endpointGroupHandler = endpointgroups.NewHandler(requestBouncer)
 	endpointGroupHandler.AuthorizationService = server.AuthorizationService
 	endpointGroupHandler.DataStore = server.DataStore
 	endpointGroupHandler.PendingActionsService = server.PendingActionsService
 
-	var endpointProxyHandler = endpointproxy.NewHandler(requestBouncer)
-	endpointProxyHandler.DataStore = server.DataStore
-	endpointProxyHandler.ProxyManager = server.ProxyManager
-	endpointProxyHandler.ReverseTunnelService = server.ReverseTunnelService
+	var proxyRateLimit portainer.ProxyRateLimitSettings
+	if settings, err := server.DataStore.Settings().Settings(); err == nil {
+		proxyRateLimit = settings.ProxyRateLimit
+	} else {
+		log.Warn().Err(err).Msg("unable to retrieve the rate limit of the proxied requests")
+	}
+
+	proxyRateLimiter := security.NewProxyRateLimiter(proxyRateLimit)
+	server.SettingsBus.Subscribe(proxyRateLimiter.SettingsChanged)
 
 	var kubernetesHandler = kubehandler.NewHandler(requestBouncer, server.AuthorizationService, server.DataStore, server.JWTService, server.KubeClusterAccessService, server.KubernetesClientFactory, nil)
+	kubernetesHandler.ClusterAdminAuditLog = server.KubernetesClusterAdminAuditLog
 
 	containerService := docker.NewContainerService(server.DockerClientFactory, server.DataStore)
 
 	var dockerHandler = dockerhandler.NewHandler(requestBouncer, server.AuthorizationService, server.DataStore, server.DockerClientFactory, containerService)
 
+	var endpointProxyHandler = endpointproxy.NewHandler(requestBouncer, proxyRateLimiter, dockerHandler)
+	endpointProxyHandler.DataStore = server.DataStore
+	endpointProxyHandler.ProxyManager = server.ProxyManager
+	endpointProxyHandler.ReverseTunnelService = server.ReverseTunnelService
+
+	var azureHandler = azurehandler.NewHandler(requestBouncer, server.DataStore, server.AzureClientFactory)
+
+	var exportsHandler = exports.NewHandler(requestBouncer)
+	exportsHandler.DataStore = server.DataStore
+
 	var fileHandler = file.NewHandler(filepath.Join(server.AssetsPath, "public"), adminMonitor.WasInstanceDisabled)
 
 	var endpointHelmHandler = helm.NewHandler(requestBouncer, server.DataStore, server.JWTService, server.KubernetesDeployer, server.HelmPackageManager, server.KubeClusterAccessService)
@@ -221,6 +364,8 @@ func (server *Server) Start() error {
 	registryHandler.FileService = server.FileService
 	registryHandler.ProxyManager = server.ProxyManager
 	registryHandler.K8sClientFactory = server.KubernetesClientFactory
+	registryHandler.RegistryClientService = registryclient.NewService(server.DataStore)
+	registryHandler.RegistryAccessService = server.RegistryAccessService
 
 	var resourceControlHandler = resourcecontrols.NewHandler(requestBouncer)
 	resourceControlHandler.DataStore = server.DataStore
@@ -230,15 +375,21 @@ func (server *Server) Start() error {
 	settingsHandler.FileService = server.FileService
 	settingsHandler.JWTService = server.JWTService
 	settingsHandler.LDAPService = server.LDAPService
-	settingsHandler.SnapshotService = server.SnapshotService
+	settingsHandler.OAuthService = server.OAuthService
+	settingsHandler.SettingsBus = server.SettingsBus
 
 	var sslHandler = sslhandler.NewHandler(requestBouncer)
 	sslHandler.SSLService = server.SSLService
+	sslHandler.ACMEService = server.ACMEService
+
+	var uiOverridesHandler = uioverrides.NewHandler(requestBouncer)
+	uiOverridesHandler.FileService = server.FileService
 
 	openAMTHandler := openamt.NewHandler(requestBouncer)
 	openAMTHandler.OpenAMTService = server.OpenAMTService
 	openAMTHandler.DataStore = server.DataStore
 	openAMTHandler.DockerClientFactory = server.DockerClientFactory
+	openAMTHandler.PowerService = server.AMTPowerService
 
 	var stackHandler = stacks.NewHandler(requestBouncer)
 	stackHandler.DataStore = server.DataStore
@@ -247,10 +398,13 @@ func (server *Server) Start() error {
 	stackHandler.KubernetesClientFactory = server.KubernetesClientFactory
 	stackHandler.KubernetesDeployer = server.KubernetesDeployer
 	stackHandler.GitService = server.GitService
+	stackHandler.OCIArtifactService = server.OCIArtifactService
 	stackHandler.Scheduler = server.Scheduler
 	stackHandler.SwarmStackManager = server.SwarmStackManager
 	stackHandler.ComposeStackManager = server.ComposeStackManager
 	stackHandler.StackDeployer = server.StackDeployer
+	stackHandler.QuotaService = server.QuotaService
+	stackHandler.DeploymentHistoryService = server.DeploymentHistoryService
 
 	var storybookHandler = storybook.NewHandler(server.AssetsPath)
 
@@ -274,6 +428,7 @@ func (server *Server) Start() error {
 	templatesHandler.DataStore = server.DataStore
 	templatesHandler.FileService = server.FileService
 	templatesHandler.GitService = server.GitService
+	templatesHandler.TemplateSources = server.TemplateSources
 
 	var uploadHandler = upload.NewHandler(requestBouncer)
 	uploadHandler.FileService = server.FileService
@@ -293,44 +448,70 @@ func (server *Server) Start() error {
 	var webhookHandler = webhooks.NewHandler(requestBouncer)
 	webhookHandler.DataStore = server.DataStore
 	webhookHandler.DockerClientFactory = server.DockerClientFactory
+	webhookHandler.ContainerService = containerService
 
 	server.Handler = &handler.Handler{
-		RoleHandler:            roleHandler,
-		AuthHandler:            authHandler,
-		BackupHandler:          backupHandler,
-		CustomTemplatesHandler: customTemplatesHandler,
-		DockerHandler:          dockerHandler,
-		EdgeGroupsHandler:      edgeGroupsHandler,
-		EdgeJobsHandler:        edgeJobsHandler,
-		EdgeStacksHandler:      edgeStacksHandler,
-		EdgeTemplatesHandler:   edgeTemplatesHandler,
-		EndpointGroupHandler:   endpointGroupHandler,
-		EndpointHandler:        endpointHandler,
-		EndpointHelmHandler:    endpointHelmHandler,
-		EndpointEdgeHandler:    endpointEdgeHandler,
-		EndpointProxyHandler:   endpointProxyHandler,
-		GitOperationHandler:    gitOperationHandler,
-		FileHandler:            fileHandler,
-		LDAPHandler:            ldapHandler,
-		HelmTemplatesHandler:   helmTemplatesHandler,
-		KubernetesHandler:      kubernetesHandler,
-		MOTDHandler:            motdHandler,
-		OpenAMTHandler:         openAMTHandler,
-		RegistryHandler:        registryHandler,
-		ResourceControlHandler: resourceControlHandler,
-		SettingsHandler:        settingsHandler,
-		SSLHandler:             sslHandler,
-		StackHandler:           stackHandler,
-		StorybookHandler:       storybookHandler,
-		SystemHandler:          systemHandler,
-		TagHandler:             tagHandler,
-		TeamHandler:            teamHandler,
-		TeamMembershipHandler:  teamMembershipHandler,
-		TemplatesHandler:       templatesHandler,
-		UploadHandler:          uploadHandler,
-		UserHandler:            userHandler,
-		WebSocketHandler:       websocketHandler,
-		WebhookHandler:         webhookHandler,
+		RoleHandler:              roleHandler,
+		ServiceAccountHandler:    serviceAccountHandler,
+		AuthHandler:              authHandler,
+		AzureHandler:             azureHandler,
+		BackupHandler:            backupHandler,
+		CloudProvisioningHandler: cloudProvisioningHandler,
+		CustomTemplatesHandler:   customTemplatesHandler,
+		DockerHandler:            dockerHandler,
+		EdgeBundleHandler:        edgeBundleHandler,
+		EdgeUpdateHandler:        edgeUpdateSchedulesHandler,
+		EdgeGroupsHandler:        edgeGroupsHandler,
+		EdgeJobsHandler:          edgeJobsHandler,
+		EdgeJoinTokensHandler:    edgeJoinTokensHandler,
+		DeploymentsHandler:       deploymentsHandler,
+		EdgeStacksHandler:        edgeStacksHandler,
+		EdgeTemplatesHandler:     edgeTemplatesHandler,
+		EndpointGroupHandler:     endpointGroupHandler,
+		EndpointHandler:          endpointHandler,
+		EndpointHelmHandler:      endpointHelmHandler,
+		EndpointEdgeHandler:      endpointEdgeHandler,
+		EndpointProxyHandler:     endpointProxyHandler,
+		ExportsHandler:           exportsHandler,
+		GitOperationHandler:      gitOperationHandler,
+		GroupReportsHandler:      groupReportsHandler,
+		FileHandler:              fileHandler,
+		LDAPHandler:              ldapHandler,
+		FailoverPoliciesHandler:  failoverPoliciesHandler,
+		HelmTemplatesHandler:     helmTemplatesHandler,
+		ImageUpdateJobsHandler:   imageUpdateJobsHandler,
+		InactiveResourcesHandler: inactiveResourcesHandler,
+		KubernetesHandler:        kubernetesHandler,
+		MOTDHandler:              motdHandler,
+		OpenAMTHandler:           openAMTHandler,
+		QuotasHandler:            quotasHandler,
+		RegistryHandler:          registryHandler,
+		ResourceControlHandler:   resourceControlHandler,
+		SettingsHandler:          settingsHandler,
+		SnapshotWebhookHandler:   snapshotWebhooksHandler,
+		NotificationHandler:      notificationChannelsHandler,
+		SSLHandler:               sslHandler,
+		StackHandler:             stackHandler,
+		StorybookHandler:         storybookHandler,
+		SystemHandler:            systemHandler,
+		TagHandler:               tagHandler,
+		TeamHandler:              teamHandler,
+		TeamMembershipHandler:    teamMembershipHandler,
+		TemplatesHandler:         templatesHandler,
+		UIOverridesHandler:       uiOverridesHandler,
+		UploadHandler:            uploadHandler,
+		UserHandler:              userHandler,
+		ValidationWebhookHandler: validationWebhooksHandler,
+		WebSocketHandler:         websocketHandler,
+		WebhookHandler:           webhookHandler,
+	}
+
+	if server.MetricsEnabled {
+		server.Handler.MetricsHandler = metricshandler.NewHandler(requestBouncer)
+	}
+
+	if server.ACMEService != nil {
+		server.Handler.ACMEChallengeHandler = server.ACMEService
 	}
 
 	errorLogger := NewHTTPLogger()
@@ -339,6 +520,10 @@ func (server *Server) Start() error {
 
 	handler = middlewares.WithSlowRequestsLogger(handler)
 
+	if server.MetricsEnabled {
+		handler = metrics.WithRequestMetrics(handler)
+	}
+
 	handler, err := csrf.WithProtect(handler)
 	if err != nil {
 		return errors.Wrap(err, "failed to create CSRF middleware")
@@ -0,0 +1,515 @@
+package acme
+
+import (
+	"cmp"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/crypto/acme"
+)
+
+const (
+	// RenewalCheckInterval is how often the expiry of the certificate is checked
+	RenewalCheckInterval = time.Hour
+	// RenewalMargin is how long before its expiry the certificate is renewed
+	RenewalMargin = 30 * 24 * time.Hour
+	// LetsEncryptDirectoryURL is the directory of the Let's Encrypt production certificate authority, it is used
+	// when no directory is specified in the settings
+	LetsEncryptDirectoryURL = acme.LetsEncryptURL
+	// ChallengePathPrefix is the path under which the tokens of the HTTP-01 challenges are served
+	ChallengePathPrefix = "/.well-known/acme-challenge/"
+
+	obtainTimeout          = 10 * time.Minute
+	dnsPropagationTimeout  = 5 * time.Minute
+	dnsPropagationInterval = 10 * time.Second
+)
+
+// ErrInvalidSettings is returned when a certificate cannot be requested with the ACME settings
+var ErrInvalidSettings = errors.New("invalid ACME settings")
+
+// certificateService is the part of the SSL service used to read and replace the HTTPS certificate
+type certificateService interface {
+	GetRawCertificate() *tls.Certificate
+	UpdateCertificates(certData, keyData []byte) error
+}
+
+// Service obtains the HTTPS certificate of Portainer from an ACME certificate authority and renews it before
+// it expires. The new certificates are handed to the SSL service which uses them without a restart
+type Service struct {
+	dataStore   dataservices.DataStore
+	fileService portainer.FileService
+	sslService  certificateService
+	// tokens holds the key authorizations of the pending HTTP-01 challenges, by token
+	tokens   map[string]string
+	tokensMu sync.RWMutex
+	// obtainMu prevents several certificates from being obtained at the same time
+	obtainMu sync.Mutex
+	// lookupTXT resolves the TXT records of a domain, it is used to wait for the propagation of the DNS-01 records
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService(dataStore dataservices.DataStore, fileService portainer.FileService, sslService certificateService) *Service {
+	return &Service{
+		dataStore:   dataStore,
+		fileService: fileService,
+		sslService:  sslService,
+		tokens:      make(map[string]string),
+		lookupTXT:   net.DefaultResolver.LookupTXT,
+	}
+}
+
+// ServeHTTP serves the key authorizations of the pending HTTP-01 challenges
+func (service *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.URL.Path, ChallengePathPrefix)
+
+	service.tokensMu.RLock()
+	keyAuthorization, ok := service.tokens[token]
+	service.tokensMu.RUnlock()
+
+	if !ok {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = w.Write([]byte(keyAuthorization))
+}
+
+// NormalizeSettings lower cases and deduplicates the domains of the settings
+func NormalizeSettings(settings *portainer.ACMESettings) {
+	domains := make([]string, 0, len(settings.Domains))
+	for _, domain := range settings.Domains {
+		domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+		if domain != "" && !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+
+	settings.Domains = domains
+	settings.Email = strings.TrimSpace(settings.Email)
+}
+
+// ValidateSettings checks that a certificate can be requested with the settings, the settings are expected to
+// be normalized
+func ValidateSettings(settings *portainer.ACMESettings) error {
+	if !settings.Enabled {
+		return nil
+	}
+
+	if len(settings.Domains) == 0 {
+		return errors.New("at least one domain is required")
+	}
+
+	for _, domain := range settings.Domains {
+		name, wildcard := strings.CutPrefix(domain, "*.")
+		if strings.ContainsAny(name, "*/:@ ") || !strings.Contains(name, ".") {
+			return fmt.Errorf("invalid domain %q", domain)
+		}
+
+		if wildcard && settings.ChallengeType != portainer.ACMEChallengeDNS01 {
+			return fmt.Errorf("the wildcard domain %q requires the %s challenge", domain, portainer.ACMEChallengeDNS01)
+		}
+	}
+
+	if settings.DirectoryURL != "" {
+		directoryURL, err := url.Parse(settings.DirectoryURL)
+		if err != nil || directoryURL.Scheme != "https" || directoryURL.Host == "" {
+			return errors.New("the directory URL must be an HTTPS URL")
+		}
+	}
+
+	switch settings.ChallengeType {
+	case portainer.ACMEChallengeHTTP01:
+		return nil
+	case portainer.ACMEChallengeDNS01:
+		_, err := NewDNSProvider(settings.DNSProvider, settings.DNSCredentials)
+
+		return err
+	}
+
+	return fmt.Errorf("unsupported challenge type %q", settings.ChallengeType)
+}
+
+// NeedsRenewal returns true when the certificate does not cover all the domains or expires within the renewal margin
+func NeedsRenewal(cert *tls.Certificate, domains []string, now time.Time) bool {
+	if cert == nil || len(cert.Certificate) == 0 {
+		return true
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return true
+	}
+
+	if leaf.NotAfter.Before(now.Add(RenewalMargin)) {
+		return true
+	}
+
+	for _, domain := range domains {
+		if !slices.Contains(leaf.DNSNames, domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UpdateSettings persists the ACME settings and obtains a new certificate in the background when they are
+// enabled. The DNS credentials are kept when none are specified, the returned error wraps ErrInvalidSettings
+// when the settings are rejected
+func (service *Service) UpdateSettings(settings *portainer.ACMESettings) error {
+	NormalizeSettings(settings)
+
+	err := service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		sslSettings, err := tx.SSLSettings().Settings()
+		if err != nil {
+			return err
+		}
+
+		if sslSettings.ACME != nil {
+			if settings.DNSCredentials == nil && settings.DNSProvider == sslSettings.ACME.DNSProvider {
+				settings.DNSCredentials = sslSettings.ACME.DNSCredentials
+			}
+
+			settings.CertificateExpiresAt = sslSettings.ACME.CertificateExpiresAt
+		}
+
+		if err := ValidateSettings(settings); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidSettings, err)
+		}
+
+		settings.LastError = ""
+		sslSettings.ACME = settings
+
+		return tx.SSLSettings().UpdateSettings(sslSettings)
+	})
+	if err != nil {
+		return err
+	}
+
+	if settings.Enabled {
+		service.Trigger()
+	}
+
+	return nil
+}
+
+// RenewIfNeeded obtains a new certificate when the current one does not cover the domains or is about to
+// expire, it is run periodically. The failures are recorded in the settings and retried on the next run
+func (service *Service) RenewIfNeeded() error {
+	settings, err := service.settings()
+	if err != nil || settings == nil || !settings.Enabled {
+		return err
+	}
+
+	if !NeedsRenewal(service.sslService.GetRawCertificate(), settings.Domains, time.Now()) {
+		return nil
+	}
+
+	service.obtainAndRecord(settings)
+
+	return nil
+}
+
+// Trigger obtains a new certificate in the background, it does nothing when a certificate is already being obtained
+func (service *Service) Trigger() {
+	go func() {
+		settings, err := service.settings()
+		if err != nil {
+			log.Error().Err(err).Msg("unable to retrieve the ACME settings")
+
+			return
+		}
+
+		if settings != nil && settings.Enabled {
+			service.obtainAndRecord(settings)
+		}
+	}()
+}
+
+func (service *Service) settings() (*portainer.ACMESettings, error) {
+	sslSettings, err := service.dataStore.SSLSettings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	return sslSettings.ACME, nil
+}
+
+func (service *Service) obtainAndRecord(settings *portainer.ACMESettings) {
+	if !service.obtainMu.TryLock() {
+		return
+	}
+	defer service.obtainMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), obtainTimeout)
+	defer cancel()
+
+	expiresAt, obtainErr := service.obtain(ctx, settings)
+	if obtainErr != nil {
+		log.Error().Err(obtainErr).Strs("domains", settings.Domains).Msg("unable to obtain a certificate from the ACME certificate authority")
+	} else {
+		log.Info().Strs("domains", settings.Domains).Time("expires_at", time.Unix(expiresAt, 0)).Msg("obtained a new certificate from the ACME certificate authority")
+	}
+
+	err := service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		sslSettings, err := tx.SSLSettings().Settings()
+		if err != nil || sslSettings.ACME == nil {
+			return err
+		}
+
+		if obtainErr != nil {
+			sslSettings.ACME.LastError = obtainErr.Error()
+		} else {
+			sslSettings.ACME.LastError = ""
+			sslSettings.ACME.CertificateExpiresAt = expiresAt
+		}
+
+		return tx.SSLSettings().UpdateSettings(sslSettings)
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("unable to persist the ACME status")
+	}
+}
+
+// obtain requests a certificate covering the domains and installs it, it returns the expiry of the certificate
+func (service *Service) obtain(ctx context.Context, settings *portainer.ACMESettings) (int64, error) {
+	accountKey, err := service.accountKey()
+	if err != nil {
+		return 0, fmt.Errorf("unable to load the ACME account key: %w", err)
+	}
+
+	client := &acme.Client{
+		Key:          accountKey,
+		DirectoryURL: cmp.Or(settings.DirectoryURL, LetsEncryptDirectoryURL),
+		UserAgent:    "portainer",
+	}
+
+	account := &acme.Account{}
+	if settings.Email != "" {
+		account.Contact = []string{"mailto:" + settings.Email}
+	}
+
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return 0, fmt.Errorf("unable to register the ACME account: %w", err)
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(settings.Domains...))
+	if err != nil {
+		return 0, fmt.Errorf("unable to create the certificate order: %w", err)
+	}
+
+	for _, authzURL := range order.AuthzURLs {
+		if err := service.authorize(ctx, client, authzURL, settings); err != nil {
+			return 0, err
+		}
+	}
+
+	order, err = client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return 0, fmt.Errorf("the certificate order failed: %w", err)
+	}
+
+	certKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return 0, err
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: settings.Domains}, certKey)
+	if err != nil {
+		return 0, err
+	}
+
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return 0, fmt.Errorf("unable to retrieve the certificate: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return 0, err
+	}
+
+	var certPEM []byte
+	for _, der := range chain {
+		certPEM = append(certPEM, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(certKey)
+	if err != nil {
+		return 0, err
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	if err := service.sslService.UpdateCertificates(certPEM, keyPEM); err != nil {
+		return 0, fmt.Errorf("unable to install the certificate: %w", err)
+	}
+
+	return leaf.NotAfter.Unix(), nil
+}
+
+// authorize completes the challenge of an authorization of the order
+func (service *Service) authorize(ctx context.Context, client *acme.Client, authzURL string, settings *portainer.ACMESettings) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the authorization: %w", err)
+	}
+
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	domain := authz.Identifier.Value
+
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == string(settings.ChallengeType) {
+			challenge = c
+
+			break
+		}
+	}
+
+	if challenge == nil {
+		return fmt.Errorf("the certificate authority does not offer the %s challenge for %s", settings.ChallengeType, domain)
+	}
+
+	switch settings.ChallengeType {
+	case portainer.ACMEChallengeHTTP01:
+		keyAuthorization, err := client.HTTP01ChallengeResponse(challenge.Token)
+		if err != nil {
+			return err
+		}
+
+		service.setToken(challenge.Token, keyAuthorization)
+		defer service.deleteToken(challenge.Token)
+
+	case portainer.ACMEChallengeDNS01:
+		provider, err := NewDNSProvider(settings.DNSProvider, settings.DNSCredentials)
+		if err != nil {
+			return err
+		}
+
+		record, err := client.DNS01ChallengeRecord(challenge.Token)
+		if err != nil {
+			return err
+		}
+
+		fqdn := "_acme-challenge." + strings.TrimPrefix(domain, "*.")
+		if err := provider.Present(ctx, fqdn, record); err != nil {
+			return fmt.Errorf("unable to publish the DNS record of %s: %w", domain, err)
+		}
+
+		defer func() {
+			if err := provider.CleanUp(context.WithoutCancel(ctx), fqdn, record); err != nil {
+				log.Warn().Err(err).Str("record", fqdn).Msg("unable to remove the DNS record of the ACME challenge")
+			}
+		}()
+
+		if err := service.waitForTXTRecord(ctx, fqdn, record); err != nil {
+			return err
+		}
+	}
+
+	if _, err := client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("unable to accept the challenge of %s: %w", domain, err)
+	}
+
+	if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("the authorization of %s failed: %w", domain, err)
+	}
+
+	return nil
+}
+
+// waitForTXTRecord waits until the TXT record is visible from the resolver of Portainer, so that the
+// certificate authority does not validate the challenge before the record propagated
+func (service *Service) waitForTXTRecord(ctx context.Context, fqdn, value string) error {
+	ctx, cancel := context.WithTimeout(ctx, dnsPropagationTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(dnsPropagationInterval)
+	defer ticker.Stop()
+
+	for {
+		records, err := service.lookupTXT(ctx, fqdn)
+		if err == nil && slices.Contains(records, value) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("the DNS record %s did not propagate in time", fqdn)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (service *Service) setToken(token, keyAuthorization string) {
+	service.tokensMu.Lock()
+	defer service.tokensMu.Unlock()
+
+	service.tokens[token] = keyAuthorization
+}
+
+func (service *Service) deleteToken(token string) {
+	service.tokensMu.Lock()
+	defer service.tokensMu.Unlock()
+
+	delete(service.tokens, token)
+}
+
+// accountKey loads the private key of the ACME account, a new key is generated on first use
+func (service *Service) accountKey() (*ecdsa.PrivateKey, error) {
+	data, err := service.fileService.GetACMEAccountKey()
+	if err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, errors.New("invalid PEM data")
+		}
+
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.fileService.StoreACMEAccountKey(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
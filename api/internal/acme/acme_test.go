@@ -0,0 +1,153 @@
+package acme
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCertificate(t *testing.T, notAfter time.Time, domains ...string) *tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+		DNSNames:     domains,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestNeedsRenewal(t *testing.T) {
+	now := time.Now()
+	domains := []string{"portainer.example.com", "*.example.com"}
+
+	assert.True(t, NeedsRenewal(nil, domains, now))
+	assert.True(t, NeedsRenewal(newTestCertificate(t, now.Add(10*24*time.Hour), domains...), domains, now))
+	assert.True(t, NeedsRenewal(newTestCertificate(t, now.Add(60*24*time.Hour), "portainer.example.com"), domains, now))
+	assert.False(t, NeedsRenewal(newTestCertificate(t, now.Add(60*24*time.Hour), domains...), domains, now))
+}
+
+func TestValidateSettings(t *testing.T) {
+	valid := func() *portainer.ACMESettings {
+		return &portainer.ACMESettings{
+			Enabled:       true,
+			Domains:       []string{" Portainer.Example.com. ", "portainer.example.com"},
+			ChallengeType: portainer.ACMEChallengeHTTP01,
+		}
+	}
+
+	settings := valid()
+	NormalizeSettings(settings)
+	assert.Equal(t, []string{"portainer.example.com"}, settings.Domains)
+	require.NoError(t, ValidateSettings(settings))
+
+	settings = valid()
+	settings.Domains = []string{"*.example.com"}
+	require.Error(t, ValidateSettings(settings), "a wildcard domain requires the DNS-01 challenge")
+
+	settings.ChallengeType = portainer.ACMEChallengeDNS01
+	settings.DNSProvider = DNSProviderCloudflare
+	require.Error(t, ValidateSettings(settings), "the cloudflare provider requires an API token")
+
+	settings.DNSCredentials = map[string]string{"apiToken": "token"}
+	require.NoError(t, ValidateSettings(settings))
+
+	settings = valid()
+	settings.DirectoryURL = "http://acme.example.com/directory"
+	require.Error(t, ValidateSettings(settings))
+
+	settings = valid()
+	settings.ChallengeType = "tls-alpn-01"
+	require.Error(t, ValidateSettings(settings))
+
+	require.NoError(t, ValidateSettings(&portainer.ACMESettings{}))
+}
+
+func TestServeHTTP(t *testing.T) {
+	service := NewService(nil, nil, nil)
+	service.setToken("token", "token.thumbprint")
+
+	rr := httptest.NewRecorder()
+	service.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ChallengePathPrefix+"token", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "token.thumbprint", rr.Body.String())
+
+	service.deleteToken("token")
+
+	rr = httptest.NewRecorder()
+	service.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, ChallengePathPrefix+"token", nil))
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestCloudflareProvider(t *testing.T) {
+	records := map[string]cloudflareRecord{}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		var result any = []any{}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/zones":
+			if r.URL.Query().Get("name") == "example.com" {
+				result = []map[string]string{{"id": "zone"}}
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/zones/zone/dns_records":
+			var record cloudflareRecord
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&record))
+			record.ID = "record"
+			records[record.ID] = record
+			result = record
+		case r.Method == http.MethodGet && r.URL.Path == "/zones/zone/dns_records":
+			list := []cloudflareRecord{}
+			for _, record := range records {
+				if record.Name == r.URL.Query().Get("name") && record.Content == r.URL.Query().Get("content") {
+					list = append(list, record)
+				}
+			}
+			result = list
+		case r.Method == http.MethodDelete && r.URL.Path == "/zones/zone/dns_records/record":
+			delete(records, "record")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]any{"success": false, "errors": []map[string]string{{"message": "not found"}}})
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "result": result})
+	}))
+	defer srv.Close()
+
+	provider := &cloudflareProvider{client: srv.Client(), baseURL: srv.URL, apiToken: "token"}
+
+	require.NoError(t, provider.Present(context.Background(), "_acme-challenge.portainer.example.com", "value"))
+	require.Len(t, records, 1)
+	assert.Equal(t, "TXT", records["record"].Type)
+	assert.Equal(t, "value", records["record"].Content)
+
+	require.NoError(t, provider.CleanUp(context.Background(), "_acme-challenge.portainer.example.com", "value"))
+	assert.Empty(t, records)
+
+	require.Error(t, provider.Present(context.Background(), "_acme-challenge.portainer.example.org", "value"))
+}
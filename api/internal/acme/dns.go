@@ -0,0 +1,241 @@
+package acme
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	// DNSProviderCloudflare publishes the records through the Cloudflare API, it requires an apiToken credential
+	// allowed to edit the DNS zone
+	DNSProviderCloudflare = "cloudflare"
+	// DNSProviderHTTPReq delegates the records to an HTTP endpoint, it requires an endpoint credential and
+	// accepts optional username and password credentials for basic authentication
+	DNSProviderHTTPReq = "httpreq"
+
+	cloudflareAPIURL = "https://api.cloudflare.com/client/v4"
+	dnsRecordTTL     = 120
+	dnsClientTimeout = 30 * time.Second
+)
+
+// DNSProvider publishes the TXT records of the DNS-01 challenges
+type DNSProvider interface {
+	// Present creates the TXT record fqdn with the specified value
+	Present(ctx context.Context, fqdn, value string) error
+	// CleanUp removes the TXT record created by Present
+	CleanUp(ctx context.Context, fqdn, value string) error
+}
+
+// NewDNSProvider returns the DNS provider with the specified name, configured with the credentials
+func NewDNSProvider(name string, credentials map[string]string) (DNSProvider, error) {
+	client := &http.Client{Timeout: dnsClientTimeout}
+
+	switch name {
+	case DNSProviderCloudflare:
+		if credentials["apiToken"] == "" {
+			return nil, errors.New("the cloudflare DNS provider requires an apiToken credential")
+		}
+
+		return &cloudflareProvider{
+			client:   client,
+			baseURL:  cloudflareAPIURL,
+			apiToken: credentials["apiToken"],
+		}, nil
+
+	case DNSProviderHTTPReq:
+		endpoint, err := url.Parse(credentials["endpoint"])
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			return nil, errors.New("the httpreq DNS provider requires an HTTP(S) endpoint credential")
+		}
+
+		return &httpReqProvider{
+			client:   client,
+			endpoint: strings.TrimSuffix(endpoint.String(), "/"),
+			username: credentials["username"],
+			password: credentials["password"],
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported DNS provider %q", name)
+}
+
+type cloudflareProvider struct {
+	client   *http.Client
+	baseURL  string
+	apiToken string
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result json.RawMessage `json:"result"`
+}
+
+type cloudflareRecord struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl"`
+}
+
+func (provider *cloudflareProvider) Present(ctx context.Context, fqdn, value string) error {
+	zoneID, err := provider.zoneID(ctx, fqdn)
+	if err != nil {
+		return err
+	}
+
+	record := cloudflareRecord{Type: "TXT", Name: fqdn, Content: value, TTL: dnsRecordTTL}
+
+	return provider.do(ctx, http.MethodPost, "/zones/"+zoneID+"/dns_records", record, nil)
+}
+
+func (provider *cloudflareProvider) CleanUp(ctx context.Context, fqdn, value string) error {
+	zoneID, err := provider.zoneID(ctx, fqdn)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{"type": {"TXT"}, "name": {fqdn}, "content": {value}}
+
+	var records []cloudflareRecord
+	if err := provider.do(ctx, http.MethodGet, "/zones/"+zoneID+"/dns_records?"+query.Encode(), nil, &records); err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if err := provider.do(ctx, http.MethodDelete, "/zones/"+zoneID+"/dns_records/"+record.ID, nil, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// zoneID returns the identifier of the closest zone containing the record
+func (provider *cloudflareProvider) zoneID(ctx context.Context, fqdn string) (string, error) {
+	labels := strings.Split(strings.TrimSuffix(fqdn, "."), ".")
+
+	for i := 0; i < len(labels)-1; i++ {
+		var zones []struct {
+			ID string `json:"id"`
+		}
+
+		query := url.Values{"name": {strings.Join(labels[i:], ".")}}
+		if err := provider.do(ctx, http.MethodGet, "/zones?"+query.Encode(), nil, &zones); err != nil {
+			return "", err
+		}
+
+		if len(zones) > 0 {
+			return zones[0].ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("no Cloudflare zone found for %s", fqdn)
+}
+
+func (provider *cloudflareProvider) do(ctx context.Context, method, path string, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, provider.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+provider.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := provider.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var response cloudflareResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("unexpected response from the Cloudflare API, status %d: %w", resp.StatusCode, err)
+	}
+
+	if !response.Success {
+		messages := make([]string, 0, len(response.Errors))
+		for _, e := range response.Errors {
+			messages = append(messages, e.Message)
+		}
+
+		return fmt.Errorf("the Cloudflare API returned an error, status %d: %s", resp.StatusCode, strings.Join(messages, ", "))
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(response.Result, result)
+}
+
+type httpReqProvider struct {
+	client   *http.Client
+	endpoint string
+	username string
+	password string
+}
+
+func (provider *httpReqProvider) Present(ctx context.Context, fqdn, value string) error {
+	return provider.send(ctx, "/present", fqdn, value)
+}
+
+func (provider *httpReqProvider) CleanUp(ctx context.Context, fqdn, value string) error {
+	return provider.send(ctx, "/cleanup", fqdn, value)
+}
+
+func (provider *httpReqProvider) send(ctx context.Context, path, fqdn, value string) error {
+	data, err := json.Marshal(map[string]string{
+		"fqdn":  strings.TrimSuffix(fqdn, ".") + ".",
+		"value": value,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.endpoint+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if provider.username != "" || provider.password != "" {
+		req.SetBasicAuth(provider.username, provider.password)
+	}
+
+	resp, err := provider.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return fmt.Errorf("the DNS endpoint returned the status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
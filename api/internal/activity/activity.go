@@ -0,0 +1,308 @@
+package activity
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	dockerclient "github.com/portainer/portainer/api/docker/client"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// EventRetention is the number of events kept in the activity feed of each environment(endpoint)
+	EventRetention = 1000
+	// SyncInterval is the interval at which the subscriptions are matched against the environments(endpoints)
+	SyncInterval = time.Minute
+
+	flushInterval = 5 * time.Second
+	flushSize     = 100
+	retryDelay    = 30 * time.Second
+)
+
+// trackedAttributes are the attributes of the Docker events kept in the activity feed, the other ones
+// such as the labels of the containers are dropped
+var trackedAttributes = []string{
+	"image",
+	"exitCode",
+	"signal",
+	"container",
+	"driver",
+	"type",
+	"com.docker.compose.project",
+	"com.docker.stack.namespace",
+}
+
+// dockerClient is the subset of the Docker client used to consume the events
+type dockerClient interface {
+	Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error)
+}
+
+// Consumer subscribes to the Docker events of the environments(endpoints) and records them in their activity feed
+type Consumer struct {
+	dataStore           dataservices.DataStore
+	dockerClientFactory *dockerclient.ClientFactory
+	shutdownCtx         context.Context
+
+	mu            sync.Mutex
+	subscriptions map[portainer.EndpointID]context.CancelFunc
+}
+
+// NewConsumer returns a new instance of Consumer
+func NewConsumer(dataStore dataservices.DataStore, dockerClientFactory *dockerclient.ClientFactory, shutdownCtx context.Context) *Consumer {
+	return &Consumer{
+		dataStore:           dataStore,
+		dockerClientFactory: dockerClientFactory,
+		shutdownCtx:         shutdownCtx,
+		subscriptions:       map[portainer.EndpointID]context.CancelFunc{},
+	}
+}
+
+// IsSupported returns true when the events of the environment are recorded
+func IsSupported(endpoint *portainer.Endpoint) bool {
+	// Edge environments are only reachable while their tunnel is open
+	return endpointutils.IsDockerEndpoint(endpoint) && !endpointutils.IsEdgeEndpoint(endpoint)
+}
+
+// Sync subscribes to the events of the supported environments(endpoints) and stops the subscriptions
+// of the environments that were removed
+func (consumer *Consumer) Sync() error {
+	endpoints, err := consumer.dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the environments: %w", err)
+	}
+
+	consumer.mu.Lock()
+	defer consumer.mu.Unlock()
+
+	supported := map[portainer.EndpointID]bool{}
+	for i := range endpoints {
+		if !IsSupported(&endpoints[i]) {
+			continue
+		}
+
+		endpointID := endpoints[i].ID
+		supported[endpointID] = true
+
+		if _, ok := consumer.subscriptions[endpointID]; ok {
+			continue
+		}
+
+		ctx, cancel := context.WithCancel(consumer.shutdownCtx)
+		consumer.subscriptions[endpointID] = cancel
+
+		go consumer.subscribe(ctx, endpointID)
+	}
+
+	for endpointID, cancel := range consumer.subscriptions {
+		if !supported[endpointID] {
+			cancel()
+			delete(consumer.subscriptions, endpointID)
+		}
+	}
+
+	return nil
+}
+
+// subscribe consumes the events of the environment until the context is cancelled, reconnecting when
+// the environment is unreachable
+func (consumer *Consumer) subscribe(ctx context.Context, endpointID portainer.EndpointID) {
+	for {
+		err := consumer.connect(ctx, endpointID)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Debug().Err(err).Int("endpoint_id", int(endpointID)).Msg("the Docker events subscription was interrupted, retrying")
+
+		select {
+		case <-time.After(retryDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (consumer *Consumer) connect(ctx context.Context, endpointID portainer.EndpointID) error {
+	endpoint, err := consumer.dataStore.Endpoint().Endpoint(endpointID)
+	if err != nil {
+		return err
+	}
+
+	cli, err := consumer.dockerClientFactory.CreateClient(endpoint, "", nil)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	return consumer.consume(ctx, cli, endpointID)
+}
+
+// consume records the events of the environment, resuming after the latest recorded one
+func (consumer *Consumer) consume(ctx context.Context, cli dockerClient, endpointID portainer.EndpointID) error {
+	last, err := consumer.latestEventTime(endpointID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	options := types.EventsOptions{}
+	if last > 0 {
+		options.Since = fmt.Sprintf("%d.%09d", last/int64(time.Second), last%int64(time.Second))
+	}
+
+	messages, errs := cli.Events(ctx, options)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	var batch []portainer.ActivityEvent
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		err := consumer.persist(endpointID, batch)
+		batch = nil
+
+		return err
+	}
+
+	for {
+		select {
+		case message := <-messages:
+			event, ok := normalize(endpointID, message)
+			// The events at the resumption time may already be recorded
+			if !ok || event.TimeNano <= last {
+				continue
+			}
+
+			last = event.TimeNano
+			batch = append(batch, event)
+
+			if len(batch) >= flushSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		case err := <-errs:
+			return errors.Join(err, flush())
+		case <-ctx.Done():
+			return flush()
+		}
+	}
+}
+
+func (consumer *Consumer) latestEventTime(endpointID portainer.EndpointID) (int64, error) {
+	events, err := consumer.dataStore.ActivityEvent().ReadAllByEndpointID(endpointID)
+	if err != nil {
+		return 0, fmt.Errorf("unable to retrieve the activity events: %w", err)
+	}
+
+	var last int64
+	for _, event := range events {
+		last = max(last, event.TimeNano)
+	}
+
+	return last, nil
+}
+
+// persist records a batch of events and removes the oldest events of the environment beyond the retention
+func (consumer *Consumer) persist(endpointID portainer.EndpointID, batch []portainer.ActivityEvent) error {
+	return consumer.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		// The environment may have been removed since the subscription started
+		if _, err := tx.Endpoint().Endpoint(endpointID); err != nil {
+			return err
+		}
+
+		for i := range batch {
+			if err := tx.ActivityEvent().Create(&batch[i]); err != nil {
+				return fmt.Errorf("unable to record the activity event: %w", err)
+			}
+		}
+
+		events, err := tx.ActivityEvent().ReadAllByEndpointID(endpointID)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve the activity events: %w", err)
+		}
+
+		if len(events) <= EventRetention {
+			return nil
+		}
+
+		SortEvents(events)
+
+		for _, event := range events[EventRetention:] {
+			if err := tx.ActivityEvent().Delete(event.ID); err != nil {
+				return fmt.Errorf("unable to remove the activity event: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// SortEvents sorts the events from the most recent to the oldest
+func SortEvents(events []portainer.ActivityEvent) {
+	slices.SortFunc(events, func(a, b portainer.ActivityEvent) int {
+		return cmp.Or(cmp.Compare(b.TimeNano, a.TimeNano), cmp.Compare(b.ID, a.ID))
+	})
+}
+
+// normalize converts a Docker event into an activity event, it returns false for the events that are
+// not recorded
+func normalize(endpointID portainer.EndpointID, message events.Message) (portainer.ActivityEvent, bool) {
+	// Some actions carry details, e.g. "health_status: healthy" or "exec_start: sh"
+	action, detail, _ := strings.Cut(string(message.Action), ":")
+	action = strings.TrimSpace(action)
+
+	// The exec events are emitted for each command run in a container, including the healthchecks
+	if action == "" || strings.HasPrefix(action, "exec_") {
+		return portainer.ActivityEvent{}, false
+	}
+
+	timeNano := message.TimeNano
+	if timeNano == 0 {
+		timeNano = message.Time * int64(time.Second)
+	}
+
+	var attributes map[string]string
+	for _, key := range trackedAttributes {
+		if value, ok := message.Actor.Attributes[key]; ok {
+			if attributes == nil {
+				attributes = map[string]string{}
+			}
+
+			attributes[key] = value
+		}
+	}
+
+	return portainer.ActivityEvent{
+		EndpointID:   endpointID,
+		Time:         timeNano / int64(time.Second),
+		TimeNano:     timeNano,
+		Type:         string(message.Type),
+		Action:       action,
+		ActionDetail: strings.TrimSpace(detail),
+		ActorID:      message.Actor.ID,
+		ActorName:    message.Actor.Attributes["name"],
+		Attributes:   attributes,
+	}, true
+}
@@ -0,0 +1,118 @@
+package activity
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDockerClient struct {
+	events  []events.Message
+	options types.EventsOptions
+}
+
+func (c *testDockerClient) Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	c.options = options
+
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+
+	go func() {
+		for _, m := range c.events {
+			messages <- m
+		}
+
+		errs <- io.EOF
+	}()
+
+	return messages, errs
+}
+
+func containerEvent(action events.Action, name string, timeNano int64) events.Message {
+	return events.Message{
+		Type:     events.ContainerEventType,
+		Action:   action,
+		Actor:    events.Actor{ID: name + "-id", Attributes: map[string]string{"name": name, "image": "nginx", "maintainer": "someone"}},
+		TimeNano: timeNano,
+	}
+}
+
+func TestConsume(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	endpoint := &portainer.Endpoint{ID: 1, Type: portainer.DockerEnvironment}
+	require.NoError(t, store.Endpoint().Create(endpoint))
+
+	consumer := NewConsumer(store, nil, context.Background())
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+
+	cli := &testDockerClient{events: []events.Message{
+		containerEvent(events.ActionStart, "web", start),
+		containerEvent("exec_start: sh -c healthcheck", "web", start+1),
+		containerEvent("health_status: healthy", "web", start+2),
+	}}
+
+	err := consumer.consume(context.Background(), cli, endpoint.ID)
+	require.ErrorIs(t, err, io.EOF)
+	assert.Empty(t, cli.options.Since)
+
+	recorded, err := store.ActivityEvent().ReadAllByEndpointID(endpoint.ID)
+	require.NoError(t, err)
+	require.Len(t, recorded, 2)
+
+	SortEvents(recorded)
+	assert.Equal(t, "health_status", recorded[0].Action)
+	assert.Equal(t, "healthy", recorded[0].ActionDetail)
+	assert.Equal(t, "web", recorded[0].ActorName)
+	assert.Equal(t, map[string]string{"image": "nginx"}, recorded[0].Attributes)
+	assert.Equal(t, "start", recorded[1].Action)
+	assert.Equal(t, start/int64(time.Second), recorded[1].Time)
+
+	// The subscription resumes after the latest recorded event
+	cli.events = append(cli.events, containerEvent(events.ActionDie, "web", start+3))
+
+	err = consumer.consume(context.Background(), cli, endpoint.ID)
+	require.ErrorIs(t, err, io.EOF)
+	assert.Equal(t, "1704067200.000000002", cli.options.Since)
+
+	recorded, err = store.ActivityEvent().ReadAllByEndpointID(endpoint.ID)
+	require.NoError(t, err)
+	assert.Len(t, recorded, 3)
+}
+
+func TestPersistRetention(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	endpoint := &portainer.Endpoint{ID: 1, Type: portainer.DockerEnvironment}
+	require.NoError(t, store.Endpoint().Create(endpoint))
+
+	consumer := NewConsumer(store, nil, context.Background())
+
+	batch := make([]portainer.ActivityEvent, EventRetention+10)
+	for i := range batch {
+		batch[i] = portainer.ActivityEvent{EndpointID: endpoint.ID, TimeNano: int64(i + 1), Action: "start"}
+	}
+
+	require.NoError(t, consumer.persist(endpoint.ID, batch))
+
+	recorded, err := store.ActivityEvent().ReadAllByEndpointID(endpoint.ID)
+	require.NoError(t, err)
+	require.Len(t, recorded, EventRetention)
+
+	SortEvents(recorded)
+	assert.Equal(t, int64(EventRetention+10), recorded[0].TimeNano)
+	assert.Equal(t, int64(11), recorded[EventRetention-1].TimeNano)
+
+	// The events of a removed environment are not recorded
+	require.Error(t, consumer.persist(2, []portainer.ActivityEvent{{EndpointID: 2, TimeNano: 1}}))
+}
@@ -0,0 +1,260 @@
+package amtpower
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/edge"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// EvaluationInterval is the interval at which the power schedules are evaluated
+	EvaluationInterval = time.Minute
+
+	// DeviceActionRetention is the number of actions kept in the history of a device
+	DeviceActionRetention = 50
+
+	// TimeOfDayLayout is the layout of the power on and off times of the power schedules
+	TimeOfDayLayout = "15:04"
+
+	ActionPowerOn  = "power on"
+	ActionPowerOff = "power off"
+)
+
+// ErrOpenAMTDisabled is returned when executing an action while OpenAMT is not enabled
+var ErrOpenAMTDisabled = errors.New("OpenAMT is not enabled")
+
+// Service executes actions on the AMT managed devices of edge groups, either on demand or following
+// the power schedules, and records them in the history of the devices
+type Service struct {
+	dataStore      dataservices.DataStore
+	openAMTService portainer.OpenAMTService
+
+	mu             sync.Mutex
+	lastEvaluation time.Time
+}
+
+// NewService returns a new instance of a service
+func NewService(dataStore dataservices.DataStore, openAMTService portainer.OpenAMTService) *Service {
+	return &Service{
+		dataStore:      dataStore,
+		openAMTService: openAMTService,
+		lastEvaluation: time.Now(),
+	}
+}
+
+// ExecuteAction executes an action on the AMT managed devices of the environments(endpoints) of edge groups.
+// The schedule or the user executing the action are recorded along with the outcome on each device
+func (service *Service) ExecuteAction(edgeGroupIDs []portainer.EdgeGroupID, action string, scheduleID portainer.OpenAMTPowerScheduleID, username string) ([]portainer.OpenAMTDeviceAction, error) {
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		return nil, err
+	}
+
+	if !settings.OpenAMTConfiguration.Enabled {
+		return nil, ErrOpenAMTDisabled
+	}
+
+	var endpoints []portainer.Endpoint
+	if err := service.dataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		endpoints, err = managedDeviceEndpoints(tx, edgeGroupIDs)
+
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	deviceGUIDs := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		deviceGUIDs = append(deviceGUIDs, endpoint.AMTDeviceGUID)
+	}
+
+	failures, err := service.openAMTService.ExecuteDeviceActions(settings.OpenAMTConfiguration, deviceGUIDs, action)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	deviceActions := make([]portainer.OpenAMTDeviceAction, 0, len(endpoints))
+
+	return deviceActions, service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		for _, endpoint := range endpoints {
+			deviceAction := portainer.OpenAMTDeviceAction{
+				EndpointID: endpoint.ID,
+				DeviceGUID: endpoint.AMTDeviceGUID,
+				Action:     action,
+				ScheduleID: scheduleID,
+				Username:   username,
+				Time:       now,
+			}
+
+			if err := failures[endpoint.AMTDeviceGUID]; err != nil {
+				deviceAction.Error = err.Error()
+			}
+
+			if err := RecordDeviceAction(tx, &deviceAction); err != nil {
+				return err
+			}
+
+			deviceActions = append(deviceActions, deviceAction)
+		}
+
+		return nil
+	})
+}
+
+// EvaluateSchedules executes the power on and off actions of the enabled power schedules whose time of the day
+// was reached since the previous evaluation
+func (service *Service) EvaluateSchedules() error {
+	service.mu.Lock()
+	from, now := service.lastEvaluation, time.Now()
+	service.lastEvaluation = now
+	service.mu.Unlock()
+
+	schedules, err := service.dataStore.OpenAMTPowerSchedule().ReadAll()
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the OpenAMT power schedules: %w", err)
+	}
+
+	for _, schedule := range schedules {
+		if !schedule.Enabled {
+			continue
+		}
+
+		for action, timeOfDay := range map[string]string{ActionPowerOn: schedule.PowerOnTime, ActionPowerOff: schedule.PowerOffTime} {
+			due, err := isDue(timeOfDay, schedule.TimeZone, from, now)
+			if err != nil {
+				log.Warn().Err(err).Int("schedule_id", int(schedule.ID)).Msg("invalid OpenAMT power schedule")
+
+				continue
+			}
+
+			if !due {
+				continue
+			}
+
+			if _, err := service.ExecuteAction(schedule.EdgeGroupIDs, action, schedule.ID, ""); err != nil {
+				log.Warn().Err(err).Int("schedule_id", int(schedule.ID)).Str("action", action).Msg("unable to execute the action of the OpenAMT power schedule")
+			}
+		}
+	}
+
+	return nil
+}
+
+// RecordDeviceAction saves an action executed on a device and removes the oldest actions of the device
+// beyond the retention
+func RecordDeviceAction(tx dataservices.DataStoreTx, deviceAction *portainer.OpenAMTDeviceAction) error {
+	if err := tx.OpenAMTDeviceAction().Create(deviceAction); err != nil {
+		return err
+	}
+
+	deviceActions, err := tx.OpenAMTDeviceAction().ReadAllByEndpointID(deviceAction.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	if len(deviceActions) <= DeviceActionRetention {
+		return nil
+	}
+
+	SortDeviceActions(deviceActions)
+
+	for _, previous := range deviceActions[DeviceActionRetention:] {
+		if err := tx.OpenAMTDeviceAction().Delete(previous.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SortDeviceActions sorts the device actions from the most recent to the oldest
+func SortDeviceActions(deviceActions []portainer.OpenAMTDeviceAction) {
+	slices.SortFunc(deviceActions, func(a, b portainer.OpenAMTDeviceAction) int {
+		return cmp.Or(cmp.Compare(b.Time, a.Time), cmp.Compare(b.ID, a.ID))
+	})
+}
+
+// ValidateSchedule returns an error when the times of the day or the time zone of a power schedule are invalid
+func ValidateSchedule(powerOnTime, powerOffTime, timeZone string) error {
+	if powerOnTime == "" && powerOffTime == "" {
+		return errors.New("a power on or power off time must be provided")
+	}
+
+	for _, timeOfDay := range []string{powerOnTime, powerOffTime} {
+		if timeOfDay == "" {
+			continue
+		}
+
+		if _, err := time.Parse(TimeOfDayLayout, timeOfDay); err != nil {
+			return fmt.Errorf("invalid time of the day %q, the HH:MM format is expected", timeOfDay)
+		}
+	}
+
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+	}
+
+	return nil
+}
+
+// managedDeviceEndpoints returns the environments(endpoints) of edge groups which have an AMT managed device
+func managedDeviceEndpoints(tx dataservices.DataStoreTx, edgeGroupIDs []portainer.EdgeGroupID) ([]portainer.Endpoint, error) {
+	endpointIDs, err := edge.GetEndpointsFromEdgeGroups(edgeGroupIDs, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(endpointIDs)
+
+	var endpoints []portainer.Endpoint
+	for _, endpointID := range slices.Compact(endpointIDs) {
+		endpoint, err := tx.Endpoint().Endpoint(endpointID)
+		if err != nil {
+			return nil, err
+		}
+
+		if endpoint.AMTDeviceGUID != "" {
+			endpoints = append(endpoints, *endpoint)
+		}
+	}
+
+	return endpoints, nil
+}
+
+// isDue returns whether a time of the day, in the HH:MM format, was reached after from and until now
+func isDue(timeOfDay, timeZone string, from, now time.Time) (bool, error) {
+	if timeOfDay == "" {
+		return false, nil
+	}
+
+	clock, err := time.Parse(TimeOfDayLayout, timeOfDay)
+	if err != nil {
+		return false, err
+	}
+
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return false, err
+	}
+
+	// The evaluations are close enough for the interval to span two days at most
+	for _, day := range []time.Time{from.In(location), now.In(location)} {
+		occurrence := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, location)
+
+		if occurrence.After(from) && !occurrence.After(now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
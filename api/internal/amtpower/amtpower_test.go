@@ -0,0 +1,89 @@
+package amtpower
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingOpenAMTService struct {
+	portainer.OpenAMTService
+	devices []string
+}
+
+func (s *recordingOpenAMTService) ExecuteDeviceActions(configuration portainer.OpenAMTConfiguration, deviceGUIDs []string, action string) (map[string]error, error) {
+	s.devices = append(s.devices, deviceGUIDs...)
+
+	return map[string]error{"device-2": errors.New("device unreachable")}, nil
+}
+
+func TestExecuteAction(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	settings, err := store.Settings().Settings()
+	require.NoError(t, err)
+	settings.OpenAMTConfiguration.Enabled = true
+	require.NoError(t, store.Settings().UpdateSettings(settings))
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1, AMTDeviceGUID: "device-1"}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 2, AMTDeviceGUID: "device-2"}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 3}))
+	require.NoError(t, store.EdgeGroup().Create(&portainer.EdgeGroup{ID: 1, Endpoints: []portainer.EndpointID{1, 2, 3}}))
+	require.NoError(t, store.EdgeGroup().Create(&portainer.EdgeGroup{ID: 2, Endpoints: []portainer.EndpointID{1}}))
+
+	openAMTService := &recordingOpenAMTService{}
+	service := NewService(store, openAMTService)
+
+	deviceActions, err := service.ExecuteAction([]portainer.EdgeGroupID{1, 2}, ActionPowerOn, 4, "")
+	require.NoError(t, err)
+
+	// The environments without an AMT managed device are skipped and the devices are only sent the action once
+	assert.ElementsMatch(t, []string{"device-1", "device-2"}, openAMTService.devices)
+	require.Len(t, deviceActions, 2)
+
+	history, err := store.OpenAMTDeviceAction().ReadAllByEndpointID(2)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, portainer.OpenAMTPowerScheduleID(4), history[0].ScheduleID)
+	assert.Equal(t, "device unreachable", history[0].Error)
+}
+
+func TestIsDue(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	from := time.Date(2024, 3, 1, 6, 59, 30, 0, paris)
+
+	due, err := isDue("07:00", "Europe/Paris", from, from.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, due)
+
+	// The time is evaluated in the time zone of the schedule
+	due, err = isDue("07:00", "", from, from.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, due)
+
+	// The interval spans midnight
+	from = time.Date(2024, 3, 1, 23, 59, 30, 0, time.UTC)
+	due, err = isDue("00:00", "", from, from.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, due)
+
+	due, err = isDue("", "", from, from.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, due)
+}
+
+func TestValidateSchedule(t *testing.T) {
+	assert.NoError(t, ValidateSchedule("07:00", "20:00", "Europe/Paris"))
+	assert.NoError(t, ValidateSchedule("", "20:00", ""))
+	assert.Error(t, ValidateSchedule("", "", ""))
+	assert.Error(t, ValidateSchedule("7am", "", ""))
+	assert.Error(t, ValidateSchedule("07:00", "", "Mars/Olympus"))
+}
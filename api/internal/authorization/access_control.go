@@ -1,6 +1,8 @@
 package authorization
 
 import (
+	"fmt"
+	"slices"
 	"strconv"
 
 	portainer "github.com/portainer/portainer/api"
@@ -106,6 +108,11 @@ func NewRestrictedResourceControl(resourceIdentifier string, resourceType portai
 	}
 }
 
+// KubernetesNamespaceResourceControlID returns the identifier of the resource control of a namespace of an environment
+func KubernetesNamespaceResourceControlID(endpointID portainer.EndpointID, namespace string) string {
+	return fmt.Sprintf("%d_%s", endpointID, namespace)
+}
+
 // DecorateStacks will iterate through a list of stacks, check for an associated resource control for each
 // stack and decorate the stack element if a resource control is found.
 func DecorateStacks(stacks []portainer.Stack, resourceControls []portainer.ResourceControl) []portainer.Stack {
@@ -152,7 +159,7 @@ func FilterAuthorizedCustomTemplates(customTemplates []portainer.CustomTemplate,
 	authorizedTemplates := make([]portainer.CustomTemplate, 0)
 
 	for _, customTemplate := range customTemplates {
-		if customTemplate.CreatedByUserID == user.ID || (customTemplate.ResourceControl != nil && UserCanAccessResource(user.ID, userTeamIDs, customTemplate.ResourceControl)) {
+		if UserCanAccessCustomTemplate(&customTemplate, user.ID, userTeamIDs) {
 			authorizedTemplates = append(authorizedTemplates, customTemplate)
 		}
 	}
@@ -160,6 +167,22 @@ func FilterAuthorizedCustomTemplates(customTemplates []portainer.CustomTemplate,
 	return authorizedTemplates
 }
 
+// UserCanAccessCustomTemplate returns true when a custom template is approved, was created by the user, is shared
+// with the user or one of the user's teams, or when its resource control grants access to the user.
+func UserCanAccessCustomTemplate(customTemplate *portainer.CustomTemplate, userID portainer.UserID, userTeamIDs []portainer.TeamID) bool {
+	if customTemplate.Approved || customTemplate.CreatedByUserID == userID || slices.Contains(customTemplate.SharedUserIDs, userID) {
+		return true
+	}
+
+	for _, teamID := range userTeamIDs {
+		if slices.Contains(customTemplate.SharedTeamIDs, teamID) {
+			return true
+		}
+	}
+
+	return customTemplate.ResourceControl != nil && UserCanAccessResource(userID, userTeamIDs, customTemplate.ResourceControl)
+}
+
 // UserCanAccessResource will valid that a user has permissions defined in the specified resource control
 // based on its identifier and the team(s) he is part of.
 func UserCanAccessResource(userID portainer.UserID, userTeamIDs []portainer.TeamID, resourceControl *portainer.ResourceControl) bool {
@@ -1,6 +1,11 @@
 package authorization
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/kubernetes/cli"
@@ -157,6 +162,72 @@ func DefaultEndpointAuthorizationsForEndpointAdministratorRole() portainer.Autho
 	}
 }
 
+// ValidateDockerOperations ensures that every authorization is a Docker operation that can be granted on an environment(endpoint).
+func ValidateDockerOperations(operations []portainer.Authorization) error {
+	dockerOperations := DefaultEndpointAuthorizationsForEndpointAdministratorRole()
+
+	for _, operation := range operations {
+		if !strings.HasPrefix(string(operation), "Docker") || !dockerOperations[operation] {
+			return fmt.Errorf("invalid Docker operation: %s", operation)
+		}
+	}
+
+	return nil
+}
+
+// ErrUnknownRole is returned when an access policy references a role which does not exist
+var ErrUnknownRole = errors.New("unknown role")
+
+// RoleAuthorizations returns every authorization that can be granted by a role on an environment(endpoint), they
+// are the building blocks of the custom roles
+func RoleAuthorizations() portainer.Authorizations {
+	authorizations := DefaultEndpointAuthorizationsForEndpointAdministratorRole()
+	maps.Copy(authorizations, DefaultEndpointAuthorizationsForHelpDeskRole(true))
+	maps.Copy(authorizations, DefaultEndpointAuthorizationsForStandardUserRole(true))
+	maps.Copy(authorizations, DefaultEndpointAuthorizationsForReadOnlyUserRole(true))
+
+	return authorizations
+}
+
+// ValidateRoleAuthorizations ensures that every authorization of a custom role can be granted on an environment(endpoint).
+func ValidateRoleAuthorizations(authorizations portainer.Authorizations) error {
+	available := RoleAuthorizations()
+
+	for authorization := range authorizations {
+		if !available[authorization] {
+			return fmt.Errorf("invalid authorization: %s", authorization)
+		}
+	}
+
+	return nil
+}
+
+// ValidateAccessPolicyRoles ensures that the roles of the access policies exist, the policies without role are valid
+func ValidateAccessPolicyRoles(tx dataservices.DataStoreTx, userPolicies portainer.UserAccessPolicies, teamPolicies portainer.TeamAccessPolicies) error {
+	roleIDs := make([]portainer.RoleID, 0, len(userPolicies)+len(teamPolicies))
+	for _, policy := range userPolicies {
+		roleIDs = append(roleIDs, policy.RoleID)
+	}
+
+	for _, policy := range teamPolicies {
+		roleIDs = append(roleIDs, policy.RoleID)
+	}
+
+	for _, roleID := range roleIDs {
+		if roleID == 0 {
+			continue
+		}
+
+		if _, err := tx.Role().Read(roleID); tx.IsErrObjectNotFound(err) {
+			return fmt.Errorf("%w: %d", ErrUnknownRole, roleID)
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // DefaultEndpointAuthorizationsForHelpDeskRole returns the default environment(endpoint) authorizations
 // associated to the helpdesk role.
 func DefaultEndpointAuthorizationsForHelpDeskRole(volumeBrowsingAuthorizations bool) portainer.Authorizations {
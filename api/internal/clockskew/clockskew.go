@@ -0,0 +1,37 @@
+package clockskew
+
+import (
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Threshold is the clock skew above which an environment(endpoint) is flagged, beyond it the validation of the
+// agent signatures and the schedules of the edge jobs become unreliable
+const Threshold = 30 * time.Second
+
+// Update records the clock skew measured for the environment(endpoint), a warning is logged when the skew
+// starts exceeding the threshold and when it is back under it
+func Update(endpoint *portainer.Endpoint, skew time.Duration) {
+	detected := skew.Abs() > Threshold
+
+	if detected && !endpoint.ClockSkewDetected {
+		log.Warn().
+			Int("endpoint_id", int(endpoint.ID)).
+			Str("endpoint", endpoint.Name).
+			Stringer("skew", skew.Round(time.Second)).
+			Stringer("threshold", Threshold).
+			Msg("the clock of the environment is skewed from the clock of the server")
+	} else if !detected && endpoint.ClockSkewDetected {
+		log.Info().
+			Int("endpoint_id", int(endpoint.ID)).
+			Str("endpoint", endpoint.Name).
+			Stringer("skew", skew.Round(time.Second)).
+			Msg("the clock of the environment is back in sync with the clock of the server")
+	}
+
+	endpoint.ClockSkew = int64(skew.Round(time.Second) / time.Second)
+	endpoint.ClockSkewDetected = detected
+}
@@ -0,0 +1,26 @@
+package clockskew
+
+import (
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdate(t *testing.T) {
+	endpoint := &portainer.Endpoint{ID: 1, Name: "edge"}
+
+	Update(endpoint, 2*time.Second)
+	assert.Equal(t, int64(2), endpoint.ClockSkew)
+	assert.False(t, endpoint.ClockSkewDetected)
+
+	Update(endpoint, -90*time.Second-400*time.Millisecond)
+	assert.Equal(t, int64(-90), endpoint.ClockSkew)
+	assert.True(t, endpoint.ClockSkewDetected)
+
+	Update(endpoint, Threshold)
+	assert.Equal(t, int64(30), endpoint.ClockSkew)
+	assert.False(t, endpoint.ClockSkewDetected)
+}
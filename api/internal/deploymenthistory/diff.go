@@ -0,0 +1,118 @@
+package deploymenthistory
+
+import (
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+// ArtifactDiff represents the changes made to a single artifact between two deployments
+type ArtifactDiff struct {
+	// Name of the artifact
+	Name string `json:"Name" example:"docker-compose.yml"`
+	// Status of the artifact, one of added, removed, modified or unchanged
+	Status string `json:"Status" example:"modified"`
+	// Line based diff of the artifact, each line is prefixed by "+", "-" or " "
+	Diff string `json:"Diff"`
+}
+
+const (
+	ArtifactAdded     = "added"
+	ArtifactRemoved   = "removed"
+	ArtifactModified  = "modified"
+	ArtifactUnchanged = "unchanged"
+)
+
+// Diff returns the differences between the artifacts of two deployments
+func Diff(from, to *portainer.Deployment) []ArtifactDiff {
+	fromArtifacts := make(map[string]string, len(from.Artifacts))
+	for _, artifact := range from.Artifacts {
+		fromArtifacts[artifact.Name] = artifact.Content
+	}
+
+	diffs := make([]ArtifactDiff, 0, len(from.Artifacts)+len(to.Artifacts))
+
+	seen := make(map[string]bool, len(to.Artifacts))
+	for _, artifact := range to.Artifacts {
+		seen[artifact.Name] = true
+
+		previous, ok := fromArtifacts[artifact.Name]
+
+		status := ArtifactModified
+		switch {
+		case !ok:
+			status = ArtifactAdded
+		case previous == artifact.Content:
+			status = ArtifactUnchanged
+		}
+
+		diffs = append(diffs, ArtifactDiff{
+			Name:   artifact.Name,
+			Status: status,
+			Diff:   diffLines(previous, artifact.Content),
+		})
+	}
+
+	for _, artifact := range from.Artifacts {
+		if seen[artifact.Name] {
+			continue
+		}
+
+		diffs = append(diffs, ArtifactDiff{
+			Name:   artifact.Name,
+			Status: ArtifactRemoved,
+			Diff:   diffLines(artifact.Content, ""),
+		})
+	}
+
+	return diffs
+}
+
+// diffLines computes a line based diff of a and b using the longest common subsequence
+func diffLines(a, b string) string {
+	aLines, bLines := splitLines(a), splitLines(b)
+
+	// lcs[i][j] holds the length of the longest common subsequence of aLines[i:] and bLines[j:]
+	lcs := make([][]int, len(aLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bLines)+1)
+	}
+
+	for i := len(aLines) - 1; i >= 0; i-- {
+		for j := len(bLines) - 1; j >= 0; j-- {
+			if aLines[i] == bLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+
+	i, j := 0, 0
+	for i < len(aLines) || j < len(bLines) {
+		switch {
+		case i < len(aLines) && j < len(bLines) && aLines[i] == bLines[j]:
+			sb.WriteString(" " + aLines[i] + "\n")
+			i++
+			j++
+		case i < len(aLines) && (j == len(bLines) || lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString("-" + aLines[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+" + bLines[j] + "\n")
+			j++
+		}
+	}
+
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
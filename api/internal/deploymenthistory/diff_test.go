@@ -0,0 +1,77 @@
+package deploymenthistory
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "identical", a: "a\nb\n", b: "a\nb\n", want: " a\n b\n"},
+		{name: "added line", a: "a\nc\n", b: "a\nb\nc\n", want: " a\n+b\n c\n"},
+		{name: "removed line", a: "a\nb\nc", b: "a\nc", want: " a\n-b\n c\n"},
+		{name: "changed line", a: "image: nginx:1.25\n", b: "image: nginx:1.27\n", want: "-image: nginx:1.25\n+image: nginx:1.27\n"},
+		{name: "from empty", a: "", b: "a\n", want: "+a\n"},
+		{name: "to empty", a: "a\n", b: "", want: "-a\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffLines(tt.a, tt.b); got != tt.want {
+				t.Errorf("diffLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	from := &portainer.Deployment{Artifacts: []portainer.DeploymentArtifact{
+		{Name: "docker-compose.yml", Content: "services:\n  web:\n    image: nginx:1.25\n"},
+		{Name: "unchanged.yml", Content: "x\n"},
+		{Name: "removed.yml", Content: "y\n"},
+	}}
+
+	to := &portainer.Deployment{Artifacts: []portainer.DeploymentArtifact{
+		{Name: "docker-compose.yml", Content: "services:\n  web:\n    image: nginx:1.27\n"},
+		{Name: "unchanged.yml", Content: "x\n"},
+		{Name: "added.yml", Content: "z\n"},
+	}}
+
+	want := map[string]string{
+		"docker-compose.yml": ArtifactModified,
+		"unchanged.yml":      ArtifactUnchanged,
+		"added.yml":          ArtifactAdded,
+		"removed.yml":        ArtifactRemoved,
+	}
+
+	diffs := Diff(from, to)
+	if len(diffs) != len(want) {
+		t.Fatalf("expected %d diffs, got %d", len(want), len(diffs))
+	}
+
+	for _, d := range diffs {
+		if want[d.Name] != d.Status {
+			t.Errorf("artifact %s: expected status %s, got %s", d.Name, want[d.Name], d.Status)
+		}
+	}
+}
+
+func TestStackIDFromArtifacts(t *testing.T) {
+	artifacts := []portainer.DeploymentArtifact{
+		{Name: "a.yml", Content: "kind: Service\n"},
+		{Name: "b.yml", Content: "metadata:\n  labels:\n    io.portainer.kubernetes.application.stackid: \"12\"\n"},
+	}
+
+	if id := stackIDFromArtifacts(artifacts); id != 12 {
+		t.Errorf("expected stack id 12, got %d", id)
+	}
+
+	if id := stackIDFromArtifacts(artifacts[:1]); id != 0 {
+		t.Errorf("expected stack id 0, got %d", id)
+	}
+}
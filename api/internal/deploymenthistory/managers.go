@@ -0,0 +1,129 @@
+package deploymenthistory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/rs/zerolog/log"
+)
+
+var stackIDLabelRegexp = regexp.MustCompile(`io\.portainer\.kubernetes\.application\.stackid:\s*["']?(\d+)["']?`)
+
+// composeStackManager records every compose stack brought up through the wrapped manager
+type composeStackManager struct {
+	portainer.ComposeStackManager
+	service *Service
+}
+
+// NewComposeStackManager wraps a compose stack manager so that its deployments are recorded
+func NewComposeStackManager(manager portainer.ComposeStackManager, service *Service) portainer.ComposeStackManager {
+	return &composeStackManager{ComposeStackManager: manager, service: service}
+}
+
+func (manager *composeStackManager) Up(ctx context.Context, stack *portainer.Stack, endpoint *portainer.Endpoint, options portainer.ComposeUpOptions) error {
+	startedAt := time.Now()
+	deployment := stackDeployment(stack)
+
+	err := manager.service.validate(ValidationRequest{Deployment: deployment, Endpoint: endpoint, Stack: stack})
+	if err == nil {
+		err = manager.ComposeStackManager.Up(ctx, stack, endpoint, options)
+	}
+
+	manager.service.Record(deployment, startedAt, err)
+
+	return err
+}
+
+// swarmStackManager records every swarm stack deployed through the wrapped manager
+type swarmStackManager struct {
+	portainer.SwarmStackManager
+	service *Service
+}
+
+// NewSwarmStackManager wraps a swarm stack manager so that its deployments are recorded
+func NewSwarmStackManager(manager portainer.SwarmStackManager, service *Service) portainer.SwarmStackManager {
+	return &swarmStackManager{SwarmStackManager: manager, service: service}
+}
+
+func (manager *swarmStackManager) Deploy(stack *portainer.Stack, prune bool, pullImage bool, endpoint *portainer.Endpoint) error {
+	startedAt := time.Now()
+	deployment := stackDeployment(stack)
+
+	err := manager.service.validate(ValidationRequest{Deployment: deployment, Endpoint: endpoint, Stack: stack})
+	if err == nil {
+		err = manager.SwarmStackManager.Deploy(stack, prune, pullImage, endpoint)
+	}
+
+	manager.service.Record(deployment, startedAt, err)
+
+	return err
+}
+
+// kubernetesDeployer records every manifest applied through the wrapped deployer
+type kubernetesDeployer struct {
+	portainer.KubernetesDeployer
+	service *Service
+}
+
+// NewKubernetesDeployer wraps a Kubernetes deployer so that its deployments are recorded
+func NewKubernetesDeployer(deployer portainer.KubernetesDeployer, service *Service) portainer.KubernetesDeployer {
+	return &kubernetesDeployer{KubernetesDeployer: deployer, service: service}
+}
+
+func (deployer *kubernetesDeployer) Deploy(userID portainer.UserID, endpoint *portainer.Endpoint, manifestFiles []string, namespace string) (string, error) {
+	// the manifests are usually temporary files, read them before they get removed
+	artifacts := make([]portainer.DeploymentArtifact, 0, len(manifestFiles))
+	for _, manifestFile := range manifestFiles {
+		content, err := os.ReadFile(strings.TrimSpace(manifestFile))
+		if err != nil {
+			log.Debug().Err(err).Str("file", manifestFile).Msg("unable to read deployment artifact")
+
+			continue
+		}
+
+		artifacts = append(artifacts, portainer.DeploymentArtifact{Name: filepath.Base(manifestFile), Content: string(content)})
+	}
+
+	deployment := &portainer.Deployment{
+		Type:       portainer.KubernetesManifestDeployment,
+		EndpointID: endpoint.ID,
+		StackID:    stackIDFromArtifacts(artifacts),
+		Artifacts:  artifacts,
+	}
+
+	if user, err := deployer.service.dataStore.User().Read(userID); err == nil {
+		deployment.Initiator = user.Username
+	}
+
+	startedAt := time.Now()
+
+	var output string
+	err := deployer.service.validate(ValidationRequest{Deployment: deployment, Endpoint: endpoint, Namespace: namespace})
+	if err == nil {
+		output, err = deployer.KubernetesDeployer.Deploy(userID, endpoint, manifestFiles, namespace)
+	}
+
+	deployer.service.Record(deployment, startedAt, err)
+
+	return output, err
+}
+
+// stackIDFromArtifacts retrieves the stack identifier from the labels Portainer adds to the manifests
+func stackIDFromArtifacts(artifacts []portainer.DeploymentArtifact) portainer.StackID {
+	for _, artifact := range artifacts {
+		if match := stackIDLabelRegexp.FindStringSubmatch(artifact.Content); match != nil {
+			if id, err := strconv.Atoi(match[1]); err == nil {
+				return portainer.StackID(id)
+			}
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,62 @@
+package deploymenthistory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testComposeStackManager struct {
+	portainer.ComposeStackManager
+	ups int
+}
+
+func (manager *testComposeStackManager) Up(ctx context.Context, stack *portainer.Stack, endpoint *portainer.Endpoint, options portainer.ComposeUpOptions) error {
+	manager.ups++
+
+	return nil
+}
+
+type testValidator struct {
+	warnings []string
+	err      error
+}
+
+func (validator testValidator) Validate(request ValidationRequest) ([]string, error) {
+	return validator.warnings, validator.err
+}
+
+func TestComposeStackManagerValidation(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	service := NewService(store)
+	composeManager := &testComposeStackManager{}
+	manager := NewComposeStackManager(composeManager, service)
+
+	stack := &portainer.Stack{ID: 1, EndpointID: 1, Type: portainer.DockerComposeStack}
+	endpoint := &portainer.Endpoint{ID: 1}
+
+	service.SetValidator(testValidator{warnings: []string{"opa: image tag latest"}})
+	require.NoError(t, manager.Up(context.Background(), stack, endpoint, portainer.ComposeUpOptions{}))
+
+	service.SetValidator(testValidator{err: errors.New("deployment rejected")})
+	require.Error(t, manager.Up(context.Background(), stack, endpoint, portainer.ComposeUpOptions{}))
+
+	// The rejected deployment is recorded but never made
+	assert.Equal(t, 1, composeManager.ups)
+
+	deployments, err := store.Deployment().ReadAll()
+	require.NoError(t, err)
+	require.Len(t, deployments, 2)
+
+	assert.Equal(t, portainer.DeploymentStatusSuccess, deployments[0].Status)
+	assert.Equal(t, []string{"opa: image tag latest"}, deployments[0].Annotations)
+	assert.Equal(t, portainer.DeploymentStatusFailed, deployments[1].Status)
+	assert.Equal(t, "deployment rejected", deployments[1].Error)
+}
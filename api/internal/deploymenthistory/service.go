@@ -0,0 +1,209 @@
+package deploymenthistory
+
+import (
+	"cmp"
+	"os"
+	"slices"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/stacks/stackutils"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MaxDeploymentsPerTarget is the number of deployments kept for a single
+// stack, edge stack or environment before the oldest ones are pruned
+const MaxDeploymentsPerTarget = 50
+
+// ValidationRequest describes a deployment about to be made
+type ValidationRequest struct {
+	Deployment *portainer.Deployment
+	Endpoint   *portainer.Endpoint
+	// Stack deployed, nil for the Kubernetes manifests deployed outside of a stack
+	Stack *portainer.Stack
+	// Namespace targeted by the Kubernetes manifests
+	Namespace string
+}
+
+// Validator approves the deployments before they are made. A deployment is blocked when an error is
+// returned, the warnings are recorded with the deployment otherwise
+type Validator interface {
+	Validate(request ValidationRequest) (warnings []string, err error)
+}
+
+// Service records the deployments made by Portainer
+type Service struct {
+	dataStore dataservices.DataStore
+	validator Validator
+}
+
+// NewService returns a new instance of a service
+func NewService(dataStore dataservices.DataStore) *Service {
+	return &Service{
+		dataStore: dataStore,
+	}
+}
+
+// SetValidator sets the validator approving the deployments, it must be called before any deployment
+func (service *Service) SetValidator(validator Validator) {
+	service.validator = validator
+}
+
+// validate runs the validator on a deployment about to be made and annotates it with the warnings
+func (service *Service) validate(request ValidationRequest) error {
+	if service.validator == nil {
+		return nil
+	}
+
+	warnings, err := service.validator.Validate(request)
+	request.Deployment.Annotations = append(request.Deployment.Annotations, warnings...)
+
+	return err
+}
+
+// Record completes the deployment with its duration and result and persists it.
+// Recording failures are logged and never interrupt the deployment itself.
+func (service *Service) Record(deployment *portainer.Deployment, startedAt time.Time, deployErr error) {
+	deployment.StartedAt = startedAt.Unix()
+	deployment.Duration = time.Since(startedAt).Milliseconds()
+	deployment.Status = portainer.DeploymentStatusSuccess
+
+	if deployErr != nil {
+		deployment.Status = portainer.DeploymentStatusFailed
+		deployment.Error = deployErr.Error()
+	}
+
+	if deployment.Artifacts == nil {
+		deployment.Artifacts = []portainer.DeploymentArtifact{}
+	}
+
+	if err := service.dataStore.Deployment().Create(deployment); err != nil {
+		log.Warn().Err(err).Msg("unable to record the deployment")
+
+		return
+	}
+
+	if err := service.prune(deployment); err != nil {
+		log.Warn().Err(err).Msg("unable to prune the deployment history")
+	}
+}
+
+// stackDeployment describes a deployment of a regular stack
+func stackDeployment(stack *portainer.Stack) *portainer.Deployment {
+	deploymentType := portainer.StackDeployment
+	if stack.FromAppTemplate {
+		deploymentType = portainer.TemplateDeployment
+	}
+
+	return &portainer.Deployment{
+		Type:       deploymentType,
+		EndpointID: stack.EndpointID,
+		StackID:    stack.ID,
+		Initiator:  cmp.Or(stack.UpdatedBy, stack.CreatedBy),
+		Artifacts:  readArtifacts(stack.ProjectPath, stackutils.GetStackFilePaths(stack, false)),
+	}
+}
+
+// FailLatestStackDeployment marks the latest deployment of a stack as failed, it is used when
+// the deployment itself succeeded but the services of the stack never became healthy
+func (service *Service) FailLatestStackDeployment(stackID portainer.StackID, reason string) {
+	deployments, err := service.dataStore.Deployment().ReadAllByFilter(func(d portainer.Deployment) bool {
+		return d.StackID == stackID
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("unable to retrieve the deployment history")
+
+		return
+	}
+
+	if len(deployments) == 0 {
+		return
+	}
+
+	latest := slices.MaxFunc(deployments, func(a, b portainer.Deployment) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	latest.Status = portainer.DeploymentStatusFailed
+	latest.Error = reason
+
+	if err := service.dataStore.Deployment().Update(latest.ID, &latest); err != nil {
+		log.Warn().Err(err).Msg("unable to update the deployment")
+	}
+}
+
+// RecordEdgeStack records a new version of an edge stack
+func (service *Service) RecordEdgeStack(edgeStack *portainer.EdgeStack, initiator string) {
+	entryPoint := edgeStack.EntryPoint
+	if edgeStack.DeploymentType == portainer.EdgeStackDeploymentKubernetes {
+		entryPoint = edgeStack.ManifestPath
+	}
+
+	service.Record(&portainer.Deployment{
+		Type:        portainer.EdgeStackDeployment,
+		EdgeStackID: edgeStack.ID,
+		Initiator:   initiator,
+		Artifacts:   readArtifacts(edgeStack.ProjectPath, []string{entryPoint}),
+	}, time.Now(), nil)
+}
+
+// prune removes the oldest deployments sharing the same target as the given deployment
+func (service *Service) prune(deployment *portainer.Deployment) error {
+	deployments, err := service.dataStore.Deployment().ReadAllByFilter(func(d portainer.Deployment) bool {
+		return sameTarget(d, *deployment)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(deployments) <= MaxDeploymentsPerTarget {
+		return nil
+	}
+
+	slices.SortFunc(deployments, func(a, b portainer.Deployment) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	for _, d := range deployments[:len(deployments)-MaxDeploymentsPerTarget] {
+		if err := service.dataStore.Deployment().Delete(d.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sameTarget(a, b portainer.Deployment) bool {
+	switch {
+	case b.StackID != 0:
+		return a.StackID == b.StackID
+	case b.EdgeStackID != 0:
+		return a.EdgeStackID == b.EdgeStackID
+	}
+
+	return a.StackID == 0 && a.EdgeStackID == 0 && a.EndpointID == b.EndpointID
+}
+
+func readArtifacts(projectPath string, fileNames []string) []portainer.DeploymentArtifact {
+	artifacts := make([]portainer.DeploymentArtifact, 0, len(fileNames))
+
+	for _, name := range fileNames {
+		if name == "" {
+			continue
+		}
+
+		content, err := os.ReadFile(filesystem.JoinPaths(projectPath, name))
+		if err != nil {
+			log.Debug().Err(err).Str("file", name).Msg("unable to read deployment artifact")
+
+			continue
+		}
+
+		artifacts = append(artifacts, portainer.DeploymentArtifact{Name: name, Content: string(content)})
+	}
+
+	return artifacts
+}
@@ -0,0 +1,229 @@
+package deploymentvalidation
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/api/internal/deploymenthistory"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	// SignatureHeader holds the HMAC-SHA256 of the body of the requests, keyed with the secret of the webhook
+	SignatureHeader = "X-Portainer-Signature"
+
+	// DefaultTimeout is the time waited for a response when the webhook does not define one
+	DefaultTimeout = 10 * time.Second
+
+	maxResponseSize = 1 << 20
+)
+
+// ErrDeploymentRejected is returned when a validation webhook rejects a deployment
+var ErrDeploymentRejected = errors.New("deployment rejected")
+
+// Request is the body POSTed to the validation webhooks
+type Request struct {
+	// Type of the deployment, one of compose, swarm or kubernetes
+	Type      string   `json:"type"`
+	Endpoint  Endpoint `json:"endpoint"`
+	Stack     *Stack   `json:"stack,omitempty"`
+	Namespace string   `json:"namespace,omitempty"`
+	// Username of the user who initiated the deployment
+	Initiator string `json:"initiator"`
+	// Rendered files about to be deployed
+	Files []File `json:"files"`
+}
+
+// Endpoint describes the environment(endpoint) targeted by a deployment
+type Endpoint struct {
+	ID      portainer.EndpointID      `json:"id"`
+	Name    string                    `json:"name"`
+	GroupID portainer.EndpointGroupID `json:"groupId"`
+}
+
+// Stack describes the stack deployed
+type Stack struct {
+	ID   portainer.StackID `json:"id"`
+	Name string            `json:"name"`
+}
+
+// File is a rendered file about to be deployed
+type File struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// Response is the body expected from the validation webhooks
+type Response struct {
+	// Whether the deployment can proceed
+	Allowed bool `json:"allowed"`
+	// Reason of the rejection
+	Message string `json:"message"`
+	// Warnings recorded with the deployment
+	Warnings []string `json:"warnings"`
+}
+
+// Service submits the deployments to the validation webhooks before they are made
+type Service struct {
+	dataStore  dataservices.DataStore
+	httpClient *http.Client
+}
+
+// NewService returns a new instance of Service, the requests go through the proxy of the outbound calls
+func NewService(dataStore dataservices.DataStore) *Service {
+	return &Service{
+		dataStore:  dataStore,
+		httpClient: &http.Client{Transport: client.NewTransport()},
+	}
+}
+
+// Validate submits a deployment to the enabled webhooks of its environment(endpoint) group, in turn.
+// The deployment is rejected by the first webhook that does not allow it
+func (service *Service) Validate(request deploymenthistory.ValidationRequest) ([]string, error) {
+	webhooks, err := service.dataStore.ValidationWebhook().ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the validation webhooks: %w", err)
+	}
+
+	webhooks = slices.DeleteFunc(webhooks, func(webhook portainer.ValidationWebhook) bool {
+		return !Applies(&webhook, request.Endpoint)
+	})
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(NewRequest(request))
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := []string{}
+	for i := range webhooks {
+		webhook := &webhooks[i]
+
+		response, err := service.send(webhook, body)
+		if err != nil {
+			if !webhook.FailOpen {
+				return warnings, fmt.Errorf("unable to validate the deployment with %s: %w", webhook.Name, err)
+			}
+
+			warnings = append(warnings, fmt.Sprintf("%s: unable to validate the deployment: %s", webhook.Name, err))
+
+			continue
+		}
+
+		for _, warning := range response.Warnings {
+			warnings = append(warnings, webhook.Name+": "+warning)
+		}
+
+		if !response.Allowed {
+			message := response.Message
+			if message == "" {
+				message = "no reason given"
+			}
+
+			return warnings, fmt.Errorf("%w by %s: %s", ErrDeploymentRejected, webhook.Name, message)
+		}
+	}
+
+	return warnings, nil
+}
+
+// Applies returns true when the webhook validates the deployments made on the environment(endpoint)
+func Applies(webhook *portainer.ValidationWebhook, endpoint *portainer.Endpoint) bool {
+	if !webhook.Enabled {
+		return false
+	}
+
+	return len(webhook.EndpointGroupIDs) == 0 || slices.Contains(webhook.EndpointGroupIDs, endpoint.GroupID)
+}
+
+// NewRequest returns the body submitted to the webhooks for a deployment
+func NewRequest(request deploymenthistory.ValidationRequest) Request {
+	body := Request{
+		Type: "kubernetes",
+		Endpoint: Endpoint{
+			ID:      request.Endpoint.ID,
+			Name:    request.Endpoint.Name,
+			GroupID: request.Endpoint.GroupID,
+		},
+		Namespace: request.Namespace,
+		Initiator: request.Deployment.Initiator,
+		Files:     make([]File, 0, len(request.Deployment.Artifacts)),
+	}
+
+	if request.Stack != nil {
+		body.Stack = &Stack{ID: request.Stack.ID, Name: request.Stack.Name}
+
+		switch request.Stack.Type {
+		case portainer.DockerComposeStack:
+			body.Type = "compose"
+		case portainer.DockerSwarmStack:
+			body.Type = "swarm"
+		}
+	}
+
+	for _, artifact := range request.Deployment.Artifacts {
+		body.Files = append(body.Files, File{Name: artifact.Name, Content: artifact.Content})
+	}
+
+	return body
+}
+
+// Sign returns the value of the signature header of a request body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (service *Service) send(webhook *portainer.ValidationWebhook, body []byte) (*Response, error) {
+	timeout := DefaultTimeout
+	if webhook.Timeout > 0 {
+		timeout = time.Duration(webhook.Timeout) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(webhook.Secret, body))
+	}
+
+	resp, err := service.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the webhook responded with %s", resp.Status)
+	}
+
+	var response Response
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&response); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+
+	return &response, nil
+}
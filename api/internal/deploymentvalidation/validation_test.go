@@ -0,0 +1,101 @@
+package deploymentvalidation
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/internal/deploymenthistory"
+
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebhookServer(t *testing.T, secret string, response Response) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		if secret != "" {
+			assert.Equal(t, Sign(secret, body), r.Header.Get(SignatureHeader))
+		}
+
+		var request Request
+		require.NoError(t, json.Unmarshal(body, &request))
+		assert.Equal(t, "compose", request.Type)
+		assert.Equal(t, "admin", request.Initiator)
+		assert.Equal(t, []File{{Name: "docker-compose.yml", Content: "services: {}"}}, request.Files)
+
+		json.NewEncoder(w).Encode(response)
+	}))
+}
+
+func newValidationRequest() deploymenthistory.ValidationRequest {
+	return deploymenthistory.ValidationRequest{
+		Deployment: &portainer.Deployment{
+			Initiator: "admin",
+			Artifacts: []portainer.DeploymentArtifact{{Name: "docker-compose.yml", Content: "services: {}"}},
+		},
+		Endpoint: &portainer.Endpoint{ID: 1, Name: "production", GroupID: 2},
+		Stack:    &portainer.Stack{ID: 1, Name: "web", Type: portainer.DockerComposeStack},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	allow := newWebhookServer(t, "secret", Response{Allowed: true, Warnings: []string{"image tag latest"}})
+	defer allow.Close()
+
+	reject := newWebhookServer(t, "", Response{Allowed: false, Message: "privileged containers are forbidden"})
+	defer reject.Close()
+
+	require.NoError(t, store.ValidationWebhook().Create(&portainer.ValidationWebhook{Name: "lint", URL: allow.URL, Secret: "secret", Enabled: true}))
+	// The webhooks of the other groups and the disabled webhooks are skipped
+	require.NoError(t, store.ValidationWebhook().Create(&portainer.ValidationWebhook{Name: "other", URL: reject.URL, EndpointGroupIDs: []portainer.EndpointGroupID{3}, Enabled: true}))
+	require.NoError(t, store.ValidationWebhook().Create(&portainer.ValidationWebhook{Name: "disabled", URL: reject.URL}))
+
+	service := NewService(store)
+
+	warnings, err := service.Validate(newValidationRequest())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lint: image tag latest"}, warnings)
+
+	require.NoError(t, store.ValidationWebhook().Create(&portainer.ValidationWebhook{Name: "opa", URL: reject.URL, EndpointGroupIDs: []portainer.EndpointGroupID{2}, Enabled: true}))
+
+	warnings, err = service.Validate(newValidationRequest())
+	require.ErrorIs(t, err, ErrDeploymentRejected)
+	assert.Contains(t, err.Error(), "opa: privileged containers are forbidden")
+	assert.Equal(t, []string{"lint: image tag latest"}, warnings)
+}
+
+func TestValidateUnreachable(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	webhook := &portainer.ValidationWebhook{Name: "opa", URL: srv.URL, FailOpen: true, Enabled: true}
+	require.NoError(t, store.ValidationWebhook().Create(webhook))
+
+	service := NewService(store)
+
+	warnings, err := service.Validate(newValidationRequest())
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "opa: unable to validate the deployment")
+
+	// The deployments are blocked when the webhook fails closed
+	webhook.FailOpen = false
+	require.NoError(t, store.ValidationWebhook().Update(webhook.ID, webhook))
+
+	_, err = service.Validate(newValidationRequest())
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrDeploymentRejected)
+}
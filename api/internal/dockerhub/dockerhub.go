@@ -0,0 +1,240 @@
+package dockerhub
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	tokenURL    = "https://auth.docker.io/token?service=registry.docker.io&scope=repository:ratelimitpreview/test:pull"
+	manifestURL = "https://registry-1.docker.io/v2/ratelimitpreview/test/manifests/latest"
+
+	// rateLimitCacheDuration is how long the quota of a set of credentials is kept before asking Docker Hub again
+	rateLimitCacheDuration = time.Minute
+)
+
+// ErrAnonymousRemoteEnvironment is returned when no DockerHub credentials are available for a remote environment,
+// its anonymous quota is tied to its own IP address and cannot be queried from Portainer
+var ErrAnonymousRemoteEnvironment = errors.New("the anonymous quota of a remote environment cannot be retrieved, a DockerHub registry must be available in the environment")
+
+// RateLimit represents the pull quota of a set of DockerHub credentials
+type RateLimit struct {
+	// Registry used to authenticate against DockerHub, 0 for the anonymous quota
+	RegistryID portainer.RegistryID `json:"RegistryId" example:"1"`
+	// Whether the account is not rate limited, the other counters are 0 in that case
+	Unlimited bool `json:"Unlimited" example:"false"`
+	// Number of pulls allowed in the window
+	Limit int `json:"Limit" example:"100"`
+	// Number of pulls left in the window
+	Remaining int `json:"Remaining" example:"42"`
+	// Duration of the window in seconds
+	Window int `json:"Window" example:"21600"`
+	// Unix timestamp of the time the quota was retrieved from DockerHub
+	CheckedAt int64 `json:"CheckedAt" example:"1700000000"`
+}
+
+type cachedRateLimit struct {
+	rateLimit RateLimit
+	// credentials used to retrieve the quota, a change of the registry credentials invalidates the entry
+	credentials string
+}
+
+// Service retrieves and caches the DockerHub pull quotas of the environments(endpoints)
+type Service struct {
+	dataStore   dataservices.DataStore
+	httpClient  *client.HTTPClient
+	tokenURL    string
+	manifestURL string
+
+	mu    sync.Mutex
+	cache map[portainer.RegistryID]cachedRateLimit
+}
+
+// NewService returns a new instance of Service
+func NewService(dataStore dataservices.DataStore) *Service {
+	return &Service{
+		dataStore:   dataStore,
+		httpClient:  client.NewHTTPClient(),
+		tokenURL:    tokenURL,
+		manifestURL: manifestURL,
+		cache:       make(map[portainer.RegistryID]cachedRateLimit),
+	}
+}
+
+// EndpointRateLimit returns the pull quota of an environment, using the credentials of the first authenticated
+// DockerHub registry available in the environment or the anonymous quota of a local environment
+func (service *Service) EndpointRateLimit(endpoint *portainer.Endpoint) (*RateLimit, error) {
+	registries, err := service.dataStore.Registry().ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(registries, func(a, b portainer.Registry) int {
+		return int(a.ID) - int(b.ID)
+	})
+
+	for _, registry := range registries {
+		if registry.Type != portainer.DockerHubRegistry || !registry.Authentication {
+			continue
+		}
+
+		if _, ok := registry.RegistryAccesses[endpoint.ID]; ok {
+			return service.RegistryRateLimit(&registry)
+		}
+	}
+
+	if !endpointutils.IsLocalEndpoint(endpoint) {
+		return nil, ErrAnonymousRemoteEnvironment
+	}
+
+	return service.RegistryRateLimit(&portainer.Registry{})
+}
+
+// RegistryRateLimit returns the pull quota of the credentials of a DockerHub registry, an empty registry
+// returns the anonymous quota of Portainer
+func (service *Service) RegistryRateLimit(registry *portainer.Registry) (*RateLimit, error) {
+	credentials := ""
+	if registry.Authentication {
+		credentials = registry.Username + ":" + registry.Password
+	}
+
+	now := time.Now()
+
+	service.mu.Lock()
+	cached, ok := service.cache[registry.ID]
+	service.mu.Unlock()
+
+	if ok && cached.credentials == credentials && now.Sub(time.Unix(cached.rateLimit.CheckedAt, 0)) < rateLimitCacheDuration {
+		rateLimit := cached.rateLimit
+
+		return &rateLimit, nil
+	}
+
+	rateLimit, err := service.fetchRateLimit(registry)
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimit.RegistryID = registry.ID
+	rateLimit.CheckedAt = now.Unix()
+
+	service.mu.Lock()
+	service.cache[registry.ID] = cachedRateLimit{rateLimit: *rateLimit, credentials: credentials}
+	service.mu.Unlock()
+
+	return rateLimit, nil
+}
+
+func (service *Service) fetchRateLimit(registry *portainer.Registry) (*RateLimit, error) {
+	token, err := service.getToken(registry)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve a DockerHub token: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodHead, service.manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Authorization", "Bearer "+token)
+
+	resp, err := service.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New("failed fetching dockerhub limits")
+	}
+
+	// The headers are not present for the DockerHub pro accounts which are not rate limited
+	// See: https://docs.docker.com/docker-hub/download-rate-limit/
+	limit, window, err := parseRateLimitHeader(resp.Header, "RateLimit-Limit")
+	if err != nil {
+		return &RateLimit{Unlimited: true}, nil
+	}
+
+	remaining, _, err := parseRateLimitHeader(resp.Header, "RateLimit-Remaining")
+	if err != nil {
+		return &RateLimit{Unlimited: true}, nil
+	}
+
+	return &RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Window:    window,
+	}, nil
+}
+
+func (service *Service) getToken(registry *portainer.Registry) (string, error) {
+	type dockerhubTokenResponse struct {
+		Token string `json:"token"`
+	}
+
+	req, err := http.NewRequest(http.MethodGet, service.tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if registry.Authentication {
+		req.SetBasicAuth(registry.Username, registry.Password)
+	}
+
+	resp, err := service.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New("failed fetching dockerhub token")
+	}
+
+	var data dockerhubTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return "", err
+	}
+
+	return data.Token, nil
+}
+
+// parseRateLimitHeader parses a header such as "100;w=21600" into the value and the window in seconds
+func parseRateLimitHeader(headers http.Header, headerKey string) (int, int, error) {
+	headerValue := headers.Get(headerKey)
+	if headerValue == "" {
+		return 0, 0, fmt.Errorf("Missing %s header", headerKey)
+	}
+
+	parts := strings.Split(headerValue, ";")
+
+	value, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	window := 0
+	for _, part := range parts[1:] {
+		if w, ok := strings.CutPrefix(strings.TrimSpace(part), "w="); ok {
+			window, _ = strconv.Atoi(w)
+		}
+	}
+
+	return value, window, nil
+}
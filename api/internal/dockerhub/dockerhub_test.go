@@ -0,0 +1,105 @@
+package dockerhub
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService(t *testing.T, store *datastore.Store, headers map[string]string) (*Service, *int) {
+	manifestRequests := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		token := "anonymous"
+		if username, _, ok := r.BasicAuth(); ok {
+			token = username
+		}
+
+		w.Write([]byte(`{"token":"` + token + `"}`))
+	})
+	mux.HandleFunc("/manifest", func(w http.ResponseWriter, r *http.Request) {
+		manifestRequests++
+
+		for key, value := range headers {
+			w.Header().Set(key, value)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	service := NewService(store)
+	service.tokenURL = server.URL + "/token"
+	service.manifestURL = server.URL + "/manifest"
+
+	return service, &manifestRequests
+}
+
+func TestEndpointRateLimit(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Registry().Create(&portainer.Registry{
+		Type:             portainer.DockerHubRegistry,
+		Authentication:   true,
+		Username:         "user",
+		Password:         "password",
+		RegistryAccesses: portainer.RegistryAccesses{2: {}},
+	}))
+
+	service, manifestRequests := newTestService(t, store, map[string]string{
+		"RateLimit-Limit":     "200;w=21600",
+		"RateLimit-Remaining": "150;w=21600",
+	})
+
+	local := &portainer.Endpoint{ID: 1, Type: portainer.DockerEnvironment, URL: "unix:///var/run/docker.sock"}
+	rateLimit, err := service.EndpointRateLimit(local)
+	require.NoError(t, err)
+	assert.Equal(t, portainer.RegistryID(0), rateLimit.RegistryID)
+	assert.Equal(t, 200, rateLimit.Limit)
+	assert.Equal(t, 150, rateLimit.Remaining)
+	assert.Equal(t, 21600, rateLimit.Window)
+	assert.False(t, rateLimit.Unlimited)
+
+	remote := &portainer.Endpoint{ID: 2, Type: portainer.AgentOnDockerEnvironment, URL: "tcp://agent:9001"}
+	rateLimit, err = service.EndpointRateLimit(remote)
+	require.NoError(t, err)
+	assert.Equal(t, portainer.RegistryID(1), rateLimit.RegistryID)
+
+	// Served from the cache
+	_, err = service.EndpointRateLimit(remote)
+	require.NoError(t, err)
+	assert.Equal(t, 2, *manifestRequests)
+
+	_, err = service.EndpointRateLimit(&portainer.Endpoint{ID: 3, Type: portainer.AgentOnDockerEnvironment, URL: "tcp://other:9001"})
+	require.ErrorIs(t, err, ErrAnonymousRemoteEnvironment)
+}
+
+func TestRegistryRateLimitUnlimited(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	service, _ := newTestService(t, store, nil)
+
+	rateLimit, err := service.RegistryRateLimit(&portainer.Registry{})
+	require.NoError(t, err)
+	assert.True(t, rateLimit.Unlimited)
+}
+
+func TestParseRateLimitHeader(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("RateLimit-Limit", "100;w=21600")
+
+	value, window, err := parseRateLimitHeader(headers, "RateLimit-Limit")
+	require.NoError(t, err)
+	assert.Equal(t, 100, value)
+	assert.Equal(t, 21600, window)
+
+	_, _, err = parseRateLimitHeader(headers, "RateLimit-Remaining")
+	require.Error(t, err)
+}
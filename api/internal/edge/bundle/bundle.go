@@ -0,0 +1,276 @@
+package bundle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/crypto"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+
+	"github.com/segmentio/encoding/json"
+)
+
+// FormatVersion is the version of the format of the bundles
+const FormatVersion = 1
+
+// ErrInvalidPassword is returned when the secrets of a bundle cannot be decrypted with the given password
+var ErrInvalidPassword = errors.New("unable to decrypt the secrets of the bundle, the password is invalid")
+
+type (
+	// Bundle holds everything needed to rebuild the Edge fleet of an instance on another one, the Edge environments
+	// keep their identifiers so that their agents reconnect without being enrolled again
+	Bundle struct {
+		Version int `json:"Version" example:"1"`
+		// Unix timestamp of the export
+		CreatedAt int64 `json:"CreatedAt" example:"1708000000"`
+		// Edge environments(endpoints), their Edge keys are part of the encrypted secrets
+		Endpoints         []portainer.Endpoint         `json:"Endpoints"`
+		EndpointRelations []portainer.EndpointRelation `json:"EndpointRelations"`
+		// Environment(Endpoint) groups and tags of the Edge environments and Edge groups
+		EndpointGroups []portainer.EndpointGroup `json:"EndpointGroups"`
+		Tags           []portainer.Tag           `json:"Tags"`
+		EdgeGroups     []portainer.EdgeGroup     `json:"EdgeGroups"`
+		EdgeStacks     []EdgeStack               `json:"EdgeStacks"`
+		EdgeJobs       []EdgeJob                 `json:"EdgeJobs"`
+		// Secrets encrypted with the password of the export
+		Secrets []byte `json:"Secrets"`
+	}
+
+	// EdgeStack is an Edge stack along with its files
+	EdgeStack struct {
+		portainer.EdgeStack
+		Files []File `json:"Files"`
+	}
+
+	// File is a file of an Edge stack, its path is relative to the project of the stack
+	File struct {
+		Path    string `json:"Path"`
+		Content []byte `json:"Content"`
+	}
+
+	// EdgeJob is an Edge job along with its script
+	EdgeJob struct {
+		portainer.EdgeJob
+		Script []byte `json:"Script"`
+	}
+
+	secrets struct {
+		EdgeKeys map[portainer.EndpointID]string `json:"EdgeKeys"`
+		// Private key of the tunnel server, its fingerprint is part of the Edge keys
+		TunnelPrivateKey []byte `json:"TunnelPrivateKey"`
+	}
+)
+
+// Export returns the bundle of the Edge configuration of the instance, its secrets are encrypted with the password
+func Export(tx dataservices.DataStoreTx, fileService portainer.FileService, password string) (*Bundle, error) {
+	bundle := &Bundle{
+		Version:           FormatVersion,
+		CreatedAt:         time.Now().Unix(),
+		Endpoints:         []portainer.Endpoint{},
+		EndpointRelations: []portainer.EndpointRelation{},
+		EndpointGroups:    []portainer.EndpointGroup{},
+		Tags:              []portainer.Tag{},
+		EdgeStacks:        []EdgeStack{},
+		EdgeJobs:          []EdgeJob{},
+	}
+
+	bundleSecrets := secrets{EdgeKeys: make(map[portainer.EndpointID]string)}
+
+	endpoints, err := tx.Endpoint().Endpoints()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the environments: %w", err)
+	}
+
+	groupIDs := make(map[portainer.EndpointGroupID]bool)
+	tagIDs := make(map[portainer.TagID]bool)
+
+	for _, endpoint := range endpoints {
+		if !endpointutils.IsEdgeEndpoint(&endpoint) {
+			continue
+		}
+
+		bundleSecrets.EdgeKeys[endpoint.ID] = endpoint.EdgeKey
+
+		endpoint.EdgeKey = ""
+		endpoint.Snapshots = []portainer.DockerSnapshot{}
+		endpoint.Kubernetes.Snapshots = []portainer.KubernetesSnapshot{}
+		bundle.Endpoints = append(bundle.Endpoints, endpoint)
+
+		groupIDs[endpoint.GroupID] = true
+		for _, tagID := range endpoint.TagIDs {
+			tagIDs[tagID] = true
+		}
+
+		relation, err := tx.EndpointRelation().EndpointRelation(endpoint.ID)
+		if err != nil && !tx.IsErrObjectNotFound(err) {
+			return nil, fmt.Errorf("unable to retrieve the relations of environment %d: %w", endpoint.ID, err)
+		} else if err == nil {
+			bundle.EndpointRelations = append(bundle.EndpointRelations, *relation)
+		}
+	}
+
+	if bundle.EdgeGroups, err = tx.EdgeGroup().ReadAll(); err != nil {
+		return nil, fmt.Errorf("unable to retrieve the Edge groups: %w", err)
+	}
+
+	for _, edgeGroup := range bundle.EdgeGroups {
+		for _, tagID := range edgeGroup.TagIDs {
+			tagIDs[tagID] = true
+		}
+	}
+
+	for groupID := range groupIDs {
+		group, err := tx.EndpointGroup().Read(groupID)
+		if tx.IsErrObjectNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to retrieve environment group %d: %w", groupID, err)
+		}
+
+		bundle.EndpointGroups = append(bundle.EndpointGroups, *group)
+		for _, tagID := range group.TagIDs {
+			tagIDs[tagID] = true
+		}
+	}
+
+	for tagID := range tagIDs {
+		tag, err := tx.Tag().Read(tagID)
+		if tx.IsErrObjectNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to retrieve tag %d: %w", tagID, err)
+		}
+
+		bundle.Tags = append(bundle.Tags, *tag)
+	}
+
+	edgeStacks, err := tx.EdgeStack().EdgeStacks()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the Edge stacks: %w", err)
+	}
+
+	for _, edgeStack := range edgeStacks {
+		// The stacks of the edge update schedules are not part of the configuration of the fleet
+		if edgeStack.EdgeUpdateID != 0 {
+			continue
+		}
+
+		files, err := readProjectFiles(edgeStack.ProjectPath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the files of Edge stack %d: %w", edgeStack.ID, err)
+		}
+
+		bundle.EdgeStacks = append(bundle.EdgeStacks, EdgeStack{EdgeStack: edgeStack, Files: files})
+	}
+
+	edgeJobs, err := tx.EdgeJob().ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the Edge jobs: %w", err)
+	}
+
+	for _, edgeJob := range edgeJobs {
+		script, err := fileService.GetFileContent(edgeJob.ScriptPath, "")
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the script of Edge job %d: %w", edgeJob.ID, err)
+		}
+
+		bundle.EdgeJobs = append(bundle.EdgeJobs, EdgeJob{EdgeJob: edgeJob, Script: script})
+	}
+
+	bundleSecrets.TunnelPrivateKey, err = os.ReadFile(fileService.GetDefaultChiselPrivateKeyPath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("unable to read the private key of the tunnel server: %w", err)
+	}
+
+	if bundle.Secrets, err = encryptSecrets(bundleSecrets, password); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+// readProjectFiles returns the files of the project of an Edge stack, all its versions included
+func readProjectFiles(projectPath string) ([]File, error) {
+	files := []File{}
+	if projectPath == "" {
+		return files, nil
+	}
+
+	err := filepath.WalkDir(projectPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			return err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, File{Path: filepath.ToSlash(rel), Content: content})
+
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return files, nil
+	}
+
+	return files, err
+}
+
+func encryptSecrets(bundleSecrets secrets, password string) ([]byte, error) {
+	data, err := json.Marshal(bundleSecrets)
+	if err != nil {
+		return nil, err
+	}
+
+	var encrypted bytes.Buffer
+	if err := crypto.AesEncrypt(bytes.NewReader(data), &encrypted, []byte(password)); err != nil {
+		return nil, fmt.Errorf("unable to encrypt the secrets of the bundle: %w", err)
+	}
+
+	return encrypted.Bytes(), nil
+}
+
+func decryptSecrets(encrypted []byte, password string) (*secrets, error) {
+	reader, err := crypto.AesDecrypt(bytes.NewReader(encrypted), []byte(password))
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+
+	var bundleSecrets secrets
+	if err := json.Unmarshal(data, &bundleSecrets); err != nil {
+		return nil, ErrInvalidPassword
+	}
+
+	return &bundleSecrets, nil
+}
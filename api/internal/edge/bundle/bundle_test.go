@@ -0,0 +1,168 @@
+package bundle
+
+import (
+	"os"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/filesystem"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSourceInstance(t *testing.T) (*datastore.Store, portainer.FileService) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	fileService, err := filesystem.NewService(t.TempDir(), "")
+	require.NoError(t, err)
+
+	require.NoError(t, fileService.StoreChiselPrivateKey([]byte("tunnel-private-key")))
+
+	tag := &portainer.Tag{Name: "site-a"}
+	require.NoError(t, store.Tag().Create(tag))
+
+	group := &portainer.EndpointGroup{Name: "stores", TagIDs: []portainer.TagID{}}
+	require.NoError(t, store.EndpointGroup().Create(group))
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1, Name: "local", Type: portainer.DockerEnvironment}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{
+		ID:      7,
+		Name:    "store-42",
+		Type:    portainer.EdgeAgentOnDockerEnvironment,
+		EdgeID:  "edge-id-42",
+		EdgeKey: "edge-key-42",
+		GroupID: group.ID,
+		TagIDs:  []portainer.TagID{tag.ID},
+	}))
+
+	// The identifier of the Edge group changes on import
+	require.NoError(t, store.EdgeGroup().Create(&portainer.EdgeGroup{Name: "unused"}))
+	edgeGroup := &portainer.EdgeGroup{Name: "stores", Dynamic: true, TagIDs: []portainer.TagID{tag.ID}}
+	require.NoError(t, store.EdgeGroup().Create(edgeGroup))
+
+	projectPath, err := fileService.StoreEdgeStackFileFromBytesByVersion("3", "docker-compose.yml", 1, []byte("services: {}"))
+	require.NoError(t, err)
+
+	require.NoError(t, store.EdgeStack().Create(3, &portainer.EdgeStack{
+		Name:        "pos",
+		EdgeGroups:  []portainer.EdgeGroupID{edgeGroup.ID},
+		ProjectPath: fileService.GetEdgeStackProjectPath("3"),
+		EntryPoint:  "docker-compose.yml",
+		Version:     1,
+	}))
+	require.DirExists(t, projectPath)
+
+	require.NoError(t, store.EndpointRelation().Create(&portainer.EndpointRelation{EndpointID: 7, EdgeStacks: map[portainer.EdgeStackID]bool{3: true}}))
+
+	scriptPath, err := fileService.StoreEdgeJobFileFromBytes("5", []byte("echo hello"))
+	require.NoError(t, err)
+
+	require.NoError(t, store.EdgeJob().CreateWithID(5, &portainer.EdgeJob{
+		Name:       "hello",
+		EdgeGroups: []portainer.EdgeGroupID{edgeGroup.ID},
+		ScriptPath: scriptPath,
+		Endpoints:  map[portainer.EndpointID]portainer.EdgeJobEndpointMeta{7: {LogsStatus: portainer.EdgeJobLogsStatusCollected}},
+	}))
+
+	return store, fileService
+}
+
+func TestExportImport(t *testing.T) {
+	source, sourceFiles := newSourceInstance(t)
+
+	var bundle *Bundle
+	require.NoError(t, source.ViewTx(func(tx dataservices.DataStoreTx) (err error) {
+		bundle, err = Export(tx, sourceFiles, "password")
+
+		return err
+	}))
+
+	// Only the Edge environments are exported and their keys are encrypted
+	require.Len(t, bundle.Endpoints, 1)
+	assert.Empty(t, bundle.Endpoints[0].EdgeKey)
+	require.Len(t, bundle.EdgeStacks, 1)
+	assert.Equal(t, []File{{Path: "v1/docker-compose.yml", Content: []byte("services: {}")}}, bundle.EdgeStacks[0].Files)
+
+	_, target := datastore.MustNewTestStore(t, true, true)
+	targetFiles, err := filesystem.NewService(t.TempDir(), "")
+	require.NoError(t, err)
+
+	err = target.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		_, err := Import(tx, targetFiles, bundle, "wrong")
+
+		return err
+	})
+	require.ErrorIs(t, err, ErrInvalidPassword)
+
+	var result *ImportResult
+	require.NoError(t, target.UpdateTx(func(tx dataservices.DataStoreTx) (err error) {
+		result, err = Import(tx, targetFiles, bundle, "password")
+
+		return err
+	}))
+	assert.Equal(t, &ImportResult{Endpoints: 1, EdgeGroups: 2, EdgeStacks: 1, EdgeJobs: 1, RestartRequired: true}, result)
+
+	// The Edge environment keeps its identifier and its key
+	endpoint, err := target.Endpoint().Endpoint(7)
+	require.NoError(t, err)
+	assert.Equal(t, "edge-key-42", endpoint.EdgeKey)
+
+	endpointID, ok := target.Endpoint().EndpointIDByEdgeID("edge-id-42")
+	require.True(t, ok)
+	assert.Equal(t, portainer.EndpointID(7), endpointID)
+
+	group, err := target.EndpointGroup().Read(endpoint.GroupID)
+	require.NoError(t, err)
+	assert.Equal(t, "stores", group.Name)
+
+	require.Len(t, endpoint.TagIDs, 1)
+	tag, err := target.Tag().Read(endpoint.TagIDs[0])
+	require.NoError(t, err)
+	assert.Equal(t, "site-a", tag.Name)
+	assert.True(t, tag.Endpoints[7])
+
+	// The Edge stack refers to the new identifier of its Edge group
+	edgeStack, err := target.EdgeStack().EdgeStack(3)
+	require.NoError(t, err)
+	require.Len(t, edgeStack.EdgeGroups, 1)
+
+	edgeGroup, err := target.EdgeGroup().Read(edgeStack.EdgeGroups[0])
+	require.NoError(t, err)
+	assert.Equal(t, "stores", edgeGroup.Name)
+	assert.Equal(t, endpoint.TagIDs, edgeGroup.TagIDs)
+
+	content, err := targetFiles.GetFileContent(edgeStack.ProjectPath, "v1/docker-compose.yml")
+	require.NoError(t, err)
+	assert.Equal(t, "services: {}", string(content))
+
+	edgeJob, err := target.EdgeJob().Read(5)
+	require.NoError(t, err)
+	assert.Equal(t, portainer.EdgeJobLogsStatusIdle, edgeJob.Endpoints[7].LogsStatus)
+
+	script, err := targetFiles.GetFileContent(edgeJob.ScriptPath, "")
+	require.NoError(t, err)
+	assert.Equal(t, "echo hello", string(script))
+
+	relation, err := target.EndpointRelation().EndpointRelation(7)
+	require.NoError(t, err)
+	assert.True(t, relation.EdgeStacks[3])
+
+	key, err := os.ReadFile(targetFiles.GetDefaultChiselPrivateKeyPath())
+	require.NoError(t, err)
+	assert.Equal(t, "tunnel-private-key", string(key))
+
+	// The new objects do not reuse the imported identifiers
+	assert.Greater(t, target.Endpoint().GetNextIdentifier(), 7)
+	assert.Greater(t, target.EdgeStack().GetNextIdentifier(), 3)
+
+	// A second import conflicts with the configuration imported
+	err = target.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		_, err := Import(tx, targetFiles, bundle, "password")
+
+		return err
+	})
+	require.ErrorIs(t, err, ErrConflict)
+}
@@ -0,0 +1,411 @@
+package bundle
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/filesystem"
+)
+
+var (
+	// ErrUnsupportedVersion is returned when the bundle was exported in an unknown format
+	ErrUnsupportedVersion = errors.New("unsupported bundle version")
+	// ErrConflict is returned when the Edge configuration of the bundle conflicts with the one of the instance
+	ErrConflict = errors.New("the Edge configuration of the bundle conflicts with the one of the instance")
+)
+
+// ImportResult summarizes what was imported from a bundle
+type ImportResult struct {
+	Endpoints  int `json:"Endpoints" example:"12"`
+	EdgeGroups int `json:"EdgeGroups" example:"3"`
+	EdgeStacks int `json:"EdgeStacks" example:"4"`
+	EdgeJobs   int `json:"EdgeJobs" example:"1"`
+	// Whether the private key of the tunnel server was restored, it is used once Portainer is restarted
+	RestartRequired bool `json:"RestartRequired" example:"true"`
+}
+
+type importer struct {
+	tx          dataservices.DataStoreTx
+	fileService portainer.FileService
+	tags        map[portainer.TagID]portainer.TagID
+	groups      map[portainer.EndpointGroupID]portainer.EndpointGroupID
+	edgeGroups  map[portainer.EdgeGroupID]portainer.EdgeGroupID
+	// Folders created for the files of the Edge stacks and jobs, removed when the import fails
+	folders []string
+}
+
+// Import recreates the Edge configuration of a bundle on an instance without one. The Edge environments, stacks and
+// jobs keep their identifiers, the groups and tags are matched by name. The access policies are not imported since
+// they refer to the users of the previous instance
+func Import(tx dataservices.DataStoreTx, fileService portainer.FileService, bundle *Bundle, password string) (result *ImportResult, err error) {
+	if bundle.Version != FormatVersion {
+		return nil, fmt.Errorf("%w %d", ErrUnsupportedVersion, bundle.Version)
+	}
+
+	bundleSecrets, err := decryptSecrets(bundle.Secrets, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkConflicts(tx, bundle); err != nil {
+		return nil, err
+	}
+
+	imp := &importer{
+		tx:          tx,
+		fileService: fileService,
+		tags:        make(map[portainer.TagID]portainer.TagID),
+		groups:      make(map[portainer.EndpointGroupID]portainer.EndpointGroupID),
+		edgeGroups:  make(map[portainer.EdgeGroupID]portainer.EdgeGroupID),
+	}
+
+	defer func() {
+		if err != nil {
+			for _, folder := range imp.folders {
+				os.RemoveAll(folder)
+			}
+		}
+	}()
+
+	if err := imp.importTags(bundle.Tags); err != nil {
+		return nil, err
+	}
+
+	if err := imp.importEndpointGroups(bundle.EndpointGroups); err != nil {
+		return nil, err
+	}
+
+	if err := imp.importEdgeGroups(bundle.EdgeGroups); err != nil {
+		return nil, err
+	}
+
+	if err := imp.importEndpoints(bundle.Endpoints, bundleSecrets.EdgeKeys); err != nil {
+		return nil, err
+	}
+
+	for i := range bundle.EndpointRelations {
+		if err := tx.EndpointRelation().Create(&bundle.EndpointRelations[i]); err != nil {
+			return nil, fmt.Errorf("unable to create the relations of environment %d: %w", bundle.EndpointRelations[i].EndpointID, err)
+		}
+	}
+
+	if err := imp.importEdgeStacks(bundle.EdgeStacks); err != nil {
+		return nil, err
+	}
+
+	if err := imp.importEdgeJobs(bundle.EdgeJobs); err != nil {
+		return nil, err
+	}
+
+	result = &ImportResult{
+		Endpoints:  len(bundle.Endpoints),
+		EdgeGroups: len(bundle.EdgeGroups),
+		EdgeStacks: len(bundle.EdgeStacks),
+		EdgeJobs:   len(bundle.EdgeJobs),
+	}
+
+	if len(bundleSecrets.TunnelPrivateKey) > 0 {
+		if err := fileService.StoreChiselPrivateKey(bundleSecrets.TunnelPrivateKey); err != nil {
+			return nil, fmt.Errorf("unable to store the private key of the tunnel server: %w", err)
+		}
+
+		result.RestartRequired = true
+	}
+
+	return result, nil
+}
+
+// checkConflicts returns ErrConflict when the instance already has an Edge configuration or an environment using
+// the identifier of an Edge environment of the bundle
+func checkConflicts(tx dataservices.DataStoreTx, bundle *Bundle) error {
+	edgeGroups, err := tx.EdgeGroup().ReadAll()
+	if err != nil {
+		return err
+	}
+
+	edgeStacks, err := tx.EdgeStack().EdgeStacks()
+	if err != nil {
+		return err
+	}
+
+	edgeJobs, err := tx.EdgeJob().ReadAll()
+	if err != nil {
+		return err
+	}
+
+	if len(edgeGroups) > 0 || len(edgeStacks) > 0 || len(edgeJobs) > 0 {
+		return fmt.Errorf("%w: the instance already has Edge groups, stacks or jobs", ErrConflict)
+	}
+
+	for _, endpoint := range bundle.Endpoints {
+		if _, err := tx.Endpoint().Endpoint(endpoint.ID); err == nil {
+			return fmt.Errorf("%w: an environment already uses the identifier %d", ErrConflict, endpoint.ID)
+		} else if !tx.IsErrObjectNotFound(err) {
+			return err
+		}
+
+		if _, ok := tx.Endpoint().EndpointIDByEdgeID(endpoint.EdgeID); ok && endpoint.EdgeID != "" {
+			return fmt.Errorf("%w: an environment already uses the Edge ID of %s", ErrConflict, endpoint.Name)
+		}
+	}
+
+	return nil
+}
+
+// reserveIdentifier moves the sequence of a bucket past an identifier kept from the bundle, so that the
+// identifier is not given to a new object
+func reserveIdentifier(nextIdentifier func() int, id int) {
+	for next := nextIdentifier(); next > 0 && next < id; next = nextIdentifier() {
+	}
+}
+
+func (imp *importer) importTags(tags []portainer.Tag) error {
+	existing, err := imp.tx.Tag().ReadAll()
+	if err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if i := slices.IndexFunc(existing, func(t portainer.Tag) bool { return t.Name == tag.Name }); i >= 0 {
+			imp.tags[tag.ID] = existing[i].ID
+
+			continue
+		}
+
+		newTag := &portainer.Tag{
+			Name:           tag.Name,
+			Endpoints:      map[portainer.EndpointID]bool{},
+			EndpointGroups: map[portainer.EndpointGroupID]bool{},
+		}
+
+		if err := imp.tx.Tag().Create(newTag); err != nil {
+			return fmt.Errorf("unable to create tag %s: %w", tag.Name, err)
+		}
+
+		imp.tags[tag.ID] = newTag.ID
+	}
+
+	return nil
+}
+
+func (imp *importer) mapTags(tagIDs []portainer.TagID) []portainer.TagID {
+	mapped := []portainer.TagID{}
+	for _, tagID := range tagIDs {
+		if newID, ok := imp.tags[tagID]; ok {
+			mapped = append(mapped, newID)
+		}
+	}
+
+	return mapped
+}
+
+// tagResource adds a resource to the tags, the tags keep track of the resources using them
+func (imp *importer) tagResource(tagIDs []portainer.TagID, update func(tag *portainer.Tag)) error {
+	for _, tagID := range tagIDs {
+		tag, err := imp.tx.Tag().Read(tagID)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve tag %d: %w", tagID, err)
+		}
+
+		update(tag)
+
+		if err := imp.tx.Tag().Update(tag.ID, tag); err != nil {
+			return fmt.Errorf("unable to update tag %s: %w", tag.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (imp *importer) importEndpointGroups(groups []portainer.EndpointGroup) error {
+	existing, err := imp.tx.EndpointGroup().ReadAll()
+	if err != nil {
+		return err
+	}
+
+	for _, group := range groups {
+		if i := slices.IndexFunc(existing, func(g portainer.EndpointGroup) bool { return g.Name == group.Name }); i >= 0 {
+			imp.groups[group.ID] = existing[i].ID
+
+			continue
+		}
+
+		oldID := group.ID
+		group.TagIDs = imp.mapTags(group.TagIDs)
+		group.UserAccessPolicies = portainer.UserAccessPolicies{}
+		group.TeamAccessPolicies = portainer.TeamAccessPolicies{}
+
+		if err := imp.tx.EndpointGroup().Create(&group); err != nil {
+			return fmt.Errorf("unable to create environment group %s: %w", group.Name, err)
+		}
+
+		imp.groups[oldID] = group.ID
+
+		if err := imp.tagResource(group.TagIDs, func(tag *portainer.Tag) {
+			if tag.EndpointGroups == nil {
+				tag.EndpointGroups = map[portainer.EndpointGroupID]bool{}
+			}
+
+			tag.EndpointGroups[group.ID] = true
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (imp *importer) importEdgeGroups(edgeGroups []portainer.EdgeGroup) error {
+	for _, edgeGroup := range edgeGroups {
+		oldID := edgeGroup.ID
+		edgeGroup.TagIDs = imp.mapTags(edgeGroup.TagIDs)
+
+		if err := imp.tx.EdgeGroup().Create(&edgeGroup); err != nil {
+			return fmt.Errorf("unable to create Edge group %s: %w", edgeGroup.Name, err)
+		}
+
+		imp.edgeGroups[oldID] = edgeGroup.ID
+	}
+
+	return nil
+}
+
+func (imp *importer) mapEdgeGroups(edgeGroupIDs []portainer.EdgeGroupID) []portainer.EdgeGroupID {
+	mapped := []portainer.EdgeGroupID{}
+	for _, edgeGroupID := range edgeGroupIDs {
+		if newID, ok := imp.edgeGroups[edgeGroupID]; ok {
+			mapped = append(mapped, newID)
+		}
+	}
+
+	return mapped
+}
+
+func (imp *importer) importEndpoints(endpoints []portainer.Endpoint, edgeKeys map[portainer.EndpointID]string) error {
+	slices.SortFunc(endpoints, func(a, b portainer.Endpoint) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	for _, endpoint := range endpoints {
+		endpoint.EdgeKey = edgeKeys[endpoint.ID]
+		endpoint.TagIDs = imp.mapTags(endpoint.TagIDs)
+		endpoint.UserAccessPolicies = portainer.UserAccessPolicies{}
+		endpoint.TeamAccessPolicies = portainer.TeamAccessPolicies{}
+
+		groupID, ok := imp.groups[endpoint.GroupID]
+		if !ok {
+			groupID = portainer.EndpointGroupID(1)
+		}
+		endpoint.GroupID = groupID
+
+		reserveIdentifier(imp.tx.Endpoint().GetNextIdentifier, int(endpoint.ID))
+
+		if err := imp.tx.Endpoint().Create(&endpoint); err != nil {
+			return fmt.Errorf("unable to create environment %s: %w", endpoint.Name, err)
+		}
+
+		if err := imp.tagResource(endpoint.TagIDs, func(tag *portainer.Tag) {
+			if tag.Endpoints == nil {
+				tag.Endpoints = map[portainer.EndpointID]bool{}
+			}
+
+			tag.Endpoints[endpoint.ID] = true
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (imp *importer) importEdgeStacks(edgeStacks []EdgeStack) error {
+	slices.SortFunc(edgeStacks, func(a, b EdgeStack) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	for _, edgeStack := range edgeStacks {
+		stack := edgeStack.EdgeStack
+		stack.EdgeGroups = imp.mapEdgeGroups(stack.EdgeGroups)
+
+		if stack.RolloutPolicy != nil {
+			for i, batch := range stack.RolloutPolicy.Batches {
+				stack.RolloutPolicy.Batches[i] = imp.mapEdgeGroups(batch)
+			}
+		}
+
+		projectPath := imp.fileService.GetEdgeStackProjectPath(strconv.Itoa(int(stack.ID)))
+		imp.folders = append(imp.folders, projectPath)
+
+		for _, file := range edgeStack.Files {
+			if err := writeFile(projectPath, file); err != nil {
+				return fmt.Errorf("unable to write the files of Edge stack %s: %w", stack.Name, err)
+			}
+		}
+
+		if stack.ProjectPath != "" {
+			stack.ProjectPath = projectPath
+		}
+
+		reserveIdentifier(imp.tx.EdgeStack().GetNextIdentifier, int(stack.ID))
+
+		if err := imp.tx.EdgeStack().Create(stack.ID, &stack); err != nil {
+			return fmt.Errorf("unable to create Edge stack %s: %w", stack.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func writeFile(projectPath string, file File) error {
+	path := filesystem.JoinPaths(projectPath, file.Path)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return filesystem.CreateFile(path, bytes.NewReader(file.Content))
+}
+
+func (imp *importer) importEdgeJobs(edgeJobs []EdgeJob) error {
+	slices.SortFunc(edgeJobs, func(a, b EdgeJob) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	for _, edgeJob := range edgeJobs {
+		job := edgeJob.EdgeJob
+		job.EdgeGroups = imp.mapEdgeGroups(job.EdgeGroups)
+
+		// The logs of the tasks are not part of the bundle
+		for endpointID, meta := range job.Endpoints {
+			meta.LogsStatus = portainer.EdgeJobLogsStatusIdle
+			meta.CollectLogs = false
+			job.Endpoints[endpointID] = meta
+		}
+		job.GroupLogsCollection = map[portainer.EndpointID]portainer.EdgeJobEndpointMeta{}
+
+		identifier := strconv.Itoa(int(job.ID))
+		imp.folders = append(imp.folders, imp.fileService.GetEdgeJobFolder(identifier))
+
+		scriptPath, err := imp.fileService.StoreEdgeJobFileFromBytes(identifier, edgeJob.Script)
+		if err != nil {
+			return fmt.Errorf("unable to write the script of Edge job %s: %w", job.Name, err)
+		}
+		job.ScriptPath = scriptPath
+
+		reserveIdentifier(imp.tx.EdgeJob().GetNextIdentifier, int(job.ID))
+
+		if err := imp.tx.EdgeJob().CreateWithID(job.ID, &job); err != nil {
+			return fmt.Errorf("unable to create Edge job %s: %w", job.Name, err)
+		}
+	}
+
+	return nil
+}
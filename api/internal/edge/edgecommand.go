@@ -0,0 +1,40 @@
+package edge
+
+import (
+	"fmt"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/edge/cache"
+)
+
+// QueueCommands queues a command for each of the environments(endpoints) running in async mode,
+// the other environments pick up the change from their status instead
+func QueueCommands(tx dataservices.DataStoreTx, endpointIDs []portainer.EndpointID, resourceType portainer.EdgeCommandResourceType, resourceID int, operation portainer.EdgeCommandOperation) error {
+	for _, endpointID := range endpointIDs {
+		endpoint, err := tx.Endpoint().Endpoint(endpointID)
+		if tx.IsErrObjectNotFound(err) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("unable to retrieve environment %d from the database: %w", endpointID, err)
+		}
+
+		if !endpoint.Edge.AsyncMode {
+			continue
+		}
+
+		if err := tx.EdgeCommand().Create(&portainer.EdgeCommand{
+			EndpointID:   endpointID,
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			Operation:    operation,
+		}); err != nil {
+			return fmt.Errorf("unable to queue the edge command for environment %d: %w", endpointID, err)
+		}
+
+		// Drop the cached status so that the agent receives the command on its next poll
+		cache.Del(endpointID)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package edgestacks
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	edgepayload "github.com/portainer/portainer/api/edge"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/api/internal/registryutils"
+	"github.com/portainer/portainer/pkg/libcrypto"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// ValidateRegistries checks that the registries referenced by an edge stack exist
+func ValidateRegistries(tx dataservices.DataStoreTx, registryIDs []portainer.RegistryID) error {
+	for _, registryID := range registryIDs {
+		if _, err := tx.Registry().Read(registryID); tx.IsErrObjectNotFound(err) {
+			return httperrors.NewInvalidPayloadError(fmt.Sprintf("unable to find the registry %d", registryID))
+		} else if err != nil {
+			return errors.WithMessage(err, "unable to retrieve the registry")
+		}
+	}
+
+	return nil
+}
+
+// RegistryCredentials returns the credentials of the registries of an edge stack for an environment(endpoint).
+// The secrets are encrypted with the edge key of the environment so that only its agent can read them,
+// the registries removed since the stack was saved are skipped
+func RegistryCredentials(dataStore dataservices.DataStore, edgeStack *portainer.EdgeStack, endpoint *portainer.Endpoint) ([]edgepayload.RegistryCredentials, error) {
+	if len(edgeStack.Registries) == 0 {
+		return nil, nil
+	}
+
+	if endpoint.EdgeKey == "" {
+		return nil, errors.New("the environment has no edge key to encrypt the registry credentials with")
+	}
+
+	credentials := []edgepayload.RegistryCredentials{}
+
+	for _, registryID := range edgeStack.Registries {
+		registry, err := dataStore.Registry().Read(registryID)
+		if dataStore.IsErrObjectNotFound(err) {
+			log.Warn().Int("registry_id", int(registryID)).Int("edge_stack_id", int(edgeStack.ID)).Msg("the registry of the edge stack was removed")
+
+			continue
+		} else if err != nil {
+			return nil, errors.WithMessage(err, "unable to retrieve the registry")
+		}
+
+		if !registry.Authentication {
+			continue
+		}
+
+		if err := registryutils.EnsureRegTokenValid(dataStore, registry); err != nil {
+			return nil, errors.WithMessagef(err, "unable to refresh the token of the registry %s", registry.Name)
+		}
+
+		username, password, err := registryutils.GetRegEffectiveCredential(registry)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "unable to retrieve the credentials of the registry %s", registry.Name)
+		}
+
+		secret, err := libcrypto.Encrypt([]byte(password), []byte(endpoint.EdgeKey))
+		if err != nil {
+			return nil, errors.WithMessage(err, "unable to encrypt the registry credentials")
+		}
+
+		credentials = append(credentials, edgepayload.RegistryCredentials{
+			ServerURL: registry.URL,
+			Username:  username,
+			Secret:    base64.StdEncoding.EncodeToString(secret),
+		})
+	}
+
+	return credentials, nil
+}
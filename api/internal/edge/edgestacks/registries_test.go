@@ -0,0 +1,57 @@
+package edgestacks
+
+import (
+	"encoding/base64"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/datastore"
+	httperrors "github.com/portainer/portainer/api/http/errors"
+	"github.com/portainer/portainer/pkg/libcrypto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryCredentials(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, false)
+
+	private := &portainer.Registry{Name: "private", URL: "registry.example.com", Authentication: true, Username: "user", Password: "secret"}
+	require.NoError(t, store.Registry().Create(private))
+
+	public := &portainer.Registry{Name: "public", URL: "public.example.com"}
+	require.NoError(t, store.Registry().Create(public))
+
+	err := store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return ValidateRegistries(tx, []portainer.RegistryID{private.ID, 42})
+	})
+	assert.True(t, httperrors.IsInvalidPayloadError(err))
+
+	endpoint := &portainer.Endpoint{ID: 1, EdgeKey: "edge-key"}
+	edgeStack := &portainer.EdgeStack{ID: 1, Registries: []portainer.RegistryID{private.ID, public.ID}}
+
+	credentials, err := RegistryCredentials(store, edgeStack, endpoint)
+	require.NoError(t, err)
+	require.Len(t, credentials, 1)
+	assert.Equal(t, "registry.example.com", credentials[0].ServerURL)
+	assert.Equal(t, "user", credentials[0].Username)
+
+	// Only the agent holding the edge key can read the password
+	secret, err := base64.StdEncoding.DecodeString(credentials[0].Secret)
+	require.NoError(t, err)
+
+	password, err := libcrypto.Decrypt(secret, []byte(endpoint.EdgeKey))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(password))
+
+	_, err = libcrypto.Decrypt(secret, []byte("another-edge-key"))
+	require.Error(t, err)
+
+	// The registries removed since the stack was saved are skipped
+	require.NoError(t, store.Registry().Delete(private.ID))
+
+	credentials, err = RegistryCredentials(store, edgeStack, endpoint)
+	require.NoError(t, err)
+	assert.Empty(t, credentials)
+}
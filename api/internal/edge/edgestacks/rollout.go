@@ -0,0 +1,173 @@
+package edgestacks
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/edge"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RolloutProgressInterval is the interval at which the staged rollouts are checked for progress
+const RolloutProgressInterval = 30 * time.Second
+
+// ValidateRolloutPolicy checks that a rollout policy can be applied
+func ValidateRolloutPolicy(policy *portainer.EdgeStackRolloutPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	if len(policy.Batches) == 0 && (policy.Percentage <= 0 || policy.Percentage > 100) {
+		return errors.New("rollout policy requires either batches of edge groups or a percentage between 1 and 100")
+	}
+
+	for _, batch := range policy.Batches {
+		if len(batch) == 0 {
+			return errors.New("rollout policy batches cannot be empty")
+		}
+	}
+
+	if policy.ErrorThreshold < 0 || policy.ErrorThreshold > 100 {
+		return errors.New("rollout policy error threshold must be between 0 and 100")
+	}
+
+	return nil
+}
+
+// NewRollout splits the related environments of an edge stack into the batches described by its rollout policy
+func NewRollout(stack *portainer.EdgeStack, previousVersion int, relatedEndpointIDs []portainer.EndpointID, relationConfig *edge.EndpointRelationsConfig) (*portainer.EdgeStackRollout, error) {
+	policy := stack.RolloutPolicy
+
+	rollout := &portainer.EdgeStackRollout{
+		Version:         stack.Version,
+		PreviousVersion: previousVersion,
+		Batches:         [][]portainer.EndpointID{},
+	}
+
+	remaining := slices.Clone(relatedEndpointIDs)
+	slices.Sort(remaining)
+
+	for _, groupIDs := range policy.Batches {
+		batchEndpointIDs, err := edge.EdgeStackRelatedEndpoints(groupIDs, relationConfig.Endpoints, relationConfig.EndpointGroups, relationConfig.EdgeGroups)
+		if err != nil {
+			return nil, err
+		}
+
+		batch := []portainer.EndpointID{}
+		remaining = slices.DeleteFunc(remaining, func(id portainer.EndpointID) bool {
+			if slices.Contains(batchEndpointIDs, id) {
+				batch = append(batch, id)
+				return true
+			}
+
+			return false
+		})
+
+		if len(batch) > 0 {
+			rollout.Batches = append(rollout.Batches, batch)
+		}
+	}
+
+	if len(policy.Batches) == 0 {
+		batchSize := max(1, (len(remaining)*policy.Percentage+99)/100)
+
+		for start := 0; start < len(remaining); start += batchSize {
+			rollout.Batches = append(rollout.Batches, remaining[start:min(start+batchSize, len(remaining))])
+		}
+	} else if len(remaining) > 0 {
+		rollout.Batches = append(rollout.Batches, remaining)
+	}
+
+	rollout.Completed = len(rollout.Batches) == 0
+
+	return rollout, nil
+}
+
+// ProgressRollout moves the rollout of the edge stack to its next batch once every environment of the current
+// batch finished deploying, or halts it when the error threshold is exceeded. The rollout is completed once the
+// last batch finished deploying. It returns true when the rollout changed
+func ProgressRollout(stack *portainer.EdgeStack) bool {
+	rollout := stack.Rollout
+	if rollout == nil || rollout.Completed || rollout.Halted || rollout.Version != stack.Version {
+		return false
+	}
+
+	errorThreshold := 0
+	if stack.RolloutPolicy != nil {
+		errorThreshold = stack.RolloutPolicy.ErrorThreshold
+	}
+
+	changed := false
+	for !rollout.Completed && !rollout.Halted {
+		batch := rollout.Batches[rollout.CurrentBatch]
+
+		succeeded, failed := 0, 0
+		for _, endpointID := range batch {
+			switch {
+			case hasStatus(stack.Status[endpointID], portainer.EdgeStackStatusError):
+				failed++
+			case hasStatus(stack.Status[endpointID], portainer.EdgeStackStatusRunning):
+				succeeded++
+			}
+		}
+
+		if failed*100 > errorThreshold*len(batch) {
+			rollout.Halted = true
+
+			log.Warn().
+				Int("stack_id", int(stack.ID)).
+				Int("batch", rollout.CurrentBatch).
+				Int("failed", failed).
+				Msg("halting the edge stack rollout, the error threshold was exceeded")
+
+			return true
+		}
+
+		if succeeded+failed < len(batch) {
+			return changed
+		}
+
+		changed = true
+
+		if rollout.CurrentBatch >= len(rollout.Batches)-1 {
+			rollout.Completed = true
+		} else {
+			rollout.CurrentBatch++
+		}
+	}
+
+	return changed
+}
+
+func hasStatus(status portainer.EdgeStackStatus, statusType portainer.EdgeStackStatusType) bool {
+	return slices.ContainsFunc(status.Status, func(s portainer.EdgeStackDeploymentStatus) bool {
+		return s.Type == statusType
+	})
+}
+
+// ProgressRollouts advances the staged rollouts of every edge stack, it is meant to be run periodically
+func (service *Service) ProgressRollouts() error {
+	return service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		stacks, err := tx.EdgeStack().EdgeStacks()
+		if err != nil {
+			return err
+		}
+
+		for i := range stacks {
+			stack := &stacks[i]
+			if !ProgressRollout(stack) {
+				continue
+			}
+
+			if err := tx.EdgeStack().UpdateEdgeStack(stack.ID, stack); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
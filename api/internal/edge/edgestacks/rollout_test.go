@@ -0,0 +1,129 @@
+package edgestacks
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/edge"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewRollout_Percentage(t *testing.T) {
+	stack := &portainer.EdgeStack{
+		Version:       2,
+		RolloutPolicy: &portainer.EdgeStackRolloutPolicy{Percentage: 40},
+	}
+
+	rollout, err := NewRollout(stack, 1, []portainer.EndpointID{5, 4, 3, 2, 1}, &edge.EndpointRelationsConfig{})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]portainer.EndpointID{{1, 2}, {3, 4}, {5}}, rollout.Batches)
+	assert.Equal(t, 2, rollout.Version)
+	assert.Equal(t, 1, rollout.PreviousVersion)
+	assert.False(t, rollout.Completed)
+}
+
+func Test_NewRollout_EdgeGroupBatches(t *testing.T) {
+	stack := &portainer.EdgeStack{
+		Version: 2,
+		RolloutPolicy: &portainer.EdgeStackRolloutPolicy{
+			Batches: [][]portainer.EdgeGroupID{{1}, {2}},
+		},
+	}
+
+	relationConfig := &edge.EndpointRelationsConfig{
+		EdgeGroups: []portainer.EdgeGroup{
+			{ID: 1, Endpoints: []portainer.EndpointID{3}},
+			{ID: 2, Endpoints: []portainer.EndpointID{1, 3}},
+		},
+	}
+
+	rollout, err := NewRollout(stack, 1, []portainer.EndpointID{1, 2, 3}, relationConfig)
+	require.NoError(t, err)
+
+	// environments not part of any batch are rolled out last
+	assert.Equal(t, [][]portainer.EndpointID{{3}, {1}, {2}}, rollout.Batches)
+}
+
+func Test_ProgressRollout(t *testing.T) {
+	running := portainer.EdgeStackStatus{Status: []portainer.EdgeStackDeploymentStatus{{Type: portainer.EdgeStackStatusRunning}}}
+	failed := portainer.EdgeStackStatus{Status: []portainer.EdgeStackDeploymentStatus{{Type: portainer.EdgeStackStatusError}}}
+
+	newStack := func() *portainer.EdgeStack {
+		return &portainer.EdgeStack{
+			Version:       2,
+			RolloutPolicy: &portainer.EdgeStackRolloutPolicy{Percentage: 50},
+			Status:        map[portainer.EndpointID]portainer.EdgeStackStatus{},
+			Rollout: &portainer.EdgeStackRollout{
+				Version:         2,
+				PreviousVersion: 1,
+				Batches:         [][]portainer.EndpointID{{1, 2}, {3, 4}},
+			},
+		}
+	}
+
+	t.Run("waits for the current batch", func(t *testing.T) {
+		stack := newStack()
+		stack.Status[1] = running
+
+		assert.False(t, ProgressRollout(stack))
+		assert.Equal(t, 0, stack.Rollout.CurrentBatch)
+		assert.Equal(t, 1, stack.Rollout.EndpointVersion(stack.Version, 3))
+	})
+
+	t.Run("moves to the next batch", func(t *testing.T) {
+		stack := newStack()
+		stack.Status[1] = running
+		stack.Status[2] = running
+
+		assert.True(t, ProgressRollout(stack))
+		assert.Equal(t, 1, stack.Rollout.CurrentBatch)
+		assert.False(t, stack.Rollout.Completed)
+		assert.Equal(t, 2, stack.Rollout.EndpointVersion(stack.Version, 3))
+	})
+
+	t.Run("completes once the last batch is deployed", func(t *testing.T) {
+		stack := newStack()
+		for _, id := range []portainer.EndpointID{1, 2, 3, 4} {
+			stack.Status[id] = running
+		}
+
+		assert.True(t, ProgressRollout(stack))
+		assert.Equal(t, 1, stack.Rollout.CurrentBatch)
+		assert.True(t, stack.Rollout.Completed)
+		assert.False(t, ProgressRollout(stack))
+	})
+
+	t.Run("halts above the error threshold", func(t *testing.T) {
+		stack := newStack()
+		stack.Status[1] = running
+		stack.Status[2] = failed
+
+		assert.True(t, ProgressRollout(stack))
+		assert.True(t, stack.Rollout.Halted)
+		assert.Equal(t, 1, stack.Rollout.EndpointVersion(stack.Version, 3))
+	})
+
+	t.Run("tolerates errors below the threshold", func(t *testing.T) {
+		stack := newStack()
+		stack.RolloutPolicy.ErrorThreshold = 50
+		stack.Status[1] = running
+		stack.Status[2] = failed
+
+		assert.True(t, ProgressRollout(stack))
+		assert.False(t, stack.Rollout.Halted)
+		assert.Equal(t, 1, stack.Rollout.CurrentBatch)
+	})
+}
+
+func Test_ValidateRolloutPolicy(t *testing.T) {
+	assert.NoError(t, ValidateRolloutPolicy(nil))
+	assert.NoError(t, ValidateRolloutPolicy(&portainer.EdgeStackRolloutPolicy{Percentage: 10}))
+	assert.NoError(t, ValidateRolloutPolicy(&portainer.EdgeStackRolloutPolicy{Batches: [][]portainer.EdgeGroupID{{1}}}))
+	assert.Error(t, ValidateRolloutPolicy(&portainer.EdgeStackRolloutPolicy{}))
+	assert.Error(t, ValidateRolloutPolicy(&portainer.EdgeStackRolloutPolicy{Percentage: 101}))
+	assert.Error(t, ValidateRolloutPolicy(&portainer.EdgeStackRolloutPolicy{Batches: [][]portainer.EdgeGroupID{{}}}))
+	assert.Error(t, ValidateRolloutPolicy(&portainer.EdgeStackRolloutPolicy{Percentage: 10, ErrorThreshold: -1}))
+}
@@ -41,6 +41,10 @@ func (service *Service) BuildEdgeStack(
 		return nil, err
 	}
 
+	if err := ValidateRegistries(tx, registries); err != nil {
+		return nil, err
+	}
+
 	stackID := tx.EdgeStack().GetNextIdentifier()
 
 	return &portainer.EdgeStack{
@@ -52,6 +56,7 @@ func (service *Service) BuildEdgeStack(
 		Status:                make(map[portainer.EndpointID]portainer.EdgeStackStatus, 0),
 		Version:               1,
 		UseManifestNamespaces: useManifestNamespaces,
+		Registries:            registries,
 	}, nil
 }
 
@@ -109,6 +114,10 @@ func (service *Service) PersistEdgeStack(
 		return nil, fmt.Errorf("unable to update endpoint relations: %w", err)
 	}
 
+	if err := edge.QueueCommands(tx, relatedEndpointIds, portainer.EdgeCommandResourceEdgeStack, int(stack.ID), portainer.EdgeCommandOperationAdd); err != nil {
+		return nil, err
+	}
+
 	return stack, nil
 }
 
@@ -161,5 +170,9 @@ func (service *Service) DeleteEdgeStack(tx dataservices.DataStoreTx, edgeStackID
 		return errors.WithMessage(err, "Unable to remove the edge stack from the database")
 	}
 
+	if err := edge.QueueCommands(tx, relatedEndpointIds, portainer.EdgeCommandResourceEdgeStack, int(edgeStackID), portainer.EdgeCommandOperationRemove); err != nil {
+		return errors.WithMessage(err, "Unable to queue the removal of the edge stack")
+	}
+
 	return nil
 }
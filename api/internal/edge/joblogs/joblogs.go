@@ -0,0 +1,162 @@
+package joblogs
+
+import (
+	"errors"
+	"io/fs"
+	"strconv"
+	"sync"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+// subscriptionBufferSize is the number of chunks a follower can lag behind before being dropped
+const subscriptionBufferSize = 64
+
+type taskKey struct {
+	edgeJobID portainer.EdgeJobID
+	taskID    portainer.EndpointID
+}
+
+// Streamer appends the output of the running Edge job tasks to their log files
+// and forwards it to the clients following the logs of the tasks
+type Streamer struct {
+	fileService portainer.FileService
+
+	mu          sync.Mutex
+	running     map[taskKey]bool
+	subscribers map[taskKey]map[*Subscription]struct{}
+}
+
+// Subscription receives the output of a running Edge job task.
+// C is closed once the task is over, or when the follower lags too far behind
+type Subscription struct {
+	C <-chan []byte
+
+	c        chan []byte
+	key      taskKey
+	streamer *Streamer
+}
+
+// NewStreamer returns a pointer to a new instance of Streamer
+func NewStreamer(fileService portainer.FileService) *Streamer {
+	return &Streamer{
+		fileService: fileService,
+		running:     make(map[taskKey]bool),
+		subscribers: make(map[taskKey]map[*Subscription]struct{}),
+	}
+}
+
+// Append appends a chunk of output to the log file of a task and forwards it to the followers of the task.
+// When completed is true, the task is considered over and the subscriptions are closed
+func (streamer *Streamer) Append(edgeJobID portainer.EdgeJobID, taskID portainer.EndpointID, data []byte, completed bool) error {
+	key := taskKey{edgeJobID: edgeJobID, taskID: taskID}
+
+	streamer.mu.Lock()
+	defer streamer.mu.Unlock()
+
+	if len(data) > 0 {
+		writer, err := streamer.fileService.OpenEdgeJobTaskLogWriter(strconv.Itoa(int(edgeJobID)), strconv.Itoa(int(taskID)))
+		if err != nil {
+			return err
+		}
+
+		_, err = writer.Write(data)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			return err
+		}
+
+		for subscription := range streamer.subscribers[key] {
+			select {
+			case subscription.c <- data:
+			default:
+				streamer.unsubscribe(subscription)
+			}
+		}
+	}
+
+	if completed {
+		streamer.complete(key)
+	} else {
+		streamer.running[key] = true
+	}
+
+	return nil
+}
+
+// Complete marks a task as over and closes the subscriptions to its output
+func (streamer *Streamer) Complete(edgeJobID portainer.EdgeJobID, taskID portainer.EndpointID) {
+	streamer.mu.Lock()
+	defer streamer.mu.Unlock()
+
+	streamer.complete(taskKey{edgeJobID: edgeJobID, taskID: taskID})
+}
+
+// IsRunning returns whether a chunk of the output of a task was received and the task is not over yet
+func (streamer *Streamer) IsRunning(edgeJobID portainer.EdgeJobID, taskID portainer.EndpointID) bool {
+	streamer.mu.Lock()
+	defer streamer.mu.Unlock()
+
+	return streamer.running[taskKey{edgeJobID: edgeJobID, taskID: taskID}]
+}
+
+// Follow returns the output of a task collected so far along with a subscription to the upcoming output.
+// No chunk is lost nor duplicated between the two. The subscription must be closed by the caller
+func (streamer *Streamer) Follow(edgeJobID portainer.EdgeJobID, taskID portainer.EndpointID) (string, *Subscription, error) {
+	key := taskKey{edgeJobID: edgeJobID, taskID: taskID}
+
+	streamer.mu.Lock()
+	defer streamer.mu.Unlock()
+
+	content, err := streamer.fileService.GetEdgeJobTaskLogFileContent(strconv.Itoa(int(edgeJobID)), strconv.Itoa(int(taskID)))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", nil, err
+	}
+
+	c := make(chan []byte, subscriptionBufferSize)
+	subscription := &Subscription{C: c, c: c, key: key, streamer: streamer}
+
+	if streamer.subscribers[key] == nil {
+		streamer.subscribers[key] = make(map[*Subscription]struct{})
+	}
+	streamer.subscribers[key][subscription] = struct{}{}
+
+	return content, subscription, nil
+}
+
+// Close stops the subscription
+func (subscription *Subscription) Close() {
+	subscription.streamer.mu.Lock()
+	defer subscription.streamer.mu.Unlock()
+
+	subscription.streamer.unsubscribe(subscription)
+}
+
+func (streamer *Streamer) complete(key taskKey) {
+	delete(streamer.running, key)
+
+	for subscription := range streamer.subscribers[key] {
+		streamer.unsubscribe(subscription)
+	}
+}
+
+func (streamer *Streamer) unsubscribe(subscription *Subscription) {
+	subscriptions, ok := streamer.subscribers[subscription.key]
+	if !ok {
+		return
+	}
+
+	if _, ok := subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(subscriptions, subscription)
+	close(subscription.c)
+
+	if len(subscriptions) == 0 {
+		delete(streamer.subscribers, subscription.key)
+	}
+}
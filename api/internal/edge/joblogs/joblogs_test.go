@@ -0,0 +1,80 @@
+package joblogs
+
+import (
+	"testing"
+
+	"github.com/portainer/portainer/api/filesystem"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamer(t *testing.T) {
+	fileService, err := filesystem.NewService(t.TempDir(), "")
+	require.NoError(t, err)
+
+	streamer := NewStreamer(fileService)
+
+	require.NoError(t, streamer.Append(1, 2, []byte("first\n"), false))
+	assert.True(t, streamer.IsRunning(1, 2))
+
+	backlog, subscription, err := streamer.Follow(1, 2)
+	require.NoError(t, err)
+	defer subscription.Close()
+
+	assert.Equal(t, "first\n", backlog)
+
+	require.NoError(t, streamer.Append(1, 2, []byte("second\n"), false))
+	assert.Equal(t, []byte("second\n"), <-subscription.C)
+
+	require.NoError(t, streamer.Append(1, 2, []byte("third\n"), true))
+	assert.Equal(t, []byte("third\n"), <-subscription.C)
+
+	_, ok := <-subscription.C
+	assert.False(t, ok, "the subscription should be closed once the task is over")
+	assert.False(t, streamer.IsRunning(1, 2))
+
+	content, err := fileService.GetEdgeJobTaskLogFileContent("1", "2")
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\nthird\n", content)
+}
+
+func TestStreamer_FollowWithoutLogs(t *testing.T) {
+	fileService, err := filesystem.NewService(t.TempDir(), "")
+	require.NoError(t, err)
+
+	streamer := NewStreamer(fileService)
+
+	backlog, subscription, err := streamer.Follow(1, 2)
+	require.NoError(t, err)
+
+	assert.Empty(t, backlog)
+
+	subscription.Close()
+	subscription.Close()
+
+	_, ok := <-subscription.C
+	assert.False(t, ok)
+}
+
+func TestStreamer_DropsSlowFollowers(t *testing.T) {
+	fileService, err := filesystem.NewService(t.TempDir(), "")
+	require.NoError(t, err)
+
+	streamer := NewStreamer(fileService)
+
+	_, subscription, err := streamer.Follow(1, 2)
+	require.NoError(t, err)
+	defer subscription.Close()
+
+	for range subscriptionBufferSize + 1 {
+		require.NoError(t, streamer.Append(1, 2, []byte("line\n"), false))
+	}
+
+	received := 0
+	for range subscription.C {
+		received++
+	}
+
+	assert.Equal(t, subscriptionBufferSize, received)
+}
@@ -0,0 +1,68 @@
+package edge
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+const (
+	// JoinTokenPrefix is the prefix of the Edge join tokens, it makes them easy to recognize
+	JoinTokenPrefix = "ejt_"
+	// JoinTokenDefaultTTL is how long an Edge join token is valid when no validity is specified
+	JoinTokenDefaultTTL = 24 * time.Hour
+	// JoinTokenMaxTTL is the longest validity of an Edge join token
+	JoinTokenMaxTTL = 30 * 24 * time.Hour
+
+	joinTokenDisplayedPrefixLength = 8
+)
+
+// ErrInvalidJoinToken is returned when an Edge join token does not exist, expired or was already used
+var ErrInvalidJoinToken = errors.New("invalid, expired or already used join token")
+
+// NewJoinToken generates a new Edge join token, it returns the raw token, to hand to the device, along with the
+// token object to persist which only holds its digest
+func NewJoinToken(now time.Time, ttl time.Duration) (string, *portainer.EdgeJoinToken, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", nil, fmt.Errorf("unable to generate the join token: %w", err)
+	}
+
+	rawToken := JoinTokenPrefix + base64.RawURLEncoding.EncodeToString(random)
+
+	return rawToken, &portainer.EdgeJoinToken{
+		Prefix:    rawToken[:joinTokenDisplayedPrefixLength],
+		Digest:    JoinTokenDigest(rawToken),
+		CreatedAt: now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}, nil
+}
+
+// JoinTokenDigest returns the digest under which an Edge join token is stored
+func JoinTokenDigest(rawToken string) string {
+	digest := sha256.Sum256([]byte(rawToken))
+
+	return base64.StdEncoding.EncodeToString(digest[:])
+}
+
+// FindUsableJoinToken returns the Edge join token when it exists, did not expire and was not used yet
+func FindUsableJoinToken(tx dataservices.DataStoreTx, rawToken string, now time.Time) (*portainer.EdgeJoinToken, error) {
+	token, err := tx.EdgeJoinToken().ReadByDigest(JoinTokenDigest(rawToken))
+	if tx.IsErrObjectNotFound(err) {
+		return nil, ErrInvalidJoinToken
+	} else if err != nil {
+		return nil, err
+	}
+
+	if token.UsedAt != 0 || token.ExpiresAt <= now.Unix() {
+		return nil, ErrInvalidJoinToken
+	}
+
+	return token, nil
+}
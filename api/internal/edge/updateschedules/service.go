@@ -0,0 +1,443 @@
+package updateschedules
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/edge"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// ProgressInterval is the interval at which the update schedules are checked for progress
+	ProgressInterval = 30 * time.Second
+	// UpdateTimeout is the time given to an environment to report the new version of its agent
+	UpdateTimeout = 30 * time.Minute
+
+	// DefaultAgentImage is the image of the agent when the schedule does not define one
+	DefaultAgentImage = "portainer/agent"
+	// DefaultUpdaterImage is the image of the updater when the schedule does not define one
+	DefaultUpdaterImage = "portainer/portainer-updater:latest"
+
+	composeFileName = "docker-compose.yml"
+)
+
+// The updater replaces the container of the agent with the image it receives, it exits without changes when
+// the agent already runs this image so that a redeployment of the stack does not restart the agent
+const composeFileTemplate = `services:
+  updater:
+    image: %s
+    command: ["agent-update"]
+    environment:
+      - AGENT_IMAGE
+      - UPDATE_ID
+    volumes:
+      - /var/run/docker.sock:/var/run/docker.sock
+`
+
+// Service represents a service rolling out the updates of the Edge agents
+type Service struct {
+	dataStore   dataservices.DataStore
+	fileService portainer.FileService
+}
+
+// NewService returns a new instance of a service.
+func NewService(dataStore dataservices.DataStore, fileService portainer.FileService) *Service {
+	return &Service{
+		dataStore:   dataStore,
+		fileService: fileService,
+	}
+}
+
+// Create persists the schedule along with the edge stack running the updater, the environments are released
+// for their update by the next progress of the schedule
+func (service *Service) Create(tx dataservices.DataStoreTx, schedule *portainer.EdgeUpdateSchedule) error {
+	stackID := portainer.EdgeStackID(tx.EdgeStack().GetNextIdentifier())
+	stackFolder := strconv.Itoa(int(stackID))
+
+	projectPath, err := service.fileService.StoreEdgeStackFileFromBytes(stackFolder, composeFileName, []byte(fmt.Sprintf(composeFileTemplate, schedule.UpdaterImage)))
+	if err != nil {
+		return fmt.Errorf("unable to store the stack file of the updater: %w", err)
+	}
+
+	schedule.EdgeStackID = stackID
+	schedule.Status = make(map[portainer.EndpointID]portainer.EdgeUpdateEndpointStatus)
+
+	if err := tx.EdgeUpdateSchedule().Create(schedule); err != nil {
+		return fmt.Errorf("unable to persist the edge update schedule: %w", err)
+	}
+
+	return tx.EdgeStack().Create(stackID, &portainer.EdgeStack{
+		Name:         fmt.Sprintf("edge-update-schedule-%d", schedule.ID),
+		CreationDate: time.Now().Unix(),
+		EdgeGroups:   []portainer.EdgeGroupID{},
+		Status:       make(map[portainer.EndpointID]portainer.EdgeStackStatus),
+		ProjectPath:  projectPath,
+		EntryPoint:   composeFileName,
+		Version:      1,
+		EdgeUpdateID: schedule.ID,
+	})
+}
+
+// Delete removes the schedule and its edge stack, the updater is removed from the environments being updated
+func (service *Service) Delete(tx dataservices.DataStoreTx, schedule *portainer.EdgeUpdateSchedule) error {
+	for endpointID, status := range schedule.Status {
+		if !inProgress(status.Status) {
+			continue
+		}
+
+		if err := releaseEndpoint(tx, schedule.EdgeStackID, endpointID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.EdgeStack().DeleteEdgeStack(schedule.EdgeStackID); err != nil && !tx.IsErrObjectNotFound(err) {
+		return fmt.Errorf("unable to remove the edge stack of the updater: %w", err)
+	}
+
+	if err := service.fileService.RemoveDirectory(service.fileService.GetEdgeStackProjectPath(strconv.Itoa(int(schedule.EdgeStackID)))); err != nil {
+		log.Warn().Err(err).Int("schedule_id", int(schedule.ID)).Msg("unable to remove the stack file of the updater")
+	}
+
+	return tx.EdgeUpdateSchedule().Delete(schedule.ID)
+}
+
+// ProgressSchedules advances the update schedules that are not completed, it is meant to be run periodically
+func (service *Service) ProgressSchedules() error {
+	return service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		schedules, err := tx.EdgeUpdateSchedule().ReadAll()
+		if err != nil {
+			return err
+		}
+
+		for i := range schedules {
+			if schedules[i].Completed {
+				continue
+			}
+
+			if err := Progress(tx, &schedules[i], time.Now()); err != nil {
+				log.Error().Err(err).Int("schedule_id", int(schedules[i].ID)).Msg("unable to progress the edge update schedule")
+			}
+		}
+
+		return nil
+	})
+}
+
+// Progress checks the environments being updated, retries or rolls back their failed updates and starts the
+// updates of the pending environments, within the concurrency of the schedule and its maintenance window
+func Progress(tx dataservices.DataStoreTx, schedule *portainer.EdgeUpdateSchedule, now time.Time) error {
+	stack, err := tx.EdgeStack().EdgeStack(schedule.EdgeStackID)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the edge stack of the updater: %w", err)
+	}
+
+	relationConfig, err := edge.FetchEndpointRelationsConfig(tx)
+	if err != nil {
+		return err
+	}
+
+	endpoints := make(map[portainer.EndpointID]*portainer.Endpoint, len(relationConfig.Endpoints))
+	for i := range relationConfig.Endpoints {
+		endpoints[relationConfig.Endpoints[i].ID] = &relationConfig.Endpoints[i]
+	}
+
+	for _, edgeGroup := range relationConfig.EdgeGroups {
+		if !slices.Contains(schedule.EdgeGroupIDs, edgeGroup.ID) {
+			continue
+		}
+
+		for _, endpointID := range edge.EdgeGroupRelatedEndpoints(&edgeGroup, relationConfig.Endpoints, relationConfig.EndpointGroups) {
+			// Only the agents running on Docker can be replaced by the updater
+			endpoint, ok := endpoints[endpointID]
+			if !ok || endpoint.Type != portainer.EdgeAgentOnDockerEnvironment {
+				continue
+			}
+
+			if _, ok := schedule.Status[endpointID]; ok {
+				continue
+			}
+
+			schedule.Status[endpointID] = portainer.EdgeUpdateEndpointStatus{
+				Status:          portainer.EdgeUpdateStatusPending,
+				PreviousVersion: endpoint.Agent.Version,
+				UpdatedAt:       now.Unix(),
+			}
+		}
+	}
+
+	endpointIDs := make([]portainer.EndpointID, 0, len(schedule.Status))
+	for endpointID := range schedule.Status {
+		endpointIDs = append(endpointIDs, endpointID)
+	}
+	slices.Sort(endpointIDs)
+
+	p := &progress{tx: tx, schedule: schedule, stack: stack, now: now}
+
+	inFlight := 0
+	for _, endpointID := range endpointIDs {
+		endpoint, ok := endpoints[endpointID]
+		if !ok {
+			delete(schedule.Status, endpointID)
+			continue
+		}
+
+		p.check(endpoint)
+
+		if inProgress(schedule.Status[endpointID].Status) {
+			inFlight++
+		}
+	}
+
+	if InMaintenanceWindow(schedule.MaintenanceWindow, now) {
+		for _, endpointID := range endpointIDs {
+			if inFlight >= schedule.Concurrency {
+				break
+			}
+
+			// A failed update is started again by the next progress, once the updater was removed
+			status, ok := schedule.Status[endpointID]
+			if !ok || status.Status != portainer.EdgeUpdateStatusPending || slices.Contains(p.released, endpointID) {
+				continue
+			}
+
+			if endpoints[endpointID].Agent.Version == schedule.AgentVersion {
+				p.setStatus(endpointID, portainer.EdgeUpdateStatusSucceeded, "")
+				continue
+			}
+
+			if status.Attempts == 0 {
+				status.PreviousVersion = endpoints[endpointID].Agent.Version
+			}
+
+			status.Attempts++
+			schedule.Status[endpointID] = status
+			p.setStatus(endpointID, portainer.EdgeUpdateStatusUpdating, status.Error)
+			p.deploy(endpointID)
+			inFlight++
+		}
+	}
+
+	schedule.Completed = len(schedule.Status) > 0
+	for _, status := range schedule.Status {
+		if inProgress(status.Status) || status.Status == portainer.EdgeUpdateStatusPending {
+			schedule.Completed = false
+		}
+	}
+
+	if err := p.apply(); err != nil {
+		return err
+	}
+
+	return tx.EdgeUpdateSchedule().Update(schedule.ID, schedule)
+}
+
+// StackEnvVars returns the variables given to the updater of the environment, the image of the agent is the one
+// of the previous version when the agent is rolled back
+func StackEnvVars(schedule *portainer.EdgeUpdateSchedule, endpointID portainer.EndpointID) []portainer.Pair {
+	version := schedule.AgentVersion
+	if status := schedule.Status[endpointID]; status.Status == portainer.EdgeUpdateStatusRollingBack {
+		version = status.PreviousVersion
+	}
+
+	return []portainer.Pair{
+		{Name: "AGENT_IMAGE", Value: schedule.AgentImage + ":" + version},
+		{Name: "UPDATE_ID", Value: strconv.Itoa(int(schedule.ID))},
+	}
+}
+
+func inProgress(status portainer.EdgeUpdateStatusType) bool {
+	return status == portainer.EdgeUpdateStatusUpdating || status == portainer.EdgeUpdateStatusRollingBack
+}
+
+// progress holds the changes made to the environments of a schedule during one of its progresses
+type progress struct {
+	tx       dataservices.DataStoreTx
+	schedule *portainer.EdgeUpdateSchedule
+	stack    *portainer.EdgeStack
+	now      time.Time
+	// Environments to which the updater is deployed again
+	deployed []portainer.EndpointID
+	// Environments whose update is over, the updater is removed from them
+	released []portainer.EndpointID
+}
+
+// check moves the environment to its next status once the updater reported its result or timed out
+func (p *progress) check(endpoint *portainer.Endpoint) {
+	status := p.schedule.Status[endpoint.ID]
+	if !inProgress(status.Status) {
+		return
+	}
+
+	stackStatus := p.stack.Status[endpoint.ID]
+	failed := hasStatus(stackStatus, portainer.EdgeStackStatusError)
+	timedOut := p.now.Sub(time.Unix(status.UpdatedAt, 0)) > UpdateTimeout
+
+	switch status.Status {
+	case portainer.EdgeUpdateStatusUpdating:
+		switch {
+		case endpoint.Agent.Version == p.schedule.AgentVersion:
+			p.setStatus(endpoint.ID, portainer.EdgeUpdateStatusSucceeded, "")
+			p.released = append(p.released, endpoint.ID)
+
+		case !failed && !timedOut:
+			// The updater is still running
+
+		case status.Attempts <= p.schedule.MaxRetries:
+			// The update is started again by the next progress of the schedule
+			p.setStatus(endpoint.ID, portainer.EdgeUpdateStatusPending, failureReason(stackStatus, timedOut))
+			p.released = append(p.released, endpoint.ID)
+
+		case status.PreviousVersion == "":
+			p.setStatus(endpoint.ID, portainer.EdgeUpdateStatusFailed, "the update failed and the previous version of the agent is unknown")
+			p.released = append(p.released, endpoint.ID)
+
+		default:
+			log.Warn().
+				Int("schedule_id", int(p.schedule.ID)).
+				Int("endpoint_id", int(endpoint.ID)).
+				Str("version", status.PreviousVersion).
+				Msg("rolling back the agent, its update failed")
+
+			p.setStatus(endpoint.ID, portainer.EdgeUpdateStatusRollingBack, failureReason(stackStatus, timedOut))
+			p.deploy(endpoint.ID)
+		}
+
+	case portainer.EdgeUpdateStatusRollingBack:
+		switch {
+		case endpoint.Agent.Version == status.PreviousVersion && deployed(stackStatus):
+			p.setStatus(endpoint.ID, portainer.EdgeUpdateStatusRolledBack, status.Error)
+			p.released = append(p.released, endpoint.ID)
+
+		case failed || timedOut:
+			p.setStatus(endpoint.ID, portainer.EdgeUpdateStatusFailed, "the rollback failed: "+failureReason(stackStatus, timedOut))
+			p.released = append(p.released, endpoint.ID)
+		}
+	}
+}
+
+func (p *progress) setStatus(endpointID portainer.EndpointID, statusType portainer.EdgeUpdateStatusType, reason string) {
+	status := p.schedule.Status[endpointID]
+	status.Status = statusType
+	status.Error = reason
+	status.UpdatedAt = p.now.Unix()
+
+	p.schedule.Status[endpointID] = status
+}
+
+// deploy resets the status of the updater on the environment so that its new result can be told apart
+func (p *progress) deploy(endpointID portainer.EndpointID) {
+	p.stack.Status[endpointID] = portainer.EdgeStackStatus{
+		Status:     []portainer.EdgeStackDeploymentStatus{},
+		EndpointID: endpointID,
+	}
+
+	p.deployed = append(p.deployed, endpointID)
+}
+
+// apply persists the edge stack of the updater and the relations of the environments
+func (p *progress) apply() error {
+	for _, endpointID := range p.released {
+		delete(p.stack.Status, endpointID)
+
+		if err := releaseEndpoint(p.tx, p.stack.ID, endpointID); err != nil {
+			return err
+		}
+	}
+
+	// A new version makes the agents that already received the updater run it again
+	if len(p.deployed) > 0 {
+		p.stack.Version++
+	}
+
+	if err := p.tx.EdgeStack().UpdateEdgeStack(p.stack.ID, p.stack); err != nil {
+		return fmt.Errorf("unable to persist the edge stack of the updater: %w", err)
+	}
+
+	// The relations of the environments being updated are restored when they were recomputed from the edge groups
+	for endpointID, status := range p.schedule.Status {
+		if !inProgress(status.Status) {
+			continue
+		}
+
+		relation, err := p.tx.EndpointRelation().EndpointRelation(endpointID)
+		if err != nil {
+			return fmt.Errorf("unable to find the relations of environment %d: %w", endpointID, err)
+		}
+
+		operation := portainer.EdgeCommandOperationAdd
+		if relation.EdgeStacks[p.stack.ID] {
+			if !slices.Contains(p.deployed, endpointID) {
+				continue
+			}
+
+			operation = portainer.EdgeCommandOperationUpdate
+		}
+
+		relation.EdgeStacks[p.stack.ID] = true
+
+		if err := p.tx.EndpointRelation().UpdateEndpointRelation(endpointID, relation); err != nil {
+			return fmt.Errorf("unable to persist the relations of environment %d: %w", endpointID, err)
+		}
+
+		if err := edge.QueueCommands(p.tx, []portainer.EndpointID{endpointID}, portainer.EdgeCommandResourceEdgeStack, int(p.stack.ID), operation); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseEndpoint removes the updater from the environment
+func releaseEndpoint(tx dataservices.DataStoreTx, stackID portainer.EdgeStackID, endpointID portainer.EndpointID) error {
+	relation, err := tx.EndpointRelation().EndpointRelation(endpointID)
+	if tx.IsErrObjectNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to find the relations of environment %d: %w", endpointID, err)
+	}
+
+	if !relation.EdgeStacks[stackID] {
+		return nil
+	}
+
+	delete(relation.EdgeStacks, stackID)
+
+	if err := tx.EndpointRelation().UpdateEndpointRelation(endpointID, relation); err != nil {
+		return fmt.Errorf("unable to persist the relations of environment %d: %w", endpointID, err)
+	}
+
+	return edge.QueueCommands(tx, []portainer.EndpointID{endpointID}, portainer.EdgeCommandResourceEdgeStack, int(stackID), portainer.EdgeCommandOperationRemove)
+}
+
+func hasStatus(status portainer.EdgeStackStatus, statusType portainer.EdgeStackStatusType) bool {
+	return slices.ContainsFunc(status.Status, func(s portainer.EdgeStackDeploymentStatus) bool {
+		return s.Type == statusType
+	})
+}
+
+// deployed returns true when the updater ran on the environment
+func deployed(status portainer.EdgeStackStatus) bool {
+	return hasStatus(status, portainer.EdgeStackStatusRunning) ||
+		hasStatus(status, portainer.EdgeStackStatusCompleted) ||
+		hasStatus(status, portainer.EdgeStackStatusRemoteUpdateSuccess)
+}
+
+func failureReason(status portainer.EdgeStackStatus, timedOut bool) string {
+	if timedOut {
+		return "the agent did not report its new version in time"
+	}
+
+	for _, s := range status.Status {
+		if s.Type == portainer.EdgeStackStatusError && s.Error != "" {
+			return s.Error
+		}
+	}
+
+	return "the updater failed"
+}
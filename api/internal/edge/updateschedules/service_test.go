@@ -0,0 +1,168 @@
+package updateschedules
+
+import (
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/filesystem"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEndpoint(id portainer.EndpointID, version string) *portainer.Endpoint {
+	endpoint := &portainer.Endpoint{ID: id, Type: portainer.EdgeAgentOnDockerEnvironment}
+	endpoint.Agent.Version = version
+
+	return endpoint
+}
+
+func TestProgress(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	fileService, err := filesystem.NewService(t.TempDir(), "")
+	require.NoError(t, err)
+
+	for id, version := range map[portainer.EndpointID]string{1: "2.20.0", 2: "2.20.0", 3: "2.21.0"} {
+		require.NoError(t, store.Endpoint().Create(newEndpoint(id, version)))
+		require.NoError(t, store.EndpointRelation().Create(&portainer.EndpointRelation{EndpointID: id, EdgeStacks: map[portainer.EdgeStackID]bool{}}))
+	}
+
+	// The agents running on Kubernetes are left out
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 4, Type: portainer.EdgeAgentOnKubernetesEnvironment}))
+
+	edgeGroup := &portainer.EdgeGroup{Name: "stores", Endpoints: []portainer.EndpointID{1, 2, 3, 4}}
+	require.NoError(t, store.EdgeGroup().Create(edgeGroup))
+
+	service := NewService(store, fileService)
+
+	schedule := &portainer.EdgeUpdateSchedule{
+		Name:         "agents-2.21",
+		EdgeGroupIDs: []portainer.EdgeGroupID{edgeGroup.ID},
+		AgentVersion: "2.21.0",
+		AgentImage:   DefaultAgentImage,
+		UpdaterImage: DefaultUpdaterImage,
+		Concurrency:  1,
+		MaxRetries:   1,
+	}
+	require.NoError(t, store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return service.Create(tx, schedule)
+	}))
+
+	now := time.Now()
+	progress := func() {
+		t.Helper()
+
+		require.NoError(t, store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+			return Progress(tx, schedule, now)
+		}))
+	}
+
+	statusOf := func(endpointID portainer.EndpointID) portainer.EdgeUpdateStatusType {
+		return schedule.Status[endpointID].Status
+	}
+
+	related := func(endpointID portainer.EndpointID) bool {
+		relation, err := store.EndpointRelation().EndpointRelation(endpointID)
+		require.NoError(t, err)
+
+		return relation.EdgeStacks[schedule.EdgeStackID]
+	}
+
+	reportStack := func(endpointID portainer.EndpointID, statusType portainer.EdgeStackStatusType) {
+		stack, err := store.EdgeStack().EdgeStack(schedule.EdgeStackID)
+		require.NoError(t, err)
+
+		stack.Status[endpointID] = portainer.EdgeStackStatus{
+			EndpointID: endpointID,
+			Status:     []portainer.EdgeStackDeploymentStatus{{Type: statusType, Error: "unable to pull the image"}},
+		}
+		require.NoError(t, store.EdgeStack().UpdateEdgeStack(stack.ID, stack))
+	}
+
+	// Only one environment is updated at a time
+	progress()
+	assert.Equal(t, portainer.EdgeUpdateStatusUpdating, statusOf(1))
+	assert.Equal(t, portainer.EdgeUpdateStatusPending, statusOf(2))
+	assert.NotContains(t, schedule.Status, portainer.EndpointID(4))
+	assert.True(t, related(1))
+	assert.Equal(t, "portainer/agent:2.21.0", StackEnvVars(schedule, 1)[0].Value)
+
+	// The failed update is retried once the updater was removed
+	reportStack(1, portainer.EdgeStackStatusError)
+	progress()
+	assert.Equal(t, portainer.EdgeUpdateStatusPending, statusOf(1))
+	assert.Equal(t, "unable to pull the image", schedule.Status[1].Error)
+	assert.False(t, related(1))
+	assert.Equal(t, portainer.EdgeUpdateStatusUpdating, statusOf(2))
+
+	endpoint, err := store.Endpoint().Endpoint(2)
+	require.NoError(t, err)
+	endpoint.Agent.Version = "2.21.0"
+	require.NoError(t, store.Endpoint().UpdateEndpoint(2, endpoint))
+
+	progress()
+	assert.Equal(t, portainer.EdgeUpdateStatusSucceeded, statusOf(2))
+	assert.False(t, related(2))
+	assert.Equal(t, portainer.EdgeUpdateStatusUpdating, statusOf(1))
+	assert.Equal(t, 2, schedule.Status[1].Attempts)
+
+	// The agent is rolled back once the retries are exhausted
+	reportStack(1, portainer.EdgeStackStatusError)
+	progress()
+	assert.Equal(t, portainer.EdgeUpdateStatusRollingBack, statusOf(1))
+	assert.True(t, related(1))
+	assert.Equal(t, "portainer/agent:2.20.0", StackEnvVars(schedule, 1)[0].Value)
+	assert.False(t, schedule.Completed)
+
+	reportStack(1, portainer.EdgeStackStatusRunning)
+	progress()
+	assert.Equal(t, portainer.EdgeUpdateStatusRolledBack, statusOf(1))
+	assert.False(t, related(1))
+	// The agent already running the version is not updated
+	assert.Equal(t, portainer.EdgeUpdateStatusSucceeded, statusOf(3))
+	assert.True(t, schedule.Completed)
+}
+
+func TestProgressOutsideMaintenanceWindow(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	fileService, err := filesystem.NewService(t.TempDir(), "")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Endpoint().Create(newEndpoint(1, "2.20.0")))
+	require.NoError(t, store.EndpointRelation().Create(&portainer.EndpointRelation{EndpointID: 1, EdgeStacks: map[portainer.EdgeStackID]bool{}}))
+
+	edgeGroup := &portainer.EdgeGroup{Name: "stores", Endpoints: []portainer.EndpointID{1}}
+	require.NoError(t, store.EdgeGroup().Create(edgeGroup))
+
+	now := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	schedule := &portainer.EdgeUpdateSchedule{
+		EdgeGroupIDs:      []portainer.EdgeGroupID{edgeGroup.ID},
+		AgentVersion:      "2.21.0",
+		AgentImage:        DefaultAgentImage,
+		UpdaterImage:      DefaultUpdaterImage,
+		MaintenanceWindow: &portainer.EdgeUpdateMaintenanceWindow{Start: "02:00", Duration: 60},
+		Concurrency:       1,
+	}
+
+	require.NoError(t, store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if err := NewService(store, fileService).Create(tx, schedule); err != nil {
+			return err
+		}
+
+		return Progress(tx, schedule, now)
+	}))
+
+	assert.Equal(t, portainer.EdgeUpdateStatusPending, schedule.Status[1].Status)
+
+	require.NoError(t, store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return Progress(tx, schedule, now.Add(14*time.Hour+30*time.Minute))
+	}))
+
+	assert.Equal(t, portainer.EdgeUpdateStatusUpdating, schedule.Status[1].Status)
+}
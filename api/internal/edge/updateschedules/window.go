@@ -0,0 +1,74 @@
+package updateschedules
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+// ValidateMaintenanceWindow checks that a maintenance window can be applied
+func ValidateMaintenanceWindow(window *portainer.EdgeUpdateMaintenanceWindow) error {
+	if window == nil {
+		return nil
+	}
+
+	if _, err := time.Parse("15:04", window.Start); err != nil {
+		return errors.New("maintenance window start must be in the HH:MM format")
+	}
+
+	if window.Duration <= 0 || window.Duration > 7*24*60 {
+		return errors.New("maintenance window duration must be between 1 minute and 7 days")
+	}
+
+	for _, weekday := range window.Weekdays {
+		if weekday < time.Sunday || weekday > time.Saturday {
+			return fmt.Errorf("invalid maintenance window weekday %d, it must be between 0 (Sunday) and 6", weekday)
+		}
+	}
+
+	if _, err := time.LoadLocation(window.TimeZone); err != nil {
+		return fmt.Errorf("invalid maintenance window time zone %q", window.TimeZone)
+	}
+
+	return nil
+}
+
+// InMaintenanceWindow returns true when new updates can be started at the given time.
+// A window opening on one day and closing on the next one stays open past midnight
+func InMaintenanceWindow(window *portainer.EdgeUpdateMaintenanceWindow, now time.Time) bool {
+	if window == nil {
+		return true
+	}
+
+	location, err := time.LoadLocation(window.TimeZone)
+	if err != nil {
+		return false
+	}
+
+	start, err := time.Parse("15:04", window.Start)
+	if err != nil {
+		return false
+	}
+
+	now = now.In(location)
+	duration := time.Duration(window.Duration) * time.Minute
+
+	// The window can still be open from one of the previous days
+	for days := 0; days <= int(duration/(24*time.Hour))+1; days++ {
+		day := now.AddDate(0, 0, -days)
+		opening := time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, location)
+
+		if len(window.Weekdays) > 0 && !slices.Contains(window.Weekdays, opening.Weekday()) {
+			continue
+		}
+
+		if !now.Before(opening) && now.Before(opening.Add(duration)) {
+			return true
+		}
+	}
+
+	return false
+}
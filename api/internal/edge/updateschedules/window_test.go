@@ -0,0 +1,45 @@
+package updateschedules
+
+import (
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInMaintenanceWindow(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+
+	window := &portainer.EdgeUpdateMaintenanceWindow{
+		Weekdays: []time.Weekday{time.Saturday},
+		Start:    "22:00",
+		Duration: 240,
+		TimeZone: "Europe/Paris",
+	}
+
+	// 2024-03-02 is a Saturday
+	assert.True(t, InMaintenanceWindow(nil, time.Date(2024, 3, 2, 12, 0, 0, 0, paris)))
+	assert.False(t, InMaintenanceWindow(window, time.Date(2024, 3, 2, 21, 59, 0, 0, paris)))
+	assert.True(t, InMaintenanceWindow(window, time.Date(2024, 3, 2, 22, 0, 0, 0, paris)))
+	// The window stays open past midnight
+	assert.True(t, InMaintenanceWindow(window, time.Date(2024, 3, 3, 1, 30, 0, 0, paris)))
+	assert.False(t, InMaintenanceWindow(window, time.Date(2024, 3, 3, 2, 0, 0, 0, paris)))
+	assert.False(t, InMaintenanceWindow(window, time.Date(2024, 3, 3, 23, 0, 0, 0, paris)))
+	// The time zone of the window applies
+	assert.True(t, InMaintenanceWindow(window, time.Date(2024, 3, 2, 21, 30, 0, 0, time.UTC)))
+}
+
+func TestValidateMaintenanceWindow(t *testing.T) {
+	assert.NoError(t, ValidateMaintenanceWindow(nil))
+	assert.NoError(t, ValidateMaintenanceWindow(&portainer.EdgeUpdateMaintenanceWindow{Start: "02:00", Duration: 60}))
+
+	assert.Error(t, ValidateMaintenanceWindow(&portainer.EdgeUpdateMaintenanceWindow{Start: "2am", Duration: 60}))
+	assert.Error(t, ValidateMaintenanceWindow(&portainer.EdgeUpdateMaintenanceWindow{Start: "02:00"}))
+	assert.Error(t, ValidateMaintenanceWindow(&portainer.EdgeUpdateMaintenanceWindow{Start: "02:00", Duration: 60, Weekdays: []time.Weekday{7}}))
+	assert.Error(t, ValidateMaintenanceWindow(&portainer.EdgeUpdateMaintenanceWindow{Start: "02:00", Duration: 60, TimeZone: "Mars/Olympus"}))
+}
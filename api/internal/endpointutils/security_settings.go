@@ -0,0 +1,84 @@
+package endpointutils
+
+import (
+	"reflect"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// SecuritySettingsLocked returns true when the group enforces its default security settings, the security
+// settings of its environments(endpoints) cannot be changed then
+func SecuritySettingsLocked(group *portainer.EndpointGroup) bool {
+	return group != nil && group.SecuritySettings != nil && group.EnforceSecuritySettings
+}
+
+// ApplyGroupSecuritySettings replaces the security settings of the environment(endpoint) by the default security
+// settings of its group, unless the environment overrides them and the group does not enforce them.
+// It returns true when the environment was changed
+func ApplyGroupSecuritySettings(endpoint *portainer.Endpoint, group *portainer.EndpointGroup) bool {
+	if group == nil || group.SecuritySettings == nil {
+		return false
+	}
+
+	if endpoint.SecuritySettingsOverridden && !group.EnforceSecuritySettings {
+		return false
+	}
+
+	changed := endpoint.SecuritySettingsOverridden
+	endpoint.SecuritySettingsOverridden = false
+
+	if reflect.DeepEqual(endpoint.SecuritySettings, *group.SecuritySettings) {
+		return changed
+	}
+
+	endpoint.SecuritySettings = *group.SecuritySettings
+	endpoint.SecuritySettings.DeniedOperationsForRegularUsers = slices.Clone(group.SecuritySettings.DeniedOperationsForRegularUsers)
+	endpoint.SecuritySettings.BlockedBindMountPrefixes = slices.Clone(group.SecuritySettings.BlockedBindMountPrefixes)
+
+	return true
+}
+
+// CreateEndpoint saves a new environment(endpoint) with the default security settings, the security settings
+// of its group are applied when they are defined, and adds the environment to its tags
+func CreateEndpoint(tx dataservices.DataStoreTx, endpoint *portainer.Endpoint) error {
+	endpoint.SecuritySettings = portainer.EndpointSecuritySettings{
+		AllowVolumeBrowserForRegularUsers: false,
+		EnableHostManagementFeatures:      false,
+
+		AllowSysctlSettingForRegularUsers:         true,
+		AllowBindMountsForRegularUsers:            true,
+		AllowPrivilegedModeForRegularUsers:        true,
+		AllowHostNamespaceForRegularUsers:         true,
+		AllowContainerCapabilitiesForRegularUsers: true,
+		AllowDeviceMappingForRegularUsers:         true,
+		AllowStackManagementForRegularUsers:       true,
+	}
+
+	endpointGroup, err := tx.EndpointGroup().Read(endpoint.GroupID)
+	if err != nil && !tx.IsErrObjectNotFound(err) {
+		return err
+	}
+
+	ApplyGroupSecuritySettings(endpoint, endpointGroup)
+
+	if err := tx.Endpoint().Create(endpoint); err != nil {
+		return err
+	}
+
+	for _, tagID := range endpoint.TagIDs {
+		tag, err := tx.Tag().Read(tagID)
+		if err != nil {
+			return err
+		}
+
+		tag.Endpoints[endpoint.ID] = true
+
+		if err := tx.Tag().Update(tagID, tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package endpointutils
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyGroupSecuritySettings(t *testing.T) {
+	defaults := &portainer.EndpointSecuritySettings{
+		AllowStackManagementForRegularUsers: true,
+		DeniedOperationsForRegularUsers:     []portainer.Authorization{portainer.OperationDockerImageBuild},
+	}
+
+	group := &portainer.EndpointGroup{ID: 2, SecuritySettings: defaults}
+
+	// An environment inheriting the settings of its group receives the defaults
+	inheriting := &portainer.Endpoint{GroupID: 2, SecuritySettings: portainer.EndpointSecuritySettings{AllowBindMountsForRegularUsers: true}}
+	assert.True(t, ApplyGroupSecuritySettings(inheriting, group))
+	assert.Equal(t, *defaults, inheriting.SecuritySettings)
+	assert.False(t, ApplyGroupSecuritySettings(inheriting, group))
+
+	// An environment overriding its settings keeps them until the group enforces its defaults
+	overriding := &portainer.Endpoint{GroupID: 2, SecuritySettingsOverridden: true}
+	assert.False(t, ApplyGroupSecuritySettings(overriding, group))
+	assert.False(t, overriding.SecuritySettings.AllowStackManagementForRegularUsers)
+
+	group.EnforceSecuritySettings = true
+	assert.True(t, SecuritySettingsLocked(group))
+	assert.True(t, ApplyGroupSecuritySettings(overriding, group))
+	assert.Equal(t, *defaults, overriding.SecuritySettings)
+	assert.False(t, overriding.SecuritySettingsOverridden)
+
+	// A group without defaults leaves the settings of its environments untouched
+	assert.False(t, ApplyGroupSecuritySettings(&portainer.Endpoint{}, &portainer.EndpointGroup{EnforceSecuritySettings: true}))
+	assert.False(t, SecuritySettingsLocked(&portainer.EndpointGroup{EnforceSecuritySettings: true}))
+}
@@ -0,0 +1,89 @@
+// Package events carries the system events, such as an environment going down, from the subsystems raising
+// them to the services acting on them.
+package events
+
+import (
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Event represents a system event
+type Event struct {
+	Type portainer.NotificationEventType `json:"type"`
+	// Unix timestamp of the event
+	Time int64 `json:"time"`
+	// Environment(endpoint) concerned by the event, 0 when the event does not concern an environment
+	EndpointID   portainer.EndpointID `json:"endpointId,omitempty"`
+	EndpointName string               `json:"endpointName,omitempty"`
+	// Short description of the event
+	Title string `json:"title"`
+	// Details of the event
+	Message string `json:"message,omitempty"`
+}
+
+// Listener is notified of each event. It is called synchronously by the publisher and must not block.
+type Listener func(event Event)
+
+// Bus delivers the events to the listeners
+type Bus struct {
+	listenersMu sync.RWMutex
+	listeners   []Listener
+}
+
+// defaultBus is the bus used by the subsystems, most of them raise their events from functions that are not
+// tied to a service
+var defaultBus = New()
+
+// New returns a new instance of Bus
+func New() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a listener notified of the next events of the default bus
+func Subscribe(listener Listener) {
+	defaultBus.Subscribe(listener)
+}
+
+// Publish notifies the listeners of the default bus of an event
+func Publish(event Event) {
+	defaultBus.Publish(event)
+}
+
+// Subscribe registers a listener notified of the next events
+func (bus *Bus) Subscribe(listener Listener) {
+	bus.listenersMu.Lock()
+	defer bus.listenersMu.Unlock()
+
+	bus.listeners = append(bus.listeners, listener)
+}
+
+// Publish notifies the listeners of an event, the time of the event is set when missing
+func (bus *Bus) Publish(event Event) {
+	if event.Time == 0 {
+		event.Time = time.Now().Unix()
+	}
+
+	bus.listenersMu.RLock()
+	listeners := bus.listeners
+	bus.listenersMu.RUnlock()
+
+	for _, listener := range listeners {
+		notify(listener, event)
+	}
+}
+
+// notify isolates the listeners from each other so that a faulty one cannot prevent the others
+// from receiving the event
+func notify(listener Listener, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().Any("panic", r).Str("event", string(event.Type)).Msg("event listener failure")
+		}
+	}()
+
+	listener(event)
+}
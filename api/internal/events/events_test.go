@@ -0,0 +1,30 @@
+package events
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublish(t *testing.T) {
+	bus := New()
+
+	var received []Event
+	bus.Subscribe(func(event Event) {
+		panic("faulty listener")
+	})
+	bus.Subscribe(func(event Event) {
+		received = append(received, event)
+	})
+
+	bus.Publish(Event{Type: portainer.NotificationEventEndpointDown, EndpointID: 1})
+	bus.Publish(Event{Type: portainer.NotificationEventBackupFailed, Time: 1700000000})
+
+	// The faulty listener does not prevent the delivery to the other ones
+	assert.Len(t, received, 2)
+	assert.Equal(t, portainer.EndpointID(1), received[0].EndpointID)
+	assert.NotZero(t, received[0].Time)
+	assert.Equal(t, int64(1700000000), received[1].Time)
+}
@@ -0,0 +1,208 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	dockerclient "github.com/portainer/portainer/api/docker/client"
+	"github.com/portainer/portainer/api/stacks/deployments"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// CheckInterval is the interval at which the primary environments of the failover policies are checked
+	CheckInterval = 30 * time.Second
+
+	// maxEvents is the number of failover events kept on a policy
+	maxEvents = 20
+
+	pingTimeout = 10 * time.Second
+)
+
+// ErrFailedOver is returned when trying to fail over a policy that already failed over
+var ErrFailedOver = errors.New("the failover policy already failed over, it must be reset first")
+
+// Service watches the primary environments of the failover policies and redeploys
+// their stacks onto the backup environments when they stay unreachable
+type Service struct {
+	dataStore     dataservices.DataStore
+	stackDeployer deployments.StackDeployer
+	ping          func(ctx context.Context, endpoint *portainer.Endpoint) error
+
+	// mu prevents a policy from failing over twice when a manual failover races with the watcher
+	mu sync.Mutex
+}
+
+// NewService returns a new instance of a service
+func NewService(dataStore dataservices.DataStore, dockerClientFactory *dockerclient.ClientFactory, stackDeployer deployments.StackDeployer) *Service {
+	return &Service{
+		dataStore:     dataStore,
+		stackDeployer: stackDeployer,
+		ping: func(ctx context.Context, endpoint *portainer.Endpoint) error {
+			cli, err := dockerClientFactory.CreateClient(endpoint, "", nil)
+			if err != nil {
+				return err
+			}
+			defer cli.Close()
+
+			_, err = cli.Ping(ctx)
+
+			return err
+		},
+	}
+}
+
+// CheckPolicies checks the primary environment of every enabled failover policy and fails over
+// the policies whose primary environment has been down for longer than their threshold
+func (service *Service) CheckPolicies() error {
+	policies, err := service.dataStore.FailoverPolicy().ReadAll()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range policies {
+		if policies[i].Enabled && policies[i].Status == portainer.FailoverStatusWatching {
+			service.checkPolicy(&policies[i], now)
+		}
+	}
+
+	return nil
+}
+
+func (service *Service) checkPolicy(policy *portainer.FailoverPolicy, now time.Time) {
+	endpoint, err := service.dataStore.Endpoint().Endpoint(policy.PrimaryEndpointID)
+	if err != nil {
+		log.Warn().Err(err).Int("policy_id", int(policy.ID)).Msg("unable to retrieve the primary environment of the failover policy")
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	err = service.ping(ctx, endpoint)
+	cancel()
+
+	downSince := policy.DownSince
+	if err == nil {
+		downSince = 0
+	} else if downSince == 0 {
+		downSince = now.Unix()
+
+		log.Warn().Err(err).
+			Int("policy_id", int(policy.ID)).
+			Int("endpoint_id", int(endpoint.ID)).
+			Msg("primary environment of the failover policy is unreachable")
+	}
+
+	if downSince != policy.DownSince {
+		if err := service.updatePolicy(policy.ID, func(policy *portainer.FailoverPolicy) {
+			policy.DownSince = downSince
+		}); err != nil {
+			log.Warn().Err(err).Int("policy_id", int(policy.ID)).Msg("unable to update the failover policy")
+
+			return
+		}
+	}
+
+	if downSince == 0 || now.Unix()-downSince < int64(policy.DownThreshold) {
+		return
+	}
+
+	if err := service.Failover(policy.ID); err != nil {
+		log.Error().Err(err).Int("policy_id", int(policy.ID)).Msg("unable to fail over")
+	}
+}
+
+// Failover redeploys the stacks of a policy onto its backup environment and records the failover event.
+// The policy stops watching its primary environment until it is reset
+func (service *Service) Failover(policyID portainer.FailoverPolicyID) error {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	policy, err := service.dataStore.FailoverPolicy().Read(policyID)
+	if err != nil {
+		return err
+	}
+
+	if policy.Status == portainer.FailoverStatusFailedOver {
+		return ErrFailedOver
+	}
+
+	backup, err := service.dataStore.Endpoint().Endpoint(policy.BackupEndpointID)
+	if err != nil {
+		return err
+	}
+
+	event := portainer.FailoverEvent{
+		DownSince: policy.DownSince,
+		Stacks:    make([]portainer.FailoverStackResult, 0, len(policy.StackIDs)),
+	}
+
+	for _, stackID := range policy.StackIDs {
+		result := portainer.FailoverStackResult{StackID: stackID}
+
+		if err := service.redeployStack(policy, backup, &result); err != nil {
+			result.Error = err.Error()
+
+			log.Error().Err(err).
+				Int("policy_id", int(policy.ID)).
+				Int("stack_id", int(stackID)).
+				Int("endpoint_id", int(backup.ID)).
+				Msg("unable to redeploy the stack onto the backup environment")
+		} else {
+			result.Success = true
+		}
+
+		event.Stacks = append(event.Stacks, result)
+	}
+
+	event.Date = time.Now().Unix()
+
+	log.Info().
+		Int("policy_id", int(policy.ID)).
+		Int("primary_endpoint_id", int(policy.PrimaryEndpointID)).
+		Int("backup_endpoint_id", int(policy.BackupEndpointID)).
+		Msg("failed over to the backup environment")
+
+	return service.updatePolicy(policy.ID, func(p *portainer.FailoverPolicy) {
+		p.Status = portainer.FailoverStatusFailedOver
+		p.Events = append(p.Events, event)
+
+		if len(p.Events) > maxEvents {
+			p.Events = p.Events[len(p.Events)-maxEvents:]
+		}
+	})
+}
+
+func (service *Service) redeployStack(policy *portainer.FailoverPolicy, backup *portainer.Endpoint, result *portainer.FailoverStackResult) error {
+	stack, err := service.dataStore.Stack().Read(result.StackID)
+	if err != nil {
+		return err
+	}
+
+	result.StackName = stack.Name
+
+	if stack.EndpointID != policy.PrimaryEndpointID {
+		return errors.New("the stack is not deployed on the primary environment")
+	}
+
+	return deployments.RedeployOnEndpoint(stack, backup, service.stackDeployer, service.dataStore)
+}
+
+func (service *Service) updatePolicy(policyID portainer.FailoverPolicyID, updateFunc func(policy *portainer.FailoverPolicy)) error {
+	return service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		policy, err := tx.FailoverPolicy().Read(policyID)
+		if err != nil {
+			return err
+		}
+
+		updateFunc(policy)
+
+		return tx.FailoverPolicy().Update(policy.ID, policy)
+	})
+}
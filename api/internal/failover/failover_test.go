@@ -0,0 +1,96 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/stacks/deployments"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingDeployer struct {
+	deployments.StackDeployer
+	deployed map[portainer.StackID]portainer.EndpointID
+}
+
+func (d *recordingDeployer) DeployComposeStack(stack *portainer.Stack, endpoint *portainer.Endpoint, registries []portainer.Registry, forcePullImage bool, forceRecreate bool) error {
+	d.deployed[stack.ID] = endpoint.ID
+
+	return nil
+}
+
+func TestCheckPolicies(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1, Type: portainer.DockerEnvironment}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 2, Type: portainer.DockerEnvironment}))
+	require.NoError(t, store.User().Create(&portainer.User{Username: "admin", Role: portainer.AdministratorRole}))
+	require.NoError(t, store.Stack().Create(&portainer.Stack{ID: 1, Name: "web", Type: portainer.DockerComposeStack, EndpointID: 1, CreatedBy: "admin"}))
+
+	policy := &portainer.FailoverPolicy{
+		PrimaryEndpointID: 1,
+		BackupEndpointID:  2,
+		StackIDs:          []portainer.StackID{1},
+		DownThreshold:     60,
+		Enabled:           true,
+		Status:            portainer.FailoverStatusWatching,
+	}
+	require.NoError(t, store.FailoverPolicy().Create(policy))
+
+	deployer := &recordingDeployer{deployed: make(map[portainer.StackID]portainer.EndpointID)}
+
+	var pingErr error
+	service := &Service{
+		dataStore:     store,
+		stackDeployer: deployer,
+		ping: func(ctx context.Context, endpoint *portainer.Endpoint) error {
+			return pingErr
+		},
+	}
+
+	readPolicy := func() *portainer.FailoverPolicy {
+		policy, err := store.FailoverPolicy().Read(policy.ID)
+		require.NoError(t, err)
+
+		return policy
+	}
+
+	now := time.Now()
+
+	// The primary environment goes down, the outage starts
+	pingErr = errors.New("unreachable")
+	service.checkPolicy(readPolicy(), now)
+	assert.Equal(t, now.Unix(), readPolicy().DownSince)
+	assert.Empty(t, deployer.deployed)
+
+	// The primary environment comes back before the threshold
+	pingErr = nil
+	service.checkPolicy(readPolicy(), now.Add(30*time.Second))
+	assert.Zero(t, readPolicy().DownSince)
+
+	// The primary environment stays down past the threshold
+	pingErr = errors.New("unreachable")
+	service.checkPolicy(readPolicy(), now.Add(time.Minute))
+	service.checkPolicy(readPolicy(), now.Add(2*time.Minute+time.Second))
+
+	assert.Equal(t, map[portainer.StackID]portainer.EndpointID{1: 2}, deployer.deployed)
+
+	updated := readPolicy()
+	assert.Equal(t, portainer.FailoverStatusFailedOver, updated.Status)
+	require.Len(t, updated.Events, 1)
+	assert.Equal(t, now.Add(time.Minute).Unix(), updated.Events[0].DownSince)
+	assert.Equal(t, []portainer.FailoverStackResult{{StackID: 1, StackName: "web", Success: true}}, updated.Events[0].Stacks)
+
+	stack, err := store.Stack().Read(1)
+	require.NoError(t, err)
+	assert.Equal(t, portainer.EndpointID(2), stack.EndpointID)
+
+	// A policy that failed over cannot fail over again until it is reset
+	assert.ErrorIs(t, service.Failover(policy.ID), ErrFailedOver)
+}
@@ -0,0 +1,350 @@
+package gc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	dockerclient "github.com/portainer/portainer/api/docker/client"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/scheduler"
+	"github.com/portainer/portainer/api/stacks/deployments"
+	"github.com/portainer/portainer/api/stacks/stackutils"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultContainerAge is the time after which a stopped container is reported as inactive
+	DefaultContainerAge = 7 * 24 * time.Hour
+	// DefaultStackInactivity is the time after which a stopped stack is reported as inactive
+	DefaultStackInactivity = 30 * 24 * time.Hour
+
+	dockerClientTimeout = 30 * time.Second
+)
+
+// Options defines what is considered inactive
+type Options struct {
+	// Stopped containers are reported when they stopped before now minus ContainerAge
+	ContainerAge time.Duration
+	// Stopped stacks are reported when they were last deployed or updated before this time
+	StackInactiveSince time.Time
+}
+
+// Report represents the inactive resources of an environment(endpoint)
+type Report struct {
+	EndpointID   portainer.EndpointID `json:"EndpointId"`
+	EndpointName string               `json:"EndpointName"`
+	Containers   []InactiveContainer  `json:"Containers"`
+	Images       []UnusedImage        `json:"Images"`
+	Volumes      []UnusedVolume       `json:"Volumes"`
+	Stacks       []InactiveStack      `json:"Stacks"`
+	// Reason why the environment could not be inspected, only set in the fleet-wide reports
+	Error string `json:"Error,omitempty"`
+}
+
+// Selection represents the inactive resources to remove from an environment(endpoint)
+type Selection struct {
+	ContainerIDs []string
+	ImageIDs     []string
+	VolumeNames  []string
+	StackIDs     []portainer.StackID
+	// Remove all the reported resources, the other fields are ignored
+	All bool
+}
+
+// CleanupResult represents the outcome of the removal of an inactive resource
+type CleanupResult struct {
+	ResourceType string `json:"ResourceType" example:"container"`
+	ResourceID   string `json:"ResourceId"`
+	Removed      bool   `json:"Removed"`
+	Error        string `json:"Error,omitempty"`
+}
+
+// Service reports the inactive resources of the environments and removes them, it also runs the
+// prune policies of the environments
+type Service struct {
+	dataStore           dataservices.DataStore
+	dockerClientFactory *dockerclient.ClientFactory
+	fileService         portainer.FileService
+	scheduler           *scheduler.Scheduler
+
+	mu            sync.Mutex
+	prunePolicies map[portainer.EndpointID]string
+}
+
+// NewService returns a new instance of a service
+func NewService(dataStore dataservices.DataStore, dockerClientFactory *dockerclient.ClientFactory, fileService portainer.FileService, scheduler *scheduler.Scheduler) *Service {
+	return &Service{
+		dataStore:           dataStore,
+		dockerClientFactory: dockerClientFactory,
+		fileService:         fileService,
+		scheduler:           scheduler,
+		prunePolicies:       make(map[portainer.EndpointID]string),
+	}
+}
+
+// IsSupported returns true when the inactive resources of the environment can be reported
+func IsSupported(endpoint *portainer.Endpoint) bool {
+	// Edge environments are only reachable while their tunnel is open
+	return endpointutils.IsDockerEndpoint(endpoint) && !endpointutils.IsEdgeEndpoint(endpoint)
+}
+
+// EndpointReport lists the inactive resources of the given environment(endpoint)
+func (service *Service) EndpointReport(ctx context.Context, endpoint *portainer.Endpoint, options Options) (*Report, error) {
+	if !IsSupported(endpoint) {
+		return nil, fmt.Errorf("unsupported environment type: %v", endpoint.Type)
+	}
+
+	cli, err := service.createClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	return service.endpointReport(ctx, cli, endpoint, options)
+}
+
+// FleetReport lists the inactive resources of all the supported environments(endpoints).
+// The environments that cannot be inspected are reported with an error.
+func (service *Service) FleetReport(ctx context.Context, options Options) ([]Report, error) {
+	endpoints, err := service.dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to retrieve the environments")
+	}
+
+	reports := []Report{}
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if !IsSupported(endpoint) {
+			continue
+		}
+
+		report, err := service.EndpointReport(ctx, endpoint, options)
+		if err != nil {
+			log.Warn().Err(err).Int("endpoint_id", int(endpoint.ID)).Msg("unable to list the inactive resources of the environment")
+
+			report = &Report{
+				EndpointID:   endpoint.ID,
+				EndpointName: endpoint.Name,
+				Containers:   []InactiveContainer{},
+				Images:       []UnusedImage{},
+				Volumes:      []UnusedVolume{},
+				Stacks:       []InactiveStack{},
+				Error:        err.Error(),
+			}
+		}
+
+		reports = append(reports, *report)
+	}
+
+	return reports, nil
+}
+
+// Cleanup removes the selected resources of the environment(endpoint). Only the resources that
+// are still reported as inactive with the given options are removed, the others are skipped.
+func (service *Service) Cleanup(ctx context.Context, endpoint *portainer.Endpoint, options Options, selection Selection) ([]CleanupResult, error) {
+	if !IsSupported(endpoint) {
+		return nil, fmt.Errorf("unsupported environment type: %v", endpoint.Type)
+	}
+
+	cli, err := service.createClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	report, err := service.endpointReport(ctx, cli, endpoint, options)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []CleanupResult{}
+
+	// The containers go first so that the images and volumes they held can be removed afterwards
+	for _, c := range report.Containers {
+		if !selection.All && !slices.Contains(selection.ContainerIDs, c.ID) {
+			continue
+		}
+
+		err := cli.ContainerRemove(ctx, c.ID, container.RemoveOptions{})
+		if err == nil {
+			service.deleteResourceControl(c.ID, portainer.ContainerResourceControl)
+		}
+
+		results = append(results, cleanupResult("container", c.ID, err))
+	}
+
+	for _, s := range report.Stacks {
+		if !selection.All && !slices.Contains(selection.StackIDs, s.ID) {
+			continue
+		}
+
+		results = append(results, cleanupResult("stack", fmt.Sprint(s.ID), service.removeStack(s.ID)))
+	}
+
+	for _, v := range report.Volumes {
+		if !selection.All && !slices.Contains(selection.VolumeNames, v.Name) {
+			continue
+		}
+
+		err := cli.VolumeRemove(ctx, v.Name, false)
+		if err == nil {
+			service.deleteResourceControl(v.Name, portainer.VolumeResourceControl)
+		}
+
+		results = append(results, cleanupResult("volume", v.Name, err))
+	}
+
+	for _, img := range report.Images {
+		if !selection.All && !slices.Contains(selection.ImageIDs, img.ID) {
+			continue
+		}
+
+		_, err := cli.ImageRemove(ctx, img.ID, image.RemoveOptions{PruneChildren: true})
+		results = append(results, cleanupResult("image", img.ID, err))
+	}
+
+	if !selection.All {
+		results = append(results, skippedResults(report, selection)...)
+	}
+
+	return results, nil
+}
+
+func (service *Service) createClient(endpoint *portainer.Endpoint) (*client.Client, error) {
+	timeout := dockerClientTimeout
+
+	return service.dockerClientFactory.CreateClient(endpoint, "", &timeout)
+}
+
+func (service *Service) endpointReport(ctx context.Context, cli *client.Client, endpoint *portainer.Endpoint, options Options) (*Report, error) {
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the containers")
+	}
+
+	images, err := cli.ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the images")
+	}
+
+	volumes, err := cli.VolumeList(ctx, volume.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the volumes")
+	}
+
+	stacks, err := service.dataStore.Stack().ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to retrieve the stacks")
+	}
+
+	report := &Report{
+		EndpointID:   endpoint.ID,
+		EndpointName: endpoint.Name,
+		Images:       FindUnusedImages(images, containers),
+		Volumes:      FindUnusedVolumes(volumes.Volumes, containers),
+		Stacks:       FindInactiveStacks(stacks, endpoint.ID, options.StackInactiveSince),
+	}
+
+	report.Containers, err = FindInactiveContainers(ctx, cli, containers, time.Now().Add(-options.ContainerAge))
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to inspect the containers")
+	}
+
+	return report, nil
+}
+
+// removeStack removes a stopped stack, its files and its resource control. The stack has no
+// running resources so there is nothing to undeploy.
+func (service *Service) removeStack(stackID portainer.StackID) error {
+	stack, err := service.dataStore.Stack().Read(stackID)
+	if err != nil {
+		return err
+	}
+
+	if stack.AutoUpdate != nil {
+		deployments.StopAutoupdate(stack.ID, stack.AutoUpdate.JobID, service.scheduler)
+	}
+
+	if err := service.dataStore.Stack().Delete(stack.ID); err != nil {
+		return err
+	}
+
+	deployments.DeleteGitOpsStatus(service.dataStore, stack.ID)
+
+	service.deleteResourceControl(stackutils.ResourceControlID(stack.EndpointID, stack.Name), portainer.StackResourceControl)
+
+	if err := service.fileService.RemoveDirectory(stack.ProjectPath); err != nil {
+		log.Warn().Err(err).Int("stack_id", int(stack.ID)).Msg("unable to remove the stack files from disk")
+	}
+
+	return nil
+}
+
+func (service *Service) deleteResourceControl(resourceID string, resourceType portainer.ResourceControlType) {
+	resourceControl, err := service.dataStore.ResourceControl().ResourceControlByResourceIDAndType(resourceID, resourceType)
+	if err != nil || resourceControl == nil {
+		return
+	}
+
+	if err := service.dataStore.ResourceControl().Delete(resourceControl.ID); err != nil {
+		log.Warn().Err(err).Str("resource_id", resourceID).Msg("unable to remove the resource control")
+	}
+}
+
+func cleanupResult(resourceType, resourceID string, err error) CleanupResult {
+	result := CleanupResult{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Removed:      err == nil,
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// skippedResults reports the selected resources that are not inactive
+func skippedResults(report *Report, selection Selection) []CleanupResult {
+	const reason = "the resource is not inactive"
+
+	results := []CleanupResult{}
+
+	for _, id := range selection.ContainerIDs {
+		if !slices.ContainsFunc(report.Containers, func(c InactiveContainer) bool { return c.ID == id }) {
+			results = append(results, CleanupResult{ResourceType: "container", ResourceID: id, Error: reason})
+		}
+	}
+
+	for _, id := range selection.StackIDs {
+		if !slices.ContainsFunc(report.Stacks, func(s InactiveStack) bool { return s.ID == id }) {
+			results = append(results, CleanupResult{ResourceType: "stack", ResourceID: fmt.Sprint(id), Error: reason})
+		}
+	}
+
+	for _, name := range selection.VolumeNames {
+		if !slices.ContainsFunc(report.Volumes, func(v UnusedVolume) bool { return v.Name == name }) {
+			results = append(results, CleanupResult{ResourceType: "volume", ResourceID: name, Error: reason})
+		}
+	}
+
+	for _, id := range selection.ImageIDs {
+		if !slices.ContainsFunc(report.Images, func(img UnusedImage) bool { return img.ID == id }) {
+			results = append(results, CleanupResult{ResourceType: "image", ResourceID: id, Error: reason})
+		}
+	}
+
+	return results
+}
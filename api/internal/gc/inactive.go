@@ -0,0 +1,178 @@
+package gc
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+)
+
+// InactiveContainer represents a container that has been stopped for longer than the requested age
+type InactiveContainer struct {
+	ID    string `json:"Id"`
+	Name  string `json:"Name"`
+	Image string `json:"Image"`
+	State string `json:"State" example:"exited"`
+	// Unix timestamp of the moment the container stopped, or of its creation when it never ran
+	StoppedAt int64 `json:"StoppedAt"`
+}
+
+// UnusedImage represents an image that is not used by any container
+type UnusedImage struct {
+	ID       string   `json:"Id"`
+	RepoTags []string `json:"RepoTags"`
+	Size     int64    `json:"Size"`
+	Created  int64    `json:"Created"`
+}
+
+// UnusedVolume represents a volume that is not mounted by any container
+type UnusedVolume struct {
+	Name      string `json:"Name"`
+	Driver    string `json:"Driver"`
+	CreatedAt string `json:"CreatedAt"`
+}
+
+// InactiveStack represents a stopped stack
+type InactiveStack struct {
+	ID   portainer.StackID   `json:"Id"`
+	Name string              `json:"Name"`
+	Type portainer.StackType `json:"Type"`
+	// Unix timestamp of the last deployment or update of the stack
+	LastActivity int64 `json:"LastActivity"`
+}
+
+// containerInspector is the subset of the Docker client used to find inactive containers
+type containerInspector interface {
+	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
+}
+
+// FindInactiveContainers returns the containers that are not running and stopped before the given time
+func FindInactiveContainers(ctx context.Context, cli containerInspector, containers []types.Container, stoppedBefore time.Time) ([]InactiveContainer, error) {
+	inactive := []InactiveContainer{}
+
+	for _, c := range containers {
+		if c.State != "exited" && c.State != "created" && c.State != "dead" {
+			continue
+		}
+
+		stoppedAt := time.Unix(c.Created, 0)
+
+		details, err := cli.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if details.ContainerJSONBase != nil && details.State != nil {
+			if finishedAt, err := time.Parse(time.RFC3339Nano, details.State.FinishedAt); err == nil && finishedAt.After(stoppedAt) {
+				stoppedAt = finishedAt
+			}
+		}
+
+		if !stoppedAt.Before(stoppedBefore) {
+			continue
+		}
+
+		name := ""
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+
+		inactive = append(inactive, InactiveContainer{
+			ID:        c.ID,
+			Name:      name,
+			Image:     c.Image,
+			State:     c.State,
+			StoppedAt: stoppedAt.Unix(),
+		})
+	}
+
+	return inactive, nil
+}
+
+// FindUnusedImages returns the images that are not used by any of the given containers, whatever their state
+func FindUnusedImages(images []image.Summary, containers []types.Container) []UnusedImage {
+	used := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		used[c.ImageID] = true
+	}
+
+	unused := []UnusedImage{}
+	for _, img := range images {
+		if used[img.ID] {
+			continue
+		}
+
+		unused = append(unused, UnusedImage{
+			ID:       img.ID,
+			RepoTags: img.RepoTags,
+			Size:     img.Size,
+			Created:  img.Created,
+		})
+	}
+
+	return unused
+}
+
+// FindUnusedVolumes returns the volumes that are not mounted by any of the given containers, whatever their state
+func FindUnusedVolumes(volumes []*volume.Volume, containers []types.Container) []UnusedVolume {
+	used := make(map[string]bool)
+	for _, c := range containers {
+		for _, m := range c.Mounts {
+			if m.Type == mount.TypeVolume {
+				used[m.Name] = true
+			}
+		}
+	}
+
+	unused := []UnusedVolume{}
+	for _, v := range volumes {
+		if v == nil || used[v.Name] {
+			continue
+		}
+
+		unused = append(unused, UnusedVolume{
+			Name:      v.Name,
+			Driver:    v.Driver,
+			CreatedAt: v.CreatedAt,
+		})
+	}
+
+	sort.Slice(unused, func(i, j int) bool {
+		return unused[i].Name < unused[j].Name
+	})
+
+	return unused
+}
+
+// FindInactiveStacks returns the stacks of the environment that are stopped and were last
+// deployed or updated before the given time
+func FindInactiveStacks(stacks []portainer.Stack, endpointID portainer.EndpointID, inactiveSince time.Time) []InactiveStack {
+	inactive := []InactiveStack{}
+
+	for _, stack := range stacks {
+		if stack.EndpointID != endpointID || stack.Status != portainer.StackStatusInactive {
+			continue
+		}
+
+		lastActivity := max(stack.CreationDate, stack.UpdateDate)
+		if !time.Unix(lastActivity, 0).Before(inactiveSince) {
+			continue
+		}
+
+		inactive = append(inactive, InactiveStack{
+			ID:           stack.ID,
+			Name:         stack.Name,
+			Type:         stack.Type,
+			LastActivity: lastActivity,
+		})
+	}
+
+	return inactive
+}
@@ -0,0 +1,114 @@
+package gc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDockerClient struct {
+	finishedAt map[string]time.Time
+}
+
+func (c *testDockerClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	state := &types.ContainerState{FinishedAt: "0001-01-01T00:00:00Z"}
+	if finishedAt, ok := c.finishedAt[containerID]; ok {
+		state.FinishedAt = finishedAt.Format(time.RFC3339Nano)
+	}
+
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{State: state},
+	}, nil
+}
+
+func TestFindInactiveContainers(t *testing.T) {
+	now := time.Now()
+	created := now.Add(-60 * 24 * time.Hour).Unix()
+
+	cli := &testDockerClient{
+		finishedAt: map[string]time.Time{
+			"old":    now.Add(-10 * 24 * time.Hour),
+			"recent": now.Add(-time.Hour),
+		},
+	}
+
+	containers := []types.Container{
+		{ID: "old", Names: []string{"/old"}, State: "exited", Created: created},
+		{ID: "recent", Names: []string{"/recent"}, State: "exited", Created: created},
+		{ID: "never-started", Names: []string{"/never-started"}, State: "created", Created: created},
+		{ID: "running", Names: []string{"/running"}, State: "running", Created: created},
+	}
+
+	inactive, err := FindInactiveContainers(context.Background(), cli, containers, now.Add(-7*24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, inactive, 2)
+
+	assert.Equal(t, "old", inactive[0].Name)
+	assert.Equal(t, cli.finishedAt["old"].Unix(), inactive[0].StoppedAt)
+	assert.Equal(t, "never-started", inactive[1].Name)
+	assert.Equal(t, created, inactive[1].StoppedAt)
+}
+
+func TestFindUnusedImages(t *testing.T) {
+	images := []image.Summary{
+		{ID: "sha256:used-by-stopped"},
+		{ID: "sha256:used-by-running"},
+		{ID: "sha256:unused", RepoTags: []string{"nginx:1.25"}, Size: 42},
+	}
+
+	containers := []types.Container{
+		{ID: "a", ImageID: "sha256:used-by-stopped", State: "exited"},
+		{ID: "b", ImageID: "sha256:used-by-running", State: "running"},
+	}
+
+	unused := FindUnusedImages(images, containers)
+	require.Len(t, unused, 1)
+	assert.Equal(t, UnusedImage{ID: "sha256:unused", RepoTags: []string{"nginx:1.25"}, Size: 42}, unused[0])
+}
+
+func TestFindUnusedVolumes(t *testing.T) {
+	volumes := []*volume.Volume{
+		{Name: "data", Driver: "local"},
+		{Name: "orphan", Driver: "local"},
+		{Name: "cache", Driver: "local"},
+	}
+
+	containers := []types.Container{
+		{ID: "a", Mounts: []types.MountPoint{{Type: mount.TypeVolume, Name: "data"}}},
+		{ID: "b", Mounts: []types.MountPoint{{Type: mount.TypeBind, Name: "cache", Source: "/cache"}}},
+	}
+
+	unused := FindUnusedVolumes(volumes, containers)
+
+	assert.Equal(t, []UnusedVolume{
+		{Name: "cache", Driver: "local"},
+		{Name: "orphan", Driver: "local"},
+	}, unused)
+}
+
+func TestFindInactiveStacks(t *testing.T) {
+	now := time.Now()
+	since := now.Add(-30 * 24 * time.Hour)
+	longAgo := now.Add(-90 * 24 * time.Hour).Unix()
+
+	stacks := []portainer.Stack{
+		{ID: 1, Name: "stale", EndpointID: 1, Status: portainer.StackStatusInactive, CreationDate: longAgo},
+		{ID: 2, Name: "updated", EndpointID: 1, Status: portainer.StackStatusInactive, CreationDate: longAgo, UpdateDate: now.Unix()},
+		{ID: 3, Name: "active", EndpointID: 1, Status: portainer.StackStatusActive, CreationDate: longAgo},
+		{ID: 4, Name: "other", EndpointID: 2, Status: portainer.StackStatusInactive, CreationDate: longAgo},
+	}
+
+	inactive := FindInactiveStacks(stacks, 1, since)
+
+	assert.Equal(t, []InactiveStack{{ID: 1, Name: "stale", LastActivity: longAgo}}, inactive)
+}
@@ -0,0 +1,356 @@
+package gc
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// PruneRunRetention is the number of prune runs kept for each environment(endpoint)
+	PruneRunRetention = 50
+
+	pruneTimeout = 30 * time.Minute
+)
+
+// predefinedNetworks are the networks created by Docker, they cannot be removed
+var predefinedNetworks = []string{"bridge", "host", "none", "docker_gwbridge", "ingress"}
+
+// StartPrunePolicies schedules the enabled prune policies of all the environments(endpoints)
+func (service *Service) StartPrunePolicies() error {
+	endpoints, err := service.dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve the environments")
+	}
+
+	for i := range endpoints {
+		if err := service.SchedulePrunePolicy(&endpoints[i]); err != nil {
+			log.Warn().Err(err).Int("endpoint_id", int(endpoints[i].ID)).Msg("unable to schedule the prune policy of the environment")
+		}
+	}
+
+	return nil
+}
+
+// SchedulePrunePolicy (re)schedules the prune policy of an environment(endpoint) according to its cron expression,
+// it is unscheduled when it is removed or disabled
+func (service *Service) SchedulePrunePolicy(endpoint *portainer.Endpoint) error {
+	service.UnschedulePrunePolicy(endpoint.ID)
+
+	if endpoint.PrunePolicy == nil || !endpoint.PrunePolicy.Enabled || !IsSupported(endpoint) {
+		return nil
+	}
+
+	endpointID := endpoint.ID
+	schedulerID, err := service.scheduler.StartJobCron(endpoint.PrunePolicy.CronExpression, func() error {
+		return service.runPrunePolicy(endpointID)
+	})
+	if err != nil {
+		return err
+	}
+
+	service.mu.Lock()
+	service.prunePolicies[endpointID] = schedulerID
+	service.mu.Unlock()
+
+	return nil
+}
+
+// UnschedulePrunePolicy prevents any future scheduled run of the prune policy of an environment(endpoint)
+func (service *Service) UnschedulePrunePolicy(endpointID portainer.EndpointID) {
+	service.mu.Lock()
+	schedulerID, ok := service.prunePolicies[endpointID]
+	delete(service.prunePolicies, endpointID)
+	service.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if err := service.scheduler.StopJob(schedulerID); err != nil {
+		log.Warn().Err(err).Int("endpoint_id", int(endpointID)).Msg("unable to stop the prune policy job")
+	}
+}
+
+func (service *Service) runPrunePolicy(endpointID portainer.EndpointID) error {
+	endpoint, err := service.dataStore.Endpoint().Endpoint(endpointID)
+	if service.dataStore.IsErrObjectNotFound(err) {
+		// The environment was removed since the policy was scheduled
+		service.UnschedulePrunePolicy(endpointID)
+
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "unable to retrieve the environment")
+	}
+
+	if endpoint.PrunePolicy == nil || !endpoint.PrunePolicy.Enabled {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pruneTimeout)
+	defer cancel()
+
+	_, err = service.Prune(ctx, endpoint, endpoint.PrunePolicy.DryRun, "")
+
+	return err
+}
+
+// Prune removes the unused resources of the environment(endpoint) selected by its prune policy, a dry run
+// only reports them. The run is recorded, along with the reason why the environment could not be pruned.
+func (service *Service) Prune(ctx context.Context, endpoint *portainer.Endpoint, dryRun bool, username string) (*portainer.PruneRun, error) {
+	if endpoint.PrunePolicy == nil {
+		return nil, errors.New("the environment has no prune policy")
+	}
+
+	if !IsSupported(endpoint) {
+		return nil, errors.Errorf("unsupported environment type: %v", endpoint.Type)
+	}
+
+	run := &portainer.PruneRun{
+		EndpointID: endpoint.ID,
+		Time:       time.Now().Unix(),
+		DryRun:     dryRun,
+		Username:   username,
+		Resources:  []portainer.PrunedResource{},
+	}
+
+	if err := service.prune(ctx, endpoint, *endpoint.PrunePolicy, run); err != nil {
+		log.Warn().Err(err).Int("endpoint_id", int(endpoint.ID)).Msg("unable to prune the environment")
+
+		run.Error = err.Error()
+	}
+
+	if err := service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return RecordPruneRun(tx, run)
+	}); err != nil {
+		return nil, errors.Wrap(err, "unable to record the prune run")
+	}
+
+	return run, nil
+}
+
+func (service *Service) prune(ctx context.Context, endpoint *portainer.Endpoint, policy portainer.EndpointPrunePolicy, run *portainer.PruneRun) error {
+	cli, err := service.createClient(endpoint)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	now := time.Now()
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return errors.Wrap(err, "unable to list the containers")
+	}
+
+	var stoppedContainers []InactiveContainer
+	if policy.StoppedContainersAgeDays > 0 {
+		stoppedContainers, err = FindInactiveContainers(ctx, cli, containers, now.Add(-days(policy.StoppedContainersAgeDays)))
+		if err != nil {
+			return errors.Wrap(err, "unable to inspect the containers")
+		}
+	}
+
+	var images []image.Summary
+	if policy.DanglingImagesAgeDays > 0 {
+		images, err = cli.ImageList(ctx, image.ListOptions{Filters: filters.NewArgs(filters.Arg("dangling", "true"))})
+		if err != nil {
+			return errors.Wrap(err, "unable to list the images")
+		}
+	}
+
+	var networks []types.NetworkResource
+	if policy.UnusedNetworks {
+		networks, err = cli.NetworkList(ctx, types.NetworkListOptions{})
+		if err != nil {
+			return errors.Wrap(err, "unable to list the networks")
+		}
+	}
+
+	var volumes []*volume.Volume
+	if policy.UnusedVolumes {
+		volumeList, err := cli.VolumeList(ctx, volume.ListOptions{})
+		if err != nil {
+			return errors.Wrap(err, "unable to list the volumes")
+		}
+
+		volumes = volumeList.Volumes
+	}
+
+	run.Resources = planPrune(policy, now, containers, stoppedContainers, images, networks, volumes)
+
+	for i := range run.Resources {
+		resource := &run.Resources[i]
+
+		if !run.DryRun {
+			if err := service.removePrunedResource(ctx, cli, resource); err != nil {
+				resource.Error = err.Error()
+
+				continue
+			}
+		}
+
+		run.ReclaimedSpace += resource.Size
+	}
+
+	return nil
+}
+
+func (service *Service) removePrunedResource(ctx context.Context, cli *client.Client, resource *portainer.PrunedResource) error {
+	switch resource.ResourceType {
+	case "container":
+		if err := cli.ContainerRemove(ctx, resource.ResourceID, container.RemoveOptions{}); err != nil {
+			return err
+		}
+
+		service.deleteResourceControl(resource.ResourceID, portainer.ContainerResourceControl)
+	case "image":
+		if _, err := cli.ImageRemove(ctx, resource.ResourceID, image.RemoveOptions{PruneChildren: true}); err != nil {
+			return err
+		}
+	case "network":
+		if err := cli.NetworkRemove(ctx, resource.ResourceID); err != nil {
+			return err
+		}
+
+		service.deleteResourceControl(resource.ResourceID, portainer.NetworkResourceControl)
+	case "volume":
+		if err := cli.VolumeRemove(ctx, resource.ResourceID, false); err != nil {
+			return err
+		}
+
+		service.deleteResourceControl(resource.ResourceID, portainer.VolumeResourceControl)
+	default:
+		return errors.Errorf("unsupported resource type: %s", resource.ResourceType)
+	}
+
+	return nil
+}
+
+// planPrune returns the resources removed by a prune policy. The containers go first, the images, networks
+// and volumes they held are considered unused once they are removed.
+func planPrune(
+	policy portainer.EndpointPrunePolicy,
+	now time.Time,
+	containers []types.Container,
+	stoppedContainers []InactiveContainer,
+	images []image.Summary,
+	networks []types.NetworkResource,
+	volumes []*volume.Volume,
+) []portainer.PrunedResource {
+	resources := []portainer.PrunedResource{}
+
+	removed := make(map[string]bool, len(stoppedContainers))
+	for _, c := range stoppedContainers {
+		removed[c.ID] = true
+
+		resources = append(resources, portainer.PrunedResource{ResourceType: "container", ResourceID: c.ID, Name: c.Name})
+	}
+
+	remaining := slices.DeleteFunc(slices.Clone(containers), func(c types.Container) bool {
+		return removed[c.ID]
+	})
+
+	if policy.DanglingImagesAgeDays > 0 {
+		createdBefore := now.Add(-days(policy.DanglingImagesAgeDays))
+
+		for _, img := range FindUnusedImages(images, remaining) {
+			if !isDanglingImage(img.RepoTags) || !time.Unix(img.Created, 0).Before(createdBefore) {
+				continue
+			}
+
+			resources = append(resources, portainer.PrunedResource{ResourceType: "image", ResourceID: img.ID, Size: img.Size})
+		}
+	}
+
+	if policy.UnusedNetworks {
+		usedNetworks := make(map[string]bool)
+		for _, c := range remaining {
+			if c.NetworkSettings == nil {
+				continue
+			}
+
+			for name, settings := range c.NetworkSettings.Networks {
+				usedNetworks[name] = true
+				if settings != nil {
+					usedNetworks[settings.NetworkID] = true
+				}
+			}
+		}
+
+		unusedNetworks := slices.DeleteFunc(slices.Clone(networks), func(n types.NetworkResource) bool {
+			// The Swarm networks are used by services rather than by containers
+			return usedNetworks[n.Name] || usedNetworks[n.ID] || n.Ingress || n.Scope == "swarm" || slices.Contains(predefinedNetworks, n.Name)
+		})
+
+		slices.SortFunc(unusedNetworks, func(a, b types.NetworkResource) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+
+		for _, n := range unusedNetworks {
+			resources = append(resources, portainer.PrunedResource{ResourceType: "network", ResourceID: n.ID, Name: n.Name})
+		}
+	}
+
+	if policy.UnusedVolumes {
+		for _, v := range FindUnusedVolumes(volumes, remaining) {
+			resources = append(resources, portainer.PrunedResource{ResourceType: "volume", ResourceID: v.Name, Name: v.Name})
+		}
+	}
+
+	return resources
+}
+
+func isDanglingImage(repoTags []string) bool {
+	return len(repoTags) == 0 || (len(repoTags) == 1 && repoTags[0] == "<none>:<none>")
+}
+
+func days(count int) time.Duration {
+	return time.Duration(count) * 24 * time.Hour
+}
+
+// RecordPruneRun saves a prune run and removes the oldest runs of the environment(endpoint) beyond the retention
+func RecordPruneRun(tx dataservices.DataStoreTx, run *portainer.PruneRun) error {
+	if err := tx.PruneRun().Create(run); err != nil {
+		return err
+	}
+
+	runs, err := tx.PruneRun().ReadAllByEndpointID(run.EndpointID)
+	if err != nil {
+		return err
+	}
+
+	if len(runs) <= PruneRunRetention {
+		return nil
+	}
+
+	SortPruneRuns(runs)
+
+	for _, previous := range runs[PruneRunRetention:] {
+		if err := tx.PruneRun().Delete(previous.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SortPruneRuns sorts the prune runs from the most recent to the oldest
+func SortPruneRuns(runs []portainer.PruneRun) {
+	slices.SortFunc(runs, func(a, b portainer.PruneRun) int {
+		return cmp.Or(cmp.Compare(b.Time, a.Time), cmp.Compare(b.ID, a.ID))
+	})
+}
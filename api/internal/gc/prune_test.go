@@ -0,0 +1,106 @@
+package gc
+
+import (
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanPrune(t *testing.T) {
+	now := time.Now()
+
+	policy := portainer.EndpointPrunePolicy{
+		DanglingImagesAgeDays:    7,
+		StoppedContainersAgeDays: 14,
+		UnusedNetworks:           true,
+		UnusedVolumes:            true,
+	}
+
+	containers := []types.Container{
+		{
+			ID:              "stopped",
+			ImageID:         "sha256:held-by-stopped",
+			Mounts:          []types.MountPoint{{Type: mount.TypeVolume, Name: "stopped-data"}},
+			NetworkSettings: &types.SummaryNetworkSettings{Networks: map[string]*network.EndpointSettings{"stopped-net": {NetworkID: "n2"}}},
+		},
+		{
+			ID:              "running",
+			ImageID:         "sha256:held-by-running",
+			Mounts:          []types.MountPoint{{Type: mount.TypeVolume, Name: "data"}},
+			NetworkSettings: &types.SummaryNetworkSettings{Networks: map[string]*network.EndpointSettings{"app-net": {NetworkID: "n1"}}},
+		},
+	}
+
+	stoppedContainers := []InactiveContainer{{ID: "stopped", Name: "old-job"}}
+
+	old := now.Add(-30 * 24 * time.Hour).Unix()
+	images := []image.Summary{
+		{ID: "sha256:held-by-stopped", Created: old, Size: 10},
+		{ID: "sha256:held-by-running", Created: old, Size: 20},
+		{ID: "sha256:recent", Created: now.Unix(), Size: 30},
+		{ID: "sha256:tagged", RepoTags: []string{"nginx:1.25"}, Created: old, Size: 40},
+	}
+
+	networks := []types.NetworkResource{
+		{ID: "n1", Name: "app-net", Scope: "local"},
+		{ID: "n2", Name: "stopped-net", Scope: "local"},
+		{ID: "n3", Name: "bridge", Scope: "local"},
+		{ID: "n4", Name: "overlay", Scope: "swarm"},
+	}
+
+	volumes := []*volume.Volume{{Name: "data"}, {Name: "stopped-data"}}
+
+	resources := planPrune(policy, now, containers, stoppedContainers, images, networks, volumes)
+
+	assert.Equal(t, []portainer.PrunedResource{
+		{ResourceType: "container", ResourceID: "stopped", Name: "old-job"},
+		{ResourceType: "image", ResourceID: "sha256:held-by-stopped", Size: 10},
+		{ResourceType: "network", ResourceID: "n2", Name: "stopped-net"},
+		{ResourceType: "volume", ResourceID: "stopped-data", Name: "stopped-data"},
+	}, resources)
+}
+
+func TestPlanPruneKeepsTheResourcesOutsideThePolicy(t *testing.T) {
+	containers := []types.Container{{ID: "stopped"}}
+	volumes := []*volume.Volume{{Name: "orphan"}}
+
+	resources := planPrune(portainer.EndpointPrunePolicy{UnusedNetworks: true}, time.Now(), containers, nil, nil, nil, volumes)
+	assert.Empty(t, resources)
+}
+
+func TestRecordPruneRun(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	for i := range PruneRunRetention + 2 {
+		err := store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+			return RecordPruneRun(tx, &portainer.PruneRun{EndpointID: 1, Time: int64(i)})
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, store.PruneRun().Create(&portainer.PruneRun{EndpointID: 2}))
+
+	runs, err := store.PruneRun().ReadAllByEndpointID(1)
+	require.NoError(t, err)
+	require.Len(t, runs, PruneRunRetention)
+
+	SortPruneRuns(runs)
+	assert.Equal(t, int64(PruneRunRetention+1), runs[0].Time)
+	assert.Equal(t, int64(2), runs[len(runs)-1].Time)
+
+	runs, err = store.PruneRun().ReadAllByEndpointID(2)
+	require.NoError(t, err)
+	assert.Len(t, runs, 1)
+}
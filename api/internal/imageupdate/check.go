@@ -0,0 +1,176 @@
+package imageupdate
+
+import (
+	"context"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker/consts"
+	"github.com/portainer/portainer/api/docker/images"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/client"
+	"github.com/rs/zerolog/log"
+)
+
+func (service *Service) checkEndpoint(ctx context.Context, endpoint *portainer.Endpoint, autoUpdate bool) ([]portainer.ImageUpdateJobResult, error) {
+	cli, err := service.dockerClientFactory.CreateClient(endpoint, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	info, err := cli.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]portainer.ImageUpdateJobResult, 0)
+	for _, ct := range containers {
+		// The tasks of the Swarm services are updated through their service
+		if ct.Labels[consts.SwarmServiceIDLabel] != "" {
+			continue
+		}
+
+		results = append(results, service.checkContainer(ctx, endpoint, ct, autoUpdate))
+	}
+
+	if !info.Swarm.ControlAvailable {
+		return results, nil
+	}
+
+	services, err := cli.ServiceList(ctx, types.ServiceListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range services {
+		results = append(results, service.checkService(ctx, cli, endpoint, s, autoUpdate))
+	}
+
+	return results, nil
+}
+
+func (service *Service) checkContainer(ctx context.Context, endpoint *portainer.Endpoint, ct types.Container, autoUpdate bool) portainer.ImageUpdateJobResult {
+	result := portainer.ImageUpdateJobResult{
+		EndpointID:   endpoint.ID,
+		ResourceType: ContainerResource,
+		ResourceID:   ct.ID,
+		Image:        ct.Image,
+	}
+
+	if len(ct.Names) > 0 {
+		result.ResourceName = ct.Names[0]
+	}
+
+	images.EvictImageStatus(ct.ID)
+
+	status, err := service.digestClient.ContainerImageStatus(ctx, ct.ID, endpoint, "")
+	result.Status = string(status)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	if status != images.Outdated || !autoUpdate {
+		return result
+	}
+
+	newContainer, err := service.containerService.Recreate(ctx, endpoint, ct.ID, true, "", "")
+	if err != nil {
+		log.Warn().Err(err).Str("container_id", ct.ID).Msg("unable to update the container")
+		result.Error = err.Error()
+
+		return result
+	}
+
+	if err := service.containerService.ReassignResources(ct.ID, newContainer.ID); err != nil {
+		log.Warn().Err(err).Str("container_id", newContainer.ID).Msg("unable to reassign the container resources")
+	}
+
+	images.EvictImageStatus(ct.ID)
+	images.EvictImageStatus(ct.Labels[consts.ComposeStackNameLabel])
+
+	result.ResourceID = newContainer.ID
+	result.Status = string(images.Updated)
+	result.Updated = true
+
+	return result
+}
+
+func (service *Service) checkService(ctx context.Context, cli *client.Client, endpoint *portainer.Endpoint, s swarm.Service, autoUpdate bool) portainer.ImageUpdateJobResult {
+	result := portainer.ImageUpdateJobResult{
+		EndpointID:   endpoint.ID,
+		ResourceType: ServiceResource,
+		ResourceID:   s.ID,
+		ResourceName: s.Spec.Name,
+	}
+
+	if s.Spec.TaskTemplate.ContainerSpec == nil {
+		result.Status = string(images.Skipped)
+
+		return result
+	}
+
+	result.Image = s.Spec.TaskTemplate.ContainerSpec.Image
+
+	images.EvictImageStatus(s.ID)
+
+	status, err := service.digestClient.ServiceImageStatus(ctx, s.ID, endpoint)
+	result.Status = string(status)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	if status != images.Outdated || !autoUpdate {
+		return result
+	}
+
+	if err := service.updateService(ctx, cli, s); err != nil {
+		log.Warn().Err(err).Str("service_id", s.ID).Msg("unable to update the service")
+		result.Error = err.Error()
+
+		return result
+	}
+
+	images.EvictImageStatus(s.ID)
+
+	result.Status = string(images.Updated)
+	result.Updated = true
+
+	return result
+}
+
+// updateService makes the service tasks use the latest image of their tag
+func (service *Service) updateService(ctx context.Context, cli *client.Client, s swarm.Service) error {
+	image, err := images.ParseImage(images.ParseImageOptions{Name: s.Spec.TaskTemplate.ContainerSpec.Image})
+	if err != nil {
+		return err
+	}
+
+	options := types.ServiceUpdateOptions{QueryRegistry: true}
+
+	// The digest is dropped so that the registry resolves the tag again
+	if image.Tag != "" {
+		s.Spec.TaskTemplate.ContainerSpec.Image = image.Name() + ":" + image.Tag
+
+		if auth, err := service.registryClient.EncodedRegistryAuth(image); err == nil {
+			options.EncodedRegistryAuth = auth
+		}
+	}
+
+	s.Spec.TaskTemplate.ForceUpdate++
+
+	_, err = cli.ServiceUpdate(ctx, s.ID, s.Version, s.Spec, options)
+
+	return err
+}
@@ -0,0 +1,198 @@
+package imageupdate
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/docker"
+	dockerclient "github.com/portainer/portainer/api/docker/client"
+	"github.com/portainer/portainer/api/docker/images"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/scheduler"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// ContainerResource is the resource type of the results about standalone containers
+	ContainerResource = "container"
+	// ServiceResource is the resource type of the results about Swarm services
+	ServiceResource = "service"
+
+	jobTimeout = 30 * time.Minute
+)
+
+// Service schedules the image update jobs and runs them
+type Service struct {
+	dataStore           dataservices.DataStore
+	dockerClientFactory *dockerclient.ClientFactory
+	containerService    *docker.ContainerService
+	digestClient        *images.DigestClient
+	registryClient      *images.RegistryClient
+	scheduler           *scheduler.Scheduler
+	shutdownCtx         context.Context
+
+	mu        sync.Mutex
+	scheduled map[portainer.ImageUpdateJobID]string
+	running   map[portainer.ImageUpdateJobID]bool
+}
+
+// NewService returns a new instance of a service
+func NewService(
+	shutdownCtx context.Context,
+	dataStore dataservices.DataStore,
+	dockerClientFactory *dockerclient.ClientFactory,
+	containerService *docker.ContainerService,
+	scheduler *scheduler.Scheduler,
+) *Service {
+	registryClient := images.NewRegistryClient(dataStore)
+
+	return &Service{
+		dataStore:           dataStore,
+		dockerClientFactory: dockerClientFactory,
+		containerService:    containerService,
+		digestClient:        images.NewClientWithRegistry(registryClient, dockerClientFactory),
+		registryClient:      registryClient,
+		scheduler:           scheduler,
+		shutdownCtx:         shutdownCtx,
+		scheduled:           make(map[portainer.ImageUpdateJobID]string),
+		running:             make(map[portainer.ImageUpdateJobID]bool),
+	}
+}
+
+// Start schedules all the enabled image update jobs
+func (service *Service) Start() error {
+	jobs, err := service.dataStore.ImageUpdateJob().ReadAll()
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve the image update jobs")
+	}
+
+	for i := range jobs {
+		if err := service.Schedule(&jobs[i]); err != nil {
+			log.Warn().Err(err).Int("job_id", int(jobs[i].ID)).Msg("unable to schedule the image update job")
+		}
+	}
+
+	return nil
+}
+
+// Schedule (re)schedules a job according to its cron expression, it is unscheduled when disabled
+func (service *Service) Schedule(job *portainer.ImageUpdateJob) error {
+	service.Unschedule(job.ID)
+
+	if !job.Enabled {
+		return nil
+	}
+
+	jobID := job.ID
+	schedulerID, err := service.scheduler.StartJobCron(job.CronExpression, func() error {
+		return service.Run(service.shutdownCtx, jobID)
+	})
+	if err != nil {
+		return err
+	}
+
+	service.mu.Lock()
+	service.scheduled[job.ID] = schedulerID
+	service.mu.Unlock()
+
+	return nil
+}
+
+// Unschedule prevents any future run of a job
+func (service *Service) Unschedule(jobID portainer.ImageUpdateJobID) {
+	service.mu.Lock()
+	schedulerID, ok := service.scheduled[jobID]
+	delete(service.scheduled, jobID)
+	service.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if err := service.scheduler.StopJob(schedulerID); err != nil {
+		log.Warn().Err(err).Int("job_id", int(jobID)).Msg("unable to stop the image update job")
+	}
+}
+
+// Run checks the images of the containers and services of the job environments and
+// updates the outdated ones when the job allows it. The results are saved on the job.
+func (service *Service) Run(ctx context.Context, jobID portainer.ImageUpdateJobID) error {
+	service.mu.Lock()
+	if service.running[jobID] {
+		service.mu.Unlock()
+
+		return fmt.Errorf("image update job %d is already running", jobID)
+	}
+	service.running[jobID] = true
+	service.mu.Unlock()
+
+	defer func() {
+		service.mu.Lock()
+		delete(service.running, jobID)
+		service.mu.Unlock()
+	}()
+
+	job, err := service.dataStore.ImageUpdateJob().Read(jobID)
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve the image update job")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, jobTimeout)
+	defer cancel()
+
+	results := make([]portainer.ImageUpdateJobResult, 0)
+	for _, endpointID := range job.EndpointIDs {
+		endpoint, err := service.dataStore.Endpoint().Endpoint(endpointID)
+		if err != nil {
+			log.Warn().Err(err).Int("job_id", int(jobID)).Int("endpoint_id", int(endpointID)).Msg("unable to retrieve the environment")
+
+			continue
+		}
+
+		if !endpointutils.IsDockerEndpoint(endpoint) {
+			continue
+		}
+
+		// Edge environments are only reachable while their tunnel is open, they are reported as skipped
+		if endpointutils.IsEdgeEndpoint(endpoint) {
+			results = append(results, portainer.ImageUpdateJobResult{
+				EndpointID: endpoint.ID,
+				Status:     string(images.Skipped),
+				Error:      "the Edge environments are not checked by the image update jobs",
+			})
+
+			continue
+		}
+
+		endpointResults, err := service.checkEndpoint(ctx, endpoint, job.AutoUpdate)
+		if err != nil {
+			results = append(results, portainer.ImageUpdateJobResult{
+				EndpointID: endpoint.ID,
+				Status:     string(images.Error),
+				Error:      err.Error(),
+			})
+
+			continue
+		}
+
+		results = append(results, endpointResults...)
+	}
+
+	return service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		job, err := tx.ImageUpdateJob().Read(jobID)
+		if err != nil {
+			return err
+		}
+
+		job.LastRun = time.Now().Unix()
+		job.Results = results
+
+		return tx.ImageUpdateJob().Update(job.ID, job)
+	})
+}
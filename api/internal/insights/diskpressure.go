@@ -0,0 +1,140 @@
+package insights
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+const (
+	// DiskPressureAdvisoryDays is the projected number of days until the disk is full under which an advisory is raised
+	DiskPressureAdvisoryDays = 7
+
+	secondsPerDay = 24 * 60 * 60
+)
+
+// DiskPressure represents the projected growth of the disk usage of an environment(endpoint)
+type DiskPressure struct {
+	// Size in bytes of the disk holding the Docker data directory, 0 when unknown
+	Capacity int64 `json:"Capacity"`
+	// Projection of the Docker data directory
+	DataRoot DiskProjection `json:"DataRoot"`
+	// Projection of each volume, computed against the space left on the disk
+	Volumes []DiskProjection `json:"Volumes"`
+}
+
+// DiskProjection represents the projected growth of a disk consumer
+type DiskProjection struct {
+	// Volume name, empty for the Docker data directory
+	Name string `json:"Name,omitempty"`
+	// Bytes used at the time of the latest sample
+	Used int64 `json:"Used"`
+	// Estimated growth in bytes per day
+	GrowthPerDay float64 `json:"GrowthPerDay"`
+	// Estimated number of days until the disk is full, omitted when the capacity is unknown or the usage is not growing
+	DaysUntilFull *float64 `json:"DaysUntilFull,omitempty"`
+	// Whether the disk is projected to be full within DiskPressureAdvisoryDays
+	Advisory bool `json:"Advisory"`
+}
+
+// ProjectDiskPressure estimates the days until the disk is full from the disk usage history of
+// an environment(endpoint), using a linear regression of the usage over time. The size of the disk is
+// the one reported with the latest sample, unless capacityOverride is set.
+// It returns nil when there are not enough samples to compute a trend.
+func ProjectDiskPressure(samples []portainer.DiskUsageSample, capacityOverride int64) *DiskPressure {
+	if len(samples) < 2 {
+		return nil
+	}
+
+	samples = slices.Clone(samples)
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Time < samples[j].Time
+	})
+
+	latest := samples[len(samples)-1]
+	capacity := cmp.Or(capacityOverride, latest.DataRootCapacity)
+
+	times := make([]int64, len(samples))
+	dataRoot := make([]int64, len(samples))
+	for i, sample := range samples {
+		times[i] = sample.Time
+		dataRoot[i] = sample.DataRootUsed
+	}
+
+	pressure := &DiskPressure{
+		Capacity: capacity,
+		DataRoot: project("", latest.DataRootUsed, growthPerDay(times, dataRoot), capacity-latest.DataRootUsed, capacity),
+		Volumes:  []DiskProjection{},
+	}
+
+	names := make([]string, 0, len(latest.Volumes))
+	for name := range latest.Volumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var volumeTimes, volumeUsage []int64
+		for _, sample := range samples {
+			if size, ok := sample.Volumes[name]; ok {
+				volumeTimes = append(volumeTimes, sample.Time)
+				volumeUsage = append(volumeUsage, size)
+			}
+		}
+
+		used := latest.Volumes[name]
+		pressure.Volumes = append(pressure.Volumes, project(name, used, growthPerDay(volumeTimes, volumeUsage), capacity-latest.DataRootUsed, capacity))
+	}
+
+	return pressure
+}
+
+func project(name string, used int64, growth float64, free int64, capacity int64) DiskProjection {
+	projection := DiskProjection{
+		Name:         name,
+		Used:         used,
+		GrowthPerDay: growth,
+	}
+
+	if capacity <= 0 || growth <= 0 {
+		return projection
+	}
+
+	days := float64(max(free, 0)) / growth
+	projection.DaysUntilFull = &days
+	projection.Advisory = days < DiskPressureAdvisoryDays
+
+	return projection
+}
+
+// growthPerDay returns the slope, in bytes per day, of the least squares line fitting the usage over time
+func growthPerDay(times []int64, usage []int64) float64 {
+	if len(times) < 2 {
+		return 0
+	}
+
+	n := float64(len(times))
+
+	var meanX, meanY float64
+	for i := range times {
+		meanX += float64(times[i]-times[0]) / secondsPerDay
+		meanY += float64(usage[i])
+	}
+	meanX /= n
+	meanY /= n
+
+	var covariance, variance float64
+	for i := range times {
+		dx := float64(times[i]-times[0])/secondsPerDay - meanX
+		covariance += dx * (float64(usage[i]) - meanY)
+		variance += dx * dx
+	}
+
+	if variance == 0 {
+		return 0
+	}
+
+	return covariance / variance
+}
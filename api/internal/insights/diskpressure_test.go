@@ -0,0 +1,98 @@
+package insights
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gib = 1 << 30
+
+func diskUsageSample(day int64, dataRoot int64, volumes map[string]int64) portainer.DiskUsageSample {
+	return portainer.DiskUsageSample{
+		Time: day * secondsPerDay,
+		DockerDiskUsage: portainer.DockerDiskUsage{
+			DataRootUsed: dataRoot,
+			Volumes:      volumes,
+		},
+	}
+}
+
+func TestProjectDiskPressure(t *testing.T) {
+	samples := []portainer.DiskUsageSample{
+		diskUsageSample(2, 14*gib, map[string]int64{"db": 4 * gib, "cache": gib}),
+		diskUsageSample(0, 10*gib, map[string]int64{"db": 2 * gib, "cache": gib}),
+		diskUsageSample(1, 12*gib, map[string]int64{"db": 3 * gib, "cache": gib}),
+	}
+
+	pressure := ProjectDiskPressure(samples, 20*gib)
+	require.NotNil(t, pressure)
+
+	assert.Equal(t, int64(14*gib), pressure.DataRoot.Used)
+	assert.InDelta(t, 2*gib, pressure.DataRoot.GrowthPerDay, 1)
+	require.NotNil(t, pressure.DataRoot.DaysUntilFull)
+	assert.InDelta(t, 3, *pressure.DataRoot.DaysUntilFull, 0.001)
+	assert.True(t, pressure.DataRoot.Advisory)
+
+	require.Len(t, pressure.Volumes, 2)
+
+	cache := pressure.Volumes[0]
+	assert.Equal(t, "cache", cache.Name)
+	assert.Zero(t, cache.GrowthPerDay)
+	assert.Nil(t, cache.DaysUntilFull)
+	assert.False(t, cache.Advisory)
+
+	db := pressure.Volumes[1]
+	assert.Equal(t, "db", db.Name)
+	assert.InDelta(t, gib, db.GrowthPerDay, 1)
+	require.NotNil(t, db.DaysUntilFull)
+	assert.InDelta(t, 6, *db.DaysUntilFull, 0.001)
+	assert.True(t, db.Advisory)
+}
+
+func TestProjectDiskPressure_UnknownCapacity(t *testing.T) {
+	samples := []portainer.DiskUsageSample{
+		diskUsageSample(0, 10*gib, nil),
+		diskUsageSample(1, 12*gib, nil),
+	}
+
+	pressure := ProjectDiskPressure(samples, 0)
+	require.NotNil(t, pressure)
+
+	assert.InDelta(t, 2*gib, pressure.DataRoot.GrowthPerDay, 1)
+	assert.Nil(t, pressure.DataRoot.DaysUntilFull)
+	assert.False(t, pressure.DataRoot.Advisory)
+	assert.Empty(t, pressure.Volumes)
+}
+
+func TestProjectDiskPressure_ReportedCapacity(t *testing.T) {
+	samples := []portainer.DiskUsageSample{
+		diskUsageSample(0, 10*gib, nil),
+		diskUsageSample(1, 12*gib, nil),
+	}
+	samples[1].DataRootCapacity = 20 * gib
+
+	// The size of the disk reported with the latest sample is used
+	pressure := ProjectDiskPressure(samples, 0)
+	require.NotNil(t, pressure)
+
+	assert.Equal(t, int64(20*gib), pressure.Capacity)
+	require.NotNil(t, pressure.DataRoot.DaysUntilFull)
+	assert.InDelta(t, 4, *pressure.DataRoot.DaysUntilFull, 0.001)
+
+	// The size set on the environment overrides it
+	pressure = ProjectDiskPressure(samples, 40*gib)
+	require.NotNil(t, pressure)
+
+	assert.Equal(t, int64(40*gib), pressure.Capacity)
+	require.NotNil(t, pressure.DataRoot.DaysUntilFull)
+	assert.InDelta(t, 14, *pressure.DataRoot.DaysUntilFull, 0.001)
+}
+
+func TestProjectDiskPressure_NotEnoughSamples(t *testing.T) {
+	assert.Nil(t, ProjectDiskPressure(nil, 20*gib))
+	assert.Nil(t, ProjectDiskPressure([]portainer.DiskUsageSample{diskUsageSample(0, gib, nil)}, 20*gib))
+}
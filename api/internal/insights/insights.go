@@ -0,0 +1,213 @@
+package insights
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	dockerclient "github.com/portainer/portainer/api/docker/client"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/events"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// RestartLoopWindow is the period of time inspected to detect containers in a restart loop
+	RestartLoopWindow = 10 * time.Minute
+	// RestartLoopThreshold is the number of restarts within RestartLoopWindow above which a container is considered in a restart loop
+	RestartLoopThreshold = 3
+	// LogTailLines is the number of log lines returned for each container in a restart loop
+	LogTailLines = 20
+	// CheckInterval is the interval at which the environments are checked for containers in a restart loop
+	CheckInterval = 5 * time.Minute
+
+	dockerClientTimeout = 30 * time.Second
+)
+
+// Insights represents the advisory information computed for an environment(endpoint)
+type Insights struct {
+	// Containers restarting repeatedly
+	RestartLoops []RestartLoop `json:"RestartLoops"`
+	// Projected disk usage growth, omitted when there is not enough history
+	DiskPressure *DiskPressure `json:"DiskPressure,omitempty"`
+}
+
+// Service computes insights about environments(endpoints)
+type Service struct {
+	dataStore           dataservices.DataStore
+	dockerClientFactory *dockerclient.ClientFactory
+
+	mu sync.Mutex
+	// Containers of each environment that were in a restart loop at the last check, they are notified once
+	restartLoops map[portainer.EndpointID]map[string]bool
+	// Disk consumers of each environment that were projected to fill the disk at the last check
+	diskAdvisories map[portainer.EndpointID]map[string]bool
+}
+
+// NewService returns a new instance of a service
+func NewService(dataStore dataservices.DataStore, dockerClientFactory *dockerclient.ClientFactory) *Service {
+	return &Service{
+		dataStore:           dataStore,
+		dockerClientFactory: dockerClientFactory,
+		restartLoops:        make(map[portainer.EndpointID]map[string]bool),
+		diskAdvisories:      make(map[portainer.EndpointID]map[string]bool),
+	}
+}
+
+// EndpointInsights computes the insights of the given environment(endpoint)
+func (service *Service) EndpointInsights(ctx context.Context, endpoint *portainer.Endpoint) (*Insights, error) {
+	insights := &Insights{
+		RestartLoops: []RestartLoop{},
+	}
+
+	if !endpointutils.IsDockerEndpoint(endpoint) {
+		return insights, nil
+	}
+
+	var err error
+	insights.RestartLoops, err = service.detectRestartLoops(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	insights.DiskPressure, err = service.projectDiskPressure(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return insights, nil
+}
+
+// CheckEndpoints looks for the containers in a restart loop and the disks projected to be full on the Docker
+// environments, it raises an event for each new restart loop or advisory and is meant to be run periodically.
+// The containers of the Edge environments are not checked, they are only reached through a tunnel opened on demand
+func (service *Service) CheckEndpoints() error {
+	endpoints, err := service.dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return err
+	}
+
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if !endpointutils.IsDockerEndpoint(endpoint) {
+			continue
+		}
+
+		if _, err := service.projectDiskPressure(endpoint); err != nil {
+			log.Debug().Err(err).Int("endpoint_id", int(endpoint.ID)).Msg("unable to project the disk pressure of the environment")
+		}
+
+		if endpointutils.IsEdgeEndpoint(endpoint) || endpoint.Status != portainer.EndpointStatusUp {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), dockerClientTimeout)
+		_, err := service.detectRestartLoops(ctx, endpoint)
+		cancel()
+
+		if err != nil {
+			log.Debug().Err(err).Int("endpoint_id", int(endpoint.ID)).Msg("unable to check the containers of the environment for restart loops")
+		}
+	}
+
+	return nil
+}
+
+// projectDiskPressure projects the disk pressure of the environment from its disk usage history and notifies
+// the advisories that were not raised at the previous check
+func (service *Service) projectDiskPressure(endpoint *portainer.Endpoint) (*DiskPressure, error) {
+	samples, err := service.dataStore.DiskUsageSample().ReadAllByEndpointID(endpoint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	pressure := ProjectDiskPressure(samples, endpoint.DiskCapacity)
+	service.notifyDiskPressure(endpoint, pressure)
+
+	return pressure, nil
+}
+
+func (service *Service) notifyDiskPressure(endpoint *portainer.Endpoint, pressure *DiskPressure) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	previous := service.diskAdvisories[endpoint.ID]
+	current := make(map[string]bool)
+
+	if pressure != nil {
+		for _, projection := range append([]DiskProjection{pressure.DataRoot}, pressure.Volumes...) {
+			if !projection.Advisory {
+				continue
+			}
+
+			current[projection.Name] = true
+			if previous[projection.Name] {
+				continue
+			}
+
+			consumer := "The Docker data directory"
+			if projection.Name != "" {
+				consumer = fmt.Sprintf("The volume %s", projection.Name)
+			}
+
+			events.Publish(events.Event{
+				Type:         portainer.NotificationEventDiskPressure,
+				EndpointID:   endpoint.ID,
+				EndpointName: endpoint.Name,
+				Title:        "Disk projected to be full",
+				Message:      fmt.Sprintf("%s is projected to fill the disk in %.1f days", consumer, *projection.DaysUntilFull),
+			})
+		}
+	}
+
+	service.diskAdvisories[endpoint.ID] = current
+}
+
+// detectRestartLoops returns the containers of the environment in a restart loop and notifies the ones
+// that were not in a restart loop at the previous check
+func (service *Service) detectRestartLoops(ctx context.Context, endpoint *portainer.Endpoint) ([]RestartLoop, error) {
+	timeout := dockerClientTimeout
+	cli, err := service.dockerClientFactory.CreateClient(endpoint, "", &timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	loops, err := DetectRestartLoops(ctx, cli, time.Now(), RestartLoopWindow, RestartLoopThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	service.notifyRestartLoops(endpoint, loops)
+
+	return loops, nil
+}
+
+func (service *Service) notifyRestartLoops(endpoint *portainer.Endpoint, loops []RestartLoop) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	previous := service.restartLoops[endpoint.ID]
+	current := make(map[string]bool, len(loops))
+
+	for _, loop := range loops {
+		current[loop.ContainerID] = true
+		if previous[loop.ContainerID] {
+			continue
+		}
+
+		events.Publish(events.Event{
+			Type:         portainer.NotificationEventRestartLoop,
+			EndpointID:   endpoint.ID,
+			EndpointName: endpoint.Name,
+			Title:        "Container in a restart loop",
+			Message:      fmt.Sprintf("The container %s restarted %d times in the last %s, exit codes: %v", loop.ContainerName, loop.Restarts, RestartLoopWindow, loop.ExitCodes),
+		})
+	}
+
+	service.restartLoops[endpoint.ID] = current
+}
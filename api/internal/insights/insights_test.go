@@ -0,0 +1,76 @@
+package insights
+
+import (
+	"sync"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/events"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyRestartLoops(t *testing.T) {
+	var mu sync.Mutex
+	var notified []string
+
+	events.Subscribe(func(event events.Event) {
+		if event.Type != portainer.NotificationEventRestartLoop || event.EndpointID != 42 {
+			return
+		}
+
+		mu.Lock()
+		notified = append(notified, event.Message)
+		mu.Unlock()
+	})
+
+	service := NewService(nil, nil)
+	endpoint := &portainer.Endpoint{ID: 42, Name: "local"}
+
+	web := RestartLoop{ContainerID: "1", ContainerName: "web", Restarts: 4, ExitCodes: []int{1}}
+	db := RestartLoop{ContainerID: "2", ContainerName: "db", Restarts: 3, ExitCodes: []int{137}}
+
+	service.notifyRestartLoops(endpoint, []RestartLoop{web})
+	service.notifyRestartLoops(endpoint, []RestartLoop{web, db})
+	assert.Len(t, notified, 2, "a container is notified once while it keeps restarting")
+
+	// A container that recovered is notified again when it enters a new restart loop
+	service.notifyRestartLoops(endpoint, []RestartLoop{db})
+	service.notifyRestartLoops(endpoint, []RestartLoop{web, db})
+	assert.Len(t, notified, 3)
+	assert.Contains(t, notified[2], "web")
+}
+
+func TestNotifyDiskPressure(t *testing.T) {
+	var mu sync.Mutex
+	var notified []string
+
+	events.Subscribe(func(event events.Event) {
+		if event.Type != portainer.NotificationEventDiskPressure || event.EndpointID != 43 {
+			return
+		}
+
+		mu.Lock()
+		notified = append(notified, event.Message)
+		mu.Unlock()
+	})
+
+	service := NewService(nil, nil)
+	endpoint := &portainer.Endpoint{ID: 43, Name: "local"}
+
+	days := 3.0
+	pressure := &DiskPressure{
+		DataRoot: DiskProjection{DaysUntilFull: &days, Advisory: true},
+		Volumes:  []DiskProjection{{Name: "db", DaysUntilFull: &days, Advisory: true}, {Name: "cache"}},
+	}
+
+	service.notifyDiskPressure(endpoint, pressure)
+	service.notifyDiskPressure(endpoint, pressure)
+	assert.Len(t, notified, 2, "an advisory is raised once while it lasts")
+	assert.Contains(t, notified[1], "db")
+
+	// The advisories are raised again once the disk pressure went away
+	service.notifyDiskPressure(endpoint, nil)
+	service.notifyDiskPressure(endpoint, pressure)
+	assert.Len(t, notified, 4)
+}
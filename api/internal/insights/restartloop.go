@@ -0,0 +1,150 @@
+package insights
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// RestartLoop represents a container that keeps restarting
+type RestartLoop struct {
+	ContainerID   string `json:"ContainerId"`
+	ContainerName string `json:"ContainerName"`
+	Image         string `json:"Image"`
+	// State of the container, e.g. restarting or running
+	State string `json:"State" example:"restarting"`
+	// Total number of restarts reported by Docker
+	RestartCount int `json:"RestartCount"`
+	// Number of times the container died during the inspected window
+	Restarts int `json:"Restarts"`
+	// Exit codes of the container during the inspected window, most recent first
+	ExitCodes []int `json:"ExitCodes"`
+	// Last lines of the container logs
+	LogTail string `json:"LogTail"`
+}
+
+// dockerClient is the subset of the Docker client used to detect restart loops
+type dockerClient interface {
+	ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error)
+	ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error)
+	ContainerLogs(ctx context.Context, containerID string, options container.LogsOptions) (io.ReadCloser, error)
+	Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error)
+}
+
+// DetectRestartLoops looks for containers that died at least threshold times during the window preceding now,
+// or that are currently restarting
+func DetectRestartLoops(ctx context.Context, cli dockerClient, now time.Time, window time.Duration, threshold int) ([]RestartLoop, error) {
+	exitCodes, err := containerExitCodes(ctx, cli, now.Add(-window), now)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, err
+	}
+
+	loops := []RestartLoop{}
+	for _, c := range containers {
+		codes := exitCodes[c.ID]
+		if len(codes) < threshold && c.State != "restarting" {
+			continue
+		}
+
+		details, err := cli.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(codes) == 0 && details.State != nil {
+			codes = []int{details.State.ExitCode}
+		}
+
+		loop := RestartLoop{
+			ContainerID:   c.ID,
+			ContainerName: strings.TrimPrefix(details.Name, "/"),
+			Image:         c.Image,
+			State:         c.State,
+			RestartCount:  details.RestartCount,
+			Restarts:      len(exitCodes[c.ID]),
+			ExitCodes:     codes,
+		}
+
+		tty := details.Config != nil && details.Config.Tty
+		loop.LogTail, err = logTail(ctx, cli, c.ID, tty)
+		if err != nil {
+			return nil, err
+		}
+
+		loops = append(loops, loop)
+	}
+
+	return loops, nil
+}
+
+// containerExitCodes returns the exit codes of the containers that died between since and until, most recent first
+func containerExitCodes(ctx context.Context, cli dockerClient, since, until time.Time) (map[string][]int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages, errs := cli.Events(ctx, types.EventsOptions{
+		Since: strconv.FormatInt(since.Unix(), 10),
+		Until: strconv.FormatInt(until.Unix(), 10),
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.ContainerEventType)),
+			filters.Arg("event", string(events.ActionDie)),
+		),
+	})
+
+	exitCodes := map[string][]int{}
+	for {
+		select {
+		case message := <-messages:
+			exitCode, err := strconv.Atoi(message.Actor.Attributes["exitCode"])
+			if err != nil {
+				continue
+			}
+
+			exitCodes[message.Actor.ID] = append([]int{exitCode}, exitCodes[message.Actor.ID]...)
+		case err := <-errs:
+			if errors.Is(err, io.EOF) {
+				return exitCodes, nil
+			}
+
+			return nil, err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func logTail(ctx context.Context, cli dockerClient, containerID string, tty bool) (string, error) {
+	reader, err := cli.ContainerLogs(ctx, containerID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       strconv.Itoa(LogTailLines),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	var buf bytes.Buffer
+	if tty {
+		_, err = io.Copy(&buf, reader)
+	} else {
+		_, err = stdcopy.StdCopy(&buf, &buf, reader)
+	}
+
+	return buf.String(), err
+}
@@ -0,0 +1,93 @@
+package insights
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDockerClient struct {
+	containers []types.Container
+	events     []events.Message
+}
+
+func (c *testDockerClient) ContainerList(ctx context.Context, options container.ListOptions) ([]types.Container, error) {
+	return c.containers, nil
+}
+
+func (c *testDockerClient) ContainerInspect(ctx context.Context, containerID string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			Name:         "/" + containerID,
+			RestartCount: 7,
+			State:        &types.ContainerState{ExitCode: 137},
+		},
+		Config: &container.Config{Tty: true},
+	}, nil
+}
+
+func (c *testDockerClient) ContainerLogs(ctx context.Context, containerID string, options container.LogsOptions) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("panic: " + containerID)), nil
+}
+
+func (c *testDockerClient) Events(ctx context.Context, options types.EventsOptions) (<-chan events.Message, <-chan error) {
+	messages := make(chan events.Message)
+	errs := make(chan error, 1)
+
+	go func() {
+		for _, m := range c.events {
+			messages <- m
+		}
+
+		errs <- io.EOF
+	}()
+
+	return messages, errs
+}
+
+func dieEvent(containerID, exitCode string) events.Message {
+	return events.Message{
+		Type:   events.ContainerEventType,
+		Action: events.ActionDie,
+		Actor:  events.Actor{ID: containerID, Attributes: map[string]string{"exitCode": exitCode}},
+	}
+}
+
+func TestDetectRestartLoops(t *testing.T) {
+	cli := &testDockerClient{
+		containers: []types.Container{
+			{ID: "flapping", Image: "app:1", State: "running"},
+			{ID: "stable", Image: "db:1", State: "running"},
+			{ID: "restarting", Image: "worker:1", State: "restarting"},
+		},
+		events: []events.Message{
+			dieEvent("flapping", "1"),
+			dieEvent("stable", "0"),
+			dieEvent("flapping", "2"),
+			dieEvent("flapping", "3"),
+		},
+	}
+
+	loops, err := DetectRestartLoops(context.Background(), cli, time.Now(), time.Minute, 3)
+	require.NoError(t, err)
+	require.Len(t, loops, 2)
+
+	assert.Equal(t, "flapping", loops[0].ContainerName)
+	assert.Equal(t, 3, loops[0].Restarts)
+	assert.Equal(t, []int{3, 2, 1}, loops[0].ExitCodes)
+	assert.Equal(t, 7, loops[0].RestartCount)
+	assert.Equal(t, "panic: flapping", loops[0].LogTail)
+
+	assert.Equal(t, "restarting", loops[1].ContainerName)
+	assert.Equal(t, 0, loops[1].Restarts)
+	assert.Equal(t, []int{137}, loops[1].ExitCodes)
+}
@@ -0,0 +1,204 @@
+// Package metrics exposes the internal metrics of the Portainer server in the Prometheus format.
+package metrics
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	namespace = "portainer"
+
+	// maxHandlerLabels bounds the number of distinct values of the handler label of the request metrics
+	maxHandlerLabels = 64
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	apiRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the requests served by the Portainer API.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"handler", "method", "code"})
+
+	snapshotDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "snapshot",
+		Name:      "duration_seconds",
+		Help:      "Duration of the environment snapshots.",
+		Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"platform"})
+
+	snapshotFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "snapshot",
+		Name:      "failures_total",
+		Help:      "Number of environment snapshots that failed.",
+	}, []string{"platform"})
+
+	snapshotQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "snapshot",
+		Name:      "queue_depth",
+		Help:      "Number of environment snapshots due and waiting for a worker.",
+	})
+
+	snapshotsRunning = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "snapshot",
+		Name:      "running",
+		Help:      "Number of environment snapshots currently running in the background.",
+	})
+
+	openTransactions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "database",
+		Name:      "open_transactions",
+		Help:      "Number of database transactions currently open.",
+	}, []string{"mode"})
+
+	databaseQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "database",
+		Name:      "query_duration_seconds",
+		Help:      "Latency of the database queries by bucket and operation.",
+		Buckets:   []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1},
+	}, []string{"bucket", "operation"})
+
+	handlerLabelsMu sync.Mutex
+	handlerLabels   = map[string]http.Handler{}
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		apiRequestDuration,
+		snapshotDuration,
+		snapshotFailures,
+		snapshotQueueDepth,
+		snapshotsRunning,
+		openTransactions,
+		databaseQueryDuration,
+	)
+}
+
+// Handler returns the HTTP handler serving the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// WithRequestMetrics records the latency of the requests served by next, labelled by the API handler serving them
+func WithRequestMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		instrumentedHandler(handlerLabel(r.URL.Path), next).ServeHTTP(w, r)
+	})
+}
+
+func instrumentedHandler(label string, next http.Handler) http.Handler {
+	handlerLabelsMu.Lock()
+	defer handlerLabelsMu.Unlock()
+
+	if h, ok := handlerLabels[label]; ok {
+		return h
+	}
+
+	if len(handlerLabels) >= maxHandlerLabels {
+		label = "other"
+		if h, ok := handlerLabels[label]; ok {
+			return h
+		}
+	}
+
+	h := promhttp.InstrumentHandlerDuration(apiRequestDuration.MustCurryWith(prometheus.Labels{"handler": label}), next)
+	handlerLabels[label] = h
+
+	return h
+}
+
+// handlerLabel returns the first segment of the API paths, e.g. endpoints for /api/endpoints/1, and static for the other paths
+func handlerLabel(path string) string {
+	path, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return "static"
+	}
+
+	segment, _, _ := strings.Cut(path, "/")
+	if segment == "" {
+		return "other"
+	}
+
+	return segment
+}
+
+// ObserveSnapshot records the duration of an environment snapshot and whether it failed
+func ObserveSnapshot(platform string, seconds float64, failed bool) {
+	snapshotDuration.WithLabelValues(platform).Observe(seconds)
+
+	if failed {
+		snapshotFailures.WithLabelValues(platform).Inc()
+	}
+}
+
+// SetSnapshotQueueDepth records the number of environment snapshots due and waiting for a worker
+func SetSnapshotQueueDepth(depth int) {
+	snapshotQueueDepth.Set(float64(depth))
+}
+
+// SnapshotStarted increments the number of background snapshots running and returns the function to call
+// once the snapshot completed
+func SnapshotStarted() func() {
+	snapshotsRunning.Inc()
+
+	return snapshotsRunning.Dec
+}
+
+// TransactionStarted increments the number of open database transactions of the given mode, read or write,
+// and returns the function to call once the transaction is closed
+func TransactionStarted(mode string) func() {
+	gauge := openTransactions.WithLabelValues(mode)
+	gauge.Inc()
+
+	return gauge.Dec
+}
+
+// ObserveDatabaseQuery records the duration of a database query on a bucket
+func ObserveDatabaseQuery(bucket, operation string, seconds float64) {
+	databaseQueryDuration.WithLabelValues(bucket, operation).Observe(seconds)
+}
+
+// RegisterTunnelStatusCounter exposes the number of reverse tunnels per status, as returned by countByStatus
+func RegisterTunnelStatusCounter(countByStatus func() map[string]int) error {
+	return registry.Register(&tunnelCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "tunnel", "count"),
+			"Number of reverse tunnels by status.",
+			[]string{"status"},
+			nil,
+		),
+		countByStatus: countByStatus,
+	})
+}
+
+type tunnelCollector struct {
+	desc          *prometheus.Desc
+	countByStatus func() map[string]int
+}
+
+func (c *tunnelCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *tunnelCollector) Collect(ch chan<- prometheus.Metric) {
+	for status, count := range c.countByStatus() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), status)
+	}
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerLabel(t *testing.T) {
+	assert.Equal(t, "endpoints", handlerLabel("/api/endpoints/1/snapshot"))
+	assert.Equal(t, "status", handlerLabel("/api/status"))
+	assert.Equal(t, "other", handlerLabel("/api/"))
+	assert.Equal(t, "static", handlerLabel("/main.js"))
+}
+
+func TestHandler(t *testing.T) {
+	require.NoError(t, RegisterTunnelStatusCounter(func() map[string]int {
+		return map[string]int{"ACTIVE": 2}
+	}))
+
+	ObserveSnapshot("docker", 1.5, true)
+	SetSnapshotQueueDepth(3)
+	done := TransactionStarted("write")
+	defer done()
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	WithRequestMetrics(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/tags", nil))
+
+	rr := httptest.NewRecorder()
+	Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/metrics", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	body := rr.Body.String()
+	for _, expected := range []string{
+		`portainer_http_request_duration_seconds_count{code="418",handler="tags",method="get"} 1`,
+		`portainer_snapshot_failures_total{platform="docker"} 1`,
+		`portainer_snapshot_queue_depth 3`,
+		`portainer_database_open_transactions{mode="write"} 1`,
+		`portainer_tunnel_count{status="ACTIVE"} 2`,
+	} {
+		assert.True(t, strings.Contains(body, expected), "missing %s", expected)
+	}
+}
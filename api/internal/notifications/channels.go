@@ -0,0 +1,247 @@
+package notifications
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"net/http"
+	"slices"
+	"text/template"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/api/internal/events"
+
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/encoding/json"
+)
+
+// deliveryTimeout is the time waited for a Slack or webhook channel to respond
+const deliveryTimeout = 10 * time.Second
+
+const (
+	defaultEmailTemplate = `<p><strong>{{ .Title }}</strong></p>
+{{ if .Message }}<p>{{ .Message }}</p>{{ end }}
+{{ if .EndpointName }}<p>Environment: {{ .EndpointName }}</p>{{ end }}
+<p>{{ formatTime .Time }}</p>`
+
+	defaultSlackTemplate = `*{{ .Title }}*{{ if .EndpointName }} ({{ .EndpointName }}){{ end }}{{ if .Message }}
+{{ .Message }}{{ end }}`
+)
+
+// EventTypes lists the system events the notification channels can subscribe to
+var EventTypes = []portainer.NotificationEventType{
+	portainer.NotificationEventEndpointDown,
+	portainer.NotificationEventEdgeStackFailure,
+	portainer.NotificationEventAutoUpdateApplied,
+	portainer.NotificationEventBackupFailed,
+	portainer.NotificationEventRestartLoop,
+	portainer.NotificationEventDiskPressure,
+}
+
+var templateFuncs = map[string]any{
+	"formatTime": func(timestamp int64) string {
+		return time.Unix(timestamp, 0).UTC().Format(time.RFC1123)
+	},
+}
+
+// Service delivers the system events to the notification channels subscribed to them
+type Service struct {
+	dataStore   dataservices.DataStore
+	emailSender EmailSender
+	httpClient  *http.Client
+}
+
+// NewService returns a new instance of Service, the requests go through the proxy of the outbound calls
+func NewService(dataStore dataservices.DataStore, emailSender EmailSender) *Service {
+	return &Service{
+		dataStore:   dataStore,
+		emailSender: emailSender,
+		httpClient:  &http.Client{Transport: client.NewTransport()},
+	}
+}
+
+// EventPublished delivers an event to the notification channels in the background
+func (service *Service) EventPublished(event events.Event) {
+	go func() {
+		if err := service.Dispatch(event); err != nil {
+			log.Warn().Err(err).Str("event", string(event.Type)).Msg("unable to notify the event")
+		}
+	}()
+}
+
+// Dispatch delivers an event to the enabled notification channels subscribed to it. A channel that cannot
+// be reached does not prevent the delivery to the other ones
+func (service *Service) Dispatch(event events.Event) error {
+	channels, err := service.dataStore.NotificationChannel().ReadAll()
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the notification channels: %w", err)
+	}
+
+	channels = slices.DeleteFunc(channels, func(channel portainer.NotificationChannel) bool {
+		return !Subscribed(&channel, event.Type)
+	})
+	if len(channels) == 0 {
+		return nil
+	}
+
+	if event.EndpointID != 0 && event.EndpointName == "" {
+		if endpoint, err := service.dataStore.Endpoint().Endpoint(event.EndpointID); err == nil {
+			event.EndpointName = endpoint.Name
+		}
+	}
+
+	for i := range channels {
+		if err := service.Deliver(&channels[i], event); err != nil {
+			log.Warn().
+				Err(err).
+				Str("channel", channels[i].Name).
+				Str("event", string(event.Type)).
+				Msg("unable to deliver the notification to the channel")
+		}
+	}
+
+	return nil
+}
+
+// Subscribed returns true when the channel is enabled and subscribed to the events of the given type
+func Subscribed(channel *portainer.NotificationChannel, eventType portainer.NotificationEventType) bool {
+	return channel.Enabled && slices.Contains(channel.Subscriptions, eventType)
+}
+
+// TestEvent returns the event delivered to check the configuration of a channel
+func TestEvent() events.Event {
+	return events.Event{
+		Title:   "Test notification",
+		Message: "This notification was sent to check the configuration of the notification channel.",
+		Time:    time.Now().Unix(),
+	}
+}
+
+// Deliver sends an event to a notification channel, rendered with the template of the channel
+func (service *Service) Deliver(channel *portainer.NotificationChannel, event events.Event) error {
+	switch channel.Type {
+	case portainer.NotificationChannelEmail:
+		body, err := renderHTML(channel.Template, defaultEmailTemplate, event)
+		if err != nil {
+			return err
+		}
+
+		settings, err := service.dataStore.Settings().Settings()
+		if err != nil {
+			return fmt.Errorf("unable to retrieve the settings: %w", err)
+		}
+
+		return service.emailSender.SendEmail(settings.SMTPSettings, EmailMessage{
+			To:      channel.Recipients,
+			Subject: "[Portainer] " + event.Title,
+			Body:    body,
+		})
+	case portainer.NotificationChannelSlack:
+		text, err := renderText(channel.Template, defaultSlackTemplate, event)
+		if err != nil {
+			return err
+		}
+
+		body, err := json.Marshal(map[string]string{"text": text})
+		if err != nil {
+			return err
+		}
+
+		return service.post(channel.URL, body)
+	case portainer.NotificationChannelWebhook:
+		var body []byte
+		var err error
+		if channel.Template == "" {
+			body, err = json.Marshal(event)
+		} else {
+			var rendered string
+			rendered, err = renderText(channel.Template, "", event)
+			body = []byte(rendered)
+		}
+		if err != nil {
+			return err
+		}
+
+		return service.post(channel.URL, body)
+	}
+
+	return fmt.Errorf("unsupported notification channel type %q", channel.Type)
+}
+
+func (service *Service) post(url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := service.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("the notification channel responded with %s", resp.Status)
+	}
+
+	return nil
+}
+
+// ValidateTemplate returns an error when the template of a channel cannot be parsed, an empty template
+// selects the default template of the channel type
+func ValidateTemplate(channelType portainer.NotificationChannelType, tmpl string) error {
+	if tmpl == "" {
+		return nil
+	}
+
+	var err error
+	if channelType == portainer.NotificationChannelEmail {
+		_, err = htmltemplate.New("notification").Funcs(templateFuncs).Parse(tmpl)
+	} else {
+		_, err = template.New("notification").Funcs(templateFuncs).Parse(tmpl)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid notification template: %w", err)
+	}
+
+	return nil
+}
+
+func renderText(tmpl, defaultTemplate string, event events.Event) (string, error) {
+	t, err := template.New("notification").Funcs(templateFuncs).Parse(cmp.Or(tmpl, defaultTemplate))
+	if err != nil {
+		return "", fmt.Errorf("invalid notification template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, event); err != nil {
+		return "", fmt.Errorf("unable to render the notification: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// renderHTML renders the body of an email, the values of the event are escaped
+func renderHTML(tmpl, defaultTemplate string, event events.Event) (string, error) {
+	t, err := htmltemplate.New("notification").Funcs(templateFuncs).Parse(cmp.Or(tmpl, defaultTemplate))
+	if err != nil {
+		return "", fmt.Errorf("invalid notification template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, event); err != nil {
+		return "", fmt.Errorf("unable to render the notification: %w", err)
+	}
+
+	return buf.String(), nil
+}
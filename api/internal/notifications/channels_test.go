@@ -0,0 +1,80 @@
+package notifications
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/internal/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEmailSender struct {
+	messages []EmailMessage
+}
+
+func (sender *testEmailSender) SendEmail(settings portainer.SMTPSettings, message EmailMessage) error {
+	sender.messages = append(sender.messages, message)
+
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1, Name: "production"}))
+
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, r.URL.Path+" "+string(body))
+	}))
+	defer srv.Close()
+
+	subscriptions := []portainer.NotificationEventType{portainer.NotificationEventEndpointDown}
+
+	for _, channel := range []portainer.NotificationChannel{
+		{Type: portainer.NotificationChannelSlack, URL: srv.URL + "/slack", Subscriptions: subscriptions, Enabled: true},
+		{Type: portainer.NotificationChannelWebhook, URL: srv.URL + "/webhook", Subscriptions: subscriptions, Enabled: true, Template: `{"env": "{{ .EndpointName }}"}`},
+		{Type: portainer.NotificationChannelEmail, Recipients: []string{"ops@example.com"}, Subscriptions: subscriptions, Enabled: true},
+		// Disabled or not subscribed channels are left out
+		{Type: portainer.NotificationChannelWebhook, URL: srv.URL + "/disabled", Subscriptions: subscriptions},
+		{Type: portainer.NotificationChannelWebhook, URL: srv.URL + "/unsubscribed", Enabled: true},
+	} {
+		require.NoError(t, store.NotificationChannel().Create(&channel))
+	}
+
+	sender := &testEmailSender{}
+	service := NewService(store, sender)
+
+	err := service.Dispatch(events.Event{
+		Type:       portainer.NotificationEventEndpointDown,
+		EndpointID: 1,
+		Title:      "Environment down",
+		Message:    "The environment <production> cannot be reached",
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		`/slack {"text":"*Environment down* (production)\nThe environment \u003cproduction\u003e cannot be reached"}`,
+		`/webhook {"env": "production"}`,
+	}, bodies)
+
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, []string{"ops@example.com"}, sender.messages[0].To)
+	assert.Equal(t, "[Portainer] Environment down", sender.messages[0].Subject)
+	// The values of the event are escaped in the emails
+	assert.Contains(t, sender.messages[0].Body, "The environment &lt;production&gt; cannot be reached")
+}
+
+func TestValidateTemplate(t *testing.T) {
+	assert.NoError(t, ValidateTemplate(portainer.NotificationChannelSlack, ""))
+	assert.NoError(t, ValidateTemplate(portainer.NotificationChannelSlack, "{{ .Title }} at {{ formatTime .Time }}"))
+	assert.Error(t, ValidateTemplate(portainer.NotificationChannelWebhook, "{{ .Title "))
+	assert.Error(t, ValidateTemplate(portainer.NotificationChannelEmail, "{{ if .Title }}"))
+}
@@ -0,0 +1,201 @@
+package notifications
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+const smtpTimeout = 30 * time.Second
+
+// ErrEmailDisabled is returned when an email is sent while no SMTP server is configured
+var ErrEmailDisabled = errors.New("email notifications are not configured")
+
+// EmailMessage represents an email sent through the notification channel
+type EmailMessage struct {
+	To      []string
+	Subject string
+	// HTML body of the email
+	Body string
+}
+
+// EmailSender sends the emails of the notification channel
+type EmailSender interface {
+	SendEmail(settings portainer.SMTPSettings, message EmailMessage) error
+}
+
+// SMTPSender sends the emails through the SMTP server defined in the settings
+type SMTPSender struct{}
+
+// NewSMTPSender returns a new instance of SMTPSender
+func NewSMTPSender() *SMTPSender {
+	return &SMTPSender{}
+}
+
+// IsEmailEnabled returns true when an SMTP server is configured
+func IsEmailEnabled(settings portainer.SMTPSettings) bool {
+	return settings.Host != ""
+}
+
+// ValidateSMTPSettings validates the SMTP settings, empty settings disable the email notifications
+func ValidateSMTPSettings(settings portainer.SMTPSettings) error {
+	if !IsEmailEnabled(settings) {
+		return nil
+	}
+
+	if settings.Port < 1 || settings.Port > 65535 {
+		return errors.New("invalid SMTP port")
+	}
+
+	if _, err := mail.ParseAddress(settings.From); err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateRecipients validates a list of email addresses
+func ValidateRecipients(recipients []string) error {
+	for _, recipient := range recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			return fmt.Errorf("invalid recipient address %q: %w", recipient, err)
+		}
+	}
+
+	return nil
+}
+
+// SendEmail sends an email, the connection is upgraded with STARTTLS when the server supports it
+func (sender *SMTPSender) SendEmail(settings portainer.SMTPSettings, message EmailMessage) error {
+	if !IsEmailEnabled(settings) {
+		return ErrEmailDisabled
+	}
+
+	from, err := mail.ParseAddress(settings.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	recipients := make([]*mail.Address, 0, len(message.To))
+	for _, to := range message.To {
+		recipient, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("invalid recipient address %q: %w", to, err)
+		}
+
+		recipients = append(recipients, recipient)
+	}
+
+	if len(recipients) == 0 {
+		return errors.New("the email has no recipient")
+	}
+
+	data, err := buildMessage(from, recipients, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(settings.Host, strconv.Itoa(settings.Port))
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	tlsConfig := &tls.Config{ServerName: settings.Host, MinVersion: tls.VersionTLS12}
+
+	var conn net.Conn
+	if settings.TLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to reach the SMTP server: %w", err)
+	}
+
+	if err := conn.SetDeadline(time.Now().Add(smtpTimeout)); err != nil {
+		conn.Close()
+
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, settings.Host)
+	if err != nil {
+		conn.Close()
+
+		return fmt.Errorf("unable to reach the SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && !settings.TLS {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("unable to secure the connection to the SMTP server: %w", err)
+		}
+	}
+
+	if settings.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", settings.Username, settings.Password, settings.Host)); err != nil {
+			return fmt.Errorf("unable to authenticate against the SMTP server: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient.Address); err != nil {
+			return fmt.Errorf("the recipient %s was refused: %w", recipient.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// buildMessage formats an HTML email, the body is encoded as quoted-printable
+func buildMessage(from *mail.Address, recipients []*mail.Address, message EmailMessage, date time.Time) ([]byte, error) {
+	to := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		to = append(to, recipient.String())
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/html; charset=\"utf-8\"\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	w := quotedprintable.NewWriter(&buf)
+	if _, err := w.Write([]byte(message.Body)); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,41 @@
+package notifications
+
+import (
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSMTPSettings(t *testing.T) {
+	assert.NoError(t, ValidateSMTPSettings(portainer.SMTPSettings{}))
+	assert.NoError(t, ValidateSMTPSettings(portainer.SMTPSettings{Host: "smtp.example.com", Port: 587, From: "Portainer <portainer@example.com>"}))
+	assert.Error(t, ValidateSMTPSettings(portainer.SMTPSettings{Host: "smtp.example.com", From: "portainer@example.com"}))
+	assert.Error(t, ValidateSMTPSettings(portainer.SMTPSettings{Host: "smtp.example.com", Port: 587, From: "portainer"}))
+}
+
+func TestBuildMessage(t *testing.T) {
+	from := &mail.Address{Name: "Portainer", Address: "portainer@example.com"}
+	to := []*mail.Address{{Address: "ops@example.com"}, {Address: "dev@example.com"}}
+
+	data, err := buildMessage(from, to, EmailMessage{
+		Subject: "Weekly report\r\nBcc: attacker@example.com",
+		Body:    "<p>déploiements</p>",
+	}, time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	headers, body, found := strings.Cut(string(data), "\r\n\r\n")
+	require.True(t, found)
+
+	assert.Contains(t, headers, "From: \"Portainer\" <portainer@example.com>\r\n")
+	assert.Contains(t, headers, "To: <ops@example.com>, <dev@example.com>\r\n")
+	assert.Contains(t, headers, "Date: Mon, 01 Jan 2024 08:00:00 +0000\r\n")
+	// The line breaks of the subject cannot inject headers
+	assert.NotContains(t, headers, "\r\nBcc:")
+	assert.Equal(t, "<p>d=C3=A9ploiements</p>", body)
+}
@@ -0,0 +1,206 @@
+package ociartifact
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/registryclient"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TitleAnnotation is set by oras on the layers of an artifact, it holds the name of the file of the layer
+	TitleAnnotation = "org.opencontainers.image.title"
+	// unpackAnnotation is set by oras on the layers holding a directory packed in a tarball
+	unpackAnnotation = "io.deis.oras.content.unpack"
+	// maxFileSize bounds the size of the files pulled from an artifact
+	maxFileSize = 10 << 20
+)
+
+var (
+	// ErrNoFiles is returned when the manifest of an artifact has no layer holding a file
+	ErrNoFiles = errors.New("the artifact holds no file")
+	// ErrNotAnArtifact is returned when the reference of an artifact points to a list of manifests
+	ErrNotAnArtifact = errors.New("the reference points to a list of manifests instead of an artifact")
+)
+
+// Registry reads the manifests and the blobs of the repositories of a registry
+type Registry interface {
+	Manifest(ctx context.Context, repository, reference string) (*registryclient.Manifest, error)
+	ManifestDigest(ctx context.Context, repository, tag string) (string, error)
+	Blob(ctx context.Context, repository, blobDigest string) (io.ReadCloser, error)
+}
+
+// Service pulls the files of the OCI artifacts hosted by the registries managed by Portainer
+type Service struct {
+	dataStore       dataservices.DataStore
+	registryClients *registryclient.Service
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService(dataStore dataservices.DataStore) *Service {
+	return &Service{
+		dataStore:       dataStore,
+		registryClients: registryclient.NewService(dataStore),
+	}
+}
+
+// ValidateConfig ensures that the configuration of an artifact designates a repository of a registry and a tag or a digest
+func ValidateConfig(config *portainer.OCIConfig) error {
+	if config.RegistryID == 0 {
+		return errors.New("invalid registry identifier")
+	}
+
+	if err := registryclient.ValidateRepository(config.Repository); err != nil {
+		return err
+	}
+
+	return registryclient.ValidateReference(config.Reference)
+}
+
+// IsPinned returns true when the reference of the artifact is a digest, the stack then always deploys the same files
+func IsPinned(config *portainer.OCIConfig) bool {
+	_, err := digest.Parse(config.Reference)
+
+	return err == nil
+}
+
+// LatestDigest returns the digest of the manifest the reference of the artifact currently points to
+func (service *Service) LatestDigest(ctx context.Context, config *portainer.OCIConfig) (string, error) {
+	if IsPinned(config) {
+		return config.Reference, nil
+	}
+
+	registry, err := service.registry(config)
+	if err != nil {
+		return "", err
+	}
+
+	return registry.ManifestDigest(ctx, config.Repository, config.Reference)
+}
+
+// Pull replaces the content of dir by the files of the artifact and returns the digest of its manifest
+func (service *Service) Pull(ctx context.Context, config *portainer.OCIConfig, dir string) (string, error) {
+	registry, err := service.registry(config)
+	if err != nil {
+		return "", err
+	}
+
+	return Pull(ctx, registry, config.Repository, config.Reference, dir)
+}
+
+func (service *Service) registry(config *portainer.OCIConfig) (Registry, error) {
+	registry, err := service.dataStore.Registry().Read(config.RegistryID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "unable to find the registry %d", config.RegistryID)
+	}
+
+	return service.registryClients.NewClient(registry)
+}
+
+// Pull replaces the content of dir by the files of the artifact matching the reference and returns the digest of its manifest.
+// Every layer of the artifact annotated with a title is written to a file of that name, the files are downloaded
+// next to dir first so that dir is left untouched when the pull fails
+func Pull(ctx context.Context, registry Registry, repository, reference, dir string) (string, error) {
+	manifest, err := registry.Manifest(ctx, repository, reference)
+	if err != nil {
+		return "", errors.WithMessagef(err, "unable to retrieve the manifest of %s:%s", repository, reference)
+	}
+
+	if len(manifest.Manifests) > 0 {
+		return "", ErrNotAnArtifact
+	}
+
+	if strings.Contains(reference, ":") && manifest.Digest != reference {
+		return "", fmt.Errorf("the registry returned the manifest %s instead of %s", manifest.Digest, reference)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0o755); err != nil {
+		return "", err
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dir), filepath.Base(dir)+".pull-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files := 0
+	for _, layer := range manifest.Layers {
+		name := layer.Annotations[TitleAnnotation]
+		if name == "" {
+			continue
+		}
+
+		if layer.Annotations[unpackAnnotation] == "true" {
+			return "", fmt.Errorf("the layer %s holds a directory, only files are supported", name)
+		}
+
+		if !filepath.IsLocal(name) {
+			return "", fmt.Errorf("invalid file name in the artifact: %q", name)
+		}
+
+		if err := pullFile(ctx, registry, repository, layer, filepath.Join(tmpDir, name)); err != nil {
+			return "", errors.WithMessagef(err, "unable to pull the file %s", name)
+		}
+
+		files++
+	}
+
+	if files == 0 {
+		return "", ErrNoFiles
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+
+	return manifest.Digest, os.Rename(tmpDir, dir)
+}
+
+// pullFile downloads a layer to a file, checking its size and its digest
+func pullFile(ctx context.Context, registry Registry, repository string, layer registryclient.Descriptor, path string) error {
+	if layer.Size > maxFileSize {
+		return fmt.Errorf("the file exceeds the maximum size of %d bytes", maxFileSize)
+	}
+
+	layerDigest, err := digest.Parse(layer.Digest)
+	if err != nil {
+		return err
+	}
+
+	blob, err := registry.Blob(ctx, repository, layer.Digest)
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	verifier := layerDigest.Verifier()
+	if _, err := io.Copy(io.MultiWriter(file, verifier), io.LimitReader(blob, maxFileSize)); err != nil {
+		return err
+	}
+
+	if !verifier.Verified() {
+		return fmt.Errorf("the content of the file does not match the digest %s", layer.Digest)
+	}
+
+	return file.Close()
+}
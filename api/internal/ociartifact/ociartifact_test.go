@@ -0,0 +1,135 @@
+package ociartifact
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/internal/registryclient"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRegistry struct {
+	manifests map[string]*registryclient.Manifest
+	blobs     map[string][]byte
+}
+
+func (registry *testRegistry) Manifest(ctx context.Context, repository, reference string) (*registryclient.Manifest, error) {
+	manifest, ok := registry.manifests[reference]
+	if !ok {
+		return nil, registryclient.ErrNotFound
+	}
+
+	return manifest, nil
+}
+
+func (registry *testRegistry) ManifestDigest(ctx context.Context, repository, tag string) (string, error) {
+	manifest, err := registry.Manifest(ctx, repository, tag)
+	if err != nil {
+		return "", err
+	}
+
+	return manifest.Digest, nil
+}
+
+func (registry *testRegistry) Blob(ctx context.Context, repository, blobDigest string) (io.ReadCloser, error) {
+	blob, ok := registry.blobs[blobDigest]
+	if !ok {
+		return nil, registryclient.ErrNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(blob)), nil
+}
+
+func (registry *testRegistry) push(reference, manifestDigest string, files map[string]string) {
+	manifest := &registryclient.Manifest{Digest: manifestDigest}
+	for name, content := range files {
+		blobDigest := digest.FromString(content).String()
+		registry.blobs[blobDigest] = []byte(content)
+
+		manifest.Layers = append(manifest.Layers, registryclient.Descriptor{
+			Digest:      blobDigest,
+			Size:        int64(len(content)),
+			Annotations: map[string]string{TitleAnnotation: name},
+		})
+	}
+
+	registry.manifests[reference] = manifest
+	registry.manifests[manifestDigest] = manifest
+}
+
+func TestPull(t *testing.T) {
+	registry := &testRegistry{manifests: map[string]*registryclient.Manifest{}, blobs: map[string][]byte{}}
+
+	v1 := digest.FromString("v1").String()
+	registry.push("latest", v1, map[string]string{
+		"docker-compose.yml": "services:\n  web:\n    image: nginx\n",
+		"config/app.env":     "MODE=production\n",
+	})
+
+	dir := filepath.Join(t.TempDir(), "compose", "1")
+
+	manifestDigest, err := Pull(context.Background(), registry, "myorg/mystack", "latest", dir)
+	require.NoError(t, err)
+	assert.Equal(t, v1, manifestDigest)
+
+	content, err := os.ReadFile(filepath.Join(dir, "config", "app.env"))
+	require.NoError(t, err)
+	assert.Equal(t, "MODE=production\n", string(content))
+
+	// A new version replaces the files of the previous one
+	v2 := digest.FromString("v2").String()
+	registry.push("latest", v2, map[string]string{"docker-compose.yml": "services:\n  web:\n    image: nginx:alpine\n"})
+
+	manifestDigest, err = Pull(context.Background(), registry, "myorg/mystack", "latest", dir)
+	require.NoError(t, err)
+	assert.Equal(t, v2, manifestDigest)
+	assert.NoFileExists(t, filepath.Join(dir, "config", "app.env"))
+
+	// The pinned digest still pulls the first version
+	manifestDigest, err = Pull(context.Background(), registry, "myorg/mystack", v1, dir)
+	require.NoError(t, err)
+	assert.Equal(t, v1, manifestDigest)
+	assert.FileExists(t, filepath.Join(dir, "config", "app.env"))
+}
+
+func TestPullRejectsInvalidArtifacts(t *testing.T) {
+	registry := &testRegistry{manifests: map[string]*registryclient.Manifest{}, blobs: map[string][]byte{}}
+
+	registry.push("escape", digest.FromString("escape").String(), map[string]string{"../docker-compose.yml": "services: {}"})
+	registry.manifests["empty"] = &registryclient.Manifest{Digest: digest.FromString("empty").String()}
+
+	tampered := digest.FromString("tampered").String()
+	registry.push("tampered", tampered, map[string]string{"docker-compose.yml": "services: {}"})
+	registry.blobs[registry.manifests["tampered"].Layers[0].Digest] = []byte("services: {evil: {}}")
+
+	dir := filepath.Join(t.TempDir(), "1")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte("previous"), 0o644))
+
+	_, err := Pull(context.Background(), registry, "myorg/mystack", "escape", dir)
+	require.Error(t, err)
+
+	_, err = Pull(context.Background(), registry, "myorg/mystack", "empty", dir)
+	require.ErrorIs(t, err, ErrNoFiles)
+
+	_, err = Pull(context.Background(), registry, "myorg/mystack", "tampered", dir)
+	require.Error(t, err)
+
+	// The files of the stack are left untouched by the failed pulls
+	content, err := os.ReadFile(filepath.Join(dir, "docker-compose.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(content))
+}
+
+func TestIsPinned(t *testing.T) {
+	assert.True(t, IsPinned(&portainer.OCIConfig{Reference: digest.FromString("v1").String()}))
+	assert.False(t, IsPinned(&portainer.OCIConfig{Reference: "latest"}))
+}
@@ -0,0 +1,397 @@
+package quotas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	dockerclient "github.com/portainer/portainer/api/docker/client"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/stacks/stackutils"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/swarm"
+)
+
+const (
+	labelDockerServiceID        = "com.docker.swarm.service.id"
+	labelDockerSwarmStackName   = "com.docker.stack.namespace"
+	labelDockerComposeStackName = "com.docker.compose.project"
+	nanoCPUsPerCPU              = 1e9
+)
+
+var (
+	// ErrQuotaExceeded is returned when a deployment would exceed a quota
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrLimitRequired is returned when a deployment does not declare a resource limit enforced by a quota
+	ErrLimitRequired = errors.New("resource limit required")
+)
+
+// IsViolation returns whether the error is due to a deployment that does not comply with a quota
+func IsViolation(err error) bool {
+	return errors.Is(err, ErrQuotaExceeded) || errors.Is(err, ErrLimitRequired)
+}
+
+// Usage holds the resources deployed on an environment(endpoint), or the resources requested by a deployment
+type Usage struct {
+	Stacks     int
+	Containers int
+	// CPUs in units of 10^-9 CPUs
+	NanoCPUs int64
+	// Memory in bytes
+	Memory int64
+}
+
+// Service checks that the deployments made by non-administrators comply with the quotas
+type Service struct {
+	dataStore           dataservices.DataStore
+	dockerClientFactory *dockerclient.ClientFactory
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService(dataStore dataservices.DataStore, dockerClientFactory *dockerclient.ClientFactory) *Service {
+	return &Service{
+		dataStore:           dataStore,
+		dockerClientFactory: dockerClientFactory,
+	}
+}
+
+// scope selects the resources counted against a quota
+type scope struct {
+	teamID    portainer.TeamID
+	memberIDs map[portainer.UserID]bool
+}
+
+// includes returns whether a resource protected by the resource control is counted against the quota.
+// All the resources are counted against the quotas of environment(endpoint) groups
+func (s scope) includes(resourceControl *portainer.ResourceControl) bool {
+	if s.teamID == 0 {
+		return true
+	}
+
+	if resourceControl == nil {
+		return false
+	}
+
+	for _, access := range resourceControl.TeamAccesses {
+		if access.TeamID == s.teamID {
+			return true
+		}
+	}
+
+	for _, access := range resourceControl.UserAccesses {
+		if s.memberIDs[access.UserID] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check returns an error when deploying the requested resources on the environment(endpoint) would not comply
+// with one of the quotas that apply to the user. Administrators are not subject to quotas
+func (service *Service) Check(ctx context.Context, endpoint *portainer.Endpoint, userID portainer.UserID, requested Usage) error {
+	user, err := service.dataStore.User().Read(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == portainer.AdministratorRole {
+		return nil
+	}
+
+	quotas, err := service.applicableQuotas(endpoint, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, quota := range quotas {
+		if err := checkLimitsDeclared(quota, requested); err != nil {
+			return err
+		}
+
+		if !enforces(quota, requested) {
+			continue
+		}
+
+		usage, err := service.usage(ctx, quota, endpoint, requested)
+		if err != nil {
+			return err
+		}
+
+		if err := checkQuota(quota, usage, requested); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applicableQuotas returns the quotas of the group of the environment(endpoint) and of the teams of the user
+func (service *Service) applicableQuotas(endpoint *portainer.Endpoint, userID portainer.UserID) ([]portainer.Quota, error) {
+	quotas, err := service.dataStore.Quota().ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := service.dataStore.TeamMembership().TeamMembershipsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	applicable := make([]portainer.Quota, 0)
+	for _, quota := range quotas {
+		if quota.EndpointGroupID != 0 && quota.EndpointGroupID == endpoint.GroupID {
+			applicable = append(applicable, quota)
+
+			continue
+		}
+
+		if quota.TeamID != 0 && slices.ContainsFunc(memberships, func(membership portainer.TeamMembership) bool {
+			return membership.TeamID == quota.TeamID
+		}) {
+			applicable = append(applicable, quota)
+		}
+	}
+
+	return applicable, nil
+}
+
+// checkLimitsDeclared ensures that new containers declare the limits of the resources capped by the quota,
+// otherwise their consumption could not be accounted for
+func checkLimitsDeclared(quota portainer.Quota, requested Usage) error {
+	if requested.Containers == 0 {
+		return nil
+	}
+
+	if quota.MaxCPU > 0 && requested.NanoCPUs == 0 {
+		return fmt.Errorf("%w: a CPU limit is required by the quota %s", ErrLimitRequired, quota.Name)
+	}
+
+	if quota.MaxMemory > 0 && requested.Memory == 0 {
+		return fmt.Errorf("%w: a memory limit is required by the quota %s", ErrLimitRequired, quota.Name)
+	}
+
+	return nil
+}
+
+// enforces returns whether the quota caps one of the requested resources
+func enforces(quota portainer.Quota, requested Usage) bool {
+	return (quota.MaxStacks > 0 && requested.Stacks > 0) ||
+		(quota.MaxContainers > 0 && requested.Containers > 0) ||
+		(quota.MaxCPU > 0 && requested.NanoCPUs > 0) ||
+		(quota.MaxMemory > 0 && requested.Memory > 0)
+}
+
+func checkQuota(quota portainer.Quota, usage, requested Usage) error {
+	if quota.MaxStacks > 0 && requested.Stacks > 0 && usage.Stacks+requested.Stacks > quota.MaxStacks {
+		return quotaExceededError(quota, fmt.Sprint(quota.MaxStacks), "stacks", fmt.Sprint(usage.Stacks))
+	}
+
+	if quota.MaxContainers > 0 && requested.Containers > 0 && usage.Containers+requested.Containers > quota.MaxContainers {
+		return quotaExceededError(quota, fmt.Sprint(quota.MaxContainers), "containers", fmt.Sprint(usage.Containers))
+	}
+
+	if maxNanoCPUs := int64(quota.MaxCPU * nanoCPUsPerCPU); maxNanoCPUs > 0 && requested.NanoCPUs > 0 && usage.NanoCPUs+requested.NanoCPUs > maxNanoCPUs {
+		return quotaExceededError(quota, fmt.Sprint(quota.MaxCPU), "CPUs", fmt.Sprint(float64(usage.NanoCPUs)/nanoCPUsPerCPU))
+	}
+
+	if quota.MaxMemory > 0 && requested.Memory > 0 && usage.Memory+requested.Memory > quota.MaxMemory {
+		return quotaExceededError(quota, fmt.Sprint(quota.MaxMemory), "bytes of memory", fmt.Sprint(usage.Memory))
+	}
+
+	return nil
+}
+
+func quotaExceededError(quota portainer.Quota, limit, resource, used string) error {
+	return fmt.Errorf("%w: the quota %s allows %s %s on the environment, %s in use", ErrQuotaExceeded, quota.Name, limit, resource, used)
+}
+
+// usage returns the resources counted against the quota on the environment(endpoint).
+// Only the resources capped by the quota and requested by the deployment are counted
+func (service *Service) usage(ctx context.Context, quota portainer.Quota, endpoint *portainer.Endpoint, requested Usage) (Usage, error) {
+	s, err := service.scope(quota)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	resourceControls, err := service.dataStore.ResourceControl().ReadAll()
+	if err != nil {
+		return Usage{}, err
+	}
+
+	var usage Usage
+
+	if quota.MaxStacks > 0 && requested.Stacks > 0 {
+		if usage.Stacks, err = service.stacksUsage(endpoint, s, resourceControls); err != nil {
+			return Usage{}, err
+		}
+	}
+
+	if requested.Containers > 0 && endpointutils.IsDockerEndpoint(endpoint) {
+		containersUsage, err := service.containersUsage(ctx, quota, endpoint, s, resourceControls)
+		if err != nil {
+			return Usage{}, err
+		}
+
+		usage.Containers = containersUsage.Containers
+		usage.NanoCPUs = containersUsage.NanoCPUs
+		usage.Memory = containersUsage.Memory
+	}
+
+	return usage, nil
+}
+
+func (service *Service) scope(quota portainer.Quota) (scope, error) {
+	s := scope{teamID: quota.TeamID}
+	if quota.TeamID == 0 {
+		return s, nil
+	}
+
+	memberships, err := service.dataStore.TeamMembership().TeamMembershipsByTeamID(quota.TeamID)
+	if err != nil {
+		return scope{}, err
+	}
+
+	s.memberIDs = make(map[portainer.UserID]bool, len(memberships))
+	for _, membership := range memberships {
+		s.memberIDs[membership.UserID] = true
+	}
+
+	return s, nil
+}
+
+func (service *Service) stacksUsage(endpoint *portainer.Endpoint, s scope, resourceControls []portainer.ResourceControl) (int, error) {
+	stacks, err := service.dataStore.Stack().ReadAll()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, stack := range stacks {
+		if stack.EndpointID != endpoint.ID {
+			continue
+		}
+
+		resourceControl := authorization.GetResourceControlByResourceIDAndType(stackutils.ResourceControlID(endpoint.ID, stack.Name), portainer.StackResourceControl, resourceControls)
+		if s.includes(resourceControl) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// containersUsage counts the containers of the environment(endpoint), the stopped ones included.
+// On Swarm managers the tasks of the services are counted from the specification of the services
+func (service *Service) containersUsage(ctx context.Context, quota portainer.Quota, endpoint *portainer.Endpoint, s scope, resourceControls []portainer.ResourceControl) (Usage, error) {
+	cli, err := service.dockerClientFactory.CreateClient(endpoint, "", nil)
+	if err != nil {
+		return Usage{}, err
+	}
+	defer cli.Close()
+
+	info, err := cli.Info(ctx)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	isSwarmManager := info.Swarm.ControlAvailable && info.Swarm.NodeID != ""
+
+	containers, err := cli.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return Usage{}, err
+	}
+
+	var usage Usage
+
+	for _, c := range containers {
+		if isSwarmManager && c.Labels[labelDockerServiceID] != "" {
+			continue
+		}
+
+		if !s.includes(findResourceControl(endpoint.ID, c.ID, portainer.ContainerResourceControl, c.Labels, resourceControls)) {
+			continue
+		}
+
+		usage.Containers++
+
+		if quota.MaxCPU == 0 && quota.MaxMemory == 0 {
+			continue
+		}
+
+		inspect, err := cli.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return Usage{}, err
+		}
+
+		if inspect.HostConfig != nil {
+			usage.NanoCPUs += inspect.HostConfig.NanoCPUs
+			usage.Memory += inspect.HostConfig.Memory
+		}
+	}
+
+	if !isSwarmManager {
+		return usage, nil
+	}
+
+	services, err := cli.ServiceList(ctx, types.ServiceListOptions{})
+	if err != nil {
+		return Usage{}, err
+	}
+
+	for _, dockerService := range services {
+		if !s.includes(findResourceControl(endpoint.ID, dockerService.ID, portainer.ServiceResourceControl, dockerService.Spec.Labels, resourceControls)) {
+			continue
+		}
+
+		replicas := ServiceReplicas(dockerService.Spec.Mode, info.Swarm.Nodes)
+		usage.Containers += replicas
+
+		if resources := dockerService.Spec.TaskTemplate.Resources; resources != nil && resources.Limits != nil {
+			usage.NanoCPUs += int64(replicas) * resources.Limits.NanoCPUs
+			usage.Memory += int64(replicas) * resources.Limits.MemoryBytes
+		}
+	}
+
+	return usage, nil
+}
+
+// ServiceReplicas returns the number of tasks run by a Swarm service, global services run one task per node
+func ServiceReplicas(mode swarm.ServiceMode, nodes int) int {
+	switch {
+	case mode.Global != nil:
+		return nodes
+	case mode.Replicated != nil && mode.Replicated.Replicas != nil:
+		return int(*mode.Replicated.Replicas)
+	}
+
+	return 1
+}
+
+// findResourceControl returns the resource control of a Docker resource,
+// or the one inherited from its service or its stack
+func findResourceControl(endpointID portainer.EndpointID, resourceID string, resourceType portainer.ResourceControlType, labels map[string]string, resourceControls []portainer.ResourceControl) *portainer.ResourceControl {
+	if resourceControl := authorization.GetResourceControlByResourceIDAndType(resourceID, resourceType, resourceControls); resourceControl != nil {
+		return resourceControl
+	}
+
+	if serviceID := labels[labelDockerServiceID]; serviceID != "" {
+		if resourceControl := authorization.GetResourceControlByResourceIDAndType(serviceID, portainer.ServiceResourceControl, resourceControls); resourceControl != nil {
+			return resourceControl
+		}
+	}
+
+	for _, label := range []string{labelDockerSwarmStackName, labelDockerComposeStackName} {
+		if stackName := labels[label]; stackName != "" {
+			return authorization.GetResourceControlByResourceIDAndType(stackutils.ResourceControlID(endpointID, stackName), portainer.StackResourceControl, resourceControls)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package quotas
+
+import (
+	"context"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/internal/authorization"
+	"github.com/portainer/portainer/api/stacks/stackutils"
+
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckQuota(t *testing.T) {
+	quota := portainer.Quota{Name: "dev", MaxStacks: 2, MaxContainers: 3, MaxCPU: 1.5, MaxMemory: 1024}
+
+	assert.NoError(t, checkQuota(quota, Usage{Stacks: 1}, Usage{Stacks: 1}))
+	assert.ErrorIs(t, checkQuota(quota, Usage{Stacks: 2}, Usage{Stacks: 1}), ErrQuotaExceeded)
+
+	assert.NoError(t, checkQuota(quota, Usage{Containers: 2, NanoCPUs: 1e9, Memory: 512}, Usage{Containers: 1, NanoCPUs: 5e8, Memory: 512}))
+	assert.ErrorIs(t, checkQuota(quota, Usage{Containers: 3}, Usage{Containers: 1, NanoCPUs: 1, Memory: 1}), ErrQuotaExceeded)
+	assert.ErrorIs(t, checkQuota(quota, Usage{NanoCPUs: 1e9}, Usage{Containers: 1, NanoCPUs: 6e8, Memory: 1}), ErrQuotaExceeded)
+	assert.ErrorIs(t, checkQuota(quota, Usage{Memory: 1000}, Usage{Containers: 1, NanoCPUs: 1, Memory: 25}), ErrQuotaExceeded)
+
+	// A resource that is not requested is not checked, even when already over the limit
+	assert.NoError(t, checkQuota(quota, Usage{Containers: 10}, Usage{Stacks: 1}))
+}
+
+func TestCheckLimitsDeclared(t *testing.T) {
+	assert.NoError(t, checkLimitsDeclared(portainer.Quota{MaxCPU: 1, MaxMemory: 1024}, Usage{Stacks: 1}))
+	assert.NoError(t, checkLimitsDeclared(portainer.Quota{MaxContainers: 1}, Usage{Containers: 1}))
+	assert.ErrorIs(t, checkLimitsDeclared(portainer.Quota{MaxCPU: 1}, Usage{Containers: 1, Memory: 1}), ErrLimitRequired)
+	assert.ErrorIs(t, checkLimitsDeclared(portainer.Quota{MaxMemory: 1024}, Usage{Containers: 1, NanoCPUs: 1}), ErrLimitRequired)
+	assert.True(t, IsViolation(checkLimitsDeclared(portainer.Quota{MaxMemory: 1024}, Usage{Containers: 1})))
+}
+
+func TestServiceReplicas(t *testing.T) {
+	replicas := uint64(3)
+
+	assert.Equal(t, 3, ServiceReplicas(swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}}, 5))
+	assert.Equal(t, 5, ServiceReplicas(swarm.ServiceMode{Global: &swarm.GlobalService{}}, 5))
+	assert.Equal(t, 1, ServiceReplicas(swarm.ServiceMode{}, 5))
+}
+
+func TestCheck_Stacks(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	endpoint := &portainer.Endpoint{ID: 1, GroupID: 2, Type: portainer.KubernetesLocalEnvironment}
+	require.NoError(t, store.Endpoint().Create(endpoint))
+
+	alice := &portainer.User{Username: "alice", Role: portainer.StandardUserRole}
+	bob := &portainer.User{Username: "bob", Role: portainer.StandardUserRole}
+	carol := &portainer.User{Username: "carol", Role: portainer.StandardUserRole}
+	for _, user := range []*portainer.User{alice, bob, carol} {
+		require.NoError(t, store.User().Create(user))
+	}
+
+	require.NoError(t, store.TeamMembership().Create(&portainer.TeamMembership{UserID: alice.ID, TeamID: 1}))
+	require.NoError(t, store.TeamMembership().Create(&portainer.TeamMembership{UserID: bob.ID, TeamID: 1}))
+
+	// bob, a member of the team, owns a stack on the environment
+	require.NoError(t, store.Stack().Create(&portainer.Stack{ID: 1, Name: "web", EndpointID: 1}))
+	require.NoError(t, store.ResourceControl().Create(authorization.NewPrivateResourceControl(stackutils.ResourceControlID(1, "web"), portainer.StackResourceControl, bob.ID)))
+
+	require.NoError(t, store.Quota().Create(&portainer.Quota{Name: "team", TeamID: 1, MaxStacks: 1}))
+
+	service := NewService(store, nil)
+
+	assert.ErrorIs(t, service.Check(context.Background(), endpoint, alice.ID, Usage{Stacks: 1}), ErrQuotaExceeded)
+
+	// carol is not part of the team
+	assert.NoError(t, service.Check(context.Background(), endpoint, carol.ID, Usage{Stacks: 1}))
+
+	// The quota of the group counts the stacks of everyone
+	require.NoError(t, store.Quota().Create(&portainer.Quota{Name: "group", EndpointGroupID: 2, MaxStacks: 1}))
+	assert.ErrorIs(t, service.Check(context.Background(), endpoint, carol.ID, Usage{Stacks: 1}), ErrQuotaExceeded)
+}
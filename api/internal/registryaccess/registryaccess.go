@@ -0,0 +1,191 @@
+package registryaccess
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/kubernetes/cli"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ReconcileInterval is how often the pull secrets of the registries are compared with the namespaces
+// the registries are assigned to
+const ReconcileInterval = 5 * time.Minute
+
+const (
+	// DriftMissingSecret is reported when a namespace the registry is assigned to has no pull secret
+	DriftMissingSecret = "missing"
+	// DriftStaleSecret is reported when a namespace holds the pull secret of a registry it is not assigned to,
+	// or of a registry that no longer exists
+	DriftStaleSecret = "stale"
+)
+
+// Drift represents a difference between the namespaces a registry is assigned to and its pull secrets
+type Drift struct {
+	EndpointID portainer.EndpointID `json:"EndpointId" example:"1"`
+	RegistryID portainer.RegistryID `json:"RegistryId" example:"1"`
+	Namespace  string               `json:"Namespace" example:"default"`
+	// Type of the drift, missing or stale
+	Type string `json:"Type" example:"missing"`
+	// Error raised while correcting the drift, empty when the drift was corrected
+	Error string `json:"Error,omitempty"`
+}
+
+// Report represents the outcome of a reconciliation
+type Report struct {
+	// Unix timestamp of the reconciliation, 0 when no reconciliation ran yet
+	Time   int64   `json:"Time" example:"1700000000"`
+	Drifts []Drift `json:"Drifts"`
+	// Errors preventing the reconciliation of environments, by environment(endpoint) identifier
+	EndpointErrors map[portainer.EndpointID]string `json:"EndpointErrors,omitempty"`
+}
+
+// Service keeps the image pull secrets of the Kubernetes environments(endpoints) in line with the namespaces
+// the registries are assigned to, creating the missing secrets and removing the stale ones. The Edge
+// environments are left out so that no tunnel is opened by the reconciliation
+type Service struct {
+	dataStore dataservices.DataStore
+	// getKubeClient returns the client of a Kubernetes environment(endpoint)
+	getKubeClient func(endpoint *portainer.Endpoint) (portainer.KubeClient, error)
+
+	mu         sync.Mutex
+	lastReport Report
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService(dataStore dataservices.DataStore, k8sClientFactory *cli.ClientFactory) *Service {
+	return &Service{
+		dataStore: dataStore,
+		getKubeClient: func(endpoint *portainer.Endpoint) (portainer.KubeClient, error) {
+			return k8sClientFactory.GetPrivilegedKubeClient(endpoint)
+		},
+		lastReport: Report{Drifts: []Drift{}},
+	}
+}
+
+// LastReport returns the report of the last reconciliation
+func (service *Service) LastReport() Report {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	return service.lastReport
+}
+
+// Reconcile corrects the drifts of the pull secrets of the Kubernetes environments, it is run periodically.
+// An environment that cannot be reached is retried on the next run
+func (service *Service) Reconcile() error {
+	endpoints, err := service.dataStore.Endpoint().Endpoints()
+	if err != nil {
+		return err
+	}
+
+	registries, err := service.dataStore.Registry().ReadAll()
+	if err != nil {
+		return err
+	}
+
+	report := Report{
+		Time:           time.Now().Unix(),
+		Drifts:         []Drift{},
+		EndpointErrors: make(map[portainer.EndpointID]string),
+	}
+
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if !endpointutils.IsKubernetesEndpoint(endpoint) || endpointutils.IsEdgeEndpoint(endpoint) || endpoint.Status == portainer.EndpointStatusDown {
+			continue
+		}
+
+		drifts, err := service.reconcileEndpoint(endpoint, registries)
+		if err != nil {
+			log.Warn().Err(err).Int("endpoint_id", int(endpoint.ID)).Msg("unable to reconcile the registry pull secrets of the environment")
+
+			report.EndpointErrors[endpoint.ID] = err.Error()
+
+			continue
+		}
+
+		report.Drifts = append(report.Drifts, drifts...)
+	}
+
+	service.mu.Lock()
+	service.lastReport = report
+	service.mu.Unlock()
+
+	return nil
+}
+
+func (service *Service) reconcileEndpoint(endpoint *portainer.Endpoint, registries []portainer.Registry) ([]Drift, error) {
+	kubeClient, err := service.getKubeClient(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := kubeClient.GetRegistrySecretNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	drifts := []Drift{}
+
+	for i := range registries {
+		registry := &registries[i]
+		namespaces := registry.RegistryAccesses[endpoint.ID].Namespaces
+
+		for _, namespace := range namespaces {
+			if slices.Contains(actual[registry.ID], namespace) {
+				continue
+			}
+
+			drift := Drift{EndpointID: endpoint.ID, RegistryID: registry.ID, Namespace: namespace, Type: DriftMissingSecret}
+			if err := kubeClient.CreateRegistrySecret(registry, namespace); err != nil {
+				drift.Error = err.Error()
+			}
+
+			drifts = append(drifts, logDrift(drift))
+		}
+	}
+
+	for registryID, secretNamespaces := range actual {
+		var namespaces []string
+		if i := slices.IndexFunc(registries, func(r portainer.Registry) bool { return r.ID == registryID }); i != -1 {
+			namespaces = registries[i].RegistryAccesses[endpoint.ID].Namespaces
+		}
+
+		for _, namespace := range secretNamespaces {
+			if slices.Contains(namespaces, namespace) {
+				continue
+			}
+
+			drift := Drift{EndpointID: endpoint.ID, RegistryID: registryID, Namespace: namespace, Type: DriftStaleSecret}
+			if err := kubeClient.DeleteRegistrySecret(registryID, namespace); err != nil {
+				drift.Error = err.Error()
+			}
+
+			drifts = append(drifts, logDrift(drift))
+		}
+	}
+
+	return drifts, nil
+}
+
+func logDrift(drift Drift) Drift {
+	event := log.Info()
+	if drift.Error != "" {
+		event = log.Warn().Str("error", drift.Error)
+	}
+
+	event.
+		Int("endpoint_id", int(drift.EndpointID)).
+		Int("registry_id", int(drift.RegistryID)).
+		Str("namespace", drift.Namespace).
+		Str("drift", drift.Type).
+		Msg("registry pull secret drift detected")
+
+	return drift
+}
@@ -0,0 +1,95 @@
+package registryaccess
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKubeClient struct {
+	portainer.KubeClient
+	secrets map[portainer.RegistryID][]string
+}
+
+// GetRegistrySecretNamespaces returns a copy of the secrets, as read from the cluster
+func (kcl *testKubeClient) GetRegistrySecretNamespaces() (map[portainer.RegistryID][]string, error) {
+	secrets := make(map[portainer.RegistryID][]string, len(kcl.secrets))
+	for registryID, namespaces := range kcl.secrets {
+		secrets[registryID] = slices.Clone(namespaces)
+	}
+
+	return secrets, nil
+}
+
+func (kcl *testKubeClient) DeleteRegistrySecret(registryID portainer.RegistryID, namespace string) error {
+	kcl.secrets[registryID] = slices.DeleteFunc(kcl.secrets[registryID], func(ns string) bool { return ns == namespace })
+
+	return nil
+}
+
+func (kcl *testKubeClient) CreateRegistrySecret(registry *portainer.Registry, namespace string) error {
+	if namespace == "missing-namespace" {
+		return errors.New("namespace not found")
+	}
+
+	kcl.secrets[registry.ID] = append(kcl.secrets[registry.ID], namespace)
+
+	return nil
+}
+
+func TestReconcile(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1, Type: portainer.KubernetesLocalEnvironment}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 2, Type: portainer.EdgeAgentOnKubernetesEnvironment}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 3, Type: portainer.KubernetesLocalEnvironment}))
+
+	require.NoError(t, store.Registry().Create(&portainer.Registry{RegistryAccesses: portainer.RegistryAccesses{
+		1: {Namespaces: []string{"default", "web", "missing-namespace"}},
+		2: {Namespaces: []string{"edge"}},
+	}}))
+
+	kubeClient := &testKubeClient{secrets: map[portainer.RegistryID][]string{
+		1: {"default", "removed"},
+		// Registry removed while the environment was unreachable
+		5: {"default"},
+	}}
+
+	service := NewService(store, nil)
+	service.getKubeClient = func(endpoint *portainer.Endpoint) (portainer.KubeClient, error) {
+		if endpoint.ID == 3 {
+			return nil, errors.New("unreachable")
+		}
+
+		require.Equal(t, portainer.EndpointID(1), endpoint.ID)
+
+		return kubeClient, nil
+	}
+
+	require.NoError(t, service.Reconcile())
+
+	assert.ElementsMatch(t, []string{"default", "web"}, kubeClient.secrets[1])
+	assert.Empty(t, kubeClient.secrets[5])
+
+	report := service.LastReport()
+	assert.NotZero(t, report.Time)
+	assert.ElementsMatch(t, []Drift{
+		{EndpointID: 1, RegistryID: 1, Namespace: "web", Type: DriftMissingSecret},
+		{EndpointID: 1, RegistryID: 1, Namespace: "missing-namespace", Type: DriftMissingSecret, Error: "namespace not found"},
+		{EndpointID: 1, RegistryID: 1, Namespace: "removed", Type: DriftStaleSecret},
+		{EndpointID: 1, RegistryID: 5, Namespace: "default", Type: DriftStaleSecret},
+	}, report.Drifts)
+	assert.Equal(t, map[portainer.EndpointID]string{3: "unreachable"}, report.EndpointErrors)
+
+	// Once corrected, the next reconciliation only reports the drifts that could not be corrected
+	require.NoError(t, service.Reconcile())
+	assert.Equal(t, []Drift{
+		{EndpointID: 1, RegistryID: 1, Namespace: "missing-namespace", Type: DriftMissingSecret, Error: "namespace not found"},
+	}, service.LastReport().Drifts)
+}
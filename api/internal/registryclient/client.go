@@ -0,0 +1,507 @@
+package registryclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+)
+
+var manifestAcceptHeader = strings.Join([]string{mediaTypeDockerManifest, mediaTypeDockerManifestList, mediaTypeOCIManifest, mediaTypeOCIIndex}, ", ")
+
+var (
+	// ErrCatalogNotSupported is returned when the registry does not expose the list of its repositories
+	ErrCatalogNotSupported = errors.New("the registry does not support listing repositories")
+	// ErrNotFound is returned when the requested repository, tag or manifest does not exist
+	ErrNotFound = errors.New("not found in the registry")
+	// ErrDeleteNotSupported is returned when the registry does not allow the deletion of manifests
+	ErrDeleteNotSupported = errors.New("the registry does not allow deleting manifests")
+)
+
+type (
+	// Page is a page of repositories or tags. Next holds the value to pass to retrieve the next page and is empty on the last page
+	Page struct {
+		Items []string `json:"Items"`
+		Next  string   `json:"Next,omitempty"`
+	}
+
+	// Descriptor describes a blob or a manifest referenced by a manifest
+	Descriptor struct {
+		MediaType   string            `json:"MediaType"`
+		Digest      string            `json:"Digest"`
+		Size        int64             `json:"Size"`
+		Platform    *Platform         `json:"Platform,omitempty"`
+		Annotations map[string]string `json:"Annotations,omitempty"`
+	}
+
+	// Platform describes the platform of an image referenced by a manifest list
+	Platform struct {
+		Architecture string `json:"Architecture"`
+		OS           string `json:"OS"`
+		Variant      string `json:"Variant,omitempty"`
+	}
+
+	// Manifest is the manifest of an image or a manifest list
+	Manifest struct {
+		Digest    string       `json:"Digest"`
+		MediaType string       `json:"MediaType"`
+		Size      int64        `json:"Size"`
+		Config    *Descriptor  `json:"Config,omitempty"`
+		Layers    []Descriptor `json:"Layers,omitempty"`
+		Manifests []Descriptor `json:"Manifests,omitempty"`
+	}
+
+	rawDescriptor struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Size        int64             `json:"size"`
+		Annotations map[string]string `json:"annotations"`
+		Platform    *struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+			Variant      string `json:"variant"`
+		} `json:"platform"`
+	}
+
+	rawManifest struct {
+		MediaType string          `json:"mediaType"`
+		Config    *rawDescriptor  `json:"config"`
+		Layers    []rawDescriptor `json:"layers"`
+		Manifests []rawDescriptor `json:"manifests"`
+	}
+)
+
+// Client talks to the Docker Registry HTTP API V2 of a single registry
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	adapter    adapter
+	username   string
+	password   string
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// Repositories returns a page of the repositories of the registry
+func (client *Client) Repositories(ctx context.Context, pageSize int, last string) (*Page, error) {
+	if lister, ok := client.adapter.(repositoryLister); ok {
+		return lister.repositories(ctx, client, pageSize, last)
+	}
+
+	var body struct {
+		Repositories []string `json:"repositories"`
+	}
+
+	next, err := client.getPage(ctx, "/v2/_catalog", "registry:catalog:*", pageSize, last, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page{Items: client.adapter.filterRepositories(body.Repositories), Next: next}, nil
+}
+
+// Tags returns a page of the tags of a repository
+func (client *Client) Tags(ctx context.Context, repository string, pageSize int, last string) (*Page, error) {
+	var body struct {
+		Tags []string `json:"tags"`
+	}
+
+	next, err := client.getPage(ctx, "/v2/"+repository+"/tags/list", pullScope(repository), pageSize, last, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page{Items: body.Tags, Next: next}, nil
+}
+
+// Manifest returns the manifest of a repository matching a tag or a digest
+func (client *Client) Manifest(ctx context.Context, repository, reference string) (*Manifest, error) {
+	resp, err := client.do(ctx, http.MethodGet, "/v2/"+repository+"/manifests/"+reference, pullScope(repository), manifestAcceptHeader)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read the manifest")
+	}
+
+	var raw rawManifest
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, errors.Wrap(err, "unable to decode the manifest")
+	}
+
+	manifest := &Manifest{
+		Digest:    resp.Header.Get("Docker-Content-Digest"),
+		MediaType: raw.MediaType,
+		Size:      int64(len(content)),
+		Layers:    convertDescriptors(raw.Layers),
+		Manifests: convertDescriptors(raw.Manifests),
+	}
+
+	if manifest.Digest == "" {
+		manifest.Digest = digest.FromBytes(content).String()
+	}
+
+	if manifest.MediaType == "" {
+		manifest.MediaType = resp.Header.Get("Content-Type")
+	}
+
+	if raw.Config != nil {
+		config := convertDescriptor(*raw.Config)
+		manifest.Config = &config
+	}
+
+	return manifest, nil
+}
+
+// ManifestDigest returns the digest of the manifest a tag currently references, without downloading the manifest
+func (client *Client) ManifestDigest(ctx context.Context, repository, tag string) (string, error) {
+	return client.manifestDigest(ctx, repository, tag, pullScope(repository))
+}
+
+// Blob returns the content of a blob of a repository, the caller is responsible for closing it
+func (client *Client) Blob(ctx context.Context, repository, blobDigest string) (io.ReadCloser, error) {
+	if _, err := digest.Parse(blobDigest); err != nil {
+		return nil, errors.Wrap(err, "invalid blob digest")
+	}
+
+	resp, err := client.do(ctx, http.MethodGet, "/v2/"+repository+"/blobs/"+blobDigest, pullScope(repository), "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// DeleteManifest deletes a manifest by digest. Every tag referencing the manifest is removed with it
+func (client *Client) DeleteManifest(ctx context.Context, repository, manifestDigest string) error {
+	if _, err := digest.Parse(manifestDigest); err != nil {
+		return errors.Wrap(err, "invalid manifest digest")
+	}
+
+	resp, err := client.do(ctx, http.MethodDelete, "/v2/"+repository+"/manifests/"+manifestDigest, pushScope(repository), "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusMethodNotAllowed {
+		return ErrDeleteNotSupported
+	}
+
+	return checkResponse(resp)
+}
+
+// DeleteTag resolves the digest of a tag and deletes the matching manifest.
+// The registry API has no way to delete a single tag, other tags referencing the same manifest are removed as well
+func (client *Client) DeleteTag(ctx context.Context, repository, tag string) (string, error) {
+	manifestDigest, err := client.manifestDigest(ctx, repository, tag, pushScope(repository))
+	if err != nil {
+		return "", err
+	}
+
+	return manifestDigest, client.DeleteManifest(ctx, repository, manifestDigest)
+}
+
+func (client *Client) manifestDigest(ctx context.Context, repository, tag, scope string) (string, error) {
+	resp, err := client.do(ctx, http.MethodHead, "/v2/"+repository+"/manifests/"+tag, scope, manifestAcceptHeader)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return "", err
+	}
+
+	manifestDigest := resp.Header.Get("Docker-Content-Digest")
+	if manifestDigest == "" {
+		return "", errors.New("the registry did not return the digest of the tag")
+	}
+
+	return manifestDigest, nil
+}
+
+func (client *Client) getPage(ctx context.Context, path, scope string, pageSize int, last string, target any) (string, error) {
+	query := url.Values{}
+	if pageSize > 0 {
+		query.Set("n", strconv.Itoa(pageSize))
+	}
+
+	if last != "" {
+		query.Set("last", last)
+	}
+
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, err := client.do(ctx, http.MethodGet, path, scope, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return "", err
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return "", errors.Wrap(err, "unable to decode the registry response")
+	}
+
+	return nextPageMarker(resp.Header.Get("Link")), nil
+}
+
+// do sends a request to the registry, answering the authentication challenge of the registry when needed
+func (client *Client) do(ctx context.Context, method, path, scope, accept string) (*http.Response, error) {
+	send := func(authorization string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, client.baseURL+path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		return client.httpClient.Do(req)
+	}
+
+	resp, err := send(client.cachedAuthorization(scope))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	authorization, err := client.authorize(ctx, challenge, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return send(authorization)
+}
+
+func (client *Client) cachedAuthorization(scope string) string {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	return client.tokens[scope]
+}
+
+// authorize answers a Basic or Bearer authentication challenge and caches the resulting authorization for the scope
+func (client *Client) authorize(ctx context.Context, challenge, scope string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+
+	var authorization string
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if client.username == "" && client.password == "" {
+			return "", errors.New("the registry requires authentication")
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "/", nil)
+		req.SetBasicAuth(client.username, client.password)
+		authorization = req.Header.Get("Authorization")
+	case "bearer":
+		token, err := client.fetchToken(ctx, params, scope)
+		if err != nil {
+			return "", err
+		}
+
+		authorization = "Bearer " + token
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge: %q", challenge)
+	}
+
+	client.mu.Lock()
+	client.tokens[scope] = authorization
+	client.mu.Unlock()
+
+	return authorization, nil
+}
+
+func (client *Client) fetchToken(ctx context.Context, params map[string]string, scope string) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", errors.New("the authentication challenge of the registry has no realm")
+	}
+
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid authentication realm")
+	}
+
+	query := tokenURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+
+	if scope != "" {
+		query.Set("scope", scope)
+	} else if challengeScope := params["scope"]; challengeScope != "" {
+		query.Set("scope", challengeScope)
+	}
+
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if client.username != "" || client.password != "" {
+		req.SetBasicAuth(client.username, client.password)
+	}
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to retrieve a registry token")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to retrieve a registry token, status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", errors.Wrap(err, "unable to decode the registry token")
+	}
+
+	if body.Token != "" {
+		return body.Token, nil
+	}
+
+	return body.AccessToken, nil
+}
+
+// parseChallenge parses a WWW-Authenticate header such as: Bearer realm="https://auth.tld/token",service="registry.tld"
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, ", "), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+
+		if key != "" {
+			params[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+
+	return scheme, params
+}
+
+// nextPageMarker extracts the last parameter of a Link header such as: </v2/_catalog?last=b&n=2>; rel="next"
+func nextPageMarker(link string) string {
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start == -1 || end <= start || !strings.Contains(link[end:], `rel="next"`) {
+		return ""
+	}
+
+	next, err := url.Parse(link[start+1 : end])
+	if err != nil {
+		return ""
+	}
+
+	return next.Query().Get("last")
+}
+
+func checkResponse(resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	}
+
+	return fmt.Errorf("unexpected response from the registry, status code: %d", resp.StatusCode)
+}
+
+func pullScope(repository string) string {
+	return "repository:" + repository + ":pull"
+}
+
+func pushScope(repository string) string {
+	return "repository:" + repository + ":pull,push,delete"
+}
+
+func convertDescriptor(raw rawDescriptor) Descriptor {
+	descriptor := Descriptor{
+		MediaType:   raw.MediaType,
+		Digest:      raw.Digest,
+		Size:        raw.Size,
+		Annotations: raw.Annotations,
+	}
+
+	if raw.Platform != nil {
+		descriptor.Platform = &Platform{
+			Architecture: raw.Platform.Architecture,
+			OS:           raw.Platform.OS,
+			Variant:      raw.Platform.Variant,
+		}
+	}
+
+	return descriptor
+}
+
+func convertDescriptors(raw []rawDescriptor) []Descriptor {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	descriptors := make([]Descriptor, 0, len(raw))
+	for _, r := range raw {
+		descriptors = append(descriptors, convertDescriptor(r))
+	}
+
+	return descriptors
+}
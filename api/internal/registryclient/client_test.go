@@ -0,0 +1,151 @@
+package registryclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDigest = "sha256:2a8f2d5c1b8bfa4f8e2a1c1f8f5d41e3c3d5f3e8c1f6b2e0f7e9d4b3a2c1e0f9"
+
+func newTestRegistry(t *testing.T, deleted *[]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	var server *httptest.Server
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte(`{"token":"token-` + r.URL.Query().Get("scope") + `"}`))
+	})
+
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/v2/library/app/tags/list":
+			assert.Equal(t, "Bearer token-repository:library/app:pull", r.Header.Get("Authorization"))
+			assert.Equal(t, "2", r.URL.Query().Get("n"))
+
+			w.Header().Set("Link", `</v2/library/app/tags/list?last=v2&n=2>; rel="next"`)
+			w.Write([]byte(`{"name":"library/app","tags":["v1","v2"]}`))
+		case r.URL.Path == "/v2/library/app/manifests/v1" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+			w.Header().Set("Docker-Content-Digest", testDigest)
+			w.Header().Set("Content-Type", mediaTypeDockerManifest)
+			if r.Method == http.MethodGet {
+				w.Write([]byte(`{"schemaVersion":2,"mediaType":"` + mediaTypeDockerManifest + `","config":{"mediaType":"application/vnd.docker.container.image.v1+json","digest":"sha256:aa","size":10},"layers":[{"mediaType":"application/vnd.docker.image.rootfs.diff.tar.gzip","digest":"sha256:bb","size":20}]}`))
+			}
+		case r.URL.Path == "/v2/library/app/manifests/"+testDigest && r.Method == http.MethodDelete:
+			assert.Equal(t, "Bearer token-repository:library/app:pull,push,delete", r.Header.Get("Authorization"))
+
+			*deleted = append(*deleted, testDigest)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func newTestClient(server *httptest.Server) *Client {
+	return &Client{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		adapter:    defaultAdapter{},
+		username:   "user",
+		password:   "secret",
+		tokens:     make(map[string]string),
+	}
+}
+
+func TestClient_Tags(t *testing.T) {
+	server := newTestRegistry(t, nil)
+	client := newTestClient(server)
+
+	page, err := client.Tags(context.Background(), "library/app", 2, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"v1", "v2"}, page.Items)
+	assert.Equal(t, "v2", page.Next)
+}
+
+func TestClient_Manifest(t *testing.T) {
+	server := newTestRegistry(t, nil)
+	client := newTestClient(server)
+
+	manifest, err := client.Manifest(context.Background(), "library/app", "v1")
+	require.NoError(t, err)
+
+	assert.Equal(t, testDigest, manifest.Digest)
+	assert.Equal(t, mediaTypeDockerManifest, manifest.MediaType)
+	require.NotNil(t, manifest.Config)
+	assert.Equal(t, "sha256:aa", manifest.Config.Digest)
+	require.Len(t, manifest.Layers, 1)
+	assert.Equal(t, int64(20), manifest.Layers[0].Size)
+
+	_, err = client.Manifest(context.Background(), "library/app", "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_DeleteTag(t *testing.T) {
+	var deleted []string
+
+	server := newTestRegistry(t, &deleted)
+	client := newTestClient(server)
+
+	manifestDigest, err := client.DeleteTag(context.Background(), "library/app", "v1")
+	require.NoError(t, err)
+
+	assert.Equal(t, testDigest, manifestDigest)
+	assert.Equal(t, []string{testDigest}, deleted)
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/nginx:pull"`)
+
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "registry.docker.io",
+		"scope":   "repository:library/nginx:pull",
+	}, params)
+}
+
+func TestNextPageMarker(t *testing.T) {
+	assert.Equal(t, "b", nextPageMarker(`</v2/_catalog?last=b&n=2>; rel="next"`))
+	assert.Empty(t, nextPageMarker(""))
+}
+
+func TestValidateRepository(t *testing.T) {
+	for _, repository := range []string{"nginx", "library/nginx", "my-org/sub_group/app.name"} {
+		assert.NoError(t, ValidateRepository(repository), repository)
+	}
+
+	for _, repository := range []string{"", "Nginx", "../etc", "library//nginx", "nginx/"} {
+		assert.Error(t, ValidateRepository(repository), repository)
+	}
+}
+
+func TestValidateReference(t *testing.T) {
+	assert.NoError(t, ValidateReference("latest"))
+	assert.NoError(t, ValidateReference(testDigest))
+	assert.Error(t, ValidateReference("sha256:invalid"))
+	assert.Error(t, ValidateReference(".hidden"))
+}
@@ -0,0 +1,270 @@
+package registryclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/crypto"
+	"github.com/portainer/portainer/api/dataservices"
+	httpclient "github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/api/internal/registryutils"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+)
+
+const defaultTimeout = 30 * time.Second
+
+var (
+	repositoryRe = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|[-]+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|[-]+)[a-z0-9]+)*)*$`)
+	tagRe        = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+)
+
+// Service creates clients for the Docker Registry HTTP API V2 of the registries managed by Portainer
+type Service struct {
+	dataStore dataservices.DataStore
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService(dataStore dataservices.DataStore) *Service {
+	return &Service{dataStore: dataStore}
+}
+
+// NewClient returns a client for the registry, using the authentication adapter matching the registry type
+func (service *Service) NewClient(registry *portainer.Registry) (*Client, error) {
+	adapter := adapterForRegistry(registry)
+
+	username, password, err := adapter.credentials(service.dataStore, registry)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to retrieve the registry credentials")
+	}
+
+	transport := httpclient.NewTransport()
+	if config := registry.ManagementConfiguration; config != nil && config.TLSConfig.TLS {
+		tlsConfig, err := crypto.CreateTLSConfigurationFromDisk(config.TLSConfig.TLSCACertPath, config.TLSConfig.TLSCertPath, config.TLSConfig.TLSKeyPath, config.TLSConfig.TLSSkipVerify)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to create the TLS configuration of the registry")
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &Client{
+		httpClient: &http.Client{Transport: transport, Timeout: defaultTimeout},
+		baseURL:    adapter.baseURL(registry),
+		adapter:    adapter,
+		username:   username,
+		password:   password,
+		tokens:     make(map[string]string),
+	}, nil
+}
+
+// ValidateRepository ensures that a repository name follows the Docker distribution naming rules
+func ValidateRepository(repository string) error {
+	if !repositoryRe.MatchString(repository) {
+		return fmt.Errorf("invalid repository name: %q", repository)
+	}
+
+	return nil
+}
+
+// ValidateTag ensures that a tag follows the Docker distribution naming rules
+func ValidateTag(tag string) error {
+	if !tagRe.MatchString(tag) {
+		return fmt.Errorf("invalid tag: %q", tag)
+	}
+
+	return nil
+}
+
+// ValidateReference ensures that a manifest reference is a valid tag or digest
+func ValidateReference(reference string) error {
+	if strings.Contains(reference, ":") {
+		_, err := digest.Parse(reference)
+
+		return err
+	}
+
+	return ValidateTag(reference)
+}
+
+type (
+	// adapter holds the registry type specific behaviours of the client
+	adapter interface {
+		baseURL(registry *portainer.Registry) string
+		credentials(dataStore dataservices.DataStore, registry *portainer.Registry) (string, string, error)
+		filterRepositories(repositories []string) []string
+	}
+
+	// repositoryLister is implemented by the adapters of the registries that do not support the catalog endpoint
+	repositoryLister interface {
+		repositories(ctx context.Context, client *Client, pageSize int, last string) (*Page, error)
+	}
+
+	defaultAdapter struct{}
+
+	ecrAdapter struct {
+		defaultAdapter
+	}
+
+	dockerHubAdapter struct {
+		defaultAdapter
+	}
+
+	quayAdapter struct {
+		defaultAdapter
+		namespace string
+	}
+
+	gitlabAdapter struct {
+		defaultAdapter
+		instanceURL string
+		projectID   int
+		token       string
+	}
+)
+
+func adapterForRegistry(registry *portainer.Registry) adapter {
+	switch registry.Type {
+	case portainer.EcrRegistry:
+		return ecrAdapter{}
+	case portainer.DockerHubRegistry:
+		return dockerHubAdapter{}
+	case portainer.QuayRegistry:
+		namespace := registry.Username
+		if registry.Quay.UseOrganisation {
+			namespace = registry.Quay.OrganisationName
+		}
+
+		return quayAdapter{namespace: namespace}
+	case portainer.GitlabRegistry:
+		return gitlabAdapter{
+			instanceURL: strings.TrimSuffix(registry.Gitlab.InstanceURL, "/"),
+			projectID:   registry.Gitlab.ProjectID,
+			token:       registry.Password,
+		}
+	}
+
+	return defaultAdapter{}
+}
+
+func (defaultAdapter) baseURL(registry *portainer.Registry) string {
+	registryURL := registry.URL
+	if registry.Type == portainer.ProGetRegistry && registry.BaseURL != "" {
+		registryURL = registry.BaseURL
+	}
+
+	if !strings.HasPrefix(registryURL, "http://") && !strings.HasPrefix(registryURL, "https://") {
+		registryURL = "https://" + registryURL
+	}
+
+	return strings.TrimSuffix(registryURL, "/")
+}
+
+func (defaultAdapter) credentials(dataStore dataservices.DataStore, registry *portainer.Registry) (string, string, error) {
+	if !registry.Authentication {
+		return "", "", nil
+	}
+
+	return registry.Username, registry.Password, nil
+}
+
+func (defaultAdapter) filterRepositories(repositories []string) []string {
+	return repositories
+}
+
+// credentials exchanges the AWS access keys of the registry for a short lived ECR token
+func (ecrAdapter) credentials(dataStore dataservices.DataStore, registry *portainer.Registry) (string, string, error) {
+	if !registry.Authentication {
+		return "", "", nil
+	}
+
+	if err := registryutils.EnsureRegTokenValid(dataStore, registry); err != nil {
+		return "", "", err
+	}
+
+	return registryutils.GetRegEffectiveCredential(registry)
+}
+
+func (dockerHubAdapter) baseURL(registry *portainer.Registry) string {
+	return "https://registry-1.docker.io"
+}
+
+func (dockerHubAdapter) repositories(ctx context.Context, client *Client, pageSize int, last string) (*Page, error) {
+	return nil, ErrCatalogNotSupported
+}
+
+// filterRepositories only keeps the repositories of the namespace of the registry, Quay returning every repository visible to the account
+func (adapter quayAdapter) filterRepositories(repositories []string) []string {
+	if adapter.namespace == "" {
+		return repositories
+	}
+
+	return slices.DeleteFunc(repositories, func(repository string) bool {
+		return !strings.HasPrefix(repository, adapter.namespace+"/")
+	})
+}
+
+// repositories lists the repositories of the GitLab project through the GitLab API as the GitLab registry does not expose its catalog
+func (adapter gitlabAdapter) repositories(ctx context.Context, client *Client, pageSize int, last string) (*Page, error) {
+	page := 1
+	if last != "" {
+		var err error
+		if page, err = strconv.Atoi(last); err != nil || page < 1 {
+			return nil, errors.New("invalid page marker")
+		}
+	}
+
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	if pageSize > 0 {
+		query.Set("per_page", strconv.Itoa(pageSize))
+	}
+
+	requestURL := fmt.Sprintf("%s/api/v4/projects/%d/registry/repositories?%s", adapter.instanceURL, adapter.projectID, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("PRIVATE-TOKEN", adapter.token)
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list the repositories of the GitLab project")
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var body []struct {
+		Path string `json:"path"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, errors.Wrap(err, "unable to decode the GitLab repositories")
+	}
+
+	result := &Page{Items: make([]string, 0, len(body))}
+	for _, repository := range body {
+		result.Items = append(result.Items, repository.Path)
+	}
+
+	if next := resp.Header.Get("X-Next-Page"); next != "" {
+		result.Next = next
+	}
+
+	return result, nil
+}
@@ -0,0 +1,210 @@
+package registrytokens
+
+import (
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/registryutils"
+	"github.com/portainer/portainer/api/kubernetes/cli"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// RefreshInterval is how often the expiry of the access tokens of the registries is checked
+	RefreshInterval = time.Minute
+	// RefreshMargin is how long before its expiry an access token is refreshed
+	RefreshMargin = 30 * time.Minute
+	// maxBackoff bounds the delay before the next attempt to refresh the token of a registry whose refreshes
+	// keep failing
+	maxBackoff = time.Hour
+)
+
+// refreshFailure tracks the consecutive failed refreshes of the token of a registry
+type refreshFailure struct {
+	count   int
+	retryAt time.Time
+}
+
+// Service refreshes the short-lived access tokens of the registries before they expire and updates the
+// pull secrets of the Kubernetes namespaces the registries are assigned to. Only the ECR registries rely on
+// short-lived tokens, the other registries, ACR and GitLab included, authenticate with their long-lived credentials
+type Service struct {
+	dataStore dataservices.DataStore
+	mu        sync.Mutex
+	failures  map[portainer.RegistryID]*refreshFailure
+	// getToken retrieves a new access token for a registry along with its expiry as a Unix timestamp
+	getToken func(registry *portainer.Registry) (string, int64, error)
+	// getKubeClient returns the client of a Kubernetes environment(endpoint)
+	getKubeClient func(endpoint *portainer.Endpoint) (portainer.KubeClient, error)
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService(dataStore dataservices.DataStore, k8sClientFactory *cli.ClientFactory) *Service {
+	return &Service{
+		dataStore: dataStore,
+		failures:  make(map[portainer.RegistryID]*refreshFailure),
+		getToken:  registryutils.GetRegToken,
+		getKubeClient: func(endpoint *portainer.Endpoint) (portainer.KubeClient, error) {
+			return k8sClientFactory.GetPrivilegedKubeClient(endpoint)
+		},
+	}
+}
+
+// NeedsRefresh returns true when the registry relies on a short-lived access token that is missing
+// or expires within the refresh margin
+func NeedsRefresh(registry *portainer.Registry, now time.Time) bool {
+	if registry.Type != portainer.EcrRegistry {
+		return false
+	}
+
+	return registry.AccessToken == "" || registry.AccessTokenExpiry <= now.Add(RefreshMargin).Unix()
+}
+
+// RefreshTokens refreshes the access tokens about to expire, it is run periodically. A registry whose token
+// cannot be refreshed is retried with an exponential backoff
+func (service *Service) RefreshTokens() error {
+	return service.refreshTokens(time.Now())
+}
+
+func (service *Service) refreshTokens(now time.Time) error {
+	registries, err := service.dataStore.Registry().ReadAll()
+	if err != nil {
+		return err
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	current := make(map[portainer.RegistryID]bool, len(registries))
+	for i := range registries {
+		registry := &registries[i]
+		current[registry.ID] = true
+
+		if !NeedsRefresh(registry, now) {
+			delete(service.failures, registry.ID)
+
+			continue
+		}
+
+		failure, ok := service.failures[registry.ID]
+		if ok && now.Before(failure.retryAt) {
+			continue
+		}
+
+		if err := service.refreshToken(registry); err != nil {
+			if !ok {
+				failure = &refreshFailure{}
+				service.failures[registry.ID] = failure
+			}
+
+			failure.count++
+			delay := backoff(failure.count)
+			failure.retryAt = now.Add(delay)
+
+			log.Warn().
+				Err(err).
+				Int("registry_id", int(registry.ID)).
+				Int("failures", failure.count).
+				Dur("retry_in", delay).
+				Msg("unable to refresh the access token of the registry")
+
+			continue
+		}
+
+		delete(service.failures, registry.ID)
+		service.updatePullSecrets(registry)
+	}
+
+	// The registries removed since the previous run are forgotten
+	for registryID := range service.failures {
+		if !current[registryID] {
+			delete(service.failures, registryID)
+		}
+	}
+
+	return nil
+}
+
+// backoff returns the delay before the next attempt to refresh a token, it doubles with each consecutive failure
+func backoff(failures int) time.Duration {
+	delay := RefreshInterval
+	for i := 1; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
+
+// refreshToken retrieves a new access token and saves it, the registry is read again so that a change made
+// while the token was retrieved is not overwritten
+func (service *Service) refreshToken(registry *portainer.Registry) error {
+	accessToken, expiry, err := service.getToken(registry)
+	if err != nil {
+		return err
+	}
+
+	return service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		current, err := tx.Registry().Read(registry.ID)
+		if err != nil {
+			return err
+		}
+
+		current.AccessToken = accessToken
+		current.AccessTokenExpiry = expiry
+		*registry = *current
+
+		return tx.Registry().Update(registry.ID, current)
+	})
+}
+
+// updatePullSecrets replaces the pull secrets of the registry in the Kubernetes namespaces it is assigned to
+func (service *Service) updatePullSecrets(registry *portainer.Registry) {
+	for endpointID, access := range registry.RegistryAccesses {
+		if len(access.Namespaces) == 0 {
+			continue
+		}
+
+		endpoint, err := service.dataStore.Endpoint().Endpoint(endpointID)
+		if err != nil {
+			if !dataservices.IsErrObjectNotFound(err) {
+				log.Warn().Err(err).Int("endpoint_id", int(endpointID)).Msg("unable to retrieve the environment")
+			}
+
+			continue
+		}
+
+		if !endpointutils.IsKubernetesEndpoint(endpoint) {
+			continue
+		}
+
+		kubeClient, err := service.getKubeClient(endpoint)
+		if err != nil {
+			log.Warn().Err(err).Int("endpoint_id", int(endpointID)).Msg("unable to create the Kubernetes client of the environment")
+
+			continue
+		}
+
+		for _, namespace := range access.Namespaces {
+			if err := updatePullSecret(kubeClient, registry, namespace); err != nil {
+				log.Warn().
+					Err(err).
+					Int("registry_id", int(registry.ID)).
+					Int("endpoint_id", int(endpointID)).
+					Str("namespace", namespace).
+					Msg("unable to update the pull secret of the registry")
+			}
+		}
+	}
+}
+
+func updatePullSecret(kubeClient portainer.KubeClient, registry *portainer.Registry, namespace string) error {
+	if err := kubeClient.DeleteRegistrySecret(registry.ID, namespace); err != nil {
+		return err
+	}
+
+	return kubeClient.CreateRegistrySecret(registry, namespace)
+}
@@ -0,0 +1,146 @@
+package registrytokens
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testKubeClient struct {
+	portainer.KubeClient
+	secrets map[string]string
+}
+
+func (kcl *testKubeClient) DeleteRegistrySecret(registryID portainer.RegistryID, namespace string) error {
+	delete(kcl.secrets, namespace)
+
+	return nil
+}
+
+func (kcl *testKubeClient) CreateRegistrySecret(registry *portainer.Registry, namespace string) error {
+	kcl.secrets[namespace] = registry.AccessToken
+
+	return nil
+}
+
+func TestNeedsRefresh(t *testing.T) {
+	now := time.Now()
+
+	assert.True(t, NeedsRefresh(&portainer.Registry{Type: portainer.EcrRegistry}, now))
+	assert.True(t, NeedsRefresh(&portainer.Registry{Type: portainer.EcrRegistry, AccessToken: "token", AccessTokenExpiry: now.Add(10 * time.Minute).Unix()}, now))
+	assert.False(t, NeedsRefresh(&portainer.Registry{Type: portainer.EcrRegistry, AccessToken: "token", AccessTokenExpiry: now.Add(time.Hour).Unix()}, now))
+	assert.False(t, NeedsRefresh(&portainer.Registry{Type: portainer.GitlabRegistry}, now))
+}
+
+func TestRefreshTokens(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1, Type: portainer.KubernetesLocalEnvironment}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 2, Type: portainer.DockerEnvironment}))
+
+	accesses := portainer.RegistryAccesses{
+		1: {Namespaces: []string{"default", "web"}},
+		2: {Namespaces: []string{"ignored"}},
+	}
+	require.NoError(t, store.Registry().Create(&portainer.Registry{Type: portainer.EcrRegistry, RegistryAccesses: accesses}))
+	require.NoError(t, store.Registry().Create(&portainer.Registry{
+		Type:              portainer.EcrRegistry,
+		AccessToken:       "valid",
+		AccessTokenExpiry: time.Now().Add(time.Hour).Unix(),
+		RegistryAccesses:  accesses,
+	}))
+	require.NoError(t, store.Registry().Create(&portainer.Registry{Type: portainer.EcrRegistry, Name: "unreachable"}))
+
+	kubeClient := &testKubeClient{secrets: map[string]string{}}
+	expiry := time.Now().Add(12 * time.Hour).Unix()
+
+	service := NewService(store, nil)
+	service.getToken = func(registry *portainer.Registry) (string, int64, error) {
+		if registry.Name == "unreachable" {
+			return "", 0, errors.New("unable to reach ECR")
+		}
+
+		return "refreshed", expiry, nil
+	}
+	service.getKubeClient = func(endpoint *portainer.Endpoint) (portainer.KubeClient, error) {
+		require.Equal(t, portainer.EndpointID(1), endpoint.ID)
+
+		return kubeClient, nil
+	}
+
+	require.NoError(t, service.RefreshTokens())
+
+	registry, err := store.Registry().Read(1)
+	require.NoError(t, err)
+	assert.Equal(t, "refreshed", registry.AccessToken)
+	assert.Equal(t, expiry, registry.AccessTokenExpiry)
+
+	// The pull secrets of the namespaces the registry is assigned to hold the new token
+	assert.Equal(t, map[string]string{"default": "refreshed", "web": "refreshed"}, kubeClient.secrets)
+
+	// A token that is not about to expire is kept
+	registry, err = store.Registry().Read(2)
+	require.NoError(t, err)
+	assert.Equal(t, "valid", registry.AccessToken)
+
+	registry, err = store.Registry().Read(3)
+	require.NoError(t, err)
+	assert.Empty(t, registry.AccessToken)
+}
+
+func TestRefreshTokensBackoff(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Registry().Create(&portainer.Registry{Type: portainer.EcrRegistry, Name: "unreachable"}))
+
+	attempts := 0
+	reachable := false
+
+	service := NewService(store, nil)
+	service.getToken = func(registry *portainer.Registry) (string, int64, error) {
+		attempts++
+		if !reachable {
+			return "", 0, errors.New("unable to reach ECR")
+		}
+
+		return "refreshed", time.Now().Add(12 * time.Hour).Unix(), nil
+	}
+
+	now := time.Now()
+	require.NoError(t, service.refreshTokens(now))
+	assert.Equal(t, 1, attempts)
+
+	// The next attempts wait for a delay that doubles with each failure
+	require.NoError(t, service.refreshTokens(now.Add(30*time.Second)))
+	assert.Equal(t, 1, attempts)
+
+	now = now.Add(RefreshInterval)
+	require.NoError(t, service.refreshTokens(now))
+	assert.Equal(t, 2, attempts)
+
+	require.NoError(t, service.refreshTokens(now.Add(RefreshInterval)))
+	assert.Equal(t, 2, attempts)
+
+	reachable = true
+	now = now.Add(2 * RefreshInterval)
+	require.NoError(t, service.refreshTokens(now))
+	assert.Equal(t, 3, attempts)
+	assert.Empty(t, service.failures)
+
+	registry, err := store.Registry().Read(1)
+	require.NoError(t, err)
+	assert.Equal(t, "refreshed", registry.AccessToken)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, RefreshInterval, backoff(1))
+	assert.Equal(t, 2*RefreshInterval, backoff(2))
+	assert.Equal(t, 8*RefreshInterval, backoff(4))
+	assert.Equal(t, maxBackoff, backoff(20))
+}
@@ -33,6 +33,54 @@ func hasPermission(
 	return
 }
 
+// TeamDefaultRegistryIDs returns the identifiers of the default registries of the teams of a user
+func TeamDefaultRegistryIDs(dataStore dataservices.DataStore, teamMemberships []portainer.TeamMembership) (map[portainer.RegistryID]bool, error) {
+	registryIDs := make(map[portainer.RegistryID]bool)
+
+	for _, membership := range teamMemberships {
+		team, err := dataStore.Team().Read(membership.TeamID)
+		if dataStore.IsErrObjectNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, registryID := range team.DefaultRegistries {
+			registryIDs[registryID] = true
+		}
+	}
+
+	return registryIDs, nil
+}
+
+// FilterDeploymentRegistries returns the registries whose credentials are used for the deployments of a user in an
+// environment(endpoint): the registries the user is authorized to access and the default registries of their teams
+func FilterDeploymentRegistries(
+	dataStore dataservices.DataStore,
+	registries []portainer.Registry,
+	user *portainer.User,
+	teamMemberships []portainer.TeamMembership,
+	endpointID portainer.EndpointID,
+) ([]portainer.Registry, error) {
+	if user.Role == portainer.AdministratorRole {
+		return registries, nil
+	}
+
+	defaultRegistryIDs, err := TeamDefaultRegistryIDs(dataStore, teamMemberships)
+	if err != nil {
+		return nil, err
+	}
+
+	filteredRegistries := make([]portainer.Registry, 0, len(registries))
+	for _, registry := range registries {
+		if defaultRegistryIDs[registry.ID] || security.AuthorizedRegistryAccess(&registry, user, teamMemberships, endpointID) {
+			filteredRegistries = append(filteredRegistries, registry)
+		}
+	}
+
+	return filteredRegistries, nil
+}
+
 // GetAccessibleRegistry get the registry if the user has permission
 func GetAccessibleRegistry(
 	dataStore dataservices.DataStore,
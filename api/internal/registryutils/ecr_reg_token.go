@@ -14,15 +14,25 @@ func isRegTokenValid(registry *portainer.Registry) (valid bool) {
 	return registry.AccessToken != "" && registry.AccessTokenExpiry > time.Now().Unix()
 }
 
-func doGetRegToken(dataStore dataservices.DataStore, registry *portainer.Registry) (err error) {
+// GetRegToken retrieves a new access token for an ECR registry along with its expiry as a Unix timestamp
+func GetRegToken(registry *portainer.Registry) (string, int64, error) {
 	ecrClient := ecr.NewService(registry.Username, registry.Password, registry.Ecr.Region)
 	accessToken, expiryAt, err := ecrClient.GetAuthorizationToken()
+	if err != nil {
+		return "", 0, err
+	}
+
+	return *accessToken, expiryAt.Unix(), nil
+}
+
+func doGetRegToken(dataStore dataservices.DataStore, registry *portainer.Registry) (err error) {
+	accessToken, expiry, err := GetRegToken(registry)
 	if err != nil {
 		return
 	}
 
-	registry.AccessToken = *accessToken
-	registry.AccessTokenExpiry = expiryAt.Unix()
+	registry.AccessToken = accessToken
+	registry.AccessTokenExpiry = expiry
 
 	err = dataStore.Registry().Update(registry.ID, registry)
 
@@ -34,6 +44,8 @@ func parseRegToken(registry *portainer.Registry) (username, password string, err
 	return ecrClient.ParseAuthorizationToken(registry.AccessToken)
 }
 
+// EnsureRegTokenValid retrieves a new access token for an ECR registry when its token expired. The tokens are
+// refreshed ahead of their expiry in the background, this only covers a token that could not be refreshed in time
 func EnsureRegTokenValid(dataStore dataservices.DataStore, registry *portainer.Registry) (err error) {
 	if registry.Type == portainer.EcrRegistry {
 		if isRegTokenValid(registry) {
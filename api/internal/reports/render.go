@@ -0,0 +1,135 @@
+package reports
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+// DefaultTemplate renders the reports without a template
+var DefaultTemplate = portainer.ReportTemplate{
+	Name:    "default",
+	Subject: `{{ .GroupName }} report, {{ date .From }} to {{ date .To }}`,
+	Body:    defaultBody,
+}
+
+const defaultBody = `<html>
+<body style="font-family: sans-serif;">
+<h2>{{ .GroupName }}</h2>
+<p>Activity from {{ datetime .From }} to {{ datetime .To }}.</p>
+
+<h3>Deployments</h3>
+<p>{{ .Deployments.Total }} deployments, {{ .Deployments.Succeeded }} succeeded and {{ .Deployments.Failed }} failed.</p>
+{{- if .Deployments.Failures }}
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Date</th><th>Environment</th><th>Initiator</th><th>Error</th></tr>
+{{- range .Deployments.Failures }}
+<tr><td>{{ datetime .Time }}</td><td>{{ .EnvironmentName }}</td><td>{{ .Initiator }}</td><td>{{ .Error }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+
+<h3>Incidents</h3>
+{{- if .Incidents }}
+<p>{{ .IncidentCount }} incidents.</p>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Date</th><th>Environment</th><th>Container</th><th>Incident</th></tr>
+{{- range .Incidents }}
+<tr><td>{{ datetime .Time }}</td><td>{{ .EnvironmentName }}</td><td>{{ .Container }}</td><td>{{ .Description }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>No incident.</p>
+{{- end }}
+
+<h3>Offline environments</h3>
+{{- if .OfflineEnvironments }}
+<ul>
+{{- range .OfflineEnvironments }}
+<li>{{ .Name }}{{ if not .LastSeen.IsZero }}, last seen {{ datetime .LastSeen }}{{ end }}</li>
+{{- end }}
+</ul>
+{{- else }}
+<p>All the environments are online.</p>
+{{- end }}
+
+<h3>Resource usage</h3>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Environment</th><th>Nodes</th><th>CPU</th><th>Memory</th><th>Running containers</th><th>Stopped containers</th><th>Unhealthy containers</th><th>Disk used</th></tr>
+{{- range .Environments }}
+<tr><td>{{ .Name }}</td><td>{{ .NodeCount }}</td><td>{{ .CPU }}</td><td>{{ bytes .Memory }}</td><td>{{ .RunningContainers }}</td><td>{{ .StoppedContainers }}</td><td>{{ .UnhealthyContainers }}</td><td>{{ bytes .DiskUsed }}</td></tr>
+{{- end }}
+</table>
+</body>
+</html>
+`
+
+var templateFuncs = map[string]any{
+	"date": func(t time.Time) string {
+		return t.UTC().Format(time.DateOnly)
+	},
+	"datetime": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04 MST")
+	},
+	"bytes": formatBytes,
+}
+
+// ValidateTemplate returns an error when the subject or the body of a template cannot be parsed
+func ValidateTemplate(subject, body string) error {
+	if _, err := texttemplate.New("subject").Funcs(templateFuncs).Parse(subject); err != nil {
+		return fmt.Errorf("invalid subject template: %w", err)
+	}
+
+	if _, err := htmltemplate.New("body").Funcs(templateFuncs).Parse(body); err != nil {
+		return fmt.Errorf("invalid body template: %w", err)
+	}
+
+	return nil
+}
+
+// Render renders the subject and the HTML body of the email of a report
+func Render(template *portainer.ReportTemplate, report *Report) (string, string, error) {
+	subjectTemplate, err := texttemplate.New("subject").Funcs(templateFuncs).Parse(template.Subject)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid subject template: %w", err)
+	}
+
+	bodyTemplate, err := htmltemplate.New("body").Funcs(templateFuncs).Parse(template.Body)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid body template: %w", err)
+	}
+
+	var subject bytes.Buffer
+	if err := subjectTemplate.Execute(&subject, report); err != nil {
+		return "", "", fmt.Errorf("unable to render the subject: %w", err)
+	}
+
+	var body bytes.Buffer
+	if err := bodyTemplate.Execute(&body, report); err != nil {
+		return "", "", fmt.Errorf("unable to render the body: %w", err)
+	}
+
+	// The subject of an email is a single line
+	return strings.Join(strings.Fields(subject.String()), " "), body.String(), nil
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,297 @@
+package reports
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/snapshot"
+)
+
+// maxListedItems is the maximum number of failed deployments and incidents detailed in a report
+const maxListedItems = 50
+
+// Report is the summary of the activity of a group rendered by the report templates
+type Report struct {
+	Name      string
+	GroupName string
+	// Period covered by the report
+	From time.Time
+	To   time.Time
+
+	Environments        []EnvironmentUsage
+	OfflineEnvironments []OfflineEnvironment
+	Deployments         DeploymentSummary
+	// Number of incidents during the period
+	IncidentCount int
+	// Latest incidents, most recent first
+	Incidents []Incident
+}
+
+// EnvironmentUsage represents the resources of an environment(endpoint) in its latest snapshot
+type EnvironmentUsage struct {
+	ID   portainer.EndpointID
+	Name string
+	// Time of the latest snapshot, zero when the environment was never snapshotted
+	SnapshotTime        time.Time
+	NodeCount           int
+	CPU                 int64
+	Memory              int64
+	RunningContainers   int
+	StoppedContainers   int
+	UnhealthyContainers int
+	// Bytes used in the Docker data directory, 0 when unknown
+	DiskUsed int64
+}
+
+// OfflineEnvironment represents an environment(endpoint) unreachable when the report is generated
+type OfflineEnvironment struct {
+	ID   portainer.EndpointID
+	Name string
+	// Last time the environment was reached, zero when unknown
+	LastSeen time.Time
+}
+
+// DeploymentSummary represents the deployments made on the environments(endpoints) of the group
+type DeploymentSummary struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	// Latest failed deployments, most recent first
+	Failures []DeploymentFailure
+}
+
+// DeploymentFailure represents a failed deployment
+type DeploymentFailure struct {
+	EnvironmentName string
+	StackID         portainer.StackID
+	Initiator       string
+	Time            time.Time
+	Error           string
+}
+
+// Incident represents a container that failed, recorded in the activity feed of an environment(endpoint)
+type Incident struct {
+	EnvironmentName string
+	Time            time.Time
+	// Name of the container
+	Container string
+	// Description of the failure, e.g. "exited with code 1"
+	Description string
+}
+
+// Generate summarizes the activity of the group of a report during the period preceding now
+func Generate(tx dataservices.DataStoreTx, groupReport *portainer.GroupReport, now time.Time) (*Report, error) {
+	group, err := tx.EndpointGroup().Read(groupReport.EndpointGroupID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the environment group: %w", err)
+	}
+
+	settings, err := tx.Settings().Settings()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the settings: %w", err)
+	}
+
+	endpoints, err := tx.Endpoint().Endpoints()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the environments: %w", err)
+	}
+
+	report := &Report{
+		Name:                groupReport.Name,
+		GroupName:           group.Name,
+		From:                now.AddDate(0, 0, -groupReport.Period),
+		To:                  now,
+		Environments:        []EnvironmentUsage{},
+		OfflineEnvironments: []OfflineEnvironment{},
+		Incidents:           []Incident{},
+	}
+
+	names := map[portainer.EndpointID]string{}
+	for i := range endpoints {
+		endpoint := &endpoints[i]
+		if endpoint.GroupID != group.ID {
+			continue
+		}
+
+		names[endpoint.ID] = endpoint.Name
+
+		if err := snapshot.FillSnapshotSummary(tx, endpoint); err != nil {
+			return nil, fmt.Errorf("unable to retrieve the snapshot of environment %d: %w", endpoint.ID, err)
+		}
+
+		usage := environmentUsage(endpoint)
+		report.Environments = append(report.Environments, usage)
+
+		if isOffline(endpoint, settings) {
+			lastSeen := usage.SnapshotTime
+			if endpointutils.IsEdgeEndpoint(endpoint) && endpoint.LastCheckInDate > 0 {
+				lastSeen = time.Unix(endpoint.LastCheckInDate, 0)
+			}
+
+			report.OfflineEnvironments = append(report.OfflineEnvironments, OfflineEnvironment{
+				ID:       endpoint.ID,
+				Name:     endpoint.Name,
+				LastSeen: lastSeen,
+			})
+		}
+
+		incidents, err := environmentIncidents(tx, endpoint, report.From, report.To)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Incidents = append(report.Incidents, incidents...)
+	}
+
+	report.Deployments, err = deploymentSummary(tx, names, report.From, report.To)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(report.Incidents, func(a, b Incident) int {
+		return b.Time.Compare(a.Time)
+	})
+
+	report.IncidentCount = len(report.Incidents)
+	report.Incidents = report.Incidents[:min(len(report.Incidents), maxListedItems)]
+
+	return report, nil
+}
+
+func environmentUsage(endpoint *portainer.Endpoint) EnvironmentUsage {
+	usage := EnvironmentUsage{
+		ID:   endpoint.ID,
+		Name: endpoint.Name,
+	}
+
+	if len(endpoint.Snapshots) > 0 {
+		snapshot := endpoint.Snapshots[len(endpoint.Snapshots)-1]
+
+		usage.SnapshotTime = time.Unix(snapshot.Time, 0)
+		usage.NodeCount = snapshot.NodeCount
+		usage.CPU = int64(snapshot.TotalCPU)
+		usage.Memory = snapshot.TotalMemory
+		usage.RunningContainers = snapshot.RunningContainerCount
+		usage.StoppedContainers = snapshot.StoppedContainerCount
+		usage.UnhealthyContainers = snapshot.UnhealthyContainerCount
+
+		if snapshot.DiskUsage != nil {
+			usage.DiskUsed = snapshot.DiskUsage.DataRootUsed
+		}
+	} else if len(endpoint.Kubernetes.Snapshots) > 0 {
+		snapshot := endpoint.Kubernetes.Snapshots[len(endpoint.Kubernetes.Snapshots)-1]
+
+		usage.SnapshotTime = time.Unix(snapshot.Time, 0)
+		usage.NodeCount = snapshot.NodeCount
+		usage.CPU = snapshot.TotalCPU
+		usage.Memory = snapshot.TotalMemory
+	}
+
+	return usage
+}
+
+// isOffline returns true when the environment is unreachable, Edge environments are offline
+// when they missed their check-ins
+func isOffline(endpoint *portainer.Endpoint, settings *portainer.Settings) bool {
+	if endpointutils.IsEdgeEndpoint(endpoint) {
+		endpointutils.UpdateEdgeEndpointHeartbeat(endpoint, settings)
+
+		return !endpoint.Heartbeat
+	}
+
+	return endpoint.Status == portainer.EndpointStatusDown
+}
+
+func environmentIncidents(tx dataservices.DataStoreTx, endpoint *portainer.Endpoint, from, to time.Time) ([]Incident, error) {
+	events, err := tx.ActivityEvent().ReadAllByEndpointID(endpoint.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the activity of the environment: %w", err)
+	}
+
+	incidents := []Incident{}
+	for _, event := range events {
+		if event.Type != "container" || event.Time < from.Unix() || event.Time > to.Unix() {
+			continue
+		}
+
+		description := incidentDescription(event)
+		if description == "" {
+			continue
+		}
+
+		incidents = append(incidents, Incident{
+			EnvironmentName: endpoint.Name,
+			Time:            time.Unix(event.Time, 0),
+			Container:       cmp.Or(event.ActorName, event.ActorID),
+			Description:     description,
+		})
+	}
+
+	return incidents, nil
+}
+
+// incidentDescription describes the failure reported by a container event, it returns an empty
+// string for the events that are not failures
+func incidentDescription(event portainer.ActivityEvent) string {
+	switch event.Action {
+	case "oom":
+		return "ran out of memory"
+	case "health_status":
+		if event.ActionDetail == "unhealthy" {
+			return "became unhealthy"
+		}
+	case "die":
+		if exitCode := event.Attributes["exitCode"]; exitCode != "" && exitCode != "0" {
+			return "exited with code " + exitCode
+		}
+	}
+
+	return ""
+}
+
+func deploymentSummary(tx dataservices.DataStoreTx, names map[portainer.EndpointID]string, from, to time.Time) (DeploymentSummary, error) {
+	deployments, err := tx.Deployment().ReadAllByFilter(func(deployment portainer.Deployment) bool {
+		_, ok := names[deployment.EndpointID]
+
+		return ok && deployment.StartedAt >= from.Unix() && deployment.StartedAt <= to.Unix()
+	})
+	if err != nil {
+		return DeploymentSummary{}, fmt.Errorf("unable to retrieve the deployments: %w", err)
+	}
+
+	slices.SortFunc(deployments, func(a, b portainer.Deployment) int {
+		return cmp.Compare(b.StartedAt, a.StartedAt)
+	})
+
+	summary := DeploymentSummary{
+		Total:    len(deployments),
+		Failures: []DeploymentFailure{},
+	}
+
+	for _, deployment := range deployments {
+		if deployment.Status != portainer.DeploymentStatusFailed {
+			summary.Succeeded++
+
+			continue
+		}
+
+		summary.Failed++
+
+		if len(summary.Failures) < maxListedItems {
+			summary.Failures = append(summary.Failures, DeploymentFailure{
+				EnvironmentName: names[deployment.EndpointID],
+				StackID:         deployment.StackID,
+				Initiator:       deployment.Initiator,
+				Time:            time.Unix(deployment.StartedAt, 0),
+				Error:           deployment.Error,
+			})
+		}
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,127 @@
+package reports
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/internal/notifications"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEmailSender struct {
+	messages []notifications.EmailMessage
+	err      error
+}
+
+func (sender *testEmailSender) SendEmail(settings portainer.SMTPSettings, message notifications.EmailMessage) error {
+	sender.messages = append(sender.messages, message)
+
+	return sender.err
+}
+
+func TestGenerate(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	now := time.Now()
+
+	group := &portainer.EndpointGroup{Name: "production"}
+	require.NoError(t, store.EndpointGroup().Create(group))
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{
+		ID:      1,
+		Name:    "web",
+		GroupID: group.ID,
+		Type:    portainer.DockerEnvironment,
+		Status:  portainer.EndpointStatusUp,
+	}))
+	require.NoError(t, store.Snapshot().Create(&portainer.Snapshot{
+		EndpointID: 1,
+		Docker: &portainer.DockerSnapshot{
+			Time:                  now.Unix(),
+			TotalCPU:              4,
+			TotalMemory:           8 << 30,
+			RunningContainerCount: 3,
+			DiskUsage:             &portainer.DockerDiskUsage{DataRootUsed: 1 << 30},
+		},
+	}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 2, Name: "db", GroupID: group.ID, Type: portainer.DockerEnvironment, Status: portainer.EndpointStatusDown}))
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 3, Name: "other", GroupID: 1, Type: portainer.DockerEnvironment, Status: portainer.EndpointStatusDown}))
+
+	for _, deployment := range []portainer.Deployment{
+		{EndpointID: 1, StartedAt: now.Add(-time.Hour).Unix(), Status: portainer.DeploymentStatusSuccess},
+		{EndpointID: 1, StartedAt: now.Add(-2 * time.Hour).Unix(), Status: portainer.DeploymentStatusFailed, Initiator: "admin", Error: "image not found"},
+		{EndpointID: 1, StartedAt: now.AddDate(0, 0, -8).Unix(), Status: portainer.DeploymentStatusFailed},
+		{EndpointID: 3, StartedAt: now.Add(-time.Hour).Unix(), Status: portainer.DeploymentStatusFailed},
+	} {
+		require.NoError(t, store.Deployment().Create(&deployment))
+	}
+
+	for _, event := range []portainer.ActivityEvent{
+		{EndpointID: 1, Time: now.Add(-time.Hour).Unix(), Type: "container", Action: "die", ActorName: "api", Attributes: map[string]string{"exitCode": "137"}},
+		{EndpointID: 1, Time: now.Add(-2 * time.Hour).Unix(), Type: "container", Action: "die", ActorName: "migrate", Attributes: map[string]string{"exitCode": "0"}},
+		{EndpointID: 1, Time: now.Add(-3 * time.Hour).Unix(), Type: "container", Action: "health_status", ActionDetail: "unhealthy", ActorName: "db"},
+		{EndpointID: 3, Time: now.Add(-time.Hour).Unix(), Type: "container", Action: "oom", ActorName: "other"},
+	} {
+		require.NoError(t, store.ActivityEvent().Create(&event))
+	}
+
+	groupReport := &portainer.GroupReport{Name: "weekly", EndpointGroupID: group.ID, Period: 7}
+
+	report, err := Generate(store, groupReport, now)
+	require.NoError(t, err)
+
+	assert.Equal(t, "production", report.GroupName)
+	assert.Len(t, report.Environments, 2)
+	assert.Equal(t, int64(1<<30), report.Environments[0].DiskUsed)
+	assert.Equal(t, []OfflineEnvironment{{ID: 2, Name: "db"}}, report.OfflineEnvironments)
+
+	assert.Equal(t, 2, report.Deployments.Total)
+	assert.Equal(t, 1, report.Deployments.Failed)
+	require.Len(t, report.Deployments.Failures, 1)
+	assert.Equal(t, "image not found", report.Deployments.Failures[0].Error)
+
+	assert.Equal(t, 2, report.IncidentCount)
+	assert.Equal(t, "exited with code 137", report.Incidents[0].Description)
+	assert.Equal(t, "became unhealthy", report.Incidents[1].Description)
+
+	subject, body, err := Render(&DefaultTemplate, report)
+	require.NoError(t, err)
+	assert.Contains(t, subject, "production report")
+	assert.Contains(t, body, "image not found")
+	assert.Contains(t, body, "8.0 GiB")
+}
+
+func TestRun(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	template := &portainer.ReportTemplate{Subject: "{{ .GroupName }}\nreport", Body: "<p>{{ .Deployments.Total }}</p>"}
+	require.NoError(t, store.ReportTemplate().Create(template))
+
+	groupReport := &portainer.GroupReport{Name: "weekly", EndpointGroupID: 1, TemplateID: template.ID, Period: 7, Recipients: []string{"ops@example.com"}}
+	require.NoError(t, store.GroupReport().Create(groupReport))
+
+	sender := &testEmailSender{}
+	service := NewService(store, sender, nil)
+
+	require.NoError(t, service.Run(groupReport.ID))
+	require.Len(t, sender.messages, 1)
+	assert.Equal(t, notifications.EmailMessage{
+		To:      []string{"ops@example.com"},
+		Subject: "Unassigned report",
+		Body:    "<p>0</p>",
+	}, sender.messages[0])
+
+	// The failures are saved on the report
+	sender.err = errors.New("connection refused")
+	require.Error(t, service.Run(groupReport.ID))
+
+	groupReport, err := store.GroupReport().Read(groupReport.ID)
+	require.NoError(t, err)
+	assert.NotZero(t, groupReport.LastRun)
+	assert.Equal(t, "connection refused", groupReport.LastError)
+}
@@ -0,0 +1,207 @@
+package reports
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/notifications"
+	"github.com/portainer/portainer/api/scheduler"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// Service schedules the group reports and sends them by email
+type Service struct {
+	dataStore dataservices.DataStore
+	sender    notifications.EmailSender
+	scheduler *scheduler.Scheduler
+
+	mu        sync.Mutex
+	scheduled map[portainer.GroupReportID]string
+	running   map[portainer.GroupReportID]bool
+}
+
+// NewService returns a new instance of a service
+func NewService(dataStore dataservices.DataStore, sender notifications.EmailSender, scheduler *scheduler.Scheduler) *Service {
+	return &Service{
+		dataStore: dataStore,
+		sender:    sender,
+		scheduler: scheduler,
+		scheduled: make(map[portainer.GroupReportID]string),
+		running:   make(map[portainer.GroupReportID]bool),
+	}
+}
+
+// Start schedules all the enabled group reports
+func (service *Service) Start() error {
+	reports, err := service.dataStore.GroupReport().ReadAll()
+	if err != nil {
+		return errors.Wrap(err, "unable to retrieve the group reports")
+	}
+
+	for i := range reports {
+		if err := service.Schedule(&reports[i]); err != nil {
+			log.Warn().Err(err).Int("report_id", int(reports[i].ID)).Msg("unable to schedule the group report")
+		}
+	}
+
+	return nil
+}
+
+// Schedule (re)schedules a report according to its cron expression, it is unscheduled when disabled
+func (service *Service) Schedule(report *portainer.GroupReport) error {
+	service.Unschedule(report.ID)
+
+	if !report.Enabled {
+		return nil
+	}
+
+	reportID := report.ID
+	schedulerID, err := service.scheduler.StartJobCron(report.CronExpression, func() error {
+		err := service.Run(reportID)
+		if _, readErr := service.dataStore.GroupReport().Read(reportID); service.dataStore.IsErrObjectNotFound(readErr) {
+			// The report was removed along with its environment group
+			service.mu.Lock()
+			delete(service.scheduled, reportID)
+			service.mu.Unlock()
+
+			return scheduler.NewPermanentError(readErr)
+		}
+
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	service.mu.Lock()
+	service.scheduled[report.ID] = schedulerID
+	service.mu.Unlock()
+
+	return nil
+}
+
+// Unschedule prevents any future run of a report
+func (service *Service) Unschedule(reportID portainer.GroupReportID) {
+	service.mu.Lock()
+	schedulerID, ok := service.scheduled[reportID]
+	delete(service.scheduled, reportID)
+	service.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	if err := service.scheduler.StopJob(schedulerID); err != nil {
+		log.Warn().Err(err).Int("report_id", int(reportID)).Msg("unable to stop the group report")
+	}
+}
+
+// Preview renders the subject and the body of a report as it would be sent now
+func (service *Service) Preview(reportID portainer.GroupReportID) (string, string, error) {
+	var subject, body string
+
+	err := service.dataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		groupReport, err := tx.GroupReport().Read(reportID)
+		if err != nil {
+			return err
+		}
+
+		subject, body, err = render(tx, groupReport, time.Now())
+
+		return err
+	})
+
+	return subject, body, err
+}
+
+// Run generates a report and sends it to its recipients. The outcome is saved on the report.
+func (service *Service) Run(reportID portainer.GroupReportID) error {
+	service.mu.Lock()
+	if service.running[reportID] {
+		service.mu.Unlock()
+
+		return fmt.Errorf("group report %d is already running", reportID)
+	}
+	service.running[reportID] = true
+	service.mu.Unlock()
+
+	defer func() {
+		service.mu.Lock()
+		delete(service.running, reportID)
+		service.mu.Unlock()
+	}()
+
+	var groupReport *portainer.GroupReport
+	var subject, body string
+	var smtpSettings portainer.SMTPSettings
+
+	err := service.dataStore.ViewTx(func(tx dataservices.DataStoreTx) error {
+		var err error
+		if groupReport, err = tx.GroupReport().Read(reportID); err != nil {
+			return err
+		}
+
+		settings, err := tx.Settings().Settings()
+		if err != nil {
+			return err
+		}
+		smtpSettings = settings.SMTPSettings
+
+		subject, body, err = render(tx, groupReport, time.Now())
+
+		return err
+	})
+	if groupReport == nil {
+		return err
+	}
+
+	runErr := err
+	if runErr == nil {
+		runErr = service.sender.SendEmail(smtpSettings, notifications.EmailMessage{
+			To:      groupReport.Recipients,
+			Subject: subject,
+			Body:    body,
+		})
+	}
+
+	if updateErr := service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		groupReport, err := tx.GroupReport().Read(reportID)
+		if err != nil {
+			return err
+		}
+
+		groupReport.LastRun = time.Now().Unix()
+		groupReport.LastError = ""
+		if runErr != nil {
+			groupReport.LastError = runErr.Error()
+		}
+
+		return tx.GroupReport().Update(groupReport.ID, groupReport)
+	}); updateErr != nil {
+		log.Warn().Err(updateErr).Int("report_id", int(reportID)).Msg("unable to save the outcome of the group report")
+	}
+
+	return runErr
+}
+
+func render(tx dataservices.DataStoreTx, groupReport *portainer.GroupReport, now time.Time) (string, string, error) {
+	template := &DefaultTemplate
+	if groupReport.TemplateID != 0 {
+		var err error
+		if template, err = tx.ReportTemplate().Read(groupReport.TemplateID); err != nil {
+			return "", "", fmt.Errorf("unable to retrieve the report template: %w", err)
+		}
+	}
+
+	report, err := Generate(tx, groupReport, now)
+	if err != nil {
+		return "", "", err
+	}
+
+	return Render(template, report)
+}
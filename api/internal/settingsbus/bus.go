@@ -0,0 +1,74 @@
+package settingsbus
+
+import (
+	"sync"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Change describes an update of the settings, the values are shared between
+// the listeners and must not be modified
+type Change struct {
+	Previous portainer.Settings
+	Current  portainer.Settings
+}
+
+// Listener is notified of each change of the settings. It is called synchronously
+// by the publisher and must not block.
+type Listener func(change Change)
+
+// Bus notifies the background services of the changes of the settings so that
+// they can apply the new values without a restart
+type Bus struct {
+	publishMu   sync.Mutex
+	listenersMu sync.RWMutex
+	listeners   []Listener
+}
+
+// New returns a new instance of Bus
+func New() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a listener notified of the next changes of the settings
+func (bus *Bus) Subscribe(listener Listener) {
+	bus.listenersMu.Lock()
+	defer bus.listenersMu.Unlock()
+
+	bus.listeners = append(bus.listeners, listener)
+}
+
+// Publish notifies the listeners of a change of the settings, it must be called once the
+// change is persisted. Changes are delivered one at a time, in the order they are published.
+func (bus *Bus) Publish(previous, current *portainer.Settings) {
+	if previous == nil || current == nil {
+		return
+	}
+
+	bus.publishMu.Lock()
+	defer bus.publishMu.Unlock()
+
+	bus.listenersMu.RLock()
+	listeners := bus.listeners
+	bus.listenersMu.RUnlock()
+
+	change := Change{Previous: *previous, Current: *current}
+
+	for _, listener := range listeners {
+		notify(listener, change)
+	}
+}
+
+// notify isolates the listeners from each other so that a faulty one cannot prevent the others
+// from receiving the change
+func notify(listener Listener, change Change) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error().Any("panic", r).Msg("settings change listener failure")
+		}
+	}()
+
+	listener(change)
+}
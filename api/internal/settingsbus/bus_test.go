@@ -0,0 +1,60 @@
+package settingsbus
+
+import (
+	"sync"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublish(t *testing.T) {
+	bus := New()
+
+	var received []Change
+	bus.Subscribe(func(change Change) {
+		panic("faulty listener")
+	})
+	bus.Subscribe(func(change Change) {
+		received = append(received, change)
+	})
+
+	bus.Publish(&portainer.Settings{SnapshotInterval: "5m"}, &portainer.Settings{SnapshotInterval: "10m"})
+	bus.Publish(nil, &portainer.Settings{})
+
+	// The faulty listener does not prevent the delivery to the other ones
+	assert.Equal(t, []Change{{
+		Previous: portainer.Settings{SnapshotInterval: "5m"},
+		Current:  portainer.Settings{SnapshotInterval: "10m"},
+	}}, received)
+}
+
+func TestPublishConcurrently(t *testing.T) {
+	bus := New()
+
+	count := 0
+	bus.Subscribe(func(change Change) {
+		// Changes are delivered one at a time
+		count++
+	})
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			bus.Publish(&portainer.Settings{}, &portainer.Settings{})
+		}()
+
+		go func() {
+			defer wg.Done()
+			bus.Subscribe(func(change Change) {})
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 50, count)
+}
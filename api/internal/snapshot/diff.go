@@ -0,0 +1,320 @@
+package snapshot
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/docker/docker/api/types/swarm"
+)
+
+type (
+	// SnapshotDiff represents what changed on an environment(endpoint) between two snapshots
+	SnapshotDiff struct {
+		// Unix timestamp of the oldest snapshot
+		From int64 `json:"From" example:"1587399600"`
+		// Unix timestamp of the newest snapshot
+		To int64 `json:"To" example:"1587403200"`
+		// Changes of the environment itself, such as its engine version or its number of nodes
+		Changes    []FieldChange `json:"Changes"`
+		Containers ResourceDiff  `json:"Containers"`
+		Images     ResourceDiff  `json:"Images"`
+		Volumes    ResourceDiff  `json:"Volumes"`
+		Services   ResourceDiff  `json:"Services"`
+	}
+
+	// ResourceDiff lists the resources of a kind created, removed or changed between two snapshots
+	ResourceDiff struct {
+		Created []ResourceSummary `json:"Created"`
+		Removed []ResourceSummary `json:"Removed"`
+		Changed []ResourceChange  `json:"Changed"`
+	}
+
+	// ResourceSummary identifies a resource found in a snapshot
+	ResourceSummary struct {
+		ID   string `json:"Id"`
+		Name string `json:"Name"`
+	}
+
+	// ResourceChange represents a resource present in both snapshots whose properties changed
+	ResourceChange struct {
+		ResourceSummary
+		Changes []FieldChange `json:"Changes"`
+	}
+
+	// FieldChange represents a property whose value changed between two snapshots
+	FieldChange struct {
+		Field string `json:"Field" example:"Image"`
+		From  string `json:"From" example:"nginx:1.25"`
+		To    string `json:"To" example:"nginx:1.27"`
+	}
+
+	// snapshotResource is a resource of a snapshot along with the properties compared between snapshots
+	snapshotResource struct {
+		key     string
+		summary ResourceSummary
+		fields  []resourceField
+	}
+
+	resourceField struct {
+		name  string
+		value string
+	}
+)
+
+// DiffSnapshots compares two recorded snapshots of the same environment(endpoint)
+func DiffSnapshots(from, to *portainer.SnapshotRecord) SnapshotDiff {
+	fromDocker, toDocker := dockerSnapshotOrEmpty(from.Docker), dockerSnapshotOrEmpty(to.Docker)
+
+	return SnapshotDiff{
+		From:       from.Time,
+		To:         to.Time,
+		Changes:    diffFields(environmentFields(from), environmentFields(to)),
+		Containers: diffResources(containerResources(fromDocker), containerResources(toDocker)),
+		Images:     diffResources(imageResources(fromDocker), imageResources(toDocker)),
+		Volumes:    diffResources(volumeResources(fromDocker), volumeResources(toDocker)),
+		Services:   diffResources(serviceResources(fromDocker), serviceResources(toDocker)),
+	}
+}
+
+// LatestRecord returns the latest record taken at or before the timestamp, or the latest record when the timestamp is 0
+func LatestRecord(records []portainer.SnapshotRecord, timestamp int64) *portainer.SnapshotRecord {
+	var latest *portainer.SnapshotRecord
+
+	for i := range records {
+		if timestamp != 0 && records[i].Time > timestamp {
+			continue
+		}
+
+		if latest == nil || records[i].Time > latest.Time {
+			latest = &records[i]
+		}
+	}
+
+	return latest
+}
+
+func dockerSnapshotOrEmpty(dockerSnapshot *portainer.DockerSnapshot) *portainer.DockerSnapshot {
+	if dockerSnapshot == nil {
+		return &portainer.DockerSnapshot{}
+	}
+
+	return dockerSnapshot
+}
+
+func environmentFields(record *portainer.SnapshotRecord) []resourceField {
+	var fields []resourceField
+
+	if record.Docker != nil {
+		fields = append(fields,
+			resourceField{"DockerVersion", record.Docker.DockerVersion},
+			resourceField{"Swarm", strconv.FormatBool(record.Docker.Swarm)},
+			resourceField{"NodeCount", strconv.Itoa(record.Docker.NodeCount)},
+			resourceField{"TotalCPU", strconv.Itoa(record.Docker.TotalCPU)},
+			resourceField{"TotalMemory", strconv.FormatInt(record.Docker.TotalMemory, 10)},
+		)
+	}
+
+	if record.Kubernetes != nil {
+		fields = append(fields,
+			resourceField{"KubernetesVersion", record.Kubernetes.KubernetesVersion},
+			resourceField{"NodeCount", strconv.Itoa(record.Kubernetes.NodeCount)},
+			resourceField{"TotalCPU", strconv.FormatInt(record.Kubernetes.TotalCPU, 10)},
+			resourceField{"TotalMemory", strconv.FormatInt(record.Kubernetes.TotalMemory, 10)},
+		)
+	}
+
+	return fields
+}
+
+// containerResources identifies the containers by name, so that a recreated container is reported as changed
+func containerResources(dockerSnapshot *portainer.DockerSnapshot) []snapshotResource {
+	resources := make([]snapshotResource, 0, len(dockerSnapshot.SnapshotRaw.Containers))
+
+	for _, container := range dockerSnapshot.SnapshotRaw.Containers {
+		name := container.ID
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+
+		resources = append(resources, snapshotResource{
+			key:     name,
+			summary: ResourceSummary{ID: container.ID, Name: name},
+			fields: []resourceField{
+				{"Image", container.Image},
+				{"ImageID", container.ImageID},
+				{"State", container.State},
+			},
+		})
+	}
+
+	return resources
+}
+
+func imageResources(dockerSnapshot *portainer.DockerSnapshot) []snapshotResource {
+	resources := make([]snapshotResource, 0, len(dockerSnapshot.SnapshotRaw.Images))
+
+	for _, image := range dockerSnapshot.SnapshotRaw.Images {
+		tags := slices.Clone(image.RepoTags)
+		slices.Sort(tags)
+
+		name := image.ID
+		if len(tags) > 0 {
+			name = tags[0]
+		}
+
+		resources = append(resources, snapshotResource{
+			key:     image.ID,
+			summary: ResourceSummary{ID: image.ID, Name: name},
+			fields:  []resourceField{{"RepoTags", strings.Join(tags, ",")}},
+		})
+	}
+
+	return resources
+}
+
+func volumeResources(dockerSnapshot *portainer.DockerSnapshot) []snapshotResource {
+	resources := make([]snapshotResource, 0, len(dockerSnapshot.SnapshotRaw.Volumes.Volumes))
+
+	for _, volume := range dockerSnapshot.SnapshotRaw.Volumes.Volumes {
+		if volume == nil {
+			continue
+		}
+
+		resources = append(resources, snapshotResource{
+			key:     volume.Name,
+			summary: ResourceSummary{ID: volume.Name, Name: volume.Name},
+			fields:  []resourceField{{"Driver", volume.Driver}},
+		})
+	}
+
+	return resources
+}
+
+func serviceResources(dockerSnapshot *portainer.DockerSnapshot) []snapshotResource {
+	resources := make([]snapshotResource, 0, len(dockerSnapshot.SnapshotRaw.Services))
+
+	for _, service := range dockerSnapshot.SnapshotRaw.Services {
+		resources = append(resources, snapshotResource{
+			key:     service.ID,
+			summary: ResourceSummary{ID: service.ID, Name: service.Spec.Name},
+			fields: []resourceField{
+				{"Image", serviceImage(service)},
+				{"Mode", serviceMode(service.Spec.Mode)},
+				{"Replicas", strconv.Itoa(serviceReplicas(service.Spec.Mode, dockerSnapshot.NodeCount))},
+			},
+		})
+	}
+
+	return resources
+}
+
+func serviceImage(service swarm.Service) string {
+	if service.Spec.TaskTemplate.ContainerSpec == nil {
+		return ""
+	}
+
+	return service.Spec.TaskTemplate.ContainerSpec.Image
+}
+
+func serviceMode(mode swarm.ServiceMode) string {
+	switch {
+	case mode.Global != nil:
+		return "global"
+	case mode.ReplicatedJob != nil:
+		return "replicated-job"
+	case mode.GlobalJob != nil:
+		return "global-job"
+	default:
+		return "replicated"
+	}
+}
+
+// serviceReplicas returns the number of tasks run by a Swarm service, global services run one task per node.
+// It mirrors quotas.ServiceReplicas, which cannot be imported here as the quotas tests depend on the datastore
+func serviceReplicas(mode swarm.ServiceMode, nodes int) int {
+	switch {
+	case mode.Global != nil:
+		return nodes
+	case mode.Replicated != nil && mode.Replicated.Replicas != nil:
+		return int(*mode.Replicated.Replicas)
+	}
+
+	return 1
+}
+
+func diffResources(from, to []snapshotResource) ResourceDiff {
+	diff := ResourceDiff{
+		Created: []ResourceSummary{},
+		Removed: []ResourceSummary{},
+		Changed: []ResourceChange{},
+	}
+
+	previous := make(map[string]snapshotResource, len(from))
+	for _, resource := range from {
+		previous[resource.key] = resource
+	}
+
+	for _, resource := range to {
+		old, ok := previous[resource.key]
+		if !ok {
+			diff.Created = append(diff.Created, resource.summary)
+
+			continue
+		}
+
+		delete(previous, resource.key)
+
+		if changes := diffFields(old.fields, resource.fields); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, ResourceChange{ResourceSummary: resource.summary, Changes: changes})
+		}
+	}
+
+	for _, resource := range from {
+		if _, ok := previous[resource.key]; ok {
+			diff.Removed = append(diff.Removed, resource.summary)
+		}
+	}
+
+	compareSummaries := func(a, b ResourceSummary) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	}
+
+	slices.SortFunc(diff.Created, compareSummaries)
+	slices.SortFunc(diff.Removed, compareSummaries)
+	slices.SortFunc(diff.Changed, func(a, b ResourceChange) int {
+		return compareSummaries(a.ResourceSummary, b.ResourceSummary)
+	})
+
+	return diff
+}
+
+// diffFields compares the properties of a resource, a property missing from one side is compared against an empty value
+func diffFields(from, to []resourceField) []FieldChange {
+	changes := []FieldChange{}
+
+	previous := make(map[string]string, len(from))
+	for _, field := range from {
+		previous[field.name] = field.value
+	}
+
+	seen := make(map[string]struct{}, len(to))
+	for _, field := range to {
+		seen[field.name] = struct{}{}
+
+		if previous[field.name] != field.value {
+			changes = append(changes, FieldChange{Field: field.name, From: previous[field.name], To: field.value})
+		}
+	}
+
+	for _, field := range from {
+		if _, ok := seen[field.name]; !ok && field.value != "" {
+			changes = append(changes, FieldChange{Field: field.name, From: field.value})
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,117 @@
+package snapshot
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/swarm"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSnapshots_Docker(t *testing.T) {
+	replicas, scaledReplicas := uint64(1), uint64(3)
+
+	from := &portainer.SnapshotRecord{
+		Time: 100,
+		Docker: &portainer.DockerSnapshot{
+			DockerVersion: "25.0.0",
+			SnapshotRaw: portainer.DockerSnapshotRaw{
+				Containers: []portainer.DockerContainerSnapshot{
+					{Container: types.Container{ID: "a1", Names: []string{"/web"}, Image: "nginx:1.25", ImageID: "sha256:old", State: "running"}},
+					{Container: types.Container{ID: "b1", Names: []string{"/worker"}, Image: "worker", State: "running"}},
+				},
+				Images: []image.Summary{{ID: "sha256:old", RepoTags: []string{"nginx:1.25"}}},
+				Volumes: volume.ListResponse{Volumes: []*volume.Volume{
+					{Name: "data", Driver: "local"},
+				}},
+				Services: []swarm.Service{{
+					ID: "s1",
+					Spec: swarm.ServiceSpec{
+						Annotations: swarm.Annotations{Name: "api"},
+						Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &replicas}},
+					},
+				}},
+			},
+		},
+	}
+
+	to := &portainer.SnapshotRecord{
+		Time: 200,
+		Docker: &portainer.DockerSnapshot{
+			DockerVersion: "26.1.0",
+			SnapshotRaw: portainer.DockerSnapshotRaw{
+				Containers: []portainer.DockerContainerSnapshot{
+					{Container: types.Container{ID: "a2", Names: []string{"/web"}, Image: "nginx:1.27", ImageID: "sha256:new", State: "running"}},
+					{Container: types.Container{ID: "c1", Names: []string{"/cache"}, Image: "redis", State: "exited"}},
+				},
+				Images: []image.Summary{
+					{ID: "sha256:old", RepoTags: []string{"nginx:1.25"}},
+					{ID: "sha256:new", RepoTags: []string{"nginx:1.27"}},
+				},
+				Services: []swarm.Service{{
+					ID: "s1",
+					Spec: swarm.ServiceSpec{
+						Annotations: swarm.Annotations{Name: "api"},
+						Mode:        swarm.ServiceMode{Replicated: &swarm.ReplicatedService{Replicas: &scaledReplicas}},
+					},
+				}},
+			},
+		},
+	}
+
+	diff := DiffSnapshots(from, to)
+
+	assert.Equal(t, int64(100), diff.From)
+	assert.Equal(t, int64(200), diff.To)
+	assert.Equal(t, []FieldChange{{Field: "DockerVersion", From: "25.0.0", To: "26.1.0"}}, diff.Changes)
+
+	// The web container was recreated with a newer image
+	assert.Equal(t, []ResourceSummary{{ID: "c1", Name: "cache"}}, diff.Containers.Created)
+	assert.Equal(t, []ResourceSummary{{ID: "b1", Name: "worker"}}, diff.Containers.Removed)
+	assert.Equal(t, []ResourceChange{{
+		ResourceSummary: ResourceSummary{ID: "a2", Name: "web"},
+		Changes: []FieldChange{
+			{Field: "Image", From: "nginx:1.25", To: "nginx:1.27"},
+			{Field: "ImageID", From: "sha256:old", To: "sha256:new"},
+		},
+	}}, diff.Containers.Changed)
+
+	assert.Equal(t, []ResourceSummary{{ID: "sha256:new", Name: "nginx:1.27"}}, diff.Images.Created)
+	assert.Empty(t, diff.Images.Removed)
+	assert.Empty(t, diff.Images.Changed)
+
+	assert.Empty(t, diff.Volumes.Created)
+	assert.Equal(t, []ResourceSummary{{ID: "data", Name: "data"}}, diff.Volumes.Removed)
+
+	assert.Equal(t, []ResourceChange{{
+		ResourceSummary: ResourceSummary{ID: "s1", Name: "api"},
+		Changes:         []FieldChange{{Field: "Replicas", From: "1", To: "3"}},
+	}}, diff.Services.Changed)
+}
+
+func TestDiffSnapshots_Kubernetes(t *testing.T) {
+	from := &portainer.SnapshotRecord{Time: 100, Kubernetes: &portainer.KubernetesSnapshot{KubernetesVersion: "v1.29.0", NodeCount: 3, TotalCPU: 12, TotalMemory: 1024}}
+	to := &portainer.SnapshotRecord{Time: 200, Kubernetes: &portainer.KubernetesSnapshot{KubernetesVersion: "v1.29.0", NodeCount: 4, TotalCPU: 16, TotalMemory: 1024}}
+
+	diff := DiffSnapshots(from, to)
+
+	assert.Equal(t, []FieldChange{
+		{Field: "NodeCount", From: "3", To: "4"},
+		{Field: "TotalCPU", From: "12", To: "16"},
+	}, diff.Changes)
+	assert.Empty(t, diff.Containers.Created)
+	assert.NotNil(t, diff.Services.Changed)
+}
+
+func TestLatestRecord(t *testing.T) {
+	records := []portainer.SnapshotRecord{{Time: 100}, {Time: 300}, {Time: 200}}
+
+	assert.Equal(t, int64(200), LatestRecord(records, 250).Time)
+	assert.Equal(t, int64(100), LatestRecord(records, 100).Time)
+	assert.Equal(t, int64(300), LatestRecord(records, 0).Time)
+	assert.Nil(t, LatestRecord(records, 50))
+}
@@ -0,0 +1,72 @@
+package snapshot
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DetectedGPUs returns the GPUs of a snapshot as they are listed in the settings of an environment(endpoint),
+// each GPU is identified by its UUID so that it can be requested when creating a container
+func DetectedGPUs(gpus []portainer.DockerSnapshotGPU) []portainer.Pair {
+	pairs := make([]portainer.Pair, 0, len(gpus))
+	for _, gpu := range gpus {
+		name := fmt.Sprintf("GPU %d: %s", gpu.Index, gpu.Model)
+		if gpu.NodeName != "" {
+			name = gpu.NodeName + " - " + name
+		}
+
+		pairs = append(pairs, portainer.Pair{Name: name, Value: cmp.Or(gpu.UUID, strconv.Itoa(gpu.Index))})
+	}
+
+	return pairs
+}
+
+// applyDetectedGPUs replaces the GPUs of the environment(endpoint) by the detected ones, the GPUs set by a user are kept.
+// It returns false when the environment is left unchanged
+func applyDetectedGPUs(endpoint *portainer.Endpoint, gpus []portainer.Pair) bool {
+	if !endpoint.GpusDetected && (len(endpoint.Gpus) > 0 || len(gpus) == 0) {
+		return false
+	}
+
+	if endpoint.GpusDetected && slices.Equal(endpoint.Gpus, gpus) {
+		return false
+	}
+
+	endpoint.Gpus = gpus
+	endpoint.GpusDetected = true
+
+	return true
+}
+
+// updateDetectedGPUs persists the GPUs detected by a snapshot, the latest version of the environment(endpoint)
+// is updated since it can be changed while it is snapshotted
+func (service *Service) updateDetectedGPUs(endpoint *portainer.Endpoint, gpus []portainer.Pair) {
+	if !applyDetectedGPUs(endpoint, gpus) {
+		return
+	}
+
+	err := service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		latestEndpoint, err := tx.Endpoint().Endpoint(endpoint.ID)
+		if err != nil {
+			return err
+		}
+
+		if !applyDetectedGPUs(latestEndpoint, gpus) {
+			return nil
+		}
+
+		return tx.Endpoint().UpdateEndpoint(latestEndpoint.ID, latestEndpoint)
+	})
+
+	// The environments are snapshotted before they are created
+	if err != nil && !dataservices.IsErrObjectNotFound(err) {
+		log.Warn().Err(err).Int("endpoint_id", int(endpoint.ID)).Msg("unable to update the GPUs of the environment")
+	}
+}
@@ -0,0 +1,42 @@
+package snapshot
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectedGPUs(t *testing.T) {
+	gpus := DetectedGPUs([]portainer.DockerSnapshotGPU{
+		{Index: 0, UUID: "GPU-1", Model: "NVIDIA A100"},
+		{NodeName: "node-2", Index: 1, Model: "NVIDIA T4"},
+	})
+
+	assert.Equal(t, []portainer.Pair{
+		{Name: "GPU 0: NVIDIA A100", Value: "GPU-1"},
+		{Name: "node-2 - GPU 1: NVIDIA T4", Value: "1"},
+	}, gpus)
+}
+
+func TestApplyDetectedGPUs(t *testing.T) {
+	detected := []portainer.Pair{{Name: "GPU 0: NVIDIA A100", Value: "GPU-1"}}
+
+	// The GPUs set by a user are kept
+	endpoint := &portainer.Endpoint{Gpus: []portainer.Pair{{Name: "gpu0", Value: "0"}}}
+	assert.False(t, applyDetectedGPUs(endpoint, detected))
+	assert.Equal(t, "gpu0", endpoint.Gpus[0].Name)
+
+	// The environments without GPUs get the detected ones
+	endpoint = &portainer.Endpoint{}
+	assert.True(t, applyDetectedGPUs(endpoint, detected))
+	assert.True(t, endpoint.GpusDetected)
+	assert.Equal(t, detected, endpoint.Gpus)
+
+	assert.False(t, applyDetectedGPUs(endpoint, detected))
+
+	// The detected GPUs are refreshed
+	assert.True(t, applyDetectedGPUs(endpoint, []portainer.Pair{}))
+	assert.Empty(t, endpoint.Gpus)
+}
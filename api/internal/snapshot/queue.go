@@ -0,0 +1,226 @@
+package snapshot
+
+import (
+	"container/heap"
+	"math/rand"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+const (
+	// snapshotWorkers bounds the number of environments(endpoints) snapshotted concurrently
+	snapshotWorkers = 10
+	// maxBackoff bounds the delay before the next snapshot of an environment whose snapshots keep failing,
+	// unless the snapshot interval is longer
+	maxBackoff = time.Hour
+	// jitterRatio is the part of the snapshot interval added at random to the delay before the next snapshot
+	// of an environment so that the snapshots stay spread over time
+	jitterRatio = 0.1
+)
+
+type queueItem struct {
+	endpointID portainer.EndpointID
+	due        time.Time
+	failures   int
+	// index is the position of the item in the heap, -1 while its snapshot is running
+	index int
+}
+
+// itemHeap implements heap.Interface, the item due first is at the top
+type itemHeap []*queueItem
+
+func (h itemHeap) Len() int { return len(h) }
+
+func (h itemHeap) Less(i, j int) bool {
+	if h[i].due.Equal(h[j].due) {
+		return h[i].failures < h[j].failures
+	}
+
+	return h[i].due.Before(h[j].due)
+}
+
+func (h itemHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *itemHeap) Push(x any) {
+	item := x.(*queueItem)
+	item.index = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *itemHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	item.index = -1
+	*h = old[:n-1]
+
+	return item
+}
+
+// snapshotQueue orders the environments(endpoints) by the time of their next snapshot. The environments are spread
+// over the snapshot interval and the environments whose snapshots fail are retried with an exponential backoff
+type snapshotQueue struct {
+	mu       sync.Mutex
+	items    itemHeap
+	byID     map[portainer.EndpointID]*queueItem
+	interval time.Duration
+	rand     *rand.Rand
+	// wakeCh is notified when an item is added so that the dispatcher does not wait for the previous due time
+	wakeCh chan struct{}
+}
+
+func newSnapshotQueue(interval time.Duration, rnd *rand.Rand) *snapshotQueue {
+	return &snapshotQueue{
+		byID:     make(map[portainer.EndpointID]*queueItem),
+		interval: interval,
+		rand:     rnd,
+		wakeCh:   make(chan struct{}, 1),
+	}
+}
+
+// sync adds the new environments to the queue, spreading their first snapshot over the interval,
+// and drops the environments that are no longer snapshotted
+func (q *snapshotQueue) sync(endpointIDs []portainer.EndpointID, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	current := make(map[portainer.EndpointID]bool, len(endpointIDs))
+	added := false
+
+	for _, endpointID := range endpointIDs {
+		current[endpointID] = true
+
+		if _, ok := q.byID[endpointID]; ok {
+			continue
+		}
+
+		item := &queueItem{endpointID: endpointID, due: now.Add(q.randomDuration(q.interval))}
+		q.byID[endpointID] = item
+		heap.Push(&q.items, item)
+		added = true
+	}
+
+	for endpointID, item := range q.byID {
+		if current[endpointID] {
+			continue
+		}
+
+		delete(q.byID, endpointID)
+
+		if item.index >= 0 {
+			heap.Remove(&q.items, item.index)
+		}
+	}
+
+	if added {
+		q.wake()
+	}
+}
+
+// setInterval applies a new snapshot interval, the snapshots due after the new interval are brought forward
+func (q *snapshotQueue) setInterval(interval time.Duration, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.interval = interval
+
+	for _, item := range q.items {
+		if item.failures == 0 && item.due.After(now.Add(interval)) {
+			item.due = now.Add(q.randomDuration(interval))
+		}
+	}
+
+	heap.Init(&q.items)
+	q.wake()
+}
+
+// pop removes the environment due first from the queue and returns it, when no environment is due
+// it returns the time to wait for the next one instead
+func (q *snapshotQueue) pop(now time.Time) (portainer.EndpointID, time.Duration, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.items) == 0 {
+		return 0, q.interval, false
+	}
+
+	if wait := q.items[0].due.Sub(now); wait > 0 {
+		return 0, wait, false
+	}
+
+	item := heap.Pop(&q.items).(*queueItem)
+
+	return item.endpointID, 0, true
+}
+
+// done schedules the next snapshot of an environment once its snapshot completed
+func (q *snapshotQueue) done(endpointID portainer.EndpointID, failed bool, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	item, ok := q.byID[endpointID]
+	if !ok || item.index >= 0 {
+		return
+	}
+
+	if failed {
+		item.failures++
+	} else {
+		item.failures = 0
+	}
+
+	item.due = now.Add(q.delay(item.failures))
+	heap.Push(&q.items, item)
+	q.wake()
+}
+
+// depth returns the number of environments whose snapshot is due and waiting for a worker
+func (q *snapshotQueue) depth(now time.Time) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	depth := 0
+	for _, item := range q.items {
+		if !item.due.After(now) {
+			depth++
+		}
+	}
+
+	return depth
+}
+
+// delay returns the delay before the next snapshot of an environment, it doubles with each consecutive failure
+func (q *snapshotQueue) delay(failures int) time.Duration {
+	delay := q.interval
+
+	limit := max(maxBackoff, q.interval)
+	for i := 1; i < failures && delay < limit; i++ {
+		delay *= 2
+	}
+
+	delay = min(delay, limit)
+
+	return delay + q.randomDuration(time.Duration(float64(q.interval)*jitterRatio))
+}
+
+func (q *snapshotQueue) randomDuration(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+
+	return time.Duration(q.rand.Int63n(int64(limit)))
+}
+
+func (q *snapshotQueue) wake() {
+	select {
+	case q.wakeCh <- struct{}{}:
+	default:
+	}
+}
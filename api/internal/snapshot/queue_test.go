@@ -0,0 +1,94 @@
+package snapshot
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotQueueSpreadsTheEnvironments(t *testing.T) {
+	now := time.Now()
+	queue := newSnapshotQueue(time.Minute, rand.New(rand.NewSource(1)))
+
+	endpointIDs := make([]portainer.EndpointID, 0, 1000)
+	for i := 1; i <= 1000; i++ {
+		endpointIDs = append(endpointIDs, portainer.EndpointID(i))
+	}
+
+	queue.sync(endpointIDs, now)
+
+	// The first snapshots are spread over the interval instead of being due at once
+	assert.Less(t, queue.depth(now.Add(10*time.Second)), 300)
+	assert.Equal(t, 1000, queue.depth(now.Add(time.Minute)))
+
+	// The environments are taken out of the queue in the order of their due time
+	var previous time.Time
+	running := 0
+	for range 10 {
+		item := queue.items[0]
+		endpointID, _, ok := queue.pop(now.Add(time.Minute))
+		require.True(t, ok)
+		assert.Equal(t, item.endpointID, endpointID)
+		assert.False(t, item.due.Before(previous))
+		previous = item.due
+
+		if endpointID > 500 {
+			running++
+		}
+	}
+
+	// The deleted environments are dropped, including the ones being snapshotted
+	queue.sync(endpointIDs[500:], now)
+	assert.Len(t, queue.byID, 500)
+	assert.Equal(t, 500-running, queue.depth(now.Add(time.Minute)))
+}
+
+func TestSnapshotQueueBackoff(t *testing.T) {
+	now := time.Now()
+	queue := newSnapshotQueue(time.Minute, rand.New(rand.NewSource(1)))
+	queue.sync([]portainer.EndpointID{1}, now.Add(-time.Minute))
+
+	next := func(failed bool) time.Duration {
+		endpointID, _, ok := queue.pop(now)
+		require.True(t, ok)
+
+		queue.done(endpointID, failed, now)
+		_, wait, ok := queue.pop(now)
+		require.False(t, ok)
+
+		now = now.Add(wait)
+
+		return wait
+	}
+
+	jitter := time.Duration(float64(time.Minute) * jitterRatio)
+
+	for _, expected := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		wait := next(true)
+		assert.GreaterOrEqual(t, wait, expected)
+		assert.Less(t, wait, expected+jitter)
+	}
+
+	for range 10 {
+		next(true)
+	}
+	assert.Less(t, next(true), maxBackoff+jitter)
+
+	// A successful snapshot brings the environment back to the snapshot interval
+	assert.Less(t, next(false), time.Minute+jitter)
+}
+
+func TestSnapshotQueueSetInterval(t *testing.T) {
+	now := time.Now()
+	queue := newSnapshotQueue(time.Hour, rand.New(rand.NewSource(1)))
+	queue.sync([]portainer.EndpointID{1, 2, 3}, now)
+
+	queue.setInterval(time.Minute, now)
+
+	assert.Equal(t, 3, queue.depth(now.Add(time.Minute)))
+}
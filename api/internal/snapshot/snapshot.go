@@ -1,21 +1,33 @@
 package snapshot
 
 import (
+	"cmp"
 	"context"
 	"crypto/tls"
 	"errors"
+	"fmt"
+	"math/rand"
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/agent"
 	"github.com/portainer/portainer/api/crypto"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/clockskew"
 	"github.com/portainer/portainer/api/internal/endpointutils"
+	"github.com/portainer/portainer/api/internal/events"
+	"github.com/portainer/portainer/api/internal/metrics"
+	"github.com/portainer/portainer/api/internal/settingsbus"
 	"github.com/portainer/portainer/api/pendingactions"
 
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	// DiskUsageRetention is how long disk usage samples are kept
+	DiskUsageRetention = 30 * 24 * time.Hour
+)
+
 // Service repesents a service to manage environment(endpoint) snapshots.
 // It provides an interface to start background snapshots as well as
 // specific Docker/Kubernetes environment(endpoint) snapshot methods.
@@ -25,8 +37,15 @@ type Service struct {
 	snapshotIntervalInSeconds float64
 	dockerSnapshotter         portainer.DockerSnapshotter
 	kubernetesSnapshotter     portainer.KubernetesSnapshotter
+	azureSnapshotter          portainer.AzureSnapshotter
 	shutdownCtx               context.Context
 	pendingActionsService     *pendingactions.PendingActionsService
+	notifier                  Notifier
+}
+
+// Notifier is told about the snapshots of the environments(endpoints), it must not block
+type Notifier interface {
+	SnapshotCreated(endpoint *portainer.Endpoint, snapshot *portainer.Snapshot)
 }
 
 // NewService creates a new instance of a service
@@ -45,7 +64,7 @@ func NewService(
 
 	return &Service{
 		dataStore:                 dataStore,
-		snapshotIntervalCh:        make(chan time.Duration),
+		snapshotIntervalCh:        make(chan time.Duration, 1),
 		snapshotIntervalInSeconds: interval,
 		dockerSnapshotter:         dockerSnapshotter,
 		kubernetesSnapshotter:     kubernetesSnapshotter,
@@ -106,28 +125,60 @@ func parseSnapshotFrequency(snapshotInterval string, dataStore dataservices.Data
 	return snapshotFrequency.Seconds(), nil
 }
 
+// SetNotifier sets the notifier told about the snapshots, it must be called before the service is started
+func (service *Service) SetNotifier(notifier Notifier) {
+	service.notifier = notifier
+}
+
+// SetAzureSnapshotter sets the snapshotter of the Azure environments, it must be called before the service is started.
+// The Azure environments are not snapshotted without it
+func (service *Service) SetAzureSnapshotter(azureSnapshotter portainer.AzureSnapshotter) {
+	service.azureSnapshotter = azureSnapshotter
+}
+
 // Start will start a background routine to execute periodic snapshots of environments(endpoints)
 func (service *Service) Start() {
 	go service.startSnapshotLoop()
 }
 
-// SetSnapshotInterval sets the snapshot interval and resets the service
+// SetSnapshotInterval sets the snapshot interval and resets the service.
+// It does not wait for the running snapshots to complete, only the latest interval is applied.
 func (service *Service) SetSnapshotInterval(snapshotInterval string) error {
 	interval, err := time.ParseDuration(snapshotInterval)
 	if err != nil {
 		return err
 	}
 
-	service.snapshotIntervalCh <- interval
+	for {
+		select {
+		case service.snapshotIntervalCh <- interval:
+			return nil
+		default:
+			// Drop the interval that was not applied yet
+			select {
+			case <-service.snapshotIntervalCh:
+			default:
+			}
+		}
+	}
+}
 
-	return nil
+// SettingsChanged applies the new snapshot interval of the settings
+func (service *Service) SettingsChanged(change settingsbus.Change) {
+	if change.Current.SnapshotInterval == change.Previous.SnapshotInterval || change.Current.SnapshotInterval == "" {
+		return
+	}
+
+	if err := service.SetSnapshotInterval(change.Current.SnapshotInterval); err != nil {
+		log.Warn().Err(err).Msg("unable to apply the new snapshot interval")
+	}
 }
 
 // SupportDirectSnapshot checks whether an environment(endpoint) can be used to trigger a direct a snapshot.
-// It is mostly true for all environments(endpoints) except Edge and Azure environments(endpoints).
+// It is true for all environments(endpoints) except Edge environments(endpoints).
 func SupportDirectSnapshot(endpoint *portainer.Endpoint) bool {
 	switch endpoint.Type {
-	case portainer.EdgeAgentOnDockerEnvironment, portainer.EdgeAgentOnKubernetesEnvironment, portainer.AzureEnvironment:
+	case portainer.EdgeAgentOnDockerEnvironment, portainer.EdgeAgentOnKubernetesEnvironment:
 		return false
 	}
 
@@ -137,6 +188,21 @@ func SupportDirectSnapshot(endpoint *portainer.Endpoint) bool {
 // SnapshotEndpoint will create a snapshot of the environment(endpoint) based on the environment(endpoint) type.
 // If the snapshot is a success, it will be associated to the environment(endpoint).
 func (service *Service) SnapshotEndpoint(endpoint *portainer.Endpoint) error {
+	start := time.Now()
+	err := service.snapshotEndpoint(endpoint)
+
+	platform := "docker"
+	if endpointutils.IsKubernetesEndpoint(endpoint) {
+		platform = "kubernetes"
+	} else if endpoint.Type == portainer.AzureEnvironment {
+		platform = "azure"
+	}
+	metrics.ObserveSnapshot(platform, time.Since(start).Seconds(), err != nil)
+
+	return err
+}
+
+func (service *Service) snapshotEndpoint(endpoint *portainer.Endpoint) error {
 	if endpoint.Type == portainer.AgentOnDockerEnvironment || endpoint.Type == portainer.AgentOnKubernetesEnvironment {
 		var err error
 		var tlsConfig *tls.Config
@@ -148,17 +214,21 @@ func (service *Service) SnapshotEndpoint(endpoint *portainer.Endpoint) error {
 			}
 		}
 
-		_, version, err := agent.GetAgentVersionAndPlatform(endpoint.URL, tlsConfig)
+		ping, err := agent.Ping(endpoint.URL, tlsConfig)
 		if err != nil {
 			return err
 		}
 
-		endpoint.Agent.Version = version
+		endpoint.Agent.Version = ping.Version
+
+		if ping.ClockSkewMeasured {
+			clockskew.Update(endpoint, ping.ClockSkew)
+		}
 	}
 
 	switch endpoint.Type {
 	case portainer.AzureEnvironment:
-		return nil
+		return service.snapshotAzureEndpoint(endpoint)
 	case portainer.KubernetesLocalEnvironment, portainer.AgentOnKubernetesEnvironment, portainer.EdgeAgentOnKubernetesEnvironment:
 		return service.snapshotKubernetesEndpoint(endpoint)
 	}
@@ -175,7 +245,21 @@ func (service *Service) FillSnapshotData(endpoint *portainer.Endpoint) error {
 }
 
 func FillSnapshotData(tx dataservices.DataStoreTx, endpoint *portainer.Endpoint) error {
-	snapshot, err := tx.Snapshot().Read(endpoint.ID)
+	return fillSnapshot(tx, endpoint, tx.Snapshot().Read)
+}
+
+// FillSnapshotSummary fills the snapshots of an environment without the raw Docker data, it avoids reading
+// the containers, images, volumes and networks of the environment when only the counters are needed
+func (service *Service) FillSnapshotSummary(endpoint *portainer.Endpoint) error {
+	return FillSnapshotSummary(service.dataStore, endpoint)
+}
+
+func FillSnapshotSummary(tx dataservices.DataStoreTx, endpoint *portainer.Endpoint) error {
+	return fillSnapshot(tx, endpoint, tx.Snapshot().ReadSummary)
+}
+
+func fillSnapshot(tx dataservices.DataStoreTx, endpoint *portainer.Endpoint, read func(portainer.EndpointID) (*portainer.Snapshot, error)) error {
+	snapshot, err := read(endpoint.ID)
 	if tx.IsErrObjectNotFound(err) {
 		endpoint.Snapshots = []portainer.DockerSnapshot{}
 		endpoint.Kubernetes.Snapshots = []portainer.KubernetesSnapshot{}
@@ -195,6 +279,31 @@ func FillSnapshotData(tx dataservices.DataStoreTx, endpoint *portainer.Endpoint)
 		endpoint.Kubernetes.Snapshots = []portainer.KubernetesSnapshot{*snapshot.Kubernetes}
 	}
 
+	if snapshot.Azure != nil {
+		endpoint.AzureSnapshots = []portainer.AzureSnapshot{*snapshot.Azure}
+	}
+
+	return nil
+}
+
+func (service *Service) snapshotAzureEndpoint(endpoint *portainer.Endpoint) error {
+	if service.azureSnapshotter == nil {
+		return nil
+	}
+
+	azureSnapshot, err := service.azureSnapshotter.CreateSnapshot(endpoint)
+	if err != nil {
+		return err
+	}
+
+	snapshot := &portainer.Snapshot{EndpointID: endpoint.ID, Azure: azureSnapshot}
+
+	if err := service.dataStore.Snapshot().Create(snapshot); err != nil {
+		return err
+	}
+
+	service.notify(endpoint, snapshot)
+
 	return nil
 }
 
@@ -207,7 +316,16 @@ func (service *Service) snapshotKubernetesEndpoint(endpoint *portainer.Endpoint)
 	if kubernetesSnapshot != nil {
 		snapshot := &portainer.Snapshot{EndpointID: endpoint.ID, Kubernetes: kubernetesSnapshot}
 
-		return service.dataStore.Snapshot().Create(snapshot)
+		if err := service.dataStore.Snapshot().Create(snapshot); err != nil {
+			return err
+		}
+
+		service.notify(endpoint, snapshot)
+
+		record := &portainer.SnapshotRecord{EndpointID: endpoint.ID, Time: kubernetesSnapshot.Time, Kubernetes: kubernetesSnapshot}
+		if err := service.recordSnapshot(record); err != nil {
+			log.Warn().Err(err).Int("endpoint_id", int(endpoint.ID)).Msg("unable to record the snapshot history")
+		}
 	}
 
 	return nil
@@ -224,14 +342,149 @@ func (service *Service) snapshotDockerEndpoint(endpoint *portainer.Endpoint) err
 	}
 
 	if dockerSnapshot != nil {
+		if dockerSnapshot.SnapshotRaw.Info.SystemTime != "" {
+			clockskew.Update(endpoint, time.Duration(dockerSnapshot.ClockSkew)*time.Second)
+		}
+
 		snapshot := &portainer.Snapshot{EndpointID: endpoint.ID, Docker: dockerSnapshot}
 
-		return service.dataStore.Snapshot().Create(snapshot)
+		if err := service.dataStore.Snapshot().Create(snapshot); err != nil {
+			return err
+		}
+
+		service.notify(endpoint, snapshot)
+
+		if dockerSnapshot.Gpus != nil {
+			service.updateDetectedGPUs(endpoint, DetectedGPUs(dockerSnapshot.Gpus))
+		}
+
+		if err := service.recordDiskUsage(endpoint.ID, dockerSnapshot); err != nil {
+			log.Warn().Err(err).Int("endpoint_id", int(endpoint.ID)).Msg("unable to record the disk usage sample")
+		}
+
+		record := &portainer.SnapshotRecord{EndpointID: endpoint.ID, Time: dockerSnapshot.Time, Docker: trimDockerSnapshot(dockerSnapshot)}
+		if err := service.recordSnapshot(record); err != nil {
+			log.Warn().Err(err).Int("endpoint_id", int(endpoint.ID)).Msg("unable to record the snapshot history")
+		}
 	}
 
 	return nil
 }
 
+func (service *Service) notify(endpoint *portainer.Endpoint, snapshot *portainer.Snapshot) {
+	if service.notifier != nil {
+		service.notifier.SnapshotCreated(endpoint, snapshot)
+	}
+}
+
+// recordDiskUsage keeps a bounded history of the disk usage reported by the snapshots,
+// used to project the disk pressure of the environment
+func (service *Service) recordDiskUsage(endpointID portainer.EndpointID, dockerSnapshot *portainer.DockerSnapshot) error {
+	if dockerSnapshot.DiskUsage == nil {
+		return nil
+	}
+
+	return service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		samples, err := tx.DiskUsageSample().ReadAllByEndpointID(endpointID)
+		if err != nil {
+			return err
+		}
+
+		// The disk usage reported between two computations is only recorded once
+		sampleTime := cmp.Or(dockerSnapshot.DiskUsage.ComputedAt, dockerSnapshot.Time)
+		now := time.Unix(sampleTime, 0)
+		cutoff := now.Add(-DiskUsageRetention).Unix()
+
+		var latest int64
+		for _, sample := range samples {
+			if sample.Time < cutoff {
+				if err := tx.DiskUsageSample().Delete(sample.ID); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			latest = max(latest, sample.Time)
+		}
+
+		if latest > 0 && now.Sub(time.Unix(latest, 0)) < portainer.DiskUsageSampleInterval {
+			return nil
+		}
+
+		return tx.DiskUsageSample().Create(&portainer.DiskUsageSample{
+			EndpointID:      endpointID,
+			Time:            sampleTime,
+			DockerDiskUsage: *dockerSnapshot.DiskUsage,
+		})
+	})
+}
+
+// recordSnapshot keeps the last snapshots of an environment, used to compare its state between snapshot intervals
+func (service *Service) recordSnapshot(record *portainer.SnapshotRecord) error {
+	return service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		settings, err := tx.Settings().Settings()
+		if err != nil {
+			return err
+		}
+
+		records, err := tx.SnapshotRecord().ReadAllByEndpointID(record.EndpointID)
+		if err != nil {
+			return err
+		}
+
+		for _, expired := range expiredRecords(records, record.Time, settings.SnapshotRetention) {
+			if err := tx.SnapshotRecord().Delete(expired.EndpointID, expired.Time); err != nil {
+				return err
+			}
+		}
+
+		return tx.SnapshotRecord().Create(record)
+	})
+}
+
+// expiredRecords returns the records, sorted by time, to remove before recording a new snapshot taken at now
+func expiredRecords(records []portainer.SnapshotRecord, now int64, retention portainer.SnapshotRetentionSettings) []portainer.SnapshotRecord {
+	maxRecords := cmp.Or(retention.MaxRecords, portainer.DefaultSnapshotRecordRetention)
+
+	expired := 0
+	if len(records) >= maxRecords {
+		expired = len(records) - maxRecords + 1
+	}
+
+	if retention.MaxAge != "" {
+		maxAge, err := time.ParseDuration(retention.MaxAge)
+		if err != nil {
+			log.Warn().Err(err).Str("max_age", retention.MaxAge).Msg("invalid snapshot retention age, the snapshots are only limited by their number")
+		} else {
+			cutoff := time.Unix(now, 0).Add(-maxAge).Unix()
+
+			for expired < len(records) && records[expired].Time < cutoff {
+				expired++
+			}
+		}
+	}
+
+	return records[:expired]
+}
+
+// trimDockerSnapshot returns a copy of the snapshot holding only the raw data needed to compare snapshots.
+// The environment variables of the containers are dropped as they can contain secrets.
+func trimDockerSnapshot(dockerSnapshot *portainer.DockerSnapshot) *portainer.DockerSnapshot {
+	trimmed := *dockerSnapshot
+	trimmed.SnapshotRaw = portainer.DockerSnapshotRaw{
+		Volumes:  dockerSnapshot.SnapshotRaw.Volumes,
+		Images:   dockerSnapshot.SnapshotRaw.Images,
+		Services: dockerSnapshot.SnapshotRaw.Services,
+	}
+
+	for _, container := range dockerSnapshot.SnapshotRaw.Containers {
+		trimmed.SnapshotRaw.Containers = append(trimmed.SnapshotRaw.Containers, portainer.DockerContainerSnapshot{Container: container.Container})
+	}
+
+	return &trimmed
+}
+
 func validateContainerEngineCompatibility(endpoint *portainer.Endpoint, dockerSnapshot *portainer.DockerSnapshot) error {
 	if endpoint.ContainerEngine == portainer.ContainerEngineDocker && dockerSnapshot.IsPodman {
 		err := errors.New("the Docker environment option doesn't support Podman environments. Please select the Podman option instead.")
@@ -246,19 +499,31 @@ func validateContainerEngineCompatibility(endpoint *portainer.Endpoint, dockerSn
 	return nil
 }
 
+// startSnapshotLoop spreads the snapshots of the environments(endpoints) over the snapshot interval and runs
+// them on a bounded pool of workers. The queue is synchronized with the environments once per interval
 func (service *Service) startSnapshotLoop() {
-	ticker := time.NewTicker(time.Duration(service.snapshotIntervalInSeconds) * time.Second)
+	interval := time.Duration(service.snapshotIntervalInSeconds) * time.Second
+	queue := newSnapshotQueue(interval, rand.New(rand.NewSource(time.Now().UnixNano())))
+
+	// The work channel is unbuffered so that the dispatcher only takes the next due environment
+	// out of the queue once a worker is available
+	work := make(chan portainer.EndpointID)
+	for range snapshotWorkers {
+		go service.snapshotWorker(queue, work)
+	}
 
-	err := service.snapshotEndpoints()
-	if err != nil {
+	go service.dispatchSnapshots(queue, work)
+
+	ticker := time.NewTicker(interval)
+
+	if err := service.syncSnapshotQueue(queue); err != nil {
 		log.Error().Err(err).Msg("background schedule error (environment snapshot)")
 	}
 
 	for {
 		select {
 		case <-ticker.C:
-			err := service.snapshotEndpoints()
-			if err != nil {
+			if err := service.syncSnapshotQueue(queue); err != nil {
 				log.Error().Err(err).Msg("background schedule error (environment snapshot)")
 			}
 		case <-service.shutdownCtx.Done():
@@ -268,31 +533,100 @@ func (service *Service) startSnapshotLoop() {
 			return
 		case interval := <-service.snapshotIntervalCh:
 			ticker.Reset(interval)
+			queue.setInterval(interval, time.Now())
 		}
 	}
 }
 
-func (service *Service) snapshotEndpoints() error {
+// syncSnapshotQueue adds the environments supporting direct snapshots to the queue and drops the others
+func (service *Service) syncSnapshotQueue(queue *snapshotQueue) error {
 	endpoints, err := service.dataStore.Endpoint().Endpoints()
 	if err != nil {
 		return err
 	}
 
+	endpointIDs := make([]portainer.EndpointID, 0, len(endpoints))
 	for _, endpoint := range endpoints {
-		if !SupportDirectSnapshot(&endpoint) || endpoint.URL == "" {
-			continue
+		if SupportDirectSnapshot(&endpoint) && endpoint.URL != "" {
+			endpointIDs = append(endpointIDs, endpoint.ID)
 		}
+	}
 
-		snapshotError := service.SnapshotEndpoint(&endpoint)
+	queue.sync(endpointIDs, time.Now())
 
-		service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
-			updateEndpointStatus(tx, &endpoint, snapshotError, service.pendingActionsService)
+	return nil
+}
 
-			return nil
-		})
+// dispatchSnapshots hands the due environments to the workers, waiting for a worker to be available
+func (service *Service) dispatchSnapshots(queue *snapshotQueue, work chan<- portainer.EndpointID) {
+	defer close(work)
+
+	var wait time.Duration
+	for {
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-service.shutdownCtx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		case <-queue.wakeCh:
+			timer.Stop()
+		}
+
+		for {
+			now := time.Now()
+			metrics.SetSnapshotQueueDepth(queue.depth(now))
+
+			endpointID, next, ok := queue.pop(now)
+			if !ok {
+				wait = next
+
+				break
+			}
+
+			select {
+			case work <- endpointID:
+			case <-service.shutdownCtx.Done():
+				return
+			}
+		}
 	}
+}
 
-	return nil
+func (service *Service) snapshotWorker(queue *snapshotQueue, work <-chan portainer.EndpointID) {
+	for endpointID := range work {
+		failed := service.snapshotQueuedEndpoint(endpointID)
+		queue.done(endpointID, failed, time.Now())
+	}
+}
+
+// snapshotQueuedEndpoint snapshots an environment taken out of the queue and returns true when the snapshot failed
+func (service *Service) snapshotQueuedEndpoint(endpointID portainer.EndpointID) bool {
+	endpoint, err := service.dataStore.Endpoint().Endpoint(endpointID)
+	if err != nil {
+		log.Debug().Err(err).Int("endpoint_id", int(endpointID)).Msg("background schedule error (environment snapshot), unable to retrieve the environment")
+
+		return true
+	}
+
+	if !SupportDirectSnapshot(endpoint) || endpoint.URL == "" {
+		return false
+	}
+
+	done := metrics.SnapshotStarted()
+	defer done()
+
+	snapshotError := service.SnapshotEndpoint(endpoint)
+
+	service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		updateEndpointStatus(tx, endpoint, snapshotError, service.pendingActionsService)
+
+		return nil
+	})
+
+	return snapshotError != nil
 }
 
 func updateEndpointStatus(tx dataservices.DataStoreTx, endpoint *portainer.Endpoint, snapshotError error, pendingActionsService *pendingactions.PendingActionsService) {
@@ -306,6 +640,7 @@ func updateEndpointStatus(tx dataservices.DataStoreTx, endpoint *portainer.Endpo
 		return
 	}
 
+	previousStatus := latestEndpointReference.Status
 	latestEndpointReference.Status = portainer.EndpointStatusUp
 
 	if snapshotError != nil {
@@ -318,6 +653,8 @@ func updateEndpointStatus(tx dataservices.DataStoreTx, endpoint *portainer.Endpo
 	}
 
 	latestEndpointReference.Agent.Version = endpoint.Agent.Version
+	latestEndpointReference.ClockSkew = endpoint.ClockSkew
+	latestEndpointReference.ClockSkewDetected = endpoint.ClockSkewDetected
 
 	if err := tx.Endpoint().UpdateEndpoint(latestEndpointReference.ID, latestEndpointReference); err != nil {
 		log.Debug().
@@ -326,6 +663,16 @@ func updateEndpointStatus(tx dataservices.DataStoreTx, endpoint *portainer.Endpo
 			Msg("background schedule error (environment snapshot), unable to update environment")
 	}
 
+	if previousStatus == portainer.EndpointStatusUp && latestEndpointReference.Status == portainer.EndpointStatusDown {
+		events.Publish(events.Event{
+			Type:         portainer.NotificationEventEndpointDown,
+			EndpointID:   latestEndpointReference.ID,
+			EndpointName: latestEndpointReference.Name,
+			Title:        "Environment down",
+			Message:      fmt.Sprintf("The environment %s cannot be reached: %s", latestEndpointReference.Name, snapshotError),
+		})
+	}
+
 	// Run the pending actions
 	if latestEndpointReference.Status == portainer.EndpointStatusUp {
 		pendingActionsService.Execute(endpoint.ID)
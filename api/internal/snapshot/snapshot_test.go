@@ -0,0 +1,34 @@
+package snapshot
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiredRecords(t *testing.T) {
+	records := []portainer.SnapshotRecord{{Time: 3600}, {Time: 7200}, {Time: 10800}}
+
+	times := func(records []portainer.SnapshotRecord) []int64 {
+		result := []int64{}
+		for _, record := range records {
+			result = append(result, record.Time)
+		}
+
+		return result
+	}
+
+	// The default retention keeps the records
+	assert.Empty(t, expiredRecords(records, 14400, portainer.SnapshotRetentionSettings{}))
+
+	// The oldest records are removed to make room for the new one
+	assert.Equal(t, []int64{3600, 7200}, times(expiredRecords(records, 14400, portainer.SnapshotRetentionSettings{MaxRecords: 2})))
+
+	// The records older than the maximum age are removed
+	assert.Equal(t, []int64{3600}, times(expiredRecords(records, 14400, portainer.SnapshotRetentionSettings{MaxAge: "2h"})))
+
+	// An invalid age falls back to the number of records
+	assert.Equal(t, []int64{3600}, times(expiredRecords(records, 14400, portainer.SnapshotRetentionSettings{MaxRecords: 3, MaxAge: "invalid"})))
+}
@@ -0,0 +1,155 @@
+package snapshotwebhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/api/internal/deploymentvalidation"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultTimeout is the time waited for a response when the webhook does not define one
+const DefaultTimeout = 10 * time.Second
+
+// Service sends the summaries of the snapshots to the snapshot webhooks
+type Service struct {
+	dataStore  dataservices.DataStore
+	httpClient *http.Client
+
+	mu sync.Mutex
+	// Digest of the last summary delivered to each webhook, for each environment(endpoint)
+	digests map[deliveryKey]string
+}
+
+type deliveryKey struct {
+	webhookID  portainer.SnapshotWebhookID
+	endpointID portainer.EndpointID
+}
+
+// NewService returns a new instance of Service, the requests go through the proxy of the outbound calls
+func NewService(dataStore dataservices.DataStore) *Service {
+	return &Service{
+		dataStore:  dataStore,
+		httpClient: &http.Client{Transport: client.NewTransport()},
+		digests:    make(map[deliveryKey]string),
+	}
+}
+
+// SnapshotCreated sends the summary of a new snapshot to the webhooks in the background
+func (service *Service) SnapshotCreated(endpoint *portainer.Endpoint, snapshot *portainer.Snapshot) {
+	summary := NewSummary(endpoint, snapshot)
+
+	go func() {
+		if err := service.Send(summary); err != nil {
+			log.Warn().Err(err).Int("endpoint_id", int(summary.Endpoint.ID)).Msg("unable to send the snapshot summary")
+		}
+	}()
+}
+
+// Send delivers a summary to the enabled webhooks of the group of its environment(endpoint). The webhooks in
+// change mode only receive it when the inventory changed since the last summary they received
+func (service *Service) Send(summary Summary) error {
+	webhooks, err := service.dataStore.SnapshotWebhook().ReadAll()
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the snapshot webhooks: %w", err)
+	}
+
+	webhooks = slices.DeleteFunc(webhooks, func(webhook portainer.SnapshotWebhook) bool {
+		return !Applies(&webhook, summary.Endpoint.GroupID)
+	})
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	digest, err := summary.Digest()
+	if err != nil {
+		return err
+	}
+
+	for i := range webhooks {
+		webhook := &webhooks[i]
+		key := deliveryKey{webhookID: webhook.ID, endpointID: summary.Endpoint.ID}
+
+		if webhook.Mode == portainer.SnapshotWebhookOnChange && service.lastDigest(key) == digest {
+			continue
+		}
+
+		body, err := MapFields(summary, webhook.FieldMapping)
+		if err != nil {
+			return err
+		}
+
+		if err := service.send(webhook, body); err != nil {
+			log.Warn().
+				Err(err).
+				Str("webhook", webhook.Name).
+				Int("endpoint_id", int(summary.Endpoint.ID)).
+				Msg("unable to deliver the snapshot summary to the webhook")
+
+			continue
+		}
+
+		service.mu.Lock()
+		service.digests[key] = digest
+		service.mu.Unlock()
+	}
+
+	return nil
+}
+
+func (service *Service) lastDigest(key deliveryKey) string {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	return service.digests[key]
+}
+
+// Applies returns true when the webhook receives the summaries of the environments(endpoints) of the group
+func Applies(webhook *portainer.SnapshotWebhook, groupID portainer.EndpointGroupID) bool {
+	if !webhook.Enabled {
+		return false
+	}
+
+	return len(webhook.EndpointGroupIDs) == 0 || slices.Contains(webhook.EndpointGroupIDs, groupID)
+}
+
+func (service *Service) send(webhook *portainer.SnapshotWebhook, body []byte) error {
+	timeout := DefaultTimeout
+	if webhook.Timeout > 0 {
+		timeout = time.Duration(webhook.Timeout) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.Secret != "" {
+		req.Header.Set(deploymentvalidation.SignatureHeader, deploymentvalidation.Sign(webhook.Secret, body))
+	}
+
+	resp, err := service.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("the webhook responded with %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package snapshotwebhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	"github.com/portainer/portainer/api/internal/deploymentvalidation"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/system"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDockerSnapshot(time int64, images ...string) *portainer.Snapshot {
+	snapshot := &portainer.Snapshot{
+		EndpointID: 1,
+		Docker: &portainer.DockerSnapshot{
+			Time:          time,
+			DockerVersion: "27.3.1",
+			TotalCPU:      4,
+			TotalMemory:   8 << 30,
+			SnapshotRaw: portainer.DockerSnapshotRaw{
+				Info: system.Info{Name: "docker-01", OperatingSystem: "Ubuntu 24.04", Architecture: "x86_64"},
+			},
+		},
+	}
+
+	for _, name := range images {
+		snapshot.Docker.SnapshotRaw.Containers = append(snapshot.Docker.SnapshotRaw.Containers, portainer.DockerContainerSnapshot{
+			Container: types.Container{
+				ID:      name + "-container",
+				Names:   []string{"/" + name},
+				Image:   name + ":latest",
+				ImageID: "sha256:" + name,
+				State:   "running",
+				Labels:  map[string]string{composeProjectLabel: "web"},
+			},
+		})
+
+		snapshot.Docker.SnapshotRaw.Images = append(snapshot.Docker.SnapshotRaw.Images, image.Summary{
+			ID:          "sha256:" + name,
+			RepoTags:    []string{name + ":latest"},
+			RepoDigests: []string{name + "@sha256:" + name},
+		})
+	}
+
+	return snapshot
+}
+
+func TestNewSummary(t *testing.T) {
+	endpoint := &portainer.Endpoint{ID: 1, Name: "production", GroupID: 2}
+
+	summary := NewSummary(endpoint, newDockerSnapshot(1708000000, "redis", "nginx"))
+
+	assert.Equal(t, Endpoint{ID: 1, Name: "production", GroupID: 2, Type: "docker"}, summary.Endpoint)
+	assert.Equal(t, []Host{{
+		Name:            "docker-01",
+		OperatingSystem: "Ubuntu 24.04",
+		Architecture:    "x86_64",
+		CPUs:            4,
+		Memory:          8 << 30,
+		EngineVersion:   "27.3.1",
+		NodeCount:       1,
+	}}, summary.Hosts)
+
+	// The resources are sorted
+	require.Len(t, summary.Containers, 2)
+	assert.Equal(t, Container{ID: "nginx-container", Name: "nginx", Image: "nginx:latest", ImageID: "sha256:nginx", State: "running", Stack: "web"}, summary.Containers[0])
+	require.Len(t, summary.Images, 2)
+	assert.Equal(t, []string{"nginx@sha256:nginx"}, summary.Images[0].Digests)
+
+	// The time of the snapshot does not change the digest
+	digest, err := summary.Digest()
+	require.NoError(t, err)
+
+	later, err := NewSummary(endpoint, newDockerSnapshot(1708000300, "nginx", "redis")).Digest()
+	require.NoError(t, err)
+	assert.Equal(t, digest, later)
+
+	changed, err := NewSummary(endpoint, newDockerSnapshot(1708000300, "nginx")).Digest()
+	require.NoError(t, err)
+	assert.NotEqual(t, digest, changed)
+}
+
+func TestMapFields(t *testing.T) {
+	summary := NewSummary(&portainer.Endpoint{ID: 1, Name: "production"}, newDockerSnapshot(1708000000, "nginx"))
+
+	body, err := MapFields(summary, map[string]string{
+		"endpoint.name":    "environment",
+		"containers":       "ci_containers",
+		"containers.image": "ci_image",
+		"images.size":      "",
+	})
+	require.NoError(t, err)
+
+	var mapped map[string]any
+	require.NoError(t, json.Unmarshal(body, &mapped))
+
+	assert.Equal(t, "production", mapped["endpoint"].(map[string]any)["environment"])
+	assert.NotContains(t, mapped, "containers")
+
+	container := mapped["ci_containers"].([]any)[0].(map[string]any)
+	assert.Equal(t, "nginx:latest", container["ci_image"])
+	assert.NotContains(t, container, "image")
+
+	assert.NotContains(t, mapped["images"].([]any)[0].(map[string]any), "size")
+}
+
+func TestSend(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	var always, change atomic.Int32
+
+	newServer := func(calls *atomic.Int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			assert.Equal(t, deploymentvalidation.Sign("secret", body), r.Header.Get(deploymentvalidation.SignatureHeader))
+
+			calls.Add(1)
+		}))
+	}
+
+	alwaysServer := newServer(&always)
+	defer alwaysServer.Close()
+
+	changeServer := newServer(&change)
+	defer changeServer.Close()
+
+	require.NoError(t, store.SnapshotWebhook().Create(&portainer.SnapshotWebhook{Name: "always", URL: alwaysServer.URL, Secret: "secret", Mode: portainer.SnapshotWebhookAlways, Enabled: true}))
+	require.NoError(t, store.SnapshotWebhook().Create(&portainer.SnapshotWebhook{Name: "change", URL: changeServer.URL, Secret: "secret", Mode: portainer.SnapshotWebhookOnChange, Enabled: true}))
+	// The webhooks of the other groups and the disabled webhooks are skipped
+	require.NoError(t, store.SnapshotWebhook().Create(&portainer.SnapshotWebhook{Name: "other", URL: changeServer.URL, EndpointGroupIDs: []portainer.EndpointGroupID{3}, Enabled: true}))
+	require.NoError(t, store.SnapshotWebhook().Create(&portainer.SnapshotWebhook{Name: "disabled", URL: changeServer.URL}))
+
+	service := NewService(store)
+	endpoint := &portainer.Endpoint{ID: 1, Name: "production", GroupID: 1}
+
+	require.NoError(t, service.Send(NewSummary(endpoint, newDockerSnapshot(1708000000, "nginx"))))
+	require.NoError(t, service.Send(NewSummary(endpoint, newDockerSnapshot(1708000300, "nginx"))))
+	require.NoError(t, service.Send(NewSummary(endpoint, newDockerSnapshot(1708000600, "nginx", "redis"))))
+
+	assert.EqualValues(t, 3, always.Load())
+	assert.EqualValues(t, 2, change.Load())
+}
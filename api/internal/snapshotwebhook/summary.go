@@ -0,0 +1,250 @@
+package snapshotwebhook
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	composeProjectLabel = "com.docker.compose.project"
+	swarmStackLabel     = "com.docker.stack.namespace"
+	fieldPathSeparator  = "."
+)
+
+// Summary is the normalized inventory of an environment(endpoint) sent to the webhooks
+type Summary struct {
+	Endpoint Endpoint `json:"endpoint"`
+	// Unix timestamp of the snapshot
+	Time       int64       `json:"time"`
+	Hosts      []Host      `json:"hosts"`
+	Containers []Container `json:"containers"`
+	Images     []Image     `json:"images"`
+}
+
+// Endpoint describes the environment(endpoint) of a summary
+type Endpoint struct {
+	ID      portainer.EndpointID      `json:"id"`
+	Name    string                    `json:"name"`
+	GroupID portainer.EndpointGroupID `json:"groupId"`
+	// Platform of the environment, docker, kubernetes or azure
+	Type string `json:"type"`
+}
+
+// Host describes the host of a Docker environment or a Kubernetes cluster
+type Host struct {
+	Name            string `json:"name"`
+	OperatingSystem string `json:"os"`
+	Architecture    string `json:"architecture"`
+	CPUs            int64  `json:"cpus"`
+	Memory          int64  `json:"memory"`
+	// Version of the Docker engine or of Kubernetes
+	EngineVersion string `json:"engineVersion"`
+	NodeCount     int    `json:"nodeCount"`
+}
+
+// Container describes a container of a Docker environment
+type Container struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Image   string `json:"image"`
+	ImageID string `json:"imageId"`
+	State   string `json:"state"`
+	// Name of the compose project or swarm stack of the container
+	Stack string `json:"stack"`
+}
+
+// Image describes an image of a Docker environment
+type Image struct {
+	ID      string   `json:"id"`
+	Tags    []string `json:"tags"`
+	Digests []string `json:"digests"`
+	Size    int64    `json:"size"`
+}
+
+// Fields lists the paths of the fields of a summary that can be mapped, the fields of the lists are
+// addressed through the name of the list such as containers.image
+var Fields = []string{
+	"endpoint", "endpoint.id", "endpoint.name", "endpoint.groupId", "endpoint.type",
+	"time",
+	"hosts", "hosts.name", "hosts.os", "hosts.architecture", "hosts.cpus", "hosts.memory", "hosts.engineVersion", "hosts.nodeCount",
+	"containers", "containers.id", "containers.name", "containers.image", "containers.imageId", "containers.state", "containers.stack",
+	"images", "images.id", "images.tags", "images.digests", "images.size",
+}
+
+// NewSummary returns the summary of the snapshot of an environment(endpoint). The resources are sorted so that
+// the summaries of an unchanged environment are identical
+func NewSummary(endpoint *portainer.Endpoint, snapshot *portainer.Snapshot) Summary {
+	summary := Summary{
+		Endpoint: Endpoint{
+			ID:      endpoint.ID,
+			Name:    endpoint.Name,
+			GroupID: endpoint.GroupID,
+			Type:    "docker",
+		},
+		Hosts:      []Host{},
+		Containers: []Container{},
+		Images:     []Image{},
+	}
+
+	if snapshot.Kubernetes != nil {
+		summary.Endpoint.Type = "kubernetes"
+		summary.Time = snapshot.Kubernetes.Time
+		summary.Hosts = append(summary.Hosts, Host{
+			Name:          endpoint.Name,
+			CPUs:          snapshot.Kubernetes.TotalCPU,
+			Memory:        snapshot.Kubernetes.TotalMemory,
+			EngineVersion: snapshot.Kubernetes.KubernetesVersion,
+			NodeCount:     snapshot.Kubernetes.NodeCount,
+		})
+	}
+
+	// The container groups of an Azure environment are listed as its containers
+	if snapshot.Azure != nil {
+		summary.Endpoint.Type = "azure"
+		summary.Time = snapshot.Azure.Time
+
+		for _, group := range snapshot.Azure.ContainerGroups {
+			summary.Containers = append(summary.Containers, Container{ID: group.ID, Name: group.Name, State: group.State})
+		}
+
+		slices.SortFunc(summary.Containers, func(a, b Container) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+	}
+
+	if snapshot.Docker == nil {
+		return summary
+	}
+
+	docker := snapshot.Docker
+	info := docker.SnapshotRaw.Info
+
+	summary.Time = docker.Time
+	summary.Hosts = append(summary.Hosts, Host{
+		Name:            info.Name,
+		OperatingSystem: info.OperatingSystem,
+		Architecture:    info.Architecture,
+		CPUs:            int64(docker.TotalCPU),
+		Memory:          docker.TotalMemory,
+		EngineVersion:   docker.DockerVersion,
+		NodeCount:       max(docker.NodeCount, 1),
+	})
+
+	for _, container := range docker.SnapshotRaw.Containers {
+		var name string
+		if len(container.Names) > 0 {
+			name = strings.TrimPrefix(container.Names[0], "/")
+		}
+
+		summary.Containers = append(summary.Containers, Container{
+			ID:      container.ID,
+			Name:    name,
+			Image:   container.Image,
+			ImageID: container.ImageID,
+			State:   container.State,
+			Stack:   cmp.Or(container.Labels[composeProjectLabel], container.Labels[swarmStackLabel]),
+		})
+	}
+
+	slices.SortFunc(summary.Containers, func(a, b Container) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	for _, image := range docker.SnapshotRaw.Images {
+		summary.Images = append(summary.Images, Image{
+			ID:      image.ID,
+			Tags:    sortedOrEmpty(image.RepoTags),
+			Digests: sortedOrEmpty(image.RepoDigests),
+			Size:    image.Size,
+		})
+	}
+
+	slices.SortFunc(summary.Images, func(a, b Image) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return summary
+}
+
+func sortedOrEmpty(values []string) []string {
+	sorted := slices.Clone(values)
+	if sorted == nil {
+		return []string{}
+	}
+
+	slices.Sort(sorted)
+
+	return sorted
+}
+
+// Digest identifies the inventory of a summary, it ignores the time of the snapshot
+func (summary Summary) Digest() (string, error) {
+	summary.Time = 0
+
+	body, err := json.Marshal(summary)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.Sum256(body)
+
+	return hex.EncodeToString(h[:]), nil
+}
+
+// MapFields returns the body of a summary with its fields renamed by the mapping, the fields mapped to an
+// empty name are left out
+func MapFields(summary Summary, mapping map[string]string) ([]byte, error) {
+	body, err := json.Marshal(summary)
+	if err != nil || len(mapping) == 0 {
+		return body, err
+	}
+
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mapValue(value, "", mapping))
+}
+
+func mapValue(value any, path string, mapping map[string]string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		mapped := make(map[string]any, len(v))
+		for key, field := range v {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + fieldPathSeparator + key
+			}
+
+			name, ok := mapping[fieldPath]
+			if !ok {
+				name = key
+			}
+
+			if name == "" {
+				continue
+			}
+
+			mapped[name] = mapValue(field, fieldPath, mapping)
+		}
+
+		return mapped
+	case []any:
+		// The elements of a list share the path of the list
+		for i := range v {
+			v[i] = mapValue(v[i], path, mapping)
+		}
+
+		return v
+	}
+
+	return value
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"os"
+	"sync"
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
@@ -19,6 +20,7 @@ type Service struct {
 	fileService     portainer.FileService
 	dataStore       dataservices.DataStore
 	rawCert         *tls.Certificate
+	rawCertMu       sync.RWMutex
 	shutdownTrigger context.CancelFunc
 }
 
@@ -84,6 +86,9 @@ func generateSelfSignedCertificates(ip, certPath, keyPath string) error {
 
 // GetRawCertificate gets the raw certificate
 func (service *Service) GetRawCertificate() *tls.Certificate {
+	service.rawCertMu.RLock()
+	defer service.rawCertMu.RUnlock()
+
 	return service.rawCert
 }
 
@@ -118,6 +123,21 @@ func (service *Service) SetCertificates(certData, keyData []byte) error {
 	return nil
 }
 
+// UpdateCertificates replaces the certificates without restarting the HTTPS server, the new certificate is
+// used from the next TLS handshake
+func (service *Service) UpdateCertificates(certData, keyData []byte) error {
+	if _, err := tls.X509KeyPair(certData, keyData); err != nil {
+		return err
+	}
+
+	certPath, keyPath, err := service.fileService.StoreSSLCertPair(certData, keyData)
+	if err != nil {
+		return err
+	}
+
+	return service.cacheInfo(certPath, keyPath, false)
+}
+
 func (service *Service) SetHTTPEnabled(httpEnabled bool) error {
 	settings, err := service.dataStore.SSLSettings().Settings()
 	if err != nil {
@@ -146,7 +166,9 @@ func (service *Service) cacheCertificate(certPath, keyPath string) error {
 		return err
 	}
 
+	service.rawCertMu.Lock()
 	service.rawCert = &rawCert
+	service.rawCertMu.Unlock()
 
 	return nil
 }
@@ -0,0 +1,397 @@
+package templatesources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	gittypes "github.com/portainer/portainer/api/git/types"
+	"github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/api/internal/settingsbus"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	// RefreshInterval is how often the templates files of the sources are fetched again
+	RefreshInterval = 15 * time.Minute
+	// DefaultFilePath is the path of the templates file inside a git repository when none is specified
+	DefaultFilePath = "templates.json"
+	// fetchTimeout bounds the download of a templates file
+	fetchTimeout = 30 * time.Second
+	// maxFileSize bounds the size of a templates file
+	maxFileSize = 20 << 20
+)
+
+// ErrChecksumMismatch is returned when the content of a templates file does not match the checksum of its source
+var ErrChecksumMismatch = errors.New("the content of the templates file does not match the checksum of the source")
+
+// Templates represents the content of a templates file
+type Templates struct {
+	Version   string               `json:"version"`
+	Templates []portainer.Template `json:"templates"`
+}
+
+// Service fetches the templates files of the sources, caches them and merges them into a single list of templates.
+// While no source is defined, the templates are fetched from the templates URL of the settings
+type Service struct {
+	dataStore   dataservices.DataStore
+	gitService  portainer.GitService
+	fileService portainer.FileService
+
+	mu    sync.Mutex
+	cache map[portainer.TemplateSourceID]*Templates
+	// generations are incremented on each invalidation so that a fetch started
+	// before a source changed is not cached
+	generations map[portainer.TemplateSourceID]int
+}
+
+// NewService returns a pointer to a new instance of Service
+func NewService(dataStore dataservices.DataStore, gitService portainer.GitService, fileService portainer.FileService) *Service {
+	return &Service{
+		dataStore:   dataStore,
+		gitService:  gitService,
+		fileService: fileService,
+		cache:       make(map[portainer.TemplateSourceID]*Templates),
+		generations: make(map[portainer.TemplateSourceID]int),
+	}
+}
+
+// ValidateChecksum ensures that a checksum is empty or a SHA-256 checksum in hexadecimal
+func ValidateChecksum(checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+
+	if decoded, err := hex.DecodeString(checksum); err != nil || len(decoded) != sha256.Size {
+		return errors.New("invalid checksum, a SHA-256 checksum in hexadecimal is expected")
+	}
+
+	return nil
+}
+
+// Templates returns the merged templates of the sources visible to a user. The templates of a source that
+// cannot be fetched are skipped, an error is only returned when none of the visible sources could be fetched.
+// The returned templates are shared between the callers and must not be modified
+func (service *Service) Templates(isAdmin bool, teamIDs []portainer.TeamID) (*Templates, error) {
+	sources, err := service.sources()
+	if err != nil {
+		return nil, err
+	}
+
+	sources = slices.DeleteFunc(sources, func(source portainer.TemplateSource) bool {
+		return !IsVisible(&source, isAdmin, teamIDs)
+	})
+
+	var fetched []*Templates
+	var fetchErr error
+	for i := range sources {
+		templates, err := service.cachedTemplates(&sources[i])
+		if err != nil {
+			log.Warn().Err(err).Str("source", sources[i].Name).Msg("unable to fetch the templates of the source")
+
+			fetchErr = err
+
+			continue
+		}
+
+		fetched = append(fetched, templates)
+	}
+
+	if len(fetched) == 0 && fetchErr != nil {
+		return nil, fetchErr
+	}
+
+	return Merge(fetched), nil
+}
+
+// IsVisible returns true when the templates of the source are visible to a user
+func IsVisible(source *portainer.TemplateSource, isAdmin bool, teamIDs []portainer.TeamID) bool {
+	if isAdmin || len(source.TeamIDs) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(source.TeamIDs, func(teamID portainer.TeamID) bool {
+		return slices.Contains(teamIDs, teamID)
+	})
+}
+
+// SortByPriority sorts the sources in the order their templates are merged, the highest priority first
+func SortByPriority(sources []portainer.TemplateSource) {
+	slices.SortStableFunc(sources, func(a, b portainer.TemplateSource) int {
+		if a.Priority != b.Priority {
+			return b.Priority - a.Priority
+		}
+
+		return int(a.ID) - int(b.ID)
+	})
+}
+
+// Merge returns the templates of the sources in the order of the sources, a template is dropped when a
+// previous source already holds a template with the same title. The templates are numbered from 1
+// as their identifiers are only unique inside a templates file
+func Merge(sources []*Templates) *Templates {
+	merged := &Templates{Version: "3", Templates: []portainer.Template{}}
+	if len(sources) > 0 && sources[0].Version != "" {
+		merged.Version = sources[0].Version
+	}
+
+	titles := make(map[string]bool)
+	for _, source := range sources {
+		for _, template := range source.Templates {
+			title := strings.ToLower(strings.TrimSpace(template.Title))
+			if titles[title] {
+				continue
+			}
+			titles[title] = true
+
+			template.ID = portainer.TemplateID(len(merged.Templates) + 1)
+			merged.Templates = append(merged.Templates, template)
+		}
+	}
+
+	return merged
+}
+
+// RefreshAll fetches the templates files of all the sources, it is run periodically so that the
+// templates are served from the cache
+func (service *Service) RefreshAll() error {
+	sources, err := service.sources()
+	if err != nil {
+		return err
+	}
+
+	for i := range sources {
+		if _, err := service.Refresh(&sources[i]); err != nil {
+			log.Warn().Err(err).Str("source", sources[i].Name).Msg("unable to refresh the templates of the source")
+		}
+	}
+
+	return nil
+}
+
+// Refresh fetches the templates file of a source, caches its templates and records the outcome in the source
+func (service *Service) Refresh(source *portainer.TemplateSource) (*Templates, error) {
+	generation := service.generation(source.ID)
+
+	templates, checksum, err := service.fetch(source)
+
+	if source.ID != 0 {
+		service.recordRefresh(source.ID, checksum, err)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	if service.generations[source.ID] == generation {
+		service.cache[source.ID] = templates
+	}
+
+	return templates, nil
+}
+
+// Invalidate drops the cached templates of a source, they are fetched again on the next request
+func (service *Service) Invalidate(sourceID portainer.TemplateSourceID) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	delete(service.cache, sourceID)
+	service.generations[sourceID]++
+}
+
+// SettingsChanged drops the templates fetched from the templates URL of the settings when it changes
+func (service *Service) SettingsChanged(change settingsbus.Change) {
+	if change.Current.TemplatesURL == change.Previous.TemplatesURL {
+		return
+	}
+
+	service.Invalidate(0)
+}
+
+// sources returns the sources in the order their templates are merged. While no source is defined, the
+// templates URL of the settings is returned as the only source
+func (service *Service) sources() ([]portainer.TemplateSource, error) {
+	sources, err := service.dataStore.TemplateSource().ReadAll()
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to retrieve the template sources from the database")
+	}
+
+	if len(sources) > 0 {
+		SortByPriority(sources)
+
+		return sources, nil
+	}
+
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to retrieve the settings from the database")
+	}
+
+	templatesURL := settings.TemplatesURL
+	if templatesURL == "" {
+		templatesURL = portainer.DefaultTemplatesURL
+	}
+
+	return []portainer.TemplateSource{{Name: "default", URL: templatesURL}}, nil
+}
+
+func (service *Service) cachedTemplates(source *portainer.TemplateSource) (*Templates, error) {
+	service.mu.Lock()
+	templates, ok := service.cache[source.ID]
+	service.mu.Unlock()
+
+	if ok {
+		return templates, nil
+	}
+
+	return service.Refresh(source)
+}
+
+func (service *Service) generation(sourceID portainer.TemplateSourceID) int {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	return service.generations[sourceID]
+}
+
+// fetch downloads the templates file of a source, checks it against the checksum of the source
+// and returns its templates along with the checksum of its content
+func (service *Service) fetch(source *portainer.TemplateSource) (*Templates, string, error) {
+	var content []byte
+	var err error
+	if source.GitConfig != nil {
+		content, err = service.fetchFromGit(source.GitConfig)
+	} else {
+		content, err = fetchFromURL(source.URL)
+	}
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	if source.Checksum != "" && !strings.EqualFold(source.Checksum, checksum) {
+		return nil, checksum, ErrChecksumMismatch
+	}
+
+	var templates Templates
+	if err := json.Unmarshal(content, &templates); err != nil {
+		return nil, checksum, errors.WithMessage(err, "unable to parse the templates file")
+	}
+
+	return &templates, checksum, nil
+}
+
+func fetchFromURL(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{Transport: client.NewTransport()}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, errors.WithMessage(err, "unable to retrieve the templates file via the network")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d when retrieving the templates file", resp.StatusCode)
+	}
+
+	return readFile(resp.Body)
+}
+
+func (service *Service) fetchFromGit(config *gittypes.RepoConfig) ([]byte, error) {
+	projectPath, err := service.fileService.GetTemporaryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := service.fileService.RemoveDirectory(projectPath); err != nil {
+			log.Debug().Err(err).Msg("unable to remove the clone of the templates repository")
+		}
+	}()
+
+	var username, password, sshPrivateKey, sshPassphrase string
+	if config.Authentication != nil {
+		username = config.Authentication.Username
+		password = config.Authentication.Password
+		sshPrivateKey = config.Authentication.SSHPrivateKey
+		sshPassphrase = config.Authentication.SSHPassphrase
+	}
+
+	if err := service.gitService.CloneRepository(projectPath, config.URL, config.ReferenceName, username, password, sshPrivateKey, sshPassphrase, config.TLSSkipVerify, config.CloneOptions()); err != nil {
+		return nil, errors.WithMessage(err, "unable to clone the templates repository")
+	}
+
+	filePath := config.ConfigFilePath
+	if filePath == "" {
+		filePath = DefaultFilePath
+	}
+
+	if !filepath.IsLocal(filePath) {
+		return nil, fmt.Errorf("invalid path of the templates file: %q", filePath)
+	}
+
+	return service.fileService.GetFileContent(projectPath, filePath)
+}
+
+func readFile(r io.Reader) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) > maxFileSize {
+		return nil, fmt.Errorf("the templates file exceeds the maximum size of %d bytes", maxFileSize)
+	}
+
+	return content, nil
+}
+
+// recordRefresh stores the outcome of the last refresh in the source, unless it was deleted in the meantime
+func (service *Service) recordRefresh(sourceID portainer.TemplateSourceID, checksum string, refreshErr error) {
+	err := service.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		source, err := tx.TemplateSource().Read(sourceID)
+		if err != nil {
+			return err
+		}
+
+		if refreshErr != nil {
+			source.LastError = refreshErr.Error()
+		} else {
+			source.LastError = ""
+			source.LastRefresh = time.Now().Unix()
+			source.LastChecksum = checksum
+		}
+
+		return tx.TemplateSource().Update(sourceID, source)
+	})
+	if err != nil && !dataservices.IsErrObjectNotFound(err) {
+		log.Warn().Err(err).Int("source_id", int(sourceID)).Msg("unable to record the refresh of the template source")
+	}
+}
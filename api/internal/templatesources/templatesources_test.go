@@ -0,0 +1,106 @@
+package templatesources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const officialTemplates = `{"version":"3","templates":[{"id":1,"title":"Nginx"},{"id":2,"title":"Redis"}]}`
+
+const teamTemplates = `{"version":"3","templates":[{"id":1,"title":"nginx","image":"registry.example.com/nginx"},{"id":2,"title":"Billing"}]}`
+
+func TestMerge(t *testing.T) {
+	merged := Merge([]*Templates{
+		{Version: "3", Templates: []portainer.Template{{ID: 7, Title: "nginx", Image: "registry.example.com/nginx"}}},
+		{Version: "3", Templates: []portainer.Template{{ID: 1, Title: "Nginx"}, {ID: 2, Title: "Redis"}}},
+	})
+
+	require.Len(t, merged.Templates, 2)
+	assert.Equal(t, portainer.Template{ID: 1, Title: "nginx", Image: "registry.example.com/nginx"}, merged.Templates[0])
+	assert.Equal(t, portainer.Template{ID: 2, Title: "Redis"}, merged.Templates[1])
+}
+
+func TestSortByPriority(t *testing.T) {
+	sources := []portainer.TemplateSource{{ID: 1, Priority: 0}, {ID: 2, Priority: 10}, {ID: 3, Priority: 0}}
+
+	SortByPriority(sources)
+
+	assert.Equal(t, []portainer.TemplateSourceID{2, 1, 3}, []portainer.TemplateSourceID{sources[0].ID, sources[1].ID, sources[2].ID})
+}
+
+func TestIsVisible(t *testing.T) {
+	source := &portainer.TemplateSource{TeamIDs: []portainer.TeamID{2}}
+
+	assert.True(t, IsVisible(source, true, nil))
+	assert.True(t, IsVisible(source, false, []portainer.TeamID{1, 2}))
+	assert.False(t, IsVisible(source, false, []portainer.TeamID{1}))
+	assert.True(t, IsVisible(&portainer.TemplateSource{}, false, nil))
+}
+
+func TestTemplates(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	files := map[string]string{"/official.json": officialTemplates, "/team.json": teamTemplates}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(files[r.URL.Path]))
+	}))
+	defer srv.Close()
+
+	sum := sha256.Sum256([]byte(teamTemplates))
+
+	require.NoError(t, store.TemplateSource().Create(&portainer.TemplateSource{Name: "official", URL: srv.URL + "/official.json"}))
+	require.NoError(t, store.TemplateSource().Create(&portainer.TemplateSource{
+		Name:     "team",
+		URL:      srv.URL + "/team.json",
+		Checksum: hex.EncodeToString(sum[:]),
+		Priority: 10,
+		TeamIDs:  []portainer.TeamID{1},
+	}))
+
+	service := NewService(store, nil, nil)
+
+	templates, err := service.Templates(false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Nginx", "Redis"}, titles(templates))
+
+	templates, err = service.Templates(false, []portainer.TeamID{1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nginx", "Billing", "Redis"}, titles(templates))
+
+	source, err := store.TemplateSource().Read(2)
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), source.LastChecksum)
+	assert.Empty(t, source.LastError)
+
+	// A file that no longer matches the checksum is rejected and the cached templates are kept
+	files["/team.json"] = `{"version":"3","templates":[{"id":1,"title":"Tampered"}]}`
+
+	_, err = service.Refresh(source)
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+
+	source, err = store.TemplateSource().Read(2)
+	require.NoError(t, err)
+	assert.Equal(t, ErrChecksumMismatch.Error(), source.LastError)
+
+	templates, err = service.Templates(true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nginx", "Billing", "Redis"}, titles(templates))
+}
+
+func titles(templates *Templates) []string {
+	titles := make([]string, 0, len(templates.Templates))
+	for _, template := range templates.Templates {
+		titles = append(titles, template.Title)
+	}
+
+	return titles
+}
@@ -0,0 +1,72 @@
+package templatevariables
+
+import (
+	"errors"
+	"fmt"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+// Variable is a custom template variable along with the value to pre-fill when deploying
+// the template on a specific environment(endpoint)
+type Variable struct {
+	portainer.CustomTemplateVariableDefinition
+	// Value pre-filled for the variable, the default value of the variable when no preset applies
+	Value string `json:"value" example:"corp.internal"`
+	// Whether the value cannot be changed by the user deploying the template
+	Locked bool `json:"locked" example:"false"`
+	// Whether the value comes from a preset of the environment(endpoint) or of its group
+	Preset bool `json:"preset" example:"true"`
+}
+
+// ValidatePresets ensures that every preset targets a named variable and that a variable is preset only once
+func ValidatePresets(presets []portainer.CustomTemplateVariablePreset) error {
+	names := make(map[string]bool, len(presets))
+
+	for _, preset := range presets {
+		if preset.Name == "" {
+			return errors.New("preset variable name is required")
+		}
+
+		if names[preset.Name] {
+			return fmt.Errorf("variable %s is preset more than once", preset.Name)
+		}
+
+		names[preset.Name] = true
+	}
+
+	return nil
+}
+
+// Resolve returns the variables of a custom template with the presets applied.
+// The presets of the environment(endpoint) take precedence over the ones of its group
+func Resolve(definitions []portainer.CustomTemplateVariableDefinition, groupPresets, endpointPresets []portainer.CustomTemplateVariablePreset) []Variable {
+	presets := make(map[string]portainer.CustomTemplateVariablePreset, len(groupPresets)+len(endpointPresets))
+
+	for _, preset := range groupPresets {
+		presets[preset.Name] = preset
+	}
+
+	for _, preset := range endpointPresets {
+		presets[preset.Name] = preset
+	}
+
+	variables := make([]Variable, 0, len(definitions))
+
+	for _, definition := range definitions {
+		variable := Variable{
+			CustomTemplateVariableDefinition: definition,
+			Value:                            definition.DefaultValue,
+		}
+
+		if preset, ok := presets[definition.Name]; ok {
+			variable.Value = preset.Value
+			variable.Locked = preset.Locked
+			variable.Preset = true
+		}
+
+		variables = append(variables, variable)
+	}
+
+	return variables
+}
@@ -0,0 +1,42 @@
+package templatevariables
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePresets(t *testing.T) {
+	assert.NoError(t, ValidatePresets(nil))
+	assert.NoError(t, ValidatePresets([]portainer.CustomTemplateVariablePreset{{Name: "A"}, {Name: "B", Value: "b", Locked: true}}))
+	assert.Error(t, ValidatePresets([]portainer.CustomTemplateVariablePreset{{Value: "a"}}))
+	assert.Error(t, ValidatePresets([]portainer.CustomTemplateVariablePreset{{Name: "A"}, {Name: "A"}}))
+}
+
+func TestResolve(t *testing.T) {
+	definitions := []portainer.CustomTemplateVariableDefinition{
+		{Name: "DNS_SUFFIX", Label: "DNS suffix", DefaultValue: "local"},
+		{Name: "STORAGE_PATH", Label: "Storage path", DefaultValue: "/data"},
+		{Name: "REPLICAS", Label: "Replicas", DefaultValue: "1"},
+	}
+
+	groupPresets := []portainer.CustomTemplateVariablePreset{
+		{Name: "DNS_SUFFIX", Value: "group.internal", Locked: true},
+		{Name: "STORAGE_PATH", Value: "/mnt/group"},
+		{Name: "UNUSED", Value: "ignored"},
+	}
+
+	endpointPresets := []portainer.CustomTemplateVariablePreset{
+		{Name: "STORAGE_PATH", Value: "/mnt/endpoint", Locked: true},
+	}
+
+	variables := Resolve(definitions, groupPresets, endpointPresets)
+
+	assert.Equal(t, []Variable{
+		{CustomTemplateVariableDefinition: definitions[0], Value: "group.internal", Locked: true, Preset: true},
+		{CustomTemplateVariableDefinition: definitions[1], Value: "/mnt/endpoint", Locked: true, Preset: true},
+		{CustomTemplateVariableDefinition: definitions[2], Value: "1"},
+	}, variables)
+}
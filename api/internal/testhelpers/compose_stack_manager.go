@@ -12,7 +12,7 @@ func NewComposeStackManager() *composeStackManager {
 	return &composeStackManager{}
 }
 
-func (manager *composeStackManager) ComposeSyntaxMaxVersion() string {
+func (manager *composeStackManager) ComposeSyntaxMaxVersion(endpoint *portainer.Endpoint) string {
 	return ""
 }
 
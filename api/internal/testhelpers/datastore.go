@@ -34,6 +34,31 @@ type testDatastore struct {
 	version                 dataservices.VersionService
 	webhook                 dataservices.WebhookService
 	pendingActionsService   dataservices.PendingActionsService
+	deployment              dataservices.DeploymentService
+	diskUsageSample         dataservices.DiskUsageSampleService
+	snapshotRecord          dataservices.SnapshotRecordService
+	imageUpdateJob          dataservices.ImageUpdateJobService
+	failoverPolicy          dataservices.FailoverPolicyService
+	quota                   dataservices.QuotaService
+	edgeCommand             dataservices.EdgeCommandService
+	activityEvent           dataservices.ActivityEventService
+	groupReport             dataservices.GroupReportService
+	reportTemplate          dataservices.ReportTemplateService
+	validationWebhook       dataservices.ValidationWebhookService
+	snapshotWebhook         dataservices.SnapshotWebhookService
+	notificationChannel     dataservices.NotificationChannelService
+	userSession             dataservices.UserSessionService
+	stackGitOpsStatus       dataservices.StackGitOpsStatusService
+	edgeUpdateSchedule      dataservices.EdgeUpdateScheduleService
+	templateSource          dataservices.TemplateSourceService
+	webhookExecution        dataservices.WebhookExecutionService
+	cloudProvisioning       dataservices.CloudProvisioningService
+	jwtSigningKey           dataservices.JWTSigningKeyService
+	openAMTPowerSchedule    dataservices.OpenAMTPowerScheduleService
+	openAMTDeviceAction     dataservices.OpenAMTDeviceActionService
+	userSettings            dataservices.UserSettingsService
+	edgeJoinToken           dataservices.EdgeJoinTokenService
+	pruneRun                dataservices.PruneRunService
 	connection              portainer.Connection
 }
 
@@ -85,6 +110,106 @@ func (d *testDatastore) PendingActions() dataservices.PendingActionsService {
 	return d.pendingActionsService
 }
 
+func (d *testDatastore) ImageUpdateJob() dataservices.ImageUpdateJobService {
+	return d.imageUpdateJob
+}
+
+func (d *testDatastore) FailoverPolicy() dataservices.FailoverPolicyService {
+	return d.failoverPolicy
+}
+
+func (d *testDatastore) Quota() dataservices.QuotaService {
+	return d.quota
+}
+
+func (d *testDatastore) DiskUsageSample() dataservices.DiskUsageSampleService {
+	return d.diskUsageSample
+}
+
+func (d *testDatastore) SnapshotRecord() dataservices.SnapshotRecordService {
+	return d.snapshotRecord
+}
+
+func (d *testDatastore) Deployment() dataservices.DeploymentService {
+	return d.deployment
+}
+
+func (d *testDatastore) EdgeCommand() dataservices.EdgeCommandService {
+	return d.edgeCommand
+}
+
+func (d *testDatastore) ActivityEvent() dataservices.ActivityEventService {
+	return d.activityEvent
+}
+
+func (d *testDatastore) GroupReport() dataservices.GroupReportService {
+	return d.groupReport
+}
+
+func (d *testDatastore) ReportTemplate() dataservices.ReportTemplateService {
+	return d.reportTemplate
+}
+
+func (d *testDatastore) ValidationWebhook() dataservices.ValidationWebhookService {
+	return d.validationWebhook
+}
+
+func (d *testDatastore) SnapshotWebhook() dataservices.SnapshotWebhookService {
+	return d.snapshotWebhook
+}
+
+func (d *testDatastore) NotificationChannel() dataservices.NotificationChannelService {
+	return d.notificationChannel
+}
+
+func (d *testDatastore) UserSession() dataservices.UserSessionService {
+	return d.userSession
+}
+
+func (d *testDatastore) StackGitOpsStatus() dataservices.StackGitOpsStatusService {
+	return d.stackGitOpsStatus
+}
+
+func (d *testDatastore) EdgeUpdateSchedule() dataservices.EdgeUpdateScheduleService {
+	return d.edgeUpdateSchedule
+}
+
+func (d *testDatastore) TemplateSource() dataservices.TemplateSourceService {
+	return d.templateSource
+}
+
+func (d *testDatastore) WebhookExecution() dataservices.WebhookExecutionService {
+	return d.webhookExecution
+}
+
+func (d *testDatastore) CloudProvisioning() dataservices.CloudProvisioningService {
+	return d.cloudProvisioning
+}
+
+func (d *testDatastore) JWTSigningKey() dataservices.JWTSigningKeyService {
+	return d.jwtSigningKey
+}
+
+func (d *testDatastore) OpenAMTPowerSchedule() dataservices.OpenAMTPowerScheduleService {
+	return d.openAMTPowerSchedule
+}
+
+func (d *testDatastore) OpenAMTDeviceAction() dataservices.OpenAMTDeviceActionService {
+	return d.openAMTDeviceAction
+}
+
+func (d *testDatastore) UserSettings() dataservices.UserSettingsService {
+	return d.userSettings
+}
+
+func (d *testDatastore) EdgeJoinToken() dataservices.EdgeJoinTokenService {
+	return d.edgeJoinToken
+}
+
+func (d *testDatastore) PruneRun() dataservices.PruneRunService {
+	return d.pruneRun
+}
+
 func (d *testDatastore) Connection() portainer.Connection {
 	return d.connection
 }
@@ -286,6 +411,25 @@ func (s *stubEndpointService) Endpoints() ([]portainer.Endpoint, error) {
 	return s.endpoints, nil
 }
 
+func (s *stubEndpointService) EndpointsPaginated(offset, limit int, filters dataservices.EndpointFilters) ([]portainer.Endpoint, int, error) {
+	endpoints := make([]portainer.Endpoint, 0)
+	total := 0
+
+	for _, e := range s.endpoints {
+		if !filters.Match(&e) {
+			continue
+		}
+
+		if total >= offset && (limit == 0 || len(endpoints) < limit) {
+			endpoints = append(endpoints, e)
+		}
+
+		total++
+	}
+
+	return endpoints, total, nil
+}
+
 func (s *stubEndpointService) Create(endpoint *portainer.Endpoint) error {
 	s.endpoints = append(s.endpoints, *endpoint)
 
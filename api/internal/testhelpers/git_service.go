@@ -1,6 +1,9 @@
 package testhelpers
 
-import portainer "github.com/portainer/portainer/api"
+import (
+	portainer "github.com/portainer/portainer/api"
+	gittypes "github.com/portainer/portainer/api/git/types"
+)
 
 type gitService struct {
 	cloneErr error
@@ -15,18 +18,22 @@ func NewGitService(cloneErr error, id string) portainer.GitService {
 	}
 }
 
-func (g *gitService) CloneRepository(destination, repositoryURL, referenceName, username, password string, tlsSkipVerify bool) error {
+func (g *gitService) CloneRepository(destination, repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, tlsSkipVerify bool, cloneOptions gittypes.CloneOptions) error {
 	return g.cloneErr
 }
 
-func (g *gitService) LatestCommitID(repositoryURL, referenceName, username, password string, tlsSkipVerify bool) (string, error) {
+func (g *gitService) LatestCommitID(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, tlsSkipVerify bool) (string, error) {
 	return g.id, nil
 }
 
-func (g *gitService) ListRefs(repositoryURL, username, password string, hardRefresh bool, tlsSkipVerify bool) ([]string, error) {
+func (g *gitService) LatestCommit(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, tlsSkipVerify bool) (*gittypes.CommitInfo, error) {
+	return &gittypes.CommitInfo{Hash: g.id}, nil
+}
+
+func (g *gitService) ListRefs(repositoryURL, username, password, sshPrivateKey, sshPassphrase string, hardRefresh bool, tlsSkipVerify bool) ([]string, error) {
 	return nil, nil
 }
 
-func (g *gitService) ListFiles(repositoryURL, referenceName, username, password string, dirOnly, hardRefresh bool, includedExts []string, tlsSkipVerify bool) ([]string, error) {
+func (g *gitService) ListFiles(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, dirOnly, hardRefresh bool, includedExts []string, tlsSkipVerify bool) ([]string, error) {
 	return nil, nil
 }
@@ -60,6 +60,12 @@ func (testRequestBouncer) JWTAuthLookup(r *http.Request) (*portainer.TokenData,
 
 func (testRequestBouncer) RevokeJWT(jti string) {}
 
+func (testRequestBouncer) RevokeSession(session *portainer.UserSession) error {
+	return nil
+}
+
+func (testRequestBouncer) RevokeSessionJWT(session *portainer.UserSession) {}
+
 // AddTestSecurityCookie adds a security cookie to the request
 func AddTestSecurityCookie(r *http.Request, jwt string) {
 	r.AddCookie(&http.Cookie{
@@ -3,6 +3,7 @@ package jwt
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
@@ -31,6 +32,13 @@ type Service struct {
 	secrets            map[scope][]byte
 	userSessionTimeout time.Duration
 	dataStore          dataservices.DataStore
+
+	mu sync.RWMutex
+	// signingKey signs the session tokens
+	signingKey *signingKey
+	// verificationKeys verify the session tokens by key ID, they include the rotated keys until the tokens
+	// they signed expire
+	verificationKeys map[string]*signingKey
 }
 
 type claims struct {
@@ -39,6 +47,7 @@ type claims struct {
 	Role                int    `json:"role"`
 	Scope               scope  `json:"scope"`
 	ForceChangePassword bool   `json:"forceChangePassword"`
+	AuthMethod          int    `json:"authMethod,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -64,13 +73,20 @@ func NewService(userSessionDuration string, dataStore dataservices.DataStore) (*
 		return nil, err
 	}
 
+	key, err := newHMACSigningKey(secret)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Service{
-		map[scope][]byte{
+		secrets: map[scope][]byte{
 			defaultScope:    secret,
 			kubeConfigScope: kubeSecret,
 		},
-		userSessionTimeout,
-		dataStore,
+		userSessionTimeout: userSessionTimeout,
+		dataStore:          dataStore,
+		signingKey:         key,
+		verificationKeys:   map[string]*signingKey{key.id: key},
 	}, nil
 }
 
@@ -99,13 +115,29 @@ func getOrCreateKubeSecret(dataStore dataservices.DataStore) ([]byte, error) {
 	return kubeSecret, nil
 }
 
-func (service *Service) defaultExpireAt() time.Time {
-	return time.Now().Add(service.userSessionTimeout)
+// sessionTimeout returns the lifetime of the session tokens of an authentication method, the user session
+// timeout applies when the settings do not define one for the method
+func (service *Service) sessionTimeout(settings *portainer.Settings, authMethod portainer.AuthenticationMethod) time.Duration {
+	if settings != nil {
+		if timeout, err := time.ParseDuration(settings.AuthenticationSessionTimeouts[authMethod]); err == nil && timeout > 0 {
+			return timeout
+		}
+	}
+
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	return service.userSessionTimeout
 }
 
 // GenerateToken generates a new JWT token.
 func (service *Service) GenerateToken(data *portainer.TokenData) (string, time.Time, error) {
-	expiryTime := service.defaultExpireAt()
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed fetching settings from db: %w", err)
+	}
+
+	expiryTime := time.Now().Add(service.sessionTimeout(settings, data.AuthenticationMethod))
 	token, err := service.generateSignedToken(data, expiryTime, defaultScope)
 
 	return token, expiryTime, err
@@ -114,14 +146,17 @@ func (service *Service) GenerateToken(data *portainer.TokenData) (string, time.T
 // ParseAndVerifyToken parses a JWT token and verify its validity. It returns an error if token is invalid.
 func (service *Service) ParseAndVerifyToken(token string) (*portainer.TokenData, string, time.Time, error) {
 	scope := parseScope(token)
-	secret := service.secrets[scope]
 
 	parsedToken, err := jwt.ParseWithClaims(token, &claims{}, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		if scope == kubeConfigScope {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+
+			return service.secrets[kubeConfigScope], nil
 		}
 
-		return secret, nil
+		return service.verificationKey(token)
 	})
 	if err != nil || parsedToken == nil {
 		return nil, "", time.Time{}, errInvalidJWTToken
@@ -142,11 +177,12 @@ func (service *Service) ParseAndVerifyToken(token string) (*portainer.TokenData,
 	}
 
 	return &portainer.TokenData{
-		ID:                  portainer.UserID(cl.UserID),
-		Username:            cl.Username,
-		Role:                portainer.UserRole(cl.Role),
-		Token:               token,
-		ForceChangePassword: cl.ForceChangePassword,
+		ID:                   portainer.UserID(cl.UserID),
+		Username:             cl.Username,
+		Role:                 portainer.UserRole(cl.Role),
+		Token:                token,
+		ForceChangePassword:  cl.ForceChangePassword,
+		AuthenticationMethod: portainer.AuthenticationMethod(cl.AuthMethod),
 	}, cl.ID, cl.ExpiresAt.Time, nil
 }
 
@@ -166,6 +202,9 @@ func parseScope(token string) scope {
 
 // SetUserSessionDuration sets the user session duration
 func (service *Service) SetUserSessionDuration(userSessionDuration time.Duration) {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
 	service.userSessionTimeout = userSessionDuration
 }
 
@@ -196,6 +235,7 @@ func (service *Service) generateSignedToken(data *portainer.TokenData, expiresAt
 		Role:                int(data.Role),
 		Scope:               scope,
 		ForceChangePassword: data.ForceChangePassword,
+		AuthMethod:          int(data.AuthenticationMethod),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ID:        uuid.String(),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -208,7 +248,17 @@ func (service *Service) generateSignedToken(data *portainer.TokenData, expiresAt
 		cl.RegisteredClaims.ExpiresAt = nil
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, cl)
+	// The Kubeconfig tokens are signed with their own persisted secret
+	if scope == kubeConfigScope {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, cl).SignedString(secret)
+	}
+
+	service.mu.RLock()
+	key := service.signingKey
+	service.mu.RUnlock()
+
+	token := jwt.NewWithClaims(key.method, cl)
+	token.Header["kid"] = key.id
 
-	return token.SignedString(secret)
+	return token.SignedString(key.signKey)
 }
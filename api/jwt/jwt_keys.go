@@ -0,0 +1,298 @@
+package jwt
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/apikey"
+	"github.com/portainer/portainer/api/crypto"
+
+	"github.com/gofrs/uuid"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// signingKey is a key signing or verifying the session tokens
+type signingKey struct {
+	// id is the key ID of the tokens signed with the key
+	id        string
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+	// persistedID identifies the key pairs saved in the database, it is zero for the HMAC secrets
+	persistedID portainer.JWTSigningKeyID
+	// verifyUntil is the time until which a rotated key verifies the tokens it signed, it is zero for the active key
+	verifyUntil time.Time
+}
+
+// newHMACSigningKey returns a key signing the tokens with HMAC SHA-256, the secret is kept in memory only
+// so the sessions do not survive a restart
+func newHMACSigningKey(secret []byte) (*signingKey, error) {
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate the key ID: %w", err)
+	}
+
+	return &signingKey{
+		id:        id.String(),
+		method:    jwt.SigningMethodHS256,
+		signKey:   secret,
+		verifyKey: secret,
+	}, nil
+}
+
+// parseSigningKey parses a persisted key pair
+func parseSigningKey(key *portainer.JWTSigningKey) (*signingKey, error) {
+	parsed := &signingKey{
+		id:          strconv.Itoa(int(key.ID)),
+		persistedID: key.ID,
+	}
+
+	if key.VerifyUntil != 0 {
+		parsed.verifyUntil = time.Unix(key.VerifyUntil, 0)
+	}
+
+	switch key.Algorithm {
+	case portainer.JWTSigningAlgorithmRS256:
+		privateKey, err := x509.ParsePKCS1PrivateKey(key.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed.method = jwt.SigningMethodRS256
+		parsed.signKey = privateKey
+		parsed.verifyKey = &privateKey.PublicKey
+	case portainer.JWTSigningAlgorithmES256:
+		privateKey, err := x509.ParseECPrivateKey(key.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed.method = jwt.SigningMethodES256
+		parsed.signKey = privateKey
+		parsed.verifyKey = &privateKey.PublicKey
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", key.Algorithm)
+	}
+
+	return parsed, nil
+}
+
+// newSignatureService returns the service generating the key pairs of an asymmetric algorithm
+func newSignatureService(algorithm portainer.JWTSigningAlgorithm) (portainer.DigitalSignatureService, error) {
+	switch algorithm {
+	case portainer.JWTSigningAlgorithmRS256:
+		return crypto.NewRSAService(), nil
+	case portainer.JWTSigningAlgorithmES256:
+		return crypto.NewECDSAService(""), nil
+	}
+
+	return nil, fmt.Errorf("unsupported signing algorithm: %s", algorithm)
+}
+
+func isHMAC(algorithm portainer.JWTSigningAlgorithm) bool {
+	return algorithm == "" || algorithm == portainer.JWTSigningAlgorithmHS256
+}
+
+// createSigningKey creates a new key for an algorithm, the key pairs of the asymmetric algorithms are saved
+// so that the sessions survive a restart
+func (service *Service) createSigningKey(algorithm portainer.JWTSigningAlgorithm) (*signingKey, error) {
+	if isHMAC(algorithm) {
+		secret := apikey.GenerateRandomKey(keyLen)
+		if secret == nil {
+			return nil, errSecretGeneration
+		}
+
+		return newHMACSigningKey(secret)
+	}
+
+	signatureService, err := newSignatureService(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, publicKey, err := signatureService.GenerateKeyPair()
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate the key pair: %w", err)
+	}
+
+	key := &portainer.JWTSigningKey{
+		Algorithm:  algorithm,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+		Created:    time.Now().Unix(),
+	}
+
+	if err := service.dataStore.JWTSigningKey().Create(key); err != nil {
+		return nil, fmt.Errorf("unable to persist the key pair: %w", err)
+	}
+
+	return parseSigningKey(key)
+}
+
+// maxSessionTimeout returns the longest lifetime of the session tokens, it bounds how long a rotated key
+// has to verify the tokens it signed
+func (service *Service) maxSessionTimeout(settings *portainer.Settings) time.Duration {
+	timeout := service.sessionTimeout(nil, 0)
+
+	for authMethod := range settings.AuthenticationSessionTimeouts {
+		timeout = max(timeout, service.sessionTimeout(settings, authMethod))
+	}
+
+	return timeout
+}
+
+// LoadSigningKeys loads the persisted key pairs and makes sure that the session tokens are signed with the
+// algorithm of the settings, it is called once at startup
+func (service *Service) LoadSigningKeys() error {
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		return err
+	}
+
+	keys, err := service.dataStore.JWTSigningKey().ReadAll()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	verifyUntil := now.Add(service.maxSessionTimeout(settings))
+
+	service.mu.Lock()
+
+	for i := range keys {
+		key := &keys[i]
+
+		if key.VerifyUntil != 0 && now.Unix() > key.VerifyUntil {
+			service.deleteKey(key.ID)
+
+			continue
+		}
+
+		parsed, err := parseSigningKey(key)
+		if err != nil {
+			log.Warn().Err(err).Int("key_id", int(key.ID)).Msg("unable to parse the JWT signing key")
+
+			continue
+		}
+
+		// The active key of a previous algorithm keeps verifying the tokens it signed
+		if key.VerifyUntil == 0 && (key.Algorithm != settings.JWTSigningAlgorithm || service.signingKey.persistedID != 0) {
+			key.VerifyUntil = verifyUntil.Unix()
+			parsed.verifyUntil = verifyUntil
+
+			if err := service.dataStore.JWTSigningKey().Update(key.ID, key); err != nil {
+				log.Warn().Err(err).Int("key_id", int(key.ID)).Msg("unable to retire the JWT signing key")
+			}
+		}
+
+		if key.VerifyUntil == 0 {
+			// The HMAC secret generated at startup did not sign any token yet
+			delete(service.verificationKeys, service.signingKey.id)
+			service.signingKey = parsed
+		}
+
+		service.verificationKeys[parsed.id] = parsed
+	}
+
+	rotate := !isHMAC(settings.JWTSigningAlgorithm) && service.signingKey.persistedID == 0
+
+	service.mu.Unlock()
+
+	if rotate {
+		return service.RotateSigningKey()
+	}
+
+	return nil
+}
+
+// RotateSigningKey replaces the key signing the session tokens with a new key using the algorithm of the
+// settings, the previous keys keep verifying the tokens they signed until these expire
+func (service *Service) RotateSigningKey() error {
+	settings, err := service.dataStore.Settings().Settings()
+	if err != nil {
+		return err
+	}
+
+	key, err := service.createSigningKey(settings.JWTSigningAlgorithm)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	verifyUntil := now.Add(service.maxSessionTimeout(settings))
+
+	service.mu.Lock()
+	defer service.mu.Unlock()
+
+	previous := service.signingKey
+	previous.verifyUntil = verifyUntil
+
+	if previous.persistedID != 0 {
+		service.retireKey(previous.persistedID, verifyUntil)
+	}
+
+	service.signingKey = key
+	service.verificationKeys[key.id] = key
+
+	for id, verificationKey := range service.verificationKeys {
+		if verificationKey.verifyUntil.IsZero() || now.Before(verificationKey.verifyUntil) {
+			continue
+		}
+
+		delete(service.verificationKeys, id)
+
+		if verificationKey.persistedID != 0 {
+			service.deleteKey(verificationKey.persistedID)
+		}
+	}
+
+	log.Info().Str("algorithm", key.method.Alg()).Msg("the JWT signing key was rotated")
+
+	return nil
+}
+
+func (service *Service) retireKey(keyID portainer.JWTSigningKeyID, verifyUntil time.Time) {
+	key, err := service.dataStore.JWTSigningKey().Read(keyID)
+	if err == nil {
+		key.VerifyUntil = verifyUntil.Unix()
+		err = service.dataStore.JWTSigningKey().Update(keyID, key)
+	}
+
+	if err != nil {
+		log.Warn().Err(err).Int("key_id", int(keyID)).Msg("unable to retire the JWT signing key")
+	}
+}
+
+func (service *Service) deleteKey(keyID portainer.JWTSigningKeyID) {
+	if err := service.dataStore.JWTSigningKey().Delete(keyID); err != nil {
+		log.Warn().Err(err).Int("key_id", int(keyID)).Msg("unable to delete the expired JWT signing key")
+	}
+}
+
+// verificationKey returns the key verifying a session token from its key ID
+func (service *Service) verificationKey(token *jwt.Token) (any, error) {
+	keyID, _ := token.Header["kid"].(string)
+
+	service.mu.RLock()
+	defer service.mu.RUnlock()
+
+	key, ok := service.verificationKeys[keyID]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	if !key.verifyUntil.IsZero() && time.Now().After(key.verifyUntil) {
+		return nil, errors.New("expired signing key")
+	}
+
+	return key.verifyKey, nil
+}
@@ -111,3 +111,84 @@ func TestExpiration(t *testing.T) {
 	_, _, _, err = service.ParseAndVerifyToken(tokenString)
 	require.Error(t, err)
 }
+
+func TestSessionTimeoutPerAuthenticationMethod(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, false)
+
+	require.NoError(t, store.User().Create(&portainer.User{ID: 1}))
+
+	settings, err := store.Settings().Settings()
+	require.NoError(t, err)
+
+	settings.AuthenticationSessionTimeouts = map[portainer.AuthenticationMethod]string{portainer.AuthenticationOAuth: "15m"}
+	require.NoError(t, store.Settings().UpdateSettings(settings))
+
+	service, err := NewService("1h", store)
+	require.NoError(t, err)
+
+	_, expiresAt, err := service.GenerateToken(&portainer.TokenData{ID: 1, AuthenticationMethod: portainer.AuthenticationOAuth})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), expiresAt, time.Minute)
+
+	_, expiresAt, err = service.GenerateToken(&portainer.TokenData{ID: 1, AuthenticationMethod: portainer.AuthenticationLDAP})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+}
+
+func TestSigningKeyRotation(t *testing.T) {
+	for _, algorithm := range []portainer.JWTSigningAlgorithm{portainer.JWTSigningAlgorithmRS256, portainer.JWTSigningAlgorithmES256} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			_, store := datastore.MustNewTestStore(t, true, false)
+
+			require.NoError(t, store.User().Create(&portainer.User{ID: 1}))
+
+			settings, err := store.Settings().Settings()
+			require.NoError(t, err)
+
+			settings.JWTSigningAlgorithm = algorithm
+			require.NoError(t, store.Settings().UpdateSettings(settings))
+
+			service, err := NewService("1h", store)
+			require.NoError(t, err)
+			require.NoError(t, service.LoadSigningKeys())
+
+			tokenData := &portainer.TokenData{ID: 1, Username: "User", Role: 1}
+
+			previousToken, _, err := service.GenerateToken(tokenData)
+			require.NoError(t, err)
+
+			parsedToken, _, err := new(jwt.Parser).ParseUnverified(previousToken, &claims{})
+			require.NoError(t, err)
+			assert.Equal(t, string(algorithm), parsedToken.Method.Alg())
+
+			require.NoError(t, service.RotateSigningKey())
+
+			token, _, err := service.GenerateToken(tokenData)
+			require.NoError(t, err)
+
+			// The tokens signed before the rotation remain valid until they expire
+			_, _, _, err = service.ParseAndVerifyToken(previousToken)
+			require.NoError(t, err)
+
+			_, _, _, err = service.ParseAndVerifyToken(token)
+			require.NoError(t, err)
+
+			keys, err := store.JWTSigningKey().ReadAll()
+			require.NoError(t, err)
+			require.Len(t, keys, 2)
+			assert.NotZero(t, keys[0].VerifyUntil)
+			assert.Zero(t, keys[1].VerifyUntil)
+
+			// The persisted keys are loaded after a restart
+			restarted, err := NewService("1h", store)
+			require.NoError(t, err)
+			require.NoError(t, restarted.LoadSigningKeys())
+
+			_, _, _, err = restarted.ParseAndVerifyToken(previousToken)
+			require.NoError(t, err)
+
+			_, _, _, err = restarted.ParseAndVerifyToken(token)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -71,6 +71,96 @@ func (kcl *KubeClient) GetConfigMap(namespace, configMapName string) (models.K8s
 	return parseConfigMap(configMap, true), nil
 }
 
+// CreateConfigMap creates a ConfigMap in the given namespace and records its creation in the change history.
+func (kcl *KubeClient) CreateConfigMap(namespace string, payload models.K8sConfigMapPayload, username string) (models.K8sConfigMap, error) {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return models.K8sConfigMap{}, err
+	}
+
+	annotations, err := appendConfigurationHistory(nil, username, diffConfigurationData(nil, payload.Data, false))
+	if err != nil {
+		return models.K8sConfigMap{}, err
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        payload.Name,
+			Namespace:   namespace,
+			Labels:      payload.Labels,
+			Annotations: annotations,
+		},
+		Data: payload.Data,
+	}
+
+	configMap, err = kcl.cli.CoreV1().ConfigMaps(namespace).Create(context.Background(), configMap, metav1.CreateOptions{})
+	if err != nil {
+		return models.K8sConfigMap{}, err
+	}
+
+	return parseConfigMap(configMap, true), nil
+}
+
+// UpdateConfigMap replaces the data of a ConfigMap and records the changed keys in the change history.
+// the update is rejected with ErrConfigurationConflict when the ConfigMap was modified since payload.ResourceVersion.
+func (kcl *KubeClient) UpdateConfigMap(namespace, configMapName string, payload models.K8sConfigMapPayload, username string) (models.K8sConfigMap, error) {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return models.K8sConfigMap{}, err
+	}
+
+	configMap, err := kcl.cli.CoreV1().ConfigMaps(namespace).Get(context.Background(), configMapName, metav1.GetOptions{})
+	if err != nil {
+		return models.K8sConfigMap{}, err
+	}
+
+	if configMap.ResourceVersion != payload.ResourceVersion {
+		return models.K8sConfigMap{}, ErrConfigurationConflict
+	}
+
+	annotations, err := appendConfigurationHistory(configMap.Annotations, username, diffConfigurationData(configMap.Data, payload.Data, false))
+	if err != nil {
+		return models.K8sConfigMap{}, err
+	}
+
+	configMap.Annotations = annotations
+	configMap.Labels = mergeLabels(configMap.Labels, payload.Labels)
+	configMap.Data = payload.Data
+
+	// the resource version is kept so that the Kubernetes API rejects concurrent updates as well
+	configMap, err = kcl.cli.CoreV1().ConfigMaps(namespace).Update(context.Background(), configMap, metav1.UpdateOptions{})
+	if err != nil {
+		return models.K8sConfigMap{}, err
+	}
+
+	return parseConfigMap(configMap, true), nil
+}
+
+// DiffConfigMap returns a preview of the changes an update of the ConfigMap would make, without applying them.
+func (kcl *KubeClient) DiffConfigMap(namespace, configMapName string, payload models.K8sConfigMapPayload) (models.K8sConfigurationDiff, error) {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return models.K8sConfigurationDiff{}, err
+	}
+
+	configMap, err := kcl.cli.CoreV1().ConfigMaps(namespace).Get(context.Background(), configMapName, metav1.GetOptions{})
+	if err != nil {
+		return models.K8sConfigurationDiff{}, err
+	}
+
+	return models.K8sConfigurationDiff{
+		ResourceVersion: configMap.ResourceVersion,
+		Changes:         diffConfigurationData(configMap.Data, payload.Data, true),
+		Conflict:        payload.ResourceVersion != "" && payload.ResourceVersion != configMap.ResourceVersion,
+	}, nil
+}
+
+// DeleteConfigMap deletes a ConfigMap from the given namespace.
+func (kcl *KubeClient) DeleteConfigMap(namespace, configMapName string) error {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return err
+	}
+
+	return kcl.cli.CoreV1().ConfigMaps(namespace).Delete(context.Background(), configMapName, metav1.DeleteOptions{})
+}
+
 // parseConfigMap parses a k8s ConfigMap object into a K8sConfigMap struct.
 // for get operation, withData will be set to true.
 // otherwise, only metadata will be parsed.
@@ -81,6 +171,7 @@ func parseConfigMap(configMap *corev1.ConfigMap, withData bool) models.K8sConfig
 			Name:                 configMap.Name,
 			Namespace:            configMap.Namespace,
 			CreationDate:         configMap.CreationTimestamp.Time.UTC().Format(time.RFC3339),
+			ResourceVersion:      configMap.ResourceVersion,
 			Annotations:          configMap.Annotations,
 			Labels:               configMap.Labels,
 			ConfigurationOwner:   configMap.Labels[labelPortainerKubeConfigOwner],
@@ -90,6 +181,7 @@ func parseConfigMap(configMap *corev1.ConfigMap, withData bool) models.K8sConfig
 
 	if withData {
 		result.Data = configMap.Data
+		result.History = parseConfigurationHistory(configMap.Annotations)
 	}
 
 	return result
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
+
+	"github.com/segmentio/encoding/json"
+)
+
+const (
+	annotationPortainerKubeConfigHistory = "io.portainer.kubernetes.configuration.history"
+	// configurationHistoryMaxEntries is the number of changes kept in the history annotation
+	configurationHistoryMaxEntries = 10
+)
+
+var (
+	// ErrNamespaceAccessDenied is returned when a non-admin user edits a configuration in a namespace they cannot access
+	ErrNamespaceAccessDenied = errors.New("the user does not have access to the namespace")
+	// ErrConfigurationConflict is returned when a configuration was modified since the resource version of an update
+	ErrConfigurationConflict = errors.New("the configuration was modified since it was retrieved, reload it and try again")
+)
+
+// checkNamespaceAccess ensures that a non-admin user has access to the namespace according to the namespace access policies.
+func (kcl *KubeClient) checkNamespaceAccess(namespace string) error {
+	if kcl.IsKubeAdmin {
+		return nil
+	}
+
+	if _, ok := kcl.buildNonAdminNamespacesMap()[namespace]; !ok {
+		return ErrNamespaceAccessDenied
+	}
+
+	return nil
+}
+
+// diffConfigurationData returns the changes between the current and the updated data of a configuration, sorted by key.
+// when withValues is false, the values are left out of the changes (e.g. for secrets).
+func diffConfigurationData(current, updated map[string]string, withValues bool) []models.K8sConfigurationChange {
+	changes := []models.K8sConfigurationChange{}
+
+	for key, oldValue := range current {
+		newValue, ok := updated[key]
+		if !ok {
+			changes = append(changes, newConfigurationChange(key, models.K8sConfigurationChangeRemoved, oldValue, "", withValues))
+			continue
+		}
+
+		if oldValue != newValue {
+			changes = append(changes, newConfigurationChange(key, models.K8sConfigurationChangeModified, oldValue, newValue, withValues))
+		}
+	}
+
+	for key, newValue := range updated {
+		if _, ok := current[key]; !ok {
+			changes = append(changes, newConfigurationChange(key, models.K8sConfigurationChangeAdded, "", newValue, withValues))
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+func newConfigurationChange(key, changeType, oldValue, newValue string, withValues bool) models.K8sConfigurationChange {
+	change := models.K8sConfigurationChange{Key: key, Type: changeType}
+	if withValues {
+		change.OldValue = oldValue
+		change.NewValue = newValue
+	}
+
+	return change
+}
+
+// parseConfigurationHistory reads the change history stored in the annotations of a configuration.
+// an invalid history is ignored.
+func parseConfigurationHistory(annotations map[string]string) []models.K8sConfigurationHistoryEntry {
+	raw, ok := annotations[annotationPortainerKubeConfigHistory]
+	if !ok {
+		return nil
+	}
+
+	var history []models.K8sConfigurationHistoryEntry
+	if err := json.Unmarshal([]byte(raw), &history); err != nil {
+		return nil
+	}
+
+	return history
+}
+
+// appendConfigurationHistory returns a copy of the annotations where the changes are recorded in the change history.
+// the values of the changes are never recorded and only the latest configurationHistoryMaxEntries entries are kept.
+func appendConfigurationHistory(annotations map[string]string, username string, changes []models.K8sConfigurationChange) (map[string]string, error) {
+	result := make(map[string]string, len(annotations)+1)
+	for key, value := range annotations {
+		result[key] = value
+	}
+
+	if len(changes) == 0 {
+		return result, nil
+	}
+
+	entry := models.K8sConfigurationHistoryEntry{
+		Date:     time.Now().UTC().Format(time.RFC3339),
+		Username: username,
+		Changes:  make([]models.K8sConfigurationChange, len(changes)),
+	}
+	for i, change := range changes {
+		entry.Changes[i] = models.K8sConfigurationChange{Key: change.Key, Type: change.Type}
+	}
+
+	history := append(parseConfigurationHistory(annotations), entry)
+	if len(history) > configurationHistoryMaxEntries {
+		history = history[len(history)-configurationHistoryMaxEntries:]
+	}
+
+	raw, err := json.Marshal(history)
+	if err != nil {
+		return nil, err
+	}
+
+	result[annotationPortainerKubeConfigHistory] = string(raw)
+
+	return result, nil
+}
+
+// mergeLabels returns a copy of the current labels updated with the given labels
+func mergeLabels(current, labels map[string]string) map[string]string {
+	result := make(map[string]string, len(current)+len(labels))
+	for key, value := range current {
+		result[key] = value
+	}
+
+	for key, value := range labels {
+		result[key] = value
+	}
+
+	return result
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_diffConfigurationData(t *testing.T) {
+	current := map[string]string{"kept": "a", "modified": "b", "removed": "c"}
+	updated := map[string]string{"kept": "a", "modified": "B", "added": "d"}
+
+	changes := diffConfigurationData(current, updated, true)
+
+	expected := []models.K8sConfigurationChange{
+		{Key: "added", Type: models.K8sConfigurationChangeAdded, NewValue: "d"},
+		{Key: "modified", Type: models.K8sConfigurationChangeModified, OldValue: "b", NewValue: "B"},
+		{Key: "removed", Type: models.K8sConfigurationChangeRemoved, OldValue: "c"},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, changes)
+	}
+
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], changes[i])
+		}
+	}
+
+	for _, change := range diffConfigurationData(current, updated, false) {
+		if change.OldValue != "" || change.NewValue != "" {
+			t.Errorf("expected the values to be left out, got %v", change)
+		}
+	}
+}
+
+func Test_appendConfigurationHistory(t *testing.T) {
+	annotations := map[string]string{"keep": "me"}
+	changes := []models.K8sConfigurationChange{{Key: "k", Type: models.K8sConfigurationChangeModified, OldValue: "old", NewValue: "new"}}
+
+	for i := 0; i < configurationHistoryMaxEntries+2; i++ {
+		var err error
+		annotations, err = appendConfigurationHistory(annotations, "user"+strconv.Itoa(i), changes)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if annotations["keep"] != "me" {
+		t.Errorf("expected the other annotations to be kept, got %v", annotations)
+	}
+
+	history := parseConfigurationHistory(annotations)
+	if len(history) != configurationHistoryMaxEntries {
+		t.Fatalf("expected %d history entries, got %d", configurationHistoryMaxEntries, len(history))
+	}
+
+	last := history[len(history)-1]
+	if last.Username != "user"+strconv.Itoa(configurationHistoryMaxEntries+1) {
+		t.Errorf("expected the latest entry to be kept last, got %s", last.Username)
+	}
+
+	if last.Changes[0].OldValue != "" || last.Changes[0].NewValue != "" {
+		t.Errorf("expected the values to be left out of the history, got %v", last.Changes[0])
+	}
+}
+
+func Test_UpdateSecret(t *testing.T) {
+	newTestClient := func(isKubeAdmin bool, nonAdminNamespaces []string) *KubeClient {
+		return &KubeClient{
+			cli: kfake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default", ResourceVersion: "1"},
+				Data:       map[string][]byte{"password": []byte("old"), "user": []byte("admin")},
+			}),
+			instanceID:         "test",
+			IsKubeAdmin:        isKubeAdmin,
+			NonAdminNamespaces: nonAdminNamespaces,
+		}
+	}
+
+	t.Run("decodes base64 values and records the history", func(t *testing.T) {
+		k := newTestClient(true, nil)
+
+		secret, err := k.UpdateSecret("default", "creds", models.K8sSecretPayload{
+			Data:            map[string]string{"password": "bmV3"},
+			Base64Encoded:   true,
+			ResourceVersion: "1",
+		}, "admin")
+		if err != nil {
+			t.Fatalf("UpdateSecret should succeed; err=%s", err)
+		}
+
+		if len(secret.Data) != 1 || secret.Data["password"] != "new" {
+			t.Errorf("expected the decoded password only, got %v", secret.Data)
+		}
+
+		if len(secret.History) != 1 || len(secret.History[0].Changes) != 2 {
+			t.Fatalf("expected a single history entry with 2 changes, got %v", secret.History)
+		}
+	})
+
+	t.Run("fails when the resource version is outdated", func(t *testing.T) {
+		k := newTestClient(true, nil)
+
+		_, err := k.UpdateSecret("default", "creds", models.K8sSecretPayload{Data: map[string]string{}, ResourceVersion: "0"}, "admin")
+		if !errors.Is(err, ErrConfigurationConflict) {
+			t.Errorf("expected ErrConfigurationConflict, got %v", err)
+		}
+	})
+
+	t.Run("fails when the user cannot access the namespace", func(t *testing.T) {
+		k := newTestClient(false, []string{"other"})
+
+		_, err := k.UpdateSecret("default", "creds", models.K8sSecretPayload{Data: map[string]string{}, ResourceVersion: "1"}, "user")
+		if !errors.Is(err, ErrNamespaceAccessDenied) {
+			t.Errorf("expected ErrNamespaceAccessDenied, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	ErrDynamicClientUnavailable   = errors.New("the custom resources cannot be browsed with this client")
+	ErrInvalidCustomResourceScope = errors.New("the scope of the custom resource definition does not match the request")
+)
+
+var crdResource = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// GetCustomResourceDefinitions lists the custom resource definitions of the cluster, sorted by name
+func (kcl *KubeClient) GetCustomResourceDefinitions() ([]models.K8sCustomResourceDefinition, error) {
+	if kcl.dynamic == nil {
+		return nil, ErrDynamicClientUnavailable
+	}
+
+	list, err := kcl.dynamic.Resource(crdResource).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := make([]models.K8sCustomResourceDefinition, 0, len(list.Items))
+	for _, item := range list.Items {
+		definitions = append(definitions, parseCustomResourceDefinition(&item))
+	}
+
+	slices.SortFunc(definitions, func(a, b models.K8sCustomResourceDefinition) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return definitions, nil
+}
+
+// GetCustomResources lists a page of the resources of a custom resource definition. The namespace must be empty for
+// the cluster scoped resources, it can be empty for the namespaced resources of a cluster administrator to list the
+// resources of every namespace. limit is the maximum number of resources of the page, continueToken is the token
+// returned with the previous page.
+func (kcl *KubeClient) GetCustomResources(crdName, namespace string, limit int64, continueToken string) (models.K8sCustomResourceList, error) {
+	definition, err := kcl.getCustomResourceDefinition(crdName, namespace)
+	if err != nil {
+		return models.K8sCustomResourceList{}, err
+	}
+
+	list, err := kcl.dynamic.Resource(customResourceGVR(definition)).Namespace(namespace).List(context.TODO(), metav1.ListOptions{
+		Limit:    limit,
+		Continue: continueToken,
+	})
+	if err != nil {
+		return models.K8sCustomResourceList{}, err
+	}
+
+	result := models.K8sCustomResourceList{
+		Items:              make([]models.K8sCustomResource, 0, len(list.Items)),
+		Continue:           list.GetContinue(),
+		RemainingItemCount: list.GetRemainingItemCount(),
+	}
+
+	for _, item := range list.Items {
+		result.Items = append(result.Items, parseCustomResource(&item))
+	}
+
+	return result, nil
+}
+
+// GetCustomResource returns a resource of a custom resource definition along with its full content
+func (kcl *KubeClient) GetCustomResource(crdName, namespace, name string) (models.K8sCustomResource, error) {
+	definition, err := kcl.getCustomResourceDefinition(crdName, namespace)
+	if err != nil {
+		return models.K8sCustomResource{}, err
+	}
+
+	if namespace == "" && definition.Scope == "Namespaced" {
+		return models.K8sCustomResource{}, ErrInvalidCustomResourceScope
+	}
+
+	item, err := kcl.dynamic.Resource(customResourceGVR(definition)).Namespace(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return models.K8sCustomResource{}, err
+	}
+
+	resource := parseCustomResource(item)
+	resource.Manifest = item.Object
+
+	return resource, nil
+}
+
+// getCustomResourceDefinition retrieves a custom resource definition and checks that the user can browse its
+// resources in the namespace
+func (kcl *KubeClient) getCustomResourceDefinition(crdName, namespace string) (models.K8sCustomResourceDefinition, error) {
+	if kcl.dynamic == nil {
+		return models.K8sCustomResourceDefinition{}, ErrDynamicClientUnavailable
+	}
+
+	item, err := kcl.dynamic.Resource(crdResource).Get(context.TODO(), crdName, metav1.GetOptions{})
+	if err != nil {
+		return models.K8sCustomResourceDefinition{}, err
+	}
+
+	definition := parseCustomResourceDefinition(item)
+
+	switch {
+	case definition.PreferredVersion == "":
+		return models.K8sCustomResourceDefinition{}, apierrors.NewNotFound(schema.GroupResource{Group: definition.Group, Resource: definition.Plural}, crdName)
+	case definition.Scope == "Cluster" && namespace != "":
+		return models.K8sCustomResourceDefinition{}, ErrInvalidCustomResourceScope
+	case namespace != "":
+		if err := kcl.checkNamespaceAccess(namespace); err != nil {
+			return models.K8sCustomResourceDefinition{}, err
+		}
+	case definition.Scope == "Namespaced" && !kcl.IsKubeAdmin:
+		return models.K8sCustomResourceDefinition{}, ErrNamespaceAccessDenied
+	}
+
+	return definition, nil
+}
+
+func customResourceGVR(definition models.K8sCustomResourceDefinition) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: definition.Group, Version: definition.PreferredVersion, Resource: definition.Plural}
+}
+
+// parseCustomResourceDefinition parses an unstructured CustomResourceDefinition object, the apiextensions types are
+// not used to avoid depending on the API server module
+func parseCustomResourceDefinition(item *unstructured.Unstructured) models.K8sCustomResourceDefinition {
+	definition := models.K8sCustomResourceDefinition{
+		Name:         item.GetName(),
+		Versions:     []string{},
+		CreationDate: item.GetCreationTimestamp().Time,
+	}
+
+	definition.Group, _, _ = unstructured.NestedString(item.Object, "spec", "group")
+	definition.Kind, _, _ = unstructured.NestedString(item.Object, "spec", "names", "kind")
+	definition.Plural, _, _ = unstructured.NestedString(item.Object, "spec", "names", "plural")
+	definition.Scope, _, _ = unstructured.NestedString(item.Object, "spec", "scope")
+
+	versions, _, _ := unstructured.NestedSlice(item.Object, "spec", "versions")
+	for _, v := range versions {
+		version, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		name, _, _ := unstructured.NestedString(version, "name")
+		served, _, _ := unstructured.NestedBool(version, "served")
+		storage, _, _ := unstructured.NestedBool(version, "storage")
+
+		if !served {
+			continue
+		}
+
+		definition.Versions = append(definition.Versions, name)
+
+		if storage || definition.PreferredVersion == "" {
+			definition.PreferredVersion = name
+		}
+	}
+
+	return definition
+}
+
+func parseCustomResource(item *unstructured.Unstructured) models.K8sCustomResource {
+	return models.K8sCustomResource{
+		Name:         item.GetName(),
+		Namespace:    item.GetNamespace(),
+		UID:          string(item.GetUID()),
+		Kind:         item.GetKind(),
+		APIVersion:   item.GetAPIVersion(),
+		Labels:       item.GetLabels(),
+		CreationDate: item.GetCreationTimestamp().Time,
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func newTestCustomResourceClient(isKubeAdmin bool, nonAdminNamespaces []string) *KubeClient {
+	certificates := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+	crd := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "apiextensions.k8s.io/v1",
+		"kind":       "CustomResourceDefinition",
+		"metadata":   map[string]any{"name": "certificates.cert-manager.io"},
+		"spec": map[string]any{
+			"group": "cert-manager.io",
+			"scope": "Namespaced",
+			"names": map[string]any{"kind": "Certificate", "plural": "certificates"},
+			"versions": []any{
+				map[string]any{"name": "v1alpha2", "served": false, "storage": false},
+				map[string]any{"name": "v1", "served": true, "storage": true},
+			},
+		},
+	}}
+
+	newCertificate := func(namespace, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "cert-manager.io/v1",
+			"kind":       "Certificate",
+			"metadata":   map[string]any{"name": name, "namespace": namespace},
+			"spec":       map[string]any{"secretName": name + "-tls"},
+		}}
+	}
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			crdResource:  "CustomResourceDefinitionList",
+			certificates: "CertificateList",
+		},
+		crd,
+		newCertificate("default", "web"),
+		newCertificate("private", "db"),
+	)
+
+	return &KubeClient{
+		dynamic:            dynamicClient,
+		instanceID:         "test",
+		IsKubeAdmin:        isKubeAdmin,
+		NonAdminNamespaces: nonAdminNamespaces,
+	}
+}
+
+func Test_GetCustomResourceDefinitions(t *testing.T) {
+	kcl := newTestCustomResourceClient(true, nil)
+
+	definitions, err := kcl.GetCustomResourceDefinitions()
+	require.NoError(t, err)
+	require.Len(t, definitions, 1)
+
+	assert.Equal(t, "certificates.cert-manager.io", definitions[0].Name)
+	assert.Equal(t, "Certificate", definitions[0].Kind)
+	assert.Equal(t, "Namespaced", definitions[0].Scope)
+	assert.Equal(t, []string{"v1"}, definitions[0].Versions)
+	assert.Equal(t, "v1", definitions[0].PreferredVersion)
+}
+
+func Test_GetCustomResources(t *testing.T) {
+	t.Run("a cluster administrator lists the resources of every namespace", func(t *testing.T) {
+		kcl := newTestCustomResourceClient(true, nil)
+
+		resources, err := kcl.GetCustomResources("certificates.cert-manager.io", "", 100, "")
+		require.NoError(t, err)
+		assert.Len(t, resources.Items, 2)
+	})
+
+	t.Run("a user lists the resources of the namespaces the user has access to", func(t *testing.T) {
+		kcl := newTestCustomResourceClient(false, []string{"default"})
+
+		resources, err := kcl.GetCustomResources("certificates.cert-manager.io", "default", 100, "")
+		require.NoError(t, err)
+		require.Len(t, resources.Items, 1)
+		assert.Equal(t, "web", resources.Items[0].Name)
+
+		_, err = kcl.GetCustomResources("certificates.cert-manager.io", "private", 100, "")
+		require.ErrorIs(t, err, ErrNamespaceAccessDenied)
+
+		_, err = kcl.GetCustomResources("certificates.cert-manager.io", "", 100, "")
+		require.ErrorIs(t, err, ErrNamespaceAccessDenied)
+	})
+
+	t.Run("an unknown custom resource definition is not found", func(t *testing.T) {
+		kcl := newTestCustomResourceClient(true, nil)
+
+		_, err := kcl.GetCustomResources("applications.argoproj.io", "default", 100, "")
+		require.True(t, k8serrors.IsNotFound(err))
+	})
+}
+
+func Test_GetCustomResource(t *testing.T) {
+	kcl := newTestCustomResourceClient(false, []string{"default"})
+
+	resource, err := kcl.GetCustomResource("certificates.cert-manager.io", "default", "web")
+	require.NoError(t, err)
+	assert.Equal(t, "Certificate", resource.Kind)
+	assert.Equal(t, "cert-manager.io/v1", resource.APIVersion)
+
+	secretName, _, _ := unstructured.NestedString(resource.Manifest, "spec", "secretName")
+	assert.Equal(t, "web-tls", secretName)
+
+	_, err = kcl.GetCustomResource("certificates.cert-manager.io", "", "web")
+	require.Error(t, err)
+}
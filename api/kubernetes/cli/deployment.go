@@ -2,11 +2,29 @@ package cli
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
+	portainer "github.com/portainer/portainer/api"
+
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	labels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// deploymentRevisionAnnotation is set by the deployment controller on the deployments and their replicasets
+	deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+	// restartedAtAnnotation is the pod template annotation set by kubectl rollout restart
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
 )
 
+// ErrDeploymentRevisionNotFound is returned when a deployment is rolled back to a revision it does not have
+var ErrDeploymentRevisionNotFound = errors.New("unable to find the revision of the deployment")
+
 // HasStackName checks whether the given name is used in the given namespace.
 func (kcl *KubeClient) HasStackName(namespace string, stackName string) (bool, error) {
 	querySet := labels.Set{"io.portainer.kubernetes.application.stack": stackName}
@@ -20,3 +38,206 @@ func (kcl *KubeClient) HasStackName(namespace string, stackName string) (bool, e
 	}
 	return true, nil
 }
+
+// GetStackRolloutStatus returns the rollout status of the deployments, statefulsets and daemonsets of a stack,
+// in the same way as kubectl rollout status.
+func (kcl *KubeClient) GetStackRolloutStatus(ctx context.Context, stackID int) ([]portainer.StackServiceHealth, error) {
+	querySet := labels.Set{"io.portainer.kubernetes.application.stackid": strconv.Itoa(stackID)}
+	listOpts := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(querySet).String()}
+
+	services := []portainer.StackServiceHealth{}
+
+	deployments, err := kcl.cli.AppsV1().Deployments("").List(ctx, listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the deployments of the stack: %w", err)
+	}
+
+	for _, d := range deployments.Items {
+		services = append(services, deploymentRolloutStatus(d))
+	}
+
+	statefulSets, err := kcl.cli.AppsV1().StatefulSets("").List(ctx, listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the statefulsets of the stack: %w", err)
+	}
+
+	for _, s := range statefulSets.Items {
+		services = append(services, statefulSetRolloutStatus(s))
+	}
+
+	daemonSets, err := kcl.cli.AppsV1().DaemonSets("").List(ctx, listOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the daemonsets of the stack: %w", err)
+	}
+
+	for _, d := range daemonSets.Items {
+		services = append(services, daemonSetRolloutStatus(d))
+	}
+
+	return services, nil
+}
+
+func deploymentRolloutStatus(d appsv1.Deployment) portainer.StackServiceHealth {
+	health := portainer.StackServiceHealth{Name: d.Namespace + "/deployment/" + d.Name, Status: portainer.StackServiceStarting}
+
+	for _, condition := range d.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			health.Status = portainer.StackServiceUnhealthy
+			health.Message = condition.Message
+
+			return health
+		}
+	}
+
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+
+	switch {
+	case d.Status.ObservedGeneration < d.Generation:
+		health.Message = "Waiting for the deployment spec update to be observed"
+	case d.Status.UpdatedReplicas < replicas:
+		health.Message = fmt.Sprintf("%d of %d new replicas have been updated", d.Status.UpdatedReplicas, replicas)
+	case d.Status.Replicas > d.Status.UpdatedReplicas:
+		health.Message = fmt.Sprintf("%d old replicas are pending termination", d.Status.Replicas-d.Status.UpdatedReplicas)
+	case d.Status.AvailableReplicas < d.Status.UpdatedReplicas:
+		health.Message = fmt.Sprintf("%d of %d updated replicas are available", d.Status.AvailableReplicas, d.Status.UpdatedReplicas)
+	default:
+		health.Status = portainer.StackServiceHealthy
+	}
+
+	return health
+}
+
+func statefulSetRolloutStatus(s appsv1.StatefulSet) portainer.StackServiceHealth {
+	health := portainer.StackServiceHealth{Name: s.Namespace + "/statefulset/" + s.Name, Status: portainer.StackServiceStarting}
+
+	replicas := int32(1)
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+
+	switch {
+	case s.Status.ObservedGeneration == 0 || s.Status.ObservedGeneration < s.Generation:
+		health.Message = "Waiting for the statefulset spec update to be observed"
+	case s.Status.ReadyReplicas < replicas:
+		health.Message = fmt.Sprintf("%d of %d replicas are ready", s.Status.ReadyReplicas, replicas)
+	case s.Spec.UpdateStrategy.Type == appsv1.RollingUpdateStatefulSetStrategyType && s.Status.UpdateRevision != s.Status.CurrentRevision:
+		health.Message = fmt.Sprintf("%d of %d replicas are updated", s.Status.UpdatedReplicas, replicas)
+	default:
+		health.Status = portainer.StackServiceHealthy
+	}
+
+	return health
+}
+
+func daemonSetRolloutStatus(d appsv1.DaemonSet) portainer.StackServiceHealth {
+	health := portainer.StackServiceHealth{Name: d.Namespace + "/daemonset/" + d.Name, Status: portainer.StackServiceStarting}
+
+	switch {
+	case d.Status.ObservedGeneration < d.Generation:
+		health.Message = "Waiting for the daemonset spec update to be observed"
+	case d.Status.UpdatedNumberScheduled < d.Status.DesiredNumberScheduled:
+		health.Message = fmt.Sprintf("%d of %d updated pods are scheduled", d.Status.UpdatedNumberScheduled, d.Status.DesiredNumberScheduled)
+	case d.Status.NumberAvailable < d.Status.DesiredNumberScheduled:
+		health.Message = fmt.Sprintf("%d of %d updated pods are available", d.Status.NumberAvailable, d.Status.DesiredNumberScheduled)
+	default:
+		health.Status = portainer.StackServiceHealthy
+	}
+
+	return health
+}
+
+// RestartDeployment restarts the pods of a deployment, in the same way as kubectl rollout restart.
+func (kcl *KubeClient) RestartDeployment(namespace, name string) error {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return err
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`, restartedAtAnnotation, time.Now().Format(time.RFC3339))
+
+	_, err := kcl.cli.AppsV1().Deployments(namespace).Patch(context.TODO(), name, types.StrategicMergePatchType, []byte(patch), metav1.PatchOptions{})
+
+	return err
+}
+
+// ScaleDeployment sets the number of replicas of a deployment.
+func (kcl *KubeClient) ScaleDeployment(namespace, name string, replicas int32) error {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return err
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)
+
+	_, err := kcl.cli.AppsV1().Deployments(namespace).Patch(context.TODO(), name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+
+	return err
+}
+
+// RollbackDeployment restores the pod template of a previous revision of a deployment, in the same way as
+// kubectl rollout undo. The revision 0 stands for the revision preceding the current one.
+func (kcl *KubeClient) RollbackDeployment(namespace, name string, revision int64) error {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return err
+	}
+
+	deployment, err := kcl.cli.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return err
+	}
+
+	replicaSets, err := kcl.cli.AppsV1().ReplicaSets(namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+
+	currentRevision := objectRevision(deployment.ObjectMeta)
+
+	var target *appsv1.ReplicaSet
+	var targetRevision int64
+
+	for i := range replicaSets.Items {
+		replicaSet := &replicaSets.Items[i]
+		if !metav1.IsControlledBy(replicaSet, deployment) {
+			continue
+		}
+
+		replicaSetRevision := objectRevision(replicaSet.ObjectMeta)
+
+		if revision != 0 && replicaSetRevision == revision {
+			target = replicaSet
+
+			break
+		}
+
+		if revision == 0 && replicaSetRevision < currentRevision && replicaSetRevision > targetRevision {
+			target, targetRevision = replicaSet, replicaSetRevision
+		}
+	}
+
+	if target == nil {
+		return ErrDeploymentRevisionNotFound
+	}
+
+	template := target.Spec.Template.DeepCopy()
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+	deployment.Spec.Template = *template
+
+	_, err = kcl.cli.AppsV1().Deployments(namespace).Update(context.TODO(), deployment, metav1.UpdateOptions{})
+
+	return err
+}
+
+// objectRevision returns the revision set by the deployment controller on a deployment or a replicaset, 0 when it is missing
+func objectRevision(meta metav1.ObjectMeta) int64 {
+	revision, _ := strconv.ParseInt(meta.Annotations[deploymentRevisionAnnotation], 10, 64)
+
+	return revision
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_GetStackRolloutStatus(t *testing.T) {
+	replicas := int32(2)
+	stackLabels := map[string]string{"io.portainer.kubernetes.application.stackid": "7"}
+
+	kcl := &KubeClient{
+		cli: kfake.NewSimpleClientset(
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Generation: 2, Labels: stackLabels},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", Generation: 1, Labels: stackLabels},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+				Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 1},
+			},
+			&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default", Labels: map[string]string{"io.portainer.kubernetes.application.stackid": "8"}},
+			},
+			&appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "data", Generation: 1, Labels: stackLabels},
+				Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+				Status:     appsv1.StatefulSetStatus{ObservedGeneration: 1, ReadyReplicas: 2, UpdatedReplicas: 2},
+			},
+		),
+		instanceID: "instance",
+	}
+
+	services, err := kcl.GetStackRolloutStatus(context.Background(), 7)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []portainer.StackServiceHealth{
+		{Name: "default/deployment/web", Status: portainer.StackServiceHealthy},
+		{Name: "default/deployment/api", Status: portainer.StackServiceStarting, Message: "1 of 2 updated replicas are available"},
+		{Name: "data/statefulset/db", Status: portainer.StackServiceHealthy},
+	}, services)
+}
+
+func Test_DeploymentRolloutActions(t *testing.T) {
+	replicas := int32(1)
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", UID: "deployment-uid", Annotations: map[string]string{deploymentRevisionAnnotation: "3"}},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: selector,
+			Template: podTemplate("nginx:1.27", "c"),
+		},
+	}
+
+	replicaSet := func(name, image, revision string) *appsv1.ReplicaSet {
+		controller := true
+
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "default",
+				Labels:          map[string]string{"app": "web"},
+				Annotations:     map[string]string{deploymentRevisionAnnotation: revision},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Deployment", Name: "web", UID: "deployment-uid", Controller: &controller}},
+			},
+			Spec: appsv1.ReplicaSetSpec{Selector: selector, Template: podTemplate(image, name)},
+		}
+	}
+
+	kcl := &KubeClient{
+		cli: kfake.NewSimpleClientset(
+			deployment,
+			replicaSet("a", "nginx:1.25", "1"),
+			replicaSet("b", "nginx:1.26", "2"),
+			replicaSet("c", "nginx:1.27", "3"),
+		),
+		instanceID:  "instance",
+		IsKubeAdmin: true,
+	}
+
+	getDeployment := func() *appsv1.Deployment {
+		d, err := kcl.cli.AppsV1().Deployments("default").Get(context.Background(), "web", metav1.GetOptions{})
+		require.NoError(t, err)
+
+		return d
+	}
+
+	require.NoError(t, kcl.RestartDeployment("default", "web"))
+	assert.NotEmpty(t, getDeployment().Spec.Template.Annotations[restartedAtAnnotation])
+
+	require.NoError(t, kcl.ScaleDeployment("default", "web", 3))
+	assert.Equal(t, int32(3), *getDeployment().Spec.Replicas)
+
+	// The revision preceding the current one
+	require.NoError(t, kcl.RollbackDeployment("default", "web", 0))
+	d := getDeployment()
+	assert.Equal(t, "nginx:1.26", d.Spec.Template.Spec.Containers[0].Image)
+	assert.NotContains(t, d.Spec.Template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+	require.NoError(t, kcl.RollbackDeployment("default", "web", 1))
+	assert.Equal(t, "nginx:1.25", getDeployment().Spec.Template.Spec.Containers[0].Image)
+
+	assert.ErrorIs(t, kcl.RollbackDeployment("default", "web", 7), ErrDeploymentRevisionNotFound)
+
+	kcl.IsKubeAdmin = false
+	assert.ErrorIs(t, kcl.ScaleDeployment("default", "web", 0), ErrNamespaceAccessDenied)
+}
+
+func podTemplate(image, podTemplateHash string) corev1.PodTemplateSpec {
+	return corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "web", appsv1.DefaultDeploymentUniqueLabelKey: podTemplateHash}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", Image: image}}},
+	}
+}
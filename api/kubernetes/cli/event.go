@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"context"
+	"slices"
+
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// GetEvents gets the events of a namespace, sorted from the oldest to the most recent.
+// When resource is not empty, only the events of the resources with this name are returned (e.g. a pod or a deployment).
+// When namespace is empty, the events of all the namespaces the user has access to are returned.
+func (kcl *KubeClient) GetEvents(namespace, resource string) ([]models.K8sEvent, error) {
+	if namespace != "" {
+		if err := kcl.checkNamespaceAccess(namespace); err != nil {
+			return nil, err
+		}
+	} else if !kcl.IsKubeAdmin && len(kcl.NonAdminNamespaces) == 0 {
+		return []models.K8sEvent{}, nil
+	}
+
+	options := metav1.ListOptions{}
+	if resource != "" {
+		options.FieldSelector = fields.OneTermEqualSelector("involvedObject.name", resource).String()
+	}
+
+	events, err := kcl.cli.CoreV1().Events(namespace).List(context.TODO(), options)
+	if err != nil {
+		return nil, err
+	}
+
+	nonAdminNamespaceSet := kcl.buildNonAdminNamespacesMap()
+
+	results := make([]models.K8sEvent, 0, len(events.Items))
+	for _, event := range events.Items {
+		if !kcl.IsKubeAdmin {
+			if _, ok := nonAdminNamespaceSet[event.Namespace]; !ok {
+				continue
+			}
+		}
+
+		results = append(results, parseEvent(&event))
+	}
+
+	slices.SortStableFunc(results, func(a, b models.K8sEvent) int {
+		return a.LastTimestamp.Compare(b.LastTimestamp)
+	})
+
+	return results, nil
+}
+
+// parseEvent parses a k8s Event object into a K8sEvent struct.
+// The recent events only set the event time while the older ones only set the first and last timestamps.
+func parseEvent(event *corev1.Event) models.K8sEvent {
+	firstTimestamp := event.FirstTimestamp.Time
+	if firstTimestamp.IsZero() {
+		firstTimestamp = event.EventTime.Time
+	}
+
+	lastTimestamp := event.LastTimestamp.Time
+	if event.Series != nil && event.Series.LastObservedTime.After(lastTimestamp) {
+		lastTimestamp = event.Series.LastObservedTime.Time
+	}
+
+	if lastTimestamp.IsZero() {
+		lastTimestamp = firstTimestamp
+	}
+
+	count := event.Count
+	if event.Series != nil && event.Series.Count > count {
+		count = event.Series.Count
+	}
+
+	return models.K8sEvent{
+		UID:            string(event.UID),
+		Type:           event.Type,
+		Reason:         event.Reason,
+		Message:        event.Message,
+		Namespace:      event.Namespace,
+		Count:          max(count, 1),
+		FirstTimestamp: firstTimestamp,
+		LastTimestamp:  lastTimestamp,
+		InvolvedObject: models.K8sEventInvolvedObject{
+			Kind: event.InvolvedObject.Kind,
+			Name: event.InvolvedObject.Name,
+			UID:  string(event.InvolvedObject.UID),
+		},
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_GetEvents(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	newTestClient := func(isKubeAdmin bool, nonAdminNamespaces []string) *KubeClient {
+		return &KubeClient{
+			cli: kfake.NewSimpleClientset(
+				&corev1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: "web.2", Namespace: "default"},
+					InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web"},
+					Reason:         "BackOff",
+					Count:          3,
+					LastTimestamp:  metav1.NewTime(now),
+				},
+				&corev1.Event{
+					ObjectMeta:     metav1.ObjectMeta{Name: "web.1", Namespace: "default"},
+					InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "web"},
+					Reason:         "Pulled",
+					EventTime:      metav1.NewMicroTime(now.Add(-time.Minute)),
+				},
+				&corev1.Event{
+					ObjectMeta: metav1.ObjectMeta{Name: "db.1", Namespace: "private"},
+					Reason:     "Scheduled",
+				},
+			),
+			instanceID:         "test",
+			IsKubeAdmin:        isKubeAdmin,
+			NonAdminNamespaces: nonAdminNamespaces,
+		}
+	}
+
+	t.Run("returns the events sorted from the oldest", func(t *testing.T) {
+		k := newTestClient(true, nil)
+
+		events, err := k.GetEvents("default", "")
+		if err != nil {
+			t.Fatalf("GetEvents should succeed; err=%s", err)
+		}
+
+		if len(events) != 2 || events[0].Reason != "Pulled" || events[1].Reason != "BackOff" {
+			t.Fatalf("expected the Pulled then BackOff events, got %v", events)
+		}
+
+		if !events[0].LastTimestamp.Equal(now.Add(-time.Minute)) || events[0].Count != 1 {
+			t.Errorf("expected the event time to be used as the timestamp, got %v", events[0])
+		}
+
+		if events[1].Count != 3 || events[1].InvolvedObject.Name != "web" {
+			t.Errorf("unexpected event %v", events[1])
+		}
+	})
+
+	t.Run("only returns the events of the namespaces the user has access to", func(t *testing.T) {
+		k := newTestClient(false, []string{"default"})
+
+		events, err := k.GetEvents("", "")
+		if err != nil {
+			t.Fatalf("GetEvents should succeed; err=%s", err)
+		}
+
+		if len(events) != 2 {
+			t.Errorf("expected the events of the default namespace only, got %v", events)
+		}
+
+		if _, err := k.GetEvents("private", ""); !errors.Is(err, ErrNamespaceAccessDenied) {
+			t.Errorf("expected ErrNamespaceAccessDenied, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math"
+	"time"
+
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultCertificateExpiryWarning is the period before the expiry of a certificate during which it is reported as expiring soon
+const DefaultCertificateExpiryWarning = 30 * 24 * time.Hour
+
+var errNoCertificate = errors.New("no PEM encoded certificate found")
+
+// CombineIngressesWithCertificates resolves the TLS secrets referenced by the ingresses and parses their certificates.
+// the secrets that cannot be read or do not hold a valid certificate are left unresolved.
+func (kcl *KubeClient) CombineIngressesWithCertificates(ingresses []models.K8sIngressInfo, expiryWarning time.Duration) []models.K8sIngressInfo {
+	certificates := make(map[string]*models.K8sCertificate)
+	now := time.Now()
+
+	for i, ingress := range ingresses {
+		for j, tls := range ingress.TLS {
+			if tls.SecretName == "" {
+				continue
+			}
+
+			key := ingress.Namespace + "/" + tls.SecretName
+			certificate, ok := certificates[key]
+			if !ok {
+				certificate = kcl.fetchSecretCertificate(ingress.Namespace, tls.SecretName, now, expiryWarning)
+				certificates[key] = certificate
+			}
+
+			ingresses[i].TLS[j].Certificate = certificate
+		}
+	}
+
+	return ingresses
+}
+
+// GetIngressCertificates returns the certificates referenced by the TLS configuration of all the ingresses of the cluster
+func (kcl *KubeClient) GetIngressCertificates(expiryWarning time.Duration) ([]models.K8sIngressCertificate, error) {
+	ingresses, err := kcl.GetIngresses("")
+	if err != nil {
+		return nil, err
+	}
+
+	results := []models.K8sIngressCertificate{}
+	for _, ingress := range kcl.CombineIngressesWithCertificates(ingresses, expiryWarning) {
+		for _, tls := range ingress.TLS {
+			if tls.Certificate == nil {
+				continue
+			}
+
+			results = append(results, models.K8sIngressCertificate{
+				Namespace:   ingress.Namespace,
+				IngressName: ingress.Name,
+				SecretName:  tls.SecretName,
+				Hosts:       tls.Hosts,
+				Certificate: *tls.Certificate,
+			})
+		}
+	}
+
+	return results, nil
+}
+
+func (kcl *KubeClient) fetchSecretCertificate(namespace, secretName string, now time.Time, expiryWarning time.Duration) *models.K8sCertificate {
+	secret, err := kcl.cli.CoreV1().Secrets(namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+	if err != nil {
+		log.Debug().Err(err).Str("namespace", namespace).Str("secret", secretName).Msg("unable to retrieve the TLS secret of an ingress")
+		return nil
+	}
+
+	certificate, err := parseCertificate(secret.Data[corev1.TLSCertKey], now, expiryWarning)
+	if err != nil {
+		log.Debug().Err(err).Str("namespace", namespace).Str("secret", secretName).Msg("unable to parse the certificate of a TLS secret")
+		return nil
+	}
+
+	return certificate
+}
+
+// parseCertificate parses the first certificate of a PEM encoded chain, which is the leaf certificate
+func parseCertificate(data []byte, now time.Time, expiryWarning time.Duration) (*models.K8sCertificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, errNoCertificate
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		remaining := certificate.NotAfter.Sub(now)
+
+		return &models.K8sCertificate{
+			Subject:         certificate.Subject.CommonName,
+			Issuer:          certificate.Issuer.CommonName,
+			DNSNames:        certificate.DNSNames,
+			NotBefore:       certificate.NotBefore,
+			NotAfter:        certificate.NotAfter,
+			DaysUntilExpiry: int(math.Floor(remaining.Hours() / 24)),
+			Expired:         remaining <= 0,
+			ExpiringSoon:    remaining <= expiryWarning,
+		}, nil
+	}
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func generateCertificatePEM(t *testing.T, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func Test_parseCertificate(t *testing.T) {
+	now := time.Now()
+
+	certificate, err := parseCertificate(generateCertificatePEM(t, now.Add(10*24*time.Hour+time.Hour)), now, DefaultCertificateExpiryWarning)
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", certificate.Subject)
+	assert.Equal(t, "example.com", certificate.Issuer)
+	assert.Equal(t, 10, certificate.DaysUntilExpiry)
+	assert.True(t, certificate.ExpiringSoon)
+	assert.False(t, certificate.Expired)
+
+	certificate, err = parseCertificate(generateCertificatePEM(t, now.Add(-time.Hour)), now, DefaultCertificateExpiryWarning)
+	require.NoError(t, err)
+	assert.True(t, certificate.Expired)
+
+	certificate, err = parseCertificate(generateCertificatePEM(t, now.Add(60*24*time.Hour)), now, DefaultCertificateExpiryWarning)
+	require.NoError(t, err)
+	assert.False(t, certificate.ExpiringSoon)
+
+	_, err = parseCertificate([]byte("not a certificate"), now, DefaultCertificateExpiryWarning)
+	assert.ErrorIs(t, err, errNoCertificate)
+}
+
+func Test_GetIngressCertificates(t *testing.T) {
+	kcl := &KubeClient{
+		cli: kfake.NewSimpleClientset(
+			&netv1.Ingress{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+				Spec: netv1.IngressSpec{
+					TLS: []netv1.IngressTLS{
+						{Hosts: []string{"example.com"}, SecretName: "web-tls"},
+						{Hosts: []string{"missing.com"}, SecretName: "missing-tls"},
+					},
+				},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-tls", Namespace: "default"},
+				Type:       corev1.SecretTypeTLS,
+				Data:       map[string][]byte{corev1.TLSCertKey: generateCertificatePEM(t, time.Now().Add(5*24*time.Hour))},
+			},
+		),
+		instanceID:  "test",
+		IsKubeAdmin: true,
+	}
+
+	certificates, err := kcl.GetIngressCertificates(DefaultCertificateExpiryWarning)
+	require.NoError(t, err)
+	require.Len(t, certificates, 1)
+
+	expected := models.K8sIngressCertificate{
+		Namespace:   "default",
+		IngressName: "web",
+		SecretName:  "web-tls",
+		Hosts:       []string{"example.com"},
+	}
+	certificate := certificates[0]
+	assert.True(t, certificate.Certificate.ExpiringSoon)
+	certificate.Certificate = models.K8sCertificate{}
+	assert.Equal(t, expected, certificate)
+}
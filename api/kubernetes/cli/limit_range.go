@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const limitRangePrefix = "portainer-lr-"
+
+// GetLimitRanges gets the limit ranges of a namespace, or of all the namespaces when namespace is empty.
+// The limit ranges of a non-admin user are filtered to the namespaces the user has access to.
+func (kcl *KubeClient) GetLimitRanges(namespace string) ([]corev1.LimitRange, error) {
+	limitRanges, err := kcl.cli.CoreV1().LimitRanges(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if kcl.IsKubeAdmin {
+		return limitRanges.Items, nil
+	}
+
+	nonAdminNamespaceSet := kcl.buildNonAdminNamespacesMap()
+	results := []corev1.LimitRange{}
+	for _, limitRange := range limitRanges.Items {
+		if _, exists := nonAdminNamespaceSet[limitRange.Namespace]; exists {
+			results = append(results, limitRange)
+		}
+	}
+
+	return results, nil
+}
+
+// GetPortainerLimitRange gets the limit range managed by Portainer in a namespace, it is prefixed with "portainer-lr-".
+func (kcl *KubeClient) GetPortainerLimitRange(namespace string) (*corev1.LimitRange, error) {
+	return kcl.cli.CoreV1().LimitRanges(namespace).Get(context.TODO(), limitRangePrefix+namespace, metav1.GetOptions{})
+}
+
+// CreateLimitRange creates the Portainer limit range of a namespace, setting the default resources of its containers
+func (kcl *KubeClient) CreateLimitRange(namespace string, limitRange models.K8sLimitRange, labels map[string]string) (*corev1.LimitRange, error) {
+	lr := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      limitRangePrefix + namespace,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: buildLimitRangeSpec(limitRange),
+	}
+
+	return kcl.cli.CoreV1().LimitRanges(namespace).Create(context.TODO(), lr, metav1.CreateOptions{})
+}
+
+// UpdateLimitRange updates the Portainer limit range of a namespace, the limit range is created when missing
+func (kcl *KubeClient) UpdateLimitRange(namespace string, limitRange models.K8sLimitRange) (*corev1.LimitRange, error) {
+	lr, err := kcl.GetPortainerLimitRange(namespace)
+	if k8serrors.IsNotFound(err) {
+		return kcl.CreateLimitRange(namespace, limitRange, nil)
+	} else if err != nil {
+		return nil, err
+	}
+
+	lr.Spec = buildLimitRangeSpec(limitRange)
+
+	return kcl.cli.CoreV1().LimitRanges(namespace).Update(context.TODO(), lr, metav1.UpdateOptions{})
+}
+
+// DeleteLimitRange removes the Portainer limit range of a namespace, if any
+func (kcl *KubeClient) DeleteLimitRange(namespace string) error {
+	err := kcl.cli.CoreV1().LimitRanges(namespace).Delete(context.TODO(), limitRangePrefix+namespace, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func buildLimitRangeSpec(limitRange models.K8sLimitRange) corev1.LimitRangeSpec {
+	item := corev1.LimitRangeItem{
+		Type:           corev1.LimitTypeContainer,
+		Default:        corev1.ResourceList{},
+		DefaultRequest: corev1.ResourceList{},
+	}
+
+	for _, value := range []struct {
+		name           corev1.ResourceName
+		limit, request string
+	}{
+		{corev1.ResourceCPU, limitRange.DefaultCPU, limitRange.DefaultRequestCPU},
+		{corev1.ResourceMemory, limitRange.DefaultMemory, limitRange.DefaultRequestMemory},
+	} {
+		limit, err := resource.ParseQuantity(value.limit)
+		if err != nil {
+			continue
+		}
+
+		item.Default[value.name] = limit
+		item.DefaultRequest[value.name] = limit
+
+		if request, err := resource.ParseQuantity(value.request); err == nil {
+			item.DefaultRequest[value.name] = request
+		}
+	}
+
+	return corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{item}}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -16,7 +17,6 @@ import (
 	"github.com/rs/zerolog/log"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -45,6 +45,38 @@ func (kcl *KubeClient) GetNamespaces() (map[string]portainer.K8sNamespaceInfo, e
 	return kcl.fetchNamespacesForNonAdmin()
 }
 
+// GetUserNamespaceNames returns the sorted names of the namespaces a user can access, every namespace for an
+// administrator. It must be called with a privileged client.
+func (kcl *KubeClient) GetUserNamespaceNames(userID int, isAdmin bool, restrictDefaultNamespace bool) ([]string, error) {
+	namespaces, err := kcl.fetchNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := map[string]struct{}{}
+	if !isAdmin {
+		nonAdminNamespaces, err := kcl.GetNonAdminNamespaces(userID, restrictDefaultNamespace)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, namespace := range nonAdminNamespaces {
+			allowed[namespace] = struct{}{}
+		}
+	}
+
+	names := make([]string, 0, len(namespaces))
+	for name := range namespaces {
+		if _, ok := allowed[name]; isAdmin || ok {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
 // fetchNamespacesForNonAdmin gets the namespaces in the current k8s environment(endpoint) for the non-admin user.
 func (kcl *KubeClient) fetchNamespacesForNonAdmin() (map[string]portainer.K8sNamespaceInfo, error) {
 	log.Debug().Msgf("Fetching namespaces for non-admin user: %v", kcl.NonAdminNamespaces)
@@ -134,36 +166,17 @@ func (kcl *KubeClient) CreateNamespace(info models.K8sNamespaceDetails) (*corev1
 		log.Info().Msgf("Creating resource quota for namespace %s", info.Name)
 		log.Debug().Msgf("Creating resource quota with details: %+v", info.ResourceQuota)
 
-		resourceQuota := &corev1.ResourceQuota{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      "portainer-rq-" + info.Name,
-				Namespace: info.Name,
-				Labels:    portainerLabels,
-			},
-			Spec: corev1.ResourceQuotaSpec{
-				Hard: corev1.ResourceList{},
-			},
+		if _, err := kcl.CreateResourceQuota(info.Name, *info.ResourceQuota, portainerLabels); err != nil {
+			log.Error().Msgf("Failed to create resource quota for namespace %s: %s", info.Name, err)
+			return nil, err
 		}
+	}
 
-		if info.ResourceQuota.Enabled {
-			memory := resource.MustParse(info.ResourceQuota.Memory)
-			cpu := resource.MustParse(info.ResourceQuota.CPU)
-			if memory.Value() > 0 {
-				memQuota := memory
-				resourceQuota.Spec.Hard[corev1.ResourceLimitsMemory] = memQuota
-				resourceQuota.Spec.Hard[corev1.ResourceRequestsMemory] = memQuota
-			}
+	if info.LimitRange != nil && info.LimitRange.Enabled {
+		log.Debug().Msgf("Creating limit range with details: %+v", info.LimitRange)
 
-			if cpu.Value() > 0 {
-				cpuQuota := cpu
-				resourceQuota.Spec.Hard[corev1.ResourceLimitsCPU] = cpuQuota
-				resourceQuota.Spec.Hard[corev1.ResourceRequestsCPU] = cpuQuota
-			}
-		}
-
-		_, err := kcl.cli.CoreV1().ResourceQuotas(info.Name).Create(context.Background(), resourceQuota, metav1.CreateOptions{})
-		if err != nil {
-			log.Error().Msgf("Failed to create resource quota for namespace %s: %s", info.Name, err)
+		if _, err := kcl.CreateLimitRange(info.Name, *info.LimitRange, portainerLabels); err != nil {
+			log.Error().Msgf("Failed to create limit range for namespace %s: %s", info.Name, err)
 			return nil, err
 		}
 	}
@@ -231,7 +244,42 @@ func (kcl *KubeClient) UpdateNamespace(info models.K8sNamespaceDetails) (*corev1
 		},
 	}
 
-	return kcl.cli.CoreV1().Namespaces().Update(context.Background(), &namespace, metav1.UpdateOptions{})
+	updatedNamespace, err := kcl.cli.CoreV1().Namespaces().Update(context.Background(), &namespace, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := kcl.updateNamespaceLimits(info); err != nil {
+		return nil, err
+	}
+
+	return updatedNamespace, nil
+}
+
+// updateNamespaceLimits applies the resource quota and the limit range of the namespace,
+// they are removed when disabled and left untouched when not specified
+func (kcl *KubeClient) updateNamespaceLimits(info models.K8sNamespaceDetails) error {
+	if info.ResourceQuota != nil {
+		if info.ResourceQuota.Enabled {
+			if _, err := kcl.UpdateResourceQuota(info.Name, *info.ResourceQuota); err != nil {
+				return errors.Wrap(err, "failed updating the resource quota")
+			}
+		} else if err := kcl.DeleteResourceQuota(info.Name); err != nil {
+			return errors.Wrap(err, "failed removing the resource quota")
+		}
+	}
+
+	if info.LimitRange != nil {
+		if info.LimitRange.Enabled {
+			if _, err := kcl.UpdateLimitRange(info.Name, *info.LimitRange); err != nil {
+				return errors.Wrap(err, "failed updating the limit range")
+			}
+		} else if err := kcl.DeleteLimitRange(info.Name); err != nil {
+			return errors.Wrap(err, "failed removing the limit range")
+		}
+	}
+
+	return nil
 }
 
 func (kcl *KubeClient) DeleteNamespace(namespaceName string) (*corev1.Namespace, error) {
@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"time"
 
@@ -25,6 +26,26 @@ func (kcl *KubeClient) GetPods(namespace string) ([]corev1.Pod, error) {
 	return pods.Items, nil
 }
 
+// GetPodLogs streams the logs of a container of a pod. Only the last lines are returned when tail is positive
+// and the stream is kept open for the new lines when follow is true, until the context is cancelled.
+// The caller must close the returned stream.
+func (kcl *KubeClient) GetPodLogs(ctx context.Context, namespace, podName, containerName string, tail int64, follow bool) (io.ReadCloser, error) {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return nil, err
+	}
+
+	options := &corev1.PodLogOptions{
+		Container: containerName,
+		Follow:    follow,
+	}
+
+	if tail > 0 {
+		options.TailLines = &tail
+	}
+
+	return kcl.cli.CoreV1().Pods(namespace).GetLogs(podName, options).Stream(ctx)
+}
+
 // isReplicaSetOwner checks if the pod's owner reference is a ReplicaSet
 func isReplicaSetOwner(pod corev1.Pod) bool {
 	return len(pod.OwnerReferences) > 0 && pod.OwnerReferences[0].Kind == "ReplicaSet"
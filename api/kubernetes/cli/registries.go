@@ -100,6 +100,32 @@ func (cli *KubeClient) IsRegistrySecret(namespace, secretName string) (bool, err
 	return isSecret, nil
 }
 
+// GetRegistrySecretNamespaces returns the namespaces holding the pull secret of each registry, only the
+// secrets created by Portainer are returned
+func (kcl *KubeClient) GetRegistrySecretNamespaces() (map[portainer.RegistryID][]string, error) {
+	secrets, err := kcl.cli.CoreV1().Secrets(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{LabelSelector: labelRegistryType})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed listing registry secrets")
+	}
+
+	namespaces := make(map[portainer.RegistryID][]string)
+
+	for _, secret := range secrets.Items {
+		if secret.Type != v1.SecretTypeDockerConfigJson {
+			continue
+		}
+
+		registryID, err := strconv.Atoi(secret.Annotations[annotationRegistryID])
+		if err != nil || secret.Name != kcl.RegistrySecretName(portainer.RegistryID(registryID)) {
+			continue
+		}
+
+		namespaces[portainer.RegistryID(registryID)] = append(namespaces[portainer.RegistryID(registryID)], secret.Namespace)
+	}
+
+	return namespaces, nil
+}
+
 func (*KubeClient) RegistrySecretName(registryID portainer.RegistryID) string {
 	return fmt.Sprintf("registry-%d", registryID)
 }
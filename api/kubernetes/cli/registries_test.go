@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_GetRegistrySecretNamespaces(t *testing.T) {
+	kcl := &KubeClient{
+		cli:        kfake.NewSimpleClientset(),
+		instanceID: "instance",
+	}
+
+	for _, namespace := range []string{"default", "web"} {
+		if err := kcl.CreateRegistrySecret(&portainer.Registry{ID: 1, URL: "registry.example.com"}, namespace); err != nil {
+			t.Fatalf("unable to create the registry secret: %s", err)
+		}
+	}
+
+	if err := kcl.CreateRegistrySecret(&portainer.Registry{ID: 2, URL: "other.example.com"}, "web"); err != nil {
+		t.Fatalf("unable to create the registry secret: %s", err)
+	}
+
+	// A secret that was not created by Portainer is ignored
+	userSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-3", Namespace: "web"},
+		Type:       v1.SecretTypeDockerConfigJson,
+	}
+	if _, err := kcl.cli.CoreV1().Secrets("web").Create(context.Background(), userSecret, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unable to create the secret: %s", err)
+	}
+
+	namespaces, err := kcl.GetRegistrySecretNamespaces()
+	if err != nil {
+		t.Fatalf("unable to retrieve the registry secrets: %s", err)
+	}
+
+	if len(namespaces) != 2 {
+		t.Fatalf("expected the secrets of 2 registries, got %v", namespaces)
+	}
+
+	slices.Sort(namespaces[1])
+	if !slices.Equal(namespaces[1], []string{"default", "web"}) {
+		t.Errorf("unexpected namespaces for the registry 1: %v", namespaces[1])
+	}
+
+	if !slices.Equal(namespaces[2], []string{"web"}) {
+		t.Errorf("unexpected namespaces for the registry 2: %v", namespaces[2])
+	}
+}
@@ -5,12 +5,16 @@ import (
 	"fmt"
 
 	portainer "github.com/portainer/portainer/api"
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
 	"github.com/rs/zerolog/log"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const resourceQuotaPrefix = "portainer-rq-"
+
 // GetResourceQuotas gets all resource quotas in the current k8s environment(endpoint).
 // if the user is an admin, all resource quotas in all namespaces are fetched.
 // otherwise, namespaces the non-admin user has access to will be used to filter the resource quotas.
@@ -58,7 +62,64 @@ func (kcl *KubeClient) fetchResourceQuotas(namespace string) (*[]corev1.Resource
 // GetPortainerResourceQuota gets the resource quota for the portainer namespace.
 // The resource quota is prefixed with "portainer-rq-".
 func (kcl *KubeClient) GetPortainerResourceQuota(namespace string) (*corev1.ResourceQuota, error) {
-	return kcl.cli.CoreV1().ResourceQuotas(namespace).Get(context.TODO(), "portainer-rq-"+namespace, metav1.GetOptions{})
+	return kcl.cli.CoreV1().ResourceQuotas(namespace).Get(context.TODO(), resourceQuotaPrefix+namespace, metav1.GetOptions{})
+}
+
+// CreateResourceQuota creates the Portainer resource quota of a namespace, limiting the cpu and memory
+// requested by the containers of the namespace.
+func (kcl *KubeClient) CreateResourceQuota(namespace string, quota models.K8sResourceQuota, labels map[string]string) (*corev1.ResourceQuota, error) {
+	resourceQuota := &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourceQuotaPrefix + namespace,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: buildResourceQuotaSpec(quota),
+	}
+
+	return kcl.cli.CoreV1().ResourceQuotas(namespace).Create(context.TODO(), resourceQuota, metav1.CreateOptions{})
+}
+
+// UpdateResourceQuota updates the Portainer resource quota of a namespace, the resource quota is created when missing
+func (kcl *KubeClient) UpdateResourceQuota(namespace string, quota models.K8sResourceQuota) (*corev1.ResourceQuota, error) {
+	resourceQuota, err := kcl.GetPortainerResourceQuota(namespace)
+	if k8serrors.IsNotFound(err) {
+		return kcl.CreateResourceQuota(namespace, quota, nil)
+	} else if err != nil {
+		return nil, err
+	}
+
+	resourceQuota.Spec = buildResourceQuotaSpec(quota)
+
+	return kcl.cli.CoreV1().ResourceQuotas(namespace).Update(context.TODO(), resourceQuota, metav1.UpdateOptions{})
+}
+
+// DeleteResourceQuota removes the Portainer resource quota of a namespace, if any
+func (kcl *KubeClient) DeleteResourceQuota(namespace string) error {
+	err := kcl.cli.CoreV1().ResourceQuotas(namespace).Delete(context.TODO(), resourceQuotaPrefix+namespace, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func buildResourceQuotaSpec(quota models.K8sResourceQuota) corev1.ResourceQuotaSpec {
+	spec := corev1.ResourceQuotaSpec{
+		Hard: corev1.ResourceList{},
+	}
+
+	if memory, err := resource.ParseQuantity(quota.Memory); err == nil && memory.Value() > 0 {
+		spec.Hard[corev1.ResourceLimitsMemory] = memory
+		spec.Hard[corev1.ResourceRequestsMemory] = memory
+	}
+
+	if cpu, err := resource.ParseQuantity(quota.CPU); err == nil && cpu.Value() > 0 {
+		spec.Hard[corev1.ResourceLimitsCPU] = cpu
+		spec.Hard[corev1.ResourceRequestsCPU] = cpu
+	}
+
+	return spec
 }
 
 // GetResourceQuota gets a resource quota in a specific namespace.
@@ -86,7 +147,7 @@ func (kcl *KubeClient) UpdateNamespacesWithResourceQuotas(namespaces map[string]
 // GetResourceQuotaFromNamespace gets the resource quota in a specific namespace where the resource quota's name is prefixed with "portainer-rq-".
 func (kcl *KubeClient) GetResourceQuotaFromNamespace(namespace portainer.K8sNamespaceInfo, resourceQuotas []corev1.ResourceQuota) *corev1.ResourceQuota {
 	for _, resourceQuota := range resourceQuotas {
-		if resourceQuota.ObjectMeta.Namespace == namespace.Name && resourceQuota.ObjectMeta.Name == "portainer-rq-"+namespace.Name {
+		if resourceQuota.ObjectMeta.Namespace == namespace.Name && resourceQuota.ObjectMeta.Name == resourceQuotaPrefix+namespace.Name {
 			return &resourceQuota
 		}
 	}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"testing"
+
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_NamespaceResourceLimits(t *testing.T) {
+	kcl := &KubeClient{
+		cli:         kfake.NewSimpleClientset(),
+		instanceID:  "instance",
+		IsKubeAdmin: true,
+	}
+
+	_, err := kcl.CreateNamespace(models.K8sNamespaceDetails{
+		Name:          "team-a",
+		ResourceQuota: &models.K8sResourceQuota{Enabled: true, CPU: "2", Memory: "4Gi"},
+		LimitRange:    &models.K8sLimitRange{Enabled: true, DefaultCPU: "500m", DefaultMemory: "512Mi", DefaultRequestCPU: "250m"},
+	})
+	require.NoError(t, err)
+
+	quota, err := kcl.GetPortainerResourceQuota("team-a")
+	require.NoError(t, err)
+	assert.True(t, resource.MustParse("2").Equal(quota.Spec.Hard[corev1.ResourceLimitsCPU]))
+	assert.True(t, resource.MustParse("4Gi").Equal(quota.Spec.Hard[corev1.ResourceRequestsMemory]))
+
+	limitRanges, err := kcl.GetLimitRanges("team-a")
+	require.NoError(t, err)
+	require.Len(t, limitRanges, 1)
+
+	limits := limitRanges[0].Spec.Limits[0]
+	assert.True(t, resource.MustParse("500m").Equal(limits.Default[corev1.ResourceCPU]))
+	assert.True(t, resource.MustParse("250m").Equal(limits.DefaultRequest[corev1.ResourceCPU]))
+	// The request defaults to the limit when not set
+	assert.True(t, resource.MustParse("512Mi").Equal(limits.DefaultRequest[corev1.ResourceMemory]))
+
+	_, err = kcl.UpdateNamespace(models.K8sNamespaceDetails{
+		Name:          "team-a",
+		ResourceQuota: &models.K8sResourceQuota{Enabled: true, CPU: "4", Memory: "4Gi"},
+		LimitRange:    &models.K8sLimitRange{Enabled: false},
+	})
+	require.NoError(t, err)
+
+	quota, err = kcl.GetPortainerResourceQuota("team-a")
+	require.NoError(t, err)
+	assert.True(t, resource.MustParse("4").Equal(quota.Spec.Hard[corev1.ResourceLimitsCPU]))
+
+	_, err = kcl.GetPortainerLimitRange("team-a")
+	assert.True(t, k8serrors.IsNotFound(err))
+
+	// The resource limits are left untouched when not specified
+	_, err = kcl.UpdateNamespace(models.K8sNamespaceDetails{Name: "team-a"})
+	require.NoError(t, err)
+
+	_, err = kcl.cli.CoreV1().ResourceQuotas("team-a").Get(context.TODO(), resourceQuotaPrefix+"team-a", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	_, err = kcl.UpdateNamespace(models.K8sNamespaceDetails{
+		Name:          "team-a",
+		ResourceQuota: &models.K8sResourceQuota{Enabled: false},
+	})
+	require.NoError(t, err)
+
+	_, err = kcl.GetPortainerResourceQuota("team-a")
+	assert.True(t, k8serrors.IsNotFound(err))
+}
+
+func Test_LimitRangeValidation(t *testing.T) {
+	validate := func(limitRange models.K8sLimitRange) error {
+		limitRange.Enabled = true
+		details := models.K8sNamespaceDetails{Name: "team-a", LimitRange: &limitRange}
+
+		return details.Validate(nil)
+	}
+
+	assert.NoError(t, validate(models.K8sLimitRange{DefaultCPU: "1", DefaultRequestCPU: "500m"}))
+	assert.Error(t, validate(models.K8sLimitRange{}))
+	assert.Error(t, validate(models.K8sLimitRange{DefaultCPU: "500m", DefaultRequestCPU: "1"}))
+	assert.Error(t, validate(models.K8sLimitRange{DefaultCPU: "1", DefaultRequestMemory: "1Gi"}))
+	assert.Error(t, validate(models.K8sLimitRange{DefaultMemory: "lots"}))
+}
@@ -80,6 +80,129 @@ func (kcl *KubeClient) GetSecret(namespace string, secretName string) (models.K8
 	return parseSecret(secret, true), nil
 }
 
+// CreateSecret creates a Secret in the given namespace and records its creation in the change history.
+func (kcl *KubeClient) CreateSecret(namespace string, payload models.K8sSecretPayload, username string) (models.K8sSecret, error) {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return models.K8sSecret{}, err
+	}
+
+	data, err := payload.DecodedData()
+	if err != nil {
+		return models.K8sSecret{}, err
+	}
+
+	annotations, err := appendConfigurationHistory(nil, username, diffConfigurationData(nil, bytesToStrings(data), false))
+	if err != nil {
+		return models.K8sSecret{}, err
+	}
+
+	secretType := corev1.SecretTypeOpaque
+	if payload.SecretType != "" {
+		secretType = corev1.SecretType(payload.SecretType)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        payload.Name,
+			Namespace:   namespace,
+			Labels:      payload.Labels,
+			Annotations: annotations,
+		},
+		Data: data,
+		Type: secretType,
+	}
+
+	secret, err = kcl.cli.CoreV1().Secrets(namespace).Create(context.Background(), secret, metav1.CreateOptions{})
+	if err != nil {
+		return models.K8sSecret{}, err
+	}
+
+	return parseSecret(secret, true), nil
+}
+
+// UpdateSecret replaces the data of a Secret and records the changed keys, never their values, in the change history.
+// the update is rejected with ErrConfigurationConflict when the Secret was modified since payload.ResourceVersion.
+func (kcl *KubeClient) UpdateSecret(namespace, secretName string, payload models.K8sSecretPayload, username string) (models.K8sSecret, error) {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return models.K8sSecret{}, err
+	}
+
+	data, err := payload.DecodedData()
+	if err != nil {
+		return models.K8sSecret{}, err
+	}
+
+	secret, err := kcl.cli.CoreV1().Secrets(namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+	if err != nil {
+		return models.K8sSecret{}, err
+	}
+
+	if secret.ResourceVersion != payload.ResourceVersion {
+		return models.K8sSecret{}, ErrConfigurationConflict
+	}
+
+	annotations, err := appendConfigurationHistory(secret.Annotations, username, diffConfigurationData(bytesToStrings(secret.Data), bytesToStrings(data), false))
+	if err != nil {
+		return models.K8sSecret{}, err
+	}
+
+	secret.Annotations = annotations
+	secret.Labels = mergeLabels(secret.Labels, payload.Labels)
+	secret.Data = data
+	// StringData would be merged into Data by the Kubernetes API and could restore removed keys
+	secret.StringData = nil
+
+	// the resource version is kept so that the Kubernetes API rejects concurrent updates as well
+	secret, err = kcl.cli.CoreV1().Secrets(namespace).Update(context.Background(), secret, metav1.UpdateOptions{})
+	if err != nil {
+		return models.K8sSecret{}, err
+	}
+
+	return parseSecret(secret, true), nil
+}
+
+// DiffSecret returns a preview of the keys an update of the Secret would change, without applying them.
+// the values of the secret are never part of the preview.
+func (kcl *KubeClient) DiffSecret(namespace, secretName string, payload models.K8sSecretPayload) (models.K8sConfigurationDiff, error) {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return models.K8sConfigurationDiff{}, err
+	}
+
+	data, err := payload.DecodedData()
+	if err != nil {
+		return models.K8sConfigurationDiff{}, err
+	}
+
+	secret, err := kcl.cli.CoreV1().Secrets(namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+	if err != nil {
+		return models.K8sConfigurationDiff{}, err
+	}
+
+	return models.K8sConfigurationDiff{
+		ResourceVersion: secret.ResourceVersion,
+		Changes:         diffConfigurationData(bytesToStrings(secret.Data), bytesToStrings(data), false),
+		Conflict:        payload.ResourceVersion != "" && payload.ResourceVersion != secret.ResourceVersion,
+	}, nil
+}
+
+// DeleteSecret deletes a Secret from the given namespace.
+func (kcl *KubeClient) DeleteSecret(namespace, secretName string) error {
+	if err := kcl.checkNamespaceAccess(namespace); err != nil {
+		return err
+	}
+
+	return kcl.cli.CoreV1().Secrets(namespace).Delete(context.Background(), secretName, metav1.DeleteOptions{})
+}
+
+func bytesToStrings(data map[string][]byte) map[string]string {
+	result := make(map[string]string, len(data))
+	for key, value := range data {
+		result[key] = string(value)
+	}
+
+	return result
+}
+
 // parseSecret parses a k8s Secret object into a K8sSecret struct.
 // for get operation, withData will be set to true.
 // otherwise, only metadata will be parsed.
@@ -90,6 +213,7 @@ func parseSecret(secret *corev1.Secret, withData bool) models.K8sSecret {
 			Name:                 secret.Name,
 			Namespace:            secret.Namespace,
 			CreationDate:         secret.CreationTimestamp.Time.UTC().Format(time.RFC3339),
+			ResourceVersion:      secret.ResourceVersion,
 			Annotations:          secret.Annotations,
 			Labels:               secret.Labels,
 			ConfigurationOwner:   secret.Labels[labelPortainerKubeConfigOwner],
@@ -99,13 +223,8 @@ func parseSecret(secret *corev1.Secret, withData bool) models.K8sSecret {
 	}
 
 	if withData {
-		secretData := secret.Data
-		secretDataMap := make(map[string]string, len(secretData))
-		for key, value := range secretData {
-			secretDataMap[key] = string(value)
-		}
-
-		result.Data = secretDataMap
+		result.Data = bytesToStrings(secret.Data)
+		result.History = parseConfigurationHistory(secret.Annotations)
 	}
 
 	return result
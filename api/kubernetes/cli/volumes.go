@@ -2,6 +2,8 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	models "github.com/portainer/portainer/api/http/models/kubernetes"
@@ -10,9 +12,17 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+var (
+	// ErrVolumeExpansionNotAllowed is returned when the storage class of a volume does not allow its expansion
+	ErrVolumeExpansionNotAllowed = errors.New("the storage class of the volume does not allow volume expansion")
+	// ErrVolumeSizeNotIncreased is returned when a volume expansion does not increase its size
+	ErrVolumeSizeNotIncreased = errors.New("the new size of the volume must be larger than its current size")
+)
+
 // GetVolumes gets the volumes in the current k8s environment(endpoint).
 // If the user is an admin, it fetches all the volumes in the cluster.
 // If the user is not an admin, it fetches the volumes in the namespaces the user has access to.
@@ -122,12 +132,14 @@ func parseVolume(persistentVolumeClaim *corev1.PersistentVolumeClaim, persistent
 // parsePersistentVolumeClaim parses the given persistent volume claim and returns a K8sPersistentVolumeClaim.
 func parsePersistentVolumeClaim(volume *corev1.PersistentVolumeClaim) models.K8sPersistentVolumeClaim {
 	storage := volume.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity := volume.Status.Capacity[corev1.ResourceStorage]
 	return models.K8sPersistentVolumeClaim{
 		ID:                 string(volume.UID),
 		Name:               volume.Name,
 		Namespace:          volume.Namespace,
 		CreationDate:       volume.CreationTimestamp.Time,
 		Storage:            storage.Value(),
+		Capacity:           capacity.Value(),
 		AccessModes:        volume.Spec.AccessModes,
 		VolumeName:         volume.Spec.VolumeName,
 		ResourcesRequests:  &volume.Spec.Resources.Requests,
@@ -151,6 +163,7 @@ func parsePersistentVolume(volume *corev1.PersistentVolume) models.K8sPersistent
 		PersistentVolumeReclaimPolicy: volume.Spec.PersistentVolumeReclaimPolicy,
 		VolumeMode:                    volume.Spec.VolumeMode,
 		CSI:                           volume.Spec.CSI,
+		Phase:                         volume.Status.Phase,
 	}
 }
 
@@ -287,3 +300,141 @@ func (kcl *KubeClient) updateVolumesWithOwningApplications(volumes *[]models.K8s
 	}
 	return volumes, nil
 }
+
+// GetPersistentVolumes gets all the persistent volumes of the cluster.
+func (kcl *KubeClient) GetPersistentVolumes() ([]models.K8sPersistentVolume, error) {
+	persistentVolumes, err := kcl.cli.CoreV1().PersistentVolumes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.K8sPersistentVolume, 0, len(persistentVolumes.Items))
+	for _, persistentVolume := range persistentVolumes.Items {
+		results = append(results, parsePersistentVolume(&persistentVolume))
+	}
+
+	return results, nil
+}
+
+// kubeletStatsSummary is the subset of the kubelet stats summary holding the volume metrics
+type kubeletStatsSummary struct {
+	Pods []struct {
+		Volumes []struct {
+			UsedBytes      *uint64 `json:"usedBytes"`
+			CapacityBytes  *uint64 `json:"capacityBytes"`
+			AvailableBytes *uint64 `json:"availableBytes"`
+			PVCRef         *struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"pvcRef"`
+		} `json:"volume"`
+	} `json:"pods"`
+}
+
+// CombineVolumesWithUsage sets the usage of the volumes reported by the kubelets. Only the volumes
+// that are mounted and whose driver exposes metrics, such as most CSI drivers, have a usage.
+// The nodes whose kubelet cannot be reached are skipped.
+func (kcl *KubeClient) CombineVolumesWithUsage(volumes []models.K8sVolumeInfo) error {
+	nodes, err := kcl.cli.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("an error occurred during the CombineVolumesWithUsage operation, unable to list nodes. Error: %w", err)
+	}
+
+	usages := make(map[string]models.K8sVolumeUsage)
+	for _, node := range nodes.Items {
+		raw, err := kcl.cli.CoreV1().RESTClient().Get().
+			Resource("nodes").
+			Name(node.Name).
+			SubResource("proxy").
+			Suffix("stats/summary").
+			DoRaw(context.TODO())
+		if err != nil {
+			log.Warn().Err(err).Str("node", node.Name).Msg("unable to retrieve the kubelet stats summary")
+
+			continue
+		}
+
+		if err := parseVolumeUsages(raw, usages); err != nil {
+			log.Warn().Err(err).Str("node", node.Name).Msg("unable to parse the kubelet stats summary")
+		}
+	}
+
+	for i := range volumes {
+		claim := &volumes[i].PersistentVolumeClaim
+		if usage, ok := usages[claim.Namespace+"/"+claim.Name]; ok {
+			claim.Usage = &usage
+		}
+	}
+
+	return nil
+}
+
+// parseVolumeUsages adds the usages of the persistent volume claims found in a kubelet stats summary,
+// indexed by namespace/name
+func parseVolumeUsages(raw []byte, usages map[string]models.K8sVolumeUsage) error {
+	var summary kubeletStatsSummary
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return err
+	}
+
+	for _, pod := range summary.Pods {
+		for _, volume := range pod.Volumes {
+			if volume.PVCRef == nil || volume.UsedBytes == nil {
+				continue
+			}
+
+			usage := models.K8sVolumeUsage{UsedBytes: int64(*volume.UsedBytes)}
+			if volume.CapacityBytes != nil {
+				usage.CapacityBytes = int64(*volume.CapacityBytes)
+			}
+
+			if volume.AvailableBytes != nil {
+				usage.AvailableBytes = int64(*volume.AvailableBytes)
+			}
+
+			usages[volume.PVCRef.Namespace+"/"+volume.PVCRef.Name] = usage
+		}
+	}
+
+	return nil
+}
+
+// ExpandVolume increases the requested size of a persistent volume claim. The storage class of the
+// claim must allow volume expansion and the new size must be larger than the current request.
+func (kcl *KubeClient) ExpandVolume(namespace, volumeName string, size resource.Quantity) error {
+	persistentVolumeClaim, err := kcl.cli.CoreV1().PersistentVolumeClaims(namespace).Get(context.TODO(), volumeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if persistentVolumeClaim.Spec.StorageClassName == nil || *persistentVolumeClaim.Spec.StorageClassName == "" {
+		return ErrVolumeExpansionNotAllowed
+	}
+
+	storageClass, err := kcl.cli.StorageV1().StorageClasses().Get(context.TODO(), *persistentVolumeClaim.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return ErrVolumeExpansionNotAllowed
+		}
+
+		return err
+	}
+
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return ErrVolumeExpansionNotAllowed
+	}
+
+	current := persistentVolumeClaim.Spec.Resources.Requests[corev1.ResourceStorage]
+	if size.Cmp(current) <= 0 {
+		return ErrVolumeSizeNotIncreased
+	}
+
+	if persistentVolumeClaim.Spec.Resources.Requests == nil {
+		persistentVolumeClaim.Spec.Resources.Requests = corev1.ResourceList{}
+	}
+	persistentVolumeClaim.Spec.Resources.Requests[corev1.ResourceStorage] = size
+
+	_, err = kcl.cli.CoreV1().PersistentVolumeClaims(namespace).Update(context.TODO(), persistentVolumeClaim, metav1.UpdateOptions{})
+
+	return err
+}
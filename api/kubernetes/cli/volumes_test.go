@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	models "github.com/portainer/portainer/api/http/models/kubernetes"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_parseVolumeUsages(t *testing.T) {
+	raw := []byte(`{
+		"pods": [
+			{"volume": [
+				{"name": "data", "usedBytes": 100, "capacityBytes": 1000, "availableBytes": 900, "pvcRef": {"name": "db-data", "namespace": "prod"}},
+				{"name": "kube-api-access", "usedBytes": 12}
+			]},
+			{"volume": [
+				{"name": "cache", "pvcRef": {"name": "no-metrics", "namespace": "prod"}}
+			]}
+		]
+	}`)
+
+	usages := make(map[string]models.K8sVolumeUsage)
+	if err := parseVolumeUsages(raw, usages); err != nil {
+		t.Fatalf("parseVolumeUsages should succeed; err=%s", err)
+	}
+
+	if len(usages) != 1 {
+		t.Fatalf("expected the usage of a single volume, got %v", usages)
+	}
+
+	expected := models.K8sVolumeUsage{UsedBytes: 100, CapacityBytes: 1000, AvailableBytes: 900}
+	if usages["prod/db-data"] != expected {
+		t.Errorf("expected %v, got %v", expected, usages["prod/db-data"])
+	}
+}
+
+func newExpandTestClient(allowVolumeExpansion bool) *KubeClient {
+	storageClassName := "standard"
+
+	return &KubeClient{
+		cli: kfake.NewSimpleClientset(
+			&storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: storageClassName},
+				AllowVolumeExpansion: &allowVolumeExpansion,
+			},
+			&corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					StorageClassName: &storageClassName,
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("10Gi")},
+					},
+				},
+			},
+		),
+		instanceID: "test",
+	}
+}
+
+func Test_ExpandVolume(t *testing.T) {
+	t.Run("increases the requested size", func(t *testing.T) {
+		k := newExpandTestClient(true)
+
+		if err := k.ExpandVolume("default", "data", resource.MustParse("20Gi")); err != nil {
+			t.Fatalf("ExpandVolume should succeed; err=%s", err)
+		}
+
+		pvc, err := k.cli.CoreV1().PersistentVolumeClaims("default").Get(context.TODO(), "data", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		size := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if size.Cmp(resource.MustParse("20Gi")) != 0 {
+			t.Errorf("expected the requested size to be 20Gi, got %s", size.String())
+		}
+	})
+
+	t.Run("fails when the storage class does not allow expansion", func(t *testing.T) {
+		k := newExpandTestClient(false)
+
+		err := k.ExpandVolume("default", "data", resource.MustParse("20Gi"))
+		if !errors.Is(err, ErrVolumeExpansionNotAllowed) {
+			t.Errorf("expected ErrVolumeExpansionNotAllowed, got %v", err)
+		}
+	})
+
+	t.Run("fails when the size is not increased", func(t *testing.T) {
+		k := newExpandTestClient(true)
+
+		err := k.ExpandVolume("default", "data", resource.MustParse("5Gi"))
+		if !errors.Is(err, ErrVolumeSizeNotIncreased) {
+			t.Errorf("expected ErrVolumeSizeNotIncreased, got %v", err)
+		}
+	})
+}
@@ -131,3 +131,11 @@ func TestKubeClusterAccessService_GetKubeConfigInternal(t *testing.T) {
 		is.Equal(wantClusterAccessDetails, clusterAccessDetails)
 	})
 }
+
+func TestKubeClusterAccessService_GetCredentialURL(t *testing.T) {
+	is := assert.New(t)
+
+	kcs := NewKubeClusterAccessService("/portainer", ":9443", "")
+	is.Equal("https://mysite.com/portainer/api/kubernetes/config/credential", kcs.GetCredentialURL("mysite.com"))
+	is.Equal("https://localhost:9443/portainer/api/kubernetes/config/credential", kcs.GetCredentialURL("localhost"))
+}
@@ -1,6 +1,7 @@
 package oauth
 
 import (
+	"cmp"
 	"context"
 	"io"
 	"mime"
@@ -18,34 +19,92 @@ import (
 )
 
 // Service represents a service used to authenticate users against an authorization server
-type Service struct{}
+type Service struct {
+	keySets *keySetCache
+}
 
 // NewService returns a pointer to a new instance of this service
 func NewService() *Service {
-	return &Service{}
+	return &Service{
+		keySets: newKeySetCache(),
+	}
 }
 
 // Authenticate takes an access code and exchanges it for an access token from portainer OAuthSettings token environment(endpoint).
-// On success, it will then return the username and token expiry time associated to authenticated user by fetching this information
-// from the resource server and matching it with the user identifier setting.
-func (*Service) Authenticate(code string, configuration *portainer.OAuthSettings) (string, error) {
+// On success, it will then return the username and the groups associated to authenticated user by fetching this information
+// from the ID token and the resource server and matching it with the user identifier setting.
+func (service *Service) Authenticate(code string, configuration *portainer.OAuthSettings) (*portainer.OAuthInfo, error) {
 	token, err := getOAuthToken(code, configuration)
 	if err != nil {
 		log.Error().Err(err).Msg("failed retrieving oauth token")
 
-		return "", err
+		return nil, err
 	}
 
-	idToken, err := getIdToken(token)
+	return service.getOAuthInfo(token, configuration, false)
+}
+
+// Refresh exchanges a refresh token for new tokens, so that the identity and the groups of the user are checked again
+// against the provider
+func (service *Service) Refresh(refreshToken string, configuration *portainer.OAuthSettings) (*portainer.OAuthInfo, error) {
+	tokenSource := buildConfig(configuration).TokenSource(context.Background(), &oauth2.Token{RefreshToken: refreshToken})
+
+	token, err := tokenSource.Token()
 	if err != nil {
-		log.Error().Err(err).Msg("failed parsing id_token")
+		log.Error().Err(err).Msg("failed refreshing oauth token")
+
+		return nil, err
 	}
 
-	resource, err := getResource(token.AccessToken, configuration)
+	info, err := service.getOAuthInfo(token, configuration, true)
 	if err != nil {
-		log.Error().Err(err).Msg("failed retrieving resource")
+		return nil, err
+	}
+
+	// Providers not rotating the refresh tokens do not return them again
+	info.RefreshToken = cmp.Or(info.RefreshToken, refreshToken)
+
+	return info, nil
+}
+
+// getOAuthInfo extracts the information about the user from the ID token and the resource server.
+// The providers can omit the ID token when refreshing the tokens, the user info endpoint is then required
+func (service *Service) getOAuthInfo(token *oauth2.Token, configuration *portainer.OAuthSettings, refreshed bool) (*portainer.OAuthInfo, error) {
+	idToken := make(map[string]any)
+	var err error
+
+	if configuration.OIDC {
+		idToken, err = service.verifyIdToken(token, configuration)
+		if errors.Is(err, errMissingIdToken) && refreshed && configuration.ResourceURI != "" {
+			idToken, err = make(map[string]any), nil
+		}
 
-		return "", err
+		if err != nil {
+			log.Error().Err(err).Msg("failed validating id_token")
+
+			return nil, err
+		}
+	} else {
+		idToken, err = getIdToken(token)
+		if err != nil {
+			log.Error().Err(err).Msg("failed parsing id_token")
+		}
+	}
+
+	resource := make(map[string]any)
+
+	// The claims of the ID token are enough for OpenID Connect providers without user info endpoint
+	if !configuration.OIDC || configuration.ResourceURI != "" {
+		resource, err = getResource(token.AccessToken, configuration)
+		if err != nil {
+			log.Error().Err(err).Msg("failed retrieving resource")
+
+			return nil, err
+		}
+	}
+
+	if configuration.OIDC && idToken["sub"] != nil && resource["sub"] != nil && idToken["sub"] != resource["sub"] {
+		return nil, errors.New("the subject of the user info does not match the subject of the id_token")
 	}
 
 	resource = mergeSecondIntoFirst(idToken, resource)
@@ -54,10 +113,14 @@ func (*Service) Authenticate(code string, configuration *portainer.OAuthSettings
 	if err != nil {
 		log.Error().Err(err).Msg("failed retrieving username")
 
-		return "", err
+		return nil, err
 	}
 
-	return username, nil
+	return &portainer.OAuthInfo{
+		Username:     username,
+		Groups:       getGroups(resource, configuration.GroupClaim),
+		RefreshToken: token.RefreshToken,
+	}, nil
 }
 
 // mergeSecondIntoFirst merges the overlap map into the base overwriting any existing values.
@@ -3,6 +3,7 @@ package oauth
 import (
 	"errors"
 	"strconv"
+	"strings"
 
 	portainer "github.com/portainer/portainer/api"
 )
@@ -22,3 +23,42 @@ func getUsername(datamap map[string]any, configuration *portainer.OAuthSettings)
 
 	return "", errors.New("failed to extract username from oauth resource")
 }
+
+// getGroups returns the values of the group claim, the claims nested in objects are separated by dots
+func getGroups(datamap map[string]any, groupClaim string) []string {
+	if groupClaim == "" {
+		return nil
+	}
+
+	var value any = datamap
+	for _, key := range strings.Split(groupClaim, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+
+		value = object[key]
+	}
+
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+
+		return []string{v}
+	case []any:
+		groups := make([]string, 0, len(v))
+		for _, group := range v {
+			if name, ok := group.(string); ok {
+				groups = append(groups, name)
+			}
+		}
+
+		return groups
+	case []string:
+		return v
+	}
+
+	return nil
+}
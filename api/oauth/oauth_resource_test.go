@@ -1,6 +1,7 @@
 package oauth
 
 import (
+	"slices"
 	"testing"
 
 	portainer "github.com/portainer/portainer/api"
@@ -78,3 +79,29 @@ func Test_getUsername(t *testing.T) {
 		}
 	})
 }
+
+func Test_getGroups(t *testing.T) {
+	datamap := map[string]any{
+		"groups":       []any{"dev", 42, "ops"},
+		"role":         "admin",
+		"realm_access": map[string]any{"roles": []any{"viewer"}},
+	}
+
+	tests := []struct {
+		groupClaim string
+		expected   []string
+	}{
+		{groupClaim: "groups", expected: []string{"dev", "ops"}},
+		{groupClaim: "role", expected: []string{"admin"}},
+		{groupClaim: "realm_access.roles", expected: []string{"viewer"}},
+		{groupClaim: "realm_access.missing", expected: nil},
+		{groupClaim: "role.nested", expected: nil},
+		{groupClaim: "", expected: nil},
+	}
+
+	for _, tc := range tests {
+		if groups := getGroups(datamap, tc.groupClaim); !slices.Equal(groups, tc.expected) {
+			t.Errorf("getGroups(%q) = %v, want %v", tc.groupClaim, groups, tc.expected)
+		}
+	}
+}
@@ -126,14 +126,14 @@ func Test_Authenticate(t *testing.T) {
 		srv, config := oauthtest.RunOAuthServer(code, config)
 		defer srv.Close()
 
-		username, err := authService.Authenticate(code, config)
+		info, err := authService.Authenticate(code, config)
 		if err != nil {
-			t.Errorf("Authenticate should succeed to extract username from resource if correct UserIdentifier provided; UserIdentifier=%s", config.UserIdentifier)
+			t.Fatalf("Authenticate should succeed to extract username from resource if correct UserIdentifier provided; UserIdentifier=%s", config.UserIdentifier)
 		}
 
 		want := "test-oauth-user"
-		if username != want {
-			t.Errorf("Authenticate should return correct username; got=%s, want=%s", username, want)
+		if info.Username != want {
+			t.Errorf("Authenticate should return correct username; got=%s, want=%s", info.Username, want)
 		}
 	})
 
@@ -0,0 +1,290 @@
+package oauth
+
+import (
+	"cmp"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/pkg/errors"
+	"github.com/segmentio/encoding/json"
+	"golang.org/x/oauth2"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	// keySetRefreshInterval is the minimum delay between two retrievals of a key set, when a token is signed with an unknown key
+	keySetRefreshInterval = 30 * time.Second
+	requestTimeout        = 10 * time.Second
+)
+
+var (
+	errMissingIdToken = errors.New("the provider did not return an id_token")
+
+	idTokenSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+)
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+	EndSessionEndpoint    string `json:"end_session_endpoint"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Discover fills the endpoints of an OpenID Connect provider from the discovery document of its issuer.
+// The user info and logout endpoints are only filled when they are not already set.
+func (service *Service) Discover(configuration *portainer.OAuthSettings) error {
+	if configuration.IssuerURL == "" {
+		return errors.New("the issuer URL is required to discover the provider")
+	}
+
+	var document discoveryDocument
+	if err := getJSON(strings.TrimSuffix(configuration.IssuerURL, "/")+discoveryPath, &document); err != nil {
+		return errors.Wrap(err, "failed to retrieve the discovery document")
+	}
+
+	if strings.TrimSuffix(document.Issuer, "/") != strings.TrimSuffix(configuration.IssuerURL, "/") {
+		return errors.Errorf("the discovery document was issued for %s instead of %s", document.Issuer, configuration.IssuerURL)
+	}
+
+	if document.AuthorizationEndpoint == "" || document.TokenEndpoint == "" || document.JWKSURI == "" {
+		return errors.New("the discovery document does not define the authorization, token and key set endpoints")
+	}
+
+	configuration.IssuerURL = document.Issuer
+	configuration.AuthorizationURI = document.AuthorizationEndpoint
+	configuration.AccessTokenURI = document.TokenEndpoint
+	configuration.JWKSURI = document.JWKSURI
+	configuration.ResourceURI = cmp.Or(configuration.ResourceURI, document.UserinfoEndpoint)
+	configuration.LogoutURI = cmp.Or(configuration.LogoutURI, document.EndSessionEndpoint)
+
+	return nil
+}
+
+// verifyIdToken validates the signature, the issuer, the audience and the expiry of the ID token and returns its claims
+func (service *Service) verifyIdToken(token *oauth2.Token, configuration *portainer.OAuthSettings) (map[string]any, error) {
+	rawIdToken, _ := token.Extra("id_token").(string)
+	if rawIdToken == "" {
+		return nil, errMissingIdToken
+	}
+
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(idTokenSigningMethods))
+
+	if _, err := parser.ParseWithClaims(rawIdToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+
+		return service.keySets.key(configuration.JWKSURI, kid)
+	}); err != nil {
+		return nil, errors.Wrap(err, "invalid id_token")
+	}
+
+	if !claims.VerifyIssuer(configuration.IssuerURL, true) {
+		return nil, errors.New("the id_token was not issued by the configured issuer")
+	}
+
+	if !claims.VerifyAudience(configuration.ClientID, true) {
+		return nil, errors.New("the id_token was not issued for this client")
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("the id_token is expired")
+	}
+
+	return claims, nil
+}
+
+// keySetCache keeps the keys of the providers, a key set is retrieved again when a token is signed with an unknown key
+type keySetCache struct {
+	mu   sync.Mutex
+	sets map[string]*keySet
+}
+
+type keySet struct {
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+func newKeySetCache() *keySetCache {
+	return &keySetCache{sets: make(map[string]*keySet)}
+}
+
+func (cache *keySetCache) key(jwksURI, kid string) (any, error) {
+	if jwksURI == "" {
+		return nil, errors.New("the key set URI of the provider is not configured")
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	set, ok := cache.sets[jwksURI]
+	if ok {
+		if key, ok := set.lookup(kid); ok {
+			return key, nil
+		}
+
+		if time.Since(set.fetchedAt) < keySetRefreshInterval {
+			return nil, errors.Errorf("unknown signing key %q", kid)
+		}
+	}
+
+	keys, err := fetchKeySet(jwksURI)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve the key set")
+	}
+
+	set = &keySet{keys: keys, fetchedAt: time.Now()}
+	cache.sets[jwksURI] = set
+
+	if key, ok := set.lookup(kid); ok {
+		return key, nil
+	}
+
+	return nil, errors.Errorf("unknown signing key %q", kid)
+}
+
+// lookup returns the key matching the identifier, a token without key identifier can only be validated by a key set of a single key
+func (set *keySet) lookup(kid string) (any, bool) {
+	if kid == "" && len(set.keys) == 1 {
+		for _, key := range set.keys {
+			return key, true
+		}
+	}
+
+	key, ok := set.keys[kid]
+
+	return key, ok
+}
+
+func fetchKeySet(jwksURI string) (map[string]any, error) {
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := getJSON(jwksURI, &document); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]any, len(document.Keys))
+	for _, webKey := range document.Keys {
+		if webKey.Use != "" && webKey.Use != "sig" {
+			continue
+		}
+
+		key, err := parsePublicKey(webKey)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key %q", webKey.Kid)
+		}
+
+		if key != nil {
+			keys[webKey.Kid] = key
+		}
+	}
+
+	return keys, nil
+}
+
+// parsePublicKey returns the RSA or EC public key described by the JSON Web Key, other key types are ignored
+func parsePublicKey(webKey jsonWebKey) (any, error) {
+	switch webKey.Kty {
+	case "RSA":
+		n, err := decodeBigInt(webKey.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(webKey.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch webKey.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %q", webKey.Crv)
+		}
+
+		x, err := decodeBigInt(webKey.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(webKey.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("the point is not on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, nil
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+func getJSON(url string, target any) error {
+	client := &http.Client{Timeout: requestTimeout}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status code %d from %s", resp.StatusCode, url)
+	}
+
+	return json.Unmarshal(body, target)
+}
@@ -0,0 +1,159 @@
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/segmentio/encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oidcTestClientID = "portainer"
+	oidcTestCode     = "valid-code"
+)
+
+type oidcTestProvider struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	claims jwt.MapClaims
+}
+
+func newOIDCTestProvider(t *testing.T) *oidcTestProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	provider := &oidcTestProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"issuer":                 provider.URL,
+			"authorization_endpoint": provider.URL + "/authorize",
+			"token_endpoint":         provider.URL + "/token",
+			"jwks_uri":               provider.URL + "/keys",
+		})
+	})
+
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"keys": []map[string]any{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if r.FormValue("code") != oidcTestCode && r.FormValue("refresh_token") != "refresh-1" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		writeJSON(w, map[string]any{
+			"access_token":  "access",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+			"refresh_token": "refresh-1",
+			"id_token":      provider.sign(t, provider.key, provider.claims),
+		})
+	})
+
+	provider.Server = httptest.NewServer(mux)
+	t.Cleanup(provider.Close)
+
+	provider.claims = jwt.MapClaims{
+		"iss":                provider.URL,
+		"aud":                oidcTestClientID,
+		"sub":                "1234",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"preferred_username": "alice",
+		"realm_access":       map[string]any{"roles": []string{"dev", "ops"}},
+	}
+
+	return provider
+}
+
+func (provider *oidcTestProvider) sign(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "key-1"
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func writeJSON(w http.ResponseWriter, content any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(content)
+}
+
+func TestOIDC_Authenticate(t *testing.T) {
+	provider := newOIDCTestProvider(t)
+	service := NewService()
+
+	configuration := &portainer.OAuthSettings{
+		OIDC:           true,
+		IssuerURL:      provider.URL,
+		ClientID:       oidcTestClientID,
+		UserIdentifier: "preferred_username",
+		GroupClaim:     "realm_access.roles",
+		Scopes:         "openid",
+	}
+
+	require.NoError(t, service.Discover(configuration))
+	assert.Equal(t, provider.URL+"/token", configuration.AccessTokenURI)
+	assert.Equal(t, provider.URL+"/keys", configuration.JWKSURI)
+
+	info, err := service.Authenticate(oidcTestCode, configuration)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", info.Username)
+	assert.Equal(t, []string{"dev", "ops"}, info.Groups)
+	assert.Equal(t, "refresh-1", info.RefreshToken)
+
+	info, err = service.Refresh(info.RefreshToken, configuration)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", info.Username)
+
+	// A token issued for another client is rejected
+	provider.claims["aud"] = "another-client"
+	_, err = service.Authenticate(oidcTestCode, configuration)
+	require.Error(t, err)
+
+	// A token signed with an unknown key is rejected
+	provider.claims["aud"] = oidcTestClientID
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	provider.key = otherKey
+
+	_, err = service.Authenticate(oidcTestCode, configuration)
+	require.Error(t, err)
+}
+
+func TestOIDC_DiscoverUnknownIssuer(t *testing.T) {
+	provider := newOIDCTestProvider(t)
+
+	assert.Error(t, NewService().Discover(&portainer.OAuthSettings{IssuerURL: provider.URL + "/realms/other"}))
+	assert.Error(t, NewService().Discover(&portainer.OAuthSettings{}))
+}
@@ -0,0 +1,225 @@
+package objectstorage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/http/client"
+	"github.com/portainer/portainer/api/internal/settingsbus"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// emptyPayloadHash is the SHA-256 of the requests without a body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3 stores the objects in an S3 compatible bucket
+type S3 struct {
+	settings   portainer.ObjectStorageSettings
+	httpClient *http.Client
+	signer     *v4.Signer
+}
+
+// NewS3 returns a new instance of S3, the requests go through the proxy of the outbound calls
+func NewS3(settings portainer.ObjectStorageSettings) *S3 {
+	return &S3{
+		settings:   settings,
+		httpClient: &http.Client{Transport: client.NewTransport()},
+		signer:     v4.NewSigner(),
+	}
+}
+
+// FromSettings returns the object storage configured in the settings, nil when it is disabled
+func FromSettings(settings portainer.ObjectStorageSettings) portainer.ObjectStorage {
+	if !settings.Enabled {
+		return nil
+	}
+
+	return NewS3(settings)
+}
+
+// SettingsListener returns a listener applying the changes of the object storage settings to a file service
+func SettingsListener(fileService portainer.FileService) settingsbus.Listener {
+	return func(change settingsbus.Change) {
+		if change.Previous.ObjectStorage == change.Current.ObjectStorage {
+			return
+		}
+
+		fileService.SetObjectStorage(FromSettings(change.Current.ObjectStorage))
+
+		log.Info().Bool("enabled", change.Current.ObjectStorage.Enabled).Msg("object storage settings applied")
+	}
+}
+
+// ValidateSettings returns an error when enabled settings are incomplete
+func ValidateSettings(settings portainer.ObjectStorageSettings) error {
+	if !settings.Enabled {
+		return nil
+	}
+
+	if settings.Bucket == "" || settings.Region == "" {
+		return errors.New("the bucket and the region of the object storage are required")
+	}
+
+	if settings.AccessKeyID == "" {
+		return errors.New("the access key of the object storage is required")
+	}
+
+	if settings.Endpoint != "" {
+		u, err := url.ParseRequestURI(settings.Endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid object storage endpoint: %w", err)
+		}
+
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return errors.New("invalid object storage endpoint, the scheme must be http or https")
+		}
+	}
+
+	return nil
+}
+
+// PutObject uploads an object, replacing the previous one
+func (storage *S3) PutObject(ctx context.Context, key string, body io.ReadSeeker) error {
+	hash := sha256.New()
+	size, err := io.Copy(hash, body)
+	if err != nil {
+		return err
+	}
+
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	req, err := storage.newRequest(ctx, http.MethodPut, key, io.NopCloser(body), hex.EncodeToString(hash.Sum(nil)))
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := storage.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// GetObject downloads an object, the reader must be closed by the caller
+func (storage *S3) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := storage.newRequest(ctx, http.MethodGet, key, nil, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := storage.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// DeleteObject removes an object, removing a missing object is not an error
+func (storage *S3) DeleteObject(ctx context.Context, key string) error {
+	req, err := storage.newRequest(ctx, http.MethodDelete, key, nil, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+
+	resp, err := storage.do(req)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (storage *S3) newRequest(ctx context.Context, method, key string, body io.ReadCloser, payloadHash string) (*http.Request, error) {
+	objectURL, err := objectURL(storage.settings, key)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	credentials := aws.Credentials{
+		AccessKeyID:     storage.settings.AccessKeyID,
+		SecretAccessKey: storage.settings.SecretAccessKey,
+	}
+
+	if err := storage.signer.SignHTTP(ctx, credentials, req, payloadHash, "s3", storage.settings.Region, time.Now()); err != nil {
+		return nil, errors.Wrap(err, "unable to sign the object storage request")
+	}
+
+	return req, nil
+}
+
+// do sends a request and turns the unsuccessful responses into errors, a missing object is reported with fs.ErrNotExist
+func (storage *S3) do(req *http.Request) (*http.Response, error) {
+	resp, err := storage.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to reach the object storage")
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("object %s: %w", req.URL.Path, fs.ErrNotExist)
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	return nil, fmt.Errorf("the object storage responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
+
+// objectURL returns the URL of an object, S3 compatible servers are addressed with path-style URLs
+func objectURL(settings portainer.ObjectStorageSettings, key string) (string, error) {
+	key = strings.TrimPrefix(path.Join(settings.Prefix, key), "/")
+
+	if settings.Endpoint == "" {
+		u := url.URL{
+			Scheme: "https",
+			Host:   fmt.Sprintf("%s.s3.%s.amazonaws.com", settings.Bucket, settings.Region),
+			Path:   "/" + key,
+		}
+
+		return u.String(), nil
+	}
+
+	u, err := url.Parse(settings.Endpoint)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid object storage endpoint")
+	}
+
+	u.Path = path.Join("/", u.Path, settings.Bucket, key)
+
+	return u.String(), nil
+}
@@ -0,0 +1,96 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		assert.NotEmpty(t, r.Header.Get("X-Amz-Content-Sha256"))
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = body
+		case http.MethodGet:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				http.Error(w, "NoSuchKey", http.StatusNotFound)
+				return
+			}
+
+			w.Write(body)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+}
+
+func TestS3(t *testing.T) {
+	srv := newTestServer(t)
+	defer srv.Close()
+
+	storage := NewS3(portainer.ObjectStorageSettings{
+		Enabled:         true,
+		Bucket:          "portainer",
+		Region:          "eu-west-1",
+		Endpoint:        srv.URL,
+		Prefix:          "instance-1",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	})
+
+	ctx := context.Background()
+
+	require.NoError(t, storage.PutObject(ctx, "edge_jobs/1/logs_2", bytes.NewReader([]byte("hello"))))
+
+	r, err := storage.GetObject(ctx, "edge_jobs/1/logs_2")
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	r.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+
+	require.NoError(t, storage.DeleteObject(ctx, "edge_jobs/1/logs_2"))
+
+	_, err = storage.GetObject(ctx, "edge_jobs/1/logs_2")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestObjectURL(t *testing.T) {
+	u, err := objectURL(portainer.ObjectStorageSettings{Bucket: "backups", Region: "us-east-1", Prefix: "/portainer"}, "archive.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "https://backups.s3.us-east-1.amazonaws.com/portainer/archive.tar.gz", u)
+
+	u, err = objectURL(portainer.ObjectStorageSettings{Bucket: "backups", Endpoint: "https://minio.corp.internal:9000/s3"}, "archive.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, "https://minio.corp.internal:9000/s3/backups/archive.tar.gz", u)
+}
+
+func TestValidateSettings(t *testing.T) {
+	assert.NoError(t, ValidateSettings(portainer.ObjectStorageSettings{}))
+	assert.NoError(t, ValidateSettings(portainer.ObjectStorageSettings{Enabled: true, Bucket: "portainer", Region: "us-east-1", AccessKeyID: "AKID"}))
+	assert.Error(t, ValidateSettings(portainer.ObjectStorageSettings{Enabled: true, Bucket: "portainer", AccessKeyID: "AKID"}))
+	assert.Error(t, ValidateSettings(portainer.ObjectStorageSettings{Enabled: true, Bucket: "portainer", Region: "us-east-1", AccessKeyID: "AKID", Endpoint: "ftp://minio"}))
+}
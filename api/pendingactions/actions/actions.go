@@ -2,6 +2,8 @@ package actions
 
 const (
 	CleanNAPWithOverridePolicies      = "CleanNAPWithOverridePolicies"
+	CollectEdgeJobLogs                = "CollectEdgeJobLogs"
 	DeletePortainerK8sRegistrySecrets = "DeletePortainerK8sRegistrySecrets"
+	DeployStack                       = "DeployStack"
 	PostInitMigrateEnvironment        = "PostInitMigrateEnvironment"
 )
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/internal/edge/cache"
+	"github.com/portainer/portainer/api/pendingactions"
+	"github.com/portainer/portainer/api/pendingactions/actions"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// EdgeJobLogsCollectionRetryInterval is the minimum delay between two attempts to collect the logs of an edge job task
+	EdgeJobLogsCollectionRetryInterval = 5 * time.Minute
+	// EdgeJobLogsCollectionExpiry is the delay after which a logs collection that was not fulfilled by the agent is abandoned
+	EdgeJobLogsCollectionExpiry = 24 * time.Hour
+)
+
+type (
+	HandlerCollectEdgeJobLogs struct {
+		dataStore dataservices.DataStore
+	}
+
+	collectEdgeJobLogsData struct {
+		EdgeJobID   portainer.EdgeJobID `json:"EdgeJobID"`
+		Attempts    int                 `json:"Attempts"`
+		LastAttempt int64               `json:"LastAttempt"`
+	}
+
+	// PendingEdgeJobLogsCollection represents a logs collection that was requested but not fulfilled by the agent yet
+	PendingEdgeJobLogsCollection struct {
+		EdgeJobID  portainer.EdgeJobID  `json:"EdgeJobId" example:"1"`
+		EndpointID portainer.EndpointID `json:"EndpointId" example:"1"`
+		// Unix timestamp of the request
+		RequestedAt int64 `json:"RequestedAt" example:"1708000000"`
+		// Number of times the collection was requested to the agent
+		Attempts int `json:"Attempts" example:"2"`
+		// Unix timestamp of the last attempt, 0 when no attempt was made yet
+		LastAttempt int64 `json:"LastAttempt" example:"1708000300"`
+		// Unix timestamp after which the collection is abandoned
+		ExpiresAt int64 `json:"ExpiresAt" example:"1708086400"`
+	}
+)
+
+// NewCollectEdgeJobLogs creates a new CollectEdgeJobLogs pending action
+func NewCollectEdgeJobLogs(endpointID portainer.EndpointID, edgeJobID portainer.EdgeJobID) portainer.PendingAction {
+	return portainer.PendingAction{
+		EndpointID: endpointID,
+		Action:     actions.CollectEdgeJobLogs,
+		ActionData: &collectEdgeJobLogsData{EdgeJobID: edgeJobID},
+	}
+}
+
+// NewHandlerCollectEdgeJobLogs creates a new handler to execute CollectEdgeJobLogs pending action
+func NewHandlerCollectEdgeJobLogs(dataStore dataservices.DataStore) *HandlerCollectEdgeJobLogs {
+	return &HandlerCollectEdgeJobLogs{dataStore: dataStore}
+}
+
+// QueueEdgeJobLogsCollection persists a logs collection request, a request already queued for the same task is restarted
+func QueueEdgeJobLogsCollection(tx dataservices.DataStoreTx, endpointID portainer.EndpointID, edgeJobID portainer.EdgeJobID) error {
+	pendingAction, _, err := findEdgeJobLogsCollection(tx, endpointID, edgeJobID)
+	if err != nil {
+		return err
+	}
+
+	if pendingAction == nil {
+		action := NewCollectEdgeJobLogs(endpointID, edgeJobID)
+
+		return tx.PendingActions().Create(&action)
+	}
+
+	pendingAction.ActionData = &collectEdgeJobLogsData{EdgeJobID: edgeJobID}
+	pendingAction.CreatedAt = time.Now().Unix()
+
+	return tx.PendingActions().Update(pendingAction.ID, pendingAction)
+}
+
+// DequeueEdgeJobLogsCollection removes the logs collection request of the task, if any
+func DequeueEdgeJobLogsCollection(tx dataservices.DataStoreTx, endpointID portainer.EndpointID, edgeJobID portainer.EdgeJobID) error {
+	pendingAction, _, err := findEdgeJobLogsCollection(tx, endpointID, edgeJobID)
+	if err != nil || pendingAction == nil {
+		return err
+	}
+
+	return tx.PendingActions().Delete(pendingAction.ID)
+}
+
+// PendingEdgeJobLogsCollections returns the logs collections that are waiting for the agent,
+// all the environments are included when endpointID is 0
+func PendingEdgeJobLogsCollections(tx dataservices.DataStoreTx, endpointID portainer.EndpointID) ([]PendingEdgeJobLogsCollection, error) {
+	pendingActions, err := tx.PendingActions().ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pending actions: %w", err)
+	}
+
+	collections := []PendingEdgeJobLogsCollection{}
+	for _, pendingAction := range pendingActions {
+		if pendingAction.Action != actions.CollectEdgeJobLogs || (endpointID != 0 && pendingAction.EndpointID != endpointID) {
+			continue
+		}
+
+		var data collectEdgeJobLogsData
+		if err := pendingAction.UnmarshallActionData(&data); err != nil {
+			return nil, err
+		}
+
+		collections = append(collections, PendingEdgeJobLogsCollection{
+			EdgeJobID:   data.EdgeJobID,
+			EndpointID:  pendingAction.EndpointID,
+			RequestedAt: pendingAction.CreatedAt,
+			Attempts:    data.Attempts,
+			LastAttempt: data.LastAttempt,
+			ExpiresAt:   pendingAction.CreatedAt + int64(EdgeJobLogsCollectionExpiry.Seconds()),
+		})
+	}
+
+	return collections, nil
+}
+
+func findEdgeJobLogsCollection(tx dataservices.DataStoreTx, endpointID portainer.EndpointID, edgeJobID portainer.EdgeJobID) (*portainer.PendingAction, *collectEdgeJobLogsData, error) {
+	pendingActions, err := tx.PendingActions().ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve pending actions: %w", err)
+	}
+
+	for _, pendingAction := range pendingActions {
+		if pendingAction.Action != actions.CollectEdgeJobLogs || pendingAction.EndpointID != endpointID {
+			continue
+		}
+
+		var data collectEdgeJobLogsData
+		if err := pendingAction.UnmarshallActionData(&data); err != nil {
+			return nil, nil, err
+		}
+
+		if data.EdgeJobID == edgeJobID {
+			return &pendingAction, &data, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// Execute asks the agent to collect the logs again when the previous attempt was not fulfilled,
+// the pending action is kept until the logs are received or the collection expires
+func (h *HandlerCollectEdgeJobLogs) Execute(pa portainer.PendingAction, endpoint *portainer.Endpoint) error {
+	if endpoint == nil || pa.ActionData == nil {
+		return nil
+	}
+
+	var data collectEdgeJobLogsData
+	if err := pa.UnmarshallActionData(&data); err != nil {
+		return err
+	}
+
+	retry := false
+	if err := h.dataStore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		edgeJob, err := tx.EdgeJob().Read(data.EdgeJobID)
+		if tx.IsErrObjectNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		metas := edgeJob.Endpoints
+		if _, ok := edgeJob.GroupLogsCollection[endpoint.ID]; ok {
+			metas = edgeJob.GroupLogsCollection
+		}
+
+		meta, ok := metas[endpoint.ID]
+		if !ok || meta.LogsStatus != portainer.EdgeJobLogsStatusPending {
+			return nil
+		}
+
+		now := time.Now()
+
+		if now.After(time.Unix(pa.CreatedAt, 0).Add(EdgeJobLogsCollectionExpiry)) {
+			log.Warn().
+				Int("edge_job_id", int(data.EdgeJobID)).
+				Int("endpoint_id", int(endpoint.ID)).
+				Int("attempts", data.Attempts).
+				Msg("the logs collection of the edge job expired before the agent sent the logs")
+
+			metas[endpoint.ID] = portainer.EdgeJobEndpointMeta{CollectLogs: false, LogsStatus: portainer.EdgeJobLogsStatusIdle}
+			if err := tx.EdgeJob().Update(edgeJob.ID, edgeJob); err != nil {
+				return err
+			}
+
+			cache.Del(endpoint.ID)
+
+			return nil
+		}
+
+		retry = true
+
+		if now.Before(time.Unix(data.LastAttempt, 0).Add(EdgeJobLogsCollectionRetryInterval)) {
+			return nil
+		}
+
+		if !meta.CollectLogs {
+			meta.CollectLogs = true
+			metas[endpoint.ID] = meta
+
+			if err := tx.EdgeJob().Update(edgeJob.ID, edgeJob); err != nil {
+				return err
+			}
+		}
+
+		// Drop the cached status so that the agent receives the collection request on its next poll
+		cache.Del(endpoint.ID)
+
+		data.Attempts++
+		data.LastAttempt = now.Unix()
+		pa.ActionData = &data
+
+		return tx.PendingActions().Update(pa.ID, &pa)
+	}); err != nil {
+		return fmt.Errorf("failed to collect the logs of edge job %d for environment %d: %w", data.EdgeJobID, endpoint.ID, err)
+	}
+
+	if retry {
+		return pendingactions.ErrRetryLater
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	dockerclient "github.com/portainer/portainer/api/docker/client"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/pendingactions"
+	"github.com/portainer/portainer/api/pendingactions/actions"
+	"github.com/portainer/portainer/api/stacks/deployments"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// StackDeploymentQueueDefaultTTL is the delay after which a queued deployment is abandoned when none is requested
+	StackDeploymentQueueDefaultTTL = 24 * time.Hour
+	// StackDeploymentQueueMaxTTL is the longest delay a deployment can be queued for
+	StackDeploymentQueueMaxTTL = 7 * 24 * time.Hour
+)
+
+type (
+	HandlerDeployStack struct {
+		dataStore     dataservices.DataStore
+		fileService   portainer.FileService
+		stackDeployer deployments.StackDeployer
+		clientFactory *dockerclient.ClientFactory
+	}
+
+	deployStackData struct {
+		StackID   portainer.StackID `json:"StackID"`
+		UserID    portainer.UserID  `json:"UserID"`
+		PullImage bool              `json:"PullImage"`
+		Prune     bool              `json:"Prune"`
+		ExpiresAt int64             `json:"ExpiresAt"`
+	}
+
+	// QueuedStackDeployment represents the deployment of a stack waiting for its environment to be reachable again
+	QueuedStackDeployment struct {
+		StackID    portainer.StackID    `json:"StackId" example:"1"`
+		EndpointID portainer.EndpointID `json:"EndpointId" example:"1"`
+		// Identifier of the user who requested the deployment
+		UserID portainer.UserID `json:"UserId" example:"1"`
+		// Unix timestamp of the request
+		QueuedAt int64 `json:"QueuedAt" example:"1708000000"`
+		// Unix timestamp after which the deployment is abandoned
+		ExpiresAt int64 `json:"ExpiresAt" example:"1708086400"`
+	}
+)
+
+// NewHandlerDeployStack creates a new handler to execute DeployStack pending action
+func NewHandlerDeployStack(dataStore dataservices.DataStore, fileService portainer.FileService, stackDeployer deployments.StackDeployer, clientFactory *dockerclient.ClientFactory) *HandlerDeployStack {
+	return &HandlerDeployStack{
+		dataStore:     dataStore,
+		fileService:   fileService,
+		stackDeployer: stackDeployer,
+		clientFactory: clientFactory,
+	}
+}
+
+// QueueStackDeployment persists the deployment of a Docker stack from its current files, it is run once its
+// environment is reachable again and abandoned after ttl. A deployment already queued for the stack is replaced
+func QueueStackDeployment(tx dataservices.DataStoreTx, stack *portainer.Stack, userID portainer.UserID, pullImage, prune bool, ttl time.Duration) (*QueuedStackDeployment, error) {
+	if err := DequeueStackDeployment(tx, stack.ID); err != nil {
+		return nil, err
+	}
+
+	data := &deployStackData{
+		StackID:   stack.ID,
+		UserID:    userID,
+		PullImage: pullImage,
+		Prune:     prune,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}
+
+	pendingAction := portainer.PendingAction{
+		EndpointID: stack.EndpointID,
+		Action:     actions.DeployStack,
+		ActionData: data,
+	}
+
+	if err := tx.PendingActions().Create(&pendingAction); err != nil {
+		return nil, fmt.Errorf("failed to queue the deployment of stack %d: %w", stack.ID, err)
+	}
+
+	return newQueuedStackDeployment(pendingAction, data), nil
+}
+
+// DequeueStackDeployment removes the queued deployment of the stack, if any
+func DequeueStackDeployment(tx dataservices.DataStoreTx, stackID portainer.StackID) error {
+	pendingActions, err := tx.PendingActions().ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve pending actions: %w", err)
+	}
+
+	for _, pendingAction := range pendingActions {
+		if pendingAction.Action != actions.DeployStack {
+			continue
+		}
+
+		var data deployStackData
+		if err := pendingAction.UnmarshallActionData(&data); err != nil {
+			return err
+		}
+
+		if data.StackID != stackID {
+			continue
+		}
+
+		if err := tx.PendingActions().Delete(pendingAction.ID); err != nil {
+			return fmt.Errorf("failed to remove the queued deployment of stack %d: %w", stackID, err)
+		}
+	}
+
+	return nil
+}
+
+// QueuedStackDeployments returns the deployments waiting for their environment, all the environments are included
+// when endpointID is 0
+func QueuedStackDeployments(tx dataservices.DataStoreTx, endpointID portainer.EndpointID) ([]QueuedStackDeployment, error) {
+	pendingActions, err := tx.PendingActions().ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pending actions: %w", err)
+	}
+
+	queued := []QueuedStackDeployment{}
+	for _, pendingAction := range pendingActions {
+		if pendingAction.Action != actions.DeployStack || (endpointID != 0 && pendingAction.EndpointID != endpointID) {
+			continue
+		}
+
+		var data deployStackData
+		if err := pendingAction.UnmarshallActionData(&data); err != nil {
+			return nil, err
+		}
+
+		queued = append(queued, *newQueuedStackDeployment(pendingAction, &data))
+	}
+
+	return queued, nil
+}
+
+func newQueuedStackDeployment(pendingAction portainer.PendingAction, data *deployStackData) *QueuedStackDeployment {
+	return &QueuedStackDeployment{
+		StackID:    data.StackID,
+		EndpointID: pendingAction.EndpointID,
+		UserID:     data.UserID,
+		QueuedAt:   pendingAction.CreatedAt,
+		ExpiresAt:  data.ExpiresAt,
+	}
+}
+
+// Execute deploys the stack once its environment is reachable again. The deployments failing for another reason
+// and the expired ones are abandoned
+func (h *HandlerDeployStack) Execute(pa portainer.PendingAction, endpoint *portainer.Endpoint) error {
+	if endpoint == nil || pa.ActionData == nil {
+		return nil
+	}
+
+	var data deployStackData
+	if err := pa.UnmarshallActionData(&data); err != nil {
+		return err
+	}
+
+	logger := log.With().Int("stack_id", int(data.StackID)).Int("endpoint_id", int(endpoint.ID)).Logger()
+
+	if time.Now().Unix() > data.ExpiresAt {
+		logger.Warn().Msg("the queued deployment of the stack expired before its environment was reachable again")
+
+		return nil
+	}
+
+	stack, err := h.dataStore.Stack().Read(data.StackID)
+	if h.dataStore.IsErrObjectNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to retrieve stack %d: %w", data.StackID, err)
+	}
+
+	// The stack was moved to another environment since the deployment was queued
+	if stack.EndpointID != endpoint.ID {
+		return nil
+	}
+
+	user, err := h.dataStore.User().Read(data.UserID)
+	if h.dataStore.IsErrObjectNotFound(err) {
+		logger.Warn().Msg("the user who queued the deployment of the stack does not exist anymore")
+
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to retrieve user %d: %w", data.UserID, err)
+	}
+
+	memberships, err := h.dataStore.TeamMembership().TeamMembershipsByUserID(user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve the memberships of user %d: %w", user.ID, err)
+	}
+
+	securityContext := &security.RestrictedRequestContext{
+		IsAdmin:         user.Role == portainer.AdministratorRole,
+		UserID:          user.ID,
+		UserMemberships: memberships,
+	}
+
+	var config deployments.StackDeploymentConfiger
+
+	switch stack.Type {
+	case portainer.DockerComposeStack:
+		config, err = deployments.CreateComposeStackDeploymentConfig(securityContext, stack, endpoint, h.dataStore, h.fileService, h.stackDeployer, data.PullImage, false)
+	case portainer.DockerSwarmStack:
+		config, err = deployments.CreateSwarmStackDeploymentConfig(securityContext, stack, endpoint, h.dataStore, h.fileService, h.stackDeployer, data.Prune, data.PullImage)
+	default:
+		logger.Warn().Int("stack_type", int(stack.Type)).Msg("the deployments of this type of stack cannot be queued")
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create the deployment config of stack %d: %w", stack.ID, err)
+	}
+
+	if err := config.Deploy(); err != nil {
+		// The environment went down again
+		if !deployments.IsEnvironmentReachable(h.clientFactory, endpoint) {
+			return pendingactions.ErrRetryLater
+		}
+
+		logger.Error().Err(err).Msg("unable to deploy the queued stack")
+
+		return nil
+	}
+
+	stack.Status = portainer.StackStatusActive
+	stack.UpdateDate = time.Now().Unix()
+
+	if err := h.dataStore.Stack().Update(stack.ID, stack); err != nil {
+		logger.Warn().Err(err).Msg("unable to persist the stack changes after its queued deployment")
+	}
+
+	logger.Info().Msg("the queued deployment of the stack is done")
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueStackDeployment(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, false, false)
+
+	web := &portainer.Stack{ID: 1, EndpointID: 1}
+	db := &portainer.Stack{ID: 2, EndpointID: 2}
+
+	err := store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		if _, err := QueueStackDeployment(tx, web, 1, false, false, time.Hour); err != nil {
+			return err
+		}
+
+		if _, err := QueueStackDeployment(tx, db, 1, true, true, time.Hour); err != nil {
+			return err
+		}
+
+		// The deployment queued again replaces the previous one
+		queued, err := QueueStackDeployment(tx, web, 2, true, false, StackDeploymentQueueDefaultTTL)
+		if err != nil {
+			return err
+		}
+
+		assert.Equal(t, portainer.UserID(2), queued.UserID)
+		assert.InDelta(t, queued.QueuedAt+int64(StackDeploymentQueueDefaultTTL.Seconds()), queued.ExpiresAt, 1)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = store.ViewTx(func(tx dataservices.DataStoreTx) error {
+		queued, err := QueuedStackDeployments(tx, 1)
+		require.NoError(t, err)
+		require.Len(t, queued, 1)
+		assert.Equal(t, web.ID, queued[0].StackID)
+		assert.Equal(t, portainer.UserID(2), queued[0].UserID)
+
+		queued, err = QueuedStackDeployments(tx, 0)
+		require.NoError(t, err)
+		assert.Len(t, queued, 2)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = store.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return DequeueStackDeployment(tx, web.ID)
+	})
+	require.NoError(t, err)
+
+	err = store.ViewTx(func(tx dataservices.DataStoreTx) error {
+		queued, err := QueuedStackDeployments(tx, 0)
+		require.NoError(t, err)
+		require.Len(t, queued, 1)
+		assert.Equal(t, db.ID, queued[0].StackID)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -1,6 +1,7 @@
 package pendingactions
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -20,6 +21,10 @@ type PendingActionsService struct {
 
 var handlers = make(map[string]portainer.PendingActionHandler)
 
+// ErrRetryLater is returned by the handlers of the pending actions that are not over yet,
+// the pending action is kept and executed again the next time the environment is reached
+var ErrRetryLater = errors.New("pending action will be retried later")
+
 func NewService(
 	dataStore dataservices.DataStore,
 	kubeFactory *kubecli.ClientFactory,
@@ -111,7 +116,10 @@ func (service *PendingActionsService) execute(environmentID portainer.EndpointID
 
 			log.Debug().Msgf("executing pending action id=%d, action=%s", pendingAction.ID, pendingAction.Action)
 			err := service.executePendingAction(pendingAction, endpoint)
-			if err != nil {
+			if errors.Is(err, ErrRetryLater) {
+				log.Debug().Msgf("pending action %d will be retried later", pendingAction.ID)
+				continue
+			} else if err != nil {
 				log.Warn().Msgf("failed to execute pending action: %v", err)
 				continue
 			}
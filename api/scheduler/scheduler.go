@@ -127,3 +127,27 @@ func (s *Scheduler) StartJobEvery(duration time.Duration, job func() error) stri
 
 	return strconv.Itoa(int(*entryID))
 }
+
+// StartJobCron schedules a new job following a standard cron expression.
+// Returns job id that could be used to stop the given job.
+// Unlike StartJobEvery, a failing run does not prevent the next runs.
+func (s *Scheduler) StartJobCron(cronExpression string, job func() error) (string, error) {
+	schedule, err := cron.ParseStandard(cronExpression)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse cron expression %q", cronExpression)
+	}
+
+	entryID := s.crontab.Schedule(schedule, cron.FuncJob(func() {
+		if err := job(); err != nil {
+			log.Error().Err(err).Msg("job returned an error")
+		}
+	}))
+
+	s.mu.Lock()
+	s.activeJobs[entryID] = func() {
+		s.crontab.Remove(entryID)
+	}
+	s.mu.Unlock()
+
+	return strconv.Itoa(int(entryID)), nil
+}
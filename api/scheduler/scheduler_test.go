@@ -138,3 +138,23 @@ func Test_StartJobEvery_Concurrently(t *testing.T) {
 
 	<-ctx.Done()
 }
+
+func Test_StartJobCron_InvalidExpression(t *testing.T) {
+	s := NewScheduler(context.Background())
+	defer s.Shutdown()
+
+	_, err := s.StartJobCron("not a cron expression", func() error { return nil })
+	assert.Error(t, err)
+}
+
+func Test_StartJobCron_CanBeStopped(t *testing.T) {
+	s := NewScheduler(context.Background())
+	defer s.Shutdown()
+
+	jobID, err := s.StartJobCron("@every 1s", func() error { return nil })
+	assert.NoError(t, err)
+	assert.Len(t, s.crontab.Entries(), 1)
+
+	assert.NoError(t, s.StopJob(jobID))
+	assert.Empty(t, s.crontab.Entries())
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+)
+
+// MaxPreviewedRuns is the maximum number of run times returned by NextRuns
+const MaxPreviewedRuns = 100
+
+// ValidateTimeZone returns an error when the time zone is not an IANA time zone name.
+// The empty time zone stands for the time zone of the server
+func ValidateTimeZone(timeZone string) error {
+	if timeZone == "" {
+		return nil
+	}
+
+	// Local depends on the host running the cron expression
+	if timeZone == "Local" {
+		return errors.New("the time zone must be an IANA time zone name")
+	}
+
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return errors.Wrapf(err, "invalid time zone %q", timeZone)
+	}
+
+	return nil
+}
+
+// CronWithTimeZone prefixes a cron expression with the time zone it runs in. The cron parsers of the
+// server and of the agents then compute the run times in local time of that zone, so that the runs
+// follow the daylight saving time changes
+func CronWithTimeZone(cronExpression, timeZone string) string {
+	if timeZone == "" {
+		return cronExpression
+	}
+
+	return "CRON_TZ=" + timeZone + " " + strings.TrimSpace(cronExpression)
+}
+
+// CronAt returns a cron expression matching the minute of a time, in UTC. The agents only know about
+// cron expressions, a one-time job is sent to them as the cron expression of its run time
+func CronAt(t time.Time) string {
+	t = t.UTC()
+
+	return fmt.Sprintf("CRON_TZ=UTC %d %d %d %d *", t.Minute(), t.Hour(), t.Day(), int(t.Month()))
+}
+
+// NextRuns returns the next count run times of a standard cron expression after a time, in the given time zone
+func NextRuns(cronExpression, timeZone string, after time.Time, count int) ([]time.Time, error) {
+	if err := ValidateTimeZone(timeZone); err != nil {
+		return nil, err
+	}
+
+	schedule, err := cron.ParseStandard(CronWithTimeZone(cronExpression, timeZone))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse cron expression %q", cronExpression)
+	}
+
+	count = min(count, MaxPreviewedRuns)
+
+	runs := make([]time.Time, 0, count)
+	for next := after; len(runs) < count; {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			// The expression never matches, e.g. on February 30th
+			break
+		}
+
+		runs = append(runs, next)
+	}
+
+	return runs, nil
+}
@@ -0,0 +1,65 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTimeZone(t *testing.T) {
+	assert.NoError(t, ValidateTimeZone(""))
+	assert.NoError(t, ValidateTimeZone("Europe/Paris"))
+	assert.Error(t, ValidateTimeZone("Local"))
+	assert.Error(t, ValidateTimeZone("Mars/Olympus_Mons"))
+}
+
+func TestCronWithTimeZone(t *testing.T) {
+	assert.Equal(t, "0 9 * * *", CronWithTimeZone("0 9 * * *", ""))
+	assert.Equal(t, "CRON_TZ=Asia/Tokyo 0 9 * * *", CronWithTimeZone(" 0 9 * * *", "Asia/Tokyo"))
+}
+
+func TestNextRuns(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// The daylight saving time starts on March 10th 2024 in New York
+	after := time.Date(2024, time.March, 8, 15, 0, 0, 0, time.UTC)
+
+	runs, err := NextRuns("0 9 * * *", "America/New_York", after, 3)
+	require.NoError(t, err)
+	require.Len(t, runs, 3)
+
+	expected := []time.Time{
+		time.Date(2024, time.March, 9, 14, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 10, 13, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 11, 13, 0, 0, 0, time.UTC),
+	}
+
+	for i, run := range runs {
+		assert.True(t, expected[i].Equal(run), "run %d is %s", i, run)
+		assert.Equal(t, 9, run.In(newYork).Hour())
+	}
+
+	// The time zone of the server is used without a time zone
+	runs, err = NextRuns("0 9 * * *", "", after, MaxPreviewedRuns+1)
+	require.NoError(t, err)
+	assert.Len(t, runs, MaxPreviewedRuns)
+
+	_, err = NextRuns("every day", "", after, 1)
+	assert.Error(t, err)
+}
+
+func TestCronAt(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	runAt := time.Date(2025, time.July, 14, 22, 30, 45, 0, paris)
+	assert.Equal(t, "CRON_TZ=UTC 30 20 14 7 *", CronAt(runAt))
+
+	runs, err := NextRuns("30 20 14 7 *", "UTC", runAt.Add(-time.Hour), 1)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.True(t, runAt.Truncate(time.Minute).Equal(runs[0]))
+}
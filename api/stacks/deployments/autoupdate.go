@@ -11,19 +11,29 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// StartAutoupdate schedules the GitOps polling of a stack, each run checks its repository, redeploys the
+// stack when it changed and records the result in the GitOps status of the stack
 func StartAutoupdate(stackID portainer.StackID, interval string, scheduler *scheduler.Scheduler, stackDeployer StackDeployer, datastore dataservices.DataStore, gitService portainer.GitService) (jobID string, e *httperror.HandlerError) {
 	d, err := time.ParseDuration(interval)
 	if err != nil {
 		return "", httperror.BadRequest("Unable to parse stack's auto update interval", err)
 	}
 
-	jobID = scheduler.StartJobEvery(d, func() error {
-		return RedeployWhenChanged(stackID, stackDeployer, datastore, gitService)
-	})
+	jobID = scheduler.StartJobEvery(d, pollStack(stackID, d, stackDeployer, datastore, gitService))
 
 	return jobID, nil
 }
 
+// pollStack returns the polling job of a stack. Each run waits for a random delay first, so that the
+// stacks scheduled at the same time do not all poll their repository at once
+func pollStack(stackID portainer.StackID, interval time.Duration, stackDeployer StackDeployer, datastore dataservices.DataStore, gitService portainer.GitService) func() error {
+	return func() error {
+		time.Sleep(pollJitter(interval))
+
+		return RedeployWhenChanged(stackID, stackDeployer, datastore, gitService)
+	}
+}
+
 func StopAutoupdate(stackID portainer.StackID, jobID string, scheduler *scheduler.Scheduler) {
 	if jobID == "" {
 		return
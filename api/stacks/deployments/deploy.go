@@ -11,8 +11,10 @@ import (
 	"github.com/portainer/portainer/api/agent"
 	"github.com/portainer/portainer/api/crypto"
 	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/git"
 	"github.com/portainer/portainer/api/git/update"
-	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/events"
+	"github.com/portainer/portainer/api/internal/registryutils/access"
 	"github.com/portainer/portainer/api/scheduler"
 	"github.com/portainer/portainer/api/stacks/stackutils"
 
@@ -33,8 +35,18 @@ func (e *StackAuthorMissingErr) Error() string {
 var singleflightGroup = &singleflight.Group{}
 
 // RedeployWhenChanged pull and redeploy the stack when git repo changed
-// Stack will always be redeployed if force deployment is set to true
+// The stacks whose GitOps updates are paused are skipped
 func RedeployWhenChanged(stackID portainer.StackID, deployer StackDeployer, datastore dataservices.DataStore, gitService portainer.GitService) error {
+	return redeploy(stackID, deployer, datastore, gitService, false)
+}
+
+// ForceResync pull and redeploy the stack even when its git repo did not change, paused stacks included.
+// A resync requested while the stack is being polled waits for the poll and returns its result
+func ForceResync(stackID portainer.StackID, deployer StackDeployer, datastore dataservices.DataStore, gitService portainer.GitService) error {
+	return redeploy(stackID, deployer, datastore, gitService, true)
+}
+
+func redeploy(stackID portainer.StackID, deployer StackDeployer, datastore dataservices.DataStore, gitService portainer.GitService, force bool) error {
 	stack, err := datastore.Stack().Read(stackID)
 	if dataservices.IsErrObjectNotFound(err) {
 		return scheduler.NewPermanentError(errors.WithMessagef(err, "failed to get the stack %v", stackID))
@@ -42,37 +54,58 @@ func RedeployWhenChanged(stackID portainer.StackID, deployer StackDeployer, data
 		return errors.WithMessagef(err, "failed to get the stack %v", stackID)
 	}
 
+	if !force {
+		if paused, err := gitOpsPaused(datastore, stackID); err != nil {
+			return errors.WithMessagef(err, "failed to get the GitOps status of the stack %v", stackID)
+		} else if paused {
+			log.Debug().Int("stack_id", int(stackID)).Msg("the GitOps updates of the stack are paused")
+
+			return nil
+		}
+	}
+
 	// Webhook
-	if stack.AutoUpdate != nil && stack.AutoUpdate.Webhook != "" {
-		return redeployWhenChanged(stack, deployer, datastore, gitService, true)
+	if !force && stack.AutoUpdate != nil && stack.AutoUpdate.Webhook != "" {
+		return redeployWhenChanged(stack, deployer, datastore, sharedPoller.gitService(gitService, 0), true, false)
+	}
+
+	// Polling, the stacks tracking the same repository share its latest commit
+	if !force {
+		gitService = sharedPoller.gitService(gitService, pollMaxAge(stack.AutoUpdate))
 	}
 
-	// Polling
 	_, err, _ = singleflightGroup.Do(strconv.Itoa(int(stackID)), func() (any, error) {
-		return nil, redeployWhenChanged(stack, deployer, datastore, gitService, false)
+		return nil, redeployWhenChanged(stack, deployer, datastore, gitService, false, force)
 	})
 
 	return err
 }
 
-func redeployWhenChanged(stack *portainer.Stack, deployer StackDeployer, datastore dataservices.DataStore, gitService portainer.GitService, webhook bool) error {
+func redeployWhenChanged(stack *portainer.Stack, deployer StackDeployer, datastore dataservices.DataStore, gitService portainer.GitService, webhook, force bool) error {
 	log.Debug().Int("stack_id", int(stack.ID)).Msg("redeploying stack")
 
 	if stack.GitConfig == nil {
 		return nil // do nothing if it isn't a git-based stack
 	}
 
+	// The errors preventing the check of the repository are recorded in the GitOps status of the stack
+	fail := func(err error) error {
+		recordGitOpsSync(datastore, stack.ID, gitOpsSync{}, err)
+
+		return err
+	}
+
 	endpoint, err := datastore.Endpoint().Endpoint(stack.EndpointID)
 	if dataservices.IsErrObjectNotFound(err) {
-		return scheduler.NewPermanentError(
+		return fail(scheduler.NewPermanentError(
 			errors.WithMessagef(err,
 				"failed to find the environment %v associated to the stack %v",
 				stack.EndpointID,
 				stack.ID,
 			),
-		)
+		))
 	} else if err != nil {
-		return errors.WithMessagef(err, "failed to find the environment %v associated to the stack %v", stack.EndpointID, stack.ID)
+		return fail(errors.WithMessagef(err, "failed to find the environment %v associated to the stack %v", stack.EndpointID, stack.ID))
 	}
 
 	author := cmp.Or(stack.UpdatedBy, stack.CreatedBy)
@@ -86,7 +119,7 @@ func redeployWhenChanged(stack *portainer.Stack, deployer StackDeployer, datasto
 			Int("endpoint_id", int(stack.EndpointID)).
 			Msg("cannot auto update a stack, stack author user is missing")
 
-		return &StackAuthorMissingErr{int(stack.ID), author}
+		return fail(&StackAuthorMissingErr{int(stack.ID), author})
 	}
 
 	if !isEnvironmentOnline(endpoint) {
@@ -95,7 +128,7 @@ func redeployWhenChanged(stack *portainer.Stack, deployer StackDeployer, datasto
 
 	if webhook {
 		go func() {
-			if err := redeployWhenChangedSecondStage(stack, deployer, datastore, gitService, user, endpoint); err != nil {
+			if err := redeployWhenChangedSecondStage(stack, deployer, datastore, gitService, user, endpoint, false); err != nil {
 				log.Error().Err(err).
 					Int("stack_id", int(stack.ID)).
 					Str("stack", stack.Name).
@@ -108,7 +141,7 @@ func redeployWhenChanged(stack *portainer.Stack, deployer StackDeployer, datasto
 		return nil
 	}
 
-	return redeployWhenChangedSecondStage(stack, deployer, datastore, gitService, user, endpoint)
+	return redeployWhenChangedSecondStage(stack, deployer, datastore, gitService, user, endpoint, force)
 }
 
 func redeployWhenChangedSecondStage(
@@ -118,17 +151,48 @@ func redeployWhenChangedSecondStage(
 	gitService portainer.GitService,
 	user *portainer.User,
 	endpoint *portainer.Endpoint,
-) error {
+	force bool,
+) (err error) {
+	sync := gitOpsSync{appliedCommit: stack.GitConfig.ConfigHash}
+	defer func() {
+		recordGitOpsSync(datastore, stack.ID, sync, err)
+	}()
+
 	var gitCommitChangedOrForceUpdate bool
 
 	if !stack.FromAppTemplate {
-		updated, newHash, err := update.UpdateGitObject(gitService, fmt.Sprintf("stack:%d", stack.ID), stack.GitConfig, false, false, stack.ProjectPath)
+		// The stack is redeployed only when the new commit changes the directories of its files
+		var digest string
+		if !force {
+			digest, _ = stackFilesDigest(stack)
+		}
+
+		updated, newHash, err := update.UpdateGitObject(gitService, fmt.Sprintf("stack:%d", stack.ID), stack.GitConfig, force, false, stack.ProjectPath)
 		if err != nil {
 			return err
 		}
 
+		sync.checkedCommit = newHash
+
 		if updated {
 			stack.GitConfig.ConfigHash = newHash
+
+			username, password, _ := git.GetCredentials(stack.GitConfig.Authentication)
+			sshPrivateKey, sshPassphrase := git.GetSSHCredentials(stack.GitConfig.Authentication)
+			stackutils.SetConfigCommit(gitService, stack.GitConfig, username, password, sshPrivateKey, sshPassphrase)
+
+			if newDigest, err := stackFilesDigest(stack); digest != "" && err == nil && newDigest == digest {
+				log.Debug().Int("stack_id", int(stack.ID)).Str("hash", newHash).Msg("the new commit does not change the stack files")
+
+				if err := datastore.Stack().Update(stack.ID, stack); err != nil {
+					return errors.WithMessagef(err, "failed to update the stack %v", stack.ID)
+				}
+
+				sync.appliedCommit = newHash
+
+				return nil
+			}
+
 			stack.UpdateDate = time.Now().Unix()
 			gitCommitChangedOrForceUpdate = updated
 		}
@@ -181,6 +245,17 @@ func redeployWhenChangedSecondStage(
 		return errors.WithMessagef(err, "failed to update the stack %v", stack.ID)
 	}
 
+	sync.appliedCommit = stack.GitConfig.ConfigHash
+	sync.deployed = true
+
+	events.Publish(events.Event{
+		Type:         portainer.NotificationEventAutoUpdateApplied,
+		EndpointID:   endpoint.ID,
+		EndpointName: endpoint.Name,
+		Title:        "Stack updated",
+		Message:      fmt.Sprintf("The stack %s was redeployed with the commit %s of its git repository", stack.Name, stack.GitConfig.ConfigHash),
+	})
+
 	return nil
 }
 
@@ -199,11 +274,9 @@ func getUserRegistries(datastore dataservices.DataStore, user *portainer.User, e
 		return nil, errors.WithMessagef(err, "failed to fetch memberships of the stack author [%s]", user.Username)
 	}
 
-	filteredRegistries := make([]portainer.Registry, 0, len(registries))
-	for _, registry := range registries {
-		if security.AuthorizedRegistryAccess(&registry, user, userMemberships, endpointID) {
-			filteredRegistries = append(filteredRegistries, registry)
-		}
+	filteredRegistries, err := access.FilterDeploymentRegistries(datastore, registries, user, userMemberships, endpointID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to fetch the default registries of the teams of the stack author [%s]", user.Username)
 	}
 
 	return filteredRegistries, nil
@@ -9,6 +9,7 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/registryutils/access"
 	"github.com/portainer/portainer/api/stacks/stackutils"
 )
 
@@ -36,7 +37,10 @@ func CreateComposeStackDeploymentConfig(securityContext *security.RestrictedRequ
 		return nil, fmt.Errorf("unable to retrieve registries from the database: %w", err)
 	}
 
-	filteredRegistries := security.FilterRegistries(registries, user, securityContext.UserMemberships, endpoint.ID)
+	filteredRegistries, err := access.FilterDeploymentRegistries(dataStore, registries, user, securityContext.UserMemberships, endpoint.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the default registries of the teams of the user: %w", err)
+	}
 
 	config := &ComposeStackDeploymentConfig{
 		stack:          stack,
@@ -78,7 +82,8 @@ func (config *ComposeStackDeploymentConfig) Deploy() error {
 		!securitySettings.AllowHostNamespaceForRegularUsers ||
 		!securitySettings.AllowDeviceMappingForRegularUsers ||
 		!securitySettings.AllowSysctlSettingForRegularUsers ||
-		!securitySettings.AllowContainerCapabilitiesForRegularUsers) &&
+		!securitySettings.AllowContainerCapabilitiesForRegularUsers ||
+		len(securitySettings.BlockedBindMountPrefixes) > 0) &&
 		!isAdminOrEndpointAdmin {
 
 		err = stackutils.ValidateStackFiles(config.stack, securitySettings, config.FileService)
@@ -3,6 +3,7 @@ package deployments
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/pkg/errors"
 	portainer "github.com/portainer/portainer/api"
@@ -18,6 +19,23 @@ type KubernetesStackDeploymentConfig struct {
 	user               *portainer.User
 	endpoint           *portainer.Endpoint
 	output             string
+	// ValidateManifests runs a server-side dry run of the manifests before deploying them
+	ValidateManifests bool
+	// StrictValidation rejects the fields that are not part of the schemas of the resources
+	StrictValidation bool
+}
+
+// ManifestValidationError is returned when the manifests of a Kubernetes stack are rejected by the validation
+type ManifestValidationError struct {
+	Errors []portainer.KubernetesManifestError
+}
+
+func (e *ManifestValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return "invalid Kubernetes manifest: " + e.Errors[0].Message
+	}
+
+	return fmt.Sprintf("%d invalid Kubernetes manifest documents, first error: %s", len(e.Errors), e.Errors[0].Message)
 }
 
 func CreateKubernetesStackDeploymentConfig(stack *portainer.Stack, kubeDeployer portainer.KubernetesDeployer, appLabels k.KubeAppLabels, user *portainer.User, endpoint *portainer.Endpoint) (*KubernetesStackDeploymentConfig, error) {
@@ -66,6 +84,24 @@ func (config *KubernetesStackDeploymentConfig) Deploy() error {
 		manifestFilePaths = append(manifestFilePaths, manifestFilePath)
 	}
 
+	if config.ValidateManifests {
+		manifestErrors, err := config.kubernetesDeployer.Validate(config.user.ID, config.endpoint, manifestFilePaths, config.stack.Namespace, config.StrictValidation)
+		if err != nil {
+			return fmt.Errorf("failed to validate kubernetes stack: %w", err)
+		}
+
+		if len(manifestErrors) > 0 {
+			// Report the files of the stack rather than the temporary ones
+			for i := range manifestErrors {
+				if file, err := filepath.Rel(tmpDir, manifestErrors[i].File); err == nil {
+					manifestErrors[i].File = file
+				}
+			}
+
+			return &ManifestValidationError{Errors: manifestErrors}
+		}
+	}
+
 	output, err := config.kubernetesDeployer.Deploy(config.user.ID, config.endpoint, manifestFilePaths, config.stack.Namespace)
 	if err != nil {
 		return fmt.Errorf("failed to deploy kubernete stack: %w", err)
@@ -8,6 +8,7 @@ import (
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/internal/registryutils/access"
 	"github.com/portainer/portainer/api/stacks/stackutils"
 )
 
@@ -34,7 +35,10 @@ func CreateSwarmStackDeploymentConfig(securityContext *security.RestrictedReques
 		return nil, fmt.Errorf("unable to retrieve registries from the database: %w", err)
 	}
 
-	filteredRegistries := security.FilterRegistries(registries, user, securityContext.UserMemberships, endpoint.ID)
+	filteredRegistries, err := access.FilterDeploymentRegistries(dataStore, registries, user, securityContext.UserMemberships, endpoint.ID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the default registries of the teams of the user: %w", err)
+	}
 
 	config := &SwarmStackDeploymentConfig{
 		stack:         stack,
@@ -71,7 +75,7 @@ func (config *SwarmStackDeploymentConfig) Deploy() error {
 
 	settings := &config.endpoint.SecuritySettings
 
-	if !settings.AllowBindMountsForRegularUsers && !isAdminOrEndpointAdmin {
+	if (!settings.AllowBindMountsForRegularUsers || len(settings.BlockedBindMountPrefixes) > 0) && !isAdminOrEndpointAdmin {
 		err = stackutils.ValidateStackFiles(config.stack, settings, config.FileService)
 		if err != nil {
 			return err
@@ -0,0 +1,77 @@
+package deployments
+
+import (
+	"cmp"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/stacks/stackutils"
+
+	"github.com/pkg/errors"
+)
+
+// RedeployOnEndpoint deploys a Docker compose stack onto another environment and moves the stack, with its
+// resource control, to that environment. Nothing is removed from the original environment as it is expected
+// to be unreachable, the stack keeps running there until the environment is cleaned up.
+func RedeployOnEndpoint(stack *portainer.Stack, target *portainer.Endpoint, deployer StackDeployer, datastore dataservices.DataStore) error {
+	if stack.Type != portainer.DockerComposeStack {
+		return errors.Errorf("stack %v is not a Docker compose stack", stack.ID)
+	}
+
+	stacks, err := datastore.Stack().StacksByName(stack.Name)
+	if err != nil {
+		return errors.WithMessage(err, "unable to check for name collision")
+	}
+
+	for _, s := range stacks {
+		if s.ID != stack.ID && s.EndpointID == target.ID {
+			return errors.Errorf("a stack with the name %q already exists on the environment %v", stack.Name, target.ID)
+		}
+	}
+
+	author := cmp.Or(stack.UpdatedBy, stack.CreatedBy)
+
+	user, err := datastore.User().UserByUsername(author)
+	if err != nil {
+		return &StackAuthorMissingErr{int(stack.ID), author}
+	}
+
+	registries, err := getUserRegistries(datastore, user, target.ID)
+	if err != nil {
+		return err
+	}
+
+	if stackutils.IsRelativePathStack(stack) {
+		err = deployer.DeployRemoteComposeStack(stack, target, registries, false, false)
+	} else {
+		err = deployer.DeployComposeStack(stack, target, registries, false, false)
+	}
+
+	if err != nil {
+		return errors.WithMessagef(err, "failed to deploy the stack %v on the environment %v", stack.ID, target.ID)
+	}
+
+	resourceControl, err := datastore.ResourceControl().ResourceControlByResourceIDAndType(stackutils.ResourceControlID(stack.EndpointID, stack.Name), portainer.StackResourceControl)
+	if err != nil && !dataservices.IsErrObjectNotFound(err) {
+		return errors.WithMessage(err, "unable to retrieve the resource control of the stack")
+	}
+
+	return datastore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		stack.EndpointID = target.ID
+		stack.Status = portainer.StackStatusActive
+		stack.UpdateDate = time.Now().Unix()
+
+		if err := tx.Stack().Update(stack.ID, stack); err != nil {
+			return errors.WithMessagef(err, "failed to update the stack %v", stack.ID)
+		}
+
+		if resourceControl == nil {
+			return nil
+		}
+
+		resourceControl.ResourceID = stackutils.ResourceControlID(stack.EndpointID, stack.Name)
+
+		return tx.ResourceControl().Update(resourceControl.ID, resourceControl)
+	})
+}
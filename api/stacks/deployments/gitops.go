@@ -0,0 +1,135 @@
+package deployments
+
+import (
+	"strings"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+
+	"github.com/rs/zerolog/log"
+)
+
+// gitOpsSync holds the outcome of a check of the git repository of a stack
+type gitOpsSync struct {
+	// Latest commit of the repository, empty when it could not be fetched
+	checkedCommit string
+	// Commit deployed once the check is done
+	appliedCommit string
+	// Whether the stack was redeployed
+	deployed bool
+}
+
+// GitOpsStatus returns the GitOps status of a git stack. The applied commit and the drift are
+// refreshed from the stack, it can be redeployed outside of the automatic updates
+func GitOpsStatus(datastore dataservices.DataStore, stack *portainer.Stack) (*portainer.StackGitOpsStatus, error) {
+	status, err := datastore.StackGitOpsStatus().Read(stack.ID)
+	if dataservices.IsErrObjectNotFound(err) {
+		status = &portainer.StackGitOpsStatus{StackID: stack.ID}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if stack.GitConfig != nil {
+		status.LastAppliedCommit = stack.GitConfig.ConfigHash
+	} else if stack.OCIConfig != nil {
+		status.LastAppliedCommit = stack.OCIConfig.Digest
+	}
+
+	status.Drift = gitOpsDrift(status)
+
+	return status, nil
+}
+
+// SetGitOpsPaused pauses or resumes the automatic updates of a stack. The polling job of a paused
+// stack keeps running but skips it, and so do its webhook
+func SetGitOpsPaused(datastore dataservices.DataStore, stackID portainer.StackID, paused bool) error {
+	return datastore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return updateGitOpsStatus(tx, stackID, func(status *portainer.StackGitOpsStatus) {
+			status.Paused = paused
+		})
+	})
+}
+
+// DeleteGitOpsStatus removes the GitOps status of a deleted stack
+func DeleteGitOpsStatus(datastore dataservices.DataStore, stackID portainer.StackID) {
+	if err := datastore.StackGitOpsStatus().Delete(stackID); err != nil && !dataservices.IsErrObjectNotFound(err) {
+		log.Warn().Err(err).Int("stack_id", int(stackID)).Msg("unable to remove the GitOps status of the stack")
+	}
+}
+
+func gitOpsPaused(datastore dataservices.DataStore, stackID portainer.StackID) (bool, error) {
+	status, err := datastore.StackGitOpsStatus().Read(stackID)
+	if dataservices.IsErrObjectNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return status.Paused, nil
+}
+
+func recordGitOpsSync(datastore dataservices.DataStore, stackID portainer.StackID, sync gitOpsSync, syncErr error) {
+	err := datastore.UpdateTx(func(tx dataservices.DataStoreTx) error {
+		return updateGitOpsStatus(tx, stackID, func(status *portainer.StackGitOpsStatus) {
+			now := time.Now().Unix()
+
+			status.LastCheck = now
+			if sync.checkedCommit != "" {
+				status.LastCheckedCommit = sync.checkedCommit
+			}
+
+			if sync.appliedCommit != "" {
+				status.LastAppliedCommit = sync.appliedCommit
+			}
+
+			if sync.deployed {
+				status.LastSync = now
+			}
+
+			status.Error = ""
+			if syncErr != nil {
+				status.Error = syncErr.Error()
+			}
+		})
+	})
+
+	// The stack can be removed while it is synced
+	if err != nil && !dataservices.IsErrObjectNotFound(err) {
+		log.Warn().Err(err).Int("stack_id", int(stackID)).Msg("unable to update the GitOps status of the stack")
+	}
+}
+
+func updateGitOpsStatus(tx dataservices.DataStoreTx, stackID portainer.StackID, update func(status *portainer.StackGitOpsStatus)) error {
+	if _, err := tx.Stack().Read(stackID); err != nil {
+		return err
+	}
+
+	status, err := tx.StackGitOpsStatus().Read(stackID)
+	if dataservices.IsErrObjectNotFound(err) {
+		status = &portainer.StackGitOpsStatus{StackID: stackID}
+		update(status)
+		status.Drift = gitOpsDrift(status)
+
+		return tx.StackGitOpsStatus().Create(status)
+	} else if err != nil {
+		return err
+	}
+
+	update(status)
+	status.Drift = gitOpsDrift(status)
+
+	return tx.StackGitOpsStatus().Update(stackID, status)
+}
+
+func gitOpsDrift(status *portainer.StackGitOpsStatus) portainer.StackGitOpsDrift {
+	if status.LastCheckedCommit == "" {
+		return portainer.StackGitOpsDriftUnknown
+	}
+
+	if strings.EqualFold(status.LastCheckedCommit, status.LastAppliedCommit) {
+		return portainer.StackGitOpsInSync
+	}
+
+	return portainer.StackGitOpsOutOfSync
+}
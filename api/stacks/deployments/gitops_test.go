@@ -0,0 +1,86 @@
+package deployments
+
+import (
+	"errors"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+	gittypes "github.com/portainer/portainer/api/git/types"
+	"github.com/portainer/portainer/api/internal/testhelpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitOpsStatus(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1}))
+	require.NoError(t, store.User().Create(&portainer.User{Username: "admin", Role: portainer.AdministratorRole}))
+	require.NoError(t, store.Stack().Create(&portainer.Stack{
+		ID:          1,
+		Type:        portainer.DockerComposeStack,
+		EndpointID:  1,
+		ProjectPath: t.TempDir(),
+		CreatedBy:   "admin",
+		AutoUpdate:  &portainer.AutoUpdateSettings{Interval: "5m"},
+		GitConfig: &gittypes.RepoConfig{
+			// The latest commits are shared between the tests by the commit poller, the repository is specific to this test
+			URL:           "gitops-status-url",
+			ReferenceName: "ref",
+			ConfigHash:    "oldHash",
+		},
+	}))
+
+	readStatus := func() *portainer.StackGitOpsStatus {
+		stack, err := store.Stack().Read(1)
+		require.NoError(t, err)
+
+		status, err := GitOpsStatus(store, stack)
+		require.NoError(t, err)
+
+		return status
+	}
+
+	status := readStatus()
+	assert.Equal(t, portainer.StackGitOpsDriftUnknown, status.Drift)
+	assert.Equal(t, "oldHash", status.LastAppliedCommit)
+
+	// A paused stack is not polled
+	require.NoError(t, SetGitOpsPaused(store, 1, true))
+	require.NoError(t, RedeployWhenChanged(1, &noopDeployer{}, store, testhelpers.NewGitService(nil, "newHash")))
+
+	status = readStatus()
+	assert.True(t, status.Paused)
+	assert.Zero(t, status.LastCheck)
+	assert.Equal(t, "oldHash", status.LastAppliedCommit)
+
+	// But it can be resynced
+	require.NoError(t, ForceResync(1, &noopDeployer{}, store, testhelpers.NewGitService(nil, "newHash")))
+
+	status = readStatus()
+	assert.True(t, status.Paused)
+	assert.Equal(t, "newHash", status.LastCheckedCommit)
+	assert.Equal(t, "newHash", status.LastAppliedCommit)
+	assert.Equal(t, portainer.StackGitOpsInSync, status.Drift)
+	assert.NotZero(t, status.LastSync)
+	assert.Empty(t, status.Error)
+
+	require.NoError(t, SetGitOpsPaused(store, 1, false))
+
+	cloneErr := errors.New("failed to clone")
+	require.ErrorIs(t, RedeployWhenChanged(1, &noopDeployer{}, store, testhelpers.NewGitService(cloneErr, "otherHash")), cloneErr)
+
+	status = readStatus()
+	assert.False(t, status.Paused)
+	assert.Contains(t, status.Error, "failed to clone")
+	assert.Equal(t, "newHash", status.LastAppliedCommit)
+
+	// The status is removed with the stack
+	require.NoError(t, store.Stack().Delete(1))
+	DeleteGitOpsStatus(store, 1)
+
+	_, err := store.StackGitOpsStatus().Read(1)
+	assert.True(t, store.IsErrObjectNotFound(err))
+}
@@ -0,0 +1,187 @@
+package deployments
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/docker/consts"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+	"github.com/rs/zerolog/log"
+)
+
+// healthCheckInterval is the delay between two health checks of the services of a stack
+var healthCheckInterval = 3 * time.Second
+
+// HealthChecker returns the current health of each service of a stack
+type HealthChecker func(ctx context.Context) ([]portainer.StackServiceHealth, error)
+
+// WaitForHealthy polls the health of the services of a stack until they are all healthy, one of them
+// becomes unhealthy or the timeout expires, and returns the aggregated result
+func WaitForHealthy(ctx context.Context, timeout time.Duration, check HealthChecker) *portainer.StackHealthReport {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	report := &portainer.StackHealthReport{Services: []portainer.StackServiceHealth{}}
+
+	for {
+		services, err := check(ctx)
+		if err != nil {
+			// The environment may be temporarily unreachable while the stack is starting
+			log.Debug().Err(err).Msg("unable to check the health of the stack services")
+
+			if ctx.Err() == nil {
+				report.Message = err.Error()
+			}
+		} else {
+			report.Services = services
+			report.Message = ""
+
+			switch aggregateHealth(services) {
+			case portainer.StackServiceHealthy:
+				report.Healthy = true
+				if len(services) == 0 {
+					report.Message = "The stack has no services to check"
+				}
+
+				return report
+			case portainer.StackServiceUnhealthy:
+				report.Message = "One or more services are unhealthy"
+
+				return report
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			report.TimedOut = true
+			if report.Message == "" {
+				report.Message = fmt.Sprintf("The services did not become healthy within %s", timeout)
+			}
+
+			return report
+		case <-ticker.C:
+		}
+	}
+}
+
+// aggregateHealth returns the worst health status of the given services
+func aggregateHealth(services []portainer.StackServiceHealth) portainer.StackServiceHealthStatus {
+	status := portainer.StackServiceHealthy
+
+	for _, service := range services {
+		switch service.Status {
+		case portainer.StackServiceUnhealthy:
+			return portainer.StackServiceUnhealthy
+		case portainer.StackServiceStarting:
+			status = portainer.StackServiceStarting
+		}
+	}
+
+	return status
+}
+
+// ComposeHealthChecker checks the health of the containers of a compose project, grouped by service
+func ComposeHealthChecker(cli *client.Client, projectName string) HealthChecker {
+	return func(ctx context.Context) ([]portainer.StackServiceHealth, error) {
+		containers, err := cli.ContainerList(ctx, container.ListOptions{
+			All:     true,
+			Filters: filters.NewArgs(filters.Arg("label", consts.ComposeStackNameLabel+"="+projectName)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list the containers of the stack: %w", err)
+		}
+
+		return composeServicesHealth(containers), nil
+	}
+}
+
+func composeServicesHealth(containers []types.Container) []portainer.StackServiceHealth {
+	services := []portainer.StackServiceHealth{}
+
+	for _, c := range containers {
+		name := c.Labels["com.docker.compose.service"]
+		status, message := containerHealth(c)
+
+		idx := slices.IndexFunc(services, func(s portainer.StackServiceHealth) bool { return s.Name == name })
+		if idx == -1 {
+			services = append(services, portainer.StackServiceHealth{Name: name, Status: status, Message: message})
+
+			continue
+		}
+
+		if aggregateHealth([]portainer.StackServiceHealth{services[idx], {Status: status}}) != services[idx].Status {
+			services[idx].Status = status
+			services[idx].Message = message
+		}
+	}
+
+	return services
+}
+
+// containerHealth maps the state of a container, and the result of its healthcheck when it has one, to a health status
+func containerHealth(c types.Container) (portainer.StackServiceHealthStatus, string) {
+	switch c.State {
+	case "running":
+		if strings.Contains(c.Status, "(unhealthy)") {
+			return portainer.StackServiceUnhealthy, "The healthcheck of the container is failing"
+		}
+
+		if strings.Contains(c.Status, "(health: starting)") {
+			return portainer.StackServiceStarting, ""
+		}
+
+		return portainer.StackServiceHealthy, ""
+	case "created", "restarting":
+		return portainer.StackServiceStarting, ""
+	case "exited":
+		// One-off services such as migrations exit once their work is done
+		if strings.HasPrefix(c.Status, "Exited (0)") {
+			return portainer.StackServiceHealthy, ""
+		}
+
+		return portainer.StackServiceUnhealthy, c.Status
+	case "paused":
+		return portainer.StackServiceHealthy, ""
+	}
+
+	return portainer.StackServiceUnhealthy, c.Status
+}
+
+// SwarmHealthChecker checks that the services of a swarm stack run all their desired tasks
+func SwarmHealthChecker(cli *client.Client, stackName string) HealthChecker {
+	return func(ctx context.Context) ([]portainer.StackServiceHealth, error) {
+		swarmServices, err := cli.ServiceList(ctx, types.ServiceListOptions{
+			Filters: filters.NewArgs(filters.Arg("label", consts.SwarmStackNameLabel+"="+stackName)),
+			Status:  true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list the services of the stack: %w", err)
+		}
+
+		services := make([]portainer.StackServiceHealth, 0, len(swarmServices))
+		for _, s := range swarmServices {
+			health := portainer.StackServiceHealth{Name: s.Spec.Name, Status: portainer.StackServiceStarting}
+
+			if s.ServiceStatus != nil && s.ServiceStatus.RunningTasks >= s.ServiceStatus.DesiredTasks {
+				health.Status = portainer.StackServiceHealthy
+			} else if s.ServiceStatus != nil {
+				health.Message = fmt.Sprintf("%d/%d tasks running", s.ServiceStatus.RunningTasks, s.ServiceStatus.DesiredTasks)
+			}
+
+			services = append(services, health)
+		}
+
+		return services, nil
+	}
+}
@@ -0,0 +1,75 @@
+package deployments
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForHealthy(t *testing.T) {
+	defaultInterval := healthCheckInterval
+	healthCheckInterval = 10 * time.Millisecond
+	t.Cleanup(func() { healthCheckInterval = defaultInterval })
+
+	t.Run("waits for the starting services", func(t *testing.T) {
+		calls := 0
+		report := WaitForHealthy(context.Background(), time.Second, func(ctx context.Context) ([]portainer.StackServiceHealth, error) {
+			calls++
+			if calls < 3 {
+				return []portainer.StackServiceHealth{{Name: "web", Status: portainer.StackServiceStarting}}, nil
+			}
+
+			return []portainer.StackServiceHealth{{Name: "web", Status: portainer.StackServiceHealthy}}, nil
+		})
+
+		assert.True(t, report.Healthy)
+		assert.False(t, report.TimedOut)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("stops on an unhealthy service", func(t *testing.T) {
+		report := WaitForHealthy(context.Background(), time.Second, func(ctx context.Context) ([]portainer.StackServiceHealth, error) {
+			return []portainer.StackServiceHealth{
+				{Name: "web", Status: portainer.StackServiceHealthy},
+				{Name: "db", Status: portainer.StackServiceUnhealthy},
+			}, nil
+		})
+
+		assert.False(t, report.Healthy)
+		assert.False(t, report.TimedOut)
+		assert.Len(t, report.Services, 2)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		report := WaitForHealthy(context.Background(), 50*time.Millisecond, func(ctx context.Context) ([]portainer.StackServiceHealth, error) {
+			return nil, errors.New("environment unreachable")
+		})
+
+		assert.False(t, report.Healthy)
+		assert.True(t, report.TimedOut)
+		assert.Equal(t, "environment unreachable", report.Message)
+	})
+}
+
+func TestComposeServicesHealth(t *testing.T) {
+	containers := []types.Container{
+		{State: "running", Status: "Up 2 minutes (healthy)", Labels: map[string]string{"com.docker.compose.service": "web"}},
+		{State: "running", Status: "Up 3 seconds (health: starting)", Labels: map[string]string{"com.docker.compose.service": "web"}},
+		{State: "exited", Status: "Exited (0) 1 minute ago", Labels: map[string]string{"com.docker.compose.service": "migrate"}},
+		{State: "exited", Status: "Exited (1) 5 seconds ago", Labels: map[string]string{"com.docker.compose.service": "worker"}},
+		{State: "running", Status: "Up 1 minute (unhealthy)", Labels: map[string]string{"com.docker.compose.service": "db"}},
+	}
+
+	assert.Equal(t, []portainer.StackServiceHealth{
+		{Name: "web", Status: portainer.StackServiceStarting},
+		{Name: "migrate", Status: portainer.StackServiceHealthy},
+		{Name: "worker", Status: portainer.StackServiceUnhealthy, Message: "Exited (1) 5 seconds ago"},
+		{Name: "db", Status: portainer.StackServiceUnhealthy, Message: "The healthcheck of the container is failing"},
+	}, composeServicesHealth(containers))
+}
@@ -0,0 +1,162 @@
+package deployments
+
+import (
+	"cmp"
+	"context"
+	"strconv"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/scheduler"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+)
+
+// ociPullTimeout bounds the check of the tag of an artifact and the download of its files
+const ociPullTimeout = 5 * time.Minute
+
+// OCIArtifactService pulls the files of the stacks deployed from an OCI artifact
+type OCIArtifactService interface {
+	// Pull replaces the content of dir by the files of the artifact and returns the digest of its manifest
+	Pull(ctx context.Context, config *portainer.OCIConfig, dir string) (string, error)
+	// LatestDigest returns the digest of the manifest the reference of the artifact currently points to
+	LatestDigest(ctx context.Context, config *portainer.OCIConfig) (string, error)
+}
+
+// StartOCIAutoupdate schedules the polling of the artifact of a stack, each run redeploys the stack
+// when its tag points to a new manifest. The digests are recorded in the GitOps status of the stack
+func StartOCIAutoupdate(stackID portainer.StackID, interval string, scheduler *scheduler.Scheduler, stackDeployer StackDeployer, datastore dataservices.DataStore, ociService OCIArtifactService) (jobID string, e *httperror.HandlerError) {
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		return "", httperror.BadRequest("Unable to parse stack's auto update interval", err)
+	}
+
+	jobID = scheduler.StartJobEvery(d, pollOCIStack(stackID, d, stackDeployer, datastore, ociService))
+
+	return jobID, nil
+}
+
+func pollOCIStack(stackID portainer.StackID, interval time.Duration, stackDeployer StackDeployer, datastore dataservices.DataStore, ociService OCIArtifactService) func() error {
+	return func() error {
+		time.Sleep(pollJitter(interval))
+
+		return RedeployOCIWhenChanged(stackID, stackDeployer, datastore, ociService)
+	}
+}
+
+// RedeployOCIWhenChanged pulls and redeploys the stack when the tag of its artifact points to a new manifest.
+// The stacks pinned to a digest and the stacks whose GitOps updates are paused are skipped
+func RedeployOCIWhenChanged(stackID portainer.StackID, deployer StackDeployer, datastore dataservices.DataStore, ociService OCIArtifactService) error {
+	_, err, _ := singleflightGroup.Do(strconv.Itoa(int(stackID)), func() (any, error) {
+		return nil, redeployOCIWhenChanged(stackID, deployer, datastore, ociService)
+	})
+
+	return err
+}
+
+func redeployOCIWhenChanged(stackID portainer.StackID, deployer StackDeployer, datastore dataservices.DataStore, ociService OCIArtifactService) (err error) {
+	stack, err := datastore.Stack().Read(stackID)
+	if dataservices.IsErrObjectNotFound(err) {
+		return scheduler.NewPermanentError(errors.WithMessagef(err, "failed to get the stack %v", stackID))
+	} else if err != nil {
+		return errors.WithMessagef(err, "failed to get the stack %v", stackID)
+	}
+
+	if stack.OCIConfig == nil {
+		return nil
+	}
+
+	if paused, err := gitOpsPaused(datastore, stackID); err != nil {
+		return errors.WithMessagef(err, "failed to get the GitOps status of the stack %v", stackID)
+	} else if paused {
+		log.Debug().Int("stack_id", int(stackID)).Msg("the GitOps updates of the stack are paused")
+
+		return nil
+	}
+
+	sync := gitOpsSync{appliedCommit: stack.OCIConfig.Digest}
+	defer func() {
+		recordGitOpsSync(datastore, stack.ID, sync, err)
+	}()
+
+	endpoint, err := datastore.Endpoint().Endpoint(stack.EndpointID)
+	if dataservices.IsErrObjectNotFound(err) {
+		return scheduler.NewPermanentError(errors.WithMessagef(err, "failed to find the environment %v associated to the stack %v", stack.EndpointID, stack.ID))
+	} else if err != nil {
+		return errors.WithMessagef(err, "failed to find the environment %v associated to the stack %v", stack.EndpointID, stack.ID)
+	}
+
+	author := cmp.Or(stack.UpdatedBy, stack.CreatedBy)
+
+	user, err := datastore.User().UserByUsername(author)
+	if err != nil {
+		log.Warn().
+			Int("stack_id", int(stack.ID)).
+			Str("stack", stack.Name).
+			Str("author", author).
+			Msg("cannot auto update a stack, stack author user is missing")
+
+		return &StackAuthorMissingErr{int(stack.ID), author}
+	}
+
+	if !isEnvironmentOnline(endpoint) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ociPullTimeout)
+	defer cancel()
+
+	latestDigest, err := ociService.LatestDigest(ctx, stack.OCIConfig)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to check the artifact of the stack %v", stack.ID)
+	}
+
+	sync.checkedCommit = latestDigest
+
+	if latestDigest == stack.OCIConfig.Digest {
+		return nil
+	}
+
+	manifestDigest, err := ociService.Pull(ctx, stack.OCIConfig, stack.ProjectPath)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to pull the artifact of the stack %v", stack.ID)
+	}
+
+	registries, err := getUserRegistries(datastore, user, endpoint.ID)
+	if err != nil {
+		return err
+	}
+
+	forceRecreate := stack.AutoUpdate != nil && stack.AutoUpdate.ForceUpdate
+
+	switch stack.Type {
+	case portainer.DockerComposeStack:
+		err = deployer.DeployComposeStack(stack, endpoint, registries, true, forceRecreate)
+	case portainer.DockerSwarmStack:
+		err = deployer.DeploySwarmStack(stack, endpoint, registries, true, true)
+	default:
+		return errors.Errorf("cannot update stack, type %v is unsupported", stack.Type)
+	}
+
+	if err != nil {
+		return errors.WithMessagef(err, "failed to deploy the stack %v", stack.ID)
+	}
+
+	stack.OCIConfig.Digest = manifestDigest
+	stack.UpdateDate = time.Now().Unix()
+	stack.Status = portainer.StackStatusActive
+
+	if err := datastore.Stack().Update(stack.ID, stack); err != nil {
+		return errors.WithMessagef(err, "failed to update the stack %v", stack.ID)
+	}
+
+	sync.appliedCommit = manifestDigest
+	sync.deployed = true
+
+	log.Info().Int("stack_id", int(stack.ID)).Str("digest", manifestDigest).Msg("the stack was redeployed from a new version of its artifact")
+
+	return nil
+}
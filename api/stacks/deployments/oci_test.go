@@ -0,0 +1,76 @@
+package deployments
+
+import (
+	"context"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/datastore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testOCIArtifactService struct {
+	digest string
+	pulls  int
+}
+
+func (service *testOCIArtifactService) Pull(ctx context.Context, config *portainer.OCIConfig, dir string) (string, error) {
+	service.pulls++
+
+	return service.digest, nil
+}
+
+func (service *testOCIArtifactService) LatestDigest(ctx context.Context, config *portainer.OCIConfig) (string, error) {
+	return service.digest, nil
+}
+
+func TestRedeployOCIWhenChanged(t *testing.T) {
+	_, store := datastore.MustNewTestStore(t, true, true)
+
+	require.NoError(t, store.Endpoint().Create(&portainer.Endpoint{ID: 1}))
+	require.NoError(t, store.User().Create(&portainer.User{Username: "admin", Role: portainer.AdministratorRole}))
+	require.NoError(t, store.Stack().Create(&portainer.Stack{
+		ID:          1,
+		Type:        portainer.DockerComposeStack,
+		EndpointID:  1,
+		ProjectPath: t.TempDir(),
+		CreatedBy:   "admin",
+		AutoUpdate:  &portainer.AutoUpdateSettings{Interval: "5m"},
+		OCIConfig: &portainer.OCIConfig{
+			RegistryID: 1,
+			Repository: "myorg/mystack",
+			Reference:  "latest",
+			Digest:     "sha256:v1",
+		},
+	}))
+
+	ociService := &testOCIArtifactService{digest: "sha256:v1"}
+
+	// The tag did not move
+	require.NoError(t, RedeployOCIWhenChanged(1, &noopDeployer{}, store, ociService))
+	assert.Zero(t, ociService.pulls)
+
+	// The tag points to a new version of the artifact
+	ociService.digest = "sha256:v2"
+	require.NoError(t, RedeployOCIWhenChanged(1, &noopDeployer{}, store, ociService))
+	assert.Equal(t, 1, ociService.pulls)
+
+	stack, err := store.Stack().Read(1)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:v2", stack.OCIConfig.Digest)
+
+	status, err := GitOpsStatus(store, stack)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:v2", status.LastCheckedCommit)
+	assert.Equal(t, "sha256:v2", status.LastAppliedCommit)
+	assert.NotZero(t, status.LastSync)
+
+	// A paused stack is not updated
+	require.NoError(t, SetGitOpsPaused(store, 1, true))
+
+	ociService.digest = "sha256:v3"
+	require.NoError(t, RedeployOCIWhenChanged(1, &noopDeployer{}, store, ociService))
+	assert.Equal(t, 1, ociService.pulls)
+}
@@ -0,0 +1,218 @@
+package deployments
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// maxConcurrentPolls bounds the number of repositories checked at the same time
+	maxConcurrentPolls = 8
+	// maxPollJitter bounds the random delay added to the polling of the stacks
+	maxPollJitter = 30 * time.Second
+)
+
+// commitPoller shares the latest commit of the repositories between the stacks tracking the same
+// repository and reference, so that each repository is checked once per polling interval
+type commitPoller struct {
+	mu      sync.Mutex
+	commits map[string]polledCommit
+	group   singleflight.Group
+	workers chan struct{}
+}
+
+type polledCommit struct {
+	hash      string
+	checkedAt time.Time
+}
+
+var sharedPoller = newCommitPoller(maxConcurrentPolls)
+
+func newCommitPoller(workers int) *commitPoller {
+	return &commitPoller{
+		commits: make(map[string]polledCommit),
+		workers: make(chan struct{}, workers),
+	}
+}
+
+// latestCommitID returns the latest commit of a repository reference, fetched less than maxAge ago
+// by any stack. A zero maxAge always fetches the commit
+func (poller *commitPoller) latestCommitID(gitService portainer.GitService, repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, tlsSkipVerify bool, maxAge time.Duration) (string, error) {
+	key := pollKey(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase, tlsSkipVerify)
+
+	if maxAge > 0 {
+		poller.mu.Lock()
+		commit, ok := poller.commits[key]
+		poller.mu.Unlock()
+
+		if ok && time.Since(commit.checkedAt) < maxAge {
+			return commit.hash, nil
+		}
+	}
+
+	hash, err, _ := poller.group.Do(key, func() (any, error) {
+		poller.workers <- struct{}{}
+		defer func() { <-poller.workers }()
+
+		hash, err := gitService.LatestCommitID(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase, tlsSkipVerify)
+		if err != nil {
+			return "", err
+		}
+
+		poller.mu.Lock()
+		poller.commits[key] = polledCommit{hash: hash, checkedAt: time.Now()}
+		poller.mu.Unlock()
+
+		return hash, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hash.(string), nil
+}
+
+// gitService returns a git service whose commit checks go through the poller
+func (poller *commitPoller) gitService(gitService portainer.GitService, maxAge time.Duration) portainer.GitService {
+	return &polledGitService{GitService: gitService, poller: poller, maxAge: maxAge}
+}
+
+type polledGitService struct {
+	portainer.GitService
+	poller *commitPoller
+	maxAge time.Duration
+}
+
+func (service *polledGitService) LatestCommitID(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, tlsSkipVerify bool) (string, error) {
+	return service.poller.latestCommitID(service.GitService, repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase, tlsSkipVerify, service.maxAge)
+}
+
+// pollKey identifies a repository reference, the credentials are part of it since they change what can be read.
+// They are hashed so that they are not kept in memory
+func pollKey(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, tlsSkipVerify bool) string {
+	h := sha256.New()
+	for _, value := range []string{repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase, strconv.FormatBool(tlsSkipVerify)} {
+		h.Write([]byte(value))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// pollJitter returns a random delay spreading the polling of the stacks scheduled at the same time
+func pollJitter(interval time.Duration) time.Duration {
+	maxJitter := min(interval/10, maxPollJitter)
+	if maxJitter <= 0 {
+		return 0
+	}
+
+	return rand.N(maxJitter)
+}
+
+// pollMaxAge returns how long the commit found by another stack can be reused by a stack polled at
+// the given interval. It is the interval minus the largest jitter so that every poll of the stack
+// sees a commit fetched after its previous poll
+func pollMaxAge(autoUpdate *portainer.AutoUpdateSettings) time.Duration {
+	if autoUpdate == nil || autoUpdate.Interval == "" {
+		return 0
+	}
+
+	interval, err := time.ParseDuration(autoUpdate.Interval)
+	if err != nil {
+		return 0
+	}
+
+	return max(interval-min(interval/10, maxPollJitter), 0)
+}
+
+// stackFilesDigest returns a digest of the directories holding the files of a git stack, the stack is
+// redeployed only when a new commit changes it
+func stackFilesDigest(stack *portainer.Stack) (string, error) {
+	dirs := []string{filepath.Dir(stack.EntryPoint)}
+	for _, file := range stack.AdditionalFiles {
+		dirs = append(dirs, filepath.Dir(file))
+	}
+
+	slices.Sort(dirs)
+	dirs = slices.Compact(dirs)
+
+	h := sha256.New()
+	for _, dir := range dirs {
+		root := filepath.Join(stack.ProjectPath, dir)
+
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if entry.IsDir() {
+				if entry.Name() == ".git" {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			rel, err := filepath.Rel(stack.ProjectPath, path)
+			if err != nil {
+				return err
+			}
+
+			digest, err := fileDigest(path, entry)
+			if err != nil {
+				return err
+			}
+
+			h.Write([]byte(rel))
+			h.Write([]byte{0})
+			h.Write(digest)
+
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func fileDigest(path string, entry fs.DirEntry) ([]byte, error) {
+	h := sha256.New()
+
+	if entry.Type()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return nil, err
+		}
+
+		h.Write([]byte(target))
+
+		return h.Sum(nil), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(h, file); err != nil {
+		return nil, err
+	}
+
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,82 @@
+package deployments
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingGitService struct {
+	portainer.GitService
+	calls atomic.Int32
+}
+
+func (service *countingGitService) LatestCommitID(repositoryURL, referenceName, username, password, sshPrivateKey, sshPassphrase string, tlsSkipVerify bool) (string, error) {
+	service.calls.Add(1)
+
+	return repositoryURL + "@" + referenceName, nil
+}
+
+func TestCommitPollerSharesCommits(t *testing.T) {
+	poller := newCommitPoller(2)
+	gitService := &countingGitService{}
+
+	for range 3 {
+		hash, err := poller.gitService(gitService, time.Minute).LatestCommitID("https://github.com/portainer/portainer", "refs/heads/main", "", "", "", "", false)
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/portainer/portainer@refs/heads/main", hash)
+	}
+
+	assert.EqualValues(t, 1, gitService.calls.Load())
+
+	// Other references and credentials are checked separately
+	_, err := poller.gitService(gitService, time.Minute).LatestCommitID("https://github.com/portainer/portainer", "refs/heads/develop", "", "", "", "", false)
+	require.NoError(t, err)
+	_, err = poller.gitService(gitService, time.Minute).LatestCommitID("https://github.com/portainer/portainer", "refs/heads/main", "user", "password", "", "", false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, gitService.calls.Load())
+
+	// Without a maximum age the commit is always fetched
+	_, err = poller.gitService(gitService, 0).LatestCommitID("https://github.com/portainer/portainer", "refs/heads/main", "", "", "", "", false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, gitService.calls.Load())
+}
+
+func TestPollMaxAge(t *testing.T) {
+	assert.Zero(t, pollMaxAge(nil))
+	assert.Zero(t, pollMaxAge(&portainer.AutoUpdateSettings{Webhook: "05de31a2-79fa-4644-9c12-faa67e5c49f0"}))
+	assert.Equal(t, 54*time.Second, pollMaxAge(&portainer.AutoUpdateSettings{Interval: "1m"}))
+	assert.Equal(t, time.Hour-30*time.Second, pollMaxAge(&portainer.AutoUpdateSettings{Interval: "1h"}))
+}
+
+func TestStackFilesDigest(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "web"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "docs"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "web", "docker-compose.yml"), []byte("services: {}"), 0o600))
+
+	stack := &portainer.Stack{ProjectPath: dir, EntryPoint: "web/docker-compose.yml"}
+
+	digest, err := stackFilesDigest(stack)
+	require.NoError(t, err)
+
+	// The files outside of the directories of the stack are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docs", "README.md"), []byte("# web"), 0o600))
+
+	newDigest, err := stackFilesDigest(stack)
+	require.NoError(t, err)
+	assert.Equal(t, digest, newDigest)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "web", ".env"), []byte("TAG=1.0"), 0o600))
+
+	newDigest, err = stackFilesDigest(stack)
+	require.NoError(t, err)
+	assert.NotEqual(t, digest, newDigest)
+}
@@ -0,0 +1,31 @@
+package deployments
+
+import (
+	"context"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	dockerclient "github.com/portainer/portainer/api/docker/client"
+)
+
+// environmentPingTimeout bounds the check of the reachability of an environment
+const environmentPingTimeout = 10 * time.Second
+
+// IsEnvironmentReachable returns false when the Docker API of the environment cannot be reached, it is used
+// to tell the deployments failing because the environment is temporarily down from the other failures
+func IsEnvironmentReachable(clientFactory *dockerclient.ClientFactory, endpoint *portainer.Endpoint) bool {
+	timeout := environmentPingTimeout
+
+	cli, err := clientFactory.CreateClient(endpoint, "", &timeout)
+	if err != nil {
+		return false
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	_, err = cli.Ping(ctx)
+
+	return err == nil
+}
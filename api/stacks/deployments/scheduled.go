@@ -9,7 +9,7 @@ import (
 	"github.com/portainer/portainer/api/scheduler"
 )
 
-func StartStackSchedules(scheduler *scheduler.Scheduler, stackdeployer StackDeployer, datastore dataservices.DataStore, gitService portainer.GitService) error {
+func StartStackSchedules(scheduler *scheduler.Scheduler, stackdeployer StackDeployer, datastore dataservices.DataStore, gitService portainer.GitService, ociService OCIArtifactService) error {
 	stacks, err := datastore.Stack().RefreshableStacks()
 	if err != nil {
 		return errors.Wrap(err, "failed to fetch refreshable stacks")
@@ -20,10 +20,13 @@ func StartStackSchedules(scheduler *scheduler.Scheduler, stackdeployer StackDepl
 		if err != nil {
 			return errors.Wrap(err, "Unable to parse auto update interval")
 		}
-		stackID := stack.ID // to be captured by the scheduled function
-		jobID := scheduler.StartJobEvery(d, func() error {
-			return RedeployWhenChanged(stackID, stackdeployer, datastore, gitService)
-		})
+
+		job := pollStack(stack.ID, d, stackdeployer, datastore, gitService)
+		if stack.OCIConfig != nil {
+			job = pollOCIStack(stack.ID, d, stackdeployer, datastore, ociService)
+		}
+
+		jobID := scheduler.StartJobEvery(d, job)
 
 		stack.AutoUpdate.JobID = jobID
 		if err := datastore.Stack().Update(stack.ID, &stack); err != nil {
@@ -43,6 +43,7 @@ func (b *ComposeStackFileContentBuilder) SetUniqueInfo(payload *StackPayload) Fi
 	b.stack.Type = portainer.DockerComposeStack
 	b.stack.EntryPoint = filesystem.ComposeFileDefaultName
 	b.stack.Env = payload.Env
+	b.stack.Profiles = payload.Profiles
 	b.stack.FromAppTemplate = payload.FromAppTemplate
 	return b
 }
@@ -41,6 +41,7 @@ func (b *ComposeStackFileUploadBuilder) SetUniqueInfo(payload *StackPayload) Fil
 	b.stack.Type = portainer.DockerComposeStack
 	b.stack.EntryPoint = filesystem.ComposeFileDefaultName
 	b.stack.Env = payload.Env
+	b.stack.Profiles = payload.Profiles
 	return b
 }
 
@@ -0,0 +1,77 @@
+package stackbuilders
+
+import (
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+	"github.com/portainer/portainer/api/http/security"
+	"github.com/portainer/portainer/api/scheduler"
+	"github.com/portainer/portainer/api/stacks/deployments"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+)
+
+type ComposeStackOCIBuilder struct {
+	OCIMethodStackBuilder
+	SecurityContext *security.RestrictedRequestContext
+}
+
+// CreateComposeStackOCIBuilder creates a builder for the compose stack (docker standalone) that will be deployed by OCI artifact method
+func CreateComposeStackOCIBuilder(securityContext *security.RestrictedRequestContext,
+	dataStore dataservices.DataStore,
+	fileService portainer.FileService,
+	ociService deployments.OCIArtifactService,
+	scheduler *scheduler.Scheduler,
+	stackDeployer deployments.StackDeployer) *ComposeStackOCIBuilder {
+
+	return &ComposeStackOCIBuilder{
+		OCIMethodStackBuilder: OCIMethodStackBuilder{
+			StackBuilder: CreateStackBuilder(dataStore, fileService, stackDeployer),
+			ociService:   ociService,
+			scheduler:    scheduler,
+		},
+		SecurityContext: securityContext,
+	}
+}
+
+func (b *ComposeStackOCIBuilder) SetGeneralInfo(payload *StackPayload, endpoint *portainer.Endpoint) OCIMethodStackBuildProcess {
+	b.OCIMethodStackBuilder.SetGeneralInfo(payload, endpoint)
+	return b
+}
+
+func (b *ComposeStackOCIBuilder) SetUniqueInfo(payload *StackPayload) OCIMethodStackBuildProcess {
+	if b.hasError() {
+		return b
+	}
+	b.stack.Name = payload.Name
+	b.stack.Type = portainer.DockerComposeStack
+	b.stack.EntryPoint = payload.ComposeFile
+	b.stack.Env = payload.Env
+	b.stack.Profiles = payload.Profiles
+	return b
+}
+
+func (b *ComposeStackOCIBuilder) SetOCIArtifact(payload *StackPayload) OCIMethodStackBuildProcess {
+	b.OCIMethodStackBuilder.SetOCIArtifact(payload)
+	return b
+}
+
+func (b *ComposeStackOCIBuilder) Deploy(payload *StackPayload, endpoint *portainer.Endpoint) OCIMethodStackBuildProcess {
+	if b.hasError() {
+		return b
+	}
+
+	composeDeploymentConfig, err := deployments.CreateComposeStackDeploymentConfig(b.SecurityContext, b.stack, endpoint, b.dataStore, b.fileService, b.stackDeployer, false, false)
+	if err != nil {
+		b.err = httperror.InternalServerError(err.Error(), err)
+		return b
+	}
+
+	b.deploymentConfiger = composeDeploymentConfig
+	b.stack.CreatedBy = b.deploymentConfiger.GetUsername()
+
+	return b.OCIMethodStackBuilder.Deploy(payload, endpoint)
+}
+
+func (b *ComposeStackOCIBuilder) SetAutoUpdate(payload *StackPayload) OCIMethodStackBuildProcess {
+	b.OCIMethodStackBuilder.SetAutoUpdate(payload)
+	return b
+}
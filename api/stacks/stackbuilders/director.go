@@ -29,6 +29,14 @@ func (d *StackBuilderDirector) Build(payload *StackPayload, endpoint *portainer.
 			SetAutoUpdate(payload).
 			SaveStack()
 
+	case OCIMethodStackBuildProcess:
+		return builder.SetGeneralInfo(payload, endpoint).
+			SetUniqueInfo(payload).
+			SetOCIArtifact(payload).
+			Deploy(payload, endpoint).
+			SetAutoUpdate(payload).
+			SaveStack()
+
 	case FileUploadMethodStackBuildProcess:
 		return builder.SetGeneralInfo(payload, endpoint).
 			SetUniqueInfo(payload).
@@ -51,5 +59,5 @@ func (d *StackBuilderDirector) Build(payload *StackPayload, endpoint *portainer.
 			SaveStack()
 	}
 
-	return nil, httperror.BadRequest("Invalid value for query parameter: method. Value must be one of: string or repository or url or file", errors.New(request.ErrInvalidQueryParameter))
+	return nil, httperror.BadRequest("Invalid value for query parameter: method. Value must be one of: string or repository or url or file or oci", errors.New(request.ErrInvalidQueryParameter))
 }
@@ -98,9 +98,14 @@ func (b *K8sStackFileContentBuilder) Deploy(payload *StackPayload, endpoint *por
 		return b
 	}
 
+	k8sDeploymentConfig.ValidateManifests = payload.ValidateManifests
+	k8sDeploymentConfig.StrictValidation = payload.StrictValidation
 	b.deploymentConfiger = k8sDeploymentConfig
 
-	return b.FileContentMethodStackBuilder.Deploy(payload, endpoint)
+	process := b.FileContentMethodStackBuilder.Deploy(payload, endpoint)
+	b.err = invalidManifestsError(b.err)
+
+	return process
 }
 
 func (b *K8sStackFileContentBuilder) GetResponse() string {
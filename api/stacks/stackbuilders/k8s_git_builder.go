@@ -87,9 +87,14 @@ func (b *KubernetesStackGitBuilder) Deploy(payload *StackPayload, endpoint *port
 		return b
 	}
 
+	k8sDeploymentConfig.ValidateManifests = payload.ValidateManifests
+	k8sDeploymentConfig.StrictValidation = payload.StrictValidation
 	b.deploymentConfiger = k8sDeploymentConfig
 
-	return b.GitMethodStackBuilder.Deploy(payload, endpoint)
+	process := b.GitMethodStackBuilder.Deploy(payload, endpoint)
+	b.err = invalidManifestsError(b.err)
+
+	return process
 }
 
 func (b *KubernetesStackGitBuilder) SetAutoUpdate(payload *StackPayload) GitMethodStackBuildProcess {
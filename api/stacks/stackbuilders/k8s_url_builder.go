@@ -104,9 +104,14 @@ func (b *KubernetesStackUrlBuilder) Deploy(payload *StackPayload, endpoint *port
 		return b
 	}
 
+	k8sDeploymentConfig.ValidateManifests = payload.ValidateManifests
+	k8sDeploymentConfig.StrictValidation = payload.StrictValidation
 	b.deploymentConfiger = k8sDeploymentConfig
 
-	return b.UrlMethodStackBuilder.Deploy(payload, endpoint)
+	process := b.UrlMethodStackBuilder.Deploy(payload, endpoint)
+	b.err = invalidManifestsError(b.err)
+
+	return process
 }
 
 func (b *KubernetesStackUrlBuilder) GetResponse() string {
@@ -1,6 +1,8 @@
 package stackbuilders
 
 import (
+	"errors"
+
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/dataservices"
 	"github.com/portainer/portainer/api/stacks/deployments"
@@ -62,3 +64,13 @@ func (b *StackBuilder) cleanUp() error {
 func (b *StackBuilder) hasError() bool {
 	return b.err != nil
 }
+
+// invalidManifestsError turns the deployment errors caused by invalid Kubernetes manifests into bad requests
+func invalidManifestsError(err *httperror.HandlerError) *httperror.HandlerError {
+	var validationErr *deployments.ManifestValidationError
+	if err != nil && errors.As(err.Err, &validationErr) {
+		return httperror.BadRequest("Invalid Kubernetes manifests", validationErr)
+	}
+
+	return err
+}
@@ -2,11 +2,13 @@ package stackbuilders
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/filesystem"
+	"github.com/portainer/portainer/api/git"
 	gittypes "github.com/portainer/portainer/api/git/types"
 	"github.com/portainer/portainer/api/scheduler"
 	"github.com/portainer/portainer/api/stacks/deployments"
@@ -61,14 +63,19 @@ func (b *GitMethodStackBuilder) SetGitRepository(payload *StackPayload) GitMetho
 	var repoConfig gittypes.RepoConfig
 	if payload.Authentication {
 		repoConfig.Authentication = &gittypes.GitAuthentication{
-			Username: payload.RepositoryConfigPayload.Username,
-			Password: payload.RepositoryConfigPayload.Password,
+			Username:      payload.RepositoryConfigPayload.Username,
+			Password:      payload.RepositoryConfigPayload.Password,
+			SSHPrivateKey: payload.RepositoryConfigPayload.SSHPrivateKey,
+			SSHPassphrase: payload.RepositoryConfigPayload.SSHPassphrase,
 		}
 	}
 
 	repoConfig.URL = payload.URL
 	repoConfig.ReferenceName = payload.ReferenceName
 	repoConfig.TLSSkipVerify = payload.TLSSkipVerify
+	repoConfig.CloneDepth = payload.CloneDepth
+	repoConfig.SparseCheckout = payload.SparseCheckout
+	repoConfig.SparseCheckoutPaths = slices.Clone(payload.SparseCheckoutPaths)
 
 	repoConfig.ConfigFilePath = payload.ComposeFile
 	if payload.ComposeFile == "" {
@@ -80,6 +87,8 @@ func (b *GitMethodStackBuilder) SetGitRepository(payload *StackPayload) GitMetho
 		repoConfig.ConfigFilePath = payload.ManifestFile
 	}
 
+	stackutils.AddAdditionalFilesToSparseCheckout(&repoConfig, payload.AdditionalFiles)
+
 	stackFolder := strconv.Itoa(int(b.stack.ID))
 	// Set the project path on the disk
 	b.stack.ProjectPath = b.fileService.GetStackProjectPath(stackFolder)
@@ -97,6 +106,11 @@ func (b *GitMethodStackBuilder) SetGitRepository(payload *StackPayload) GitMetho
 
 	// Update the latest commit id
 	repoConfig.ConfigHash = commitHash
+
+	username, password, _ := git.GetCredentials(repoConfig.Authentication)
+	sshPrivateKey, sshPassphrase := git.GetSSHCredentials(repoConfig.Authentication)
+	stackutils.SetConfigCommit(b.gitService, &repoConfig, username, password, sshPrivateKey, sshPassphrase)
+
 	b.stack.GitConfig = &repoConfig
 
 	return b
@@ -0,0 +1,121 @@
+package stackbuilders
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/scheduler"
+	"github.com/portainer/portainer/api/stacks/deployments"
+	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+)
+
+// ociPullTimeout bounds the download of the files of the artifact of a new stack
+const ociPullTimeout = 5 * time.Minute
+
+type OCIMethodStackBuildProcess interface {
+	// Set general stack information
+	SetGeneralInfo(payload *StackPayload, endpoint *portainer.Endpoint) OCIMethodStackBuildProcess
+	// Set unique stack information, e.g. swarm stack has swarmID, kubernetes stack has namespace
+	SetUniqueInfo(payload *StackPayload) OCIMethodStackBuildProcess
+	// Deploy stack based on the configuration
+	Deploy(payload *StackPayload, endpoint *portainer.Endpoint) OCIMethodStackBuildProcess
+	// Save the stack information to database and return the stack object
+	SaveStack() (*portainer.Stack, *httperror.HandlerError)
+	// Get response from HTTP request. Use if it is needed
+	GetResponse() string
+	// Pull the files of the OCI artifact
+	SetOCIArtifact(payload *StackPayload) OCIMethodStackBuildProcess
+	// Set auto update setting
+	SetAutoUpdate(payload *StackPayload) OCIMethodStackBuildProcess
+}
+
+type OCIMethodStackBuilder struct {
+	StackBuilder
+	ociService deployments.OCIArtifactService
+	scheduler  *scheduler.Scheduler
+}
+
+func (b *OCIMethodStackBuilder) SetGeneralInfo(payload *StackPayload, endpoint *portainer.Endpoint) OCIMethodStackBuildProcess {
+	stackID := b.dataStore.Stack().GetNextIdentifier()
+	b.stack.ID = portainer.StackID(stackID)
+	b.stack.EndpointID = endpoint.ID
+	b.stack.AdditionalFiles = payload.AdditionalFiles
+	b.stack.Status = portainer.StackStatusActive
+	b.stack.CreationDate = time.Now().Unix()
+	b.stack.AutoUpdate = payload.AutoUpdate
+
+	return b
+}
+
+func (b *OCIMethodStackBuilder) SetUniqueInfo(payload *StackPayload) OCIMethodStackBuildProcess {
+	return b
+}
+
+func (b *OCIMethodStackBuilder) SetOCIArtifact(payload *StackPayload) OCIMethodStackBuildProcess {
+	if b.hasError() {
+		return b
+	}
+
+	ociConfig := *payload.OCIConfig
+
+	stackFolder := strconv.Itoa(int(b.stack.ID))
+	// Set the project path on the disk
+	b.stack.ProjectPath = b.fileService.GetStackProjectPath(stackFolder)
+
+	ctx, cancel := context.WithTimeout(context.Background(), ociPullTimeout)
+	defer cancel()
+
+	manifestDigest, err := b.ociService.Pull(ctx, &ociConfig, b.stack.ProjectPath)
+	if err != nil {
+		b.err = httperror.InternalServerError("Unable to pull the OCI artifact", err)
+		return b
+	}
+
+	// The stack is redeployed from the same version of the artifact until its tag moves
+	ociConfig.Digest = manifestDigest
+	b.stack.OCIConfig = &ociConfig
+
+	return b
+}
+
+func (b *OCIMethodStackBuilder) Deploy(payload *StackPayload, endpoint *portainer.Endpoint) OCIMethodStackBuildProcess {
+	if b.hasError() {
+		return b
+	}
+
+	// Deploy the stack
+	if err := b.deploymentConfiger.Deploy(); err != nil {
+		b.err = httperror.InternalServerError(err.Error(), err)
+	}
+
+	return b
+}
+
+func (b *OCIMethodStackBuilder) SetAutoUpdate(payload *StackPayload) OCIMethodStackBuildProcess {
+	if b.hasError() {
+		return b
+	}
+
+	if payload.AutoUpdate != nil && payload.AutoUpdate.Interval != "" {
+		jobID, err := deployments.StartOCIAutoupdate(b.stack.ID,
+			b.stack.AutoUpdate.Interval,
+			b.scheduler,
+			b.stackDeployer,
+			b.dataStore,
+			b.ociService)
+		if err != nil {
+			b.err = err
+			return b
+		}
+
+		b.stack.AutoUpdate.JobID = jobID
+	}
+
+	return b
+}
+
+func (b *OCIMethodStackBuilder) GetResponse() string {
+	return ""
+}
@@ -17,6 +17,8 @@ type StackPayload struct {
 	Webhook          string
 	// A list of environment(endpoint) variables used during stack deployment
 	Env []portainer.Pair
+	// Compose profiles enabled when deploying a Compose stack
+	Profiles []string
 	// Optional GitOps update configuration
 	AutoUpdate *portainer.AutoUpdateSettings
 	// Whether the stack is from a app template
@@ -29,12 +31,18 @@ type StackPayload struct {
 	ManifestFile string
 	// URL to the k8s Stack file. Used by k8s git repository method
 	ManifestURL string
+	// Run a server-side dry run of the k8s manifests before deploying them
+	ValidateManifests bool
+	// Reject the fields of the k8s manifests that are not part of the schemas of the resources
+	StrictValidation bool
 	// Path to the Stack file inside the Git repository
 	ComposeFile string `example:"docker-compose.yml" default:"docker-compose.yml"`
 	// Applicable when deploying with multiple stack files
 	AdditionalFiles []string `example:"[nz.compose.yml, uat.compose.yml]"`
 	// Git repository configuration of a stack
 	RepositoryConfigPayload
+	// OCI artifact configuration of a stack. Used by OCI artifact method
+	OCIConfig *portainer.OCIConfig
 }
 
 type RepositoryConfigPayload struct {
@@ -50,6 +58,16 @@ type RepositoryConfigPayload struct {
 	// Password used in basic authentication. Required when RepositoryAuthentication is true
 	// and RepositoryGitCredentialID is 0
 	Password string `example:"myGitPassword"`
+	// PEM encoded private key used to authenticate over SSH, as an alternative to the password
+	SSHPrivateKey string
+	// Passphrase of the SSH private key
+	SSHPassphrase string
 	// TLSSkipVerify skips SSL verification when cloning the Git repository
 	TLSSkipVerify bool `example:"false"`
+	// Number of commits fetched when cloning the repository, only the latest commit is fetched when 0
+	CloneDepth int `example:"1"`
+	// Whether only the directories of the stack files and SparseCheckoutPaths are checked out
+	SparseCheckout bool `example:"false"`
+	// Directories checked out in addition to the directories of the stack files when SparseCheckout is enabled
+	SparseCheckoutPaths []string `example:"shared/config"`
 }
@@ -0,0 +1,82 @@
+package stackutils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+// ErrDependencyCycle is returned when the dependencies of a stack lead back to the stack itself
+var ErrDependencyCycle = errors.New("the stack dependencies contain a cycle")
+
+// DeployOrder returns the given stacks along with all their dependencies, ordered so that every stack comes after the
+// stacks it depends on. The dependsOn function returns the dependencies of a stack.
+func DeployOrder(stackIDs []portainer.StackID, dependsOn func(portainer.StackID) ([]portainer.StackID, error)) ([]portainer.StackID, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	state := make(map[portainer.StackID]int)
+	order := make([]portainer.StackID, 0, len(stackIDs))
+	path := []portainer.StackID{}
+
+	var visit func(stackID portainer.StackID) error
+	visit = func(stackID portainer.StackID) error {
+		switch state[stackID] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, formatCycle(append(path, stackID)))
+		}
+
+		state[stackID] = visiting
+		path = append(path, stackID)
+
+		dependencies, err := dependsOn(stackID)
+		if err != nil {
+			return err
+		}
+
+		for _, dependency := range dependencies {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[stackID] = visited
+		order = append(order, stackID)
+
+		return nil
+	}
+
+	for _, stackID := range stackIDs {
+		if err := visit(stackID); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+func formatCycle(path []portainer.StackID) string {
+	// The path starts from the requested stack, only the looping part is reported
+	last := path[len(path)-1]
+	for i, stackID := range path {
+		if stackID == last {
+			path = path[i:]
+
+			break
+		}
+	}
+
+	ids := make([]string, 0, len(path))
+	for _, stackID := range path {
+		ids = append(ids, fmt.Sprint(stackID))
+	}
+
+	return strings.Join(ids, " -> ")
+}
@@ -0,0 +1,47 @@
+package stackutils
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dependencyGraph(graph map[portainer.StackID][]portainer.StackID) func(portainer.StackID) ([]portainer.StackID, error) {
+	return func(stackID portainer.StackID) ([]portainer.StackID, error) {
+		return graph[stackID], nil
+	}
+}
+
+func TestDeployOrder(t *testing.T) {
+	// 3 (app) depends on 2 (cache) and 1 (database), 2 depends on 1
+	graph := dependencyGraph(map[portainer.StackID][]portainer.StackID{
+		3: {2, 1},
+		2: {1},
+	})
+
+	order, err := DeployOrder([]portainer.StackID{3}, graph)
+	require.NoError(t, err)
+	assert.Equal(t, []portainer.StackID{1, 2, 3}, order)
+
+	// The stacks requested several times are deployed once
+	order, err = DeployOrder([]portainer.StackID{1, 3, 4, 2}, graph)
+	require.NoError(t, err)
+	assert.Equal(t, []portainer.StackID{1, 2, 3, 4}, order)
+}
+
+func TestDeployOrderCycle(t *testing.T) {
+	graph := dependencyGraph(map[portainer.StackID][]portainer.StackID{
+		1: {2},
+		2: {3},
+		3: {2},
+	})
+
+	_, err := DeployOrder([]portainer.StackID{1}, graph)
+	require.ErrorIs(t, err, ErrDependencyCycle)
+	assert.Contains(t, err.Error(), "2 -> 3 -> 2")
+
+	_, err = DeployOrder([]portainer.StackID{4}, dependencyGraph(map[portainer.StackID][]portainer.StackID{4: {4}}))
+	require.ErrorIs(t, err, ErrDependencyCycle)
+}
@@ -2,11 +2,15 @@ package stackutils
 
 import (
 	"fmt"
+	"path"
+	"slices"
+	"strings"
 
 	"github.com/pkg/errors"
 	portainer "github.com/portainer/portainer/api"
 	"github.com/portainer/portainer/api/git"
 	gittypes "github.com/portainer/portainer/api/git/types"
+	"github.com/rs/zerolog/log"
 )
 
 var (
@@ -23,9 +27,10 @@ func DownloadGitRepository(config gittypes.RepoConfig, gitService portainer.GitS
 		username = config.Authentication.Username
 		password = config.Authentication.Password
 	}
+	sshPrivateKey, sshPassphrase := git.GetSSHCredentials(config.Authentication)
 
 	projectPath := getProjectPath()
-	err := gitService.CloneRepository(projectPath, config.URL, config.ReferenceName, username, password, config.TLSSkipVerify)
+	err := gitService.CloneRepository(projectPath, config.URL, config.ReferenceName, username, password, sshPrivateKey, sshPassphrase, config.TLSSkipVerify, config.CloneOptions())
 	if err != nil {
 		if errors.Is(err, gittypes.ErrAuthenticationFailure) {
 			newErr := git.ErrInvalidGitCredential
@@ -36,10 +41,43 @@ func DownloadGitRepository(config gittypes.RepoConfig, gitService portainer.GitS
 		return "", newErr
 	}
 
-	commitID, err := gitService.LatestCommitID(config.URL, config.ReferenceName, username, password, config.TLSSkipVerify)
+	commitID, err := gitService.LatestCommitID(config.URL, config.ReferenceName, username, password, sshPrivateKey, sshPassphrase, config.TLSSkipVerify)
 	if err != nil {
 		newErr := fmt.Errorf("unable to fetch git repository id: %w", err)
 		return "", newErr
 	}
 	return commitID, nil
 }
+
+// SetConfigCommit stores the author, message and date of the commit ConfigHash in the git config of a stack.
+// They are cleared when they cannot be fetched or when the reference moved past ConfigHash since the deployment
+func SetConfigCommit(gitService portainer.GitService, config *gittypes.RepoConfig, username, password, sshPrivateKey, sshPassphrase string) {
+	config.ConfigCommit = nil
+
+	commit, err := gitService.LatestCommit(config.URL, config.ReferenceName, username, password, sshPrivateKey, sshPassphrase, config.TLSSkipVerify)
+	if err != nil {
+		log.Warn().Err(err).Str("url", config.URL).Str("ref", config.ReferenceName).Msg("unable to fetch the deployed commit")
+
+		return
+	}
+
+	if commit == nil || !strings.EqualFold(commit.Hash, config.ConfigHash) {
+		return
+	}
+
+	config.ConfigCommit = commit
+}
+
+// AddAdditionalFilesToSparseCheckout checks out the directories of the additional files of a stack along with
+// the directory of its entry point when the sparse checkout of its repository is enabled
+func AddAdditionalFilesToSparseCheckout(config *gittypes.RepoConfig, additionalFiles []string) {
+	if !config.SparseCheckout {
+		return
+	}
+
+	for _, additionalFile := range additionalFiles {
+		if directory := path.Dir(additionalFile); !slices.Contains(config.SparseCheckoutPaths, directory) {
+			config.SparseCheckoutPaths = append(config.SparseCheckoutPaths, directory)
+		}
+	}
+}
@@ -15,12 +15,18 @@ func Test_GetStackFilePaths(t *testing.T) {
 
 	t.Run("stack doesn't have additional files", func(t *testing.T) {
 		expected := []string{"/tmp/stack/1/file-one.yml"}
-		assert.ElementsMatch(t, expected, GetStackFilePaths(stack, true))
+		assert.Equal(t, expected, GetStackFilePaths(stack, true))
 	})
 
 	t.Run("stack has additional files", func(t *testing.T) {
 		stack.AdditionalFiles = []string{"file-two.yml", "file-three.yml"}
 		expected := []string{"/tmp/stack/1/file-one.yml", "/tmp/stack/1/file-two.yml", "/tmp/stack/1/file-three.yml"}
-		assert.ElementsMatch(t, expected, GetStackFilePaths(stack, true))
+		assert.Equal(t, expected, GetStackFilePaths(stack, true))
+	})
+
+	t.Run("relative paths keep the entry point first", func(t *testing.T) {
+		stack.AdditionalFiles = []string{"override.yml", "local.yml"}
+		expected := []string{"file-one.yml", "override.yml", "local.yml"}
+		assert.Equal(t, expected, GetStackFilePaths(stack, false))
 	})
 }
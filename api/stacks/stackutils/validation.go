@@ -1,23 +1,56 @@
 package stackutils
 
 import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
 	"github.com/docker/cli/cli/compose/loader"
+	"github.com/docker/cli/cli/compose/template"
 	"github.com/docker/cli/cli/compose/types"
+	"github.com/joho/godotenv"
 	"github.com/pkg/errors"
 	portainer "github.com/portainer/portainer/api"
 )
 
-func IsValidStackFile(stackFileContent []byte, securitySettings *portainer.EndpointSecuritySettings) error {
+// composeProfileRegex matches the profile names accepted by docker compose
+var composeProfileRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ValidateComposeProfiles ensures that the profiles of a Compose stack are valid profile names
+func ValidateComposeProfiles(profiles []string) error {
+	for _, profile := range profiles {
+		if !composeProfileRegex.MatchString(profile) {
+			return fmt.Errorf("invalid profile name %q", profile)
+		}
+	}
+
+	return nil
+}
+
+// IsValidStackFile ensures that a stack file complies with the security settings of the environment. The relative
+// bind mount sources are resolved against projectDir and the variables they hold are interpolated from env, the same
+// way docker compose does when the stack is deployed
+func IsValidStackFile(stackFileContent []byte, securitySettings *portainer.EndpointSecuritySettings, projectDir string, env map[string]string) error {
 	composeConfigYAML, err := loader.ParseYAML(stackFileContent)
 	if err != nil {
 		return err
 	}
 
+	normalizeDependsOn(composeConfigYAML)
+
+	if err := interpolateVolumeSources(composeConfigYAML, env); err != nil {
+		return err
+	}
+
 	composeConfigFile := types.ConfigFile{
 		Config: composeConfigYAML,
 	}
 
 	composeConfigDetails := types.ConfigDetails{
+		WorkingDir:  projectDir,
 		ConfigFiles: []types.ConfigFile{composeConfigFile},
 		Environment: map[string]string{},
 	}
@@ -32,11 +65,23 @@ func IsValidStackFile(stackFileContent []byte, securitySettings *portainer.Endpo
 
 	for key := range composeConfig.Services {
 		service := composeConfig.Services[key]
-		if !securitySettings.AllowBindMountsForRegularUsers {
-			for _, volume := range service.Volumes {
-				if volume.Type == "bind" {
-					return errors.New("bind-mount disabled for non administrator users")
-				}
+		for _, volume := range service.Volumes {
+			// A source left with a variable could be a bind mount of any path once deployed, even when it reads as a
+			// named volume
+			if strings.Contains(volume.Source, "$") {
+				return fmt.Errorf("volume %s cannot be resolved, the variables it uses are not defined by the stack", volume.Source)
+			}
+
+			if volume.Type != "bind" {
+				continue
+			}
+
+			if !securitySettings.AllowBindMountsForRegularUsers {
+				return errors.New("bind-mount disabled for non administrator users")
+			}
+
+			if IsBindMountBlocked(securitySettings, volume.Source) {
+				return fmt.Errorf("bind-mount of %s disabled for non administrator users", volume.Source)
 			}
 		}
 
@@ -64,17 +109,201 @@ func IsValidStackFile(stackFileContent []byte, securitySettings *portainer.Endpo
 	return nil
 }
 
+// IsBindMountBlocked returns true when the host path of a bind mount is one of the paths blocked for the
+// non-administrator users by the security settings, or is located below one of them
+func IsBindMountBlocked(securitySettings *portainer.EndpointSecuritySettings, source string) bool {
+	if !path.IsAbs(source) {
+		return false
+	}
+
+	source = path.Clean(source)
+
+	for _, prefix := range securitySettings.BlockedBindMountPrefixes {
+		prefix = path.Clean(prefix)
+
+		if prefix == "/" || source == prefix || strings.HasPrefix(source, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateBlockedBindMountPrefixes ensures that the blocked bind mount prefixes are absolute host paths
+func ValidateBlockedBindMountPrefixes(prefixes []string) error {
+	for _, prefix := range prefixes {
+		if !path.IsAbs(prefix) {
+			return fmt.Errorf("the blocked bind mount prefix %q must be an absolute path", prefix)
+		}
+	}
+
+	return nil
+}
+
+// interpolateVolumeSources interpolates the variables of the volumes of the services with the stack environment
+// variables. The variables that the stack does not define are left as is so that their bind mounts are rejected, and
+// so are the sources relying on the home directory of the host running docker compose
+func interpolateVolumeSources(composeConfigYAML map[string]any, env map[string]string) error {
+	services, ok := composeConfigYAML["services"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	mapping := func(name string) (string, bool) {
+		if value, ok := env[name]; ok {
+			return value, true
+		}
+
+		return "${" + name + "}", true
+	}
+
+	for _, service := range services {
+		serviceConfig, ok := service.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		volumes, ok := serviceConfig["volumes"].([]any)
+		if !ok {
+			continue
+		}
+
+		for i, volume := range volumes {
+			switch volume := volume.(type) {
+			case string:
+				source, err := interpolateVolumeSource(volume, mapping)
+				if err != nil {
+					return err
+				}
+
+				volumes[i] = source
+			case map[string]any:
+				source, ok := volume["source"].(string)
+				if !ok {
+					continue
+				}
+
+				source, err := interpolateVolumeSource(source, mapping)
+				if err != nil {
+					return err
+				}
+
+				volume["source"] = source
+			}
+		}
+	}
+
+	return nil
+}
+
+func interpolateVolumeSource(source string, mapping template.Mapping) (string, error) {
+	interpolated, err := template.Substitute(source, mapping)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid volume %s", source)
+	}
+
+	if strings.HasPrefix(interpolated, "~") {
+		return "", fmt.Errorf("bind-mount of %s cannot be resolved, the home directory is not allowed", source)
+	}
+
+	return interpolated, nil
+}
+
+// normalizeDependsOn converts the long syntax of depends_on from the Compose Specification, where the dependencies
+// are a map holding their conditions, to the list of the legacy formats so that the file can be loaded
+func normalizeDependsOn(composeConfigYAML map[string]any) {
+	services, ok := composeConfigYAML["services"].(map[string]any)
+	if !ok {
+		return
+	}
+
+	for _, service := range services {
+		serviceConfig, ok := service.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		dependencies, ok := serviceConfig["depends_on"].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		names := make([]any, 0, len(dependencies))
+		for name := range dependencies {
+			names = append(names, name)
+		}
+
+		slices.SortFunc(names, func(a, b any) int {
+			return strings.Compare(a.(string), b.(string))
+		})
+
+		serviceConfig["depends_on"] = names
+	}
+}
+
 func ValidateStackFiles(stack *portainer.Stack, securitySettings *portainer.EndpointSecuritySettings, fileService portainer.FileService) error {
+	env, err := stackEnvironment(stack, fileService)
+	if err != nil {
+		return err
+	}
+
+	// docker compose resolves the relative paths of every file against the directory of the first one
+	projectDir := path.Join(stack.ProjectPath, path.Dir(stack.EntryPoint))
+
 	for _, file := range GetStackFilePaths(stack, false) {
 		stackContent, err := fileService.GetFileContent(stack.ProjectPath, file)
 		if err != nil {
 			return errors.Wrap(err, "failed to get stack file content")
 		}
 
-		err = IsValidStackFile(stackContent, securitySettings)
+		err = IsValidStackFile(stackContent, securitySettings, projectDir, env)
 		if err != nil {
 			return errors.Wrap(err, "stack config file is invalid")
 		}
 	}
 	return nil
 }
+
+// stackEnvironment returns the variables that docker compose interpolates the stack files with, read from the default
+// .env file of the project, the uploaded env file and the stack environment variables, in the order of precedence
+// of the env file written for the deployment
+func stackEnvironment(stack *portainer.Stack, fileService portainer.FileService) (map[string]string, error) {
+	env := map[string]string{}
+
+	// A missing default .env file is ignored, as docker compose does
+	if content, err := fileService.GetFileContent(stack.ProjectPath, path.Join(path.Dir(stack.EntryPoint), ".env")); err == nil {
+		if err := parseEnvFile(env, content); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the default .env file of the stack")
+		}
+	}
+
+	if stack.EnvFilePath != "" {
+		content, err := fileService.GetFileContent(stack.EnvFilePath, "")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get the stack env file content")
+		}
+
+		if err := parseEnvFile(env, content); err != nil {
+			return nil, errors.Wrap(err, "failed to parse the stack env file")
+		}
+	}
+
+	for _, pair := range stack.Env {
+		env[pair.Name] = pair.Value
+	}
+
+	return env, nil
+}
+
+func parseEnvFile(env map[string]string, content []byte) error {
+	values, err := godotenv.Parse(bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	for name, value := range values {
+		env[name] = value
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package stackutils
+
+import (
+	"fmt"
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/stretchr/testify/assert"
+)
+
+const composeSpecStackFile = `
+x-logging: &logging
+  driver: json-file
+services:
+  web:
+    image: nginx
+    profiles: [frontend]
+    logging: *logging
+    depends_on:
+      db:
+        condition: service_healthy
+        restart: true
+  db:
+    image: postgres
+    privileged: true
+    healthcheck:
+      test: ["CMD", "pg_isready"]
+      start_interval: 5s
+`
+
+func Test_IsValidStackFile(t *testing.T) {
+	t.Run("accepts the Compose Specification", func(t *testing.T) {
+		err := IsValidStackFile([]byte(composeSpecStackFile), &portainer.EndpointSecuritySettings{AllowPrivilegedModeForRegularUsers: true}, "/data/compose/1", nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("still enforces the security settings", func(t *testing.T) {
+		err := IsValidStackFile([]byte(composeSpecStackFile), &portainer.EndpointSecuritySettings{}, "/data/compose/1", nil)
+		assert.ErrorContains(t, err, "privileged mode disabled")
+	})
+}
+
+func Test_IsValidStackFile_BlockedBindMountPrefixes(t *testing.T) {
+	const stackFile = `
+services:
+  web:
+    image: nginx
+    volumes:
+      - /var/run/docker.sock:/var/run/docker.sock
+`
+
+	securitySettings := &portainer.EndpointSecuritySettings{
+		AllowBindMountsForRegularUsers: true,
+		BlockedBindMountPrefixes:       []string{"/var/run"},
+	}
+
+	err := IsValidStackFile([]byte(stackFile), securitySettings, "/data/compose/1", nil)
+	assert.ErrorContains(t, err, "bind-mount of /var/run/docker.sock disabled")
+
+	securitySettings.BlockedBindMountPrefixes = []string{"/etc", "/var/run/docker"}
+	assert.NoError(t, IsValidStackFile([]byte(stackFile), securitySettings, "/data/compose/1", nil))
+}
+
+func Test_IsValidStackFile_ResolvesBindMountSources(t *testing.T) {
+	securitySettings := &portainer.EndpointSecuritySettings{
+		AllowBindMountsForRegularUsers: true,
+		BlockedBindMountPrefixes:       []string{"/etc"},
+	}
+
+	isValid := func(volume string, env map[string]string) error {
+		stackFile := fmt.Sprintf("services:\n  web:\n    image: nginx\n    volumes:\n      - %q\n", volume)
+
+		return IsValidStackFile([]byte(stackFile), securitySettings, "/data/compose/1", env)
+	}
+
+	t.Run("relative sources are resolved against the project directory", func(t *testing.T) {
+		assert.ErrorContains(t, isValid("../../../../etc:/x", nil), "bind-mount of /etc disabled")
+		assert.ErrorContains(t, isValid("./../../../etc/ssl:/x", nil), "bind-mount of /etc/ssl disabled")
+		assert.NoError(t, isValid("./config:/x", nil))
+	})
+
+	t.Run("sources are interpolated with the stack environment", func(t *testing.T) {
+		assert.ErrorContains(t, isValid("${HOST_DIR}:/x", map[string]string{"HOST_DIR": "/etc"}), "bind-mount of /etc disabled")
+		assert.ErrorContains(t, isValid("${HOST_DIR:-/data}:/x", map[string]string{"HOST_DIR": "../../../../etc"}), "bind-mount of /etc disabled")
+		assert.NoError(t, isValid("${HOST_DIR:-/etc}:/x", map[string]string{"HOST_DIR": "/data"}))
+	})
+
+	t.Run("sources with variables the stack does not define are rejected", func(t *testing.T) {
+		assert.ErrorContains(t, isValid("${HOST_DIR:-/etc}:/x", nil), "cannot be resolved")
+		assert.ErrorContains(t, isValid("${HOST_DIR}:/x", nil), "cannot be resolved")
+		assert.ErrorContains(t, isValid("$HOST_DIR/data:/x", nil), "cannot be resolved")
+		assert.ErrorContains(t, isValid("~/data:/x", nil), "cannot be resolved")
+	})
+
+	t.Run("long syntax sources are resolved too", func(t *testing.T) {
+		const stackFile = `
+services:
+  web:
+    image: nginx
+    volumes:
+      - type: bind
+        source: ${HOST_DIR:-/etc}
+        target: /x
+`
+
+		err := IsValidStackFile([]byte(stackFile), securitySettings, "/data/compose/1", nil)
+		assert.ErrorContains(t, err, "cannot be resolved")
+
+		err = IsValidStackFile([]byte(stackFile), securitySettings, "/data/compose/1", map[string]string{"HOST_DIR": "../../../etc"})
+		assert.ErrorContains(t, err, "bind-mount of /etc disabled")
+	})
+}
+
+func Test_IsBindMountBlocked(t *testing.T) {
+	securitySettings := &portainer.EndpointSecuritySettings{BlockedBindMountPrefixes: []string{"/etc/", "/var/run/docker.sock"}}
+
+	assert.True(t, IsBindMountBlocked(securitySettings, "/etc"))
+	assert.True(t, IsBindMountBlocked(securitySettings, "/etc/ssl/certs"))
+	assert.True(t, IsBindMountBlocked(securitySettings, "/data/../etc/shadow"))
+	assert.True(t, IsBindMountBlocked(securitySettings, "/var/run/docker.sock"))
+	assert.False(t, IsBindMountBlocked(securitySettings, "/etcetera"))
+	assert.False(t, IsBindMountBlocked(securitySettings, "/var/run"))
+	assert.False(t, IsBindMountBlocked(securitySettings, "data"))
+
+	assert.True(t, IsBindMountBlocked(&portainer.EndpointSecuritySettings{BlockedBindMountPrefixes: []string{"/"}}, "/data"))
+}
+
+func Test_ValidateComposeProfiles(t *testing.T) {
+	assert.NoError(t, ValidateComposeProfiles(nil))
+	assert.NoError(t, ValidateComposeProfiles([]string{"debug", "metrics_v2", "a.b-c"}))
+	assert.Error(t, ValidateComposeProfiles([]string{"-debug"}))
+	assert.Error(t, ValidateComposeProfiles([]string{"with space"}))
+}
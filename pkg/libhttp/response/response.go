@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	httperror "github.com/portainer/portainer/pkg/libhttp/error"
+	"github.com/portainer/portainer/pkg/redact"
 
 	"github.com/segmentio/encoding/json"
 )
@@ -17,8 +18,8 @@ func JSON(rw http.ResponseWriter, data any) *httperror.HandlerError {
 	return JSONWithStatus(rw, data, http.StatusOK)
 }
 
-// JSONWithStatus encodes data to rw in JSON format with a specific status code.
-// Returns a pointer to a HandlerError if encoding fails.
+// JSONWithStatus encodes data to rw in JSON format with a specific status code,
+// the secret fields of data are redacted. Returns a pointer to a HandlerError if encoding fails.
 func JSONWithStatus(rw http.ResponseWriter, data any, status int) *httperror.HandlerError {
 	rw.Header().Set("Content-Type", "application/json")
 	rw.WriteHeader(status)
@@ -27,7 +28,7 @@ func JSONWithStatus(rw http.ResponseWriter, data any, status int) *httperror.Han
 	enc.SetSortMapKeys(false)
 	enc.SetAppendNewline(false)
 
-	err := enc.Encode(data)
+	err := enc.Encode(redact.Redact(data, redact.Response))
 	if err != nil {
 		return httperror.InternalServerError("Unable to write JSON response", err)
 	}
@@ -141,3 +141,17 @@ func TestEmpty(t *testing.T) {
 	assert.Nil(t, httpErr)
 	assert.Equal(t, http.StatusNoContent, recorder.Code)
 }
+
+func TestJSONRedactsSecrets(t *testing.T) {
+	type Credentials struct {
+		Username string `json:"username"`
+		Password string `json:"password,omitempty" redact:"true"`
+	}
+
+	recorder := httptest.NewRecorder()
+
+	httpErr := JSON(recorder, &Credentials{Username: "bob", Password: "secret"})
+
+	assert.Nil(t, httpErr)
+	assert.JSONEq(t, `{"username":"bob"}`, recorder.Body.String())
+}
@@ -135,6 +135,10 @@ func (wrapper *PluginWrapper) command(command composeCommand, options libstack.O
 		command.WithProjectDirectory(options.ProjectDir)
 	}
 
+	if len(options.Profiles) > 0 {
+		command.WithProfiles(options.Profiles)
+	}
+
 	var stderr bytes.Buffer
 
 	args := []string{}
@@ -256,6 +260,12 @@ func (command *composeCommand) WithProjectDirectory(projectDir string) {
 	command.globalArgs = append(command.globalArgs, "--project-directory", projectDir)
 }
 
+func (command *composeCommand) WithProfiles(profiles []string) {
+	for _, profile := range profiles {
+		command.globalArgs = append(command.globalArgs, "--profile", profile)
+	}
+}
+
 func (command *composeCommand) ToArgs() []string {
 	return append(command.globalArgs, command.subCommandAndArgs...)
 }
@@ -58,6 +58,16 @@ func Test_NewCommand_MultiFilePaths_WithSpaces(t *testing.T) {
 	}
 }
 
+func Test_NewCommand_WithProfiles(t *testing.T) {
+	cmd := newCommand([]string{"up", "-d"}, []string{"docker-compose.yml"})
+	cmd.WithProfiles([]string{"debug", "metrics"})
+
+	expected := []string{"-f", "docker-compose.yml", "--profile", "debug", "--profile", "metrics", "up", "-d"}
+	if !reflect.DeepEqual(cmd.ToArgs(), expected) {
+		t.Errorf("wrong output args, want: %v, got: %v", expected, cmd.ToArgs())
+	}
+}
+
 func Test_UpAndDown(t *testing.T) {
 	checkPrerequisites(t)
 
@@ -51,6 +51,8 @@ type Options struct {
 	ProjectDir string
 	// ConfigOptions is a list of options to pass to the docker-compose config command
 	ConfigOptions []string
+	// Profiles is a list of the compose profiles to enable, the services assigned to other profiles are ignored
+	Profiles []string
 }
 
 type DeployOptions struct {
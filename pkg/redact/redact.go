@@ -0,0 +1,233 @@
+// Package redact strips the secret fields of a value before it leaves Portainer, either
+// in an API response or in a log entry.
+//
+// The secret fields are flagged with the redact struct tag:
+//
+//	Password string `json:"Password" redact:"true"`  // redacted from the responses and the logs
+//	Token    string `json:"Token" redact:"log"`      // redacted from the logs only
+//
+// Redacting never alters the value it is given, a copy is returned instead.
+package redact
+
+import (
+	"reflect"
+	"sync"
+)
+
+// TagName is the name of the struct tag used to flag the secret fields
+const TagName = "redact"
+
+// Scope is the destination of a redacted value
+type Scope int
+
+const (
+	// Response redacts the fields tagged with `redact:"true"`
+	Response Scope = iota
+	// Log redacts the fields tagged with `redact:"true"` or `redact:"log"`
+	Log
+)
+
+type typeKey struct {
+	t     reflect.Type
+	scope Scope
+}
+
+// holdsSecrets caches whether a type holds secret fields for a scope
+var holdsSecrets sync.Map
+
+// Redact returns a copy of v in which the secret fields are set to their zero value, including the ones
+// held behind interface values. Only the parts of v holding a secret are copied, v is returned as is when
+// it holds none.
+func Redact(v any, scope Scope) any {
+	if v == nil {
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if !hasSecrets(rv.Type(), scope) {
+		return v
+	}
+
+	if redacted, changed := redactValue(rv, scope); changed {
+		return redacted.Interface()
+	}
+
+	return v
+}
+
+func isSecret(field reflect.StructField, scope Scope) bool {
+	switch field.Tag.Get(TagName) {
+	case "true":
+		return true
+	case "log":
+		return scope == Log
+	}
+
+	return false
+}
+
+func hasSecrets(t reflect.Type, scope Scope) bool {
+	key := typeKey{t: t, scope: scope}
+	if cached, ok := holdsSecrets.Load(key); ok {
+		return cached.(bool)
+	}
+
+	result := inspectType(t, scope, map[reflect.Type]struct{}{})
+	holdsSecrets.Store(key, result)
+
+	return result
+}
+
+func inspectType(t reflect.Type, scope Scope, visiting map[reflect.Type]struct{}) bool {
+	if _, ok := visiting[t]; ok {
+		return false
+	}
+
+	visiting[t] = struct{}{}
+	defer delete(visiting, t)
+
+	switch t.Kind() {
+	case reflect.Interface:
+		// The secrets of the dynamic value are only known at runtime
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return inspectType(t.Elem(), scope, visiting)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			if isSecret(field, scope) || inspectType(field.Type, scope, visiting) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// redactValue returns a copy of v without its secrets and true when v holds a secret,
+// otherwise v is returned as is along with false
+func redactValue(v reflect.Value, scope Scope) (reflect.Value, bool) {
+	t := v.Type()
+
+	switch t.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() || !hasSecrets(v.Elem().Type(), scope) {
+			return v, false
+		}
+
+		elem, changed := redactValue(v.Elem(), scope)
+		if !changed {
+			return v, false
+		}
+
+		if t.Kind() == reflect.Interface {
+			c := reflect.New(t).Elem()
+			c.Set(elem)
+
+			return c, true
+		}
+
+		c := reflect.New(t.Elem())
+		c.Elem().Set(elem)
+
+		return c, true
+	case reflect.Struct:
+		var c reflect.Value
+
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			var redacted reflect.Value
+			if isSecret(field, scope) {
+				if v.Field(i).IsZero() {
+					continue
+				}
+
+				redacted = reflect.Zero(field.Type)
+			} else if hasSecrets(field.Type, scope) {
+				var changed bool
+				if redacted, changed = redactValue(v.Field(i), scope); !changed {
+					continue
+				}
+			} else {
+				continue
+			}
+
+			if !c.IsValid() {
+				c = reflect.New(t).Elem()
+				c.Set(v)
+			}
+
+			c.Field(i).Set(redacted)
+		}
+
+		if !c.IsValid() {
+			return v, false
+		}
+
+		return c, true
+	case reflect.Slice, reflect.Array:
+		var c reflect.Value
+
+		for i := range v.Len() {
+			elem, changed := redactValue(v.Index(i), scope)
+			if !changed {
+				continue
+			}
+
+			if !c.IsValid() {
+				if t.Kind() == reflect.Slice {
+					c = reflect.MakeSlice(t, v.Len(), v.Len())
+				} else {
+					c = reflect.New(t).Elem()
+				}
+
+				reflect.Copy(c, v)
+			}
+
+			c.Index(i).Set(elem)
+		}
+
+		if !c.IsValid() {
+			return v, false
+		}
+
+		return c, true
+	case reflect.Map:
+		var c reflect.Value
+
+		iter := v.MapRange()
+		for iter.Next() {
+			elem, changed := redactValue(iter.Value(), scope)
+			if !changed {
+				continue
+			}
+
+			if !c.IsValid() {
+				c = reflect.MakeMapWithSize(t, v.Len())
+
+				copyIter := v.MapRange()
+				for copyIter.Next() {
+					c.SetMapIndex(copyIter.Key(), copyIter.Value())
+				}
+			}
+
+			c.SetMapIndex(iter.Key(), elem)
+		}
+
+		if !c.IsValid() {
+			return v, false
+		}
+
+		return c, true
+	}
+
+	return v, false
+}
@@ -0,0 +1,106 @@
+package redact
+
+import (
+	"testing"
+
+	portainer "github.com/portainer/portainer/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type credentials struct {
+	Username string
+	Password string `redact:"true"`
+	Token    string `redact:"log"`
+}
+
+type registry struct {
+	Name        string
+	Credentials *credentials
+	Mirrors     []credentials
+	Accesses    map[int]credentials
+}
+
+type public struct {
+	Name string
+	Tags []string
+}
+
+func TestRedact(t *testing.T) {
+	is := assert.New(t)
+
+	original := registry{
+		Name:        "registry",
+		Credentials: &credentials{Username: "bob", Password: "secret", Token: "token"},
+		Mirrors:     []credentials{{Username: "alice", Password: "secret", Token: "token"}},
+		Accesses:    map[int]credentials{1: {Username: "carol", Password: "secret"}},
+	}
+
+	redacted := Redact(&original, Response).(*registry)
+	is.Equal("registry", redacted.Name)
+	is.Equal(credentials{Username: "bob", Token: "token"}, *redacted.Credentials)
+	is.Equal([]credentials{{Username: "alice", Token: "token"}}, redacted.Mirrors)
+	is.Equal(map[int]credentials{1: {Username: "carol"}}, redacted.Accesses)
+
+	logged := Redact([]registry{original}, Log).([]registry)
+	is.Equal(credentials{Username: "bob"}, *logged[0].Credentials)
+	is.Equal([]credentials{{Username: "alice"}}, logged[0].Mirrors)
+
+	t.Run("the original value is left untouched", func(t *testing.T) {
+		is.Equal("secret", original.Credentials.Password)
+		is.Equal("secret", original.Mirrors[0].Password)
+		is.Equal("secret", original.Accesses[1].Password)
+	})
+
+	t.Run("values without secrets are returned as is", func(t *testing.T) {
+		value := &public{Name: "public", Tags: []string{"a"}}
+
+		is.Same(value, Redact(value, Log))
+		is.Nil(Redact(nil, Response))
+		is.Equal("text", Redact("text", Response))
+	})
+
+	t.Run("nil values are kept", func(t *testing.T) {
+		redacted := Redact(registry{Name: "empty"}, Response).(registry)
+
+		is.Nil(redacted.Credentials)
+		is.Nil(redacted.Mirrors)
+		is.Nil(redacted.Accesses)
+	})
+
+	t.Run("secrets behind interface values are redacted", func(t *testing.T) {
+		value := map[string]any{
+			"registry": &credentials{Username: "bob", Password: "secret"},
+			"list":     []any{credentials{Username: "alice", Password: "secret"}},
+			"name":     "registry",
+		}
+
+		redacted := Redact(value, Response).(map[string]any)
+		is.Equal(&credentials{Username: "bob"}, redacted["registry"])
+		is.Equal([]any{credentials{Username: "alice"}}, redacted["list"])
+		is.Equal("registry", redacted["name"])
+		is.Equal("secret", value["registry"].(*credentials).Password)
+	})
+
+	t.Run("values without secret set are not copied", func(t *testing.T) {
+		value := &registry{Name: "registry", Credentials: &credentials{Username: "bob", Token: "token"}}
+
+		is.Same(value, Redact(value, Response))
+	})
+}
+
+func TestRedactPortainerSecrets(t *testing.T) {
+	is := assert.New(t)
+
+	acme := Redact(&portainer.ACMESettings{DNSProvider: "cloudflare", DNSCredentials: map[string]string{"CF_API_TOKEN": "token"}}, Response).(*portainer.ACMESettings)
+	is.Equal("cloudflare", acme.DNSProvider)
+	is.Nil(acme.DNSCredentials)
+
+	signingKey := Redact(portainer.JWTSigningKey{ID: 1, PrivateKey: []byte("private"), PublicKey: []byte("public")}, Response).(portainer.JWTSigningKey)
+	is.Nil(signingKey.PrivateKey)
+	is.Equal([]byte("public"), signingKey.PublicKey)
+
+	oauthInfo := Redact(&portainer.OAuthInfo{Username: "bob", RefreshToken: "refresh"}, Log).(*portainer.OAuthInfo)
+	is.Equal("bob", oauthInfo.Username)
+	is.Empty(oauthInfo.RefreshToken)
+}